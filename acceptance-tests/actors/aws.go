@@ -28,7 +28,7 @@ func NewAWSLBHelper(c acceptance.Config) awsLBHelper {
 
 	logger := application.NewLogger(os.Stdout, os.Stdin)
 
-	client := aws.NewClient(creds, logger)
+	client := aws.NewClient(creds, logger, false, nil)
 
 	elbConfig := &awslib.Config{
 		Credentials: credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, ""),
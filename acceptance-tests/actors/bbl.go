@@ -46,6 +46,7 @@ func (b BBL) Up(additionalArgs ...string) *gexec.Session {
 		"--state-dir", b.stateDirectory,
 		"--debug",
 		"up",
+		"--auto-approve",
 	}
 
 	args = append(args, additionalArgs...)
@@ -1,8 +1,10 @@
 package application
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/catalog"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -12,36 +14,134 @@ type CommandSet map[string]commands.Command
 type usage interface {
 	Print()
 	PrintCommandUsage(command, message string)
+	PrintCommandExamples(command string)
+}
+
+type stateLock interface {
+	Lock() error
+	Unlock() error
+	IsLocked() bool
+}
+
+// stateBackup copies the state file before a command changes it, for bbl
+// state restore.
+type stateBackup interface {
+	Backup() error
+}
+
+type operations interface {
+	Start(command string) (storage.Operation, error)
+}
+
+type logger interface {
+	Println(string)
+}
+
+// Commands that write to the state directory. They hold the state lock while
+// they run; every other command reads the last saved state.
+var mutatingCommands = map[string]struct{}{
+	"up":                          struct{}{},
+	"plan":                        struct{}{},
+	"destroy":                     struct{}{},
+	"down":                        struct{}{},
+	"rotate":                      struct{}{},
+	"rotate-keypair":              struct{}{},
+	"rotate-director-credentials": struct{}{},
+	"upgrade-director":            struct{}{},
+	"copy-stemcell-ami":           struct{}{},
+	"upload-certificate":          struct{}{},
+	"create-certificate":          struct{}{},
+	"attach-certificate":          struct{}{},
+	"rotate-certificate":          struct{}{},
+	"pin-artifacts":               struct{}{},
+	"update-security-groups":      struct{}{},
+	"migrate-region":              struct{}{},
+	"detach-lb":                   struct{}{},
+	"adopt-lb":                    struct{}{},
+	"apply":                       struct{}{},
+	"clone":                       struct{}{},
+	"annotate":                    struct{}{},
+}
+
+// mutatingSubcommands are the subcommands that write to the state directory,
+// of commands whose other subcommands only read it.
+var mutatingSubcommands = map[string]map[string]struct{}{
+	"state": {
+		"encrypt": struct{}{},
+		"decrypt": struct{}{},
+		"restore": struct{}{},
+	},
+}
+
+// IsMutating reports whether the command, with its subcommand when it has
+// subcommands, writes to the state directory.
+func IsMutating(command string, subcommandFlags []string) bool {
+	if subcommands, ok := mutatingSubcommands[command]; ok {
+		if len(subcommandFlags) == 0 {
+			return false
+		}
+		_, ok = subcommands[subcommandFlags[0]]
+		return ok
+	}
+
+	_, ok := mutatingCommands[command]
+	return ok
 }
 
 type App struct {
 	commands      CommandSet
 	configuration Configuration
 	usage         usage
+	stateLock     stateLock
+	stateBackup   stateBackup
+	operations    operations
+	logger        logger
+	output        logger
+	messages      catalog.Catalog
 }
 
-func New(commands CommandSet, configuration Configuration, usage usage) App {
+// New builds the app. logger is for messages about the run; output receives
+// the operation id that --no-wait prints, so that scripts can capture it.
+// messages holds the messages of the language that bbl speaks.
+func New(commands CommandSet, configuration Configuration, usage usage, stateLock stateLock, stateBackup stateBackup, operations operations, logger logger, output logger, messages catalog.Catalog) App {
 	return App{
 		commands:      commands,
 		configuration: configuration,
 		usage:         usage,
+		stateLock:     stateLock,
+		stateBackup:   stateBackup,
+		operations:    operations,
+		logger:        logger,
+		output:        output,
+		messages:      messages,
 	}
 }
 
-func (a App) Run() error {
-	return a.execute()
+// Run runs the command until it completes or ctx is done, by an interrupt or
+// by --timeout. A command that fails once ctx is done fails as Interrupted,
+// since the steps it completed are saved and running it again resumes it.
+func (a App) Run(ctx context.Context) error {
+	err := a.execute(ctx)
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return bblerrors.New(bblerrors.Interrupted, a.messages.Error(catalog.TimedOut, a.configuration.Command, a.configuration.Global.Timeout, err))
+	}
+	return bblerrors.New(bblerrors.Interrupted, a.messages.Error(catalog.Interrupted, a.configuration.Command, err))
 }
 
 func (a App) getCommand(commandString string) (commands.Command, error) {
 	command, ok := a.commands[commandString]
 	if !ok {
 		a.usage.Print()
-		return nil, fmt.Errorf("unknown command: %s", commandString)
+		return nil, bblerrors.New(bblerrors.Validation, a.messages.Error(catalog.UnknownCommand, commandString))
 	}
 	return command, nil
 }
 
-func (a App) execute() error {
+func (a App) execute(ctx context.Context) error {
 	command, err := a.getCommand(a.configuration.Command)
 	if err != nil {
 		return err
@@ -52,6 +152,11 @@ func (a App) execute() error {
 		return nil
 	}
 
+	if a.configuration.ShowCommandExamples {
+		a.usage.PrintCommandExamples(a.configuration.Command)
+		return nil
+	}
+
 	if a.configuration.Command == "help" && len(a.configuration.SubcommandFlags) != 0 {
 		commandString := a.configuration.SubcommandFlags[0]
 		command, err = a.getCommand(commandString)
@@ -68,13 +173,78 @@ func (a App) execute() error {
 			return err
 		}
 
-		return versionCommand.Execute([]string{}, storage.State{})
+		return versionCommand.Execute(ctx, []string{}, storage.State{})
+	}
+
+	if a.configuration.Global.NoWait {
+		return a.startOperation(command)
+	}
+
+	mutating := IsMutating(a.configuration.Command, a.configuration.SubcommandFlags)
+	if mutating {
+		err = a.stateLock.Lock()
+		if err != nil {
+			return err
+		}
+		defer a.stateLock.Unlock()
+	} else if a.stateLock.IsLocked() {
+		a.logger.Println(a.messages.Message(catalog.MutationInProgress))
 	}
 
 	err = command.CheckFastFails(a.configuration.SubcommandFlags, a.configuration.State)
+	if err != nil {
+		return fastFailError(err)
+	}
+
+	if mutating {
+		err = a.stateBackup.Backup()
+		if err != nil {
+			return err
+		}
+	}
+
+	return command.Execute(ctx, a.configuration.SubcommandFlags, a.configuration.State)
+}
+
+// startOperation runs a mutating command in the background and returns once
+// it has started. The flags are checked here first, so that a mistake fails
+// straight away rather than in the background. The command has no terminal
+// there, so a prompt would read no answer and the command would stop without
+// failing, unless --no-confirm answers it.
+func (a App) startOperation(command commands.Command) error {
+	if !IsMutating(a.configuration.Command, a.configuration.SubcommandFlags) {
+		return bblerrors.New(bblerrors.Validation, a.messages.Error(catalog.NoWaitReadOnly, a.configuration.Command))
+	}
+
+	if !a.configuration.Global.NoConfirm {
+		return bblerrors.New(bblerrors.Validation, a.messages.Error(catalog.NoWaitConfirm, a.configuration.Command))
+	}
+
+	if a.stateLock.IsLocked() {
+		return bblerrors.New(bblerrors.Conflict, a.messages.Error(catalog.NoWaitLocked))
+	}
+
+	err := command.CheckFastFails(a.configuration.SubcommandFlags, a.configuration.State)
+	if err != nil {
+		return fastFailError(err)
+	}
+
+	operation, err := a.operations.Start(a.configuration.Command)
 	if err != nil {
 		return err
 	}
 
-	return command.Execute(a.configuration.SubcommandFlags, a.configuration.State)
+	a.logger.Println(a.messages.Message(catalog.NoWaitStarted, operation.Command, operation.ID, operation.ID))
+	a.output.Println(operation.ID)
+	return nil
+}
+
+// fastFailError marks the error of the checks of a command that has no
+// kind of its own as a validation error, since the checks read the flags and
+// the state before the command changes anything.
+func fastFailError(err error) error {
+	if bblerrors.KindOf(err) != bblerrors.Unknown {
+		return err
+	}
+	return bblerrors.New(bblerrors.Validation, err)
 }
@@ -1,9 +1,13 @@
 package application_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/catalog"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
@@ -14,12 +18,17 @@ import (
 
 var _ = Describe("App", func() {
 	var (
-		app        application.App
-		helpCmd    *fakes.Command
-		versionCmd *fakes.Command
-		someCmd    *fakes.Command
-		errorCmd   *fakes.Command
-		usage      *fakes.Usage
+		app         application.App
+		helpCmd     *fakes.Command
+		versionCmd  *fakes.Command
+		someCmd     *fakes.Command
+		errorCmd    *fakes.Command
+		usage       *fakes.Usage
+		stateLock   *fakes.StateLock
+		stateBackup *fakes.StateBackup
+		operations  *fakes.Operations
+		logger      *fakes.Logger
+		output      *fakes.Logger
 	)
 
 	var NewAppWithConfiguration = func(configuration application.Configuration) application.App {
@@ -28,10 +37,18 @@ var _ = Describe("App", func() {
 			"version":   versionCmd,
 			"--version": versionCmd,
 			"some":      someCmd,
+			"up":        someCmd,
+			"state":     someCmd,
 			"error":     errorCmd,
 		},
 			configuration,
 			usage,
+			stateLock,
+			stateBackup,
+			operations,
+			logger,
+			output,
+			catalog.Catalog{},
 		)
 	}
 
@@ -44,6 +61,11 @@ var _ = Describe("App", func() {
 		someCmd.ExecuteCall.PassState = true
 
 		usage = &fakes.Usage{}
+		stateLock = &fakes.StateLock{}
+		stateBackup = &fakes.StateBackup{}
+		operations = &fakes.Operations{}
+		logger = &fakes.Logger{}
+		output = &fakes.Logger{}
 
 		app = NewAppWithConfiguration(application.Configuration{})
 	})
@@ -63,7 +85,7 @@ var _ = Describe("App", func() {
 					State: storage.State{},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 
 				Expect(someCmd.ExecuteCall.CallCount).To(Equal(1))
 				Expect(someCmd.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{
@@ -73,6 +95,217 @@ var _ = Describe("App", func() {
 			})
 		})
 
+		Context("when the command is interrupted", func() {
+			BeforeEach(func() {
+				someCmd.ExecuteCall.Returns.Error = errors.New("Run bosh create-env: signal: interrupt")
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "up",
+					Global:  application.GlobalConfiguration{Timeout: 90 * time.Minute},
+				})
+			})
+
+			It("fails as interrupted, for running it again to resume it", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := app.Run(ctx)
+				Expect(err).To(MatchError("bbl up was interrupted: Run bosh create-env: signal: interrupt. Run it again to resume it."))
+				Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Interrupted))
+				Expect(stateLock.UnlockCall.CallCount).To(Equal(1))
+			})
+
+			It("says when the command ran out of its timeout", func() {
+				ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+				defer cancel()
+
+				err := app.Run(ctx)
+				Expect(err).To(MatchError("bbl up did not finish within --timeout 1h30m0s: Run bosh create-env: signal: interrupt. Run it again to resume it."))
+				Expect(bblerrors.ExitCode(err)).To(Equal(7))
+			})
+
+			It("returns the error of a command that failed before it was interrupted", func() {
+				Expect(app.Run(context.Background())).To(MatchError("Run bosh create-env: signal: interrupt"))
+			})
+		})
+
+		Context("state locking", func() {
+			It("holds the state lock while a mutating command runs", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "up",
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(stateLock.LockCall.CallCount).To(Equal(1))
+				Expect(someCmd.ExecuteCall.CallCount).To(Equal(1))
+				Expect(stateLock.UnlockCall.CallCount).To(Equal(1))
+			})
+
+			It("backs up the state before a mutating command runs", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "up",
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(stateBackup.BackupCall.CallCount).To(Equal(1))
+			})
+
+			It("does not run a mutating command when the state cannot be backed up", func() {
+				stateBackup.BackupCall.Returns.Error = errors.New("Back up state: disk full")
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "up",
+				})
+
+				Expect(app.Run(context.Background())).To(MatchError("Back up state: disk full"))
+
+				Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("does not back up the state for read-only commands", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "some",
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(stateBackup.BackupCall.CallCount).To(Equal(0))
+			})
+
+			It("does not lock or back up the state to list the backups", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command:         "state",
+					SubcommandFlags: []string{"list-backups"},
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(stateLock.LockCall.CallCount).To(Equal(0))
+				Expect(stateBackup.BackupCall.CallCount).To(Equal(0))
+				Expect(someCmd.ExecuteCall.CallCount).To(Equal(1))
+			})
+
+			It("locks and backs up the state to restore a backup", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command:         "state",
+					SubcommandFlags: []string{"restore"},
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(stateLock.LockCall.CallCount).To(Equal(1))
+				Expect(stateBackup.BackupCall.CallCount).To(Equal(1))
+			})
+
+			It("does not lock the state for read-only commands", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "some",
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(stateLock.LockCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+			})
+
+			Context("when another command holds the lock", func() {
+				BeforeEach(func() {
+					stateLock.IsLockedCall.Returns.Locked = true
+					stateLock.LockCall.Returns.Error = errors.New("state is locked")
+				})
+
+				It("runs read-only commands against the last saved state and notes the mutation in progress", func() {
+					app = NewAppWithConfiguration(application.Configuration{
+						Command: "some",
+					})
+
+					Expect(app.Run(context.Background())).To(Succeed())
+
+					Expect(someCmd.ExecuteCall.CallCount).To(Equal(1))
+					Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring("mutation in progress"))
+				})
+
+				It("refuses to run mutating commands", func() {
+					app = NewAppWithConfiguration(application.Configuration{
+						Command: "up",
+					})
+
+					Expect(app.Run(context.Background())).To(MatchError("state is locked"))
+
+					Expect(someCmd.CheckFastFailsCall.CallCount).To(Equal(0))
+					Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
+					Expect(stateLock.UnlockCall.CallCount).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when --no-wait is set", func() {
+			var configuration application.Configuration
+
+			BeforeEach(func() {
+				configuration = application.Configuration{
+					Command:         "up",
+					SubcommandFlags: []string{"--some-flag"},
+					Global:          application.GlobalConfiguration{NoWait: true, NoConfirm: true},
+				}
+				operations.StartCall.Returns.Operation = storage.Operation{ID: "some-id", Command: "up"}
+			})
+
+			It("starts the command in the background and prints the operation id", func() {
+				app = NewAppWithConfiguration(configuration)
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(someCmd.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{"--some-flag"}))
+				Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
+				Expect(stateLock.LockCall.CallCount).To(Equal(0))
+				Expect(operations.StartCall.Receives.Command).To(Equal("up"))
+				Expect(output.PrintlnCall.Messages).To(Equal([]string{"some-id"}))
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal("bbl up is running in the background as operation some-id. Run bbl wait some-id to wait for it to finish."))
+			})
+
+			It("returns an error for commands that do not change the environment", func() {
+				configuration.Command = "some"
+				app = NewAppWithConfiguration(configuration)
+
+				Expect(app.Run(context.Background())).To(MatchError("--no-wait only applies to commands that change the environment, not to some."))
+				Expect(operations.StartCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error without --no-confirm, since the command cannot be asked for confirmation", func() {
+				configuration.Global.NoConfirm = false
+				app = NewAppWithConfiguration(configuration)
+
+				Expect(app.Run(context.Background())).To(MatchError("--no-wait runs bbl up in the background, where it cannot ask for confirmation. Pass --no-confirm as well."))
+				Expect(someCmd.CheckFastFailsCall.CallCount).To(Equal(0))
+				Expect(operations.StartCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when another command holds the lock", func() {
+				stateLock.IsLockedCall.Returns.Locked = true
+				app = NewAppWithConfiguration(configuration)
+
+				Expect(app.Run(context.Background())).To(MatchError(ContainSubstring("Another bbl command is modifying this environment.")))
+				Expect(operations.StartCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error and starts nothing when a fast fail occurs", func() {
+				someCmd.CheckFastFailsCall.Returns.Error = errors.New("fast failed command")
+				app = NewAppWithConfiguration(configuration)
+
+				Expect(app.Run(context.Background())).To(MatchError("fast failed command"))
+				Expect(operations.StartCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the operation cannot start", func() {
+				operations.StartCall.Returns.Error = errors.New("no shell")
+				app = NewAppWithConfiguration(configuration)
+
+				Expect(app.Run(context.Background())).To(MatchError("no shell"))
+				Expect(output.PrintlnCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when subcommand flags contains help", func() {
 			DescribeTable("prints command specific usage when help subcommand flag is provided", func(helpFlag string) {
 				someCmd.UsageCall.Returns.Usage = "some usage message"
@@ -83,7 +316,7 @@ var _ = Describe("App", func() {
 					ShowCommandHelp: true,
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 				Expect(someCmd.UsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.Receives.Message).To(Equal("some usage message"))
@@ -95,6 +328,21 @@ var _ = Describe("App", func() {
 			)
 		})
 
+		Context("when examples are requested for a command", func() {
+			It("prints the examples instead of running it", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command:             "some",
+					ShowCommandExamples: true,
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+				Expect(usage.PrintCommandExamplesCall.CallCount).To(Equal(1))
+				Expect(usage.PrintCommandExamplesCall.Receives.Command).To(Equal("some"))
+				Expect(someCmd.CheckFastFailsCall.CallCount).To(Equal(0))
+				Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when help is called with a command", func() {
 			It("prints the command specific help", func() {
 				someCmd.UsageCall.Returns.Usage = "some usage message"
@@ -104,7 +352,7 @@ var _ = Describe("App", func() {
 					SubcommandFlags: []string{"some"},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 				Expect(someCmd.UsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.Receives.Message).To(Equal("some usage message"))
@@ -122,7 +370,7 @@ var _ = Describe("App", func() {
 					})
 
 					It("prints the usage", func() {
-						err := app.Run()
+						err := app.Run(context.Background())
 						Expect(err).To(MatchError("unknown command: invalid-command"))
 						Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
 						Expect(usage.PrintCall.CallCount).To(Equal(1))
@@ -141,7 +389,7 @@ var _ = Describe("App", func() {
 					},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 
 				Expect(versionCmd.ExecuteCall.CallCount).To(Equal(1))
 				Expect(versionCmd.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
@@ -155,7 +403,7 @@ var _ = Describe("App", func() {
 					SubcommandFlags: []string{versionFlag},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 				Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
 				Expect(versionCmd.ExecuteCall.CallCount).To(Equal(1))
 				Expect(versionCmd.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
@@ -173,11 +421,11 @@ var _ = Describe("App", func() {
 						}, application.Configuration{
 							Command:         "some",
 							SubcommandFlags: []string{"-v"},
-						}, usage)
+						}, usage, stateLock, stateBackup, operations, logger, output, catalog.Catalog{})
 					})
 
 					It("returns an error", func() {
-						Expect(app.Run()).To(MatchError("unknown command: version"))
+						Expect(app.Run(context.Background())).To(MatchError("unknown command: version"))
 					})
 				})
 			})
@@ -193,11 +441,30 @@ var _ = Describe("App", func() {
 					app = NewAppWithConfiguration(application.Configuration{
 						Command: "some",
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(someCmd.CheckFastFailsCall.CallCount).To(Equal(1))
 					Expect(err).To(MatchError("fast failed command"))
 					Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
 				})
+
+				It("returns it as a validation error", func() {
+					app = NewAppWithConfiguration(application.Configuration{
+						Command: "some",
+					})
+					err := app.Run(context.Background())
+					Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Validation))
+				})
+
+				Context("when the error has a kind of its own", func() {
+					It("keeps it", func() {
+						someCmd.CheckFastFailsCall.Returns.Error = errors.New("Describe regions: AuthFailure: AWS was not able to validate the provided access credentials")
+						app = NewAppWithConfiguration(application.Configuration{
+							Command: "some",
+						})
+						err := app.Run(context.Background())
+						Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Credentials))
+					})
+				})
 			})
 
 			Context("when an unknown command is provided", func() {
@@ -205,12 +472,26 @@ var _ = Describe("App", func() {
 					app = NewAppWithConfiguration(application.Configuration{
 						Command: "some-unknown-command",
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(err).To(MatchError("unknown command: some-unknown-command"))
 					Expect(usage.PrintCall.CallCount).To(Equal(1))
 				})
 			})
 
+			Context("when bbl speaks another language", func() {
+				It("returns the translated error with its code", func() {
+					messages, err := catalog.New("ja")
+					Expect(err).NotTo(HaveOccurred())
+					app = application.New(application.CommandSet{}, application.Configuration{
+						Command: "some-unknown-command",
+					}, usage, stateLock, stateBackup, operations, logger, output, messages)
+
+					err = app.Run(context.Background())
+					Expect(err).To(MatchError("不明なコマンドです: some-unknown-command"))
+					Expect(catalog.Code(err)).To(Equal("unknown-command"))
+				})
+			})
+
 			Context("when the command fails to execute", func() {
 				It("returns an error", func() {
 					errorCmd.ExecuteCall.Returns.Error = errors.New("error executing command")
@@ -220,9 +501,29 @@ var _ = Describe("App", func() {
 							Debug: true,
 						},
 					})
-					Expect(app.Run()).To(MatchError("error executing command"))
+					Expect(app.Run(context.Background())).To(MatchError("error executing command"))
 				})
 			})
 		})
 	})
+
+	Describe("IsMutating", func() {
+		It("reports the commands that write to the state directory", func() {
+			Expect(application.IsMutating("up", nil)).To(BeTrue())
+			Expect(application.IsMutating("upload-certificate", nil)).To(BeTrue())
+			Expect(application.IsMutating("attach-certificate", nil)).To(BeTrue())
+			Expect(application.IsMutating("pin-artifacts", nil)).To(BeTrue())
+			Expect(application.IsMutating("rotate-certificate", nil)).To(BeTrue())
+			Expect(application.IsMutating("update-security-groups", nil)).To(BeTrue())
+			Expect(application.IsMutating("print-env", nil)).To(BeFalse())
+		})
+
+		It("reports the subcommands of bbl state that write to the state directory", func() {
+			Expect(application.IsMutating("state", []string{"restore", "--backup", "some-backup"})).To(BeTrue())
+			Expect(application.IsMutating("state", []string{"encrypt"})).To(BeTrue())
+			Expect(application.IsMutating("state", []string{"decrypt"})).To(BeTrue())
+			Expect(application.IsMutating("state", []string{"list-backups"})).To(BeFalse())
+			Expect(application.IsMutating("state", nil)).To(BeFalse())
+		})
+	})
 })
@@ -1,10 +1,41 @@
 package application
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type GlobalConfiguration struct {
 	StateDir string
 	Debug    bool
+	JSON     bool
+	NoWait   bool
+	NoCache  bool
+
+	// NoConfirm answers the prompts of the command, which a command that
+	// --no-wait runs in the background cannot be asked.
+	NoConfirm bool
+
+	// OverrideAccountCheck runs mutating commands with AWS credentials of
+	// another account than the one the environment was created in.
+	OverrideAccountCheck bool
+
+	// HealthListen is the address that the progress of the command is
+	// served on while it runs.
+	HealthListen string
+
+	// ProfileRun is the file that the timing of the steps of the command
+	// and of its requests to AWS is written to when it completes.
+	ProfileRun string
+
+	// ProxyURL is the HTTP proxy that bbl, terraform and the bosh cli reach
+	// the IAAS, bosh.io and the jumpbox through.
+	ProxyURL string
+
+	// Timeout bounds how long the command runs. Once it is up, the command
+	// stops as if it was interrupted.
+	Timeout time.Duration
 }
 
 type StringSlice []string
@@ -21,9 +52,10 @@ func (s StringSlice) ContainsAny(targets ...string) bool {
 }
 
 type Configuration struct {
-	Global          GlobalConfiguration
-	Command         string
-	SubcommandFlags StringSlice
-	State           storage.State
-	ShowCommandHelp bool
+	Global              GlobalConfiguration
+	Command             string
+	SubcommandFlags     StringSlice
+	State               storage.State
+	ShowCommandHelp     bool
+	ShowCommandExamples bool
 }
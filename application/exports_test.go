@@ -0,0 +1,11 @@
+package application
+
+import "time"
+
+func NewHealthWithInjectedClock(command string, now func() time.Time) *Health {
+	return newHealth(command, now)
+}
+
+func NewProfileWithInjectedClock(command string, now func() time.Time) *Profile {
+	return newProfile(command, now)
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthEventLimit is how many of the recent events the health endpoint
+// reports.
+const healthEventLimit = 20
+
+// Health records the progress of the running command for the endpoint of
+// --health-listen, which orchestration polls to tell a long bbl up from a
+// stuck one.
+type Health struct {
+	mutex     sync.Mutex
+	command   string
+	started   time.Time
+	phase     string
+	heartbeat time.Time
+	events    []HealthEvent
+	now       func() time.Time
+}
+
+type HealthEvent struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+type HealthReport struct {
+	Command   string        `json:"command"`
+	Started   time.Time     `json:"started"`
+	Phase     string        `json:"phase"`
+	Heartbeat time.Time     `json:"heartbeat"`
+	Events    []HealthEvent `json:"events"`
+}
+
+func NewHealth(command string) *Health {
+	return newHealth(command, time.Now)
+}
+
+func newHealth(command string, now func() time.Time) *Health {
+	started := now()
+	return &Health{
+		command:   command,
+		started:   started,
+		heartbeat: started,
+		events:    []HealthEvent{},
+		now:       now,
+	}
+}
+
+// Step starts a new phase of the command.
+func (h *Health) Step(step string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.phase = step
+	h.record(step)
+}
+
+// Event records a message of the command.
+func (h *Health) Event(message string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.record(message)
+}
+
+// Beat shows that the command is still making progress without a message,
+// as the dots of a long wait do.
+func (h *Health) Beat() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.heartbeat = h.now()
+}
+
+func (h *Health) record(message string) {
+	h.heartbeat = h.now()
+	h.events = append(h.events, HealthEvent{Time: h.heartbeat, Message: message})
+	if len(h.events) > healthEventLimit {
+		h.events = h.events[len(h.events)-healthEventLimit:]
+	}
+}
+
+func (h *Health) Report() HealthReport {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return HealthReport{
+		Command:   h.command,
+		Started:   h.started,
+		Phase:     h.phase,
+		Heartbeat: h.heartbeat,
+		Events:    append([]HealthEvent{}, h.events...),
+	}
+}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Report())
+}
+
+// ListenHealth serves the health of the command on addr until the returned
+// server is closed, once the command completes.
+func ListenHealth(addr string, health *Health) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Addr: listener.Addr().String(), Handler: health}
+	go server.Serve(listener)
+
+	return server, nil
+}
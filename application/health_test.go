@@ -0,0 +1,94 @@
+package application_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Health", func() {
+	var (
+		now    time.Time
+		health *application.Health
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2018, time.May, 26, 10, 0, 0, 0, time.UTC)
+		health = application.NewHealthWithInjectedClock("up", func() time.Time { return now })
+	})
+
+	It("reports the phase, the last heartbeat and the recent events of the command", func() {
+		now = now.Add(time.Minute)
+		health.Step("terraform apply")
+		now = now.Add(time.Minute)
+		health.Event("Applying the changes.")
+		now = now.Add(time.Minute)
+		health.Beat()
+
+		Expect(health.Report()).To(Equal(application.HealthReport{
+			Command:   "up",
+			Started:   time.Date(2018, time.May, 26, 10, 0, 0, 0, time.UTC),
+			Phase:     "terraform apply",
+			Heartbeat: time.Date(2018, time.May, 26, 10, 3, 0, 0, time.UTC),
+			Events: []application.HealthEvent{
+				{Time: time.Date(2018, time.May, 26, 10, 1, 0, 0, time.UTC), Message: "terraform apply"},
+				{Time: time.Date(2018, time.May, 26, 10, 2, 0, 0, time.UTC), Message: "Applying the changes."},
+			},
+		}))
+	})
+
+	It("only keeps the recent events", func() {
+		for i := 0; i < 25; i++ {
+			health.Event(fmt.Sprintf("event %d", i))
+		}
+
+		events := health.Report().Events
+		Expect(events).To(HaveLen(20))
+		Expect(events[0].Message).To(Equal("event 5"))
+		Expect(events[19].Message).To(Equal("event 24"))
+	})
+
+	It("serves the report as json", func() {
+		health.Step("creating jumpbox")
+
+		recorder := httptest.NewRecorder()
+		health.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		var report application.HealthReport
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &report)).To(Succeed())
+		Expect(report.Phase).To(Equal("creating jumpbox"))
+	})
+
+	Describe("ListenHealth", func() {
+		It("serves the health on the address until it is closed", func() {
+			server, err := application.ListenHealth("127.0.0.1:0", health)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.Addr).NotTo(BeEmpty())
+
+			response, err := http.Get(fmt.Sprintf("http://%s", server.Addr))
+			Expect(err).NotTo(HaveOccurred())
+			body, err := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(ContainSubstring(`"command":"up"`))
+
+			Expect(server.Close()).To(Succeed())
+			_, err = http.Get(fmt.Sprintf("http://%s", server.Addr))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the address cannot be listened on", func() {
+			_, err := application.ListenHealth("not-an-address", health)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
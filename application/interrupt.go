@@ -0,0 +1,42 @@
+package application
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// NotifyInterrupt returns the context of the command: it is done once bbl is
+// interrupted, by ctrl-c or SIGTERM, or once the timeout is up, if there is
+// one. bbl does not exit on the first interrupt, so that the command can stop
+// its waiting, let terraform and bosh create-env stop, and save what it
+// completed. A second interrupt exits straight away. stop releases the
+// signals.
+func NotifyInterrupt(timeout time.Duration) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelTimeout := func() {}
+	if timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-stopped:
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, func() {
+		close(stopped)
+		cancelTimeout()
+		cancel()
+	}
+}
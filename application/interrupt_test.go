@@ -0,0 +1,32 @@
+package application_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NotifyInterrupt", func() {
+	It("is done once the timeout is up", func() {
+		ctx, stop := application.NotifyInterrupt(10 * time.Millisecond)
+		defer stop()
+
+		Eventually(ctx.Done()).Should(BeClosed())
+		Expect(ctx.Err()).To(Equal(context.DeadlineExceeded))
+	})
+
+	It("has no deadline without a timeout", func() {
+		ctx, stop := application.NotifyInterrupt(0)
+
+		_, ok := ctx.Deadline()
+		Expect(ok).To(BeFalse())
+		Expect(ctx.Err()).NotTo(HaveOccurred())
+
+		stop()
+		Expect(ctx.Err()).To(Equal(context.Canceled))
+	})
+})
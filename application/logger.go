@@ -4,13 +4,26 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Logger struct {
+	// mutex keeps the lines of steps that run concurrently, such as the
+	// initializers of bbl plan, from running into each other.
+	mutex sync.Mutex
+
 	newline   bool
 	writer    io.Writer
 	reader    io.Reader
 	noConfirm bool
+
+	debugWriter io.Writer
+	lastStep    string
+	stepStarted time.Time
+
+	health  *Health
+	profile *Profile
 }
 
 func NewLogger(writer io.Writer, reader io.Reader) *Logger {
@@ -32,26 +45,103 @@ func (l *Logger) clear() {
 }
 
 func (l *Logger) Step(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	step := fmt.Sprintf(message, a...)
+	if l.lastStep != "" {
+		l.debugf("step %q took %s", l.lastStep, time.Since(l.stepStarted).Round(time.Millisecond))
+	}
+	if l.debugWriter != nil {
+		l.lastStep = step
+		l.stepStarted = time.Now()
+	}
+
+	if l.health != nil {
+		l.health.Step(step)
+	}
+	if l.profile != nil {
+		l.profile.Step(step)
+	}
+
 	l.clear()
-	fmt.Fprintf(l.writer, "step: %s\n", fmt.Sprintf(message, a...))
+	fmt.Fprintf(l.writer, "step: %s\n", step)
 	l.newline = true
 }
 
 func (l *Logger) Dot() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.health != nil {
+		l.health.Beat()
+	}
+
 	l.writer.Write([]byte("\u2022"))
 	l.newline = false
 }
 
 func (l *Logger) Printf(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.health != nil {
+		l.health.Event(strings.TrimSuffix(fmt.Sprintf(message, a...), "\n"))
+	}
+
 	l.clear()
 	fmt.Fprintf(l.writer, "%s", fmt.Sprintf(message, a...))
 }
 
 func (l *Logger) Println(message string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.health != nil {
+		l.health.Event(message)
+	}
+
 	l.clear()
 	fmt.Fprintf(l.writer, "%s\n", message)
 }
 
+// Health records the steps and messages of the logger in h, for the
+// endpoint of --health-listen.
+func (l *Logger) Health(h *Health) {
+	l.health = h
+}
+
+// Profile records the steps of the logger in p, for the report of
+// --profile-run.
+func (l *Logger) Profile(p *Profile) {
+	l.profile = p
+}
+
+// Debug turns on debug messages, which are written to w, and the timing
+// of each step.
+func (l *Logger) Debug(w io.Writer) {
+	l.debugWriter = w
+}
+
+// Debugf writes a debug message when Debug has been called.
+func (l *Logger) Debugf(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.debugf(message, a...)
+}
+
+func (l *Logger) debugf(message string, a ...interface{}) {
+	if l.debugWriter == nil {
+		return
+	}
+
+	if l.debugWriter == l.writer {
+		l.clear()
+	}
+	fmt.Fprintf(l.debugWriter, "debug: %s\n", strings.TrimSuffix(fmt.Sprintf(message, a...), "\n"))
+}
+
 func (l *Logger) NoConfirm() {
 	l.noConfirm = true
 }
@@ -74,3 +164,27 @@ func (l *Logger) Prompt(message string) bool {
 	}
 	return false
 }
+
+// PromptForName has the operator type a name to confirm an operation that
+// cannot be undone. --no-confirm answers it.
+func (l *Logger) PromptForName(message, name string) bool {
+	if l.noConfirm {
+		return true
+	}
+
+	return l.PromptForInput(fmt.Sprintf("%s Type %s to confirm", message, name)) == name
+}
+
+// PromptForInput asks for a line of input, for confirmations that have the
+// operator type something, such as the name of what will be deleted.
+// --no-confirm does not answer it.
+func (l *Logger) PromptForInput(message string) string {
+	l.clear()
+	fmt.Fprintf(l.writer, "%s: ", message)
+	l.newline = true
+
+	var input string
+	fmt.Fscanln(l.reader, &input)
+
+	return strings.TrimSpace(input)
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"strings"
+	"sync"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
 
@@ -39,6 +41,53 @@ var _ = Describe("Logger", func() {
 			logger.Step("Random variable is: %d", randomInt)
 			Expect(writer.String()).To(Equal(fmt.Sprintf("step: Random variable is: %d\n", randomInt)))
 		})
+
+		It("keeps the lines of steps of concurrent goroutines whole", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					logger.Step("step %d", i)
+				}(i)
+			}
+			wg.Wait()
+
+			lines := strings.Split(strings.TrimSuffix(writer.String(), "\n"), "\n")
+			Expect(lines).To(HaveLen(10))
+			for _, line := range lines {
+				Expect(line).To(MatchRegexp(`^step: step \d$`))
+			}
+		})
+	})
+
+	Describe("Debugf", func() {
+		It("prints nothing by default", func() {
+			logger.Debugf("some %s", "message")
+
+			Expect(writer.String()).To(BeEmpty())
+		})
+
+		It("prints debug messages to the debug writer once Debug has been called", func() {
+			debugWriter := bytes.NewBuffer([]byte{})
+			logger.Debug(debugWriter)
+
+			logger.Debugf("some %s\n", "message")
+
+			Expect(debugWriter.String()).To(Equal("debug: some message\n"))
+			Expect(writer.String()).To(BeEmpty())
+		})
+
+		It("times each step", func() {
+			debugWriter := bytes.NewBuffer([]byte{})
+			logger.Debug(debugWriter)
+
+			logger.Step("creating key")
+			logger.Step("uploading key")
+
+			Expect(writer.String()).To(Equal("step: creating key\nstep: uploading key\n"))
+			Expect(debugWriter.String()).To(MatchRegexp(`^debug: step "creating key" took \S+s\n$`))
+		})
 	})
 
 	Describe("Dot", func() {
@@ -97,6 +146,84 @@ var _ = Describe("Logger", func() {
 		)
 	})
 
+	Describe("PromptForName", func() {
+		It("proceeds when the name is typed", func() {
+			fmt.Fprintf(reader, "%s\n", "some-env")
+
+			proceed := logger.PromptForName("Delete some-env?", "some-env")
+			Expect(proceed).To(BeTrue())
+
+			Expect(writer.String()).To(Equal("Delete some-env? Type some-env to confirm: "))
+		})
+
+		It("does not proceed when something else is typed", func() {
+			fmt.Fprintf(reader, "%s\n", "y")
+
+			proceed := logger.PromptForName("Delete some-env?", "some-env")
+			Expect(proceed).To(BeFalse())
+		})
+
+		It("doesn't prompt when NoConfirm has been called", func() {
+			logger.NoConfirm()
+
+			proceed := logger.PromptForName("Delete some-env?", "some-env")
+			Expect(proceed).To(BeTrue())
+			Expect(writer.String()).To(Equal(""))
+		})
+	})
+
+	Describe("PromptForInput", func() {
+		It("prompts for the given message and returns the input", func() {
+			fmt.Fprintf(reader, "%s\n", "some-env")
+
+			input := logger.PromptForInput("Type the environment name")
+			Expect(input).To(Equal("some-env"))
+
+			Expect(writer.String()).To(Equal("Type the environment name: "))
+		})
+
+		It("still prompts when NoConfirm has been called", func() {
+			logger.NoConfirm()
+			fmt.Fprintf(reader, "%s\n", "some-env")
+
+			input := logger.PromptForInput("Type the environment name")
+			Expect(input).To(Equal("some-env"))
+		})
+	})
+
+	Describe("Health", func() {
+		It("records the steps and messages for the health endpoint", func() {
+			health := application.NewHealth("up")
+			logger.Health(health)
+
+			logger.Step("applying %s", "terraform")
+			logger.Printf("some message\n")
+			logger.Println("another message")
+
+			report := health.Report()
+			Expect(report.Phase).To(Equal("applying terraform"))
+			Expect(report.Events).To(HaveLen(3))
+			Expect(report.Events[1].Message).To(Equal("some message"))
+			Expect(report.Events[2].Message).To(Equal("another message"))
+		})
+	})
+
+	Describe("Profile", func() {
+		It("records the steps for the profile of the run", func() {
+			profile := application.NewProfile("up")
+			logger.Profile(profile)
+
+			logger.Step("applying %s", "terraform")
+			logger.Println("some message")
+			logger.Step("creating jumpbox")
+
+			steps := profile.Report().Steps
+			Expect(steps).To(HaveLen(2))
+			Expect(steps[0].Name).To(Equal("applying terraform"))
+			Expect(steps[1].Name).To(Equal("creating jumpbox"))
+		})
+	})
+
 	Describe("mixing steps, dots and printlns", func() {
 		It("prints out a coherent set of lines", func() {
 			logger.Step("creating key")
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Profile records how long each step of the command takes and every request
+// that bbl makes to AWS, for the report of --profile-run, which shows where
+// the time of a slow bbl up goes.
+type Profile struct {
+	mutex   sync.Mutex
+	command string
+	started time.Time
+	steps   []ProfileStep
+	calls   []ProfileAWSCall
+	now     func() time.Time
+}
+
+type ProfileStep struct {
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+	Seconds float64   `json:"seconds"`
+}
+
+type ProfileAWSCall struct {
+	Service   string    `json:"service"`
+	Operation string    `json:"operation"`
+	Started   time.Time `json:"started"`
+	Seconds   float64   `json:"seconds"`
+	Retries   int       `json:"retries"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ProfileAWSOperation sums up the requests of one AWS operation.
+type ProfileAWSOperation struct {
+	Service    string  `json:"service"`
+	Operation  string  `json:"operation"`
+	Calls      int     `json:"calls"`
+	Retries    int     `json:"retries"`
+	Errors     int     `json:"errors"`
+	Seconds    float64 `json:"seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+type ProfileReport struct {
+	Command    string                `json:"command"`
+	Started    time.Time             `json:"started"`
+	Seconds    float64               `json:"seconds"`
+	Steps      []ProfileStep         `json:"steps"`
+	AWSCalls   []ProfileAWSCall      `json:"aws_calls"`
+	AWSSummary []ProfileAWSOperation `json:"aws_summary"`
+}
+
+func NewProfile(command string) *Profile {
+	return newProfile(command, time.Now)
+}
+
+func newProfile(command string, now func() time.Time) *Profile {
+	return &Profile{
+		command: command,
+		started: now(),
+		steps:   []ProfileStep{},
+		calls:   []ProfileAWSCall{},
+		now:     now,
+	}
+}
+
+// Step starts a new step of the command. A step lasts until the next one
+// starts, or until the command completes.
+func (p *Profile) Step(step string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	p.endStep(now)
+	p.steps = append(p.steps, ProfileStep{Name: step, Started: now})
+}
+
+func (p *Profile) endStep(now time.Time) {
+	if len(p.steps) == 0 {
+		return
+	}
+
+	last := &p.steps[len(p.steps)-1]
+	last.Seconds = seconds(now.Sub(last.Started))
+}
+
+// AWSCall records a request to AWS once it completes, with the number of
+// times it was retried.
+func (p *Profile) AWSCall(service, operation string, started time.Time, duration time.Duration, retries int, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	call := ProfileAWSCall{
+		Service:   service,
+		Operation: operation,
+		Started:   started,
+		Seconds:   seconds(duration),
+		Retries:   retries,
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	p.calls = append(p.calls, call)
+}
+
+func (p *Profile) Report() ProfileReport {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	p.endStep(now)
+
+	return ProfileReport{
+		Command:    p.command,
+		Started:    p.started,
+		Seconds:    seconds(now.Sub(p.started)),
+		Steps:      append([]ProfileStep{}, p.steps...),
+		AWSCalls:   append([]ProfileAWSCall{}, p.calls...),
+		AWSSummary: summarizeAWSCalls(p.calls),
+	}
+}
+
+// Write writes the report to path as JSON.
+func (p *Profile) Write(path string) error {
+	contents, err := json.MarshalIndent(p.Report(), "", "  ")
+	if err != nil {
+		return err //not tested
+	}
+
+	return ioutil.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// summarizeAWSCalls sums up the calls by operation, the operations that took
+// the longest first.
+func summarizeAWSCalls(calls []ProfileAWSCall) []ProfileAWSOperation {
+	operations := map[string]*ProfileAWSOperation{}
+	summary := []*ProfileAWSOperation{}
+	for _, call := range calls {
+		key := call.Service + "/" + call.Operation
+		operation, ok := operations[key]
+		if !ok {
+			operation = &ProfileAWSOperation{Service: call.Service, Operation: call.Operation}
+			operations[key] = operation
+			summary = append(summary, operation)
+		}
+
+		operation.Calls++
+		operation.Retries += call.Retries
+		if call.Error != "" {
+			operation.Errors++
+		}
+		operation.Seconds += call.Seconds
+		if call.Seconds > operation.MaxSeconds {
+			operation.MaxSeconds = call.Seconds
+		}
+	}
+
+	sort.SliceStable(summary, func(i, j int) bool {
+		return summary[i].Seconds > summary[j].Seconds
+	})
+
+	result := []ProfileAWSOperation{}
+	for _, operation := range summary {
+		result = append(result, *operation)
+	}
+	return result
+}
+
+func seconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
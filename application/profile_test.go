@@ -0,0 +1,96 @@
+package application_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profile", func() {
+	var (
+		now     time.Time
+		profile *application.Profile
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2018, time.May, 26, 10, 0, 0, 0, time.UTC)
+		profile = application.NewProfileWithInjectedClock("up", func() time.Time { return now })
+	})
+
+	It("reports how long each step took, the last one until the report", func() {
+		now = now.Add(time.Second)
+		profile.Step("terraform apply")
+		now = now.Add(25 * time.Minute)
+		profile.Step("creating jumpbox")
+		now = now.Add(90 * time.Second)
+
+		report := profile.Report()
+		Expect(report.Command).To(Equal("up"))
+		Expect(report.Started).To(Equal(time.Date(2018, time.May, 26, 10, 0, 0, 0, time.UTC)))
+		Expect(report.Seconds).To(Equal(1591.0))
+		Expect(report.Steps).To(Equal([]application.ProfileStep{
+			{Name: "terraform apply", Started: time.Date(2018, time.May, 26, 10, 0, 1, 0, time.UTC), Seconds: 1500},
+			{Name: "creating jumpbox", Started: time.Date(2018, time.May, 26, 10, 25, 1, 0, time.UTC), Seconds: 90},
+		}))
+	})
+
+	It("reports every aws call and sums them up by operation, the slowest first", func() {
+		started := time.Date(2018, time.May, 26, 10, 0, 1, 0, time.UTC)
+		profile.AWSCall("ec2", "DescribeAvailabilityZones", started, 200*time.Millisecond, 0, nil)
+		profile.AWSCall("iam", "GetRole", started, 4*time.Second, 3, errors.New("Throttling: Rate exceeded"))
+		profile.AWSCall("iam", "GetRole", started, time.Second, 1, nil)
+
+		report := profile.Report()
+		Expect(report.AWSCalls).To(Equal([]application.ProfileAWSCall{
+			{Service: "ec2", Operation: "DescribeAvailabilityZones", Started: started, Seconds: 0.2},
+			{Service: "iam", Operation: "GetRole", Started: started, Seconds: 4, Retries: 3, Error: "Throttling: Rate exceeded"},
+			{Service: "iam", Operation: "GetRole", Started: started, Seconds: 1, Retries: 1},
+		}))
+		Expect(report.AWSSummary).To(Equal([]application.ProfileAWSOperation{
+			{Service: "iam", Operation: "GetRole", Calls: 2, Retries: 4, Errors: 1, Seconds: 5, MaxSeconds: 4},
+			{Service: "ec2", Operation: "DescribeAvailabilityZones", Calls: 1, Seconds: 0.2, MaxSeconds: 0.2},
+		}))
+	})
+
+	Describe("Write", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("writes the report as json", func() {
+			profile.Step("terraform apply")
+			path := filepath.Join(dir, "profile.json")
+
+			err := profile.Write(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			var report application.ProfileReport
+			Expect(json.Unmarshal(contents, &report)).To(Succeed())
+			Expect(report.Steps[0].Name).To(Equal("terraform apply"))
+			Expect(report.AWSCalls).To(BeEmpty())
+		})
+
+		It("returns an error when the file cannot be written", func() {
+			err := profile.Write(filepath.Join(dir, "missing", "profile.json"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,21 @@
+package application
+
+import "os"
+
+// proxyVariables are the environment variables that the HTTP clients of bbl,
+// the aws sdk, terraform and the bosh cli read their proxy from.
+var proxyVariables = []string{"HTTPS_PROXY", "HTTP_PROXY"}
+
+// UseProxy sends the requests of bbl and of the terraform and bosh commands
+// it runs through the proxy at proxyURL, by setting the proxy environment
+// variables that they all read. It is called before any request is made, as
+// the clients read the variables once. NO_PROXY still exempts hosts.
+func UseProxy(proxyURL string) error {
+	for _, name := range proxyVariables {
+		err := os.Setenv(name, proxyURL)
+		if err != nil {
+			return err //not tested
+		}
+	}
+	return nil
+}
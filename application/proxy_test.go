@@ -0,0 +1,35 @@
+package application_test
+
+import (
+	"os"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UseProxy", func() {
+	var (
+		httpsProxy string
+		httpProxy  string
+	)
+
+	BeforeEach(func() {
+		httpsProxy = os.Getenv("HTTPS_PROXY")
+		httpProxy = os.Getenv("HTTP_PROXY")
+	})
+
+	AfterEach(func() {
+		os.Setenv("HTTPS_PROXY", httpsProxy)
+		os.Setenv("HTTP_PROXY", httpProxy)
+	})
+
+	It("sets the proxy environment variables to the proxy url", func() {
+		err := application.UseProxy("http://proxy.example.com:3128")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Getenv("HTTPS_PROXY")).To(Equal("http://proxy.example.com:3128"))
+		Expect(os.Getenv("HTTP_PROXY")).To(Equal("http://proxy.example.com:3128"))
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 type StateValidator struct {
@@ -15,9 +17,12 @@ func NewStateValidator(stateDir string) StateValidator {
 }
 
 func (s StateValidator) Validate() error {
-	_, err := os.Stat(filepath.Join(s.stateDir, "bbl-state.json"))
+	_, err := os.Stat(filepath.Join(s.stateDir, storage.StateFileName))
+	if os.IsNotExist(err) {
+		_, err = os.Stat(filepath.Join(s.stateDir, storage.YAMLStateFileName))
+	}
 	if os.IsNotExist(err) {
-		return fmt.Errorf("bbl-state.json not found in %q, ensure you're running this command in the proper state directory or create a new environment with bbl up", s.stateDir)
+		return fmt.Errorf("neither %s nor %s found in %q, ensure you're running this command in the proper state directory or create a new environment with bbl up", storage.StateFileName, storage.YAMLStateFileName, s.stateDir)
 	}
 	if err != nil {
 		return err
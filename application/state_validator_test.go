@@ -39,9 +39,20 @@ var _ = Describe("StateValidator", func() {
 		})
 	})
 
+	Context("when a yaml state file exists", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(filepath.Join(tempDirectory, "bbl-state.yml"), []byte(""), storage.StateMode)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns no error ", func() {
+			Expect(stateValidator.Validate()).To(Succeed())
+		})
+	})
+
 	Context("when state file cannot be found", func() {
 		It("returns an error", func() {
-			expectedError := fmt.Errorf("bbl-state.json not found in %q, ensure you're running this command in the proper state directory or create a new environment with bbl up", tempDirectory)
+			expectedError := fmt.Errorf("neither bbl-state.json nor bbl-state.yml found in %q, ensure you're running this command in the proper state directory or create a new environment with bbl up", tempDirectory)
 			Expect(stateValidator.Validate()).To(MatchError(expectedError))
 		})
 	})
@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"fmt"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// AccountMismatchError reports credentials of another account than the one
+// that the environment was created in.
+type AccountMismatchError struct {
+	EnvID          string
+	Region         string
+	StateAccountID string
+	AccountID      string
+	Identity       string
+}
+
+func (e AccountMismatchError) Error() string {
+	return fmt.Sprintf(`The AWS credentials belong to another account than the environment %s:
+  environment: account %s, region %s
+  credentials: account %s, as %s
+Pass the credentials of account %s, or --override-account-check to run the command against account %s anyway.`,
+		e.EnvID, e.StateAccountID, e.Region, e.AccountID, e.Identity, e.StateAccountID, e.AccountID)
+}
+
+func (e AccountMismatchError) Kind() bblerrors.Kind {
+	return bblerrors.Credentials
+}
+
+// VerifyAccount records the account of the credentials in the state of an
+// environment that has none yet. Credentials of another account than the
+// recorded one are refused unless override is set, so that a stale profile
+// or environment variable does not change the wrong account. A state without
+// an environment has no account to check, so no credentials are needed yet.
+func (r CredentialsResolver) VerifyAccount(state storage.State, override bool) (storage.State, error) {
+	if state.EnvID == "" {
+		return state, nil
+	}
+
+	output, err := r.stsClient(state.AWS).GetCallerIdentity(&awssts.GetCallerIdentityInput{})
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Get caller identity: %s", err)
+	}
+
+	accountID := awslib.StringValue(output.Account)
+	switch {
+	case state.AWS.AccountID == "":
+		state.AWS.AccountID = accountID
+	case state.AWS.AccountID != accountID && !override:
+		return storage.State{}, AccountMismatchError{
+			EnvID:          state.EnvID,
+			Region:         state.AWS.Region,
+			StateAccountID: state.AWS.AccountID,
+			AccountID:      accountID,
+			Identity:       awslib.StringValue(output.Arn),
+		}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,113 @@
+package aws_test
+
+import (
+	"errors"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VerifyAccount", func() {
+	var (
+		stsClient      *fakes.AWSSTSClient
+		stsClientCreds storage.AWS
+		resolver       aws.CredentialsResolver
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stsClient = &fakes.AWSSTSClient{}
+		stsClient.GetCallerIdentityCall.Returns.Output = &awssts.GetCallerIdentityOutput{
+			Account: awslib.String("123456789012"),
+			Arn:     awslib.String("arn:aws:iam::123456789012:user/some-user"),
+		}
+
+		resolver = aws.NewCredentialsResolverWithInjectedClients(&fakes.Logger{},
+			func(creds storage.AWS) aws.STSClient {
+				stsClientCreds = creds
+				return stsClient
+			},
+			func(string) (credentials.Value, string, error) {
+				return credentials.Value{}, "", nil
+			},
+		)
+
+		state = storage.State{
+			EnvID: "some-env",
+			AWS: storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "us-east-1",
+			},
+		}
+	})
+
+	It("records the account of the credentials in a state without one", func() {
+		verified, err := resolver.VerifyAccount(state, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stsClientCreds.AccessKeyID).To(Equal("some-access-key-id"))
+		Expect(verified.AWS.AccountID).To(Equal("123456789012"))
+	})
+
+	It("accepts credentials of the account in the state", func() {
+		state.AWS.AccountID = "123456789012"
+
+		verified, err := resolver.VerifyAccount(state, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verified).To(Equal(state))
+	})
+
+	Context("when the credentials belong to another account", func() {
+		BeforeEach(func() {
+			state.AWS.AccountID = "210987654321"
+		})
+
+		It("returns a report of the mismatch", func() {
+			_, err := resolver.VerifyAccount(state, false)
+			Expect(err).To(Equal(aws.AccountMismatchError{
+				EnvID:          "some-env",
+				Region:         "us-east-1",
+				StateAccountID: "210987654321",
+				AccountID:      "123456789012",
+				Identity:       "arn:aws:iam::123456789012:user/some-user",
+			}))
+			Expect(err).To(MatchError(`The AWS credentials belong to another account than the environment some-env:
+  environment: account 210987654321, region us-east-1
+  credentials: account 123456789012, as arn:aws:iam::123456789012:user/some-user
+Pass the credentials of account 210987654321, or --override-account-check to run the command against account 123456789012 anyway.`))
+			Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Credentials))
+		})
+
+		It("keeps the recorded account when the check is overridden", func() {
+			verified, err := resolver.VerifyAccount(state, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified.AWS.AccountID).To(Equal("210987654321"))
+		})
+	})
+
+	It("does not call STS for a state without an environment", func() {
+		state.EnvID = ""
+
+		verified, err := resolver.VerifyAccount(state, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verified).To(Equal(state))
+		Expect(stsClient.GetCallerIdentityCall.CallCount).To(Equal(0))
+	})
+
+	It("returns an error when the caller identity cannot be retrieved", func() {
+		stsClient.GetCallerIdentityCall.Returns.Error = errors.New("InvalidClientTokenId")
+
+		_, err := resolver.VerifyAccount(state, false)
+		Expect(err).To(MatchError("Get caller identity: InvalidClientTokenId"))
+	})
+})
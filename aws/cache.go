@@ -0,0 +1,138 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+)
+
+const (
+	cacheFile = "aws.json"
+
+	// AvailabilityZonesTTL is how long the availability zones of a region are
+	// cached. Regions gain availability zones rarely.
+	AvailabilityZonesTTL = 24 * time.Hour
+)
+
+var timeNow = time.Now
+
+type cacheFS interface {
+	fileio.FileReader
+	fileio.FileWriter
+}
+
+type cacheEntry struct {
+	Value   json.RawMessage `json:"value"`
+	Expires time.Time       `json:"expires"`
+}
+
+// Cache keeps the results of slow-changing describes in a file of the state
+// directory, so that later runs skip the call until the result expires. A
+// cache that cannot be read is treated as empty.
+type Cache struct {
+	path string
+	fs   cacheFS
+}
+
+func NewCache(dir string, fs cacheFS) Cache {
+	return Cache{
+		path: filepath.Join(dir, cacheFile),
+		fs:   fs,
+	}
+}
+
+// Get reads the cached result of key into value, and reports whether there
+// was one that has not expired.
+func (c Cache) Get(key string, value interface{}) bool {
+	entry, ok := c.read()[key]
+	if !ok || timeNow().After(entry.Expires) {
+		return false
+	}
+
+	return json.Unmarshal(entry.Value, value) == nil
+}
+
+// Set caches value as the result of key until ttl has passed, and drops the
+// results that have expired.
+func (c Cache) Set(key string, value interface{}, ttl time.Duration) error {
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return err //not tested
+	}
+
+	now := timeNow()
+	entries := map[string]cacheEntry{}
+	for k, entry := range c.read() {
+		if now.Before(entry.Expires) {
+			entries[k] = entry
+		}
+	}
+	entries[key] = cacheEntry{Value: contents, Expires: now.Add(ttl)}
+
+	contents, err = json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err //not tested
+	}
+
+	err = c.fs.WriteFile(c.path, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("Write cache: %s", err)
+	}
+
+	return nil
+}
+
+func (c Cache) read() map[string]cacheEntry {
+	entries := map[string]cacheEntry{}
+
+	contents, err := c.fs.ReadFile(c.path)
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(contents, &entries); err != nil {
+		return map[string]cacheEntry{}
+	}
+
+	return entries
+}
+
+// CachingAvailabilityZoneRetriever returns the availability zones of a region
+// from the cache, and retrieves and caches them when they are not cached.
+type CachingAvailabilityZoneRetriever struct {
+	retriever AvailabilityZoneRetriever
+	cache     Cache
+	logger    debugLogger
+}
+
+func NewCachingAvailabilityZoneRetriever(retriever AvailabilityZoneRetriever, cache Cache, logger debugLogger) CachingAvailabilityZoneRetriever {
+	return CachingAvailabilityZoneRetriever{
+		retriever: retriever,
+		cache:     cache,
+		logger:    logger,
+	}
+}
+
+func (c CachingAvailabilityZoneRetriever) RetrieveAvailabilityZones(region string) ([]string, error) {
+	key := fmt.Sprintf("availability-zones/%s", region)
+
+	var azs []string
+	if c.cache.Get(key, &azs) {
+		c.logger.Debugf("using the cached availability zones of %s", region)
+		return azs, nil
+	}
+
+	azs, err := c.retriever.RetrieveAvailabilityZones(region)
+	if err != nil {
+		return []string{}, err
+	}
+
+	// Failing to cache only costs the next run the describe.
+	if err := c.cache.Set(key, azs, AvailabilityZonesTTL); err != nil {
+		c.logger.Debugf("not caching the availability zones of %s: %s", region, err)
+	}
+
+	return azs, nil
+}
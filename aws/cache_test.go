@@ -0,0 +1,127 @@
+package aws_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		fs    *afero.Afero
+		cache aws.Cache
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+		aws.SetTimeNow(func() time.Time { return now })
+
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		cache = aws.NewCache("/some/state-dir/cache", fs)
+	})
+
+	AfterEach(func() {
+		aws.ResetTimeNow()
+	})
+
+	It("returns what was cached until it expires", func() {
+		Expect(cache.Set("some-key", []string{"a", "b"}, time.Hour)).To(Succeed())
+
+		var value []string
+		Expect(cache.Get("some-key", &value)).To(BeTrue())
+		Expect(value).To(Equal([]string{"a", "b"}))
+
+		now = now.Add(2 * time.Hour)
+		Expect(cache.Get("some-key", &value)).To(BeFalse())
+	})
+
+	It("persists the results in the directory", func() {
+		Expect(cache.Set("some-key", "some-value", time.Hour)).To(Succeed())
+
+		var value string
+		Expect(aws.NewCache("/some/state-dir/cache", fs).Get("some-key", &value)).To(BeTrue())
+		Expect(value).To(Equal("some-value"))
+	})
+
+	It("drops the results that have expired", func() {
+		Expect(cache.Set("old-key", "old-value", time.Hour)).To(Succeed())
+		now = now.Add(2 * time.Hour)
+		Expect(cache.Set("new-key", "new-value", time.Hour)).To(Succeed())
+
+		contents, err := fs.ReadFile("/some/state-dir/cache/aws.json")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("old-key"))
+		Expect(string(contents)).To(ContainSubstring("new-key"))
+	})
+
+	It("treats a cache that cannot be read as empty", func() {
+		Expect(fs.WriteFile("/some/state-dir/cache/aws.json", []byte("%%%"), 0600)).To(Succeed())
+
+		var value string
+		Expect(cache.Get("some-key", &value)).To(BeFalse())
+		Expect(cache.Set("some-key", "some-value", time.Hour)).To(Succeed())
+		Expect(cache.Get("some-key", &value)).To(BeTrue())
+	})
+})
+
+var _ = Describe("CachingAvailabilityZoneRetriever", func() {
+	var (
+		retriever *fakes.AvailabilityZoneRetriever
+		fileIO    *fakes.FileIO
+		logger    *fakes.Logger
+		caching   aws.CachingAvailabilityZoneRetriever
+	)
+
+	BeforeEach(func() {
+		retriever = &fakes.AvailabilityZoneRetriever{}
+		retriever.RetrieveAvailabilityZonesCall.Returns.AZs = []string{"us-east-1a", "us-east-1b"}
+		fileIO = &fakes.FileIO{}
+		fileIO.ReadFileCall.Returns.Error = errors.New("no such file")
+		logger = &fakes.Logger{}
+		caching = aws.NewCachingAvailabilityZoneRetriever(retriever, aws.NewCache("/some/cache", fileIO), logger)
+	})
+
+	It("retrieves and caches the availability zones", func() {
+		azs, err := caching.RetrieveAvailabilityZones("us-east-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(azs).To(Equal([]string{"us-east-1a", "us-east-1b"}))
+
+		Expect(retriever.RetrieveAvailabilityZonesCall.Receives.Region).To(Equal("us-east-1"))
+		Expect(fileIO.WriteFileCall.Receives).To(HaveLen(1))
+		Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal("/some/cache/aws.json"))
+		Expect(string(fileIO.WriteFileCall.Receives[0].Contents)).To(ContainSubstring("availability-zones/us-east-1"))
+	})
+
+	It("returns the cached availability zones without retrieving them", func() {
+		fileIO.ReadFileCall.Returns.Error = nil
+		fileIO.ReadFileCall.Returns.Contents = []byte(`{"availability-zones/us-east-1": {"value": ["us-east-1c"], "expires": "2999-01-01T00:00:00Z"}}`)
+
+		azs, err := caching.RetrieveAvailabilityZones("us-east-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(azs).To(Equal([]string{"us-east-1c"}))
+		Expect(retriever.RetrieveAvailabilityZonesCall.CallCount).To(Equal(0))
+	})
+
+	It("returns the availability zones when they cannot be cached", func() {
+		fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("read-only file system")}}
+
+		azs, err := caching.RetrieveAvailabilityZones("us-east-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(azs).To(Equal([]string{"us-east-1a", "us-east-1b"}))
+	})
+
+	It("returns an error when the availability zones cannot be retrieved", func() {
+		retriever.RetrieveAvailabilityZonesCall.Returns.Error = errors.New("throttled")
+
+		_, err := caching.RetrieveAvailabilityZones("us-east-1")
+		Expect(err).To(MatchError("throttled"))
+		Expect(fileIO.WriteFileCall.Receives).To(BeEmpty())
+	})
+})
@@ -0,0 +1,25 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// CallRecorder is told of every request to AWS once it completes, for the
+// report of --profile-run.
+type CallRecorder interface {
+	AWSCall(service, operation string, started time.Time, duration time.Duration, retries int, err error)
+}
+
+// recordCalls has the clients made with handlers report their requests to
+// recorder, with the time they took including their retries.
+func recordCalls(handlers *request.Handlers, recorder CallRecorder) {
+	if recorder == nil {
+		return
+	}
+
+	handlers.Complete.PushBack(func(req *request.Request) {
+		recorder.AWSCall(req.ClientInfo.ServiceName, req.Operation.Name, req.Time, time.Since(req.Time), req.RetryCount, req.Error)
+	})
+}
@@ -0,0 +1,88 @@
+package aws_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RecordCalls", func() {
+	var (
+		server   *httptest.Server
+		failures int
+		recorder *fakes.AWSCallRecorder
+		sess     *session.Session
+	)
+
+	BeforeEach(func() {
+		failures = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failures > 0 {
+				failures--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`<DescribeVpcsResponse><vpcSet></vpcSet></DescribeVpcsResponse>`))
+		}))
+
+		recorder = &fakes.AWSCallRecorder{}
+		sess = session.New(&awslib.Config{
+			Credentials: credentials.NewStaticCredentials("some-access-key-id", "some-secret-access-key", ""),
+			Region:      awslib.String("some-region"),
+			Endpoint:    awslib.String(server.URL),
+			Retryer:     client.DefaultRetryer{NumMaxRetries: 3},
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reports each request with its retries once it completes", func() {
+		failures = 2
+		aws.RecordCalls(&sess.Handlers, recorder)
+
+		_, err := awsec2.New(sess).DescribeVpcs(&awsec2.DescribeVpcsInput{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.AWSCallCall.CallCount).To(Equal(1))
+		call := recorder.AWSCallCall.Receives[0]
+		Expect(call.Service).To(Equal("ec2"))
+		Expect(call.Operation).To(Equal("DescribeVpcs"))
+		Expect(call.Retries).To(Equal(2))
+		Expect(call.Started).NotTo(BeZero())
+		Expect(call.Duration).To(BeNumerically(">", 0))
+		Expect(call.Error).NotTo(HaveOccurred())
+	})
+
+	It("reports requests that fail", func() {
+		failures = 10
+		aws.RecordCalls(&sess.Handlers, recorder)
+
+		_, err := awsec2.New(sess).DescribeVpcs(&awsec2.DescribeVpcsInput{})
+		Expect(err).To(HaveOccurred())
+
+		Expect(recorder.AWSCallCall.CallCount).To(Equal(1))
+		Expect(recorder.AWSCallCall.Receives[0].Retries).To(Equal(3))
+		Expect(recorder.AWSCallCall.Receives[0].Error).To(Equal(err))
+	})
+
+	It("does nothing without a recorder", func() {
+		aws.RecordCalls(&sess.Handlers, nil)
+
+		_, err := awsec2.New(sess).DescribeVpcs(&awsec2.DescribeVpcsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Handlers.Complete.Len()).To(Equal(0))
+	})
+})
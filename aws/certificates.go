@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"fmt"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+)
+
+// UploadServerCertificate uploads a certificate, its key and its chain to IAM
+// under the name, and returns its ARN and whether it was created. When a
+// certificate with the name was uploaded before, its ARN is returned instead.
+func (c Client) UploadServerCertificate(name string, cert, key, chain []byte) (string, bool, error) {
+	c.logger.Step("uploading the server certificate %s", name)
+
+	input := &awsiam.UploadServerCertificateInput{
+		ServerCertificateName: awslib.String(name),
+		CertificateBody:       awslib.String(string(cert)),
+		PrivateKey:            awslib.String(string(key)),
+	}
+	if len(chain) > 0 {
+		input.CertificateChain = awslib.String(string(chain))
+	}
+
+	output, err := c.iamClient.UploadServerCertificate(input)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awsiam.ErrCodeEntityAlreadyExistsException {
+			c.logger.Step("the server certificate %s already exists", name)
+			arn, err := c.serverCertificateARN(name)
+			return arn, false, err
+		}
+		return "", false, fmt.Errorf("Upload server certificate %s: %s", name, err)
+	}
+
+	return awslib.StringValue(output.ServerCertificateMetadata.Arn), true, nil
+}
+
+// DeleteServerCertificate deletes the certificate of the name from IAM.
+func (c Client) DeleteServerCertificate(name string) error {
+	c.logger.Step("deleting the server certificate %s", name)
+
+	_, err := c.iamClient.DeleteServerCertificate(&awsiam.DeleteServerCertificateInput{
+		ServerCertificateName: awslib.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Delete server certificate %s: %s", name, err)
+	}
+
+	return nil
+}
+
+func (c Client) serverCertificateARN(name string) (string, error) {
+	output, err := c.iamClient.GetServerCertificate(&awsiam.GetServerCertificateInput{
+		ServerCertificateName: awslib.String(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Get server certificate %s: %s", name, err)
+	}
+
+	return awslib.StringValue(output.ServerCertificate.ServerCertificateMetadata.Arn), nil
+}
+
+// SetListenerCertificate switches the TLS listener of the load balancer on
+// the port over to the certificate, which takes effect at once.
+func (c Client) SetListenerCertificate(lbName string, port int64, certificateARN string) error {
+	c.logger.Step("switching the listener on port %d of %s to the certificate %s", port, lbName, certificateARN)
+
+	_, err := c.elbClient.SetLoadBalancerListenerSSLCertificate(&awselb.SetLoadBalancerListenerSSLCertificateInput{
+		LoadBalancerName: awslib.String(lbName),
+		LoadBalancerPort: awslib.Int64(port),
+		SSLCertificateId: awslib.String(certificateARN),
+	})
+	if err != nil {
+		return fmt.Errorf("Set the certificate of the listener on port %d of %s: %s", port, lbName, err)
+	}
+
+	return nil
+}
+
+// SetLBListenerCertificate switches the TLS listener of the ELBv2 load
+// balancer on the port over to the certificate, which takes effect at once.
+func (c Client) SetLBListenerCertificate(lbName string, port int64, certificateARN string) error {
+	c.logger.Step("switching the listener on port %d of %s to the certificate %s", port, lbName, certificateARN)
+
+	lbs, err := c.elbv2Client.DescribeLoadBalancers(&awselbv2.DescribeLoadBalancersInput{
+		Names: []*string{awslib.String(lbName)},
+	})
+	if err != nil {
+		return fmt.Errorf("Describe the load balancer %s: %s", lbName, err)
+	}
+	if len(lbs.LoadBalancers) == 0 {
+		return fmt.Errorf("There is no load balancer named %s.", lbName)
+	}
+
+	listeners, err := c.elbv2Client.DescribeListeners(&awselbv2.DescribeListenersInput{
+		LoadBalancerArn: lbs.LoadBalancers[0].LoadBalancerArn,
+	})
+	if err != nil {
+		return fmt.Errorf("Describe the listeners of %s: %s", lbName, err)
+	}
+
+	for _, listener := range listeners.Listeners {
+		if awslib.Int64Value(listener.Port) != port {
+			continue
+		}
+
+		_, err = c.elbv2Client.ModifyListener(&awselbv2.ModifyListenerInput{
+			ListenerArn:  listener.ListenerArn,
+			Certificates: []*awselbv2.Certificate{{CertificateArn: awslib.String(certificateARN)}},
+		})
+		if err != nil {
+			return fmt.Errorf("Set the certificate of the listener on port %d of %s: %s", port, lbName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%s has no listener on port %d.", lbName, port)
+}
@@ -0,0 +1,170 @@
+package aws_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Certificates", func() {
+	var (
+		iamClient *fakes.AWSIAMClient
+		logger    *fakes.Logger
+		client    aws.Client
+	)
+
+	BeforeEach(func() {
+		iamClient = &fakes.AWSIAMClient{}
+		iamClient.UploadServerCertificateCall.Returns.Output = &awsiam.UploadServerCertificateOutput{
+			ServerCertificateMetadata: &awsiam.ServerCertificateMetadata{Arn: awslib.String("arn:aws:iam::123456789012:server-certificate/some-cert")},
+		}
+		logger = &fakes.Logger{}
+		client = aws.NewClientWithInjectedIAMClient(iamClient, logger)
+	})
+
+	Describe("UploadServerCertificate", func() {
+		It("uploads the certificate with its chain and returns its arn", func() {
+			arn, created, err := client.UploadServerCertificate("some-cert", []byte("some-cert-body"), []byte("some-key"), []byte("some-chain"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(arn).To(Equal("arn:aws:iam::123456789012:server-certificate/some-cert"))
+			Expect(created).To(BeTrue())
+
+			Expect(iamClient.UploadServerCertificateCall.Receives.Input).To(Equal(&awsiam.UploadServerCertificateInput{
+				ServerCertificateName: awslib.String("some-cert"),
+				CertificateBody:       awslib.String("some-cert-body"),
+				PrivateKey:            awslib.String("some-key"),
+				CertificateChain:      awslib.String("some-chain"),
+			}))
+			Expect(logger.StepCall.Messages).To(Equal([]string{"uploading the server certificate some-cert"}))
+		})
+
+		It("leaves out an empty chain", func() {
+			_, _, err := client.UploadServerCertificate("some-cert", []byte("some-cert-body"), []byte("some-key"), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(iamClient.UploadServerCertificateCall.Receives.Input.CertificateChain).To(BeNil())
+		})
+
+		It("returns the arn of a certificate that was uploaded before", func() {
+			iamClient.UploadServerCertificateCall.Returns.Error = awserr.New("EntityAlreadyExists", "The Server Certificate with name some-cert already exists.", nil)
+			iamClient.GetServerCertificateCall.Returns.Output = &awsiam.GetServerCertificateOutput{
+				ServerCertificate: &awsiam.ServerCertificate{
+					ServerCertificateMetadata: &awsiam.ServerCertificateMetadata{Arn: awslib.String("arn:aws:iam::123456789012:server-certificate/some-cert")},
+				},
+			}
+
+			arn, created, err := client.UploadServerCertificate("some-cert", []byte("some-cert-body"), []byte("some-key"), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(arn).To(Equal("arn:aws:iam::123456789012:server-certificate/some-cert"))
+			Expect(created).To(BeFalse())
+			Expect(iamClient.GetServerCertificateCall.Receives.Input.ServerCertificateName).To(Equal(awslib.String("some-cert")))
+		})
+
+		It("returns other errors", func() {
+			iamClient.UploadServerCertificateCall.Returns.Error = errors.New("MalformedCertificate")
+
+			_, _, err := client.UploadServerCertificate("some-cert", []byte("some-cert-body"), []byte("some-key"), nil)
+			Expect(err).To(MatchError("Upload server certificate some-cert: MalformedCertificate"))
+		})
+	})
+
+	Describe("DeleteServerCertificate", func() {
+		It("deletes the certificate", func() {
+			err := client.DeleteServerCertificate("some-cert")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamClient.DeleteServerCertificateCall.Receives.Input).To(Equal(&awsiam.DeleteServerCertificateInput{
+				ServerCertificateName: awslib.String("some-cert"),
+			}))
+			Expect(logger.StepCall.Messages).To(Equal([]string{"deleting the server certificate some-cert"}))
+		})
+
+		It("returns an error when the certificate cannot be deleted", func() {
+			iamClient.DeleteServerCertificateCall.Returns.Error = errors.New("DeleteConflict")
+
+			err := client.DeleteServerCertificate("some-cert")
+			Expect(err).To(MatchError("Delete server certificate some-cert: DeleteConflict"))
+		})
+	})
+
+	Describe("SetListenerCertificate", func() {
+		var elbClient *fakes.AWSELBClient
+
+		BeforeEach(func() {
+			elbClient = &fakes.AWSELBClient{}
+			client = aws.NewClientWithInjectedELBClient(elbClient, logger)
+		})
+
+		It("switches the listener of the load balancer to the certificate", func() {
+			err := client.SetListenerCertificate("some-lb", 443, "some-arn")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(elbClient.SetLoadBalancerListenerSSLCertificateCall.Receives.Input).To(Equal(&awselb.SetLoadBalancerListenerSSLCertificateInput{
+				LoadBalancerName: awslib.String("some-lb"),
+				LoadBalancerPort: awslib.Int64(443),
+				SSLCertificateId: awslib.String("some-arn"),
+			}))
+			Expect(logger.StepCall.Messages).To(Equal([]string{"switching the listener on port 443 of some-lb to the certificate some-arn"}))
+		})
+
+		It("returns an error when the listener cannot be switched", func() {
+			elbClient.SetLoadBalancerListenerSSLCertificateCall.Returns.Error = errors.New("CertificateNotFound")
+
+			err := client.SetListenerCertificate("some-lb", 443, "some-arn")
+			Expect(err).To(MatchError("Set the certificate of the listener on port 443 of some-lb: CertificateNotFound"))
+		})
+	})
+
+	Describe("SetLBListenerCertificate", func() {
+		var elbv2Client *fakes.AWSELBV2Client
+
+		BeforeEach(func() {
+			elbv2Client = &fakes.AWSELBV2Client{}
+			client = aws.NewClientWithInjectedELBV2Client(elbv2Client, logger)
+
+			elbv2Client.DescribeLoadBalancersCall.Returns.Output = &awselbv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []*awselbv2.LoadBalancer{{LoadBalancerArn: awslib.String("some-lb-arn")}},
+			}
+			elbv2Client.DescribeListenersCall.Returns.Output = &awselbv2.DescribeListenersOutput{
+				Listeners: []*awselbv2.Listener{
+					{ListenerArn: awslib.String("some-80-listener-arn"), Port: awslib.Int64(80)},
+					{ListenerArn: awslib.String("some-443-listener-arn"), Port: awslib.Int64(443)},
+				},
+			}
+		})
+
+		It("switches the listener of the load balancer on the port to the certificate", func() {
+			err := client.SetLBListenerCertificate("some-lb", 443, "some-arn")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(elbv2Client.DescribeLoadBalancersCall.Receives.Input.Names).To(Equal([]*string{awslib.String("some-lb")}))
+			Expect(elbv2Client.DescribeListenersCall.Receives.Input.LoadBalancerArn).To(Equal(awslib.String("some-lb-arn")))
+			Expect(elbv2Client.ModifyListenerCall.Receives.Input).To(Equal(&awselbv2.ModifyListenerInput{
+				ListenerArn:  awslib.String("some-443-listener-arn"),
+				Certificates: []*awselbv2.Certificate{{CertificateArn: awslib.String("some-arn")}},
+			}))
+		})
+
+		It("returns an error when the load balancer has no listener on the port", func() {
+			err := client.SetLBListenerCertificate("some-lb", 4443, "some-arn")
+			Expect(err).To(MatchError("some-lb has no listener on port 4443."))
+			Expect(elbv2Client.ModifyListenerCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the listener cannot be switched", func() {
+			elbv2Client.ModifyListenerCall.Returns.Error = errors.New("CertificateNotFound")
+
+			err := client.SetLBListenerCertificate("some-lb", 443, "some-arn")
+			Expect(err).To(MatchError("Set the certificate of the listener on port 443 of some-lb: CertificateNotFound"))
+		})
+	})
+})
@@ -7,9 +7,14 @@ import (
 	"strings"
 
 	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -17,10 +22,44 @@ type EC2Client interface {
 	DescribeAvailabilityZones(*awsec2.DescribeAvailabilityZonesInput) (*awsec2.DescribeAvailabilityZonesOutput, error)
 	DescribeInstances(*awsec2.DescribeInstancesInput) (*awsec2.DescribeInstancesOutput, error)
 	DescribeVpcs(*awsec2.DescribeVpcsInput) (*awsec2.DescribeVpcsOutput, error)
+	DescribeKeyPairs(*awsec2.DescribeKeyPairsInput) (*awsec2.DescribeKeyPairsOutput, error)
+	DescribeImages(*awsec2.DescribeImagesInput) (*awsec2.DescribeImagesOutput, error)
+	CopyImage(*awsec2.CopyImageInput) (*awsec2.CopyImageOutput, error)
+	DescribeRegions(*awsec2.DescribeRegionsInput) (*awsec2.DescribeRegionsOutput, error)
+	DescribeAccountAttributes(*awsec2.DescribeAccountAttributesInput) (*awsec2.DescribeAccountAttributesOutput, error)
+	DescribeAddresses(*awsec2.DescribeAddressesInput) (*awsec2.DescribeAddressesOutput, error)
+}
+
+type IAMClient interface {
+	CreateServiceLinkedRole(*awsiam.CreateServiceLinkedRoleInput) (*awsiam.CreateServiceLinkedRoleOutput, error)
+	UploadServerCertificate(*awsiam.UploadServerCertificateInput) (*awsiam.UploadServerCertificateOutput, error)
+	GetServerCertificate(*awsiam.GetServerCertificateInput) (*awsiam.GetServerCertificateOutput, error)
+	DeleteServerCertificate(*awsiam.DeleteServerCertificateInput) (*awsiam.DeleteServerCertificateOutput, error)
+	GetRole(*awsiam.GetRoleInput) (*awsiam.GetRoleOutput, error)
+	GetInstanceProfile(*awsiam.GetInstanceProfileInput) (*awsiam.GetInstanceProfileOutput, error)
+	SimulatePrincipalPolicy(*awsiam.SimulatePrincipalPolicyInput) (*awsiam.SimulatePolicyResponse, error)
+}
+
+type ELBClient interface {
+	SetLoadBalancerListenerSSLCertificate(*awselb.SetLoadBalancerListenerSSLCertificateInput) (*awselb.SetLoadBalancerListenerSSLCertificateOutput, error)
+	DescribeAccountLimits(*awselb.DescribeAccountLimitsInput) (*awselb.DescribeAccountLimitsOutput, error)
+	DescribeLoadBalancers(*awselb.DescribeLoadBalancersInput) (*awselb.DescribeLoadBalancersOutput, error)
+}
+
+type ELBV2Client interface {
+	DescribeLoadBalancers(*awselbv2.DescribeLoadBalancersInput) (*awselbv2.DescribeLoadBalancersOutput, error)
+	DescribeListeners(*awselbv2.DescribeListenersInput) (*awselbv2.DescribeListenersOutput, error)
+	ModifyListener(*awselbv2.ModifyListenerInput) (*awselbv2.ModifyListenerOutput, error)
+	DescribeAccountLimits(*awselbv2.DescribeAccountLimitsInput) (*awselbv2.DescribeAccountLimitsOutput, error)
 }
 
 type logger interface {
 	Step(string, ...interface{})
+	debugLogger
+}
+
+type debugLogger interface {
+	Debugf(string, ...interface{})
 }
 
 type AvailabilityZoneRetriever interface {
@@ -28,20 +67,62 @@ type AvailabilityZoneRetriever interface {
 }
 
 type Client struct {
-	ec2Client EC2Client
-	logger    logger
+	ec2Client   EC2Client
+	iamClient   IAMClient
+	elbClient   ELBClient
+	elbv2Client ELBV2Client
+	stsClient   STSClient
+	logger      logger
 }
 
-func NewClient(creds storage.AWS, logger logger) Client {
+// NewClient returns a Client whose requests are retried with backoff when
+// they fail or are throttled. With debug set, every request and response is
+// logged, with the credentials redacted. A non-nil calls is told of every
+// request.
+func NewClient(creds storage.AWS, logger logger, debug bool, calls CallRecorder) Client {
+	maxRetries := DefaultMaxRetries
+	if creds.MaxRetries != 0 {
+		maxRetries = creds.MaxRetries
+	}
+
+	jitter := DefaultRetryJitter
+	if creds.RetryJitter != nil {
+		jitter = *creds.RetryJitter
+	}
+
 	config := &awslib.Config{
-		Credentials: credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, ""),
+		Credentials: credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
 		Region:      awslib.String(creds.Region),
+		Retryer:     newRetryer(maxRetries, jitter, logger),
 	}
 
+	if debug {
+		config.LogLevel = awslib.LogLevel(awslib.LogDebugWithHTTPBody)
+		config.Logger = newRedactingLogger(logger, creds)
+	}
+
+	sess := session.New(config)
+	recordCalls(&sess.Handlers, calls)
+
 	return Client{
-		ec2Client: awsec2.New(session.New(config)),
-		logger:    logger,
+		ec2Client:   awsec2.New(sess, endpointConfig(creds, "ec2")),
+		iamClient:   awsiam.New(sess, endpointConfig(creds, "iam")),
+		elbClient:   awselb.New(sess, endpointConfig(creds, "elb")),
+		elbv2Client: awselbv2.New(sess, endpointConfig(creds, "elb")),
+		stsClient:   awssts.New(sess, endpointConfig(creds, "sts")),
+		logger:      logger,
+	}
+}
+
+// endpointConfig points a client of a service at the endpoint of the
+// service, if there is one in place of the endpoint of the region. The sdk
+// finds the endpoints of the regions of GovCloud and China itself.
+func endpointConfig(creds storage.AWS, service string) *awslib.Config {
+	endpoint := creds.EndpointFor(service)
+	if endpoint == "" {
+		return nil
 	}
+	return &awslib.Config{Endpoint: awslib.String(endpoint)}
 }
 
 func (c Client) RetrieveAvailabilityZones(region string) ([]string, error) {
@@ -72,6 +153,66 @@ func (c Client) RetrieveAvailabilityZones(region string) ([]string, error) {
 	return azList, nil
 }
 
+// AvailabilityZones returns the availability zones pinned for the
+// environment, after checking that they are in its region, or every
+// availability zone in the region when none are pinned.
+func AvailabilityZones(retriever AvailabilityZoneRetriever, state storage.AWS) ([]string, error) {
+	regionAZs, err := retriever.RetrieveAvailabilityZones(state.Region)
+	if err != nil {
+		return []string{}, err
+	}
+
+	// A cheap environment pays for the subnets and traffic of a single
+	// availability zone.
+	if len(state.AZs) == 0 && state.Cheap && len(regionAZs) > 0 {
+		return regionAZs[:1], nil
+	}
+
+	if len(state.AZs) == 0 {
+		return regionAZs, nil
+	}
+
+	for _, az := range state.AZs {
+		if !containsString(regionAZs, az) {
+			return []string{}, fmt.Errorf("Availability zone %s is not in %s. Its availability zones are %s.", az, state.Region, strings.Join(regionAZs, ", "))
+		}
+	}
+
+	azs := append([]string{}, state.AZs...)
+	sort.Strings(azs)
+
+	return azs, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// BootstrapAccount creates the account-wide prerequisites that fresh accounts
+// can lack: the service-linked role Elastic Load Balancing needs before the
+// first load balancer is created. It succeeds if the role already exists.
+func (c Client) BootstrapAccount() error {
+	c.logger.Step("creating the elastic load balancing service-linked role")
+
+	_, err := c.iamClient.CreateServiceLinkedRole(&awsiam.CreateServiceLinkedRoleInput{
+		AWSServiceName: awslib.String("elasticloadbalancing.amazonaws.com"),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awsiam.ErrCodeInvalidInputException && strings.Contains(awsErr.Message(), "has been taken") {
+			c.logger.Step("the elastic load balancing service-linked role already exists")
+			return nil
+		}
+		return fmt.Errorf("Create the elastic load balancing service-linked role: %s", err)
+	}
+
+	return nil
+}
+
 func (c Client) CheckExists(networkName string) (bool, error) {
 	vpcs, err := c.ec2Client.DescribeVpcs(&awsec2.DescribeVpcsInput{
 		Filters: []*awsec2.Filter{
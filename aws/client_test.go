@@ -9,7 +9,9 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
 	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -25,6 +27,8 @@ var _ = Describe("Client", func() {
 					Region:          "some-region",
 				},
 				&fakes.Logger{},
+				false,
+				nil,
 			)
 
 			ec2Client, ok := client.GetEC2Client().(*awsec2.EC2)
@@ -32,6 +36,66 @@ var _ = Describe("Client", func() {
 
 			Expect(ec2Client.Config.Credentials).To(Equal(credentials.NewStaticCredentials("some-access-key-id", "some-secret-access-key", "")))
 			Expect(ec2Client.Config.Region).To(Equal(awslib.String("some-region")))
+
+			iamClient, ok := client.GetIAMClient().(*awsiam.IAM)
+			Expect(ok).To(BeTrue())
+			Expect(iamClient.Config.Credentials).To(Equal(credentials.NewStaticCredentials("some-access-key-id", "some-secret-access-key", "")))
+		})
+
+		It("retries requests up to the default number of times", func() {
+			client := aws.NewClient(storage.AWS{Region: "some-region"}, &fakes.Logger{}, false, nil)
+
+			ec2Client := client.GetEC2Client().(*awsec2.EC2)
+			Expect(ec2Client.Client.Retryer.MaxRetries()).To(Equal(aws.DefaultMaxRetries))
+		})
+
+		It("retries requests up to the configured number of times", func() {
+			client := aws.NewClient(storage.AWS{Region: "some-region", MaxRetries: 20}, &fakes.Logger{}, false, nil)
+
+			ec2Client := client.GetEC2Client().(*awsec2.EC2)
+			Expect(ec2Client.Client.Retryer.MaxRetries()).To(Equal(20))
+
+			iamClient := client.GetIAMClient().(*awsiam.IAM)
+			Expect(iamClient.Client.Retryer.MaxRetries()).To(Equal(20))
+		})
+	})
+
+	Describe("BootstrapAccount", func() {
+		var (
+			client    aws.Client
+			iamClient *fakes.AWSIAMClient
+			logger    *fakes.Logger
+		)
+
+		BeforeEach(func() {
+			iamClient = &fakes.AWSIAMClient{}
+			logger = &fakes.Logger{}
+			client = aws.NewClientWithInjectedIAMClient(iamClient, logger)
+		})
+
+		It("creates the elastic load balancing service-linked role", func() {
+			err := client.BootstrapAccount()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamClient.CreateServiceLinkedRoleCall.Receives.Input).To(Equal(&awsiam.CreateServiceLinkedRoleInput{
+				AWSServiceName: awslib.String("elasticloadbalancing.amazonaws.com"),
+			}))
+			Expect(logger.StepCall.Messages).To(Equal([]string{"creating the elastic load balancing service-linked role"}))
+		})
+
+		It("succeeds when the role already exists", func() {
+			iamClient.CreateServiceLinkedRoleCall.Returns.Error = awserr.New("InvalidInput", "Service role name AWSServiceRoleForElasticLoadBalancing has been taken in this account, please try a different suffix.", nil)
+
+			err := client.BootstrapAccount()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.StepCall.Messages).To(ContainElement("the elastic load balancing service-linked role already exists"))
+		})
+
+		It("returns other errors", func() {
+			iamClient.CreateServiceLinkedRoleCall.Returns.Error = errors.New("AccessDenied")
+
+			err := client.BootstrapAccount()
+			Expect(err).To(MatchError("Create the elastic load balancing service-linked role: AccessDenied"))
 		})
 	})
 
@@ -108,6 +172,51 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("AvailabilityZones", func() {
+		var retriever *fakes.AvailabilityZoneRetriever
+
+		BeforeEach(func() {
+			retriever = &fakes.AvailabilityZoneRetriever{}
+			retriever.RetrieveAvailabilityZonesCall.Returns.AZs = []string{"us-east-1a", "us-east-1b", "us-east-1c"}
+		})
+
+		It("returns every availability zone in the region when none are pinned", func() {
+			azs, err := aws.AvailabilityZones(retriever, storage.AWS{Region: "us-east-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(azs).To(Equal([]string{"us-east-1a", "us-east-1b", "us-east-1c"}))
+			Expect(retriever.RetrieveAvailabilityZonesCall.Receives.Region).To(Equal("us-east-1"))
+		})
+
+		It("returns the pinned availability zones in order", func() {
+			azs, err := aws.AvailabilityZones(retriever, storage.AWS{Region: "us-east-1", AZs: []string{"us-east-1c", "us-east-1a"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(azs).To(Equal([]string{"us-east-1a", "us-east-1c"}))
+		})
+
+		It("returns the first availability zone of a cheap environment", func() {
+			azs, err := aws.AvailabilityZones(retriever, storage.AWS{Region: "us-east-1", Cheap: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(azs).To(Equal([]string{"us-east-1a"}))
+		})
+
+		It("returns the pinned availability zones of a cheap environment", func() {
+			azs, err := aws.AvailabilityZones(retriever, storage.AWS{Region: "us-east-1", Cheap: true, AZs: []string{"us-east-1b", "us-east-1c"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(azs).To(Equal([]string{"us-east-1b", "us-east-1c"}))
+		})
+
+		It("returns an error when a pinned availability zone is not in the region", func() {
+			_, err := aws.AvailabilityZones(retriever, storage.AWS{Region: "us-east-1", AZs: []string{"us-west-2a"}})
+			Expect(err).To(MatchError("Availability zone us-west-2a is not in us-east-1. Its availability zones are us-east-1a, us-east-1b, us-east-1c."))
+		})
+
+		It("returns an error when the availability zones cannot be retrieved", func() {
+			retriever.RetrieveAvailabilityZonesCall.Returns.Error = errors.New("kumquat")
+			_, err := aws.AvailabilityZones(retriever, storage.AWS{Region: "us-east-1", AZs: []string{"us-east-1a"}})
+			Expect(err).To(MatchError("kumquat"))
+		})
+	})
+
 	Describe("ValidateSafeToDelete", func() {
 		var (
 			client    aws.Client
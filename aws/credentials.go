@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const roleSessionName = "bbl"
+
+type STSClient interface {
+	AssumeRole(*awssts.AssumeRoleInput) (*awssts.AssumeRoleOutput, error)
+	GetSessionToken(*awssts.GetSessionTokenInput) (*awssts.GetSessionTokenOutput, error)
+	GetCallerIdentity(*awssts.GetCallerIdentityInput) (*awssts.GetCallerIdentityOutput, error)
+}
+
+type credentialsLogger interface {
+	PromptForInput(string) string
+}
+
+// CredentialsResolver turns the ways bbl accepts AWS credentials into the
+// access key, secret key and session token that bbl, terraform and the bosh
+// cli sign their requests with.
+type CredentialsResolver struct {
+	logger      credentialsLogger
+	stsClient   func(storage.AWS) STSClient
+	profileKeys func(profile string) (credentials.Value, string, error)
+}
+
+// NewCredentialsResolver returns a CredentialsResolver whose requests to STS
+// are reported to a non-nil calls.
+func NewCredentialsResolver(logger credentialsLogger, calls CallRecorder) CredentialsResolver {
+	return CredentialsResolver{
+		logger: logger,
+		stsClient: func(creds storage.AWS) STSClient {
+			return newSTSClient(creds, calls)
+		},
+		profileKeys: profileKeys,
+	}
+}
+
+// Resolve reads the credentials of a shared config profile, then trades them
+// for temporary credentials from STS: those of a role with --aws-role-arn, or
+// of an MFA session with only --aws-mfa-serial. The MFA code is asked for
+// when it is not given.
+func (r CredentialsResolver) Resolve(creds storage.AWS) (storage.AWS, error) {
+	if creds.Profile != "" {
+		value, region, err := r.profileKeys(creds.Profile)
+		if err != nil {
+			return storage.AWS{}, bblerrors.New(bblerrors.Credentials, fmt.Errorf("Read AWS profile %s: %s", creds.Profile, err))
+		}
+		creds.AccessKeyID = value.AccessKeyID
+		creds.SecretAccessKey = value.SecretAccessKey
+		creds.SessionToken = value.SessionToken
+		if creds.Region == "" {
+			creds.Region = region
+		}
+	}
+
+	if creds.RoleARN == "" && creds.MFASerial == "" {
+		return creds, nil
+	}
+
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return storage.AWS{}, bblerrors.New(bblerrors.Credentials, errors.New("Temporary AWS credentials are requested with the credentials of an IAM user or a profile. Pass --aws-access-key-id and --aws-secret-access-key, or --aws-profile."))
+	}
+
+	var serialNumber, tokenCode *string
+	if creds.MFASerial != "" {
+		code := creds.MFATokenCode
+		if code == "" {
+			code = r.logger.PromptForInput(fmt.Sprintf("MFA code for %s", creds.MFASerial))
+		}
+		serialNumber = awslib.String(creds.MFASerial)
+		tokenCode = awslib.String(code)
+	}
+
+	client := r.stsClient(creds)
+
+	var temporary *awssts.Credentials
+	if creds.RoleARN != "" {
+		output, err := client.AssumeRole(&awssts.AssumeRoleInput{
+			RoleArn:         awslib.String(creds.RoleARN),
+			RoleSessionName: awslib.String(roleSessionName),
+			SerialNumber:    serialNumber,
+			TokenCode:       tokenCode,
+		})
+		if err != nil {
+			return storage.AWS{}, bblerrors.New(bblerrors.Credentials, fmt.Errorf("Assume role %s: %s", creds.RoleARN, err))
+		}
+		temporary = output.Credentials
+	} else {
+		output, err := client.GetSessionToken(&awssts.GetSessionTokenInput{
+			SerialNumber: serialNumber,
+			TokenCode:    tokenCode,
+		})
+		if err != nil {
+			return storage.AWS{}, bblerrors.New(bblerrors.Credentials, fmt.Errorf("Get session token for %s: %s", creds.MFASerial, err))
+		}
+		temporary = output.Credentials
+	}
+
+	creds.AccessKeyID = awslib.StringValue(temporary.AccessKeyId)
+	creds.SecretAccessKey = awslib.StringValue(temporary.SecretAccessKey)
+	creds.SessionToken = awslib.StringValue(temporary.SessionToken)
+	return creds, nil
+}
+
+func newSTSClient(creds storage.AWS, calls CallRecorder) STSClient {
+	region := creds.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	config := &awslib.Config{
+		Credentials: credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+		Region:      awslib.String(region),
+	}
+
+	sess := session.New(config)
+	recordCalls(&sess.Handlers, calls)
+
+	return awssts.New(sess, endpointConfig(creds, "sts"))
+}
+
+// profileKeys reads a profile from ~/.aws/credentials and ~/.aws/config, the
+// way the aws cli does, including profiles that assume a role themselves.
+func profileKeys(profile string) (credentials.Value, string, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Profile:                 profile,
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+	})
+	if err != nil {
+		return credentials.Value{}, "", err
+	}
+
+	value, err := sess.Config.Credentials.Get()
+	if err != nil {
+		return credentials.Value{}, "", err
+	}
+
+	return value, awslib.StringValue(sess.Config.Region), nil
+}
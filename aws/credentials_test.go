@@ -0,0 +1,229 @@
+package aws_test
+
+import (
+	"errors"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CredentialsResolver", func() {
+	var (
+		logger    *fakes.Logger
+		stsClient *fakes.AWSSTSClient
+		resolver  aws.CredentialsResolver
+
+		stsClientCreds storage.AWS
+		profile        string
+		profileValue   credentials.Value
+		profileRegion  string
+		profileError   error
+
+		temporary *awssts.Credentials
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stsClient = &fakes.AWSSTSClient{}
+
+		profile = ""
+		profileValue = credentials.Value{}
+		profileRegion = ""
+		profileError = nil
+
+		resolver = aws.NewCredentialsResolverWithInjectedClients(logger,
+			func(creds storage.AWS) aws.STSClient {
+				stsClientCreds = creds
+				return stsClient
+			},
+			func(p string) (credentials.Value, string, error) {
+				profile = p
+				return profileValue, profileRegion, profileError
+			},
+		)
+
+		temporary = &awssts.Credentials{
+			AccessKeyId:     awslib.String("some-temporary-access-key-id"),
+			SecretAccessKey: awslib.String("some-temporary-secret-access-key"),
+			SessionToken:    awslib.String("some-session-token"),
+		}
+		stsClient.AssumeRoleCall.Returns.Output = &awssts.AssumeRoleOutput{Credentials: temporary}
+		stsClient.GetSessionTokenCall.Returns.Output = &awssts.GetSessionTokenOutput{Credentials: temporary}
+	})
+
+	It("returns the keys of an IAM user as they are", func() {
+		creds := storage.AWS{
+			AccessKeyID:     "some-access-key-id",
+			SecretAccessKey: "some-secret-access-key",
+			SessionToken:    "some-session-token",
+			Region:          "some-region",
+		}
+
+		resolved, err := resolver.Resolve(creds)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(Equal(creds))
+
+		Expect(stsClient.AssumeRoleCall.CallCount).To(Equal(0))
+		Expect(stsClient.GetSessionTokenCall.CallCount).To(Equal(0))
+	})
+
+	Context("when a profile is given", func() {
+		BeforeEach(func() {
+			profileValue = credentials.Value{
+				AccessKeyID:     "some-profile-access-key-id",
+				SecretAccessKey: "some-profile-secret-access-key",
+				SessionToken:    "some-profile-session-token",
+			}
+			profileRegion = "some-profile-region"
+		})
+
+		It("uses the credentials and region of the profile", func() {
+			resolved, err := resolver.Resolve(storage.AWS{Profile: "some-profile"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(profile).To(Equal("some-profile"))
+			Expect(resolved.AccessKeyID).To(Equal("some-profile-access-key-id"))
+			Expect(resolved.SecretAccessKey).To(Equal("some-profile-secret-access-key"))
+			Expect(resolved.SessionToken).To(Equal("some-profile-session-token"))
+			Expect(resolved.Region).To(Equal("some-profile-region"))
+		})
+
+		It("keeps a region that is given", func() {
+			resolved, err := resolver.Resolve(storage.AWS{Profile: "some-profile", Region: "some-region"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolved.Region).To(Equal("some-region"))
+		})
+
+		Context("when the profile cannot be read", func() {
+			BeforeEach(func() {
+				profileError = errors.New("failed to read")
+			})
+
+			It("returns an error", func() {
+				_, err := resolver.Resolve(storage.AWS{Profile: "some-profile"})
+				Expect(err).To(MatchError("Read AWS profile some-profile: failed to read"))
+			})
+		})
+	})
+
+	Context("when a role is given", func() {
+		var creds storage.AWS
+
+		BeforeEach(func() {
+			creds = storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "some-region",
+				RoleARN:         "some-role-arn",
+			}
+		})
+
+		It("assumes the role with the given keys", func() {
+			resolved, err := resolver.Resolve(creds)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stsClientCreds.AccessKeyID).To(Equal("some-access-key-id"))
+			Expect(stsClient.AssumeRoleCall.Receives.Input).To(Equal(&awssts.AssumeRoleInput{
+				RoleArn:         awslib.String("some-role-arn"),
+				RoleSessionName: awslib.String("bbl"),
+			}))
+
+			Expect(resolved.AccessKeyID).To(Equal("some-temporary-access-key-id"))
+			Expect(resolved.SecretAccessKey).To(Equal("some-temporary-secret-access-key"))
+			Expect(resolved.SessionToken).To(Equal("some-session-token"))
+			Expect(resolved.Region).To(Equal("some-region"))
+		})
+
+		Context("when an MFA device is given", func() {
+			BeforeEach(func() {
+				creds.MFASerial = "some-mfa-serial"
+				logger.PromptForInputCall.Returns.Input = "123456"
+			})
+
+			It("prompts for the MFA code", func() {
+				_, err := resolver.Resolve(creds)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PromptForInputCall.Receives.Message).To(Equal("MFA code for some-mfa-serial"))
+				Expect(stsClient.AssumeRoleCall.Receives.Input.SerialNumber).To(Equal(awslib.String("some-mfa-serial")))
+				Expect(stsClient.AssumeRoleCall.Receives.Input.TokenCode).To(Equal(awslib.String("123456")))
+			})
+
+			Context("when the MFA code is given", func() {
+				BeforeEach(func() {
+					creds.MFATokenCode = "654321"
+				})
+
+				It("does not prompt", func() {
+					_, err := resolver.Resolve(creds)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PromptForInputCall.CallCount).To(Equal(0))
+					Expect(stsClient.AssumeRoleCall.Receives.Input.TokenCode).To(Equal(awslib.String("654321")))
+				})
+			})
+		})
+
+		Context("when the role cannot be assumed", func() {
+			BeforeEach(func() {
+				stsClient.AssumeRoleCall.Returns.Error = errors.New("access denied")
+			})
+
+			It("returns an error", func() {
+				_, err := resolver.Resolve(creds)
+				Expect(err).To(MatchError("Assume role some-role-arn: access denied"))
+			})
+		})
+
+		Context("when there are no keys to assume it with", func() {
+			It("returns an error", func() {
+				_, err := resolver.Resolve(storage.AWS{RoleARN: "some-role-arn"})
+				Expect(err).To(MatchError("Temporary AWS credentials are requested with the credentials of an IAM user or a profile. Pass --aws-access-key-id and --aws-secret-access-key, or --aws-profile."))
+			})
+		})
+	})
+
+	Context("when only an MFA device is given", func() {
+		var creds storage.AWS
+
+		BeforeEach(func() {
+			creds = storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				MFASerial:       "some-mfa-serial",
+				MFATokenCode:    "123456",
+			}
+		})
+
+		It("gets a session token", func() {
+			resolved, err := resolver.Resolve(creds)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stsClient.AssumeRoleCall.CallCount).To(Equal(0))
+			Expect(stsClient.GetSessionTokenCall.Receives.Input).To(Equal(&awssts.GetSessionTokenInput{
+				SerialNumber: awslib.String("some-mfa-serial"),
+				TokenCode:    awslib.String("123456"),
+			}))
+			Expect(resolved.SessionToken).To(Equal("some-session-token"))
+		})
+
+		Context("when the session token cannot be got", func() {
+			BeforeEach(func() {
+				stsClient.GetSessionTokenCall.Returns.Error = errors.New("invalid code")
+			})
+
+			It("returns an error", func() {
+				_, err := resolver.Resolve(creds)
+				Expect(err).To(MatchError("Get session token for some-mfa-serial: invalid code"))
+			})
+		})
+	})
+})
@@ -1,5 +1,14 @@
 package aws
 
+import (
+	"time"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
 func NewClientWithInjectedEC2Client(ec2Client EC2Client, logger logger) Client {
 	return Client{
 		ec2Client: ec2Client,
@@ -7,6 +16,97 @@ func NewClientWithInjectedEC2Client(ec2Client EC2Client, logger logger) Client {
 	}
 }
 
+func NewClientWithInjectedIAMClient(iamClient IAMClient, logger logger) Client {
+	return Client{
+		iamClient: iamClient,
+		logger:    logger,
+	}
+}
+
+func NewClientWithInjectedELBClient(elbClient ELBClient, logger logger) Client {
+	return Client{
+		elbClient: elbClient,
+		logger:    logger,
+	}
+}
+
+func NewClientWithInjectedELBV2Client(elbv2Client ELBV2Client, logger logger) Client {
+	return Client{
+		elbv2Client: elbv2Client,
+		logger:      logger,
+	}
+}
+
+func NewClientWithInjectedQuotaClients(ec2Client EC2Client, elbClient ELBClient, elbv2Client ELBV2Client, logger logger) Client {
+	return Client{
+		ec2Client:   ec2Client,
+		elbClient:   elbClient,
+		elbv2Client: elbv2Client,
+		logger:      logger,
+	}
+}
+
+func NewClientWithInjectedClients(ec2Client EC2Client, iamClient IAMClient, logger logger) Client {
+	return Client{
+		ec2Client: ec2Client,
+		iamClient: iamClient,
+		logger:    logger,
+	}
+}
+
+func NewClientWithInjectedSTSClient(stsClient STSClient, logger logger) Client {
+	return Client{
+		stsClient: stsClient,
+		logger:    logger,
+	}
+}
+
 func (c Client) GetEC2Client() EC2Client {
 	return c.ec2Client
 }
+
+func (c Client) GetIAMClient() IAMClient {
+	return c.iamClient
+}
+
+func NewRetryer(maxRetries int, jitter float64, logger debugLogger) retryer {
+	return newRetryer(maxRetries, jitter, logger)
+}
+
+func (r retryer) Delay(retryCount int, throttle bool) time.Duration {
+	return r.delay(retryCount, throttle)
+}
+
+func NewRedactingLogger(logger debugLogger, creds storage.AWS) awslib.Logger {
+	return newRedactingLogger(logger, creds)
+}
+
+func NewCredentialsResolverWithInjectedClients(logger credentialsLogger, stsClient func(storage.AWS) STSClient, profileKeys func(string) (credentials.Value, string, error)) CredentialsResolver {
+	return CredentialsResolver{
+		logger:      logger,
+		stsClient:   stsClient,
+		profileKeys: profileKeys,
+	}
+}
+
+func NewKMSClientWithEndpoint(creds storage.AWS, endpoint string) KMSClient {
+	client := NewKMSClient(creds)
+	client.endpoint = endpoint
+	return client
+}
+
+func KMSEndpoint(client KMSClient) string {
+	return client.endpoint
+}
+
+func SetTimeNow(f func() time.Time) {
+	timeNow = f
+}
+
+func ResetTimeNow() {
+	timeNow = time.Now
+}
+
+func RecordCalls(handlers *request.Handlers, recorder CallRecorder) {
+	recordCalls(handlers, recorder)
+}
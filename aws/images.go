@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ImageState returns the state of an AMI in the region of the client, such
+// as pending or available, or "" when the AMI is not there or not shared
+// with the account.
+func (c Client) ImageState(imageID string) (string, error) {
+	output, err := c.ec2Client.DescribeImages(&awsec2.DescribeImagesInput{
+		ImageIds: []*string{awslib.String(imageID)},
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && strings.HasPrefix(awsErr.Code(), "InvalidAMIID") {
+			return "", nil
+		}
+		return "", fmt.Errorf("Describe image %s: %s", imageID, err)
+	}
+
+	if len(output.Images) == 0 || output.Images[0].State == nil {
+		return "", nil
+	}
+	return *output.Images[0].State, nil
+}
+
+// FindOwnImage returns the id of the AMI of the account with the name in the
+// region of the client, or "" when there is none.
+func (c Client) FindOwnImage(name string) (string, error) {
+	output, err := c.ec2Client.DescribeImages(&awsec2.DescribeImagesInput{
+		Owners: []*string{awslib.String("self")},
+		Filters: []*awsec2.Filter{{
+			Name:   awslib.String("name"),
+			Values: []*string{awslib.String(name)},
+		}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Describe images named %s: %s", name, err)
+	}
+
+	for _, image := range output.Images {
+		if image.ImageId != nil {
+			return *image.ImageId, nil
+		}
+	}
+	return "", nil
+}
+
+// CopyImage starts copying an AMI from another region into the region of
+// the client, and returns the id of the copy, which is pending until the
+// copy finishes.
+func (c Client) CopyImage(sourceRegion, sourceImageID, name string) (string, error) {
+	c.logger.Step("copying the image %s from %s", sourceImageID, sourceRegion)
+
+	output, err := c.ec2Client.CopyImage(&awsec2.CopyImageInput{
+		SourceRegion:  awslib.String(sourceRegion),
+		SourceImageId: awslib.String(sourceImageID),
+		Name:          awslib.String(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Copy image %s from %s: %s", sourceImageID, sourceRegion, err)
+	}
+
+	return awslib.StringValue(output.ImageId), nil
+}
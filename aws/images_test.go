@@ -0,0 +1,122 @@
+package aws_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Images", func() {
+	var (
+		ec2Client *fakes.AWSEC2Client
+		logger    *fakes.Logger
+		client    aws.Client
+	)
+
+	BeforeEach(func() {
+		ec2Client = &fakes.AWSEC2Client{}
+		logger = &fakes.Logger{}
+		client = aws.NewClientWithInjectedEC2Client(ec2Client, logger)
+	})
+
+	Describe("ImageState", func() {
+		It("returns the state of the image", func() {
+			ec2Client.DescribeImagesCall.Returns.Output = &awsec2.DescribeImagesOutput{
+				Images: []*awsec2.Image{{ImageId: awslib.String("ami-1234"), State: awslib.String("available")}},
+			}
+
+			state, err := client.ImageState("ami-1234")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal("available"))
+			Expect(ec2Client.DescribeImagesCall.Receives[0].ImageIds).To(Equal([]*string{awslib.String("ami-1234")}))
+		})
+
+		It("returns nothing when the image is not in the region", func() {
+			ec2Client.DescribeImagesCall.Returns.Error = awserr.New("InvalidAMIID.NotFound", "The image id '[ami-1234]' does not exist", nil)
+
+			state, err := client.ImageState("ami-1234")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(BeEmpty())
+
+			ec2Client.DescribeImagesCall.Returns.Error = nil
+			ec2Client.DescribeImagesCall.Returns.Output = &awsec2.DescribeImagesOutput{}
+
+			state, err = client.ImageState("ami-1234")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(BeEmpty())
+		})
+
+		It("returns an error when the image cannot be described", func() {
+			ec2Client.DescribeImagesCall.Returns.Error = errors.New("failed to describe")
+
+			_, err := client.ImageState("ami-1234")
+			Expect(err).To(MatchError("Describe image ami-1234: failed to describe"))
+		})
+	})
+
+	Describe("FindOwnImage", func() {
+		It("returns the image of the account with the name", func() {
+			ec2Client.DescribeImagesCall.Returns.Output = &awsec2.DescribeImagesOutput{
+				Images: []*awsec2.Image{{ImageId: awslib.String("ami-5678")}},
+			}
+
+			id, err := client.FindOwnImage("some-stemcell-1.2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("ami-5678"))
+
+			input := ec2Client.DescribeImagesCall.Receives[0]
+			Expect(input.Owners).To(Equal([]*string{awslib.String("self")}))
+			Expect(input.Filters).To(Equal([]*awsec2.Filter{{
+				Name:   awslib.String("name"),
+				Values: []*string{awslib.String("some-stemcell-1.2")},
+			}}))
+		})
+
+		It("returns nothing when the account has no such image", func() {
+			ec2Client.DescribeImagesCall.Returns.Output = &awsec2.DescribeImagesOutput{}
+
+			id, err := client.FindOwnImage("some-stemcell-1.2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(BeEmpty())
+		})
+
+		It("returns an error when the images cannot be described", func() {
+			ec2Client.DescribeImagesCall.Returns.Error = errors.New("failed to describe")
+
+			_, err := client.FindOwnImage("some-stemcell-1.2")
+			Expect(err).To(MatchError("Describe images named some-stemcell-1.2: failed to describe"))
+		})
+	})
+
+	Describe("CopyImage", func() {
+		It("copies the image into the region of the client", func() {
+			ec2Client.CopyImageCall.Returns.Output = &awsec2.CopyImageOutput{ImageId: awslib.String("ami-5678")}
+
+			id, err := client.CopyImage("us-east-1", "ami-1234", "some-stemcell-1.2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("ami-5678"))
+
+			Expect(ec2Client.CopyImageCall.Receives.Input).To(Equal(&awsec2.CopyImageInput{
+				SourceRegion:  awslib.String("us-east-1"),
+				SourceImageId: awslib.String("ami-1234"),
+				Name:          awslib.String("some-stemcell-1.2"),
+			}))
+			Expect(logger.StepCall.Messages).To(ConsistOf("copying the image ami-1234 from us-east-1"))
+		})
+
+		It("returns an error when the image cannot be copied", func() {
+			ec2Client.CopyImageCall.Returns.Error = errors.New("failed to copy")
+
+			_, err := client.CopyImage("us-east-1", "ami-1234", "some-stemcell-1.2")
+			Expect(err).To(MatchError("Copy image ami-1234 from us-east-1: failed to copy"))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// KMSClient encrypts and decrypts the data key of an encrypted state with an
+// AWS KMS key. The aws-sdk-go vendored with bbl has no KMS package, so it
+// signs the JSON requests of the KMS API itself.
+type KMSClient struct {
+	endpoint   string
+	region     string
+	signer     *v4.Signer
+	httpClient *http.Client
+}
+
+type kmsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// NewKMSClient returns a KMSClient of the kms endpoint of the region, in its
+// partition, unless the credentials have an endpoint for kms.
+func NewKMSClient(creds storage.AWS) KMSClient {
+	endpoint := creds.EndpointFor("kms")
+	if endpoint == "" {
+		endpoint = PartitionOf(creds.Region).ServiceEndpoint("kms", creds.Region)
+	}
+
+	return KMSClient{
+		endpoint:   endpoint,
+		region:     creds.Region,
+		signer:     v4.NewSigner(credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)),
+		httpClient: http.DefaultClient,
+	}
+}
+
+func (k KMSClient) GenerateDataKey(keyID string) ([]byte, []byte, error) {
+	var output struct {
+		CiphertextBlob []byte
+		Plaintext      []byte
+	}
+	err := k.call("GenerateDataKey", map[string]string{"KeyId": keyID, "KeySpec": "AES_256"}, &output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output.Plaintext, output.CiphertextBlob, nil
+}
+
+func (k KMSClient) Decrypt(ciphertext []byte) ([]byte, error) {
+	var output struct {
+		Plaintext []byte
+	}
+	err := k.call("Decrypt", map[string][]byte{"CiphertextBlob": ciphertext}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Plaintext, nil
+}
+
+func (k KMSClient) call(action string, input, output interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", k.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", fmt.Sprintf("TrentService.%s", action))
+
+	_, err = k.signer.Sign(request, bytes.NewReader(body), "kms", k.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("Sign KMS request: %s", err)
+	}
+
+	response, err := k.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var kmsErr kmsError
+		json.Unmarshal(responseBody, &kmsErr)
+		if kmsErr.Message == "" {
+			kmsErr.Message = string(responseBody)
+		}
+		return fmt.Errorf("KMS %s: %s %s", action, kmsErr.Type, kmsErr.Message)
+	}
+
+	return json.Unmarshal(responseBody, output)
+}
@@ -0,0 +1,102 @@
+package aws_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KMSClient", func() {
+	var (
+		server    *httptest.Server
+		target    string
+		auth      string
+		body      map[string]interface{}
+		status    int
+		response  string
+		kmsClient aws.KMSClient
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target = r.Header.Get("X-Amz-Target")
+			auth = r.Header.Get("Authorization")
+			contents, _ := ioutil.ReadAll(r.Body)
+			body = map[string]interface{}{}
+			json.Unmarshal(contents, &body)
+
+			w.WriteHeader(status)
+			w.Write([]byte(response))
+		}))
+
+		kmsClient = aws.NewKMSClientWithEndpoint(storage.AWS{
+			AccessKeyID:     "some-access-key-id",
+			SecretAccessKey: "some-secret-access-key",
+			Region:          "some-region",
+		}, server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewKMSClient", func() {
+		It("sends the requests to the kms endpoint of the region in its partition", func() {
+			Expect(aws.KMSEndpoint(aws.NewKMSClient(storage.AWS{Region: "us-east-1"}))).To(Equal("https://kms.us-east-1.amazonaws.com/"))
+			Expect(aws.KMSEndpoint(aws.NewKMSClient(storage.AWS{Region: "cn-north-1"}))).To(Equal("https://kms.cn-north-1.amazonaws.com.cn/"))
+		})
+
+		It("sends the requests to the endpoint for kms", func() {
+			client := aws.NewKMSClient(storage.AWS{Region: "us-gov-west-1", Endpoints: map[string]string{"kms": "https://kms.example.com"}})
+			Expect(aws.KMSEndpoint(client)).To(Equal("https://kms.example.com"))
+		})
+	})
+
+	Describe("GenerateDataKey", func() {
+		It("returns the data key and the data key encrypted by the KMS key", func() {
+			response = `{"CiphertextBlob": "Y2lwaGVydGV4dA==", "Plaintext": "cGxhaW50ZXh0", "KeyId": "some-key-id"}`
+
+			plaintext, ciphertext, err := kmsClient.GenerateDataKey("some-key-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(target).To(Equal("TrentService.GenerateDataKey"))
+			Expect(auth).To(ContainSubstring("Credential=some-access-key-id/"))
+			Expect(auth).To(ContainSubstring("/some-region/kms/aws4_request"))
+			Expect(body).To(Equal(map[string]interface{}{"KeyId": "some-key-id", "KeySpec": "AES_256"}))
+
+			Expect(plaintext).To(Equal([]byte("plaintext")))
+			Expect(ciphertext).To(Equal([]byte("ciphertext")))
+		})
+	})
+
+	Describe("Decrypt", func() {
+		It("returns the decrypted data key", func() {
+			response = `{"Plaintext": "cGxhaW50ZXh0"}`
+
+			plaintext, err := kmsClient.Decrypt([]byte("ciphertext"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(target).To(Equal("TrentService.Decrypt"))
+			Expect(body).To(Equal(map[string]interface{}{"CiphertextBlob": "Y2lwaGVydGV4dA=="}))
+			Expect(plaintext).To(Equal([]byte("plaintext")))
+		})
+
+		Context("when KMS returns an error", func() {
+			It("returns the error", func() {
+				status = http.StatusBadRequest
+				response = `{"__type": "AccessDeniedException", "message": "not allowed"}`
+
+				_, err := kmsClient.Decrypt([]byte("ciphertext"))
+				Expect(err).To(MatchError("KMS Decrypt: AccessDeniedException not allowed"))
+			})
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Partition is a group of AWS regions, such as GovCloud or China, with ARNs
+// and service endpoints of its own. Accounts, credentials and images do not
+// cross partitions.
+type Partition struct {
+	ID        string
+	DNSSuffix string
+}
+
+var (
+	commercialPartition = Partition{ID: "aws", DNSSuffix: "amazonaws.com"}
+	govCloudPartition   = Partition{ID: "aws-us-gov", DNSSuffix: "amazonaws.com"}
+	chinaPartition      = Partition{ID: "aws-cn", DNSSuffix: "amazonaws.com.cn"}
+)
+
+// PartitionOf returns the partition of a region, the commercial partition
+// unless the region is one of GovCloud, such as us-gov-west-1, or China,
+// such as cn-north-1.
+func PartitionOf(region string) Partition {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return govCloudPartition
+	case strings.HasPrefix(region, "cn-"):
+		return chinaPartition
+	default:
+		return commercialPartition
+	}
+}
+
+// ARN returns the ARN of a resource of the partition, such as
+// arn:aws-us-gov:s3:::some-bucket. The region and account are empty for
+// services such as s3 and iam whose ARNs leave them out.
+func (p Partition) ARN(service, region, accountID, resource string) string {
+	return fmt.Sprintf("arn:%s:%s:%s:%s:%s", p.ID, service, region, accountID, resource)
+}
+
+// ServiceEndpoint returns the url of the default endpoint of a service in a
+// region of the partition, such as https://kms.cn-north-1.amazonaws.com.cn/.
+func (p Partition) ServiceEndpoint(service, region string) string {
+	return fmt.Sprintf("https://%s.%s.%s/", service, region, p.DNSSuffix)
+}
+
+// SamePartition reports whether two regions are of the same partition.
+func SamePartition(region, otherRegion string) bool {
+	return PartitionOf(region).ID == PartitionOf(otherRegion).ID
+}
@@ -0,0 +1,32 @@
+package aws_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Partition", func() {
+	DescribeTable("PartitionOf",
+		func(region, id, dnsSuffix string) {
+			partition := aws.PartitionOf(region)
+			Expect(partition.ID).To(Equal(id))
+			Expect(partition.DNSSuffix).To(Equal(dnsSuffix))
+		},
+		Entry("a commercial region", "eu-west-1", "aws", "amazonaws.com"),
+		Entry("a GovCloud region", "us-gov-west-1", "aws-us-gov", "amazonaws.com"),
+		Entry("a China region", "cn-northwest-1", "aws-cn", "amazonaws.com.cn"),
+	)
+
+	It("builds the ARNs of the partition", func() {
+		Expect(aws.PartitionOf("us-gov-east-1").ARN("s3", "", "", "some-bucket")).To(Equal("arn:aws-us-gov:s3:::some-bucket"))
+		Expect(aws.PartitionOf("cn-north-1").ARN("iam", "", "123456789012", "role/bbl")).To(Equal("arn:aws-cn:iam::123456789012:role/bbl"))
+	})
+
+	It("tells whether regions are of the same partition", func() {
+		Expect(aws.SamePartition("us-gov-west-1", "us-gov-east-1")).To(BeTrue())
+		Expect(aws.SamePartition("us-gov-west-1", "us-east-1")).To(BeFalse())
+	})
+})
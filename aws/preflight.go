@@ -0,0 +1,96 @@
+package aws
+
+import (
+	"strings"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+)
+
+// RequiredActions are the actions that bbl up calls to create each kind of
+// resource of an environment. bbl validate simulates the policies of the
+// credentials on them, rather than on every action of the templates.
+var RequiredActions = []string{
+	"ec2:CreateVpc",
+	"ec2:CreateSubnet",
+	"ec2:CreateSecurityGroup",
+	"ec2:CreateKeyPair",
+	"ec2:AllocateAddress",
+	"ec2:RunInstances",
+	"ec2:CreateNatGateway",
+	"elasticloadbalancing:CreateLoadBalancer",
+	"iam:CreateRole",
+	"iam:CreateInstanceProfile",
+	"iam:PassRole",
+	"logs:CreateLogGroup",
+}
+
+// CallerIdentity returns the account of the credentials, and the ARN of the
+// user or role they act as.
+func (c Client) CallerIdentity() (string, string, error) {
+	output, err := c.stsClient.GetCallerIdentity(&awssts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", "", err
+	}
+
+	return awslib.StringValue(output.Account), awslib.StringValue(output.Arn), nil
+}
+
+// RegionExists reports whether region is a region that the account can use.
+func (c Client) RegionExists(region string) (bool, error) {
+	output, err := c.ec2Client.DescribeRegions(&awsec2.DescribeRegionsInput{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range output.Regions {
+		if awslib.StringValue(r.RegionName) == region {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeniedActions simulates the policies of the user or role of callerARN on
+// actions, and returns the actions that they do not allow.
+func (c Client) DeniedActions(callerARN string, actions []string) ([]string, error) {
+	input := &awsiam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: awslib.String(principalARN(callerARN)),
+		ActionNames:     awslib.StringSlice(actions),
+	}
+
+	denied := []string{}
+	for {
+		output, err := c.iamClient.SimulatePrincipalPolicy(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range output.EvaluationResults {
+			if awslib.StringValue(result.EvalDecision) != awsiam.PolicyEvaluationDecisionTypeAllowed {
+				denied = append(denied, awslib.StringValue(result.EvalActionName))
+			}
+		}
+
+		if !awslib.BoolValue(output.IsTruncated) {
+			return denied, nil
+		}
+		input.Marker = output.Marker
+	}
+}
+
+// principalARN is the IAM role of the session of an assumed role, such as
+// arn:aws:iam::123456789012:role/bbl of
+// arn:aws:sts::123456789012:assumed-role/bbl/session, which IAM simulates the
+// policies of. Other ARNs are IAM users and roles already.
+func principalARN(callerARN string) string {
+	parts := strings.SplitN(callerARN, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return callerARN
+	}
+
+	role := strings.Split(strings.TrimPrefix(parts[5], "assumed-role/"), "/")[0]
+	return Partition{ID: parts[1]}.ARN("iam", "", parts[4], "role/"+role)
+}
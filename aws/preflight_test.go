@@ -0,0 +1,152 @@
+package aws_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Preflight", func() {
+	Describe("CallerIdentity", func() {
+		var stsClient *fakes.AWSSTSClient
+
+		BeforeEach(func() {
+			stsClient = &fakes.AWSSTSClient{}
+			stsClient.GetCallerIdentityCall.Returns.Output = &awssts.GetCallerIdentityOutput{
+				Account: awslib.String("123456789012"),
+				Arn:     awslib.String("arn:aws:iam::123456789012:user/bbl"),
+			}
+		})
+
+		It("returns the account and arn of the credentials", func() {
+			client := aws.NewClientWithInjectedSTSClient(stsClient, &fakes.Logger{})
+
+			account, arn, err := client.CallerIdentity()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(account).To(Equal("123456789012"))
+			Expect(arn).To(Equal("arn:aws:iam::123456789012:user/bbl"))
+		})
+
+		Context("when the credentials are not valid", func() {
+			It("returns an error", func() {
+				stsClient.GetCallerIdentityCall.Returns.Error = errors.New("InvalidClientTokenId")
+				client := aws.NewClientWithInjectedSTSClient(stsClient, &fakes.Logger{})
+
+				_, _, err := client.CallerIdentity()
+				Expect(err).To(MatchError("InvalidClientTokenId"))
+			})
+		})
+	})
+
+	Describe("RegionExists", func() {
+		var (
+			ec2Client *fakes.AWSEC2Client
+			client    aws.Client
+		)
+
+		BeforeEach(func() {
+			ec2Client = &fakes.AWSEC2Client{}
+			ec2Client.DescribeRegionsCall.Returns.Output = &awsec2.DescribeRegionsOutput{
+				Regions: []*awsec2.Region{
+					{RegionName: awslib.String("us-east-1")},
+					{RegionName: awslib.String("eu-west-1")},
+				},
+			}
+			client = aws.NewClientWithInjectedEC2Client(ec2Client, &fakes.Logger{})
+		})
+
+		It("reports whether the account can use the region", func() {
+			exists, err := client.RegionExists("eu-west-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+
+			exists, err = client.RegionExists("us-east-3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		Context("when the regions cannot be described", func() {
+			It("returns an error", func() {
+				ec2Client.DescribeRegionsCall.Returns.Error = errors.New("UnauthorizedOperation")
+
+				_, err := client.RegionExists("eu-west-1")
+				Expect(err).To(MatchError("UnauthorizedOperation"))
+			})
+		})
+	})
+
+	Describe("DeniedActions", func() {
+		var (
+			iamClient *fakes.AWSIAMClient
+			client    aws.Client
+		)
+
+		BeforeEach(func() {
+			iamClient = &fakes.AWSIAMClient{}
+			iamClient.SimulatePrincipalPolicyCall.Stub = func(input *awsiam.SimulatePrincipalPolicyInput) (*awsiam.SimulatePolicyResponse, error) {
+				if input.Marker == nil {
+					return &awsiam.SimulatePolicyResponse{
+						EvaluationResults: []*awsiam.EvaluationResult{
+							{EvalActionName: awslib.String("ec2:CreateVpc"), EvalDecision: awslib.String("allowed")},
+							{EvalActionName: awslib.String("iam:PassRole"), EvalDecision: awslib.String("implicitDeny")},
+						},
+						IsTruncated: awslib.Bool(true),
+						Marker:      awslib.String("some-marker"),
+					}, nil
+				}
+				return &awsiam.SimulatePolicyResponse{
+					EvaluationResults: []*awsiam.EvaluationResult{
+						{EvalActionName: awslib.String("iam:CreateRole"), EvalDecision: awslib.String("explicitDeny")},
+					},
+				}, nil
+			}
+			client = aws.NewClientWithInjectedIAMClient(iamClient, &fakes.Logger{})
+		})
+
+		It("returns the actions that the policies of the caller do not allow", func() {
+			denied, err := client.DeniedActions("arn:aws:iam::123456789012:user/bbl", []string{"ec2:CreateVpc", "iam:PassRole", "iam:CreateRole"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(denied).To(Equal([]string{"iam:PassRole", "iam:CreateRole"}))
+
+			Expect(iamClient.SimulatePrincipalPolicyCall.CallCount).To(Equal(2))
+			first := iamClient.SimulatePrincipalPolicyCall.Receives[0]
+			Expect(awslib.StringValue(first.PolicySourceArn)).To(Equal("arn:aws:iam::123456789012:user/bbl"))
+			Expect(awslib.StringValueSlice(first.ActionNames)).To(Equal([]string{"ec2:CreateVpc", "iam:PassRole", "iam:CreateRole"}))
+			Expect(awslib.StringValue(iamClient.SimulatePrincipalPolicyCall.Receives[1].Marker)).To(Equal("some-marker"))
+		})
+
+		It("simulates the role of an assumed role session", func() {
+			_, err := client.DeniedActions("arn:aws:sts::123456789012:assumed-role/bbl-deployer/some-session", []string{"ec2:CreateVpc"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(awslib.StringValue(iamClient.SimulatePrincipalPolicyCall.Receives[0].PolicySourceArn)).To(Equal("arn:aws:iam::123456789012:role/bbl-deployer"))
+		})
+
+		It("keeps the partition of an assumed role session", func() {
+			_, err := client.DeniedActions("arn:aws-us-gov:sts::123456789012:assumed-role/bbl-deployer/some-session", []string{"ec2:CreateVpc"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(awslib.StringValue(iamClient.SimulatePrincipalPolicyCall.Receives[0].PolicySourceArn)).To(Equal("arn:aws-us-gov:iam::123456789012:role/bbl-deployer"))
+		})
+
+		Context("when the policies cannot be simulated", func() {
+			It("returns an error", func() {
+				iamClient.SimulatePrincipalPolicyCall.Stub = func(*awsiam.SimulatePrincipalPolicyInput) (*awsiam.SimulatePolicyResponse, error) {
+					return nil, errors.New("AccessDenied")
+				}
+
+				_, err := client.DeniedActions("arn:aws:iam::123456789012:user/bbl", []string{"ec2:CreateVpc"})
+				Expect(err).To(MatchError("AccessDenied"))
+			})
+		})
+	})
+})
@@ -0,0 +1,316 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// DefaultVPCLimit is the number of VPCs that a region allows an account by
+// default. EC2 does not report the limit of an account, so bbl up
+// --skip-quota-check is for accounts whose limit was raised.
+const DefaultVPCLimit = 5
+
+// quotaResource is a resource of the terraform template that counts against a
+// limit of the account, with the number of them that the plan has.
+type quotaResource struct {
+	address string
+	limit   string
+	count   int
+}
+
+const (
+	vpcLimit           = "VPCs per region"
+	elasticIPLimit     = "EC2-VPC Elastic IPs"
+	instanceLimit      = "On-Demand instances"
+	classicLBLimit     = "Classic Load Balancers per region"
+	applicationLBLimit = "Application Load Balancers per region"
+	networkLBLimit     = "Network Load Balancers per region"
+)
+
+var quotaLimitOrder = []string{vpcLimit, elasticIPLimit, instanceLimit, classicLBLimit, applicationLBLimit, networkLBLimit}
+
+// ExistingResources are what an environment has already, which bbl up does
+// not create again: the resources of its terraform state, as terraform state
+// list prints them, and the jumpbox and director that bosh create-env keeps a
+// state of.
+type ExistingResources struct {
+	Terraform []string
+	Jumpbox   bool
+	Director  bool
+}
+
+// QuotaShortfalls checks that the limits of the account in the region of the
+// environment leave room for the VPC, elastic IPs, instances and load
+// balancers that bbl up creates for it. The existing resources of the
+// environment are left out. It returns a message for each limit to raise.
+func (c Client) QuotaShortfalls(state storage.State, existing ExistingResources) ([]string, error) {
+	needs, err := c.quotaNeeds(state, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	shortfalls := []string{}
+	for _, limit := range quotaLimitOrder {
+		need := needs[limit]
+		if need == 0 {
+			continue
+		}
+
+		used, max, err := c.quotaUsage(limit)
+		if err != nil {
+			return nil, fmt.Errorf("Check the limit of %s: %s", limit, err)
+		}
+
+		if used+need > max {
+			shortfall := fmt.Sprintf("The account uses %d of its limit of %d %s in %s, and bbl up needs %d more. Raise the limit of %s before bbl up.", used, max, limit, state.AWS.Region, need, limit)
+			if limit == vpcLimit {
+				shortfall = fmt.Sprintf("%s If it was raised already, pass --skip-quota-check, since EC2 does not report it.", shortfall)
+			}
+			shortfalls = append(shortfalls, shortfall)
+		}
+	}
+
+	return shortfalls, nil
+}
+
+// quotaNeeds are the number of resources of each limit that bbl up creates,
+// by the resources of the plan that the terraform state does not have. The
+// jumpbox and director are created by bosh create-env rather than terraform,
+// so they count until their create-env state is kept.
+func (c Client) quotaNeeds(state storage.State, existing ExistingResources) (map[string]int, error) {
+	resources := []quotaResource{{address: "aws_eip.jumpbox_eip", limit: elasticIPLimit, count: 1}}
+
+	if state.AWS.ExistingVPCID == "" {
+		resources = append(resources, quotaResource{address: "aws_vpc.vpc", limit: vpcLimit, count: 1})
+	}
+
+	natInstance := !state.AWS.Minimal && (!state.AWS.NATGateway || state.AWS.KeepNATInstance)
+	if natInstance {
+		resources = append(resources,
+			quotaResource{address: "aws_instance.nat", limit: instanceLimit, count: 1},
+			quotaResource{address: "aws_eip.nat_eip", limit: elasticIPLimit, count: 1},
+		)
+	}
+
+	if !state.AWS.Minimal && state.AWS.NATGateway {
+		azs, err := AvailabilityZones(c, state.AWS)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, quotaResource{address: "aws_eip.nat_gateway_eips", limit: elasticIPLimit, count: len(azs)})
+	}
+
+	switch state.LB.Type {
+	case "concourse":
+		resources = append(resources, quotaResource{address: "aws_lb.concourse_lb", limit: networkLBLimit, count: 1})
+	case "cf":
+		if state.LB.ELBv2 {
+			resources = append(resources,
+				quotaResource{address: "aws_lb.cf_router_lb", limit: applicationLBLimit, count: 1},
+				quotaResource{address: "aws_lb.cf_ssh_lb", limit: networkLBLimit, count: 1},
+			)
+		} else {
+			resources = append(resources,
+				quotaResource{address: "aws_elb.cf_router_lb", limit: classicLBLimit, count: 1},
+				quotaResource{address: "aws_elb.cf_ssh_lb", limit: classicLBLimit, count: 1},
+			)
+		}
+		resources = append(resources, quotaResource{address: "aws_elb.cf_tcp_lb", limit: classicLBLimit, count: 1})
+
+		if len(state.AWS.SNIDomains) > 0 {
+			resources = append(resources, quotaResource{address: "aws_elb.cf_router_sni_lbs", limit: classicLBLimit, count: len(state.AWS.SNIDomains)})
+		}
+	}
+
+	needs := map[string]int{}
+	for _, resource := range resources {
+		need := resource.count - countResources(existing.Terraform, resource.address)
+		if need > 0 {
+			needs[resource.limit] += need
+		}
+	}
+
+	if !state.NoDirector {
+		if !existing.Jumpbox {
+			needs[instanceLimit]++
+		}
+		if !existing.Director {
+			needs[instanceLimit]++
+		}
+	}
+	return needs, nil
+}
+
+// countResources counts the resources of a terraform state at the address,
+// as address or, with a count, as address[index] or address.index.
+func countResources(resources []string, address string) int {
+	count := 0
+	for _, resource := range resources {
+		if resource == address || strings.HasPrefix(resource, address+"[") || strings.HasPrefix(resource, address+".") {
+			count++
+		}
+	}
+	return count
+}
+
+// quotaUsage returns how many of a limit the account uses in the region, and
+// the limit.
+func (c Client) quotaUsage(limit string) (int, int, error) {
+	switch limit {
+	case vpcLimit:
+		output, err := c.ec2Client.DescribeVpcs(&awsec2.DescribeVpcsInput{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return len(output.Vpcs), DefaultVPCLimit, nil
+	case elasticIPLimit:
+		max, err := c.accountAttribute("vpc-max-elastic-ips")
+		if err != nil {
+			return 0, 0, err
+		}
+		output, err := c.ec2Client.DescribeAddresses(&awsec2.DescribeAddressesInput{
+			Filters: []*awsec2.Filter{{Name: awslib.String("domain"), Values: []*string{awslib.String("vpc")}}},
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		return len(output.Addresses), max, nil
+	case instanceLimit:
+		max, err := c.accountAttribute("max-instances")
+		if err != nil {
+			return 0, 0, err
+		}
+		used, err := c.runningInstances()
+		return used, max, err
+	case classicLBLimit:
+		return c.classicLBUsage()
+	default:
+		return c.elbv2Usage(limit)
+	}
+}
+
+func (c Client) accountAttribute(name string) (int, error) {
+	output, err := c.ec2Client.DescribeAccountAttributes(&awsec2.DescribeAccountAttributesInput{
+		AttributeNames: []*string{awslib.String(name)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, attribute := range output.AccountAttributes {
+		if awslib.StringValue(attribute.AttributeName) != name || len(attribute.AttributeValues) == 0 {
+			continue
+		}
+		return strconv.Atoi(awslib.StringValue(attribute.AttributeValues[0].AttributeValue))
+	}
+	return 0, fmt.Errorf("EC2 did not report the account attribute %s", name)
+}
+
+func (c Client) runningInstances() (int, error) {
+	input := &awsec2.DescribeInstancesInput{
+		Filters: []*awsec2.Filter{{
+			Name:   awslib.String("instance-state-name"),
+			Values: awslib.StringSlice([]string{"pending", "running"}),
+		}},
+	}
+
+	count := 0
+	for {
+		output, err := c.ec2Client.DescribeInstances(input)
+		if err != nil {
+			return 0, err
+		}
+		for _, reservation := range output.Reservations {
+			count += len(reservation.Instances)
+		}
+
+		if awslib.StringValue(output.NextToken) == "" {
+			return count, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func (c Client) classicLBUsage() (int, int, error) {
+	limits, err := c.elbClient.DescribeAccountLimits(&awselb.DescribeAccountLimitsInput{})
+	if err != nil {
+		return 0, 0, err
+	}
+	max := -1
+	for _, limit := range limits.Limits {
+		if awslib.StringValue(limit.Name) == "classic-load-balancers" {
+			max, err = strconv.Atoi(awslib.StringValue(limit.Max))
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if max < 0 {
+		return 0, 0, fmt.Errorf("Elastic Load Balancing did not report the limit classic-load-balancers")
+	}
+
+	input := &awselb.DescribeLoadBalancersInput{}
+	count := 0
+	for {
+		output, err := c.elbClient.DescribeLoadBalancers(input)
+		if err != nil {
+			return 0, 0, err
+		}
+		count += len(output.LoadBalancerDescriptions)
+
+		if awslib.StringValue(output.NextMarker) == "" {
+			return count, max, nil
+		}
+		input.Marker = output.NextMarker
+	}
+}
+
+func (c Client) elbv2Usage(limit string) (int, int, error) {
+	limitName, lbType := "application-load-balancers", awselbv2.LoadBalancerTypeEnumApplication
+	if limit == networkLBLimit {
+		limitName, lbType = "network-load-balancers", awselbv2.LoadBalancerTypeEnumNetwork
+	}
+
+	limits, err := c.elbv2Client.DescribeAccountLimits(&awselbv2.DescribeAccountLimitsInput{})
+	if err != nil {
+		return 0, 0, err
+	}
+	max := -1
+	for _, l := range limits.Limits {
+		if awslib.StringValue(l.Name) == limitName {
+			max, err = strconv.Atoi(awslib.StringValue(l.Max))
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if max < 0 {
+		return 0, 0, fmt.Errorf("Elastic Load Balancing did not report the limit %s", limitName)
+	}
+
+	input := &awselbv2.DescribeLoadBalancersInput{}
+	count := 0
+	for {
+		output, err := c.elbv2Client.DescribeLoadBalancers(input)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, lb := range output.LoadBalancers {
+			if awslib.StringValue(lb.Type) == lbType {
+				count++
+			}
+		}
+
+		if awslib.StringValue(output.NextMarker) == "" {
+			return count, max, nil
+		}
+		input.Marker = output.NextMarker
+	}
+}
@@ -0,0 +1,198 @@
+package aws_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuotaShortfalls", func() {
+	var (
+		ec2Client   *fakes.AWSEC2Client
+		elbClient   *fakes.AWSELBClient
+		elbv2Client *fakes.AWSELBV2Client
+		client      aws.Client
+		state       storage.State
+	)
+
+	BeforeEach(func() {
+		ec2Client = &fakes.AWSEC2Client{}
+		elbClient = &fakes.AWSELBClient{}
+		elbv2Client = &fakes.AWSELBV2Client{}
+
+		ec2Client.DescribeAccountAttributesCall.Returns.Output = &awsec2.DescribeAccountAttributesOutput{
+			AccountAttributes: []*awsec2.AccountAttribute{
+				{
+					AttributeName:   awslib.String("max-instances"),
+					AttributeValues: []*awsec2.AccountAttributeValue{{AttributeValue: awslib.String("20")}},
+				},
+				{
+					AttributeName:   awslib.String("vpc-max-elastic-ips"),
+					AttributeValues: []*awsec2.AccountAttributeValue{{AttributeValue: awslib.String("5")}},
+				},
+			},
+		}
+		ec2Client.DescribeVpcsCall.Returns.Output = &awsec2.DescribeVpcsOutput{Vpcs: []*awsec2.Vpc{{}}}
+		ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{Addresses: []*awsec2.Address{{}, {}}}
+		ec2Client.DescribeInstancesCall.Returns.Output = &awsec2.DescribeInstancesOutput{
+			Reservations: []*awsec2.Reservation{{Instances: []*awsec2.Instance{{}, {}}}},
+		}
+		elbClient.DescribeAccountLimitsCall.Returns.Output = &awselb.DescribeAccountLimitsOutput{
+			Limits: []*awselb.Limit{{Name: awslib.String("classic-load-balancers"), Max: awslib.String("20")}},
+		}
+		elbClient.DescribeLoadBalancersCall.Returns.Output = &awselb.DescribeLoadBalancersOutput{
+			LoadBalancerDescriptions: []*awselb.LoadBalancerDescription{{}},
+		}
+		elbv2Client.DescribeAccountLimitsCall.Returns.Output = &awselbv2.DescribeAccountLimitsOutput{
+			Limits: []*awselbv2.Limit{
+				{Name: awslib.String("application-load-balancers"), Max: awslib.String("20")},
+				{Name: awslib.String("network-load-balancers"), Max: awslib.String("20")},
+			},
+		}
+		elbv2Client.DescribeLoadBalancersCall.Returns.Output = &awselbv2.DescribeLoadBalancersOutput{}
+
+		client = aws.NewClientWithInjectedQuotaClients(ec2Client, elbClient, elbv2Client, &fakes.Logger{})
+
+		state = storage.State{
+			IAAS: "aws",
+			AWS:  storage.AWS{Region: "some-region"},
+		}
+	})
+
+	It("returns no shortfalls when the limits leave room for the environment", func() {
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(BeEmpty())
+
+		Expect(ec2Client.DescribeAddressesCall.Receives.Input.Filters[0].Values).To(Equal([]*string{awslib.String("vpc")}))
+		Expect(elbClient.DescribeLoadBalancersCall.CallCount).To(Equal(0))
+	})
+
+	It("says which limit to raise when a new environment does not fit", func() {
+		ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{Addresses: []*awsec2.Address{{}, {}, {}, {}}}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(Equal([]string{
+			"The account uses 4 of its limit of 5 EC2-VPC Elastic IPs in some-region, and bbl up needs 2 more. Raise the limit of EC2-VPC Elastic IPs before bbl up.",
+		}))
+	})
+
+	It("counts the VPCs against the default limit", func() {
+		ec2Client.DescribeVpcsCall.Returns.Output = &awsec2.DescribeVpcsOutput{Vpcs: make([]*awsec2.Vpc, 5)}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(ConsistOf(ContainSubstring("The account uses 5 of its limit of 5 VPCs per region in some-region, and bbl up needs 1 more.")))
+		Expect(shortfalls[0]).To(ContainSubstring("pass --skip-quota-check"))
+	})
+
+	It("does not need a VPC in an existing VPC", func() {
+		ec2Client.DescribeVpcsCall.Returns.Output = &awsec2.DescribeVpcsOutput{Vpcs: make([]*awsec2.Vpc, 5)}
+		state.AWS.ExistingVPCID = "vpc-1234"
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(BeEmpty())
+	})
+
+	It("counts the classic load balancers of cf", func() {
+		elbClient.DescribeAccountLimitsCall.Returns.Output.Limits[0].Max = awslib.String("3")
+		state.LB = storage.LB{Type: "cf"}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(Equal([]string{
+			"The account uses 1 of its limit of 3 Classic Load Balancers per region in some-region, and bbl up needs 3 more. Raise the limit of Classic Load Balancers per region before bbl up.",
+		}))
+	})
+
+	It("counts the application and network load balancers of cf with ELBv2", func() {
+		elbv2Client.DescribeAccountLimitsCall.Returns.Output.Limits[1].Max = awslib.String("1")
+		elbv2Client.DescribeLoadBalancersCall.Returns.Output = &awselbv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []*awselbv2.LoadBalancer{{Type: awslib.String("network")}, {Type: awslib.String("application")}},
+		}
+		state.LB = storage.LB{Type: "cf", ELBv2: true}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(Equal([]string{
+			"The account uses 1 of its limit of 1 Network Load Balancers per region in some-region, and bbl up needs 1 more. Raise the limit of Network Load Balancers per region before bbl up.",
+		}))
+	})
+
+	It("leaves out the resources that the environment has already", func() {
+		ec2Client.DescribeVpcsCall.Returns.Output = &awsec2.DescribeVpcsOutput{Vpcs: make([]*awsec2.Vpc, 5)}
+		ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{Addresses: make([]*awsec2.Address, 5)}
+		ec2Client.DescribeInstancesCall.Returns.Output = &awsec2.DescribeInstancesOutput{
+			Reservations: []*awsec2.Reservation{{Instances: make([]*awsec2.Instance, 20)}},
+		}
+		state.LB = storage.LB{Type: "cf"}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{
+			Terraform: []string{"aws_vpc.vpc", "aws_eip.jumpbox_eip", "aws_eip.nat_eip", "aws_instance.nat"},
+			Jumpbox:   true,
+			Director:  true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(BeEmpty())
+
+		Expect(ec2Client.DescribeVpcsCall.Receives.Input).To(BeNil())
+		Expect(ec2Client.DescribeInstancesCall.Receives.Input).To(BeNil())
+		Expect(elbClient.DescribeLoadBalancersCall.CallCount).To(Equal(1))
+	})
+
+	It("leaves out the counted resources that the terraform state has", func() {
+		ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{Addresses: make([]*awsec2.Address, 3)}
+		state.AWS.NATGateway = true
+		state.AWS.AZs = []string{"some-region-1a", "some-region-1b"}
+		ec2Client.DescribeAvailabilityZonesCall.Returns.Output = &awsec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []*awsec2.AvailabilityZone{
+				{ZoneName: awslib.String("some-region-1a")},
+				{ZoneName: awslib.String("some-region-1b")},
+			},
+		}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{
+			Terraform: []string{"aws_eip.jumpbox_eip", "aws_eip.nat_gateway_eips[0]", "aws_eip.nat_gateway_eips[1]"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(BeEmpty())
+		Expect(ec2Client.DescribeAddressesCall.CallCount).To(Equal(0))
+	})
+
+	It("counts an elastic IP for each availability zone with NAT gateways", func() {
+		state.AWS.NATGateway = true
+		state.AWS.AZs = []string{"some-region-1a", "some-region-1b", "some-region-1c"}
+		ec2Client.DescribeAvailabilityZonesCall.Returns.Output = &awsec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []*awsec2.AvailabilityZone{
+				{ZoneName: awslib.String("some-region-1a")},
+				{ZoneName: awslib.String("some-region-1b")},
+				{ZoneName: awslib.String("some-region-1c")},
+			},
+		}
+
+		shortfalls, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shortfalls).To(Equal([]string{
+			"The account uses 2 of its limit of 5 EC2-VPC Elastic IPs in some-region, and bbl up needs 4 more. Raise the limit of EC2-VPC Elastic IPs before bbl up.",
+		}))
+	})
+
+	It("returns an error when a limit cannot be read", func() {
+		ec2Client.DescribeAccountAttributesCall.Returns.Error = errors.New("UnauthorizedOperation")
+
+		_, err := client.QuotaShortfalls(state, aws.ExistingResources{})
+		Expect(err).To(MatchError("Check the limit of EC2-VPC Elastic IPs: UnauthorizedOperation"))
+	})
+})
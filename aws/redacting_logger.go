@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const redacted = "[REDACTED]"
+
+var signedHeaders = regexp.MustCompile(`(?m)^((?:Authorization|X-Amz-Security-Token): ).*$`)
+
+// redactingLogger passes the requests and responses that the sdk logs on to
+// the debug log, without the credentials that they were signed with.
+type redactingLogger struct {
+	logger   debugLogger
+	replacer *strings.Replacer
+}
+
+func newRedactingLogger(logger debugLogger, creds storage.AWS) awslib.Logger {
+	var secrets []string
+	for _, secret := range []string{creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken} {
+		if secret != "" {
+			secrets = append(secrets, secret, redacted)
+		}
+	}
+
+	return redactingLogger{
+		logger:   logger,
+		replacer: strings.NewReplacer(secrets...),
+	}
+}
+
+func (l redactingLogger) Log(args ...interface{}) {
+	message := signedHeaders.ReplaceAllString(fmt.Sprint(args...), "${1}"+redacted)
+	l.logger.Debugf("%s", l.replacer.Replace(message))
+}
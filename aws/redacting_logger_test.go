@@ -0,0 +1,28 @@
+package aws_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RedactingLogger", func() {
+	It("logs requests without the credentials", func() {
+		logger := &fakes.Logger{}
+		redactingLogger := aws.NewRedactingLogger(logger, storage.AWS{
+			AccessKeyID:     "some-access-key-id",
+			SecretAccessKey: "some-secret-access-key",
+		})
+
+		redactingLogger.Log("DEBUG: Request ec2/DescribeVpcs Details:\n",
+			"POST / HTTP/1.1\nAuthorization: AWS4-HMAC-SHA256 Credential=some-access-key-id/20180101/us-east-1/ec2/aws4_request, Signature=abc\n",
+			"X-Amz-Security-Token: some-token\nAction=DescribeVpcs&Key=some-secret-access-key")
+
+		Expect(logger.DebugfCall.Messages).To(ConsistOf("DEBUG: Request ec2/DescribeVpcs Details:\n" +
+			"POST / HTTP/1.1\nAuthorization: [REDACTED]\n" +
+			"X-Amz-Security-Token: [REDACTED]\nAction=DescribeVpcs&Key=[REDACTED]"))
+	})
+})
@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"fmt"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+)
+
+// ResourceName is a resource that the terraform templates name after the env
+// id, so that another resource of that name makes bbl up fail partway through.
+type ResourceName struct {
+	Kind string
+	Name string
+}
+
+func (r ResourceName) String() string {
+	return fmt.Sprintf("%s %s", r.Kind, r.Name)
+}
+
+// ResourceNames are the names of the resources of an environment without a
+// load balancer. The IAM names are unique in the account, the others in the
+// region. The certificates and the log group only get a name prefix, and
+// DNS zones may share a name, so they cannot collide.
+func ResourceNames(envID string) []ResourceName {
+	return []ResourceName{
+		{Kind: "vpc", Name: fmt.Sprintf("%s-vpc", envID)},
+		{Kind: "key pair", Name: fmt.Sprintf("%s_bosh_vms", envID)},
+		{Kind: "IAM role", Name: fmt.Sprintf("%s_bosh_role", envID)},
+		{Kind: "IAM role", Name: fmt.Sprintf("%s-flow-logs-role", envID)},
+		{Kind: "IAM instance profile", Name: fmt.Sprintf("%s-bosh", envID)},
+	}
+}
+
+// ConflictingResources returns the resources of ResourceNames that already
+// exist in the account and region of the client.
+func (c Client) ConflictingResources(envID string) ([]string, error) {
+	var conflicts []string
+	for _, resource := range ResourceNames(envID) {
+		var (
+			exists bool
+			err    error
+		)
+
+		switch resource.Kind {
+		case "vpc":
+			exists, err = c.CheckExists(resource.Name)
+		case "key pair":
+			exists, err = c.keyPairExists(resource.Name)
+		case "IAM role":
+			_, err = c.iamClient.GetRole(&awsiam.GetRoleInput{RoleName: awslib.String(resource.Name)})
+			exists, err = iamEntityExists(err)
+		case "IAM instance profile":
+			_, err = c.iamClient.GetInstanceProfile(&awsiam.GetInstanceProfileInput{InstanceProfileName: awslib.String(resource.Name)})
+			exists, err = iamEntityExists(err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Check %s: %s", resource, err)
+		}
+
+		if exists {
+			conflicts = append(conflicts, resource.String())
+		}
+	}
+
+	return conflicts, nil
+}
+
+func (c Client) keyPairExists(name string) (bool, error) {
+	// A filter returns no key pairs where asking for the name returns an
+	// InvalidKeyPair.NotFound error.
+	output, err := c.ec2Client.DescribeKeyPairs(&awsec2.DescribeKeyPairsInput{
+		Filters: []*awsec2.Filter{{
+			Name:   awslib.String("key-name"),
+			Values: []*string{awslib.String(name)},
+		}},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(output.KeyPairs) > 0, nil
+}
+
+func iamEntityExists(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awsiam.ErrCodeNoSuchEntityException {
+		return false, nil
+	}
+
+	return false, err
+}
@@ -0,0 +1,92 @@
+package aws_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	awslib "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResourceNames", func() {
+	It("names the resources that collide after the env id", func() {
+		Expect(aws.ResourceNames("some-env")).To(Equal([]aws.ResourceName{
+			{Kind: "vpc", Name: "some-env-vpc"},
+			{Kind: "key pair", Name: "some-env_bosh_vms"},
+			{Kind: "IAM role", Name: "some-env_bosh_role"},
+			{Kind: "IAM role", Name: "some-env-flow-logs-role"},
+			{Kind: "IAM instance profile", Name: "some-env-bosh"},
+		}))
+	})
+})
+
+var _ = Describe("ConflictingResources", func() {
+	var (
+		ec2Client *fakes.AWSEC2Client
+		iamClient *fakes.AWSIAMClient
+		client    aws.Client
+	)
+
+	BeforeEach(func() {
+		ec2Client = &fakes.AWSEC2Client{}
+		ec2Client.DescribeVpcsCall.Returns.Output = &awsec2.DescribeVpcsOutput{}
+		ec2Client.DescribeKeyPairsCall.Returns.Output = &awsec2.DescribeKeyPairsOutput{}
+
+		iamClient = &fakes.AWSIAMClient{}
+		iamClient.GetRoleCall.Stub = func(*awsiam.GetRoleInput) (*awsiam.GetRoleOutput, error) {
+			return nil, awserr.New(awsiam.ErrCodeNoSuchEntityException, "The role cannot be found.", nil)
+		}
+		iamClient.GetInstanceProfileCall.Stub = func(*awsiam.GetInstanceProfileInput) (*awsiam.GetInstanceProfileOutput, error) {
+			return nil, awserr.New(awsiam.ErrCodeNoSuchEntityException, "The instance profile cannot be found.", nil)
+		}
+
+		client = aws.NewClientWithInjectedClients(ec2Client, iamClient, &fakes.Logger{})
+	})
+
+	It("returns nothing when none of the names are taken", func() {
+		conflicts, err := client.ConflictingResources("some-env")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+
+		Expect(ec2Client.DescribeKeyPairsCall.Receives.Input.Filters[0].Values).To(Equal([]*string{awslib.String("some-env_bosh_vms")}))
+		Expect(iamClient.GetRoleCall.Receives).To(Equal([]*awsiam.GetRoleInput{
+			{RoleName: awslib.String("some-env_bosh_role")},
+			{RoleName: awslib.String("some-env-flow-logs-role")},
+		}))
+		Expect(iamClient.GetInstanceProfileCall.Receives).To(Equal([]*awsiam.GetInstanceProfileInput{
+			{InstanceProfileName: awslib.String("some-env-bosh")},
+		}))
+	})
+
+	It("returns the resources that already exist", func() {
+		ec2Client.DescribeKeyPairsCall.Returns.Output = &awsec2.DescribeKeyPairsOutput{
+			KeyPairs: []*awsec2.KeyPairInfo{{KeyName: awslib.String("some-env_bosh_vms")}},
+		}
+		iamClient.GetRoleCall.Stub = func(input *awsiam.GetRoleInput) (*awsiam.GetRoleOutput, error) {
+			if *input.RoleName == "some-env_bosh_role" {
+				return &awsiam.GetRoleOutput{}, nil
+			}
+			return nil, awserr.New(awsiam.ErrCodeNoSuchEntityException, "The role cannot be found.", nil)
+		}
+
+		conflicts, err := client.ConflictingResources("some-env")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(Equal([]string{"key pair some-env_bosh_vms", "IAM role some-env_bosh_role"}))
+	})
+
+	It("returns an error when a resource cannot be checked", func() {
+		iamClient.GetInstanceProfileCall.Stub = func(*awsiam.GetInstanceProfileInput) (*awsiam.GetInstanceProfileOutput, error) {
+			return nil, errors.New("AccessDenied")
+		}
+
+		_, err := client.ConflictingResources("some-env")
+		Expect(err).To(MatchError("Check IAM instance profile some-env-bosh: AccessDenied"))
+	})
+})
@@ -0,0 +1,78 @@
+package aws
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	DefaultMaxRetries  = 10
+	DefaultRetryJitter = 0.5
+)
+
+// retryer backs off exponentially between attempts of a retryable or
+// throttled request, in the same way as the sdk's default retryer, but
+// picks a configurable share of each delay at random so that many
+// callers in one account do not retry in step.
+type retryer struct {
+	client.DefaultRetryer
+	jitter float64
+	logger debugLogger
+}
+
+var (
+	jitterRand     = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterRandLock sync.Mutex
+)
+
+func newRetryer(maxRetries int, jitter float64, logger debugLogger) retryer {
+	return retryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: maxRetries},
+		jitter:         jitter,
+		logger:         logger,
+	}
+}
+
+func (r retryer) RetryRules(req *request.Request) time.Duration {
+	delay := r.delay(req.RetryCount, isThrottle(req))
+
+	r.logger.Debugf("retrying %s/%s after %s (attempt %d of %d): %s",
+		req.ClientInfo.ServiceName, req.Operation.Name, delay, req.RetryCount+2, r.MaxRetries()+1, req.Error)
+
+	return delay
+}
+
+func (r retryer) delay(retryCount int, throttle bool) time.Duration {
+	base := 60 * time.Millisecond
+	if throttle {
+		base = time.Second
+	}
+
+	if throttle && retryCount > 8 {
+		retryCount = 8
+	} else if retryCount > 13 {
+		retryCount = 13
+	}
+
+	jitterRandLock.Lock()
+	random := jitterRand.Float64()
+	jitterRandLock.Unlock()
+
+	max := base * time.Duration(1<<uint(retryCount))
+	return max - time.Duration(float64(max)*r.jitter*random)
+}
+
+func isThrottle(req *request.Request) bool {
+	if req.HTTPResponse != nil {
+		switch req.HTTPResponse.StatusCode {
+		case 429, 502, 503, 504:
+			return true
+		}
+	}
+
+	return req.IsErrorThrottle()
+}
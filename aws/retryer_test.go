@@ -0,0 +1,76 @@
+package aws_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retryer", func() {
+	Describe("Delay", func() {
+		It("doubles the delay with every attempt", func() {
+			retryer := aws.NewRetryer(10, 0, &fakes.Logger{})
+
+			Expect(retryer.Delay(0, false)).To(Equal(60 * time.Millisecond))
+			Expect(retryer.Delay(1, false)).To(Equal(120 * time.Millisecond))
+			Expect(retryer.Delay(3, false)).To(Equal(480 * time.Millisecond))
+		})
+
+		It("waits longer when the request was throttled", func() {
+			retryer := aws.NewRetryer(10, 0, &fakes.Logger{})
+
+			Expect(retryer.Delay(0, true)).To(Equal(time.Second))
+			Expect(retryer.Delay(2, true)).To(Equal(4 * time.Second))
+			Expect(retryer.Delay(12, true)).To(Equal(256 * time.Second))
+		})
+
+		It("takes up to the jitter share off each delay at random", func() {
+			retryer := aws.NewRetryer(10, 0.5, &fakes.Logger{})
+
+			for i := 0; i < 20; i++ {
+				delay := retryer.Delay(2, true)
+				Expect(delay).To(BeNumerically(">", 2*time.Second))
+				Expect(delay).To(BeNumerically("<=", 4*time.Second))
+			}
+		})
+	})
+
+	Describe("RetryRules", func() {
+		var req *request.Request
+
+		BeforeEach(func() {
+			req = &request.Request{
+				ClientInfo:   metadata.ClientInfo{ServiceName: "ec2"},
+				Operation:    &request.Operation{Name: "DescribeVpcs"},
+				HTTPResponse: &http.Response{StatusCode: 400},
+				Error:        awserr.New("Throttling", "Rate exceeded", nil),
+				RetryCount:   1,
+			}
+		})
+
+		It("backs off as for a throttled request", func() {
+			retryer := aws.NewRetryer(10, 0, &fakes.Logger{})
+
+			Expect(retryer.RetryRules(req)).To(Equal(2 * time.Second))
+		})
+
+		It("logs the attempt count", func() {
+			logger := &fakes.Logger{}
+			retryer := aws.NewRetryer(10, 0, logger)
+
+			retryer.RetryRules(req)
+
+			Expect(logger.DebugfCall.Messages).To(ConsistOf(
+				"retrying ec2/DescribeVpcs after 2s (attempt 3 of 11): Throttling: Rate exceeded",
+			))
+		})
+	})
+})
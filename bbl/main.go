@@ -1,26 +1,30 @@
 package main
 
 import (
-	"bytes"
 	"crypto/rand"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
-	"path/filepath"
+	"time"
 
+	bbl "github.com/cloudfoundry/bosh-bootloader"
 	"github.com/cloudfoundry/bosh-bootloader/application"
 	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/azure"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/catalog"
 	"github.com/cloudfoundry/bosh-bootloader/certs"
 	"github.com/cloudfoundry/bosh-bootloader/cloudconfig"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/config"
 	"github.com/cloudfoundry/bosh-bootloader/gcp"
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/ssh"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
-	proxy "github.com/cloudfoundry/socks5-proxy"
 	"github.com/spf13/afero"
 
 	awscloudconfig "github.com/cloudfoundry/bosh-bootloader/cloudconfig/aws"
@@ -51,55 +55,140 @@ func main() {
 
 	globals, _, err := config.ParseArgs(os.Args)
 	if err != nil {
-		log.Fatalf("\n\n%s\n", err)
+		fail(bblerrors.New(bblerrors.Validation, err))
 	}
 	if globals.NoConfirm {
 		logger.NoConfirm()
 	}
+	messages, err := catalog.New(globals.Lang)
+	if err != nil {
+		fail(err)
+	}
 
 	// File IO
 	fs := afero.NewOsFs()
 	afs := &afero.Afero{Fs: fs}
 
 	// bbl Configuration
-	stateStore := storage.NewStore(globals.StateDir, afs)
+	stateSerializer, err := storage.NewStateSerializer(globals.StateFormat, globals.StateDir)
+	if err != nil {
+		fail(bblerrors.New(bblerrors.Validation, err))
+	}
+	stateCipher := storage.NewStateCipher(globals.StatePassphrase)
+	stateStore := storage.NewStore(globals.StateDir, afs, stateSerializer, stateCipher)
 	stateMigrator := storage.NewMigrator(stateStore, afs)
 	newConfig := config.NewConfig(stateBootstrap, stateMigrator, stderrLogger, afs)
 
 	appConfig, err := newConfig.Bootstrap(os.Args)
 	if err != nil {
-		log.Fatalf("\n\n%s\n", err)
+		fail(err)
+	}
+	if appConfig.Global.Debug {
+		logger.Debug(os.Stderr)
+		stderrLogger.Debug(os.Stderr)
+	}
+
+	// The proxy is set before the first request, as the clients read it once.
+	if appConfig.Global.ProxyURL != "" {
+		err = application.UseProxy(appConfig.Global.ProxyURL)
+		if err != nil {
+			fail(fmt.Errorf("Use proxy: %s", err))
+		}
+	}
+
+	// The command runs until it completes, bbl is interrupted or --timeout is
+	// up. terraform and bosh create-env are interrupted along with it.
+	ctx, stopInterrupt := application.NotifyInterrupt(appConfig.Global.Timeout)
+	defer stopInterrupt()
+
+	// The health endpoint is served until the command completes. With
+	// --no-wait, the command that runs in the background serves it.
+	var healthServer *http.Server
+	if appConfig.Global.HealthListen != "" && !appConfig.Global.NoWait {
+		health := application.NewHealth(appConfig.Command)
+		logger.Health(health)
+		stderrLogger.Health(health)
+		healthServer, err = application.ListenHealth(appConfig.Global.HealthListen, health)
+		if err != nil {
+			fail(fmt.Errorf("Listen for health checks: %s", err))
+		}
+	}
+
+	// The profile records the steps and the requests to AWS of the command
+	// and is written once it completes. With --no-wait, the command that runs
+	// in the background writes it.
+	var (
+		profile  *application.Profile
+		awsCalls aws.CallRecorder
+	)
+	if appConfig.Global.ProfileRun != "" && !appConfig.Global.NoWait {
+		profile = application.NewProfile(appConfig.Command)
+		logger.Profile(profile)
+		stderrLogger.Profile(profile)
+		awsCalls = profile
 	}
 
 	needsIAASCreds := config.NeedsIAASCreds(appConfig.Command) && !appConfig.ShowCommandHelp
+	encryption := appConfig.State.Encryption
+	needsKMS := appConfig.Command == "state" || (encryption != nil && encryption.Method == storage.KMSEncryption)
+	credentialsResolver := aws.NewCredentialsResolver(stderrLogger, awsCalls)
+	if appConfig.State.IAAS == "aws" && (needsIAASCreds || needsKMS) {
+		appConfig.State.AWS, err = credentialsResolver.Resolve(appConfig.State.AWS)
+		if err != nil {
+			fail(err)
+		}
+	}
 	if needsIAASCreds {
 		err = config.ValidateIAAS(appConfig.State)
 		if err != nil {
-			log.Fatal(err)
+			log.Print(err)
+			os.Exit(bblerrors.ExitCode(err))
+		}
+	}
+	if appConfig.State.IAAS == "aws" && needsIAASCreds && application.IsMutating(appConfig.Command, appConfig.SubcommandFlags) {
+		appConfig.State, err = credentialsResolver.VerifyAccount(appConfig.State, appConfig.Global.OverrideAccountCheck)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	// The state is decrypted once the credentials of a KMS key are known.
+	if encryption != nil && !appConfig.ShowCommandHelp && appConfig.Command != "help" && appConfig.Command != "version" {
+		var kmsClient storage.KMSClient
+		if encryption.Method == storage.KMSEncryption {
+			kmsClient = aws.NewKMSClient(encryption.KMSCredentials(appConfig.State.AWS))
+		}
+		err = stateCipher.Unlock(encryption, kmsClient)
+		if err != nil {
+			fail(err)
+		}
+		appConfig.State, err = stateCipher.Decrypt(appConfig.State)
+		if err != nil {
+			fail(err)
 		}
 	}
 
 	// Utilities
 	envIDGenerator := helpers.NewEnvIDGenerator(rand.Reader)
 	stateValidator := application.NewStateValidator(appConfig.Global.StateDir)
+	stateBackups := storage.NewStateBackups(appConfig.Global.StateDir)
 	certificateValidator := certs.NewValidator()
 	lbArgsHandler := commands.NewLBArgsHandler(certificateValidator)
 
-	// Terraform
-	terraformOutputBuffer := bytes.NewBuffer([]byte{})
-	terraformCmd := terraform.NewCmd(os.Stderr, terraformOutputBuffer, filepath.Join(appConfig.Global.StateDir, "terraform", ".terraform"))
-	terraformExecutor := terraform.NewExecutor(terraformCmd, stateStore, afs, appConfig.Global.Debug)
-
-	// BOSH
-	hostKey := proxy.NewHostKey()
-	socks5Proxy := proxy.NewSocks5Proxy(hostKey, nil)
-	boshCommand := bosh.NewCmd(os.Stderr)
-	boshExecutor := bosh.NewExecutor(boshCommand, afs, json.Unmarshal, json.Marshal)
-	sshKeyGetter := bosh.NewSSHKeyGetter(stateStore, afs)
+	// Terraform and BOSH, which bbl.Client runs the same way.
+	wiring := bbl.NewWiring(ctx, bbl.WiringConfig{
+		StateDir: appConfig.Global.StateDir,
+		Stderr:   os.Stderr,
+		Debug:    appConfig.Global.Debug,
+		Version:  Version,
+	}, stateStore, afs, logger, stderrLogger)
+	boshManager := wiring.BOSHManager
+	boshClientProvider := wiring.BOSHClientProvider
+	directorVerifier := wiring.DirectorVerifier
+	sshKeyGetter := wiring.SSHKeyGetter
+	sshCertIssuer := bosh.NewSSHCertIssuer(stateStore, afs)
 	allProxyGetter := bosh.NewAllProxyGetter(sshKeyGetter, afs)
 	credhubGetter := bosh.NewCredhubGetter(stateStore, afs)
-	boshManager := bosh.NewManager(boshExecutor, logger, stateStore, sshKeyGetter, afs)
-	boshClientProvider := bosh.NewClientProvider(socks5Proxy, sshKeyGetter)
 
 	// Clients that require IAAS credentials.
 	var (
@@ -108,25 +197,59 @@ func main() {
 
 		availabilityZoneRetriever aws.AvailabilityZoneRetriever
 		leftovers                 commands.FilteredDeleter
+		leftoversLister           commands.FilteredDeleter
+		accountBootstrapper       commands.AccountBootstrapper
+		imageCopier               commands.ImageCopier
+		certificateUploader       commands.CertificateUploader
+		certificateRotator        commands.CertificateRotator
+		preflightClient           commands.PreflightClient
+		quotaChecker              commands.QuotaChecker
 	)
+	// The leftovers of bbl cleanup-leftovers --dry-run list what they would
+	// delete instead of asking.
+	dryRunLogger := commands.NewLeftoversLister(logger)
 	if needsIAASCreds {
 		switch appConfig.State.IAAS {
 		case "aws":
-			awsClient := aws.NewClient(appConfig.State.AWS, logger)
+			awsClient := aws.NewClient(appConfig.State.AWS, logger, appConfig.Global.Debug, awsCalls)
 
 			availabilityZoneRetriever = awsClient
+			if region := commands.MigrateRegionTarget(appConfig.SubcommandFlags); appConfig.Command == "migrate-region" && region != "" {
+				targetCreds := appConfig.State.AWS
+				targetCreds.Region = region
+				availabilityZoneRetriever = aws.NewClient(targetCreds, logger, appConfig.Global.Debug, awsCalls)
+			}
+			if !appConfig.Global.NoCache {
+				availabilityZoneRetriever, err = wiring.CachingAvailabilityZoneRetriever(availabilityZoneRetriever)
+				if err != nil {
+					fail(err)
+				}
+			}
 			networkDeletionValidator = awsClient
 			networkClient = awsClient
-
+			accountBootstrapper = awsClient
+			imageCopier = awsClient
+			certificateUploader = awsClient
+			certificateRotator = awsClient
+			preflightClient = awsClient
+			quotaChecker = awsClient
+
+			if appConfig.State.AWS.SessionToken != "" && (appConfig.Command == "cleanup-leftovers" || appConfig.Command == "clean-leftovers") {
+				fail(bblerrors.New(bblerrors.Credentials, errors.New("cleanup-leftovers does not support temporary AWS credentials. Pass the keys of an IAM user.")))
+			}
 			leftovers, err = awsleftovers.NewLeftovers(logger, appConfig.State.AWS.AccessKeyID, appConfig.State.AWS.SecretAccessKey, appConfig.State.AWS.Region)
 			if err != nil {
-				log.Fatalf("\n\n%s\n", err)
+				fail(err)
+			}
+			leftoversLister, err = awsleftovers.NewLeftovers(dryRunLogger, appConfig.State.AWS.AccessKeyID, appConfig.State.AWS.SecretAccessKey, appConfig.State.AWS.Region)
+			if err != nil {
+				fail(err)
 			}
 
 		case "gcp":
 			gcpClient, err := gcp.NewClient(appConfig.State.GCP, "")
 			if err != nil {
-				log.Fatalf("\n\n%s\n", err)
+				fail(err)
 			}
 
 			networkDeletionValidator = gcpClient
@@ -135,19 +258,23 @@ func main() {
 			gcpZonerHack := config.NewGCPZonerHack(gcpClient)
 			stateWithZones, err := gcpZonerHack.SetZones(appConfig.State)
 			if err != nil {
-				log.Fatalf("\n\n%s\n", err)
+				fail(err)
 			}
 			appConfig.State = stateWithZones
 
 			leftovers, err = gcpleftovers.NewLeftovers(logger, appConfig.State.GCP.ServiceAccountKeyPath)
 			if err != nil {
-				log.Fatalf("\n\n%s\n", err)
+				fail(err)
+			}
+			leftoversLister, err = gcpleftovers.NewLeftovers(dryRunLogger, appConfig.State.GCP.ServiceAccountKeyPath)
+			if err != nil {
+				fail(err)
 			}
 
 		case "azure":
 			azureClient, err := azure.NewClient(appConfig.State.Azure)
 			if err != nil {
-				log.Fatalf("\n\n%s\n", err)
+				fail(err)
 			}
 
 			networkDeletionValidator = azureClient
@@ -155,7 +282,11 @@ func main() {
 
 			leftovers, err = azureleftovers.NewLeftovers(logger, appConfig.State.Azure.ClientID, appConfig.State.Azure.ClientSecret, appConfig.State.Azure.SubscriptionID, appConfig.State.Azure.TenantID)
 			if err != nil {
-				log.Fatalf("\n\n%s\n", err)
+				fail(err)
+			}
+			leftoversLister, err = azureleftovers.NewLeftovers(dryRunLogger, appConfig.State.Azure.ClientID, appConfig.State.Azure.ClientSecret, appConfig.State.Azure.SubscriptionID, appConfig.State.Azure.TenantID)
+			if err != nil {
+				fail(err)
 			}
 		}
 	}
@@ -175,7 +306,7 @@ func main() {
 		templateGenerator = awsterraform.NewTemplateGenerator()
 		inputGenerator = awsterraform.NewInputGenerator(availabilityZoneRetriever)
 
-		terraformManager = terraform.NewManager(terraformExecutor, templateGenerator, inputGenerator, terraformOutputBuffer, logger)
+		terraformManager = wiring.TerraformManager(templateGenerator, inputGenerator)
 
 		cloudConfigOpsGenerator = awscloudconfig.NewOpsGenerator(terraformManager, availabilityZoneRetriever)
 
@@ -184,7 +315,7 @@ func main() {
 		templateGenerator = azureterraform.NewTemplateGenerator()
 		inputGenerator = azureterraform.NewInputGenerator()
 
-		terraformManager = terraform.NewManager(terraformExecutor, templateGenerator, inputGenerator, terraformOutputBuffer, logger)
+		terraformManager = wiring.TerraformManager(templateGenerator, inputGenerator)
 
 		cloudConfigOpsGenerator = azurecloudconfig.NewOpsGenerator(terraformManager)
 
@@ -193,7 +324,7 @@ func main() {
 		templateGenerator = gcpterraform.NewTemplateGenerator()
 		inputGenerator = gcpterraform.NewInputGenerator()
 
-		terraformManager = terraform.NewManager(terraformExecutor, templateGenerator, inputGenerator, terraformOutputBuffer, logger)
+		terraformManager = wiring.TerraformManager(templateGenerator, inputGenerator)
 
 		cloudConfigOpsGenerator = gcpcloudconfig.NewOpsGenerator(terraformManager)
 
@@ -202,7 +333,7 @@ func main() {
 		templateGenerator = vsphereterraform.NewTemplateGenerator()
 		inputGenerator = vsphereterraform.NewInputGenerator()
 
-		terraformManager = terraform.NewManager(terraformExecutor, templateGenerator, inputGenerator, terraformOutputBuffer, logger)
+		terraformManager = wiring.TerraformManager(templateGenerator, inputGenerator)
 
 		cloudConfigOpsGenerator = vspherecloudconfig.NewOpsGenerator(terraformManager)
 
@@ -210,50 +341,129 @@ func main() {
 		templateGenerator = openstackterraform.NewTemplateGenerator()
 		inputGenerator = openstackterraform.NewInputGenerator()
 
-		terraformManager = terraform.NewManager(terraformExecutor, templateGenerator, inputGenerator, terraformOutputBuffer, logger)
+		terraformManager = wiring.TerraformManager(templateGenerator, inputGenerator)
 
 		cloudConfigOpsGenerator = openstackcloudconfig.NewOpsGenerator(terraformManager)
 	}
 
-	cloudConfigManager := cloudconfig.NewManager(logger, boshCommand, stateStore, cloudConfigOpsGenerator, boshClientProvider, terraformManager, afs)
-
 	// Commands
 	var envIDManager helpers.EnvIDManager
 	if appConfig.State.IAAS != "" {
 		envIDManager = helpers.NewEnvIDManager(envIDGenerator, networkClient)
 	}
-	plan := commands.NewPlan(boshManager, cloudConfigManager, stateStore, envIDManager, terraformManager, lbArgsHandler, stderrLogger, Version)
-	up := commands.NewUp(plan, boshManager, cloudConfigManager, stateStore, terraformManager)
+	wired := wiring.Commands(bbl.WiringIAAS{
+		TerraformManager:         terraformManager,
+		CloudConfigOpsGenerator:  cloudConfigOpsGenerator,
+		EnvIDManager:             envIDManager,
+		NetworkDeletionValidator: networkDeletionValidator,
+		Leftovers:                leftovers,
+		AccountBootstrapper:      accountBootstrapper,
+		QuotaChecker:             quotaChecker,
+	}, lbArgsHandler, stateValidator)
+	cloudConfigManager := wired.CloudConfigManager
+	plan := wired.Plan
+	up := wired.Up
 	usage := commands.NewUsage(logger)
+	output := commands.NewOutputFormatter(logger, appConfig.Global.JSON)
 
 	commandSet := application.CommandSet{}
 	commandSet["help"] = usage
-	commandSet["version"] = commands.NewVersion(Version, logger)
-	commandSet["outputs"] = commands.NewOutputs(logger, terraformManager, stateValidator)
+	commandSet["version"] = commands.NewVersion(Version, logger, templateGenerator, output)
+	commandSet["outputs"] = commands.NewOutputs(output, terraformManager, stateValidator)
 	commandSet["up"] = up
 	commandSet["plan"] = plan
 	sshKeyDeleter := bosh.NewSSHKeyDeleter(stateStore, afs)
 	commandSet["rotate"] = commands.NewRotate(stateValidator, sshKeyDeleter, up)
-	commandSet["destroy"] = commands.NewDestroy(plan, logger, boshManager, stateStore, stateValidator, terraformManager, networkDeletionValidator)
+	commandSet["rotate-keypair"] = commands.NewRotateKeyPair(stateValidator, terraformManager, up)
+	directorCredentialsDeleter := bosh.NewDirectorCredentialsDeleter(stateStore, afs)
+	commandSet["rotate-director-credentials"] = commands.NewRotateDirectorCredentials(stateValidator, directorCredentialsDeleter, up)
+	commandSet["upgrade-director"] = commands.NewUpgradeDirector(stateValidator, boshManager, terraformManager, cloudConfigManager, stateStore, directorVerifier, logger)
+	commandSet["destroy"] = wired.Destroy
 	commandSet["down"] = commandSet["destroy"]
-	commandSet["cleanup-leftovers"] = commands.NewCleanupLeftovers(leftovers)
+	commandSet["cleanup-leftovers"] = commands.NewCleanupLeftovers(leftovers, leftoversLister, logger)
 	commandSet["leftovers"] = commandSet["cleanup-leftovers"]
-	commandSet["lbs"] = commands.NewLBs(lbsCmd, stateValidator)
-	commandSet["jumpbox-address"] = commands.NewStateQuery(logger, stateValidator, terraformManager, commands.JumpboxAddressPropertyName)
-	commandSet["director-address"] = commands.NewStateQuery(logger, stateValidator, terraformManager, commands.DirectorAddressPropertyName)
-	commandSet["director-username"] = commands.NewStateQuery(logger, stateValidator, terraformManager, commands.DirectorUsernamePropertyName)
-	commandSet["director-password"] = commands.NewStateQuery(logger, stateValidator, terraformManager, commands.DirectorPasswordPropertyName)
-	commandSet["director-ca-cert"] = commands.NewStateQuery(logger, stateValidator, terraformManager, commands.DirectorCACertPropertyName)
-	commandSet["ssh-key"] = commands.NewSSHKey(logger, stateValidator, sshKeyGetter)
-	commandSet["director-ssh-key"] = commands.NewDirectorSSHKey(logger, stateValidator, sshKeyGetter)
-	commandSet["env-id"] = commands.NewStateQuery(logger, stateValidator, terraformManager, commands.EnvIDPropertyName)
+	commandSet["clean-leftovers"] = commandSet["cleanup-leftovers"]
+	commandSet["detach-lb"] = commands.NewDetachLB(stateValidator, terraformManager, stateStore, afs, logger)
+	commandSet["adopt-lb"] = commands.NewAdoptLB(stateValidator, terraformManager, stateStore, afs, logger)
+	commandSet["clone"] = commands.NewClone(stateBootstrap, plan, up, terraformManager, stateStore, afs, logger)
+	commandSet["apply"] = commands.NewApply(plan, up, afs, logger)
+	commandSet["migrate-region"] = commands.NewMigrateRegion(stateValidator, plan, up, stateStore, afs, logger)
+	commandSet["migrate-commands"] = commands.NewMigrateCommands(logger, afs)
+	commandSet["bootstrap-account"] = commands.NewBootstrapAccount(accountBootstrapper)
+	commandSet["upload-certificate"] = commands.NewUploadCertificate(stateValidator, certificateValidator, certificateUploader, stateStore, logger)
+	commandSet["create-certificate"] = commands.NewCreateCertificate(stateValidator, certificateValidator, certificateUploader, stateStore, logger)
+	commandSet["attach-certificate"] = commands.NewAttachCertificate(stateValidator, stateStore, logger)
+	commandSet["update-security-groups"] = commands.NewUpdateSecurityGroups(stateValidator, terraformManager, stateStore, logger)
+	commandSet["rotate-certificate"] = commands.NewRotateCertificate(stateValidator, certificateValidator, certificateRotator, terraformManager, stateStore, logger, time.Now)
+	commandSet["pin-artifacts"] = commands.NewPinArtifacts(stateValidator, bosh.NewBOSHIO(http.DefaultClient, "https://bosh.io"), stateStore, logger)
+	commandSet["validate"] = commands.NewValidate(preflightClient, &http.Client{Timeout: 30 * time.Second}, stateStore, afs, output)
+	commandSet["download-artifacts"] = commands.NewDownloadArtifacts(stateValidator, bosh.NewArtifactDownloader(http.DefaultClient), logger)
+	commandSet["copy-stemcell-ami"] = commands.NewCopyStemcellAMI(stateValidator, stateStore, imageCopier, http.DefaultClient, afs, logger, 15*time.Second)
+	for _, name := range commands.DeprecatedCommandNames() {
+		commandSet[name] = commands.NewDeprecated(name, certificateValidator, logger)
+	}
+	commandSet["lbs"] = commands.NewLBs(lbsCmd, stateValidator, output)
+	commandSet["jumpbox-address"] = commands.NewStateQuery(output, stateValidator, terraformManager, commands.JumpboxAddressPropertyName)
+	commandSet["director-address"] = commands.NewStateQuery(output, stateValidator, terraformManager, commands.DirectorAddressPropertyName)
+	commandSet["director-username"] = commands.NewStateQuery(output, stateValidator, terraformManager, commands.DirectorUsernamePropertyName)
+	commandSet["director-password"] = commands.NewStateQuery(output, stateValidator, terraformManager, commands.DirectorPasswordPropertyName)
+	commandSet["director-ca-cert"] = commands.NewStateQuery(output, stateValidator, terraformManager, commands.DirectorCACertPropertyName)
+	commandSet["credhub-server"] = commands.NewCredhubQuery(output, stateValidator, credhubGetter, commands.CredhubServerPropertyName)
+	commandSet["credhub-client"] = commands.NewCredhubQuery(output, stateValidator, credhubGetter, commands.CredhubClientPropertyName)
+	commandSet["credhub-password"] = commands.NewCredhubQuery(output, stateValidator, credhubGetter, commands.CredhubPasswordPropertyName)
+	commandSet["credhub-ca-cert"] = commands.NewCredhubQuery(output, stateValidator, credhubGetter, commands.CredhubCACertPropertyName)
+	commandSet["ssh-key"] = commands.NewSSHKey(output, stateValidator, sshKeyGetter)
+	commandSet["director-ssh-key"] = commands.NewDirectorSSHKey(output, stateValidator, sshKeyGetter)
+	commandSet["escrow"] = commands.NewEscrow(stateValidator, sshKeyGetter, afs, logger)
+	commandSet["ssh-cert"] = commands.NewSSHCert(output, stateValidator, sshCertIssuer, afs)
+	commandSet["ssh"] = commands.NewSSH(stateValidator, sshKeyGetter, ssh.NewCmd(os.Stdin, os.Stdout, os.Stderr), afs)
+	commandSet["env-id"] = commands.NewStateQuery(output, stateValidator, terraformManager, commands.EnvIDPropertyName)
 	commandSet["latest-error"] = commands.NewLatestError(logger, stateValidator)
+	commandSet["cloud-config"] = commands.NewCloudConfig(logger, stateValidator, cloudConfigManager)
+	commandSet["bench"] = commands.NewBench(logger, stateValidator, stateStore, templateGenerator, cloudConfigManager, stateSerializer, afs)
+	commandSet["pre-upgrade-check"] = commands.NewPreUpgradeCheck(stateValidator, boshClientProvider, Version, output, stderrLogger)
+	commandSet["curl"] = commands.NewCurl(stateValidator, boshClientProvider, logger)
+	commandSet["annotate"] = commands.NewAnnotate(stateValidator, stateStore, logger)
+	commandSet["annotations"] = commands.NewAnnotations(stateValidator, output)
+	commandSet["bosh-deployment-vars"] = commands.NewBOSHDeploymentVars(output, stateValidator, terraformManager, boshManager)
 	commandSet["print-env"] = commands.NewPrintEnv(logger, stderrLogger, stateValidator, allProxyGetter, credhubGetter, terraformManager, afs)
 
-	app := application.New(commandSet, appConfig, usage)
-
-	err = app.Run()
+	bblPath, err := os.Executable()
+	if err != nil {
+		bblPath = os.Args[0]
+	}
+	operations := storage.NewOperations(appConfig.Global.StateDir, bblPath, os.Args[1:])
+	stateEncryption := commands.NewStateEncryption(stateValidator, stateStore, stateCipher, func(creds storage.AWS) storage.KMSClient { return aws.NewKMSClient(creds) }, logger)
+	commandSet["state"] = commands.NewState(stateEncryption, commands.NewStateBackups(stateValidator, stateBackups, logger))
+	commandSet["status"] = commands.NewStatus(operations, output)
+	commandSet["wait"] = commands.NewWait(logger, operations, time.Second)
+	commandSet["man"] = commands.NewMan(logger, commandSet, afs)
+	commandSet["completion"] = commands.NewCompletion(logger, commandSet)
+
+	stateLock := storage.NewStateLock(appConfig.Global.StateDir)
+	app := application.New(commandSet, appConfig, usage, stateLock, stateBackups, operations, stderrLogger, logger, messages)
+
+	err = app.Run(ctx)
+	if healthServer != nil {
+		healthServer.Close()
+	}
+	if profile != nil {
+		if profileErr := profile.Write(appConfig.Global.ProfileRun); profileErr != nil {
+			stderrLogger.Println(fmt.Sprintf("Write the profile of the run: %s", profileErr))
+		}
+	}
 	if err != nil {
-		log.Fatalf("\n\n%s\n", err)
+		if code := catalog.Code(err); code != "" {
+			log.Printf("\n\n%s\n", messages.Message(catalog.ErrorWithCode, err, code))
+			os.Exit(bblerrors.ExitCode(err))
+		}
+		fail(err)
 	}
 }
+
+// fail prints the error and exits with the exit code of its kind, so that
+// scripts can tell, for example, a throttled request from a bad certificate.
+func fail(err error) {
+	log.Printf("\n\n%s\n", err)
+	os.Exit(bblerrors.ExitCode(err))
+}
@@ -0,0 +1,222 @@
+package bblerrors
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// Kind sorts the errors of bbl by what a script does about them. Each kind
+// exits bbl with a code of its own, which must not change once released.
+type Kind int
+
+const (
+	// Unknown is any error that bbl cannot sort.
+	Unknown Kind = iota
+
+	// Validation is a mistake in the flags, the state directory or a file,
+	// such as a certificate, that bbl was given.
+	Validation
+
+	// Credentials is an IAAS credential that is missing, wrong, expired or
+	// not allowed to do what bbl asked.
+	Credentials
+
+	// Quota is a limit of the account that bbl ran into, such as the number
+	// of VPCs or elastic IPs.
+	Quota
+
+	// Conflict is a resource or a bbl command that is in the way, such as a
+	// resource with the name bbl creates, or another bbl command modifying
+	// the environment.
+	Conflict
+
+	// Throttle is a request that the IAAS throttled or failed to serve. The
+	// command may succeed when it is run again.
+	Throttle
+
+	// Interrupted is a command that an interrupt or --timeout stopped. The
+	// steps it completed are saved, so that running it again resumes it.
+	Interrupted
+)
+
+var kinds = map[Kind]struct {
+	name     string
+	exitCode int
+}{
+	Unknown:     {"unknown", 1},
+	Validation:  {"validation", 2},
+	Credentials: {"credentials", 3},
+	Quota:       {"quota", 4},
+	Conflict:    {"conflict", 5},
+	Throttle:    {"throttle", 6},
+	Interrupted: {"interrupted", 7},
+}
+
+func (k Kind) String() string {
+	return kinds[k].name
+}
+
+// ExitCode is the exit code of bbl for an error of the kind.
+func (k Kind) ExitCode() int {
+	if kind, ok := kinds[k]; ok {
+		return kind.exitCode
+	}
+	return kinds[Unknown].exitCode
+}
+
+// Kinds lists the kinds in the order of their exit codes.
+func Kinds() []Kind {
+	return []Kind{Unknown, Validation, Credentials, Quota, Conflict, Throttle, Interrupted}
+}
+
+// Error is an error of a known kind.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// New marks an error as of a kind. An error of the Unknown kind is returned
+// as it is.
+func New(kind Kind, err error) error {
+	if err == nil || kind == Unknown {
+		return err
+	}
+	return Error{Kind: kind, Err: err}
+}
+
+// kinder is implemented by the errors of other packages that know their
+// kind, such as the account mismatch of aws.
+type kinder interface {
+	Kind() Kind
+}
+
+// KindOf returns the kind of an error: the one it was marked with, or else
+// the kind of the AWS error code that it wraps or that its message quotes,
+// since most errors wrap the message of the error they report.
+func KindOf(err error) Kind {
+	if err == nil {
+		return Unknown
+	}
+
+	var e Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	var k kinder
+	if errors.As(err, &k) {
+		return k.Kind()
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if kind := KindOfCode(awsErr.Code()); kind != Unknown {
+			return kind
+		}
+	}
+
+	return KindOfOutput(err.Error())
+}
+
+// ExitCode is the exit code of bbl for an error: 0 for none, and the exit
+// code of its kind otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindOf(err).ExitCode()
+}
+
+// awsErrorCode matches the codes of the AWS errors in messages, which the
+// AWS SDK and the terraform aws provider print as "Code: message".
+var awsErrorCode = regexp.MustCompile(`\b([A-Z][A-Za-z0-9]+(?:\.[A-Za-z0-9]+)*): `)
+
+// KindOfOutput returns the kind of the first AWS error code in a message or
+// in the output of a command, such as terraform, that has one.
+func KindOfOutput(output string) Kind {
+	for _, match := range awsErrorCode.FindAllStringSubmatch(output, -1) {
+		if kind := KindOfCode(match[1]); kind != Unknown {
+			return kind
+		}
+	}
+	return Unknown
+}
+
+var (
+	throttleCodes = map[string]bool{
+		"Throttling":                             true,
+		"ThrottlingException":                    true,
+		"ThrottledException":                     true,
+		"RequestLimitExceeded":                   true,
+		"RequestThrottled":                       true,
+		"RequestThrottledException":              true,
+		"TooManyRequestsException":               true,
+		"ProvisionedThroughputExceededException": true,
+		"SlowDown":                               true,
+		"ServiceUnavailable":                     true,
+		"Unavailable":                            true,
+		"InternalError":                          true,
+		"InternalFailure":                        true,
+		"RequestTimeout":                         true,
+		"RequestTimeoutException":                true,
+		"PriorRequestNotComplete":                true,
+		"EC2ThrottledException":                  true,
+	}
+
+	credentialsCodes = map[string]bool{
+		"AuthFailure":                 true,
+		"InvalidClientTokenId":        true,
+		"InvalidAccessKeyId":          true,
+		"SignatureDoesNotMatch":       true,
+		"UnrecognizedClientException": true,
+		"ExpiredToken":                true,
+		"ExpiredTokenException":       true,
+		"AccessDenied":                true,
+		"AccessDeniedException":       true,
+		"UnauthorizedOperation":       true,
+		"OptInRequired":               true,
+		"NoCredentialProviders":       true,
+	}
+
+	quotaCodes = map[string]bool{
+		"TooManyBuckets":               true,
+		"TooManyLoadBalancers":         true,
+		"TooManyTargetGroups":          true,
+		"InsufficientInstanceCapacity": true,
+		"MaxSpotInstanceCountExceeded": true,
+	}
+
+	conflictCodes = map[string]bool{
+		"DeleteConflict":          true,
+		"DependencyViolation":     true,
+		"ConcurrentModification":  true,
+		"BucketAlreadyOwnedByYou": true,
+		"IncorrectState":          true,
+	}
+)
+
+// KindOfCode returns the kind of an AWS error code.
+func KindOfCode(code string) Kind {
+	switch {
+	case throttleCodes[code]:
+		return Throttle
+	case credentialsCodes[code]:
+		return Credentials
+	case quotaCodes[code] || strings.HasSuffix(code, "LimitExceeded") || strings.HasSuffix(code, "QuotaExceeded"):
+		return Quota
+	case conflictCodes[code] || strings.HasSuffix(code, ".Duplicate") || strings.Contains(code, "AlreadyExists") || strings.HasPrefix(code, "Duplicate"):
+		return Conflict
+	}
+	return Unknown
+}
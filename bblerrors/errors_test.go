@@ -0,0 +1,98 @@
+package bblerrors_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+type accountError struct{}
+
+func (accountError) Error() string { return "another account" }
+
+func (accountError) Kind() bblerrors.Kind { return bblerrors.Credentials }
+
+var _ = Describe("Errors", func() {
+	Describe("New", func() {
+		It("marks the error with the kind", func() {
+			err := bblerrors.New(bblerrors.Conflict, errors.New("in the way"))
+
+			Expect(err).To(MatchError("in the way"))
+			Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Conflict))
+		})
+
+		It("returns errors of the unknown kind and nil as they are", func() {
+			err := errors.New("something")
+
+			Expect(bblerrors.New(bblerrors.Unknown, err)).To(BeIdenticalTo(err))
+			Expect(bblerrors.New(bblerrors.Validation, nil)).To(BeNil())
+		})
+	})
+
+	Describe("KindOf", func() {
+		It("finds the kind of a wrapped error", func() {
+			err := fmt.Errorf("Up: %w", bblerrors.New(bblerrors.Quota, errors.New("too many")))
+
+			Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Quota))
+		})
+
+		It("asks errors that know their kind", func() {
+			Expect(bblerrors.KindOf(accountError{})).To(Equal(bblerrors.Credentials))
+		})
+
+		It("sorts AWS errors by their codes", func() {
+			err := awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil)
+
+			Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Throttle))
+		})
+
+		It("sorts errors by the AWS error codes that their messages quote", func() {
+			err := fmt.Errorf("Create VPC: %s", awserr.New("VpcLimitExceeded", "The maximum number of VPCs has been reached.", nil))
+
+			Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Quota))
+		})
+
+		It("returns unknown for other errors", func() {
+			Expect(bblerrors.KindOf(errors.New("Executor apply: exit status 1"))).To(Equal(bblerrors.Unknown))
+			Expect(bblerrors.KindOf(nil)).To(Equal(bblerrors.Unknown))
+		})
+	})
+
+	DescribeTable("KindOfOutput",
+		func(output string, kind bblerrors.Kind) {
+			Expect(bblerrors.KindOfOutput(output)).To(Equal(kind))
+		},
+		Entry("throttled requests", "* aws_instance.nat: Error launching source instance: Throttling: Rate exceeded\n\tstatus code: 400", bblerrors.Throttle),
+		Entry("unavailable services", "Error: ServiceUnavailable: Service is unavailable", bblerrors.Throttle),
+		Entry("bad credentials", "Error: AuthFailure: AWS was not able to validate the provided access credentials", bblerrors.Credentials),
+		Entry("denied actions", "Error creating VPC: UnauthorizedOperation: You are not authorized to perform this operation.", bblerrors.Credentials),
+		Entry("limits", "Error creating EIP: AddressLimitExceeded: The maximum number of addresses has been reached.", bblerrors.Quota),
+		Entry("duplicate names", "Error creating Security Group: InvalidGroup.Duplicate: The security group 'some-env' already exists", bblerrors.Conflict),
+		Entry("existing entities", "Error creating IAM Role some-env-bosh: EntityAlreadyExists: Role with name some-env-bosh already exists.", bblerrors.Conflict),
+		Entry("the first code that has a kind", "Error: UnknownCode: something\nError: Throttling: Rate exceeded", bblerrors.Throttle),
+		Entry("output without codes", "Error: Invalid reference: A reference to a resource type must be followed by a name.", bblerrors.Unknown),
+	)
+
+	Describe("ExitCode", func() {
+		It("has an exit code for each kind", func() {
+			codes := []int{}
+			for _, kind := range bblerrors.Kinds() {
+				codes = append(codes, kind.ExitCode())
+			}
+
+			Expect(codes).To(Equal([]int{1, 2, 3, 4, 5, 6, 7}))
+		})
+
+		It("exits with the code of the kind of the error", func() {
+			Expect(bblerrors.ExitCode(bblerrors.New(bblerrors.Throttle, errors.New("slow down")))).To(Equal(6))
+			Expect(bblerrors.ExitCode(errors.New("something"))).To(Equal(1))
+			Expect(bblerrors.ExitCode(nil)).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package bblerrors_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBBLErrors(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "bblerrors")
+}
@@ -0,0 +1,86 @@
+package bosh
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ArtifactDownloader downloads the tarballs of releases and stemcells into an
+// artifacts directory, from which bosh create-env installs them on machines
+// without internet access.
+type ArtifactDownloader struct {
+	client httpClient
+}
+
+func NewArtifactDownloader(client httpClient) ArtifactDownloader {
+	return ArtifactDownloader{
+		client: client,
+	}
+}
+
+// Download downloads the artifact into dir and checks its sha1, unless the
+// tarball is there with that sha1 already. It reports whether it downloaded
+// the artifact.
+func (d ArtifactDownloader) Download(artifact Artifact, dir string) (bool, error) {
+	tarball := filepath.Join(dir, artifact.FileName())
+
+	if existing, err := fileSHA1(tarball); err == nil && existing == artifact.SHA1 {
+		return false, nil
+	}
+
+	response, err := d.client.Get(artifact.URL)
+	if err != nil {
+		return false, fmt.Errorf("Download %s: %s", artifact.FileName(), err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("Download %s: %s returned %s", artifact.FileName(), artifact.URL, response.Status)
+	}
+
+	// The tarball is downloaded next to its final name, so that an interrupted
+	// download never leaves a tarball behind.
+	file, err := ioutil.TempFile(dir, ".download-")
+	if err != nil {
+		return false, fmt.Errorf("Download %s: %s", artifact.FileName(), err)
+	}
+	defer os.Remove(file.Name())
+
+	hash := sha1.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), response.Body)
+	file.Close()
+	if err != nil {
+		return false, fmt.Errorf("Download %s: %s", artifact.FileName(), err)
+	}
+
+	if downloaded := fmt.Sprintf("%x", hash.Sum(nil)); downloaded != artifact.SHA1 {
+		return false, fmt.Errorf("The download of %s has the sha1 %s instead of %s.", artifact.FileName(), downloaded, artifact.SHA1)
+	}
+
+	err = os.Rename(file.Name(), tarball)
+	if err != nil {
+		return false, fmt.Errorf("Download %s: %s", artifact.FileName(), err)
+	}
+
+	return true, nil
+}
+
+func fileSHA1(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha1.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,106 @@
+package bosh_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ArtifactDownloader", func() {
+	var (
+		server   *httptest.Server
+		requests int
+		dir      string
+		artifact bosh.Artifact
+
+		downloader bosh.ArtifactDownloader
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path != "/d/os-conf" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("some-tarball"))
+		}))
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		artifact = bosh.Artifact{
+			Name:    "os-conf",
+			Version: "18",
+			URL:     server.URL + "/d/os-conf",
+			SHA1:    "4f2c686a8bdd78359a3e7a853fd4bb92c5078a7f",
+		}
+
+		downloader = bosh.NewArtifactDownloader(http.DefaultClient)
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("downloads the tarball and checks its sha1", func() {
+		downloaded, err := downloader.Download(artifact, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloaded).To(BeTrue())
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "os-conf-18.tgz"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-tarball"))
+	})
+
+	It("keeps a tarball that is there with the sha1", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, "os-conf-18.tgz"), []byte("some-tarball"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		downloaded, err := downloader.Download(artifact, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloaded).To(BeFalse())
+		Expect(requests).To(Equal(0))
+	})
+
+	It("replaces a tarball with another sha1", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, "os-conf-18.tgz"), []byte("some-partial-tar"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		downloaded, err := downloader.Download(artifact, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloaded).To(BeTrue())
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "os-conf-18.tgz"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-tarball"))
+	})
+
+	It("leaves nothing behind when the sha1 does not match", func() {
+		artifact.SHA1 = "some-other-sha1"
+
+		_, err := downloader.Download(artifact, dir)
+		Expect(err).To(MatchError("The download of os-conf-18.tgz has the sha1 4f2c686a8bdd78359a3e7a853fd4bb92c5078a7f instead of some-other-sha1."))
+
+		files, err := ioutil.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("returns an error when the download fails", func() {
+		artifact.URL = server.URL + "/d/missing"
+
+		_, err := downloader.Download(artifact, dir)
+		Expect(err).To(MatchError(ContainSubstring("Download os-conf-18.tgz: " + server.URL + "/d/missing returned 404 Not Found")))
+	})
+})
@@ -0,0 +1,175 @@
+package bosh
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Artifact is a release or stemcell that bosh-deployment pins.
+type Artifact struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	URL     string `json:"url" yaml:"url"`
+	SHA1    string `json:"sha1" yaml:"sha1"`
+}
+
+// Artifacts are what a director built by this bbl is made of. The CPI and
+// stemcell are only known for a given iaas.
+type Artifacts struct {
+	BOSH     Artifact  `json:"bosh"`
+	CPI      *Artifact `json:"cpi,omitempty"`
+	Stemcell *Artifact `json:"stemcell,omitempty"`
+}
+
+type opsFileEntry struct {
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value"`
+}
+
+// PinnedArtifacts reads the releases and stemcell that the bosh-deployment
+// built into bbl pins for the director on iaas. An empty iaas returns only
+// the BOSH release.
+func PinnedArtifacts(iaas string) (Artifacts, error) {
+	var manifest struct {
+		Releases []Artifact `yaml:"releases"`
+	}
+	err := yaml.Unmarshal(MustAsset(boshDeploymentRepo+"/bosh.yml"), &manifest)
+	if err != nil {
+		return Artifacts{}, fmt.Errorf("Read bosh.yml: %s", err)
+	}
+
+	var artifacts Artifacts
+	for _, release := range manifest.Releases {
+		if release.Name == "bosh" {
+			artifacts.BOSH = release
+		}
+	}
+
+	if iaas == "" {
+		return artifacts, nil
+	}
+
+	cpiOpsFile, err := Asset(path.Join(boshDeploymentRepo, iaas, "cpi.yml"))
+	if err != nil {
+		return Artifacts{}, fmt.Errorf("Unknown iaas %q: %s", iaas, err)
+	}
+
+	var ops []opsFileEntry
+	err = yaml.Unmarshal(cpiOpsFile, &ops)
+	if err != nil {
+		return Artifacts{}, fmt.Errorf("Read %s/cpi.yml: %s", iaas, err)
+	}
+
+	for _, op := range ops {
+		switch op.Path {
+		case "/releases/-":
+			if artifacts.CPI == nil {
+				artifacts.CPI, err = opsFileArtifact(op)
+			}
+		case "/resource_pools/name=vms/stemcell?":
+			artifacts.Stemcell, err = opsFileArtifact(op)
+			if err == nil {
+				if u, err := url.Parse(artifacts.Stemcell.URL); err == nil {
+					artifacts.Stemcell.Name = path.Base(u.Path)
+					artifacts.Stemcell.Version = u.Query().Get("v")
+				}
+			}
+		}
+		if err != nil {
+			return Artifacts{}, fmt.Errorf("Read %s/cpi.yml: %s", iaas, err)
+		}
+	}
+
+	return artifacts, nil
+}
+
+// DirectorVersions returns the versions that this bbl deploys the director
+// of state with: the pinned ones, with the overrides of bbl pin-artifacts.
+func DirectorVersions(state storage.State) (storage.DirectorVersions, error) {
+	artifacts, err := PinnedArtifacts(state.IAAS)
+	if err != nil {
+		return storage.DirectorVersions{}, err
+	}
+	if state.ArtifactOverrides != nil {
+		artifacts = artifacts.WithOverrides(*state.ArtifactOverrides)
+	}
+
+	versions := storage.DirectorVersions{BOSH: artifacts.BOSH.Version}
+	if artifacts.CPI != nil {
+		versions.CPI = artifacts.CPI.Version
+	}
+	if artifacts.Stemcell != nil {
+		versions.Stemcell = artifacts.Stemcell.Version
+	}
+	return versions, nil
+}
+
+// DeployedArtifacts returns the artifacts that this bbl deploys the director
+// of state from, as the overrides that pin a director to them.
+func DeployedArtifacts(state storage.State) (storage.ArtifactOverrides, error) {
+	artifacts, err := PinnedArtifacts(state.IAAS)
+	if err != nil {
+		return storage.ArtifactOverrides{}, err
+	}
+	if state.ArtifactOverrides != nil {
+		artifacts = artifacts.WithOverrides(*state.ArtifactOverrides)
+	}
+
+	overrides := storage.ArtifactOverrides{
+		BOSHReleaseURL:  artifacts.BOSH.URL,
+		BOSHReleaseSHA1: artifacts.BOSH.SHA1,
+	}
+	if artifacts.CPI != nil {
+		overrides.CPIReleaseURL, overrides.CPIReleaseSHA1 = artifacts.CPI.URL, artifacts.CPI.SHA1
+	}
+	if artifacts.Stemcell != nil {
+		overrides.StemcellURL, overrides.StemcellSHA1 = artifacts.Stemcell.URL, artifacts.Stemcell.SHA1
+	}
+	return overrides, nil
+}
+
+// WithOverrides returns the artifacts that a director is built from once
+// the overrides of bbl plan are applied.
+func (a Artifacts) WithOverrides(overrides storage.ArtifactOverrides) Artifacts {
+	override := func(artifact Artifact, artifactURL, sha1 string) Artifact {
+		if artifactURL == "" {
+			return artifact
+		}
+		artifact.URL, artifact.SHA1 = artifactURL, sha1
+		if u, err := url.Parse(artifactURL); err == nil && u.Query().Get("v") != "" {
+			artifact.Version = u.Query().Get("v")
+		}
+		return artifact
+	}
+
+	a.BOSH = override(a.BOSH, overrides.BOSHReleaseURL, overrides.BOSHReleaseSHA1)
+	if a.CPI != nil {
+		cpi := override(*a.CPI, overrides.CPIReleaseURL, overrides.CPIReleaseSHA1)
+		a.CPI = &cpi
+	}
+	if a.Stemcell != nil {
+		stemcell := override(*a.Stemcell, overrides.StemcellURL, overrides.StemcellSHA1)
+		a.Stemcell = &stemcell
+	}
+
+	return a
+}
+
+func opsFileArtifact(op opsFileEntry) (*Artifact, error) {
+	value, err := yaml.Marshal(op.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifact Artifact
+	err = yaml.Unmarshal(value, &artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return &artifact, nil
+}
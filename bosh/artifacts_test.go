@@ -0,0 +1,86 @@
+package bosh_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PinnedArtifacts", func() {
+	It("returns the bosh release, cpi release and stemcell pinned for the iaas", func() {
+		artifacts, err := bosh.PinnedArtifacts("aws")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(artifacts.BOSH.Name).To(Equal("bosh"))
+		Expect(artifacts.BOSH.Version).NotTo(BeEmpty())
+		Expect(artifacts.BOSH.SHA1).NotTo(BeEmpty())
+
+		Expect(artifacts.CPI.Name).To(Equal("bosh-aws-cpi"))
+		Expect(artifacts.CPI.Version).NotTo(BeEmpty())
+
+		Expect(artifacts.Stemcell.Name).To(Equal("bosh-aws-xen-hvm-ubuntu-trusty-go_agent"))
+		Expect(artifacts.Stemcell.Version).To(MatchRegexp(`^\d+(\.\d+)*$`))
+		Expect(artifacts.Stemcell.SHA1).NotTo(BeEmpty())
+	})
+
+	It("returns only the bosh release when there is no iaas", func() {
+		artifacts, err := bosh.PinnedArtifacts("")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(artifacts.BOSH.Name).To(Equal("bosh"))
+		Expect(artifacts.CPI).To(BeNil())
+		Expect(artifacts.Stemcell).To(BeNil())
+	})
+
+	It("returns an error for an unknown iaas", func() {
+		_, err := bosh.PinnedArtifacts("some-iaas")
+		Expect(err).To(MatchError(ContainSubstring(`Unknown iaas "some-iaas"`)))
+	})
+})
+
+var _ = Describe("DirectorVersions", func() {
+	It("returns the pinned versions with the overrides of the state", func() {
+		versions, err := bosh.DirectorVersions(storage.State{
+			IAAS:              "aws",
+			ArtifactOverrides: &storage.ArtifactOverrides{StemcellURL: "https://bosh.io/d/stemcells/bosh-aws-xen-hvm-ubuntu-trusty-go_agent?v=3586.100"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		pinned, err := bosh.PinnedArtifacts("aws")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(Equal(storage.DirectorVersions{
+			BOSH:     pinned.BOSH.Version,
+			CPI:      pinned.CPI.Version,
+			Stemcell: "3586.100",
+		}))
+	})
+})
+
+var _ = Describe("DeployedArtifacts", func() {
+	It("returns the urls and sha1s of the pinned artifacts with the overrides of the state", func() {
+		overrides := storage.ArtifactOverrides{
+			StemcellURL:  "https://bosh.io/d/stemcells/bosh-aws-xen-hvm-ubuntu-trusty-go_agent?v=3586.100",
+			StemcellSHA1: "some-stemcell-sha1",
+		}
+		artifacts, err := bosh.DeployedArtifacts(storage.State{IAAS: "aws", ArtifactOverrides: &overrides})
+		Expect(err).NotTo(HaveOccurred())
+
+		pinned, err := bosh.PinnedArtifacts("aws")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifacts).To(Equal(storage.ArtifactOverrides{
+			BOSHReleaseURL:  pinned.BOSH.URL,
+			BOSHReleaseSHA1: pinned.BOSH.SHA1,
+			CPIReleaseURL:   pinned.CPI.URL,
+			CPIReleaseSHA1:  pinned.CPI.SHA1,
+			StemcellURL:     overrides.StemcellURL,
+			StemcellSHA1:    "some-stemcell-sha1",
+		}))
+	})
+
+	It("returns an error for an unknown iaas", func() {
+		_, err := bosh.DeployedArtifacts(storage.State{IAAS: "some-iaas"})
+		Expect(err).To(MatchError(ContainSubstring(`Unknown iaas "some-iaas"`)))
+	})
+})
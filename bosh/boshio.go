@@ -0,0 +1,237 @@
+package bosh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const boshReleaseRepo = "github.com/cloudfoundry/bosh"
+
+type httpClient interface {
+	Get(url string) (*http.Response, error)
+	Head(url string) (*http.Response, error)
+}
+
+// BOSHIO resolves versions of releases and stemcells with the API of bosh.io,
+// so that a director can be built from newer ones than this bbl pins.
+type BOSHIO struct {
+	client httpClient
+	url    string
+}
+
+type boshioRelease struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+	SHA1    string `json:"sha1"`
+}
+
+type boshioStemcell struct {
+	Version string `json:"version"`
+	Light   *struct {
+		SHA1 string `json:"sha1"`
+	} `json:"light"`
+	Regular *struct {
+		SHA1 string `json:"sha1"`
+	} `json:"regular"`
+}
+
+func NewBOSHIO(client httpClient, url string) BOSHIO {
+	return BOSHIO{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+	}
+}
+
+// BOSHRelease resolves the newest version of the BOSH release that matches
+// version, and checks that it can be downloaded. The version is "latest", an
+// exact version, or a major version such as 270 or 270.x.
+func (b BOSHIO) BOSHRelease(version string) (Artifact, error) {
+	return b.release(boshReleaseRepo, version)
+}
+
+// CPIRelease resolves a version of the CPI release that bosh-deployment pins
+// for iaas, as BOSHRelease does.
+func (b BOSHIO) CPIRelease(iaas, version string) (Artifact, error) {
+	pinned, err := PinnedArtifacts(iaas)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	repo, ok := boshioPath(pinned.CPI.URL, "/d/")
+	if !ok {
+		return Artifact{}, fmt.Errorf("The CPI release of %s is not downloaded from bosh.io.", iaas)
+	}
+	return b.release(repo, version)
+}
+
+// Stemcell resolves a version of the stemcell that bosh-deployment pins for
+// iaas, as BOSHRelease does. bosh.io serves the light stemcell of the iaas
+// when there is one, so its sha1 is the one of the download url.
+func (b BOSHIO) Stemcell(iaas, version string) (Artifact, error) {
+	pinned, err := PinnedArtifacts(iaas)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	name, ok := boshioPath(pinned.Stemcell.URL, "/d/stemcells/")
+	if !ok {
+		return Artifact{}, fmt.Errorf("The stemcell of %s is not downloaded from bosh.io.", iaas)
+	}
+	return b.stemcell(name, version)
+}
+
+func (b BOSHIO) release(repo, version string) (Artifact, error) {
+	var releases []boshioRelease
+	err := b.getJSON(fmt.Sprintf("%s/api/v1/releases/%s", b.url, repo), &releases)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("List versions of %s: %s", repo, err)
+	}
+
+	versions := []string{}
+	for _, release := range releases {
+		versions = append(versions, release.Version)
+	}
+	resolved, ok := matchVersion(versions, version)
+	if !ok {
+		return Artifact{}, fmt.Errorf("bosh.io has no version %s of %s.", version, repo)
+	}
+
+	for _, release := range releases {
+		if release.Version != resolved {
+			continue
+		}
+
+		artifact := Artifact{Name: path.Base(repo), Version: release.Version, URL: release.URL, SHA1: release.SHA1}
+		return artifact, b.checkAvailable(artifact)
+	}
+	return Artifact{}, nil //not tested
+}
+
+func (b BOSHIO) stemcell(name, version string) (Artifact, error) {
+	var stemcells []boshioStemcell
+	err := b.getJSON(fmt.Sprintf("%s/api/v1/stemcells/%s", b.url, name), &stemcells)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("List versions of %s: %s", name, err)
+	}
+
+	versions := []string{}
+	for _, stemcell := range stemcells {
+		versions = append(versions, stemcell.Version)
+	}
+	resolved, ok := matchVersion(versions, version)
+	if !ok {
+		return Artifact{}, fmt.Errorf("bosh.io has no version %s of %s.", version, name)
+	}
+
+	for _, stemcell := range stemcells {
+		if stemcell.Version != resolved {
+			continue
+		}
+
+		artifact := Artifact{
+			Name:    name,
+			Version: stemcell.Version,
+			URL:     fmt.Sprintf("%s/d/stemcells/%s?v=%s", b.url, name, url.QueryEscape(stemcell.Version)),
+		}
+		switch {
+		case stemcell.Light != nil:
+			artifact.SHA1 = stemcell.Light.SHA1
+		case stemcell.Regular != nil:
+			artifact.SHA1 = stemcell.Regular.SHA1
+		default:
+			return Artifact{}, fmt.Errorf("bosh.io has no download of %s/%s.", name, stemcell.Version)
+		}
+		return artifact, b.checkAvailable(artifact)
+	}
+	return Artifact{}, nil //not tested
+}
+
+// boshioPath reads the release or stemcell of a bosh.io download url, such as
+// github.com/cloudfoundry-incubator/bosh-aws-cpi-release of
+// https://bosh.io/d/github.com/cloudfoundry-incubator/bosh-aws-cpi-release?v=69.
+func boshioPath(artifactURL, prefix string) (string, bool) {
+	u, err := url.Parse(artifactURL)
+	if err != nil || u.Host != "bosh.io" || !strings.HasPrefix(u.Path, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(u.Path, prefix), true
+}
+
+func (b BOSHIO) getJSON(url string, v interface{}) error {
+	response, err := b.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+// checkAvailable asks for the download of the artifact without downloading
+// it, so that a release that bosh.io lists but no longer serves is caught
+// before bosh create-env.
+func (b BOSHIO) checkAvailable(artifact Artifact) error {
+	response, err := b.client.Head(artifact.URL)
+	if err != nil {
+		return fmt.Errorf("%s/%s is not available: %s", artifact.Name, artifact.Version, err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s/%s is not available: %s returned %s", artifact.Name, artifact.Version, artifact.URL, response.Status)
+	}
+	return nil
+}
+
+// matchVersion returns the newest of versions that matches the constraint.
+func matchVersion(versions []string, constraint string) (string, bool) {
+	prefix := strings.TrimSuffix(strings.TrimSuffix(constraint, "x"), ".") + "."
+
+	matches := []string{}
+	for _, version := range versions {
+		if constraint == "latest" || version == constraint || strings.HasPrefix(version, prefix) {
+			matches = append(matches, version)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return compareVersions(matches[i], matches[j]) > 0
+	})
+	return matches[0], true
+}
+
+// compareVersions compares dotted versions part by part, numerically where
+// both parts are numbers.
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.Atoi(aParts[i])
+		bNumber, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil && aNumber != bNumber:
+			if aNumber > bNumber {
+				return 1
+			}
+			return -1
+		case (aErr != nil || bErr != nil) && aParts[i] != bParts[i]:
+			if aParts[i] > bParts[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return len(aParts) - len(bParts)
+}
@@ -0,0 +1,145 @@
+package bosh_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BOSHIO", func() {
+	var (
+		server      *httptest.Server
+		unavailable map[string]bool
+		boshio      bosh.BOSHIO
+	)
+
+	BeforeEach(func() {
+		unavailable = map[string]bool{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "HEAD" {
+				if unavailable[r.URL.RequestURI()] {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return
+			}
+
+			switch r.URL.Path {
+			case "/api/v1/releases/github.com/cloudfoundry/bosh":
+				fmt.Fprintf(w, `[
+					{"name": "github.com/cloudfoundry/bosh", "version": "270.2.0", "url": "%[1]s/d/github.com/cloudfoundry/bosh?v=270.2.0", "sha1": "sha1-270.2.0"},
+					{"name": "github.com/cloudfoundry/bosh", "version": "270.10.0", "url": "%[1]s/d/github.com/cloudfoundry/bosh?v=270.10.0", "sha1": "sha1-270.10.0"},
+					{"name": "github.com/cloudfoundry/bosh", "version": "271.0.0", "url": "%[1]s/d/github.com/cloudfoundry/bosh?v=271.0.0", "sha1": "sha1-271.0.0"},
+					{"name": "github.com/cloudfoundry/bosh", "version": "264.7.0", "url": "%[1]s/d/github.com/cloudfoundry/bosh?v=264.7.0", "sha1": "sha1-264.7.0"}
+				]`, server.URL)
+			case "/api/v1/releases/github.com/cloudfoundry-incubator/bosh-aws-cpi-release":
+				fmt.Fprintf(w, `[
+					{"name": "github.com/cloudfoundry-incubator/bosh-aws-cpi-release", "version": "69", "url": "%[1]s/d/github.com/cloudfoundry-incubator/bosh-aws-cpi-release?v=69", "sha1": "sha1-69"},
+					{"name": "github.com/cloudfoundry-incubator/bosh-aws-cpi-release", "version": "75", "url": "%[1]s/d/github.com/cloudfoundry-incubator/bosh-aws-cpi-release?v=75", "sha1": "sha1-75"}
+				]`, server.URL)
+			case "/api/v1/stemcells/bosh-aws-xen-hvm-ubuntu-trusty-go_agent":
+				fmt.Fprint(w, `[
+					{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3586.40", "light": {"sha1": "light-sha1-3586.40"}, "regular": {"sha1": "sha1-3586.40"}},
+					{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3468.21", "regular": {"sha1": "sha1-3468.21"}}
+				]`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		boshio = bosh.NewBOSHIO(http.DefaultClient, server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("BOSHRelease", func() {
+		It("resolves the latest version", func() {
+			artifact, err := boshio.BOSHRelease("latest")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact).To(Equal(bosh.Artifact{
+				Name:    "bosh",
+				Version: "271.0.0",
+				URL:     server.URL + "/d/github.com/cloudfoundry/bosh?v=271.0.0",
+				SHA1:    "sha1-271.0.0",
+			}))
+		})
+
+		It("resolves the newest version of a major version", func() {
+			artifact, err := boshio.BOSHRelease("270.x")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.Version).To(Equal("270.10.0"))
+
+			artifact, err = boshio.BOSHRelease("270")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.Version).To(Equal("270.10.0"))
+		})
+
+		It("resolves an exact version", func() {
+			artifact, err := boshio.BOSHRelease("270.2.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.SHA1).To(Equal("sha1-270.2.0"))
+		})
+
+		It("returns an error when no version matches", func() {
+			_, err := boshio.BOSHRelease("272.x")
+			Expect(err).To(MatchError("bosh.io has no version 272.x of github.com/cloudfoundry/bosh."))
+		})
+
+		It("returns an error when the release cannot be downloaded", func() {
+			unavailable["/d/github.com/cloudfoundry/bosh?v=271.0.0"] = true
+
+			_, err := boshio.BOSHRelease("latest")
+			Expect(err).To(MatchError(fmt.Sprintf("bosh/271.0.0 is not available: %s/d/github.com/cloudfoundry/bosh?v=271.0.0 returned 404 Not Found", server.URL)))
+		})
+
+		It("returns an error when bosh.io cannot list the versions", func() {
+			boshio = bosh.NewBOSHIO(http.DefaultClient, server.URL+"/missing")
+
+			_, err := boshio.BOSHRelease("latest")
+			Expect(err).To(MatchError(fmt.Sprintf("List versions of github.com/cloudfoundry/bosh: %s/missing/api/v1/releases/github.com/cloudfoundry/bosh returned 404 Not Found", server.URL)))
+		})
+	})
+
+	Describe("CPIRelease", func() {
+		It("resolves a version of the cpi release of the iaas", func() {
+			artifact, err := boshio.CPIRelease("aws", "latest")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact).To(Equal(bosh.Artifact{
+				Name:    "bosh-aws-cpi-release",
+				Version: "75",
+				URL:     server.URL + "/d/github.com/cloudfoundry-incubator/bosh-aws-cpi-release?v=75",
+				SHA1:    "sha1-75",
+			}))
+		})
+
+		It("returns an error for an unknown iaas", func() {
+			_, err := boshio.CPIRelease("some-iaas", "latest")
+			Expect(err).To(MatchError(ContainSubstring(`Unknown iaas "some-iaas"`)))
+		})
+	})
+
+	Describe("Stemcell", func() {
+		It("resolves a version of the stemcell of the iaas, with the sha1 of the light stemcell", func() {
+			artifact, err := boshio.Stemcell("aws", "latest")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact).To(Equal(bosh.Artifact{
+				Name:    "bosh-aws-xen-hvm-ubuntu-trusty-go_agent",
+				Version: "3586.40",
+				URL:     server.URL + "/d/stemcells/bosh-aws-xen-hvm-ubuntu-trusty-go_agent?v=3586.40",
+				SHA1:    "light-sha1-3586.40",
+			}))
+		})
+
+		It("uses the sha1 of the regular stemcell when there is no light one", func() {
+			artifact, err := boshio.Stemcell("aws", "3468.21")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.SHA1).To(Equal("sha1-3468.21"))
+		})
+	})
+})
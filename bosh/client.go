@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -18,6 +19,7 @@ import (
 type Client interface {
 	UpdateCloudConfig(yaml []byte) error
 	Info() (Info, error)
+	Curl(method, path string, body []byte) (int, []byte, error)
 }
 
 type Info struct {
@@ -79,14 +81,62 @@ func (c client) UpdateCloudConfig(yaml []byte) error {
 	}
 	request.Header.Set("Content-Type", "text/yaml")
 
-	urlParts, err := url.Parse(c.directorAddress)
+	httpClient, err := c.uaaClient()
 	if err != nil {
 		return err //not tested
 	}
 
+	response, err := makeRequests(httpClient, request)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected http response %d %s", response.StatusCode, http.StatusText(response.StatusCode))
+	}
+
+	return nil
+}
+
+// Curl sends a request to the director API, authenticated with a UAA token,
+// and returns the status and body of the response whatever the status is.
+func (c client) Curl(method, path string, body []byte) (int, []byte, error) {
+	request, err := http.NewRequest(method, fmt.Sprintf("%s/%s", c.directorAddress, strings.TrimPrefix(path, "/")), bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(body) > 0 {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient, err := c.uaaClient()
+	if err != nil {
+		return 0, nil, err //not tested
+	}
+
+	response, err := makeRequests(httpClient, request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, err //not tested
+	}
+
+	return response.StatusCode, responseBody, nil
+}
+
+func (c client) uaaClient() (*http.Client, error) {
+	urlParts, err := url.Parse(c.directorAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	boshHost, _, err := net.SplitHostPort(urlParts.Host)
 	if err != nil {
-		return err //not tested
+		return nil, err
 	}
 
 	ctx := context.Background()
@@ -98,17 +148,7 @@ func (c client) UpdateCloudConfig(yaml []byte) error {
 		TokenURL:     fmt.Sprintf("https://%s:8443/oauth/token", boshHost),
 	}
 
-	httpClient := conf.Client(ctx)
-	response, err := makeRequests(httpClient, request)
-	if err != nil {
-		return err
-	}
-
-	if response.StatusCode != http.StatusCreated {
-		return fmt.Errorf("unexpected http response %d %s", response.StatusCode, http.StatusText(response.StatusCode))
-	}
-
-	return nil
+	return conf.Client(ctx), nil
 }
 
 func makeRequests(httpClient *http.Client, request *http.Request) (*http.Response, error) {
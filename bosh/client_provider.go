@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	socks5proxy "github.com/cloudfoundry/socks5-proxy"
 	"golang.org/x/net/proxy"
 )
 
@@ -20,6 +22,7 @@ type ClientProvider struct {
 
 type socks5Proxy interface {
 	Start(string, string) error
+	StartWithDialer(socks5proxy.DialFunc) error
 	Addr() (string, error)
 }
 
@@ -36,7 +39,19 @@ func (c ClientProvider) Dialer(jumpbox storage.Jumpbox) (proxy.Dialer, error) {
 		return nil, fmt.Errorf("get jumpbox ssh key: %s", err)
 	}
 
-	err = c.socks5Proxy.Start(privateKey, jumpbox.URL)
+	// Behind an HTTP proxy, the ssh connection to the jumpbox goes through
+	// it too, so that the director is reached on networks without direct
+	// outbound connections.
+	httpProxy, err := jumpboxProxy(jumpbox.URL)
+	if err != nil {
+		return nil, fmt.Errorf("get http proxy: %s", err)
+	}
+
+	if httpProxy == nil {
+		err = c.socks5Proxy.Start(privateKey, jumpbox.URL)
+	} else {
+		err = c.startThroughProxy(privateKey, jumpbox.URL, httpProxy)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("start proxy: %s", err)
 	}
@@ -54,6 +69,47 @@ func (c ClientProvider) Dialer(jumpbox storage.Jumpbox) (proxy.Dialer, error) {
 	return socks5Dialer, nil
 }
 
+// TunnelAddr returns the address of the socks5 proxy to the addresses behind
+// the jumpbox when the jumpbox is behind an HTTP proxy, starting it when it
+// is not running yet, and an empty address otherwise.
+func (c ClientProvider) TunnelAddr(jumpbox storage.Jumpbox) (string, error) {
+	httpProxy, err := jumpboxProxy(jumpbox.URL)
+	if err != nil {
+		return "", fmt.Errorf("get http proxy: %s", err)
+	}
+	if httpProxy == nil {
+		return "", nil
+	}
+
+	privateKey, err := c.sshKeyGetter.Get("jumpbox")
+	if err != nil {
+		return "", fmt.Errorf("get jumpbox ssh key: %s", err)
+	}
+
+	err = c.startThroughProxy(privateKey, jumpbox.URL, httpProxy)
+	if err != nil {
+		return "", fmt.Errorf("start proxy: %s", err)
+	}
+
+	return c.socks5Proxy.Addr()
+}
+
+// startThroughProxy starts the socks5 proxy with an ssh connection to the
+// jumpbox through the HTTP proxy. Like Start, it keeps the socks5 proxy that
+// is running already.
+func (c ClientProvider) startThroughProxy(privateKey, jumpboxURL string, httpProxy *url.URL) error {
+	if _, err := c.socks5Proxy.Addr(); err == nil {
+		return nil
+	}
+
+	dial, err := jumpboxDialerThroughProxy(privateKey, jumpboxURL, httpProxy)
+	if err != nil {
+		return fmt.Errorf("connect to the jumpbox through %s: %s", httpProxy.Host, err)
+	}
+
+	return c.socks5Proxy.StartWithDialer(dial)
+}
+
 func (ClientProvider) HTTPClient(dialer proxy.Dialer, directorCACert []byte) *http.Client {
 	pool := x509.NewCertPool()
 	pool.AppendCertsFromPEM(directorCACert)
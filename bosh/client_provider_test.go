@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
@@ -72,6 +73,52 @@ var _ = Describe("Client Provider", func() {
 				Expect(socks5Forward).To(Equal(proxy.Direct))
 			})
 
+			Context("when the jumpbox is reached through an http proxy", func() {
+				var proxyRequest *http.Request
+
+				BeforeEach(func() {
+					bosh.SetProxyFromEnvironment(func(request *http.Request) (*url.URL, error) {
+						proxyRequest = request
+						return &url.URL{Scheme: "http", Host: "127.0.0.1:0"}, nil
+					})
+				})
+
+				AfterEach(func() {
+					bosh.ResetProxyFromEnvironment()
+				})
+
+				It("asks for the proxy of the jumpbox address", func() {
+					clientProvider.Dialer(storage.Jumpbox{URL: "some-jumpbox:22"})
+
+					Expect(proxyRequest.URL.Host).To(Equal("some-jumpbox:22"))
+				})
+
+				Context("when the socks5 proxy is running already", func() {
+					It("keeps it instead of connecting to the jumpbox again", func() {
+						socks5Client, err := clientProvider.Dialer(storage.Jumpbox{URL: "some-jumpbox:22"})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(socks5Client).To(Equal(fakeSocks5Client))
+
+						Expect(socks5Proxy.StartCall.CallCount).To(Equal(0))
+						Expect(socks5Proxy.StartWithDialerCall.CallCount).To(Equal(0))
+						Expect(socks5Addr).To(Equal("some-socks-proxy-addr"))
+					})
+				})
+
+				Context("when connecting to the jumpbox through the proxy fails", func() {
+					BeforeEach(func() {
+						socks5Proxy.AddrCall.Returns.Error = errors.New("socks5 proxy is not running")
+					})
+
+					It("returns an error", func() {
+						_, err := clientProvider.Dialer(storage.Jumpbox{URL: "some-jumpbox:22"})
+						Expect(err).To(MatchError(ContainSubstring("start proxy: ")))
+						Expect(socks5Proxy.StartCall.CallCount).To(Equal(0))
+						Expect(socks5Proxy.StartWithDialerCall.CallCount).To(Equal(0))
+					})
+				})
+			})
+
 			Context("when retrieving the private key fails", func() {
 				BeforeEach(func() {
 					sshKeyGetter.GetCall.Returns.Error = errors.New("tamarind")
@@ -94,6 +94,10 @@ var _ = Describe("Client", func() {
 				var err error
 				cloudConfig, err = ioutil.ReadAll(req.Body)
 				Expect(err).NotTo(HaveOccurred())
+			case "/deployments":
+				token = req.Header.Get("Authorization")
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`[{"name": "` + req.Method + `"}]`))
 			default:
 				dump, err := httputil.DumpRequest(req, true)
 				Expect(err).NotTo(HaveOccurred())
@@ -223,4 +227,41 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("Curl", func() {
+		It("sends the request with a UAA token and returns the response", func() {
+			dialer := &fakes.Socks5Client{}
+			dialer.DialCall.Stub = func(network, addr string) (net.Conn, error) {
+				u, _ := url.Parse(fakeBOSH.URL)
+				return net.Dial(network, u.Host)
+			}
+
+			httpClient = &http.Client{
+				Transport: &http.Transport{
+					Dial:            dialer.Dial,
+					TLSClientConfig: tlsConfig,
+				},
+			}
+
+			fakeBOSH.StartTLS()
+
+			client := bosh.NewClient(httpClient, fakeBOSH.URL, "some-username", "some-password", string(ca))
+
+			status, body, err := client.Curl("DELETE", "/deployments", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(token).To(Equal("Bearer some-uaa-token"))
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(string(body)).To(Equal(`[{"name": "DELETE"}]`))
+		})
+
+		Context("when the request fails", func() {
+			It("returns an error", func() {
+				client := bosh.NewClient(httpClient, "https://127.0.0.1:0", "some-username", "some-password", string(ca))
+
+				_, _, err := client.Curl("GET", "/deployments", nil)
+				Expect(err).To(MatchError(ContainSubstring("made 1 attempts, last error: Get")))
+			})
+		})
+	})
 })
@@ -1,17 +1,24 @@
 package bosh
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os/exec"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 )
 
 type Cmd struct {
+	ctx    context.Context
 	stderr io.Writer
 }
 
-func NewCmd(stderr io.Writer) Cmd {
+// NewCmd returns a Cmd whose bosh commands are interrupted once ctx runs out
+// of time.
+func NewCmd(ctx context.Context, stderr io.Writer) Cmd {
 	return Cmd{
+		ctx:    ctx,
 		stderr: stderr,
 	}
 }
@@ -44,5 +51,5 @@ func (c Cmd) Run(stdout io.Writer, args []string) error {
 	command.Stdout = stdout
 	command.Stderr = c.stderr
 
-	return command.Run()
+	return helpers.RunUntilDone(c.ctx, command)
 }
@@ -2,6 +2,7 @@ package bosh_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -54,7 +55,7 @@ var _ = Describe("Cmd", func() {
 		stdout = bytes.NewBuffer([]byte{})
 		stderr = bytes.NewBuffer([]byte{})
 
-		cmd = bosh.NewCmd(stderr)
+		cmd = bosh.NewCmd(context.Background(), stderr)
 
 		fakeBOSHBackendServer = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 			switch request.URL.Path {
@@ -0,0 +1,133 @@
+package bosh
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// deploymentLog streams the output of bosh create-env and delete-env through
+// the logger, with a step for each stage and task, such as compiling a package
+// or updating an instance. The full output, stderr included, is kept in a file.
+type deploymentLog struct {
+	deployment string
+	logger     logger
+	file       io.WriteCloser
+	stderr     io.Writer
+
+	mutex   sync.Mutex
+	partial []byte
+}
+
+// deploymentLogPath returns the file that keeps the output of a command. An
+// operation started with --no-wait keeps it in its own directory, next to its
+// output. Otherwise the output of the last run is in bbl-operations/logs.
+func deploymentLogPath(stateDir, deployment, command string) string {
+	dir := os.Getenv("BBL_OPERATION_DIR")
+	if dir == "" {
+		dir = filepath.Join(stateDir, storage.OperationsDirName, "logs")
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.log", deployment, command))
+}
+
+func newDeploymentLog(path, deployment string, logger logger) (*deploymentLog, error) {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, storage.StateMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deploymentLog{
+		deployment: deployment,
+		logger:     logger,
+		file:       file,
+		stderr:     os.Stderr,
+	}, nil
+}
+
+// Write is the stdout of the command.
+func (d *deploymentLog) Write(p []byte) (int, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	_, err := d.file.Write(p)
+	if err != nil {
+		return 0, err
+	}
+
+	d.partial = append(d.partial, p...)
+	for {
+		i := bytes.IndexByte(d.partial, '\n')
+		if i < 0 {
+			break
+		}
+		d.logLine(string(d.partial[:i]))
+		d.partial = d.partial[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Stderr is written to the file and to bbl's stderr as it is.
+func (d *deploymentLog) Stderr() io.Writer {
+	return stderrWriter{log: d}
+}
+
+func (d *deploymentLog) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.partial) > 0 {
+		d.logLine(string(d.partial))
+		d.partial = nil
+	}
+
+	return d.file.Close()
+}
+
+func (d *deploymentLog) logLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "":
+	case isDeploymentStep(line):
+		d.logger.Step("%s: %s", d.deployment, trimmed)
+	default:
+		d.logger.Println(line)
+	}
+}
+
+// isDeploymentStep recognizes the stages of bosh create-env, such as
+// "Started deploying", and its tasks, such as
+// "  Compiling package 'ruby/0123'... Finished (00:01:02)".
+func isDeploymentStep(line string) bool {
+	return strings.HasPrefix(line, "Started ") ||
+		strings.HasPrefix(line, "Succeeded") ||
+		strings.Contains(line, "... ") ||
+		strings.HasSuffix(line, "...")
+}
+
+type stderrWriter struct {
+	log *deploymentLog
+}
+
+func (s stderrWriter) Write(p []byte) (int, error) {
+	s.log.mutex.Lock()
+	defer s.log.mutex.Unlock()
+
+	_, err := s.log.file.Write(p)
+	if err != nil {
+		return 0, err
+	}
+	return s.log.stderr.Write(p)
+}
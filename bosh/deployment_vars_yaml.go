@@ -5,12 +5,14 @@ type sharedDeploymentVarsYAML struct {
 	GCPYAML          GCPYAML                `yaml:",inline"`
 	AzureYAML        AzureYAML              `yaml:",inline"`
 	VSphereYAML      VSphereYAML            `yaml:",inline"`
+	OpenStackYAML    OpenStackYAML          `yaml:",inline"`
 	TerraformOutputs map[string]interface{} `yaml:",inline"`
 }
 
 type AWSYAML struct {
 	AccessKeyID     string `yaml:"access_key_id,omitempty"`
 	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	SessionToken    string `yaml:"session_token,omitempty"`
 }
 
 type GCPYAML struct {
@@ -30,3 +32,8 @@ type VSphereYAML struct {
 	VCenterUser     string `yaml:"vcenter_user,omitempty"`
 	VCenterPassword string `yaml:"vcenter_password,omitempty"`
 }
+
+type OpenStackYAML struct {
+	Username string `yaml:"openstack_username,omitempty"`
+	Password string `yaml:"openstack_password,omitempty"`
+}
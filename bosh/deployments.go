@@ -0,0 +1,25 @@
+package bosh
+
+import "fmt"
+
+// Deployments lists the names of the deployments of the director, leaving
+// out a deployment named bosh, which is the director itself when it was
+// deployed by another director.
+func Deployments(client Client) ([]string, error) {
+	var deployments []struct {
+		Name string `json:"name"`
+	}
+	err := curlJSON(client, "/deployments", &deployments)
+	if err != nil {
+		return nil, fmt.Errorf("List deployments: %s", err)
+	}
+
+	names := []string{}
+	for _, deployment := range deployments {
+		if deployment.Name == "bosh" {
+			continue
+		}
+		names = append(names, deployment.Name)
+	}
+	return names, nil
+}
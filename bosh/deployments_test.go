@@ -0,0 +1,46 @@
+package bosh_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deployments", func() {
+	var client *fakes.BOSHClient
+
+	BeforeEach(func() {
+		client = &fakes.BOSHClient{}
+		client.CurlCall.Returns.Status = 200
+		client.CurlCall.Returns.Body = []byte(`[{"name": "cf"}, {"name": "bosh"}, {"name": "concourse"}]`)
+	})
+
+	It("lists the deployments other than bosh", func() {
+		deployments, err := bosh.Deployments(client)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployments).To(Equal([]string{"cf", "concourse"}))
+
+		Expect(client.CurlCall.Receives.Method).To(Equal("GET"))
+		Expect(client.CurlCall.Receives.Path).To(Equal("/deployments"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the director cannot be reached", func() {
+			client.CurlCall.Returns.Error = errors.New("connection refused")
+
+			_, err := bosh.Deployments(client)
+			Expect(err).To(MatchError("List deployments: connection refused"))
+		})
+
+		It("returns an error when the director responds with an error", func() {
+			client.CurlCall.Returns.Status = 401
+
+			_, err := bosh.Deployments(client)
+			Expect(err).To(MatchError("List deployments: unexpected http response 401 Unauthorized"))
+		})
+	})
+})
@@ -0,0 +1,69 @@
+package bosh
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// directorCredentials are the variables of the director vars store that
+// bosh create-env generates again once they are deleted.
+var directorCredentials = []string{
+	"admin_password",
+	"nats_password",
+	"registry_password",
+	"postgres_password",
+	"director_ssl",
+}
+
+type DirectorCredentialsDeleter struct {
+	stateStore stateStore
+	fs         deleterFs
+}
+
+func NewDirectorCredentialsDeleter(stateStore stateStore, fs deleterFs) DirectorCredentialsDeleter {
+	return DirectorCredentialsDeleter{
+		stateStore: stateStore,
+		fs:         fs,
+	}
+}
+
+// Delete removes the passwords and the SSL certificate of the director from
+// its vars store, so that the next bosh create-env generates new ones. The
+// certificate authority is kept, so the new certificate is still trusted.
+func (d DirectorCredentialsDeleter) Delete() error {
+	varsDir, err := d.stateStore.GetVarsDir()
+	if err != nil {
+		return fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	varsStore := filepath.Join(varsDir, "director-vars-store.yml")
+	contents, err := d.fs.ReadFile(varsStore)
+	if err != nil {
+		return fmt.Errorf("Read director vars store: %s", err)
+	}
+
+	vars := map[string]interface{}{}
+	err = yaml.Unmarshal(contents, &vars)
+	if err != nil {
+		return fmt.Errorf("Director variables: %s", err)
+	}
+	for _, name := range directorCredentials {
+		delete(vars, name)
+	}
+
+	newVars, err := yaml.Marshal(vars)
+	if err != nil {
+		return err //not tested
+	}
+
+	err = d.fs.WriteFile(varsStore, newVars, storage.StateMode)
+	if err != nil {
+		return fmt.Errorf("Write director vars store: %s", err)
+	}
+
+	return nil
+}
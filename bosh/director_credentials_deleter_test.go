@@ -0,0 +1,81 @@
+package bosh_test
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirectorCredentialsDeleter", func() {
+	var (
+		deleter    bosh.DirectorCredentialsDeleter
+		stateStore *fakes.StateStore
+		fileIO     *fakes.FileIO
+	)
+
+	BeforeEach(func() {
+		stateStore = &fakes.StateStore{}
+		stateStore.GetVarsDirCall.Returns.Directory = "some-vars-dir"
+		fileIO = &fakes.FileIO{}
+		fileIO.ReadFileCall.Returns.Contents = []byte(`admin_password: some-password
+nats_password: some-nats-password
+registry_password: some-registry-password
+postgres_password: some-postgres-password
+hm_password: some-hm-password
+default_ca:
+  certificate: some-ca
+director_ssl:
+  ca: some-ca
+  certificate: some-certificate
+`)
+
+		deleter = bosh.NewDirectorCredentialsDeleter(stateStore, fileIO)
+	})
+
+	It("deletes the passwords and the SSL certificate of the director from its vars store", func() {
+		err := deleter.Delete()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fileIO.ReadFileCall.Receives.Filename).To(Equal(filepath.Join("some-vars-dir", "director-vars-store.yml")))
+		Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join("some-vars-dir", "director-vars-store.yml")))
+		Expect(string(fileIO.WriteFileCall.Receives[0].Contents)).To(MatchYAML(`default_ca:
+  certificate: some-ca
+hm_password: some-hm-password
+`))
+	})
+
+	Describe("failure cases", func() {
+		It("returns an error when the vars dir can't be accessed", func() {
+			stateStore.GetVarsDirCall.Returns.Error = errors.New("potato")
+
+			err := deleter.Delete()
+			Expect(err).To(MatchError("Get vars dir: potato"))
+		})
+
+		It("returns an error when the vars store cannot be read", func() {
+			fileIO.ReadFileCall.Returns.Error = errors.New("no such file")
+
+			err := deleter.Delete()
+			Expect(err).To(MatchError("Read director vars store: no such file"))
+		})
+
+		It("returns an error when the vars store is invalid YAML", func() {
+			fileIO.ReadFileCall.Returns.Contents = []byte("invalid yaml")
+
+			err := deleter.Delete()
+			Expect(err).To(MatchError(ContainSubstring("Director variables: yaml: unmarshal errors:")))
+		})
+
+		It("returns an error when the vars store cannot be written", func() {
+			fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("disk full")}}
+
+			err := deleter.Delete()
+			Expect(err).To(MatchError("Write director vars store: disk full"))
+		})
+	})
+})
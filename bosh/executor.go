@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -22,16 +24,50 @@ type executorFs interface {
 }
 
 type Executor struct {
+	ctx           context.Context
 	command       command
 	fs            executorFs
 	unmarshalJSON func([]byte, interface{}) error
 	marshalJSON   func(interface{}) ([]byte, error)
+	logger        logger
 }
 
 type DirInput struct {
 	StateDir   string
 	VarsDir    string
 	Deployment string
+	SSHCA      bool
+
+	// TrustedCACerts are PEM encoded certificates that the director installs
+	// on the VMs it creates.
+	TrustedCACerts string
+
+	DirectorPorts storage.DirectorPorts
+
+	DirectorVM storage.DirectorVM
+
+	// DirectorSyslog colocates the syslog forwarder of syslog-release on the
+	// director.
+	DirectorSyslog storage.DirectorSyslog
+
+	// S3Blobstore points the director and its agents at the blobstore
+	// bucket of the terraform outputs.
+	S3Blobstore bool
+
+	// Lite deploys a bosh-lite director, whose warden cpi runs the VMs of
+	// deployments as containers on the director.
+	Lite bool
+
+	ArtifactOverrides storage.ArtifactOverrides
+
+	// ArtifactsDir holds the tarballs of the releases and stemcells, which
+	// create-env installs instead of downloading them.
+	ArtifactsDir string
+
+	// OpsFiles and VarsFiles are the operator's files, which are applied
+	// after the ones of bbl so that they can change anything bbl sets.
+	OpsFiles  []storage.DirectorFile
+	VarsFiles []storage.DirectorFile
 }
 
 type command interface {
@@ -50,14 +86,18 @@ var (
 	boshDeploymentRepo    = "vendor/github.com/cloudfoundry/bosh-deployment"
 )
 
-func NewExecutor(cmd command, fs executorFs,
+// NewExecutor returns an Executor whose create-env and delete-env scripts are
+// interrupted once ctx runs out of time.
+func NewExecutor(ctx context.Context, cmd command, fs executorFs,
 	unmarshalJSON func([]byte, interface{}) error,
-	marshalJSON func(interface{}) ([]byte, error)) Executor {
+	marshalJSON func(interface{}) ([]byte, error), logger logger) Executor {
 	return Executor{
+		ctx:           ctx,
 		command:       cmd,
 		fs:            fs,
 		unmarshalJSON: unmarshalJSON,
 		marshalJSON:   marshalJSON,
+		logger:        logger,
 	}
 }
 
@@ -113,6 +153,32 @@ func (e Executor) PlanJumpbox(input DirInput, deploymentDir, iaas string) error
 		}
 	}
 
+	if input.SSHCA {
+		path := filepath.Join(deploymentDir, "jumpbox-ssh-ca.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		err := e.fs.WriteFile(path, []byte(JumpboxSSHCAOps), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("Jumpbox write ssh ca ops file: %s", err) //not tested
+		}
+	}
+
+	if input.ArtifactsDir != "" {
+		artifacts, err := JumpboxArtifacts(iaas)
+		if err != nil {
+			return fmt.Errorf("Jumpbox artifacts: %s", err)
+		}
+		ops, err := e.localArtifactsOps(artifacts, input.ArtifactsDir)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(deploymentDir, "jumpbox-local-artifacts.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		err = e.fs.WriteFile(path, []byte(ops), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Jumpbox write local artifacts ops file: %s", err) //not tested
+		}
+	}
+
 	jumpboxState := filepath.Join(input.VarsDir, "jumpbox-state.json")
 
 	boshArgs := append([]string{
@@ -122,9 +188,15 @@ func (e Executor) PlanJumpbox(input DirInput, deploymentDir, iaas string) error
 
 	switch iaas {
 	case "aws":
+		sessionTokenOpsPath, err := e.writeAWSSessionTokenOps(input.StateDir, "")
+		if err != nil {
+			return fmt.Errorf("Jumpbox write session token ops file: %s", err) //not tested
+		}
 		boshArgs = append(boshArgs,
+			"-o", sessionTokenOpsPath,
 			"-v", `access_key_id="${BBL_AWS_ACCESS_KEY_ID}"`,
 			"-v", `secret_access_key="${BBL_AWS_SECRET_ACCESS_KEY}"`,
+			"-v", `session_token="${BBL_AWS_SESSION_TOKEN}"`,
 		)
 	case "azure":
 		boshArgs = append(boshArgs,
@@ -244,6 +316,143 @@ func (e Executor) PlanDirector(input DirInput, deploymentDir, iaas string) error
 		sharedArgs = append(sharedArgs, "-o", f)
 	}
 
+	if input.Lite {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-lite-ops.yml")
+		sharedArgs = append(sharedArgs,
+			"-o", filepath.Join(deploymentDir, "bosh-lite.yml"),
+			"-o", filepath.Join(deploymentDir, "bosh-lite-runc.yml"),
+			"-o", path,
+		)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(DirectorLiteOps), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write lite ops file: %s", err) //not tested
+		}
+	}
+
+	if input.SSHCA {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-ssh-ca-ops.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(DirectorSSHCAOps), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write ssh ca ops file: %s", err) //not tested
+		}
+	}
+
+	if input.TrustedCACerts != "" {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-trusted-certs-ops.yml")
+		certsPath := filepath.Join(input.VarsDir, "trusted-ca-certs.pem")
+		sharedArgs = append(sharedArgs, "-o", path, "--var-file", fmt.Sprintf("trusted_ca_certs=%s", certsPath))
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(DirectorTrustedCertsOps), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write trusted certs ops file: %s", err) //not tested
+		}
+		err = e.fs.WriteFile(certsPath, []byte(input.TrustedCACerts), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write trusted certs: %s", err) //not tested
+		}
+	}
+
+	if !input.DirectorPorts.IsEmpty() {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-ports-ops.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(DirectorPortsOps(input.DirectorPorts)), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write ports ops file: %s", err) //not tested
+		}
+	}
+
+	if !input.DirectorVM.IsEmpty() {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-vm-ops.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(DirectorVMOps(input.DirectorVM)), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write vm ops file: %s", err) //not tested
+		}
+	}
+
+	if !input.DirectorSyslog.IsEmpty() {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-syslog-ops.yml")
+		sharedArgs = append(sharedArgs,
+			"-o", filepath.Join(deploymentDir, "syslog.yml"),
+			"-o", path,
+			"-v", fmt.Sprintf("syslog_address=%s", input.DirectorSyslog.Address),
+			"-v", fmt.Sprintf("syslog_port=%d", input.DirectorSyslog.Port),
+			"-v", fmt.Sprintf("syslog_transport=%s", input.DirectorSyslog.Transport),
+		)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(DirectorSyslogOps), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write syslog ops file: %s", err) //not tested
+		}
+	}
+
+	if input.S3Blobstore {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-s3-blobstore-ops.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(AWSS3BlobstoreOps), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write s3 blobstore ops file: %s", err) //not tested
+		}
+	}
+
+	if !input.ArtifactOverrides.IsEmpty() {
+		ops, err := ArtifactOverridesOps(input.ArtifactOverrides, iaas)
+		if err != nil {
+			return fmt.Errorf("Director artifact overrides: %s", err)
+		}
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-artifacts-ops.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err = e.fs.WriteFile(path, []byte(ops), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write artifacts ops file: %s", err) //not tested
+		}
+	}
+
+	if input.ArtifactsDir != "" {
+		artifacts, err := DirectorArtifacts(iaas, input.Lite, input.ArtifactOverrides)
+		if err != nil {
+			return fmt.Errorf("Director artifacts: %s", err)
+		}
+		ops, err := e.localArtifactsOps(artifacts, input.ArtifactsDir)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "bosh-director-local-artifacts-ops.yml")
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err = e.fs.WriteFile(path, []byte(ops), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write local artifacts ops file: %s", err) //not tested
+		}
+	}
+
+	for i, opsFile := range input.OpsFiles {
+		path := filepath.Join(input.StateDir, "bbl-ops-files", "operator", fmt.Sprintf("%d-%s", i, opsFile.Name))
+		sharedArgs = append(sharedArgs, "-o", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(opsFile.Contents), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write operator ops file: %s", err) //not tested
+		}
+	}
+
+	for i, varsFile := range input.VarsFiles {
+		path := filepath.Join(input.VarsDir, "operator", fmt.Sprintf("%d-%s", i, varsFile.Name))
+		sharedArgs = append(sharedArgs, "-l", path)
+		os.MkdirAll(filepath.Dir(path), storage.StateMode)
+		err := e.fs.WriteFile(path, []byte(varsFile.Contents), storage.StateMode)
+		if err != nil {
+			return fmt.Errorf("Director write operator vars file: %s", err) //not tested
+		}
+	}
+
 	boshState := filepath.Join(input.VarsDir, "bosh-state.json")
 
 	boshPath, err := e.command.GetBOSHPath()
@@ -258,9 +467,15 @@ func (e Executor) PlanDirector(input DirInput, deploymentDir, iaas string) error
 
 	switch iaas {
 	case "aws":
+		sessionTokenOpsPath, err := e.writeAWSSessionTokenOps(input.StateDir, "")
+		if err != nil {
+			return fmt.Errorf("Director write session token ops file: %s", err) //not tested
+		}
 		boshArgs = append(boshArgs,
+			"-o", sessionTokenOpsPath,
 			"-v", `access_key_id="${BBL_AWS_ACCESS_KEY_ID}"`,
 			"-v", `secret_access_key="${BBL_AWS_SECRET_ACCESS_KEY}"`,
+			"-v", `session_token="${BBL_AWS_SESSION_TOKEN}"`,
 		)
 	case "azure":
 		boshArgs = append(boshArgs,
@@ -315,6 +530,24 @@ func formatScript(boshPath, stateDir, command string, args []string) string {
 	return fmt.Sprintf("%s\n", script[:len(script)-2])
 }
 
+// writeAWSSessionTokenOps writes the session token ops file of the jumpbox and
+// the director. Session tokens expire, so bbl writes it again with the token
+// of each create-env and delete-env.
+func (e Executor) writeAWSSessionTokenOps(stateDir, sessionToken string) (string, error) {
+	path := filepath.Join(stateDir, "bbl-ops-files", "aws", "session-token-ops.yml")
+	ops := NoOps
+	if sessionToken != "" {
+		ops = AWSSessionTokenOps
+	}
+
+	os.MkdirAll(filepath.Dir(path), storage.StateMode)
+	err := e.fs.WriteFile(path, []byte(ops), storage.StateMode)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 func (e Executor) WriteDeploymentVars(input DirInput, deploymentVars string) error {
 	varsFilePath := filepath.Join(input.VarsDir, fmt.Sprintf("%s-vars-file.yml", input.Deployment))
 	err := e.fs.WriteFile(varsFilePath, []byte(deploymentVars), storage.StateMode)
@@ -336,6 +569,11 @@ func (e Executor) CreateEnv(input DirInput, state storage.State) (string, error)
 	case "aws":
 		os.Setenv("BBL_AWS_ACCESS_KEY_ID", state.AWS.AccessKeyID)
 		os.Setenv("BBL_AWS_SECRET_ACCESS_KEY", state.AWS.SecretAccessKey)
+		os.Setenv("BBL_AWS_SESSION_TOKEN", state.AWS.SessionToken)
+		_, err = e.writeAWSSessionTokenOps(input.StateDir, state.AWS.SessionToken)
+		if err != nil {
+			return "", fmt.Errorf("Write session token ops file: %s", err) //not tested
+		}
 	case "azure":
 		os.Setenv("BBL_AZURE_CLIENT_ID", state.Azure.ClientID)
 		os.Setenv("BBL_AZURE_CLIENT_SECRET", state.Azure.ClientSecret)
@@ -353,13 +591,20 @@ func (e Executor) CreateEnv(input DirInput, state storage.State) (string, error)
 		os.Setenv("BBL_OPENSTACK_PASSWORD", state.OpenStack.Password)
 	}
 
+	logPath := deploymentLogPath(input.StateDir, input.Deployment, "create-env")
+	log, err := newDeploymentLog(logPath, input.Deployment, e.logger)
+	if err != nil {
+		return "", fmt.Errorf("Create bosh create-env log: %s", err) //not tested
+	}
+
 	cmd := exec.Command(createEnvScript) // the way this is tied to the filesystem makes for weird tests
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = log
+	cmd.Stderr = log.Stderr()
 
-	err = cmd.Run()
+	err = helpers.RunUntilDone(e.ctx, cmd)
+	log.Close()
 	if err != nil {
-		return "", fmt.Errorf("Run bosh create-env: %s", err)
+		return "", fmt.Errorf("Run bosh create-env: %s. The full output is in %s", err, logPath)
 	}
 
 	varsStoreFileName := fmt.Sprintf("%s-vars-store.yml", input.Deployment)
@@ -392,6 +637,11 @@ func (e Executor) DeleteEnv(input DirInput, state storage.State) error {
 	case "aws":
 		os.Setenv("BBL_AWS_ACCESS_KEY_ID", state.AWS.AccessKeyID)
 		os.Setenv("BBL_AWS_SECRET_ACCESS_KEY", state.AWS.SecretAccessKey)
+		os.Setenv("BBL_AWS_SESSION_TOKEN", state.AWS.SessionToken)
+		_, err = e.writeAWSSessionTokenOps(input.StateDir, state.AWS.SessionToken)
+		if err != nil {
+			return fmt.Errorf("Write session token ops file: %s", err) //not tested
+		}
 	case "azure":
 		os.Setenv("BBL_AZURE_CLIENT_ID", state.Azure.ClientID)
 		os.Setenv("BBL_AZURE_CLIENT_SECRET", state.Azure.ClientSecret)
@@ -406,13 +656,20 @@ func (e Executor) DeleteEnv(input DirInput, state storage.State) error {
 		os.Setenv("BBL_VSPHERE_VCENTER_PASSWORD", state.VSphere.VCenterPassword)
 	}
 
+	logPath := deploymentLogPath(input.StateDir, input.Deployment, "delete-env")
+	log, err := newDeploymentLog(logPath, input.Deployment, e.logger)
+	if err != nil {
+		return fmt.Errorf("Create bosh delete-env log: %s", err) //not tested
+	}
+
 	cmd := exec.Command(deleteEnvScript) // the way this is tied to the filesystem makes for weird tests
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = log
+	cmd.Stderr = log.Stderr()
 
-	err = cmd.Run()
+	err = helpers.RunUntilDone(e.ctx, cmd)
+	log.Close()
 	if err != nil {
-		return fmt.Errorf("Run bosh delete-env %s: %s", input.Deployment, err)
+		return fmt.Errorf("Run bosh delete-env %s: %s. The full output is in %s", input.Deployment, err, logPath)
 	}
 
 	return nil
@@ -1,7 +1,11 @@
 package bosh
 
 import (
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"time"
 
 	"golang.org/x/net/proxy"
 )
@@ -29,3 +33,23 @@ func SetProxySOCKS5(f func(string, string, *proxy.Auth, proxy.Dialer) (proxy.Dia
 func ResetProxySOCKS5() {
 	proxySOCKS5 = proxy.SOCKS5
 }
+
+func SetTimeNow(f func() time.Time) {
+	timeNow = f
+}
+
+func ResetTimeNow() {
+	timeNow = time.Now
+}
+
+func SetProxyFromEnvironment(f func(*http.Request) (*url.URL, error)) {
+	proxyFromEnvironment = f
+}
+
+func ResetProxyFromEnvironment() {
+	proxyFromEnvironment = http.ProxyFromEnvironment
+}
+
+func DialThroughProxy(proxyURL *url.URL, addr string) (net.Conn, error) {
+	return dialThroughProxy(proxyURL, addr)
+}
@@ -0,0 +1,180 @@
+package bosh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// lbWorkload is a job that a load balancer sends traffic to, once the
+// instance group that runs it uses the vm_extension of the load balancer.
+type lbWorkload struct {
+	jobs        []string
+	vmExtension string
+
+	// required workloads are the reason for the load balancer, so bbl warns
+	// when no deployment runs them yet.
+	required bool
+}
+
+var lbWorkloads = map[string][]lbWorkload{
+	"cf": {
+		{jobs: []string{"gorouter"}, vmExtension: "cf-router-network-properties", required: true},
+		{jobs: []string{"ssh_proxy"}, vmExtension: "diego-ssh-proxy-network-properties"},
+		{jobs: []string{"tcp_router"}, vmExtension: "cf-tcp-router-network-properties"},
+	},
+	"concourse": {
+		{jobs: []string{"atc", "web"}, vmExtension: "lb", required: true},
+	},
+}
+
+type deploymentManifest struct {
+	InstanceGroups []struct {
+		Name         string        `yaml:"name"`
+		VMExtensions []string      `yaml:"vm_extensions"`
+		Jobs         []manifestJob `yaml:"jobs"`
+	} `yaml:"instance_groups"`
+}
+
+type manifestJob struct {
+	Name string `yaml:"name"`
+}
+
+// LBWorkloadWarnings compares the load balancers of a type with the
+// deployments of the director. It warns about instance groups that run a job
+// of the load balancer without its vm_extension, and about load balancers
+// that no deployment uses yet.
+func LBWorkloadWarnings(client Client, lbType string) ([]string, error) {
+	workloads, ok := lbWorkloads[lbType]
+	if !ok {
+		return nil, nil
+	}
+
+	deployments, err := deploymentManifests(client)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []string{}
+	running := map[string]bool{}
+	for _, deployment := range deployments {
+		for _, instanceGroup := range deployment.manifest.InstanceGroups {
+			for _, workload := range workloads {
+				job, ok := workload.runIn(instanceGroup.Jobs)
+				if !ok {
+					continue
+				}
+				running[workload.vmExtension] = true
+				if !contains(instanceGroup.VMExtensions, workload.vmExtension) {
+					warnings = append(warnings, fmt.Sprintf("Instance group %s of deployment %s runs %s without the vm_extension %s, so the %s load balancer does not send it traffic. Add the vm_extension and redeploy it after bbl up.", instanceGroup.Name, deployment.name, job, workload.vmExtension, lbType))
+				}
+			}
+		}
+	}
+
+	for _, workload := range workloads {
+		if workload.required && !running[workload.vmExtension] {
+			warnings = append(warnings, fmt.Sprintf("No deployment runs %s yet. The %s load balancer sends it traffic once it is deployed with the vm_extension %s, after bbl up updates the cloud config.", workload.jobs[0], lbType, workload.vmExtension))
+		}
+	}
+
+	return warnings, nil
+}
+
+// LBWorkloadUsers lists the instance groups of the deployments of the director
+// that use a vm_extension of the load balancers of a type, which the cloud
+// config no longer defines once the load balancers are removed.
+func LBWorkloadUsers(client Client, lbType string) ([]string, error) {
+	workloads, ok := lbWorkloads[lbType]
+	if !ok {
+		return nil, nil
+	}
+
+	deployments, err := deploymentManifests(client)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []string{}
+	for _, deployment := range deployments {
+		for _, instanceGroup := range deployment.manifest.InstanceGroups {
+			for _, workload := range workloads {
+				if contains(instanceGroup.VMExtensions, workload.vmExtension) {
+					users = append(users, fmt.Sprintf("instance group %s of deployment %s uses the vm_extension %s", instanceGroup.Name, deployment.name, workload.vmExtension))
+				}
+			}
+		}
+	}
+
+	return users, nil
+}
+
+type namedManifest struct {
+	name     string
+	manifest deploymentManifest
+}
+
+// deploymentManifests returns the manifests of the deployments of the
+// director.
+func deploymentManifests(client Client) ([]namedManifest, error) {
+	var deployments []struct {
+		Name string `json:"name"`
+	}
+	err := curlJSON(client, "/deployments", &deployments)
+	if err != nil {
+		return nil, fmt.Errorf("List deployments: %s", err)
+	}
+
+	manifests := []namedManifest{}
+	for _, deployment := range deployments {
+		var response struct {
+			Manifest string `json:"manifest"`
+		}
+		err := curlJSON(client, fmt.Sprintf("/deployments/%s", url.PathEscape(deployment.Name)), &response)
+		if err != nil {
+			return nil, fmt.Errorf("Get manifest of deployment %s: %s", deployment.Name, err)
+		}
+
+		var manifest deploymentManifest
+		err = yaml.Unmarshal([]byte(response.Manifest), &manifest)
+		if err != nil {
+			return nil, fmt.Errorf("Read manifest of deployment %s: %s", deployment.Name, err)
+		}
+
+		manifests = append(manifests, namedManifest{name: deployment.Name, manifest: manifest})
+	}
+
+	return manifests, nil
+}
+
+func (w lbWorkload) runIn(jobs []manifestJob) (string, bool) {
+	for _, job := range jobs {
+		if contains(w.jobs, job.Name) {
+			return job.Name, true
+		}
+	}
+	return "", false
+}
+
+func curlJSON(client Client, path string, output interface{}) error {
+	status, body, err := client.Curl("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected http response %d %s", status, http.StatusText(status))
+	}
+	return json.Unmarshal(body, output)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
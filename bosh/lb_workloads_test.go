@@ -0,0 +1,155 @@
+package bosh_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LBWorkloadWarnings", func() {
+	var (
+		client    *fakes.BOSHClient
+		manifests map[string]string
+	)
+
+	BeforeEach(func() {
+		client = &fakes.BOSHClient{}
+		manifests = map[string]string{}
+		client.CurlCall.Stub = func(method, path string, body []byte) (int, []byte, error) {
+			if path == "/deployments" {
+				list := "["
+				for name := range manifests {
+					if list != "[" {
+						list += ","
+					}
+					list += `{"name": "` + name + `"}`
+				}
+				return 200, []byte(list + "]"), nil
+			}
+			manifest, ok := manifests[path[len("/deployments/"):]]
+			if !ok {
+				return 404, []byte("not found"), nil
+			}
+			return 200, []byte(`{"manifest": ` + manifest + `}`), nil
+		}
+	})
+
+	Context("cf", func() {
+		It("warns when no deployment runs the router yet", func() {
+			warnings, err := bosh.LBWorkloadWarnings(client, "cf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"No deployment runs gorouter yet. The cf load balancer sends it traffic once it is deployed with the vm_extension cf-router-network-properties, after bbl up updates the cloud config.",
+			))
+		})
+
+		It("warns about instance groups without the vm_extensions of their jobs", func() {
+			manifests["cf"] = `"instance_groups:\n- name: router\n  vm_extensions: [cf-router-network-properties]\n  jobs:\n  - name: gorouter\n- name: scheduler\n  jobs:\n  - name: auctioneer\n  - name: ssh_proxy\n"`
+
+			warnings, err := bosh.LBWorkloadWarnings(client, "cf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"Instance group scheduler of deployment cf runs ssh_proxy without the vm_extension diego-ssh-proxy-network-properties, so the cf load balancer does not send it traffic. Add the vm_extension and redeploy it after bbl up.",
+			))
+			Expect(client.CurlCall.Receives.Method).To(Equal("GET"))
+		})
+
+		It("does not warn when the deployments use the vm_extensions", func() {
+			manifests["cf"] = `"instance_groups:\n- name: router\n  vm_extensions: [cf-router-network-properties]\n  jobs:\n  - name: gorouter\n"`
+			manifests["other"] = `"instance_groups:\n- name: web\n  jobs:\n  - name: nginx\n"`
+
+			warnings, err := bosh.LBWorkloadWarnings(client, "cf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("concourse", func() {
+		It("checks the web job for the lb vm_extension", func() {
+			manifests["concourse"] = `"instance_groups:\n- name: web\n  jobs:\n  - name: web\n"`
+
+			warnings, err := bosh.LBWorkloadWarnings(client, "concourse")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"Instance group web of deployment concourse runs web without the vm_extension lb, so the concourse load balancer does not send it traffic. Add the vm_extension and redeploy it after bbl up.",
+			))
+		})
+	})
+
+	It("checks nothing for other load balancer types", func() {
+		warnings, err := bosh.LBWorkloadWarnings(client, "none")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+		Expect(client.CurlCall.CallCount).To(Equal(0))
+	})
+
+	Describe("failure cases", func() {
+		It("returns an error when the deployments cannot be listed", func() {
+			client.CurlCall.Stub = nil
+			client.CurlCall.Returns.Error = errors.New("failed to curl")
+
+			_, err := bosh.LBWorkloadWarnings(client, "cf")
+			Expect(err).To(MatchError("List deployments: failed to curl"))
+		})
+
+		It("returns an error when a manifest cannot be read", func() {
+			manifests["cf"] = `"instance_groups: ["`
+
+			_, err := bosh.LBWorkloadWarnings(client, "cf")
+			Expect(err).To(MatchError(ContainSubstring("Read manifest of deployment cf:")))
+		})
+	})
+})
+
+var _ = Describe("LBWorkloadUsers", func() {
+	var (
+		client    *fakes.BOSHClient
+		manifests map[string]string
+	)
+
+	BeforeEach(func() {
+		client = &fakes.BOSHClient{}
+		manifests = map[string]string{}
+		client.CurlCall.Stub = func(method, path string, body []byte) (int, []byte, error) {
+			if path == "/deployments" {
+				list := "["
+				for name := range manifests {
+					if list != "[" {
+						list += ","
+					}
+					list += `{"name": "` + name + `"}`
+				}
+				return 200, []byte(list + "]"), nil
+			}
+			return 200, []byte(`{"manifest": ` + manifests[path[len("/deployments/"):]] + `}`), nil
+		}
+	})
+
+	It("lists the instance groups that use the vm_extensions of the load balancer", func() {
+		manifests["cf"] = `"instance_groups:\n- name: router\n  vm_extensions: [cf-router-network-properties]\n  jobs:\n  - name: gorouter\n- name: api\n  vm_extensions: [50GB_ephemeral_disk]\n  jobs:\n  - name: cloud_controller_ng\n"`
+
+		users, err := bosh.LBWorkloadUsers(client, "cf")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(ConsistOf("instance group router of deployment cf uses the vm_extension cf-router-network-properties"))
+	})
+
+	It("lists nothing when no deployment uses them", func() {
+		manifests["concourse"] = `"instance_groups:\n- name: web\n  jobs:\n  - name: web\n"`
+
+		users, err := bosh.LBWorkloadUsers(client, "concourse")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(BeEmpty())
+	})
+
+	It("returns an error when the deployments cannot be listed", func() {
+		client.CurlCall.Stub = nil
+		client.CurlCall.Returns.Error = errors.New("failed to curl")
+
+		_, err := bosh.LBWorkloadUsers(client, "cf")
+		Expect(err).To(MatchError("List deployments: failed to curl"))
+	})
+})
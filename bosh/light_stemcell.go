@@ -0,0 +1,160 @@
+package bosh
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const stemcellManifestName = "stemcell.MF"
+
+// LightStemcell is an aws stemcell that only refers to an AMI of the stemcell
+// in each region, as bosh.io publishes them.
+type LightStemcell struct {
+	Name    string
+	Version string
+	AMIs    map[string]string
+
+	files []stemcellFile
+}
+
+type stemcellFile struct {
+	header   *tar.Header
+	contents []byte
+}
+
+// ReadLightStemcell reads the name, version and AMIs of a light stemcell
+// tarball.
+func ReadLightStemcell(tgz []byte) (LightStemcell, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(tgz))
+	if err != nil {
+		return LightStemcell{}, fmt.Errorf("Read stemcell: %s", err)
+	}
+
+	var stemcell LightStemcell
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return LightStemcell{}, fmt.Errorf("Read stemcell: %s", err)
+		}
+
+		contents, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return LightStemcell{}, fmt.Errorf("Read stemcell: %s", err)
+		}
+		stemcell.files = append(stemcell.files, stemcellFile{header: header, contents: contents})
+	}
+
+	manifest, ok := stemcell.manifest()
+	if !ok {
+		return LightStemcell{}, errors.New("Read stemcell: it has no stemcell.MF.")
+	}
+
+	var properties struct {
+		Name            string      `yaml:"name"`
+		Version         interface{} `yaml:"version"`
+		CloudProperties struct {
+			AMI map[string]string `yaml:"ami"`
+		} `yaml:"cloud_properties"`
+	}
+	err = yaml.Unmarshal(manifest.contents, &properties)
+	if err != nil {
+		return LightStemcell{}, fmt.Errorf("Read stemcell.MF: %s", err)
+	}
+	if len(properties.CloudProperties.AMI) == 0 {
+		return LightStemcell{}, fmt.Errorf("Stemcell %s is not a light stemcell: it refers to no AMIs.", properties.Name)
+	}
+
+	stemcell.Name = properties.Name
+	stemcell.Version = fmt.Sprint(properties.Version)
+	stemcell.AMIs = properties.CloudProperties.AMI
+	return stemcell, nil
+}
+
+// WithAMI returns the tarball of the stemcell with the AMI of the region
+// replaced by imageID. The other files and AMIs are kept as they are.
+func (s LightStemcell) WithAMI(region, imageID string) ([]byte, error) {
+	manifest, _ := s.manifest()
+
+	var properties yaml.MapSlice
+	err := yaml.Unmarshal(manifest.contents, &properties)
+	if err != nil {
+		return nil, fmt.Errorf("Read stemcell.MF: %s", err)
+	}
+	properties = setMapSliceValue(properties, []string{"cloud_properties", "ami", region}, imageID)
+
+	contents, err := yaml.Marshal(properties)
+	if err != nil {
+		return nil, err //not tested
+	}
+
+	var tgz bytes.Buffer
+	gzipWriter := gzip.NewWriter(&tgz)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range s.files {
+		header := *file.header
+		fileContents := file.contents
+		if header.Name == manifest.header.Name {
+			fileContents = contents
+			header.Size = int64(len(contents))
+		}
+
+		err = tarWriter.WriteHeader(&header)
+		if err != nil {
+			return nil, err //not tested
+		}
+		_, err = tarWriter.Write(fileContents)
+		if err != nil {
+			return nil, err //not tested
+		}
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, err //not tested
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, err //not tested
+	}
+
+	return tgz.Bytes(), nil
+}
+
+func (s LightStemcell) manifest() (stemcellFile, bool) {
+	for _, file := range s.files {
+		if file.header.Name == stemcellManifestName || file.header.Name == "./"+stemcellManifestName {
+			return file, true
+		}
+	}
+	return stemcellFile{}, false
+}
+
+func setMapSliceValue(m yaml.MapSlice, path []string, value string) yaml.MapSlice {
+	for i, item := range m {
+		if item.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			m[i].Value = value
+			return m
+		}
+		child, _ := item.Value.(yaml.MapSlice)
+		m[i].Value = setMapSliceValue(child, path[1:], value)
+		return m
+	}
+
+	if len(path) == 1 {
+		return append(m, yaml.MapItem{Key: path[0], Value: value})
+	}
+	return append(m, yaml.MapItem{Key: path[0], Value: setMapSliceValue(nil, path[1:], value)})
+}
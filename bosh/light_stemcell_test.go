@@ -0,0 +1,139 @@
+package bosh_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LightStemcell", func() {
+	var tgz []byte
+
+	pack := func(files map[string]string, names ...string) []byte {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+		for _, name := range names {
+			Expect(tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name]))})).To(Succeed())
+			_, err := tarWriter.Write([]byte(files[name]))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+		return buffer.Bytes()
+	}
+
+	unpack := func(tgz []byte) map[string]string {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(tgz))
+		Expect(err).NotTo(HaveOccurred())
+		tarReader := tar.NewReader(gzipReader)
+
+		files := map[string]string{}
+		for {
+			header, err := tarReader.Next()
+			if err != nil {
+				break
+			}
+			contents, err := ioutil.ReadAll(tarReader)
+			Expect(err).NotTo(HaveOccurred())
+			files[header.Name] = string(contents)
+		}
+		return files
+	}
+
+	BeforeEach(func() {
+		tgz = pack(map[string]string{
+			"stemcell.MF": `---
+name: bosh-aws-xen-hvm-ubuntu-trusty-go_agent
+version: '3586.42'
+cloud_properties:
+  ami:
+    us-east-1: ami-1111
+    eu-west-1: ami-2222
+  root_device_name: /dev/sda1
+`,
+			"image":               "",
+			"stemcell_dpkg_l.txt": "some-packages",
+		}, "stemcell.MF", "image", "stemcell_dpkg_l.txt")
+	})
+
+	Describe("ReadLightStemcell", func() {
+		It("reads the name, version and AMIs of the stemcell", func() {
+			stemcell, err := bosh.ReadLightStemcell(tgz)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stemcell.Name).To(Equal("bosh-aws-xen-hvm-ubuntu-trusty-go_agent"))
+			Expect(stemcell.Version).To(Equal("3586.42"))
+			Expect(stemcell.AMIs).To(Equal(map[string]string{
+				"us-east-1": "ami-1111",
+				"eu-west-1": "ami-2222",
+			}))
+		})
+
+		It("returns an error for a stemcell that is not light", func() {
+			_, err := bosh.ReadLightStemcell(pack(map[string]string{
+				"stemcell.MF": "name: some-stemcell\nversion: 1\ncloud_properties: {}\n",
+			}, "stemcell.MF"))
+			Expect(err).To(MatchError("Stemcell some-stemcell is not a light stemcell: it refers to no AMIs."))
+		})
+
+		It("returns an error for a tarball without a stemcell.MF", func() {
+			_, err := bosh.ReadLightStemcell(pack(map[string]string{"image": ""}, "image"))
+			Expect(err).To(MatchError("Read stemcell: it has no stemcell.MF."))
+		})
+
+		It("returns an error for a file that is not a tarball", func() {
+			_, err := bosh.ReadLightStemcell([]byte("not a tarball"))
+			Expect(err).To(MatchError(ContainSubstring("Read stemcell:")))
+		})
+	})
+
+	Describe("WithAMI", func() {
+		It("repacks the stemcell with the AMI of the region", func() {
+			stemcell, err := bosh.ReadLightStemcell(tgz)
+			Expect(err).NotTo(HaveOccurred())
+
+			repacked, err := stemcell.WithAMI("ap-south-1", "ami-3333")
+			Expect(err).NotTo(HaveOccurred())
+
+			files := unpack(repacked)
+			Expect(files).To(HaveKeyWithValue("stemcell_dpkg_l.txt", "some-packages"))
+			Expect(files).To(HaveKeyWithValue("image", ""))
+			Expect(files["stemcell.MF"]).To(MatchYAML(`---
+name: bosh-aws-xen-hvm-ubuntu-trusty-go_agent
+version: '3586.42'
+cloud_properties:
+  ami:
+    us-east-1: ami-1111
+    eu-west-1: ami-2222
+    ap-south-1: ami-3333
+  root_device_name: /dev/sda1
+`))
+
+			copied, err := bosh.ReadLightStemcell(repacked)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied.AMIs).To(HaveKeyWithValue("ap-south-1", "ami-3333"))
+		})
+
+		It("replaces the AMI of a region that has one", func() {
+			stemcell, err := bosh.ReadLightStemcell(tgz)
+			Expect(err).NotTo(HaveOccurred())
+
+			repacked, err := stemcell.WithAMI("eu-west-1", "ami-3333")
+			Expect(err).NotTo(HaveOccurred())
+
+			copied, err := bosh.ReadLightStemcell(repacked)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied.AMIs).To(Equal(map[string]string{
+				"us-east-1": "ami-1111",
+				"eu-west-1": "ami-3333",
+			}))
+		})
+	})
+})
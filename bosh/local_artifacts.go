@@ -0,0 +1,244 @@
+package bosh
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DeploymentArtifacts are the releases and stemcell that bosh create-env
+// installs on the jumpbox or the director.
+type DeploymentArtifacts struct {
+	Releases []Artifact
+	Stemcell *Artifact
+}
+
+// All lists the releases, then the stemcell.
+func (d DeploymentArtifacts) All() []Artifact {
+	all := append([]Artifact{}, d.Releases...)
+	if d.Stemcell != nil {
+		all = append(all, *d.Stemcell)
+	}
+	return all
+}
+
+// FileName is the name of the tarball of the artifact in an artifacts
+// directory.
+func (a Artifact) FileName() string {
+	if a.Version == "" {
+		return fmt.Sprintf("%s.tgz", a.Name)
+	}
+	return fmt.Sprintf("%s-%s.tgz", a.Name, a.Version)
+}
+
+// JumpboxArtifacts reads the releases and stemcell of the jumpbox of iaas.
+func JumpboxArtifacts(iaas string) (DeploymentArtifacts, error) {
+	ops := []string{}
+	if iaas == "openstack" {
+		ops = append(ops, OpenStackJumpboxKeystoneV3Ops)
+	}
+
+	return deploymentArtifacts(path.Join(jumpboxDeploymentRepo, "jumpbox.yml"), []string{
+		path.Join(jumpboxDeploymentRepo, iaas, "cpi.yml"),
+	}, ops)
+}
+
+// DirectorArtifacts reads the releases and stemcell of the director of iaas,
+// once the overrides of bbl plan are applied.
+func DirectorArtifacts(iaas string, lite bool, overrides storage.ArtifactOverrides) (DeploymentArtifacts, error) {
+	opsFiles := []string{
+		path.Join(boshDeploymentRepo, iaas, "cpi.yml"),
+		path.Join(boshDeploymentRepo, "jumpbox-user.yml"),
+		path.Join(boshDeploymentRepo, "uaa.yml"),
+		path.Join(boshDeploymentRepo, "credhub.yml"),
+	}
+	if lite {
+		opsFiles = append(opsFiles, path.Join(boshDeploymentRepo, "bosh-lite.yml"), path.Join(boshDeploymentRepo, "bosh-lite-runc.yml"))
+	}
+
+	ops := []string{}
+	if !overrides.IsEmpty() {
+		overridesOps, err := ArtifactOverridesOps(overrides, iaas)
+		if err != nil {
+			return DeploymentArtifacts{}, err
+		}
+		ops = append(ops, overridesOps)
+	}
+
+	return deploymentArtifacts(path.Join(boshDeploymentRepo, "bosh.yml"), opsFiles, ops)
+}
+
+// localArtifactsOps points the releases and stemcell of a create-env at their
+// tarballs in dir, which bosh create-env reads instead of downloading them,
+// and still checks against their sha1s.
+func (e Executor) localArtifactsOps(artifacts DeploymentArtifacts, dir string) (string, error) {
+	ops := "---\n"
+
+	for _, artifact := range artifacts.All() {
+		tarball := filepath.Join(dir, artifact.FileName())
+		if _, err := e.fs.Stat(tarball); err != nil {
+			return "", fmt.Errorf("The artifacts directory has no %s. Run bbl download-artifacts --dir %s on a machine with internet access.", artifact.FileName(), dir)
+		}
+	}
+
+	for _, release := range artifacts.Releases {
+		ops += fmt.Sprintf(`- type: replace
+  path: /releases/name=%s/url
+  value: %q
+
+`, release.Name, "file://"+filepath.Join(dir, release.FileName()))
+	}
+
+	if artifacts.Stemcell != nil {
+		ops += fmt.Sprintf(`- type: replace
+  path: /resource_pools/name=vms/stemcell/url
+  value: %q
+
+`, "file://"+filepath.Join(dir, artifacts.Stemcell.FileName()))
+	}
+
+	return ops, nil
+}
+
+// deploymentArtifacts reads the releases of a manifest and the stemcell of
+// its vms, and follows the ops files that add, replace or remove them: the
+// assets at opsFiles, then ops.
+func deploymentArtifacts(manifestAsset string, opsFiles []string, ops []string) (DeploymentArtifacts, error) {
+	var manifest struct {
+		Releases []Artifact `yaml:"releases"`
+	}
+	err := yaml.Unmarshal(MustAsset(manifestAsset), &manifest)
+	if err != nil {
+		return DeploymentArtifacts{}, fmt.Errorf("Read %s: %s", path.Base(manifestAsset), err)
+	}
+
+	allOps := []string{}
+	for _, opsFile := range opsFiles {
+		contents, err := Asset(opsFile)
+		if err != nil {
+			return DeploymentArtifacts{}, fmt.Errorf("Read %s: %s", opsFile, err)
+		}
+		allOps = append(allOps, string(contents))
+	}
+	allOps = append(allOps, ops...)
+
+	artifacts := DeploymentArtifacts{Releases: manifest.Releases}
+	for _, contents := range allOps {
+		var entries []struct {
+			Type  string      `yaml:"type"`
+			Path  string      `yaml:"path"`
+			Value interface{} `yaml:"value"`
+		}
+		err := yaml.Unmarshal([]byte(contents), &entries)
+		if err != nil {
+			return DeploymentArtifacts{}, fmt.Errorf("Read ops file: %s", err)
+		}
+
+		for _, entry := range entries {
+			err = artifacts.apply(entry.Type, entry.Path, entry.Value)
+			if err != nil {
+				return DeploymentArtifacts{}, err
+			}
+		}
+	}
+
+	// Releases without a version, such as the cpis of the jumpbox, are
+	// versioned by the v parameter of their bosh.io urls.
+	for i, release := range artifacts.Releases {
+		if u, err := url.Parse(release.URL); err == nil && release.Version == "" {
+			artifacts.Releases[i].Version = u.Query().Get("v")
+		}
+	}
+
+	return artifacts, nil
+}
+
+// apply follows an operation of an ops file on the releases or the stemcell.
+// Other operations are left out.
+func (d *DeploymentArtifacts) apply(opType, opPath string, value interface{}) error {
+	switch {
+	case opPath == "/resource_pools/name=vms/stemcell" || opPath == "/resource_pools/name=vms/stemcell?":
+		artifact, err := opsFileArtifact(opsFileEntry{Path: opPath, Value: value})
+		if err != nil {
+			return err
+		}
+		stemcell := stemcellArtifact(*artifact)
+		d.Stemcell = &stemcell
+
+	case opPath == "/releases/-":
+		artifact, err := opsFileArtifact(opsFileEntry{Path: opPath, Value: value})
+		if err != nil {
+			return err
+		}
+		d.replaceRelease(*artifact)
+
+	case strings.HasPrefix(opPath, "/releases/name="):
+		parts := strings.SplitN(strings.TrimPrefix(opPath, "/releases/name="), "/", 2)
+		name := strings.TrimSuffix(parts[0], "?")
+
+		if len(parts) == 1 {
+			if opType == "remove" {
+				d.removeRelease(name)
+				return nil
+			}
+			artifact, err := opsFileArtifact(opsFileEntry{Path: opPath, Value: value})
+			if err != nil {
+				return err
+			}
+			d.replaceRelease(*artifact)
+			return nil
+		}
+
+		for i := range d.Releases {
+			if d.Releases[i].Name != name {
+				continue
+			}
+			switch parts[1] {
+			case "url":
+				d.Releases[i].URL = fmt.Sprintf("%v", value)
+			case "sha1":
+				d.Releases[i].SHA1 = fmt.Sprintf("%v", value)
+			case "version":
+				d.Releases[i].Version = fmt.Sprintf("%v", value)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *DeploymentArtifacts) replaceRelease(release Artifact) {
+	for i := range d.Releases {
+		if d.Releases[i].Name == release.Name {
+			d.Releases[i] = release
+			return
+		}
+	}
+	d.Releases = append(d.Releases, release)
+}
+
+func (d *DeploymentArtifacts) removeRelease(name string) {
+	kept := []Artifact{}
+	for _, release := range d.Releases {
+		if release.Name != name {
+			kept = append(kept, release)
+		}
+	}
+	d.Releases = kept
+}
+
+// stemcellArtifact names a stemcell after the url it is downloaded from, and
+// takes its version from the v parameter of bosh.io urls.
+func stemcellArtifact(artifact Artifact) Artifact {
+	u, err := url.Parse(artifact.URL)
+	if err != nil {
+		return artifact
+	}
+	artifact.Name = strings.TrimSuffix(path.Base(u.Path), ".tgz")
+	artifact.Version = u.Query().Get("v")
+	return artifact
+}
@@ -0,0 +1,90 @@
+package bosh_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeploymentArtifacts", func() {
+	fileNames := func(artifacts bosh.DeploymentArtifacts) []string {
+		names := []string{}
+		for _, artifact := range artifacts.All() {
+			names = append(names, artifact.FileName())
+		}
+		return names
+	}
+
+	Describe("JumpboxArtifacts", func() {
+		It("returns the releases and stemcell of the jumpbox", func() {
+			artifacts, err := bosh.JumpboxArtifacts("aws")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileNames(artifacts)).To(Equal([]string{
+				"os-conf-13.tgz",
+				"bosh-aws-cpi-69.tgz",
+				"bosh-aws-xen-hvm-ubuntu-trusty-go_agent-3468.17.tgz",
+			}))
+			Expect(artifacts.Stemcell.URL).To(Equal("https://bosh.io/d/stemcells/bosh-aws-xen-hvm-ubuntu-trusty-go_agent?v=3468.17"))
+			Expect(artifacts.Stemcell.SHA1).To(Equal("c09040e9e0fcef6dffaece68f8d4173066e4f458"))
+		})
+
+		It("follows the cpi that bbl pins for openstack", func() {
+			artifacts, err := bosh.JumpboxArtifacts("openstack")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(artifacts.Releases[1].FileName()).To(Equal("bosh-openstack-cpi-37.tgz"))
+			Expect(artifacts.Releases[1].SHA1).To(Equal("4507b907955909bc8036afc1cf6be339b306ca03"))
+		})
+	})
+
+	Describe("DirectorArtifacts", func() {
+		It("returns the releases and stemcell of the director", func() {
+			artifacts, err := bosh.DirectorArtifacts("gcp", false, storage.ArtifactOverrides{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileNames(artifacts)).To(Equal([]string{
+				"bosh-264.7.0.tgz",
+				"bosh-google-cpi-26.0.0.tgz",
+				"os-conf-18.tgz",
+				"uaa-52.7.tgz",
+				"credhub-1.6.5.tgz",
+				"bosh-google-kvm-ubuntu-trusty-go_agent-3468.21.tgz",
+			}))
+		})
+
+		It("adds the releases of a bosh-lite director, without the ones it removes", func() {
+			artifacts, err := bosh.DirectorArtifacts("aws", true, storage.ArtifactOverrides{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileNames(artifacts)).To(ContainElement("bosh-warden-cpi-37.tgz"))
+			Expect(fileNames(artifacts)).To(ContainElement("garden-runc-1.9.4.tgz"))
+			Expect(fileNames(artifacts)).NotTo(ContainElement(HavePrefix("garden-linux")))
+		})
+
+		It("applies the overrides of bbl plan", func() {
+			artifacts, err := bosh.DirectorArtifacts("aws", false, storage.ArtifactOverrides{
+				CPIReleaseURL:  "https://bosh.io/d/github.com/cloudfoundry-incubator/bosh-aws-cpi-release?v=70",
+				CPIReleaseSHA1: "some-cpi-sha1",
+				StemcellURL:    "https://example.com/light-stemcell.tgz",
+				StemcellSHA1:   "some-stemcell-sha1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(artifacts.Releases[1]).To(Equal(bosh.Artifact{
+				Name:    "bosh-aws-cpi",
+				Version: "70",
+				URL:     "https://bosh.io/d/github.com/cloudfoundry-incubator/bosh-aws-cpi-release?v=70",
+				SHA1:    "some-cpi-sha1",
+			}))
+			Expect(*artifacts.Stemcell).To(Equal(bosh.Artifact{
+				Name: "light-stemcell",
+				URL:  "https://example.com/light-stemcell.tgz",
+				SHA1: "some-stemcell-sha1",
+			}))
+			Expect(artifacts.Stemcell.FileName()).To(Equal("light-stemcell.tgz"))
+		})
+	})
+})
@@ -21,14 +21,16 @@ var (
 type managerFs interface {
 	fileio.FileWriter
 	fileio.TempDirer
+	fileio.Stater
 }
 
 type Manager struct {
-	executor     executor
-	logger       logger
-	stateStore   stateStore
-	sshKeyGetter sshKeyGetter
-	fs           managerFs
+	executor      executor
+	logger        logger
+	stateStore    stateStore
+	sshKeyGetter  sshKeyGetter
+	fs            managerFs
+	jumpboxTunnel jumpboxTunnel
 }
 
 type directorVars struct {
@@ -64,13 +66,18 @@ type sshKeyGetter interface {
 	Get(string) (string, error)
 }
 
-func NewManager(executor executor, logger logger, stateStore stateStore, sshKeyGetter sshKeyGetter, fs deleterFs) *Manager {
+type jumpboxTunnel interface {
+	TunnelAddr(storage.Jumpbox) (string, error)
+}
+
+func NewManager(executor executor, logger logger, stateStore stateStore, sshKeyGetter sshKeyGetter, fs managerFs, jumpboxTunnel jumpboxTunnel) *Manager {
 	return &Manager{
-		executor:     executor,
-		logger:       logger,
-		stateStore:   stateStore,
-		sshKeyGetter: sshKeyGetter,
-		fs:           fs,
+		executor:      executor,
+		logger:        logger,
+		stateStore:    stateStore,
+		sshKeyGetter:  sshKeyGetter,
+		fs:            fs,
+		jumpboxTunnel: jumpboxTunnel,
 	}
 }
 
@@ -97,8 +104,10 @@ func (m *Manager) InitializeJumpbox(state storage.State) error {
 	}
 
 	iaasInputs := DirInput{
-		StateDir: stateDir,
-		VarsDir:  varsDir,
+		StateDir:     stateDir,
+		VarsDir:      varsDir,
+		SSHCA:        state.SSHCA,
+		ArtifactsDir: state.ArtifactsDir,
 	}
 
 	err = m.executor.PlanJumpbox(iaasInputs, deploymentDir, state.IAAS)
@@ -109,6 +118,38 @@ func (m *Manager) InitializeJumpbox(state storage.State) error {
 	return nil
 }
 
+// WriteDeploymentVars writes the jumpbox and director vars files that the
+// create-jumpbox.sh and create-director.sh scripts read, without running them,
+// for environments whose director is deployed by the operator.
+func (m *Manager) WriteDeploymentVars(state storage.State, terraformOutputs terraform.Outputs) error {
+	varsDir, err := m.stateStore.GetVarsDir()
+	if err != nil {
+		return fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	stateDir := m.stateStore.GetStateDir()
+
+	err = m.executor.WriteDeploymentVars(DirInput{
+		Deployment: "jumpbox",
+		StateDir:   stateDir,
+		VarsDir:    varsDir,
+	}, m.GetJumpboxDeploymentVars(state, terraformOutputs))
+	if err != nil {
+		return fmt.Errorf("Write jumpbox deployment vars: %s", err)
+	}
+
+	err = m.executor.WriteDeploymentVars(DirInput{
+		Deployment: "director",
+		StateDir:   stateDir,
+		VarsDir:    varsDir,
+	}, m.GetDirectorDeploymentVars(state, terraformOutputs))
+	if err != nil {
+		return fmt.Errorf("Write director deployment vars: %s", err)
+	}
+
+	return nil
+}
+
 func (m *Manager) CreateJumpbox(state storage.State, terraformOutputs terraform.Outputs) (storage.State, error) {
 	m.logger.Step("creating jumpbox")
 
@@ -140,25 +181,11 @@ func (m *Manager) CreateJumpbox(state storage.State, terraformOutputs terraform.
 		URL: terraformOutputs.GetString("jumpbox_url"),
 	}
 
-	dir, err := m.fs.TempDir("", "bosh-jumpbox")
-	if err != nil {
-		return storage.State{}, fmt.Errorf("Create temp dir for jumpbox private key: %s", err)
-	}
-
-	privateKeyPath := filepath.Join(dir, "bosh_jumpbox_private.key")
-
-	privateKeyContents, err := m.sshKeyGetter.Get("jumpbox")
-	if err != nil {
-		return storage.State{}, fmt.Errorf("Get jumpbox private key: %s", err)
-	}
-
-	err = m.fs.WriteFile(privateKeyPath, []byte(privateKeyContents), 0600)
+	err = m.setAllProxy(state.Jumpbox)
 	if err != nil {
-		return storage.State{}, fmt.Errorf("Write jumpbox private key: %s", err)
+		return storage.State{}, err
 	}
 
-	osSetenv("BOSH_ALL_PROXY", fmt.Sprintf("ssh+socks5://jumpbox@%s?private-key=%s", state.Jumpbox.URL, privateKeyPath))
-
 	return state, nil
 }
 
@@ -176,8 +203,27 @@ func (m *Manager) InitializeDirector(state storage.State) error {
 	}
 
 	iaasInputs := DirInput{
-		StateDir: stateDir,
-		VarsDir:  varsDir,
+		StateDir:       stateDir,
+		VarsDir:        varsDir,
+		SSHCA:          state.SSHCA,
+		TrustedCACerts: state.TrustedCACerts,
+		S3Blobstore:    state.IAAS == "aws" && state.AWS.S3Blobstore,
+		Lite:           state.IAAS == "aws" && state.AWS.Lite,
+		ArtifactsDir:   state.ArtifactsDir,
+		OpsFiles:       state.DirectorOpsFiles,
+		VarsFiles:      state.DirectorVarsFiles,
+	}
+	if state.DirectorPorts != nil {
+		iaasInputs.DirectorPorts = *state.DirectorPorts
+	}
+	if state.DirectorVM != nil {
+		iaasInputs.DirectorVM = *state.DirectorVM
+	}
+	if state.DirectorSyslog != nil {
+		iaasInputs.DirectorSyslog = *state.DirectorSyslog
+	}
+	if state.ArtifactOverrides != nil {
+		iaasInputs.ArtifactOverrides = *state.ArtifactOverrides
 	}
 
 	err = m.executor.PlanDirector(iaasInputs, directorDeploymentDir, state.IAAS)
@@ -209,6 +255,13 @@ func (m *Manager) CreateDirector(state storage.State, terraformOutputs terraform
 		return storage.State{}, fmt.Errorf("Write deployment vars: %s", err)
 	}
 
+	// A bbl up that resumes after the jumpbox was created skips
+	// CreateJumpbox, so the director is reached through the jumpbox here too.
+	err = m.setAllProxy(state.Jumpbox)
+	if err != nil {
+		return storage.State{}, err
+	}
+
 	variables, err := m.executor.CreateEnv(dirInput, state)
 	if err != nil {
 		state.BOSH = storage.BOSH{
@@ -241,10 +294,37 @@ func (m *Manager) CreateDirector(state storage.State, terraformOutputs terraform
 		DirectorSSLPrivateKey:  directorVars.sslPrivateKey,
 	}
 
+	// The versions are recorded for bbl upgrade-director to compare with the
+	// ones of a newer bbl, and the artifacts for a failed upgrade to roll
+	// back to. Only an unknown iaas has none.
+	if versions, err := DirectorVersions(state); err == nil {
+		state.DirectorVersions = &versions
+	}
+	if artifacts, err := DeployedArtifacts(state); err == nil {
+		state.DeployedArtifacts = &artifacts
+	}
+
 	m.logger.Step("created bosh director")
 	return state, nil
 }
 
+// IsDeployed reports whether bosh create-env keeps a state of the jumpbox or
+// the director in the vars directory, where the state migrator moves it.
+func (m *Manager) IsDeployed(deployment string) (bool, error) {
+	varsDir, err := m.stateStore.GetVarsDir()
+	if err != nil {
+		return false, fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	stateFile := "jumpbox-state.json"
+	if deployment == "director" {
+		stateFile = "bosh-state.json"
+	}
+
+	_, err = m.fs.Stat(filepath.Join(varsDir, stateFile))
+	return err == nil, nil
+}
+
 func (m *Manager) DeleteDirector(state storage.State, terraformOutputs terraform.Outputs) error {
 	if state.BOSH.IsEmpty() {
 		return nil
@@ -270,25 +350,11 @@ func (m *Manager) DeleteDirector(state storage.State, terraformOutputs terraform
 		return fmt.Errorf("Write deployment vars: %s", err)
 	}
 
-	dir, err := m.fs.TempDir("", "bosh-jumpbox")
-	if err != nil {
-		return fmt.Errorf("Create temp dir for jumpbox private key: %s", err)
-	}
-
-	privateKeyPath := filepath.Join(dir, "bosh_jumpbox_private.key")
-
-	privateKeyContents, err := m.sshKeyGetter.Get("jumpbox")
-	if err != nil {
-		return fmt.Errorf("Get jumpbox private key: %s", err)
-	}
-
-	err = m.fs.WriteFile(privateKeyPath, []byte(privateKeyContents), 0600)
+	err = m.setAllProxy(state.Jumpbox)
 	if err != nil {
-		return fmt.Errorf("Write jumpbox private key: %s", err)
+		return err
 	}
 
-	osSetenv("BOSH_ALL_PROXY", fmt.Sprintf("ssh+socks5://jumpbox@%s?private-key=%s", state.Jumpbox.URL, privateKeyPath))
-
 	err = m.executor.DeleteEnv(dirInput, state)
 	if err != nil {
 		return NewManagerDeleteError(state, err)
@@ -359,6 +425,58 @@ func mustMarshal(yamlStruct interface{}) []byte {
 }
 
 func (m *Manager) GetDirectorDeploymentVars(state storage.State, terraformOutputs terraform.Outputs) string {
+	vars := sharedDeploymentVarsYAML{
+		TerraformOutputs: directorOutputs(terraformOutputs),
+	}
+
+	return string(mustMarshal(vars))
+}
+
+// GetBOSHDeploymentVars are the director vars with the credentials of the
+// iaas, which bosh create-env of the bosh-deployment repo takes with
+// --vars-file for users that create the director of an environment
+// themselves.
+func (m *Manager) GetBOSHDeploymentVars(state storage.State, terraformOutputs terraform.Outputs) string {
+	vars := sharedDeploymentVarsYAML{
+		TerraformOutputs: directorOutputs(terraformOutputs),
+	}
+
+	switch state.IAAS {
+	case "aws":
+		vars.AWSYAML = AWSYAML{
+			AccessKeyID:     state.AWS.AccessKeyID,
+			SecretAccessKey: state.AWS.SecretAccessKey,
+			SessionToken:    state.AWS.SessionToken,
+		}
+	case "gcp":
+		vars.GCPYAML = GCPYAML{
+			Zone:           state.GCP.Zone,
+			ProjectID:      state.GCP.ProjectID,
+			CredentialJSON: state.GCP.ServiceAccountKey,
+		}
+	case "azure":
+		vars.AzureYAML = AzureYAML{
+			SubscriptionID: state.Azure.SubscriptionID,
+			TenantID:       state.Azure.TenantID,
+			ClientID:       state.Azure.ClientID,
+			ClientSecret:   state.Azure.ClientSecret,
+		}
+	case "vsphere":
+		vars.VSphereYAML = VSphereYAML{
+			VCenterUser:     state.VSphere.VCenterUser,
+			VCenterPassword: state.VSphere.VCenterPassword,
+		}
+	case "openstack":
+		vars.OpenStackYAML = OpenStackYAML{
+			Username: state.OpenStack.Username,
+			Password: state.OpenStack.Password,
+		}
+	}
+
+	return string(mustMarshal(vars))
+}
+
+func directorOutputs(terraformOutputs terraform.Outputs) map[string]interface{} {
 	allOutputs := map[string]interface{}{}
 	for k, v := range terraformOutputs.Map {
 		if strings.HasPrefix(k, "director__") || strings.HasPrefix(k, "jumpbox__") {
@@ -374,11 +492,7 @@ func (m *Manager) GetDirectorDeploymentVars(state storage.State, terraformOutput
 		}
 	}
 
-	vars := sharedDeploymentVarsYAML{
-		TerraformOutputs: allOutputs,
-	}
-
-	return string(mustMarshal(vars))
+	return allOutputs
 }
 
 func getDirectorVars(v string) directorVars {
@@ -404,3 +518,48 @@ func getDirectorVars(v string) directorVars {
 		sslPrivateKey:  vars.DirectorSSL.PrivateKey,
 	}
 }
+
+// setAllProxy sets BOSH_ALL_PROXY for the bosh cli to reach the director
+// through the jumpbox, with the private key of the jumpbox in a temp file.
+func (m *Manager) setAllProxy(jumpbox storage.Jumpbox) error {
+	dir, err := m.fs.TempDir("", "bosh-jumpbox")
+	if err != nil {
+		return fmt.Errorf("Create temp dir for jumpbox private key: %s", err)
+	}
+
+	privateKeyPath := filepath.Join(dir, "bosh_jumpbox_private.key")
+
+	privateKeyContents, err := m.sshKeyGetter.Get("jumpbox")
+	if err != nil {
+		return fmt.Errorf("Get jumpbox private key: %s", err)
+	}
+
+	err = m.fs.WriteFile(privateKeyPath, []byte(privateKeyContents), 0600)
+	if err != nil {
+		return fmt.Errorf("Write jumpbox private key: %s", err)
+	}
+
+	allProxy, err := m.allProxy(jumpbox, privateKeyPath)
+	if err != nil {
+		return err
+	}
+	osSetenv("BOSH_ALL_PROXY", allProxy)
+
+	return nil
+}
+
+// allProxy is the BOSH_ALL_PROXY that the bosh cli reaches the director
+// through. The bosh cli connects to the jumpbox over ssh itself, unless the
+// jumpbox is behind an HTTP proxy, which it cannot tunnel through. The socks5
+// proxy of bbl is used then.
+func (m *Manager) allProxy(jumpbox storage.Jumpbox, privateKeyPath string) (string, error) {
+	addr, err := m.jumpboxTunnel.TunnelAddr(jumpbox)
+	if err != nil {
+		return "", fmt.Errorf("Connect to the jumpbox through the proxy: %s", err)
+	}
+	if addr != "" {
+		return fmt.Sprintf("socks5://%s", addr), nil
+	}
+
+	return fmt.Sprintf("ssh+socks5://jumpbox@%s?private-key=%s", jumpbox.URL, privateKeyPath), nil
+}
@@ -22,6 +22,7 @@ var _ = Describe("Manager", func() {
 		stateStore   *fakes.StateStore
 		sshKeyGetter *fakes.SSHKeyGetter
 		fs           *fakes.FileIO
+		tunnel       *fakes.JumpboxTunnel
 
 		boshManager      *bosh.Manager
 		terraformOutputs terraform.Outputs
@@ -38,6 +39,7 @@ var _ = Describe("Manager", func() {
 		sshKeyGetter = &fakes.SSHKeyGetter{}
 		sshKeyGetter.GetCall.Returns.PrivateKey = "some-jumpbox-private-key"
 		fs = &fakes.FileIO{}
+		tunnel = &fakes.JumpboxTunnel{}
 
 		stateStore = &fakes.StateStore{}
 		stateStore.GetVarsDirCall.Returns.Directory = "some-bbl-vars-dir"
@@ -45,7 +47,7 @@ var _ = Describe("Manager", func() {
 		stateStore.GetDirectorDeploymentDirCall.Returns.Directory = "some-director-deployment-dir"
 		stateStore.GetJumpboxDeploymentDirCall.Returns.Directory = "some-jumpbox-deployment-dir"
 
-		boshManager = bosh.NewManager(boshExecutor, logger, stateStore, sshKeyGetter, fs)
+		boshManager = bosh.NewManager(boshExecutor, logger, stateStore, sshKeyGetter, fs, tunnel)
 
 		boshVars = `admin_password: some-admin-password
 director_ssl:
@@ -102,6 +104,64 @@ director_ssl:
 				Expect(boshExecutor.CreateEnvCall.CallCount).To(Equal(0))
 			})
 
+			It("passes on whether the environment uses an ssh certificate authority", func() {
+				state.SSHCA = true
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.SSHCA).To(BeTrue())
+			})
+
+			It("passes on the trusted ca certificates", func() {
+				state.TrustedCACerts = "some-ca-certs"
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.TrustedCACerts).To(Equal("some-ca-certs"))
+			})
+
+			It("passes on the artifact overrides", func() {
+				state.ArtifactOverrides = &storage.ArtifactOverrides{StemcellURL: "some-url", StemcellSHA1: "some-sha1"}
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.ArtifactOverrides).To(Equal(storage.ArtifactOverrides{StemcellURL: "some-url", StemcellSHA1: "some-sha1"}))
+			})
+
+			It("passes on the s3 blobstore of aws environments", func() {
+				state.IAAS = "aws"
+				state.AWS.S3Blobstore = true
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.S3Blobstore).To(BeTrue())
+			})
+
+			It("passes on the bosh-lite mode of aws environments", func() {
+				state.IAAS = "aws"
+				state.AWS.Lite = true
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.Lite).To(BeTrue())
+			})
+
+			It("passes on the director ports", func() {
+				state.DirectorPorts = &storage.DirectorPorts{NATS: 4223}
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.DirectorPorts).To(Equal(storage.DirectorPorts{NATS: 4223}))
+			})
+
+			It("passes on the size of the director", func() {
+				state.DirectorVM = &storage.DirectorVM{InstanceType: "t2.medium", DiskSize: 200}
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.DirectorVM).To(Equal(storage.DirectorVM{InstanceType: "t2.medium", DiskSize: 200}))
+			})
+
+			It("passes on the syslog forwarding of the director", func() {
+				state.DirectorSyslog = &storage.DirectorSyslog{Address: "logs.example.com", Port: 514, Transport: "tcp"}
+				err := boshManager.InitializeDirector(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanDirectorCall.Receives.DirInput.DirectorSyslog).To(Equal(storage.DirectorSyslog{Address: "logs.example.com", Port: 514, Transport: "tcp"}))
+			})
+
 			Context("when create env args fails", func() {
 				BeforeEach(func() {
 					boshExecutor.PlanDirectorCall.Returns.Error = errors.New("failed to interpolate")
@@ -164,7 +224,46 @@ director_ssl:
 				}))
 			})
 
+			It("records the versions of the artifacts it deployed the director from", func() {
+				state.IAAS = "aws"
+				state.ArtifactOverrides = &storage.ArtifactOverrides{BOSHReleaseURL: "https://bosh.io/d/github.com/cloudfoundry/bosh?v=271.0.0"}
+
+				stateWithDirector, err := boshManager.CreateDirector(state, terraformOutputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				pinned, err := bosh.PinnedArtifacts("aws")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stateWithDirector.DirectorVersions).To(Equal(&storage.DirectorVersions{
+					BOSH:     "271.0.0",
+					CPI:      pinned.CPI.Version,
+					Stemcell: pinned.Stemcell.Version,
+				}))
+				Expect(stateWithDirector.DeployedArtifacts.BOSHReleaseURL).To(Equal("https://bosh.io/d/github.com/cloudfoundry/bosh?v=271.0.0"))
+				Expect(stateWithDirector.DeployedArtifacts.StemcellURL).To(Equal(pinned.Stemcell.URL))
+			})
+
+			It("sets BOSH_ALL_PROXY to reach the director through the jumpbox, which a resumed up did not create", func() {
+				state.Jumpbox = storage.Jumpbox{URL: "some-jumpbox-url:22"}
+
+				_, err := boshManager.CreateDirector(state, terraformOutputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(osSetenvKey).To(Equal("BOSH_ALL_PROXY"))
+				Expect(osSetenvValue).To(HavePrefix("ssh+socks5://jumpbox@some-jumpbox-url:22?private-key="))
+				Expect(osSetenvValue).To(HaveSuffix("bosh_jumpbox_private.key"))
+			})
+
 			Context("when an error occurs", func() {
+				Context("when getting the jumpbox key fails", func() {
+					It("returns an error", func() {
+						sshKeyGetter.GetCall.Returns.Error = errors.New("soursop")
+
+						_, err := boshManager.CreateDirector(state, terraformOutputs)
+						Expect(err).To(MatchError("Get jumpbox private key: soursop"))
+						Expect(boshExecutor.CreateEnvCall.CallCount).To(Equal(0))
+					})
+				})
+
 				Context("when get vars dir fails", func() {
 					It("returns an error", func() {
 						stateStore.GetVarsDirCall.Returns.Error = errors.New("pineapple")
@@ -253,6 +352,13 @@ gcp_credentials_json: some-credential-json
 				Expect(boshExecutor.PlanJumpboxCall.Receives.DirInput.StateDir).To(Equal("some-state-dir"))
 			})
 
+			It("passes on whether the environment uses an ssh certificate authority", func() {
+				state.SSHCA = true
+				err := boshManager.InitializeJumpbox(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshExecutor.PlanJumpboxCall.Receives.DirInput.SSHCA).To(BeTrue())
+			})
+
 			Context("when an error occurs", func() {
 				Context("when get vars dir fails", func() {
 					It("returns an error", func() {
@@ -289,6 +395,32 @@ gcp_credentials_json: some-credential-json
 				}))
 			})
 
+			Context("when the jumpbox is behind an http proxy", func() {
+				BeforeEach(func() {
+					tunnel.TunnelAddrCall.Returns.Addr = "127.0.0.1:40000"
+				})
+
+				It("sets BOSH_ALL_PROXY to the socks5 proxy of bbl to create the director", func() {
+					_, err := boshManager.CreateJumpbox(state, terraformOutputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(tunnel.TunnelAddrCall.Receives.Jumpbox.URL).To(Equal("some-jumpbox-url:22"))
+					Expect(osSetenvKey).To(Equal("BOSH_ALL_PROXY"))
+					Expect(osSetenvValue).To(Equal("socks5://127.0.0.1:40000"))
+				})
+
+				Context("when the jumpbox cannot be reached through the proxy", func() {
+					BeforeEach(func() {
+						tunnel.TunnelAddrCall.Returns.Error = errors.New("proxy refused")
+					})
+
+					It("returns an error", func() {
+						_, err := boshManager.CreateJumpbox(state, terraformOutputs)
+						Expect(err).To(MatchError("Connect to the jumpbox through the proxy: proxy refused"))
+					})
+				})
+			})
+
 			It("returns a bbl state with bosh and jumpbox deployment values", func() {
 				state, err := boshManager.CreateJumpbox(state, terraformOutputs)
 				Expect(err).NotTo(HaveOccurred())
@@ -428,6 +560,34 @@ gcp_credentials_json: some-credential-json
 		})
 	})
 
+	Describe("IsDeployed", func() {
+		It("looks for the create-env state of the deployment in the vars directory", func() {
+			deployed, err := boshManager.IsDeployed("director")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployed).To(BeTrue())
+			Expect(fs.StatCall.Receives.Name).To(Equal("some-bbl-vars-dir/bosh-state.json"))
+
+			_, err = boshManager.IsDeployed("jumpbox")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fs.StatCall.Receives.Name).To(Equal("some-bbl-vars-dir/jumpbox-state.json"))
+		})
+
+		It("returns false when there is no create-env state", func() {
+			fs.StatCall.Returns.Error = errors.New("no such file")
+
+			deployed, err := boshManager.IsDeployed("jumpbox")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployed).To(BeFalse())
+		})
+
+		It("returns an error when the vars dir cannot be found", func() {
+			stateStore.GetVarsDirCall.Returns.Error = errors.New("pineapple")
+
+			_, err := boshManager.IsDeployed("jumpbox")
+			Expect(err).To(MatchError("Get vars dir: pineapple"))
+		})
+	})
+
 	Describe("DeleteDirector", func() {
 		var varsDir string
 
@@ -563,6 +723,77 @@ key: some-director-value
 		})
 	})
 
+	Describe("GetBOSHDeploymentVars", func() {
+		It("adds the credentials of the iaas to the director vars", func() {
+			vars := boshManager.GetBOSHDeploymentVars(storage.State{
+				IAAS: "aws",
+				AWS: storage.AWS{
+					AccessKeyID:     "some-access-key-id",
+					SecretAccessKey: "some-secret-access-key",
+					Region:          "some-region",
+				},
+			}, terraform.Outputs{Map: map[string]interface{}{
+				"subnet_id":     "some-subnet-id",
+				"director__key": "some-director-value",
+				"jumpbox__key":  "some-jumpbox-value",
+			}})
+			Expect(vars).To(MatchYAML(`---
+access_key_id: some-access-key-id
+secret_access_key: some-secret-access-key
+subnet_id: some-subnet-id
+key: some-director-value
+`))
+		})
+
+		It("adds the credentials of gcp", func() {
+			vars := boshManager.GetBOSHDeploymentVars(storage.State{
+				IAAS: "gcp",
+				GCP: storage.GCP{
+					ServiceAccountKey: "some-service-account-key",
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+				},
+			}, terraform.Outputs{Map: map[string]interface{}{}})
+			Expect(vars).To(MatchYAML(`---
+gcp_credentials_json: some-service-account-key
+project_id: some-project-id
+zone: some-zone
+`))
+		})
+	})
+
+	Describe("WriteDeploymentVars", func() {
+		It("writes the jumpbox and the director vars without creating either", func() {
+			err := boshManager.WriteDeploymentVars(storage.State{}, terraform.Outputs{Map: map[string]interface{}{
+				"director__key": "some-director-value",
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshExecutor.WriteDeploymentVarsCall.CallCount).To(Equal(2))
+			Expect(boshExecutor.WriteDeploymentVarsCall.Receives.DirInput).To(Equal(bosh.DirInput{
+				Deployment: "director",
+				StateDir:   "some-state-dir",
+				VarsDir:    "some-bbl-vars-dir",
+			}))
+			Expect(boshExecutor.WriteDeploymentVarsCall.Receives.DeploymentVars).To(ContainSubstring("key: some-director-value"))
+			Expect(boshExecutor.CreateEnvCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the vars dir cannot be found", func() {
+			stateStore.GetVarsDirCall.Returns.Error = errors.New("pineapple")
+
+			err := boshManager.WriteDeploymentVars(storage.State{}, terraform.Outputs{})
+			Expect(err).To(MatchError("Get vars dir: pineapple"))
+		})
+
+		It("returns an error when the vars cannot be written", func() {
+			boshExecutor.WriteDeploymentVarsCall.Returns.Error = errors.New("mango")
+
+			err := boshManager.WriteDeploymentVars(storage.State{}, terraform.Outputs{})
+			Expect(err).To(MatchError("Write jumpbox deployment vars: mango"))
+		})
+	})
+
 	Describe("Version", func() {
 		BeforeEach(func() {
 			boshExecutor.VersionCall.Returns.Version = "1.1.1"
@@ -0,0 +1,117 @@
+package bosh
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	socks5proxy "github.com/cloudfoundry/socks5-proxy"
+	"golang.org/x/crypto/ssh"
+)
+
+// proxyFromEnvironment is the proxy that HTTPS_PROXY and NO_PROXY set for a
+// request, the same one that the other clients of bbl use.
+var proxyFromEnvironment = http.ProxyFromEnvironment
+
+// jumpboxProxy returns the proxy to connect to the jumpbox through, or nil
+// when the jumpbox is reached directly.
+func jumpboxProxy(jumpboxURL string) (*url.URL, error) {
+	return proxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: "https", Host: jumpboxURL}})
+}
+
+// jumpboxDialerThroughProxy connects to the jumpbox over ssh through the
+// proxy, and returns a dialer of the addresses behind the jumpbox for the
+// socks5 proxy. As the socks5 proxy does, it trusts the host key that the
+// jumpbox presents.
+func jumpboxDialerThroughProxy(privateKey, jumpboxURL string, proxyURL *url.URL) (socks5proxy.DialFunc, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %s", err)
+	}
+
+	conn, err := dialThroughProxy(proxyURL, jumpboxURL)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, jumpboxURL, &ssh.ClientConfig{
+		User:            "jumpbox",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh dial: %s", err)
+	}
+
+	return ssh.NewClient(clientConn, channels, requests).Dial, nil
+}
+
+// dialThroughProxy opens a tunnel to addr with a CONNECT request to an HTTP
+// proxy, authenticating with the user and password of the proxy url.
+func dialThroughProxy(proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("connect to proxy %s: %s", proxyURL.Host, err)
+	}
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	}
+
+	request := &http.Request{
+		Method: "CONNECT",
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	err = request.Write(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connect to %s through proxy %s: %s", addr, proxyURL.Host, err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connect to %s through proxy %s: %s", addr, proxyURL.Host, err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxyURL.Host, addr, response.Status)
+	}
+
+	// The jumpbox may speak first, so what the reader buffered past the
+	// response is read before the rest of the connection.
+	return bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
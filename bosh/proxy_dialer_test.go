@@ -0,0 +1,100 @@
+package bosh_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DialThroughProxy", func() {
+	var (
+		listener net.Listener
+		requests chan *http.Request
+		status   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		requests = make(chan *http.Request, 1)
+		status = "200 Connection established"
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			request, err := http.ReadRequest(bufio.NewReader(conn))
+			if err != nil {
+				return
+			}
+			requests <- request
+
+			// The jumpbox speaks first, in the same packet as the response.
+			conn.Write([]byte("HTTP/1.1 " + status + "\r\n\r\nSSH-2.0-jumpbox\r\n"))
+		}()
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	It("opens a tunnel to the address with a CONNECT request", func() {
+		conn, err := bosh.DialThroughProxy(&url.URL{Scheme: "http", Host: listener.Addr().String()}, "10.0.0.5:22")
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+
+		var request *http.Request
+		Eventually(requests).Should(Receive(&request))
+		Expect(request.Method).To(Equal("CONNECT"))
+		Expect(request.Host).To(Equal("10.0.0.5:22"))
+		Expect(request.Header.Get("Proxy-Authorization")).To(BeEmpty())
+
+		banner, err := ioutil.ReadAll(conn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(banner)).To(Equal("SSH-2.0-jumpbox\r\n"))
+	})
+
+	Context("when the proxy url has a user and password", func() {
+		It("authenticates with them", func() {
+			proxyURL := &url.URL{Scheme: "http", Host: listener.Addr().String(), User: url.UserPassword("some-user", "some-password")}
+			conn, err := bosh.DialThroughProxy(proxyURL, "10.0.0.5:22")
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			var request *http.Request
+			Eventually(requests).Should(Receive(&request))
+			Expect(request.Header.Get("Proxy-Authorization")).To(Equal("Basic c29tZS11c2VyOnNvbWUtcGFzc3dvcmQ="))
+		})
+	})
+
+	Context("when the proxy refuses the tunnel", func() {
+		BeforeEach(func() {
+			status = "403 Forbidden"
+		})
+
+		It("returns an error", func() {
+			_, err := bosh.DialThroughProxy(&url.URL{Scheme: "http", Host: listener.Addr().String()}, "10.0.0.5:22")
+			Expect(err).To(MatchError(ContainSubstring("refused to connect to 10.0.0.5:22: 403 Forbidden")))
+		})
+	})
+
+	Context("when the proxy cannot be reached", func() {
+		It("returns an error", func() {
+			listener.Close()
+
+			_, err := bosh.DialThroughProxy(&url.URL{Scheme: "http", Host: listener.Addr().String()}, "10.0.0.5:22")
+			Expect(err).To(MatchError(ContainSubstring("connect to proxy")))
+		})
+	})
+})
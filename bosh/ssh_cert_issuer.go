@@ -0,0 +1,103 @@
+package bosh
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"golang.org/x/crypto/ssh"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var timeNow = time.Now
+
+type SSHCertIssuer struct {
+	stateStore stateStore
+	fReader    fileio.FileReader
+}
+
+func NewSSHCertIssuer(stateStore stateStore, fReader fileio.FileReader) SSHCertIssuer {
+	return SSHCertIssuer{
+		stateStore: stateStore,
+		fReader:    fReader,
+	}
+}
+
+// Issue signs publicKey with the SSH certificate authority of the deployment,
+// generated by create-env into its vars store, for the jumpbox user. The
+// certificate is valid for ttl, and is returned in authorized_keys format.
+func (s SSHCertIssuer) Issue(deployment string, publicKey []byte, ttl time.Duration) (string, error) {
+	var p struct {
+		SSHCA struct {
+			PrivateKey string `yaml:"private_key"`
+		} `yaml:"ssh_ca"`
+	}
+
+	varsDir, err := s.stateStore.GetVarsDir()
+	if err != nil {
+		return "", fmt.Errorf("Get vars directory: %s", err)
+	}
+
+	varsStore, err := s.fReader.ReadFile(filepath.Join(varsDir, fmt.Sprintf("%s-vars-store.yml", deployment)))
+	if err != nil {
+		return "", fmt.Errorf("Read %s vars file: %s", deployment, err)
+	}
+
+	err = yaml.Unmarshal(varsStore, &p)
+	if err != nil {
+		return "", err
+	}
+
+	if p.SSHCA.PrivateKey == "" {
+		return "", fmt.Errorf("The %s vars store does not contain an SSH certificate authority. Run bbl up to create it.", deployment)
+	}
+
+	authority, err := ssh.ParsePrivateKey([]byte(p.SSHCA.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("Parse certificate authority key: %s", err)
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("Parse public key: %s", err)
+	}
+
+	if _, ok := key.(*ssh.Certificate); ok {
+		return "", errors.New("Parse public key: expected a public key, not a certificate")
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).SetUint64(1<<63))
+	if err != nil {
+		return "", err //not tested
+	}
+
+	issuedAt := timeNow()
+	cert := &ssh.Certificate{
+		Key:             key,
+		Serial:          serial.Uint64(),
+		CertType:        ssh.UserCert,
+		KeyId:           fmt.Sprintf("bbl-%s-%d", deployment, issuedAt.Unix()),
+		ValidPrincipals: []string{"jumpbox"},
+		// Allow for clock skew between the operator and the VM.
+		ValidAfter:  uint64(issuedAt.Add(-time.Minute).Unix()),
+		ValidBefore: uint64(issuedAt.Add(ttl).Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-pty":             "",
+				"permit-port-forwarding": "",
+			},
+		},
+	}
+
+	err = cert.SignCert(rand.Reader, authority)
+	if err != nil {
+		return "", fmt.Errorf("Sign certificate: %s", err) //not tested
+	}
+
+	return string(ssh.MarshalAuthorizedKey(cert)), nil
+}
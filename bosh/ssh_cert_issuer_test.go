@@ -0,0 +1,113 @@
+package bosh_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSHCertIssuer", func() {
+	Describe("Issue", func() {
+		var (
+			sshCertIssuer bosh.SSHCertIssuer
+			stateStore    *fakes.StateStore
+			fileIO        *fakes.FileIO
+
+			authority ssh.Signer
+			publicKey []byte
+			issuedAt  time.Time
+		)
+
+		BeforeEach(func() {
+			stateStore = &fakes.StateStore{}
+			stateStore.GetVarsDirCall.Returns.Directory = "some-vars-dir"
+
+			caKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+			caPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(caKey)})
+			authority, err = ssh.NewSignerFromKey(caKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			fileIO = &fakes.FileIO{}
+			fileIO.ReadFileCall.Returns.Contents = []byte(fmt.Sprintf("ssh_ca:\n  private_key: |\n    %s", strings.Replace(string(caPEM), "\n", "\n    ", -1)))
+
+			userKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+			userPublicKey, err := ssh.NewPublicKey(&userKey.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+			publicKey = ssh.MarshalAuthorizedKey(userPublicKey)
+
+			issuedAt = time.Now()
+			bosh.SetTimeNow(func() time.Time { return issuedAt })
+
+			sshCertIssuer = bosh.NewSSHCertIssuer(stateStore, fileIO)
+		})
+
+		AfterEach(func() {
+			bosh.ResetTimeNow()
+		})
+
+		It("signs a certificate for the jumpbox user with the certificate authority of the deployment", func() {
+			certificate, err := sshCertIssuer.Issue("jumpbox", publicKey, 8*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.ReadFileCall.Receives.Filename).To(Equal(filepath.Join("some-vars-dir", "jumpbox-vars-store.yml")))
+
+			key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(certificate))
+			Expect(err).NotTo(HaveOccurred())
+			cert, ok := key.(*ssh.Certificate)
+			Expect(ok).To(BeTrue())
+
+			Expect(cert.CertType).To(Equal(uint32(ssh.UserCert)))
+			Expect(cert.ValidPrincipals).To(Equal([]string{"jumpbox"}))
+			Expect(cert.ValidBefore).To(Equal(uint64(issuedAt.Add(8 * time.Hour).Unix())))
+			Expect(cert.Permissions.Extensions).To(HaveKey("permit-pty"))
+			Expect(cert.SignatureKey.Marshal()).To(Equal(authority.PublicKey().Marshal()))
+
+			checker := ssh.CertChecker{
+				IsUserAuthority: func(auth ssh.PublicKey) bool {
+					return string(auth.Marshal()) == string(authority.PublicKey().Marshal())
+				},
+			}
+			Expect(checker.CheckCert("jumpbox", cert)).To(Succeed())
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the vars directory cannot be found", func() {
+				stateStore.GetVarsDirCall.Returns.Error = errors.New("fig")
+				_, err := sshCertIssuer.Issue("jumpbox", publicKey, time.Hour)
+				Expect(err).To(MatchError("Get vars directory: fig"))
+			})
+
+			It("returns an error when the vars store cannot be read", func() {
+				fileIO.ReadFileCall.Returns.Error = errors.New("guava")
+				_, err := sshCertIssuer.Issue("jumpbox", publicKey, time.Hour)
+				Expect(err).To(MatchError("Read jumpbox vars file: guava"))
+			})
+
+			It("returns an error when the vars store has no certificate authority", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("jumpbox_ssh:\n  private_key: some-key\n")
+				_, err := sshCertIssuer.Issue("director", publicKey, time.Hour)
+				Expect(err).To(MatchError("The director vars store does not contain an SSH certificate authority. Run bbl up to create it."))
+			})
+
+			It("returns an error when the public key cannot be parsed", func() {
+				_, err := sshCertIssuer.Issue("jumpbox", []byte("not a key"), time.Hour)
+				Expect(err).To(MatchError(ContainSubstring("Parse public key: ")))
+			})
+		})
+	})
+})
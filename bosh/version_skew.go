@@ -0,0 +1,108 @@
+package bosh
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// stemcellConstraint is the oldest stemcell of some operating systems whose
+// agent the directors from a BOSH release on still talk to.
+type stemcellConstraint struct {
+	directorMajor    int
+	operatingSystems []string
+	stemcellMajor    int
+	reason           string
+}
+
+var stemcellConstraints = []stemcellConstraint{
+	{
+		directorMajor:    262,
+		operatingSystems: []string{"ubuntu-trusty", "centos-7"},
+		stemcellMajor:    3421,
+		reason:           "the director talks to agents over NATS with TLS, which stemcells older than 3421 do not support",
+	},
+}
+
+// SkewedDeployment is a deployment whose stemcell a director cannot manage
+// once it is upgraded.
+type SkewedDeployment struct {
+	Name            string `json:"name"`
+	Stemcell        string `json:"stemcell"`
+	StemcellVersion string `json:"stemcellVersion"`
+	Reason          string `json:"reason"`
+}
+
+func (d SkewedDeployment) String() string {
+	return fmt.Sprintf("The deployment %s uses the stemcell %s/%s, which BOSH %s", d.Name, d.Stemcell, d.StemcellVersion, d.Reason)
+}
+
+type deploymentVersions struct {
+	Name      string `json:"name"`
+	Stemcells []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"stemcells"`
+}
+
+// StemcellSkew lists the deployments of the director whose stemcells are too
+// old for a director of directorVersion, so that they are redeployed with a
+// newer stemcell before the director is upgraded.
+func StemcellSkew(client Client, directorVersion string) ([]SkewedDeployment, error) {
+	directorMajor, ok := majorVersion(directorVersion)
+	if !ok {
+		return []SkewedDeployment{}, nil
+	}
+
+	var deployments []deploymentVersions
+	err := curlJSON(client, "/deployments", &deployments)
+	if err != nil {
+		return nil, fmt.Errorf("List deployments: %s", err)
+	}
+
+	skewed := []SkewedDeployment{}
+	for _, deployment := range deployments {
+		for _, stemcell := range deployment.Stemcells {
+			for _, constraint := range stemcellConstraints {
+				if !constraint.rejects(directorMajor, stemcell.Name, stemcell.Version) {
+					continue
+				}
+				skewed = append(skewed, SkewedDeployment{
+					Name:            deployment.Name,
+					Stemcell:        stemcell.Name,
+					StemcellVersion: stemcell.Version,
+					Reason:          fmt.Sprintf("%s does not support: %s.", strings.TrimSpace(directorVersion), constraint.reason),
+				})
+				break
+			}
+		}
+	}
+
+	return skewed, nil
+}
+
+func (c stemcellConstraint) rejects(directorMajor int, stemcell, version string) bool {
+	if directorMajor < c.directorMajor {
+		return false
+	}
+
+	applies := false
+	for _, os := range c.operatingSystems {
+		if strings.Contains(stemcell, os) {
+			applies = true
+		}
+	}
+	if !applies {
+		return false
+	}
+
+	stemcellMajor, ok := majorVersion(version)
+	return ok && stemcellMajor < c.stemcellMajor
+}
+
+// majorVersion reads the major version of a release or stemcell, such as 264
+// of "264.7.0 (00000000)".
+func majorVersion(version string) (int, bool) {
+	major, err := strconv.Atoi(strings.SplitN(strings.TrimSpace(version), ".", 2)[0])
+	return major, err == nil
+}
@@ -0,0 +1,59 @@
+package bosh_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StemcellSkew", func() {
+	var client *fakes.BOSHClient
+
+	BeforeEach(func() {
+		client = &fakes.BOSHClient{}
+		client.CurlCall.Returns.Status = 200
+		client.CurlCall.Returns.Body = []byte(`[
+			{"name": "cf", "stemcells": [{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3468.21"}]},
+			{"name": "old", "stemcells": [{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3363.20"}]},
+			{"name": "windows", "stemcells": [{"name": "bosh-aws-xen-hvm-windows2012R2-go_agent", "version": "1200.14"}]}
+		]`)
+	})
+
+	It("lists the deployments whose stemcells are too old for the director", func() {
+		skewed, err := bosh.StemcellSkew(client, "264.7.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.CurlCall.Receives.Path).To(Equal("/deployments"))
+
+		Expect(skewed).To(Equal([]bosh.SkewedDeployment{{
+			Name:            "old",
+			Stemcell:        "bosh-aws-xen-hvm-ubuntu-trusty-go_agent",
+			StemcellVersion: "3363.20",
+			Reason:          "264.7.0 does not support: the director talks to agents over NATS with TLS, which stemcells older than 3421 do not support.",
+		}}))
+		Expect(skewed[0].String()).To(Equal("The deployment old uses the stemcell bosh-aws-xen-hvm-ubuntu-trusty-go_agent/3363.20, which BOSH 264.7.0 does not support: the director talks to agents over NATS with TLS, which stemcells older than 3421 do not support."))
+	})
+
+	It("does not apply the constraints of newer directors", func() {
+		skewed, err := bosh.StemcellSkew(client, "261.2.0 (00000000)")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(skewed).To(BeEmpty())
+	})
+
+	It("does not check against a director version that does not parse", func() {
+		skewed, err := bosh.StemcellSkew(client, "latest")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(skewed).To(BeEmpty())
+		Expect(client.CurlCall.CallCount).To(Equal(0))
+	})
+
+	It("returns an error when the deployments cannot be listed", func() {
+		client.CurlCall.Returns.Error = errors.New("connection refused")
+
+		_, err := bosh.StemcellSkew(client, "264.7.0")
+		Expect(err).To(MatchError("List deployments: connection refused"))
+	})
+})
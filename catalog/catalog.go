@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+// Catalog looks up the user-facing messages of bbl in one language. A message
+// that has no translation yet is printed in English.
+type Catalog struct {
+	language string
+}
+
+// Error is a message from the catalog returned as an error. Code is the id of
+// the message, which stays the same in every language, so that scripts can
+// match on it.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// New returns the catalog of a language, such as "ja", or of the language of
+// a locale, such as "ja_JP.UTF-8". An empty language is English.
+func New(language string) (Catalog, error) {
+	if language == "" {
+		return Catalog{language: DefaultLanguage}, nil
+	}
+
+	language = strings.ToLower(language)
+	language = strings.SplitN(language, ".", 2)[0]
+	language = strings.SplitN(strings.Replace(language, "-", "_", -1), "_", 2)[0]
+
+	if _, ok := languages[language]; !ok {
+		return Catalog{}, fmt.Errorf("bbl does not speak %q. Languages: %s.", language, strings.Join(Languages(), ", "))
+	}
+
+	return Catalog{language: language}, nil
+}
+
+// Languages returns the languages that the catalog has messages in.
+func Languages() []string {
+	names := []string{}
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c Catalog) Language() string {
+	if c.language == "" {
+		return DefaultLanguage
+	}
+	return c.language
+}
+
+// Message formats the message with the given id.
+func (c Catalog) Message(id string, a ...interface{}) string {
+	format, ok := languages[c.Language()][id]
+	if !ok {
+		format, ok = languages[DefaultLanguage][id]
+	}
+	if !ok {
+		return id
+	}
+	return fmt.Sprintf(format, a...)
+}
+
+// Error formats the message with the given id as an error that carries the id
+// as its code.
+func (c Catalog) Error(id string, a ...interface{}) error {
+	return Error{
+		Code:    id,
+		Message: c.Message(id, a...),
+	}
+}
+
+// Code returns the code of an error from the catalog, also when it is marked
+// with a kind, and an empty string for any other error.
+func Code(err error) string {
+	var e Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package catalog_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/catalog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Catalog", func() {
+	Describe("New", func() {
+		DescribeTable("selects the language of a language or locale",
+			func(language, expected string) {
+				c, err := catalog.New(language)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.Language()).To(Equal(expected))
+			},
+			Entry("no language", "", "en"),
+			Entry("a language", "ja", "ja"),
+			Entry("a locale", "ja_JP.UTF-8", "ja"),
+			Entry("a language tag", "EN-us", "en"),
+		)
+
+		It("returns an error for a language without messages", func() {
+			_, err := catalog.New("xx")
+			Expect(err).To(MatchError(`bbl does not speak "xx". Languages: en, ja.`))
+		})
+	})
+
+	Describe("Message", func() {
+		It("formats the message in the language of the catalog", func() {
+			c, err := catalog.New("ja")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Message(catalog.UnknownCommand, "foo")).To(Equal("不明なコマンドです: foo"))
+		})
+
+		It("uses English for the zero catalog", func() {
+			Expect(catalog.Catalog{}.Message(catalog.UnknownCommand, "foo")).To(Equal("unknown command: foo"))
+		})
+
+		It("returns the id of an unknown message", func() {
+			Expect(catalog.Catalog{}.Message("some-unknown-id")).To(Equal("some-unknown-id"))
+		})
+	})
+
+	Describe("Error", func() {
+		It("returns the translated message with the id as its code", func() {
+			c, err := catalog.New("ja")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = c.Error(catalog.UnknownCommand, "foo")
+			Expect(err).To(MatchError("不明なコマンドです: foo"))
+			Expect(catalog.Code(err)).To(Equal("unknown-command"))
+		})
+
+		It("has no code for other errors", func() {
+			Expect(catalog.Code(errors.New("some error"))).To(BeEmpty())
+		})
+	})
+
+	It("has every English message in every language", func() {
+		for _, language := range catalog.Languages() {
+			c, err := catalog.New(language)
+			Expect(err).NotTo(HaveOccurred())
+			for _, id := range catalog.MessageIDs() {
+				Expect(c.Translated(id)).To(BeTrue(), "%s has no %s message", language, id)
+			}
+		}
+	})
+})
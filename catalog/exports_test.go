@@ -0,0 +1,14 @@
+package catalog
+
+func MessageIDs() []string {
+	ids := []string{}
+	for id := range languages[DefaultLanguage] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (c Catalog) Translated(id string) bool {
+	_, ok := languages[c.Language()][id]
+	return ok
+}
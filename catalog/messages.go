@@ -0,0 +1,40 @@
+package catalog
+
+// Message ids. They are the codes of the errors that bbl prints, so they must
+// not change once released.
+const (
+	UnknownCommand     = "unknown-command"
+	MutationInProgress = "mutation-in-progress"
+	NoWaitReadOnly     = "no-wait-read-only"
+	NoWaitLocked       = "no-wait-locked"
+	NoWaitConfirm      = "no-wait-confirm"
+	NoWaitStarted      = "no-wait-started"
+	ErrorWithCode      = "error-with-code"
+	Interrupted        = "interrupted"
+	TimedOut           = "timed-out"
+)
+
+var languages = map[string]map[string]string{
+	"en": {
+		UnknownCommand:     "unknown command: %s",
+		MutationInProgress: "Another bbl command is modifying this environment (mutation in progress). Showing the last saved state.",
+		NoWaitReadOnly:     "--no-wait only applies to commands that change the environment, not to %s.",
+		NoWaitLocked:       "Another bbl command is modifying this environment. Run bbl status to see the operations that are running.",
+		NoWaitConfirm:      "--no-wait runs bbl %s in the background, where it cannot ask for confirmation. Pass --no-confirm as well.",
+		NoWaitStarted:      "bbl %s is running in the background as operation %s. Run bbl wait %s to wait for it to finish.",
+		ErrorWithCode:      "%s (error code: %s)",
+		Interrupted:        "bbl %s was interrupted: %s. Run it again to resume it.",
+		TimedOut:           "bbl %s did not finish within --timeout %s: %s. Run it again to resume it.",
+	},
+	"ja": {
+		UnknownCommand:     "不明なコマンドです: %s",
+		MutationInProgress: "別の bbl コマンドがこの環境を変更中です。最後に保存された状態を表示します。",
+		NoWaitReadOnly:     "--no-wait は環境を変更するコマンドにのみ使えます。%s には使えません。",
+		NoWaitLocked:       "別の bbl コマンドがこの環境を変更中です。実行中の操作は bbl status で確認できます。",
+		NoWaitConfirm:      "--no-wait は bbl %s をバックグラウンドで実行するため、確認を求めることができません。--no-confirm も指定してください。",
+		NoWaitStarted:      "bbl %s を操作 %s としてバックグラウンドで実行しています。完了を待つには bbl wait %s を実行してください。",
+		ErrorWithCode:      "%s (エラーコード: %s)",
+		Interrupted:        "bbl %s は中断されました: %s。もう一度実行すると再開します。",
+		TimedOut:           "bbl %s は --timeout %s 以内に完了しませんでした: %s。もう一度実行すると再開します。",
+	},
+}
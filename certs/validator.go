@@ -1,7 +1,9 @@
 package certs
 
 import (
+	"bytes"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -10,6 +12,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/pkcs12"
 
@@ -22,6 +25,8 @@ type CertData struct {
 	Chain []byte
 }
 
+var timeNow = time.Now
+
 type Validator struct{}
 
 func NewValidator() Validator {
@@ -119,6 +124,10 @@ func (v Validator) Validate(cert, key, chain []byte) error {
 		}
 	}
 
+	if certificate != nil && timeNow().After(certificate.NotAfter) {
+		validateErrors.Add(fmt.Errorf("certificate expired on %s", certificate.NotAfter.Format("2006-01-02")))
+	}
+
 	if privateKey != nil && certificate != nil {
 		if err := validateCertAndKey(certificate, privateKey); err != nil {
 			validateErrors.Add(err)
@@ -128,6 +137,8 @@ func (v Validator) Validate(cert, key, chain []byte) error {
 	if certPool != nil && certificate != nil {
 		if err := validateCertAndChain(certificate, certPool); err != nil {
 			validateErrors.Add(err)
+		} else if err := validateChainOrder(certificate, chain); err != nil {
+			validateErrors.Add(err)
 		}
 	}
 
@@ -152,6 +163,23 @@ func (v Validator) ReadAndValidatePKCS12(certPath, passwordPath string) (CertDat
 	return certData, nil
 }
 
+// Fingerprint returns the SHA-256 fingerprint of a PEM encoded certificate,
+// in the format of openssl x509 -fingerprint -sha256.
+func Fingerprint(cert []byte) (string, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return "", errors.New("certificate is not PEM encoded")
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	hexBytes := make([]string, len(sum))
+	for i, b := range sum {
+		hexBytes[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hexBytes, ":"), nil
+}
+
 func (v Validator) ReadPKCS12(certPath, passwordPath string) (CertData, error) {
 	validateErrors := multierror.NewMultiError("")
 
@@ -238,7 +266,7 @@ func validateCertAndKey(certificate *x509.Certificate, privateKey *rsa.PrivateKe
 }
 
 func validateCertAndChain(certificate *x509.Certificate, certPool *x509.CertPool) error {
-	opts := x509.VerifyOptions{Roots: certPool}
+	opts := x509.VerifyOptions{Roots: certPool, CurrentTime: timeNow()}
 
 	if _, err := certificate.Verify(opts); err != nil {
 		return fmt.Errorf("certificate and chain mismatch: %s", err.Error())
@@ -247,6 +275,26 @@ func validateCertAndChain(certificate *x509.Certificate, certPool *x509.CertPool
 	return nil
 }
 
+// validateChainOrder checks that each certificate of the chain issued the
+// one before it, starting from the certificate. Load balancers send the chain
+// to clients in the order it is uploaded in.
+func validateChainOrder(certificate *x509.Certificate, chainData []byte) error {
+	previous := certificate
+	for block, rest := pem.Decode(chainData); block != nil; block, rest = pem.Decode(rest) {
+		next, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse chain: %s", err)
+		}
+
+		if !bytes.Equal(previous.RawIssuer, next.RawSubject) {
+			return fmt.Errorf("chain is out of order: %q is followed by %q instead of its issuer %q", previous.Subject.CommonName, next.Subject.CommonName, previous.Issuer.CommonName)
+		}
+		previous = next
+	}
+
+	return nil
+}
+
 func parseCertificate(certificateData []byte, loadKeyPairError error) (*x509.Certificate, error) {
 	pemCertData, _ := pem.Decode(certificateData)
 	cert, err := x509.ParseCertificate(pemCertData.Bytes)
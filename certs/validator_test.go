@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/certs"
 	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
@@ -28,6 +29,11 @@ var _ = Describe("CertificateValidator", func() {
 	)
 
 	BeforeEach(func() {
+		// The fixtures were valid in July 2017.
+		certs.SetTimeNow(func() time.Time {
+			return time.Date(2017, time.July, 1, 0, 0, 0, 0, time.UTC)
+		})
+
 		var err error
 		certificateValidator = certs.NewValidator()
 		chainFilePath, err = testhelpers.WriteContentsToTempFile(testhelpers.BBL_CHAIN)
@@ -57,6 +63,23 @@ var _ = Describe("CertificateValidator", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	AfterEach(func() {
+		certs.ResetTimeNow()
+	})
+
+	Describe("Fingerprint", func() {
+		It("returns the sha256 fingerprint of the certificate", func() {
+			fingerprint, err := certs.Fingerprint([]byte(testhelpers.BBL_CERT))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fingerprint).To(Equal("47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A"))
+		})
+
+		It("returns an error when the certificate is not PEM encoded", func() {
+			_, err := certs.Fingerprint([]byte("not a cert"))
+			Expect(err).To(MatchError("certificate is not PEM encoded"))
+		})
+	})
+
 	Describe("ReadPKCS12", func() {
 		Context("when cert and password files exist and can be read", func() {
 			It("returns cert and password data", func() {
@@ -353,6 +376,20 @@ var _ = Describe("CertificateValidator", func() {
 				})
 			})
 
+			Context("when the cert has expired", func() {
+				It("returns an error", func() {
+					certs.SetTimeNow(func() time.Time {
+						return time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+					})
+
+					err := certificateValidator.Validate(realCert, realKey, []byte{})
+
+					expectedErr := multierror.NewMultiError("")
+					expectedErr.Add(errors.New("certificate expired on 2018-05-26"))
+					Expect(err).To(Equal(expectedErr))
+				})
+			})
+
 			Context("when the cert is not valid", func() {
 				It("returns an error", func() {
 					err := certificateValidator.Validate(invalidCert, realKey, []byte{})
@@ -385,6 +422,16 @@ var _ = Describe("CertificateValidator", func() {
 					})
 				})
 
+				Context("if the chain is out of order", func() {
+					It("returns an error", func() {
+						err := certificateValidator.Validate(realCert, realKey, append(append(otherChain, '\n'), realChain...))
+
+						expectedErr := multierror.NewMultiError("")
+						expectedErr.Add(errors.New(`chain is out of order: "bbl-intermediate" is followed by "consulCA" instead of its issuer "bbl-ca"`))
+						Expect(err).To(Equal(expectedErr))
+					})
+				})
+
 				Context("if the chain is not PEM encoded", func() {
 					It("returns an error", func() {
 						err := certificateValidator.Validate(realCert, realKey, fakeChain)
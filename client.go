@@ -0,0 +1,409 @@
+// Package bbl runs bbl up, bbl destroy and the load balancers of bbl plan
+// from Go, for tools that embed bbl rather than run its binary and read its
+// output. The client only paves AWS environments.
+package bbl
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/config"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	awscloudconfig "github.com/cloudfoundry/bosh-bootloader/cloudconfig/aws"
+	awsterraform "github.com/cloudfoundry/bosh-bootloader/terraform/aws"
+)
+
+// Config is the environment a Client works on.
+type Config struct {
+	// StateDir is the state directory of the environment, which is created
+	// when it does not exist.
+	StateDir string
+
+	// AWS holds the credentials and the region. The region of an existing
+	// environment cannot be changed.
+	AWS storage.AWS
+
+	// StatePassphrase unlocks a state that bbl state encrypt encrypted with
+	// a passphrase.
+	StatePassphrase string
+
+	// Stdout and Stderr receive what bbl prints, terraform and bosh
+	// create-env included. They are discarded when nil.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Debug prints the output of terraform and bosh create-env as they run.
+	Debug bool
+
+	// Version is the bbl version that is recorded in the state.
+	Version string
+
+	// OverrideAccountCheck runs the commands with credentials of another
+	// AWS account than the one the environment was created in, as
+	// --override-account-check does.
+	OverrideAccountCheck bool
+}
+
+// UpOptions are the options of Client.Up, which bbl up takes as flags.
+type UpOptions struct {
+	// Name is the env id of a new environment. One is generated when it is
+	// empty.
+	Name string
+
+	// NoDirector only creates the infrastructure.
+	NoDirector bool
+
+	// LB plans load balancers along with the environment.
+	LB *LBOptions
+
+	SkipQuotaCheck bool
+}
+
+// LBOptions are the load balancers of an environment, which bbl plan takes
+// as --lb flags. The certificate, key and chain are paths to PEM files.
+type LBOptions struct {
+	Type       string
+	CertPath   string
+	KeyPath    string
+	ChainPath  string
+	CertARN    string
+	Domain     string
+	AppsDomain string
+	Internal   bool
+	ELBv2      bool
+	// ACMCertificate requests the certificate of Domain from AWS
+	// Certificate Manager, in place of CertPath, KeyPath or CertARN.
+	ACMCertificate bool
+}
+
+// DestroyOptions are the options of Client.Destroy, which bbl destroy takes
+// as flags.
+type DestroyOptions struct {
+	SkipIfMissing bool
+	Force         bool
+}
+
+// Environment is an environment that Client.Up brought up.
+type Environment struct {
+	EnvID            string
+	Region           string
+	DirectorName     string
+	DirectorAddress  string
+	DirectorUsername string
+	DirectorPassword string
+	DirectorCACert   string
+}
+
+// Client runs bbl commands on the environment of its config. It holds the
+// state lock of the environment while a command runs, as bbl does.
+type Client struct {
+	config Config
+}
+
+func NewClient(config Config) (Client, error) {
+	if config.StateDir == "" {
+		return Client{}, errors.New("The state directory of the environment is missing.")
+	}
+	if config.Stdout == nil {
+		config.Stdout = ioutil.Discard
+	}
+	if config.Stderr == nil {
+		config.Stderr = ioutil.Discard
+	}
+	if config.Version == "" {
+		config.Version = "dev"
+	}
+
+	err := os.MkdirAll(config.StateDir, os.ModePerm)
+	if err != nil {
+		return Client{}, fmt.Errorf("Create state directory: %s", err)
+	}
+
+	return Client{config: config}, nil
+}
+
+// Up brings up the environment, or updates it with the options, and returns
+// its director.
+func (c Client) Up(ctx context.Context, options UpOptions) (Environment, error) {
+	var environment Environment
+	err := c.run(ctx, func(cmds clientCommands, state storage.State) error {
+		planConfig := commands.PlanConfig{Name: options.Name, NoDirector: options.NoDirector}
+		if options.LB != nil {
+			lb, err := cmds.lbArgsHandler.GetLBState(state.IAAS, options.LB.args())
+			if err != nil {
+				return err
+			}
+			planConfig.LB = lb
+		}
+
+		err := cmds.plan.Check(planConfig, state)
+		if err != nil {
+			return err
+		}
+
+		// The load balancers of an existing environment are only planned by
+		// bbl plan.
+		if options.LB != nil && cmds.plan.IsInitialized(state) {
+			state, err = cmds.plan.InitializePlan(planConfig, state)
+			if err != nil {
+				return err
+			}
+		}
+
+		state, err = cmds.up.Run(ctx, commands.UpOptions{AutoApprove: true, SkipQuotaCheck: options.SkipQuotaCheck}, planConfig, state)
+		if err != nil {
+			return err
+		}
+
+		environment = Environment{
+			EnvID:            state.EnvID,
+			Region:           state.AWS.Region,
+			DirectorName:     state.BOSH.DirectorName,
+			DirectorAddress:  state.BOSH.DirectorAddress,
+			DirectorUsername: state.BOSH.DirectorUsername,
+			DirectorPassword: state.BOSH.DirectorPassword,
+			DirectorCACert:   state.BOSH.DirectorSSLCA,
+		}
+		return nil
+	})
+	return environment, err
+}
+
+// Destroy deletes the director, the jumpbox and the infrastructure of the
+// environment, and clears its state.
+func (c Client) Destroy(ctx context.Context, options DestroyOptions) error {
+	return c.run(ctx, func(cmds clientCommands, state storage.State) error {
+		destroyOptions := commands.DestroyOptions{SkipIfMissing: options.SkipIfMissing, Force: options.Force}
+
+		err := cmds.destroy.Check(destroyOptions, state)
+		if err != nil {
+			return err
+		}
+
+		return cmds.destroy.Run(ctx, destroyOptions, state)
+	})
+}
+
+// UpdateLBs plans the load balancers of lb for the environment, applies
+// them and returns them.
+func (c Client) UpdateLBs(ctx context.Context, lb LBOptions) (commands.AWSLoadBalancers, error) {
+	var lbs commands.AWSLoadBalancers
+	err := c.run(ctx, func(cmds clientCommands, state storage.State) error {
+		if !cmds.plan.IsInitialized(state) || state.EnvID == "" {
+			return errors.New("The environment does not exist yet. Bring it up with its load balancers instead.")
+		}
+
+		lbState, err := cmds.lbArgsHandler.GetLBState(state.IAAS, lb.args())
+		if err != nil {
+			return err
+		}
+		planConfig := commands.PlanConfig{LB: lbState}
+
+		err = cmds.plan.Check(planConfig, state)
+		if err != nil {
+			return err
+		}
+
+		state, err = cmds.plan.InitializePlan(planConfig, state)
+		if err != nil {
+			return err
+		}
+
+		state, err = cmds.up.Run(ctx, commands.UpOptions{AutoApprove: true}, commands.PlanConfig{}, state)
+		if err != nil {
+			return err
+		}
+
+		lbs, err = cmds.lbs.LoadBalancers(state)
+		return err
+	})
+	return lbs, err
+}
+
+func (o LBOptions) args() commands.LBArgs {
+	return commands.LBArgs{
+		LBType:         o.Type,
+		CertPath:       o.CertPath,
+		KeyPath:        o.KeyPath,
+		ChainPath:      o.ChainPath,
+		CertARN:        o.CertARN,
+		Domain:         o.Domain,
+		AppsDomain:     o.AppsDomain,
+		Internal:       o.Internal,
+		ELBv2:          o.ELBv2,
+		ACMCertificate: o.ACMCertificate,
+	}
+}
+
+type clientCommands struct {
+	plan          commands.Plan
+	up            commands.Up
+	destroy       commands.Destroy
+	lbs           commands.AWSLBs
+	lbArgsHandler commands.LBArgsHandler
+}
+
+// run loads the state of the environment and runs command on it while
+// holding the state lock. The state is backed up first, for bbl state
+// restore.
+func (c Client) run(ctx context.Context, command func(clientCommands, storage.State) error) error {
+	stateLock := storage.NewStateLock(c.config.StateDir)
+	err := stateLock.Lock()
+	if err != nil {
+		return err
+	}
+	defer stateLock.Unlock()
+
+	logger := application.NewLogger(c.config.Stdout, strings.NewReader(""))
+	logger.NoConfirm()
+	stderrLogger := application.NewLogger(c.config.Stderr, strings.NewReader(""))
+	stderrLogger.NoConfirm()
+
+	afs := &afero.Afero{Fs: afero.NewOsFs()}
+	stateSerializer, err := storage.NewStateSerializer("", c.config.StateDir)
+	if err != nil {
+		return err
+	}
+	stateCipher := storage.NewStateCipher(c.config.StatePassphrase)
+	stateStore := storage.NewStore(c.config.StateDir, afs, stateSerializer, stateCipher)
+
+	state, err := c.loadState(stateStore, stateCipher, afs, stderrLogger)
+	if err != nil {
+		return err
+	}
+
+	err = storage.NewStateBackups(c.config.StateDir).Backup()
+	if err != nil {
+		return err
+	}
+
+	cmds, err := c.commands(ctx, state, stateStore, afs, logger, stderrLogger)
+	if err != nil {
+		return err
+	}
+
+	return command(cmds, state)
+}
+
+// loadState reads and migrates the state of the environment, as bbl does
+// before each command, sets the credentials of the config on it and checks
+// that they belong to the account of the environment.
+func (c Client) loadState(stateStore storage.Store, stateCipher *storage.StateCipher, afs *afero.Afero, logger *application.Logger) (storage.State, error) {
+	state, err := storage.NewStateBootstrap(logger, c.config.Version).GetState(c.config.StateDir)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	state, err = storage.NewMigrator(stateStore, afs).Migrate(state)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	if state.IAAS != "" && state.IAAS != "aws" {
+		return storage.State{}, fmt.Errorf("The environment is on %s. The bbl client only supports aws.", state.IAAS)
+	}
+	if c.config.AWS.Region != "" && state.AWS.Region != "" && c.config.AWS.Region != state.AWS.Region {
+		return storage.State{}, fmt.Errorf("The region cannot be changed for an existing environment. The current region is %s.", state.AWS.Region)
+	}
+
+	state.IAAS = "aws"
+	region := state.AWS.Region
+	if region == "" {
+		region = c.config.AWS.Region
+	}
+	credentials := c.config.AWS
+	credentials.Region = region
+	state.AWS.AccessKeyID = credentials.AccessKeyID
+	state.AWS.SecretAccessKey = credentials.SecretAccessKey
+	state.AWS.SessionToken = credentials.SessionToken
+	state.AWS.Profile = credentials.Profile
+	state.AWS.RoleARN = credentials.RoleARN
+	state.AWS.Endpoint = credentials.Endpoint
+	state.AWS.Endpoints = credentials.Endpoints
+	state.AWS.Region = region
+
+	credentialsResolver := aws.NewCredentialsResolver(logger, nil)
+	state.AWS, err = credentialsResolver.Resolve(state.AWS)
+	if err != nil {
+		return storage.State{}, err
+	}
+	err = config.ValidateIAAS(state)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	// Each command of the client changes the environment, so the account of
+	// the credentials is checked as bbl does before the commands that do.
+	state, err = credentialsResolver.VerifyAccount(state, c.config.OverrideAccountCheck)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	if state.Encryption != nil {
+		var kmsClient storage.KMSClient
+		if state.Encryption.Method == storage.KMSEncryption {
+			kmsClient = aws.NewKMSClient(state.Encryption.KMSCredentials(state.AWS))
+		}
+		err = stateCipher.Unlock(state.Encryption, kmsClient)
+		if err != nil {
+			return storage.State{}, err
+		}
+		state, err = stateCipher.Decrypt(state)
+		if err != nil {
+			return storage.State{}, err
+		}
+	}
+
+	return state, nil
+}
+
+// commands wires the commands with the Wiring of bbl/main.go, with
+// terraform and bosh create-env stopped once ctx is done.
+func (c Client) commands(ctx context.Context, state storage.State, stateStore storage.Store, afs *afero.Afero, logger, stderrLogger *application.Logger) (clientCommands, error) {
+	stateValidator := application.NewStateValidator(c.config.StateDir)
+	lbArgsHandler := commands.NewLBArgsHandler(certs.NewValidator())
+
+	wiring := NewWiring(ctx, WiringConfig{
+		StateDir: c.config.StateDir,
+		Stderr:   c.config.Stderr,
+		Debug:    c.config.Debug,
+		Version:  c.config.Version,
+	}, stateStore, afs, logger, stderrLogger)
+
+	awsClient := aws.NewClient(state.AWS, logger, c.config.Debug, nil)
+	availabilityZoneRetriever, err := wiring.CachingAvailabilityZoneRetriever(awsClient)
+	if err != nil {
+		return clientCommands{}, err
+	}
+	terraformManager := wiring.TerraformManager(awsterraform.NewTemplateGenerator(), awsterraform.NewInputGenerator(availabilityZoneRetriever))
+
+	wired := wiring.Commands(WiringIAAS{
+		TerraformManager:         terraformManager,
+		CloudConfigOpsGenerator:  awscloudconfig.NewOpsGenerator(terraformManager, availabilityZoneRetriever),
+		EnvIDManager:             helpers.NewEnvIDManager(helpers.NewEnvIDGenerator(rand.Reader), awsClient),
+		NetworkDeletionValidator: awsClient,
+		AccountBootstrapper:      awsClient,
+		QuotaChecker:             awsClient,
+	}, lbArgsHandler, stateValidator)
+
+	return clientCommands{
+		plan:          wired.Plan,
+		up:            wired.Up,
+		destroy:       wired.Destroy,
+		lbs:           commands.NewAWSLBs(terraformManager, logger),
+		lbArgsHandler: lbArgsHandler,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package bbl_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	bbl "github.com/cloudfoundry/bosh-bootloader"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		stateDir  string
+		stsServer *httptest.Server
+		config    bbl.Config
+	)
+
+	writeState := func(state string) {
+		err := ioutil.WriteFile(filepath.Join(stateDir, "bbl-state.json"), []byte(state), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "bbl-client")
+		Expect(err).NotTo(HaveOccurred())
+
+		stsServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::222222222222:user/some-user</Arn>
+    <Account>222222222222</Account>
+  </GetCallerIdentityResult>
+</GetCallerIdentityResponse>`)
+		}))
+
+		config = bbl.Config{
+			StateDir: stateDir,
+			AWS: storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "us-west-1",
+				Endpoints:       map[string]string{"sts": stsServer.URL},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		stsServer.Close()
+		os.RemoveAll(stateDir)
+	})
+
+	Describe("NewClient", func() {
+		It("creates the state directory", func() {
+			config.StateDir = filepath.Join(stateDir, "some-env")
+
+			_, err := bbl.NewClient(config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config.StateDir).To(BeADirectory())
+		})
+
+		It("returns an error without a state directory", func() {
+			config.StateDir = ""
+
+			_, err := bbl.NewClient(config)
+			Expect(err).To(MatchError("The state directory of the environment is missing."))
+		})
+	})
+
+	Describe("the state of the environment", func() {
+		var client bbl.Client
+
+		BeforeEach(func() {
+			var err error
+			client, err = bbl.NewClient(config)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("refuses an environment on another IAAS", func() {
+			writeState(fmt.Sprintf(`{"version": %d, "iaas": "gcp"}`, storage.STATE_SCHEMA))
+
+			_, err := client.Up(context.Background(), bbl.UpOptions{})
+			Expect(err).To(MatchError("The environment is on gcp. The bbl client only supports aws."))
+		})
+
+		It("refuses to change the region of an environment", func() {
+			writeState(fmt.Sprintf(`{"version": %d, "iaas": "aws", "aws": {"region": "us-east-1"}}`, storage.STATE_SCHEMA))
+
+			err := client.Destroy(context.Background(), bbl.DestroyOptions{})
+			Expect(err).To(MatchError("The region cannot be changed for an existing environment. The current region is us-east-1."))
+		})
+
+		It("refuses credentials of another account than the environment's", func() {
+			writeState(fmt.Sprintf(`{"version": %d, "iaas": "aws", "envID": "some-env", "aws": {"region": "us-west-1", "accountID": "111111111111"}}`, storage.STATE_SCHEMA))
+
+			_, err := client.Up(context.Background(), bbl.UpOptions{})
+			Expect(err).To(BeAssignableToTypeOf(aws.AccountMismatchError{}))
+			Expect(err).To(MatchError(ContainSubstring("environment: account 111111111111, region us-west-1")))
+			Expect(err).To(MatchError(ContainSubstring("credentials: account 222222222222, as arn:aws:iam::222222222222:user/some-user")))
+		})
+
+		It("refuses credentials of another account when updating the load balancers", func() {
+			writeState(fmt.Sprintf(`{"version": %d, "iaas": "aws", "envID": "some-env", "aws": {"region": "us-west-1", "accountID": "111111111111"}}`, storage.STATE_SCHEMA))
+
+			_, err := client.UpdateLBs(context.Background(), bbl.LBOptions{Type: "concourse"})
+			Expect(err).To(BeAssignableToTypeOf(aws.AccountMismatchError{}))
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -61,6 +62,18 @@ type networkSubnetCloudProperties struct {
 	SecurityGroups []string `yaml:"security_groups"`
 }
 
+// spot is the vm_extension that runs the VMs of a deployment on spot
+// instances, or on demand while no spot instance is to be had.
+type spot struct {
+	Name            string
+	CloudProperties spotCloudProperties `yaml:"cloud_properties"`
+}
+
+type spotCloudProperties struct {
+	SpotBidPrice         float64 `yaml:"spot_bid_price"`
+	SpotOnDemandFallback bool    `yaml:"spot_ondemand_fallback"`
+}
+
 type lb struct {
 	Name            string
 	CloudProperties lbCloudProperties `yaml:"cloud_properties"`
@@ -109,6 +122,9 @@ func (o OpsGenerator) GenerateVars(state storage.State) (string, error) {
 			"cf_tcp_lb_name",
 			"cf_tcp_lb_internal_security_group",
 		)
+		if state.LB.ELBv2 {
+			requiredOutputs = append(requiredOutputs, "cf_router_lb_target_groups", "cf_ssh_lb_target_groups")
+		}
 	}
 
 	for _, output := range requiredOutputs {
@@ -135,6 +151,12 @@ func (o OpsGenerator) GenerateVars(state storage.State) (string, error) {
 		}
 	}
 
+	// bosh variables cannot index into lists, so each SNI load balancer gets
+	// a variable of its own.
+	for i, name := range terraformOutputs.GetStringSlice("cf_router_sni_lb_names") {
+		varsYAML[fmt.Sprintf("cf_router_sni_lb_name_%d", i+1)] = name
+	}
+
 	isoSegAZSubnetIDMap := terraformOutputs.GetStringMap("iso_az_subnet_id_mapping")
 	isoSegAZSubnetCIDRMap := terraformOutputs.GetStringMap("iso_az_subnet_cidr_mapping")
 	if len(isoSegAZSubnetIDMap) > 0 && len(isoSegAZSubnetCIDRMap) > 0 {
@@ -215,7 +237,7 @@ func (o OpsGenerator) generateOps(state storage.State) ([]op, error) {
 	ops := []op{}
 	subnets := []networkSubnet{}
 
-	azs, err := o.availabilityZoneRetriever.RetrieveAvailabilityZones(state.AWS.Region)
+	azs, err := aws.AvailabilityZones(o.availabilityZoneRetriever, state.AWS)
 	if err != nil {
 		return []op{}, fmt.Errorf("Retrieve availability zones: %s", err)
 	}
@@ -245,6 +267,16 @@ func (o OpsGenerator) generateOps(state storage.State) ([]op, error) {
 		Type:    "manual",
 	}))
 
+	if state.AWS.SpotBidPrice != 0 {
+		ops = append(ops, createOp("replace", "/vm_extensions/-", spot{
+			Name: "spot",
+			CloudProperties: spotCloudProperties{
+				SpotBidPrice:         state.AWS.SpotBidPrice,
+				SpotOnDemandFallback: true,
+			},
+		}))
+	}
+
 	switch state.LB.Type {
 	case "cf":
 		lbSecurityGroups := []map[string]string{
@@ -255,16 +287,41 @@ func (o OpsGenerator) generateOps(state storage.State) ([]op, error) {
 			map[string]string{"name": "ssh-proxy-lb", "lb": "((cf_ssh_lb_name))", "group": "((cf_ssh_lb_internal_security_group))"},
 		}
 
+		// The load balancers of the SNI domains send their traffic to the
+		// routers as well.
+		routerELBs := []string{"((cf_router_lb_name))"}
+		for i := range state.AWS.SNIDomains {
+			routerELBs = append(routerELBs, fmt.Sprintf("((cf_router_sni_lb_name_%d))", i+1))
+		}
+
+		// The VMs of ELBv2 load balancers join their target groups instead.
+		elbv2TargetGroups := map[string]string{}
+		if state.LB.ELBv2 {
+			elbv2TargetGroups = map[string]string{
+				"((cf_router_lb_name))": "((cf_router_lb_target_groups))",
+				"((cf_ssh_lb_name))":    "((cf_ssh_lb_target_groups))",
+			}
+		}
+
 		for _, details := range lbSecurityGroups {
-			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
-				Name: details["name"],
-				CloudProperties: lbCloudProperties{
-					ELBs: []string{details["lb"]},
-					SecurityGroups: []string{
-						details["group"],
-						"((internal_security_group))",
-					},
+			cloudProperties := lbCloudProperties{
+				ELBs: []string{details["lb"]},
+				SecurityGroups: []string{
+					details["group"],
+					"((internal_security_group))",
 				},
+			}
+			if details["lb"] == "((cf_router_lb_name))" {
+				cloudProperties.ELBs = routerELBs
+			}
+			if targetGroups, ok := elbv2TargetGroups[details["lb"]]; ok {
+				cloudProperties.ELBs = nil
+				cloudProperties.LBTargetGroups = targetGroups
+			}
+
+			ops = append(ops, createOp("replace", "/vm_extensions/-", lb{
+				Name:            details["name"],
+				CloudProperties: cloudProperties,
 			}))
 		}
 	case "concourse":
@@ -158,6 +158,15 @@ iso_az_subnet_id_mapping:
 `))
 		})
 
+		It("gives each load balancer of the SNI domains a variable", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs.Map["cf_router_sni_lb_names"] = []interface{}{"some-env-cf-sni-0", "some-env-cf-sni-1"}
+
+			varsYAML, err := opsGenerator.GenerateVars(incomingState)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(varsYAML).To(ContainSubstring("cf_router_sni_lb_name_1: some-env-cf-sni-0\n"))
+			Expect(varsYAML).To(ContainSubstring("cf_router_sni_lb_name_2: some-env-cf-sni-1\n"))
+		})
+
 		Context("failure cases", func() {
 			Context("when the az subnet id map has a key not in the cidr map", func() {
 				BeforeEach(func() {
@@ -206,6 +215,12 @@ iso_az_subnet_id_mapping:
 				Entry("when concourse_lb_target_groups is missing", "concourse_lb_target_groups", "concourse"),
 				Entry("when concourse_lb_internal_security_group is missing", "concourse_lb_internal_security_group", "concourse"),
 			)
+
+			It("returns an error when the target groups of ELBv2 cf load balancers are missing", func() {
+				incomingState.LB = storage.LB{Type: "cf", ELBv2: true}
+				_, err := opsGenerator.GenerateVars(incomingState)
+				Expect(err).To(MatchError("missing cf_router_lb_target_groups terraform output"))
+			})
 		})
 	})
 
@@ -249,6 +264,39 @@ iso_az_subnet_id_mapping:
 			})
 		})
 
+		Context("when the cf lb has SNI domains", func() {
+			It("attaches their load balancers to the routers", func() {
+				incomingState.LB.Type = "cf"
+				incomingState.AWS.SNIDomains = []storage.SNIDomain{
+					{Domain: "apps.example.com", Certificate: "apps"},
+					{Domain: "api.example.com", Certificate: "api"},
+				}
+
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				routerELBs := "- ((cf_router_lb_name))\n      - ((cf_router_sni_lb_name_1))\n      - ((cf_router_sni_lb_name_2))\n"
+				Expect(strings.Count(opsYAML, routerELBs)).To(Equal(2))
+				Expect(opsYAML).To(ContainSubstring("elbs:\n      - ((cf_ssh_lb_name))\n      security_groups"))
+			})
+		})
+
+		Context("when the cf lbs are ELBv2", func() {
+			It("adds the routers and ssh proxies to the target groups of their load balancers", func() {
+				incomingState.LB = storage.LB{Type: "cf", ELBv2: true}
+				terraformManager.GetOutputsCall.Returns.Outputs.Map["cf_router_lb_target_groups"] = []string{"some-router-target-group"}
+				terraformManager.GetOutputsCall.Returns.Outputs.Map["cf_ssh_lb_target_groups"] = []string{"some-ssh-target-group"}
+
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strings.Count(opsYAML, "lb_target_groups: ((cf_router_lb_target_groups))")).To(Equal(2))
+				Expect(strings.Count(opsYAML, "lb_target_groups: ((cf_ssh_lb_target_groups))")).To(Equal(2))
+				Expect(opsYAML).To(ContainSubstring("elbs:\n      - ((cf_tcp_lb_name))"))
+				Expect(opsYAML).NotTo(ContainSubstring("((cf_router_lb_name))"))
+			})
+		})
+
 		Context("when there is a concourse lb", func() {
 			BeforeEach(func() {
 				baseOpsYAMLContents, err := ioutil.ReadFile(filepath.Join("fixtures", "aws-ops.yml"))
@@ -267,6 +315,40 @@ iso_az_subnet_id_mapping:
 			})
 		})
 
+		Context("when the environment bids for spot instances", func() {
+			It("adds the spot vm_extension", func() {
+				incomingState.AWS.SpotBidPrice = 0.05
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(ContainSubstring(`- type: replace
+  path: /vm_extensions/-
+  value:
+    name: spot
+    cloud_properties:
+      spot_bid_price: 0.05
+      spot_ondemand_fallback: true
+`))
+			})
+		})
+
+		Context("when availability zones are pinned", func() {
+			It("only adds those availability zones", func() {
+				incomingState.AWS.AZs = []string{"us-east-1a", "us-east-1c"}
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(ContainSubstring("((az2_name))"))
+				Expect(opsYAML).NotTo(ContainSubstring("((az3_name))"))
+			})
+
+			It("returns an error when a pinned availability zone is not in the region", func() {
+				incomingState.AWS.AZs = []string{"us-east-1z"}
+				_, err := opsGenerator.Generate(incomingState)
+				Expect(err).To(MatchError(ContainSubstring("Retrieve availability zones: Availability zone us-east-1z is not in")))
+			})
+		})
+
 		Context("when an error occurs", func() {
 			Context("when ops fails to marshal", func() {
 				It("returns an error", func() {
@@ -43,6 +43,73 @@ vm_types:
 - name: large
 - name: extra-large
 
+vm_extensions:
+- name: 1GB_ephemeral_disk
+- name: 5GB_ephemeral_disk
+- name: 10GB_ephemeral_disk
+- name: 50GB_ephemeral_disk
+- name: 100GB_ephemeral_disk
+- name: 500GB_ephemeral_disk
+- name: 1TB_ephemeral_disk
+`
+
+	// LiteCloudConfig is the cloud config of a bosh-lite director, whose
+	// warden cpi puts the containers it creates on a network of the director
+	// VM rather than on the subnets of the iaas.
+	LiteCloudConfig = `---
+azs:
+- name: z1
+- name: z2
+- name: z3
+
+compilation:
+  az: z1
+  network: default
+  reuse_compilation_vms: true
+  vm_type: default
+  workers: 5
+
+disk_types:
+- name: default
+  disk_size: 1024
+- name: 1GB
+  disk_size: 1024
+- name: 5GB
+  disk_size: 5120
+- name: 10GB
+  disk_size: 10240
+- name: 50GB
+  disk_size: 51200
+- name: 100GB
+  disk_size: 102400
+
+networks:
+- name: default
+  type: manual
+  subnets:
+  - azs: [z1, z2, z3]
+    range: 10.244.0.0/24
+    gateway: 10.244.0.1
+    dns: [8.8.8.8]
+    reserved: []
+    static: [10.244.0.2-10.244.0.127]
+  - azs: [z1, z2, z3]
+    range: 10.244.1.0/24
+    gateway: 10.244.1.1
+    dns: [8.8.8.8]
+    reserved: []
+    static: [10.244.1.2-10.244.1.127]
+
+vm_types:
+- name: default
+- name: minimal
+- name: sharedcpu
+- name: small
+- name: small-highmem
+- name: medium
+- name: large
+- name: extra-large
+
 vm_extensions:
 - name: 1GB_ephemeral_disk
 - name: 5GB_ephemeral_disk
@@ -73,6 +73,17 @@ func (m Manager) Initialize(state storage.State) error {
 		return fmt.Errorf("Get cloud config dir: %s", err)
 	}
 
+	// The ops of the iaas refer to its subnets, which the containers of a
+	// bosh-lite director are not on.
+	if state.IAAS == "aws" && state.AWS.Lite {
+		err = m.fs.WriteFile(filepath.Join(cloudConfigDir, "cloud-config.yml"), []byte(LiteCloudConfig), storage.StateMode)
+		if err != nil {
+			return err
+		}
+
+		return m.fs.WriteFile(filepath.Join(cloudConfigDir, "ops.yml"), []byte("--- []\n"), storage.StateMode)
+	}
+
 	err = m.fs.WriteFile(filepath.Join(cloudConfigDir, "cloud-config.yml"), []byte(BaseCloudConfig), storage.StateMode)
 	if err != nil {
 		return err
@@ -92,6 +92,19 @@ var _ = Describe("Manager", func() {
 			Expect(fileIO.WriteFileCall.Receives[1].Contents).To(Equal([]byte("some-ops")))
 		})
 
+		It("writes the cloud config of the containers of a bosh-lite director without the ops of the iaas", func() {
+			err := manager.Initialize(storage.State{IAAS: "aws", AWS: storage.AWS{Lite: true}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join("some-cloud-config-dir", "cloud-config.yml")))
+			Expect(string(fileIO.WriteFileCall.Receives[0].Contents)).To(Equal(cloudconfig.LiteCloudConfig))
+			Expect(string(fileIO.WriteFileCall.Receives[0].Contents)).To(ContainSubstring("range: 10.244.0.0/24"))
+
+			Expect(opsGenerator.GenerateCall.Receives.State).To(Equal(storage.State{}))
+			Expect(fileIO.WriteFileCall.Receives[1].Filename).To(Equal(filepath.Join("some-cloud-config-dir", "ops.yml")))
+			Expect(string(fileIO.WriteFileCall.Receives[1].Contents)).To(Equal("--- []\n"))
+		})
+
 		Context("failure cases", func() {
 			Context("when getting the cloud config dir fails", func() {
 				BeforeEach(func() {
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+var (
+	// annotationKey and annotationValue accept what aws accepts in the key
+	// and value of a tag, without spaces in keys.
+	annotationKey   = regexp.MustCompile(`^[A-Za-z0-9_.:/+@-]{1,128}$`)
+	annotationValue = regexp.MustCompile(`^[A-Za-z0-9_.:/=+@ -]{0,256}$`)
+)
+
+// Annotate records metadata about the environment in the state, such as
+// owner=platform-team, for inventory systems to read with bbl annotations.
+type Annotate struct {
+	stateValidator stateValidator
+	stateStore     stateStore
+	logger         logger
+}
+
+func NewAnnotate(stateValidator stateValidator, stateStore stateStore, logger logger) Annotate {
+	return Annotate{
+		stateValidator: stateValidator,
+		stateStore:     stateStore,
+		logger:         logger,
+	}
+}
+
+func (a Annotate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := a.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseAnnotations(subcommandFlags)
+	return err
+}
+
+// Execute sets each key=value annotation, and removes those given as key=.
+func (a Annotate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	annotations, err := parseAnnotations(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	state.Annotations = applyAnnotations(state.Annotations, annotations)
+
+	err = a.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	if state.IAAS == "aws" {
+		a.logger.Println("Run bbl plan and bbl up to update the tags of the resources of the environment.")
+	}
+	return nil
+}
+
+// applyAnnotations returns the existing annotations with each key=value set,
+// and those given as key= removed, or nil when none are left.
+func applyAnnotations(existing map[string]string, annotations [][2]string) map[string]string {
+	merged := map[string]string{}
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for _, annotation := range annotations {
+		if annotation[1] == "" {
+			delete(merged, annotation[0])
+			continue
+		}
+		merged[annotation[0]] = annotation[1]
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+func parseAnnotations(args []string) ([][2]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("Pass one or more annotations as key=value, or key= to remove one.")
+	}
+
+	annotations := [][2]string{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Annotation %q is not key=value.", arg)
+		}
+		key, value := parts[0], parts[1]
+		if !annotationKey.MatchString(key) {
+			return nil, fmt.Errorf("Annotation key %q must be 1 to 128 letters, digits or _.:/+@- characters.", key)
+		}
+		if key == "Name" || strings.HasPrefix(strings.ToLower(key), "aws:") {
+			return nil, fmt.Errorf("Annotation key %q is reserved for the tags that aws and bbl set.", key)
+		}
+		if !annotationValue.MatchString(value) {
+			return nil, fmt.Errorf("Annotation value %q of %s must be up to 256 letters, digits, spaces or _.:/=+@- characters.", value, key)
+		}
+		annotations = append(annotations, [2]string{key, value})
+	}
+	return annotations, nil
+}
+
+// Annotations prints the annotations of the environment, one key=value per
+// line, or as a JSON object with --json.
+type Annotations struct {
+	stateValidator stateValidator
+	output         OutputFormatter
+}
+
+func NewAnnotations(stateValidator stateValidator, output OutputFormatter) Annotations {
+	return Annotations{
+		stateValidator: stateValidator,
+		output:         output,
+	}
+}
+
+func (a Annotations) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	return a.stateValidator.Validate()
+}
+
+func (a Annotations) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	if a.output.JSON() {
+		annotations := state.Annotations
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		return a.output.PrintJSON(annotations)
+	}
+
+	keys := []string{}
+	for key := range state.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		a.output.Printf("%s=%s\n", key, state.Annotations[key])
+	}
+	return nil
+}
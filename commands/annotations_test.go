@@ -0,0 +1,170 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Annotate", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		stateStore     *fakes.StateStore
+		logger         *fakes.Logger
+		command        commands.Annotate
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		command = commands.NewAnnotate(stateValidator, stateStore, logger)
+
+		state = storage.State{
+			IAAS:        "aws",
+			EnvID:       "some-env-id",
+			Annotations: map[string]string{"owner": "some-team"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"owner=platform-team"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when the state cannot be validated", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("no state")
+
+			err := command.CheckFastFails([]string{"owner=platform-team"}, state)
+			Expect(err).To(MatchError("no state"))
+		})
+
+		It("requires an annotation", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("Pass one or more annotations as key=value, or key= to remove one."))
+		})
+
+		It("rejects an annotation without a value", func() {
+			err := command.CheckFastFails([]string{"owner"}, state)
+			Expect(err).To(MatchError(`Annotation "owner" is not key=value.`))
+		})
+
+		It("rejects keys and values that cannot be tags", func() {
+			err := command.CheckFastFails([]string{"owner name=x"}, state)
+			Expect(err).To(MatchError(`Annotation key "owner name" must be 1 to 128 letters, digits or _.:/+@- characters.`))
+
+			err = command.CheckFastFails([]string{`owner="x"`}, state)
+			Expect(err).To(MatchError(`Annotation value "\"x\"" of owner must be up to 256 letters, digits, spaces or _.:/=+@- characters.`))
+		})
+
+		It("rejects the keys of the tags that aws and bbl set", func() {
+			err := command.CheckFastFails([]string{"Name=x"}, state)
+			Expect(err).To(MatchError(`Annotation key "Name" is reserved for the tags that aws and bbl set.`))
+
+			err = command.CheckFastFails([]string{"aws:owner=x"}, state)
+			Expect(err).To(MatchError(`Annotation key "aws:owner" is reserved for the tags that aws and bbl set.`))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("sets and removes annotations in the state", func() {
+			err := command.Execute(context.Background(), []string{"cost-center=1234", "team=platform team", "owner="}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateStore.SetCall.CallCount).To(Equal(1))
+			Expect(stateStore.SetCall.Receives[0].State.Annotations).To(Equal(map[string]string{
+				"cost-center": "1234",
+				"team":        "platform team",
+			}))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Run bbl plan and bbl up to update the tags of the resources of the environment."))
+		})
+
+		It("removes the annotations from the state when none are left", func() {
+			err := command.Execute(context.Background(), []string{"owner="}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateStore.SetCall.Receives[0].State.Annotations).To(BeNil())
+		})
+
+		It("does not mention tags outside of aws", func() {
+			state.IAAS = "gcp"
+
+			err := command.Execute(context.Background(), []string{"owner=platform-team"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the state cannot be saved", func() {
+			stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+
+			err := command.Execute(context.Background(), []string{"owner=platform-team"}, state)
+			Expect(err).To(MatchError("Save state: disk full"))
+		})
+	})
+})
+
+var _ = Describe("Annotations", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		logger         *fakes.Logger
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		logger = &fakes.Logger{}
+
+		state = storage.State{
+			Annotations: map[string]string{"owner": "platform-team", "cost-center": "1234"},
+		}
+	})
+
+	It("validates the state", func() {
+		command := commands.NewAnnotations(stateValidator, commands.NewOutputFormatter(logger, false))
+
+		err := command.CheckFastFails([]string{}, state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+	})
+
+	It("prints the annotations in order", func() {
+		command := commands.NewAnnotations(stateValidator, commands.NewOutputFormatter(logger, false))
+
+		err := command.Execute(context.Background(), []string{}, state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logger.PrintfCall.Messages).To(Equal([]string{"cost-center=1234\n", "owner=platform-team\n"}))
+	})
+
+	Context("with --json", func() {
+		It("prints the annotations as a JSON object", func() {
+			command := commands.NewAnnotations(stateValidator, commands.NewOutputFormatter(logger, true))
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{"owner": "platform-team", "cost-center": "1234"}`))
+		})
+
+		It("prints an empty object without annotations", func() {
+			command := commands.NewAnnotations(stateValidator, commands.NewOutputFormatter(logger, true))
+
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(Equal("{}"))
+		})
+	})
+})
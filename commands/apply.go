@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// EnvironmentDocument is the desired state of an environment, as read by
+// `bbl apply`. Certificate paths are relative to the document.
+type EnvironmentDocument struct {
+	IAAS   string `yaml:"iaas"`
+	Name   string `yaml:"name"`
+	Region string `yaml:"region"`
+	LB     struct {
+		Type    string `yaml:"type"`
+		Cert    string `yaml:"cert"`
+		Key     string `yaml:"key"`
+		Chain   string `yaml:"chain"`
+		CertARN string `yaml:"cert_arn"`
+		Domain  string `yaml:"domain"`
+		// ACMCertificate requests the certificate of the domain from
+		// AWS Certificate Manager.
+		ACMCertificate bool `yaml:"acm_certificate"`
+	} `yaml:"lb"`
+}
+
+type Apply struct {
+	plan   plan
+	up     up
+	fs     fileio.FileReader
+	logger logger
+}
+
+type applyConfig struct {
+	path     string
+	dryRun   bool
+	document EnvironmentDocument
+}
+
+type applyOperation struct {
+	command string
+	reason  string
+}
+
+func NewApply(plan plan, up up, fs fileio.FileReader, logger logger) Apply {
+	return Apply{
+		plan:   plan,
+		up:     up,
+		fs:     fs,
+		logger: logger,
+	}
+}
+
+func (a Apply) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := a.parseArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	document := config.document
+	if document.IAAS != "" && document.IAAS != state.IAAS {
+		return fmt.Errorf("%s describes an environment on %s, but the environment is on %s.", config.path, document.IAAS, state.IAAS)
+	}
+
+	if region := stateRegion(state); document.Region != "" && region != "" && document.Region != region {
+		if state.IAAS == "aws" {
+			return fmt.Errorf("%s describes an environment in %s, but the environment is in %s. Run bbl migrate-region --to %s to move it.", config.path, document.Region, region, document.Region)
+		}
+		return fmt.Errorf("%s describes an environment in %s, but the environment is in %s. The region cannot be changed for an existing environment.", config.path, document.Region, region)
+	}
+
+	err = a.plan.CheckFastFails(applyPlanArgs(config), state)
+	if err != nil {
+		return fmt.Errorf("plan: %s", err)
+	}
+
+	return nil
+}
+
+// Execute converges the environment to the document: the plan is updated
+// when the document differs from the state, then up brings the
+// infrastructure and director in line with it.
+func (a Apply) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := a.parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	planArgs := applyPlanArgs(config)
+	planConfig, err := a.plan.ParseArgs(planArgs, state)
+	if err != nil {
+		return fmt.Errorf("plan: %s", err)
+	}
+
+	operations := a.operations(planConfig, state)
+	for _, operation := range operations {
+		a.logger.Printf("bbl %s: %s\n", operation.command, operation.reason)
+	}
+
+	if config.dryRun {
+		return nil
+	}
+
+	for _, operation := range operations {
+		switch operation.command {
+		case "plan":
+			state, err = a.plan.InitializePlan(planConfig, state)
+			if err != nil {
+				return fmt.Errorf("plan: %s", err)
+			}
+		case "up":
+			err = a.up.Execute(ctx, planArgs, state)
+			if err != nil {
+				return fmt.Errorf("up: %s", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a Apply) operations(planConfig PlanConfig, state storage.State) []applyOperation {
+	if !a.plan.IsInitialized(state) {
+		return []applyOperation{{command: "up", reason: "create the environment"}}
+	}
+
+	operations := []applyOperation{}
+	if planConfig.LB != state.LB {
+		operations = append(operations, applyOperation{command: "plan", reason: lbChange(state.LB, planConfig.LB)})
+	}
+
+	return append(operations, applyOperation{command: "up", reason: "converge the infrastructure and director"})
+}
+
+func (a Apply) parseArgs(args []string) (applyConfig, error) {
+	var config applyConfig
+
+	applyFlags := flags.New("apply")
+	applyFlags.Bool(&config.dryRun, "dry-run", false)
+
+	err := applyFlags.Parse(args)
+	if err != nil {
+		return applyConfig{}, err
+	}
+
+	if len(applyFlags.Args()) != 1 {
+		return applyConfig{}, errors.New("apply takes the path to an environment file")
+	}
+	config.path = applyFlags.Args()[0]
+
+	contents, err := a.fs.ReadFile(config.path)
+	if err != nil {
+		return applyConfig{}, fmt.Errorf("Read %s: %s", config.path, err)
+	}
+
+	err = yaml.UnmarshalStrict(contents, &config.document)
+	if err != nil {
+		return applyConfig{}, fmt.Errorf("Parse %s: %s", config.path, err)
+	}
+
+	return config, nil
+}
+
+func applyPlanArgs(config applyConfig) []string {
+	document := config.document
+	args := []string{}
+
+	if document.Name != "" {
+		args = append(args, "--name", document.Name)
+	}
+
+	lbFlags := []struct {
+		name  string
+		value string
+		path  bool
+	}{
+		{"lb-type", document.LB.Type, false},
+		{"lb-cert", document.LB.Cert, true},
+		{"lb-key", document.LB.Key, true},
+		{"lb-chain", document.LB.Chain, true},
+		{"lb-cert-arn", document.LB.CertARN, false},
+		{"lb-domain", document.LB.Domain, false},
+	}
+	for _, flag := range lbFlags {
+		if flag.value == "" {
+			continue
+		}
+
+		value := flag.value
+		if flag.path && !filepath.IsAbs(value) {
+			value = filepath.Join(filepath.Dir(config.path), value)
+		}
+		args = append(args, "--"+flag.name, value)
+	}
+	if document.LB.ACMCertificate {
+		args = append(args, "--lb-acm-certificate")
+	}
+
+	return args
+}
+
+func lbChange(current, desired storage.LB) string {
+	switch {
+	case current.Type == "":
+		return fmt.Sprintf("create %s load balancers", desired.Type)
+	case desired.Type == "":
+		return fmt.Sprintf("delete %s load balancers", current.Type)
+	case current.Type != desired.Type:
+		return fmt.Sprintf("replace %s load balancers with %s load balancers", current.Type, desired.Type)
+	default:
+		return fmt.Sprintf("update %s load balancers", desired.Type)
+	}
+}
+
+func stateRegion(state storage.State) string {
+	switch state.IAAS {
+	case "aws":
+		return state.AWS.Region
+	case "gcp":
+		return state.GCP.Region
+	case "azure":
+		return state.Azure.Region
+	}
+	return ""
+}
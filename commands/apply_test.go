@@ -0,0 +1,227 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Apply", func() {
+	var (
+		plan   *fakes.Plan
+		up     *fakes.Up
+		fs     *afero.Afero
+		logger *fakes.Logger
+		apply  commands.Apply
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		plan = &fakes.Plan{}
+		up = &fakes.Up{}
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		logger = &fakes.Logger{}
+		apply = commands.NewApply(plan, up, fs, logger)
+
+		state = storage.State{
+			IAAS:    "aws",
+			EnvID:   "some-env",
+			Version: 14,
+			AWS:     storage.AWS{Region: "us-west-2"},
+		}
+
+		Expect(fs.WriteFile("/envs/env.yml", []byte(`---
+iaas: aws
+name: some-env
+region: us-west-2
+lb:
+  type: cf
+  cert: certs/lb.crt
+  key: /secrets/lb.key
+  domain: example.com
+`), 0644)).To(Succeed())
+	})
+
+	Describe("CheckFastFails", func() {
+		It("calls plan.CheckFastFails with the flags for the document", func() {
+			err := apply.CheckFastFails([]string{"/envs/env.yml"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.CheckFastFailsCall.CallCount).To(Equal(1))
+			Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{
+				"--name", "some-env",
+				"--lb-type", "cf",
+				"--lb-cert", "/envs/certs/lb.crt",
+				"--lb-key", "/secrets/lb.key",
+				"--lb-domain", "example.com",
+			}))
+		})
+
+		It("passes --lb-acm-certificate when the document requests the certificate from ACM", func() {
+			Expect(fs.WriteFile("/envs/acm.yml", []byte(`---
+iaas: aws
+lb:
+  type: cf
+  domain: example.com
+  acm_certificate: true
+`), 0644)).To(Succeed())
+
+			err := apply.CheckFastFails([]string{"/envs/acm.yml"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{
+				"--lb-type", "cf",
+				"--lb-domain", "example.com",
+				"--lb-acm-certificate",
+			}))
+		})
+
+		It("requires a path", func() {
+			err := apply.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("apply takes the path to an environment file"))
+		})
+
+		It("returns an error when the document cannot be read", func() {
+			err := apply.CheckFastFails([]string{"/envs/missing.yml"}, state)
+			Expect(err).To(MatchError(ContainSubstring("Read /envs/missing.yml: ")))
+		})
+
+		It("returns an error for unknown fields", func() {
+			Expect(fs.WriteFile("/envs/env.yml", []byte("iaas: aws\nnetworks: []\n"), 0644)).To(Succeed())
+
+			err := apply.CheckFastFails([]string{"/envs/env.yml"}, state)
+			Expect(err).To(MatchError(ContainSubstring("Parse /envs/env.yml: ")))
+		})
+
+		It("returns an error when the iaas does not match", func() {
+			state.IAAS = "gcp"
+			err := apply.CheckFastFails([]string{"/envs/env.yml"}, state)
+			Expect(err).To(MatchError("/envs/env.yml describes an environment on aws, but the environment is on gcp."))
+		})
+
+		It("points at migrate-region when the aws region does not match", func() {
+			state.AWS.Region = "us-east-1"
+			err := apply.CheckFastFails([]string{"/envs/env.yml"}, state)
+			Expect(err).To(MatchError("/envs/env.yml describes an environment in us-west-2, but the environment is in us-east-1. Run bbl migrate-region --to us-west-2 to move it."))
+		})
+
+		Context("when plan.CheckFastFails returns an error", func() {
+			BeforeEach(func() {
+				plan.CheckFastFailsCall.Returns.Error = errors.New("kiwi")
+			})
+
+			It("wraps and returns the error", func() {
+				err := apply.CheckFastFails([]string{"/envs/env.yml"}, state)
+				Expect(err).To(MatchError("plan: kiwi"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		var desiredLB storage.LB
+
+		BeforeEach(func() {
+			plan.IsInitializedCall.Returns.IsInitialized = true
+			desiredLB = storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key", Domain: "example.com"}
+			plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-env", LB: desiredLB}
+			plan.InitializePlanCall.Returns.State = storage.State{IAAS: "aws", EnvID: "some-env", LB: desiredLB}
+		})
+
+		Context("when the load balancers differ from the state", func() {
+			It("updates the plan and then brings the environment up", func() {
+				err := apply.Execute(context.Background(), []string{"/envs/env.yml"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(1))
+				Expect(plan.InitializePlanCall.Receives.Plan.LB).To(Equal(desiredLB))
+				Expect(plan.InitializePlanCall.Receives.State).To(Equal(state))
+
+				Expect(up.ExecuteCall.CallCount).To(Equal(1))
+				Expect(up.ExecuteCall.Receives.State).To(Equal(plan.InitializePlanCall.Returns.State))
+
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{
+					"bbl plan: create cf load balancers\n",
+					"bbl up: converge the infrastructure and director\n",
+				}))
+			})
+		})
+
+		Context("when the state matches the document", func() {
+			BeforeEach(func() {
+				state.LB = desiredLB
+			})
+
+			It("only runs up", func() {
+				err := apply.Execute(context.Background(), []string{"/envs/env.yml"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(0))
+				Expect(up.ExecuteCall.CallCount).To(Equal(1))
+				Expect(up.ExecuteCall.Receives.State).To(Equal(state))
+			})
+		})
+
+		Context("when the state directory has not been initialized", func() {
+			BeforeEach(func() {
+				plan.IsInitializedCall.Returns.IsInitialized = false
+			})
+
+			It("lets up create the environment from the document", func() {
+				err := apply.Execute(context.Background(), []string{"/envs/env.yml"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(0))
+				Expect(up.ExecuteCall.Receives.Args).To(Equal([]string{
+					"--name", "some-env",
+					"--lb-type", "cf",
+					"--lb-cert", "/envs/certs/lb.crt",
+					"--lb-key", "/secrets/lb.key",
+					"--lb-domain", "example.com",
+				}))
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{"bbl up: create the environment\n"}))
+			})
+		})
+
+		Context("when --dry-run is passed", func() {
+			It("prints the operations without running them", func() {
+				err := apply.Execute(context.Background(), []string{"--dry-run", "/envs/env.yml"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.Messages).To(HaveLen(2))
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(0))
+				Expect(up.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the plan cannot be initialized", func() {
+			BeforeEach(func() {
+				plan.InitializePlanCall.Returns.Error = errors.New("lychee")
+			})
+
+			It("returns the error without running up", func() {
+				err := apply.Execute(context.Background(), []string{"/envs/env.yml"}, state)
+				Expect(err).To(MatchError("plan: lychee"))
+				Expect(up.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when up fails", func() {
+			BeforeEach(func() {
+				up.ExecuteCall.Returns.Error = errors.New("papaya")
+			})
+
+			It("wraps and returns the error", func() {
+				err := apply.Execute(context.Background(), []string{"/envs/env.yml"}, state)
+				Expect(err).To(MatchError("up: papaya"))
+			})
+		})
+	})
+})
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type attachCertificateConfig struct {
+	lb   string
+	name string
+}
+
+// AttachCertificate attaches a certificate of bbl create-certificate to the
+// TLS listeners of a load balancer, which the next bbl plan and bbl up switch
+// over to it.
+type AttachCertificate struct {
+	stateValidator stateValidator
+	stateStore     stateStore
+	logger         logger
+}
+
+func NewAttachCertificate(stateValidator stateValidator, stateStore stateStore, logger logger) AttachCertificate {
+	return AttachCertificate{
+		stateValidator: stateValidator,
+		stateStore:     stateStore,
+		logger:         logger,
+	}
+}
+
+func (a AttachCertificate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := parseAttachCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = a.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("attach-certificate only attaches certificates to the load balancers of aws environments.")
+	}
+
+	if err := checkNotLite("attach-certificate", state); err != nil {
+		return err
+	}
+
+	if state.AWS.CertificateNamed(config.name) == nil {
+		return fmt.Errorf("There is no certificate named %s. Create it with bbl create-certificate.", config.name)
+	}
+
+	return nil
+}
+
+func (a AttachCertificate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseAttachCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	state.AWS.AttachCertificate(config.name, config.lb)
+
+	err = a.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	a.logger.Println(fmt.Sprintf("Attached the certificate %s to the %s load balancer. Run bbl plan --lb-type cf and bbl up to use it.", config.name, config.lb))
+	return nil
+}
+
+func parseAttachCertificateArgs(args []string) (attachCertificateConfig, error) {
+	var config attachCertificateConfig
+
+	attachFlags := flags.New("attach-certificate")
+	attachFlags.String(&config.lb, "lb", "")
+	attachFlags.String(&config.name, "name", "")
+
+	err := attachFlags.Parse(args)
+	if err != nil {
+		return attachCertificateConfig{}, err
+	}
+
+	if config.lb == "" || config.name == "" {
+		return attachCertificateConfig{}, errors.New("--lb and --name are required")
+	}
+
+	if _, ok := storage.CertificateLBs[config.lb]; !ok {
+		lbs := []string{}
+		for lb := range storage.CertificateLBs {
+			lbs = append(lbs, lb)
+		}
+		sort.Strings(lbs)
+		return attachCertificateConfig{}, fmt.Errorf("--lb %q is not a load balancer that certificates are attached to. Use one of: %s.", config.lb, strings.Join(lbs, ", "))
+	}
+
+	return config, nil
+}
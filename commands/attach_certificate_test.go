@@ -0,0 +1,88 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachCertificate", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		stateStore     *fakes.StateStore
+		logger         *fakes.Logger
+		command        commands.AttachCertificate
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		command = commands.NewAttachCertificate(stateValidator, stateStore, logger)
+
+		state = storage.State{IAAS: "aws", EnvID: "some-env"}
+		state.AWS.Certificates = []storage.ServerCertificate{
+			{Name: "bar", ARN: "bar-arn", LBs: []string{"cf-router"}},
+			{Name: "foo", ARN: "foo-arn"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--lb", "cf-router", "--name", "foo"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the load balancer and the name", func() {
+			err := command.CheckFastFails([]string{"--name", "foo"}, state)
+			Expect(err).To(MatchError("--lb and --name are required"))
+		})
+
+		It("requires a load balancer with TLS listeners", func() {
+			err := command.CheckFastFails([]string{"--lb", "concourse", "--name", "foo"}, state)
+			Expect(err).To(MatchError(`--lb "concourse" is not a load balancer that certificates are attached to. Use one of: cf-iso-router, cf-router.`))
+		})
+
+		It("requires a certificate of create-certificate", func() {
+			err := command.CheckFastFails([]string{"--lb", "cf-router", "--name", "baz"}, state)
+			Expect(err).To(MatchError("There is no certificate named baz. Create it with bbl create-certificate."))
+		})
+
+		It("does not apply to bosh-lite environments", func() {
+			state.AWS.Lite = true
+
+			err := command.CheckFastFails([]string{"--lb", "cf-router", "--name", "foo"}, state)
+			Expect(err).To(MatchError("attach-certificate does not apply to bosh-lite environments, which have no load balancers."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("attaches the certificate in place of the one that was attached", func() {
+			err := command.Execute(context.Background(), []string{"--lb", "cf-router", "--name", "foo"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			saved := stateStore.SetCall.Receives[0].State
+			Expect(saved.AWS.Certificates).To(Equal([]storage.ServerCertificate{
+				{Name: "bar", ARN: "bar-arn"},
+				{Name: "foo", ARN: "foo-arn", LBs: []string{"cf-router"}},
+			}))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Attached the certificate foo to the cf-router load balancer. Run bbl plan --lb-type cf and bbl up to use it."))
+		})
+
+		It("returns an error when the state cannot be saved", func() {
+			stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+
+			err := command.Execute(context.Background(), []string{"--lb", "cf-router", "--name", "foo"}, state)
+			Expect(err).To(MatchError("Save state: disk full"))
+		})
+	})
+})
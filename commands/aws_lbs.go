@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"context"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -20,54 +24,118 @@ func NewAWSLBs(terraformManager terraformManager, logger logger) AWSLBs {
 	}
 }
 
-func (l AWSLBs) Execute(subcommandFlags []string, state storage.State) error {
-	terraformOutputs, err := l.terraformManager.GetOutputs()
+// AWSLoadBalancers are the load balancers of an aws environment, which bbl
+// lbs prints.
+type AWSLoadBalancers struct {
+	Type                   string   `json:"type"`
+	CertificateName        string   `json:"certificate_name,omitempty"`
+	CertificateARN         string   `json:"certificate_arn,omitempty"`
+	CertificateExpiry      string   `json:"certificate_expiry,omitempty"`
+	RouterLBName           string   `json:"cf_router_lb,omitempty"`
+	RouterLBURL            string   `json:"cf_router_lb_url,omitempty"`
+	SSHProxyLBName         string   `json:"cf_ssh_proxy_lb,omitempty"`
+	SSHProxyLBURL          string   `json:"cf_ssh_proxy_lb_url,omitempty"`
+	TCPRouterLBName        string   `json:"cf_tcp_lb,omitempty"`
+	TCPRouterLBURL         string   `json:"cf_tcp_lb_url,omitempty"`
+	SystemDomainDNSServers []string `json:"env_dns_zone_name_servers,omitempty"`
+	ConcourseLBName        string   `json:"concourse_lb,omitempty"`
+	ConcourseLBURL         string   `json:"concourse_lb_url,omitempty"`
+}
+
+func (l AWSLBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	lbs, err := l.LoadBalancers(state)
 	if err != nil {
 		return err
 	}
 
+	if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
+		lbOutput, err := json.Marshal(lbs)
+		if err != nil {
+			// not tested
+			return err
+		}
+
+		l.logger.Println(string(lbOutput))
+		return nil
+	}
+
+	l.logger.Printf("LB Type: %s\n", lbs.Type)
+	switch lbs.Type {
+	case "cf":
+		l.logger.Printf("CF Router LB: %s [%s]\n", lbs.RouterLBName, lbs.RouterLBURL)
+		l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", lbs.SSHProxyLBName, lbs.SSHProxyLBURL)
+		l.logger.Printf("CF TCP Router LB: %s [%s]\n", lbs.TCPRouterLBName, lbs.TCPRouterLBURL)
+
+		if len(lbs.SystemDomainDNSServers) > 0 {
+			l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(lbs.SystemDomainDNSServers, " "))
+		}
+
+		if lbs.CertificateName != "" {
+			l.logger.Printf("Certificate: %s [%s]\n", lbs.CertificateName, lbs.CertificateARN)
+		} else if lbs.CertificateARN != "" {
+			l.logger.Printf("Certificate: %s\n", lbs.CertificateARN)
+		}
+		if lbs.CertificateExpiry != "" {
+			l.logger.Printf("Certificate Expiry: %s\n", lbs.CertificateExpiry)
+		}
+	case "concourse":
+		l.logger.Printf("Concourse LB: %s [%s]\n", lbs.ConcourseLBName, lbs.ConcourseLBURL)
+	}
+
+	return nil
+}
+
+// LoadBalancers returns the load balancers of the environment of state from
+// the terraform outputs.
+func (l AWSLBs) LoadBalancers(state storage.State) (AWSLoadBalancers, error) {
+	terraformOutputs, err := l.terraformManager.GetOutputs()
+	if err != nil {
+		return AWSLoadBalancers{}, err
+	}
+
 	switch state.LB.Type {
 	case "cf":
-		if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
-			lbOutput, err := json.Marshal(struct {
-				RouterLBName           string   `json:"cf_router_lb,omitempty"`
-				RouterLBURL            string   `json:"cf_router_lb_url,omitempty"`
-				SSHProxyLBName         string   `json:"cf_ssh_proxy_lb,omitempty"`
-				SSHProxyLBURL          string   `json:"cf_ssh_proxy_lb_url,omitempty"`
-				TCPRouterLBName        string   `json:"cf_tcp_lb,omitempty"`
-				TCPRouterLBURL         string   `json:"cf_tcp_lb_url,omitempty"`
-				SystemDomainDNSServers []string `json:"env_dns_zone_name_servers,omitempty"`
-			}{
-				RouterLBName:           terraformOutputs.GetString("cf_router_lb_name"),
-				RouterLBURL:            terraformOutputs.GetString("cf_router_lb_url"),
-				SSHProxyLBName:         terraformOutputs.GetString("cf_ssh_lb_name"),
-				SSHProxyLBURL:          terraformOutputs.GetString("cf_ssh_lb_url"),
-				TCPRouterLBName:        terraformOutputs.GetString("cf_tcp_lb_name"),
-				TCPRouterLBURL:         terraformOutputs.GetString("cf_tcp_lb_url"),
-				SystemDomainDNSServers: terraformOutputs.GetStringSlice("env_dns_zone_name_servers"),
-			})
-			if err != nil {
-				// not tested
-				return err
-			}
-
-			l.logger.Println(string(lbOutput))
-		} else {
-			l.logger.Printf("CF Router LB: %s [%s]\n", terraformOutputs.GetString("cf_router_lb_name"), terraformOutputs.GetString("cf_router_lb_url"))
-			l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", terraformOutputs.GetString("cf_ssh_lb_name"), terraformOutputs.GetString("cf_ssh_lb_url"))
-			l.logger.Printf("CF TCP Router LB: %s [%s]\n", terraformOutputs.GetString("cf_tcp_lb_name"), terraformOutputs.GetString("cf_tcp_lb_url"))
-
-			dnsServers := terraformOutputs.GetStringSlice("env_dns_zone_name_servers")
-
-			if len(dnsServers) > 0 {
-				l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(dnsServers, " "))
-			}
+		certName, certARN := terraformOutputs.GetString("lb_cert_name"), terraformOutputs.GetString("lb_cert_arn")
+		if state.LB.CertARN != "" {
+			certName, certARN = "", state.LB.CertARN
 		}
+
+		return AWSLoadBalancers{
+			Type:                   state.LB.Type,
+			CertificateName:        certName,
+			CertificateARN:         certARN,
+			CertificateExpiry:      certificateExpiry(state.LB.Cert),
+			RouterLBName:           terraformOutputs.GetString("cf_router_lb_name"),
+			RouterLBURL:            terraformOutputs.GetString("cf_router_lb_url"),
+			SSHProxyLBName:         terraformOutputs.GetString("cf_ssh_lb_name"),
+			SSHProxyLBURL:          terraformOutputs.GetString("cf_ssh_lb_url"),
+			TCPRouterLBName:        terraformOutputs.GetString("cf_tcp_lb_name"),
+			TCPRouterLBURL:         terraformOutputs.GetString("cf_tcp_lb_url"),
+			SystemDomainDNSServers: terraformOutputs.GetStringSlice("env_dns_zone_name_servers"),
+		}, nil
 	case "concourse":
-		l.logger.Printf("Concourse LB: %s [%s]\n", terraformOutputs.GetString("concourse_lb_name"), terraformOutputs.GetString("concourse_lb_url"))
+		return AWSLoadBalancers{
+			Type:            state.LB.Type,
+			ConcourseLBName: terraformOutputs.GetString("concourse_lb_name"),
+			ConcourseLBURL:  terraformOutputs.GetString("concourse_lb_url"),
+		}, nil
 	default:
-		return errors.New("no lbs found")
+		return AWSLoadBalancers{}, errors.New("no lbs found")
 	}
+}
 
-	return nil
+// certificateExpiry returns when the certificate bbl uploaded for the load
+// balancers expires, or "" if there is none to read.
+func certificateExpiry(certPEM string) string {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return ""
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ""
+	}
+
+	return certificate.NotAfter.UTC().Format(time.RFC3339)
 }
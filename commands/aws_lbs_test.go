@@ -1,7 +1,15 @@
 package commands_test
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
@@ -49,11 +57,12 @@ var _ = Describe("AWSLBs", func() {
 			})
 
 			It("prints LB names and URLs for router and ssh proxy", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
 				Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+					"LB Type: cf\n",
 					"CF Router LB: some-router-lb-name [some-router-lb-url]\n",
 					"CF SSH Proxy LB: some-ssh-lb-name [some-ssh-lb-url]\n",
 					"CF TCP Router LB: some-tcp-lb-name [some-tcp-lb-url]\n",
@@ -68,11 +77,12 @@ var _ = Describe("AWSLBs", func() {
 				})
 
 				It("prints LB names, URLs, and DNS servers", func() {
-					err := command.Execute([]string{}, incomingState)
+					err := command.Execute(context.Background(), []string{}, incomingState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
 					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+						"LB Type: cf\n",
 						"CF Router LB: some-router-lb-name [some-router-lb-url]\n",
 						"CF SSH Proxy LB: some-ssh-lb-name [some-ssh-lb-url]\n",
 						"CF TCP Router LB: some-tcp-lb-name [some-tcp-lb-url]\n",
@@ -86,10 +96,11 @@ var _ = Describe("AWSLBs", func() {
 							Type:   "cf",
 							Domain: "some-domain",
 						}
-						err := command.Execute([]string{"--json"}, incomingState)
+						err := command.Execute(context.Background(), []string{"--json"}, incomingState)
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+								"type": "cf",
 								"cf_router_lb": "some-router-lb-name",
 								"cf_router_lb_url": "some-router-lb-url",
 								"cf_ssh_proxy_lb": "some-ssh-lb-name",
@@ -104,6 +115,53 @@ var _ = Describe("AWSLBs", func() {
 					})
 				})
 			})
+
+			Context("when bbl uploaded the certificate to IAM", func() {
+				BeforeEach(func() {
+					incomingState.LB.Cert = certificatePEM(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+					terraformManager.GetOutputsCall.Returns.Outputs.Map["lb_cert_name"] = "some-cert-name"
+					terraformManager.GetOutputsCall.Returns.Outputs.Map["lb_cert_arn"] = "some-cert-arn"
+				})
+
+				It("prints the certificate name, arn and expiry", func() {
+					err := command.Execute(context.Background(), []string{}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCall.Messages).To(ContainElement("Certificate: some-cert-name [some-cert-arn]\n"))
+					Expect(logger.PrintfCall.Messages).To(ContainElement("Certificate Expiry: 2030-01-02T03:04:05Z\n"))
+				})
+
+				It("includes the certificate in json format", func() {
+					err := command.Execute(context.Background(), []string{"--json"}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+						"type": "cf",
+						"certificate_name": "some-cert-name",
+						"certificate_arn": "some-cert-arn",
+						"certificate_expiry": "2030-01-02T03:04:05Z",
+						"cf_router_lb": "some-router-lb-name",
+						"cf_router_lb_url": "some-router-lb-url",
+						"cf_ssh_proxy_lb": "some-ssh-lb-name",
+						"cf_ssh_proxy_lb_url": "some-ssh-lb-url",
+						"cf_tcp_lb": "some-tcp-lb-name",
+						"cf_tcp_lb_url":  "some-tcp-lb-url"
+					}`))
+				})
+			})
+
+			Context("when the lbs use an ACM certificate", func() {
+				BeforeEach(func() {
+					incomingState.LB.CertARN = "some-acm-cert-arn"
+				})
+
+				It("prints the certificate arn", func() {
+					err := command.Execute(context.Background(), []string{}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCall.Messages).To(ContainElement("Certificate: some-acm-cert-arn\n"))
+				})
+			})
 		})
 
 		Context("when the lb type is concourse", func() {
@@ -121,14 +179,26 @@ var _ = Describe("AWSLBs", func() {
 			})
 
 			It("prints LB name and URL", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
 				Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+					"LB Type: concourse\n",
 					"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
 				}))
 			})
+
+			It("prints LB name and URL as json", func() {
+				err := command.Execute(context.Background(), []string{"--json"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+					"type": "concourse",
+					"concourse_lb": "some-concourse-lb-name",
+					"concourse_lb_url": "some-concourse-lb-url"
+				}`))
+			})
 		})
 
 		Context("when lb type is not cf or concourse", func() {
@@ -142,7 +212,7 @@ var _ = Describe("AWSLBs", func() {
 			})
 
 			It("returns error", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).To(MatchError("no lbs found"))
 			})
 		})
@@ -155,11 +225,50 @@ var _ = Describe("AWSLBs", func() {
 			Context("when terraform manager fails", func() {
 				It("returns an error", func() {
 					terraformManager.GetOutputsCall.Returns.Error = errors.New("terraform manager failed")
-					err := command.Execute([]string{}, incomingState)
+					err := command.Execute(context.Background(), []string{}, incomingState)
 
 					Expect(err).To(MatchError("terraform manager failed"))
 				})
 			})
 		})
 	})
+
+	Describe("LoadBalancers", func() {
+		It("returns the load balancers of a concourse environment", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{
+				"concourse_lb_name": "some-concourse-lb-name",
+				"concourse_lb_url":  "some-concourse-lb-url",
+			}}
+
+			lbs, err := command.LoadBalancers(storage.State{IAAS: "aws", LB: storage.LB{Type: "concourse"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lbs).To(Equal(commands.AWSLoadBalancers{
+				Type:            "concourse",
+				ConcourseLBName: "some-concourse-lb-name",
+				ConcourseLBURL:  "some-concourse-lb-url",
+			}))
+			Expect(logger.PrintfCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error without load balancers", func() {
+			_, err := command.LoadBalancers(storage.State{IAAS: "aws"})
+			Expect(err).To(MatchError("no lbs found"))
+		})
+	})
 })
+
+func certificatePEM(notAfter time.Time) string {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "some-domain"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -18,16 +20,34 @@ func NewAzureLBs(terraformManager terraformManager, logger logger) AzureLBs {
 	}
 }
 
-func (l AzureLBs) Execute(subcommandFlags []string, state storage.State) error {
+func (l AzureLBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	terraformOutputs, err := l.terraformManager.GetOutputs()
 	if err != nil {
 		return err
 	}
 
+	jsonOutput := len(subcommandFlags) > 0 && subcommandFlags[0] == "--json"
+
 	switch state.LB.Type {
 	case "cf":
+		if jsonOutput {
+			return l.printJSON(struct {
+				CFLBName string `json:"cf_lb,omitempty"`
+			}{
+				CFLBName: terraformOutputs.GetString("cf_app_gateway_name"),
+			})
+		}
 		l.logger.Printf("CF LB: %s\n", terraformOutputs.GetString("cf_app_gateway_name"))
 	case "concourse":
+		if jsonOutput {
+			return l.printJSON(struct {
+				ConcourseLBName string `json:"concourse_lb,omitempty"`
+				ConcourseLBIP   string `json:"concourse_lb_ip,omitempty"`
+			}{
+				ConcourseLBName: terraformOutputs.GetString("concourse_lb_name"),
+				ConcourseLBIP:   terraformOutputs.GetString("concourse_lb_ip"),
+			})
+		}
 		l.logger.Printf("Concourse LB: %s (%s)\n", terraformOutputs.GetString("concourse_lb_name"), terraformOutputs.GetString("concourse_lb_ip"))
 	default:
 		return errors.New("no lbs found")
@@ -35,3 +55,14 @@ func (l AzureLBs) Execute(subcommandFlags []string, state storage.State) error {
 
 	return nil
 }
+
+func (l AzureLBs) printJSON(lbs interface{}) error {
+	lbOutput, err := json.Marshal(lbs)
+	if err != nil {
+		// not tested
+		return err
+	}
+
+	l.logger.Println(string(lbOutput))
+	return nil
+}
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -44,7 +45,7 @@ var _ = Describe("Azure LBs", func() {
 			})
 
 			It("prints LB name", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -52,6 +53,13 @@ var _ = Describe("Azure LBs", func() {
 					"CF LB: some-app-gateway-name\n",
 				}))
 			})
+
+			It("prints LB name as json", func() {
+				err := command.Execute(context.Background(), []string{"--json"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{"cf_lb": "some-app-gateway-name"}`))
+			})
 		})
 
 		Context("when the lb type is concourse", func() {
@@ -69,7 +77,7 @@ var _ = Describe("Azure LBs", func() {
 			})
 
 			It("prints LB name", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -77,6 +85,16 @@ var _ = Describe("Azure LBs", func() {
 					"Concourse LB: some-load-balancer-name (5.6.7.8)\n",
 				}))
 			})
+
+			It("prints LB name and ip as json", func() {
+				err := command.Execute(context.Background(), []string{"--json"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+					"concourse_lb": "some-load-balancer-name",
+					"concourse_lb_ip": "5.6.7.8"
+				}`))
+			})
 		})
 
 		Context("when lb type is not cf or concourse", func() {
@@ -90,7 +108,7 @@ var _ = Describe("Azure LBs", func() {
 			})
 
 			It("returns error", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).To(MatchError("no lbs found"))
 			})
 		})
@@ -103,7 +121,7 @@ var _ = Describe("Azure LBs", func() {
 			Context("when terraform manager fails", func() {
 				It("returns an error", func() {
 					terraformManager.GetOutputsCall.Returns.Error = errors.New("terraform manager failed")
-					err := command.Execute([]string{}, incomingState)
+					err := command.Execute(context.Background(), []string{}, incomingState)
 
 					Expect(err).To(MatchError("terraform manager failed"))
 				})
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type templateGenerator interface {
+	Generate(storage.State) string
+}
+
+type benchStateStore interface {
+	GetStateDir() string
+}
+
+type benchFS interface {
+	fileio.TempDirer
+	fileio.FileWriter
+	fileio.FileReader
+	fileio.AllRemover
+}
+
+// Bench times the steps of plan and up that bbl runs locally, without
+// calling the iaas or the director. Nothing in the state directory changes.
+type Bench struct {
+	logger             logger
+	stateValidator     stateValidator
+	stateStore         benchStateStore
+	templateGenerator  templateGenerator
+	cloudConfigManager cloudConfigManager
+	serializer         storage.StateSerializer
+	fs                 benchFS
+}
+
+type benchStep struct {
+	name string
+	run  func() error
+}
+
+func NewBench(logger logger, stateValidator stateValidator, stateStore benchStateStore, templateGenerator templateGenerator,
+	cloudConfigManager cloudConfigManager, serializer storage.StateSerializer, fs benchFS) Bench {
+	return Bench{
+		logger:             logger,
+		stateValidator:     stateValidator,
+		stateStore:         stateStore,
+		templateGenerator:  templateGenerator,
+		cloudConfigManager: cloudConfigManager,
+		serializer:         serializer,
+		fs:                 fs,
+	}
+}
+
+func (b Bench) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseBenchArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return b.stateValidator.Validate()
+}
+
+func (b Bench) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	iterations, err := parseBenchArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	// The state is saved and loaded next to the real one, so that a slow
+	// filesystem under the state directory shows up in the timings.
+	dir, err := b.fs.TempDir(b.stateStore.GetStateDir(), "bbl-bench")
+	if err != nil {
+		return fmt.Errorf("Create bench dir: %s", err)
+	}
+	defer b.fs.RemoveAll(dir)
+	statePath := filepath.Join(dir, b.serializer.FileName())
+
+	steps := []benchStep{
+		{name: "render terraform template", run: func() error {
+			b.templateGenerator.Generate(state)
+			return nil
+		}},
+		{name: "save state", run: func() error {
+			contents, err := b.serializer.Marshal(state, nil)
+			if err != nil {
+				return err
+			}
+			return b.fs.WriteFile(statePath, contents, storage.StateMode)
+		}},
+		{name: "load state", run: func() error {
+			contents, err := b.fs.ReadFile(statePath)
+			if err != nil {
+				return err
+			}
+			var loaded storage.State
+			return b.serializer.Unmarshal(contents, &loaded)
+		}},
+	}
+
+	if !state.NoDirector && b.cloudConfigManager.IsPresentCloudConfig() && b.cloudConfigManager.IsPresentCloudConfigVars() {
+		steps = append(steps, benchStep{name: "interpolate cloud config", run: func() error {
+			_, err := b.cloudConfigManager.Interpolate()
+			return err
+		}})
+	}
+
+	b.logger.Printf("%d iterations of each step\n", iterations)
+	b.logger.Printf("%-26s %12s %12s %12s\n", "step", "min", "mean", "max")
+	for _, step := range steps {
+		var min, max, total time.Duration
+		for i := 0; i < iterations; i++ {
+			start := time.Now()
+			err := step.run()
+			elapsed := time.Since(start)
+			if err != nil {
+				return fmt.Errorf("Bench %s: %s", step.name, err)
+			}
+
+			if i == 0 || elapsed < min {
+				min = elapsed
+			}
+			if elapsed > max {
+				max = elapsed
+			}
+			total += elapsed
+		}
+
+		mean := total / time.Duration(iterations)
+		b.logger.Printf("%-26s %12s %12s %12s\n", step.name, min, mean, max)
+	}
+
+	return nil
+}
+
+func parseBenchArgs(args []string) (int, error) {
+	var iterations string
+	benchFlags := flags.New("bench")
+	benchFlags.String(&iterations, "iterations", "10")
+
+	err := benchFlags.Parse(args)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(iterations)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("--iterations %q is not a positive number.", iterations)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,118 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bench", func() {
+	var (
+		logger             *fakes.Logger
+		stateValidator     *fakes.StateValidator
+		stateStore         *fakes.StateStore
+		templateGenerator  *fakes.TemplateGenerator
+		cloudConfigManager *fakes.CloudConfigManager
+		fileIO             *fakes.FileIO
+		command            commands.Bench
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stateValidator = &fakes.StateValidator{}
+		stateStore = &fakes.StateStore{}
+		stateStore.GetStateDirCall.Returns.Directory = "/state"
+		templateGenerator = &fakes.TemplateGenerator{}
+		cloudConfigManager = &fakes.CloudConfigManager{}
+		cloudConfigManager.IsPresentCloudConfigCall.Returns.IsPresent = true
+		cloudConfigManager.IsPresentCloudConfigVarsCall.Returns.IsPresent = true
+		fileIO = &fakes.FileIO{}
+		fileIO.TempDirCall.Returns.Name = "/state/bbl-bench123"
+		fileIO.ReadFileCall.Fake = func(string) ([]byte, error) {
+			return fileIO.WriteFileCall.Receives[len(fileIO.WriteFileCall.Receives)-1].Contents, nil
+		}
+
+		command = commands.NewBench(logger, stateValidator, stateStore, templateGenerator, cloudConfigManager, storage.JSONStateSerializer{}, fileIO)
+
+		state = storage.State{IAAS: "aws", EnvID: "some-env-id"}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when the iterations are not a positive number", func() {
+			err := command.CheckFastFails([]string{"--iterations", "0"}, state)
+			Expect(err).To(MatchError(`--iterations "0" is not a positive number.`))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("runs each step the given number of times and prints the timings", func() {
+			err := command.Execute(context.Background(), []string{"--iterations", "3"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templateGenerator.GenerateCall.CallCount).To(Equal(3))
+			Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(state))
+			Expect(fileIO.WriteFileCall.CallCount).To(Equal(3))
+			Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join("/state/bbl-bench123", "bbl-state.json")))
+			Expect(fileIO.ReadFileCall.CallCount).To(Equal(3))
+			Expect(cloudConfigManager.InterpolateCall.CallCount).To(Equal(3))
+
+			Expect(logger.PrintfCall.Messages).To(HaveLen(6))
+			Expect(logger.PrintfCall.Messages[0]).To(Equal("3 iterations of each step\n"))
+			Expect(logger.PrintfCall.Messages[1]).To(MatchRegexp(`^step\s+min\s+mean\s+max\n$`))
+			Expect(logger.PrintfCall.Messages[2]).To(MatchRegexp(`^render terraform template\s+\S+\s+\S+\s+\S+\n$`))
+			Expect(logger.PrintfCall.Messages[3]).To(HavePrefix("save state"))
+			Expect(logger.PrintfCall.Messages[4]).To(HavePrefix("load state"))
+			Expect(logger.PrintfCall.Messages[5]).To(HavePrefix("interpolate cloud config"))
+		})
+
+		It("works in a temporary directory under the state directory and removes it", func() {
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.TempDirCall.Receives.Dir).To(Equal("/state"))
+			Expect(fileIO.RemoveAllCall.Receives).To(ConsistOf(fakes.RemoveAllReceive{Path: "/state/bbl-bench123"}))
+			Expect(templateGenerator.GenerateCall.CallCount).To(Equal(10))
+		})
+
+		It("skips the cloud config when there is none", func() {
+			cloudConfigManager.IsPresentCloudConfigVarsCall.Returns.IsPresent = false
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cloudConfigManager.InterpolateCall.CallCount).To(Equal(0))
+			Expect(logger.PrintfCall.Messages).To(HaveLen(5))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the bench directory cannot be created", func() {
+				fileIO.TempDirCall.Returns.Error = errors.New("read-only")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Create bench dir: read-only"))
+			})
+
+			It("returns an error when a step fails", func() {
+				cloudConfigManager.InterpolateCall.Returns.Error = errors.New("bad ops file")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Bench interpolate cloud config: bad ops file"))
+			})
+		})
+	})
+})
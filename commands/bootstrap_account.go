@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type AccountBootstrapper interface {
+	BootstrapAccount() error
+}
+
+type BootstrapAccount struct {
+	accountBootstrapper AccountBootstrapper
+}
+
+func NewBootstrapAccount(accountBootstrapper AccountBootstrapper) BootstrapAccount {
+	return BootstrapAccount{
+		accountBootstrapper: accountBootstrapper,
+	}
+}
+
+func (b BootstrapAccount) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	if state.IAAS != "aws" {
+		return errors.New("bootstrap-account is only supported for aws environments")
+	}
+
+	return nil
+}
+
+func (b BootstrapAccount) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	return b.accountBootstrapper.BootstrapAccount()
+}
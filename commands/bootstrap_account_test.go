@@ -0,0 +1,52 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BootstrapAccount", func() {
+	var (
+		accountBootstrapper *fakes.AccountBootstrapper
+		command             commands.BootstrapAccount
+	)
+
+	BeforeEach(func() {
+		accountBootstrapper = &fakes.AccountBootstrapper{}
+		command = commands.NewBootstrapAccount(accountBootstrapper)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error outside of aws", func() {
+			err := command.CheckFastFails([]string{}, storage.State{IAAS: "gcp"})
+			Expect(err).To(MatchError("bootstrap-account is only supported for aws environments"))
+		})
+
+		It("does not need an existing environment", func() {
+			err := command.CheckFastFails([]string{}, storage.State{IAAS: "aws"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Execute", func() {
+		It("bootstraps the account", func() {
+			err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "aws"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accountBootstrapper.BootstrapAccountCall.CallCount).To(Equal(1))
+		})
+
+		It("returns the error when bootstrapping fails", func() {
+			accountBootstrapper.BootstrapAccountCall.Returns.Error = errors.New("AccessDenied")
+
+			err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "aws"})
+			Expect(err).To(MatchError("AccessDenied"))
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// BOSHDeploymentVars prints the vars of the director with the credentials of
+// the iaas, for users that run bosh create-env of the bosh-deployment repo
+// against the infrastructure of bbl themselves.
+type BOSHDeploymentVars struct {
+	output                   OutputFormatter
+	stateValidator           stateValidator
+	terraformManager         terraformManager
+	boshDeploymentVarsGetter boshDeploymentVarsGetter
+}
+
+type boshDeploymentVarsGetter interface {
+	GetBOSHDeploymentVars(bblState storage.State, terraformOutputs terraform.Outputs) string
+}
+
+func NewBOSHDeploymentVars(output OutputFormatter, stateValidator stateValidator, terraformManager terraformManager, boshDeploymentVarsGetter boshDeploymentVarsGetter) BOSHDeploymentVars {
+	return BOSHDeploymentVars{
+		output:                   output,
+		stateValidator:           stateValidator,
+		terraformManager:         terraformManager,
+		boshDeploymentVarsGetter: boshDeploymentVarsGetter,
+	}
+}
+
+func (b BOSHDeploymentVars) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := b.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b BOSHDeploymentVars) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	outputs, err := b.terraformManager.GetOutputs()
+	if err != nil {
+		return err
+	}
+
+	vars := b.boshDeploymentVarsGetter.GetBOSHDeploymentVars(state, outputs)
+
+	if !b.output.JSON() {
+		b.output.Printf("%s", vars)
+		return nil
+	}
+
+	var values map[string]interface{}
+	err = yaml.Unmarshal([]byte(vars), &values)
+	if err != nil {
+		return fmt.Errorf("Parse bosh deployment vars: %s", err) //not tested
+	}
+
+	return b.output.PrintJSON(jsonValue(values))
+}
+
+// jsonValue turns the maps of parsed yaml, whose keys json cannot marshal,
+// into maps with string keys.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		values := map[string]interface{}{}
+		for key, item := range v {
+			values[key] = jsonValue(item)
+		}
+		return values
+	case map[interface{}]interface{}:
+		values := map[string]interface{}{}
+		for key, item := range v {
+			values[fmt.Sprintf("%v", key)] = jsonValue(item)
+		}
+		return values
+	case []interface{}:
+		items := []interface{}{}
+		for _, item := range v {
+			items = append(items, jsonValue(item))
+		}
+		return items
+	default:
+		return v
+	}
+}
@@ -0,0 +1,78 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BOSHDeploymentVars", func() {
+	var (
+		command          commands.BOSHDeploymentVars
+		stateValidator   *fakes.StateValidator
+		logger           *fakes.Logger
+		terraformManager *fakes.TerraformManager
+		boshManager      *fakes.BOSHManager
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		logger = &fakes.Logger{}
+		terraformManager = &fakes.TerraformManager{}
+		boshManager = &fakes.BOSHManager{}
+		boshManager.GetBOSHDeploymentVarsCall.Returns.Vars = "access_key_id: some-access-key-id\ninternal_az_subnet_id_mapping:\n  us-east-1a: subnet-1\n"
+		command = commands.NewBOSHDeploymentVars(commands.NewOutputFormatter(logger, false), stateValidator, terraformManager, boshManager)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when state validation fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("state validation failed")
+
+			err := command.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("state validation failed"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints the vars of the director with the credentials", func() {
+			outputs := terraform.Outputs{Map: map[string]interface{}{"subnet_id": "some-subnet-id"}}
+			terraformManager.GetOutputsCall.Returns.Outputs = outputs
+			state := storage.State{IAAS: "aws"}
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshManager.GetBOSHDeploymentVarsCall.Receives.State).To(Equal(state))
+			Expect(boshManager.GetBOSHDeploymentVarsCall.Receives.TerraformOutputs).To(Equal(outputs))
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"access_key_id: some-access-key-id\ninternal_az_subnet_id_mapping:\n  us-east-1a: subnet-1\n",
+			}))
+		})
+
+		It("prints the vars as json with --json", func() {
+			command = commands.NewBOSHDeploymentVars(commands.NewOutputFormatter(logger, true), stateValidator, terraformManager, boshManager)
+
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+				"access_key_id": "some-access-key-id",
+				"internal_az_subnet_id_mapping": {"us-east-1a": "subnet-1"}
+			}`))
+		})
+
+		It("returns an error when the outputs cannot be read", func() {
+			terraformManager.GetOutputsCall.Returns.Error = errors.New("pineapple")
+
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).To(MatchError("pineapple"))
+		})
+	})
+})
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -13,11 +15,21 @@ type FilteredDeleter interface {
 
 type CleanupLeftovers struct {
 	deleter FilteredDeleter
+	lister  FilteredDeleter
+	logger  leftoversLogger
 }
 
-func NewCleanupLeftovers(deleter FilteredDeleter) CleanupLeftovers {
+type leftoversLogger interface {
+	Printf(string, ...interface{})
+	Println(string)
+	Prompt(string) bool
+}
+
+func NewCleanupLeftovers(deleter, lister FilteredDeleter, logger leftoversLogger) CleanupLeftovers {
 	return CleanupLeftovers{
 		deleter: deleter,
+		lister:  lister,
+		logger:  logger,
 	}
 }
 
@@ -25,10 +37,14 @@ func (l CleanupLeftovers) CheckFastFails(subcommandFlags []string, state storage
 	return nil
 }
 
-func (l CleanupLeftovers) Execute(subcommandFlags []string, state storage.State) error {
-	var filter string
+func (l CleanupLeftovers) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	var (
+		filter string
+		dryRun bool
+	)
 	f := flags.New("cleanup-leftovers")
 	f.String(&filter, "filter", "")
+	f.Bool(&dryRun, "dry-run", false)
 
 	err := f.Parse(subcommandFlags)
 	if err != nil {
@@ -41,9 +57,52 @@ func (l CleanupLeftovers) Execute(subcommandFlags []string, state storage.State)
 		return nil
 	}
 
+	if dryRun {
+		err = l.lister.Delete(filter)
+		if err != nil {
+			return err
+		}
+		l.logger.Println("Nothing was deleted. Run bbl cleanup-leftovers without --dry-run to delete these resources.")
+		return nil
+	}
+
 	return l.deleter.Delete(filter)
 }
 
 func (l CleanupLeftovers) Usage() string {
 	return fmt.Sprintf("%s%s%s", CleanupLeftoversCommandUsage, requiresCredentials, Credentials)
 }
+
+// leftoversPrompt matches the question the leftovers ask before deleting each
+// resource, such as "Are you sure you want to delete key pair some-env?".
+var leftoversPrompt = regexp.MustCompile(`^Are you sure you want to (\w+) (.+)\?$`)
+
+// LeftoversLister is the logger of the leftovers of bbl cleanup-leftovers
+// --dry-run. It prints each resource that would be deleted instead of asking
+// to delete it, and declines, so that none is.
+type LeftoversLister struct {
+	logger leftoversLogger
+}
+
+func NewLeftoversLister(logger leftoversLogger) LeftoversLister {
+	return LeftoversLister{
+		logger: logger,
+	}
+}
+
+func (l LeftoversLister) Printf(message string, a ...interface{}) {
+	l.logger.Printf(message, a...)
+}
+
+func (l LeftoversLister) Println(message string) {
+	l.logger.Println(message)
+}
+
+func (l LeftoversLister) Prompt(message string) bool {
+	if match := leftoversPrompt.FindStringSubmatch(message); match != nil {
+		l.logger.Println(fmt.Sprintf("Would %s %s.", match[1], match[2]))
+	} else {
+		l.logger.Println(message)
+	}
+	return false
+}
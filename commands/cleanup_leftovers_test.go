@@ -1,6 +1,8 @@
 package commands_test
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -13,31 +15,68 @@ var _ = Describe("CleanupLeftovers", func() {
 	var (
 		filter  string
 		deleter *fakes.FilteredDeleter
+		lister  *fakes.FilteredDeleter
+		logger  *fakes.Logger
 		cleanup commands.CleanupLeftovers
 	)
 
 	BeforeEach(func() {
 		filter = "banana"
 		deleter = &fakes.FilteredDeleter{}
-		cleanup = commands.NewCleanupLeftovers(deleter)
+		lister = &fakes.FilteredDeleter{}
+		logger = &fakes.Logger{}
+		cleanup = commands.NewCleanupLeftovers(deleter, lister, logger)
 	})
 
 	Describe("Execute", func() {
 		It("calls delete on leftovers with the filter", func() {
-			err := cleanup.Execute([]string{"--filter", filter}, storage.State{})
+			err := cleanup.Execute(context.Background(), []string{"--filter", filter}, storage.State{})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(deleter.DeleteCall.CallCount).To(Equal(1))
 			Expect(deleter.DeleteCall.Receives.Filter).To(Equal(filter))
 		})
 
+		Context("when --dry-run is passed", func() {
+			It("lists the leftovers with the filter without deleting them", func() {
+				err := cleanup.Execute(context.Background(), []string{"--filter", filter, "--dry-run"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(lister.DeleteCall.CallCount).To(Equal(1))
+				Expect(lister.DeleteCall.Receives.Filter).To(Equal(filter))
+				Expect(deleter.DeleteCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"Nothing was deleted. Run bbl cleanup-leftovers without --dry-run to delete these resources."}))
+			})
+		})
+
 		Context("when parsing flags throws an error", func() {
 			It("returns a helpful message", func() {
-				err := cleanup.Execute([]string{"--filter"}, storage.State{})
+				err := cleanup.Execute(context.Background(), []string{"--filter"}, storage.State{})
 				Expect(err).To(MatchError(ContainSubstring("Parsing cleanup-leftovers args: flag needs an argument")))
 
 				Expect(deleter.DeleteCall.CallCount).To(Equal(0))
 			})
 		})
 	})
+
+	Describe("LeftoversLister", func() {
+		var lister commands.LeftoversLister
+
+		BeforeEach(func() {
+			lister = commands.NewLeftoversLister(logger)
+		})
+
+		It("prints what would be deleted and declines", func() {
+			proceed := lister.Prompt("Are you sure you want to delete key pair some-env-keypair?")
+			Expect(proceed).To(BeFalse())
+			Expect(logger.PromptCall.CallCount).To(Equal(0))
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"Would delete key pair some-env-keypair."}))
+		})
+
+		It("prints other questions as they are", func() {
+			proceed := lister.Prompt("Delete everything?")
+			Expect(proceed).To(BeFalse())
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"Delete everything?"}))
+		})
+	})
 })
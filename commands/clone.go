@@ -0,0 +1,338 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+type stateReader interface {
+	GetState(dir string) (storage.State, error)
+}
+
+type cloneStore interface {
+	GetStateDir() string
+	GetTerraformDir() (string, error)
+	GetCloudConfigDir() (string, error)
+}
+
+type cloneFS interface {
+	fileio.DirReader
+	fileio.FileReader
+	fileio.FileWriter
+}
+
+type Clone struct {
+	stateReader      stateReader
+	plan             plan
+	up               up
+	terraformManager terraformManager
+	stateStore       cloneStore
+	fs               cloneFS
+	logger           logger
+}
+
+type cloneConfig struct {
+	from string
+	name string
+}
+
+func NewClone(stateReader stateReader, plan plan, up up, terraformManager terraformManager, stateStore cloneStore, fs cloneFS, logger logger) Clone {
+	return Clone{
+		stateReader:      stateReader,
+		plan:             plan,
+		up:               up,
+		terraformManager: terraformManager,
+		stateStore:       stateStore,
+		fs:               fs,
+		logger:           logger,
+	}
+}
+
+func (c Clone) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := parseCloneArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	source, err := c.readSource(config)
+	if err != nil {
+		return err
+	}
+
+	if state.EnvID != "" {
+		return fmt.Errorf("The state directory already contains the environment %s. Clone into an empty state directory.", state.EnvID)
+	}
+
+	if source.IAAS != state.IAAS {
+		return fmt.Errorf("%s is on %s, but the new environment is on %s. Run bbl with --iaas %s.", source.EnvID, source.IAAS, state.IAAS, source.IAAS)
+	}
+
+	if acmCertificateARN.MatchString(source.LB.CertARN) && source.AWS.Region != state.AWS.Region {
+		return fmt.Errorf("%s uses an ACM certificate, which can only be used in %s. Clone into %s, or create the load balancer in %s with bbl plan --lb-cert-arn.", source.EnvID, source.AWS.Region, source.AWS.Region, state.AWS.Region)
+	}
+
+	err = c.plan.CheckFastFails(cloneUpArgs(config), state)
+	if err != nil {
+		return fmt.Errorf("plan: %s", err)
+	}
+
+	return nil
+}
+
+// Execute provisions a new environment with the configuration of the source
+// environment: its load balancers and the terraform, cloud config and
+// create-env overrides in its state directory. Secrets, such as the director
+// credentials and the terraform state, are generated anew.
+func (c Clone) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseCloneArgs(args)
+	if err != nil {
+		return err
+	}
+
+	source, err := c.readSource(config)
+	if err != nil {
+		return err
+	}
+
+	planConfig := PlanConfig{Name: config.name, LB: source.LB, NoDirector: source.NoDirector, SSHCA: source.SSHCA, Minimal: source.AWS.Minimal, VPCCIDR: source.AWS.VPCCIDR, TrustedCACerts: source.TrustedCACerts}
+	if source.DirectorPorts != nil {
+		planConfig.DirectorPorts = *source.DirectorPorts
+	}
+	planConfig.Lite = source.AWS.Lite
+	planConfig.Cheap = source.AWS.Cheap
+	planConfig.SpotBidPrice = source.AWS.SpotBidPrice
+	planConfig.DualStack = source.AWS.DualStack
+
+	// A source that still routes through its NAT instance gets a clone
+	// that does too, rather than the NAT gateways of new environments.
+	if !source.AWS.Minimal && !source.AWS.Lite && !source.AWS.Cheap {
+		planConfig.NATInstance = !source.AWS.NATGateway
+	}
+	if source.DirectorVM != nil {
+		planConfig.DirectorVM = *source.DirectorVM
+	}
+	if source.DirectorSyslog != nil {
+		planConfig.DirectorSyslog = *source.DirectorSyslog
+	}
+	if source.ArtifactOverrides != nil {
+		planConfig.ArtifactOverrides = *source.ArtifactOverrides
+	}
+	planConfig.ArtifactsDir = source.ArtifactsDir
+	planConfig.OpsFiles = source.DirectorOpsFiles
+	planConfig.VarsFiles = source.DirectorVarsFiles
+	planConfig.ReservedCIDRs = source.AWS.ReservedCIDRs
+	planConfig.Tags = sortedAnnotations(source.Annotations)
+	planConfig.DirectorAllowedCIDRs = source.AWS.DirectorAllowedCIDRs
+	planConfig.LBAllowedCIDRs = source.AWS.LBAllowedCIDRs
+
+	// The SNI domains keep their certificates, which are IAM server
+	// certificates and so can be used in any region.
+	for _, sni := range source.AWS.SNIDomains {
+		planConfig.SNIDomains = append(planConfig.SNIDomains, [2]string{sni.Domain, sni.Certificate})
+		if certificate := source.AWS.CertificateNamed(sni.Certificate); certificate != nil && state.AWS.CertificateNamed(sni.Certificate) == nil {
+			state.AWS.Certificates = append(state.AWS.Certificates, storage.ServerCertificate{Name: certificate.Name, ARN: certificate.ARN, Fingerprint: certificate.Fingerprint})
+		}
+	}
+
+	// The clone gets a bucket of its own for its blobstore.
+	planConfig.S3Blobstore = source.AWS.S3Blobstore
+
+	// Availability zones are specific to a region.
+	if source.AWS.Region == state.AWS.Region {
+		planConfig.AZs = source.AWS.AZs
+		planConfig.SubnetSizes = source.AWS.SubnetSizes
+	}
+
+	state, err = c.plan.InitializePlan(planConfig, state)
+	if err != nil {
+		return fmt.Errorf("Initialize plan: %s", err)
+	}
+
+	err = c.copyOverrides(config.from)
+	if err != nil {
+		return fmt.Errorf("Copy overrides: %s", err)
+	}
+
+	// Up re-initializes plans saved at an older schema, which would drop the
+	// load balancers taken from the source environment.
+	state.Version = storage.STATE_SCHEMA
+
+	err = c.up.Execute(ctx, []string{}, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+
+	newOutputs, err := c.terraformManager.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("Get terraform outputs: %s", err)
+	}
+
+	oldOutputs := terraform.Outputs{}
+	tfState, err := c.fs.ReadFile(filepath.Join(config.from, "vars", "terraform.tfstate"))
+	if err == nil {
+		oldOutputs, _ = terraform.OutputsFromState(tfState)
+	}
+
+	c.report(source, state, oldOutputs, newOutputs)
+
+	return nil
+}
+
+func (c Clone) readSource(config cloneConfig) (storage.State, error) {
+	source, err := c.stateReader.GetState(config.from)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Read state in %s: %s", config.from, err)
+	}
+
+	if source.EnvID == "" {
+		return storage.State{}, fmt.Errorf("%s does not contain a bbl environment", config.from)
+	}
+
+	return source, nil
+}
+
+// copyOverrides copies the files operators add to a state directory to
+// customize it. The files bbl generates are regenerated by the plan instead.
+func (c Clone) copyOverrides(from string) error {
+	err := c.copyFiles(from, c.stateStore.GetStateDir(), func(name string) bool {
+		return strings.HasSuffix(name, "-override.sh")
+	})
+	if err != nil {
+		return err
+	}
+
+	terraformDir, err := c.stateStore.GetTerraformDir()
+	if err != nil {
+		return err
+	}
+
+	err = c.copyFiles(filepath.Join(from, "terraform"), terraformDir, func(name string) bool {
+		return filepath.Ext(name) == ".tf" && name != "bbl-template.tf"
+	})
+	if err != nil {
+		return err
+	}
+
+	cloudConfigDir, err := c.stateStore.GetCloudConfigDir()
+	if err != nil {
+		return err
+	}
+
+	return c.copyFiles(filepath.Join(from, "cloud-config"), cloudConfigDir, func(name string) bool {
+		return name != "cloud-config.yml" && name != "ops.yml"
+	})
+}
+
+func (c Clone) copyFiles(src, dst string, include func(name string) bool) error {
+	entries, err := c.fs.ReadDir(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !include(entry.Name()) {
+			continue
+		}
+
+		contents, err := c.fs.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		err = c.fs.WriteFile(filepath.Join(dst, entry.Name()), contents, entry.Mode())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c Clone) report(source, state storage.State, oldOutputs, newOutputs terraform.Outputs) {
+	c.logger.Printf("Cloned %s (%s) to %s (%s).\n", source.EnvID, stateRegion(source), state.EnvID, stateRegion(state))
+
+	names := []string{}
+	for name := range newOutputs.Map {
+		if _, ok := newOutputs.Map[name].(string); !ok || isSecretOutput(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldValue := oldOutputs.GetString(name)
+		if oldValue == "" {
+			oldValue = "-"
+		}
+		c.logger.Printf("  %s: %s -> %s\n", name, oldValue, newOutputs.GetString(name))
+	}
+}
+
+// sortedAnnotations returns the annotations as key=value pairs in the order
+// of their keys, or nil when there are none.
+func sortedAnnotations(annotations map[string]string) [][2]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	keys := []string{}
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := [][2]string{}
+	for _, key := range keys {
+		pairs = append(pairs, [2]string{key, annotations[key]})
+	}
+	return pairs
+}
+
+func isSecretOutput(name string) bool {
+	for _, secret := range []string{"private_key", "password", "secret"} {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCloneArgs(args []string) (cloneConfig, error) {
+	var config cloneConfig
+
+	cloneFlags := flags.New("clone")
+	cloneFlags.String(&config.from, "from", "")
+	cloneFlags.String(&config.name, "name", "")
+
+	err := cloneFlags.Parse(args)
+	if err != nil {
+		return cloneConfig{}, err
+	}
+
+	if config.from == "" {
+		return cloneConfig{}, errors.New("--from is required")
+	}
+
+	return config, nil
+}
+
+func cloneUpArgs(config cloneConfig) []string {
+	if config.name == "" {
+		return []string{}
+	}
+	return []string{"--name", config.name}
+}
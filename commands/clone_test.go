@@ -0,0 +1,285 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clone", func() {
+	var (
+		stateBootstrap   *fakes.StateBootstrap
+		plan             *fakes.Plan
+		up               *fakes.Up
+		terraformManager *fakes.TerraformManager
+		stateStore       *fakes.StateStore
+		fs               *afero.Afero
+		logger           *fakes.Logger
+		clone            commands.Clone
+
+		source storage.State
+		state  storage.State
+	)
+
+	BeforeEach(func() {
+		stateBootstrap = &fakes.StateBootstrap{}
+		plan = &fakes.Plan{}
+		up = &fakes.Up{}
+		terraformManager = &fakes.TerraformManager{}
+		stateStore = &fakes.StateStore{}
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		logger = &fakes.Logger{}
+		clone = commands.NewClone(stateBootstrap, plan, up, terraformManager, stateStore, fs, logger)
+
+		source = storage.State{
+			IAAS:  "aws",
+			EnvID: "prod",
+			AWS:   storage.AWS{Region: "us-east-1"},
+			LB:    storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key"},
+			BOSH:  storage.BOSH{DirectorPassword: "secret"},
+		}
+		stateBootstrap.GetStateCall.Returns.State = source
+
+		state = storage.State{
+			IAAS: "aws",
+			AWS:  storage.AWS{Region: "us-west-2"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("reads the source environment and calls plan.CheckFastFails", func() {
+			err := clone.CheckFastFails([]string{"--from", "/prod", "--name", "staging"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateBootstrap.GetStateCall.Receives.Dir).To(Equal("/prod"))
+			Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{"--name", "staging"}))
+		})
+
+		It("requires --from", func() {
+			err := clone.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--from is required"))
+		})
+
+		It("returns an error when the source has no environment", func() {
+			stateBootstrap.GetStateCall.Returns.State = storage.State{}
+			err := clone.CheckFastFails([]string{"--from", "/prod"}, state)
+			Expect(err).To(MatchError("/prod does not contain a bbl environment"))
+		})
+
+		It("returns an error when the source state cannot be read", func() {
+			stateBootstrap.GetStateCall.Returns.Error = errors.New("peach")
+			err := clone.CheckFastFails([]string{"--from", "/prod"}, state)
+			Expect(err).To(MatchError("Read state in /prod: peach"))
+		})
+
+		It("returns an error when the state directory already has an environment", func() {
+			state.EnvID = "existing"
+			err := clone.CheckFastFails([]string{"--from", "/prod"}, state)
+			Expect(err).To(MatchError("The state directory already contains the environment existing. Clone into an empty state directory."))
+		})
+
+		It("returns an error when the iaas differs", func() {
+			state.IAAS = "gcp"
+			err := clone.CheckFastFails([]string{"--from", "/prod"}, state)
+			Expect(err).To(MatchError("prod is on aws, but the new environment is on gcp. Run bbl with --iaas aws."))
+		})
+
+		It("returns an error when an ACM certificate would be used in another region", func() {
+			source.LB = storage.LB{Type: "cf", CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/some-cert"}
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.CheckFastFails([]string{"--from", "/prod"}, state)
+			Expect(err).To(MatchError("prod uses an ACM certificate, which can only be used in us-east-1. Clone into us-east-1, or create the load balancer in us-west-2 with bbl plan --lb-cert-arn."))
+		})
+
+		It("clones a load balancer with an IAM server certificate into another region", func() {
+			source.LB = storage.LB{Type: "cf", CertARN: "arn:aws:iam::123456789012:server-certificate/some-cert"}
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.CheckFastFails([]string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			stateStore.GetStateDirCall.Returns.Directory = "/staging"
+			stateStore.GetTerraformDirCall.Returns.Directory = "/staging/terraform"
+			stateStore.GetCloudConfigDirCall.Returns.Directory = "/staging/cloud-config"
+
+			Expect(fs.MkdirAll("/prod/terraform", os.ModePerm)).To(Succeed())
+			Expect(fs.MkdirAll("/prod/cloud-config", os.ModePerm)).To(Succeed())
+			Expect(fs.MkdirAll("/prod/vars", os.ModePerm)).To(Succeed())
+			Expect(fs.MkdirAll("/staging/terraform", os.ModePerm)).To(Succeed())
+			Expect(fs.MkdirAll("/staging/cloud-config", os.ModePerm)).To(Succeed())
+			Expect(fs.WriteFile("/prod/create-director-override.sh", []byte("custom create-env"), 0755)).To(Succeed())
+			Expect(fs.WriteFile("/prod/create-director.sh", []byte("generated create-env"), 0755)).To(Succeed())
+			Expect(fs.WriteFile("/prod/terraform/bbl-template.tf", []byte("generated template"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/prod/terraform/my-override.tf", []byte("custom template"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/prod/cloud-config/ops.yml", []byte("generated ops"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/prod/cloud-config/my-ops.yml", []byte("custom ops"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/prod/vars/director-vars-store.yml", []byte("secrets"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/prod/vars/terraform.tfstate", []byte(`{"version": 3, "modules": [{"path": ["root"], "outputs": {
+				"vpc_id": {"value": "vpc-old"},
+				"internal_az_subnet_id_mapping": {"value": {"us-east-1a": "subnet-old"}}
+			}}]}`), 0644)).To(Succeed())
+
+			plan.InitializePlanCall.Returns.State = storage.State{
+				IAAS:  "aws",
+				EnvID: "staging",
+				AWS:   storage.AWS{Region: "us-west-2"},
+				LB:    source.LB,
+			}
+			terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{
+				"vpc_id":         "vpc-new",
+				"bosh_subnet_id": "subnet-new",
+				"private_key":    "some-private-key",
+			}}
+		})
+
+		It("initializes the plan from the source, copies overrides, brings it up and reports the new resources", func() {
+			err := clone.Execute(context.Background(), []string{"--from", "/prod", "--name", "staging"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan).To(Equal(commands.PlanConfig{Name: "staging", LB: source.LB, NATInstance: true}))
+			Expect(plan.InitializePlanCall.Receives.State).To(Equal(state))
+
+			contents, err := fs.ReadFile("/staging/create-director-override.sh")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("custom create-env"))
+
+			contents, err = fs.ReadFile("/staging/terraform/my-override.tf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("custom template"))
+
+			contents, err = fs.ReadFile("/staging/cloud-config/my-ops.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("custom ops"))
+
+			for _, generated := range []string{"/staging/create-director.sh", "/staging/terraform/bbl-template.tf", "/staging/cloud-config/ops.yml", "/staging/vars/director-vars-store.yml"} {
+				_, err = fs.Stat(generated)
+				Expect(os.IsNotExist(err)).To(BeTrue(), generated)
+			}
+
+			Expect(up.ExecuteCall.CallCount).To(Equal(1))
+			Expect(up.ExecuteCall.Receives.State.EnvID).To(Equal("staging"))
+			Expect(up.ExecuteCall.Receives.State.Version).To(Equal(storage.STATE_SCHEMA))
+
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"Cloned prod (us-east-1) to staging (us-west-2).\n",
+				"  bosh_subnet_id: - -> subnet-new\n",
+				"  vpc_id: vpc-old -> vpc-new\n",
+			}))
+		})
+
+		It("keeps the blocks that the source allows to reach the director and load balancers", func() {
+			source.AWS.DirectorAllowedCIDRs = []string{"10.0.0.0/8"}
+			source.AWS.LBAllowedCIDRs = []string{"192.168.0.0/16", "172.16.0.0/12"}
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan.DirectorAllowedCIDRs).To(Equal([]string{"10.0.0.0/8"}))
+			Expect(plan.InitializePlanCall.Receives.Plan.LBAllowedCIDRs).To(Equal([]string{"192.168.0.0/16", "172.16.0.0/12"}))
+		})
+
+		It("keeps the NAT instance of a source that routes through one", func() {
+			err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan.NATInstance).To(BeTrue())
+		})
+
+		It("plans NAT gateways for a source that routes through them", func() {
+			source.AWS.NATGateway = true
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan.NATInstance).To(BeFalse())
+		})
+
+		It("plans no NAT for a minimal source", func() {
+			source.AWS.Minimal = true
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan.Minimal).To(BeTrue())
+			Expect(plan.InitializePlanCall.Receives.Plan.NATInstance).To(BeFalse())
+		})
+
+		It("tags the new environment with the annotations of the source", func() {
+			source.Annotations = map[string]string{"team": "platform", "cost-center": "42"}
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan.Tags).To(Equal([][2]string{{"cost-center", "42"}, {"team", "platform"}}))
+		})
+
+		It("serves the SNI domains of the source with their certificates", func() {
+			source.AWS.Certificates = []storage.ServerCertificate{
+				{Name: "apps", ARN: "arn:aws:iam::123456789012:server-certificate/apps", Fingerprint: "ab:cd", LBs: []string{"cf-router"}},
+				{Name: "unused", ARN: "arn:aws:iam::123456789012:server-certificate/unused"},
+			}
+			source.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "apps"}}
+			stateBootstrap.GetStateCall.Returns.State = source
+
+			err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.InitializePlanCall.Receives.Plan.SNIDomains).To(Equal([][2]string{{"apps.example.com", "apps"}}))
+			Expect(plan.InitializePlanCall.Receives.State.AWS.Certificates).To(Equal([]storage.ServerCertificate{
+				{Name: "apps", ARN: "arn:aws:iam::123456789012:server-certificate/apps", Fingerprint: "ab:cd"},
+			}))
+		})
+
+		Context("when the plan cannot be initialized", func() {
+			BeforeEach(func() {
+				plan.InitializePlanCall.Returns.Error = errors.New("apricot")
+			})
+
+			It("returns the error", func() {
+				err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+				Expect(err).To(MatchError("Initialize plan: apricot"))
+				Expect(up.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when up fails", func() {
+			BeforeEach(func() {
+				up.ExecuteCall.Returns.Error = errors.New("nectarine")
+			})
+
+			It("returns the error", func() {
+				err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+				Expect(err).To(MatchError("up: nectarine"))
+			})
+		})
+
+		Context("when the terraform outputs cannot be read", func() {
+			BeforeEach(func() {
+				terraformManager.GetOutputsCall.Returns.Error = errors.New("plum")
+			})
+
+			It("returns the error", func() {
+				err := clone.Execute(context.Background(), []string{"--from", "/prod"}, state)
+				Expect(err).To(MatchError("Get terraform outputs: plum"))
+			})
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type CloudConfig struct {
+	logger             logger
+	stateValidator     stateValidator
+	cloudConfigManager cloudConfigManager
+}
+
+func NewCloudConfig(logger logger, stateValidator stateValidator, cloudConfigManager cloudConfigManager) CloudConfig {
+	return CloudConfig{
+		logger:             logger,
+		stateValidator:     stateValidator,
+		cloudConfigManager: cloudConfigManager,
+	}
+}
+
+func (c CloudConfig) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.NoDirector {
+		return errors.New("Error BBL does not manage this director.")
+	}
+
+	return nil
+}
+
+// Execute prints the cloud config that bbl up uploads to the director. The
+// vars are generated from the terraform outputs if up has not written them.
+func (c CloudConfig) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	if !c.cloudConfigManager.IsPresentCloudConfig() {
+		return errors.New("The state directory does not contain a cloud config. Run bbl plan to create it.")
+	}
+
+	if !c.cloudConfigManager.IsPresentCloudConfigVars() {
+		err := c.cloudConfigManager.GenerateVars(state)
+		if err != nil {
+			return err
+		}
+	}
+
+	cloudConfig, err := c.cloudConfigManager.Interpolate()
+	if err != nil {
+		return fmt.Errorf("Interpolate cloud config: %s", err)
+	}
+
+	c.logger.Println(cloudConfig)
+	return nil
+}
@@ -0,0 +1,108 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CloudConfig", func() {
+	var (
+		logger             *fakes.Logger
+		stateValidator     *fakes.StateValidator
+		cloudConfigManager *fakes.CloudConfigManager
+		command            commands.CloudConfig
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stateValidator = &fakes.StateValidator{}
+		cloudConfigManager = &fakes.CloudConfigManager{}
+		cloudConfigManager.IsPresentCloudConfigCall.Returns.IsPresent = true
+		cloudConfigManager.IsPresentCloudConfigVarsCall.Returns.IsPresent = true
+		cloudConfigManager.InterpolateCall.Returns.CloudConfig = "some-cloud-config"
+
+		command = commands.NewCloudConfig(logger, stateValidator, cloudConfigManager)
+
+		state = storage.State{EnvID: "some-env-id"}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when bbl does not manage the director", func() {
+			state.NoDirector = true
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("Error BBL does not manage this director."))
+		})
+
+		Context("when the state validator returns an error", func() {
+			BeforeEach(func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("fig")
+			})
+
+			It("returns the error", func() {
+				err := command.CheckFastFails([]string{}, state)
+				Expect(err).To(MatchError("fig"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints the interpolated cloud config", func() {
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cloudConfigManager.GenerateVarsCall.CallCount).To(Equal(0))
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-cloud-config"}))
+		})
+
+		It("generates the vars when bbl up has not written them", func() {
+			cloudConfigManager.IsPresentCloudConfigVarsCall.Returns.IsPresent = false
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cloudConfigManager.GenerateVarsCall.CallCount).To(Equal(1))
+			Expect(cloudConfigManager.GenerateVarsCall.Receives.State).To(Equal(state))
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-cloud-config"}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the state directory has no cloud config", func() {
+				cloudConfigManager.IsPresentCloudConfigCall.Returns.IsPresent = false
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("The state directory does not contain a cloud config. Run bbl plan to create it."))
+			})
+
+			It("returns an error when the vars cannot be generated", func() {
+				cloudConfigManager.IsPresentCloudConfigVarsCall.Returns.IsPresent = false
+				cloudConfigManager.GenerateVarsCall.Returns.Error = errors.New("lime")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("lime"))
+			})
+
+			It("returns an error when the cloud config cannot be interpolated", func() {
+				cloudConfigManager.InterpolateCall.Returns.Error = errors.New("kiwi")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Interpolate cloud config: kiwi"))
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+})
@@ -1,9 +1,13 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type Command interface {
 	CheckFastFails(subcommandFlags []string, state storage.State) error
-	Execute(subcommandFlags []string, state storage.State) error
+	Execute(ctx context.Context, subcommandFlags []string, state storage.State) error
 	Usage() string
 }
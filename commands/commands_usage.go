@@ -7,6 +7,11 @@ const (
   --aws-access-key-id        AWS Access Key ID              env: $BBL_AWS_ACCESS_KEY_ID
   --aws-secret-access-key    AWS Secret Access Key          env: $BBL_AWS_SECRET_ACCESS_KEY
   --aws-region               AWS Region                     env: $BBL_AWS_REGION
+  --aws-session-token        AWS Session Token              env: $BBL_AWS_SESSION_TOKEN
+  --aws-profile              AWS shared config profile      env: $BBL_AWS_PROFILE
+  --aws-role-arn             AWS IAM role to assume         env: $BBL_AWS_ROLE_ARN
+  --aws-mfa-serial           AWS MFA device serial or ARN   env: $BBL_AWS_MFA_SERIAL
+  --aws-mfa-token-code       AWS MFA code, or prompted      env: $BBL_AWS_MFA_TOKEN_CODE
 
   --gcp-service-account-key  GCP Service Access Key to use  env: $BBL_GCP_SERVICE_ACCOUNT_KEY
   --gcp-region               GCP Region to use              env: $BBL_GCP_REGION
@@ -38,34 +43,177 @@ const (
   --lb-cert                  Path to SSL certificate (supported when type="cf")
   --lb-key                   Path to SSL certificate key (supported when type="cf")
   --lb-chain                 Path to SSL certificate chain (supported when iaas="aws")
-  --lb-domain                Creates a DNS zone and records for the given domain (supported when type="cf")`
+  --lb-cert-arn              ARN of an AWS Certificate Manager or IAM server certificate to use instead of --lb-cert and --lb-key (supported when iaas="aws")
+  --lb-acm-certificate       Requests a certificate of --lb-domain and its wildcard from AWS Certificate Manager, validated with a record in its DNS zone (supported when iaas="aws")
+  --lb-domain                Creates a DNS zone and records for the given domain (supported when type="cf")
+  --lb-dns-role-arn          IAM role to assume for the DNS zone and records, when the domain is managed in another AWS account (supported when iaas="aws")
+  --lb-dns-zone-id           Existing Route53 hosted zone for the records of --lb-domain, which bbl neither creates nor deletes (supported when iaas="aws")
+  --lb-apps-domain           Apps domain whose wildcard record bbl points at the cf router as well (supported when iaas="aws")
+  --lb-sni                   Serves a domain with a certificate of bbl create-certificate, on a load balancer of its own: domain=certificate, or domain= to remove it (repeatable, supported when iaas="aws")
+  --lb-internal              Creates the load balancers on the internal subnets, reachable only from inside the VPC (supported when iaas="aws")
+  --lb-elbv2                 Creates an application load balancer for the cf router and a network load balancer for the ssh proxy instead of classic ELBs (supported when iaas="aws")
+  --lb-allowed-cidrs         Comma-separated blocks that may reach the load balancers instead of anywhere (supported when iaas="aws")
+  --lb-health-check          Health check of the cf router load balancers: target=HTTP:8080/health,interval=10,timeout=5,healthy-threshold=2,unhealthy-threshold=3 (supported when iaas="aws")
+  --lb-check-workloads       Warns when the deployments of the director do not use the vm_extensions of the load balancers yet (optional)
+  --lb-ignore-workloads      Removes the load balancers even though deployments of the director still use their vm_extensions (optional)`
 
 	PlanCommandUsage = `Populates a state directory with the latest config without applying it
 
   --iaas                     IAAS to deploy your BOSH director onto: "aws", "azure", "gcp", "vsphere"   env: $BBL_IAAS
   --name                     Name to assign to your BOSH director (optional)                            env: $BBL_ENV_NAME
+  --no-director              Provisions only the infrastructure, for a director you deploy yourself (optional)
+  --ssh-ca                   Makes the jumpbox and director trust an SSH certificate authority, for certificates from bbl ssh-cert issue (optional)
+  --trusted-ca-certs         Path to PEM encoded CA certificates that the director installs on the VMs it creates (optional)
+  --director-disk-size       Size in GB of the persistent disk of the director (optional, default: 64)
+  --bosh-release-url         URL of a BOSH release to deploy the director with instead of the pinned one, with --bosh-release-sha1 (optional)
+  --cpi-release-url          URL of a CPI release to deploy the director with instead of the pinned one, with --cpi-release-sha1 (optional)
+  --stemcell-url             URL of a stemcell to deploy the director on instead of the pinned one, with --stemcell-sha1 (optional)
+  --artifacts-dir            Installs the releases and stemcells of the jumpbox and director from the tarballs of bbl download-artifacts, without internet access (optional)
+  --ops-file                 Path to a go-patch ops file that is applied to the director manifest after the ones of bbl, can be repeated (optional)
+  --vars-file                Path to a YAML file of variables of the ops files, can be repeated (optional)
+  --syslog-address           Forwards the logs of the director, including the audit log of its API, to a syslog server (optional)
+  --syslog-port              Port of the syslog server (optional, default: 514)
+  --syslog-transport         Transport to the syslog server: "tcp", "udp" or "relp" (optional, default: tcp)
+  --azs                      Comma-separated availability zones to use instead of every zone in the region (optional, supported when iaas="aws")
+  --minimal                  Leaves out the NAT instance and gives VMs public IPs, for throwaway environments (optional, supported when iaas="aws")
+  --lite                     Deploys a bosh-lite director, whose warden cpi runs the VMs of deployments as containers on the director, without a NAT instance or load balancers (optional, supported when iaas="aws")
+  --nat-gateway              Routes the internal subnets through a NAT gateway in each availability zone, and replaces the NAT instance of an existing environment on the next up (optional, default for new environments, supported when iaas="aws")
+  --nat-instance             Keeps a NAT instance for a new environment instead of NAT gateways (optional, supported when iaas="aws")
+  --cheap                    Plans a throwaway environment with a small NAT instance, a burstable director and a single availability zone (optional, supported when iaas="aws")
+  --spot-bid-price           Price in USD per hour that the VMs of deployments with the spot vm_extension bid for spot instances, with --cheap (optional, supported when iaas="aws")
+  --dual-stack               Gives the VPC and its subnets IPv6 blocks, and has the application load balancer of the router answer on IPv6 too (optional, supported when iaas="aws")
+  --vpc-cidr                 CIDR block of the VPC, from /16 to /20, that the subnets are carved from (optional, default: 10.0.0.0/16, supported when iaas="aws")
+  --existing-vpc-id          Creates the subnets in an existing VPC instead of creating one, set --vpc-cidr to a free block of it (optional, supported when iaas="aws")
+  --subnet-sizes             Prefix length of the internal subnet of each availability zone, for example: us-east-1a=20,us-east-1b=22 (optional, supported when iaas="aws")
+  --reserved-cidrs           Comma-separated blocks of the VPC that bbl leaves out of its subnets, for example: 10.0.128.0/17 (optional, supported when iaas="aws")
+  --director-allowed-cidrs   Comma-separated blocks that may reach the jumpbox and director instead of anywhere, including the machine bbl runs on (optional, supported when iaas="aws")
+  --director-ports           Ports for the director's internal services, for example: blobstore=25251,nats=4223,registry=25778,mbus=6869 (optional, supported when iaas="aws")
+  --director-instance-type   EC2 instance type of the director, for example: t2.medium (optional, default: m4.xlarge, supported when iaas="aws")
+  --tags                     Tags the aws resources of the environment with key=value, repeatable, key= removes a tag (optional, supported when iaas="aws")
+  --s3-blobstore             Stores the blobs of the director in an S3 bucket that bbl creates, reached with narrowly scoped instance profiles (optional, supported when iaas="aws")
+  --s3-blobstore-bucket      Stores the blobs of the director in an existing S3 bucket instead (optional, supported when iaas="aws")
 `
 
 	UpCommandUsage = `Deploys BOSH director on an IAAS
 
   --iaas                     IAAS to deploy your BOSH director onto: "aws", "azure", "gcp", "vsphere"   env: $BBL_IAAS
   --name                     Name to assign to your BOSH director (optional)                            env: $BBL_ENV_NAME
+  --no-director              Provisions only the infrastructure, for a director you deploy yourself (optional)
+  --ssh-ca                   Makes the jumpbox and director trust an SSH certificate authority, for certificates from bbl ssh-cert issue (optional)
+  --trusted-ca-certs         Path to PEM encoded CA certificates that the director installs on the VMs it creates (optional)
+  --director-disk-size       Size in GB of the persistent disk of the director (optional, default: 64)
+  --bosh-release-url         URL of a BOSH release to deploy the director with instead of the pinned one, with --bosh-release-sha1 (optional)
+  --cpi-release-url          URL of a CPI release to deploy the director with instead of the pinned one, with --cpi-release-sha1 (optional)
+  --stemcell-url             URL of a stemcell to deploy the director on instead of the pinned one, with --stemcell-sha1 (optional)
+  --artifacts-dir            Installs the releases and stemcells of the jumpbox and director from the tarballs of bbl download-artifacts, without internet access (optional)
+  --ops-file                 Path to a go-patch ops file that is applied to the director manifest after the ones of bbl, can be repeated (optional)
+  --vars-file                Path to a YAML file of variables of the ops files, can be repeated (optional)
+  --syslog-address           Forwards the logs of the director, including the audit log of its API, to a syslog server (optional)
+  --syslog-port              Port of the syslog server (optional, default: 514)
+  --syslog-transport         Transport to the syslog server: "tcp", "udp" or "relp" (optional, default: tcp)
+  --azs                      Comma-separated availability zones to use instead of every zone in the region (optional, supported when iaas="aws")
+  --minimal                  Leaves out the NAT instance and gives VMs public IPs, for throwaway environments (optional, supported when iaas="aws")
+  --lite                     Deploys a bosh-lite director, whose warden cpi runs the VMs of deployments as containers on the director, without a NAT instance or load balancers (optional, supported when iaas="aws")
+  --nat-gateway              Routes the internal subnets through a NAT gateway in each availability zone, and replaces the NAT instance of an existing environment on the next up (optional, default for new environments, supported when iaas="aws")
+  --nat-instance             Keeps a NAT instance for a new environment instead of NAT gateways (optional, supported when iaas="aws")
+  --cheap                    Plans a throwaway environment with a small NAT instance, a burstable director and a single availability zone (optional, supported when iaas="aws")
+  --spot-bid-price           Price in USD per hour that the VMs of deployments with the spot vm_extension bid for spot instances, with --cheap (optional, supported when iaas="aws")
+  --dual-stack               Gives the VPC and its subnets IPv6 blocks, and has the application load balancer of the router answer on IPv6 too (optional, supported when iaas="aws")
+  --vpc-cidr                 CIDR block of the VPC, from /16 to /20, that the subnets are carved from (optional, default: 10.0.0.0/16, supported when iaas="aws")
+  --existing-vpc-id          Creates the subnets in an existing VPC instead of creating one, set --vpc-cidr to a free block of it (optional, supported when iaas="aws")
+  --subnet-sizes             Prefix length of the internal subnet of each availability zone, for example: us-east-1a=20,us-east-1b=22 (optional, supported when iaas="aws")
+  --reserved-cidrs           Comma-separated blocks of the VPC that bbl leaves out of its subnets, for example: 10.0.128.0/17 (optional, supported when iaas="aws")
+  --director-allowed-cidrs   Comma-separated blocks that may reach the jumpbox and director instead of anywhere, including the machine bbl runs on (optional, supported when iaas="aws")
+  --director-ports           Ports for the director's internal services, for example: blobstore=25251,nats=4223,registry=25778,mbus=6869 (optional, supported when iaas="aws")
+  --director-instance-type   EC2 instance type of the director, for example: t2.medium (optional, default: m4.xlarge, supported when iaas="aws")
+  --tags                     Tags the aws resources of the environment with key=value, repeatable, key= removes a tag (optional, supported when iaas="aws")
+  --s3-blobstore             Stores the blobs of the director in an S3 bucket that bbl creates, reached with narrowly scoped instance profiles (optional, supported when iaas="aws")
+  --s3-blobstore-bucket      Stores the blobs of the director in an existing S3 bucket instead (optional, supported when iaas="aws")
+  --dry-run                  Prints the changes terraform would make to the infrastructure without making them (optional)
+  --auto-approve             Applies changes to existing infrastructure without asking for confirmation. Also --yes (optional)
+  --bootstrap-account        Creates the service-linked role Elastic Load Balancing needs in fresh accounts first (optional, supported when iaas="aws")
+  --restart                  Runs every step again instead of resuming an interrupted up after the steps it completed (optional)
+  --skip-quota-check         Creates the infrastructure without first checking the limits of the account (optional, supported when iaas="aws")
 `
 
 	DestroyCommandUsage = `Tears down BOSH director infrastructure
 
   [--no-confirm]       Do not ask for confirmation (optional)
-  [--skip-if-missing]  Gracefully exit if there is no state file (optional)`
+  [--skip-if-missing]  Gracefully exit if there is no state file, and keep tearing down if parts of the environment were already deleted (optional)
+  [--discover]         Finds the resources of an environment whose state directory was lost by name, and deletes them after typed confirmation. Requires --env-name
+  [--env-name]         Name of the environment to find with --discover
+  [--force]            Deletes the environment even when its director still has deployments, whose VMs are left behind (optional)`
 
 	CleanupLeftoversCommandUsage = `Cleans up orphaned IAAS resources
 
-  --filter            Only delete resources with this string in their name`
+  --filter            Only delete resources with this string in their name
+  [--dry-run]         Lists the resources that would be deleted without deleting them (optional)`
+
+	MigrateCommandsCommandUsage = `Finds removed bbl commands in scripts and pipelines and prints their replacements
+
+  --dir               Directory to search for scripts and pipelines
+  [--write]           Rewrite the invocations that can be translated exactly`
+
+	CloneCommandUsage = `Creates a new environment with the configuration of an existing one
+
+  --from              State directory of the environment to clone. Its secrets are not copied
+  [--name]            Name for the new environment. A new name is generated if it is not given`
+
+	ApplyCommandUsage = `Converges the environment to the one described in an environment file
+
+  <path>              YAML file describing the environment: iaas, name, region and lb (type, cert, key, chain, cert_arn, domain)
+  [--dry-run]         Prints the operations that would run without running them`
+
+	MigrateRegionCommandUsage = `Moves an AWS environment to another region
+
+  --to                Region to move the environment to
+  [--name]            Name for the environment in the new region. A new name is generated if it is not given
+  [--lb-cert-arn]     ACM certificate ARN in the new region, required when the load balancer uses an ACM certificate`
+
+	CurlCommandUsage = `Sends a request to the BOSH director API and prints the response
+
+  <path>              Path to request, for example /deployments
+  [-X]                HTTP method. Defaults to GET
+  [--body]            Request body, sent as JSON`
+
+	SSHCertCommandUsage = `Issues a short-lived SSH certificate for the jumpbox user, signed by the certificate authority of an environment planned with --ssh-ca
+
+  issue               Signs the public key and prints the certificate. Save it next to the private key as <key>-cert.pub
+  --public-key        Path to the SSH public key to sign
+  [--ttl]             How long the certificate is valid for. Defaults to 8h
+  [--director]        Issues the certificate for the director instead of the jumpbox`
+
+	SSHCommandUsage = `Opens an SSH session to the jumpbox, or through it to the director
+
+  --jumpbox           Connects to the jumpbox
+  --director          Connects to the director through the jumpbox
+  [--cmd]             Runs the command and exits instead of opening a shell`
+
+	ManCommandUsage = `Prints the manual of bbl, or of a command, as a man page
+
+  [<command>]         Command to print the manual of. Prints the manual of bbl if it is not given
+  [--output-dir]      Writes the manual of bbl and of every command to the directory instead`
+
+	CompletionCommandUsage = `Prints a completion script of bbl's commands and flags for a shell
+
+  <shell>             Shell to print the script for: bash, zsh or fish`
+
+	BenchCommandUsage = `Times the steps that bbl runs locally, such as rendering templates and saving the state, without changing the environment
+
+  [--iterations]      Number of times to run each step. Defaults to 10`
+
+	DeprecatedCommandUsage = "This command has been removed. Run it to see the command that replaces it, or use bbl migrate-commands to update scripts."
 
 	LBsCommandUsage = "Prints attached load balancer(s)"
 
-	OutputsCommandUsage = "Prints the outputs from terraform."
+	OutputsCommandUsage = `Prints the outputs from terraform, such as the subnets, security groups and load balancers, sorted by name. Name outputs to print only those, a single output without its name. Lists and maps are printed as json, and --json prints all of it as a json object.
+
+  [<output>...]       Names of the outputs to print
+  [--format]          "terraform" prints a terraform file that declares the outputs as variables and, on aws, looks up the vpc, subnets and security groups as data sources`
 
-	VersionCommandUsage = "Prints version"
+	VersionCommandUsage = `Prints version, and the BOSH release, CPI release, stemcell and terraform template that bbl builds the environment from
+
+  [--json]            Prints the version as JSON`
 
 	UsageCommandUsage = "Prints helpful message for the given command"
 
@@ -77,6 +225,10 @@ const (
 
 	RotateCommandUsage = "Rotates SSH key for the jumpbox user."
 
+	RotateKeyPairCommandUsage = "Rotates the EC2 key pair used by the director and the VMs it deploys."
+
+	RotateDirectorCredentialsCommandUsage = "Rotates the admin, NATS, registry and postgres passwords and the SSL certificate of the director, and redeploys it."
+
 	JumpboxAddressCommandUsage = "Prints BOSH jumpbox address"
 
 	DirectorUsernameCommandUsage = "Prints BOSH director username"
@@ -87,9 +239,116 @@ const (
 
 	DirectorCACertCommandUsage = "Prints BOSH director CA certificate"
 
+	CredhubServerCommandUsage = "Prints the address of the CredHub of the director"
+
+	CredhubClientCommandUsage = "Prints the UAA client of the CredHub of the director"
+
+	CredhubPasswordCommandUsage = "Prints the secret of the UAA client of the CredHub of the director"
+
+	CredhubCACertCommandUsage = "Prints the CA certificates of the CredHub and UAA of the director"
+
 	PrintEnvCommandUsage = "Prints required BOSH environment variables"
 
+	BOSHDeploymentVarsCommandUsage = "Prints the vars of the director with the credentials of the IAAS, for bosh create-env of the bosh-deployment repo, for example: bosh create-env bosh.yml -l <(bbl bosh-deployment-vars)"
+
 	LatestErrorCommandUsage = "Prints the output from the latest call to terraform"
+
+	CloudConfigCommandUsage = "Prints the cloud config that bbl uploads to the director"
+
+	PreUpgradeCheckCommandUsage = "Checks that this bbl can upgrade the environment, and lists the bbl releases that must upgrade it first"
+
+	UpgradeDirectorCommandUsage = `Redeploys the director with the BOSH release, CPI release and stemcell that this bbl pins, and records their versions in the state
+
+  [--dry-run]         Prints the versions that would change without redeploying the director`
+
+	ValidateCommandUsage = `Checks what bbl up needs without creating anything: that the state directory is writable, that bosh.io and S3 can be reached, and on AWS that the credentials are valid, the region exists, their policies allow the actions of bbl up and no resources have the names of the environment
+
+  [--name]            Name of the environment to plan, whose resource names are checked`
+
+	StateEncryptionCommandUsage = `Encrypts or decrypts the credentials in the state file: the director password and private key, and the private key of the load balancer. The vars directory, with the variables of the jumpbox and the director and the terraform state, stays in plain text
+
+  encrypt             Encrypts them with the passphrase given with --state-passphrase
+  [--kms-key-id]      Encrypts them with a data key of an AWS KMS key instead
+  decrypt             Writes them in plain text again`
+
+	StateCommandUsage = StateEncryptionCommandUsage + `
+
+Lists or restores the copies of the state file that bbl keeps in .bbl-backups from before each command that changes it
+
+  list-backups        Lists the times of the backups, oldest first
+  restore <time>      Replaces the state file with the backup of the time, for example: bbl state restore 20260304T050607Z`
+
+	StatusCommandUsage = `Prints the commands that --no-wait runs in the background, and whether they are running, succeeded or failed
+
+  [<operation-id>]    Prints only the given operation`
+
+	WaitCommandUsage = `Waits for a command that --no-wait runs in the background to finish, and fails if it failed
+
+  <operation-id>      The operation to wait for, as printed by --no-wait or bbl status`
+
+	EscrowCommandUsage = `Seals the SSH private keys and the director credentials of the environment for PGP recipients, for break-glass access. Recipients read them with gpg --decrypt
+
+  --recipients        Directory of the PGP public keys, armored or not, to seal the credentials for
+  [--output]          File to write the sealed credentials to. Defaults to <env-id>-escrow.asc`
+
+	AnnotateCommandUsage = `Records metadata about the environment in the state, such as its owner. On aws they are also added to the tags of its resources by bbl up
+
+  <key>=<value>       Sets an annotation. Pass several to set them at once
+  <key>=              Removes an annotation`
+
+	AnnotationsCommandUsage = "Prints the annotations of the environment, one key=value per line, or as a JSON object with --json"
+
+	BootstrapAccountCommandUsage = "Creates the account-wide prerequisites of an AWS environment, such as the service-linked role of Elastic Load Balancing"
+
+	DetachLBCommandUsage = `Moves the cf load balancer of an AWS environment, with its DNS zone, out of the environment into a directory, for another environment to adopt
+
+  --dir               Directory to write the load balancer to`
+
+	AdoptLBCommandUsage = `Moves a cf load balancer that bbl detach-lb wrote into a directory into an AWS environment in the same VPC
+
+  --dir               Directory that bbl detach-lb wrote the load balancer to`
+
+	UploadCertificateCommandUsage = `Uploads a certificate to IAM and records it in the state without changing the load balancers. bbl plan --lb-type cf uses it when --lb-cert is not given
+
+  --lb-cert           Path to the SSL certificate
+  --lb-key            Path to the SSL certificate key
+  [--lb-chain]        Path to the SSL certificate chain`
+
+	CreateCertificateCommandUsage = `Uploads a certificate to IAM under a name and records it in the state, for bbl attach-certificate to attach to a load balancer
+
+  --name              Name of the certificate
+  --cert              Path to the SSL certificate
+  --key               Path to the SSL certificate key
+  [--chain]           Path to the SSL certificate chain`
+
+	AttachCertificateCommandUsage = `Attaches a certificate of bbl create-certificate to the TLS listeners of a load balancer of an AWS environment. bbl plan --lb-type cf and bbl up apply it
+
+  --lb                Load balancer to attach the certificate to: cf-router or cf-iso-router
+  --name              Name of the certificate`
+
+	RotateCertificateCommandUsage = `Uploads a certificate to IAM and switches the TLS listeners of a cf load balancer of an AWS environment over to it at once, then deletes the certificate that bbl uploaded before
+
+  --cert              Path to the SSL certificate
+  --key               Path to the SSL certificate key
+  [--chain]           Path to the SSL certificate chain
+  [--lb]              Load balancer to rotate the certificate of: cf-router or cf-iso-router. Defaults to cf-router`
+
+	PinArtifactsCommandUsage = `Resolves versions of the BOSH release, CPI release and stemcell of the director on bosh.io and records them in the state, for bbl plan and bbl up to build the director from
+
+  [--bosh]            Version of the BOSH release: a version, a major version such as 270.x, or latest
+  [--cpi]             Version of the CPI release of the IAAS, as --bosh
+  [--stemcell]        Version of the stemcell of the IAAS, as --bosh`
+
+	DownloadArtifactsCommandUsage = `Downloads the releases and stemcells of the jumpbox and director into a directory and checks their SHA1s, for bbl plan --artifacts-dir to install them without internet access
+
+  --dir               Directory to download the tarballs into. Tarballs that are there already are kept`
+
+	UpdateSecurityGroupsCommandUsage = `Changes the blocks that may reach the jumpbox, director and load balancers of an AWS environment, and applies only its terraform
+
+  [--director-allowed-cidrs]  Comma-separated blocks that may reach the jumpbox and director, including the machine bbl runs on
+  [--lb-allowed-cidrs]        Comma-separated blocks that may reach the load balancers`
+
+	CopyStemcellAMICommandUsage = "Copies the AMI of the light stemcell of the director into the region of an AWS environment when bosh.io does not publish one there, and points bbl plan at a stemcell that uses the copy"
 )
 
 func (Up) Usage() string {
@@ -108,6 +367,72 @@ func (Rotate) Usage() string {
 	return fmt.Sprintf("%s%s%s", RotateCommandUsage, requiresCredentials, Credentials)
 }
 
+func (RotateKeyPair) Usage() string {
+	return fmt.Sprintf("%s%s%s", RotateKeyPairCommandUsage, requiresCredentials, Credentials)
+}
+
+func (RotateDirectorCredentials) Usage() string {
+	return fmt.Sprintf("%s%s%s", RotateDirectorCredentialsCommandUsage, requiresCredentials, Credentials)
+}
+
+func (CopyStemcellAMI) Usage() string {
+	return fmt.Sprintf("%s%s%s", CopyStemcellAMICommandUsage, requiresCredentials, Credentials)
+}
+
+func (UploadCertificate) Usage() string {
+	return fmt.Sprintf("%s%s%s", UploadCertificateCommandUsage, requiresCredentials, Credentials)
+}
+
+func (CreateCertificate) Usage() string {
+	return fmt.Sprintf("%s%s%s", CreateCertificateCommandUsage, requiresCredentials, Credentials)
+}
+
+func (AttachCertificate) Usage() string { return AttachCertificateCommandUsage }
+
+func (PinArtifacts) Usage() string { return PinArtifactsCommandUsage }
+
+func (DownloadArtifacts) Usage() string { return DownloadArtifactsCommandUsage }
+
+func (RotateCertificate) Usage() string {
+	return fmt.Sprintf("%s%s%s", RotateCertificateCommandUsage, requiresCredentials, Credentials)
+}
+
+func (UpdateSecurityGroups) Usage() string {
+	return fmt.Sprintf("%s%s%s", UpdateSecurityGroupsCommandUsage, requiresCredentials, Credentials)
+}
+
+func (DetachLB) Usage() string { return DetachLBCommandUsage }
+
+func (AdoptLB) Usage() string { return AdoptLBCommandUsage }
+
+func (MigrateCommands) Usage() string { return MigrateCommandsCommandUsage }
+
+func (Clone) Usage() string {
+	return fmt.Sprintf("%s%s%s", CloneCommandUsage, requiresCredentials, Credentials)
+}
+
+func (Apply) Usage() string {
+	return fmt.Sprintf("%s%s%s", ApplyCommandUsage, requiresCredentials, Credentials)
+}
+
+func (MigrateRegion) Usage() string {
+	return fmt.Sprintf("%s%s%s", MigrateRegionCommandUsage, requiresCredentials, Credentials)
+}
+
+func (Curl) Usage() string { return CurlCommandUsage }
+
+func (SSHCert) Usage() string { return SSHCertCommandUsage }
+
+func (SSH) Usage() string { return SSHCommandUsage }
+
+func (Man) Usage() string { return ManCommandUsage }
+
+func (Completion) Usage() string { return CompletionCommandUsage }
+
+func (Bench) Usage() string { return BenchCommandUsage }
+
+func (Deprecated) Usage() string { return DeprecatedCommandUsage }
+
 func (LBs) Usage() string { return LBsCommandUsage }
 
 func (Outputs) Usage() string { return OutputsCommandUsage }
@@ -118,8 +443,38 @@ func (Usage) Usage() string { return UsageCommandUsage }
 
 func (PrintEnv) Usage() string { return PrintEnvCommandUsage }
 
+func (BOSHDeploymentVars) Usage() string { return BOSHDeploymentVarsCommandUsage }
+
 func (LatestError) Usage() string { return LatestErrorCommandUsage }
 
+func (CloudConfig) Usage() string { return CloudConfigCommandUsage }
+
+func (PreUpgradeCheck) Usage() string { return PreUpgradeCheckCommandUsage }
+
+func (UpgradeDirector) Usage() string { return UpgradeDirectorCommandUsage }
+
+func (Validate) Usage() string { return ValidateCommandUsage }
+
+func (StateEncryption) Usage() string { return StateEncryptionCommandUsage }
+
+func (StateBackups) Usage() string { return StateCommandUsage }
+
+func (State) Usage() string { return StateCommandUsage }
+
+func (Status) Usage() string { return StatusCommandUsage }
+
+func (Escrow) Usage() string { return EscrowCommandUsage }
+
+func (Annotate) Usage() string { return AnnotateCommandUsage }
+
+func (Annotations) Usage() string { return AnnotationsCommandUsage }
+
+func (Wait) Usage() string { return WaitCommandUsage }
+
+func (BootstrapAccount) Usage() string {
+	return fmt.Sprintf("%s%s%s", BootstrapAccountCommandUsage, requiresCredentials, Credentials)
+}
+
 func (s SSHKey) Usage() string {
 	if s.Director {
 		return DirectorSSHKeyCommandUsage
@@ -144,3 +499,17 @@ func (s StateQuery) Usage() string {
 	}
 	return ""
 }
+
+func (c CredhubQuery) Usage() string {
+	switch c.propertyName {
+	case CredhubServerPropertyName:
+		return CredhubServerCommandUsage
+	case CredhubClientPropertyName:
+		return CredhubClientCommandUsage
+	case CredhubPasswordPropertyName:
+		return CredhubPasswordCommandUsage
+	case CredhubCACertPropertyName:
+		return CredhubCACertCommandUsage
+	}
+	return ""
+}
@@ -20,10 +20,51 @@ var _ = Describe("Commands Usage", func() {
 
   --iaas                     IAAS to deploy your BOSH director onto: "aws", "azure", "gcp", "vsphere"   env: $BBL_IAAS
   --name                     Name to assign to your BOSH director (optional)                            env: $BBL_ENV_NAME
+  --no-director              Provisions only the infrastructure, for a director you deploy yourself (optional)
+  --ssh-ca                   Makes the jumpbox and director trust an SSH certificate authority, for certificates from bbl ssh-cert issue (optional)
+  --trusted-ca-certs         Path to PEM encoded CA certificates that the director installs on the VMs it creates (optional)
+  --director-disk-size       Size in GB of the persistent disk of the director (optional, default: 64)
+  --bosh-release-url         URL of a BOSH release to deploy the director with instead of the pinned one, with --bosh-release-sha1 (optional)
+  --cpi-release-url          URL of a CPI release to deploy the director with instead of the pinned one, with --cpi-release-sha1 (optional)
+  --stemcell-url             URL of a stemcell to deploy the director on instead of the pinned one, with --stemcell-sha1 (optional)
+  --artifacts-dir            Installs the releases and stemcells of the jumpbox and director from the tarballs of bbl download-artifacts, without internet access (optional)
+  --ops-file                 Path to a go-patch ops file that is applied to the director manifest after the ones of bbl, can be repeated (optional)
+  --vars-file                Path to a YAML file of variables of the ops files, can be repeated (optional)
+  --syslog-address           Forwards the logs of the director, including the audit log of its API, to a syslog server (optional)
+  --syslog-port              Port of the syslog server (optional, default: 514)
+  --syslog-transport         Transport to the syslog server: "tcp", "udp" or "relp" (optional, default: tcp)
+  --azs                      Comma-separated availability zones to use instead of every zone in the region (optional, supported when iaas="aws")
+  --minimal                  Leaves out the NAT instance and gives VMs public IPs, for throwaway environments (optional, supported when iaas="aws")
+  --lite                     Deploys a bosh-lite director, whose warden cpi runs the VMs of deployments as containers on the director, without a NAT instance or load balancers (optional, supported when iaas="aws")
+  --nat-gateway              Routes the internal subnets through a NAT gateway in each availability zone, and replaces the NAT instance of an existing environment on the next up (optional, default for new environments, supported when iaas="aws")
+  --nat-instance             Keeps a NAT instance for a new environment instead of NAT gateways (optional, supported when iaas="aws")
+  --cheap                    Plans a throwaway environment with a small NAT instance, a burstable director and a single availability zone (optional, supported when iaas="aws")
+  --spot-bid-price           Price in USD per hour that the VMs of deployments with the spot vm_extension bid for spot instances, with --cheap (optional, supported when iaas="aws")
+  --dual-stack               Gives the VPC and its subnets IPv6 blocks, and has the application load balancer of the router answer on IPv6 too (optional, supported when iaas="aws")
+  --vpc-cidr                 CIDR block of the VPC, from /16 to /20, that the subnets are carved from (optional, default: 10.0.0.0/16, supported when iaas="aws")
+  --existing-vpc-id          Creates the subnets in an existing VPC instead of creating one, set --vpc-cidr to a free block of it (optional, supported when iaas="aws")
+  --subnet-sizes             Prefix length of the internal subnet of each availability zone, for example: us-east-1a=20,us-east-1b=22 (optional, supported when iaas="aws")
+  --reserved-cidrs           Comma-separated blocks of the VPC that bbl leaves out of its subnets, for example: 10.0.128.0/17 (optional, supported when iaas="aws")
+  --director-allowed-cidrs   Comma-separated blocks that may reach the jumpbox and director instead of anywhere, including the machine bbl runs on (optional, supported when iaas="aws")
+  --director-ports           Ports for the director's internal services, for example: blobstore=25251,nats=4223,registry=25778,mbus=6869 (optional, supported when iaas="aws")
+  --director-instance-type   EC2 instance type of the director, for example: t2.medium (optional, default: m4.xlarge, supported when iaas="aws")
+  --tags                     Tags the aws resources of the environment with key=value, repeatable, key= removes a tag (optional, supported when iaas="aws")
+  --s3-blobstore             Stores the blobs of the director in an S3 bucket that bbl creates, reached with narrowly scoped instance profiles (optional, supported when iaas="aws")
+  --s3-blobstore-bucket      Stores the blobs of the director in an existing S3 bucket instead (optional, supported when iaas="aws")
+  --dry-run                  Prints the changes terraform would make to the infrastructure without making them (optional)
+  --auto-approve             Applies changes to existing infrastructure without asking for confirmation. Also --yes (optional)
+  --bootstrap-account        Creates the service-linked role Elastic Load Balancing needs in fresh accounts first (optional, supported when iaas="aws")
+  --restart                  Runs every step again instead of resuming an interrupted up after the steps it completed (optional)
+  --skip-quota-check         Creates the infrastructure without first checking the limits of the account (optional, supported when iaas="aws")
 
   --aws-access-key-id        AWS Access Key ID              env: $BBL_AWS_ACCESS_KEY_ID
   --aws-secret-access-key    AWS Secret Access Key          env: $BBL_AWS_SECRET_ACCESS_KEY
   --aws-region               AWS Region                     env: $BBL_AWS_REGION
+  --aws-session-token        AWS Session Token              env: $BBL_AWS_SESSION_TOKEN
+  --aws-profile              AWS shared config profile      env: $BBL_AWS_PROFILE
+  --aws-role-arn             AWS IAM role to assume         env: $BBL_AWS_ROLE_ARN
+  --aws-mfa-serial           AWS MFA device serial or ARN   env: $BBL_AWS_MFA_SERIAL
+  --aws-mfa-token-code       AWS MFA code, or prompted      env: $BBL_AWS_MFA_TOKEN_CODE
 
   --gcp-service-account-key  GCP Service Access Key to use  env: $BBL_GCP_SERVICE_ACCOUNT_KEY
   --gcp-region               GCP Region to use              env: $BBL_GCP_REGION
@@ -49,7 +90,19 @@ var _ = Describe("Commands Usage", func() {
   --lb-cert                  Path to SSL certificate (supported when type="cf")
   --lb-key                   Path to SSL certificate key (supported when type="cf")
   --lb-chain                 Path to SSL certificate chain (supported when iaas="aws")
-  --lb-domain                Creates a DNS zone and records for the given domain (supported when type="cf")`))
+  --lb-cert-arn              ARN of an AWS Certificate Manager or IAM server certificate to use instead of --lb-cert and --lb-key (supported when iaas="aws")
+  --lb-acm-certificate       Requests a certificate of --lb-domain and its wildcard from AWS Certificate Manager, validated with a record in its DNS zone (supported when iaas="aws")
+  --lb-domain                Creates a DNS zone and records for the given domain (supported when type="cf")
+  --lb-dns-role-arn          IAM role to assume for the DNS zone and records, when the domain is managed in another AWS account (supported when iaas="aws")
+  --lb-dns-zone-id           Existing Route53 hosted zone for the records of --lb-domain, which bbl neither creates nor deletes (supported when iaas="aws")
+  --lb-apps-domain           Apps domain whose wildcard record bbl points at the cf router as well (supported when iaas="aws")
+  --lb-sni                   Serves a domain with a certificate of bbl create-certificate, on a load balancer of its own: domain=certificate, or domain= to remove it (repeatable, supported when iaas="aws")
+  --lb-internal              Creates the load balancers on the internal subnets, reachable only from inside the VPC (supported when iaas="aws")
+  --lb-elbv2                 Creates an application load balancer for the cf router and a network load balancer for the ssh proxy instead of classic ELBs (supported when iaas="aws")
+  --lb-allowed-cidrs         Comma-separated blocks that may reach the load balancers instead of anywhere (supported when iaas="aws")
+  --lb-health-check          Health check of the cf router load balancers: target=HTTP:8080/health,interval=10,timeout=5,healthy-threshold=2,unhealthy-threshold=3 (supported when iaas="aws")
+  --lb-check-workloads       Warns when the deployments of the director do not use the vm_extensions of the load balancers yet (optional)
+  --lb-ignore-workloads      Removes the load balancers even though deployments of the director still use their vm_extensions (optional)`))
 			})
 		})
 	})
@@ -63,6 +116,37 @@ var _ = Describe("Commands Usage", func() {
 
   --iaas                     IAAS to deploy your BOSH director onto: "aws", "azure", "gcp", "vsphere"   env: $BBL_IAAS
   --name                     Name to assign to your BOSH director (optional)                            env: $BBL_ENV_NAME
+  --no-director              Provisions only the infrastructure, for a director you deploy yourself (optional)
+  --ssh-ca                   Makes the jumpbox and director trust an SSH certificate authority, for certificates from bbl ssh-cert issue (optional)
+  --trusted-ca-certs         Path to PEM encoded CA certificates that the director installs on the VMs it creates (optional)
+  --director-disk-size       Size in GB of the persistent disk of the director (optional, default: 64)
+  --bosh-release-url         URL of a BOSH release to deploy the director with instead of the pinned one, with --bosh-release-sha1 (optional)
+  --cpi-release-url          URL of a CPI release to deploy the director with instead of the pinned one, with --cpi-release-sha1 (optional)
+  --stemcell-url             URL of a stemcell to deploy the director on instead of the pinned one, with --stemcell-sha1 (optional)
+  --artifacts-dir            Installs the releases and stemcells of the jumpbox and director from the tarballs of bbl download-artifacts, without internet access (optional)
+  --ops-file                 Path to a go-patch ops file that is applied to the director manifest after the ones of bbl, can be repeated (optional)
+  --vars-file                Path to a YAML file of variables of the ops files, can be repeated (optional)
+  --syslog-address           Forwards the logs of the director, including the audit log of its API, to a syslog server (optional)
+  --syslog-port              Port of the syslog server (optional, default: 514)
+  --syslog-transport         Transport to the syslog server: "tcp", "udp" or "relp" (optional, default: tcp)
+  --azs                      Comma-separated availability zones to use instead of every zone in the region (optional, supported when iaas="aws")
+  --minimal                  Leaves out the NAT instance and gives VMs public IPs, for throwaway environments (optional, supported when iaas="aws")
+  --lite                     Deploys a bosh-lite director, whose warden cpi runs the VMs of deployments as containers on the director, without a NAT instance or load balancers (optional, supported when iaas="aws")
+  --nat-gateway              Routes the internal subnets through a NAT gateway in each availability zone, and replaces the NAT instance of an existing environment on the next up (optional, default for new environments, supported when iaas="aws")
+  --nat-instance             Keeps a NAT instance for a new environment instead of NAT gateways (optional, supported when iaas="aws")
+  --cheap                    Plans a throwaway environment with a small NAT instance, a burstable director and a single availability zone (optional, supported when iaas="aws")
+  --spot-bid-price           Price in USD per hour that the VMs of deployments with the spot vm_extension bid for spot instances, with --cheap (optional, supported when iaas="aws")
+  --dual-stack               Gives the VPC and its subnets IPv6 blocks, and has the application load balancer of the router answer on IPv6 too (optional, supported when iaas="aws")
+  --vpc-cidr                 CIDR block of the VPC, from /16 to /20, that the subnets are carved from (optional, default: 10.0.0.0/16, supported when iaas="aws")
+  --existing-vpc-id          Creates the subnets in an existing VPC instead of creating one, set --vpc-cidr to a free block of it (optional, supported when iaas="aws")
+  --subnet-sizes             Prefix length of the internal subnet of each availability zone, for example: us-east-1a=20,us-east-1b=22 (optional, supported when iaas="aws")
+  --reserved-cidrs           Comma-separated blocks of the VPC that bbl leaves out of its subnets, for example: 10.0.128.0/17 (optional, supported when iaas="aws")
+  --director-allowed-cidrs   Comma-separated blocks that may reach the jumpbox and director instead of anywhere, including the machine bbl runs on (optional, supported when iaas="aws")
+  --director-ports           Ports for the director's internal services, for example: blobstore=25251,nats=4223,registry=25778,mbus=6869 (optional, supported when iaas="aws")
+  --director-instance-type   EC2 instance type of the director, for example: t2.medium (optional, default: m4.xlarge, supported when iaas="aws")
+  --tags                     Tags the aws resources of the environment with key=value, repeatable, key= removes a tag (optional, supported when iaas="aws")
+  --s3-blobstore             Stores the blobs of the director in an S3 bucket that bbl creates, reached with narrowly scoped instance profiles (optional, supported when iaas="aws")
+  --s3-blobstore-bucket      Stores the blobs of the director in an existing S3 bucket instead (optional, supported when iaas="aws")
 %s%s`, commands.Credentials, commands.LBUsage)))
 			})
 		})
@@ -76,7 +160,10 @@ var _ = Describe("Commands Usage", func() {
 				Expect(usageText).To(Equal(fmt.Sprintf(`Tears down BOSH director infrastructure
 
   [--no-confirm]       Do not ask for confirmation (optional)
-  [--skip-if-missing]  Gracefully exit if there is no state file (optional)
+  [--skip-if-missing]  Gracefully exit if there is no state file, and keep tearing down if parts of the environment were already deleted (optional)
+  [--discover]         Finds the resources of an environment whose state directory was lost by name, and deletes them after typed confirmation. Requires --env-name
+  [--env-name]         Name of the environment to find with --discover
+  [--force]            Deletes the environment even when its director still has deployments, whose VMs are left behind (optional)
 
   Credentials for your IaaS are required:%s`, commands.Credentials)))
 			})
@@ -90,11 +177,31 @@ var _ = Describe("Commands Usage", func() {
 			Expect(usageText).To(Equal(fmt.Sprintf(`Cleans up orphaned IAAS resources
 
   --filter            Only delete resources with this string in their name
+  [--dry-run]         Lists the resources that would be deleted without deleting them (optional)
 
   Credentials for your IaaS are required:%s`, commands.Credentials)))
 		})
 	})
 
+	Describe("MigrateCommands", func() {
+		It("returns string describing usage", func() {
+			command := commands.MigrateCommands{}
+			usageText := command.Usage()
+			Expect(usageText).To(Equal(`Finds removed bbl commands in scripts and pipelines and prints their replacements
+
+  --dir               Directory to search for scripts and pipelines
+  [--write]           Rewrite the invocations that can be translated exactly`))
+		})
+	})
+
+	Describe("Deprecated", func() {
+		It("returns string describing usage", func() {
+			command := commands.Deprecated{}
+			usageText := command.Usage()
+			Expect(usageText).To(Equal("This command has been removed. Run it to see the command that replaces it, or use bbl migrate-commands to update scripts."))
+		})
+	})
+
 	Describe("Rotate", func() {
 		Describe("Usage", func() {
 			It("returns string describing usage", func() {
@@ -107,6 +214,427 @@ var _ = Describe("Commands Usage", func() {
 		})
 	})
 
+	Describe("RotateKeyPair", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.RotateKeyPair{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Rotates the EC2 key pair used by the director and the VMs it deploys.
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("RotateDirectorCredentials", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.RotateDirectorCredentials{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Rotates the admin, NATS, registry and postgres passwords and the SSL certificate of the director, and redeploys it.
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("DetachLB", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.DetachLB{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Moves the cf load balancer of an AWS environment, with its DNS zone, out of the environment into a directory, for another environment to adopt
+
+  --dir               Directory to write the load balancer to`))
+			})
+		})
+	})
+
+	Describe("AdoptLB", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.AdoptLB{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Moves a cf load balancer that bbl detach-lb wrote into a directory into an AWS environment in the same VPC
+
+  --dir               Directory that bbl detach-lb wrote the load balancer to`))
+			})
+		})
+	})
+
+	Describe("CopyStemcellAMI", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.CopyStemcellAMI{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Copies the AMI of the light stemcell of the director into the region of an AWS environment when bosh.io does not publish one there, and points bbl plan at a stemcell that uses the copy
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("UploadCertificate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.UploadCertificate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Uploads a certificate to IAM and records it in the state without changing the load balancers. bbl plan --lb-type cf uses it when --lb-cert is not given
+
+  --lb-cert           Path to the SSL certificate
+  --lb-key            Path to the SSL certificate key
+  [--lb-chain]        Path to the SSL certificate chain
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("CreateCertificate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.CreateCertificate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Uploads a certificate to IAM under a name and records it in the state, for bbl attach-certificate to attach to a load balancer
+
+  --name              Name of the certificate
+  --cert              Path to the SSL certificate
+  --key               Path to the SSL certificate key
+  [--chain]           Path to the SSL certificate chain
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("AttachCertificate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.AttachCertificate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Attaches a certificate of bbl create-certificate to the TLS listeners of a load balancer of an AWS environment. bbl plan --lb-type cf and bbl up apply it
+
+  --lb                Load balancer to attach the certificate to: cf-router or cf-iso-router
+  --name              Name of the certificate`))
+			})
+		})
+	})
+
+	Describe("RotateCertificate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.RotateCertificate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Uploads a certificate to IAM and switches the TLS listeners of a cf load balancer of an AWS environment over to it at once, then deletes the certificate that bbl uploaded before
+
+  --cert              Path to the SSL certificate
+  --key               Path to the SSL certificate key
+  [--chain]           Path to the SSL certificate chain
+  [--lb]              Load balancer to rotate the certificate of: cf-router or cf-iso-router. Defaults to cf-router
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("UpdateSecurityGroups", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.UpdateSecurityGroups{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Changes the blocks that may reach the jumpbox, director and load balancers of an AWS environment, and applies only its terraform
+
+  [--director-allowed-cidrs]  Comma-separated blocks that may reach the jumpbox and director, including the machine bbl runs on
+  [--lb-allowed-cidrs]        Comma-separated blocks that may reach the load balancers
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("PinArtifacts", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.PinArtifacts{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Resolves versions of the BOSH release, CPI release and stemcell of the director on bosh.io and records them in the state, for bbl plan and bbl up to build the director from
+
+  [--bosh]            Version of the BOSH release: a version, a major version such as 270.x, or latest
+  [--cpi]             Version of the CPI release of the IAAS, as --bosh
+  [--stemcell]        Version of the stemcell of the IAAS, as --bosh`))
+			})
+		})
+	})
+
+	Describe("Validate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Validate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Checks what bbl up needs without creating anything: that the state directory is writable, that bosh.io and S3 can be reached, and on AWS that the credentials are valid, the region exists, their policies allow the actions of bbl up and no resources have the names of the environment
+
+  [--name]            Name of the environment to plan, whose resource names are checked`))
+			})
+		})
+	})
+
+	Describe("DownloadArtifacts", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.DownloadArtifacts{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Downloads the releases and stemcells of the jumpbox and director into a directory and checks their SHA1s, for bbl plan --artifacts-dir to install them without internet access
+
+  --dir               Directory to download the tarballs into. Tarballs that are there already are kept`))
+			})
+		})
+	})
+
+	Describe("Clone", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Clone{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Creates a new environment with the configuration of an existing one
+
+  --from              State directory of the environment to clone. Its secrets are not copied
+  [--name]            Name for the new environment. A new name is generated if it is not given
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("Apply", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Apply{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Converges the environment to the one described in an environment file
+
+  <path>              YAML file describing the environment: iaas, name, region and lb (type, cert, key, chain, cert_arn, domain)
+  [--dry-run]         Prints the operations that would run without running them
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("MigrateRegion", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.MigrateRegion{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Moves an AWS environment to another region
+
+  --to                Region to move the environment to
+  [--name]            Name for the environment in the new region. A new name is generated if it is not given
+  [--lb-cert-arn]     ACM certificate ARN in the new region, required when the load balancer uses an ACM certificate
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("BootstrapAccount", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.BootstrapAccount{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(fmt.Sprintf(`Creates the account-wide prerequisites of an AWS environment, such as the service-linked role of Elastic Load Balancing
+
+  Credentials for your IaaS are required:%s`, commands.Credentials)))
+			})
+		})
+	})
+
+	Describe("Curl", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Curl{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Sends a request to the BOSH director API and prints the response
+
+  <path>              Path to request, for example /deployments
+  [-X]                HTTP method. Defaults to GET
+  [--body]            Request body, sent as JSON`))
+			})
+		})
+	})
+
+	Describe("SSHCert", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.SSHCert{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Issues a short-lived SSH certificate for the jumpbox user, signed by the certificate authority of an environment planned with --ssh-ca
+
+  issue               Signs the public key and prints the certificate. Save it next to the private key as <key>-cert.pub
+  --public-key        Path to the SSH public key to sign
+  [--ttl]             How long the certificate is valid for. Defaults to 8h
+  [--director]        Issues the certificate for the director instead of the jumpbox`))
+			})
+		})
+	})
+
+	Describe("SSH", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.SSH{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Opens an SSH session to the jumpbox, or through it to the director
+
+  --jumpbox           Connects to the jumpbox
+  --director          Connects to the director through the jumpbox
+  [--cmd]             Runs the command and exits instead of opening a shell`))
+			})
+		})
+	})
+
+	Describe("Man", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Man{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints the manual of bbl, or of a command, as a man page
+
+  [<command>]         Command to print the manual of. Prints the manual of bbl if it is not given
+  [--output-dir]      Writes the manual of bbl and of every command to the directory instead`))
+			})
+		})
+	})
+
+	Describe("UpgradeDirector", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.UpgradeDirector{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Redeploys the director with the BOSH release, CPI release and stemcell that this bbl pins, and records their versions in the state
+
+  [--dry-run]         Prints the versions that would change without redeploying the director`))
+			})
+		})
+	})
+
+	Describe("Completion", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Completion{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints a completion script of bbl's commands and flags for a shell
+
+  <shell>             Shell to print the script for: bash, zsh or fish`))
+			})
+		})
+	})
+
+	Describe("Bench", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Bench{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Times the steps that bbl runs locally, such as rendering templates and saving the state, without changing the environment
+
+  [--iterations]      Number of times to run each step. Defaults to 10`))
+			})
+		})
+	})
+
+	Describe("Version", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Version{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints version, and the BOSH release, CPI release, stemcell and terraform template that bbl builds the environment from
+
+  [--json]            Prints the version as JSON`))
+			})
+		})
+	})
+
+	Describe("Status", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Status{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints the commands that --no-wait runs in the background, and whether they are running, succeeded or failed
+
+  [<operation-id>]    Prints only the given operation`))
+			})
+		})
+	})
+
+	Describe("Escrow", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Escrow{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Seals the SSH private keys and the director credentials of the environment for PGP recipients, for break-glass access. Recipients read them with gpg --decrypt
+
+  --recipients        Directory of the PGP public keys, armored or not, to seal the credentials for
+  [--output]          File to write the sealed credentials to. Defaults to <env-id>-escrow.asc`))
+			})
+		})
+	})
+
+	Describe("Annotate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Annotate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Records metadata about the environment in the state, such as its owner. On aws they are also added to the tags of its resources by bbl up
+
+  <key>=<value>       Sets an annotation. Pass several to set them at once
+  <key>=              Removes an annotation`))
+			})
+		})
+	})
+
+	Describe("StateEncryption", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.StateEncryption{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Encrypts or decrypts the credentials in the state file: the director password and private key, and the private key of the load balancer. The vars directory, with the variables of the jumpbox and the director and the terraform state, stays in plain text
+
+  encrypt             Encrypts them with the passphrase given with --state-passphrase
+  [--kms-key-id]      Encrypts them with a data key of an AWS KMS key instead
+  decrypt             Writes them in plain text again`))
+			})
+		})
+	})
+
+	Describe("State", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.State{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Encrypts or decrypts the credentials in the state file: the director password and private key, and the private key of the load balancer. The vars directory, with the variables of the jumpbox and the director and the terraform state, stays in plain text
+
+  encrypt             Encrypts them with the passphrase given with --state-passphrase
+  [--kms-key-id]      Encrypts them with a data key of an AWS KMS key instead
+  decrypt             Writes them in plain text again
+
+Lists or restores the copies of the state file that bbl keeps in .bbl-backups from before each command that changes it
+
+  list-backups        Lists the times of the backups, oldest first
+  restore <time>      Replaces the state file with the backup of the time, for example: bbl state restore 20260304T050607Z`))
+			})
+		})
+	})
+
+	Describe("Wait", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Wait{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Waits for a command that --no-wait runs in the background to finish, and fails if it failed
+
+  <operation-id>      The operation to wait for, as printed by --no-wait or bbl status`))
+			})
+		})
+	})
+
 	Describe("Usage", func() {
 		Describe("Usage", func() {
 			It("returns string describing usage", func() {
@@ -122,21 +650,35 @@ var _ = Describe("Commands Usage", func() {
 		Expect(usageText).To(Equal(expectedDescription))
 	},
 		Entry("LBs", commands.LBs{}, "Prints attached load balancer(s)"),
-		Entry("outputs", commands.Outputs{}, "Prints the outputs from terraform."),
+		Entry("outputs", commands.Outputs{}, `Prints the outputs from terraform, such as the subnets, security groups and load balancers, sorted by name. Name outputs to print only those, a single output without its name. Lists and maps are printed as json, and --json prints all of it as a json object.
+
+  [<output>...]       Names of the outputs to print
+  [--format]          "terraform" prints a terraform file that declares the outputs as variables and, on aws, looks up the vpc, subnets and security groups as data sources`),
 		Entry("jumpbox-address", newStateQuery("jumpbox address"), "Prints BOSH jumpbox address"),
 		Entry("director-address", newStateQuery("director address"), "Prints BOSH director address"),
 		Entry("director-password", newStateQuery("director password"), "Prints BOSH director password"),
 		Entry("director-username", newStateQuery("director username"), "Prints BOSH director username"),
 		Entry("director-ca-cert", newStateQuery("director ca cert"), "Prints BOSH director CA certificate"),
 		Entry("env-id", newStateQuery("environment id"), "Prints environment ID"),
+		Entry("credhub-server", newCredhubQuery("credhub server"), "Prints the address of the CredHub of the director"),
+		Entry("credhub-client", newCredhubQuery("credhub client"), "Prints the UAA client of the CredHub of the director"),
+		Entry("credhub-password", newCredhubQuery("credhub password"), "Prints the secret of the UAA client of the CredHub of the director"),
+		Entry("credhub-ca-cert", newCredhubQuery("credhub ca cert"), "Prints the CA certificates of the CredHub and UAA of the director"),
 		Entry("ssh-key", commands.SSHKey{}, "Prints SSH private key for the jumpbox."),
 		Entry("director-ssh-key", commands.SSHKey{Director: true}, "Prints SSH private key for the director."),
 		Entry("print-env", commands.PrintEnv{}, "Prints required BOSH environment variables"),
+		Entry("bosh-deployment-vars", commands.BOSHDeploymentVars{}, "Prints the vars of the director with the credentials of the IAAS, for bosh create-env of the bosh-deployment repo, for example: bosh create-env bosh.yml -l <(bbl bosh-deployment-vars)"),
 		Entry("latest-error", commands.LatestError{}, "Prints the output from the latest call to terraform"),
-		Entry("version", commands.Version{}, "Prints version"),
+		Entry("cloud-config", commands.CloudConfig{}, "Prints the cloud config that bbl uploads to the director"),
+		Entry("pre-upgrade-check", commands.PreUpgradeCheck{}, "Checks that this bbl can upgrade the environment, and lists the bbl releases that must upgrade it first"),
+		Entry("annotations", commands.Annotations{}, "Prints the annotations of the environment, one key=value per line, or as a JSON object with --json"),
 	)
 })
 
 func newStateQuery(propertyName string) commands.StateQuery {
-	return commands.NewStateQuery(nil, nil, nil, propertyName)
+	return commands.NewStateQuery(commands.OutputFormatter{}, nil, nil, propertyName)
+}
+
+func newCredhubQuery(propertyName string) commands.CredhubQuery {
+	return commands.NewCredhubQuery(commands.OutputFormatter{}, nil, nil, propertyName)
 }
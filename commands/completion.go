@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// usageFlag matches the flag a usage line documents, such as
+// "  --jumpbox   Connects to the jumpbox" or "  [--cmd]   Runs the command".
+var usageFlag = regexp.MustCompile(`^\s+\[?--([a-z0-9-]+)`)
+
+// Completion prints shell completion scripts generated from the usage of the
+// registered commands, so completion offers the same commands and flags as
+// --help and bbl man.
+type Completion struct {
+	logger   logger
+	commands map[string]Command
+}
+
+type completionCommand struct {
+	name        string
+	description string
+	flags       []string
+}
+
+func NewCompletion(logger logger, commands map[string]Command) Completion {
+	return Completion{
+		logger:   logger,
+		commands: commands,
+	}
+}
+
+func (c Completion) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseCompletionArgs(subcommandFlags)
+	return err
+}
+
+func (c Completion) Execute(ctx context.Context, args []string, state storage.State) error {
+	shell, err := parseCompletionArgs(args)
+	if err != nil {
+		return err
+	}
+
+	commands := c.completionCommands()
+	globalFlags := usageFlags(UsageHeader)
+
+	switch shell {
+	case "bash":
+		c.logger.Println(bashCompletion(commands, globalFlags))
+	case "zsh":
+		c.logger.Println(zshCompletion(commands, globalFlags))
+	case "fish":
+		c.logger.Println(fishCompletion(commands, globalFlags))
+	}
+
+	return nil
+}
+
+func (c Completion) completionCommands() []completionCommand {
+	commands := []completionCommand{}
+	for _, name := range commandNames(c.commands) {
+		usage := c.commands[name].Usage()
+		commands = append(commands, completionCommand{
+			name:        name,
+			description: strings.SplitN(usage, "\n", 2)[0],
+			flags:       usageFlags(usage),
+		})
+	}
+	return commands
+}
+
+// usageFlags returns the names of the flags a usage text documents, in order
+// and without duplicates.
+func usageFlags(usage string) []string {
+	seen := map[string]struct{}{}
+	names := []string{}
+	for _, line := range strings.Split(usage, "\n") {
+		match := usageFlag.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if _, ok := seen[match[1]]; ok {
+			continue
+		}
+		seen[match[1]] = struct{}{}
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func dashed(names []string) string {
+	dashedNames := []string{}
+	for _, name := range names {
+		dashedNames = append(dashedNames, "--"+name)
+	}
+	return strings.Join(dashedNames, " ")
+}
+
+func bashCompletion(commands []completionCommand, globalFlags []string) string {
+	names := []string{}
+	cases := []string{}
+	for _, command := range commands {
+		names = append(names, command.name)
+		cases = append(cases, fmt.Sprintf("    %s) flags=%q ;;", command.name, dashed(command.flags)))
+	}
+
+	return strings.Join([]string{
+		"# bash completion for bbl, generated by: bbl completion bash",
+		"_bbl() {",
+		`  local cur="${COMP_WORDS[COMP_CWORD]}"`,
+		fmt.Sprintf("  local commands=%q", strings.Join(names, " ")),
+		fmt.Sprintf("  local global_flags=%q", dashed(globalFlags)),
+		`  local command="" flags="" word`,
+		"",
+		`  for word in "${COMP_WORDS[@]:1:COMP_CWORD-1}"; do`,
+		`    if [[ " ${commands} " == *" ${word} "* ]]; then`,
+		`      command="${word}"`,
+		"      break",
+		"    fi",
+		"  done",
+		"",
+		`  if [[ -z "${command}" && "${cur}" != -* ]]; then`,
+		`    COMPREPLY=($(compgen -W "${commands}" -- "${cur}"))`,
+		"    return",
+		"  fi",
+		"",
+		`  case "${command}" in`,
+		strings.Join(cases, "\n"),
+		"  esac",
+		`  COMPREPLY=($(compgen -W "${flags} ${global_flags}" -- "${cur}"))`,
+		"}",
+		"complete -F _bbl bbl",
+	}, "\n")
+}
+
+func zshCompletion(commands []completionCommand, globalFlags []string) string {
+	names := []string{}
+	described := []string{}
+	cases := []string{}
+	for _, command := range commands {
+		names = append(names, command.name)
+		described = append(described, fmt.Sprintf("    %s", zshQuote(command.name+":"+strings.Replace(command.description, ":", `\:`, -1))))
+		cases = append(cases, fmt.Sprintf("    %s) flags=(%s) ;;", command.name, dashed(command.flags)))
+	}
+
+	return strings.Join([]string{
+		"#compdef bbl",
+		"# zsh completion for bbl, generated by: bbl completion zsh",
+		"_bbl() {",
+		"  local -a commands global_flags flags",
+		"  local command word",
+		"  commands=(",
+		strings.Join(described, "\n"),
+		"  )",
+		fmt.Sprintf("  global_flags=(%s)", dashed(globalFlags)),
+		"",
+		"  for word in ${words[2,CURRENT-1]}; do",
+		`    case "${word}" in`,
+		fmt.Sprintf("      %s) command=${word}; break ;;", strings.Join(names, "|")),
+		"    esac",
+		"  done",
+		"",
+		`  if [[ -z "${command}" && "${PREFIX}" != -* ]]; then`,
+		"    _describe 'command' commands",
+		"    return",
+		"  fi",
+		"",
+		`  case "${command}" in`,
+		strings.Join(cases, "\n"),
+		"  esac",
+		"  compadd -- ${flags} ${global_flags}",
+		"}",
+		`compdef _bbl bbl`,
+	}, "\n")
+}
+
+func zshQuote(text string) string {
+	return "'" + strings.Replace(text, "'", `'\''`, -1) + "'"
+}
+
+func fishCompletion(commands []completionCommand, globalFlags []string) string {
+	lines := []string{
+		"# fish completion for bbl, generated by: bbl completion fish",
+		"complete -c bbl -f",
+	}
+
+	for _, name := range globalFlags {
+		lines = append(lines, fmt.Sprintf("complete -c bbl -l %s", name))
+	}
+
+	for _, command := range commands {
+		lines = append(lines, fmt.Sprintf("complete -c bbl -n __fish_use_subcommand -a %s -d %s", command.name, fishQuote(command.description)))
+		for _, name := range command.flags {
+			lines = append(lines, fmt.Sprintf("complete -c bbl -n '__fish_seen_subcommand_from %s' -l %s", command.name, name))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func fishQuote(text string) string {
+	return "'" + strings.Replace(strings.Replace(text, `\`, `\\`, -1), "'", `\'`, -1) + "'"
+}
+
+func parseCompletionArgs(args []string) (string, error) {
+	completionFlags := flags.New("completion")
+
+	err := completionFlags.Parse(args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(completionFlags.Args()) != 1 {
+		return "", errors.New("completion takes a shell, for example: bbl completion bash")
+	}
+
+	shell := completionFlags.Args()[0]
+	switch shell {
+	case "bash", "zsh", "fish":
+		return shell, nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q, use bash, zsh or fish", shell)
+	}
+}
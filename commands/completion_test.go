@@ -0,0 +1,85 @@
+package commands_test
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Completion", func() {
+	var (
+		logger     *fakes.Logger
+		completion commands.Completion
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+
+		sshCmd := &fakes.Command{}
+		sshCmd.UsageCall.Returns.Usage = "Opens an SSH session: jumpbox or director\n\n  --jumpbox           Connects to the jumpbox\n  [--cmd]             Runs the command"
+		envIDCmd := &fakes.Command{}
+		envIDCmd.UsageCall.Returns.Usage = "Prints environment ID"
+
+		completion = commands.NewCompletion(logger, map[string]commands.Command{
+			"ssh":        sshCmd,
+			"env-id":     envIDCmd,
+			"create-lbs": commands.NewDeprecated("create-lbs", &fakes.CertificateValidator{}, logger),
+		})
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when not given a shell", func() {
+			err := completion.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("completion takes a shell, for example: bbl completion bash"))
+		})
+
+		It("returns an error for an unsupported shell", func() {
+			err := completion.CheckFastFails([]string{"powershell"}, storage.State{})
+			Expect(err).To(MatchError(`unsupported shell "powershell", use bash, zsh or fish`))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints a bash script with the commands and their flags", func() {
+			err := completion.Execute(context.Background(), []string{"bash"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			script := logger.PrintlnCall.Receives.Message
+			Expect(script).To(ContainSubstring(`local commands="env-id ssh"`))
+			Expect(script).To(ContainSubstring(`ssh) flags="--jumpbox --cmd" ;;`))
+			Expect(script).To(ContainSubstring(`env-id) flags="" ;;`))
+			Expect(script).To(ContainSubstring("--state-dir --state-format"))
+			Expect(script).To(ContainSubstring("complete -F _bbl bbl"))
+			Expect(script).NotTo(ContainSubstring("create-lbs"))
+		})
+
+		It("prints a zsh script with the commands, their descriptions and their flags", func() {
+			err := completion.Execute(context.Background(), []string{"zsh"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			script := logger.PrintlnCall.Receives.Message
+			Expect(script).To(HavePrefix("#compdef bbl\n"))
+			Expect(script).To(ContainSubstring(`'ssh:Opens an SSH session\: jumpbox or director'`))
+			Expect(script).To(ContainSubstring("env-id|ssh) command=${word}; break ;;"))
+			Expect(script).To(ContainSubstring("ssh) flags=(--jumpbox --cmd) ;;"))
+			Expect(script).NotTo(ContainSubstring("create-lbs"))
+		})
+
+		It("prints a fish script with the commands, their descriptions and their flags", func() {
+			err := completion.Execute(context.Background(), []string{"fish"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			script := logger.PrintlnCall.Receives.Message
+			Expect(script).To(ContainSubstring("complete -c bbl -l state-dir\n"))
+			Expect(script).To(ContainSubstring("complete -c bbl -n __fish_use_subcommand -a env-id -d 'Prints environment ID'\n"))
+			Expect(script).To(ContainSubstring("complete -c bbl -n '__fish_seen_subcommand_from ssh' -l jumpbox\n"))
+			Expect(script).To(ContainSubstring("complete -c bbl -n '__fish_seen_subcommand_from ssh' -l cmd"))
+			Expect(script).NotTo(ContainSubstring("create-lbs"))
+		})
+	})
+})
@@ -0,0 +1,260 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const stemcellCacheDir = "stemcells"
+
+type ImageCopier interface {
+	ImageState(imageID string) (string, error)
+	FindOwnImage(name string) (string, error)
+	CopyImage(sourceRegion, sourceImageID, name string) (string, error)
+}
+
+type httpGetter interface {
+	Get(url string) (*http.Response, error)
+}
+
+type stemcellCacheStore interface {
+	Set(state storage.State) error
+	GetStateDir() string
+}
+
+type stemcellCacheFS interface {
+	fileio.FileReader
+	fileio.FileWriter
+	fileio.AllMkdirer
+}
+
+// CopyStemcellAMI copies the AMI of the light stemcell of the director into
+// the region of the environment ahead of bbl up, when bosh.io does not
+// publish one there. The copy is named after the stemcell and its version,
+// so it is reused by later runs and by the other environments of the account.
+type CopyStemcellAMI struct {
+	stateValidator stateValidator
+	stateStore     stemcellCacheStore
+	imageCopier    ImageCopier
+	httpClient     httpGetter
+	fs             stemcellCacheFS
+	logger         logger
+	pollInterval   time.Duration
+}
+
+func NewCopyStemcellAMI(stateValidator stateValidator, stateStore stemcellCacheStore, imageCopier ImageCopier,
+	httpClient httpGetter, fs stemcellCacheFS, logger logger, pollInterval time.Duration) CopyStemcellAMI {
+	return CopyStemcellAMI{
+		stateValidator: stateValidator,
+		stateStore:     stateStore,
+		imageCopier:    imageCopier,
+		httpClient:     httpClient,
+		fs:             fs,
+		logger:         logger,
+		pollInterval:   pollInterval,
+	}
+}
+
+func (c CopyStemcellAMI) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("copy-stemcell-ami only copies the stemcell AMI of an aws environment.")
+	}
+
+	if state.NoDirector {
+		return errors.New("copy-stemcell-ami needs an environment with a director.")
+	}
+
+	return nil
+}
+
+func (c CopyStemcellAMI) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	region := state.AWS.Region
+
+	tgz, err := c.readStemcell(state)
+	if err != nil {
+		return err
+	}
+
+	stemcell, err := bosh.ReadLightStemcell(tgz)
+	if err != nil {
+		return err
+	}
+
+	if imageID, ok := stemcell.AMIs[region]; ok {
+		imageState, err := c.imageCopier.ImageState(imageID)
+		if err != nil {
+			return err
+		}
+		if imageState == "available" {
+			c.logger.Println(fmt.Sprintf("The AMI %s of stemcell %s/%s is available in %s. There is nothing to copy.", imageID, stemcell.Name, stemcell.Version, region))
+			return nil
+		}
+	}
+
+	imageName := fmt.Sprintf("%s-%s", stemcell.Name, stemcell.Version)
+	imageID, err := c.imageCopier.FindOwnImage(imageName)
+	if err != nil {
+		return err
+	}
+	if imageID == "" {
+		sourceRegion, ok := stemcellSourceRegion(stemcell.AMIs, region)
+		if !ok {
+			return fmt.Errorf("The stemcell %s/%s has no AMI in the partition of %s to copy, since AMIs are not copied between partitions. Use a stemcell published for %s.", stemcell.Name, stemcell.Version, region, aws.PartitionOf(region).ID)
+		}
+		imageID, err = c.imageCopier.CopyImage(sourceRegion, stemcell.AMIs[sourceRegion], imageName)
+		if err != nil {
+			return err
+		}
+	} else {
+		c.logger.Step("reusing the image %s of the account", imageID)
+	}
+
+	err = c.waitForImage(ctx, imageID)
+	if err != nil {
+		return err
+	}
+
+	copied, err := stemcell.WithAMI(region, imageID)
+	if err != nil {
+		return err
+	}
+
+	// bosh create-env reads the stemcell from a file:// url, which must be
+	// absolute.
+	dir, err := filepath.Abs(filepath.Join(c.stateStore.GetStateDir(), stemcellCacheDir))
+	if err != nil {
+		return err //not tested
+	}
+	err = c.fs.MkdirAll(dir, storage.StateMode)
+	if err != nil {
+		return fmt.Errorf("Create stemcell cache: %s", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s-%s.tgz", stemcell.Name, stemcell.Version, region))
+	err = c.fs.WriteFile(path, copied, storage.StateMode)
+	if err != nil {
+		return fmt.Errorf("Write stemcell: %s", err)
+	}
+
+	overrides := storage.ArtifactOverrides{}
+	if state.ArtifactOverrides != nil {
+		overrides = *state.ArtifactOverrides
+	}
+	overrides.StemcellURL = "file://" + path
+	overrides.StemcellSHA1 = fmt.Sprintf("%x", sha1.Sum(copied))
+	state.ArtifactOverrides = &overrides
+
+	err = c.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	c.logger.Println(fmt.Sprintf("Copied stemcell %s/%s to %s as %s. Run bbl plan and bbl up to create the director from it.", stemcell.Name, stemcell.Version, region, imageID))
+	return nil
+}
+
+// readStemcell reads the stemcell that bbl plan pinned for the director,
+// either the one of bosh-deployment or the one of --stemcell-url.
+func (c CopyStemcellAMI) readStemcell(state storage.State) ([]byte, error) {
+	stemcellURL := ""
+	if state.ArtifactOverrides != nil {
+		stemcellURL = state.ArtifactOverrides.StemcellURL
+	}
+	if stemcellURL == "" {
+		artifacts, err := bosh.PinnedArtifacts(state.IAAS)
+		if err != nil {
+			return nil, err //not tested
+		}
+		stemcellURL = artifacts.Stemcell.URL
+	}
+
+	if strings.HasPrefix(stemcellURL, "file://") {
+		contents, err := c.fs.ReadFile(strings.TrimPrefix(stemcellURL, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("Read stemcell: %s", err)
+		}
+		return contents, nil
+	}
+
+	c.logger.Step("downloading the stemcell %s", stemcellURL)
+	response, err := c.httpClient.Get(stemcellURL)
+	if err != nil {
+		return nil, fmt.Errorf("Download stemcell: %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Download stemcell: %s returned %s", stemcellURL, response.Status)
+	}
+
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Download stemcell: %s", err)
+	}
+	return contents, nil
+}
+
+// waitForImage polls the copy of the AMI until it is available, printing a
+// dot each time. An interrupted copy carries on, and bbl copy-stemcell-ami
+// finds it when it is run again.
+func (c CopyStemcellAMI) waitForImage(ctx context.Context, imageID string) error {
+	c.logger.Step("waiting for the image %s to be available", imageID)
+
+	for {
+		imageState, err := c.imageCopier.ImageState(imageID)
+		if err != nil {
+			return err
+		}
+
+		switch imageState {
+		case "available":
+			return nil
+		case "pending", "":
+			c.logger.Dot()
+			err = sleep(ctx, c.pollInterval)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("The copy %s of the stemcell AMI is %s. Deregister it and run bbl copy-stemcell-ami again.", imageID, imageState)
+		}
+	}
+}
+
+// stemcellSourceRegion picks the region to copy the AMI from, of the
+// partition of the region, us-east-1 when the stemcell has one there since
+// bosh.io publishes every stemcell there.
+func stemcellSourceRegion(amis map[string]string, region string) (string, bool) {
+	if _, ok := amis["us-east-1"]; ok && aws.SamePartition(region, "us-east-1") {
+		return "us-east-1", true
+	}
+
+	regions := []string{}
+	for amiRegion := range amis {
+		if aws.SamePartition(region, amiRegion) {
+			regions = append(regions, amiRegion)
+		}
+	}
+	if len(regions) == 0 {
+		return "", false
+	}
+	sort.Strings(regions)
+	return regions[0], true
+}
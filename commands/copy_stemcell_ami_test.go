@@ -0,0 +1,225 @@
+package commands_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CopyStemcellAMI", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		stateStore     *fakes.StateStore
+		imageCopier    *fakes.ImageCopier
+		fileIO         *fakes.FileIO
+		logger         *fakes.Logger
+		server         *httptest.Server
+		command        commands.CopyStemcellAMI
+
+		lightStemcell []byte
+		imageStates   []string
+		state         storage.State
+	)
+
+	BeforeEach(func() {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+		manifest := "name: some-stemcell\nversion: '1.2'\ncloud_properties:\n  ami:\n    eu-west-1: ami-eu\n    us-east-1: ami-us\n"
+		Expect(tarWriter.WriteHeader(&tar.Header{Name: "stemcell.MF", Mode: 0644, Size: int64(len(manifest))})).To(Succeed())
+		_, err := tarWriter.Write([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+		lightStemcell = buffer.Bytes()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/some-stemcell" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(lightStemcell)
+		}))
+
+		stateValidator = &fakes.StateValidator{}
+		stateStore = &fakes.StateStore{}
+		stateStore.GetStateDirCall.Returns.Directory = "/some/state-dir"
+		imageCopier = &fakes.ImageCopier{}
+		imageStates = []string{"pending", "available"}
+		imageCopier.ImageStateCall.Stub = func(string) (string, error) {
+			imageState := imageStates[0]
+			imageStates = imageStates[1:]
+			return imageState, nil
+		}
+		imageCopier.CopyImageCall.Returns.ImageID = "ami-copy"
+		fileIO = &fakes.FileIO{}
+		logger = &fakes.Logger{}
+		command = commands.NewCopyStemcellAMI(stateValidator, stateStore, imageCopier, http.DefaultClient, fileIO, logger, 0)
+
+		state = storage.State{
+			IAAS: "aws",
+			AWS:  storage.AWS{Region: "ap-south-1"},
+			ArtifactOverrides: &storage.ArtifactOverrides{
+				StemcellURL:  server.URL + "/some-stemcell",
+				StemcellSHA1: "some-sha1",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("only copies the stemcell of aws environments", func() {
+			state.IAAS = "gcp"
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("copy-stemcell-ami only copies the stemcell AMI of an aws environment."))
+		})
+
+		It("needs an environment with a director", func() {
+			state.NoDirector = true
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("copy-stemcell-ami needs an environment with a director."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("copies the AMI into the region and points the director at the repacked stemcell", func() {
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(imageCopier.FindOwnImageCall.Receives.Name).To(Equal("some-stemcell-1.2"))
+			Expect(imageCopier.CopyImageCall.Receives.SourceRegion).To(Equal("us-east-1"))
+			Expect(imageCopier.CopyImageCall.Receives.SourceImageID).To(Equal("ami-us"))
+			Expect(imageCopier.CopyImageCall.Receives.Name).To(Equal("some-stemcell-1.2"))
+			Expect(imageCopier.ImageStateCall.Receives.ImageID).To(Equal("ami-copy"))
+			Expect(logger.DotCall.CallCount).To(Equal(1))
+
+			Expect(fileIO.MkdirAllCall.Receives.Dir).To(Equal("/some/state-dir/stemcells"))
+			Expect(fileIO.WriteFileCall.Receives).To(HaveLen(1))
+			written := fileIO.WriteFileCall.Receives[0]
+			Expect(written.Filename).To(Equal("/some/state-dir/stemcells/some-stemcell-1.2-ap-south-1.tgz"))
+			Expect(written.Mode).To(Equal(os.FileMode(storage.StateMode)))
+
+			copied, err := bosh.ReadLightStemcell(written.Contents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied.AMIs).To(HaveKeyWithValue("ap-south-1", "ami-copy"))
+
+			Expect(stateStore.SetCall.Receives[0].State.ArtifactOverrides).To(Equal(&storage.ArtifactOverrides{
+				StemcellURL:  "file:///some/state-dir/stemcells/some-stemcell-1.2-ap-south-1.tgz",
+				StemcellSHA1: fmt.Sprintf("%x", sha1.Sum(written.Contents)),
+			}))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Copied stemcell some-stemcell/1.2 to ap-south-1 as ami-copy. Run bbl plan and bbl up to create the director from it."))
+		})
+
+		It("reuses the copy the account already has", func() {
+			imageStates = []string{"available"}
+			imageCopier.FindOwnImageCall.Returns.ImageID = "ami-earlier-copy"
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(imageCopier.CopyImageCall.CallCount).To(Equal(0))
+			copied, err := bosh.ReadLightStemcell(fileIO.WriteFileCall.Receives[0].Contents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copied.AMIs).To(HaveKeyWithValue("ap-south-1", "ami-earlier-copy"))
+		})
+
+		It("copies nothing when the AMI of the region is available", func() {
+			state.AWS.Region = "eu-west-1"
+			imageStates = []string{"available"}
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(imageCopier.ImageStateCall.Receives.ImageID).To(Equal("ami-eu"))
+			Expect(imageCopier.CopyImageCall.CallCount).To(Equal(0))
+			Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("The AMI ami-eu of stemcell some-stemcell/1.2 is available in eu-west-1. There is nothing to copy."))
+		})
+
+		It("reads a stemcell that is already cached", func() {
+			state.ArtifactOverrides.StemcellURL = "file:///some/state-dir/stemcells/some-stemcell-1.2-eu-west-1.tgz"
+			state.AWS.Region = "eu-west-1"
+			imageStates = []string{"available"}
+			fileIO.ReadFileCall.Returns.Contents = lightStemcell
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.ReadFileCall.Receives.Filename).To(Equal("/some/state-dir/stemcells/some-stemcell-1.2-eu-west-1.tgz"))
+			Expect(imageCopier.CopyImageCall.CallCount).To(Equal(0))
+		})
+
+		Describe("failure cases", func() {
+			It("returns an error when the stemcell cannot be downloaded", func() {
+				state.ArtifactOverrides.StemcellURL = server.URL + "/other-stemcell"
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError(fmt.Sprintf("Download stemcell: %s/other-stemcell returned 404 Not Found", server.URL)))
+			})
+
+			It("returns an error when the stemcell has no AMI in the partition of the region", func() {
+				state.AWS.Region = "us-gov-west-1"
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("The stemcell some-stemcell/1.2 has no AMI in the partition of us-gov-west-1 to copy, since AMIs are not copied between partitions. Use a stemcell published for aws-us-gov."))
+				Expect(imageCopier.CopyImageCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the AMI cannot be copied", func() {
+				imageCopier.CopyImageCall.Returns.Error = errors.New("failed to copy")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to copy"))
+			})
+
+			It("returns an error when the copy fails", func() {
+				imageStates = []string{"pending", "failed"}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("The copy ami-copy of the stemcell AMI is failed. Deregister it and run bbl copy-stemcell-ami again."))
+				Expect(fileIO.WriteFileCall.Receives).To(BeEmpty())
+			})
+
+			It("returns an error when the stemcell cannot be written", func() {
+				fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("disk full")}}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Write stemcell: disk full"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the state cannot be saved", func() {
+				stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Save state: disk full"))
+			})
+		})
+	})
+})
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+var certificateName = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+type createCertificateConfig struct {
+	name      string
+	certPath  string
+	keyPath   string
+	chainPath string
+}
+
+// CreateCertificate uploads a certificate to IAM under a name of the user and
+// records it in the state, for bbl attach-certificate to attach to a load
+// balancer.
+type CreateCertificate struct {
+	stateValidator       stateValidator
+	certificateValidator certificateValidator
+	certificateUploader  CertificateUploader
+	stateStore           stateStore
+	logger               logger
+}
+
+func NewCreateCertificate(stateValidator stateValidator, certificateValidator certificateValidator, certificateUploader CertificateUploader,
+	stateStore stateStore, logger logger) CreateCertificate {
+	return CreateCertificate{
+		stateValidator:       stateValidator,
+		certificateValidator: certificateValidator,
+		certificateUploader:  certificateUploader,
+		stateStore:           stateStore,
+		logger:               logger,
+	}
+}
+
+func (c CreateCertificate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := parseCreateCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("create-certificate only uploads certificates to IAM for aws environments.")
+	}
+
+	if err := checkNotLite("create-certificate", state); err != nil {
+		return err
+	}
+
+	if state.AWS.CertificateNamed(config.name) != nil {
+		return fmt.Errorf("There is a certificate named %s already. Pick another name.", config.name)
+	}
+
+	return nil
+}
+
+func (c CreateCertificate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseCreateCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	certData, err := c.certificateValidator.ReadAndValidate(config.certPath, config.keyPath, config.chainPath)
+	if err != nil {
+		return bblerrors.New(bblerrors.Validation, fmt.Errorf("Validate certificate: %s", err))
+	}
+
+	fingerprint, err := certs.Fingerprint(certData.Cert)
+	if err != nil {
+		return err //not tested
+	}
+
+	arn, err := storeCertificate(c.certificateUploader, c.stateStore, fmt.Sprintf("%s-%s", state.EnvID, config.name), certData, func(arn string) storage.State {
+		state.AWS.Certificates = append(state.AWS.Certificates, storage.ServerCertificate{
+			Name:        config.name,
+			ARN:         arn,
+			Fingerprint: fingerprint,
+		})
+		return state
+	})
+	if err != nil {
+		return err
+	}
+
+	c.logger.Println(fmt.Sprintf("Created the certificate %s as %s. Run bbl attach-certificate --lb cf-router --name %s to use it for a load balancer.", config.name, arn, config.name))
+	return nil
+}
+
+func parseCreateCertificateArgs(args []string) (createCertificateConfig, error) {
+	var config createCertificateConfig
+
+	createFlags := flags.New("create-certificate")
+	createFlags.String(&config.name, "name", "")
+	createFlags.String(&config.certPath, "cert", "")
+	createFlags.String(&config.keyPath, "key", "")
+	createFlags.String(&config.chainPath, "chain", "")
+
+	err := createFlags.Parse(args)
+	if err != nil {
+		return createCertificateConfig{}, err
+	}
+
+	if config.name == "" || config.certPath == "" || config.keyPath == "" {
+		return createCertificateConfig{}, errors.New("--name, --cert and --key are required")
+	}
+
+	if !certificateName.MatchString(config.name) {
+		return createCertificateConfig{}, fmt.Errorf("--name %q may only contain letters, digits and hyphens.", config.name)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,114 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateCertificate", func() {
+	var (
+		stateValidator       *fakes.StateValidator
+		certificateValidator *fakes.CertificateValidator
+		certificateUploader  *fakes.CertificateUploader
+		stateStore           *fakes.StateStore
+		logger               *fakes.Logger
+		command              commands.CreateCertificate
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		certificateValidator = &fakes.CertificateValidator{}
+		certificateValidator.ReadAndValidateCall.Returns.CertData = certs.CertData{
+			Cert: []byte(testhelpers.BBL_CERT),
+			Key:  []byte("some-key"),
+		}
+		certificateUploader = &fakes.CertificateUploader{}
+		certificateUploader.UploadServerCertificateCall.Returns.ARN = "arn:aws:iam::123456789012:server-certificate/some-env-foo"
+		certificateUploader.UploadServerCertificateCall.Returns.Created = true
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		command = commands.NewCreateCertificate(stateValidator, certificateValidator, certificateUploader, stateStore, logger)
+
+		state = storage.State{IAAS: "aws", EnvID: "some-env"}
+		state.AWS.Certificates = []storage.ServerCertificate{{Name: "bar", ARN: "bar-arn", LBs: []string{"cf-router"}}}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--name", "foo", "--cert", "cert", "--key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the name, the certificate and the key", func() {
+			err := command.CheckFastFails([]string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("--name, --cert and --key are required"))
+		})
+
+		It("requires a name that IAM accepts", func() {
+			err := command.CheckFastFails([]string{"--name", "foo bar", "--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError(`--name "foo bar" may only contain letters, digits and hyphens.`))
+		})
+
+		It("refuses a name that is taken", func() {
+			err := command.CheckFastFails([]string{"--name", "bar", "--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("There is a certificate named bar already. Pick another name."))
+		})
+
+		It("only uploads certificates of aws environments", func() {
+			state.IAAS = "gcp"
+
+			err := command.CheckFastFails([]string{"--name", "foo", "--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("create-certificate only uploads certificates to IAM for aws environments."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("uploads the certificate under the name and records it without attaching it", func() {
+			err := command.Execute(context.Background(), []string{"--name", "foo", "--cert", "cert", "--key", "key", "--chain", "chain"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateValidator.ReadAndValidateCall.Receives.ChainPath).To(Equal("chain"))
+			Expect(certificateUploader.UploadServerCertificateCall.Receives.Name).To(Equal("some-env-foo"))
+
+			saved := stateStore.SetCall.Receives[0].State
+			Expect(saved.AWS.Certificates).To(Equal([]storage.ServerCertificate{
+				{Name: "bar", ARN: "bar-arn", LBs: []string{"cf-router"}},
+				{
+					Name:        "foo",
+					ARN:         "arn:aws:iam::123456789012:server-certificate/some-env-foo",
+					Fingerprint: "47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A",
+				},
+			}))
+
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Created the certificate foo as arn:aws:iam::123456789012:server-certificate/some-env-foo. Run bbl attach-certificate --lb cf-router --name foo to use it for a load balancer."))
+		})
+
+		It("deletes the certificate it uploaded when the state cannot be saved", func() {
+			stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+
+			err := command.Execute(context.Background(), []string{"--name", "foo", "--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("Save state: disk full"))
+			Expect(certificateUploader.DeleteServerCertificateCall.Receives.Name).To(Equal("some-env-foo"))
+		})
+
+		It("returns an error when the certificate is not valid", func() {
+			certificateValidator.ReadAndValidateCall.Returns.Error = errors.New("certificate expired on 2018-05-26")
+
+			err := command.Execute(context.Background(), []string{"--name", "foo", "--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("Validate certificate: certificate expired on 2018-05-26"))
+			Expect(certificateUploader.UploadServerCertificateCall.CallCount).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	CredhubServerPropertyName   = "credhub server"
+	CredhubClientPropertyName   = "credhub client"
+	CredhubPasswordPropertyName = "credhub password"
+	CredhubCACertPropertyName   = "credhub ca cert"
+
+	// credhubAdminClient is the UAA client of the credhub.yml ops file of
+	// bosh-deployment that administers the CredHub of the director.
+	credhubAdminClient = "credhub-admin"
+)
+
+var credhubQueryJSONKeys = map[string]string{
+	CredhubServerPropertyName:   "credhub_server",
+	CredhubClientPropertyName:   "credhub_client",
+	CredhubPasswordPropertyName: "credhub_password",
+	CredhubCACertPropertyName:   "credhub_ca_cert",
+}
+
+// CredhubQuery prints how to reach the CredHub that runs on the director
+// next to UAA, from the vars of the director in the state directory.
+type CredhubQuery struct {
+	output         OutputFormatter
+	stateValidator stateValidator
+	credhubGetter  credhubGetter
+	propertyName   string
+}
+
+func NewCredhubQuery(output OutputFormatter, stateValidator stateValidator, credhubGetter credhubGetter, propertyName string) CredhubQuery {
+	return CredhubQuery{
+		output:         output,
+		stateValidator: stateValidator,
+		credhubGetter:  credhubGetter,
+		propertyName:   propertyName,
+	}
+}
+
+func (c CredhubQuery) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.NoDirector {
+		return errors.New("Error BBL does not manage this director.")
+	}
+
+	return nil
+}
+
+func (c CredhubQuery) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	var (
+		propertyValue string
+		err           error
+	)
+	switch c.propertyName {
+	case CredhubServerPropertyName:
+		propertyValue, err = c.credhubGetter.GetServer()
+	case CredhubClientPropertyName:
+		propertyValue = credhubAdminClient
+	case CredhubPasswordPropertyName:
+		propertyValue, err = c.credhubGetter.GetPassword()
+	case CredhubCACertPropertyName:
+		propertyValue, err = c.credhubGetter.GetCerts()
+	}
+	if err != nil {
+		return fmt.Errorf("Get %s: %s", c.propertyName, err)
+	}
+
+	if propertyValue == "" {
+		return fmt.Errorf("Could not retrieve %s, please make sure you are targeting the proper state dir.", c.propertyName)
+	}
+
+	return c.output.PrintValue(credhubQueryJSONKeys[c.propertyName], propertyValue)
+}
@@ -0,0 +1,86 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CredhubQuery", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		credhubGetter  *fakes.CredhubGetter
+		logger         *fakes.Logger
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		logger = &fakes.Logger{}
+
+		credhubGetter = &fakes.CredhubGetter{}
+		credhubGetter.GetServerCall.Returns.Server = "https://10.0.0.6:8844"
+		credhubGetter.GetPasswordCall.Returns.Password = "some-credhub-secret"
+		credhubGetter.GetCertsCall.Returns.Certs = "some-credhub-ca-cert"
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when the state is not valid", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("no state")
+
+			command := commands.NewCredhubQuery(commands.NewOutputFormatter(logger, false), stateValidator, credhubGetter, commands.CredhubServerPropertyName)
+			err := command.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("no state"))
+		})
+
+		It("returns an error when bbl does not manage the director", func() {
+			command := commands.NewCredhubQuery(commands.NewOutputFormatter(logger, false), stateValidator, credhubGetter, commands.CredhubServerPropertyName)
+			err := command.CheckFastFails([]string{}, storage.State{NoDirector: true})
+			Expect(err).To(MatchError("Error BBL does not manage this director."))
+		})
+	})
+
+	Describe("Execute", func() {
+		DescribeTable("prints the property of the CredHub of the director",
+			func(propertyName, expected string) {
+				command := commands.NewCredhubQuery(commands.NewOutputFormatter(logger, false), stateValidator, credhubGetter, propertyName)
+				err := command.Execute(context.Background(), []string{}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{expected}))
+			},
+			Entry("server", commands.CredhubServerPropertyName, "https://10.0.0.6:8844"),
+			Entry("client", commands.CredhubClientPropertyName, "credhub-admin"),
+			Entry("password", commands.CredhubPasswordPropertyName, "some-credhub-secret"),
+			Entry("ca cert", commands.CredhubCACertPropertyName, "some-credhub-ca-cert"),
+		)
+
+		It("prints the property as a json object", func() {
+			command := commands.NewCredhubQuery(commands.NewOutputFormatter(logger, true), stateValidator, credhubGetter, commands.CredhubPasswordPropertyName)
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{`{"credhub_password":"some-credhub-secret"}`}))
+		})
+
+		It("returns an error when the vars of the director cannot be read", func() {
+			credhubGetter.GetServerCall.Returns.Error = errors.New("no vars file")
+
+			command := commands.NewCredhubQuery(commands.NewOutputFormatter(logger, false), stateValidator, credhubGetter, commands.CredhubServerPropertyName)
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).To(MatchError("Get credhub server: no vars file"))
+		})
+
+		It("returns an error when the director has no CredHub password", func() {
+			credhubGetter.GetPasswordCall.Returns.Password = ""
+
+			command := commands.NewCredhubQuery(commands.NewOutputFormatter(logger, false), stateValidator, credhubGetter, commands.CredhubPasswordPropertyName)
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).To(MatchError("Could not retrieve credhub password, please make sure you are targeting the proper state dir."))
+		})
+	})
+})
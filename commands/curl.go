@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type boshClientProvider interface {
+	Client(jumpbox storage.Jumpbox, directorAddress, directorUsername, directorPassword, directorCACert string) (bosh.Client, error)
+}
+
+type Curl struct {
+	stateValidator     stateValidator
+	boshClientProvider boshClientProvider
+	logger             logger
+}
+
+type curlConfig struct {
+	method string
+	body   string
+	path   string
+}
+
+func NewCurl(stateValidator stateValidator, boshClientProvider boshClientProvider, logger logger) Curl {
+	return Curl{
+		stateValidator:     stateValidator,
+		boshClientProvider: boshClientProvider,
+		logger:             logger,
+	}
+}
+
+func (c Curl) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := c.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseCurlArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if state.NoDirector {
+		return errors.New("Error BBL does not manage this director.")
+	}
+
+	return nil
+}
+
+// Execute sends a request to the director API with the credentials in the
+// state, through the jumpbox, and prints the response body. Responses with an
+// error status are printed as well, and returned as an error.
+func (c Curl) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseCurlArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.boshClientProvider.Client(state.Jumpbox, state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	if err != nil {
+		return fmt.Errorf("Connect to the director: %s", err)
+	}
+
+	var body []byte
+	if config.body != "" {
+		body = []byte(config.body)
+	}
+
+	status, responseBody, err := client.Curl(config.method, config.path, body)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Println(string(responseBody))
+
+	if status < 200 || status > 299 {
+		return fmt.Errorf("%s %s returned %d", config.method, config.path, status)
+	}
+
+	return nil
+}
+
+func parseCurlArgs(args []string) (curlConfig, error) {
+	var config curlConfig
+
+	curlFlags := flags.New("curl")
+	curlFlags.String(&config.method, "X", "GET")
+	curlFlags.String(&config.body, "body", "")
+
+	err := curlFlags.Parse(args)
+	if err != nil {
+		return curlConfig{}, err
+	}
+
+	if len(curlFlags.Args()) != 1 {
+		return curlConfig{}, errors.New("curl takes the path to request, for example: bbl curl /deployments")
+	}
+
+	config.method = strings.ToUpper(config.method)
+	config.path = curlFlags.Args()[0]
+
+	return config, nil
+}
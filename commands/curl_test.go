@@ -0,0 +1,142 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Curl", func() {
+	var (
+		stateValidator     *fakes.StateValidator
+		boshClientProvider *fakes.BOSHClientProvider
+		boshClient         *fakes.BOSHClient
+		logger             *fakes.Logger
+		curl               commands.Curl
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		boshClient = &fakes.BOSHClient{}
+		boshClientProvider = &fakes.BOSHClientProvider{}
+		boshClientProvider.ClientCall.Returns.Client = boshClient
+		logger = &fakes.Logger{}
+		curl = commands.NewCurl(stateValidator, boshClientProvider, logger)
+
+		state = storage.State{
+			Jumpbox: storage.Jumpbox{URL: "some-jumpbox-url"},
+			BOSH: storage.BOSH{
+				DirectorAddress:  "some-director-address",
+				DirectorUsername: "some-director-username",
+				DirectorPassword: "some-director-password",
+				DirectorSSLCA:    "some-director-ca",
+			},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := curl.CheckFastFails([]string{"/deployments"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires a path", func() {
+			err := curl.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("curl takes the path to request, for example: bbl curl /deployments"))
+		})
+
+		It("returns an error when bbl does not manage the director", func() {
+			state.NoDirector = true
+			err := curl.CheckFastFails([]string{"/deployments"}, state)
+			Expect(err).To(MatchError("Error BBL does not manage this director."))
+		})
+
+		Context("when the state validator returns an error", func() {
+			BeforeEach(func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("fig")
+			})
+
+			It("returns the error", func() {
+				err := curl.CheckFastFails([]string{"/deployments"}, state)
+				Expect(err).To(MatchError("fig"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			boshClient.CurlCall.Returns.Status = 200
+			boshClient.CurlCall.Returns.Body = []byte(`[{"name": "cf"}]`)
+		})
+
+		It("requests the path from the director and prints the response", func() {
+			err := curl.Execute(context.Background(), []string{"/deployments"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClientProvider.ClientCall.Receives.Jumpbox).To(Equal(state.Jumpbox))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-director-username"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-director-password"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca"))
+
+			Expect(boshClient.CurlCall.Receives.Method).To(Equal("GET"))
+			Expect(boshClient.CurlCall.Receives.Path).To(Equal("/deployments"))
+			Expect(boshClient.CurlCall.Receives.Body).To(BeNil())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{`[{"name": "cf"}]`}))
+		})
+
+		It("sends the method and body", func() {
+			err := curl.Execute(context.Background(), []string{"-X", "post", "--body", `{"some":"body"}`, "/tasks"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClient.CurlCall.Receives.Method).To(Equal("POST"))
+			Expect(boshClient.CurlCall.Receives.Path).To(Equal("/tasks"))
+			Expect(string(boshClient.CurlCall.Receives.Body)).To(Equal(`{"some":"body"}`))
+		})
+
+		Context("when the director responds with an error status", func() {
+			BeforeEach(func() {
+				boshClient.CurlCall.Returns.Status = 404
+				boshClient.CurlCall.Returns.Body = []byte(`{"code": 70000}`)
+			})
+
+			It("prints the response and returns an error", func() {
+				err := curl.Execute(context.Background(), []string{"/deployments/missing"}, state)
+				Expect(err).To(MatchError("GET /deployments/missing returned 404"))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{`{"code": 70000}`}))
+			})
+		})
+
+		Context("when the client cannot be created", func() {
+			BeforeEach(func() {
+				boshClientProvider.ClientCall.Returns.Error = errors.New("date")
+			})
+
+			It("returns the error", func() {
+				err := curl.Execute(context.Background(), []string{"/deployments"}, state)
+				Expect(err).To(MatchError("Connect to the director: date"))
+			})
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				boshClient.CurlCall.Returns.Error = errors.New("quince")
+			})
+
+			It("returns the error", func() {
+				err := curl.Execute(context.Background(), []string{"/deployments"}, state)
+				Expect(err).To(MatchError("quince"))
+			})
+		})
+	})
+})
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type deprecatedCommand struct {
+	// flags maps the removed command's flags to the flags of its replacement.
+	// Flags mapped to "" have no replacement and are dropped.
+	flags map[string]string
+	// viaPlan is set when the replacement is `bbl plan ... && bbl up`.
+	viaPlan bool
+	// needsLBType is set when the replacement requires --lb-type, which the
+	// removed command read from the state.
+	needsLBType bool
+}
+
+var deprecatedLBFlags = map[string]string{
+	"type":            "lb-type",
+	"cert":            "lb-cert",
+	"key":             "lb-key",
+	"chain":           "lb-chain",
+	"domain":          "lb-domain",
+	"skip-if-exists":  "",
+	"skip-if-missing": "",
+}
+
+var deprecatedBoolFlags = map[string]struct{}{
+	"skip-if-exists":  struct{}{},
+	"skip-if-missing": struct{}{},
+}
+
+// deprecatedCommands are the removed commands, with the unsupported-
+// commands that create-lbs and update-lbs were called before bbl v3.
+var deprecatedCommands = map[string]deprecatedCommand{
+	"create-lbs":             {flags: deprecatedLBFlags, viaPlan: true},
+	"update-lbs":             {flags: deprecatedLBFlags, viaPlan: true, needsLBType: true},
+	"delete-lbs":             {flags: map[string]string{"skip-if-missing": ""}, viaPlan: true},
+	"unsupported-create-lbs": {flags: deprecatedLBFlags, viaPlan: true},
+	"unsupported-update-lbs": {flags: deprecatedLBFlags, viaPlan: true, needsLBType: true},
+	"unsupported-deploy-bosh-on-aws-for-concourse": {flags: map[string]string{}},
+}
+
+func DeprecatedCommandNames() []string {
+	names := []string{}
+	for name := range deprecatedCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deprecatedReplacement returns the invocation that replaces a removed
+// command. bbl is the binary and global flags to prefix each invocation with.
+// The returned bool is false when the replacement contains a placeholder
+// that has to be filled in by hand.
+func deprecatedReplacement(bbl, command string, args []string, lbType string) (string, bool) {
+	deprecated := deprecatedCommands[command]
+	exact := true
+
+	newArgs := []string{}
+	if deprecated.needsLBType && !hasValueFlag(args, "type") {
+		if lbType == "" {
+			lbType = "<lb-type>"
+			exact = false
+		}
+		newArgs = append(newArgs, "--lb-type", lbType)
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if parts := strings.SplitN(name, "=", 2); len(parts) == 2 {
+			name, value, hasValue = parts[0], parts[1], true
+		}
+
+		newName, ok := deprecated.flags[name]
+		if !ok {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
+		_, isBool := deprecatedBoolFlags[name]
+		if !isBool && !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+
+		if newName == "" {
+			continue
+		}
+		newArgs = append(newArgs, "--"+newName)
+		if !isBool {
+			newArgs = append(newArgs, value)
+		}
+	}
+
+	flags := ""
+	if len(newArgs) > 0 {
+		flags = " " + strings.Join(newArgs, " ")
+	}
+
+	if deprecated.viaPlan {
+		return fmt.Sprintf("%s plan%s && %s up", bbl, flags, bbl), exact
+	}
+	return fmt.Sprintf("%s up%s", bbl, flags), exact
+}
+
+type certificateReader interface {
+	Read(certPath, keyPath, chainPath string) (certs.CertData, error)
+}
+
+type Deprecated struct {
+	command           string
+	certificateReader certificateReader
+	logger            logger
+}
+
+func NewDeprecated(command string, certificateReader certificateReader, logger logger) Deprecated {
+	return Deprecated{
+		command:           command,
+		certificateReader: certificateReader,
+		logger:            logger,
+	}
+}
+
+func (d Deprecated) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	return nil
+}
+
+func (d Deprecated) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	args := []string{}
+	for _, arg := range subcommandFlags {
+		if parts := strings.SplitN(arg, "=", 2); strings.HasPrefix(arg, "-") && len(parts) == 2 {
+			args = append(args, parts[0]+"="+shellQuote(parts[1]))
+			continue
+		}
+		args = append(args, shellQuote(arg))
+	}
+
+	replacement, _ := deprecatedReplacement("bbl", d.command, args, state.LB.Type)
+
+	// update-lbs updated the load balancer of the state. Pipelines that run it
+	// for every environment skip the ones without a load balancer with
+	// --skip-if-missing.
+	if deprecatedCommands[d.command].needsLBType && state.LB.Type == "" {
+		if hasBoolFlag(subcommandFlags, "skip-if-missing") {
+			d.logger.Println("The environment has no load balancer to update, skipping...")
+			return nil
+		}
+		return fmt.Errorf("bbl %s has been removed, and the environment has no load balancer to update. Pass --skip-if-missing to skip environments without one, or create one with:\n  %s", d.command, replacement)
+	}
+
+	// Pipelines also run update-lbs on every deploy with the certificate the
+	// load balancer already has.
+	if deprecatedCommands[d.command].needsLBType {
+		if fingerprint, ok := d.unchangedLB(subcommandFlags, state.LB); ok {
+			d.logger.Println(fmt.Sprintf("no change: the %s load balancer already has the certificate %s", state.LB.Type, fingerprint))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("bbl %s has been removed. Run this instead:\n  %s", d.command, replacement)
+}
+
+// unchangedLB reports whether the flags of update-lbs describe the load
+// balancer of the state, and returns the fingerprint of its certificate.
+func (d Deprecated) unchangedLB(args []string, lb storage.LB) (string, bool) {
+	var lbType, certPath, keyPath, chainPath, domain string
+	var skip bool
+
+	lbFlags := flags.New(d.command)
+	lbFlags.String(&lbType, "type", "")
+	lbFlags.String(&certPath, "cert", "")
+	lbFlags.String(&keyPath, "key", "")
+	lbFlags.String(&chainPath, "chain", "")
+	lbFlags.String(&domain, "domain", "")
+	lbFlags.Bool(&skip, "skip-if-exists", false)
+	lbFlags.Bool(&skip, "skip-if-missing", false)
+
+	if err := lbFlags.Parse(args); err != nil {
+		return "", false
+	}
+
+	if (lbType != "" && lbType != lb.Type) || (domain != "" && domain != lb.Domain) {
+		return "", false
+	}
+
+	certData, err := d.certificateReader.Read(certPath, keyPath, chainPath)
+	if err != nil {
+		return "", false
+	}
+
+	fingerprint, err := certs.Fingerprint(certData.Cert)
+	if err != nil {
+		return "", false
+	}
+	current, err := certs.Fingerprint([]byte(lb.Cert))
+	if err != nil {
+		return "", false
+	}
+
+	if fingerprint != current || string(certData.Key) != lb.Key || string(certData.Chain) != lb.Chain {
+		return "", false
+	}
+
+	return fingerprint, true
+}
+
+func hasBoolFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		switch strings.TrimLeft(arg, "-") {
+		case name, name + "=true":
+			return true
+		}
+	}
+	return false
+}
+
+func hasValueFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"$`\\|&;<>()*?![]{}") {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
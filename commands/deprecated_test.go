@@ -0,0 +1,157 @@
+package commands_test
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deprecated", func() {
+	var (
+		certificateValidator *fakes.CertificateValidator
+		logger               *fakes.Logger
+	)
+
+	BeforeEach(func() {
+		certificateValidator = &fakes.CertificateValidator{}
+		logger = &fakes.Logger{}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("does not fail", func() {
+			err := commands.NewDeprecated("create-lbs", certificateValidator, &fakes.Logger{}).CheckFastFails([]string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Execute", func() {
+		It("returns the replacement for create-lbs with translated flags", func() {
+			command := commands.NewDeprecated("create-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--type", "cf", "--cert", "/some/cert", "--key=/some/key", "--skip-if-exists"}, storage.State{})
+			Expect(err).To(MatchError("bbl create-lbs has been removed. Run this instead:\n  bbl plan --lb-type cf --lb-cert /some/cert --lb-key /some/key && bbl up"))
+		})
+
+		It("fills in the lb type from the state for update-lbs", func() {
+			command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--key", "/some/key"}, storage.State{LB: storage.LB{Type: "concourse"}})
+			Expect(err).To(MatchError("bbl update-lbs has been removed. Run this instead:\n  bbl plan --lb-type concourse --lb-cert /some/cert --lb-key /some/key && bbl up"))
+		})
+
+		It("returns the replacement for unsupported-create-lbs with translated flags", func() {
+			command := commands.NewDeprecated("unsupported-create-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--type", "concourse", "--cert", "/some/cert", "--key", "/some/key"}, storage.State{})
+			Expect(err).To(MatchError("bbl unsupported-create-lbs has been removed. Run this instead:\n  bbl plan --lb-type concourse --lb-cert /some/cert --lb-key /some/key && bbl up"))
+		})
+
+		It("fills in the lb type from the state for unsupported-update-lbs", func() {
+			command := commands.NewDeprecated("unsupported-update-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--key", "/some/key"}, storage.State{LB: storage.LB{Type: "cf"}})
+			Expect(err).To(MatchError("bbl unsupported-update-lbs has been removed. Run this instead:\n  bbl plan --lb-type cf --lb-cert /some/cert --lb-key /some/key && bbl up"))
+		})
+
+		It("keeps the lb type that update-lbs is given instead of the one of the state", func() {
+			command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--type=concourse", "--cert", "/some/cert"}, storage.State{LB: storage.LB{Type: "cf"}})
+			Expect(err).To(MatchError("bbl update-lbs has been removed. Run this instead:\n  bbl plan --lb-type concourse --lb-cert /some/cert && bbl up"))
+		})
+
+		It("explains that update-lbs has no load balancer to update when the state has none", func() {
+			command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--cert", "/some/cert"}, storage.State{})
+			Expect(err).To(MatchError("bbl update-lbs has been removed, and the environment has no load balancer to update. Pass --skip-if-missing to skip environments without one, or create one with:\n  bbl plan --lb-type <lb-type> --lb-cert /some/cert && bbl up"))
+		})
+
+		It("skips update-lbs with --skip-if-missing when the state has no load balancer", func() {
+			command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--skip-if-missing"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("The environment has no load balancer to update, skipping..."))
+		})
+
+		It("still returns the replacement for update-lbs with --skip-if-missing when the state has a load balancer", func() {
+			command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--skip-if-missing"}, storage.State{LB: storage.LB{Type: "cf"}})
+			Expect(err).To(MatchError("bbl update-lbs has been removed. Run this instead:\n  bbl plan --lb-type cf --lb-cert /some/cert && bbl up"))
+		})
+
+		Context("when update-lbs is given the certificate the load balancer already has", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				certificateValidator.ReadCall.Returns.CertData = certs.CertData{Cert: []byte(testhelpers.BBL_CERT), Key: []byte("some-key")}
+				state = storage.State{LB: storage.LB{Type: "cf", Cert: testhelpers.BBL_CERT, Key: "some-key", Domain: "cf.example.com"}}
+			})
+
+			It("prints that there is no change", func() {
+				command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+				err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--key", "/some/key", "--domain", "cf.example.com"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateValidator.ReadCall.Receives.CertificatePath).To(Equal("/some/cert"))
+				Expect(certificateValidator.ReadCall.Receives.KeyPath).To(Equal("/some/key"))
+				Expect(logger.PrintlnCall.Messages).To(ConsistOf("no change: the cf load balancer already has the certificate 47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A"))
+			})
+
+			It("returns the replacement when the certificate is another one", func() {
+				certificateValidator.ReadCall.Returns.CertData.Cert = []byte(testhelpers.OTHER_BBL_CERT)
+
+				command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+				err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--key", "/some/key"}, state)
+				Expect(err).To(MatchError("bbl update-lbs has been removed. Run this instead:\n  bbl plan --lb-type cf --lb-cert /some/cert --lb-key /some/key && bbl up"))
+			})
+
+			It("returns the replacement when the key is another one", func() {
+				certificateValidator.ReadCall.Returns.CertData.Key = []byte("other-key")
+
+				command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+				err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--key", "/some/key"}, state)
+				Expect(err).To(MatchError(ContainSubstring("bbl update-lbs has been removed.")))
+			})
+
+			It("returns the replacement when the domain changes", func() {
+				command := commands.NewDeprecated("update-lbs", certificateValidator, logger)
+				err := command.Execute(context.Background(), []string{"--cert", "/some/cert", "--key", "/some/key", "--domain", "other.example.com"}, state)
+				Expect(err).To(MatchError(ContainSubstring("bbl update-lbs has been removed.")))
+				Expect(certificateValidator.ReadCall.CallCount).To(Equal(0))
+			})
+		})
+
+		It("replaces delete-lbs with a plan without lb flags", func() {
+			command := commands.NewDeprecated("delete-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--skip-if-missing"}, storage.State{})
+			Expect(err).To(MatchError("bbl delete-lbs has been removed. Run this instead:\n  bbl plan && bbl up"))
+		})
+
+		It("replaces unsupported-deploy-bosh-on-aws-for-concourse with up", func() {
+			command := commands.NewDeprecated("unsupported-deploy-bosh-on-aws-for-concourse", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).To(MatchError("bbl unsupported-deploy-bosh-on-aws-for-concourse has been removed. Run this instead:\n  bbl up"))
+		})
+
+		It("quotes values that need quoting in a shell", func() {
+			command := commands.NewDeprecated("create-lbs", certificateValidator, logger)
+			err := command.Execute(context.Background(), []string{"--type", "cf", "--cert", "/some dir/cert", "--key=/some dir/key"}, storage.State{})
+			Expect(err).To(MatchError("bbl create-lbs has been removed. Run this instead:\n  bbl plan --lb-type cf --lb-cert '/some dir/cert' --lb-key '/some dir/key' && bbl up"))
+		})
+	})
+
+	Describe("DeprecatedCommandNames", func() {
+		It("returns the removed commands", func() {
+			Expect(commands.DeprecatedCommandNames()).To(Equal([]string{
+				"create-lbs",
+				"delete-lbs",
+				"unsupported-create-lbs",
+				"unsupported-deploy-bosh-on-aws-for-concourse",
+				"unsupported-update-lbs",
+				"update-lbs",
+			}))
+		})
+	})
+})
@@ -1,9 +1,15 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -17,10 +23,18 @@ type Destroy struct {
 	stateValidator           stateValidator
 	terraformManager         terraformManager
 	networkDeletionValidator NetworkDeletionValidator
+	leftovers                FilteredDeleter
+	reader                   fileio.FileReader
+	boshClientProvider       boshClientProvider
 }
 
-type destroyConfig struct {
-	NoConfirm bool
+// DestroyOptions are the flags of bbl destroy.
+type DestroyOptions struct {
+	NoConfirm     bool
+	SkipIfMissing bool
+	Discover      bool
+	EnvName       string
+	Force         bool
 }
 
 type NetworkDeletionValidator interface {
@@ -29,7 +43,8 @@ type NetworkDeletionValidator interface {
 
 func NewDestroy(plan plan, logger logger, boshManager boshManager, stateStore stateStore,
 	stateValidator stateValidator, terraformManager terraformManager,
-	networkDeletionValidator NetworkDeletionValidator) Destroy {
+	networkDeletionValidator NetworkDeletionValidator, leftovers FilteredDeleter, reader fileio.FileReader,
+	boshClientProvider boshClientProvider) Destroy {
 	return Destroy{
 		plan:                     plan,
 		logger:                   logger,
@@ -38,10 +53,28 @@ func NewDestroy(plan plan, logger logger, boshManager boshManager, stateStore st
 		stateValidator:           stateValidator,
 		terraformManager:         terraformManager,
 		networkDeletionValidator: networkDeletionValidator,
+		leftovers:                leftovers,
+		reader:                   reader,
+		boshClientProvider:       boshClientProvider,
 	}
 }
 
 func (d Destroy) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := d.parseArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return d.Check(config, state)
+}
+
+// Check returns why the environment of state cannot be destroyed, before
+// anything is deleted.
+func (d Destroy) Check(config DestroyOptions, state storage.State) error {
+	if config.Discover {
+		return checkDiscover(config, state)
+	}
+
 	err := fastFailBOSHVersion(d.boshManager)
 	if err != nil {
 		return err
@@ -54,6 +87,9 @@ func (d Destroy) CheckFastFails(subcommandFlags []string, state storage.State) e
 
 	err = d.stateValidator.Validate()
 	if err != nil {
+		if config.SkipIfMissing {
+			return nil
+		}
 		return err
 	}
 
@@ -97,8 +133,47 @@ func (d Destroy) CheckFastFails(subcommandFlags []string, state storage.State) e
 	return nil
 }
 
-func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
-	proceed := d.logger.Prompt(fmt.Sprintf("Are you sure you want to delete infrastructure for %q? This operation cannot be undone!", state.EnvID))
+// Execute tears down the director, jumpbox and infrastructure. With
+// --skip-if-missing, a component that cannot be deleted, usually because it
+// was already deleted out-of-band, is reported and the rest of the
+// environment is still torn down, so that the state is cleared either way.
+// Unless --force is given, a director that still has deployments is not
+// deleted, since their VMs would be left running without it.
+func (d Destroy) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := d.parseArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return d.Run(ctx, config, state)
+}
+
+// Run destroys the environment of state. It is bbl destroy without its
+// flags, for tools that embed bbl.
+func (d Destroy) Run(ctx context.Context, config DestroyOptions, state storage.State) error {
+	var err error
+
+	if config.Discover {
+		return d.discoverAndDelete(config.EnvName)
+	}
+
+	if config.SkipIfMissing {
+		if err := d.stateValidator.Validate(); err != nil {
+			d.logger.Step("state file not found, and --skip-if-missing flag provided, exiting")
+			return nil
+		}
+	}
+
+	if !config.Force {
+		err = d.checkDeployments(state, config.SkipIfMissing)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.listResources(state)
+
+	proceed := d.logger.PromptForName(fmt.Sprintf("Are you sure you want to delete infrastructure for %q? This operation cannot be undone!", state.EnvID), state.EnvID)
 	if !proceed {
 		d.logger.Step("exiting")
 		return nil
@@ -110,7 +185,6 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 			LB:   state.LB,
 		}
 
-		var err error
 		state, err = d.plan.InitializePlan(planConfig, state)
 		if err != nil {
 			return fmt.Errorf("Initialize plan during destroy: %s", err)
@@ -131,10 +205,14 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 
 	terraformOutputs, err := d.terraformManager.GetOutputs()
 	if err != nil {
-		return err
+		if !config.SkipIfMissing {
+			return err
+		}
+		d.skipMissing("Reading terraform outputs", err)
+		terraformOutputs = terraform.Outputs{}
 	}
 
-	state, err = d.deleteBOSH(state, terraformOutputs)
+	state, err = d.deleteBOSH(state, terraformOutputs, config.SkipIfMissing)
 	switch err.(type) {
 	case bosh.ManagerDeleteError:
 		mdErr := err.(bosh.ManagerDeleteError)
@@ -160,7 +238,11 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 
 	state, err = d.terraformManager.Destroy(state)
 	if err != nil {
-		return handleTerraformError(err, state, d.stateStore)
+		if !config.SkipIfMissing {
+			return handleTerraformError(err, state, d.stateStore)
+		}
+		d.skipMissing("Deleting the infrastructure", err)
+		d.logger.Printf("Some infrastructure may be left behind. Run bbl cleanup-leftovers --filter %s to find it.\n", state.EnvID)
 	}
 
 	if err := d.stateStore.Set(storage.State{}); err != nil {
@@ -170,7 +252,7 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 	return nil
 }
 
-func (d Destroy) deleteBOSH(state storage.State, terraformOutputs terraform.Outputs) (storage.State, error) {
+func (d Destroy) deleteBOSH(state storage.State, terraformOutputs terraform.Outputs, skipIfMissing bool) (storage.State, error) {
 	if state.NoDirector {
 		d.logger.Println("No BOSH director, skipping...")
 		return state, nil
@@ -178,17 +260,138 @@ func (d Destroy) deleteBOSH(state storage.State, terraformOutputs terraform.Outp
 
 	err := d.boshManager.DeleteDirector(state, terraformOutputs)
 	if err != nil {
-		return state, err
+		if !skipIfMissing {
+			return state, err
+		}
+		d.skipMissing("Deleting the BOSH director", err)
 	}
 
 	state.BOSH = storage.BOSH{}
 
 	err = d.boshManager.DeleteJumpbox(state, terraformOutputs)
 	if err != nil {
-		return state, err
+		if !skipIfMissing {
+			return state, err
+		}
+		d.skipMissing("Deleting the jumpbox", err)
 	}
 
 	state.Jumpbox = storage.Jumpbox{}
 
 	return state, nil
 }
+
+// checkDeployments refuses to delete a director that still has deployments.
+// A director that cannot be reached is only skipped with --skip-if-missing.
+func (d Destroy) checkDeployments(state storage.State, skipIfMissing bool) error {
+	if state.NoDirector || state.BOSH.DirectorAddress == "" {
+		return nil
+	}
+
+	var deployments []string
+	client, err := d.boshClientProvider.Client(state.Jumpbox, state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	if err == nil {
+		deployments, err = bosh.Deployments(client)
+	}
+	if err != nil {
+		if !skipIfMissing {
+			return fmt.Errorf("Could not check the deployments of the director: %s. Run bbl destroy --force to delete the environment without checking.", err)
+		}
+		d.skipMissing("Checking the deployments of the director", err)
+		return nil
+	}
+
+	if len(deployments) > 0 {
+		return fmt.Errorf("The director still has the deployments %s. Delete them first, or run bbl destroy --force to delete the environment anyway and leave their VMs behind.", strings.Join(deployments, ", "))
+	}
+
+	return nil
+}
+
+// listResources prints what destroy is about to delete: the director, the
+// jumpbox and the resources in the terraform state. A terraform state that
+// cannot be read is left out of the list, it does not stop the destroy.
+func (d Destroy) listResources(state storage.State) {
+	d.logger.Printf("bbl will delete the environment %s:\n", state.EnvID)
+	if !state.NoDirector && state.BOSH.DirectorAddress != "" {
+		d.logger.Printf("  the bosh director at %s\n", state.BOSH.DirectorAddress)
+	}
+	if state.Jumpbox.URL != "" {
+		d.logger.Printf("  the jumpbox at %s\n", state.Jumpbox.URL)
+	}
+
+	varsDir, err := d.stateStore.GetVarsDir()
+	if err != nil {
+		return
+	}
+	tfState, err := d.reader.ReadFile(filepath.Join(varsDir, "terraform.tfstate"))
+	if err != nil {
+		return
+	}
+	resources, err := terraform.ResourcesFromState(tfState)
+	if err != nil {
+		d.logger.Printf("  the infrastructure in the terraform state, which could not be read: %s\n", err)
+		return
+	}
+	for _, resource := range resources {
+		d.logger.Printf("  %s\n", resource)
+	}
+}
+
+func checkDiscover(config DestroyOptions, state storage.State) error {
+	if config.EnvName == "" {
+		return errors.New("--discover requires --env-name")
+	}
+
+	if state.EnvID != "" {
+		return fmt.Errorf("The state directory contains the environment %s. Run bbl destroy without --discover to delete it.", state.EnvID)
+	}
+
+	if state.IAAS == "vsphere" || state.IAAS == "openstack" {
+		return fmt.Errorf("--discover is not supported on %s", state.IAAS)
+	}
+
+	return nil
+}
+
+// discoverAndDelete tears down an environment whose state directory was
+// lost. bbl names and tags the resources it creates with the environment
+// name, so they are found by name and deleted, after the operator types the
+// name to confirm.
+func (d Destroy) discoverAndDelete(envName string) error {
+	d.logger.Printf("bbl will look for the resources of %q by name and delete every one whose name contains it, including the director and jumpbox VMs, key pairs and certificates.\n", envName)
+
+	input := d.logger.PromptForInput(fmt.Sprintf("Type %s to confirm", envName))
+	if input != envName {
+		d.logger.Step("exiting")
+		return nil
+	}
+
+	err := d.leftovers.Delete(envName)
+	if err != nil {
+		return fmt.Errorf("Delete resources of %s: %s", envName, err)
+	}
+
+	return nil
+}
+
+func (d Destroy) skipMissing(operation string, err error) {
+	d.logger.Printf("%s failed, continuing because of --skip-if-missing: %s\n", operation, err)
+}
+
+func (d Destroy) parseArgs(args []string) (DestroyOptions, error) {
+	var config DestroyOptions
+
+	destroyFlags := flags.New("destroy")
+	destroyFlags.Bool(&config.SkipIfMissing, "skip-if-missing", false)
+	destroyFlags.Bool(&config.Discover, "discover", false)
+	destroyFlags.String(&config.EnvName, "env-name", "")
+	destroyFlags.Bool(&config.Force, "force", false)
+
+	err := destroyFlags.Parse(args)
+	if err != nil {
+		return DestroyOptions{}, err
+	}
+
+	return config, nil
+}
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
@@ -24,11 +25,15 @@ var _ = Describe("Destroy", func() {
 		stateValidator           *fakes.StateValidator
 		terraformManager         *fakes.TerraformManager
 		networkDeletionValidator *fakes.NetworkDeletionValidator
+		leftovers                *fakes.FilteredDeleter
+		fileIO                   *fakes.FileIO
+		boshClientProvider       *fakes.BOSHClientProvider
+		boshClient               *fakes.BOSHClient
 	)
 
 	BeforeEach(func() {
 		logger = &fakes.Logger{}
-		logger.PromptCall.Returns.Proceed = true
+		logger.PromptForNameCall.Returns.Proceed = true
 
 		plan = &fakes.Plan{}
 		boshManager = &fakes.BOSHManager{}
@@ -37,12 +42,21 @@ var _ = Describe("Destroy", func() {
 		stateValidator = &fakes.StateValidator{}
 		terraformManager = &fakes.TerraformManager{}
 		networkDeletionValidator = &fakes.NetworkDeletionValidator{}
+		leftovers = &fakes.FilteredDeleter{}
+		fileIO = &fakes.FileIO{}
+		fileIO.ReadFileCall.Returns.Error = errors.New("no terraform state")
 
 		terraformManager.DestroyCall.Returns.BBLState = storage.State{ID: "some-state-id"}
 		terraformManager.IsPavedCall.Returns.IsPaved = true
 
+		boshClient = &fakes.BOSHClient{}
+		boshClient.CurlCall.Returns.Status = 200
+		boshClient.CurlCall.Returns.Body = []byte(`[]`)
+		boshClientProvider = &fakes.BOSHClientProvider{}
+		boshClientProvider.ClientCall.Returns.Client = boshClient
+
 		destroy = commands.NewDestroy(plan, logger, boshManager, stateStore,
-			stateValidator, terraformManager, networkDeletionValidator)
+			stateValidator, terraformManager, networkDeletionValidator, leftovers, fileIO, boshClientProvider)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -80,6 +94,21 @@ var _ = Describe("Destroy", func() {
 			})
 		})
 
+		Context("when the state file is missing and --skip-if-missing is passed", func() {
+			It("does not return the state validator error", func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("state file not found")
+
+				err := destroy.CheckFastFails([]string{"--skip-if-missing"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(networkDeletionValidator.ValidateSafeToDeleteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		It("returns flag parsing errors", func() {
+			err := destroy.CheckFastFails([]string{"--unknown-flag"}, storage.State{})
+			Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+		})
+
 		Context("when the environment is not paved", func() {
 			It("deletes the directory without attempting to destroy bosh or terraform", func() {
 				terraformManager.IsPavedCall.Returns.IsPaved = false
@@ -213,13 +242,72 @@ var _ = Describe("Destroy", func() {
 		})
 	})
 
+	Describe("--discover", func() {
+		Describe("CheckFastFails", func() {
+			It("does not require a state file", func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("no state")
+				err := destroy.CheckFastFails([]string{"--discover", "--env-name", "lost-env"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("requires --env-name", func() {
+				err := destroy.CheckFastFails([]string{"--discover"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--discover requires --env-name"))
+			})
+
+			It("returns an error when the state directory has an environment", func() {
+				err := destroy.CheckFastFails([]string{"--discover", "--env-name", "lost-env"}, storage.State{IAAS: "aws", EnvID: "some-env"})
+				Expect(err).To(MatchError("The state directory contains the environment some-env. Run bbl destroy without --discover to delete it."))
+			})
+
+			It("returns an error on iaases bbl cannot search", func() {
+				err := destroy.CheckFastFails([]string{"--discover", "--env-name", "lost-env"}, storage.State{IAAS: "vsphere"})
+				Expect(err).To(MatchError("--discover is not supported on vsphere"))
+			})
+		})
+
+		Describe("Execute", func() {
+			It("deletes the resources named after the environment once its name is typed", func() {
+				logger.PromptForInputCall.Returns.Input = "lost-env"
+
+				err := destroy.Execute(context.Background(), []string{"--discover", "--env-name", "lost-env"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PromptForInputCall.Receives.Message).To(Equal("Type lost-env to confirm"))
+				Expect(leftovers.DeleteCall.Receives.Filter).To(Equal("lost-env"))
+
+				Expect(logger.PromptForNameCall.CallCount).To(Equal(0))
+				Expect(terraformManager.DestroyCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("does not delete anything when the name does not match", func() {
+				logger.PromptForInputCall.Returns.Input = "y"
+
+				err := destroy.Execute(context.Background(), []string{"--discover", "--env-name", "lost-env"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(leftovers.DeleteCall.CallCount).To(Equal(0))
+				Expect(logger.StepCall.Receives.Message).To(Equal("exiting"))
+			})
+
+			It("returns an error when the resources cannot be deleted", func() {
+				logger.PromptForInputCall.Returns.Input = "lost-env"
+				leftovers.DeleteCall.Returns.Error = errors.New("persimmon")
+
+				err := destroy.Execute(context.Background(), []string{"--discover", "--env-name", "lost-env"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("Delete resources of lost-env: persimmon"))
+			})
+		})
+	})
+
 	Describe("Execute", func() {
 		BeforeEach(func() {
 			plan.IsInitializedCall.Returns.IsInitialized = true
 		})
 
-		It("prompts the user for confirmation", func() {
-			err := destroy.Execute([]string{}, storage.State{
+		It("has the user type the environment name to confirm", func() {
+			err := destroy.Execute(context.Background(), []string{}, storage.State{
 				BOSH: storage.BOSH{
 					DirectorName: "some-director",
 				},
@@ -227,17 +315,102 @@ var _ = Describe("Destroy", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(logger.PromptCall.Receives.Message).To(Equal(`Are you sure you want to delete infrastructure for "some-lake"? This operation cannot be undone!`))
+			Expect(logger.PromptForNameCall.Receives.Message).To(Equal(`Are you sure you want to delete infrastructure for "some-lake"? This operation cannot be undone!`))
+			Expect(logger.PromptForNameCall.Receives.Name).To(Equal("some-lake"))
 			Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(1))
 		})
 
+		It("lists what it is about to delete before the prompt", func() {
+			stateStore.GetVarsDirCall.Returns.Directory = "some-vars-dir"
+			fileIO.ReadFileCall.Returns.Error = nil
+			fileIO.ReadFileCall.Returns.Contents = []byte(`{"version": 3, "modules": [{"path": ["root"], "resources": {
+				"aws_elb.cf_router_lb": {"primary": {"id": "some-lake-cf-router-lb"}},
+				"aws_key_pair.bosh_vms": {"primary": {"id": "some-lake_bosh_vms"}}
+			}}]}`)
+
+			err := destroy.Execute(context.Background(), []string{}, storage.State{
+				EnvID:   "some-lake",
+				BOSH:    storage.BOSH{DirectorAddress: "https://10.0.0.6:25555"},
+				Jumpbox: storage.Jumpbox{URL: "10.0.0.5:22"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.ReadFileCall.Receives.Filename).To(Equal("some-vars-dir/terraform.tfstate"))
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"bbl will delete the environment some-lake:\n",
+				"  the bosh director at https://10.0.0.6:25555\n",
+				"  the jumpbox at 10.0.0.5:22\n",
+				"  aws_elb.cf_router_lb (some-lake-cf-router-lb)\n",
+				"  aws_key_pair.bosh_vms (some-lake_bosh_vms)\n",
+			}))
+		})
+
+		Context("when the director still has deployments", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				boshClient.CurlCall.Returns.Body = []byte(`[{"name": "cf"}, {"name": "concourse"}]`)
+				state = storage.State{
+					EnvID: "some-lake",
+					BOSH: storage.BOSH{
+						DirectorAddress:  "https://10.0.0.6:25555",
+						DirectorUsername: "some-username",
+						DirectorPassword: "some-password",
+						DirectorSSLCA:    "some-ca",
+					},
+					Jumpbox: storage.Jumpbox{URL: "10.0.0.5:22"},
+				}
+			})
+
+			It("refuses to delete the environment", func() {
+				err := destroy.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("The director still has the deployments cf, concourse. Delete them first, or run bbl destroy --force to delete the environment anyway and leave their VMs behind."))
+
+				Expect(boshClientProvider.ClientCall.Receives.Jumpbox).To(Equal(state.Jumpbox))
+				Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("https://10.0.0.6:25555"))
+				Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-username"))
+				Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-password"))
+				Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-ca"))
+				Expect(logger.PromptForNameCall.CallCount).To(Equal(0))
+				Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(0))
+			})
+
+			It("deletes the environment with --force", func() {
+				err := destroy.Execute(context.Background(), []string{"--force"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+				Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(1))
+			})
+
+			Context("when the director cannot be reached", func() {
+				BeforeEach(func() {
+					boshClientProvider.ClientCall.Returns.Error = errors.New("no route to host")
+				})
+
+				It("returns an error", func() {
+					err := destroy.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError("Could not check the deployments of the director: no route to host. Run bbl destroy --force to delete the environment without checking."))
+					Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(0))
+				})
+
+				It("continues with --skip-if-missing", func() {
+					err := destroy.Execute(context.Background(), []string{"--skip-if-missing"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCall.Messages).To(ContainElement("Checking the deployments of the director failed, continuing because of --skip-if-missing: no route to host\n"))
+					Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(1))
+				})
+			})
+		})
+
 		Context("when the user says no to the prompt", func() {
 			BeforeEach(func() {
-				logger.PromptCall.Returns.Proceed = false
+				logger.PromptForNameCall.Returns.Proceed = false
 			})
 
 			It("does not delete anything", func() {
-				err := destroy.Execute([]string{}, storage.State{
+				err := destroy.Execute(context.Background(), []string{}, storage.State{
 					BOSH: storage.BOSH{
 						DirectorName: "some-director",
 					},
@@ -245,7 +418,7 @@ var _ = Describe("Destroy", func() {
 				})
 				Expect(err).NotTo(HaveOccurred())
 
-				Expect(logger.PromptCall.Receives.Message).To(Equal(`Are you sure you want to delete infrastructure for "some-lake"? This operation cannot be undone!`))
+				Expect(logger.PromptForNameCall.CallCount).To(Equal(1))
 				Expect(logger.StepCall.Receives.Message).To(Equal("exiting"))
 				Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(0))
 			})
@@ -258,7 +431,7 @@ var _ = Describe("Destroy", func() {
 				},
 			}
 
-			err := destroy.Execute([]string{}, state)
+			err := destroy.Execute(context.Background(), []string{}, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(1))
@@ -284,7 +457,7 @@ var _ = Describe("Destroy", func() {
 				},
 			}
 
-			err := destroy.Execute([]string{}, state)
+			err := destroy.Execute(context.Background(), []string{}, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(plan.IsInitializedCall.CallCount).To(Equal(1))
@@ -305,7 +478,7 @@ var _ = Describe("Destroy", func() {
 					EnvID: "unintialized",
 					LB:    storage.LB{Type: "lb-type", Domain: "lb-domain"},
 				}
-				err := destroy.Execute([]string{}, state)
+				err := destroy.Execute(context.Background(), []string{}, state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(plan.IsInitializedCall.CallCount).To(Equal(1))
@@ -323,7 +496,7 @@ var _ = Describe("Destroy", func() {
 			It("deletes the directory without attempting to destroy bosh or terraform", func() {
 				terraformManager.IsPavedCall.Returns.IsPaved = false
 
-				err := destroy.Execute([]string{}, storage.State{})
+				err := destroy.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(boshManager.DeleteDirectorCall.CallCount).To(Equal(0))
 				Expect(boshManager.DeleteJumpboxCall.CallCount).To(Equal(0))
@@ -338,7 +511,7 @@ var _ = Describe("Destroy", func() {
 				It("returns an error", func() {
 					terraformManager.GetOutputsCall.Returns.Error = errors.New("nope")
 
-					err := destroy.Execute([]string{}, storage.State{})
+					err := destroy.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("nope"))
 				})
 			})
@@ -347,7 +520,7 @@ var _ = Describe("Destroy", func() {
 				It("returns an error", func() {
 					boshManager.DeleteDirectorCall.Returns.Error = errors.New("bosh delete-env failed")
 
-					err := destroy.Execute([]string{}, storage.State{
+					err := destroy.Execute(context.Background(), []string{}, storage.State{
 						BOSH: storage.BOSH{
 							DirectorName: "some-director",
 						},
@@ -360,7 +533,7 @@ var _ = Describe("Destroy", func() {
 				It("returns an error", func() {
 					stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to set state")}}
 
-					err := destroy.Execute([]string{}, storage.State{})
+					err := destroy.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("failed to set state"))
 				})
 			})
@@ -377,7 +550,7 @@ var _ = Describe("Destroy", func() {
 			})
 
 			It("calls terraform destroy and deletes the state file", func() {
-				err := destroy.Execute([]string{}, state)
+				err := destroy.Execute(context.Background(), []string{}, state)
 				Expect(err).NotTo(HaveOccurred())
 
 				expectedState := state
@@ -404,7 +577,7 @@ var _ = Describe("Destroy", func() {
 				})
 
 				It("saves the partially destroyed tf state", func() {
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).To(MatchError("failed to destroy"))
 
 					Expect(terraformManager.InitCall.CallCount).To(Equal(1))
@@ -419,7 +592,7 @@ var _ = Describe("Destroy", func() {
 				Context("when the state fails to be set", func() {
 					It("returns an error containing both messages", func() {
 						stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to set state")}}
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "gcp",
 						})
 
@@ -432,7 +605,7 @@ var _ = Describe("Destroy", func() {
 				Context("when NoDirector is true", func() {
 					It("does not attempt to delete the bosh director", func() {
 						state.NoDirector = true
-						err := destroy.Execute([]string{}, state)
+						err := destroy.Execute(context.Background(), []string{}, state)
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(logger.PrintlnCall.Receives.Message).To(Equal("No BOSH director, skipping..."))
@@ -444,6 +617,84 @@ var _ = Describe("Destroy", func() {
 			})
 		})
 
+		Context("when --skip-if-missing is passed", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS:  "aws",
+					EnvID: "some-env-id",
+					BOSH:  storage.BOSH{State: map[string]interface{}{"key": "value"}},
+				}
+			})
+
+			Context("when the state file is missing", func() {
+				BeforeEach(func() {
+					stateValidator.ValidateCall.Returns.Error = errors.New("state file not found")
+				})
+
+				It("exits without deleting anything", func() {
+					err := destroy.Execute(context.Background(), []string{"--skip-if-missing"}, storage.State{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.StepCall.Receives.Message).To(Equal("state file not found, and --skip-if-missing flag provided, exiting"))
+					Expect(logger.PromptForNameCall.CallCount).To(Equal(0))
+					Expect(terraformManager.DestroyCall.CallCount).To(Equal(0))
+				})
+			})
+
+			It("takes the options without flags", func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("state file not found")
+
+				err := destroy.Run(context.Background(), commands.DestroyOptions{SkipIfMissing: true}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.DestroyCall.CallCount).To(Equal(0))
+			})
+
+			Context("when parts of the environment were already deleted", func() {
+				BeforeEach(func() {
+					boshManager.DeleteDirectorCall.Returns.Error = errors.New("director vm not found")
+					boshManager.DeleteJumpboxCall.Returns.Error = errors.New("jumpbox vm not found")
+					terraformManager.DestroyCall.Returns.BBLState = state
+					terraformManager.DestroyCall.Returns.Error = errors.New("key pair not found")
+				})
+
+				It("tears down the rest and clears the state", func() {
+					err := destroy.Execute(context.Background(), []string{"--skip-if-missing"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshManager.DeleteJumpboxCall.CallCount).To(Equal(1))
+					Expect(terraformManager.DestroyCall.CallCount).To(Equal(1))
+					Expect(terraformManager.DestroyCall.Receives.BBLState.BOSH).To(Equal(storage.BOSH{}))
+
+					Expect(stateStore.SetCall.CallCount).To(Equal(2))
+					Expect(stateStore.SetCall.Receives[1].State).To(Equal(storage.State{}))
+
+					Expect(logger.PrintfCall.Messages).To(Equal([]string{
+						"bbl will delete the environment some-env-id:\n",
+						"Deleting the BOSH director failed, continuing because of --skip-if-missing: director vm not found\n",
+						"Deleting the jumpbox failed, continuing because of --skip-if-missing: jumpbox vm not found\n",
+						"Deleting the infrastructure failed, continuing because of --skip-if-missing: key pair not found\n",
+						"Some infrastructure may be left behind. Run bbl cleanup-leftovers --filter some-env-id to find it.\n",
+					}))
+				})
+			})
+
+			Context("when the terraform outputs cannot be read", func() {
+				BeforeEach(func() {
+					terraformManager.GetOutputsCall.Returns.Error = errors.New("no outputs")
+				})
+
+				It("continues with empty outputs", func() {
+					err := destroy.Execute(context.Background(), []string{"--skip-if-missing"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshManager.DeleteDirectorCall.Receives.TerraformOutputs).To(Equal(terraform.Outputs{}))
+					Expect(terraformManager.DestroyCall.CallCount).To(Equal(1))
+				})
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when bosh fails to delete the director", func() {
 				var state storage.State
@@ -467,7 +718,7 @@ var _ = Describe("Destroy", func() {
 					})
 
 					It("saves the bosh state and returns an error", func() {
-						err := destroy.Execute([]string{}, state)
+						err := destroy.Execute(context.Background(), []string{}, state)
 						Expect(err).To(MatchError("deletion failed"))
 						Expect(stateStore.SetCall.CallCount).To(Equal(1))
 						Expect(stateStore.SetCall.Receives[0].State).To(Equal(errState))
@@ -480,7 +731,7 @@ var _ = Describe("Destroy", func() {
 							}}
 						})
 						It("returns an error", func() {
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(MatchError("the following errors occurred:\ndeletion failed,\nsaving state failed"))
 						})
 					})
@@ -488,7 +739,7 @@ var _ = Describe("Destroy", func() {
 
 				It("returns an error", func() {
 					boshManager.DeleteDirectorCall.Returns.Error = errors.New("deletion failed")
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).To(MatchError("deletion failed"))
 				})
 			})
@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+const (
+	detachedLBFile      = "lb.json"
+	detachedLBStateFile = "terraform.tfstate"
+)
+
+// cfLBResources are the terraform resources that make up the cf load
+// balancer of an aws environment: the load balancers with their security
+// groups and certificate, the listeners and target groups of ELBv2, and the
+// DNS zone of the system domain with its records. A hosted zone of the user
+// stays where it is, only its records move.
+var cfLBResources = []string{
+	"aws_elb.cf_router_lb",
+	"aws_elb.cf_ssh_lb",
+	"aws_elb.cf_tcp_lb",
+	"aws_lb.cf_router_lb",
+	"aws_lb.cf_ssh_lb",
+	"aws_lb_listener.cf_router_lb_80",
+	"aws_lb_listener.cf_router_lb_443",
+	"aws_lb_listener.cf_router_lb_4443",
+	"aws_lb_listener.cf_ssh_lb_2222",
+	"aws_lb_target_group.cf_router_lb_80",
+	"aws_lb_target_group.cf_ssh_lb_2222",
+	"aws_security_group.cf_router_lb_security_group",
+	"aws_security_group.cf_router_lb_internal_security_group",
+	"aws_security_group.cf_ssh_lb_security_group",
+	"aws_security_group.cf_ssh_lb_internal_security_group",
+	"aws_security_group.cf_tcp_lb_security_group",
+	"aws_security_group.cf_tcp_lb_internal_security_group",
+	"aws_iam_server_certificate.lb_cert",
+	"aws_acm_certificate.lb_cert",
+	"aws_acm_certificate_validation.lb_cert",
+	"aws_route53_record.lb_cert_validation",
+	"aws_route53_zone.env_dns_zone",
+	"aws_route53_record.wildcard_dns",
+	"aws_route53_record.apps_wildcard_dns",
+	"aws_route53_record.ssh",
+	"aws_route53_record.bosh",
+	"aws_route53_record.tcp",
+	"aws_route53_record.iso",
+}
+
+type lbResourceMover interface {
+	GetOutputs() (terraform.Outputs, error)
+	ListResources() ([]string, error)
+	MoveResourcesOut(resources []string, toState string) error
+	MoveResourcesIn(fromState string, resources []string) error
+}
+
+type detachedLBFS interface {
+	fileio.FileReader
+	fileio.FileWriter
+	fileio.AllMkdirer
+	fileio.Stater
+}
+
+// detachedLB is what detach-lb writes next to the terraform state of the
+// load balancer, for adopt-lb to read.
+type detachedLB struct {
+	EnvID     string     `json:"envID"`
+	VPCID     string     `json:"vpcID"`
+	LB        storage.LB `json:"lb"`
+	Resources []string   `json:"resources"`
+}
+
+type lbMoveConfig struct {
+	dir string
+}
+
+// DetachLB moves the load balancer of an environment out of its terraform
+// state into a directory, so that another environment can adopt it without
+// the endpoint changing.
+type DetachLB struct {
+	stateValidator stateValidator
+	lbMover        lbResourceMover
+	stateStore     stateStore
+	fs             detachedLBFS
+	logger         logger
+}
+
+func NewDetachLB(stateValidator stateValidator, lbMover lbResourceMover, stateStore stateStore, fs detachedLBFS, logger logger) DetachLB {
+	return DetachLB{
+		stateValidator: stateValidator,
+		lbMover:        lbMover,
+		stateStore:     stateStore,
+		fs:             fs,
+		logger:         logger,
+	}
+}
+
+func (d DetachLB) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseLBMoveArgs("detach-lb", subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = d.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = checkLBMove("detach-lb", state)
+	if err != nil {
+		return err
+	}
+
+	if state.LB.Type == "" {
+		return errors.New("The environment has no load balancer to detach.")
+	}
+
+	return nil
+}
+
+func (d DetachLB) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseLBMoveArgs("detach-lb", subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(config.dir)
+	if err != nil {
+		return err //not tested
+	}
+	if _, err := d.fs.Stat(filepath.Join(dir, detachedLBFile)); err == nil {
+		return fmt.Errorf("%s already holds a detached load balancer.", config.dir)
+	}
+
+	outputs, err := d.lbMover.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("Get terraform outputs: %s", err)
+	}
+
+	resources, err := d.lbMover.ListResources()
+	if err != nil {
+		return err
+	}
+	lbResources := selectResources(resources, cfLBResources)
+
+	contents, err := json.MarshalIndent(detachedLB{
+		EnvID:     state.EnvID,
+		VPCID:     outputs.GetString("vpc_id"),
+		LB:        state.LB,
+		Resources: lbResources,
+	}, "", "  ")
+	if err != nil {
+		return err //not tested
+	}
+
+	err = d.fs.MkdirAll(dir, storage.StateMode)
+	if err != nil {
+		return fmt.Errorf("Create %s: %s", config.dir, err)
+	}
+	err = d.fs.WriteFile(filepath.Join(dir, detachedLBFile), contents, storage.StateMode)
+	if err != nil {
+		return fmt.Errorf("Write detached load balancer: %s", err)
+	}
+
+	err = d.lbMover.MoveResourcesOut(lbResources, filepath.Join(dir, detachedLBStateFile))
+	if err != nil {
+		return err
+	}
+
+	state.LB = storage.LB{}
+	err = d.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	d.logger.Println(fmt.Sprintf("Detached the cf load balancer into %s. Run bbl adopt-lb --dir %s in the environment that takes it over, then bbl up there.", config.dir, config.dir))
+	return nil
+}
+
+// AdoptLB moves a load balancer that detach-lb wrote into a directory into
+// the terraform state of the environment. The next bbl up moves it onto the
+// subnets of the environment and adds it to the cloud config.
+type AdoptLB struct {
+	stateValidator stateValidator
+	lbMover        lbResourceMover
+	stateStore     stateStore
+	fs             detachedLBFS
+	logger         logger
+}
+
+func NewAdoptLB(stateValidator stateValidator, lbMover lbResourceMover, stateStore stateStore, fs detachedLBFS, logger logger) AdoptLB {
+	return AdoptLB{
+		stateValidator: stateValidator,
+		lbMover:        lbMover,
+		stateStore:     stateStore,
+		fs:             fs,
+		logger:         logger,
+	}
+}
+
+func (a AdoptLB) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseLBMoveArgs("adopt-lb", subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = a.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = checkLBMove("adopt-lb", state)
+	if err != nil {
+		return err
+	}
+
+	if state.LB.Type != "" {
+		return fmt.Errorf("The environment already has a %s load balancer.", state.LB.Type)
+	}
+
+	return nil
+}
+
+func (a AdoptLB) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseLBMoveArgs("adopt-lb", subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(config.dir)
+	if err != nil {
+		return err //not tested
+	}
+
+	contents, err := a.fs.ReadFile(filepath.Join(dir, detachedLBFile))
+	if err != nil {
+		return fmt.Errorf("Read detached load balancer: %s", err)
+	}
+	var lb detachedLB
+	err = json.Unmarshal(contents, &lb)
+	if err != nil {
+		return fmt.Errorf("Read detached load balancer: %s", err)
+	}
+
+	// A classic load balancer only moves between subnets of its own VPC.
+	outputs, err := a.lbMover.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("Get terraform outputs: %s", err)
+	}
+	vpcID := outputs.GetString("vpc_id")
+	if vpcID != lb.VPCID {
+		return fmt.Errorf("The load balancer is in the VPC %s, but the environment is in %s. Plan the environment with --existing-vpc-id %s to adopt it.", lb.VPCID, vpcID, lb.VPCID)
+	}
+
+	err = a.lbMover.MoveResourcesIn(filepath.Join(dir, detachedLBStateFile), lb.Resources)
+	if err != nil {
+		return err
+	}
+
+	state.LB = lb.LB
+	err = a.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	a.logger.Println(fmt.Sprintf("Adopted the cf load balancer of %s. Run bbl up to move it onto the subnets of this environment, then redeploy the deployments behind it to register their VMs.", lb.EnvID))
+	return nil
+}
+
+func checkLBMove(command string, state storage.State) error {
+	if state.IAAS != "aws" {
+		return fmt.Errorf("%s only moves the load balancers of aws environments.", command)
+	}
+
+	if err := checkNotLite(command, state); err != nil {
+		return err
+	}
+
+	// The subnets of a network load balancer cannot be changed, so the
+	// concourse load balancer would be replaced by the next bbl up.
+	if state.LB.Type == "concourse" {
+		return fmt.Errorf("%s only moves cf load balancers. A concourse load balancer is replaced when it moves to other subnets.", command)
+	}
+
+	return nil
+}
+
+// selectResources picks the addresses of the state that are one of the
+// resources, or an instance of one.
+func selectResources(addresses, resources []string) []string {
+	selected := []string{}
+	for _, address := range addresses {
+		for _, resource := range resources {
+			if address == resource || strings.HasPrefix(address, resource+"[") || strings.HasPrefix(address, resource+".") {
+				selected = append(selected, address)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func parseLBMoveArgs(command string, args []string) (lbMoveConfig, error) {
+	var config lbMoveConfig
+
+	moveFlags := flags.New(command)
+	moveFlags.String(&config.dir, "dir", "")
+
+	err := moveFlags.Parse(args)
+	if err != nil {
+		return lbMoveConfig{}, err
+	}
+
+	if config.dir == "" {
+		return lbMoveConfig{}, errors.New("--dir is required")
+	}
+
+	return config, nil
+}
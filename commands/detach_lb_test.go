@@ -0,0 +1,258 @@
+package commands_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DetachLB", func() {
+	var (
+		stateValidator   *fakes.StateValidator
+		terraformManager *fakes.TerraformManager
+		stateStore       *fakes.StateStore
+		fileIO           *fakes.FileIO
+		logger           *fakes.Logger
+		command          commands.DetachLB
+
+		dir   string
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{"vpc_id": "vpc-1234"}}
+		terraformManager.ListResourcesCall.Returns.Resources = []string{
+			"aws_elb.cf_router_lb",
+			"aws_elb.cf_ssh_lb",
+			"aws_route53_record.iso[0]",
+			"aws_subnet.lb_subnets[0]",
+			"aws_vpc.vpc",
+		}
+		stateStore = &fakes.StateStore{}
+		fileIO = &fakes.FileIO{}
+		fileIO.StatCall.Returns.Error = errors.New("no such file")
+		logger = &fakes.Logger{}
+		command = commands.NewDetachLB(stateValidator, terraformManager, stateStore, fileIO, logger)
+
+		var err error
+		dir, err = filepath.Abs("some-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-old-env",
+			LB:    storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key", Domain: "cf.example.com"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires --dir", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--dir is required"))
+		})
+
+		It("only moves the load balancers of aws environments", func() {
+			state.IAAS = "gcp"
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("detach-lb only moves the load balancers of aws environments."))
+		})
+
+		It("does not apply to bosh-lite environments", func() {
+			state.AWS.Lite = true
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("detach-lb does not apply to bosh-lite environments, which have no load balancers."))
+		})
+
+		It("does not move concourse load balancers", func() {
+			state.LB.Type = "concourse"
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("detach-lb only moves cf load balancers. A concourse load balancer is replaced when it moves to other subnets."))
+		})
+
+		It("needs a load balancer", func() {
+			state.LB = storage.LB{}
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("The environment has no load balancer to detach."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("moves the resources of the load balancer into the directory and removes it from the state", func() {
+			err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.MkdirAllCall.Receives.Dir).To(Equal(dir))
+			Expect(fileIO.WriteFileCall.Receives).To(HaveLen(1))
+			written := fileIO.WriteFileCall.Receives[0]
+			Expect(written.Filename).To(Equal(filepath.Join(dir, "lb.json")))
+			Expect(written.Mode).To(Equal(os.FileMode(storage.StateMode)))
+
+			var detached map[string]interface{}
+			Expect(json.Unmarshal(written.Contents, &detached)).To(Succeed())
+			Expect(detached["envID"]).To(Equal("some-old-env"))
+			Expect(detached["vpcID"]).To(Equal("vpc-1234"))
+			Expect(detached["resources"]).To(Equal([]interface{}{"aws_elb.cf_router_lb", "aws_elb.cf_ssh_lb", "aws_route53_record.iso[0]"}))
+
+			Expect(terraformManager.MoveResourcesOutCall.Receives.Resources).To(Equal([]string{"aws_elb.cf_router_lb", "aws_elb.cf_ssh_lb", "aws_route53_record.iso[0]"}))
+			Expect(terraformManager.MoveResourcesOutCall.Receives.ToState).To(Equal(filepath.Join(dir, "terraform.tfstate")))
+
+			Expect(stateStore.SetCall.Receives[0].State.LB).To(Equal(storage.LB{}))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Detached the cf load balancer into some-dir. Run bbl adopt-lb --dir some-dir in the environment that takes it over, then bbl up there."))
+		})
+
+		Describe("failure cases", func() {
+			It("does not overwrite a detached load balancer", func() {
+				fileIO.StatCall.Returns.Error = nil
+
+				err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+				Expect(err).To(MatchError("some-dir already holds a detached load balancer."))
+				Expect(terraformManager.MoveResourcesOutCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the resources cannot be moved", func() {
+				terraformManager.MoveResourcesOutCall.Returns.Error = errors.New("state locked")
+
+				err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+				Expect(err).To(MatchError("state locked"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the directory cannot be written", func() {
+				fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("disk full")}}
+
+				err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+				Expect(err).To(MatchError("Write detached load balancer: disk full"))
+				Expect(terraformManager.MoveResourcesOutCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+})
+
+var _ = Describe("AdoptLB", func() {
+	var (
+		stateValidator   *fakes.StateValidator
+		terraformManager *fakes.TerraformManager
+		stateStore       *fakes.StateStore
+		fileIO           *fakes.FileIO
+		logger           *fakes.Logger
+		command          commands.AdoptLB
+
+		dir   string
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{"vpc_id": "vpc-1234"}}
+		stateStore = &fakes.StateStore{}
+		fileIO = &fakes.FileIO{}
+		fileIO.ReadFileCall.Returns.Contents = []byte(`{
+			"envID": "some-old-env",
+			"vpcID": "vpc-1234",
+			"lb": {"type": "cf", "cert": "some-cert", "key": "some-key", "domain": "cf.example.com"},
+			"resources": ["aws_elb.cf_router_lb", "aws_route53_zone.env_dns_zone"]
+		}`)
+		logger = &fakes.Logger{}
+		command = commands.NewAdoptLB(stateValidator, terraformManager, stateStore, fileIO, logger)
+
+		var err error
+		dir, err = filepath.Abs("some-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-new-env",
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("only moves the load balancers of aws environments", func() {
+			state.IAAS = "azure"
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("adopt-lb only moves the load balancers of aws environments."))
+		})
+
+		It("does not apply to bosh-lite environments", func() {
+			state.AWS.Lite = true
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("adopt-lb does not apply to bosh-lite environments, which have no load balancers."))
+		})
+
+		It("does not replace the load balancer of the environment", func() {
+			state.LB = storage.LB{Type: "cf"}
+
+			err := command.CheckFastFails([]string{"--dir", "some-dir"}, state)
+			Expect(err).To(MatchError("The environment already has a cf load balancer."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("moves the resources of the load balancer into the environment and adds it to the state", func() {
+			err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.ReadFileCall.Receives.Filename).To(Equal(filepath.Join(dir, "lb.json")))
+			Expect(terraformManager.MoveResourcesInCall.Receives.FromState).To(Equal(filepath.Join(dir, "terraform.tfstate")))
+			Expect(terraformManager.MoveResourcesInCall.Receives.Resources).To(Equal([]string{"aws_elb.cf_router_lb", "aws_route53_zone.env_dns_zone"}))
+
+			Expect(stateStore.SetCall.Receives[0].State.LB).To(Equal(storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key", Domain: "cf.example.com"}))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Adopted the cf load balancer of some-old-env. Run bbl up to move it onto the subnets of this environment, then redeploy the deployments behind it to register their VMs."))
+		})
+
+		Describe("failure cases", func() {
+			It("refuses a load balancer of another VPC", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{"vpc_id": "vpc-5678"}}
+
+				err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+				Expect(err).To(MatchError("The load balancer is in the VPC vpc-1234, but the environment is in vpc-5678. Plan the environment with --existing-vpc-id vpc-1234 to adopt it."))
+				Expect(terraformManager.MoveResourcesInCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the directory holds no detached load balancer", func() {
+				fileIO.ReadFileCall.Returns.Error = errors.New("no such file")
+
+				err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+				Expect(err).To(MatchError("Read detached load balancer: no such file"))
+			})
+
+			It("returns an error when the resources cannot be moved", func() {
+				terraformManager.MoveResourcesInCall.Returns.Error = errors.New("state locked")
+
+				err := command.Execute(context.Background(), []string{"--dir", "some-dir"}, state)
+				Expect(err).To(MatchError("state locked"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+})
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+// directorRollback redeploys a director whose upgrade failed verification
+// from the artifacts that it was deployed from before the upgrade.
+type directorRollback struct {
+	boshManager boshManager
+	stateStore  stateStore
+	logger      logger
+}
+
+// rollBack deploys the director of upgraded from previous, the artifacts
+// recorded before the upgrade, and returns the state of the rolled back
+// director. The director manifest is rendered again with the artifacts of
+// the plan afterwards, so that the next bbl up tries the upgrade again.
+func (r directorRollback) rollBack(upgraded storage.State, previous *storage.ArtifactOverrides, terraformOutputs terraform.Outputs) (storage.State, error) {
+	if previous == nil || upgraded.DeployedArtifacts == nil {
+		return storage.State{}, errors.New("the artifacts of the previous director were not recorded")
+	}
+	if *previous == *upgraded.DeployedArtifacts {
+		return storage.State{}, errors.New("the director already runs the artifacts it ran before the upgrade")
+	}
+
+	r.logger.Step("rolling back the director to its previous releases and stemcell")
+
+	rollback := upgraded
+	rollback.ArtifactOverrides = previous
+	err := r.boshManager.InitializeDirector(rollback)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Render director manifest: %s", err)
+	}
+
+	rolledBack, err := r.boshManager.CreateDirector(rollback, terraformOutputs)
+	switch err.(type) {
+	case bosh.ManagerCreateError:
+		bcErr := err.(bosh.ManagerCreateError)
+		failed := bcErr.State()
+		failed.ArtifactOverrides = upgraded.ArtifactOverrides
+		if setErr := r.stateStore.Set(failed); setErr != nil {
+			return storage.State{}, fmt.Errorf("Save state after bosh director create error: %s, %s", err, setErr)
+		}
+		return storage.State{}, err
+	case error:
+		return storage.State{}, err
+	}
+	rolledBack.ArtifactOverrides = upgraded.ArtifactOverrides
+
+	err = r.stateStore.Set(rolledBack)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Save state after rollback: %s", err)
+	}
+
+	err = r.boshManager.InitializeDirector(rolledBack)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Render director manifest: %s", err)
+	}
+
+	return rolledBack, nil
+}
+
+// verifyFailed is the error of an upgrade that failed verification, once
+// the director is rolled back or could not be.
+func (r directorRollback) verifyFailed(verifyErr error, upgraded storage.State, previous *storage.ArtifactOverrides, terraformOutputs terraform.Outputs) error {
+	rolledBack, err := r.rollBack(upgraded, previous, terraformOutputs)
+	if err != nil {
+		return fmt.Errorf("Verify director upgrade: %s. Roll back director: %s. The previous director can be restored by running bbl up with the bbl version that deployed it.", verifyErr, err)
+	}
+
+	version := "its previous version"
+	if rolledBack.DirectorVersions != nil {
+		version = "bosh " + rolledBack.DirectorVersions.BOSH
+	}
+	return fmt.Errorf("Verify director upgrade: %s. The director was rolled back to %s. Run bbl up to try the upgrade again.", verifyErr, version)
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type ArtifactDownloader interface {
+	Download(artifact bosh.Artifact, dir string) (bool, error)
+}
+
+type downloadArtifactsConfig struct {
+	dir string
+}
+
+// DownloadArtifacts downloads the releases and stemcells of the jumpbox and
+// director of an environment into a directory, on a machine with internet
+// access, so that bbl plan --artifacts-dir installs them from there on one
+// without.
+type DownloadArtifacts struct {
+	stateValidator stateValidator
+	downloader     ArtifactDownloader
+	logger         logger
+}
+
+func NewDownloadArtifacts(stateValidator stateValidator, downloader ArtifactDownloader, logger logger) DownloadArtifacts {
+	return DownloadArtifacts{
+		stateValidator: stateValidator,
+		downloader:     downloader,
+		logger:         logger,
+	}
+}
+
+func (d DownloadArtifacts) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseDownloadArtifactsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = d.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.NoDirector {
+		return errors.New("download-artifacts needs an environment with a director.")
+	}
+
+	return nil
+}
+
+func (d DownloadArtifacts) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseDownloadArtifactsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := createEnvArtifacts(state)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(config.dir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Create artifacts directory: %s", err)
+	}
+
+	for _, artifact := range artifacts {
+		d.logger.Step("downloading %s", artifact.FileName())
+		downloaded, err := d.downloader.Download(artifact, config.dir)
+		if err != nil {
+			return err
+		}
+		if !downloaded {
+			d.logger.Printf("%s is in %s already.\n", artifact.FileName(), config.dir)
+		}
+	}
+
+	d.logger.Println(fmt.Sprintf("The releases and stemcells of the jumpbox and director are in %s. Run bbl plan --artifacts-dir with a copy of it to install them without internet access.", config.dir))
+	return nil
+}
+
+// createEnvArtifacts lists the releases and stemcells of the jumpbox and the
+// director of the environment. A release that both use is listed once.
+func createEnvArtifacts(state storage.State) ([]bosh.Artifact, error) {
+	jumpbox, err := bosh.JumpboxArtifacts(state.IAAS)
+	if err != nil {
+		return nil, fmt.Errorf("Jumpbox artifacts: %s", err)
+	}
+
+	var overrides storage.ArtifactOverrides
+	if state.ArtifactOverrides != nil {
+		overrides = *state.ArtifactOverrides
+	}
+	director, err := bosh.DirectorArtifacts(state.IAAS, state.IAAS == "aws" && state.AWS.Lite, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("Director artifacts: %s", err)
+	}
+
+	artifacts := []bosh.Artifact{}
+	listed := map[string]bool{}
+	for _, artifact := range append(jumpbox.All(), director.All()...) {
+		if listed[artifact.FileName()] {
+			continue
+		}
+		listed[artifact.FileName()] = true
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+func parseDownloadArtifactsArgs(args []string) (downloadArtifactsConfig, error) {
+	var config downloadArtifactsConfig
+
+	downloadFlags := flags.New("download-artifacts")
+	downloadFlags.String(&config.dir, "dir", "")
+
+	err := downloadFlags.Parse(args)
+	if err != nil {
+		return downloadArtifactsConfig{}, err
+	}
+
+	if config.dir == "" {
+		return downloadArtifactsConfig{}, errors.New("--dir is required")
+	}
+
+	config.dir, err = filepath.Abs(config.dir)
+	if err != nil {
+		return downloadArtifactsConfig{}, err //not tested
+	}
+
+	return config, nil
+}
@@ -0,0 +1,124 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DownloadArtifacts", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		downloader     *fakes.ArtifactDownloader
+		logger         *fakes.Logger
+		command        commands.DownloadArtifacts
+
+		dir   string
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		downloader = &fakes.ArtifactDownloader{}
+		downloader.DownloadCall.Returns.Downloaded = true
+		logger = &fakes.Logger{}
+		command = commands.NewDownloadArtifacts(stateValidator, downloader, logger)
+
+		tempDir, err := ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+		dir = filepath.Join(tempDir, "artifacts")
+
+		state = storage.State{IAAS: "aws"}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(dir))
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--dir", dir}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires a directory", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--dir is required"))
+		})
+
+		It("returns an error for an environment without a director", func() {
+			state.NoDirector = true
+
+			err := command.CheckFastFails([]string{"--dir", dir}, state)
+			Expect(err).To(MatchError("download-artifacts needs an environment with a director."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("downloads the releases and stemcells of the jumpbox and director into the directory once each", func() {
+			err := command.Execute(context.Background(), []string{"--dir", dir}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dir).To(BeADirectory())
+
+			fileNames := []string{}
+			for _, receive := range downloader.DownloadCall.Receives {
+				Expect(receive.Dir).To(Equal(dir))
+				fileNames = append(fileNames, receive.Artifact.FileName())
+			}
+			Expect(fileNames).To(Equal([]string{
+				"os-conf-13.tgz",
+				"bosh-aws-cpi-69.tgz",
+				"bosh-aws-xen-hvm-ubuntu-trusty-go_agent-3468.17.tgz",
+				"bosh-264.7.0.tgz",
+				"os-conf-18.tgz",
+				"uaa-52.7.tgz",
+				"credhub-1.6.5.tgz",
+				"bosh-aws-xen-hvm-ubuntu-trusty-go_agent-3468.21.tgz",
+			}))
+
+			Expect(logger.StepCall.Messages).To(ContainElement("downloading bosh-264.7.0.tgz"))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("The releases and stemcells of the jumpbox and director are in " + dir + ". Run bbl plan --artifacts-dir with a copy of it to install them without internet access."))
+		})
+
+		It("downloads the artifacts that override the pinned ones", func() {
+			state.ArtifactOverrides = &storage.ArtifactOverrides{
+				BOSHReleaseURL:  "https://bosh.io/d/github.com/cloudfoundry/bosh?v=270.2.0",
+				BOSHReleaseSHA1: "some-bosh-sha1",
+			}
+
+			err := command.Execute(context.Background(), []string{"--dir", dir}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(downloader.DownloadCall.Receives[3].Artifact.FileName()).To(Equal("bosh-270.2.0.tgz"))
+			Expect(downloader.DownloadCall.Receives[3].Artifact.SHA1).To(Equal("some-bosh-sha1"))
+		})
+
+		It("says which artifacts were downloaded already", func() {
+			downloader.DownloadCall.Returns.Downloaded = false
+
+			err := command.Execute(context.Background(), []string{"--dir", dir}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("uaa-52.7.tgz is in " + dir + " already.\n"))
+		})
+
+		It("returns an error when a download fails", func() {
+			downloader.DownloadCall.Returns.Error = errors.New("The download of os-conf-13.tgz has the sha1 a instead of b.")
+
+			err := command.Execute(context.Background(), []string{"--dir", dir}, state)
+			Expect(err).To(MatchError("The download of os-conf-13.tgz has the sha1 a instead of b."))
+			Expect(downloader.DownloadCall.CallCount).To(Equal(1))
+		})
+	})
+})
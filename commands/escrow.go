@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type escrowFs interface {
+	fileio.DirReader
+	fileio.FileReader
+	fileio.FileWriter
+}
+
+// Escrow seals the SSH private keys and the director credentials of an
+// environment for a set of PGP recipients, for break-glass access. The
+// credentials are only written encrypted; a recipient reads them with
+// gpg --decrypt.
+type Escrow struct {
+	stateValidator stateValidator
+	sshKeyGetter   sshKeyGetter
+	fs             escrowFs
+	logger         logger
+}
+
+type escrowConfig struct {
+	recipients string
+	output     string
+}
+
+type escrowBundle struct {
+	EnvID                 string `yaml:"env_id"`
+	IAAS                  string `yaml:"iaas"`
+	JumpboxAddress        string `yaml:"jumpbox_address,omitempty"`
+	JumpboxSSHPrivateKey  string `yaml:"jumpbox_ssh_private_key"`
+	DirectorAddress       string `yaml:"director_address,omitempty"`
+	DirectorUsername      string `yaml:"director_username,omitempty"`
+	DirectorPassword      string `yaml:"director_password,omitempty"`
+	DirectorCACert        string `yaml:"director_ca_cert,omitempty"`
+	DirectorSSHPrivateKey string `yaml:"director_ssh_private_key,omitempty"`
+}
+
+func NewEscrow(stateValidator stateValidator, sshKeyGetter sshKeyGetter, fs escrowFs, logger logger) Escrow {
+	return Escrow{
+		stateValidator: stateValidator,
+		sshKeyGetter:   sshKeyGetter,
+		fs:             fs,
+		logger:         logger,
+	}
+}
+
+func (e Escrow) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := e.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseEscrowArgs(subcommandFlags, state)
+	return err
+}
+
+func (e Escrow) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseEscrowArgs(subcommandFlags, state)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := e.readRecipients(config.recipients)
+	if err != nil {
+		return err
+	}
+
+	bundle := escrowBundle{
+		EnvID:          state.EnvID,
+		IAAS:           state.IAAS,
+		JumpboxAddress: state.Jumpbox.URL,
+	}
+	bundle.JumpboxSSHPrivateKey, err = e.sshKeyGetter.Get("jumpbox")
+	if err != nil {
+		return fmt.Errorf("Get jumpbox ssh key: %s", err)
+	}
+	if !state.NoDirector {
+		bundle.DirectorAddress = state.BOSH.DirectorAddress
+		bundle.DirectorUsername = state.BOSH.DirectorUsername
+		bundle.DirectorPassword = state.BOSH.DirectorPassword
+		bundle.DirectorCACert = state.BOSH.DirectorSSLCA
+		bundle.DirectorSSHPrivateKey, err = e.sshKeyGetter.Get("director")
+		if err != nil {
+			return fmt.Errorf("Get director ssh key: %s", err)
+		}
+	}
+
+	plaintext, err := yaml.Marshal(bundle)
+	if err != nil {
+		return err //not tested
+	}
+
+	sealed, err := sealForRecipients(plaintext, recipients)
+	if err != nil {
+		return fmt.Errorf("Seal credentials: %s", err)
+	}
+
+	err = e.fs.WriteFile(config.output, sealed, 0600)
+	if err != nil {
+		return fmt.Errorf("Write sealed credentials: %s", err)
+	}
+
+	e.logger.Println(fmt.Sprintf("Sealed the credentials of %s for %d recipient(s) in %s. Recipients read them with: gpg --decrypt %s", state.EnvID, len(recipients), config.output, config.output))
+	return nil
+}
+
+// readRecipients reads the public keys of the recipients from the files in a
+// directory, armored or not. Hidden files and directories are skipped.
+func (e Escrow) readRecipients(dir string) (openpgp.EntityList, error) {
+	files, err := e.fs.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Read recipients: %s", err)
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+
+	recipients := openpgp.EntityList{}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		contents, err := e.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Read recipient %s: %s", path, err)
+		}
+
+		var keys openpgp.EntityList
+		if bytes.Contains(contents, []byte("-----BEGIN PGP")) {
+			keys, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(contents))
+		} else {
+			keys, err = openpgp.ReadKeyRing(bytes.NewReader(contents))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Read recipient %s: %s", path, err)
+		}
+		recipients = append(recipients, keys...)
+	}
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("There are no PGP public keys in %s.", dir)
+	}
+	return recipients, nil
+}
+
+func sealForRecipients(plaintext []byte, recipients openpgp.EntityList) ([]byte, error) {
+	var sealed bytes.Buffer
+	armored, err := armor.Encode(&sealed, "PGP MESSAGE", nil)
+	if err != nil {
+		return nil, err //not tested
+	}
+
+	writer, err := openpgp.Encrypt(armored, recipients, nil, &openpgp.FileHints{IsBinary: true}, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = writer.Write(plaintext)
+	if err != nil {
+		return nil, err //not tested
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err //not tested
+	}
+	err = armored.Close()
+	if err != nil {
+		return nil, err //not tested
+	}
+
+	return sealed.Bytes(), nil
+}
+
+func parseEscrowArgs(args []string, state storage.State) (escrowConfig, error) {
+	var config escrowConfig
+	escrowFlags := flags.New("escrow")
+	escrowFlags.String(&config.recipients, "recipients", "")
+	escrowFlags.String(&config.output, "output", "")
+
+	err := escrowFlags.Parse(args)
+	if err != nil {
+		return escrowConfig{}, err
+	}
+
+	if config.recipients == "" {
+		return escrowConfig{}, errors.New("--recipients is required: a directory of the PGP public keys to seal the credentials for.")
+	}
+	if config.output == "" {
+		config.output = fmt.Sprintf("%s-escrow.asc", state.EnvID)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,214 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+	yaml "gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Escrow", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		sshKeyGetter   *fakes.SSHKeyGetter
+		fileIO         *fakes.FileIO
+		logger         *fakes.Logger
+		command        commands.Escrow
+
+		recipient *openpgp.Entity
+		state     storage.State
+	)
+
+	newRecipient := func(name string) (*openpgp.Entity, []byte) {
+		entity, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{RSABits: 1024})
+		Expect(err).NotTo(HaveOccurred())
+
+		// gpg keys prefer SHA256 and AES256, which NewEntity does not sign.
+		for _, identity := range entity.Identities {
+			identity.SelfSignature.PreferredHash = []uint8{8}
+			identity.SelfSignature.PreferredSymmetric = []uint8{uint8(packet.CipherAES256)}
+			Expect(identity.SelfSignature.SignUserId(identity.UserId.Id, entity.PrimaryKey, entity.PrivateKey, nil)).To(Succeed())
+		}
+
+		var public bytes.Buffer
+		writer, err := armor.Encode(&public, openpgp.PublicKeyType, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entity.Serialize(writer)).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+		return entity, public.Bytes()
+	}
+
+	open := func(sealed []byte, recipients openpgp.EntityList) map[string]string {
+		block, err := armor.Decode(bytes.NewReader(sealed))
+		Expect(err).NotTo(HaveOccurred())
+		message, err := openpgp.ReadMessage(block.Body, recipients, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		plaintext, err := ioutil.ReadAll(message.UnverifiedBody)
+		Expect(err).NotTo(HaveOccurred())
+
+		bundle := map[string]string{}
+		Expect(yaml.Unmarshal(plaintext, &bundle)).To(Succeed())
+		return bundle
+	}
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		sshKeyGetter = &fakes.SSHKeyGetter{}
+		sshKeyGetter.GetCall.Stub = func(deployment string) (string, error) {
+			return "some-" + deployment + "-key", nil
+		}
+		fileIO = &fakes.FileIO{}
+		logger = &fakes.Logger{}
+		command = commands.NewEscrow(stateValidator, sshKeyGetter, fileIO, logger)
+
+		var publicKey []byte
+		recipient, publicKey = newRecipient("alice")
+		fileIO.ReadDirCall.Returns.FileInfos = []os.FileInfo{
+			fakes.FileInfo{FileName: "alice.asc"},
+			fakes.FileInfo{FileName: ".DS_Store"},
+		}
+		fileIO.ReadFileCall.Fake = func(path string) ([]byte, error) {
+			if path == "pgp-keys/alice.asc" {
+				return publicKey, nil
+			}
+			return nil, errors.New("unexpected file " + path)
+		}
+
+		state = storage.State{
+			EnvID:   "some-env-id",
+			IAAS:    "aws",
+			Jumpbox: storage.Jumpbox{URL: "10.0.0.5:22"},
+			BOSH: storage.BOSH{
+				DirectorAddress:  "https://10.0.0.6:25555",
+				DirectorUsername: "admin",
+				DirectorPassword: "some-password",
+				DirectorSSLCA:    "some-ca",
+			},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--recipients", "pgp-keys"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the recipients", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--recipients is required: a directory of the PGP public keys to seal the credentials for."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("seals the keys and the director credentials for the recipients", func() {
+			err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.ReadDirCall.Receives.Dirname).To(Equal("pgp-keys"))
+			Expect(fileIO.WriteFileCall.Receives).To(HaveLen(1))
+			Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal("some-env-id-escrow.asc"))
+			Expect(fileIO.WriteFileCall.Receives[0].Mode).To(Equal(os.FileMode(0600)))
+
+			sealed := fileIO.WriteFileCall.Receives[0].Contents
+			Expect(string(sealed)).NotTo(ContainSubstring("some-password"))
+			Expect(open(sealed, openpgp.EntityList{recipient})).To(Equal(map[string]string{
+				"env_id":                   "some-env-id",
+				"iaas":                     "aws",
+				"jumpbox_address":          "10.0.0.5:22",
+				"jumpbox_ssh_private_key":  "some-jumpbox-key",
+				"director_address":         "https://10.0.0.6:25555",
+				"director_username":        "admin",
+				"director_password":        "some-password",
+				"director_ca_cert":         "some-ca",
+				"director_ssh_private_key": "some-director-key",
+			}))
+
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Sealed the credentials of some-env-id for 1 recipient(s) in some-env-id-escrow.asc. Recipients read them with: gpg --decrypt some-env-id-escrow.asc"))
+		})
+
+		It("seals the credentials for every recipient", func() {
+			bob, bobKey := newRecipient("bob")
+			fileIO.ReadDirCall.Returns.FileInfos = append(fileIO.ReadDirCall.Returns.FileInfos, fakes.FileInfo{FileName: "bob.asc"})
+			aliceFake := fileIO.ReadFileCall.Fake
+			fileIO.ReadFileCall.Fake = func(path string) ([]byte, error) {
+				if path == "pgp-keys/bob.asc" {
+					return bobKey, nil
+				}
+				return aliceFake(path)
+			}
+
+			err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys", "--output", "escrow.asc"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal("escrow.asc"))
+			sealed := fileIO.WriteFileCall.Receives[0].Contents
+			Expect(open(sealed, openpgp.EntityList{recipient})).To(HaveKeyWithValue("director_password", "some-password"))
+			Expect(open(sealed, openpgp.EntityList{bob})).To(HaveKeyWithValue("director_password", "some-password"))
+		})
+
+		It("only seals the jumpbox key of an environment without a director", func() {
+			state.NoDirector = true
+
+			err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			bundle := open(fileIO.WriteFileCall.Receives[0].Contents, openpgp.EntityList{recipient})
+			Expect(bundle).To(HaveKeyWithValue("jumpbox_ssh_private_key", "some-jumpbox-key"))
+			Expect(bundle).NotTo(HaveKey("director_password"))
+			Expect(sshKeyGetter.GetCall.CallCount).To(Equal(1))
+		})
+
+		Describe("failure cases", func() {
+			It("returns an error when the recipients cannot be read", func() {
+				fileIO.ReadDirCall.Returns.Error = errors.New("no such directory")
+
+				err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+				Expect(err).To(MatchError("Read recipients: no such directory"))
+			})
+
+			It("returns an error when a recipient is not a public key", func() {
+				fileIO.ReadFileCall.Fake = func(string) ([]byte, error) { return []byte("not a key"), nil }
+
+				err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+				Expect(err).To(MatchError(ContainSubstring("Read recipient pgp-keys/alice.asc:")))
+			})
+
+			It("returns an error when there are no recipients", func() {
+				fileIO.ReadDirCall.Returns.FileInfos = []os.FileInfo{}
+
+				err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+				Expect(err).To(MatchError("There are no PGP public keys in pgp-keys."))
+			})
+
+			It("returns an error when an ssh key cannot be read", func() {
+				sshKeyGetter.GetCall.Stub = func(deployment string) (string, error) {
+					return "", errors.New("no vars")
+				}
+
+				err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+				Expect(err).To(MatchError("Get jumpbox ssh key: no vars"))
+				Expect(fileIO.WriteFileCall.Receives).To(BeEmpty())
+			})
+
+			It("returns an error when the sealed credentials cannot be written", func() {
+				fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("disk full")}}
+
+				err := command.Execute(context.Background(), []string{"--recipients", "pgp-keys"}, state)
+				Expect(err).To(MatchError("Write sealed credentials: disk full"))
+			})
+		})
+	})
+})
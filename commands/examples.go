@@ -0,0 +1,126 @@
+package commands
+
+// Example is a command line shown by --help-examples and in the man pages.
+type Example struct {
+	Description string
+	Command     string
+}
+
+var examples = map[string][]Example{
+	"up": {
+		{"Deploys a jumpbox and director on AWS", "bbl up --iaas aws --aws-region us-west-1 --aws-access-key-id KEY_ID --aws-secret-access-key SECRET"},
+		{"Deploys an environment with a CF load balancer", "bbl up --lb-type cf --lb-cert cf.crt --lb-key cf.key --lb-domain cf.example.com"},
+		{"Only creates the infrastructure, for a director managed elsewhere", "bbl up --no-director"},
+		{"Prints the infrastructure changes without making them", "bbl up --dry-run"},
+		{"Applies changes to existing infrastructure without asking, for example in CI", "bbl up --auto-approve"},
+	},
+	"plan": {
+		{"Writes the terraform templates and bosh scripts to customize before bbl up", "bbl plan --iaas gcp --gcp-region us-east1 --gcp-service-account-key key.json"},
+		{"Adds a Concourse load balancer", "bbl plan --lb-type concourse"},
+		{"Pins the availability zones of an AWS environment", "bbl plan --azs us-east-1a,us-east-1b"},
+		{"Lets operators log in with short-lived SSH certificates", "bbl plan --ssh-ca"},
+	},
+	"destroy": {
+		{"Deletes the environment in the state directory", "bbl destroy"},
+		{"Deletes the environment without asking for confirmation", "bbl destroy --no-confirm"},
+		{"Deletes an environment whose state directory was lost", "bbl destroy --discover --env-name bbl-env-malawi-2017-09-01 --iaas aws"},
+	},
+	"cleanup-leftovers": {
+		{"Deletes every resource whose name contains malawi, confirming each one", "bbl cleanup-leftovers --filter malawi --iaas aws"},
+	},
+	"create-lbs": {
+		{"Adds a CF load balancer now that create-lbs is removed", "bbl plan --lb-type cf --lb-cert cf.crt --lb-key cf.key && bbl up"},
+	},
+	"update-lbs": {
+		{"Replaces the certificate of the CF load balancer now that update-lbs is removed", "bbl plan --lb-type cf --lb-cert new.crt --lb-key new.key && bbl up"},
+	},
+	"delete-lbs": {
+		{"Removes the load balancers now that delete-lbs is removed", "bbl plan && bbl up"},
+	},
+	"rotate": {
+		{"Replaces the SSH key of the jumpbox user", "bbl rotate"},
+	},
+	"rotate-director-credentials": {
+		{"Replaces a leaked director password and redeploys the director", "bbl rotate-director-credentials"},
+	},
+	"copy-stemcell-ami": {
+		{"Copies the stemcell AMI into a region without one before creating the director", "bbl copy-stemcell-ami && bbl plan && bbl up"},
+	},
+	"upload-certificate": {
+		{"Uploads the next certificate ahead of switching the CF load balancer over to it", "bbl upload-certificate --lb-cert cf.crt --lb-key cf.key --lb-chain chain.crt && bbl plan --lb-type cf && bbl up"},
+	},
+	"apply": {
+		{"Converges the environment to the one in env.yml", "bbl apply env.yml"},
+	},
+	"clone": {
+		{"Creates another environment from the configuration of this one", "bbl --state-dir staging clone --from production --name staging"},
+	},
+	"detach-lb": {
+		{"Moves the cf load balancer from the old environment to the new one in the same VPC", "bbl --state-dir old detach-lb --dir lb && bbl --state-dir new adopt-lb --dir lb && bbl --state-dir new up"},
+	},
+	"adopt-lb": {
+		{"Adopts the cf load balancer that the old environment detached", "bbl adopt-lb --dir lb && bbl up"},
+	},
+	"migrate-region": {
+		{"Moves an AWS environment to another region", "bbl migrate-region --to us-east-2"},
+	},
+	"print-env": {
+		{"Targets the director with the bosh and credhub CLIs", `eval "$(bbl print-env)"`},
+	},
+	"bosh-deployment-vars": {
+		{"Creates the director of an AWS environment with bosh-deployment through the jumpbox", `eval "$(bbl print-env)" && bosh create-env bosh-deployment/bosh.yml --state state.json --vars-store creds.yml -o bosh-deployment/aws/cpi.yml -o bosh-deployment/jumpbox-user.yml -l <(bbl bosh-deployment-vars)`},
+	},
+	"ssh": {
+		{"Opens a shell on the jumpbox", "bbl ssh --jumpbox"},
+		{"Runs a single command on the director", `bbl ssh --director --cmd "sudo monit summary"`},
+	},
+	"ssh-cert": {
+		{"Issues a certificate for your key that is valid for 8 hours", "bbl ssh-cert issue --ttl 8h --public-key ~/.ssh/id_rsa.pub > ~/.ssh/id_rsa-cert.pub"},
+	},
+	"curl": {
+		{"Lists the deployments of the director", "bbl curl /deployments"},
+		{"Cancels a task", "bbl curl -X DELETE /tasks/42"},
+	},
+	"escrow": {
+		{"Seals the credentials for the keys of the on-call team", "bbl escrow --recipients pgp-keys/ --output escrow/prod.asc"},
+		{"Reads them in an emergency", "gpg --decrypt escrow/prod.asc"},
+	},
+	"annotate": {
+		{"Records the owner and cost center of the environment", "bbl annotate owner=platform-team cost-center=1234"},
+		{"Removes an annotation", "bbl annotate cost-center="},
+	},
+	"annotations": {
+		{"Prints the annotations as JSON for an inventory system", "bbl annotations --json"},
+	},
+	"cloud-config": {
+		{"Saves the cloud config to review it or upload it with the bosh CLI", "bbl cloud-config > cloud-config.yml"},
+	},
+	"lbs": {
+		{"Prints the load balancers as JSON", "bbl lbs --json"},
+	},
+	"director-ssh-key": {
+		{"Saves the director key for ssh", "bbl director-ssh-key > director.key && chmod 600 director.key"},
+	},
+	"credhub-password": {
+		{"Logs the credhub CLI in to the CredHub of the director", "credhub login --server $(bbl credhub-server) --client-name $(bbl credhub-client) --client-secret $(bbl credhub-password) --ca-cert \"$(bbl credhub-ca-cert)\""},
+	},
+	"man": {
+		{"Reads the manual of bbl up", "bbl man up > bbl-up.1 && man ./bbl-up.1"},
+		{"Writes the manual of every command to a directory", "bbl man --output-dir /usr/local/share/man/man1"},
+	},
+	"upgrade-director": {
+		{"Shows the versions that the director would move to", "bbl upgrade-director --dry-run"},
+		{"Upgrades the director after upgrading bbl", "bbl pre-upgrade-check && bbl upgrade-director"},
+	},
+	"completion": {
+		{"Completes bbl in the current bash shell", "source <(bbl completion bash)"},
+		{"Installs completion for zsh", `bbl completion zsh > "${fpath[1]}/_bbl"`},
+		{"Installs completion for fish", "bbl completion fish > ~/.config/fish/completions/bbl.fish"},
+	},
+}
+
+// Examples returns the examples for a command, or none when it has no
+// examples.
+func Examples(command string) []Example {
+	return examples[command]
+}
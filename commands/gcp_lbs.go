@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"strings"
@@ -20,7 +21,7 @@ func NewGCPLBs(terraformManager terraformManager, logger logger) GCPLBs {
 	}
 }
 
-func (l GCPLBs) Execute(subcommandFlags []string, state storage.State) error {
+func (l GCPLBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	terraformOutputs, err := l.terraformManager.GetOutputs()
 	if err != nil {
 		return err
@@ -59,7 +60,21 @@ func (l GCPLBs) Execute(subcommandFlags []string, state storage.State) error {
 			}
 		}
 	case "concourse":
-		l.logger.Printf("Concourse LB: %s\n", terraformOutputs.GetString("concourse_lb_ip"))
+		if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
+			lbOutput, err := json.Marshal(struct {
+				ConcourseLBIP string `json:"concourse_lb,omitempty"`
+			}{
+				ConcourseLBIP: terraformOutputs.GetString("concourse_lb_ip"),
+			})
+			if err != nil {
+				// not tested
+				return err
+			}
+
+			l.logger.Println(string(lbOutput))
+		} else {
+			l.logger.Printf("Concourse LB: %s\n", terraformOutputs.GetString("concourse_lb_ip"))
+		}
 	default:
 		return errors.New("no lbs found")
 	}
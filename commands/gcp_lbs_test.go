@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -42,7 +43,7 @@ var _ = Describe("GCPLBs", func() {
 			incomingState.LB = storage.LB{
 				Type: "cf",
 			}
-			err := command.Execute([]string{}, incomingState)
+			err := command.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -71,7 +72,7 @@ var _ = Describe("GCPLBs", func() {
 					Type:   "cf",
 					Domain: "some-domain",
 				}
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -90,7 +91,7 @@ var _ = Describe("GCPLBs", func() {
 						Type:   "cf",
 						Domain: "some-domain",
 					}
-					err := command.Execute([]string{"--json"}, incomingState)
+					err := command.Execute(context.Background(), []string{"--json"}, incomingState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
@@ -111,7 +112,7 @@ var _ = Describe("GCPLBs", func() {
 			incomingState.LB = storage.LB{
 				Type: "concourse",
 			}
-			err := command.Execute([]string{}, incomingState)
+			err := command.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -120,6 +121,16 @@ var _ = Describe("GCPLBs", func() {
 			}))
 		})
 
+		It("prints LB ips for lb type concourse as json", func() {
+			incomingState.LB = storage.LB{
+				Type: "concourse",
+			}
+			err := command.Execute(context.Background(), []string{"--json"}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{"concourse_lb": "some-concourse-lb-ip"}`))
+		})
+
 		Context("failure cases", func() {
 			Context("when terraform output provider fails", func() {
 				BeforeEach(func() {
@@ -127,7 +138,7 @@ var _ = Describe("GCPLBs", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, incomingState)
+					err := command.Execute(context.Background(), []string{}, incomingState)
 					Expect(err).To(MatchError("failed to return terraform output"))
 				})
 			})
@@ -140,7 +151,7 @@ var _ = Describe("GCPLBs", func() {
 				})
 
 				It("returns an nice error message", func() {
-					err := command.Execute([]string{}, incomingState)
+					err := command.Execute(context.Background(), []string{}, incomingState)
 					Expect(err).To(MatchError("no lbs found"))
 				})
 			})
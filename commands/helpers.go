@@ -2,7 +2,9 @@ package commands
 
 import (
 	"errors"
+	"sync"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -16,5 +18,29 @@ func handleTerraformError(err error, state storage.State, stateStore stateStore)
 		errorList.Add(setErr)
 	}
 
-	return errors.New(errorList.Error())
+	return bblerrors.New(bblerrors.KindOf(err), errors.New(errorList.Error()))
+}
+
+// runConcurrently runs the independent steps at the same time and returns
+// the error of the first step, in the order given, that failed.
+func runConcurrently(steps ...func() error) error {
+	errs := make([]error, len(steps))
+
+	var wg sync.WaitGroup
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step func() error) {
+			defer wg.Done()
+			errs[i] = step()
+		}(i, step)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/certs"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -9,15 +11,17 @@ import (
 type plan interface {
 	CheckFastFails([]string, storage.State) error
 	ParseArgs([]string, storage.State) (PlanConfig, error)
-	Execute([]string, storage.State) error
+	Execute(context.Context, []string, storage.State) error
 	InitializePlan(PlanConfig, storage.State) (storage.State, error)
 	IsInitialized(storage.State) bool
+	CheckLBWorkloads(PlanConfig, storage.State)
+	CheckLBRemoval(PlanConfig, storage.State) error
 }
 
 type up interface {
 	CheckFastFails([]string, storage.State) error
 	ParseArgs([]string, storage.State) (PlanConfig, error)
-	Execute([]string, storage.State) error
+	Execute(context.Context, []string, storage.State) error
 }
 
 type terraformManager interface {
@@ -25,8 +29,10 @@ type terraformManager interface {
 	GetOutputs() (terraform.Outputs, error)
 	Init(storage.State) error
 	Apply(storage.State) (storage.State, error)
+	Plan(storage.State) (string, error)
 	Destroy(storage.State) (storage.State, error)
 	IsPaved() (bool, error)
+	ListResources() ([]string, error)
 }
 
 type boshManager interface {
@@ -38,6 +44,8 @@ type boshManager interface {
 	DeleteJumpbox(bblState storage.State, terraformOutputs terraform.Outputs) error
 	GetDirectorDeploymentVars(bblState storage.State, terraformOutputs terraform.Outputs) string
 	GetJumpboxDeploymentVars(bblState storage.State, terraformOutputs terraform.Outputs) string
+	WriteDeploymentVars(bblState storage.State, terraformOutputs terraform.Outputs) error
+	IsDeployed(deployment string) (bool, error)
 	Version() (string, error)
 }
 
@@ -84,9 +92,12 @@ type createLBsCmd interface {
 
 type logger interface {
 	Step(string, ...interface{})
+	Dot()
 	Printf(string, ...interface{})
 	Println(string)
 	Prompt(string) bool
+	PromptForName(string, string) bool
+	PromptForInput(string) string
 }
 
 type stateStore interface {
@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type LatestError struct {
 	logger         logger
@@ -23,7 +27,7 @@ func (l LatestError) CheckFastFails(subcommandFlags []string, state storage.Stat
 	return nil
 }
 
-func (l LatestError) Execute(subcommandFlags []string, bblState storage.State) error {
+func (l LatestError) Execute(ctx context.Context, subcommandFlags []string, bblState storage.State) error {
 	l.logger.Println(bblState.LatestTFOutput)
 	return nil
 }
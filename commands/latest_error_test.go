@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -45,7 +46,7 @@ var _ = Describe("latest-error", func() {
 				LatestTFOutput: "some tf output",
 			}
 
-			err := command.Execute([]string{}, bblState)
+			err := command.Execute(context.Background(), []string{}, bblState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(logger.PrintlnCall.Messages).To(ContainElement("some tf output"))
@@ -4,21 +4,42 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/certs"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
+var (
+	acmCertificateARN = regexp.MustCompile(`^arn:aws[\w-]*:acm:[\w-]+:\d{12}:certificate/[\w-]+$`)
+	iamCertificateARN = regexp.MustCompile(`^arn:aws[\w-]*:iam::\d{12}:server-certificate/.+$`)
+	healthCheckTarget = regexp.MustCompile(`^((TCP|SSL):\d+|(HTTP|HTTPS):\d+/\S*)$`)
+)
+
 type LBArgsHandler struct {
 	certificateValidator certificateValidator
 }
 
 type LBArgs struct {
-	LBType    string
-	CertPath  string
-	KeyPath   string
-	ChainPath string
-	Domain    string
+	LBType     string
+	CertPath   string
+	KeyPath    string
+	ChainPath  string
+	CertARN    string
+	Domain     string
+	DNSRoleARN string
+	DNSZoneID  string
+	AppsDomain string
+	Internal   bool
+	ELBv2      bool
+	// ACMCertificate is --lb-acm-certificate, which requests the
+	// certificate of the domain from AWS Certificate Manager.
+	ACMCertificate bool
+	// HealthCheck is the --lb-health-check list of setting=value pairs.
+	HealthCheck string
 }
 
 func NewLBArgsHandler(certificateValidator certificateValidator) LBArgsHandler {
@@ -29,16 +50,78 @@ func NewLBArgsHandler(certificateValidator certificateValidator) LBArgsHandler {
 
 func (l LBArgsHandler) GetLBState(iaas string, args LBArgs) (storage.LB, error) {
 	if args.LBType == "" {
+		if args.HealthCheck != "" {
+			return storage.LB{}, errors.New("--lb-health-check requires --lb-type cf.")
+		}
+		if args.Internal {
+			return storage.LB{}, errors.New("--lb-internal requires --lb-type.")
+		}
+		if args.ELBv2 {
+			return storage.LB{}, errors.New("--lb-elbv2 requires --lb-type cf.")
+		}
+		if args.ACMCertificate {
+			return storage.LB{}, errors.New("--lb-acm-certificate requires --lb-type cf.")
+		}
 		return storage.LB{}, nil
 	}
 
+	if args.Internal && iaas != "aws" {
+		return storage.LB{}, errors.New("--lb-internal is only supported on aws.")
+	}
+
+	if args.ELBv2 && (iaas != "aws" || args.LBType != "cf") {
+		return storage.LB{}, errors.New("--lb-elbv2 is only supported for cf load balancers on aws. The concourse load balancer of aws is a network load balancer already.")
+	}
+
+	healthCheck, err := parseLBHealthCheck(iaas, args)
+	if err != nil {
+		return storage.LB{}, err
+	}
+
+	if args.DNSRoleARN != "" && args.Domain == "" {
+		return storage.LB{}, errors.New("--lb-dns-role-arn requires --lb-domain.")
+	}
+
+	if args.DNSZoneID != "" && args.Domain == "" {
+		return storage.LB{}, errors.New("--lb-dns-zone-id requires --lb-domain.")
+	}
+
+	if args.AppsDomain != "" {
+		if err := checkAppsDomain(args); err != nil {
+			return storage.LB{}, err
+		}
+	}
+
+	if args.ACMCertificate {
+		lb, err := getACMRequestLBState(iaas, args)
+		if err != nil {
+			return storage.LB{}, err
+		}
+		lb.Internal = args.Internal
+		lb.ELBv2 = args.ELBv2
+		lb.LBHealthCheck = healthCheck
+		return lb, nil
+	}
+
+	if args.CertARN != "" {
+		lb, err := l.getACMLBState(iaas, args)
+		if err != nil {
+			return storage.LB{}, err
+		}
+		lb.DNSZoneID = args.DNSZoneID
+		lb.AppsDomain = args.AppsDomain
+		lb.Internal = args.Internal
+		lb.ELBv2 = args.ELBv2
+		lb.LBHealthCheck = healthCheck
+		return lb, nil
+	}
+
 	var certData certs.CertData
-	var err error
 
 	if iaas == "azure" && args.LBType == "cf" {
 		certData, err = l.certificateValidator.ReadAndValidatePKCS12(args.CertPath, args.KeyPath)
 		if err != nil {
-			return storage.LB{}, fmt.Errorf("Validate certificate: %s", err)
+			return storage.LB{}, bblerrors.New(bblerrors.Validation, fmt.Errorf("Validate certificate: %s", err))
 		}
 
 		return storage.LB{
@@ -52,7 +135,7 @@ func (l LBArgsHandler) GetLBState(iaas string, args LBArgs) (storage.LB, error)
 	if args.LBType != "concourse" {
 		certData, err = l.certificateValidator.ReadAndValidate(args.CertPath, args.KeyPath, args.ChainPath)
 		if err != nil {
-			return storage.LB{}, fmt.Errorf("Validate certificate: %s", err)
+			return storage.LB{}, bblerrors.New(bblerrors.Validation, fmt.Errorf("Validate certificate: %s", err))
 		}
 	}
 
@@ -61,18 +144,177 @@ func (l LBArgsHandler) GetLBState(iaas string, args LBArgs) (storage.LB, error)
 	}
 
 	return storage.LB{
-		Type:   args.LBType,
-		Cert:   string(certData.Cert),
-		Key:    string(certData.Key),
-		Chain:  string(certData.Chain),
-		Domain: args.Domain,
+		Type:          args.LBType,
+		Cert:          string(certData.Cert),
+		Key:           string(certData.Key),
+		Chain:         string(certData.Chain),
+		Domain:        args.Domain,
+		DNSRoleARN:    args.DNSRoleARN,
+		DNSZoneID:     args.DNSZoneID,
+		AppsDomain:    args.AppsDomain,
+		Internal:      args.Internal,
+		ELBv2:         args.ELBv2,
+		LBHealthCheck: healthCheck,
+	}, nil
+}
+
+// getACMLBState references a certificate that already exists in AWS
+// Certificate Manager, or in IAM, instead of uploading one to IAM.
+func (l LBArgsHandler) getACMLBState(iaas string, args LBArgs) (storage.LB, error) {
+	if iaas != "aws" || args.LBType != "cf" {
+		return storage.LB{}, errors.New("--lb-cert-arn is only supported for cf load balancers on aws.")
+	}
+
+	if args.CertPath != "" || args.KeyPath != "" || args.ChainPath != "" {
+		return storage.LB{}, errors.New("--lb-cert-arn cannot be used with --lb-cert, --lb-key or --lb-chain.")
+	}
+
+	if !acmCertificateARN.MatchString(args.CertARN) && !iamCertificateARN.MatchString(args.CertARN) {
+		return storage.LB{}, fmt.Errorf("%q is not the ARN of an AWS Certificate Manager certificate or an IAM server certificate.", args.CertARN)
+	}
+
+	return storage.LB{
+		Type:       args.LBType,
+		CertARN:    args.CertARN,
+		Domain:     args.Domain,
+		DNSRoleARN: args.DNSRoleARN,
 	}, nil
 }
 
+// getACMRequestLBState has terraform request a certificate of the domain and
+// its wildcard from AWS Certificate Manager, and validate it with a record in
+// the hosted zone of the domain. The certificate only covers the system
+// domain, so an apps domain needs a certificate of the user.
+func getACMRequestLBState(iaas string, args LBArgs) (storage.LB, error) {
+	if iaas != "aws" || args.LBType != "cf" {
+		return storage.LB{}, errors.New("--lb-acm-certificate is only supported for cf load balancers on aws.")
+	}
+
+	if args.CertPath != "" || args.KeyPath != "" || args.ChainPath != "" || args.CertARN != "" {
+		return storage.LB{}, errors.New("--lb-acm-certificate cannot be used with --lb-cert, --lb-key, --lb-chain or --lb-cert-arn.")
+	}
+
+	if args.Domain == "" {
+		return storage.LB{}, errors.New("--lb-acm-certificate requires --lb-domain, whose hosted zone validates the certificate.")
+	}
+
+	if args.AppsDomain != "" {
+		return storage.LB{}, errors.New("--lb-acm-certificate only covers --lb-domain. Pass the certificate of both domains with --lb-cert-arn to use --lb-apps-domain.")
+	}
+
+	return storage.LB{
+		Type:           args.LBType,
+		ACMCertificate: true,
+		Domain:         args.Domain,
+		DNSRoleARN:     args.DNSRoleARN,
+		DNSZoneID:      args.DNSZoneID,
+	}, nil
+}
+
+// checkAppsDomain checks that the records of the apps domain fit into the
+// hosted zone. A zone that bbl creates is the zone of the system domain, so
+// without a zone of the user the apps domain must be under the system domain.
+func checkAppsDomain(args LBArgs) error {
+	if args.Domain == "" {
+		return errors.New("--lb-apps-domain requires --lb-domain.")
+	}
+
+	if !sniDomain.MatchString(args.AppsDomain) || strings.HasPrefix(args.AppsDomain, "*.") {
+		return fmt.Errorf("--lb-apps-domain %q is not a domain name.", args.AppsDomain)
+	}
+
+	if args.DNSZoneID == "" && !strings.HasSuffix(args.AppsDomain, "."+args.Domain) {
+		return fmt.Errorf("--lb-apps-domain %q is outside of the hosted zone of %s. Pass --lb-dns-zone-id with a hosted zone that holds both domains.", args.AppsDomain, args.Domain)
+	}
+
+	return nil
+}
+
+// healthCheckLimits are the values that classic load balancers accept for the
+// settings of a health check.
+var healthCheckLimits = map[string]struct {
+	min, max int
+	unit     string
+}{
+	"interval":            {5, 300, " seconds"},
+	"timeout":             {2, 60, " seconds"},
+	"healthy-threshold":   {2, 10, ""},
+	"unhealthy-threshold": {2, 10, ""},
+}
+
+// parseLBHealthCheck reads the --lb-health-check setting=value pairs. The
+// timeout is compared with the interval of the template when the interval is
+// not set.
+func parseLBHealthCheck(iaas string, args LBArgs) (storage.LBHealthCheck, error) {
+	if args.HealthCheck == "" {
+		return storage.LBHealthCheck{}, nil
+	}
+
+	if iaas != "aws" || args.LBType != "cf" {
+		return storage.LBHealthCheck{}, errors.New("--lb-health-check is only supported for cf load balancers on aws.")
+	}
+
+	healthCheck := storage.LBHealthCheck{}
+	for _, pair := range strings.Split(args.HealthCheck, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check %q is not a setting=value pair.", pair)
+		}
+
+		if parts[0] == "target" {
+			if !healthCheckTarget.MatchString(parts[1]) {
+				return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check target %q is not TCP:port, SSL:port, HTTP:port/path or HTTPS:port/path.", parts[1])
+			}
+			if args.ELBv2 && !strings.HasPrefix(parts[1], "HTTP") {
+				return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check target %q is not HTTP:port/path or HTTPS:port/path, which the target group of an ELBv2 router checks.", parts[1])
+			}
+			healthCheck.HealthCheckTarget = parts[1]
+			continue
+		}
+
+		limits, ok := healthCheckLimits[parts[0]]
+		if !ok {
+			return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check %q is not one of target, interval, timeout, healthy-threshold or unhealthy-threshold.", parts[0])
+		}
+
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check %s %q is not a number.", parts[0], parts[1])
+		}
+		if value < limits.min || value > limits.max {
+			return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check %s must be between %d and %d%s.", parts[0], limits.min, limits.max, limits.unit)
+		}
+
+		switch parts[0] {
+		case "interval":
+			healthCheck.HealthCheckInterval = value
+		case "timeout":
+			healthCheck.HealthCheckTimeout = value
+		case "healthy-threshold":
+			healthCheck.HealthyThreshold = value
+		case "unhealthy-threshold":
+			healthCheck.UnhealthyThreshold = value
+		}
+	}
+
+	interval := healthCheck.HealthCheckInterval
+	if interval == 0 {
+		interval = 12
+	}
+	if healthCheck.HealthCheckTimeout >= interval {
+		return storage.LBHealthCheck{}, fmt.Errorf("--lb-health-check timeout must be shorter than the interval of %d seconds.", interval)
+	}
+
+	return healthCheck, nil
+}
+
 func (l LBArgsHandler) Merge(new storage.LB, old storage.LB) storage.LB {
 	if old.Type != "" {
 		if new.Domain == "" {
 			new.Domain = old.Domain
+			new.DNSRoleARN = old.DNSRoleARN
+			new.DNSZoneID = old.DNSZoneID
+			new.AppsDomain = old.AppsDomain
 		}
 
 		if new.Type == "" {
@@ -101,6 +101,182 @@ var _ = Describe("LBArgsHandler", func() {
 			})
 		})
 
+		Context("when an ACM certificate arn is provided", func() {
+			It("returns a storage.LB object that references the certificate", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:  "cf",
+					CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+					Domain:  "something.io",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState).To(Equal(storage.LB{
+					Type:    "cf",
+					CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+					Domain:  "something.io",
+				}))
+				Expect(certificateValidator.ReadAndValidateCall.CallCount).To(Equal(0))
+			})
+
+			It("also references an IAM server certificate", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:  "cf",
+					CertARN: "arn:aws:iam::123456789012:server-certificate/some-cert",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.CertARN).To(Equal("arn:aws:iam::123456789012:server-certificate/some-cert"))
+			})
+		})
+
+		Context("when an ACM certificate is requested", func() {
+			It("returns a storage.LB object that requests the certificate of the domain", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:         "cf",
+					Domain:         "something.io",
+					DNSZoneID:      "Z123",
+					ACMCertificate: true,
+					ELBv2:          true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState).To(Equal(storage.LB{
+					Type:           "cf",
+					Domain:         "something.io",
+					DNSZoneID:      "Z123",
+					ACMCertificate: true,
+					ELBv2:          true,
+				}))
+				Expect(certificateValidator.ReadAndValidateCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when a dns role arn is provided", func() {
+			It("returns a storage.LB object that references the role", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:     "cf",
+					CertPath:   "/path/to/cert",
+					KeyPath:    "/path/to/key",
+					Domain:     "something.io",
+					DNSRoleARN: "arn:aws:iam::123456789012:role/dns",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.Domain).To(Equal("something.io"))
+				Expect(lbState.DNSRoleARN).To(Equal("arn:aws:iam::123456789012:role/dns"))
+			})
+		})
+
+		Context("when a health check is provided", func() {
+			It("returns a storage.LB object with the health check", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:      "cf",
+					CertPath:    "/path/to/cert",
+					KeyPath:     "/path/to/key",
+					HealthCheck: "target=HTTP:8080/health, interval=10,healthy-threshold=2,unhealthy-threshold=3,timeout=5",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.LBHealthCheck).To(Equal(storage.LBHealthCheck{
+					HealthCheckTarget:   "HTTP:8080/health",
+					HealthCheckInterval: 10,
+					HealthyThreshold:    2,
+					UnhealthyThreshold:  3,
+					HealthCheckTimeout:  5,
+				}))
+				Expect(lbState.HealthCheckPort()).To(Equal(8080))
+			})
+
+			It("keeps the health check of a load balancer that references a certificate", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:      "cf",
+					CertARN:     "arn:aws:iam::123456789012:server-certificate/some-cert",
+					HealthCheck: "target=TCP:8080",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.LBHealthCheck).To(Equal(storage.LBHealthCheck{HealthCheckTarget: "TCP:8080"}))
+			})
+		})
+
+		Context("when a hosted zone and apps domain are provided", func() {
+			It("returns a storage.LB object that references them", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:     "cf",
+					CertARN:    "arn:aws:iam::123456789012:server-certificate/some-cert",
+					Domain:     "sys.example.com",
+					DNSZoneID:  "Z123",
+					AppsDomain: "apps.example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.DNSZoneID).To(Equal("Z123"))
+				Expect(lbState.AppsDomain).To(Equal("apps.example.com"))
+			})
+
+			It("allows an apps domain under the system domain without a hosted zone", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:     "cf",
+					CertPath:   "/path/to/cert",
+					KeyPath:    "/path/to/key",
+					Domain:     "example.com",
+					AppsDomain: "apps.example.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.AppsDomain).To(Equal("apps.example.com"))
+			})
+		})
+
+		Context("when the load balancer is internal", func() {
+			It("returns a storage.LB object that is internal", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:   "cf",
+					CertPath: "/path/to/cert",
+					KeyPath:  "/path/to/key",
+					Internal: true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.Internal).To(BeTrue())
+
+				lbState, err = handler.GetLBState("aws", commands.LBArgs{LBType: "concourse", Internal: true})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.Internal).To(BeTrue())
+			})
+
+			It("returns an error without a load balancer type", func() {
+				_, err := handler.GetLBState("aws", commands.LBArgs{Internal: true})
+				Expect(err).To(MatchError("--lb-internal requires --lb-type."))
+			})
+
+			It("returns an error when the iaas is not aws", func() {
+				_, err := handler.GetLBState("gcp", commands.LBArgs{LBType: "concourse", Internal: true})
+				Expect(err).To(MatchError("--lb-internal is only supported on aws."))
+			})
+		})
+
+		Context("when the load balancers are ELBv2", func() {
+			It("returns a storage.LB object that is ELBv2", func() {
+				lbState, err := handler.GetLBState("aws", commands.LBArgs{
+					LBType:      "cf",
+					CertPath:    "/path/to/cert",
+					KeyPath:     "/path/to/key",
+					ELBv2:       true,
+					HealthCheck: "target=HTTP:8080/health",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lbState.ELBv2).To(BeTrue())
+				Expect(lbState.HealthCheckTarget).To(Equal("HTTP:8080/health"))
+			})
+
+			It("returns an error without a load balancer type", func() {
+				_, err := handler.GetLBState("aws", commands.LBArgs{ELBv2: true})
+				Expect(err).To(MatchError("--lb-elbv2 requires --lb-type cf."))
+			})
+
+			It("returns an error for a concourse load balancer", func() {
+				_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "concourse", ELBv2: true})
+				Expect(err).To(MatchError("--lb-elbv2 is only supported for cf load balancers on aws. The concourse load balancer of aws is a network load balancer already."))
+			})
+
+			It("returns an error for a health check target that a target group cannot check", func() {
+				_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", ELBv2: true, HealthCheck: "target=TCP:80"})
+				Expect(err).To(MatchError(`--lb-health-check target "TCP:80" is not HTTP:port/path or HTTPS:port/path, which the target group of an ELBv2 router checks.`))
+			})
+		})
+
 		Context("when empty config is passed in", func() {
 			It("does not call certificateValidator", func() {
 				_, err := handler.GetLBState("", commands.LBArgs{})
@@ -128,6 +304,120 @@ var _ = Describe("LBArgsHandler", func() {
 				})
 			})
 
+			Context("when an ACM certificate arn is provided", func() {
+				It("returns an error when the iaas is not aws", func() {
+					_, err := handler.GetLBState("gcp", commands.LBArgs{LBType: "cf", CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012"})
+					Expect(err).To(MatchError("--lb-cert-arn is only supported for cf load balancers on aws."))
+				})
+
+				It("returns an error when the lb type is not cf", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "concourse", CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012"})
+					Expect(err).To(MatchError("--lb-cert-arn is only supported for cf load balancers on aws."))
+				})
+
+				It("returns an error when a certificate path is also provided", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012", CertPath: "/path/to/cert"})
+					Expect(err).To(MatchError("--lb-cert-arn cannot be used with --lb-cert, --lb-key or --lb-chain."))
+				})
+
+				It("returns an error when the arn is not a certificate arn", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", CertARN: "arn:aws:iam::123456789012:role/some-role"})
+					Expect(err).To(MatchError(`"arn:aws:iam::123456789012:role/some-role" is not the ARN of an AWS Certificate Manager certificate or an IAM server certificate.`))
+				})
+			})
+
+			Context("when an ACM certificate is requested", func() {
+				It("returns an error when the iaas is not aws", func() {
+					_, err := handler.GetLBState("gcp", commands.LBArgs{LBType: "cf", Domain: "something.io", ACMCertificate: true})
+					Expect(err).To(MatchError("--lb-acm-certificate is only supported for cf load balancers on aws."))
+				})
+
+				It("returns an error without an lb type", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{Domain: "something.io", ACMCertificate: true})
+					Expect(err).To(MatchError("--lb-acm-certificate requires --lb-type cf."))
+				})
+
+				It("returns an error when a certificate is also provided", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", Domain: "something.io", ACMCertificate: true, CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012"})
+					Expect(err).To(MatchError("--lb-acm-certificate cannot be used with --lb-cert, --lb-key, --lb-chain or --lb-cert-arn."))
+				})
+
+				It("returns an error without a domain", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", ACMCertificate: true})
+					Expect(err).To(MatchError("--lb-acm-certificate requires --lb-domain, whose hosted zone validates the certificate."))
+				})
+
+				It("returns an error with an apps domain", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", Domain: "something.io", AppsDomain: "apps.something.io", ACMCertificate: true})
+					Expect(err).To(MatchError("--lb-acm-certificate only covers --lb-domain. Pass the certificate of both domains with --lb-cert-arn to use --lb-apps-domain."))
+				})
+			})
+
+			Context("when a dns role arn is provided without a domain", func() {
+				It("returns an error", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{
+						LBType:     "cf",
+						DNSRoleARN: "arn:aws:iam::123456789012:role/dns",
+					})
+					Expect(err).To(MatchError("--lb-dns-role-arn requires --lb-domain."))
+					Expect(certificateValidator.ReadAndValidateCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when a hosted zone or apps domain is provided", func() {
+				It("returns an error without a domain", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", DNSZoneID: "Z123"})
+					Expect(err).To(MatchError("--lb-dns-zone-id requires --lb-domain."))
+
+					_, err = handler.GetLBState("aws", commands.LBArgs{LBType: "cf", AppsDomain: "apps.example.com"})
+					Expect(err).To(MatchError("--lb-apps-domain requires --lb-domain."))
+				})
+
+				It("returns an error when the apps domain is not a domain name", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", Domain: "sys.example.com", DNSZoneID: "Z123", AppsDomain: "*.apps.example.com"})
+					Expect(err).To(MatchError(`--lb-apps-domain "*.apps.example.com" is not a domain name.`))
+				})
+
+				It("returns an error when the apps domain is outside of the hosted zone that bbl creates", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", Domain: "sys.example.com", AppsDomain: "apps.example.com"})
+					Expect(err).To(MatchError(`--lb-apps-domain "apps.example.com" is outside of the hosted zone of sys.example.com. Pass --lb-dns-zone-id with a hosted zone that holds both domains.`))
+				})
+			})
+
+			Context("when a health check is provided", func() {
+				It("returns an error without a load balancer type", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{HealthCheck: "interval=10"})
+					Expect(err).To(MatchError("--lb-health-check requires --lb-type cf."))
+				})
+
+				It("returns an error when the load balancer is not a cf load balancer on aws", func() {
+					_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "concourse", HealthCheck: "interval=10"})
+					Expect(err).To(MatchError("--lb-health-check is only supported for cf load balancers on aws."))
+
+					_, err = handler.GetLBState("gcp", commands.LBArgs{LBType: "cf", HealthCheck: "interval=10"})
+					Expect(err).To(MatchError("--lb-health-check is only supported for cf load balancers on aws."))
+				})
+
+				It("returns an error when a setting is not valid", func() {
+					for healthCheck, message := range map[string]string{
+						"interval":               `--lb-health-check "interval" is not a setting=value pair.`,
+						"target=HTTP:8080":       `--lb-health-check target "HTTP:8080" is not TCP:port, SSL:port, HTTP:port/path or HTTPS:port/path.`,
+						"interval=often":         `--lb-health-check interval "often" is not a number.`,
+						"interval=4":             "--lb-health-check interval must be between 5 and 300 seconds.",
+						"timeout=61":             "--lb-health-check timeout must be between 2 and 60 seconds.",
+						"healthy-threshold=1":    "--lb-health-check healthy-threshold must be between 2 and 10.",
+						"unhealthy-threshold=11": "--lb-health-check unhealthy-threshold must be between 2 and 10.",
+						"path=/health":           `--lb-health-check "path" is not one of target, interval, timeout, healthy-threshold or unhealthy-threshold.`,
+						"timeout=12":             "--lb-health-check timeout must be shorter than the interval of 12 seconds.",
+						"interval=5,timeout=5":   "--lb-health-check timeout must be shorter than the interval of 5 seconds.",
+					} {
+						_, err := handler.GetLBState("aws", commands.LBArgs{LBType: "cf", HealthCheck: healthCheck})
+						Expect(err).To(MatchError(message))
+					}
+					Expect(certificateValidator.ReadAndValidateCall.CallCount).To(Equal(0))
+				})
+			})
+
 			Context("when lb type is concourse and domain flag is supplied", func() {
 				It("returns an error", func() {
 					_, err := handler.GetLBState("gcp", commands.LBArgs{
@@ -153,11 +443,14 @@ var _ = Describe("LBArgsHandler", func() {
 				Domain: "new-domain",
 			}
 			old = storage.LB{
-				Type:   "old-type",
-				Cert:   "old-cert",
-				Key:    "old-key",
-				Chain:  "old-chain",
-				Domain: "old-domain",
+				Type:       "old-type",
+				Cert:       "old-cert",
+				Key:        "old-key",
+				Chain:      "old-chain",
+				Domain:     "old-domain",
+				DNSRoleARN: "old-dns-role-arn",
+				DNSZoneID:  "old-dns-zone-id",
+				AppsDomain: "apps.old-domain",
 			}
 		})
 
@@ -176,11 +469,14 @@ var _ = Describe("LBArgsHandler", func() {
 		})
 
 		Context("when the new state is empty", func() {
-			It("keeps the old domain and type", func() {
+			It("keeps the old domain, dns role, hosted zone, apps domain and type", func() {
 				merged := handler.Merge(storage.LB{}, old)
 				Expect(merged).To(Equal(storage.LB{
-					Type:   "old-type",
-					Domain: "old-domain",
+					Type:       "old-type",
+					Domain:     "old-domain",
+					DNSRoleARN: "old-dns-role-arn",
+					DNSZoneID:  "old-dns-zone-id",
+					AppsDomain: "apps.old-domain",
 				}))
 			})
 		})
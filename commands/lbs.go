@@ -1,22 +1,26 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 type LBs struct {
 	lbs            LBsCmd
 	stateValidator stateValidator
+	output         OutputFormatter
 }
 
 type LBsCmd interface {
-	Execute([]string, storage.State) error
+	Execute(context.Context, []string, storage.State) error
 }
 
-func NewLBs(lbs LBsCmd, stateValidator stateValidator) LBs {
+func NewLBs(lbs LBsCmd, stateValidator stateValidator, output OutputFormatter) LBs {
 	return LBs{
 		lbs:            lbs,
 		stateValidator: stateValidator,
+		output:         output,
 	}
 }
 
@@ -26,9 +30,13 @@ func (l LBs) CheckFastFails(subcommandFlags []string, state storage.State) error
 		return err
 	}
 
-	return nil
+	return checkNotLite("lbs", state)
 }
 
-func (l LBs) Execute(subcommandFlags []string, state storage.State) error {
-	return l.lbs.Execute(subcommandFlags, state)
+func (l LBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	// The iaas specific commands take --json as their first flag.
+	if l.output.JSON() && (len(subcommandFlags) == 0 || subcommandFlags[0] != "--json") {
+		subcommandFlags = append([]string{"--json"}, subcommandFlags...)
+	}
+	return l.lbs.Execute(ctx, subcommandFlags, state)
 }
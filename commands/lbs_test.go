@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -23,7 +24,7 @@ var _ = Describe("LBs", func() {
 		lbs = &fakes.LBs{}
 		stateValidator = &fakes.StateValidator{}
 
-		lbsCommand = commands.NewLBs(lbs, stateValidator)
+		lbsCommand = commands.NewLBs(lbs, stateValidator, commands.NewOutputFormatter(&fakes.Logger{}, false))
 	})
 
 	Describe("CheckFastFails", func() {
@@ -39,6 +40,13 @@ var _ = Describe("LBs", func() {
 				Expect(err).To(MatchError("state validator failed"))
 			})
 		})
+
+		Context("when the environment is a bosh-lite", func() {
+			It("returns an error", func() {
+				err := lbsCommand.CheckFastFails([]string{}, storage.State{IAAS: "aws", AWS: storage.AWS{Lite: true}})
+				Expect(err).To(MatchError("lbs does not apply to bosh-lite environments, which have no load balancers."))
+			})
+		})
 	})
 
 	Describe("Execute", func() {
@@ -46,13 +54,26 @@ var _ = Describe("LBs", func() {
 			incomingState := storage.State{
 				IAAS: "aws",
 			}
-			err := lbsCommand.Execute([]string{}, incomingState)
+			err := lbsCommand.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(lbs.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
 			Expect(lbs.ExecuteCall.Receives.State).To(Equal(incomingState))
 		})
 
+		Context("when --json is passed globally", func() {
+			BeforeEach(func() {
+				lbsCommand = commands.NewLBs(lbs, stateValidator, commands.NewOutputFormatter(&fakes.Logger{}, true))
+			})
+
+			It("passes --json to the iaas specific command", func() {
+				err := lbsCommand.Execute(context.Background(), []string{}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(lbs.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{"--json"}))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when LBs fails", func() {
 				BeforeEach(func() {
@@ -60,7 +81,7 @@ var _ = Describe("LBs", func() {
 				})
 
 				It("returns an error", func() {
-					err := lbsCommand.Execute([]string{}, storage.State{})
+					err := lbsCommand.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("something bad happened"))
 				})
 			})
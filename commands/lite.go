@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// checkNotLite refuses the load balancer commands for bosh-lite
+// environments, whose only VM besides the jumpbox is the director.
+func checkNotLite(command string, state storage.State) error {
+	if state.IAAS == "aws" && state.AWS.Lite {
+		return fmt.Errorf("%s does not apply to bosh-lite environments, which have no load balancers.", command)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// Man renders man pages from the usage and examples of the registered
+// commands, so the pages ship with the binary and cannot drift from --help.
+type Man struct {
+	logger   logger
+	commands map[string]Command
+	fs       manFS
+}
+
+type manFS interface {
+	fileio.FileWriter
+	fileio.AllMkdirer
+}
+
+type manConfig struct {
+	command   string
+	outputDir string
+}
+
+func NewMan(logger logger, commands map[string]Command, fs manFS) Man {
+	return Man{
+		logger:   logger,
+		commands: commands,
+		fs:       fs,
+	}
+}
+
+func (m Man) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := parseManArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.command != "" {
+		if _, ok := m.commands[config.command]; !ok {
+			return fmt.Errorf("unknown command: %s", config.command)
+		}
+	}
+
+	return nil
+}
+
+func (m Man) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseManArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if config.outputDir != "" {
+		return m.writePages(config.outputDir)
+	}
+
+	if config.command == "" {
+		m.logger.Println(m.bblPage())
+		return nil
+	}
+
+	m.logger.Println(commandPage(config.command, m.commands[config.command]))
+	return nil
+}
+
+func (m Man) writePages(dir string) error {
+	err := m.fs.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("Create man page directory: %s", err)
+	}
+
+	pages := map[string]string{"bbl.1": m.bblPage()}
+	for _, name := range commandNames(m.commands) {
+		pages[fmt.Sprintf("bbl-%s.1", name)] = commandPage(name, m.commands[name])
+	}
+
+	for fileName, page := range pages {
+		err = m.fs.WriteFile(filepath.Join(dir, fileName), []byte(page), 0644)
+		if err != nil {
+			return fmt.Errorf("Write man page %s: %s", fileName, err)
+		}
+	}
+
+	m.logger.Println(fmt.Sprintf("Wrote %d man pages to %s", len(pages), dir))
+	return nil
+}
+
+// commandNames returns the commands that get a page or completion, leaving
+// out the removed commands that are only registered to print their
+// replacements.
+func commandNames(commands map[string]Command) []string {
+	deprecated := map[string]struct{}{}
+	for _, name := range DeprecatedCommandNames() {
+		deprecated[name] = struct{}{}
+	}
+
+	names := []string{}
+	for name := range commands {
+		if _, ok := deprecated[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m Man) bblPage() string {
+	seeAlso := []string{}
+	for _, name := range commandNames(m.commands) {
+		seeAlso = append(seeAlso, fmt.Sprintf(".BR bbl-%s (1)", name))
+	}
+
+	return strings.Join([]string{
+		`.TH BBL 1 "" "bbl" "bbl Manual"`,
+		".SH NAME",
+		`bbl \- deploys and manages BOSH directors`,
+		".SH SYNOPSIS",
+		"bbl [GLOBAL OPTIONS] COMMAND [OPTIONS]",
+		".SH DESCRIPTION",
+		".nf",
+		roffEscape(strings.Trim(fmt.Sprintf(UsageHeader, "COMMAND", GlobalUsage), "\n")),
+		".fi",
+		".SH SEE ALSO",
+		strings.Join(seeAlso, "\n"),
+	}, "\n")
+}
+
+func commandPage(name string, command Command) string {
+	usage := strings.SplitN(command.Usage(), "\n", 2)
+
+	lines := []string{
+		fmt.Sprintf(`.TH BBL-%s 1 "" "bbl" "bbl Manual"`, strings.ToUpper(name)),
+		".SH NAME",
+		fmt.Sprintf(`bbl-%s \- %s`, name, roffEscape(usage[0])),
+		".SH SYNOPSIS",
+		fmt.Sprintf("bbl [GLOBAL OPTIONS] %s [OPTIONS]", name),
+	}
+
+	if len(usage) == 2 && strings.TrimSpace(usage[1]) != "" {
+		lines = append(lines, ".SH OPTIONS", ".nf", roffEscape(strings.Trim(usage[1], "\n")), ".fi")
+	}
+
+	if commandExamples := Examples(name); len(commandExamples) > 0 {
+		lines = append(lines, ".SH EXAMPLES")
+		for _, example := range commandExamples {
+			lines = append(lines, ".PP", roffEscape(example.Description), ".RS", ".nf", roffEscape(example.Command), ".fi", ".RE")
+		}
+	}
+
+	lines = append(lines, ".SH SEE ALSO", ".BR bbl (1)")
+
+	return strings.Join(lines, "\n")
+}
+
+// roffEscape keeps text from being read as roff: backslashes would start
+// escapes and a leading dot or quote would start a request.
+func roffEscape(text string) string {
+	lines := strings.Split(strings.Replace(text, `\`, `\e`, -1), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseManArgs(args []string) (manConfig, error) {
+	var config manConfig
+
+	manFlags := flags.New("man")
+	manFlags.String(&config.outputDir, "output-dir", "")
+
+	err := manFlags.Parse(args)
+	if err != nil {
+		return manConfig{}, err
+	}
+
+	switch len(manFlags.Args()) {
+	case 0:
+	case 1:
+		config.command = manFlags.Args()[0]
+	default:
+		return manConfig{}, errors.New("man takes at most one command, for example: bbl man up")
+	}
+
+	if config.command != "" && config.outputDir != "" {
+		return manConfig{}, errors.New("--output-dir writes the pages of every command and does not take a command")
+	}
+
+	return config, nil
+}
@@ -0,0 +1,135 @@
+package commands_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Man", func() {
+	var (
+		logger *fakes.Logger
+		fs     *afero.Afero
+		man    commands.Man
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+		sshCmd := &fakes.Command{}
+		sshCmd.UsageCall.Returns.Usage = "Opens an SSH session\n\n  --jumpbox           Connects to the jumpbox\n  [--cmd]             Runs the command"
+		envIDCmd := &fakes.Command{}
+		envIDCmd.UsageCall.Returns.Usage = "Prints environment ID"
+
+		man = commands.NewMan(logger, map[string]commands.Command{
+			"ssh":        sshCmd,
+			"env-id":     envIDCmd,
+			"create-lbs": commands.NewDeprecated("create-lbs", &fakes.CertificateValidator{}, logger),
+		}, fs)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error for an unknown command", func() {
+			err := man.CheckFastFails([]string{"banana"}, storage.State{})
+			Expect(err).To(MatchError("unknown command: banana"))
+		})
+
+		It("returns an error when given more than one command", func() {
+			err := man.CheckFastFails([]string{"ssh", "env-id"}, storage.State{})
+			Expect(err).To(MatchError("man takes at most one command, for example: bbl man up"))
+		})
+
+		It("returns an error when given a command and --output-dir", func() {
+			err := man.CheckFastFails([]string{"--output-dir", "/man", "ssh"}, storage.State{})
+			Expect(err).To(MatchError("--output-dir writes the pages of every command and does not take a command"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints the page of a command with its options and examples", func() {
+			err := man.Execute(context.Background(), []string{"ssh"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(Equal(strings.Join([]string{
+				`.TH BBL-SSH 1 "" "bbl" "bbl Manual"`,
+				".SH NAME",
+				`bbl-ssh \- Opens an SSH session`,
+				".SH SYNOPSIS",
+				"bbl [GLOBAL OPTIONS] ssh [OPTIONS]",
+				".SH OPTIONS",
+				".nf",
+				"  --jumpbox           Connects to the jumpbox",
+				"  [--cmd]             Runs the command",
+				".fi",
+				".SH EXAMPLES",
+				".PP",
+				"Opens a shell on the jumpbox",
+				".RS",
+				".nf",
+				"bbl ssh --jumpbox",
+				".fi",
+				".RE",
+				".PP",
+				"Runs a single command on the director",
+				".RS",
+				".nf",
+				`bbl ssh --director --cmd "sudo monit summary"`,
+				".fi",
+				".RE",
+				".SH SEE ALSO",
+				".BR bbl (1)",
+			}, "\n")))
+		})
+
+		It("leaves out the sections a command has nothing for", func() {
+			err := man.Execute(context.Background(), []string{"env-id"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			page := logger.PrintlnCall.Receives.Message
+			Expect(page).To(ContainSubstring(`bbl-env-id \- Prints environment ID`))
+			Expect(page).NotTo(ContainSubstring(".SH OPTIONS"))
+			Expect(page).NotTo(ContainSubstring(".SH EXAMPLES"))
+		})
+
+		It("prints the page of bbl with the global usage", func() {
+			err := man.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			page := logger.PrintlnCall.Receives.Message
+			Expect(page).To(HavePrefix(`.TH BBL 1 "" "bbl" "bbl Manual"`))
+			Expect(page).To(ContainSubstring("Global Options:"))
+			Expect(page).To(ContainSubstring(`print-env               All environment variables needed for targeting BOSH. Use with: eval "$(bbl print-env)"`))
+			Expect(page).To(HaveSuffix(".SH SEE ALSO\n.BR bbl-env-id (1)\n.BR bbl-ssh (1)"))
+		})
+
+		Context("when --output-dir is passed", func() {
+			It("writes the page of bbl and of every command that was not removed", func() {
+				err := man.Execute(context.Background(), []string{"--output-dir", "/man/man1"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				files, err := fs.ReadDir("/man/man1")
+				Expect(err).NotTo(HaveOccurred())
+
+				names := []string{}
+				for _, file := range files {
+					names = append(names, file.Name())
+				}
+				Expect(names).To(ConsistOf("bbl.1", "bbl-env-id.1", "bbl-ssh.1"))
+
+				page, err := fs.ReadFile("/man/man1/bbl-ssh.1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(page)).To(HavePrefix(`.TH BBL-SSH 1 "" "bbl" "bbl Manual"`))
+
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal("Wrote 3 man pages to /man/man1"))
+			})
+		})
+	})
+})
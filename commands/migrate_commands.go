@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+var (
+	scriptTokens     = regexp.MustCompile(`\S+`)
+	plainScriptArg   = regexp.MustCompile(`^[\w@%+=:,./${}-]+$`)
+	scriptExtensions = map[string]struct{}{"": struct{}{}, ".sh": struct{}{}, ".bash": struct{}{}, ".yml": struct{}{}, ".yaml": struct{}{}}
+	shellOperators   = map[string]struct{}{"&&": struct{}{}, "||": struct{}{}, "|": struct{}{}, ";": struct{}{}, "\\": struct{}{}}
+	globalBoolFlags  = map[string]struct{}{"-d": struct{}{}, "--debug": struct{}{}, "-n": struct{}{}, "--no-confirm": struct{}{}}
+)
+
+type scriptFS interface {
+	fileio.DirReader
+	fileio.FileReader
+	fileio.FileWriter
+}
+
+type MigrateCommands struct {
+	logger logger
+	fs     scriptFS
+}
+
+type migrateCommandsConfig struct {
+	dir   string
+	write bool
+}
+
+type invocation struct {
+	original    string
+	replacement string
+	exact       bool
+}
+
+func NewMigrateCommands(logger logger, fs scriptFS) MigrateCommands {
+	return MigrateCommands{
+		logger: logger,
+		fs:     fs,
+	}
+}
+
+func (m MigrateCommands) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := m.parseArgs(subcommandFlags)
+	return err
+}
+
+func (m MigrateCommands) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := m.parseArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	found, files := 0, 0
+	err = m.walk(config.dir, func(path string, contents []byte, mode os.FileMode) error {
+		lines := strings.Split(string(contents), "\n")
+		rewritten := false
+
+		for i, line := range lines {
+			newLine, invocations := migrateLine(line, state.LB.Type)
+			for _, inv := range invocations {
+				if inv.exact {
+					m.logger.Printf("%s:%d: %s\n  -> %s\n", path, i+1, inv.original, inv.replacement)
+				} else {
+					m.logger.Printf("%s:%d: %s\n  -> %s (needs to be migrated by hand)\n", path, i+1, inv.original, inv.replacement)
+				}
+			}
+			found += len(invocations)
+
+			if newLine != line {
+				lines[i] = newLine
+				rewritten = true
+			}
+		}
+
+		if !rewritten {
+			return nil
+		}
+		files++
+
+		if config.write {
+			err := m.fs.WriteFile(path, []byte(strings.Join(lines, "\n")), mode)
+			if err != nil {
+				return fmt.Errorf("Write %s: %s", path, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case found == 0:
+		m.logger.Println("No deprecated bbl commands found.")
+	case config.write:
+		m.logger.Printf("Found %d deprecated bbl command(s), rewrote %d file(s).\n", found, files)
+	default:
+		m.logger.Printf("Found %d deprecated bbl command(s). Run again with --write to rewrite them.\n", found)
+	}
+
+	return nil
+}
+
+func (m MigrateCommands) parseArgs(args []string) (migrateCommandsConfig, error) {
+	var config migrateCommandsConfig
+
+	migrateFlags := flags.New("migrate-commands")
+	migrateFlags.String(&config.dir, "dir", "")
+	migrateFlags.Bool(&config.write, "write", false)
+
+	err := migrateFlags.Parse(args)
+	if err != nil {
+		return migrateCommandsConfig{}, err
+	}
+
+	if config.dir == "" {
+		return migrateCommandsConfig{}, errors.New("--dir is required")
+	}
+
+	return config, nil
+}
+
+func (m MigrateCommands) walk(dir string, visit func(path string, contents []byte, mode os.FileMode) error) error {
+	entries, err := m.fs.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("Read directory %s: %s", dir, err)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			if err := m.walk(path, visit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, ok := scriptExtensions[filepath.Ext(entry.Name())]; !ok {
+			continue
+		}
+
+		contents, err := m.fs.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Read %s: %s", path, err)
+		}
+
+		if err := visit(path, contents, entry.Mode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateLine finds invocations of removed commands in a line of a script and
+// rewrites the ones that can be translated exactly.
+func migrateLine(line, lbType string) (string, []invocation) {
+	invocations := []invocation{}
+	tokens := scriptTokens.FindAllStringIndex(line, -1)
+	newLine := ""
+	last := 0
+
+	for i := 0; i < len(tokens); i++ {
+		token := line[tokens[i][0]:tokens[i][1]]
+		if token != "bbl" && !strings.HasSuffix(token, "/bbl") {
+			continue
+		}
+
+		j := i + 1
+		for j < len(tokens) && strings.HasPrefix(line[tokens[j][0]:tokens[j][1]], "-") {
+			global := line[tokens[j][0]:tokens[j][1]]
+			if _, ok := globalBoolFlags[global]; !ok && !strings.Contains(global, "=") {
+				j++
+			}
+			j++
+		}
+		if j >= len(tokens) {
+			continue
+		}
+
+		command := line[tokens[j][0]:tokens[j][1]]
+		if _, ok := deprecatedCommands[command]; !ok {
+			continue
+		}
+
+		end := j + 1
+		for end < len(tokens) {
+			if _, ok := shellOperators[line[tokens[end][0]:tokens[end][1]]]; ok {
+				break
+			}
+			end++
+		}
+
+		// Quoting, brackets and line continuations are left for a person to
+		// migrate, since splitting on whitespace cannot be trusted for them.
+		plain := end == len(tokens) || line[tokens[end][0]:tokens[end][1]] != "\\"
+		args := []string{}
+		for _, t := range tokens[j+1 : end] {
+			arg := line[t[0]:t[1]]
+			if !plainScriptArg.MatchString(arg) {
+				plain = false
+			}
+			args = append(args, arg)
+		}
+
+		bbl := line[tokens[i][0]:tokens[j-1][1]]
+		replacement, exact := deprecatedReplacement(bbl, command, args, lbType)
+		exact = exact && plain
+
+		spanEnd := tokens[end-1][1]
+		invocations = append(invocations, invocation{
+			original:    line[tokens[i][0]:spanEnd],
+			replacement: replacement,
+			exact:       exact,
+		})
+
+		if exact {
+			newLine += line[last:tokens[i][0]] + replacement
+			last = spanEnd
+		}
+		i = end - 1
+	}
+
+	return newLine + line[last:], invocations
+}
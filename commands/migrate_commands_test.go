@@ -0,0 +1,134 @@
+package commands_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MigrateCommands", func() {
+	var (
+		logger  *fakes.Logger
+		fs      *afero.Afero
+		command commands.MigrateCommands
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		command = commands.NewMigrateCommands(logger, fs)
+
+		Expect(fs.MkdirAll("/scripts/ci/tasks", os.ModePerm)).To(Succeed())
+		Expect(fs.WriteFile("/scripts/deploy.sh", []byte("#!/bin/bash\nbbl --state-dir state create-lbs --type cf --cert cert.pem --key key.pem\nbbl up\n"), 0755)).To(Succeed())
+		Expect(fs.WriteFile("/scripts/ci/tasks/teardown", []byte("set -e\nbbl delete-lbs --skip-if-missing && bbl destroy -n\n"), 0644)).To(Succeed())
+		Expect(fs.WriteFile("/scripts/ci/pipeline.yml", []byte("run:\n  args: [-c, bbl update-lbs --cert cert.pem --key key.pem]\n"), 0644)).To(Succeed())
+		Expect(fs.WriteFile("/scripts/ci/tasks/rotate-certs.sh", []byte("bbl update-lbs --cert $CERT --key $KEY\n"), 0644)).To(Succeed())
+		Expect(fs.WriteFile("/scripts/README.md", []byte("bbl create-lbs --type cf\n"), 0644)).To(Succeed())
+	})
+
+	Describe("CheckFastFails", func() {
+		It("requires --dir", func() {
+			err := command.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("--dir is required"))
+		})
+
+		It("returns flag parsing errors", func() {
+			err := command.CheckFastFails([]string{"--unknown-flag"}, storage.State{})
+			Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("reports deprecated invocations without changing files", func() {
+			err := command.Execute(context.Background(), []string{"--dir", "/scripts"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"/scripts/ci/pipeline.yml:2: bbl update-lbs --cert cert.pem --key key.pem]\n  -> bbl plan --lb-type <lb-type> --lb-cert cert.pem --lb-key key.pem] && bbl up (needs to be migrated by hand)\n",
+				"/scripts/ci/tasks/rotate-certs.sh:1: bbl update-lbs --cert $CERT --key $KEY\n  -> bbl plan --lb-type <lb-type> --lb-cert $CERT --lb-key $KEY && bbl up (needs to be migrated by hand)\n",
+				"/scripts/ci/tasks/teardown:2: bbl delete-lbs --skip-if-missing\n  -> bbl plan && bbl up\n",
+				"/scripts/deploy.sh:2: bbl --state-dir state create-lbs --type cf --cert cert.pem --key key.pem\n  -> bbl --state-dir state plan --lb-type cf --lb-cert cert.pem --lb-key key.pem && bbl --state-dir state up\n",
+				"Found 4 deprecated bbl command(s). Run again with --write to rewrite them.\n",
+			}))
+
+			contents, err := fs.ReadFile("/scripts/deploy.sh")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("create-lbs"))
+		})
+
+		It("rewrites the invocations that can be translated exactly when --write is passed", func() {
+			err := command.Execute(context.Background(), []string{"--dir", "/scripts", "--write"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := fs.ReadFile("/scripts/deploy.sh")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("#!/bin/bash\nbbl --state-dir state plan --lb-type cf --lb-cert cert.pem --lb-key key.pem && bbl --state-dir state up\nbbl up\n"))
+
+			info, err := fs.Stat("/scripts/deploy.sh")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+
+			contents, err = fs.ReadFile("/scripts/ci/tasks/teardown")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("set -e\nbbl plan && bbl up && bbl destroy -n\n"))
+
+			contents, err = fs.ReadFile("/scripts/ci/pipeline.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("bbl update-lbs"))
+
+			contents, err = fs.ReadFile("/scripts/README.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("bbl create-lbs --type cf\n"))
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("Found 4 deprecated bbl command(s), rewrote 2 file(s).\n"))
+		})
+
+		It("rewrites the unsupported- commands of bbl v2", func() {
+			Expect(fs.MkdirAll("/legacy", os.ModePerm)).To(Succeed())
+			Expect(fs.WriteFile("/legacy/lbs.sh", []byte("bbl unsupported-create-lbs --type concourse --cert cert.pem --key key.pem\nbbl unsupported-update-lbs --cert cert.pem --key key.pem\n"), 0644)).To(Succeed())
+
+			err := command.Execute(context.Background(), []string{"--dir", "/legacy", "--write"}, storage.State{LB: storage.LB{Type: "concourse"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := fs.ReadFile("/legacy/lbs.sh")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("bbl plan --lb-type concourse --lb-cert cert.pem --lb-key key.pem && bbl up\nbbl plan --lb-type concourse --lb-cert cert.pem --lb-key key.pem && bbl up\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("Found 2 deprecated bbl command(s), rewrote 1 file(s).\n"))
+		})
+
+		It("uses the lb type from the state for update-lbs", func() {
+			err := command.Execute(context.Background(), []string{"--dir", "/scripts/ci", "--write"}, storage.State{LB: storage.LB{Type: "cf"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := fs.ReadFile("/scripts/ci/tasks/rotate-certs.sh")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("bbl plan --lb-type cf --lb-cert $CERT --lb-key $KEY && bbl up\n"))
+		})
+
+		Context("when no deprecated commands are used", func() {
+			BeforeEach(func() {
+				Expect(fs.MkdirAll("/empty", os.ModePerm)).To(Succeed())
+			})
+
+			It("says so", func() {
+				err := command.Execute(context.Background(), []string{"--dir", "/empty"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"No deprecated bbl commands found."}))
+			})
+		})
+
+		Context("when the directory cannot be read", func() {
+			It("returns an error", func() {
+				err := command.Execute(context.Background(), []string{"--dir", "/missing"}, storage.State{})
+				Expect(err).To(MatchError(ContainSubstring("Read directory /missing")))
+			})
+		})
+	})
+})
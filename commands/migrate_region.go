@@ -0,0 +1,354 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const regionMigrationDir = "region-migration"
+
+type regionMigrationStore interface {
+	Set(state storage.State) error
+	GetStateDir() string
+	GetVarsDir() (string, error)
+}
+
+type snapshotFS interface {
+	fileio.DirReader
+	fileio.FileReader
+	fileio.FileWriter
+	fileio.AllMkdirer
+	fileio.AllRemover
+}
+
+// upRunner brings up an environment and returns the state that it saved.
+type upRunner interface {
+	ParseArgs([]string, storage.State) (PlanConfig, error)
+	Run(context.Context, UpOptions, PlanConfig, storage.State) (storage.State, error)
+}
+
+type MigrateRegion struct {
+	stateValidator stateValidator
+	plan           plan
+	up             upRunner
+	stateStore     regionMigrationStore
+	fs             snapshotFS
+	logger         logger
+}
+
+type migrateRegionConfig struct {
+	to      string
+	name    string
+	certARN string
+}
+
+func NewMigrateRegion(stateValidator stateValidator, plan plan, up upRunner, stateStore regionMigrationStore, fs snapshotFS, logger logger) MigrateRegion {
+	return MigrateRegion{
+		stateValidator: stateValidator,
+		plan:           plan,
+		up:             up,
+		stateStore:     stateStore,
+		fs:             fs,
+		logger:         logger,
+	}
+}
+
+// MigrateRegionTarget returns the region given to --to, or "" if there is
+// none. The AWS client has to be built for the new region before the state
+// is switched over to it.
+func MigrateRegionTarget(args []string) string {
+	config, err := parseMigrateRegionArgs(args)
+	if err != nil {
+		return ""
+	}
+	return config.to
+}
+
+func (m MigrateRegion) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := m.stateValidator.Validate()
+	if err != nil {
+		return fmt.Errorf("validate state: %s", err)
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("migrate-region is only supported for aws environments")
+	}
+
+	config, err := parseMigrateRegionArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if resumingRegionMigration(state) {
+		if config.to != state.RegionMigration.To {
+			return fmt.Errorf("A migration to %s is in progress. Run bbl migrate-region --to %s to resume it.", state.RegionMigration.To, state.RegionMigration.To)
+		}
+	} else {
+		if config.to == state.AWS.Region {
+			return fmt.Errorf("The environment is already in %s.", state.AWS.Region)
+		}
+
+		if acmCertificateARN.MatchString(state.LB.CertARN) && config.certARN == "" {
+			return fmt.Errorf("ACM certificates can only be used in their own region. Provide a certificate in %s with --lb-cert-arn.", config.to)
+		}
+
+		// The environment in the new region gets a new env id, since IAM
+		// names are global and would collide with the old environment's.
+		state.EnvID = ""
+	}
+
+	err = m.plan.CheckFastFails(regionMigrationPlanArgs(config, state), state)
+	if err != nil {
+		return fmt.Errorf("plan: %s", err)
+	}
+
+	return nil
+}
+
+// Execute moves the environment to a new region in phases, saving the state
+// after each one so that running it again picks up where it stopped. The
+// state directory is first copied to a snapshot, which keeps the old
+// environment manageable with `bbl --state-dir`. The state is then reset to
+// the new region, with freshly generated director credentials and
+// certificates, and the new environment is brought up. Updating DNS and
+// destroying the old environment are left to the operator.
+func (m MigrateRegion) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseMigrateRegionArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if !resumingRegionMigration(state) {
+		state, err = m.snapshot(config, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.RegionMigration.Phase == storage.RegionMigrationSnapshotted {
+		state, err = m.initialize(config, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	migration := state.RegionMigration
+	m.logger.Step("bringing up the environment in %s", migration.To)
+	upConfig, err := m.up.ParseArgs([]string{}, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+
+	state, err = m.up.Run(ctx, UpOptions{}, upConfig, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+
+	// The environment is up in the new region, so a later migrate-region
+	// starts a new migration instead of resuming this one.
+	state.RegionMigration = nil
+	err = m.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	m.logger.Printf("The environment has been migrated to %s. To finish the migration:\n", migration.To)
+	m.logger.Printf("  1. Point your DNS at the new environment. `bbl lbs` prints its name servers.\n")
+	m.logger.Printf("  2. Destroy the old environment in %s with `bbl --state-dir %s destroy`.\n", migration.FromRegion, migration.SnapshotDir)
+
+	return nil
+}
+
+func (m MigrateRegion) snapshot(config migrateRegionConfig, state storage.State) (storage.State, error) {
+	stateDir := m.stateStore.GetStateDir()
+	snapshotDir := filepath.Join(stateDir, regionMigrationDir, fmt.Sprintf("%s-%s", state.EnvID, state.AWS.Region))
+
+	m.logger.Step("snapshotting %s to %s", state.AWS.Region, snapshotDir)
+	err := m.copyDir(stateDir, snapshotDir)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Snapshot state: %s", err)
+	}
+
+	// The new environment keeps the plan of the old one. What belongs to the
+	// old region is cleared: its infrastructure, its jumpbox and director, and
+	// its availability zones, VPC and bucket.
+	migrated := state
+	migrated.EnvID = ""
+	migrated.TFState = ""
+	migrated.LatestTFOutput = ""
+	migrated.Jumpbox = storage.Jumpbox{}
+	migrated.BOSH = storage.BOSH{}
+	migrated.UpProgress = nil
+	migrated.DirectorVersions = nil
+	migrated.DeployedArtifacts = nil
+	migrated.AWS.Region = config.to
+	migrated.AWS.AZs = nil
+	migrated.AWS.ExistingVPCID = ""
+	migrated.AWS.S3BlobstoreBucket = ""
+	migrated.AWS.SubnetSizes = regionSubnetSizes(state.AWS.SubnetSizes, state.AWS.Region, config.to)
+	migrated.RegionMigration = &storage.RegionMigration{
+		FromRegion:  state.AWS.Region,
+		FromEnvID:   state.EnvID,
+		To:          config.to,
+		SnapshotDir: snapshotDir,
+		Phase:       storage.RegionMigrationSnapshotted,
+	}
+
+	// KMS keys are regional, so the data key is still decrypted in the
+	// region it was generated in.
+	if state.Encryption != nil && state.Encryption.Method == storage.KMSEncryption && state.Encryption.KMSRegion == "" {
+		encryption := *state.Encryption
+		encryption.KMSRegion = state.AWS.Region
+		migrated.Encryption = &encryption
+	}
+
+	err = m.stateStore.Set(migrated)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Save state: %s", err)
+	}
+
+	return migrated, nil
+}
+
+func (m MigrateRegion) initialize(config migrateRegionConfig, state storage.State) (storage.State, error) {
+	m.logger.Step("re-issuing director credentials and certificates")
+	varsDir, err := m.stateStore.GetVarsDir()
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	err = m.fs.RemoveAll(varsDir)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Remove vars dir: %s", err)
+	}
+
+	planConfig, err := m.plan.ParseArgs(regionMigrationPlanArgs(config, state), state)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("plan: %s", err)
+	}
+	if planConfig.LB == (storage.LB{}) {
+		planConfig.LB = state.LB
+	}
+
+	state, err = m.plan.InitializePlan(planConfig, state)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Initialize plan: %s", err)
+	}
+
+	// The store saves states at the current schema, which up relies on to
+	// know that the plan has already been initialized.
+	state.Version = storage.STATE_SCHEMA
+
+	migration := *state.RegionMigration
+	migration.Phase = storage.RegionMigrationProvisioning
+	state.RegionMigration = &migration
+
+	err = m.stateStore.Set(state)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Save state: %s", err)
+	}
+
+	return state, nil
+}
+
+func (m MigrateRegion) copyDir(src, dst string) error {
+	entries, err := m.fs.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	err = m.fs.MkdirAll(dst, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch entry.Name() {
+		case regionMigrationDir, ".terraform", storage.StateLockFileName:
+			continue
+		}
+
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+
+		if entry.IsDir() {
+			err = m.copyDir(srcPath, dstPath)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		contents, err := m.fs.ReadFile(srcPath)
+		if err != nil {
+			return err
+		}
+
+		err = m.fs.WriteFile(dstPath, contents, entry.Mode())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseMigrateRegionArgs(args []string) (migrateRegionConfig, error) {
+	var config migrateRegionConfig
+
+	migrateFlags := flags.New("migrate-region")
+	migrateFlags.String(&config.to, "to", "")
+	migrateFlags.String(&config.name, "name", "")
+	migrateFlags.String(&config.certARN, "lb-cert-arn", "")
+
+	err := migrateFlags.Parse(args)
+	if err != nil {
+		return migrateRegionConfig{}, err
+	}
+
+	if config.to == "" {
+		return migrateRegionConfig{}, errors.New("--to is required")
+	}
+
+	return config, nil
+}
+
+func resumingRegionMigration(state storage.State) bool {
+	return state.RegionMigration != nil && state.RegionMigration.To == state.AWS.Region
+}
+
+// regionSubnetSizes moves the subnet sizes of the availability zones of one
+// region to the zones of the same letter in another.
+func regionSubnetSizes(sizes map[string]int, from, to string) map[string]int {
+	if sizes == nil {
+		return nil
+	}
+
+	moved := map[string]int{}
+	for az, size := range sizes {
+		if strings.HasPrefix(az, from) {
+			az = to + strings.TrimPrefix(az, from)
+		}
+		moved[az] = size
+	}
+	return moved
+}
+
+func regionMigrationPlanArgs(config migrateRegionConfig, state storage.State) []string {
+	args := []string{}
+	if config.name != "" {
+		args = append(args, "--name", config.name)
+	}
+	if config.certARN != "" {
+		args = append(args, "--lb-type", state.LB.Type, "--lb-cert-arn", config.certARN)
+	}
+	return args
+}
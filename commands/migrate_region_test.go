@@ -0,0 +1,374 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MigrateRegion", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		plan           *fakes.Plan
+		up             *fakes.Up
+		stateStore     *fakes.StateStore
+		fs             *afero.Afero
+		logger         *fakes.Logger
+		migrateRegion  commands.MigrateRegion
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		plan = &fakes.Plan{}
+		up = &fakes.Up{}
+		stateStore = &fakes.StateStore{}
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		logger = &fakes.Logger{}
+		migrateRegion = commands.NewMigrateRegion(stateValidator, plan, up, stateStore, fs, logger)
+
+		state = storage.State{
+			IAAS:       "aws",
+			EnvID:      "some-env-id",
+			BBLVersion: "some-bbl-version",
+			AWS: storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "us-east-1",
+			},
+			LB:      storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key"},
+			TFState: "some-tf-state",
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state and calls plan.CheckFastFails for a new environment", func() {
+			err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2", "--name", "new-env"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(plan.CheckFastFailsCall.CallCount).To(Equal(1))
+			Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{"--name", "new-env"}))
+			Expect(plan.CheckFastFailsCall.Receives.State.EnvID).To(Equal(""))
+		})
+
+		It("requires --to", func() {
+			err := migrateRegion.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--to is required"))
+		})
+
+		It("returns an error when the environment is already in the region", func() {
+			err := migrateRegion.CheckFastFails([]string{"--to", "us-east-1"}, state)
+			Expect(err).To(MatchError("The environment is already in us-east-1."))
+		})
+
+		It("returns an error when the iaas is not aws", func() {
+			state.IAAS = "gcp"
+			err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2"}, state)
+			Expect(err).To(MatchError("migrate-region is only supported for aws environments"))
+		})
+
+		Context("when the load balancer uses an ACM certificate", func() {
+			BeforeEach(func() {
+				state.LB = storage.LB{Type: "cf", CertARN: "arn:aws:acm:us-east-1:123456789012:certificate/old"}
+			})
+
+			It("requires a certificate in the new region", func() {
+				err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2"}, state)
+				Expect(err).To(MatchError("ACM certificates can only be used in their own region. Provide a certificate in us-west-2 with --lb-cert-arn."))
+			})
+
+			It("passes the new certificate to plan", func() {
+				err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2", "--lb-cert-arn", "some-arn"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{"--lb-type", "cf", "--lb-cert-arn", "some-arn"}))
+			})
+		})
+
+		Context("when a migration is in progress", func() {
+			BeforeEach(func() {
+				state.AWS.Region = "us-west-2"
+				state.RegionMigration = &storage.RegionMigration{To: "us-west-2", Phase: storage.RegionMigrationProvisioning}
+			})
+
+			It("allows it to be resumed", func() {
+				err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.CheckFastFailsCall.Receives.State.EnvID).To(Equal("some-env-id"))
+			})
+
+			It("returns an error for another region", func() {
+				err := migrateRegion.CheckFastFails([]string{"--to", "eu-west-1"}, state)
+				Expect(err).To(MatchError("A migration to us-west-2 is in progress. Run bbl migrate-region --to us-west-2 to resume it."))
+			})
+		})
+
+		Context("when the state validator returns an error", func() {
+			BeforeEach(func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("coconut")
+			})
+
+			It("returns the error", func() {
+				err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2"}, state)
+				Expect(err).To(MatchError("validate state: coconut"))
+			})
+		})
+
+		Context("when plan.CheckFastFails returns an error", func() {
+			BeforeEach(func() {
+				plan.CheckFastFailsCall.Returns.Error = errors.New("passionfruit")
+			})
+
+			It("wraps and returns the error", func() {
+				err := migrateRegion.CheckFastFails([]string{"--to", "us-west-2"}, state)
+				Expect(err).To(MatchError("plan: passionfruit"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			stateStore.GetStateDirCall.Returns.Directory = "/state"
+			stateStore.GetVarsDirCall.Returns.Directory = "/state/vars"
+
+			Expect(fs.MkdirAll("/state/vars", os.ModePerm)).To(Succeed())
+			Expect(fs.MkdirAll("/state/terraform/.terraform", os.ModePerm)).To(Succeed())
+			Expect(fs.WriteFile("/state/bbl-state.json", []byte("old-state"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/state/bbl-state.lock", []byte("123"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/state/vars/director-vars-store.yml", []byte("old-certs"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/state/terraform/bbl-template.tf", []byte("template"), 0644)).To(Succeed())
+			Expect(fs.WriteFile("/state/terraform/.terraform/plugin", []byte("plugin"), 0755)).To(Succeed())
+
+			plan.InitializePlanCall.Returns.State = storage.State{
+				IAAS:  "aws",
+				EnvID: "new-env-id",
+				AWS:   storage.AWS{Region: "us-west-2"},
+				RegionMigration: &storage.RegionMigration{
+					To:          "us-west-2",
+					FromRegion:  "us-east-1",
+					SnapshotDir: "/state/region-migration/some-env-id-us-east-1",
+					Phase:       storage.RegionMigrationSnapshotted,
+				},
+			}
+			up.RunCall.Returns.State = storage.State{
+				IAAS:  "aws",
+				EnvID: "new-env-id",
+				AWS:   storage.AWS{Region: "us-west-2"},
+				BOSH:  storage.BOSH{DirectorAddress: "https://10.0.0.6:25555"},
+				RegionMigration: &storage.RegionMigration{
+					To:          "us-west-2",
+					FromRegion:  "us-east-1",
+					SnapshotDir: "/state/region-migration/some-env-id-us-east-1",
+					Phase:       storage.RegionMigrationProvisioning,
+				},
+			}
+		})
+
+		It("snapshots the state, re-initializes it in the new region and brings it up", func() {
+			err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("copying the state dir to a snapshot", func() {
+				contents, err := fs.ReadFile("/state/region-migration/some-env-id-us-east-1/bbl-state.json")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("old-state"))
+
+				contents, err = fs.ReadFile("/state/region-migration/some-env-id-us-east-1/vars/director-vars-store.yml")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("old-certs"))
+
+				_, err = fs.Stat("/state/region-migration/some-env-id-us-east-1/terraform/.terraform")
+				Expect(os.IsNotExist(err)).To(BeTrue())
+
+				_, err = fs.Stat("/state/region-migration/some-env-id-us-east-1/bbl-state.lock")
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+
+			By("saving a state for the new region", func() {
+				Expect(stateStore.SetCall.CallCount).To(Equal(3))
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(storage.State{
+					IAAS:       "aws",
+					BBLVersion: "some-bbl-version",
+					AWS: storage.AWS{
+						AccessKeyID:     "some-access-key-id",
+						SecretAccessKey: "some-secret-access-key",
+						Region:          "us-west-2",
+					},
+					LB: storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key"},
+					RegionMigration: &storage.RegionMigration{
+						FromRegion:  "us-east-1",
+						FromEnvID:   "some-env-id",
+						To:          "us-west-2",
+						SnapshotDir: "/state/region-migration/some-env-id-us-east-1",
+						Phase:       storage.RegionMigrationSnapshotted,
+					},
+				}))
+			})
+
+			By("removing the old credentials and certificates", func() {
+				_, err := fs.Stat("/state/vars")
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+
+			By("initializing the plan with the existing load balancer", func() {
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(1))
+				Expect(plan.InitializePlanCall.Receives.Plan.LB).To(Equal(storage.LB{Type: "cf", Cert: "some-cert", Key: "some-key"}))
+				Expect(plan.InitializePlanCall.Receives.State.AWS.Region).To(Equal("us-west-2"))
+				Expect(stateStore.SetCall.Receives[1].State.RegionMigration.Phase).To(Equal(storage.RegionMigrationProvisioning))
+			})
+
+			By("bringing up the environment", func() {
+				Expect(up.ParseArgsCall.Receives.Args).To(Equal([]string{}))
+				Expect(up.RunCall.CallCount).To(Equal(1))
+				Expect(up.RunCall.Receives.State.EnvID).To(Equal("new-env-id"))
+				Expect(up.RunCall.Receives.State.Version).To(Equal(storage.STATE_SCHEMA))
+			})
+
+			By("finishing the migration once the environment is up", func() {
+				finished := stateStore.SetCall.Receives[2].State
+				Expect(finished.RegionMigration).To(BeNil())
+				Expect(finished.BOSH.DirectorAddress).To(Equal("https://10.0.0.6:25555"))
+			})
+
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"The environment has been migrated to us-west-2. To finish the migration:\n",
+				"  1. Point your DNS at the new environment. `bbl lbs` prints its name servers.\n",
+				"  2. Destroy the old environment in us-east-1 with `bbl --state-dir /state/region-migration/some-env-id-us-east-1 destroy`.\n",
+			}))
+		})
+
+		It("keeps the plan of the environment and clears what belongs to the old region", func() {
+			state.AWS.AZs = []string{"us-east-1a", "us-east-1b"}
+			state.AWS.ExistingVPCID = "vpc-0a1b2c3d"
+			state.AWS.S3BlobstoreBucket = "some-bucket"
+			state.AWS.SubnetSizes = map[string]int{"us-east-1a": 20, "us-east-1b": 22}
+			state.AWS.NATGateway = true
+			state.AWS.DirectorAllowedCIDRs = []string{"10.10.0.0/16"}
+			state.AWS.LBAllowedCIDRs = []string{"0.0.0.0/0"}
+			state.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "apps"}}
+			state.AWS.Certificates = []storage.ServerCertificate{{Name: "apps"}}
+			state.TrustedCACerts = "some-ca-certs"
+			state.Annotations = map[string]string{"owner": "some-team"}
+			state.ArtifactsDir = "/artifacts"
+			state.Encryption = &storage.Encryption{Method: "passphrase", Salt: "some-salt"}
+			state.BOSH = storage.BOSH{DirectorAddress: "https://10.0.0.6:25555"}
+			state.Jumpbox = storage.Jumpbox{URL: "10.0.0.5:22"}
+			state.DirectorVersions = &storage.DirectorVersions{BOSH: "264.7.0"}
+			state.DeployedArtifacts = &storage.ArtifactOverrides{BOSHReleaseURL: "some-bosh-url"}
+
+			err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			migrated := stateStore.SetCall.Receives[0].State
+			Expect(migrated.AWS.NATGateway).To(BeTrue())
+			Expect(migrated.AWS.DirectorAllowedCIDRs).To(Equal([]string{"10.10.0.0/16"}))
+			Expect(migrated.AWS.LBAllowedCIDRs).To(Equal([]string{"0.0.0.0/0"}))
+			Expect(migrated.AWS.SNIDomains).To(Equal(state.AWS.SNIDomains))
+			Expect(migrated.AWS.Certificates).To(Equal(state.AWS.Certificates))
+			Expect(migrated.AWS.SubnetSizes).To(Equal(map[string]int{"us-west-2a": 20, "us-west-2b": 22}))
+			Expect(migrated.TrustedCACerts).To(Equal("some-ca-certs"))
+			Expect(migrated.Annotations).To(Equal(map[string]string{"owner": "some-team"}))
+			Expect(migrated.ArtifactsDir).To(Equal("/artifacts"))
+			Expect(migrated.Encryption).To(Equal(&storage.Encryption{Method: "passphrase", Salt: "some-salt"}))
+
+			Expect(migrated.AWS.AZs).To(BeEmpty())
+			Expect(migrated.AWS.ExistingVPCID).To(BeEmpty())
+			Expect(migrated.AWS.S3BlobstoreBucket).To(BeEmpty())
+			Expect(migrated.BOSH).To(Equal(storage.BOSH{}))
+			Expect(migrated.Jumpbox).To(Equal(storage.Jumpbox{}))
+			Expect(migrated.DirectorVersions).To(BeNil())
+			Expect(migrated.DeployedArtifacts).To(BeNil())
+		})
+
+		It("keeps decrypting the data key of a KMS encrypted state in the region of the key", func() {
+			state.Encryption = &storage.Encryption{Method: "kms", KMSKeyID: "some-key-id", DataKey: "some-data-key"}
+
+			err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateStore.SetCall.Receives[0].State.Encryption).To(Equal(&storage.Encryption{
+				Method:    "kms",
+				KMSKeyID:  "some-key-id",
+				DataKey:   "some-data-key",
+				KMSRegion: "us-east-1",
+			}))
+			Expect(state.Encryption.KMSRegion).To(BeEmpty())
+		})
+
+		Context("when resuming a migration that was provisioning", func() {
+			BeforeEach(func() {
+				state.AWS.Region = "us-west-2"
+				state.RegionMigration = &storage.RegionMigration{
+					FromRegion:  "us-east-1",
+					To:          "us-west-2",
+					SnapshotDir: "/snapshot",
+					Phase:       storage.RegionMigrationProvisioning,
+				}
+			})
+
+			It("only brings up the environment", func() {
+				err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(0))
+				Expect(up.RunCall.CallCount).To(Equal(1))
+				Expect(up.RunCall.Receives.State).To(Equal(state))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.Receives[0].State.RegionMigration).To(BeNil())
+
+				_, err = fs.Stat("/state/vars")
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when plan initialization fails", func() {
+			BeforeEach(func() {
+				plan.InitializePlanCall.Returns.Error = errors.New("guava")
+			})
+
+			It("returns the error after checkpointing the snapshot", func() {
+				err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+				Expect(err).To(MatchError("Initialize plan: guava"))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.Receives[0].State.RegionMigration.Phase).To(Equal(storage.RegionMigrationSnapshotted))
+				Expect(up.RunCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when up fails", func() {
+			BeforeEach(func() {
+				up.RunCall.Returns.Error = errors.New("mango")
+			})
+
+			It("wraps and returns the error and keeps the migration to resume it", func() {
+				err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+				Expect(err).To(MatchError("up: mango"))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+				Expect(stateStore.SetCall.Receives[1].State.RegionMigration.Phase).To(Equal(storage.RegionMigrationProvisioning))
+			})
+		})
+
+		Context("when the state cannot be saved once the environment is up", func() {
+			BeforeEach(func() {
+				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {Error: errors.New("papaya")}}
+			})
+
+			It("returns the error", func() {
+				err := migrateRegion.Execute(context.Background(), []string{"--to", "us-west-2"}, state)
+				Expect(err).To(MatchError("Save state: papaya"))
+			})
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package commands
+
+import "encoding/json"
+
+// OutputFormatter prints the results of informational commands, as text or,
+// with the global --json flag, as JSON for scripts to read.
+type OutputFormatter struct {
+	logger logger
+	json   bool
+}
+
+func NewOutputFormatter(logger logger, json bool) OutputFormatter {
+	return OutputFormatter{
+		logger: logger,
+		json:   json,
+	}
+}
+
+func (o OutputFormatter) JSON() bool {
+	return o.json
+}
+
+// PrintValue prints a single value on its own, or as a JSON object with the
+// value under key.
+func (o OutputFormatter) PrintValue(key, value string) error {
+	if !o.json {
+		o.logger.Println(value)
+		return nil
+	}
+
+	return o.PrintJSON(map[string]string{key: value})
+}
+
+func (o OutputFormatter) PrintJSON(value interface{}) error {
+	output, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	o.logger.Println(string(output))
+	return nil
+}
+
+func (o OutputFormatter) Printf(message string, a ...interface{}) {
+	o.logger.Printf(message, a...)
+}
@@ -1,25 +1,43 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 type Outputs struct {
-	logger           logger
+	output           OutputFormatter
 	terraformManager terraformManager
 	stateValidator   stateValidator
 }
 
-func NewOutputs(logger logger, terraformManager terraformManager, stateValidator stateValidator) Outputs {
+type outputsConfig struct {
+	format string
+	names  []string
+}
+
+func NewOutputs(output OutputFormatter, terraformManager terraformManager, stateValidator stateValidator) Outputs {
 	return Outputs{
-		logger:           logger,
+		output:           output,
 		terraformManager: terraformManager,
 		stateValidator:   stateValidator,
 	}
 }
 
 func (o Outputs) CheckFastFails(subcommandFlags []string, state storage.State) error {
-	err := o.stateValidator.Validate()
+	_, err := o.parseArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = o.stateValidator.Validate()
 	if err != nil {
 		return err
 	}
@@ -27,13 +45,171 @@ func (o Outputs) CheckFastFails(subcommandFlags []string, state storage.State) e
 	return nil
 }
 
-func (o Outputs) Execute(subcommandFlags []string, state storage.State) error {
+func (o Outputs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := o.parseArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
 	outputs, err := o.terraformManager.GetOutputs()
 	if err != nil {
 		return err
 	}
-	for k, v := range outputs.Map {
-		o.logger.Printf("%s: %+v\n", k, v)
+
+	if config.format == "terraform" {
+		o.output.Printf("%s", terraformConfiguration(state, outputs.Map))
+		return nil
+	}
+
+	selected := outputs.Map
+	if len(config.names) > 0 {
+		selected = map[string]interface{}{}
+		for _, name := range config.names {
+			value, ok := outputs.Map[name]
+			if !ok {
+				return fmt.Errorf("The environment has no output %s. Run bbl outputs to list the outputs.", name)
+			}
+			selected[name] = value
+		}
+	}
+
+	if o.output.JSON() {
+		return o.output.PrintJSON(selected)
+	}
+
+	// A single output is printed without its name, so that scripts can use
+	// it as it is, such as $(bbl outputs vpc_id).
+	if len(config.names) == 1 {
+		o.output.Printf("%s\n", textValue(selected[config.names[0]]))
+		return nil
+	}
+
+	for _, name := range sortedKeys(selected) {
+		o.output.Printf("%s: %s\n", name, textValue(selected[name]))
 	}
 	return nil
 }
+
+func (o Outputs) parseArgs(args []string) (outputsConfig, error) {
+	var config outputsConfig
+
+	outputsFlags := flags.New("outputs")
+	outputsFlags.String(&config.format, "format", "")
+
+	err := outputsFlags.Parse(args)
+	if err != nil {
+		return outputsConfig{}, err
+	}
+
+	config.names = outputsFlags.Args()
+
+	switch config.format {
+	case "":
+	case "terraform":
+		if o.output.JSON() {
+			return outputsConfig{}, errors.New("--format terraform cannot be used with --json.")
+		}
+		if len(config.names) > 0 {
+			return outputsConfig{}, errors.New("--format terraform prints all the outputs, leave out the output names.")
+		}
+	default:
+		return outputsConfig{}, fmt.Errorf("--format %q is not supported. Use --format terraform.", config.format)
+	}
+
+	return config, nil
+}
+
+// terraformConfiguration is a terraform file for the infrastructure that is
+// deployed next to a bbl environment, such as the databases of cf. On aws it
+// looks up the vpc, the internal subnets and the security groups of the
+// environment as data sources, and on every iaas it declares the outputs as
+// variables. The private key of the jumpbox is left out.
+func terraformConfiguration(state storage.State, outputs map[string]interface{}) string {
+	var blocks []string
+
+	if state.IAAS == "aws" {
+		if vpcID, ok := outputs["vpc_id"].(string); ok {
+			blocks = append(blocks, terraformBlock("data", "aws_vpc", "bbl", vpcID))
+		}
+
+		if subnets, ok := outputs["internal_az_subnet_id_mapping"].(map[string]interface{}); ok {
+			for _, az := range sortedKeys(subnets) {
+				if id, ok := subnets[az].(string); ok {
+					blocks = append(blocks, terraformBlock("data", "aws_subnet", fmt.Sprintf("bbl_internal_%s", az), id))
+				}
+			}
+		}
+
+		for _, name := range sortedKeys(outputs) {
+			id, ok := outputs[name].(string)
+			if !ok || !(strings.HasSuffix(name, "_security_group") || strings.HasSuffix(name, "_security_group_id")) {
+				continue
+			}
+			blocks = append(blocks, terraformBlock("data", "aws_security_group", fmt.Sprintf("bbl_%s", strings.TrimSuffix(name, "_id")), id))
+		}
+	}
+
+	for _, name := range sortedKeys(outputs) {
+		if name == "private_key" {
+			continue
+		}
+		blocks = append(blocks, fmt.Sprintf("variable %q {\n  default = %s\n}\n", fmt.Sprintf("bbl_%s", name), hclValue(outputs[name], "  ")))
+	}
+
+	header := fmt.Sprintf("# The infrastructure of the bbl environment %s, written by bbl outputs\n# --format terraform. Write it again after bbl up changes the environment.\n", state.EnvID)
+	return strings.Join(append([]string{header}, blocks...), "\n")
+}
+
+func terraformBlock(kind, resourceType, name, id string) string {
+	return fmt.Sprintf("%s %q %q {\n  id = %q\n}\n", kind, resourceType, name, id)
+}
+
+// hclValue writes the strings, lists and maps of terraform outputs in the
+// syntax of terraform files. Numbers and booleans are written as strings,
+// which terraform converts.
+func hclValue(value interface{}, indent string) string {
+	switch v := value.(type) {
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, hclValue(item, indent))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		lines := []string{"{"}
+		for _, key := range sortedKeys(v) {
+			lines = append(lines, fmt.Sprintf("%s  %q = %s", indent, key, hclValue(v[key], indent+"  ")))
+		}
+		lines = append(lines, fmt.Sprintf("%s}", indent))
+		return strings.Join(lines, "\n")
+	default:
+		return fmt.Sprintf("%q", fmt.Sprintf("%v", v))
+	}
+}
+
+// textValue writes strings and numbers as they are, and the lists and maps of
+// the subnets and security groups as json, which scripts can parse.
+func textValue(value interface{}) string {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		contents, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%+v", value) //not tested
+		}
+		return string(contents)
+	default:
+		return fmt.Sprintf("%+v", value)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -24,7 +25,7 @@ var _ = Describe("Outputs", func() {
 		stateValidator = &fakes.StateValidator{}
 		logger = &fakes.Logger{}
 		terraformManager = &fakes.TerraformManager{}
-		outputsCommand = commands.NewOutputs(logger, terraformManager, stateValidator)
+		outputsCommand = commands.NewOutputs(commands.NewOutputFormatter(logger, false), terraformManager, stateValidator)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -51,20 +52,195 @@ var _ = Describe("Outputs", func() {
 				},
 			}
 			terraformManager.GetOutputsCall.Returns.Outputs = terraformOutputs
-			err := outputsCommand.Execute([]string{}, storage.State{})
+			err := outputsCommand.Execute(context.Background(), []string{}, storage.State{})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
-				"firewall: cidr\n",
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
 				"external: address\n",
+				"firewall: cidr\n",
+			}))
+		})
+
+		It("prints the outputs sorted by name, with the lists and maps as json", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{
+				Map: map[string]interface{}{
+					"vpc_id":                        "vpc-1234",
+					"internal_az_subnet_id_mapping": map[string]interface{}{"us-east-1a": "subnet-1"},
+					"lb_subnet_ids":                 []interface{}{"subnet-2", "subnet-3"},
+				},
+			}
+
+			err := outputsCommand.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"internal_az_subnet_id_mapping: {\"us-east-1a\":\"subnet-1\"}\n",
+				"lb_subnet_ids: [\"subnet-2\",\"subnet-3\"]\n",
+				"vpc_id: vpc-1234\n",
 			}))
 		})
 
+		Context("when output names are passed", func() {
+			BeforeEach(func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{
+					Map: map[string]interface{}{
+						"vpc_id":        "vpc-1234",
+						"internal_cidr": "10.0.0.0/16",
+						"lb_subnet_ids": []interface{}{"subnet-2"},
+					},
+				}
+			})
+
+			It("prints only the value of a single output", func() {
+				err := outputsCommand.Execute(context.Background(), []string{"vpc_id"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{"vpc-1234\n"}))
+			})
+
+			It("prints the outputs that are named", func() {
+				err := outputsCommand.Execute(context.Background(), []string{"vpc_id", "lb_subnet_ids"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{
+					"lb_subnet_ids: [\"subnet-2\"]\n",
+					"vpc_id: vpc-1234\n",
+				}))
+			})
+
+			It("prints the outputs that are named as a json object with --json", func() {
+				outputsCommand = commands.NewOutputs(commands.NewOutputFormatter(logger, true), terraformManager, stateValidator)
+
+				err := outputsCommand.Execute(context.Background(), []string{"internal_cidr"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{`{"internal_cidr":"10.0.0.0/16"}`}))
+			})
+
+			It("returns an error when the environment has no such output", func() {
+				err := outputsCommand.Execute(context.Background(), []string{"vpc_id", "nat_eip"}, storage.State{})
+				Expect(err).To(MatchError("The environment has no output nat_eip. Run bbl outputs to list the outputs."))
+				Expect(logger.PrintfCall.Messages).To(BeEmpty())
+			})
+
+			It("cannot be used with --format terraform", func() {
+				err := outputsCommand.CheckFastFails([]string{"--format", "terraform", "vpc_id"}, storage.State{})
+				Expect(err).To(MatchError("--format terraform prints all the outputs, leave out the output names."))
+			})
+		})
+
+		Context("when --json is passed", func() {
+			BeforeEach(func() {
+				outputsCommand = commands.NewOutputs(commands.NewOutputFormatter(logger, true), terraformManager, stateValidator)
+			})
+
+			It("prints the terraform outputs as a json object", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{
+					Map: map[string]interface{}{
+						"firewall": "cidr",
+						"zones":    []interface{}{"z1", "z2"},
+					},
+				}
+
+				err := outputsCommand.Execute(context.Background(), []string{}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{`{"firewall":"cidr","zones":["z1","z2"]}`}))
+			})
+		})
+
+		Context("when --format terraform is passed", func() {
+			It("prints a terraform file with data sources for the aws resources and variables for the outputs", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{
+					Map: map[string]interface{}{
+						"vpc_id":                           "vpc-1234",
+						"internal_az_subnet_id_mapping":    map[string]interface{}{"us-east-1b": "subnet-2", "us-east-1a": "subnet-1"},
+						"internal_security_group":          "sg-1",
+						"iso_security_group_id":            "sg-2",
+						"jumpbox__default_security_groups": []interface{}{"sg-3"},
+						"private_key":                      "some-private-key",
+					},
+				}
+
+				err := outputsCommand.Execute(context.Background(), []string{"--format", "terraform"}, storage.State{IAAS: "aws", EnvID: "some-env"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{`# The infrastructure of the bbl environment some-env, written by bbl outputs
+# --format terraform. Write it again after bbl up changes the environment.
+
+data "aws_vpc" "bbl" {
+  id = "vpc-1234"
+}
+
+data "aws_subnet" "bbl_internal_us-east-1a" {
+  id = "subnet-1"
+}
+
+data "aws_subnet" "bbl_internal_us-east-1b" {
+  id = "subnet-2"
+}
+
+data "aws_security_group" "bbl_internal_security_group" {
+  id = "sg-1"
+}
+
+data "aws_security_group" "bbl_iso_security_group" {
+  id = "sg-2"
+}
+
+variable "bbl_internal_az_subnet_id_mapping" {
+  default = {
+    "us-east-1a" = "subnet-1"
+    "us-east-1b" = "subnet-2"
+  }
+}
+
+variable "bbl_internal_security_group" {
+  default = "sg-1"
+}
+
+variable "bbl_iso_security_group_id" {
+  default = "sg-2"
+}
+
+variable "bbl_jumpbox__default_security_groups" {
+  default = ["sg-3"]
+}
+
+variable "bbl_vpc_id" {
+  default = "vpc-1234"
+}
+`}))
+			})
+
+			It("only declares the variables for other iaases", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{
+					Map: map[string]interface{}{"network": "some-network"},
+				}
+
+				err := outputsCommand.Execute(context.Background(), []string{"--format", "terraform"}, storage.State{IAAS: "gcp", EnvID: "some-env"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintfCall.Messages[0]).To(HaveSuffix(`
+
+variable "bbl_network" {
+  default = "some-network"
+}
+`))
+				Expect(logger.PrintfCall.Messages[0]).NotTo(ContainSubstring("data "))
+			})
+
+			It("cannot be used with --json", func() {
+				outputsCommand = commands.NewOutputs(commands.NewOutputFormatter(logger, true), terraformManager, stateValidator)
+
+				err := outputsCommand.CheckFastFails([]string{"--format", "terraform"}, storage.State{})
+				Expect(err).To(MatchError("--format terraform cannot be used with --json."))
+			})
+
+			It("rejects other formats", func() {
+				err := outputsCommand.CheckFastFails([]string{"--format", "yaml"}, storage.State{})
+				Expect(err).To(MatchError(`--format "yaml" is not supported. Use --format terraform.`))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when getOutputs failes", func() {
 				It("returns an error", func() {
 					terraformManager.GetOutputsCall.Returns.Error = errors.New("tangelo")
 
-					err := outputsCommand.Execute([]string{}, storage.State{})
+					err := outputsCommand.Execute(context.Background(), []string{}, storage.State{})
 
 					Expect(err).To(MatchError("tangelo"))
 				})
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type ArtifactResolver interface {
+	BOSHRelease(version string) (bosh.Artifact, error)
+	CPIRelease(iaas, version string) (bosh.Artifact, error)
+	Stemcell(iaas, version string) (bosh.Artifact, error)
+}
+
+type pinArtifactsConfig struct {
+	bosh     string
+	cpi      string
+	stemcell string
+}
+
+// PinArtifacts resolves versions of the BOSH release, CPI release and
+// stemcell of the director on bosh.io, and records them in the artifact
+// overrides of the state, so that a director is not held to the versions that
+// this bbl was built with.
+type PinArtifacts struct {
+	stateValidator stateValidator
+	resolver       ArtifactResolver
+	stateStore     stateStore
+	logger         logger
+}
+
+func NewPinArtifacts(stateValidator stateValidator, resolver ArtifactResolver, stateStore stateStore, logger logger) PinArtifacts {
+	return PinArtifacts{
+		stateValidator: stateValidator,
+		resolver:       resolver,
+		stateStore:     stateStore,
+		logger:         logger,
+	}
+}
+
+func (p PinArtifacts) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parsePinArtifactsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = p.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.NoDirector {
+		return errors.New("pin-artifacts needs an environment with a director.")
+	}
+
+	return nil
+}
+
+func (p PinArtifacts) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parsePinArtifactsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	pins := storage.ArtifactOverrides{}
+	pinned := []string{}
+
+	if config.bosh != "" {
+		p.logger.Step("resolving version %s of the bosh release", config.bosh)
+		artifact, err := p.resolver.BOSHRelease(config.bosh)
+		if err != nil {
+			return fmt.Errorf("Resolve bosh release: %s", err)
+		}
+		pins.BOSHReleaseURL, pins.BOSHReleaseSHA1 = artifact.URL, artifact.SHA1
+		pinned = append(pinned, fmt.Sprintf("%s/%s", artifact.Name, artifact.Version))
+	}
+
+	if config.cpi != "" {
+		p.logger.Step("resolving version %s of the cpi release", config.cpi)
+		artifact, err := p.resolver.CPIRelease(state.IAAS, config.cpi)
+		if err != nil {
+			return fmt.Errorf("Resolve cpi release: %s", err)
+		}
+		pins.CPIReleaseURL, pins.CPIReleaseSHA1 = artifact.URL, artifact.SHA1
+		pinned = append(pinned, fmt.Sprintf("%s/%s", artifact.Name, artifact.Version))
+	}
+
+	if config.stemcell != "" {
+		p.logger.Step("resolving version %s of the stemcell", config.stemcell)
+		artifact, err := p.resolver.Stemcell(state.IAAS, config.stemcell)
+		if err != nil {
+			return fmt.Errorf("Resolve stemcell: %s", err)
+		}
+		pins.StemcellURL, pins.StemcellSHA1 = artifact.URL, artifact.SHA1
+		pinned = append(pinned, fmt.Sprintf("%s/%s", artifact.Name, artifact.Version))
+	}
+
+	overrides := pins
+	if state.ArtifactOverrides != nil {
+		overrides = state.ArtifactOverrides.Merge(pins)
+	}
+	state.ArtifactOverrides = &overrides
+
+	err = p.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state: %s", err)
+	}
+
+	p.logger.Println(fmt.Sprintf("Pinned %s. Run bbl plan and bbl up to build the director from them.", strings.Join(pinned, ", ")))
+	return nil
+}
+
+func parsePinArtifactsArgs(args []string) (pinArtifactsConfig, error) {
+	var config pinArtifactsConfig
+
+	pinFlags := flags.New("pin-artifacts")
+	pinFlags.String(&config.bosh, "bosh", "")
+	pinFlags.String(&config.cpi, "cpi", "")
+	pinFlags.String(&config.stemcell, "stemcell", "")
+
+	err := pinFlags.Parse(args)
+	if err != nil {
+		return pinArtifactsConfig{}, err
+	}
+
+	if config == (pinArtifactsConfig{}) {
+		return pinArtifactsConfig{}, errors.New("Pass one or more of --bosh, --cpi and --stemcell, each with a version, a major version such as 270.x, or latest.")
+	}
+
+	return config, nil
+}
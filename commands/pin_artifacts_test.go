@@ -0,0 +1,118 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PinArtifacts", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		resolver       *fakes.ArtifactResolver
+		stateStore     *fakes.StateStore
+		logger         *fakes.Logger
+		command        commands.PinArtifacts
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		resolver = &fakes.ArtifactResolver{}
+		resolver.BOSHReleaseCall.Returns.Artifact = bosh.Artifact{Name: "bosh", Version: "270.10.0", URL: "bosh-url", SHA1: "bosh-sha1"}
+		resolver.StemcellCall.Returns.Artifact = bosh.Artifact{Name: "some-stemcell", Version: "3586.40", URL: "stemcell-url", SHA1: "stemcell-sha1"}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		command = commands.NewPinArtifacts(stateValidator, resolver, stateStore, logger)
+
+		state = storage.State{
+			IAAS: "aws",
+			ArtifactOverrides: &storage.ArtifactOverrides{
+				CPIReleaseURL:  "cpi-url",
+				CPIReleaseSHA1: "cpi-sha1",
+				StemcellURL:    "file:///some/stemcell.tgz",
+				StemcellSHA1:   "old-sha1",
+			},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--bosh", "latest"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires a version to pin", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("Pass one or more of --bosh, --cpi and --stemcell, each with a version, a major version such as 270.x, or latest."))
+		})
+
+		It("returns an error for an environment without a director", func() {
+			state.NoDirector = true
+
+			err := command.CheckFastFails([]string{"--bosh", "latest"}, state)
+			Expect(err).To(MatchError("pin-artifacts needs an environment with a director."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("records the resolved versions in the overrides of the state", func() {
+			err := command.Execute(context.Background(), []string{"--bosh", "270.x", "--stemcell", "latest"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolver.BOSHReleaseCall.Receives.Version).To(Equal("270.x"))
+			Expect(resolver.StemcellCall.Receives.IAAS).To(Equal("aws"))
+			Expect(resolver.StemcellCall.Receives.Version).To(Equal("latest"))
+			Expect(resolver.CPIReleaseCall.CallCount).To(Equal(0))
+
+			Expect(stateStore.SetCall.Receives[0].State.ArtifactOverrides).To(Equal(&storage.ArtifactOverrides{
+				BOSHReleaseURL:  "bosh-url",
+				BOSHReleaseSHA1: "bosh-sha1",
+				CPIReleaseURL:   "cpi-url",
+				CPIReleaseSHA1:  "cpi-sha1",
+				StemcellURL:     "stemcell-url",
+				StemcellSHA1:    "stemcell-sha1",
+			}))
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Pinned bosh/270.10.0, some-stemcell/3586.40. Run bbl plan and bbl up to build the director from them."))
+		})
+
+		It("pins the cpi release of the iaas", func() {
+			state.ArtifactOverrides = nil
+			resolver.CPIReleaseCall.Returns.Artifact = bosh.Artifact{Name: "bosh-gcp-cpi-release", Version: "30.0.0", URL: "new-cpi-url", SHA1: "new-cpi-sha1"}
+			state.IAAS = "gcp"
+
+			err := command.Execute(context.Background(), []string{"--cpi", "latest"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolver.CPIReleaseCall.Receives.IAAS).To(Equal("gcp"))
+			Expect(stateStore.SetCall.Receives[0].State.ArtifactOverrides).To(Equal(&storage.ArtifactOverrides{
+				CPIReleaseURL:  "new-cpi-url",
+				CPIReleaseSHA1: "new-cpi-sha1",
+			}))
+		})
+
+		It("saves nothing when a version cannot be resolved", func() {
+			resolver.StemcellCall.Returns.Error = errors.New("bosh.io has no version 9999.x of some-stemcell.")
+
+			err := command.Execute(context.Background(), []string{"--bosh", "270.x", "--stemcell", "9999.x"}, state)
+			Expect(err).To(MatchError("Resolve stemcell: bosh.io has no version 9999.x of some-stemcell."))
+			Expect(stateStore.SetCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the state cannot be saved", func() {
+			stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+
+			err := command.Execute(context.Background(), []string{"--bosh", "latest"}, state)
+			Expect(err).To(MatchError("Save state: disk full"))
+		})
+	})
+})
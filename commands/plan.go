@@ -1,11 +1,50 @@
 package commands
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// cheapDirectorInstanceType is the burstable director of --cheap
+// environments, which still fits UAA and CredHub.
+const cheapDirectorInstanceType = "t2.large"
+
+var (
+	vpcID = regexp.MustCompile(`^vpc-[0-9a-f]+$`)
+
+	// s3BucketName accepts the names of buckets that S3 serves with virtual
+	// host style requests, which the director makes.
+	s3BucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+	// sha1Digest also accepts the sha256 digests that bosh takes in the
+	// sha1 field of a release or stemcell.
+	sha1Digest = regexp.MustCompile(`^([0-9a-f]{40}|sha256:[0-9a-f]{64})$`)
+
+	awsInstanceType = regexp.MustCompile(`^[a-z][a-z0-9-]*\.[a-z0-9]+$`)
+
+	sniDomain = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}$`)
+
+	// syslogAddress accepts host names and IPv4 and IPv6 addresses.
+	syslogAddress = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.:-]*[a-zA-Z0-9])?$`)
 )
 
 type Plan struct {
@@ -15,13 +54,92 @@ type Plan struct {
 	envIDManager       envIDManager
 	terraformManager   terraformManager
 	lbArgsHandler      lbArgsHandler
+	boshClientProvider boshClientProvider
+	reader             fileio.FileReader
 	logger             logger
 	bblVersion         string
 }
 
 type PlanConfig struct {
-	Name string
-	LB   storage.LB
+	Name       string
+	LB         storage.LB
+	NoDirector bool
+	SSHCA      bool
+	AZs        []string
+	Minimal    bool
+	Lite       bool
+	VPCCIDR    string
+
+	// NATGateway routes the internal subnets through NAT gateways, and
+	// NATInstance keeps the NAT instance of a new environment instead.
+	NATGateway  bool
+	NATInstance bool
+
+	// Cheap plans the cost-saving profile of throwaway environments, whose
+	// deployments may bid SpotBidPrice for spot instances.
+	Cheap        bool
+	SpotBidPrice float64
+
+	// DualStack gives the VPC and its subnets IPv6 blocks.
+	DualStack bool
+
+	// ExistingVPCID is a VPC that bbl creates its subnets in, instead of
+	// creating and owning a VPC of its own.
+	ExistingVPCID string
+
+	// SubnetSizes and ReservedCIDRs plan the internal subnets instead of
+	// carving a /20 of the VPC for each availability zone.
+	SubnetSizes   map[string]int
+	ReservedCIDRs []string
+
+	// DirectorAllowedCIDRs and LBAllowedCIDRs replace anywhere as the blocks
+	// that may reach the jumpbox and director, and the load balancers.
+	DirectorAllowedCIDRs []string
+	LBAllowedCIDRs       []string
+
+	// TrustedCACerts holds the contents of the --trusted-ca-certs file.
+	TrustedCACerts string
+
+	DirectorPorts storage.DirectorPorts
+
+	// OpsFiles and VarsFiles are the --ops-file and --vars-file files of the
+	// operator, which replace the ones of the state.
+	OpsFiles  []storage.DirectorFile
+	VarsFiles []storage.DirectorFile
+
+	// DirectorVM sizes the director VM instead of bosh-deployment.
+	DirectorVM storage.DirectorVM
+
+	// DirectorSyslog forwards the logs of the director to a syslog server.
+	DirectorSyslog storage.DirectorSyslog
+
+	// S3Blobstore stores the blobs of the director in S3BlobstoreBucket, or
+	// in a bucket that bbl creates when it is empty.
+	S3Blobstore       bool
+	S3BlobstoreBucket string
+
+	// ArtifactOverrides replace the releases and stemcell of the director.
+	ArtifactOverrides storage.ArtifactOverrides
+
+	// ArtifactsDir holds the tarballs of the releases and stemcells of the
+	// jumpbox and director, for environments without internet access.
+	ArtifactsDir string
+
+	// Tags are the --tags key=value pairs, which are merged into the
+	// annotations of the state and tag the aws resources of the environment.
+	Tags [][2]string
+
+	// SNIDomains are the --lb-sni domain=certificate pairs, which are merged
+	// into the SNI domains of the state. A domain= pair removes the domain.
+	SNIDomains [][2]string
+
+	// CheckLBWorkloads compares the load balancers with the deployments of
+	// the director before they are attached.
+	CheckLBWorkloads bool
+
+	// IgnoreLBWorkloads removes the load balancers even though deployments of
+	// the director still use their vm_extensions.
+	IgnoreLBWorkloads bool
 }
 
 func NewPlan(boshManager boshManager,
@@ -30,6 +148,8 @@ func NewPlan(boshManager boshManager,
 	envIDManager envIDManager,
 	terraformManager terraformManager,
 	lbArgsHandler lbArgsHandler,
+	boshClientProvider boshClientProvider,
+	reader fileio.FileReader,
 	logger logger,
 	bblVersion string,
 ) Plan {
@@ -40,6 +160,8 @@ func NewPlan(boshManager boshManager,
 		envIDManager:       envIDManager,
 		terraformManager:   terraformManager,
 		lbArgsHandler:      lbArgsHandler,
+		boshClientProvider: boshClientProvider,
+		reader:             reader,
 		logger:             logger,
 		bblVersion:         bblVersion,
 	}
@@ -51,6 +173,16 @@ func (p Plan) CheckFastFails(args []string, state storage.State) error {
 		return err
 	}
 
+	return p.Check(config, state)
+}
+
+// Check returns why the plan of config cannot be made for state, before
+// anything is changed.
+func (p Plan) Check(config PlanConfig, state storage.State) error {
+	if upgrades := storage.RequiredUpgrades(state.Version); len(upgrades) > 0 {
+		return fmt.Errorf("The environment was last changed by bbl %s. Upgrade it with bbl up from bbl %s first. Run bbl pre-upgrade-check for the upgrade path.", state.BBLVersion, upgrades[0].BBLVersion)
+	}
+
 	if err := fastFailBOSHVersion(p.boshManager); err != nil {
 		return err
 	}
@@ -63,13 +195,36 @@ func (p Plan) CheckFastFails(args []string, state storage.State) error {
 		return fmt.Errorf("The director name cannot be changed for an existing environment. Current name is %s.", state.EnvID)
 	}
 
+	if config.NoDirector && !state.BOSH.IsEmpty() {
+		return errors.New(`A BOSH director already exists for this environment. Run bbl destroy before using "--no-director".`)
+	}
+
+	if config.Lite && !state.AWS.Lite && !state.BOSH.IsEmpty() {
+		return errors.New(`A BOSH director already exists for this environment. Run bbl destroy before using "--lite".`)
+	}
+
 	return nil
 }
 
 func (p Plan) ParseArgs(args []string, state storage.State) (PlanConfig, error) {
 	var (
-		config PlanConfig
-		lbArgs LBArgs
+		config         PlanConfig
+		lbArgs         LBArgs
+		azs            string
+		vpcCIDR        string
+		trustedCACerts string
+		directorPorts  string
+		diskSize       string
+		syslogPort     string
+		spotBidPrice   string
+		tags           []string
+		sniDomains     []string
+		subnetSizes    string
+		reservedCIDRs  string
+		directorCIDRs  string
+		lbCIDRs        string
+		opsFiles       []string
+		varsFiles      []string
 	)
 	planFlags := flags.New("up")
 	planFlags.String(&config.Name, "name", os.Getenv("BBL_ENV_NAME"))
@@ -77,8 +232,54 @@ func (p Plan) ParseArgs(args []string, state storage.State) (PlanConfig, error)
 	planFlags.String(&lbArgs.CertPath, "lb-cert", "")
 	planFlags.String(&lbArgs.KeyPath, "lb-key", "")
 	planFlags.String(&lbArgs.Domain, "lb-domain", "")
+	planFlags.Bool(&config.CheckLBWorkloads, "lb-check-workloads", false)
+	planFlags.Bool(&config.IgnoreLBWorkloads, "lb-ignore-workloads", false)
+	planFlags.Bool(&config.NoDirector, "no-director", false)
+	planFlags.Bool(&config.SSHCA, "ssh-ca", false)
+	planFlags.String(&trustedCACerts, "trusted-ca-certs", "")
+	planFlags.String(&diskSize, "director-disk-size", "")
+	planFlags.String(&config.ArtifactOverrides.BOSHReleaseURL, "bosh-release-url", "")
+	planFlags.String(&config.ArtifactOverrides.BOSHReleaseSHA1, "bosh-release-sha1", "")
+	planFlags.String(&config.ArtifactOverrides.CPIReleaseURL, "cpi-release-url", "")
+	planFlags.String(&config.ArtifactOverrides.CPIReleaseSHA1, "cpi-release-sha1", "")
+	planFlags.String(&config.ArtifactOverrides.StemcellURL, "stemcell-url", "")
+	planFlags.String(&config.ArtifactOverrides.StemcellSHA1, "stemcell-sha1", "")
+	planFlags.String(&config.ArtifactsDir, "artifacts-dir", "")
+	planFlags.String(&config.DirectorSyslog.Address, "syslog-address", "")
+	planFlags.String(&syslogPort, "syslog-port", "")
+	planFlags.String(&config.DirectorSyslog.Transport, "syslog-transport", "")
+	planFlags.StringSlice(&opsFiles, "ops-file")
+	planFlags.StringSlice(&varsFiles, "vars-file")
 	if state.IAAS == "aws" {
 		planFlags.String(&lbArgs.ChainPath, "lb-chain", "")
+		planFlags.String(&lbArgs.CertARN, "lb-cert-arn", "")
+		planFlags.Bool(&lbArgs.ACMCertificate, "lb-acm-certificate", false)
+		planFlags.String(&lbArgs.DNSRoleARN, "lb-dns-role-arn", "")
+		planFlags.String(&lbArgs.DNSZoneID, "lb-dns-zone-id", "")
+		planFlags.String(&lbArgs.AppsDomain, "lb-apps-domain", "")
+		planFlags.String(&lbArgs.HealthCheck, "lb-health-check", "")
+		planFlags.Bool(&lbArgs.Internal, "lb-internal", false)
+		planFlags.Bool(&lbArgs.ELBv2, "lb-elbv2", false)
+		planFlags.String(&azs, "azs", "")
+		planFlags.Bool(&config.Minimal, "minimal", false)
+		planFlags.Bool(&config.Lite, "lite", false)
+		planFlags.Bool(&config.NATGateway, "nat-gateway", false)
+		planFlags.Bool(&config.NATInstance, "nat-instance", false)
+		planFlags.Bool(&config.Cheap, "cheap", false)
+		planFlags.String(&spotBidPrice, "spot-bid-price", "")
+		planFlags.Bool(&config.DualStack, "dual-stack", false)
+		planFlags.String(&vpcCIDR, "vpc-cidr", "")
+		planFlags.String(&config.ExistingVPCID, "existing-vpc-id", "")
+		planFlags.String(&subnetSizes, "subnet-sizes", "")
+		planFlags.String(&reservedCIDRs, "reserved-cidrs", "")
+		planFlags.String(&directorCIDRs, "director-allowed-cidrs", "")
+		planFlags.String(&lbCIDRs, "lb-allowed-cidrs", "")
+		planFlags.String(&directorPorts, "director-ports", "")
+		planFlags.String(&config.DirectorVM.InstanceType, "director-instance-type", "")
+		planFlags.StringSlice(&tags, "tags")
+		planFlags.StringSlice(&sniDomains, "lb-sni")
+		planFlags.Bool(&config.S3Blobstore, "s3-blobstore", false)
+		planFlags.String(&config.S3BlobstoreBucket, "s3-blobstore-bucket", "")
 	}
 
 	err := planFlags.Parse(args)
@@ -86,7 +287,194 @@ func (p Plan) ParseArgs(args []string, state storage.State) (PlanConfig, error)
 		return PlanConfig{}, err
 	}
 
+	for _, az := range strings.Split(azs, ",") {
+		if az = strings.TrimSpace(az); az != "" {
+			config.AZs = append(config.AZs, az)
+		}
+	}
+
+	if vpcCIDR != "" {
+		isPaved, err := p.isPaved()
+		if err != nil {
+			return PlanConfig{}, err
+		}
+		config.VPCCIDR, err = parseVPCCIDR(vpcCIDR, state, isPaved)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if config.ExistingVPCID != "" {
+		if !vpcID.MatchString(config.ExistingVPCID) {
+			return PlanConfig{}, fmt.Errorf("--existing-vpc-id %q is not a VPC ID.", config.ExistingVPCID)
+		}
+		if config.ExistingVPCID != state.AWS.ExistingVPCID {
+			isPaved, err := p.isPaved()
+			if err != nil {
+				return PlanConfig{}, err
+			}
+			if isPaved {
+				return PlanConfig{}, errors.New("The VPC of an existing environment cannot be changed.")
+			}
+		}
+	}
+
+	if subnetSizes != "" {
+		config.SubnetSizes, err = parseSubnetSizes(subnetSizes)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if reservedCIDRs != "" {
+		config.ReservedCIDRs, err = parseCIDRs("reserved-cidrs", reservedCIDRs)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if directorCIDRs != "" {
+		config.DirectorAllowedCIDRs, err = parseCIDRs("director-allowed-cidrs", directorCIDRs)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if lbCIDRs != "" {
+		config.LBAllowedCIDRs, err = parseCIDRs("lb-allowed-cidrs", lbCIDRs)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if (config.SubnetSizes != nil || config.ReservedCIDRs != nil) && !sameSubnetPlan(config, state.AWS) {
+		isPaved, err := p.isPaved()
+		if err != nil {
+			return PlanConfig{}, err
+		}
+		if isPaved {
+			return PlanConfig{}, errors.New("The subnets of an existing environment cannot be changed.")
+		}
+	}
+
+	if len(tags) > 0 {
+		config.Tags, err = parseAnnotations(tags)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if directorPorts != "" {
+		config.DirectorPorts, err = parseDirectorPorts(directorPorts)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if config.DirectorVM.InstanceType != "" && !awsInstanceType.MatchString(config.DirectorVM.InstanceType) {
+		return PlanConfig{}, fmt.Errorf("--director-instance-type %q is not an EC2 instance type.", config.DirectorVM.InstanceType)
+	}
+
+	if diskSize != "" {
+		config.DirectorVM.DiskSize, err = strconv.Atoi(diskSize)
+		if err != nil || config.DirectorVM.DiskSize < 1 {
+			return PlanConfig{}, fmt.Errorf("--director-disk-size %q is not a size in GB.", diskSize)
+		}
+	}
+
+	if !config.DirectorSyslog.IsEmpty() || syslogPort != "" {
+		config.DirectorSyslog, err = parseDirectorSyslog(config.DirectorSyslog, syslogPort, state.DirectorSyslog)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if config.S3BlobstoreBucket != "" {
+		if !s3BucketName.MatchString(config.S3BlobstoreBucket) {
+			return PlanConfig{}, fmt.Errorf("--s3-blobstore-bucket %q is not an S3 bucket name.", config.S3BlobstoreBucket)
+		}
+		config.S3Blobstore = true
+	}
+
+	if config.S3Blobstore {
+		if config.DirectorPorts.Blobstore != 0 {
+			return PlanConfig{}, errors.New("--director-ports blobstore cannot be used with --s3-blobstore, since the director then has no blobstore of its own.")
+		}
+		// The blobs of the director would be left behind in the blobstore it
+		// was created with.
+		if !state.BOSH.IsEmpty() && !sameBlobstorePlan(config, state.AWS) {
+			return PlanConfig{}, errors.New("The blobstore of an existing director cannot be changed.")
+		}
+	}
+
+	err = validateArtifactOverrides(config.ArtifactOverrides)
+	if err != nil {
+		return PlanConfig{}, err
+	}
+
+	// The create-env scripts run from the state directory, so the tarballs
+	// are found from anywhere.
+	if config.ArtifactsDir != "" {
+		config.ArtifactsDir, err = filepath.Abs(config.ArtifactsDir)
+		if err != nil {
+			return PlanConfig{}, err //not tested
+		}
+	}
+
+	if trustedCACerts != "" {
+		config.TrustedCACerts, err = p.readTrustedCACerts(trustedCACerts)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if len(opsFiles) > 0 {
+		config.OpsFiles, err = p.readOpsFiles(opsFiles)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	if len(varsFiles) > 0 {
+		config.VarsFiles, err = p.readVarsFiles(varsFiles)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	// A cf load balancer planned without a certificate uses the one that bbl
+	// upload-certificate or bbl attach-certificate attached to the cf router.
+	if certificate := state.AWS.AttachedCertificate("cf-router"); lbArgs.LBType == "cf" && lbArgs.CertPath == "" && lbArgs.KeyPath == "" && lbArgs.CertARN == "" && !lbArgs.ACMCertificate && certificate != nil {
+		lbArgs.CertARN = certificate.ARN
+	}
+
+	// Changing the scheme replaces the load balancers, so planning them again
+	// keeps an internal load balancer internal unless --lb-internal=false is
+	// given.
+	if lbArgs.LBType != "" && lbArgs.LBType == state.LB.Type && !planFlags.Changed("lb-internal") {
+		lbArgs.Internal = state.LB.Internal
+	}
+
+	// Likewise the load balancers stay ELBv2 unless --lb-elbv2=false is
+	// given.
+	if lbArgs.LBType != "" && lbArgs.LBType == state.LB.Type && !planFlags.Changed("lb-elbv2") {
+		lbArgs.ELBv2 = state.LB.ELBv2
+	}
+
+	// A cf load balancer planned again without a certificate keeps the
+	// certificate that bbl requested from ACM.
+	if lbArgs.LBType == "cf" && state.LB.ACMCertificate && lbArgs.CertPath == "" && lbArgs.KeyPath == "" && lbArgs.CertARN == "" {
+		lbArgs.ACMCertificate = true
+	}
+
 	if (lbArgs != LBArgs{}) {
+		if config.Lite {
+			return PlanConfig{}, errors.New("--lite cannot be used with a load balancer, since bosh-lite environments have none.")
+		}
+		if err := checkNotLite("--lb-type", state); err != nil {
+			return PlanConfig{}, err
+		}
+
 		lbState, err := p.lbArgsHandler.GetLBState(state.IAAS, lbArgs)
 		if err != nil {
 			return PlanConfig{}, err
@@ -94,23 +482,264 @@ func (p Plan) ParseArgs(args []string, state storage.State) (PlanConfig, error)
 		config.LB = lbState
 	}
 
+	if len(sniDomains) > 0 {
+		config.SNIDomains, err = parseSNIDomains(sniDomains, config.LB, state.AWS)
+		if err != nil {
+			return PlanConfig{}, err
+		}
+	}
+
+	// Each SNI domain is served by a classic load balancer of its own, next
+	// to the classic router load balancer.
+	if config.LB.ELBv2 && len(applySNIDomains(state.AWS.SNIDomains, config.SNIDomains)) > 0 {
+		return PlanConfig{}, errors.New("--lb-elbv2 cannot be used with the SNI domains of --lb-sni, which are served by classic load balancers.")
+	}
+
+	if config.NATGateway && config.NATInstance {
+		return PlanConfig{}, errors.New("--nat-gateway cannot be used with --nat-instance.")
+	}
+	if config.NATGateway && (config.Minimal || config.Lite || state.AWS.Minimal) {
+		return PlanConfig{}, errors.New("--nat-gateway cannot be used for a minimal environment, which has no NAT.")
+	}
+	if config.NATInstance && state.AWS.NATGateway {
+		return PlanConfig{}, errors.New("The environment uses NAT gateways already, which bbl does not replace with a NAT instance.")
+	}
+
+	// The profile decides the subnets and the NAT of the environment, which
+	// terraform would replace.
+	if config.Cheap && !state.AWS.Cheap {
+		isPaved, err := p.isPaved()
+		if err != nil {
+			return PlanConfig{}, err
+		}
+		if isPaved {
+			return PlanConfig{}, errors.New("--cheap cannot be used for an existing environment. Run bbl destroy first.")
+		}
+	}
+	if config.Cheap && config.NATGateway {
+		return PlanConfig{}, errors.New("--nat-gateway cannot be used with --cheap, which routes through a small NAT instance.")
+	}
+	// Application load balancers need subnets in two availability zones.
+	if config.LB.ELBv2 && (config.Cheap || state.AWS.Cheap) && len(config.AZs) < 2 && len(state.AWS.AZs) < 2 {
+		return PlanConfig{}, errors.New("--lb-elbv2 needs two availability zones, which --cheap leaves out. Pass them with --azs.")
+	}
+
+	// bbl can only give the VPC that it owns an IPv6 block.
+	if config.DualStack && (config.ExistingVPCID != "" || state.AWS.ExistingVPCID != "") {
+		return PlanConfig{}, errors.New("--dual-stack cannot be used with --existing-vpc-id, since bbl does not own the VPC.")
+	}
+
+	if spotBidPrice != "" {
+		config.SpotBidPrice, err = strconv.ParseFloat(spotBidPrice, 64)
+		if err != nil || config.SpotBidPrice <= 0 {
+			return PlanConfig{}, fmt.Errorf("--spot-bid-price %q is not a price in USD per hour.", spotBidPrice)
+		}
+		if !config.Cheap && !state.AWS.Cheap {
+			return PlanConfig{}, errors.New("--spot-bid-price needs --cheap.")
+		}
+	}
+
+	if config.Lite {
+		if config.NoDirector {
+			return PlanConfig{}, errors.New("--lite cannot be used with --no-director.")
+		}
+		if state.LB.Type != "" {
+			return PlanConfig{}, fmt.Errorf("--lite cannot be used for an environment with a %s load balancer. Run bbl detach-lb first.", state.LB.Type)
+		}
+	}
+
 	return config, nil
 }
 
-func (p Plan) Execute(args []string, state storage.State) error {
+func (p Plan) Execute(ctx context.Context, args []string, state storage.State) error {
 	config, err := p.ParseArgs(args, state)
 	if err != nil {
 		return err
 	}
 
+	p.CheckLBWorkloads(config, state)
+
+	err = p.CheckLBRemoval(config, state)
+	if err != nil {
+		return err
+	}
+
 	_, err = p.InitializePlan(config, state)
 	return err
 }
 
+// CheckLBRemoval refuses to remove the load balancers of the state while
+// deployments of the director still use their vm_extensions, which the cloud
+// config no longer defines once they are removed.
+func (p Plan) CheckLBRemoval(config PlanConfig, state storage.State) error {
+	if state.LB.Type == "" || config.LB.Type != "" || config.IgnoreLBWorkloads {
+		return nil
+	}
+
+	if state.NoDirector || state.BOSH.DirectorAddress == "" {
+		return nil
+	}
+
+	var users []string
+	client, err := p.boshClientProvider.Client(state.Jumpbox, state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	if err == nil {
+		users, err = bosh.LBWorkloadUsers(client, state.LB.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("Could not check whether deployments of the director use the %s load balancer: %s. Pass --lb-ignore-workloads to remove it without checking.", state.LB.Type, err)
+	}
+
+	if len(users) > 0 {
+		return bblerrors.New(bblerrors.Conflict, fmt.Errorf("The %s load balancer is still used by the deployments of the director:\n  %s\nRemove the vm_extensions from the deployments and redeploy them, pass --lb-type %s to keep the load balancer, or pass --lb-ignore-workloads to remove it anyway.", state.LB.Type, strings.Join(users, "\n  "), state.LB.Type))
+	}
+
+	return nil
+}
+
+// CheckLBWorkloads warns when the deployments of the director are not ready
+// for the load balancers of the plan, so that they can be deployed, or given
+// the vm_extensions of the load balancers, in the right order. It never fails
+// the plan: a director that cannot be reached is only reported.
+func (p Plan) CheckLBWorkloads(config PlanConfig, state storage.State) {
+	if !config.CheckLBWorkloads || config.LB.Type == "" {
+		return
+	}
+
+	if state.NoDirector || state.BOSH.DirectorAddress == "" {
+		p.logger.Println(fmt.Sprintf("The environment has no director yet, so the %s load balancer is not compared with its deployments.", config.LB.Type))
+		return
+	}
+
+	client, err := p.boshClientProvider.Client(state.Jumpbox, state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	if err != nil {
+		p.logger.Println(fmt.Sprintf("Could not connect to the director, so its deployments are not checked: %s", err))
+		return
+	}
+
+	warnings, err := bosh.LBWorkloadWarnings(client, config.LB.Type)
+	if err != nil {
+		p.logger.Println(fmt.Sprintf("Could not check the deployments of the director: %s", err))
+		return
+	}
+
+	for _, warning := range warnings {
+		p.logger.Println(fmt.Sprintf("Warning: %s", warning))
+	}
+}
+
 func (p Plan) InitializePlan(config PlanConfig, state storage.State) (storage.State, error) {
 	state.BBLVersion = p.bblVersion
 	state.LB = config.LB
-	state.NoDirector = false
+	// A new plan is applied from the start by the next up.
+	state.UpProgress = nil
+	if config.NoDirector {
+		state.NoDirector = true
+	}
+	if config.SSHCA {
+		state.SSHCA = true
+	}
+	if len(config.AZs) > 0 {
+		state.AWS.AZs = config.AZs
+	}
+	if config.Minimal {
+		state.AWS.Minimal = true
+	}
+	if config.Lite {
+		// The warden containers reach the internet through the director,
+		// which needs a public address of its own without a NAT instance.
+		state.AWS.Lite = true
+		state.AWS.Minimal = true
+	}
+	if config.Cheap {
+		state.AWS.Cheap = true
+	}
+	if config.SpotBidPrice != 0 {
+		state.AWS.SpotBidPrice = config.SpotBidPrice
+	}
+	if config.DualStack {
+		state.AWS.DualStack = true
+	}
+	// New environments route the internal subnets through NAT gateways,
+	// while existing ones keep their NAT instance until bbl plan
+	// --nat-gateway. Cheap ones keep a small NAT instance.
+	newEnvironment := state.EnvID == "" && state.IAAS == "aws" && !config.NATInstance && !state.AWS.Minimal && !state.AWS.Cheap
+	if config.NATGateway || newEnvironment {
+		state.AWS.NATGateway = true
+	}
+	if config.VPCCIDR != "" {
+		state.AWS.VPCCIDR = config.VPCCIDR
+	}
+	if config.ExistingVPCID != "" {
+		state.AWS.ExistingVPCID = config.ExistingVPCID
+	}
+	if config.SubnetSizes != nil {
+		state.AWS.SubnetSizes = config.SubnetSizes
+	}
+	if config.ReservedCIDRs != nil {
+		state.AWS.ReservedCIDRs = config.ReservedCIDRs
+	}
+	if config.DirectorAllowedCIDRs != nil {
+		state.AWS.DirectorAllowedCIDRs = config.DirectorAllowedCIDRs
+	}
+	if config.LBAllowedCIDRs != nil {
+		state.AWS.LBAllowedCIDRs = config.LBAllowedCIDRs
+	}
+	if config.S3Blobstore {
+		state.AWS.S3Blobstore = true
+	}
+	if config.S3BlobstoreBucket != "" {
+		state.AWS.S3BlobstoreBucket = config.S3BlobstoreBucket
+	}
+	if config.TrustedCACerts != "" {
+		state.TrustedCACerts = config.TrustedCACerts
+	}
+	if config.OpsFiles != nil {
+		state.DirectorOpsFiles = config.OpsFiles
+	}
+	if config.VarsFiles != nil {
+		state.DirectorVarsFiles = config.VarsFiles
+	}
+	if len(config.Tags) > 0 {
+		state.Annotations = applyAnnotations(state.Annotations, config.Tags)
+	}
+	if len(config.SNIDomains) > 0 {
+		state.AWS.SNIDomains = applySNIDomains(state.AWS.SNIDomains, config.SNIDomains)
+	}
+	if !config.DirectorPorts.IsEmpty() {
+		ports := config.DirectorPorts
+		state.DirectorPorts = &ports
+	}
+	if !config.DirectorVM.IsEmpty() {
+		vm := config.DirectorVM
+		if state.DirectorVM != nil {
+			vm = state.DirectorVM.Merge(vm)
+		}
+		state.DirectorVM = &vm
+	}
+	if state.AWS.Cheap && (state.DirectorVM == nil || state.DirectorVM.InstanceType == "") {
+		vm := storage.DirectorVM{InstanceType: cheapDirectorInstanceType}
+		if state.DirectorVM != nil {
+			vm = state.DirectorVM.Merge(vm)
+		}
+		state.DirectorVM = &vm
+	}
+	if !config.DirectorSyslog.IsEmpty() {
+		syslog := config.DirectorSyslog
+		if state.DirectorSyslog != nil {
+			syslog = state.DirectorSyslog.Merge(syslog)
+		}
+		state.DirectorSyslog = &syslog
+	}
+	if !config.ArtifactOverrides.IsEmpty() {
+		overrides := config.ArtifactOverrides
+		if state.ArtifactOverrides != nil {
+			overrides = state.ArtifactOverrides.Merge(overrides)
+		}
+		state.ArtifactOverrides = &overrides
+	}
+	if config.ArtifactsDir != "" {
+		state.ArtifactsDir = config.ArtifactsDir
+	}
 
 	var err error
 	state, err = p.envIDManager.Sync(state, config.Name)
@@ -123,23 +752,363 @@ func (p Plan) InitializePlan(config PlanConfig, state storage.State) (storage.St
 		return storage.State{}, fmt.Errorf("Save state: %s", err)
 	}
 
-	if err := p.terraformManager.Init(state); err != nil {
-		return storage.State{}, fmt.Errorf("Terraform manager init: %s", err)
+	// The terraform template, the cloud config and the create-env scripts are
+	// written to their own directories, so terraform init and the lookup of
+	// the availability zones, which take the longest, do not hold up the rest.
+	err = runConcurrently(
+		func() error {
+			if err := p.terraformManager.Init(state); err != nil {
+				return fmt.Errorf("Terraform manager init: %s", err)
+			}
+			return nil
+		},
+		func() error {
+			if err := p.cloudConfigManager.Initialize(state); err != nil {
+				return fmt.Errorf("Cloud config manager initialize: %s", err)
+			}
+			return nil
+		},
+		func() error {
+			if err := p.boshManager.InitializeJumpbox(state); err != nil {
+				return fmt.Errorf("Bosh manager initialize jumpbox: %s", err)
+			}
+
+			if err := p.boshManager.InitializeDirector(state); err != nil {
+				return fmt.Errorf("Bosh manager initialize director: %s", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return storage.State{}, err
 	}
 
-	if err := p.cloudConfigManager.Initialize(state); err != nil {
-		return storage.State{}, fmt.Errorf("Cloud config manager initialize: %s", err)
+	return state, nil
+}
+
+// readTrustedCACerts reads a PEM file of one or more CA certificates, so that
+// a typo in the file fails the plan rather than the director deployment.
+func (p Plan) readTrustedCACerts(path string) (string, error) {
+	contents, err := p.reader.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Read trusted CA certificates: %s", err)
 	}
 
-	if err := p.boshManager.InitializeJumpbox(state); err != nil {
-		return storage.State{}, fmt.Errorf("Bosh manager initialize jumpbox: %s", err)
+	count := 0
+	rest := contents
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return "", fmt.Errorf("Parse trusted CA certificates: %s", err)
+		}
+		count++
 	}
 
-	if err := p.boshManager.InitializeDirector(state); err != nil {
-		return storage.State{}, fmt.Errorf("Bosh manager initialize director: %s", err)
+	if count == 0 {
+		return "", fmt.Errorf("%s does not contain any PEM encoded certificates.", path)
 	}
 
-	return state, nil
+	return string(contents), nil
+}
+
+// readOpsFiles reads the go-patch ops files of --ops-file, so that a typo in
+// an operation fails the plan rather than the director deployment.
+func (p Plan) readOpsFiles(paths []string) ([]storage.DirectorFile, error) {
+	files := []storage.DirectorFile{}
+	for _, path := range paths {
+		contents, err := p.reader.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Read ops file: %s", err)
+		}
+
+		var ops []struct {
+			Type string `yaml:"type"`
+			Path string `yaml:"path"`
+		}
+		err = yaml.Unmarshal(contents, &ops)
+		if err != nil {
+			return nil, fmt.Errorf("--ops-file %s is not a list of operations: %s", path, err)
+		}
+		for _, op := range ops {
+			if op.Type != "replace" && op.Type != "remove" {
+				return nil, fmt.Errorf("--ops-file %s has an operation of type %q, which is not replace or remove.", path, op.Type)
+			}
+			if !strings.HasPrefix(op.Path, "/") {
+				return nil, fmt.Errorf("--ops-file %s has an operation with the path %q, which does not start with /.", path, op.Path)
+			}
+		}
+
+		files = append(files, storage.DirectorFile{Name: filepath.Base(path), Contents: string(contents)})
+	}
+	return files, nil
+}
+
+// readVarsFiles reads the YAML files of variables of --vars-file.
+func (p Plan) readVarsFiles(paths []string) ([]storage.DirectorFile, error) {
+	files := []storage.DirectorFile{}
+	for _, path := range paths {
+		contents, err := p.reader.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Read vars file: %s", err)
+		}
+
+		var vars map[string]interface{}
+		err = yaml.Unmarshal(contents, &vars)
+		if err != nil {
+			return nil, fmt.Errorf("--vars-file %s is not a map of variables: %s", path, err)
+		}
+
+		files = append(files, storage.DirectorFile{Name: filepath.Base(path), Contents: string(contents)})
+	}
+	return files, nil
+}
+
+// parseSNIDomains reads the --lb-sni domain=certificate pairs. The
+// certificates are the ones of bbl create-certificate, and only the cf load
+// balancer serves SNI domains.
+func parseSNIDomains(args []string, lb storage.LB, state storage.AWS) ([][2]string, error) {
+	domains := [][2]string{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("--lb-sni %q is not domain=certificate, or domain= to remove the domain.", arg)
+		}
+		domain, certificate := parts[0], parts[1]
+		if !sniDomain.MatchString(domain) {
+			return nil, fmt.Errorf("--lb-sni %q is not a domain name.", domain)
+		}
+		if certificate != "" {
+			if lb.Type != "cf" {
+				return nil, errors.New("--lb-sni can only add domains to a cf load balancer. Pass it with --lb-type cf.")
+			}
+			if state.CertificateNamed(certificate) == nil {
+				return nil, fmt.Errorf("There is no certificate named %s. Create it with bbl create-certificate.", certificate)
+			}
+		}
+		domains = append(domains, [2]string{strings.ToLower(domain), certificate})
+	}
+	return domains, nil
+}
+
+// applySNIDomains returns the existing SNI domains with each domain=certificate
+// pair set, and those given as domain= removed, or nil when none are left. The
+// domains keep their order, since each is served by the load balancer at its
+// index.
+func applySNIDomains(existing []storage.SNIDomain, domains [][2]string) []storage.SNIDomain {
+	merged := append([]storage.SNIDomain{}, existing...)
+	for _, domain := range domains {
+		index := -1
+		for i := range merged {
+			if merged[i].Domain == domain[0] {
+				index = i
+			}
+		}
+
+		switch {
+		case domain[1] == "" && index >= 0:
+			merged = append(merged[:index], merged[index+1:]...)
+		case domain[1] == "":
+		case index >= 0:
+			merged[index].Certificate = domain[1]
+		default:
+			merged = append(merged, storage.SNIDomain{Domain: domain[0], Certificate: domain[1]})
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// validateArtifactOverrides checks that each url comes with the sha1 that
+// bosh create-env verifies the download against.
+func validateArtifactOverrides(overrides storage.ArtifactOverrides) error {
+	pairs := []struct {
+		name      string
+		url, sha1 string
+	}{
+		{"bosh-release", overrides.BOSHReleaseURL, overrides.BOSHReleaseSHA1},
+		{"cpi-release", overrides.CPIReleaseURL, overrides.CPIReleaseSHA1},
+		{"stemcell", overrides.StemcellURL, overrides.StemcellSHA1},
+	}
+
+	for _, pair := range pairs {
+		if pair.url == "" && pair.sha1 == "" {
+			continue
+		}
+		if pair.url == "" || pair.sha1 == "" {
+			return fmt.Errorf("--%s-url and --%s-sha1 must be passed together.", pair.name, pair.name)
+		}
+		if u, err := url.Parse(pair.url); err != nil || u.Scheme == "" {
+			return fmt.Errorf("--%s-url %q is not a URL.", pair.name, pair.url)
+		}
+		if !sha1Digest.MatchString(pair.sha1) {
+			return fmt.Errorf("--%s-sha1 %q is not a SHA1 digest.", pair.name, pair.sha1)
+		}
+	}
+
+	return nil
+}
+
+// parseDirectorPorts reads a comma-separated list of service=port pairs.
+func parseDirectorPorts(list string) (storage.DirectorPorts, error) {
+	ports := storage.DirectorPorts{}
+	for _, pair := range strings.Split(list, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return storage.DirectorPorts{}, fmt.Errorf("--director-ports %q is not a service=port pair.", pair)
+		}
+
+		port, err := strconv.Atoi(parts[1])
+		if err != nil || port < 1 || port > 65535 {
+			return storage.DirectorPorts{}, fmt.Errorf("--director-ports %q is not a valid port.", parts[1])
+		}
+
+		switch parts[0] {
+		case "blobstore":
+			ports.Blobstore = port
+		case "nats":
+			ports.NATS = port
+		case "registry":
+			ports.Registry = port
+		case "mbus":
+			ports.Mbus = port
+		default:
+			return storage.DirectorPorts{}, fmt.Errorf("--director-ports %q is not one of blobstore, nats, registry or mbus.", parts[0])
+		}
+	}
+
+	return ports, nil
+}
+
+// parseDirectorSyslog checks the --syslog flags. A new forwarding needs an
+// address, and sends over TCP to port 514 unless told otherwise, while the
+// flags of a planned one change only what they set.
+func parseDirectorSyslog(syslog storage.DirectorSyslog, port string, planned *storage.DirectorSyslog) (storage.DirectorSyslog, error) {
+	if syslog.Address != "" && !syslogAddress.MatchString(syslog.Address) {
+		return storage.DirectorSyslog{}, fmt.Errorf("--syslog-address %q is not a host name or IP address.", syslog.Address)
+	}
+
+	if port != "" {
+		var err error
+		syslog.Port, err = strconv.Atoi(port)
+		if err != nil || syslog.Port < 1 || syslog.Port > 65535 {
+			return storage.DirectorSyslog{}, fmt.Errorf("--syslog-port %q is not a valid port.", port)
+		}
+	}
+
+	switch syslog.Transport {
+	case "", "tcp", "udp", "relp":
+	default:
+		return storage.DirectorSyslog{}, fmt.Errorf("--syslog-transport %q is not one of tcp, udp or relp.", syslog.Transport)
+	}
+
+	if planned == nil {
+		if syslog.Address == "" {
+			return storage.DirectorSyslog{}, errors.New("--syslog-port and --syslog-transport need a --syslog-address.")
+		}
+		if syslog.Port == 0 {
+			syslog.Port = 514
+		}
+		if syslog.Transport == "" {
+			syslog.Transport = "tcp"
+		}
+	}
+
+	return syslog, nil
+}
+
+// parseVPCCIDR checks that the block leaves room for the subnets that the
+// terraform templates carve out of it, and that it does not change the block
+// of a VPC that already exists.
+func parseVPCCIDR(cidr string, state storage.State, isPaved bool) (string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil || network.IP.To4() == nil {
+		return "", fmt.Errorf("--vpc-cidr %q is not an IPv4 CIDR block.", cidr)
+	}
+
+	if ones, _ := network.Mask.Size(); ones < 16 || ones > 20 {
+		return "", fmt.Errorf("--vpc-cidr %q must be between /16 and /20.", cidr)
+	}
+
+	current := state.AWS.VPCCIDR
+	if current == "" {
+		current = "10.0.0.0/16"
+	}
+	if isPaved && network.String() != current {
+		return "", fmt.Errorf("The VPC of this environment uses %s, which cannot be changed without recreating it.", current)
+	}
+
+	return network.String(), nil
+}
+
+// parseSubnetSizes reads the prefix length of the internal subnet of each
+// availability zone, for example: us-east-1a=20,us-east-1b=22. Whether they
+// fit the VPC is checked once the availability zones are known.
+func parseSubnetSizes(value string) (map[string]int, error) {
+	sizes := map[string]int{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("--subnet-sizes %q is not an az=prefix-length pair.", pair)
+		}
+		prefix, err := strconv.Atoi(strings.TrimPrefix(parts[1], "/"))
+		if err != nil || prefix < 16 || prefix > 28 {
+			return nil, fmt.Errorf("--subnet-sizes %q must be a prefix length between 16 and 28.", parts[1])
+		}
+		sizes[parts[0]] = prefix
+	}
+	return sizes, nil
+}
+
+// parseCIDRs reads the comma separated IPv4 CIDR blocks of the flag name.
+func parseCIDRs(name, value string) ([]string, error) {
+	cidrs := []string{}
+	for _, cidr := range strings.Split(value, ",") {
+		cidr = strings.TrimSpace(cidr)
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil || network.IP.To4() == nil {
+			return nil, fmt.Errorf("--%s %q is not an IPv4 CIDR block.", name, cidr)
+		}
+		cidrs = append(cidrs, network.String())
+	}
+	return cidrs, nil
+}
+
+// sameAllowedCIDRsPlan is whether the security groups of aws already allow
+// the blocks of config.
+func sameAllowedCIDRsPlan(config PlanConfig, aws storage.AWS) bool {
+	return (config.DirectorAllowedCIDRs == nil || reflect.DeepEqual(config.DirectorAllowedCIDRs, aws.DirectorAllowedCIDRs)) &&
+		(config.LBAllowedCIDRs == nil || reflect.DeepEqual(config.LBAllowedCIDRs, aws.LBAllowedCIDRs))
+}
+
+func sameSubnetPlan(config PlanConfig, aws storage.AWS) bool {
+	return (config.SubnetSizes == nil || reflect.DeepEqual(config.SubnetSizes, aws.SubnetSizes)) &&
+		(config.ReservedCIDRs == nil || reflect.DeepEqual(config.ReservedCIDRs, aws.ReservedCIDRs))
+}
+
+// sameBlobstorePlan is whether the director of aws already stores its blobs
+// where config puts them.
+func sameBlobstorePlan(config PlanConfig, aws storage.AWS) bool {
+	return aws.S3Blobstore && (config.S3BlobstoreBucket == "" || config.S3BlobstoreBucket == aws.S3BlobstoreBucket)
+}
+
+// isPaved reports whether terraform has created the environment. The state
+// migrator keeps its terraform state in the vars directory, so the TFState of
+// the bbl state is always empty.
+func (p Plan) isPaved() (bool, error) {
+	isPaved, err := p.terraformManager.IsPaved()
+	if err != nil {
+		return false, fmt.Errorf("Check for existing infrastructure: %s", err)
+	}
+	return isPaved, nil
 }
 
 func (p Plan) IsInitialized(state storage.State) bool {
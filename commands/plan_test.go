@@ -1,15 +1,20 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"os"
+	"path/filepath"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -21,6 +26,9 @@ var _ = Describe("Plan", func() {
 		cloudConfigManager *fakes.CloudConfigManager
 		envIDManager       *fakes.EnvIDManager
 		lbArgsHandler      *fakes.LBArgsHandler
+		boshClientProvider *fakes.BOSHClientProvider
+		boshClient         *fakes.BOSHClient
+		fileIO             *fakes.FileIO
 		logger             *fakes.Logger
 		stateStore         *fakes.StateStore
 		terraformManager   *fakes.TerraformManager
@@ -32,6 +40,10 @@ var _ = Describe("Plan", func() {
 		cloudConfigManager = &fakes.CloudConfigManager{}
 		envIDManager = &fakes.EnvIDManager{}
 		lbArgsHandler = &fakes.LBArgsHandler{}
+		boshClient = &fakes.BOSHClient{}
+		boshClientProvider = &fakes.BOSHClientProvider{}
+		boshClientProvider.ClientCall.Returns.Client = boshClient
+		fileIO = &fakes.FileIO{}
 		logger = &fakes.Logger{}
 		stateStore = &fakes.StateStore{}
 		terraformManager = &fakes.TerraformManager{}
@@ -46,6 +58,8 @@ var _ = Describe("Plan", func() {
 			envIDManager,
 			terraformManager,
 			lbArgsHandler,
+			boshClientProvider,
+			fileIO,
 			logger,
 			bblVersion,
 		)
@@ -67,7 +81,7 @@ var _ = Describe("Plan", func() {
 
 		It("sets up the bbl state dir", func() {
 			args := []string{}
-			err := command.Execute(args, state)
+			err := command.Execute(context.Background(), args, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(lbArgsHandler.GetLBStateCall.CallCount).To(Equal(0))
@@ -102,7 +116,7 @@ var _ = Describe("Plan", func() {
 
 			Context("aws", func() {
 				It("sets LB args on the state", func() {
-					err := command.Execute(
+					err := command.Execute(context.Background(),
 						[]string{
 							"--lb-type", "cf",
 							"--lb-cert", "cert",
@@ -123,45 +137,1030 @@ var _ = Describe("Plan", func() {
 
 					Expect(envIDManager.SyncCall.CallCount).To(Equal(1))
 					Expect(envIDManager.SyncCall.Receives.State.LB).To(Equal(lb))
+
+					Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when --lb-check-workloads is passed", func() {
+				BeforeEach(func() {
+					lbArgsHandler.GetLBStateCall.Returns.LB = storage.LB{Type: "cf"}
+					state.BOSH = storage.BOSH{
+						DirectorAddress:  "https://10.0.0.6:25555",
+						DirectorUsername: "admin",
+						DirectorPassword: "some-password",
+						DirectorSSLCA:    "some-ca",
+					}
+					boshClient.CurlCall.Stub = func(method, path string, body []byte) (int, []byte, error) {
+						switch path {
+						case "/deployments":
+							return 200, []byte(`[{"name": "cf"}]`), nil
+						case "/deployments/cf":
+							return 200, []byte(`{"manifest": "instance_groups:\n- name: router\n  jobs:\n  - name: gorouter\n"}`), nil
+						}
+						return 404, nil, nil
+					}
+				})
+
+				It("warns about the deployments that are not ready for the load balancers", func() {
+					err := command.Execute(context.Background(), []string{"--lb-type", "cf", "--lb-check-workloads"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("https://10.0.0.6:25555"))
+					Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-password"))
+					Expect(logger.PrintlnCall.Messages).To(ConsistOf(
+						"Warning: Instance group router of deployment cf runs gorouter without the vm_extension cf-router-network-properties, so the cf load balancer does not send it traffic. Add the vm_extension and redeploy it after bbl up.",
+					))
+					Expect(envIDManager.SyncCall.CallCount).To(Equal(1))
+				})
+
+				It("does not fail the plan when the director cannot be reached", func() {
+					boshClientProvider.ClientCall.Returns.Error = errors.New("no route")
+
+					err := command.Execute(context.Background(), []string{"--lb-type", "cf", "--lb-check-workloads"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Messages).To(ConsistOf("Could not connect to the director, so its deployments are not checked: no route"))
+					Expect(envIDManager.SyncCall.CallCount).To(Equal(1))
+				})
+
+				It("does not fail the plan when the deployments cannot be read", func() {
+					boshClient.CurlCall.Stub = nil
+					boshClient.CurlCall.Returns.Status = 401
+
+					err := command.Execute(context.Background(), []string{"--lb-type", "cf", "--lb-check-workloads"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Messages).To(ConsistOf("Could not check the deployments of the director: List deployments: unexpected http response 401 Unauthorized"))
+				})
+
+				It("says so when the environment has no director yet", func() {
+					state.BOSH = storage.BOSH{}
+
+					err := command.Execute(context.Background(), []string{"--lb-type", "cf", "--lb-check-workloads"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+					Expect(logger.PrintlnCall.Messages).To(ConsistOf("The environment has no director yet, so the cf load balancer is not compared with its deployments."))
 				})
 			})
 		})
 
+		Context("when the load balancer is removed", func() {
+			BeforeEach(func() {
+				state.LB = storage.LB{Type: "cf"}
+				state.BOSH = storage.BOSH{
+					DirectorAddress:  "https://10.0.0.6:25555",
+					DirectorUsername: "admin",
+					DirectorPassword: "some-password",
+					DirectorSSLCA:    "some-ca",
+				}
+				boshClient.CurlCall.Stub = func(method, path string, body []byte) (int, []byte, error) {
+					switch path {
+					case "/deployments":
+						return 200, []byte(`[{"name": "cf"}]`), nil
+					case "/deployments/cf":
+						return 200, []byte(`{"manifest": "instance_groups:\n- name: router\n  vm_extensions: [cf-router-network-properties]\n  jobs:\n  - name: gorouter\n"}`), nil
+					}
+					return 404, nil, nil
+				}
+			})
+
+			It("refuses while deployments use its vm_extensions", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("The cf load balancer is still used by the deployments of the director:\n  instance group router of deployment cf uses the vm_extension cf-router-network-properties\nRemove the vm_extensions from the deployments and redeploy them, pass --lb-type cf to keep the load balancer, or pass --lb-ignore-workloads to remove it anyway."))
+				Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Conflict))
+
+				Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("https://10.0.0.6:25555"))
+				Expect(envIDManager.SyncCall.CallCount).To(Equal(0))
+			})
+
+			It("removes it when no deployment uses its vm_extensions", func() {
+				boshClient.CurlCall.Stub = func(method, path string, body []byte) (int, []byte, error) {
+					return 200, []byte(`[]`), nil
+				}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.LB).To(Equal(storage.LB{}))
+			})
+
+			It("removes it anyway with --lb-ignore-workloads", func() {
+				err := command.Execute(context.Background(), []string{"--lb-ignore-workloads"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+				Expect(envIDManager.SyncCall.Receives.State.LB).To(Equal(storage.LB{}))
+			})
+
+			It("refuses when the director cannot be reached", func() {
+				boshClientProvider.ClientCall.Returns.Error = errors.New("no route")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Could not check whether deployments of the director use the cf load balancer: no route. Pass --lb-ignore-workloads to remove it without checking."))
+			})
+
+			It("does not check an environment without a director", func() {
+				state.BOSH = storage.BOSH{}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --no-director is passed", func() {
+			It("records it in the state", func() {
+				err := command.Execute(context.Background(), []string{"--no-director"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.NoDirector).To(BeTrue())
+			})
+		})
+
+		It("has the next up start from the beginning of the new plan", func() {
+			state.UpProgress = &storage.UpProgress{Completed: []string{"infrastructure"}}
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(envIDManager.SyncCall.Receives.State.UpProgress).To(BeNil())
+		})
+
+		Context("when --ssh-ca is passed", func() {
+			It("records it in the state", func() {
+				err := command.Execute(context.Background(), []string{"--ssh-ca"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.SSHCA).To(BeTrue())
+			})
+		})
+
+		Context("when --azs is passed", func() {
+			It("pins the availability zones in the state", func() {
+				err := command.Execute(context.Background(), []string{"--azs", "us-east-1a, us-east-1c"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.AZs).To(Equal([]string{"us-east-1a", "us-east-1c"}))
+			})
+
+			It("keeps the pinned availability zones without the flag", func() {
+				err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "aws", AWS: storage.AWS{AZs: []string{"us-east-1a"}}})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.AZs).To(Equal([]string{"us-east-1a"}))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--azs", "us-east-1a"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -azs"))
+			})
+		})
+
+		Context("when --minimal is passed", func() {
+			It("records it in the state", func() {
+				err := command.Execute(context.Background(), []string{"--minimal"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.Minimal).To(BeTrue())
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--minimal"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -minimal"))
+			})
+		})
+
+		Context("when --lite is passed", func() {
+			It("records a minimal bosh-lite environment in the state", func() {
+				err := command.Execute(context.Background(), []string{"--lite"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.Lite).To(BeTrue())
+				Expect(envIDManager.SyncCall.Receives.State.AWS.Minimal).To(BeTrue())
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--lite"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -lite"))
+			})
+
+			It("cannot be used with a load balancer", func() {
+				err := command.Execute(context.Background(), []string{"--lite", "--lb-type", "concourse"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--lite cannot be used with a load balancer, since bosh-lite environments have none."))
+				Expect(lbArgsHandler.GetLBStateCall.CallCount).To(Equal(0))
+			})
+
+			It("cannot be used for an environment with a load balancer", func() {
+				err := command.Execute(context.Background(), []string{"--lite"}, storage.State{IAAS: "aws", LB: storage.LB{Type: "cf"}})
+				Expect(err).To(MatchError("--lite cannot be used for an environment with a cf load balancer. Run bbl detach-lb first."))
+			})
+
+			It("cannot be used without a director", func() {
+				err := command.Execute(context.Background(), []string{"--lite", "--no-director"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--lite cannot be used with --no-director."))
+			})
+		})
+
+		Context("when the environment is new", func() {
+			It("routes the internal subnets through NAT gateways", func() {
+				err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.NATGateway).To(BeTrue())
+			})
+
+			It("keeps a NAT instance when --nat-instance is passed", func() {
+				err := command.Execute(context.Background(), []string{"--nat-instance"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.NATGateway).To(BeFalse())
+			})
+
+			It("has no NAT when it is minimal", func() {
+				err := command.Execute(context.Background(), []string{"--minimal"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.NATGateway).To(BeFalse())
+			})
+		})
+
+		Context("when --nat-gateway is passed", func() {
+			It("moves an existing environment onto NAT gateways", func() {
+				err := command.Execute(context.Background(), []string{"--nat-gateway"}, storage.State{IAAS: "aws", EnvID: "some-env"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.NATGateway).To(BeTrue())
+			})
+
+			It("leaves the NAT instance of an existing environment without it", func() {
+				err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "aws", EnvID: "some-env"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.NATGateway).To(BeFalse())
+			})
+
+			It("cannot be used with --nat-instance", func() {
+				err := command.Execute(context.Background(), []string{"--nat-gateway", "--nat-instance"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--nat-gateway cannot be used with --nat-instance."))
+			})
+
+			It("cannot be used for a minimal environment", func() {
+				err := command.Execute(context.Background(), []string{"--nat-gateway"}, storage.State{IAAS: "aws", AWS: storage.AWS{Minimal: true}})
+				Expect(err).To(MatchError("--nat-gateway cannot be used for a minimal environment, which has no NAT."))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--nat-gateway"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -nat-gateway"))
+			})
+		})
+
+		Context("when --cheap is passed", func() {
+			It("records the profile with a NAT instance and a burstable director", func() {
+				err := command.Execute(context.Background(), []string{"--cheap"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.Cheap).To(BeTrue())
+				Expect(envIDManager.SyncCall.Receives.State.AWS.NATGateway).To(BeFalse())
+				Expect(envIDManager.SyncCall.Receives.State.DirectorVM).To(Equal(&storage.DirectorVM{InstanceType: "t2.large"}))
+			})
+
+			It("keeps the director instance type that is passed", func() {
+				err := command.Execute(context.Background(), []string{"--cheap", "--director-instance-type", "t2.xlarge", "--director-disk-size", "50"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorVM).To(Equal(&storage.DirectorVM{InstanceType: "t2.xlarge", DiskSize: 50}))
+			})
+
+			It("records the spot bid price", func() {
+				err := command.Execute(context.Background(), []string{"--cheap", "--spot-bid-price", "0.05"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.SpotBidPrice).To(Equal(0.05))
+			})
+
+			It("returns an error for an invalid spot bid price", func() {
+				err := command.Execute(context.Background(), []string{"--cheap", "--spot-bid-price", "cheap"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--spot-bid-price "cheap" is not a price in USD per hour.`))
+			})
+
+			It("returns an error for a spot bid price without --cheap", func() {
+				err := command.Execute(context.Background(), []string{"--spot-bid-price", "0.05"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--spot-bid-price needs --cheap."))
+			})
+
+			It("returns an error for an existing environment", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+
+				err := command.Execute(context.Background(), []string{"--cheap"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--cheap cannot be used for an existing environment. Run bbl destroy first."))
+			})
+
+			It("keeps the profile of an existing cheap environment", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+
+				err := command.Execute(context.Background(), []string{"--cheap"}, storage.State{IAAS: "aws", AWS: storage.AWS{Cheap: true}})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("cannot be used with --nat-gateway", func() {
+				err := command.Execute(context.Background(), []string{"--cheap", "--nat-gateway"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--nat-gateway cannot be used with --cheap, which routes through a small NAT instance."))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--cheap"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -cheap"))
+			})
+		})
+
+		Context("when --dual-stack is passed", func() {
+			It("records it in the state", func() {
+				err := command.Execute(context.Background(), []string{"--dual-stack"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.DualStack).To(BeTrue())
+			})
+
+			It("cannot be used with --existing-vpc-id", func() {
+				err := command.Execute(context.Background(), []string{"--dual-stack", "--existing-vpc-id", "vpc-12345678"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--dual-stack cannot be used with --existing-vpc-id, since bbl does not own the VPC."))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--dual-stack"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -dual-stack"))
+			})
+		})
+
+		Context("when --nat-instance is passed for an environment with NAT gateways", func() {
+			It("returns an error", func() {
+				err := command.Execute(context.Background(), []string{"--nat-instance"}, storage.State{IAAS: "aws", EnvID: "some-env", AWS: storage.AWS{NATGateway: true}})
+				Expect(err).To(MatchError("The environment uses NAT gateways already, which bbl does not replace with a NAT instance."))
+			})
+		})
+
+		Context("when a load balancer is requested for a bosh-lite environment", func() {
+			It("returns an error", func() {
+				err := command.Execute(context.Background(), []string{"--lb-type", "concourse"}, storage.State{IAAS: "aws", AWS: storage.AWS{Lite: true}})
+				Expect(err).To(MatchError("--lb-type does not apply to bosh-lite environments, which have no load balancers."))
+				Expect(lbArgsHandler.GetLBStateCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --trusted-ca-certs is passed", func() {
+			It("records the certificates in the state", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte(testhelpers.BBL_CHAIN)
+
+				err := command.Execute(context.Background(), []string{"--trusted-ca-certs", "/path/to/ca.pem"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fileIO.ReadFileCall.Receives.Filename).To(Equal("/path/to/ca.pem"))
+				Expect(envIDManager.SyncCall.Receives.State.TrustedCACerts).To(Equal(testhelpers.BBL_CHAIN))
+			})
+
+			It("returns an error when the file cannot be read", func() {
+				fileIO.ReadFileCall.Returns.Error = errors.New("no such file")
+
+				err := command.Execute(context.Background(), []string{"--trusted-ca-certs", "/path/to/ca.pem"}, state)
+				Expect(err).To(MatchError("Read trusted CA certificates: no such file"))
+			})
+
+			It("returns an error when the file has no certificates", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("not a certificate")
+
+				err := command.Execute(context.Background(), []string{"--trusted-ca-certs", "/path/to/ca.pem"}, state)
+				Expect(err).To(MatchError("/path/to/ca.pem does not contain any PEM encoded certificates."))
+			})
+		})
+
+		Context("when --ops-file and --vars-file are passed", func() {
+			It("records the ops files in the state in order", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("- type: replace\n  path: /instance_groups/name=bosh/properties/director/workers?\n  value: ((workers))\n")
+
+				err := command.Execute(context.Background(), []string{"--ops-file", "/path/to/workers.yml", "--ops-file", "/path/to/more-workers.yml"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				opsFiles := envIDManager.SyncCall.Receives.State.DirectorOpsFiles
+				Expect(opsFiles).To(HaveLen(2))
+				Expect(opsFiles[0].Name).To(Equal("workers.yml"))
+				Expect(opsFiles[1].Name).To(Equal("more-workers.yml"))
+				Expect(opsFiles[1].Contents).To(ContainSubstring("value: ((workers))"))
+			})
+
+			It("records the vars files in the state", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("workers: 8\n")
+
+				err := command.Execute(context.Background(), []string{"--vars-file", "/path/to/workers-vars.yml"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorVarsFiles).To(Equal([]storage.DirectorFile{
+					{Name: "workers-vars.yml", Contents: "workers: 8\n"},
+				}))
+			})
+
+			It("keeps the files of the state when they are not passed", func() {
+				state.DirectorOpsFiles = []storage.DirectorFile{{Name: "workers.yml", Contents: "[]"}}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorOpsFiles).To(Equal(state.DirectorOpsFiles))
+			})
+
+			It("returns an error for an operation that go-patch does not apply", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("- type: append\n  path: /releases\n")
+
+				err := command.Execute(context.Background(), []string{"--ops-file", "/path/to/workers.yml"}, state)
+				Expect(err).To(MatchError(`--ops-file /path/to/workers.yml has an operation of type "append", which is not replace or remove.`))
+			})
+
+			It("returns an error for a relative path", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("- type: remove\n  path: releases\n")
+
+				err := command.Execute(context.Background(), []string{"--ops-file", "/path/to/workers.yml"}, state)
+				Expect(err).To(MatchError(`--ops-file /path/to/workers.yml has an operation with the path "releases", which does not start with /.`))
+			})
+
+			It("returns an error for a vars file that is not a map", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte("- workers\n")
+
+				err := command.Execute(context.Background(), []string{"--vars-file", "/path/to/workers-vars.yml"}, state)
+				Expect(err).To(MatchError(ContainSubstring("--vars-file /path/to/workers-vars.yml is not a map of variables")))
+			})
+
+			It("returns an error when a file cannot be read", func() {
+				fileIO.ReadFileCall.Returns.Error = errors.New("no such file")
+
+				err := command.Execute(context.Background(), []string{"--ops-file", "/path/to/workers.yml"}, state)
+				Expect(err).To(MatchError("Read ops file: no such file"))
+			})
+		})
+
+		Context("when --vpc-cidr is passed", func() {
+			It("records the network of the block in the state", func() {
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "192.168.1.0/20"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.VPCCIDR).To(Equal("192.168.0.0/20"))
+			})
+
+			It("returns an error when the block is not an IPv4 CIDR block", func() {
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "fd00::/16"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--vpc-cidr "fd00::/16" is not an IPv4 CIDR block.`))
+			})
+
+			It("returns an error when the block is too small or too large for the subnets", func() {
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "10.0.0.0/24"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--vpc-cidr "10.0.0.0/24" must be between /16 and /20.`))
+
+				err = command.Execute(context.Background(), []string{"--vpc-cidr", "10.0.0.0/8"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--vpc-cidr "10.0.0.0/8" must be between /16 and /20.`))
+			})
+
+			It("returns an error when it would change the block of an existing vpc", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "192.168.0.0/16"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("The VPC of this environment uses 10.0.0.0/16, which cannot be changed without recreating it."))
+			})
+
+			It("accepts the block an existing vpc already uses", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "192.168.0.0/16"}, storage.State{
+					IAAS: "aws",
+					AWS:  storage.AWS{VPCCIDR: "192.168.0.0/16"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error when it cannot check for an existing vpc", func() {
+				terraformManager.IsPavedCall.Returns.Error = errors.New("permission denied")
+
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "192.168.0.0/16"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("Check for existing infrastructure: permission denied"))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "10.0.0.0/16"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -vpc-cidr"))
+			})
+		})
+
+		Context("when --existing-vpc-id is passed", func() {
+			It("records it in the state", func() {
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d", "--vpc-cidr", "10.0.16.0/20"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.ExistingVPCID).To(Equal("vpc-0a1b2c3d"))
+				Expect(envIDManager.SyncCall.Receives.State.AWS.VPCCIDR).To(Equal("10.0.16.0/20"))
+			})
+
+			It("returns an error when it is not a vpc id", func() {
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "subnet-0a1b2c3d"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--existing-vpc-id "subnet-0a1b2c3d" is not a VPC ID.`))
+			})
+
+			It("returns an error when it would move an existing environment to another vpc", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("The VPC of an existing environment cannot be changed."))
+			})
+
+			It("accepts the vpc an existing environment already uses", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d"}, storage.State{
+					IAAS: "aws",
+					AWS:  storage.AWS{ExistingVPCID: "vpc-0a1b2c3d"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error when it cannot check for an existing environment", func() {
+				terraformManager.IsPavedCall.Returns.Error = errors.New("permission denied")
+
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("Check for existing infrastructure: permission denied"))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -existing-vpc-id"))
+			})
+		})
+
+		Context("when --director-ports is passed", func() {
+			It("records the ports in the state", func() {
+				err := command.Execute(context.Background(), []string{"--director-ports", "blobstore=25251, nats=4223,registry=25778,mbus=6869"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorPorts).To(Equal(&storage.DirectorPorts{
+					Blobstore: 25251,
+					NATS:      4223,
+					Registry:  25778,
+					Mbus:      6869,
+				}))
+			})
+
+			It("returns an error for an unknown service", func() {
+				err := command.Execute(context.Background(), []string{"--director-ports", "postgres=5433"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--director-ports "postgres" is not one of blobstore, nats, registry or mbus.`))
+			})
+
+			It("returns an error for an invalid port", func() {
+				err := command.Execute(context.Background(), []string{"--director-ports", "nats=70000"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--director-ports "70000" is not a valid port.`))
+			})
+
+			It("returns an error for a malformed pair", func() {
+				err := command.Execute(context.Background(), []string{"--director-ports", "nats"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--director-ports "nats" is not a service=port pair.`))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--director-ports", "nats=4223"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -director-ports"))
+			})
+		})
+
+		Context("when --director-instance-type and --director-disk-size are passed", func() {
+			It("records the size of the director in the state", func() {
+				err := command.Execute(context.Background(), []string{"--director-instance-type", "t2.medium", "--director-disk-size", "200"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorVM).To(Equal(&storage.DirectorVM{
+					InstanceType: "t2.medium",
+					DiskSize:     200,
+				}))
+			})
+
+			It("keeps the instance type of the plan when only the disk is resized", func() {
+				state := storage.State{IAAS: "aws", DirectorVM: &storage.DirectorVM{InstanceType: "m4.xlarge", DiskSize: 100}}
+
+				err := command.Execute(context.Background(), []string{"--director-disk-size", "200"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.DirectorVM).To(Equal(&storage.DirectorVM{
+					InstanceType: "m4.xlarge",
+					DiskSize:     200,
+				}))
+			})
+
+			It("returns an error for an invalid instance type", func() {
+				err := command.Execute(context.Background(), []string{"--director-instance-type", "xlarge"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--director-instance-type "xlarge" is not an EC2 instance type.`))
+			})
+
+			It("returns an error for an invalid disk size", func() {
+				err := command.Execute(context.Background(), []string{"--director-disk-size", "200GB"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--director-disk-size "200GB" is not a size in GB.`))
+			})
+
+			It("resizes the disk outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--director-disk-size", "200"}, storage.State{IAAS: "gcp"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.DirectorVM).To(Equal(&storage.DirectorVM{DiskSize: 200}))
+			})
+
+			It("does not support the instance type outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--director-instance-type", "t2.medium"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -director-instance-type"))
+			})
+		})
+
+		Context("when --syslog-address is passed", func() {
+			It("records the syslog forwarding with the default port and transport", func() {
+				err := command.Execute(context.Background(), []string{"--syslog-address", "logs.example.com"}, storage.State{IAAS: "gcp"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorSyslog).To(Equal(&storage.DirectorSyslog{
+					Address:   "logs.example.com",
+					Port:      514,
+					Transport: "tcp",
+				}))
+			})
+
+			It("records the port and transport", func() {
+				err := command.Execute(context.Background(), []string{"--syslog-address", "10.0.0.5", "--syslog-port", "6514", "--syslog-transport", "relp"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.DirectorSyslog).To(Equal(&storage.DirectorSyslog{
+					Address:   "10.0.0.5",
+					Port:      6514,
+					Transport: "relp",
+				}))
+			})
+
+			It("changes only the port of a planned forwarding", func() {
+				state := storage.State{IAAS: "aws", DirectorSyslog: &storage.DirectorSyslog{Address: "logs.example.com", Port: 514, Transport: "udp"}}
+
+				err := command.Execute(context.Background(), []string{"--syslog-port", "1514"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.DirectorSyslog).To(Equal(&storage.DirectorSyslog{
+					Address:   "logs.example.com",
+					Port:      1514,
+					Transport: "udp",
+				}))
+			})
+
+			It("returns an error for a port without an address", func() {
+				err := command.Execute(context.Background(), []string{"--syslog-port", "514"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--syslog-port and --syslog-transport need a --syslog-address."))
+			})
+
+			It("returns an error for an invalid address", func() {
+				err := command.Execute(context.Background(), []string{"--syslog-address", "logs example"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--syslog-address "logs example" is not a host name or IP address.`))
+			})
+
+			It("returns an error for an invalid port", func() {
+				err := command.Execute(context.Background(), []string{"--syslog-address", "logs.example.com", "--syslog-port", "70000"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--syslog-port "70000" is not a valid port.`))
+			})
+
+			It("returns an error for an unknown transport", func() {
+				err := command.Execute(context.Background(), []string{"--syslog-address", "logs.example.com", "--syslog-transport", "tls"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--syslog-transport "tls" is not one of tcp, udp or relp.`))
+			})
+		})
+
+		Context("when --s3-blobstore is passed", func() {
+			It("records the s3 blobstore in the state", func() {
+				err := command.Execute(context.Background(), []string{"--s3-blobstore"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.S3Blobstore).To(BeTrue())
+				Expect(envIDManager.SyncCall.Receives.State.AWS.S3BlobstoreBucket).To(BeEmpty())
+			})
+
+			It("records the bucket of the user", func() {
+				err := command.Execute(context.Background(), []string{"--s3-blobstore-bucket", "some-bucket"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.S3Blobstore).To(BeTrue())
+				Expect(envIDManager.SyncCall.Receives.State.AWS.S3BlobstoreBucket).To(Equal("some-bucket"))
+			})
+
+			It("returns an error for a bucket name that S3 does not accept", func() {
+				err := command.Execute(context.Background(), []string{"--s3-blobstore-bucket", "Some_Bucket"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--s3-blobstore-bucket "Some_Bucket" is not an S3 bucket name.`))
+			})
+
+			It("returns an error with a blobstore port of the director", func() {
+				err := command.Execute(context.Background(), []string{"--s3-blobstore", "--director-ports", "blobstore=25251"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("--director-ports blobstore cannot be used with --s3-blobstore, since the director then has no blobstore of its own."))
+			})
+
+			It("returns an error when the blobstore of an existing director changes", func() {
+				state := storage.State{IAAS: "aws", BOSH: storage.BOSH{DirectorName: "some-director"}}
+
+				err := command.Execute(context.Background(), []string{"--s3-blobstore"}, state)
+				Expect(err).To(MatchError("The blobstore of an existing director cannot be changed."))
+
+				state.AWS = storage.AWS{S3Blobstore: true, S3BlobstoreBucket: "some-bucket"}
+				err = command.Execute(context.Background(), []string{"--s3-blobstore"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(context.Background(), []string{"--s3-blobstore-bucket", "other-bucket"}, state)
+				Expect(err).To(MatchError("The blobstore of an existing director cannot be changed."))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--s3-blobstore"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -s3-blobstore"))
+			})
+		})
+
+		Context("when --subnet-sizes and --reserved-cidrs are passed", func() {
+			It("records the subnet plan in the state", func() {
+				err := command.Execute(context.Background(), []string{"--subnet-sizes", "us-east-1a=20, us-east-1b=/22", "--reserved-cidrs", "10.0.128.0/17,10.0.96.1/20"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.SubnetSizes).To(Equal(map[string]int{"us-east-1a": 20, "us-east-1b": 22}))
+				Expect(envIDManager.SyncCall.Receives.State.AWS.ReservedCIDRs).To(Equal([]string{"10.0.128.0/17", "10.0.96.0/20"}))
+			})
+
+			It("returns an error for a malformed subnet size", func() {
+				err := command.Execute(context.Background(), []string{"--subnet-sizes", "us-east-1a"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--subnet-sizes "us-east-1a" is not an az=prefix-length pair.`))
+
+				err = command.Execute(context.Background(), []string{"--subnet-sizes", "us-east-1a=30"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--subnet-sizes "30" must be a prefix length between 16 and 28.`))
+			})
+
+			It("returns an error for a reserved block that is not a CIDR block", func() {
+				err := command.Execute(context.Background(), []string{"--reserved-cidrs", "10.0.128.0"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--reserved-cidrs "10.0.128.0" is not an IPv4 CIDR block.`))
+			})
+
+			It("returns an error when the subnets of an existing environment change", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+				state := storage.State{IAAS: "aws", AWS: storage.AWS{ReservedCIDRs: []string{"10.0.128.0/17"}}}
+
+				err := command.Execute(context.Background(), []string{"--reserved-cidrs", "10.0.128.0/17"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = command.Execute(context.Background(), []string{"--reserved-cidrs", "10.0.64.0/18"}, state)
+				Expect(err).To(MatchError("The subnets of an existing environment cannot be changed."))
+			})
+
+			It("changes the subnets of an environment that terraform has not created yet", func() {
+				state := storage.State{IAAS: "aws", AWS: storage.AWS{ReservedCIDRs: []string{"10.0.128.0/17"}}}
+
+				err := command.Execute(context.Background(), []string{"--reserved-cidrs", "10.0.64.0/18"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.AWS.ReservedCIDRs).To(Equal([]string{"10.0.64.0/18"}))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--subnet-sizes", "z1=20"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -subnet-sizes"))
+			})
+		})
+
+		Context("when --director-allowed-cidrs and --lb-allowed-cidrs are passed", func() {
+			It("records the blocks in the state", func() {
+				err := command.Execute(context.Background(), []string{"--director-allowed-cidrs", "198.51.100.7/24, 192.0.2.0/24", "--lb-allowed-cidrs", "203.0.113.0/24"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.DirectorAllowedCIDRs).To(Equal([]string{"198.51.100.0/24", "192.0.2.0/24"}))
+				Expect(envIDManager.SyncCall.Receives.State.AWS.LBAllowedCIDRs).To(Equal([]string{"203.0.113.0/24"}))
+			})
+
+			It("keeps the blocks of the state otherwise", func() {
+				state := storage.State{IAAS: "aws", AWS: storage.AWS{DirectorAllowedCIDRs: []string{"198.51.100.0/24"}}}
+
+				err := command.Execute(context.Background(), []string{"--lb-allowed-cidrs", "203.0.113.0/24"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.AWS.DirectorAllowedCIDRs).To(Equal([]string{"198.51.100.0/24"}))
+			})
+
+			It("returns an error for a block that is not a CIDR block", func() {
+				err := command.Execute(context.Background(), []string{"--director-allowed-cidrs", "office"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`--director-allowed-cidrs "office" is not an IPv4 CIDR block.`))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--lb-allowed-cidrs", "203.0.113.0/24"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -lb-allowed-cidrs"))
+			})
+		})
+
+		Context("when --tags is passed", func() {
+			It("merges the tags into the annotations in the state", func() {
+				state := storage.State{IAAS: "aws", Annotations: map[string]string{"owner": "some-team", "team": "some-team"}}
+
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center=1234", "--tags", "owner=platform-team", "--tags", "team="}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.Annotations).To(Equal(map[string]string{
+					"cost-center": "1234",
+					"owner":       "platform-team",
+				}))
+			})
+
+			It("returns an error for a tag that is not key=value", func() {
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError(`Annotation "cost-center" is not key=value.`))
+			})
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center=1234"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -tags"))
+			})
+		})
+
+		Context("when --lb-sni is passed", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				lbArgsHandler.GetLBStateCall.Returns.LB = storage.LB{Type: "cf", CertARN: "some-arn"}
+				state = storage.State{
+					IAAS: "aws",
+					AWS: storage.AWS{
+						Certificates: []storage.ServerCertificate{{Name: "apps"}, {Name: "api"}},
+						SNIDomains: []storage.SNIDomain{
+							{Domain: "apps.example.com", Certificate: "apps"},
+							{Domain: "old.example.com", Certificate: "apps"},
+						},
+					},
+				}
+			})
+
+			It("merges the SNI domains into the state", func() {
+				err := command.Execute(context.Background(), []string{"--lb-type", "cf", "--lb-sni", "API.example.com=api", "--lb-sni", "old.example.com=", "--lb-sni", "apps.example.com=api"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.SNIDomains).To(Equal([]storage.SNIDomain{
+					{Domain: "apps.example.com", Certificate: "api"},
+					{Domain: "api.example.com", Certificate: "api"},
+				}))
+			})
+
+			It("keeps the SNI domains when none are passed", func() {
+				err := command.Execute(context.Background(), []string{"--lb-type", "cf"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.SNIDomains).To(Equal(state.AWS.SNIDomains))
+			})
+
+			It("removes the last SNI domains", func() {
+				err := command.Execute(context.Background(), []string{"--lb-sni", "apps.example.com=", "--lb-sni", "old.example.com="}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.SNIDomains).To(BeNil())
+			})
+
+			DescribeTable("returns an error for an invalid SNI domain",
+				func(args []string, message string) {
+					err := command.Execute(context.Background(), args, state)
+					Expect(err).To(MatchError(message))
+					Expect(envIDManager.SyncCall.CallCount).To(Equal(0))
+				},
+				Entry("without a certificate", []string{"--lb-type", "cf", "--lb-sni", "api.example.com"}, `--lb-sni "api.example.com" is not domain=certificate, or domain= to remove the domain.`),
+				Entry("that is not a domain", []string{"--lb-type", "cf", "--lb-sni", "api/example=api"}, `--lb-sni "api/example" is not a domain name.`),
+				Entry("with an unknown certificate", []string{"--lb-type", "cf", "--lb-sni", "api.example.com=www"}, "There is no certificate named www. Create it with bbl create-certificate."),
+				Entry("without a cf load balancer", []string{"--lb-sni", "api.example.com=api"}, "--lb-sni can only add domains to a cf load balancer. Pass it with --lb-type cf."),
+			)
+
+			It("is not supported outside of aws", func() {
+				err := command.Execute(context.Background(), []string{"--lb-sni", "api.example.com=api"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("flag provided but not defined: -lb-sni"))
+			})
+		})
+
+		Context("when the pinned artifacts are overridden", func() {
+			It("records the overrides in the state", func() {
+				err := command.Execute(context.Background(), []string{
+					"--bosh-release-url", "https://example.com/bosh.tgz",
+					"--bosh-release-sha1", "3850c68124bf5bce3cfb1433cce52e2d67741d94",
+					"--stemcell-url", "https://example.com/stemcell.tgz",
+					"--stemcell-sha1", "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				}, storage.State{IAAS: "gcp"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.ArtifactOverrides).To(Equal(&storage.ArtifactOverrides{
+					BOSHReleaseURL:  "https://example.com/bosh.tgz",
+					BOSHReleaseSHA1: "3850c68124bf5bce3cfb1433cce52e2d67741d94",
+					StemcellURL:     "https://example.com/stemcell.tgz",
+					StemcellSHA1:    "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				}))
+			})
+
+			It("keeps the overrides of an earlier plan", func() {
+				err := command.Execute(context.Background(), []string{
+					"--stemcell-url", "https://example.com/newer-stemcell.tgz",
+					"--stemcell-sha1", "3850c68124bf5bce3cfb1433cce52e2d67741d94",
+				}, storage.State{IAAS: "gcp", ArtifactOverrides: &storage.ArtifactOverrides{
+					CPIReleaseURL:  "https://example.com/cpi.tgz",
+					CPIReleaseSHA1: "some-sha1",
+					StemcellURL:    "https://example.com/stemcell.tgz",
+					StemcellSHA1:   "some-sha1",
+				}})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.ArtifactOverrides).To(Equal(&storage.ArtifactOverrides{
+					CPIReleaseURL:  "https://example.com/cpi.tgz",
+					CPIReleaseSHA1: "some-sha1",
+					StemcellURL:    "https://example.com/newer-stemcell.tgz",
+					StemcellSHA1:   "3850c68124bf5bce3cfb1433cce52e2d67741d94",
+				}))
+			})
+
+			It("returns an error when a url is passed without its sha1", func() {
+				err := command.Execute(context.Background(), []string{"--cpi-release-url", "https://example.com/cpi.tgz"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("--cpi-release-url and --cpi-release-sha1 must be passed together."))
+			})
+
+			It("returns an error for a url that is not a url", func() {
+				err := command.Execute(context.Background(), []string{"--stemcell-url", "stemcell.tgz", "--stemcell-sha1", "3850c68124bf5bce3cfb1433cce52e2d67741d94"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError(`--stemcell-url "stemcell.tgz" is not a URL.`))
+			})
+
+			It("returns an error for a sha1 that is not a digest", func() {
+				err := command.Execute(context.Background(), []string{"--bosh-release-url", "https://example.com/bosh.tgz", "--bosh-release-sha1", "abc"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError(`--bosh-release-sha1 "abc" is not a SHA1 digest.`))
+			})
+		})
+
+		Context("when --artifacts-dir is passed", func() {
+			It("records the absolute path of the directory in the state", func() {
+				err := command.Execute(context.Background(), []string{"--artifacts-dir", "some-artifacts"}, storage.State{IAAS: "gcp"})
+				Expect(err).NotTo(HaveOccurred())
+
+				workingDir, err := os.Getwd()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(envIDManager.SyncCall.Receives.State.ArtifactsDir).To(Equal(filepath.Join(workingDir, "some-artifacts")))
+			})
+
+			It("keeps the directory of an earlier plan", func() {
+				err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "gcp", ArtifactsDir: "/some/artifacts"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.ArtifactsDir).To(Equal("/some/artifacts"))
+			})
+		})
+
+		Context("when the environment has no director", func() {
+			It("keeps it director-less without the flag", func() {
+				state.NoDirector = true
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.NoDirector).To(BeTrue())
+			})
+		})
+
 		Describe("failure cases", func() {
 			It("returns an error if state store set fails", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("peach")}}
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("Save state: peach"))
 			})
 
 			It("returns an error if terraform manager init fails", func() {
 				terraformManager.InitCall.Returns.Error = errors.New("pomegranate")
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("Terraform manager init: pomegranate"))
 			})
 
 			It("returns an error if bosh manager initialize jumpbox fails", func() {
 				boshManager.InitializeJumpboxCall.Returns.Error = errors.New("tomato")
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("Bosh manager initialize jumpbox: tomato"))
 			})
 
 			It("returns an error if bosh manager initialize director fails", func() {
 				boshManager.InitializeDirectorCall.Returns.Error = errors.New("tomatoe")
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("Bosh manager initialize director: tomatoe"))
 			})
 
 			It("returns an error if cloud config initialize fails", func() {
 				cloudConfigManager.InitializeCall.Returns.Error = errors.New("potato")
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("Cloud config manager initialize: potato"))
 			})
+
+			It("runs the other initializers when one fails and returns the error of the first", func() {
+				terraformManager.InitCall.Returns.Error = errors.New("pomegranate")
+				cloudConfigManager.InitializeCall.Returns.Error = errors.New("potato")
+
+				err := command.Execute(context.Background(), []string{}, storage.State{})
+				Expect(err).To(MatchError("Terraform manager init: pomegranate"))
+
+				Expect(cloudConfigManager.InitializeCall.CallCount).To(Equal(1))
+				Expect(boshManager.InitializeJumpboxCall.CallCount).To(Equal(1))
+				Expect(boshManager.InitializeDirectorCall.CallCount).To(Equal(1))
+			})
 		})
 	})
 
@@ -175,6 +1174,22 @@ var _ = Describe("Plan", func() {
 			})
 		})
 
+		Context("when it checks a plan config instead of flags", func() {
+			It("returns an error for another name", func() {
+				err := command.Check(commands.PlanConfig{Name: "other-env"}, storage.State{Version: 999, EnvID: "some-env"})
+				Expect(err).To(MatchError("The director name cannot be changed for an existing environment. Current name is some-env."))
+				Expect(lbArgsHandler.GetLBStateCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the environment must be upgraded with an older bbl first", func() {
+			It("returns an error naming that bbl", func() {
+				err := command.CheckFastFails([]string{}, storage.State{Version: 3, BBLVersion: "3.2.1"})
+				Expect(err).To(MatchError("The environment was last changed by bbl 3.2.1. Upgrade it with bbl up from bbl 4.0.0 first. Run bbl pre-upgrade-check for the upgrade path."))
+				Expect(terraformManager.ValidateVersionCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when the version of BOSH is a dev build", func() {
 			It("does not fail", func() {
 				boshManager.VersionCall.Returns.Error = bosh.NewBOSHVersionError(errors.New("BOSH version could not be parsed"))
@@ -230,6 +1245,34 @@ var _ = Describe("Plan", func() {
 				})
 			})
 		})
+
+		Context("when --no-director is passed for an environment with a director", func() {
+			It("returns an error", func() {
+				err := command.CheckFastFails([]string{"--no-director"}, storage.State{
+					BOSH: storage.BOSH{DirectorName: "some-director"},
+				})
+				Expect(err).To(MatchError(`A BOSH director already exists for this environment. Run bbl destroy before using "--no-director".`))
+			})
+		})
+
+		Context("when --lite is passed for an environment with a director", func() {
+			It("returns an error", func() {
+				err := command.CheckFastFails([]string{"--lite"}, storage.State{
+					IAAS: "aws",
+					BOSH: storage.BOSH{DirectorName: "some-director"},
+				})
+				Expect(err).To(MatchError(`A BOSH director already exists for this environment. Run bbl destroy before using "--lite".`))
+			})
+
+			It("does not return an error when the director is a bosh-lite", func() {
+				err := command.CheckFastFails([]string{"--lite"}, storage.State{
+					IAAS: "aws",
+					AWS:  storage.AWS{Lite: true},
+					BOSH: storage.BOSH{DirectorName: "some-director"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("ParseArgs", func() {
@@ -291,6 +1334,162 @@ var _ = Describe("Plan", func() {
 
 					Expect(config.LB).To(Equal(lb))
 				})
+
+				It("requests an ACM certificate with --lb-acm-certificate", func() {
+					_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-domain", "something.io", "--lb-acm-certificate"}, storage.State{IAAS: "aws"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args).To(Equal(commands.LBArgs{
+						LBType:         "cf",
+						Domain:         "something.io",
+						ACMCertificate: true,
+					}))
+				})
+
+				It("keeps the ACM certificate when the cf load balancer is planned again without a certificate", func() {
+					state := storage.State{IAAS: "aws", LB: storage.LB{Type: "cf", Domain: "something.io", ACMCertificate: true}}
+
+					_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-domain", "something.io"}, state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args.ACMCertificate).To(BeTrue())
+
+					_, err = command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert-arn", "some-cert-arn"}, state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args.ACMCertificate).To(BeFalse())
+				})
+
+				It("passes the ACM certificate arn", func() {
+					_, err := command.ParseArgs(
+						[]string{
+							"--lb-type", "cf",
+							"--lb-cert-arn", "some-cert-arn",
+						}, storage.State{IAAS: "aws"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args).To(Equal(commands.LBArgs{
+						LBType:  "cf",
+						CertARN: "some-cert-arn",
+					}))
+				})
+
+				It("passes the certificate attached to the cf router", func() {
+					state := storage.State{IAAS: "aws"}
+					state.AWS.Certificates = []storage.ServerCertificate{{ARN: "some-iam-cert-arn", LBs: []string{"cf-router"}}}
+
+					_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-domain", "something.io"}, state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args).To(Equal(commands.LBArgs{
+						LBType:  "cf",
+						CertARN: "some-iam-cert-arn",
+						Domain:  "something.io",
+					}))
+				})
+
+				It("passes the dns role arn", func() {
+					_, err := command.ParseArgs(
+						[]string{
+							"--lb-type", "cf",
+							"--lb-domain", "something.io",
+							"--lb-dns-role-arn", "some-role-arn",
+						}, storage.State{IAAS: "aws"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args.DNSRoleARN).To(Equal("some-role-arn"))
+				})
+
+				It("passes the hosted zone and the apps domain", func() {
+					_, err := command.ParseArgs(
+						[]string{
+							"--lb-type", "cf",
+							"--lb-domain", "sys.example.com",
+							"--lb-dns-zone-id", "Z123",
+							"--lb-apps-domain", "apps.example.com",
+						}, storage.State{IAAS: "aws"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args.DNSZoneID).To(Equal("Z123"))
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args.AppsDomain).To(Equal("apps.example.com"))
+				})
+
+				It("passes the health check", func() {
+					_, err := command.ParseArgs(
+						[]string{
+							"--lb-type", "cf",
+							"--lb-health-check", "target=HTTP:8080/health,interval=10",
+						}, storage.State{IAAS: "aws"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lbArgsHandler.GetLBStateCall.Receives.Args.HealthCheck).To(Equal("target=HTTP:8080/health,interval=10"))
+				})
+
+				Context("when the load balancer is internal", func() {
+					var state storage.State
+
+					BeforeEach(func() {
+						state = storage.State{IAAS: "aws", LB: storage.LB{Type: "concourse", Internal: true}}
+					})
+
+					It("passes --lb-internal", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "concourse", "--lb-internal"}, storage.State{IAAS: "aws"})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.Internal).To(BeTrue())
+					})
+
+					It("keeps an internal load balancer internal when it is planned again", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "concourse"}, state)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.Internal).To(BeTrue())
+					})
+
+					It("makes it internet-facing with --lb-internal=false", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "concourse", "--lb-internal=false"}, state)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.Internal).To(BeFalse())
+					})
+
+					It("does not carry the scheme over to another type of load balancer", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert", "cert", "--lb-key", "key"}, state)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.Internal).To(BeFalse())
+					})
+				})
+
+				Context("when the load balancers are ELBv2", func() {
+					var state storage.State
+
+					BeforeEach(func() {
+						state = storage.State{IAAS: "aws", LB: storage.LB{Type: "cf", ELBv2: true}}
+					})
+
+					It("passes --lb-elbv2", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert", "cert", "--lb-key", "key", "--lb-elbv2"}, storage.State{IAAS: "aws"})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.ELBv2).To(BeTrue())
+					})
+
+					It("keeps the load balancers ELBv2 when they are planned again", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert", "cert", "--lb-key", "key"}, state)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.ELBv2).To(BeTrue())
+					})
+
+					It("makes them classic with --lb-elbv2=false", func() {
+						_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert", "cert", "--lb-key", "key", "--lb-elbv2=false"}, state)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(lbArgsHandler.GetLBStateCall.Receives.Args.ELBv2).To(BeFalse())
+					})
+
+					It("returns an error for an environment with SNI domains", func() {
+						lbArgsHandler.GetLBStateCall.Returns.LB = storage.LB{Type: "cf", ELBv2: true}
+						state.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "apps"}}
+
+						_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert", "cert", "--lb-key", "key"}, state)
+						Expect(err).To(MatchError("--lb-elbv2 cannot be used with the SNI domains of --lb-sni, which are served by classic load balancers."))
+					})
+
+					It("returns an error for a cheap environment in a single availability zone", func() {
+						lbArgsHandler.GetLBStateCall.Returns.LB = storage.LB{Type: "cf", ELBv2: true}
+						state.AWS.Cheap = true
+
+						_, err := command.ParseArgs([]string{"--lb-type", "cf", "--lb-cert", "cert", "--lb-key", "key"}, state)
+						Expect(err).To(MatchError("--lb-elbv2 needs two availability zones, which --cheap leaves out. Pass them with --azs."))
+					})
+				})
 			})
 
 			Context("gcp", func() {
@@ -301,6 +1500,22 @@ var _ = Describe("Plan", func() {
 						}, storage.State{IAAS: "gcp"})
 					Expect(err).To(MatchError("flag provided but not defined: -lb-chain"))
 				})
+
+				It("doesn't use --lb-cert-arn", func() {
+					_, err := command.ParseArgs(
+						[]string{
+							"--lb-cert-arn", "some-cert-arn",
+						}, storage.State{IAAS: "gcp"})
+					Expect(err).To(MatchError("flag provided but not defined: -lb-cert-arn"))
+				})
+
+				It("doesn't use --lb-health-check", func() {
+					_, err := command.ParseArgs(
+						[]string{
+							"--lb-health-check", "interval=10",
+						}, storage.State{IAAS: "gcp"})
+					Expect(err).To(MatchError("flag provided but not defined: -lb-health-check"))
+				})
 			})
 
 			Context("when the lb args are not valid", func() {
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// PreUpgradeCheck compares an environment with the running bbl, using the
+// compatibility matrix, and lists the releases that must upgrade it first.
+// It also lists the deployments whose stemcells the upgraded director cannot
+// manage. It changes nothing, and fails when this bbl cannot upgrade the
+// environment.
+type PreUpgradeCheck struct {
+	stateValidator     stateValidator
+	boshClientProvider boshClientProvider
+	version            string
+	output             OutputFormatter
+	stderr             logger
+}
+
+type preUpgradeCheckOutput struct {
+	BBL              storage.Compatibility   `json:"bbl"`
+	Environment      storage.Compatibility   `json:"environment"`
+	RunningDirector  string                  `json:"runningDirectorVersion,omitempty"`
+	RequiredUpgrades []storage.Compatibility `json:"requiredUpgrades"`
+	SkewedStemcells  []bosh.SkewedDeployment `json:"skewedStemcells"`
+	Problems         []string                `json:"problems"`
+}
+
+func NewPreUpgradeCheck(stateValidator stateValidator, boshClientProvider boshClientProvider, version string, output OutputFormatter, stderr logger) PreUpgradeCheck {
+	return PreUpgradeCheck{
+		stateValidator:     stateValidator,
+		boshClientProvider: boshClientProvider,
+		version:            version,
+		output:             output,
+		stderr:             stderr,
+	}
+}
+
+func (p PreUpgradeCheck) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	return p.stateValidator.Validate()
+}
+
+func (p PreUpgradeCheck) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	current, _ := storage.CompatibilityFor(storage.STATE_SCHEMA)
+	current.BBLVersion = p.version
+
+	artifacts, err := bosh.PinnedArtifacts(state.IAAS)
+	if err != nil {
+		return err
+	}
+	if state.ArtifactOverrides != nil {
+		artifacts = artifacts.WithOverrides(*state.ArtifactOverrides)
+	}
+	current.DirectorVersion = artifacts.BOSH.Version
+
+	environment, ok := storage.CompatibilityFor(state.Version)
+	if !ok {
+		environment = storage.Compatibility{StateSchema: state.Version}
+	}
+	if state.BBLVersion != "" {
+		environment.BBLVersion = state.BBLVersion
+	}
+
+	result := preUpgradeCheckOutput{
+		BBL:              current,
+		Environment:      environment,
+		RequiredUpgrades: storage.RequiredUpgrades(state.Version),
+		SkewedStemcells:  []bosh.SkewedDeployment{},
+		Problems:         []string{},
+	}
+
+	if state.Version > storage.STATE_SCHEMA {
+		result.Problems = append(result.Problems, fmt.Sprintf("The environment was last changed by bbl %s, which is newer than this bbl.", environment.BBLVersion))
+	}
+	for _, upgrade := range result.RequiredUpgrades {
+		result.Problems = append(result.Problems, fmt.Sprintf("The environment must be upgraded with bbl up from bbl %s first.", upgrade.BBLVersion))
+	}
+
+	if !state.NoDirector && state.BOSH.DirectorAddress != "" {
+		client, err := p.boshClientProvider.Client(state.Jumpbox, state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+		if err != nil {
+			p.stderr.Println(fmt.Sprintf("Could not connect to the director, so it is not checked: %s", err))
+		} else {
+			result.RunningDirector = p.runningDirectorVersion(client)
+			if isNewerMajor(result.RunningDirector, current.DirectorVersion) {
+				result.Problems = append(result.Problems, fmt.Sprintf("The director runs BOSH %s, which is newer than BOSH %s that this bbl deploys. bbl does not downgrade directors.", result.RunningDirector, current.DirectorVersion))
+			}
+
+			result.SkewedStemcells = p.skewedStemcells(client, current.DirectorVersion)
+			for _, deployment := range result.SkewedStemcells {
+				result.Problems = append(result.Problems, fmt.Sprintf("%s Deploy it with a newer stemcell first.", deployment))
+			}
+		}
+	}
+
+	if p.output.JSON() {
+		err = p.output.PrintJSON(result)
+		if err != nil {
+			return err
+		}
+	} else {
+		p.print(result)
+	}
+
+	if len(result.Problems) > 0 {
+		return fmt.Errorf("bbl %s cannot upgrade this environment directly.", p.version)
+	}
+	return nil
+}
+
+// runningDirectorVersion asks the director for its version. The check goes on
+// without it when the director cannot be reached.
+func (p PreUpgradeCheck) runningDirectorVersion(client bosh.Client) string {
+	info, err := client.Info()
+	if err != nil {
+		p.stderr.Println(fmt.Sprintf("Could not get the version of the director, so it is not checked: %s", err))
+		return ""
+	}
+
+	return info.Version
+}
+
+// skewedStemcells lists the deployments whose stemcells are too old for the
+// director this bbl deploys. The check goes on without them when the
+// deployments cannot be listed.
+func (p PreUpgradeCheck) skewedStemcells(client bosh.Client, directorVersion string) []bosh.SkewedDeployment {
+	skewed, err := bosh.StemcellSkew(client, directorVersion)
+	if err != nil {
+		p.stderr.Println(fmt.Sprintf("Could not list the deployments of the director, so their stemcells are not checked: %s", err))
+		return []bosh.SkewedDeployment{}
+	}
+
+	return skewed
+}
+
+func (p PreUpgradeCheck) print(result preUpgradeCheckOutput) {
+	p.output.Printf("bbl %s: state schema %d, terraform template version %d, BOSH director %s\n",
+		result.BBL.BBLVersion, result.BBL.StateSchema, result.BBL.TemplateVersion, result.BBL.DirectorVersion)
+	p.output.Printf("environment: last changed by bbl %s, state schema %d, terraform template version %d\n",
+		result.Environment.BBLVersion, result.Environment.StateSchema, result.Environment.TemplateVersion)
+	if result.RunningDirector != "" {
+		p.output.Printf("director: BOSH %s\n", result.RunningDirector)
+	}
+
+	if len(result.Problems) == 0 {
+		p.output.Printf("This bbl can upgrade the environment directly. Run bbl plan and bbl up.\n")
+		return
+	}
+
+	for _, problem := range result.Problems {
+		p.output.Printf("%s\n", problem)
+	}
+	if len(result.RequiredUpgrades) > 0 {
+		p.output.Printf("Upgrade path:\n")
+		for i, upgrade := range result.RequiredUpgrades {
+			p.output.Printf("  %d. bbl up with bbl %s\n", i+1, upgrade.BBLVersion)
+		}
+		p.output.Printf("  %d. bbl plan and bbl up with bbl %s\n", len(result.RequiredUpgrades)+1, result.BBL.BBLVersion)
+	}
+}
+
+// isNewerMajor compares the major versions of two BOSH releases, such as
+// "265.2.0 (00000000)" and "264.7.0". Versions that do not parse compare as
+// not newer.
+func isNewerMajor(version, than string) bool {
+	major := func(v string) (int, bool) {
+		n, err := strconv.Atoi(strings.SplitN(strings.TrimSpace(v), ".", 2)[0])
+		return n, err == nil
+	}
+
+	a, ok := major(version)
+	if !ok {
+		return false
+	}
+	b, ok := major(than)
+	if !ok {
+		return false
+	}
+	return a > b
+}
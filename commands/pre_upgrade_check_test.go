@@ -0,0 +1,165 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreUpgradeCheck", func() {
+	var (
+		stateValidator     *fakes.StateValidator
+		boshClientProvider *fakes.BOSHClientProvider
+		boshClient         *fakes.BOSHClient
+		logger             *fakes.Logger
+		stderr             *fakes.Logger
+		command            commands.PreUpgradeCheck
+
+		state      storage.State
+		pinnedBOSH string
+		currentBBL storage.Compatibility
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		boshClient = &fakes.BOSHClient{}
+		boshClient.InfoCall.Returns.Info = bosh.Info{Version: "264.5.0 (00000000)"}
+		boshClient.CurlCall.Returns.Status = 200
+		boshClient.CurlCall.Returns.Body = []byte(`[{"name": "cf", "stemcells": [{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3468.21"}]}]`)
+		boshClientProvider = &fakes.BOSHClientProvider{}
+		boshClientProvider.ClientCall.Returns.Client = boshClient
+		logger = &fakes.Logger{}
+		stderr = &fakes.Logger{}
+
+		command = commands.NewPreUpgradeCheck(stateValidator, boshClientProvider, "6.1.0", commands.NewOutputFormatter(logger, false), stderr)
+
+		state = storage.State{
+			IAAS:       "aws",
+			Version:    storage.STATE_SCHEMA,
+			BBLVersion: "6.0.0",
+			BOSH: storage.BOSH{
+				DirectorAddress:  "https://10.0.0.6:25555",
+				DirectorUsername: "admin",
+				DirectorPassword: "some-password",
+				DirectorSSLCA:    "some-ca",
+			},
+		}
+
+		artifacts, err := bosh.PinnedArtifacts("aws")
+		Expect(err).NotTo(HaveOccurred())
+		pinnedBOSH = artifacts.BOSH.Version
+		currentBBL, _ = storage.CompatibilityFor(storage.STATE_SCHEMA)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("no state")
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("no state"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("reports that this bbl can upgrade the environment directly", func() {
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("https://10.0.0.6:25555"))
+			Expect(boshClient.CurlCall.Receives.Path).To(Equal("/deployments"))
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				fmt.Sprintf("bbl 6.1.0: state schema %d, terraform template version %d, BOSH director %s\n", storage.STATE_SCHEMA, currentBBL.TemplateVersion, pinnedBOSH),
+				fmt.Sprintf("environment: last changed by bbl 6.0.0, state schema %d, terraform template version %d\n", storage.STATE_SCHEMA, currentBBL.TemplateVersion),
+				"director: BOSH 264.5.0 (00000000)\n",
+				"This bbl can upgrade the environment directly. Run bbl plan and bbl up.\n",
+			}))
+		})
+
+		It("lists the releases that must upgrade an older environment first", func() {
+			state.Version = 3
+			state.BBLVersion = "3.2.1"
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(MatchError("bbl 6.1.0 cannot upgrade this environment directly."))
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("environment: last changed by bbl 3.2.1, state schema 3, terraform template version 1\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("The environment must be upgraded with bbl up from bbl 4.0.0 first.\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("Upgrade path:\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("  1. bbl up with bbl 4.0.0\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("  2. bbl plan and bbl up with bbl 6.1.0\n"))
+		})
+
+		It("fails when the director is newer than the one this bbl deploys", func() {
+			boshClient.InfoCall.Returns.Info = bosh.Info{Version: "999.0.0 (00000000)"}
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(MatchError("bbl 6.1.0 cannot upgrade this environment directly."))
+			Expect(logger.PrintfCall.Messages).To(ContainElement(fmt.Sprintf("The director runs BOSH 999.0.0 (00000000), which is newer than BOSH %s that this bbl deploys. bbl does not downgrade directors.\n", pinnedBOSH)))
+		})
+
+		It("fails when a deployment uses a stemcell that the upgraded director cannot manage", func() {
+			boshClient.CurlCall.Returns.Body = []byte(`[
+				{"name": "cf", "stemcells": [{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3468.21"}]},
+				{"name": "concourse", "stemcells": [{"name": "bosh-aws-xen-hvm-ubuntu-trusty-go_agent", "version": "3363.20"}]}
+			]`)
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(MatchError("bbl 6.1.0 cannot upgrade this environment directly."))
+			Expect(logger.PrintfCall.Messages).To(ContainElement(fmt.Sprintf("The deployment concourse uses the stemcell bosh-aws-xen-hvm-ubuntu-trusty-go_agent/3363.20, which BOSH %s does not support: the director talks to agents over NATS with TLS, which stemcells older than 3421 do not support. Deploy it with a newer stemcell first.\n", pinnedBOSH)))
+			Expect(logger.PrintfCall.Messages).NotTo(ContainElement(ContainSubstring("The deployment cf")))
+		})
+
+		It("goes on without the stemcells when the deployments cannot be listed", func() {
+			boshClient.CurlCall.Returns.Status = 401
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stderr.PrintlnCall.Receives.Message).To(Equal("Could not list the deployments of the director, so their stemcells are not checked: List deployments: unexpected http response 401 Unauthorized"))
+		})
+
+		It("goes on without the director version when the director cannot be reached", func() {
+			boshClient.InfoCall.Returns.Error = errors.New("connection refused")
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stderr.PrintlnCall.Receives.Message).To(Equal("Could not get the version of the director, so it is not checked: connection refused"))
+			Expect(logger.PrintfCall.Messages).NotTo(ContainElement(HavePrefix("director:")))
+		})
+
+		It("does not ask a director that bbl does not manage", func() {
+			state.NoDirector = true
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+		})
+
+		It("prints the check as JSON with --json", func() {
+			command = commands.NewPreUpgradeCheck(stateValidator, boshClientProvider, "6.1.0", commands.NewOutputFormatter(logger, true), stderr)
+			state.Version = 3
+			state.BBLVersion = "3.2.1"
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(fmt.Sprintf(`{
+				"bbl": {"bblVersion": "6.1.0", "stateSchema": %d, "templateVersion": %d, "directorVersion": %q, "upgradeThrough": false},
+				"environment": {"bblVersion": "3.2.1", "stateSchema": 3, "templateVersion": 1, "directorVersion": "262", "upgradeThrough": false},
+				"runningDirectorVersion": "264.5.0 (00000000)",
+				"requiredUpgrades": [{"bblVersion": "4.0.0", "stateSchema": 5, "templateVersion": 1, "directorVersion": "262", "upgradeThrough": true}],
+				"skewedStemcells": [],
+				"problems": ["The environment must be upgraded with bbl up from bbl 4.0.0 first."]
+			}`, storage.STATE_SCHEMA, currentBBL.TemplateVersion, pinnedBOSH)))
+		})
+	})
+})
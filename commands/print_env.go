@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry/bosh-bootloader/fileio"
@@ -65,7 +66,7 @@ func (p PrintEnv) CheckFastFails(subcommandFlags []string, state storage.State)
 	return nil
 }
 
-func (p PrintEnv) Execute(args []string, state storage.State) error {
+func (p PrintEnv) Execute(ctx context.Context, args []string, state storage.State) error {
 	if state.NoDirector {
 		terraformOutputs, err := p.terraformManager.GetOutputs()
 		if err != nil {
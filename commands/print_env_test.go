@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -68,7 +69,7 @@ var _ = Describe("PrintEnv", func() {
 
 	Describe("Execute", func() {
 		It("prints the correct environment variables for the bosh cli", func() {
-			err := printEnv.Execute([]string{}, state)
+			err := printEnv.Execute(context.Background(), []string{}, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(allProxyGetter.GeneratePrivateKeyCall.CallCount).To(Equal(1))
@@ -98,7 +99,7 @@ var _ = Describe("PrintEnv", func() {
 			})
 
 			It("prints only the BOSH_ENVIRONMENT", func() {
-				err := printEnv.Execute([]string{}, storage.State{
+				err := printEnv.Execute(context.Background(), []string{}, storage.State{
 					NoDirector: true,
 				})
 				Expect(err).NotTo(HaveOccurred())
@@ -117,7 +118,7 @@ var _ = Describe("PrintEnv", func() {
 				})
 
 				It("returns an error", func() {
-					err := printEnv.Execute([]string{}, storage.State{NoDirector: true})
+					err := printEnv.Execute(context.Background(), []string{}, storage.State{NoDirector: true})
 					Expect(err).To(MatchError("failed to get terraform output"))
 				})
 			})
@@ -128,7 +129,7 @@ var _ = Describe("PrintEnv", func() {
 				})
 
 				It("returns an error", func() {
-					err := printEnv.Execute([]string{}, storage.State{})
+					err := printEnv.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("papaya"))
 				})
 			})
@@ -139,7 +140,7 @@ var _ = Describe("PrintEnv", func() {
 				})
 
 				It("logs a warning and prints the other information", func() {
-					err := printEnv.Execute([]string{}, state)
+					err := printEnv.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(stderrLogger.PrintlnCall.Messages).To(ContainElement("No credhub password found."))
 					Expect(logger.PrintlnCall.Messages).To(ContainElement(MatchRegexp(`export JUMPBOX_PRIVATE_KEY=`)))
@@ -152,7 +153,7 @@ var _ = Describe("PrintEnv", func() {
 				})
 
 				It("logs a warning and prints the other information", func() {
-					err := printEnv.Execute([]string{}, state)
+					err := printEnv.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(stderrLogger.PrintlnCall.Messages).To(ContainElement("No credhub server found."))
 					Expect(logger.PrintlnCall.Messages).To(ContainElement(MatchRegexp(`export JUMPBOX_PRIVATE_KEY=`)))
@@ -165,7 +166,7 @@ var _ = Describe("PrintEnv", func() {
 				})
 
 				It("logs a warning and prints the other information", func() {
-					err := printEnv.Execute([]string{}, state)
+					err := printEnv.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(stderrLogger.PrintlnCall.Messages).To(ContainElement("No credhub certs found."))
 					Expect(logger.PrintlnCall.Messages).To(ContainElement(MatchRegexp(`export JUMPBOX_PRIVATE_KEY=`)))
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -37,13 +38,13 @@ func (r Rotate) CheckFastFails(subcommandFlags []string, state storage.State) er
 	return nil
 }
 
-func (r Rotate) Execute(args []string, state storage.State) error {
+func (r Rotate) Execute(ctx context.Context, args []string, state storage.State) error {
 	err := r.sshKeyDeleter.Delete()
 	if err != nil {
 		return fmt.Errorf("delete ssh key: %s", err)
 	}
 
-	err = r.up.Execute(args, state)
+	err = r.up.Execute(ctx, args, state)
 	if err != nil {
 		return fmt.Errorf("up: %s", err)
 	}
@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// listenerPorts are the ports of the TLS listeners of the cf router load
+// balancers.
+var listenerPorts = []int64{443, 4443}
+
+// certificateLBOutputs are the terraform outputs of the names of the load
+// balancers that certificates are attached to.
+var certificateLBOutputs = map[string]string{
+	"cf-router":     "cf_router_lb_name",
+	"cf-iso-router": "cf_iso_router_lb_name",
+}
+
+type CertificateRotator interface {
+	CertificateUploader
+	SetListenerCertificate(lbName string, port int64, certificateARN string) error
+	SetLBListenerCertificate(lbName string, port int64, certificateARN string) error
+}
+
+type rotateCertificateConfig struct {
+	lb        string
+	certPath  string
+	keyPath   string
+	chainPath string
+}
+
+// RotateCertificate uploads a new certificate to IAM and switches the TLS
+// listeners of a cf load balancer over to it with the ELB API, which takes
+// effect at once, rather than with a terraform apply. The terraform template
+// is regenerated with the new certificate attached, so that the next bbl up
+// keeps it, and the previous certificate is deleted when bbl uploaded it and
+// nothing else uses it.
+type RotateCertificate struct {
+	stateValidator       stateValidator
+	certificateValidator certificateValidator
+	certificateRotator   CertificateRotator
+	terraformManager     terraformManager
+	stateStore           stateStore
+	logger               logger
+	now                  func() time.Time
+}
+
+func NewRotateCertificate(stateValidator stateValidator, certificateValidator certificateValidator, certificateRotator CertificateRotator,
+	terraformManager terraformManager, stateStore stateStore, logger logger, now func() time.Time) RotateCertificate {
+	return RotateCertificate{
+		stateValidator:       stateValidator,
+		certificateValidator: certificateValidator,
+		certificateRotator:   certificateRotator,
+		terraformManager:     terraformManager,
+		stateStore:           stateStore,
+		logger:               logger,
+		now:                  now,
+	}
+}
+
+func (r RotateCertificate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseRotateCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = r.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("rotate-certificate only rotates the certificates of the load balancers of aws environments.")
+	}
+
+	if err := checkNotLite("rotate-certificate", state); err != nil {
+		return err
+	}
+
+	if state.LB.Type != "cf" {
+		return errors.New("rotate-certificate needs a cf load balancer. Create it with bbl plan --lb-type cf and bbl up first.")
+	}
+
+	return nil
+}
+
+func (r RotateCertificate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseRotateCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	certData, err := r.certificateValidator.ReadAndValidate(config.certPath, config.keyPath, config.chainPath)
+	if err != nil {
+		return bblerrors.New(bblerrors.Validation, fmt.Errorf("Validate certificate: %s", err))
+	}
+
+	fingerprint, err := certs.Fingerprint(certData.Cert)
+	if err != nil {
+		return err //not tested
+	}
+
+	outputs, err := r.terraformManager.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("Get terraform outputs: %s", err)
+	}
+	lbName := outputs.GetString(certificateLBOutputs[config.lb])
+	if lbName == "" {
+		return fmt.Errorf("The environment has no %s load balancer. Run bbl up first.", config.lb)
+	}
+
+	var previous *storage.ServerCertificate
+	if attached := state.AWS.AttachedCertificate(config.lb); attached != nil {
+		certificate := *attached
+		previous = &certificate
+	}
+
+	// The timestamp keeps the name of each rotation apart, so that the
+	// previous certificate can be deleted after the switch.
+	name := fmt.Sprintf("%s-%s", state.EnvID, r.now().UTC().Format("20060102T150405Z"))
+	arn, err := storeCertificate(r.certificateRotator, r.stateStore, name, certData, func(arn string) storage.State {
+		state.AWS.Certificates = append(state.AWS.Certificates, storage.ServerCertificate{
+			Name:        name,
+			ARN:         arn,
+			Fingerprint: fingerprint,
+		})
+		state.AWS.AttachCertificate(name, config.lb)
+		return state
+	})
+	if err != nil {
+		return err
+	}
+
+	// The router of ELBv2 environments is an application load balancer,
+	// whose listeners are switched with the ELBv2 API.
+	setListenerCertificate := r.certificateRotator.SetListenerCertificate
+	if config.lb == "cf-router" && state.LB.ELBv2 {
+		setListenerCertificate = r.certificateRotator.SetLBListenerCertificate
+	}
+
+	for _, port := range listenerPorts {
+		err = setListenerCertificate(lbName, port, arn)
+		if err != nil {
+			return fmt.Errorf("%s. The certificate %s is attached to the %s load balancer in the state, so bbl up switches the listeners over to it.", err, name, config.lb)
+		}
+	}
+
+	err = r.terraformManager.Init(state)
+	if err != nil {
+		return fmt.Errorf("Terraform manager init: %s", err)
+	}
+
+	if previous != nil && !certificateInUse(state.AWS, previous.Name) {
+		state, err = r.deleteCertificate(state, *previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.logger.Println(fmt.Sprintf("Switched the %s load balancer to the certificate %s as %s.", config.lb, fingerprint, arn))
+	return nil
+}
+
+// deleteCertificate deletes a certificate that no load balancer uses any
+// more from IAM and the state. The listeners may take a moment to let go of
+// it, so a certificate that cannot be deleted is only reported.
+func (r RotateCertificate) deleteCertificate(state storage.State, certificate storage.ServerCertificate) (storage.State, error) {
+	iamName := certificate.ARN[strings.LastIndex(certificate.ARN, "/")+1:]
+
+	err := r.certificateRotator.DeleteServerCertificate(iamName)
+	if err != nil {
+		r.logger.Println(fmt.Sprintf("Could not delete the previous certificate %s, delete it from IAM once no load balancer uses it: %s", iamName, err))
+		return state, nil
+	}
+
+	certificates := []storage.ServerCertificate{}
+	for _, c := range state.AWS.Certificates {
+		if c.Name != certificate.Name {
+			certificates = append(certificates, c)
+		}
+	}
+	state.AWS.Certificates = certificates
+
+	err = r.stateStore.Set(state)
+	if err != nil {
+		return storage.State{}, fmt.Errorf("Save state: %s", err)
+	}
+	return state, nil
+}
+
+// certificateInUse reports whether the certificate is attached to a load
+// balancer or serves an SNI domain.
+func certificateInUse(state storage.AWS, name string) bool {
+	if certificate := state.CertificateNamed(name); certificate != nil && len(certificate.LBs) > 0 {
+		return true
+	}
+	for _, sni := range state.SNIDomains {
+		if sni.Certificate == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRotateCertificateArgs(args []string) (rotateCertificateConfig, error) {
+	var config rotateCertificateConfig
+
+	rotateFlags := flags.New("rotate-certificate")
+	rotateFlags.String(&config.lb, "lb", "cf-router")
+	rotateFlags.String(&config.certPath, "cert", "")
+	rotateFlags.String(&config.keyPath, "key", "")
+	rotateFlags.String(&config.chainPath, "chain", "")
+
+	err := rotateFlags.Parse(args)
+	if err != nil {
+		return rotateCertificateConfig{}, err
+	}
+
+	if config.certPath == "" || config.keyPath == "" {
+		return rotateCertificateConfig{}, errors.New("--cert and --key are required")
+	}
+
+	if _, ok := certificateLBOutputs[config.lb]; !ok {
+		return rotateCertificateConfig{}, fmt.Errorf("--lb %q is not a load balancer that certificates are attached to. Use one of: cf-iso-router, cf-router.", config.lb)
+	}
+
+	return config, nil
+}
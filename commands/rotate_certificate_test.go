@@ -0,0 +1,195 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RotateCertificate", func() {
+	var (
+		stateValidator       *fakes.StateValidator
+		certificateValidator *fakes.CertificateValidator
+		certificateRotator   *fakes.CertificateRotator
+		terraformManager     *fakes.TerraformManager
+		stateStore           *fakes.StateStore
+		logger               *fakes.Logger
+		command              commands.RotateCertificate
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		certificateValidator = &fakes.CertificateValidator{}
+		certificateValidator.ReadAndValidateCall.Returns.CertData = certs.CertData{
+			Cert: []byte(testhelpers.BBL_CERT),
+			Key:  []byte("some-key"),
+		}
+		certificateRotator = &fakes.CertificateRotator{}
+		certificateRotator.UploadServerCertificateCall.Returns.ARN = "arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z"
+		certificateRotator.UploadServerCertificateCall.Returns.Created = true
+		terraformManager = &fakes.TerraformManager{}
+		terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{
+			"cf_router_lb_name":     "some-env-cf-router-lb",
+			"cf_iso_router_lb_name": "some-env-cf-iso-router-lb",
+		}}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		now := func() time.Time { return time.Date(2026, 10, 16, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)) }
+		command = commands.NewRotateCertificate(stateValidator, certificateValidator, certificateRotator, terraformManager, stateStore, logger, now)
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env",
+			LB:    storage.LB{Type: "cf", CertARN: "some-acm-arn"},
+			AWS: storage.AWS{
+				Certificates: []storage.ServerCertificate{{
+					Name: "old",
+					ARN:  "arn:aws:iam::123456789012:server-certificate/some-env-old",
+					LBs:  []string{"cf-router"},
+				}},
+			},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the certificate and the key", func() {
+			err := command.CheckFastFails([]string{"--cert", "cert"}, state)
+			Expect(err).To(MatchError("--cert and --key are required"))
+		})
+
+		It("only rotates the certificates of known load balancers", func() {
+			err := command.CheckFastFails([]string{"--cert", "cert", "--key", "key", "--lb", "cf-ssh"}, state)
+			Expect(err).To(MatchError(`--lb "cf-ssh" is not a load balancer that certificates are attached to. Use one of: cf-iso-router, cf-router.`))
+		})
+
+		It("only applies to aws environments", func() {
+			state.IAAS = "gcp"
+
+			err := command.CheckFastFails([]string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("rotate-certificate only rotates the certificates of the load balancers of aws environments."))
+		})
+
+		It("needs a cf load balancer", func() {
+			state.LB = storage.LB{Type: "concourse"}
+
+			err := command.CheckFastFails([]string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).To(MatchError("rotate-certificate needs a cf load balancer. Create it with bbl plan --lb-type cf and bbl up first."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("switches the listeners to the new certificate and deletes the old one", func() {
+			err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateRotator.UploadServerCertificateCall.Receives.Name).To(Equal("some-env-20261016T120000Z"))
+			Expect(certificateRotator.SetListenerCertificateCall.Receives).To(Equal([]fakes.SetListenerCertificateReceive{
+				{LBName: "some-env-cf-router-lb", Port: 443, CertificateARN: "arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z"},
+				{LBName: "some-env-cf-router-lb", Port: 4443, CertificateARN: "arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z"},
+			}))
+
+			Expect(terraformManager.InitCall.CallCount).To(Equal(1))
+			Expect(terraformManager.InitCall.Receives.BBLState.AWS.AttachedCertificate("cf-router").Name).To(Equal("some-env-20261016T120000Z"))
+
+			Expect(certificateRotator.DeleteServerCertificateCall.Receives.Name).To(Equal("some-env-old"))
+			Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			Expect(stateStore.SetCall.Receives[1].State.AWS.Certificates).To(Equal([]storage.ServerCertificate{{
+				Name:        "some-env-20261016T120000Z",
+				ARN:         "arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z",
+				Fingerprint: "47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A",
+				LBs:         []string{"cf-router"},
+			}}))
+
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Switched the cf-router load balancer to the certificate 47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A as arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z."))
+		})
+
+		It("rotates the certificate of the isolation segment router", func() {
+			err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key", "--lb", "cf-iso-router"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateRotator.SetListenerCertificateCall.Receives[0].LBName).To(Equal("some-env-cf-iso-router-lb"))
+			Expect(certificateRotator.DeleteServerCertificateCall.CallCount).To(Equal(0))
+		})
+
+		It("switches the listeners of an ELBv2 router with the ELBv2 API", func() {
+			state.LB.ELBv2 = true
+
+			err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateRotator.SetListenerCertificateCall.CallCount).To(Equal(0))
+			Expect(certificateRotator.SetLBListenerCertificateCall.Receives).To(Equal([]fakes.SetListenerCertificateReceive{
+				{LBName: "some-env-cf-router-lb", Port: 443, CertificateARN: "arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z"},
+				{LBName: "some-env-cf-router-lb", Port: 4443, CertificateARN: "arn:aws:iam::123456789012:server-certificate/some-env-20261016T120000Z"},
+			}))
+		})
+
+		It("keeps an old certificate that an SNI domain uses", func() {
+			state.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "old"}}
+
+			err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(certificateRotator.DeleteServerCertificateCall.CallCount).To(Equal(0))
+		})
+
+		It("only reports an old certificate that cannot be deleted", func() {
+			certificateRotator.DeleteServerCertificateCall.Returns.Error = errors.New("DeleteConflict")
+
+			err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateStore.SetCall.CallCount).To(Equal(1))
+			Expect(logger.PrintlnCall.Messages).To(ContainElement("Could not delete the previous certificate some-env-old, delete it from IAM once no load balancer uses it: DeleteConflict"))
+		})
+
+		Describe("failure cases", func() {
+			It("returns an error when the environment has no load balancer yet", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{}}
+
+				err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+				Expect(err).To(MatchError("The environment has no cf-router load balancer. Run bbl up first."))
+				Expect(certificateRotator.UploadServerCertificateCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the certificate is not valid", func() {
+				certificateValidator.ReadAndValidateCall.Returns.Error = errors.New("certificate expired")
+
+				err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+				Expect(err).To(MatchError("Validate certificate: certificate expired"))
+			})
+
+			It("keeps the old certificate when a listener cannot be switched", func() {
+				certificateRotator.SetListenerCertificateCall.Returns.Error = errors.New("CertificateNotFound")
+
+				err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+				Expect(err).To(MatchError("CertificateNotFound. The certificate some-env-20261016T120000Z is attached to the cf-router load balancer in the state, so bbl up switches the listeners over to it."))
+				Expect(certificateRotator.DeleteServerCertificateCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the terraform template cannot be regenerated", func() {
+				terraformManager.InitCall.Returns.Error = errors.New("disk full")
+
+				err := command.Execute(context.Background(), []string{"--cert", "cert", "--key", "key"}, state)
+				Expect(err).To(MatchError("Terraform manager init: disk full"))
+				Expect(certificateRotator.DeleteServerCertificateCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+})
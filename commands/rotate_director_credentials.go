@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type directorCredentialsDeleter interface {
+	Delete() error
+}
+
+type RotateDirectorCredentials struct {
+	stateValidator             stateValidator
+	directorCredentialsDeleter directorCredentialsDeleter
+	up                         up
+}
+
+func NewRotateDirectorCredentials(stateValidator stateValidator, directorCredentialsDeleter directorCredentialsDeleter, up up) RotateDirectorCredentials {
+	return RotateDirectorCredentials{
+		stateValidator:             stateValidator,
+		directorCredentialsDeleter: directorCredentialsDeleter,
+		up:                         up,
+	}
+}
+
+func (r RotateDirectorCredentials) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := r.stateValidator.Validate()
+	if err != nil {
+		return fmt.Errorf("validate state: %s", err)
+	}
+
+	if state.NoDirector || state.BOSH.IsEmpty() {
+		return errors.New("rotate-director-credentials needs an environment with a director")
+	}
+
+	err = r.up.CheckFastFails(subcommandFlags, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+	return nil
+}
+
+// Execute deletes the passwords and the SSL certificate of the director from
+// its vars store, then redeploys the director, which generates new ones and
+// saves them in the state.
+func (r RotateDirectorCredentials) Execute(ctx context.Context, args []string, state storage.State) error {
+	err := r.directorCredentialsDeleter.Delete()
+	if err != nil {
+		return fmt.Errorf("delete director credentials: %s", err)
+	}
+
+	err = r.up.Execute(ctx, args, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RotateDirectorCredentials", func() {
+	var (
+		stateValidator             *fakes.StateValidator
+		directorCredentialsDeleter *fakes.DirectorCredentialsDeleter
+		up                         *fakes.Up
+		command                    commands.RotateDirectorCredentials
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		directorCredentialsDeleter = &fakes.DirectorCredentialsDeleter{}
+		up = &fakes.Up{}
+		command = commands.NewRotateDirectorCredentials(stateValidator, directorCredentialsDeleter, up)
+
+		state = storage.State{
+			EnvID: "some-env-id",
+			BOSH: storage.BOSH{
+				DirectorAddress:  "https://10.0.0.6:25555",
+				DirectorPassword: "some-password",
+			},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state and calls up.CheckFastFails", func() {
+			err := command.CheckFastFails([]string{"some-flag"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(up.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{"some-flag"}))
+			Expect(up.CheckFastFailsCall.Receives.State).To(Equal(state))
+		})
+
+		It("returns an error when the state validator fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("coconut")
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("validate state: coconut"))
+		})
+
+		It("returns an error for an environment without a director", func() {
+			state.NoDirector = true
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("rotate-director-credentials needs an environment with a director"))
+			Expect(up.CheckFastFailsCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when up.CheckFastFails fails", func() {
+			up.CheckFastFailsCall.Returns.Error = errors.New("passionfruit")
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("up: passionfruit"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("deletes the director credentials and redeploys the director with up", func() {
+			err := command.Execute(context.Background(), []string{"some-flag"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(directorCredentialsDeleter.DeleteCall.CallCount).To(Equal(1))
+			Expect(up.ExecuteCall.Receives.Args).To(Equal([]string{"some-flag"}))
+			Expect(up.ExecuteCall.Receives.State).To(Equal(state))
+		})
+
+		It("does not redeploy when the credentials cannot be deleted", func() {
+			directorCredentialsDeleter.DeleteCall.Returns.Error = errors.New("guava")
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(MatchError("delete director credentials: guava"))
+			Expect(up.ExecuteCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when up fails", func() {
+			up.ExecuteCall.Returns.Error = errors.New("fig")
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(MatchError("up: fig"))
+		})
+	})
+})
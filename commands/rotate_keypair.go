@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const boshVMsKeyPairResource = "tls_private_key.bosh_vms"
+
+type resourceTainter interface {
+	Taint(resource string) error
+}
+
+type RotateKeyPair struct {
+	stateValidator  stateValidator
+	resourceTainter resourceTainter
+	up              up
+}
+
+func NewRotateKeyPair(stateValidator stateValidator, resourceTainter resourceTainter, up up) RotateKeyPair {
+	return RotateKeyPair{
+		stateValidator:  stateValidator,
+		resourceTainter: resourceTainter,
+		up:              up,
+	}
+}
+
+func (r RotateKeyPair) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := r.stateValidator.Validate()
+	if err != nil {
+		return fmt.Errorf("validate state: %s", err)
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("rotate-keypair is only supported for aws environments")
+	}
+
+	err = r.up.CheckFastFails(subcommandFlags, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+	return nil
+}
+
+// Execute marks the bosh VMs private key as tainted so that terraform
+// generates a new key and replaces the EC2 key pair, then redeploys the
+// jumpbox and director with it. The new key is kept in the terraform state.
+func (r RotateKeyPair) Execute(ctx context.Context, args []string, state storage.State) error {
+	err := r.resourceTainter.Taint(boshVMsKeyPairResource)
+	if err != nil {
+		return fmt.Errorf("taint key pair: %s", err)
+	}
+
+	err = r.up.Execute(ctx, args, state)
+	if err != nil {
+		return fmt.Errorf("up: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RotateKeyPair", func() {
+	var (
+		stateValidator   *fakes.StateValidator
+		terraformManager *fakes.TerraformManager
+		up               *fakes.Up
+		rotateKeyPair    commands.RotateKeyPair
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		up = &fakes.Up{}
+		rotateKeyPair = commands.NewRotateKeyPair(stateValidator, terraformManager, up)
+	})
+
+	Describe("CheckFastFails", func() {
+		var state storage.State
+
+		BeforeEach(func() {
+			state = storage.State{IAAS: "aws", EnvID: "some-env-id"}
+		})
+
+		It("validates the state and calls up.CheckFastFails", func() {
+			subcommandFlags := []string{"some", "subcommand", "flags"}
+			err := rotateKeyPair.CheckFastFails(subcommandFlags, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(up.CheckFastFailsCall.CallCount).To(Equal(1))
+			Expect(up.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal(subcommandFlags))
+			Expect(up.CheckFastFailsCall.Receives.State).To(Equal(state))
+		})
+
+		Context("when the state validator returns an error", func() {
+			BeforeEach(func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("coconut")
+			})
+
+			It("returns the error", func() {
+				err := rotateKeyPair.CheckFastFails([]string{}, state)
+				Expect(err).To(MatchError("validate state: coconut"))
+			})
+		})
+
+		Context("when the iaas is not aws", func() {
+			BeforeEach(func() {
+				state.IAAS = "gcp"
+			})
+
+			It("returns an error", func() {
+				err := rotateKeyPair.CheckFastFails([]string{}, state)
+				Expect(err).To(MatchError("rotate-keypair is only supported for aws environments"))
+				Expect(up.CheckFastFailsCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when up.CheckFastFails returns an error", func() {
+			BeforeEach(func() {
+				up.CheckFastFailsCall.Returns.Error = errors.New("passionfruit")
+			})
+
+			It("wraps and returns the error", func() {
+				err := rotateKeyPair.CheckFastFails([]string{}, state)
+				Expect(err).To(MatchError("up: passionfruit"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		var (
+			state storage.State
+			args  []string
+		)
+
+		BeforeEach(func() {
+			args = []string{"some", "args"}
+			state = storage.State{IAAS: "aws", EnvID: "some-env-id"}
+		})
+
+		It("taints the bosh vms private key", func() {
+			err := rotateKeyPair.Execute(context.Background(), args, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.TaintCall.CallCount).To(Equal(1))
+			Expect(terraformManager.TaintCall.Receives.Resource).To(Equal("tls_private_key.bosh_vms"))
+		})
+
+		It("calls up with args and state", func() {
+			err := rotateKeyPair.Execute(context.Background(), args, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(up.ExecuteCall.CallCount).To(Equal(1))
+			Expect(up.ExecuteCall.Receives.Args).To(Equal(args))
+			Expect(up.ExecuteCall.Receives.State).To(Equal(state))
+		})
+
+		Context("when tainting the key pair fails", func() {
+			BeforeEach(func() {
+				terraformManager.TaintCall.Returns.Error = errors.New("guava")
+			})
+
+			It("returns the error without calling up", func() {
+				err := rotateKeyPair.Execute(context.Background(), args, state)
+				Expect(err).To(MatchError("taint key pair: guava"))
+				Expect(up.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when up returns an error", func() {
+			BeforeEach(func() {
+				up.ExecuteCall.Returns.Error = errors.New("fig")
+			})
+
+			It("returns the error from up", func() {
+				err := rotateKeyPair.Execute(context.Background(), args, state)
+				Expect(err).To(MatchError("up: fig"))
+			})
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -85,14 +86,14 @@ var _ = Describe("Rotate", func() {
 		})
 
 		It("deletes the ssh key", func() {
-			err := rotate.Execute(args, state)
+			err := rotate.Execute(context.Background(), args, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(sshKeyDeleter.DeleteCall.CallCount).To(Equal(1))
 		})
 
 		It("calls up with args and new state", func() {
-			err := rotate.Execute(args, state)
+			err := rotate.Execute(context.Background(), args, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(up.ExecuteCall.CallCount).To(Equal(1))
@@ -106,7 +107,7 @@ var _ = Describe("Rotate", func() {
 			})
 
 			It("wraps and returns the error from the sshKeyDeleter", func() {
-				err := rotate.Execute(args, state)
+				err := rotate.Execute(context.Background(), args, state)
 				Expect(err).To(MatchError("delete ssh key: guava"))
 			})
 		})
@@ -117,7 +118,7 @@ var _ = Describe("Rotate", func() {
 			})
 
 			It("returns the error from up", func() {
-				err := rotate.Execute(args, state)
+				err := rotate.Execute(context.Background(), args, state)
 				Expect(err).To(MatchError("up: fig"))
 			})
 		})
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type SSH struct {
+	stateValidator stateValidator
+	sshKeyGetter   sshKeyGetter
+	sshCLI         sshCLI
+	fs             sshFS
+}
+
+type sshCLI interface {
+	Run(args []string) error
+}
+
+type sshFS interface {
+	fileio.TempDirer
+	fileio.FileWriter
+	fileio.AllRemover
+}
+
+type sshConfig struct {
+	jumpbox  bool
+	director bool
+	cmd      string
+}
+
+func NewSSH(stateValidator stateValidator, sshKeyGetter sshKeyGetter, sshCLI sshCLI, fs sshFS) SSH {
+	return SSH{
+		stateValidator: stateValidator,
+		sshKeyGetter:   sshKeyGetter,
+		sshCLI:         sshCLI,
+		fs:             fs,
+	}
+}
+
+func (s SSH) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := s.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	config, err := parseSSHArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.director && state.NoDirector {
+		return errors.New("Error BBL does not manage this director.")
+	}
+
+	return nil
+}
+
+// Execute opens an SSH session to the jumpbox, or through it to the director,
+// with the keys from the vars stores. The keys are written to a temporary
+// directory that is removed once the session ends.
+func (s SSH) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseSSHArgs(args)
+	if err != nil {
+		return err
+	}
+
+	jumpboxHost, jumpboxPort, err := net.SplitHostPort(state.Jumpbox.URL)
+	if err != nil {
+		return fmt.Errorf("Parse jumpbox url: %s", err)
+	}
+
+	dir, err := s.fs.TempDir("", "bbl-ssh")
+	if err != nil {
+		return fmt.Errorf("Create temp dir: %s", err)
+	}
+	defer s.fs.RemoveAll(dir)
+
+	jumpboxKeyPath, err := s.writeKey(dir, "jumpbox")
+	if err != nil {
+		return err
+	}
+
+	sshArgs := []string{
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "ServerAliveInterval=300",
+	}
+
+	if config.director {
+		directorURL, err := url.Parse(state.BOSH.DirectorAddress)
+		if err != nil {
+			return fmt.Errorf("Parse director address: %s", err)
+		}
+
+		directorKeyPath, err := s.writeKey(dir, "director")
+		if err != nil {
+			return err
+		}
+
+		proxyCommand := fmt.Sprintf("ssh -o StrictHostKeyChecking=no -i %s -p %s -W %%h:%%p jumpbox@%s", jumpboxKeyPath, jumpboxPort, jumpboxHost)
+		sshArgs = append(sshArgs,
+			"-o", fmt.Sprintf("ProxyCommand=%s", proxyCommand),
+			"-i", directorKeyPath,
+			fmt.Sprintf("jumpbox@%s", directorURL.Hostname()),
+		)
+	} else {
+		sshArgs = append(sshArgs,
+			"-i", jumpboxKeyPath,
+			"-p", jumpboxPort,
+			fmt.Sprintf("jumpbox@%s", jumpboxHost),
+		)
+	}
+
+	if config.cmd != "" {
+		sshArgs = append(sshArgs, config.cmd)
+	}
+
+	return s.sshCLI.Run(sshArgs)
+}
+
+func (s SSH) writeKey(dir, deployment string) (string, error) {
+	privateKey, err := s.sshKeyGetter.Get(deployment)
+	if err != nil {
+		return "", fmt.Errorf("Get %s private key: %s", deployment, err)
+	}
+
+	if privateKey == "" {
+		return "", errors.New("Could not retrieve the ssh key, please make sure you are targeting the proper state dir.")
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s.key", deployment))
+	err = s.fs.WriteFile(path, []byte(privateKey), 0600)
+	if err != nil {
+		return "", fmt.Errorf("Write %s private key: %s", deployment, err)
+	}
+
+	return path, nil
+}
+
+func parseSSHArgs(args []string) (sshConfig, error) {
+	var config sshConfig
+
+	sshFlags := flags.New("ssh")
+	sshFlags.Bool(&config.jumpbox, "jumpbox", false)
+	sshFlags.Bool(&config.director, "director", false)
+	sshFlags.String(&config.cmd, "cmd", "")
+
+	err := sshFlags.Parse(args)
+	if err != nil {
+		return sshConfig{}, err
+	}
+
+	if config.jumpbox == config.director {
+		return sshConfig{}, errors.New("ssh takes either --jumpbox or --director")
+	}
+
+	return config, nil
+}
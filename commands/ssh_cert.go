@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type sshCertIssuer interface {
+	Issue(deployment string, publicKey []byte, ttl time.Duration) (string, error)
+}
+
+type SSHCert struct {
+	output         OutputFormatter
+	stateValidator stateValidator
+	sshCertIssuer  sshCertIssuer
+	fs             fileio.FileReader
+}
+
+type sshCertConfig struct {
+	publicKey string
+	ttl       time.Duration
+	director  bool
+}
+
+func NewSSHCert(output OutputFormatter, stateValidator stateValidator, sshCertIssuer sshCertIssuer, fs fileio.FileReader) SSHCert {
+	return SSHCert{
+		output:         output,
+		stateValidator: stateValidator,
+		sshCertIssuer:  sshCertIssuer,
+		fs:             fs,
+	}
+}
+
+func (s SSHCert) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := s.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseSSHCertArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if !state.SSHCA {
+		return errors.New("The environment does not have an SSH certificate authority. Run bbl plan --ssh-ca and bbl up to create one.")
+	}
+
+	return nil
+}
+
+// Execute signs the operator's public key with the certificate authority of
+// the jumpbox, or of the director, and prints the certificate. Saved next to
+// the private key as <key>-cert.pub, it lets ssh log in as the jumpbox user
+// until it expires.
+func (s SSHCert) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseSSHCertArgs(args)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := s.fs.ReadFile(config.publicKey)
+	if err != nil {
+		return fmt.Errorf("Read public key: %s", err)
+	}
+
+	deployment := "jumpbox"
+	if config.director {
+		deployment = "director"
+	}
+
+	certificate, err := s.sshCertIssuer.Issue(deployment, publicKey, config.ttl)
+	if err != nil {
+		return fmt.Errorf("Issue certificate: %s", err)
+	}
+
+	return s.output.PrintValue("ssh_cert", certificate)
+}
+
+func parseSSHCertArgs(args []string) (sshCertConfig, error) {
+	if len(args) == 0 || args[0] != "issue" {
+		return sshCertConfig{}, errors.New("ssh-cert takes a subcommand, for example: bbl ssh-cert issue --public-key ~/.ssh/id_rsa.pub")
+	}
+
+	var (
+		config sshCertConfig
+		ttl    string
+	)
+
+	sshCertFlags := flags.New("ssh-cert")
+	sshCertFlags.String(&config.publicKey, "public-key", "")
+	sshCertFlags.String(&ttl, "ttl", "8h")
+	sshCertFlags.Bool(&config.director, "director", false)
+
+	err := sshCertFlags.Parse(args[1:])
+	if err != nil {
+		return sshCertConfig{}, err
+	}
+
+	if config.publicKey == "" {
+		return sshCertConfig{}, errors.New("--public-key is required")
+	}
+
+	config.ttl, err = time.ParseDuration(ttl)
+	if err != nil || config.ttl <= 0 {
+		return sshCertConfig{}, fmt.Errorf("--ttl must be a positive duration, for example 8h: %q", ttl)
+	}
+
+	return config, nil
+}
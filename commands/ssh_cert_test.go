@@ -0,0 +1,118 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSHCert", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		sshCertIssuer  *fakes.SSHCertIssuer
+		fs             *afero.Afero
+		logger         *fakes.Logger
+		sshCert        commands.SSHCert
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		sshCertIssuer = &fakes.SSHCertIssuer{}
+		sshCertIssuer.IssueCall.Returns.Certificate = "ssh-rsa-cert-v01@openssh.com some-cert"
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		logger = &fakes.Logger{}
+		sshCert = commands.NewSSHCert(commands.NewOutputFormatter(logger, false), stateValidator, sshCertIssuer, fs)
+
+		state = storage.State{SSHCA: true}
+
+		Expect(fs.WriteFile("/keys/id_rsa.pub", []byte("ssh-rsa some-public-key"), 0644)).To(Succeed())
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := sshCert.CheckFastFails([]string{"issue", "--public-key", "/keys/id_rsa.pub"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the issue subcommand", func() {
+			err := sshCert.CheckFastFails([]string{"--public-key", "/keys/id_rsa.pub"}, state)
+			Expect(err).To(MatchError("ssh-cert takes a subcommand, for example: bbl ssh-cert issue --public-key ~/.ssh/id_rsa.pub"))
+		})
+
+		It("requires a public key", func() {
+			err := sshCert.CheckFastFails([]string{"issue"}, state)
+			Expect(err).To(MatchError("--public-key is required"))
+		})
+
+		It("requires a positive ttl", func() {
+			err := sshCert.CheckFastFails([]string{"issue", "--public-key", "/keys/id_rsa.pub", "--ttl", "-1h"}, state)
+			Expect(err).To(MatchError(`--ttl must be a positive duration, for example 8h: "-1h"`))
+		})
+
+		It("returns an error when the environment has no certificate authority", func() {
+			state.SSHCA = false
+			err := sshCert.CheckFastFails([]string{"issue", "--public-key", "/keys/id_rsa.pub"}, state)
+			Expect(err).To(MatchError("The environment does not have an SSH certificate authority. Run bbl plan --ssh-ca and bbl up to create one."))
+		})
+
+		Context("when the state validator returns an error", func() {
+			BeforeEach(func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("fig")
+			})
+
+			It("returns the error", func() {
+				err := sshCert.CheckFastFails([]string{"issue", "--public-key", "/keys/id_rsa.pub"}, state)
+				Expect(err).To(MatchError("fig"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		It("issues an eight hour certificate for the jumpbox and prints it", func() {
+			err := sshCert.Execute(context.Background(), []string{"issue", "--public-key", "/keys/id_rsa.pub"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sshCertIssuer.IssueCall.Receives.Deployment).To(Equal("jumpbox"))
+			Expect(string(sshCertIssuer.IssueCall.Receives.PublicKey)).To(Equal("ssh-rsa some-public-key"))
+			Expect(sshCertIssuer.IssueCall.Receives.TTL).To(Equal(8 * time.Hour))
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"ssh-rsa-cert-v01@openssh.com some-cert"}))
+		})
+
+		It("issues a certificate for the director with the given ttl", func() {
+			err := sshCert.Execute(context.Background(), []string{"issue", "--director", "--ttl", "30m", "--public-key", "/keys/id_rsa.pub"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sshCertIssuer.IssueCall.Receives.Deployment).To(Equal("director"))
+			Expect(sshCertIssuer.IssueCall.Receives.TTL).To(Equal(30 * time.Minute))
+		})
+
+		It("returns an error when the public key cannot be read", func() {
+			err := sshCert.Execute(context.Background(), []string{"issue", "--public-key", "/keys/missing.pub"}, state)
+			Expect(err).To(MatchError(ContainSubstring("Read public key: ")))
+			Expect(sshCertIssuer.IssueCall.CallCount).To(Equal(0))
+		})
+
+		Context("when the certificate cannot be issued", func() {
+			BeforeEach(func() {
+				sshCertIssuer.IssueCall.Returns.Error = errors.New("quince")
+			})
+
+			It("returns the error", func() {
+				err := sshCert.Execute(context.Background(), []string{"issue", "--public-key", "/keys/id_rsa.pub"}, state)
+				Expect(err).To(MatchError("Issue certificate: quince"))
+			})
+		})
+	})
+})
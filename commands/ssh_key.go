@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -8,7 +9,7 @@ import (
 )
 
 type SSHKey struct {
-	logger         logger
+	output         OutputFormatter
 	stateValidator stateValidator
 	sshKeyGetter   sshKeyGetter
 	Director       bool
@@ -20,17 +21,17 @@ type sshKeyGetter interface {
 
 var unmarshal = yaml.Unmarshal
 
-func NewSSHKey(logger logger, stateValidator stateValidator, sshKeyGetter sshKeyGetter) SSHKey {
+func NewSSHKey(output OutputFormatter, stateValidator stateValidator, sshKeyGetter sshKeyGetter) SSHKey {
 	return SSHKey{
-		logger:         logger,
+		output:         output,
 		stateValidator: stateValidator,
 		sshKeyGetter:   sshKeyGetter,
 	}
 }
 
-func NewDirectorSSHKey(logger logger, stateValidator stateValidator, sshKeyGetter sshKeyGetter) SSHKey {
+func NewDirectorSSHKey(output OutputFormatter, stateValidator stateValidator, sshKeyGetter sshKeyGetter) SSHKey {
 	return SSHKey{
-		logger:         logger,
+		output:         output,
 		stateValidator: stateValidator,
 		sshKeyGetter:   sshKeyGetter,
 		Director:       true,
@@ -46,7 +47,7 @@ func (s SSHKey) CheckFastFails(subcommandFlags []string, state storage.State) er
 	return nil
 }
 
-func (s SSHKey) Execute(subcommandFlags []string, state storage.State) error {
+func (s SSHKey) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	deployment := "jumpbox"
 	if s.Director {
 		deployment = "director"
@@ -61,7 +62,5 @@ func (s SSHKey) Execute(subcommandFlags []string, state storage.State) error {
 		return errors.New("Could not retrieve the ssh key, please make sure you are targeting the proper state dir.")
 	}
 
-	s.logger.Println(privateKey)
-
-	return nil
+	return s.output.PrintValue("ssh_key", privateKey)
 }
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -29,7 +30,7 @@ var _ = Describe("SSHKey", func() {
 		sshKeyGetter = &fakes.SSHKeyGetter{}
 		sshKeyGetter.GetCall.Returns.PrivateKey = "some-private-ssh-key"
 
-		sshKeyCommand = commands.NewSSHKey(logger, stateValidator, sshKeyGetter)
+		sshKeyCommand = commands.NewSSHKey(commands.NewOutputFormatter(logger, false), stateValidator, sshKeyGetter)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -47,7 +48,7 @@ var _ = Describe("SSHKey", func() {
 
 	Describe("Execute", func() {
 		It("prints the private ssh key of the jumpbox user", func() {
-			err := sshKeyCommand.Execute([]string{}, incomingState)
+			err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(sshKeyGetter.GetCall.CallCount).To(Equal(1))
@@ -55,13 +56,27 @@ var _ = Describe("SSHKey", func() {
 			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-private-ssh-key"}))
 		})
 
+		Context("when --json is passed", func() {
+			BeforeEach(func() {
+				sshKeyGetter.GetCall.Returns.PrivateKey = "some-private-ssh-key\nsecond-line"
+				sshKeyCommand = commands.NewSSHKey(commands.NewOutputFormatter(logger, true), stateValidator, sshKeyGetter)
+			})
+
+			It("prints the key as a json object", func() {
+				err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{`{"ssh_key":"some-private-ssh-key\nsecond-line"}`}))
+			})
+		})
+
 		Context("director-ssh-key", func() {
 			BeforeEach(func() {
-				sshKeyCommand = commands.NewDirectorSSHKey(logger, stateValidator, sshKeyGetter)
+				sshKeyCommand = commands.NewDirectorSSHKey(commands.NewOutputFormatter(logger, false), stateValidator, sshKeyGetter)
 			})
 
 			It("uses BOSH variables to get the SSH key", func() {
-				err := sshKeyCommand.Execute([]string{}, incomingState)
+				err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(sshKeyGetter.GetCall.CallCount).To(Equal(1))
@@ -76,7 +91,7 @@ var _ = Describe("SSHKey", func() {
 				})
 
 				It("returns an error", func() {
-					err := sshKeyCommand.Execute([]string{}, incomingState)
+					err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 					Expect(err).To(MatchError("jumpbox ssh key getter failed"))
 				})
 			})
@@ -87,7 +102,7 @@ var _ = Describe("SSHKey", func() {
 				})
 
 				It("returns an error", func() {
-					err := sshKeyCommand.Execute([]string{}, incomingState)
+					err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 					Expect(err).To(MatchError("Could not retrieve the ssh key, please make sure you are targeting the proper state dir."))
 				})
 			})
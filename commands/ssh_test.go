@@ -0,0 +1,183 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSH", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		sshKeyGetter   *fakes.SSHKeyGetter
+		sshCLI         *fakes.SSHCLI
+		fs             *afero.Afero
+		ssh            commands.SSH
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		sshKeyGetter = &fakes.SSHKeyGetter{}
+		sshKeyGetter.GetCall.Stub = func(deployment string) (string, error) {
+			return fmt.Sprintf("%s-private-key", deployment), nil
+		}
+		sshCLI = &fakes.SSHCLI{}
+		fs = &afero.Afero{Fs: afero.NewMemMapFs()}
+		ssh = commands.NewSSH(stateValidator, sshKeyGetter, sshCLI, fs)
+
+		state = storage.State{
+			Jumpbox: storage.Jumpbox{URL: "10.0.0.5:22"},
+			BOSH:    storage.BOSH{DirectorAddress: "https://10.0.0.6:25555"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := ssh.CheckFastFails([]string{"--jumpbox"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires either --jumpbox or --director", func() {
+			err := ssh.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("ssh takes either --jumpbox or --director"))
+
+			err = ssh.CheckFastFails([]string{"--jumpbox", "--director"}, state)
+			Expect(err).To(MatchError("ssh takes either --jumpbox or --director"))
+		})
+
+		It("returns an error for --director when bbl does not manage the director", func() {
+			state.NoDirector = true
+			err := ssh.CheckFastFails([]string{"--director"}, state)
+			Expect(err).To(MatchError("Error BBL does not manage this director."))
+		})
+
+		Context("when the state validator returns an error", func() {
+			BeforeEach(func() {
+				stateValidator.ValidateCall.Returns.Error = errors.New("fig")
+			})
+
+			It("returns the error", func() {
+				err := ssh.CheckFastFails([]string{"--jumpbox"}, state)
+				Expect(err).To(MatchError("fig"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		It("opens a session to the jumpbox with its key", func() {
+			err := ssh.Execute(context.Background(), []string{"--jumpbox"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := sshCLI.RunCall.Receives.Args
+			Expect(args).To(HaveLen(9))
+			Expect(args[:4]).To(Equal([]string{"-o", "StrictHostKeyChecking=no", "-o", "ServerAliveInterval=300"}))
+			Expect(args[4]).To(Equal("-i"))
+			Expect(args[5]).To(HaveSuffix("jumpbox.key"))
+			Expect(args[6:]).To(Equal([]string{"-p", "22", "jumpbox@10.0.0.5"}))
+
+			Expect(sshKeyGetter.GetCall.Receives.Deployment).To(Equal("jumpbox"))
+		})
+
+		It("opens a session to the director through the jumpbox", func() {
+			err := ssh.Execute(context.Background(), []string{"--director"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := sshCLI.RunCall.Receives.Args
+			Expect(args).To(HaveLen(9))
+			Expect(args[4]).To(Equal("-o"))
+			Expect(args[5]).To(MatchRegexp(`^ProxyCommand=ssh -o StrictHostKeyChecking=no -i \S+jumpbox.key -p 22 -W %h:%p jumpbox@10.0.0.5$`))
+			Expect(args[6]).To(Equal("-i"))
+			Expect(args[7]).To(HaveSuffix("director.key"))
+			Expect(args[8]).To(Equal("jumpbox@10.0.0.6"))
+		})
+
+		It("runs the command passed with --cmd", func() {
+			err := ssh.Execute(context.Background(), []string{"--director", "--cmd", "sudo monit summary"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := sshCLI.RunCall.Receives.Args
+			Expect(args[len(args)-1]).To(Equal("sudo monit summary"))
+		})
+
+		It("writes the keys only readable by the user and removes them afterwards", func() {
+			keys := map[string]string{}
+			sshCLI.RunCall.Stub = func(args []string) error {
+				for _, arg := range args {
+					if strings.HasSuffix(arg, ".key") {
+						info, err := fs.Stat(arg)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+						contents, err := fs.ReadFile(arg)
+						Expect(err).NotTo(HaveOccurred())
+						keys[filepath.Base(arg)] = string(contents)
+					}
+				}
+				return nil
+			}
+
+			err := ssh.Execute(context.Background(), []string{"--director"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(keys).To(Equal(map[string]string{"director.key": "director-private-key"}))
+
+			keyPath := sshCLI.RunCall.Receives.Args[7]
+			_, err = fs.Stat(keyPath)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("returns the error when ssh fails", func() {
+			sshCLI.RunCall.Returns.Error = errors.New("exit status 255")
+
+			err := ssh.Execute(context.Background(), []string{"--jumpbox"}, state)
+			Expect(err).To(MatchError("exit status 255"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the jumpbox url cannot be parsed", func() {
+				state.Jumpbox.URL = "10.0.0.5"
+				err := ssh.Execute(context.Background(), []string{"--jumpbox"}, state)
+				Expect(err).To(MatchError(ContainSubstring("Parse jumpbox url: ")))
+			})
+
+			It("returns an error when the key cannot be retrieved", func() {
+				sshKeyGetter.GetCall.Stub = func(deployment string) (string, error) {
+					return "", errors.New("lime")
+				}
+
+				err := ssh.Execute(context.Background(), []string{"--jumpbox"}, state)
+				Expect(err).To(MatchError("Get jumpbox private key: lime"))
+				Expect(sshCLI.RunCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the vars store has no key", func() {
+				sshKeyGetter.GetCall.Stub = func(deployment string) (string, error) {
+					return "", nil
+				}
+
+				err := ssh.Execute(context.Background(), []string{"--jumpbox"}, state)
+				Expect(err).To(MatchError("Could not retrieve the ssh key, please make sure you are targeting the proper state dir."))
+			})
+
+			It("returns an error when the director address cannot be parsed", func() {
+				state.BOSH.DirectorAddress = "%%"
+				err := ssh.Execute(context.Background(), []string{"--director"}, state)
+				Expect(err).To(MatchError(ContainSubstring("Parse director address: ")))
+			})
+		})
+	})
+})
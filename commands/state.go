@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// State runs the subcommands of bbl state: encrypt and decrypt of
+// StateEncryption, and list-backups and restore of StateBackups.
+type State struct {
+	encryption StateEncryption
+	backups    StateBackups
+}
+
+func NewState(encryption StateEncryption, backups StateBackups) State {
+	return State{
+		encryption: encryption,
+		backups:    backups,
+	}
+}
+
+func (s State) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	command, err := s.subcommand(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return command.CheckFastFails(subcommandFlags, state)
+}
+
+func (s State) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	command, err := s.subcommand(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return command.Execute(ctx, subcommandFlags, state)
+}
+
+func (s State) subcommand(args []string) (Command, error) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "encrypt", "decrypt":
+			return s.encryption, nil
+		case "list-backups", "restore":
+			return s.backups, nil
+		}
+	}
+
+	return nil, errors.New("state takes a subcommand: encrypt, decrypt, list-backups or restore, for example: bbl state encrypt")
+}
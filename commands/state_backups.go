@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type stateBackups interface {
+	List() ([]string, error)
+	Restore(timestamp string) error
+}
+
+// StateBackups lists the copies of the state file that bbl keeps from before
+// each command that changed it, and puts one of them back.
+type StateBackups struct {
+	stateValidator stateValidator
+	backups        stateBackups
+	logger         logger
+}
+
+type stateBackupsConfig struct {
+	restore   bool
+	timestamp string
+}
+
+func NewStateBackups(stateValidator stateValidator, backups stateBackups, logger logger) StateBackups {
+	return StateBackups{
+		stateValidator: stateValidator,
+		backups:        backups,
+		logger:         logger,
+	}
+}
+
+func (s StateBackups) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := s.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseStateBackupsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s StateBackups) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseStateBackupsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.restore {
+		err = s.backups.Restore(config.timestamp)
+		if err != nil {
+			return err
+		}
+		s.logger.Println(fmt.Sprintf("The state is restored from the backup of %s. Run bbl plan to see what bbl up would change to converge the environment to it.", config.timestamp))
+		return nil
+	}
+
+	timestamps, err := s.backups.List()
+	if err != nil {
+		return err
+	}
+
+	if len(timestamps) == 0 {
+		s.logger.Println("There are no state backups.")
+		return nil
+	}
+
+	for _, timestamp := range timestamps {
+		s.logger.Println(timestamp)
+	}
+	return nil
+}
+
+func parseStateBackupsArgs(args []string) (stateBackupsConfig, error) {
+	if len(args) == 0 || (args[0] != "list-backups" && args[0] != "restore") {
+		return stateBackupsConfig{}, errors.New("state takes a subcommand, for example: bbl state list-backups")
+	}
+
+	config := stateBackupsConfig{restore: args[0] == "restore"}
+
+	stateFlags := flags.New("state")
+	err := stateFlags.Parse(args[1:])
+	if err != nil {
+		return stateBackupsConfig{}, err
+	}
+
+	switch {
+	case config.restore && len(stateFlags.Args()) != 1:
+		return stateBackupsConfig{}, errors.New("bbl state restore takes the time of a backup, for example: bbl state restore 20260304T050607Z. Run bbl state list-backups to list them.")
+	case !config.restore && len(stateFlags.Args()) != 0:
+		return stateBackupsConfig{}, errors.New("bbl state list-backups takes no arguments.")
+	}
+
+	if config.restore {
+		config.timestamp = stateFlags.Args()[0]
+	}
+
+	return config, nil
+}
@@ -0,0 +1,115 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateBackups", func() {
+	var (
+		stateValidator *fakes.StateValidator
+		backups        *fakes.StateBackups
+		logger         *fakes.Logger
+		stateBackups   commands.StateBackups
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		backups = &fakes.StateBackups{}
+		logger = &fakes.Logger{}
+		stateBackups = commands.NewStateBackups(stateValidator, backups, logger)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := stateBackups.CheckFastFails([]string{"list-backups"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the time of the backup to restore", func() {
+			err := stateBackups.CheckFastFails([]string{"restore"}, storage.State{})
+			Expect(err).To(MatchError("bbl state restore takes the time of a backup, for example: bbl state restore 20260304T050607Z. Run bbl state list-backups to list them."))
+		})
+
+		It("takes no arguments to list the backups", func() {
+			err := stateBackups.CheckFastFails([]string{"list-backups", "20260304T050607Z"}, storage.State{})
+			Expect(err).To(MatchError("bbl state list-backups takes no arguments."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("lists the times of the backups", func() {
+			backups.ListCall.Returns.Timestamps = []string{"20260304T040607Z", "20260304T050607Z"}
+
+			err := stateBackups.Execute(context.Background(), []string{"list-backups"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"20260304T040607Z", "20260304T050607Z"}))
+		})
+
+		It("says when there are no backups", func() {
+			err := stateBackups.Execute(context.Background(), []string{"list-backups"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"There are no state backups."}))
+		})
+
+		It("restores the backup of the time", func() {
+			err := stateBackups.Execute(context.Background(), []string{"restore", "20260304T050607Z"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(backups.RestoreCall.Receives.Timestamp).To(Equal("20260304T050607Z"))
+			Expect(logger.PrintlnCall.Messages).To(ContainElement(ContainSubstring("The state is restored from the backup of 20260304T050607Z.")))
+		})
+
+		It("returns an error when the backup cannot be restored", func() {
+			backups.RestoreCall.Returns.Error = errors.New("There is no state backup from 20260304T050607Z.")
+
+			err := stateBackups.Execute(context.Background(), []string{"restore", "20260304T050607Z"}, storage.State{})
+			Expect(err).To(MatchError("There is no state backup from 20260304T050607Z."))
+		})
+	})
+})
+
+var _ = Describe("State", func() {
+	var (
+		logger  *fakes.Logger
+		backups *fakes.StateBackups
+		state   commands.State
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		backups = &fakes.StateBackups{}
+		cipher := &fakes.StateCipher{}
+		stateStore := &fakes.StateStore{}
+		state = commands.NewState(
+			commands.NewStateEncryption(&fakes.StateValidator{}, stateStore, cipher, nil, logger),
+			commands.NewStateBackups(&fakes.StateValidator{}, backups, logger),
+		)
+	})
+
+	It("runs the backup subcommands", func() {
+		err := state.Execute(context.Background(), []string{"restore", "20260304T050607Z"}, storage.State{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backups.RestoreCall.CallCount).To(Equal(1))
+	})
+
+	It("runs the encryption subcommands", func() {
+		err := state.CheckFastFails([]string{"decrypt"}, storage.State{})
+		Expect(err).To(MatchError("The state is not encrypted."))
+	})
+
+	It("requires a subcommand", func() {
+		err := state.CheckFastFails([]string{"rollback"}, storage.State{})
+		Expect(err).To(MatchError("state takes a subcommand: encrypt, decrypt, list-backups or restore, for example: bbl state encrypt"))
+	})
+})
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type stateCipher interface {
+	Enable(state storage.State, kmsKeyID string, kmsClient storage.KMSClient) (storage.State, error)
+	Disable(state storage.State) storage.State
+}
+
+// StateEncryption migrates an existing state file to and from encryption. The
+// state is decrypted when bbl loads it, so encrypting and decrypting are both a
+// matter of saving it again.
+type StateEncryption struct {
+	stateValidator stateValidator
+	stateStore     stateStore
+	cipher         stateCipher
+	newKMSClient   func(storage.AWS) storage.KMSClient
+	logger         logger
+}
+
+type stateEncryptionConfig struct {
+	encrypt  bool
+	kmsKeyID string
+}
+
+func NewStateEncryption(stateValidator stateValidator, stateStore stateStore, cipher stateCipher, newKMSClient func(storage.AWS) storage.KMSClient, logger logger) StateEncryption {
+	return StateEncryption{
+		stateValidator: stateValidator,
+		stateStore:     stateStore,
+		cipher:         cipher,
+		newKMSClient:   newKMSClient,
+		logger:         logger,
+	}
+}
+
+func (s StateEncryption) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := s.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	config, err := parseStateEncryptionArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.encrypt && state.Encryption != nil {
+		return errors.New("The state is already encrypted. Run bbl state decrypt first to encrypt it another way.")
+	}
+	if !config.encrypt && state.Encryption == nil {
+		return errors.New("The state is not encrypted.")
+	}
+	if config.kmsKeyID != "" && state.IAAS != "aws" {
+		return errors.New("--kms-key-id is only supported for aws environments")
+	}
+
+	return nil
+}
+
+func (s StateEncryption) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseStateEncryptionArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if !config.encrypt {
+		err = s.stateStore.Set(s.cipher.Disable(state))
+		if err != nil {
+			return err
+		}
+		s.logger.Println("The state is no longer encrypted.")
+		return nil
+	}
+
+	var kmsClient storage.KMSClient
+	if config.kmsKeyID != "" {
+		kmsClient = s.newKMSClient(state.AWS)
+	}
+
+	state, err = s.cipher.Enable(state, config.kmsKeyID, kmsClient)
+	if err != nil {
+		return err
+	}
+
+	err = s.stateStore.Set(state)
+	if err != nil {
+		return err
+	}
+
+	if config.kmsKeyID != "" {
+		s.logger.Println("The state is encrypted with the AWS KMS key. Commands that read it need AWS credentials that can use the key.")
+	} else {
+		s.logger.Println("The state is encrypted. Commands that read it need --state-passphrase or BBL_STATE_PASSPHRASE.")
+	}
+	s.logger.Println("The vars directory is not encrypted. It keeps the credentials of the jumpbox and the director and the terraform state in plain text.")
+	return nil
+}
+
+func parseStateEncryptionArgs(args []string) (stateEncryptionConfig, error) {
+	if len(args) == 0 || (args[0] != "encrypt" && args[0] != "decrypt") {
+		return stateEncryptionConfig{}, errors.New("state takes a subcommand, for example: bbl state encrypt")
+	}
+
+	config := stateEncryptionConfig{encrypt: args[0] == "encrypt"}
+
+	stateFlags := flags.New("state")
+	if config.encrypt {
+		stateFlags.String(&config.kmsKeyID, "kms-key-id", "")
+	}
+
+	err := stateFlags.Parse(args[1:])
+	if err != nil {
+		return stateEncryptionConfig{}, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,137 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateEncryption", func() {
+	var (
+		stateValidator  *fakes.StateValidator
+		stateStore      *fakes.StateStore
+		cipher          *fakes.StateCipher
+		kmsClient       *fakes.KMSClient
+		kmsClientCreds  storage.AWS
+		logger          *fakes.Logger
+		stateEncryption commands.StateEncryption
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		stateStore = &fakes.StateStore{}
+		cipher = &fakes.StateCipher{}
+		kmsClient = &fakes.KMSClient{}
+		logger = &fakes.Logger{}
+		stateEncryption = commands.NewStateEncryption(stateValidator, stateStore, cipher, func(creds storage.AWS) storage.KMSClient {
+			kmsClientCreds = creds
+			return kmsClient
+		}, logger)
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env-id",
+			AWS:   storage.AWS{Region: "some-region"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := stateEncryption.CheckFastFails([]string{"encrypt"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires a subcommand", func() {
+			err := stateEncryption.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("state takes a subcommand, for example: bbl state encrypt"))
+		})
+
+		It("does not encrypt a state that is encrypted", func() {
+			state.Encryption = &storage.Encryption{Method: "passphrase"}
+
+			err := stateEncryption.CheckFastFails([]string{"encrypt"}, state)
+			Expect(err).To(MatchError("The state is already encrypted. Run bbl state decrypt first to encrypt it another way."))
+		})
+
+		It("does not decrypt a state that is not encrypted", func() {
+			err := stateEncryption.CheckFastFails([]string{"decrypt"}, state)
+			Expect(err).To(MatchError("The state is not encrypted."))
+		})
+
+		It("only supports KMS keys on aws", func() {
+			state.IAAS = "gcp"
+
+			err := stateEncryption.CheckFastFails([]string{"encrypt", "--kms-key-id", "some-key-id"}, state)
+			Expect(err).To(MatchError("--kms-key-id is only supported for aws environments"))
+		})
+	})
+
+	Describe("Execute", func() {
+		Context("encrypt", func() {
+			BeforeEach(func() {
+				cipher.EnableCall.Returns.State = storage.State{
+					EnvID:      "some-env-id",
+					Encryption: &storage.Encryption{Method: "passphrase"},
+				}
+			})
+
+			It("saves the state encrypted with the passphrase", func() {
+				err := stateEncryption.Execute(context.Background(), []string{"encrypt"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cipher.EnableCall.Receives.State).To(Equal(state))
+				Expect(cipher.EnableCall.Receives.KMSKeyID).To(Equal(""))
+				Expect(cipher.EnableCall.Receives.KMSClient).To(BeNil())
+
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(cipher.EnableCall.Returns.State))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"The state is encrypted. Commands that read it need --state-passphrase or BBL_STATE_PASSPHRASE.",
+					"The vars directory is not encrypted. It keeps the credentials of the jumpbox and the director and the terraform state in plain text.",
+				}))
+			})
+
+			It("saves the state encrypted with a KMS key", func() {
+				err := stateEncryption.Execute(context.Background(), []string{"encrypt", "--kms-key-id", "some-key-id"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(kmsClientCreds).To(Equal(storage.AWS{Region: "some-region"}))
+				Expect(cipher.EnableCall.Receives.KMSKeyID).To(Equal("some-key-id"))
+				Expect(cipher.EnableCall.Receives.KMSClient).To(Equal(kmsClient))
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+			})
+
+			Context("when encryption cannot be turned on", func() {
+				It("returns the error and does not save the state", func() {
+					cipher.EnableCall.Returns.Error = errors.New("failed to enable")
+
+					err := stateEncryption.Execute(context.Background(), []string{"encrypt"}, state)
+					Expect(err).To(MatchError("failed to enable"))
+					Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				})
+			})
+		})
+
+		Context("decrypt", func() {
+			It("saves the state in plain text", func() {
+				state.Encryption = &storage.Encryption{Method: "passphrase"}
+				cipher.DisableCall.Returns.State = storage.State{EnvID: "some-env-id"}
+
+				err := stateEncryption.Execute(context.Background(), []string{"decrypt"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cipher.DisableCall.Receives.State).To(Equal(state))
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(storage.State{EnvID: "some-env-id"}))
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal("The state is no longer encrypted."))
+			})
+		})
+	})
+})
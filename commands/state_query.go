@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -23,8 +24,17 @@ const (
 	DirectorCACertPropertyName   = "director ca cert"
 )
 
+var stateQueryJSONKeys = map[string]string{
+	EnvIDPropertyName:            "env_id",
+	JumpboxAddressPropertyName:   "jumpbox_address",
+	DirectorUsernamePropertyName: "director_username",
+	DirectorPasswordPropertyName: "director_password",
+	DirectorAddressPropertyName:  "director_address",
+	DirectorCACertPropertyName:   "director_ca_cert",
+}
+
 type StateQuery struct {
-	logger           logger
+	output           OutputFormatter
 	stateValidator   stateValidator
 	terraformManager terraformManager
 	propertyName     string
@@ -32,9 +42,9 @@ type StateQuery struct {
 
 type getPropertyFunc func(storage.State) string
 
-func NewStateQuery(logger logger, stateValidator stateValidator, terraformManager terraformManager, propertyName string) StateQuery {
+func NewStateQuery(output OutputFormatter, stateValidator stateValidator, terraformManager terraformManager, propertyName string) StateQuery {
 	return StateQuery{
-		logger:           logger,
+		output:           output,
 		stateValidator:   stateValidator,
 		terraformManager: terraformManager,
 		propertyName:     propertyName,
@@ -54,7 +64,7 @@ func (s StateQuery) CheckFastFails(subcommandFlags []string, state storage.State
 	return nil
 }
 
-func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error {
+func (s StateQuery) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	var (
 		propertyValue string
 		err           error
@@ -84,8 +94,7 @@ func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error
 		return fmt.Errorf("Could not retrieve %s, please make sure you are targeting the proper state dir.", s.propertyName)
 	}
 
-	s.logger.Println(propertyValue)
-	return nil
+	return s.output.PrintValue(stateQueryJSONKeys[s.propertyName], propertyValue)
 }
 
 func (s StateQuery) getDirectorAddress(state storage.State) (string, error) {
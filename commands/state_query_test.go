@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -35,7 +36,7 @@ var _ = Describe("StateQuery", func() {
 			})
 
 			It("returns an error", func() {
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, "")
+				command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, "")
 
 				err := command.CheckFastFails([]string{}, storage.State{})
 				Expect(err).To(MatchError("state validator failed"))
@@ -54,7 +55,7 @@ var _ = Describe("StateQuery", func() {
 
 			DescribeTable("prints out the director information",
 				func(propertyName string) {
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, propertyName)
+					command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, propertyName)
 
 					err := command.CheckFastFails([]string{}, state)
 					Expect(err).To(MatchError("Error BBL does not manage this director."))
@@ -75,9 +76,9 @@ var _ = Describe("StateQuery", func() {
 			})
 
 			It("prints out the jumpbox information", func() {
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, "jumpbox address")
+				command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, "jumpbox address")
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -101,9 +102,9 @@ var _ = Describe("StateQuery", func() {
 
 			DescribeTable("prints out the director information",
 				func(propertyName, expectedOutput string) {
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, propertyName)
+					command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, propertyName)
 
-					err := command.Execute([]string{}, state)
+					err := command.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal(expectedOutput))
@@ -126,9 +127,9 @@ var _ = Describe("StateQuery", func() {
 			})
 
 			It("prints the env id", func() {
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, "environment id")
+				command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, "environment id")
 
-				err := command.Execute([]string{}, state)
+				err := command.Execute(context.Background(), []string{}, state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal("some-env-id"))
@@ -139,13 +140,24 @@ var _ = Describe("StateQuery", func() {
 					Map: map[string]interface{}{"external_ip": "some-external-ip"},
 				}
 
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, "director address")
-				err := command.Execute([]string{}, state)
+				command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, "director address")
+				err := command.Execute(context.Background(), []string{}, state)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal("https://some-external-ip:25555"))
 			})
 		})
 
+		Context("when --json is passed", func() {
+			It("prints the property as a json object", func() {
+				command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, true), fakeStateValidator, terraformManager, "environment id")
+
+				err := command.Execute(context.Background(), []string{}, storage.State{EnvID: "some-env-id"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal(`{"env_id":"some-env-id"}`))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when the terraform output provider fails", func() {
 				BeforeEach(func() {
@@ -153,9 +165,9 @@ var _ = Describe("StateQuery", func() {
 				})
 
 				It("director-address returns an error for no-director environment", func() {
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, "director address")
+					command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, "director address")
 
-					err := command.Execute([]string{}, storage.State{
+					err := command.Execute(context.Background(), []string{}, storage.State{
 						IAAS:       "gcp",
 						NoDirector: true,
 					})
@@ -163,9 +175,9 @@ var _ = Describe("StateQuery", func() {
 				})
 
 				It("jumpbox-address returns an error", func() {
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, "jumpbox address")
+					command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, "jumpbox address")
 
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("failed to get terraform output"))
 				})
 			})
@@ -173,8 +185,8 @@ var _ = Describe("StateQuery", func() {
 			Context("when the state value is empty", func() {
 				It("returns an error", func() {
 					propertyName := fmt.Sprintf("%s-%d", "some-name", rand.Int())
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, terraformManager, propertyName)
-					err := command.Execute([]string{}, storage.State{
+					command := commands.NewStateQuery(commands.NewOutputFormatter(fakeLogger, false), fakeStateValidator, terraformManager, propertyName)
+					err := command.Execute(context.Background(), []string{}, storage.State{
 						BOSH: storage.BOSH{},
 					})
 					Expect(err).To(MatchError(fmt.Sprintf("Could not retrieve %s, please make sure you are targeting the proper state dir.", propertyName)))
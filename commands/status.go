@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type operations interface {
+	Get(id string) (storage.Operation, error)
+	List() ([]storage.Operation, error)
+}
+
+// Status prints the commands that --no-wait started in the background, or
+// one of them when given its operation id.
+type Status struct {
+	operations operations
+	output     OutputFormatter
+}
+
+func NewStatus(operations operations, output OutputFormatter) Status {
+	return Status{
+		operations: operations,
+		output:     output,
+	}
+}
+
+func (s Status) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	return nil
+}
+
+func (s Status) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	if len(subcommandFlags) > 0 {
+		operation, err := s.operations.Get(subcommandFlags[0])
+		if err != nil {
+			return err
+		}
+
+		if s.output.JSON() {
+			return s.output.PrintJSON(operation)
+		}
+		s.printOperations([]storage.Operation{operation})
+		return nil
+	}
+
+	operations, err := s.operations.List()
+	if err != nil {
+		return err
+	}
+
+	if s.output.JSON() {
+		return s.output.PrintJSON(operations)
+	}
+
+	if len(operations) == 0 {
+		s.output.Printf("There are no operations. Commands run with --no-wait are listed here.\n")
+		return nil
+	}
+	s.printOperations(operations)
+	return nil
+}
+
+func (s Status) printOperations(operations []storage.Operation) {
+	s.output.Printf("%-26s %-16s %-10s %s\n", "operation", "command", "status", "started")
+	for _, operation := range operations {
+		s.output.Printf("%-26s %-16s %-10s %s\n", operation.ID, operation.Command, operation.Status, operation.Started.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,87 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		logger     *fakes.Logger
+		operations *fakes.Operations
+		command    commands.Status
+
+		started time.Time
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		operations = &fakes.Operations{}
+		started = time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC)
+		operations.ListCall.Returns.Operations = []storage.Operation{
+			{ID: "20170601T120000Z-abcd1234", Command: "up", Status: storage.OperationRunning, Started: started},
+		}
+
+		command = commands.NewStatus(operations, commands.NewOutputFormatter(logger, false))
+	})
+
+	Describe("Execute", func() {
+		It("prints the operations", func() {
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(HaveLen(2))
+			Expect(logger.PrintfCall.Messages[0]).To(MatchRegexp(`^operation\s+command\s+status\s+started\n$`))
+			Expect(logger.PrintfCall.Messages[1]).To(MatchRegexp(`^20170601T120000Z-abcd1234\s+up\s+running\s+2017-06-01T12:00:00Z\n$`))
+		})
+
+		It("prints one operation when given its id", func() {
+			operations.GetCall.Returns = []fakes.GetOperationReturn{{Operation: storage.Operation{ID: "some-id", Command: "plan", Status: storage.OperationSucceeded}}}
+
+			err := command.Execute(context.Background(), []string{"some-id"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(operations.GetCall.Receives.ID).To(Equal("some-id"))
+			Expect(operations.ListCall.CallCount).To(Equal(0))
+			Expect(logger.PrintfCall.Messages[1]).To(MatchRegexp(`^some-id\s+plan\s+succeeded\s+`))
+		})
+
+		It("says so when there are no operations", func() {
+			operations.ListCall.Returns.Operations = []storage.Operation{}
+
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{"There are no operations. Commands run with --no-wait are listed here.\n"}))
+		})
+
+		It("prints the operations as JSON with --json", func() {
+			command = commands.NewStatus(operations, commands.NewOutputFormatter(logger, true))
+
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`[{
+				"id": "20170601T120000Z-abcd1234",
+				"command": "up",
+				"pid": 0,
+				"started": "2017-06-01T12:00:00Z",
+				"status": "running",
+				"output": ""
+			}]`))
+		})
+
+		It("returns an error when the operations cannot be read", func() {
+			operations.ListCall.Returns.Error = errors.New("permission denied")
+
+			err := command.Execute(context.Background(), []string{}, storage.State{})
+			Expect(err).To(MatchError("permission denied"))
+		})
+	})
+})
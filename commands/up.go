@@ -1,82 +1,389 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/verifier"
 )
 
 type Up struct {
-	plan               plan
-	boshManager        boshManager
-	cloudConfigManager cloudConfigManager
-	stateStore         stateStore
-	terraformManager   terraformManager
+	plan                plan
+	boshManager         boshManager
+	cloudConfigManager  cloudConfigManager
+	stateStore          stateStore
+	terraformManager    terraformManager
+	directorVerifier    directorVerifier
+	accountBootstrapper AccountBootstrapper
+	quotaChecker        QuotaChecker
+	rollback            directorRollback
+	logger              logger
+}
+
+// QuotaChecker checks the limits of the account before bbl up creates the
+// infrastructure of an environment.
+type QuotaChecker interface {
+	QuotaShortfalls(state storage.State, existing aws.ExistingResources) ([]string, error)
+}
+
+type directorVerifier interface {
+	Snapshot(state storage.State) (verifier.Snapshot, error)
+	Verify(state storage.State, before verifier.Snapshot) error
 }
 
 func NewUp(plan plan, boshManager boshManager,
 	cloudConfigManager cloudConfigManager,
-	stateStore stateStore, terraformManager terraformManager) Up {
+	stateStore stateStore, terraformManager terraformManager,
+	directorVerifier directorVerifier, accountBootstrapper AccountBootstrapper,
+	quotaChecker QuotaChecker, logger logger) Up {
 	return Up{
-		plan:               plan,
-		boshManager:        boshManager,
-		cloudConfigManager: cloudConfigManager,
-		stateStore:         stateStore,
-		terraformManager:   terraformManager,
+		plan:                plan,
+		boshManager:         boshManager,
+		cloudConfigManager:  cloudConfigManager,
+		stateStore:          stateStore,
+		terraformManager:    terraformManager,
+		directorVerifier:    directorVerifier,
+		accountBootstrapper: accountBootstrapper,
+		quotaChecker:        quotaChecker,
+		rollback:            directorRollback{boshManager: boshManager, stateStore: stateStore, logger: logger},
+		logger:              logger,
 	}
 }
 
+// replanChecks are the flags of bbl up that would change an existing plan.
+// bbl plan generates the terraform template, the terraform variables and the
+// create-env scripts from the state, and bbl up only applies them, so these
+// changes are refused with the bbl plan flags that make them.
+var replanChecks = []struct {
+	changed func(config PlanConfig, state storage.State) bool
+	err     string
+}{
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return len(c.AZs) > 0 && !reflect.DeepEqual(c.AZs, s.AWS.AZs)
+		},
+		`The plan was created with other availability zones. Run bbl plan --azs before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.Minimal && !s.AWS.Minimal },
+		`The plan was created without --minimal. Run bbl plan --minimal before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.VPCCIDR != "" && c.VPCCIDR != s.AWS.VPCCIDR },
+		`The plan was created with another VPC CIDR block. Run bbl plan --vpc-cidr before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return c.ExistingVPCID != "" && c.ExistingVPCID != s.AWS.ExistingVPCID
+		},
+		`The plan was created for another VPC. Run bbl plan --existing-vpc-id before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.NATGateway && !s.AWS.NATGateway },
+		`The plan was created with a NAT instance. Run bbl plan --nat-gateway before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.Cheap && !s.AWS.Cheap },
+		`The plan was created without --cheap. Run bbl plan --cheap before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.DualStack && !s.AWS.DualStack },
+		`The plan was created without --dual-stack. Run bbl plan --dual-stack before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.SSHCA && !s.SSHCA },
+		`The plan was created without an SSH certificate authority. Run bbl plan --ssh-ca before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.Lite && !s.AWS.Lite },
+		`The plan was created without --lite. Run bbl plan --lite before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return c.TrustedCACerts != "" && c.TrustedCACerts != s.TrustedCACerts
+		},
+		`The plan was created without these trusted CA certificates. Run bbl plan --trusted-ca-certs before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return !c.DirectorPorts.IsEmpty() && (s.DirectorPorts == nil || c.DirectorPorts != *s.DirectorPorts)
+		},
+		`The plan was created with other director ports. Run bbl plan --director-ports before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return (c.OpsFiles != nil && !reflect.DeepEqual(c.OpsFiles, s.DirectorOpsFiles)) ||
+				(c.VarsFiles != nil && !reflect.DeepEqual(c.VarsFiles, s.DirectorVarsFiles))
+		},
+		`The plan was created with other ops files or vars files. Run bbl plan --ops-file --vars-file before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return !c.DirectorVM.IsEmpty() && (s.DirectorVM == nil || s.DirectorVM.Merge(c.DirectorVM) != *s.DirectorVM)
+		},
+		`The plan was created with another director instance type or disk size. Run bbl plan --director-instance-type --director-disk-size before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return !c.DirectorSyslog.IsEmpty() && (s.DirectorSyslog == nil || s.DirectorSyslog.Merge(c.DirectorSyslog) != *s.DirectorSyslog)
+		},
+		`The plan was created with other syslog forwarding. Run bbl plan --syslog-address --syslog-port --syslog-transport before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return !c.ArtifactOverrides.IsEmpty() && (s.ArtifactOverrides == nil || s.ArtifactOverrides.Merge(c.ArtifactOverrides) != *s.ArtifactOverrides)
+		},
+		`The plan was created with other BOSH, CPI or stemcell artifacts. Run bbl plan with these flags before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return c.ArtifactsDir != "" && c.ArtifactsDir != s.ArtifactsDir
+		},
+		`The plan was created with another artifacts directory. Run bbl plan --artifacts-dir before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool { return c.S3Blobstore && !sameBlobstorePlan(c, s.AWS) },
+		`The plan was created with another blobstore. Run bbl plan --s3-blobstore before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return (c.SubnetSizes != nil || c.ReservedCIDRs != nil) && !sameSubnetPlan(c, s.AWS)
+		},
+		`The plan was created with other subnets. Run bbl plan --subnet-sizes --reserved-cidrs before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return (c.DirectorAllowedCIDRs != nil || c.LBAllowedCIDRs != nil) && !sameAllowedCIDRsPlan(c, s.AWS)
+		},
+		`The plan was created with other allowed CIDRs. Run bbl plan --director-allowed-cidrs --lb-allowed-cidrs, or bbl update-security-groups, before bbl up.`,
+	},
+	{
+		func(c PlanConfig, s storage.State) bool {
+			return len(c.SNIDomains) > 0 && !reflect.DeepEqual(applySNIDomains(s.AWS.SNIDomains, c.SNIDomains), s.AWS.SNIDomains)
+		},
+		`The plan was created with other SNI domains. Run bbl plan --lb-sni before bbl up.`,
+	},
+}
+
+// UpOptions are the flags of bbl up that are not flags of its plan.
+type UpOptions struct {
+	DryRun           bool
+	AutoApprove      bool
+	BootstrapAccount bool
+	Restart          bool
+	SkipQuotaCheck   bool
+}
+
 func (u Up) CheckFastFails(args []string, state storage.State) error {
+	options, args := parseUpArgs(args)
+	if options.BootstrapAccount && state.IAAS != "aws" {
+		return errors.New("--bootstrap-account is only supported for aws environments")
+	}
+
 	return u.plan.CheckFastFails(args, state)
 }
 
-func (u Up) Execute(args []string, state storage.State) error {
+func (u Up) Execute(ctx context.Context, args []string, state storage.State) error {
+	options, args := parseUpArgs(args)
+
 	config, err := u.ParseArgs(args, state)
 	if err != nil {
 		return err
 	}
 
+	_, err = u.Run(ctx, options, config, state)
+	return err
+}
+
+// Run brings up the environment of state with the plan of config, and
+// returns the state that it saved. It is bbl up without its flags, for tools
+// that embed bbl.
+func (u Up) Run(ctx context.Context, options UpOptions, config PlanConfig, state storage.State) (storage.State, error) {
+	var err error
+
+	u.plan.CheckLBWorkloads(config, state)
+
 	if !u.plan.IsInitialized(state) {
+		err = u.plan.CheckLBRemoval(config, state)
+		if err != nil {
+			return storage.State{}, err
+		}
+
 		planState, err := u.plan.InitializePlan(config, state)
 		if err != nil {
-			return err
+			return storage.State{}, err
 		}
 		state = planState
 	}
 
-	state, err = u.terraformManager.Apply(state)
-	if err != nil {
-		return handleTerraformError(err, state, u.stateStore)
+	if config.NoDirector {
+		state.NoDirector = true
 	}
 
-	state.NoDirector = false
+	// The bid price is only in the cloud config, which bbl up updates.
+	if config.SpotBidPrice != 0 {
+		state.AWS.SpotBidPrice = config.SpotBidPrice
+	}
 
-	err = u.stateStore.Set(state)
-	if err != nil {
-		return fmt.Errorf("Save state after terraform apply: %s", err)
+	for _, check := range replanChecks {
+		if check.changed(config, state) {
+			return storage.State{}, errors.New(check.err)
+		}
+	}
+
+	// The tags are only in the terraform variables, which up generates again
+	// rather than asking for bbl plan.
+	retag := len(config.Tags) > 0 && !reflect.DeepEqual(applyAnnotations(state.Annotations, config.Tags), state.Annotations)
+	if retag {
+		state.Annotations = applyAnnotations(state.Annotations, config.Tags)
+	}
+
+	if options.DryRun {
+		if retag {
+			u.logger.Println("The terraform variables would be generated again with the new tags, which the plan below does not show yet.")
+		}
+		return state, u.dryRun(state)
+	}
+
+	if retag {
+		err = u.terraformManager.Init(state)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("Generate terraform variables with the tags: %s", err)
+		}
+
+		err = u.stateStore.Set(state)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("Save state with the tags: %s", err)
+		}
+	}
+
+	if options.Restart {
+		state.UpProgress = nil
+	}
+
+	// The limits of the account are checked before terraform creates
+	// anything, rather than when a resource fails to be created halfway.
+	if state.IAAS == "aws" && !options.SkipQuotaCheck && !state.UpProgress.Done(storage.UpStepInfrastructure) {
+		err = u.checkQuotas(state)
+		if err != nil {
+			return storage.State{}, err
+		}
+	}
+	if state.UpProgress != nil {
+		u.logger.Printf("Resuming the bbl up that was interrupted. Skipping the steps it completed: %s. Pass --restart to run them again.\n", strings.Join(state.UpProgress.Completed, ", "))
+	}
+
+	// The infrastructure of an interrupted up was applied already, so there
+	// are no changes to confirm.
+	if !options.AutoApprove && !state.UpProgress.Done(storage.UpStepInfrastructure) {
+		proceed, err := u.confirmChanges(state)
+		if err != nil {
+			return storage.State{}, err
+		}
+		if !proceed {
+			u.logger.Step("exiting")
+			return state, nil
+		}
+	}
+
+	if options.BootstrapAccount {
+		err = u.accountBootstrapper.BootstrapAccount()
+		if err != nil {
+			return storage.State{}, fmt.Errorf("Bootstrap account: %s", err)
+		}
+	}
+
+	if !state.UpProgress.Done(storage.UpStepInfrastructure) {
+		err = u.replaceNATInstance(state)
+		if err != nil {
+			return storage.State{}, err
+		}
+
+		state, err = u.terraformManager.Apply(state)
+		if err != nil {
+			return storage.State{}, handleTerraformError(err, state, u.stateStore)
+		}
+
+		// An environment without a director has no steps after terraform
+		// to resume.
+		if !state.NoDirector {
+			state.UpProgress = state.UpProgress.Complete(storage.UpStepInfrastructure)
+		}
+
+		err = u.stateStore.Set(state)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("Save state after terraform apply: %s", err)
+		}
 	}
 
 	terraformOutputs, err := u.terraformManager.GetOutputs()
 	if err != nil {
-		return fmt.Errorf("Parse terraform outputs: %s", err)
+		return storage.State{}, fmt.Errorf("Parse terraform outputs: %s", err)
 	}
 
-	state, err = u.boshManager.CreateJumpbox(state, terraformOutputs)
-	switch err.(type) {
-	case bosh.ManagerCreateError:
-		bcErr := err.(bosh.ManagerCreateError)
-		if setErr := u.stateStore.Set(bcErr.State()); setErr != nil {
-			return fmt.Errorf("Save state after jumpbox create error: %s, %s", err, setErr)
+	// Environments created with --no-director only have their infrastructure
+	// managed by bbl; the operator deploys the director with bosh create-env,
+	// from the vars files that are written for it.
+	if state.NoDirector {
+		err = u.boshManager.WriteDeploymentVars(state, terraformOutputs)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("Write deployment vars: %s", err)
 		}
-		return fmt.Errorf("Create jumpbox: %s", err)
-	case error:
-		return fmt.Errorf("Create jumpbox: %s", err)
+		u.logger.Println("The infrastructure is up. The jumpbox and director vars files are in the vars directory of the state directory, for create-jumpbox.sh and create-director.sh or your own bosh create-env.")
+		return state, nil
 	}
 
-	err = u.stateStore.Set(state)
-	if err != nil {
-		return fmt.Errorf("Save state after create jumpbox: %s", err)
+	// An interrupt between the steps stops up after the step it completed,
+	// which is saved for the next up to resume from.
+	if ctx.Err() != nil {
+		return state, ctx.Err()
+	}
+
+	if !state.UpProgress.Done(storage.UpStepJumpbox) {
+		progress := state.UpProgress
+		state, err = u.boshManager.CreateJumpbox(state, terraformOutputs)
+		switch err.(type) {
+		case bosh.ManagerCreateError:
+			bcErr := err.(bosh.ManagerCreateError)
+			if setErr := u.stateStore.Set(bcErr.State()); setErr != nil {
+				return storage.State{}, fmt.Errorf("Save state after jumpbox create error: %s, %s", err, setErr)
+			}
+			return storage.State{}, fmt.Errorf("Create jumpbox: %s", err)
+		case error:
+			return storage.State{}, fmt.Errorf("Create jumpbox: %s", err)
+		}
+
+		state.UpProgress = progress.Complete(storage.UpStepJumpbox)
+
+		err = u.stateStore.Set(state)
+		if err != nil {
+			return storage.State{}, fmt.Errorf("Save state after create jumpbox: %s", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return state, ctx.Err()
+	}
+
+	// An existing director is being upgraded. What it reports now is compared
+	// with what the upgraded director reports once up is done, and it is
+	// rolled back to the artifacts it runs now if they differ.
+	upgrading := !state.BOSH.IsEmpty()
+	previousArtifacts := state.DeployedArtifacts
+	var snapshot verifier.Snapshot
+	if upgrading {
+		snapshot, err = u.directorVerifier.Snapshot(state)
+		if err != nil {
+			u.logger.Printf("The director could not be inspected before the upgrade, so the upgrade will not be verified: %s\n", err)
+			upgrading = false
+		}
 	}
 
 	state, err = u.boshManager.CreateDirector(state, terraformOutputs)
@@ -84,26 +391,206 @@ func (u Up) Execute(args []string, state storage.State) error {
 	case bosh.ManagerCreateError:
 		bcErr := err.(bosh.ManagerCreateError)
 		if setErr := u.stateStore.Set(bcErr.State()); setErr != nil {
-			return fmt.Errorf("Save state after bosh director create error: %s, %s", err, setErr)
+			return storage.State{}, fmt.Errorf("Save state after bosh director create error: %s, %s", err, setErr)
 		}
-		return fmt.Errorf("Create bosh director: %s", err)
+		return storage.State{}, fmt.Errorf("Create bosh director: %s", err)
 	case error:
-		return fmt.Errorf("Create bosh director: %s", err)
+		return storage.State{}, fmt.Errorf("Create bosh director: %s", err)
 	}
 
+	// The cloud config is updated by every up, so there is nothing left to
+	// resume once the director is up.
+	state.UpProgress = nil
+
 	err = u.stateStore.Set(state)
 	if err != nil {
-		return fmt.Errorf("Save state after create director: %s", err)
+		return storage.State{}, fmt.Errorf("Save state after create director: %s", err)
 	}
 
 	err = u.cloudConfigManager.Update(state)
 	if err != nil {
-		return fmt.Errorf("Update cloud config: %s", err)
+		return storage.State{}, fmt.Errorf("Update cloud config: %s", err)
+	}
+
+	if upgrading {
+		err = u.directorVerifier.Verify(state, snapshot)
+		if err != nil {
+			return storage.State{}, u.rollback.verifyFailed(err, state, previousArtifacts, terraformOutputs)
+		}
+	}
+
+	return state, nil
+}
+
+// replaceNATInstance moves the internal subnets of an environment that has a
+// NAT instance onto the NAT gateways of its plan. The gateways are created
+// and the subnets moved while the instance is kept, so that the director
+// always has a way out, and the apply that follows deletes the instance.
+func (u Up) replaceNATInstance(state storage.State) error {
+	if state.IAAS != "aws" || !state.AWS.NATGateway || state.AWS.Minimal {
+		return nil
+	}
+
+	isPaved, err := u.terraformManager.IsPaved()
+	if err != nil {
+		return fmt.Errorf("Check for existing infrastructure: %s", err)
+	}
+	if !isPaved {
+		return nil
+	}
+
+	outputs, err := u.terraformManager.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("Parse terraform outputs: %s", err)
+	}
+	if outputs.GetString("nat_eip") == "" {
+		return nil
+	}
+
+	u.logger.Step("moving the internal subnets from the NAT instance to NAT gateways")
+
+	state.AWS.KeepNATInstance = true
+	err = u.terraformManager.Init(state)
+	if err != nil {
+		return fmt.Errorf("Generate terraform template with the NAT instance: %s", err)
+	}
+
+	migratedState, err := u.terraformManager.Apply(state)
+	if err != nil {
+		return handleTerraformError(err, migratedState, u.stateStore)
+	}
+
+	state.AWS.KeepNATInstance = false
+	err = u.terraformManager.Init(state)
+	if err != nil {
+		return fmt.Errorf("Generate terraform template without the NAT instance: %s", err)
 	}
 
 	return nil
 }
 
+// dryRun prints the changes terraform would make to the infrastructure. The
+// director and jumpbox manifests are rendered by bosh create-env from the
+// terraform outputs, so only the scripts that would converge them are listed.
+func (u Up) dryRun(state storage.State) error {
+	plan, err := u.terraformManager.Plan(state)
+	if err != nil {
+		return fmt.Errorf("Terraform plan: %s", err)
+	}
+
+	u.logger.Println(plan)
+
+	if state.NoDirector {
+		u.logger.Println("The environment has no director, so bosh create-env would not run.")
+		return nil
+	}
+
+	u.logger.Println("bosh create-env would then converge the jumpbox and director with create-jumpbox.sh and create-director.sh, or their -override.sh replacements, and the cloud config would be updated.")
+	return nil
+}
+
+// confirmChanges prints the changes terraform would make to the existing
+// infrastructure of an environment and asks the operator to approve them.
+// Creating an environment and applying no changes are not confirmed.
+func (u Up) confirmChanges(state storage.State) (bool, error) {
+	isPaved, err := u.terraformManager.IsPaved()
+	if err != nil {
+		return false, fmt.Errorf("Check for existing infrastructure: %s", err)
+	}
+	if !isPaved {
+		return true, nil
+	}
+
+	plan, err := u.terraformManager.Plan(state)
+	if err != nil {
+		return false, fmt.Errorf("Terraform plan: %s", err)
+	}
+
+	if strings.Contains(plan, "No changes.") {
+		return true, nil
+	}
+
+	u.logger.Println(plan)
+
+	return u.logger.Prompt(fmt.Sprintf("Apply these changes to the infrastructure of %q?", state.EnvID)), nil
+}
+
 func (u Up) ParseArgs(args []string, state storage.State) (PlanConfig, error) {
 	return u.plan.ParseArgs(args, state)
 }
+
+// checkQuotas fails when a limit of the account leaves too little room for
+// what bbl up creates. A check that cannot read the limits, such as with
+// credentials that may not describe them, does not stop bbl up.
+func (u Up) checkQuotas(state storage.State) error {
+	existing, err := u.existingResources()
+	if err != nil {
+		u.logger.Printf("Skipping the quota check, which could not read the resources of the environment: %s\n", err)
+		return nil
+	}
+
+	shortfalls, err := u.quotaChecker.QuotaShortfalls(state, existing)
+	if err != nil {
+		u.logger.Printf("Skipping the quota check, which could not read the limits of the account: %s\n", err)
+		return nil
+	}
+
+	if len(shortfalls) > 0 {
+		return bblerrors.New(bblerrors.Quota, errors.New(strings.Join(shortfalls, "\n")))
+	}
+	return nil
+}
+
+// existingResources reads what the environment has already from the vars
+// directory, where the state migrator keeps the terraform state and the
+// create-env states of the jumpbox and director.
+func (u Up) existingResources() (aws.ExistingResources, error) {
+	existing := aws.ExistingResources{}
+
+	isPaved, err := u.terraformManager.IsPaved()
+	if err != nil {
+		return aws.ExistingResources{}, fmt.Errorf("Check for existing infrastructure: %s", err)
+	}
+	if isPaved {
+		existing.Terraform, err = u.terraformManager.ListResources()
+		if err != nil {
+			return aws.ExistingResources{}, err
+		}
+	}
+
+	existing.Jumpbox, err = u.boshManager.IsDeployed("jumpbox")
+	if err != nil {
+		return aws.ExistingResources{}, err
+	}
+
+	existing.Director, err = u.boshManager.IsDeployed("director")
+	if err != nil {
+		return aws.ExistingResources{}, err
+	}
+
+	return existing, nil
+}
+
+// parseUpArgs takes the flags of up out of the args, leaving the flags of
+// plan.
+func parseUpArgs(args []string) (UpOptions, []string) {
+	options := UpOptions{}
+	rest := []string{}
+	for _, arg := range args {
+		switch arg {
+		case "--dry-run", "-dry-run":
+			options.DryRun = true
+		case "--auto-approve", "-auto-approve", "--yes", "-yes":
+			options.AutoApprove = true
+		case "--bootstrap-account", "-bootstrap-account":
+			options.BootstrapAccount = true
+		case "--restart", "-restart":
+			options.Restart = true
+		case "--skip-quota-check", "-skip-quota-check":
+			options.SkipQuotaCheck = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return options, rest
+}
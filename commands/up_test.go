@@ -1,15 +1,20 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	"github.com/cloudfoundry/bosh-bootloader/verifier"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -17,11 +22,15 @@ var _ = Describe("Up", func() {
 	var (
 		command commands.Up
 
-		plan               *fakes.Plan
-		boshManager        *fakes.BOSHManager
-		terraformManager   *fakes.TerraformManager
-		cloudConfigManager *fakes.CloudConfigManager
-		stateStore         *fakes.StateStore
+		plan                *fakes.Plan
+		boshManager         *fakes.BOSHManager
+		terraformManager    *fakes.TerraformManager
+		cloudConfigManager  *fakes.CloudConfigManager
+		stateStore          *fakes.StateStore
+		directorVerifier    *fakes.DirectorVerifier
+		accountBootstrapper *fakes.AccountBootstrapper
+		quotaChecker        *fakes.QuotaChecker
+		logger              *fakes.Logger
 	)
 
 	BeforeEach(func() {
@@ -30,8 +39,12 @@ var _ = Describe("Up", func() {
 		terraformManager = &fakes.TerraformManager{}
 		cloudConfigManager = &fakes.CloudConfigManager{}
 		stateStore = &fakes.StateStore{}
+		directorVerifier = &fakes.DirectorVerifier{}
+		accountBootstrapper = &fakes.AccountBootstrapper{}
+		quotaChecker = &fakes.QuotaChecker{}
+		logger = &fakes.Logger{}
 
-		command = commands.NewUp(plan, boshManager, cloudConfigManager, stateStore, terraformManager)
+		command = commands.NewUp(plan, boshManager, cloudConfigManager, stateStore, terraformManager, directorVerifier, accountBootstrapper, quotaChecker, logger)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -43,6 +56,19 @@ var _ = Describe("Up", func() {
 			Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{}))
 			Expect(plan.CheckFastFailsCall.Receives.State).To(Equal(storage.State{Version: 999}))
 		})
+
+		It("returns an error for --bootstrap-account outside of aws", func() {
+			err := command.CheckFastFails([]string{"--bootstrap-account"}, storage.State{IAAS: "gcp"})
+			Expect(err).To(MatchError("--bootstrap-account is only supported for aws environments"))
+			Expect(plan.CheckFastFailsCall.CallCount).To(Equal(0))
+		})
+
+		It("does not pass --dry-run to Plan", func() {
+			err := command.CheckFastFails([]string{"--dry-run", "--name", "some-name"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(plan.CheckFastFailsCall.Receives.SubcommandFlags).To(Equal([]string{"--name", "some-name"}))
+		})
 	})
 
 	Describe("Execute", func() {
@@ -85,7 +111,7 @@ var _ = Describe("Up", func() {
 
 		Context("when bbl plan has been run", func() {
 			It("applies without re-initializing", func() {
-				err := command.Execute([]string{"some", "flags"}, incomingState)
+				err := command.Execute(context.Background(), []string{"some", "flags"}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(plan.IsInitializedCall.CallCount).To(Equal(1))
@@ -95,12 +121,16 @@ var _ = Describe("Up", func() {
 				Expect(plan.ParseArgsCall.Receives.Args).To(Equal([]string{"some", "flags"}))
 				Expect(plan.ParseArgsCall.Receives.State).To(Equal(incomingState))
 
+				Expect(plan.CheckLBWorkloadsCall.CallCount).To(Equal(1))
+				Expect(plan.CheckLBWorkloadsCall.Receives.State).To(Equal(incomingState))
+
 				Expect(plan.InitializePlanCall.CallCount).To(Equal(0))
 
 				Expect(terraformManager.InitCall.CallCount).To(Equal(0))
 
 				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
 				Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(incomingState))
+				terraformApplyState.UpProgress = &storage.UpProgress{Completed: []string{"infrastructure"}}
 				Expect(stateStore.SetCall.Receives[0].State).To(Equal(terraformApplyState))
 
 				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
@@ -109,6 +139,7 @@ var _ = Describe("Up", func() {
 				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(1))
 				Expect(boshManager.CreateJumpboxCall.Receives.State).To(Equal(terraformApplyState))
 				Expect(boshManager.CreateJumpboxCall.Receives.TerraformOutputs).To(Equal(terraformOutputs))
+				createJumpboxState.UpProgress = &storage.UpProgress{Completed: []string{"infrastructure", "jumpbox"}}
 				Expect(stateStore.SetCall.Receives[1].State).To(Equal(createJumpboxState))
 
 				Expect(boshManager.InitializeDirectorCall.CallCount).To(Equal(0))
@@ -124,11 +155,834 @@ var _ = Describe("Up", func() {
 			})
 		})
 
+		Context("when it is run with options instead of flags", func() {
+			It("applies the plan config and returns the state it saved", func() {
+				state, err := command.Run(context.Background(), commands.UpOptions{AutoApprove: true}, planConfig, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state).To(Equal(createDirectorState))
+
+				Expect(plan.ParseArgsCall.CallCount).To(Equal(0))
+				Expect(plan.CheckLBWorkloadsCall.Receives.Plan).To(Equal(planConfig))
+				Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(incomingState))
+				Expect(cloudConfigManager.UpdateCall.Receives.State).To(Equal(createDirectorState))
+			})
+		})
+
+		Context("when --no-director is passed", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", NoDirector: true}
+				terraformApplyState.NoDirector = true
+				terraformManager.ApplyCall.Returns.BBLState = terraformApplyState
+			})
+
+			It("records it in the state and only applies terraform", func() {
+				err := command.Execute(context.Background(), []string{"--no-director"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.NoDirector).To(BeTrue())
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(terraformApplyState))
+
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+				Expect(cloudConfigManager.UpdateCall.CallCount).To(Equal(0))
+			})
+
+			It("writes the vars files for the operator's bosh create-env", func() {
+				err := command.Execute(context.Background(), []string{"--no-director"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.WriteDeploymentVarsCall.CallCount).To(Equal(1))
+				Expect(boshManager.WriteDeploymentVarsCall.Receives.State).To(Equal(terraformApplyState))
+				Expect(boshManager.WriteDeploymentVarsCall.Receives.TerraformOutputs).To(Equal(terraformOutputs))
+				Expect(logger.PrintlnCall.Messages).To(ContainElement(ContainSubstring("The infrastructure is up.")))
+			})
+
+			It("returns an error when the vars files cannot be written", func() {
+				boshManager.WriteDeploymentVarsCall.Returns.Error = errors.New("disk full")
+
+				err := command.Execute(context.Background(), []string{"--no-director"}, incomingState)
+				Expect(err).To(MatchError("Write deployment vars: disk full"))
+			})
+		})
+
+		Context("when the environment has no director", func() {
+			BeforeEach(func() {
+				incomingState.NoDirector = true
+				terraformApplyState.NoDirector = true
+				terraformManager.ApplyCall.Returns.BBLState = terraformApplyState
+			})
+
+			It("keeps it director-less without the flag", func() {
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --azs is passed for an existing plan", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", AZs: []string{"us-east-1a", "us-east-1c"}}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--azs", "us-east-1a,us-east-1c"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other availability zones. Run bbl plan --azs before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("goes on when the plan has the same availability zones", func() {
+				incomingState.AWS.AZs = []string{"us-east-1a", "us-east-1c"}
+
+				err := command.Execute(context.Background(), []string{"--azs", "us-east-1a,us-east-1c"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.AZs).To(Equal([]string{"us-east-1a", "us-east-1c"}))
+			})
+		})
+
+		Context("when --bootstrap-account is passed", func() {
+			It("bootstraps the account before applying terraform", func() {
+				err := command.Execute(context.Background(), []string{"--bootstrap-account", "--name", "some-name"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.ParseArgsCall.Receives.Args).To(Equal([]string{"--name", "some-name"}))
+				Expect(accountBootstrapper.BootstrapAccountCall.CallCount).To(Equal(1))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("returns an error without applying terraform when bootstrapping fails", func() {
+				accountBootstrapper.BootstrapAccountCall.Returns.Error = errors.New("AccessDenied")
+
+				err := command.Execute(context.Background(), []string{"--bootstrap-account"}, incomingState)
+				Expect(err).To(MatchError("Bootstrap account: AccessDenied"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("does not bootstrap the account without the flag", func() {
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(accountBootstrapper.BootstrapAccountCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the environment is on aws", func() {
+			BeforeEach(func() {
+				incomingState.IAAS = "aws"
+			})
+
+			It("checks the limits of the account before applying terraform", func() {
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.QuotaShortfallsCall.CallCount).To(Equal(1))
+				Expect(quotaChecker.QuotaShortfallsCall.Receives.State.IAAS).To(Equal("aws"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("leaves out what the vars directory says the environment has already", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+				terraformManager.ListResourcesCall.Returns.Resources = []string{"aws_vpc.vpc", "aws_eip.jumpbox_eip"}
+				boshManager.IsDeployedCall.Returns.Deployed = map[string]bool{"jumpbox": true, "director": true}
+
+				err := command.Execute(context.Background(), []string{"--auto-approve"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.IsDeployedCall.Receives.Deployments).To(Equal([]string{"jumpbox", "director"}))
+				Expect(quotaChecker.QuotaShortfallsCall.Receives.Existing).To(Equal(aws.ExistingResources{
+					Terraform: []string{"aws_vpc.vpc", "aws_eip.jumpbox_eip"},
+					Jumpbox:   true,
+					Director:  true,
+				}))
+			})
+
+			It("goes on when the resources of the environment cannot be read", func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+				terraformManager.ListResourcesCall.Returns.Error = errors.New("Executor init: no network")
+
+				err := command.Execute(context.Background(), []string{"--auto-approve"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintfCall.Messages).To(ContainElement("Skipping the quota check, which could not read the resources of the environment: Executor init: no network\n"))
+				Expect(quotaChecker.QuotaShortfallsCall.CallCount).To(Equal(0))
+			})
+
+			It("returns a quota error without applying terraform when a limit is too low", func() {
+				quotaChecker.QuotaShortfallsCall.Returns.Shortfalls = []string{"Raise the limit of EC2-VPC Elastic IPs before bbl up.", "Raise the limit of VPCs per region before bbl up."}
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).To(MatchError("Raise the limit of EC2-VPC Elastic IPs before bbl up.\nRaise the limit of VPCs per region before bbl up."))
+				Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Quota))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("goes on when the limits cannot be read", func() {
+				quotaChecker.QuotaShortfallsCall.Returns.Error = errors.New("UnauthorizedOperation")
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintfCall.Messages).To(ContainElement("Skipping the quota check, which could not read the limits of the account: UnauthorizedOperation\n"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("does not check the limits with --skip-quota-check", func() {
+				err := command.Execute(context.Background(), []string{"--skip-quota-check"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(quotaChecker.QuotaShortfallsCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --minimal is passed for an existing plan", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", Minimal: true}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--minimal"}, incomingState)
+				Expect(err).To(MatchError("The plan was created without --minimal. Run bbl plan --minimal before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("goes on when the plan is minimal", func() {
+				incomingState.AWS.Minimal = true
+
+				err := command.Execute(context.Background(), []string{"--minimal"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.Minimal).To(BeTrue())
+			})
+		})
+
+		Context("when --vpc-cidr is passed for an existing plan", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", VPCCIDR: "192.168.0.0/16"}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "192.168.0.0/16"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with another VPC CIDR block. Run bbl plan --vpc-cidr before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("applies terraform when the plan has the block", func() {
+				incomingState.AWS.VPCCIDR = "192.168.0.0/16"
+
+				err := command.Execute(context.Background(), []string{"--vpc-cidr", "192.168.0.0/16"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.VPCCIDR).To(Equal("192.168.0.0/16"))
+			})
+		})
+
+		Context("when --existing-vpc-id is passed for an existing plan", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", ExistingVPCID: "vpc-0a1b2c3d"}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d"}, incomingState)
+				Expect(err).To(MatchError("The plan was created for another VPC. Run bbl plan --existing-vpc-id before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("applies terraform in the existing vpc of the plan", func() {
+				incomingState.AWS.ExistingVPCID = "vpc-0a1b2c3d"
+
+				err := command.Execute(context.Background(), []string{"--existing-vpc-id", "vpc-0a1b2c3d"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.ExistingVPCID).To(Equal("vpc-0a1b2c3d"))
+			})
+		})
+
+		Context("when --reserved-cidrs is passed for a plan with other subnets", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", ReservedCIDRs: []string{"10.0.128.0/17"}}
+
+				err := command.Execute(context.Background(), []string{"--reserved-cidrs", "10.0.128.0/17"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other subnets. Run bbl plan --subnet-sizes --reserved-cidrs before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --lb-allowed-cidrs is passed for a plan with other allowed blocks", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", LBAllowedCIDRs: []string{"203.0.113.0/24"}}
+
+				err := command.Execute(context.Background(), []string{"--lb-allowed-cidrs", "203.0.113.0/24"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other allowed CIDRs. Run bbl plan --director-allowed-cidrs --lb-allowed-cidrs, or bbl update-security-groups, before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --s3-blobstore is passed for a plan with another blobstore", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", S3Blobstore: true}
+
+				err := command.Execute(context.Background(), []string{"--s3-blobstore"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with another blobstore. Run bbl plan --s3-blobstore before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --tags is passed for an existing plan", func() {
+			BeforeEach(func() {
+				incomingState.Annotations = map[string]string{"owner": "some-team"}
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", Tags: [][2]string{{"cost-center", "1234"}}}
+			})
+
+			It("generates the terraform variables again with the tags and applies them", func() {
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center=1234"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				tags := map[string]string{"owner": "some-team", "cost-center": "1234"}
+				Expect(terraformManager.InitCall.CallCount).To(Equal(1))
+				Expect(terraformManager.InitCall.Receives.BBLState.Annotations).To(Equal(tags))
+				Expect(stateStore.SetCall.Receives[0].State.Annotations).To(Equal(tags))
+				Expect(terraformManager.ApplyCall.Receives.BBLState.Annotations).To(Equal(tags))
+			})
+
+			It("does not generate the terraform variables again when the plan has the tags", func() {
+				incomingState.Annotations["cost-center"] = "1234"
+
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center=1234"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.InitCall.CallCount).To(Equal(0))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("does not write anything with --dry-run", func() {
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center=1234", "--dry-run"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.InitCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("The terraform variables would be generated again with the new tags, which the plan below does not show yet."))
+			})
+
+			It("returns an error when the terraform variables cannot be generated", func() {
+				terraformManager.InitCall.Returns.Error = errors.New("disk full")
+
+				err := command.Execute(context.Background(), []string{"--tags", "cost-center=1234"}, incomingState)
+				Expect(err).To(MatchError("Generate terraform variables with the tags: disk full"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --lb-sni is passed for an existing plan", func() {
+			BeforeEach(func() {
+				incomingState.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "apps"}}
+			})
+
+			It("returns an error without applying anything when the SNI domains change", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", SNIDomains: [][2]string{{"api.example.com", "api"}}}
+
+				err := command.Execute(context.Background(), []string{"--lb-sni", "api.example.com=api"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other SNI domains. Run bbl plan --lb-sni before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("applies terraform when the plan has the SNI domains", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", SNIDomains: [][2]string{{"apps.example.com", "apps"}}}
+
+				err := command.Execute(context.Background(), []string{"--lb-sni", "apps.example.com=apps"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+		})
+
+		Context("when artifact overrides are passed for a plan without them", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", ArtifactOverrides: storage.ArtifactOverrides{StemcellURL: "some-url", StemcellSHA1: "some-sha1"}}
+
+				err := command.Execute(context.Background(), []string{"--stemcell-url", "some-url", "--stemcell-sha1", "some-sha1"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other BOSH, CPI or stemcell artifacts. Run bbl plan with these flags before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --artifacts-dir is passed for a plan with another directory", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", ArtifactsDir: "/some/artifacts"}
+
+				err := command.Execute(context.Background(), []string{"--artifacts-dir", "/some/artifacts"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with another artifacts directory. Run bbl plan --artifacts-dir before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --director-ports is passed for a plan with other ports", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", DirectorPorts: storage.DirectorPorts{NATS: 4223}}
+
+				err := command.Execute(context.Background(), []string{"--director-ports", "nats=4223"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other director ports. Run bbl plan --director-ports before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --director-disk-size is passed for a plan with another disk size", func() {
+			It("returns an error without applying anything", func() {
+				incomingState.DirectorVM = &storage.DirectorVM{InstanceType: "m4.xlarge", DiskSize: 100}
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", DirectorVM: storage.DirectorVM{DiskSize: 200}}
+
+				err := command.Execute(context.Background(), []string{"--director-disk-size", "200"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with another director instance type or disk size. Run bbl plan --director-instance-type --director-disk-size before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("applies the plan when it already has the disk size", func() {
+				incomingState.DirectorVM = &storage.DirectorVM{InstanceType: "m4.xlarge", DiskSize: 200}
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", DirectorVM: storage.DirectorVM{DiskSize: 200}}
+
+				err := command.Execute(context.Background(), []string{"--director-disk-size", "200"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+		})
+
+		Context("when --syslog-address is passed for a plan with other syslog forwarding", func() {
+			It("returns an error without applying anything", func() {
+				incomingState.DirectorSyslog = &storage.DirectorSyslog{Address: "logs.example.com", Port: 514, Transport: "tcp"}
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", DirectorSyslog: storage.DirectorSyslog{Address: "other-logs.example.com"}}
+
+				err := command.Execute(context.Background(), []string{"--syslog-address", "other-logs.example.com"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other syslog forwarding. Run bbl plan --syslog-address --syslog-port --syslog-transport before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --ssh-ca is passed for a plan without a certificate authority", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", SSHCA: true}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--ssh-ca"}, incomingState)
+				Expect(err).To(MatchError("The plan was created without an SSH certificate authority. Run bbl plan --ssh-ca before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("succeeds once the plan has the certificate authority", func() {
+				incomingState.SSHCA = true
+				err := command.Execute(context.Background(), []string{"--ssh-ca"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.SSHCA).To(BeTrue())
+			})
+		})
+
+		Context("when --lite is passed for a plan without bosh-lite", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", Lite: true}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--lite"}, incomingState)
+				Expect(err).To(MatchError("The plan was created without --lite. Run bbl plan --lite before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("succeeds once the plan is a bosh-lite", func() {
+				incomingState.AWS.Lite = true
+				err := command.Execute(context.Background(), []string{"--lite"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.Lite).To(BeTrue())
+			})
+		})
+
+		Context("when --cheap is passed for a plan without the profile", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", Cheap: true}
+
+				err := command.Execute(context.Background(), []string{"--cheap"}, incomingState)
+				Expect(err).To(MatchError("The plan was created without --cheap. Run bbl plan --cheap before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --dual-stack is passed for a plan without it", func() {
+			It("returns an error without applying anything", func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", DualStack: true}
+
+				err := command.Execute(context.Background(), []string{"--dual-stack"}, incomingState)
+				Expect(err).To(MatchError("The plan was created without --dual-stack. Run bbl plan --dual-stack before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --nat-gateway is passed for a plan with a NAT instance", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", NATGateway: true}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--nat-gateway"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with a NAT instance. Run bbl plan --nat-gateway before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the plan moves an environment with a NAT instance onto NAT gateways", func() {
+			BeforeEach(func() {
+				incomingState.IAAS = "aws"
+				incomingState.AWS.NATGateway = true
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+				terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{"nat_eip": "203.0.113.7"}}
+			})
+
+			It("creates the NAT gateways alongside the NAT instance before deleting it", func() {
+				err := command.Execute(context.Background(), []string{"--auto-approve"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.StepCall.Messages).To(ContainElement("moving the internal subnets from the NAT instance to NAT gateways"))
+				Expect(terraformManager.InitCall.CallCount).To(Equal(2))
+				Expect(terraformManager.InitCall.Receives.BBLState.AWS.KeepNATInstance).To(BeFalse())
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(2))
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.KeepNATInstance).To(BeFalse())
+			})
+
+			It("does nothing more once the NAT instance is gone", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = terraformOutputs
+
+				err := command.Execute(context.Background(), []string{"--auto-approve"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.InitCall.CallCount).To(Equal(0))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("saves the state and stops when the NAT gateways cannot be created", func() {
+				terraformManager.ApplyCall.Returns.Error = errors.New("NatGatewayLimitExceeded: too many")
+
+				err := command.Execute(context.Background(), []string{"--auto-approve"}, incomingState)
+				Expect(err).To(MatchError("NatGatewayLimitExceeded: too many"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.KeepNATInstance).To(BeTrue())
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+			})
+
+			It("returns an error when the template cannot be generated", func() {
+				terraformManager.InitCall.Returns.Error = errors.New("disk full")
+
+				err := command.Execute(context.Background(), []string{"--auto-approve"}, incomingState)
+				Expect(err).To(MatchError("Generate terraform template with the NAT instance: disk full"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --trusted-ca-certs is passed for a plan without those certificates", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{Name: "some-name", TrustedCACerts: "some-ca-certs"}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--trusted-ca-certs", "some-path"}, incomingState)
+				Expect(err).To(MatchError("The plan was created without these trusted CA certificates. Run bbl plan --trusted-ca-certs before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("succeeds once the plan has the certificates", func() {
+				incomingState.TrustedCACerts = "some-ca-certs"
+				err := command.Execute(context.Background(), []string{"--trusted-ca-certs", "some-path"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when --ops-file is passed for a plan with other ops files", func() {
+			BeforeEach(func() {
+				plan.ParseArgsCall.Returns.Config = commands.PlanConfig{
+					Name:     "some-name",
+					OpsFiles: []storage.DirectorFile{{Name: "workers.yml", Contents: "[]"}},
+				}
+			})
+
+			It("returns an error without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--ops-file", "workers.yml"}, incomingState)
+				Expect(err).To(MatchError("The plan was created with other ops files or vars files. Run bbl plan --ops-file --vars-file before bbl up."))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("succeeds once the plan has the ops files", func() {
+				incomingState.DirectorOpsFiles = []storage.DirectorFile{{Name: "workers.yml", Contents: "[]"}}
+				err := command.Execute(context.Background(), []string{"--ops-file", "workers.yml"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the infrastructure already exists", func() {
+			BeforeEach(func() {
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+				terraformManager.PlanCall.Returns.Output = "Plan: 1 to add, 2 to change, 0 to destroy."
+				incomingState.EnvID = "some-env"
+			})
+
+			It("prints the changes and applies them once they are confirmed", func() {
+				logger.PromptCall.Returns.Proceed = true
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.PlanCall.Receives.BBLState).To(Equal(incomingState))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"Plan: 1 to add, 2 to change, 0 to destroy."}))
+				Expect(logger.PromptCall.Receives.Message).To(Equal(`Apply these changes to the infrastructure of "some-env"?`))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("does not change anything when the changes are not confirmed", func() {
+				logger.PromptCall.Returns.Proceed = false
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(0))
+			})
+
+			It("does not ask when terraform has no changes to make", func() {
+				terraformManager.PlanCall.Returns.Output = "No changes. Infrastructure is up-to-date."
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PromptCall.CallCount).To(Equal(0))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			DescribeTable("does not ask when the changes are approved with a flag",
+				func(flag string) {
+					err := command.Execute(context.Background(), []string{flag, "--name", "some-name"}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(plan.ParseArgsCall.Receives.Args).To(Equal([]string{"--name", "some-name"}))
+					Expect(terraformManager.PlanCall.CallCount).To(Equal(0))
+					Expect(logger.PromptCall.CallCount).To(Equal(0))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+				},
+				Entry("--auto-approve", "--auto-approve"),
+				Entry("--yes", "--yes"),
+			)
+
+			It("returns an error when terraform plan fails", func() {
+				terraformManager.PlanCall.Returns.Error = errors.New("guava")
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).To(MatchError("Terraform plan: guava"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when a previous up was interrupted", func() {
+			BeforeEach(func() {
+				incomingState.UpProgress = &storage.UpProgress{Completed: []string{"infrastructure"}}
+				terraformManager.IsPavedCall.Returns.IsPaved = true
+				terraformManager.PlanCall.Returns.Output = "Plan: 1 to add, 0 to change, 0 to destroy."
+			})
+
+			It("resumes after the steps it completed", func() {
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.Messages).To(ContainElement("Resuming the bbl up that was interrupted. Skipping the steps it completed: infrastructure. Pass --restart to run them again.\n"))
+				Expect(logger.PromptCall.CallCount).To(Equal(0))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+
+				Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(1))
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(1))
+				Expect(boshManager.CreateJumpboxCall.Receives.State).To(Equal(incomingState))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(1))
+				Expect(cloudConfigManager.UpdateCall.CallCount).To(Equal(1))
+			})
+
+			It("skips the jumpbox when it was created", func() {
+				incomingState.UpProgress = &storage.UpProgress{Completed: []string{"infrastructure", "jumpbox"}}
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateDirectorCall.Receives.State).To(Equal(incomingState))
+			})
+
+			It("clears the progress once the director is up", func() {
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+				Expect(stateStore.SetCall.Receives[0].State.UpProgress).To(Equal(&storage.UpProgress{Completed: []string{"infrastructure", "jumpbox"}}))
+				Expect(stateStore.SetCall.Receives[1].State.UpProgress).To(BeNil())
+			})
+
+			It("stops before the next step when it is interrupted", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := command.Execute(ctx, []string{}, incomingState)
+				Expect(err).To(Equal(context.Canceled))
+
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+			})
+
+			It("runs every step again with --restart", func() {
+				logger.PromptCall.Returns.Proceed = true
+
+				err := command.Execute(context.Background(), []string{"--restart"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.ParseArgsCall.Receives.Args).To(BeEmpty())
+				Expect(logger.PrintfCall.Messages).To(BeEmpty())
+				Expect(logger.PromptCall.CallCount).To(Equal(1))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+				Expect(terraformManager.ApplyCall.Receives.BBLState.UpProgress).To(BeNil())
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(1))
+			})
+		})
+
+		Context("when the director already exists", func() {
+			var snapshot verifier.Snapshot
+
+			BeforeEach(func() {
+				createJumpboxState.BOSH = storage.BOSH{DirectorAddress: "some-director-address"}
+				boshManager.CreateJumpboxCall.Returns.State = createJumpboxState
+
+				snapshot = verifier.Snapshot{Version: "264.1.0", Deployments: []string{"cf"}}
+				directorVerifier.SnapshotCall.Returns.Snapshot = snapshot
+			})
+
+			It("verifies the upgraded director against what the old one reported", func() {
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				createJumpboxState.UpProgress = &storage.UpProgress{Completed: []string{"infrastructure", "jumpbox"}}
+				Expect(directorVerifier.SnapshotCall.Receives.State).To(Equal(createJumpboxState))
+				Expect(directorVerifier.VerifyCall.CallCount).To(Equal(1))
+				Expect(directorVerifier.VerifyCall.Receives.State).To(Equal(createDirectorState))
+				Expect(directorVerifier.VerifyCall.Receives.Snapshot).To(Equal(snapshot))
+			})
+
+			It("returns an error when the upgraded director fails verification", func() {
+				directorVerifier.VerifyCall.Returns.Error = errors.New("deployments missing after the upgrade: cf")
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).To(MatchError("Verify director upgrade: deployments missing after the upgrade: cf. Roll back director: the artifacts of the previous director were not recorded. The previous director can be restored by running bbl up with the bbl version that deployed it."))
+				Expect(cloudConfigManager.UpdateCall.CallCount).To(Equal(1))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(1))
+			})
+
+			Context("when the artifacts of the old director were recorded", func() {
+				var previous storage.ArtifactOverrides
+
+				BeforeEach(func() {
+					previous = storage.ArtifactOverrides{BOSHReleaseURL: "some-old-bosh-url", BOSHReleaseSHA1: "some-old-bosh-sha1"}
+					createJumpboxState.DeployedArtifacts = &previous
+					boshManager.CreateJumpboxCall.Returns.State = createJumpboxState
+
+					createDirectorState.DeployedArtifacts = &storage.ArtifactOverrides{BOSHReleaseURL: "some-new-bosh-url", BOSHReleaseSHA1: "some-new-bosh-sha1"}
+					createDirectorState.DirectorVersions = &storage.DirectorVersions{BOSH: "264.1.0"}
+					boshManager.CreateDirectorCall.Returns.State = createDirectorState
+
+					directorVerifier.VerifyCall.Returns.Error = errors.New("deployments missing after the upgrade: cf")
+				})
+
+				It("rolls the director back to them and renders the manifest of the plan again", func() {
+					err := command.Execute(context.Background(), []string{}, incomingState)
+					Expect(err).To(MatchError("Verify director upgrade: deployments missing after the upgrade: cf. The director was rolled back to bosh 264.1.0. Run bbl up to try the upgrade again."))
+
+					Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(2))
+					Expect(boshManager.CreateDirectorCall.Receives.State.ArtifactOverrides).To(Equal(&previous))
+					Expect(boshManager.InitializeDirectorCall.CallCount).To(Equal(2))
+					Expect(boshManager.InitializeDirectorCall.Receives.State.ArtifactOverrides).To(BeNil())
+
+					rolledBack := stateStore.SetCall.Receives[stateStore.SetCall.CallCount-1].State
+					Expect(rolledBack.ArtifactOverrides).To(BeNil())
+					Expect(rolledBack.DirectorVersions).To(Equal(&storage.DirectorVersions{BOSH: "264.1.0"}))
+				})
+
+				It("saves the state and returns an error when the rollback fails", func() {
+					failedState := storage.State{EnvID: "failed-env"}
+					boshManager.CreateDirectorCall.Stub = func(state storage.State) (storage.State, error) {
+						if boshManager.CreateDirectorCall.CallCount == 2 {
+							return storage.State{}, bosh.NewManagerCreateError(failedState, errors.New("lime"))
+						}
+						return createDirectorState, nil
+					}
+
+					err := command.Execute(context.Background(), []string{}, incomingState)
+					Expect(err).To(MatchError("Verify director upgrade: deployments missing after the upgrade: cf. Roll back director: lime. The previous director can be restored by running bbl up with the bbl version that deployed it."))
+					Expect(stateStore.SetCall.Receives[stateStore.SetCall.CallCount-1].State).To(Equal(failedState))
+				})
+			})
+
+			It("upgrades without verifying when the old director cannot be inspected", func() {
+				directorVerifier.SnapshotCall.Returns.Error = errors.New("Connect to the director: lime")
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.Messages).To(ContainElement("The director could not be inspected before the upgrade, so the upgrade will not be verified: Connect to the director: lime\n"))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(1))
+				Expect(directorVerifier.VerifyCall.CallCount).To(Equal(0))
+			})
+		})
+
+		It("does not verify a new director", func() {
+			err := command.Execute(context.Background(), []string{}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(directorVerifier.SnapshotCall.CallCount).To(Equal(0))
+			Expect(directorVerifier.VerifyCall.CallCount).To(Equal(0))
+		})
+
+		Context("when --dry-run is passed", func() {
+			BeforeEach(func() {
+				terraformManager.PlanCall.Returns.Output = "Plan: 3 to add, 1 to change, 0 to destroy."
+			})
+
+			It("prints the terraform plan without applying anything", func() {
+				err := command.Execute(context.Background(), []string{"--dry-run", "--name", "some-name"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(plan.ParseArgsCall.Receives.Args).To(Equal([]string{"--name", "some-name"}))
+				Expect(terraformManager.PlanCall.Receives.BBLState).To(Equal(incomingState))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"Plan: 3 to add, 1 to change, 0 to destroy.",
+					"bosh create-env would then converge the jumpbox and director with create-jumpbox.sh and create-director.sh, or their -override.sh replacements, and the cloud config would be updated.",
+				}))
+
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateJumpboxCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+				Expect(cloudConfigManager.UpdateCall.CallCount).To(Equal(0))
+			})
+
+			Context("when the environment has no director", func() {
+				It("only prints the terraform plan", func() {
+					incomingState.NoDirector = true
+					err := command.Execute(context.Background(), []string{"--dry-run"}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+						"Plan: 3 to add, 1 to change, 0 to destroy.",
+						"The environment has no director, so bosh create-env would not run.",
+					}))
+				})
+			})
+
+			Context("when terraform plan fails", func() {
+				BeforeEach(func() {
+					terraformManager.PlanCall.Returns.Error = errors.New("kumquat")
+				})
+
+				It("returns the error", func() {
+					err := command.Execute(context.Background(), []string{"--dry-run"}, incomingState)
+					Expect(err).To(MatchError("Terraform plan: kumquat"))
+				})
+			})
+		})
+
 		Context("if parse args fails", func() {
 			It("returns an error if parse args fails", func() {
 				plan.ParseArgsCall.Returns.Error = errors.New("canteloupe")
 
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("canteloupe"))
 			})
 		})
@@ -138,7 +992,7 @@ var _ = Describe("Up", func() {
 				plan.IsInitializedCall.Returns.IsInitialized = false
 			})
 			It("calls bbl plan", func() {
-				err := command.Execute([]string{"some", "flags"}, incomingState)
+				err := command.Execute(context.Background(), []string{"some", "flags"}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(plan.IsInitializedCall.CallCount).To(Equal(1))
@@ -148,9 +1002,22 @@ var _ = Describe("Up", func() {
 				Expect(plan.InitializePlanCall.Receives.Plan).To(Equal(planConfig))
 				Expect(plan.InitializePlanCall.Receives.State).To(Equal(incomingState))
 
+				Expect(plan.CheckLBRemovalCall.CallCount).To(Equal(1))
+				Expect(plan.CheckLBRemovalCall.Receives.Plan).To(Equal(planConfig))
+
 				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
 				Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(planState))
 			})
+
+			It("does not remove a load balancer that deployments still use", func() {
+				plan.CheckLBRemovalCall.Returns.Error = errors.New("still used")
+
+				err := command.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).To(MatchError("still used"))
+
+				Expect(plan.InitializePlanCall.CallCount).To(Equal(0))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
 		})
 
 		Describe("failure cases", func() {
@@ -160,7 +1027,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("apple"))
 				})
 			})
@@ -171,7 +1038,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns the error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("passionfruit"))
 				})
 			})
@@ -182,7 +1049,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Save state after terraform apply: kiwi"))
 				})
 			})
@@ -193,7 +1060,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Parse terraform outputs: raspberry"))
 				})
 			})
@@ -204,7 +1071,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Create jumpbox: pineapple"))
 				})
 			})
@@ -215,7 +1082,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Save state after create jumpbox: kiwi"))
 				})
 			})
@@ -226,7 +1093,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Create bosh director: pineapple"))
 				})
 			})
@@ -237,7 +1104,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Save state after create director: kiwi"))
 				})
 			})
@@ -248,7 +1115,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Update cloud config: coconut"))
 				})
 			})
@@ -265,7 +1132,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("saves the bbl state and returns the error", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("grapefruit"))
 
 					Expect(stateStore.SetCall.CallCount).To(Equal(1))
@@ -278,7 +1145,7 @@ var _ = Describe("Up", func() {
 					})
 
 					It("saves the bbl state and returns the error", func() {
-						err := command.Execute([]string{}, storage.State{})
+						err := command.Execute(context.Background(), []string{}, storage.State{})
 						Expect(err).To(MatchError("the following errors occurred:\ngrapefruit,\nfailed to set bbl state"))
 					})
 				})
@@ -294,7 +1161,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns the error and saves the state", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Create jumpbox: rambutan"))
 
 					Expect(stateStore.SetCall.CallCount).To(Equal(2))
@@ -307,7 +1174,7 @@ var _ = Describe("Up", func() {
 					})
 
 					It("returns a compound error", func() {
-						err := command.Execute([]string{}, storage.State{})
+						err := command.Execute(context.Background(), []string{}, storage.State{})
 						Expect(err).To(MatchError("Save state after jumpbox create error: rambutan, lychee"))
 
 						Expect(stateStore.SetCall.CallCount).To(Equal(2))
@@ -326,7 +1193,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("returns the error and saves the state", func() {
-					err := command.Execute([]string{}, storage.State{})
+					err := command.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("Create bosh director: rambutan"))
 
 					Expect(stateStore.SetCall.CallCount).To(Equal(3))
@@ -339,7 +1206,7 @@ var _ = Describe("Up", func() {
 					})
 
 					It("returns a compound error", func() {
-						err := command.Execute([]string{}, storage.State{})
+						err := command.Execute(context.Background(), []string{}, storage.State{})
 						Expect(err).To(MatchError("Save state after bosh director create error: rambutan, lychee"))
 
 						Expect(stateStore.SetCall.CallCount).To(Equal(3))
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type updateSecurityGroupsConfig struct {
+	directorCIDRs []string
+	lbCIDRs       []string
+}
+
+// UpdateSecurityGroups changes the blocks that may reach the jumpbox and the
+// director, and the load balancers, of an environment that is up. Only the
+// terraform of the environment is applied, since the security groups do not
+// change the director.
+type UpdateSecurityGroups struct {
+	stateValidator   stateValidator
+	terraformManager terraformManager
+	stateStore       stateStore
+	logger           logger
+}
+
+func NewUpdateSecurityGroups(stateValidator stateValidator, terraformManager terraformManager, stateStore stateStore, logger logger) UpdateSecurityGroups {
+	return UpdateSecurityGroups{
+		stateValidator:   stateValidator,
+		terraformManager: terraformManager,
+		stateStore:       stateStore,
+		logger:           logger,
+	}
+}
+
+func (u UpdateSecurityGroups) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := parseUpdateSecurityGroupsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = u.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("update-security-groups only updates the security groups of aws environments.")
+	}
+
+	isPaved, err := u.terraformManager.IsPaved()
+	if err != nil {
+		return fmt.Errorf("Check for existing infrastructure: %s", err)
+	}
+	if !isPaved {
+		return errors.New("The environment is not up yet. Pass --director-allowed-cidrs and --lb-allowed-cidrs to bbl plan or bbl up instead.")
+	}
+
+	if config.lbCIDRs != nil && state.LB.Type == "" {
+		return errors.New("The environment has no load balancer to allow --lb-allowed-cidrs to.")
+	}
+
+	return u.terraformManager.ValidateVersion()
+}
+
+func (u UpdateSecurityGroups) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseUpdateSecurityGroupsArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.directorCIDRs != nil {
+		state.AWS.DirectorAllowedCIDRs = config.directorCIDRs
+	}
+	if config.lbCIDRs != nil {
+		state.AWS.LBAllowedCIDRs = config.lbCIDRs
+	}
+
+	err = u.terraformManager.Init(state)
+	if err != nil {
+		return fmt.Errorf("Terraform manager init: %s", err)
+	}
+
+	state, err = u.terraformManager.Apply(state)
+	if err != nil {
+		return handleTerraformError(err, state, u.stateStore)
+	}
+
+	err = u.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state after terraform apply: %s", err)
+	}
+
+	u.logger.Println(fmt.Sprintf("The director accepts connections from %s and the load balancers from %s.",
+		describeCIDRs(state.AWS.DirectorAllowedCIDRs), describeCIDRs(state.AWS.LBAllowedCIDRs)))
+	return nil
+}
+
+func describeCIDRs(cidrs []string) string {
+	if len(cidrs) == 0 {
+		return "anywhere"
+	}
+	return strings.Join(cidrs, ", ")
+}
+
+func parseUpdateSecurityGroupsArgs(args []string) (updateSecurityGroupsConfig, error) {
+	var (
+		config        updateSecurityGroupsConfig
+		directorCIDRs string
+		lbCIDRs       string
+	)
+
+	updateFlags := flags.New("update-security-groups")
+	updateFlags.String(&directorCIDRs, "director-allowed-cidrs", "")
+	updateFlags.String(&lbCIDRs, "lb-allowed-cidrs", "")
+
+	err := updateFlags.Parse(args)
+	if err != nil {
+		return updateSecurityGroupsConfig{}, err
+	}
+
+	if directorCIDRs == "" && lbCIDRs == "" {
+		return updateSecurityGroupsConfig{}, errors.New("--director-allowed-cidrs or --lb-allowed-cidrs is required")
+	}
+
+	if directorCIDRs != "" {
+		config.directorCIDRs, err = parseCIDRs("director-allowed-cidrs", directorCIDRs)
+		if err != nil {
+			return updateSecurityGroupsConfig{}, err
+		}
+	}
+
+	if lbCIDRs != "" {
+		config.lbCIDRs, err = parseCIDRs("lb-allowed-cidrs", lbCIDRs)
+		if err != nil {
+			return updateSecurityGroupsConfig{}, err
+		}
+	}
+
+	return config, nil
+}
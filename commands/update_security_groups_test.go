@@ -0,0 +1,147 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateSecurityGroups", func() {
+	var (
+		stateValidator   *fakes.StateValidator
+		terraformManager *fakes.TerraformManager
+		stateStore       *fakes.StateStore
+		logger           *fakes.Logger
+		command          commands.UpdateSecurityGroups
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		command = commands.NewUpdateSecurityGroups(stateValidator, terraformManager, stateStore, logger)
+
+		// The terraform state of a loaded state is in the vars directory,
+		// where the migrator moves it, so TFState is empty.
+		terraformManager.IsPavedCall.Returns.IsPaved = true
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env",
+			LB:    storage.LB{Type: "cf"},
+			AWS:   storage.AWS{LBAllowedCIDRs: []string{"203.0.113.0/24"}},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state and the terraform version", func() {
+			err := command.CheckFastFails([]string{"--director-allowed-cidrs", "198.51.100.0/24"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(terraformManager.ValidateVersionCall.CallCount).To(Equal(1))
+		})
+
+		It("requires a block to allow", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--director-allowed-cidrs or --lb-allowed-cidrs is required"))
+		})
+
+		It("returns an error when a block is not a CIDR block", func() {
+			err := command.CheckFastFails([]string{"--lb-allowed-cidrs", "203.0.113.0/24,office"}, state)
+			Expect(err).To(MatchError(`--lb-allowed-cidrs "office" is not an IPv4 CIDR block.`))
+		})
+
+		It("only updates aws environments", func() {
+			state.IAAS = "gcp"
+
+			err := command.CheckFastFails([]string{"--director-allowed-cidrs", "198.51.100.0/24"}, state)
+			Expect(err).To(MatchError("update-security-groups only updates the security groups of aws environments."))
+		})
+
+		It("accepts an environment whose terraform state was migrated to the vars directory", func() {
+			err := command.CheckFastFails([]string{"--director-allowed-cidrs", "198.51.100.0/24"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(terraformManager.IsPavedCall.CallCount).To(Equal(1))
+		})
+
+		It("needs an environment that is up", func() {
+			terraformManager.IsPavedCall.Returns.IsPaved = false
+
+			err := command.CheckFastFails([]string{"--director-allowed-cidrs", "198.51.100.0/24"}, state)
+			Expect(err).To(MatchError("The environment is not up yet. Pass --director-allowed-cidrs and --lb-allowed-cidrs to bbl plan or bbl up instead."))
+		})
+
+		It("returns an error when the infrastructure cannot be checked", func() {
+			terraformManager.IsPavedCall.Returns.Error = errors.New("pineapple")
+
+			err := command.CheckFastFails([]string{"--director-allowed-cidrs", "198.51.100.0/24"}, state)
+			Expect(err).To(MatchError("Check for existing infrastructure: pineapple"))
+		})
+
+		It("needs a load balancer for --lb-allowed-cidrs", func() {
+			state.LB = storage.LB{}
+
+			err := command.CheckFastFails([]string{"--lb-allowed-cidrs", "203.0.113.0/24"}, state)
+			Expect(err).To(MatchError("The environment has no load balancer to allow --lb-allowed-cidrs to."))
+		})
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			terraformManager.ApplyCall.Returns.BBLState = storage.State{
+				EnvID: "some-env",
+				AWS: storage.AWS{
+					DirectorAllowedCIDRs: []string{"198.51.100.0/24", "192.0.2.0/24"},
+					LBAllowedCIDRs:       []string{"203.0.113.0/24"},
+				},
+			}
+		})
+
+		It("records the blocks and applies the terraform of the environment", func() {
+			err := command.Execute(context.Background(), []string{"--director-allowed-cidrs", "198.51.100.7/24, 192.0.2.0/24"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.InitCall.Receives.BBLState.AWS.DirectorAllowedCIDRs).To(Equal([]string{"198.51.100.0/24", "192.0.2.0/24"}))
+			Expect(terraformManager.InitCall.Receives.BBLState.AWS.LBAllowedCIDRs).To(Equal([]string{"203.0.113.0/24"}))
+			Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(terraformManager.InitCall.Receives.BBLState))
+
+			Expect(stateStore.SetCall.CallCount).To(Equal(1))
+			Expect(stateStore.SetCall.Receives[0].State).To(Equal(terraformManager.ApplyCall.Returns.BBLState))
+
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("The director accepts connections from 198.51.100.0/24, 192.0.2.0/24 and the load balancers from 203.0.113.0/24."))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the terraform template cannot be written", func() {
+				terraformManager.InitCall.Returns.Error = errors.New("disk full")
+
+				err := command.Execute(context.Background(), []string{"--lb-allowed-cidrs", "0.0.0.0/0"}, state)
+				Expect(err).To(MatchError("Terraform manager init: disk full"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("saves the state when terraform apply fails", func() {
+				terraformManager.ApplyCall.Returns.Error = errors.New("apply failed")
+
+				err := command.Execute(context.Background(), []string{"--lb-allowed-cidrs", "0.0.0.0/0"}, state)
+				Expect(err).To(MatchError("apply failed"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+			})
+
+			It("returns an error when the state cannot be saved", func() {
+				stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+
+				err := command.Execute(context.Background(), []string{"--lb-allowed-cidrs", "0.0.0.0/0"}, state)
+				Expect(err).To(MatchError("Save state after terraform apply: disk full"))
+			})
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type upgradeDirectorConfig struct {
+	dryRun bool
+}
+
+// UpgradeDirector moves the director of an environment to the BOSH release,
+// CPI release and stemcell that this bbl pins. It compares them with the
+// versions recorded in the state when the director was last deployed, renders
+// the director manifest again and redeploys only the director, without
+// applying terraform.
+type UpgradeDirector struct {
+	stateValidator     stateValidator
+	boshManager        boshManager
+	terraformManager   terraformManager
+	cloudConfigManager cloudConfigManager
+	stateStore         stateStore
+	directorVerifier   directorVerifier
+	rollback           directorRollback
+	logger             logger
+}
+
+func NewUpgradeDirector(stateValidator stateValidator, boshManager boshManager, terraformManager terraformManager,
+	cloudConfigManager cloudConfigManager, stateStore stateStore, directorVerifier directorVerifier, logger logger) UpgradeDirector {
+	return UpgradeDirector{
+		stateValidator:     stateValidator,
+		boshManager:        boshManager,
+		terraformManager:   terraformManager,
+		cloudConfigManager: cloudConfigManager,
+		stateStore:         stateStore,
+		directorVerifier:   directorVerifier,
+		rollback:           directorRollback{boshManager: boshManager, stateStore: stateStore, logger: logger},
+		logger:             logger,
+	}
+}
+
+func (u UpgradeDirector) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseUpgradeDirectorArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = u.stateValidator.Validate()
+	if err != nil {
+		return fmt.Errorf("validate state: %s", err)
+	}
+
+	if state.NoDirector || state.BOSH.IsEmpty() {
+		return errors.New("upgrade-director needs an environment with a director. Run bbl up to create one.")
+	}
+
+	return nil
+}
+
+func (u UpgradeDirector) Execute(ctx context.Context, args []string, state storage.State) error {
+	config, err := parseUpgradeDirectorArgs(args)
+	if err != nil {
+		return err
+	}
+
+	target, err := bosh.DirectorVersions(state)
+	if err != nil {
+		return fmt.Errorf("Director versions: %s", err)
+	}
+
+	if state.DirectorVersions != nil && *state.DirectorVersions == target {
+		u.logger.Printf("The director already runs bosh %s, which this bbl pins. There is nothing to upgrade.\n", target.BOSH)
+		return nil
+	}
+
+	deployed := storage.DirectorVersions{BOSH: "unknown", CPI: "unknown", Stemcell: "unknown"}
+	if state.DirectorVersions != nil {
+		deployed = *state.DirectorVersions
+	}
+	u.printChange("bosh", deployed.BOSH, target.BOSH)
+	u.printChange("cpi", deployed.CPI, target.CPI)
+	u.printChange("stemcell", deployed.Stemcell, target.Stemcell)
+
+	if config.dryRun {
+		return nil
+	}
+
+	u.logger.Step("rendering the director manifest with the new versions")
+	err = u.boshManager.InitializeDirector(state)
+	if err != nil {
+		return fmt.Errorf("Render director manifest: %s", err)
+	}
+
+	terraformOutputs, err := u.terraformManager.GetOutputs()
+	if err != nil {
+		return fmt.Errorf("Parse terraform outputs: %s", err)
+	}
+
+	// What the director reports now is compared with what the upgraded
+	// director reports, as bbl up does, and it is rolled back to the
+	// artifacts it runs now when they do not match.
+	previousArtifacts := state.DeployedArtifacts
+	verify := true
+	snapshot, err := u.directorVerifier.Snapshot(state)
+	if err != nil {
+		u.logger.Printf("The director could not be inspected before the upgrade, so the upgrade will not be verified: %s\n", err)
+		verify = false
+	}
+
+	state, err = u.boshManager.CreateDirector(state, terraformOutputs)
+	switch err.(type) {
+	case bosh.ManagerCreateError:
+		bcErr := err.(bosh.ManagerCreateError)
+		if setErr := u.stateStore.Set(bcErr.State()); setErr != nil {
+			return fmt.Errorf("Save state after bosh director create error: %s, %s", err, setErr)
+		}
+		return fmt.Errorf("Upgrade bosh director: %s", err)
+	case error:
+		return fmt.Errorf("Upgrade bosh director: %s", err)
+	}
+
+	err = u.stateStore.Set(state)
+	if err != nil {
+		return fmt.Errorf("Save state after upgrade director: %s", err)
+	}
+
+	err = u.cloudConfigManager.Update(state)
+	if err != nil {
+		return fmt.Errorf("Update cloud config: %s", err)
+	}
+
+	if verify {
+		err = u.directorVerifier.Verify(state, snapshot)
+		if err != nil {
+			return u.rollback.verifyFailed(err, state, previousArtifacts, terraformOutputs)
+		}
+	}
+
+	u.logger.Printf("Upgraded the director to bosh %s.\n", target.BOSH)
+	return nil
+}
+
+func (u UpgradeDirector) printChange(artifact, from, to string) {
+	if from == to {
+		u.logger.Printf("%s: %s (unchanged)\n", artifact, to)
+		return
+	}
+	u.logger.Printf("%s: %s -> %s\n", artifact, from, to)
+}
+
+func parseUpgradeDirectorArgs(args []string) (upgradeDirectorConfig, error) {
+	var config upgradeDirectorConfig
+
+	upgradeFlags := flags.New("upgrade-director")
+	upgradeFlags.Bool(&config.dryRun, "dry-run", false)
+
+	err := upgradeFlags.Parse(args)
+	if err != nil {
+		return upgradeDirectorConfig{}, err
+	}
+
+	return config, nil
+}
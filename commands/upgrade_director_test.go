@@ -0,0 +1,245 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	"github.com/cloudfoundry/bosh-bootloader/verifier"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpgradeDirector", func() {
+	var (
+		stateValidator     *fakes.StateValidator
+		boshManager        *fakes.BOSHManager
+		terraformManager   *fakes.TerraformManager
+		cloudConfigManager *fakes.CloudConfigManager
+		stateStore         *fakes.StateStore
+		directorVerifier   *fakes.DirectorVerifier
+		logger             *fakes.Logger
+		command            commands.UpgradeDirector
+
+		state    storage.State
+		upgraded storage.State
+		pinned   storage.DirectorVersions
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		boshManager = &fakes.BOSHManager{}
+		terraformManager = &fakes.TerraformManager{}
+		cloudConfigManager = &fakes.CloudConfigManager{}
+		stateStore = &fakes.StateStore{}
+		directorVerifier = &fakes.DirectorVerifier{}
+		logger = &fakes.Logger{}
+		command = commands.NewUpgradeDirector(stateValidator, boshManager, terraformManager, cloudConfigManager, stateStore, directorVerifier, logger)
+
+		var err error
+		pinned, err = bosh.DirectorVersions(storage.State{IAAS: "aws"})
+		Expect(err).NotTo(HaveOccurred())
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env-id",
+			BOSH: storage.BOSH{
+				DirectorAddress:  "https://10.0.0.6:25555",
+				DirectorPassword: "some-password",
+			},
+			DirectorVersions: &storage.DirectorVersions{BOSH: "200.0.0", CPI: pinned.CPI, Stemcell: "3000.1"},
+		}
+
+		upgraded = state
+		upgraded.DirectorVersions = &pinned
+		boshManager.CreateDirectorCall.Returns.State = upgraded
+		terraformManager.GetOutputsCall.Returns.Outputs = terraform.Outputs{Map: map[string]interface{}{"some-key": "some-value"}}
+		directorVerifier.SnapshotCall.Returns.Snapshot = verifier.Snapshot{Version: "200.0.0"}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when the state validator fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("coconut")
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("validate state: coconut"))
+		})
+
+		It("returns an error for an environment without a director", func() {
+			state.NoDirector = true
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("upgrade-director needs an environment with a director. Run bbl up to create one."))
+		})
+
+		It("returns an error for an unknown flag", func() {
+			err := command.CheckFastFails([]string{"--banana"}, state)
+			Expect(err).To(MatchError(ContainSubstring("banana")))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("renders the director manifest, redeploys the director and saves the new versions", func() {
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("bosh: 200.0.0 -> " + pinned.BOSH + "\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("cpi: " + pinned.CPI + " (unchanged)\n"))
+			Expect(logger.PrintfCall.Messages).To(ContainElement("stemcell: 3000.1 -> " + pinned.Stemcell + "\n"))
+
+			Expect(boshManager.InitializeDirectorCall.Receives.State).To(Equal(state))
+			Expect(directorVerifier.SnapshotCall.Receives.State).To(Equal(state))
+			Expect(boshManager.CreateDirectorCall.Receives.State).To(Equal(state))
+			Expect(boshManager.CreateDirectorCall.Receives.TerraformOutputs).To(Equal(terraformManager.GetOutputsCall.Returns.Outputs))
+			Expect(stateStore.SetCall.Receives[0].State).To(Equal(upgraded))
+			Expect(cloudConfigManager.UpdateCall.Receives.State).To(Equal(upgraded))
+			Expect(directorVerifier.VerifyCall.Receives.State).To(Equal(upgraded))
+			Expect(directorVerifier.VerifyCall.Receives.Snapshot).To(Equal(verifier.Snapshot{Version: "200.0.0"}))
+		})
+
+		It("upgrades a director whose versions were not recorded", func() {
+			state.DirectorVersions = nil
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("bosh: unknown -> " + pinned.BOSH + "\n"))
+			Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(1))
+		})
+
+		It("does nothing when the director runs the pinned versions", func() {
+			state.DirectorVersions = &pinned
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"The director already runs bosh " + pinned.BOSH + ", which this bbl pins. There is nothing to upgrade.\n",
+			}))
+			Expect(boshManager.InitializeDirectorCall.CallCount).To(Equal(0))
+			Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+		})
+
+		It("only prints the versions with --dry-run", func() {
+			err := command.Execute(context.Background(), []string{"--dry-run"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintfCall.Messages).To(ContainElement("bosh: 200.0.0 -> " + pinned.BOSH + "\n"))
+			Expect(boshManager.InitializeDirectorCall.CallCount).To(Equal(0))
+			Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+			Expect(stateStore.SetCall.CallCount).To(Equal(0))
+		})
+
+		It("upgrades without verifying when the director cannot be inspected", func() {
+			directorVerifier.SnapshotCall.Returns.Error = errors.New("unreachable")
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(1))
+			Expect(directorVerifier.VerifyCall.CallCount).To(Equal(0))
+		})
+
+		Context("when an error occurs", func() {
+			It("returns an error when the manifest cannot be rendered", func() {
+				boshManager.InitializeDirectorCall.Returns.Error = errors.New("pineapple")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Render director manifest: pineapple"))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the terraform outputs cannot be read", func() {
+				terraformManager.GetOutputsCall.Returns.Error = errors.New("pineapple")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Parse terraform outputs: pineapple"))
+			})
+
+			It("saves the state of a failed create env and returns an error", func() {
+				failedState := storage.State{EnvID: "failed-env"}
+				boshManager.CreateDirectorCall.Returns.Error = bosh.NewManagerCreateError(failedState, errors.New("pineapple"))
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Upgrade bosh director: pineapple"))
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(failedState))
+			})
+
+			It("returns an error when the cloud config cannot be updated", func() {
+				cloudConfigManager.UpdateCall.Returns.Error = errors.New("pineapple")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Update cloud config: pineapple"))
+			})
+
+			It("returns an error when the upgrade cannot be verified", func() {
+				directorVerifier.VerifyCall.Returns.Error = errors.New("deployments are missing")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Verify director upgrade: deployments are missing. Roll back director: the artifacts of the previous director were not recorded. The previous director can be restored by running bbl up with the bbl version that deployed it."))
+			})
+		})
+
+		Context("when the upgrade cannot be verified", func() {
+			var previous storage.ArtifactOverrides
+
+			BeforeEach(func() {
+				previous = storage.ArtifactOverrides{BOSHReleaseURL: "some-old-bosh-url", BOSHReleaseSHA1: "some-old-bosh-sha1"}
+				state.DeployedArtifacts = &previous
+				state.ArtifactOverrides = &storage.ArtifactOverrides{StemcellURL: "some-stemcell-url", StemcellSHA1: "some-stemcell-sha1"}
+
+				upgraded.ArtifactOverrides = state.ArtifactOverrides
+				upgraded.DeployedArtifacts = &storage.ArtifactOverrides{BOSHReleaseURL: "some-new-bosh-url", BOSHReleaseSHA1: "some-new-bosh-sha1"}
+				rolledBack := upgraded
+				rolledBack.DirectorVersions = &storage.DirectorVersions{BOSH: "200.0.0"}
+				boshManager.CreateDirectorCall.Stub = func(storage.State) (storage.State, error) {
+					if boshManager.CreateDirectorCall.CallCount == 2 {
+						return rolledBack, nil
+					}
+					return upgraded, nil
+				}
+
+				directorVerifier.VerifyCall.Returns.Error = errors.New("deployments are missing")
+			})
+
+			It("rolls the director back to the artifacts it ran before the upgrade", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Verify director upgrade: deployments are missing. The director was rolled back to bosh 200.0.0. Run bbl up to try the upgrade again."))
+
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(2))
+				Expect(boshManager.CreateDirectorCall.Receives.State.ArtifactOverrides).To(Equal(&previous))
+				Expect(boshManager.CreateDirectorCall.Receives.TerraformOutputs).To(Equal(terraformManager.GetOutputsCall.Returns.Outputs))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+				rolledBack := stateStore.SetCall.Receives[1].State
+				Expect(rolledBack.DirectorVersions).To(Equal(&storage.DirectorVersions{BOSH: "200.0.0"}))
+				Expect(rolledBack.ArtifactOverrides).To(Equal(state.ArtifactOverrides))
+
+				Expect(boshManager.InitializeDirectorCall.CallCount).To(Equal(3))
+				Expect(boshManager.InitializeDirectorCall.Receives.State).To(Equal(rolledBack))
+			})
+
+			It("does not roll back a director that runs the artifacts it ran before", func() {
+				upgraded.DeployedArtifacts = &previous
+				boshManager.CreateDirectorCall.Stub = nil
+				boshManager.CreateDirectorCall.Returns.State = upgraded
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Verify director upgrade: deployments are missing. Roll back director: the director already runs the artifacts it ran before the upgrade. The previous director can be restored by running bbl up with the bbl version that deployed it."))
+				Expect(boshManager.CreateDirectorCall.CallCount).To(Equal(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type CertificateUploader interface {
+	UploadServerCertificate(name string, cert, key, chain []byte) (string, bool, error)
+	DeleteServerCertificate(name string) error
+}
+
+type uploadCertificateConfig struct {
+	certPath  string
+	keyPath   string
+	chainPath string
+}
+
+// UploadCertificate uploads a certificate to IAM and records it in the
+// state, attached to the cf router load balancer but without changing the
+// load balancers, so that the certificate can be rotated ahead of the bbl plan
+// and bbl up that switch the cf load balancer over to it.
+type UploadCertificate struct {
+	stateValidator       stateValidator
+	certificateValidator certificateValidator
+	certificateUploader  CertificateUploader
+	stateStore           stateStore
+	logger               logger
+}
+
+func NewUploadCertificate(stateValidator stateValidator, certificateValidator certificateValidator, certificateUploader CertificateUploader,
+	stateStore stateStore, logger logger) UploadCertificate {
+	return UploadCertificate{
+		stateValidator:       stateValidator,
+		certificateValidator: certificateValidator,
+		certificateUploader:  certificateUploader,
+		stateStore:           stateStore,
+		logger:               logger,
+	}
+}
+
+func (u UploadCertificate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseUploadCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = u.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("upload-certificate only uploads certificates to IAM for aws environments.")
+	}
+
+	if err := checkNotLite("upload-certificate", state); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u UploadCertificate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseUploadCertificateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	certData, err := u.certificateValidator.ReadAndValidate(config.certPath, config.keyPath, config.chainPath)
+	if err != nil {
+		return bblerrors.New(bblerrors.Validation, fmt.Errorf("Validate certificate: %s", err))
+	}
+
+	fingerprint, err := certs.Fingerprint(certData.Cert)
+	if err != nil {
+		return err //not tested
+	}
+
+	// Naming the certificate after its fingerprint makes uploading it again
+	// return the certificate that is already there.
+	name := fmt.Sprintf("%s-%s", state.EnvID, strings.ToLower(strings.Replace(fingerprint, ":", "", -1))[:16])
+
+	arn, err := storeCertificate(u.certificateUploader, u.stateStore, name, certData, func(arn string) storage.State {
+		if certificate := state.AWS.CertificateNamed(name); certificate != nil {
+			certificate.ARN = arn
+		} else {
+			state.AWS.Certificates = append(state.AWS.Certificates, storage.ServerCertificate{
+				Name:        name,
+				ARN:         arn,
+				Fingerprint: fingerprint,
+			})
+		}
+		state.AWS.AttachCertificate(name, "cf-router")
+		return state
+	})
+	if err != nil {
+		return err
+	}
+
+	u.logger.Println(fmt.Sprintf("Uploaded the certificate %s as %s. Run bbl plan --lb-type cf and bbl up to use it for the cf load balancer.", fingerprint, arn))
+	return nil
+}
+
+// storeCertificate uploads a certificate to IAM as name and saves the state
+// that record returns with its ARN.
+func storeCertificate(uploader CertificateUploader, store stateStore, name string, certData certs.CertData, record func(arn string) storage.State) (string, error) {
+	arn, created, err := uploader.UploadServerCertificate(name, certData.Cert, certData.Key, certData.Chain)
+	if err != nil {
+		return "", err
+	}
+
+	err = store.Set(record(arn))
+	if err != nil {
+		// The state keeps the previous certificates, so a certificate that
+		// nothing refers to is deleted again. One that existed before may be
+		// in use.
+		if created {
+			if deleteErr := uploader.DeleteServerCertificate(name); deleteErr != nil {
+				return "", fmt.Errorf("Save state: %s. The uploaded certificate %s could not be deleted either, delete it from IAM: %s", err, name, deleteErr)
+			}
+		}
+		return "", fmt.Errorf("Save state: %s", err)
+	}
+
+	return arn, nil
+}
+
+func parseUploadCertificateArgs(args []string) (uploadCertificateConfig, error) {
+	var config uploadCertificateConfig
+
+	uploadFlags := flags.New("upload-certificate")
+	uploadFlags.String(&config.certPath, "lb-cert", "")
+	uploadFlags.String(&config.keyPath, "lb-key", "")
+	uploadFlags.String(&config.chainPath, "lb-chain", "")
+
+	err := uploadFlags.Parse(args)
+	if err != nil {
+		return uploadCertificateConfig{}, err
+	}
+
+	if config.certPath == "" || config.keyPath == "" {
+		return uploadCertificateConfig{}, errors.New("--lb-cert and --lb-key are required")
+	}
+
+	return config, nil
+}
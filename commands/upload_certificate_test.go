@@ -0,0 +1,174 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/certs"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UploadCertificate", func() {
+	var (
+		stateValidator       *fakes.StateValidator
+		certificateValidator *fakes.CertificateValidator
+		certificateUploader  *fakes.CertificateUploader
+		stateStore           *fakes.StateStore
+		logger               *fakes.Logger
+		command              commands.UploadCertificate
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		stateValidator = &fakes.StateValidator{}
+		certificateValidator = &fakes.CertificateValidator{}
+		certificateValidator.ReadAndValidateCall.Returns.CertData = certs.CertData{
+			Cert:  []byte(testhelpers.BBL_CERT),
+			Key:   []byte("some-key"),
+			Chain: []byte("some-chain"),
+		}
+		certificateUploader = &fakes.CertificateUploader{}
+		certificateUploader.UploadServerCertificateCall.Returns.ARN = "arn:aws:iam::123456789012:server-certificate/some-env-475fcfe6f4b01a10"
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+		command = commands.NewUploadCertificate(stateValidator, certificateValidator, certificateUploader, stateStore, logger)
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env",
+			LB:    storage.LB{Type: "cf", Cert: "old-cert", Key: "old-key"},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state", func() {
+			err := command.CheckFastFails([]string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+		})
+
+		It("requires the certificate and the key", func() {
+			err := command.CheckFastFails([]string{"--lb-cert", "cert"}, state)
+			Expect(err).To(MatchError("--lb-cert and --lb-key are required"))
+		})
+
+		It("only uploads certificates of aws environments", func() {
+			state.IAAS = "gcp"
+
+			err := command.CheckFastFails([]string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+			Expect(err).To(MatchError("upload-certificate only uploads certificates to IAM for aws environments."))
+		})
+
+		It("does not apply to bosh-lite environments", func() {
+			state.AWS.Lite = true
+
+			err := command.CheckFastFails([]string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+			Expect(err).To(MatchError("upload-certificate does not apply to bosh-lite environments, which have no load balancers."))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("uploads the certificate and records it in the state without changing the load balancer", func() {
+			err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key", "--lb-chain", "chain"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateValidator.ReadAndValidateCall.Receives.CertificatePath).To(Equal("cert"))
+			Expect(certificateValidator.ReadAndValidateCall.Receives.KeyPath).To(Equal("key"))
+			Expect(certificateValidator.ReadAndValidateCall.Receives.ChainPath).To(Equal("chain"))
+
+			Expect(certificateUploader.UploadServerCertificateCall.Receives.Name).To(Equal("some-env-475fcfe6f4b01a10"))
+			Expect(certificateUploader.UploadServerCertificateCall.Receives.Cert).To(Equal([]byte(testhelpers.BBL_CERT)))
+			Expect(certificateUploader.UploadServerCertificateCall.Receives.Key).To(Equal([]byte("some-key")))
+			Expect(certificateUploader.UploadServerCertificateCall.Receives.Chain).To(Equal([]byte("some-chain")))
+
+			saved := stateStore.SetCall.Receives[0].State
+			Expect(saved.AWS.Certificates).To(Equal([]storage.ServerCertificate{{
+				Name:        "some-env-475fcfe6f4b01a10",
+				ARN:         "arn:aws:iam::123456789012:server-certificate/some-env-475fcfe6f4b01a10",
+				Fingerprint: "47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A",
+				LBs:         []string{"cf-router"},
+			}}))
+			Expect(saved.LB).To(Equal(state.LB))
+
+			Expect(logger.PrintlnCall.Messages).To(ConsistOf("Uploaded the certificate 47:5F:CF:E6:F4:B0:1A:10:71:74:10:21:A6:9E:84:55:9D:33:4E:7D:1E:7C:CA:51:8C:27:D3:3B:D8:B9:1B:0A as arn:aws:iam::123456789012:server-certificate/some-env-475fcfe6f4b01a10. Run bbl plan --lb-type cf and bbl up to use it for the cf load balancer."))
+		})
+
+		It("attaches the certificate to the cf router in place of the one before", func() {
+			state.AWS.Certificates = []storage.ServerCertificate{{Name: "some-env-0000000000000000", ARN: "old-arn", LBs: []string{"cf-router"}}}
+
+			err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			saved := stateStore.SetCall.Receives[0].State
+			Expect(saved.AWS.Certificates).To(HaveLen(2))
+			Expect(saved.AWS.Certificates[0].LBs).To(BeNil())
+			Expect(saved.AWS.AttachedCertificate("cf-router").Name).To(Equal("some-env-475fcfe6f4b01a10"))
+		})
+
+		Describe("failure cases", func() {
+			It("returns an error when the certificate is not valid", func() {
+				certificateValidator.ReadAndValidateCall.Returns.Error = errors.New("certificate expired on 2018-05-26")
+
+				err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+				Expect(err).To(MatchError("Validate certificate: certificate expired on 2018-05-26"))
+				Expect(certificateUploader.UploadServerCertificateCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the certificate cannot be uploaded", func() {
+				certificateUploader.UploadServerCertificateCall.Returns.Error = errors.New("MalformedCertificate")
+
+				err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+				Expect(err).To(MatchError("MalformedCertificate"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				Expect(certificateUploader.DeleteServerCertificateCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error without uploading anything when the certificate cannot be fingerprinted", func() {
+				certificateValidator.ReadAndValidateCall.Returns.CertData = certs.CertData{Cert: []byte("not a certificate")}
+
+				err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+				Expect(err).To(HaveOccurred())
+				Expect(certificateUploader.UploadServerCertificateCall.CallCount).To(Equal(0))
+			})
+
+			Context("when the state cannot be saved", func() {
+				BeforeEach(func() {
+					stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("disk full")}}
+				})
+
+				It("deletes the certificate it uploaded", func() {
+					certificateUploader.UploadServerCertificateCall.Returns.Created = true
+
+					err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+					Expect(err).To(MatchError("Save state: disk full"))
+
+					Expect(certificateUploader.DeleteServerCertificateCall.CallCount).To(Equal(1))
+					Expect(certificateUploader.DeleteServerCertificateCall.Receives.Name).To(Equal("some-env-475fcfe6f4b01a10"))
+				})
+
+				It("keeps a certificate that was uploaded before", func() {
+					err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+					Expect(err).To(MatchError("Save state: disk full"))
+
+					Expect(certificateUploader.DeleteServerCertificateCall.CallCount).To(Equal(0))
+				})
+
+				It("names the certificate when it cannot be deleted either", func() {
+					certificateUploader.UploadServerCertificateCall.Returns.Created = true
+					certificateUploader.DeleteServerCertificateCall.Returns.Error = errors.New("Delete server certificate some-env-475fcfe6f4b01a10: Throttling")
+
+					err := command.Execute(context.Background(), []string{"--lb-cert", "cert", "--lb-key", "key"}, state)
+					Expect(err).To(MatchError("Save state: disk full. The uploaded certificate some-env-475fcfe6f4b01a10 could not be deleted either, delete it from IAM: Delete server certificate some-env-475fcfe6f4b01a10: Throttling"))
+				})
+			})
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -14,10 +15,24 @@ Usage:
 
 Global Options:
   --help       [-h]        Prints usage. Use "bbl [command] --help" for more information about a command
+  --help-examples          Prints examples of a command, for example: bbl up --help-examples
   --state-dir  [-s]        Directory containing the bbl state                                            env:"BBL_STATE_DIRECTORY"
+  --state-format           State file format: "json" (default) or "yaml"                                 env:"BBL_STATE_FORMAT"
+  --env                    Named environment of the state directory, kept in its envs directory          env:"BBL_ENV"
   --debug      [-d]        Prints debugging output                                                       env:"BBL_DEBUG"
   --version    [-v]        Prints version
   --no-confirm [-n]        No confirm
+  --json                   Prints the output of informational commands as JSON                           env:"BBL_JSON"
+  --no-wait                Runs commands that change the environment in the background. See bbl status   env:"BBL_NO_WAIT"
+  --no-cache               Looks up availability zones again instead of using the ones cached            env:"BBL_NO_CACHE"
+  --lang                   Language of bbl's messages, for example: ja. Defaults to en                   env:"BBL_LANG"
+  --state-passphrase       Passphrase of an encrypted state. See bbl state                               env:"BBL_STATE_PASSPHRASE"
+  --override-account-check Runs commands with AWS credentials of another account than the environment's  env:"BBL_OVERRIDE_ACCOUNT_CHECK"
+  --health-listen          Serves the phase and recent events of the running command on a local address  env:"BBL_HEALTH_LISTEN"
+  --profile-run            Writes the timing of each step and AWS request of the command to a JSON file  env:"BBL_PROFILE_RUN"
+  --proxy-url              Sends the requests of bbl, terraform and bosh through an HTTP proxy           env:"BBL_PROXY_URL"
+  --timeout                Stops the command after a duration, for example: 90m. See exit code 7         env:"BBL_TIMEOUT"
+  --config                 Reads global flags from a YAML file, by default bbl.yml if it exists          env:"BBL_CONFIG"
 %s
 `
 	CommandUsage = `
@@ -29,12 +44,36 @@ const GlobalUsage = `
 Basic Commands: A good place to start
   up                      Deploys BOSH director on an IAAS, creates CF/Concourse load balancers. Updates existing director.
   print-env               All environment variables needed for targeting BOSH. Use with: eval "$(bbl print-env)"
+  apply                   Converges the environment to the one described in a YAML file
 
 Maintenance Lifecycle Commands:
   destroy                 Tears down BOSH director infrastructure. Cleans up state directory
   rotate                  Rotates SSH key for the jumpbox user
+  rotate-keypair          Rotates the EC2 key pair for the director and its VMs
+  rotate-director-credentials Rotates the passwords and SSL certificate of the director
+  migrate-region          Moves an AWS environment to another region
+  detach-lb               Moves the cf load balancer of an AWS environment out of it, for another environment to adopt
+  adopt-lb                Moves a load balancer that detach-lb moved out of an environment into this one
+  bootstrap-account       Creates account-wide prerequisites, such as the load balancing service-linked role, in a fresh AWS account
+  copy-stemcell-ami       Copies the stemcell AMI of the director into the region of an AWS environment, ahead of bbl up
+  upload-certificate      Uploads a certificate to IAM for the cf load balancer of an AWS environment, without changing the load balancer
+  create-certificate      Uploads a certificate to IAM under a name, for bbl attach-certificate
+  attach-certificate      Attaches a named certificate to a load balancer of an AWS environment, for example: --lb cf-router --name foo
+  rotate-certificate      Switches the TLS listeners of a cf load balancer of an AWS environment to a new certificate at once, without bbl up
+  update-security-groups  Changes the blocks that may reach the jumpbox, director and load balancers of an AWS environment
+  pin-artifacts           Pins newer BOSH, CPI and stemcell versions from bosh.io for the director, for example: --bosh 270.x --stemcell latest
+  download-artifacts      Downloads the releases and stemcells of the jumpbox and director, for bbl plan --artifacts-dir without internet access
   plan                    Populates a state directory with the latest config without applying it
+  validate                Checks the credentials, permissions, names and network access that bbl up needs, without creating anything
+  pre-upgrade-check       Checks that this bbl can upgrade the environment, and lists the releases to upgrade with first
+  upgrade-director        Redeploys the director with the releases and stemcell that this bbl pins, without applying terraform
+  clone                   Creates a new environment with the configuration of an existing one
   cleanup-leftovers       Cleans up orphaned IAAS resources
+  migrate-commands        Finds removed bbl commands in scripts and prints their replacements
+  status                  Prints the commands that --no-wait runs in the background
+  wait                    Waits for a command that --no-wait runs in the background, for example: bbl wait <operation-id>
+  state                   Encrypts or decrypts the credentials in the state file, or restores a backup of it, for example: bbl state encrypt
+  annotate                Records metadata about the environment, for example: bbl annotate owner=platform-team
 
 Environmental Detail Commands: Useful for automation and gaining access
   jumpbox-address         Prints BOSH jumpbox address
@@ -42,16 +81,30 @@ Environmental Detail Commands: Useful for automation and gaining access
   director-username       Prints BOSH director username
   director-password       Prints BOSH director password
   director-ca-cert        Prints BOSH director CA certificate
+  credhub-server          Prints the address of the CredHub of the director
+  credhub-client          Prints the UAA client of the CredHub of the director
+  credhub-password        Prints the secret of the UAA client of the CredHub of the director
+  credhub-ca-cert         Prints the CA certificates of the CredHub and UAA of the director
   env-id                  Prints environment ID
   ssh-key                 Prints jumpbox SSH private key
   director-ssh-key        Prints director SSH private key
+  escrow                  Seals the SSH keys and director credentials for PGP recipients, for example: bbl escrow --recipients pgp-keys/
+  cloud-config            Prints the cloud config that bbl uploads to the director
+  ssh-cert                Issues a short-lived SSH certificate, for example: bbl ssh-cert issue --ttl 8h --public-key ~/.ssh/id_rsa.pub
+  ssh                     Opens an SSH session, for example: bbl ssh --director --cmd "sudo monit summary"
   lbs                     Prints load balancer(s) and DNS records
   outputs                 Prints the outputs from terraform
+  bosh-deployment-vars    Prints the vars and credentials for bosh create-env of the bosh-deployment repo, for creating the director yourself
+  curl                    Sends a request to the BOSH director API, for example: bbl curl /deployments
+  annotations             Prints the annotations of the environment
 
 Troubleshooting Commands:
   help                    Prints usage
+  man                     Prints the manual of bbl or a command, for example: bbl man up
+  completion              Prints a completion script for a shell, for example: source <(bbl completion bash)
   version                 Prints version
-  latest-error            Prints the output from the latest call to terraform`
+  latest-error            Prints the output from the latest call to terraform
+  bench                   Times the steps that bbl runs locally, for example: bbl bench --iterations 50`
 
 type Usage struct {
 	logger logger
@@ -67,7 +120,7 @@ func (u Usage) CheckFastFails(subcommandFlags []string, state storage.State) err
 	return nil
 }
 
-func (u Usage) Execute(subcommandFlags []string, state storage.State) error {
+func (u Usage) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	u.Print()
 	return nil
 }
@@ -82,3 +135,17 @@ func (u Usage) PrintCommandUsage(command, message string) {
 	content := fmt.Sprintf(UsageHeader, command, commandUsage)
 	u.logger.Println(strings.TrimLeft(content, "\n"))
 }
+
+func (u Usage) PrintCommandExamples(command string) {
+	commandExamples := Examples(command)
+	if len(commandExamples) == 0 {
+		u.logger.Println(fmt.Sprintf("There are no examples for %s.", command))
+		return
+	}
+
+	lines := []string{"Examples:"}
+	for _, example := range commandExamples {
+		lines = append(lines, fmt.Sprintf("  # %s", example.Description), fmt.Sprintf("  %s", example.Command), "")
+	}
+	u.logger.Println(strings.Join(lines[:len(lines)-1], "\n"))
+}
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fileio"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	checkPassed  = "pass"
+	checkFailed  = "fail"
+	checkSkipped = "skip"
+)
+
+// reachabilityURLs are the hosts that bosh create-env downloads the releases
+// and stemcells of the jumpbox and director from.
+var reachabilityURLs = []string{"https://bosh.io", "https://s3.amazonaws.com"}
+
+type PreflightClient interface {
+	CallerIdentity() (string, string, error)
+	RegionExists(region string) (bool, error)
+	DeniedActions(callerARN string, actions []string) ([]string, error)
+	ConflictingResources(envID string) ([]string, error)
+}
+
+type validateStateStore interface {
+	GetStateDir() string
+}
+
+type validateFS interface {
+	fileio.FileWriter
+	fileio.Remover
+}
+
+type validateConfig struct {
+	name string
+}
+
+type validateCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Validate checks what bbl up needs before it creates anything: that the
+// state directory is writable, that the downloads of bosh create-env can be
+// reached, and on AWS that the credentials are valid, the region exists, the
+// policies of the credentials allow the actions of bbl up, and that no
+// resources have the names of a new environment. It fails when a check
+// fails.
+type Validate struct {
+	preflightClient PreflightClient
+	httpClient      httpGetter
+	stateStore      validateStateStore
+	fs              validateFS
+	output          OutputFormatter
+}
+
+func NewValidate(preflightClient PreflightClient, httpClient httpGetter, stateStore validateStateStore, fs validateFS, output OutputFormatter) Validate {
+	return Validate{
+		preflightClient: preflightClient,
+		httpClient:      httpClient,
+		stateStore:      stateStore,
+		fs:              fs,
+		output:          output,
+	}
+}
+
+func (v Validate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, err := parseValidateArgs(subcommandFlags)
+	return err
+}
+
+func (v Validate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseValidateArgs(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	checks := []validateCheck{v.checkStateDir()}
+	if state.IAAS == "aws" {
+		checks = append(checks, v.checkAWS(state, config.name)...)
+	}
+	for _, url := range reachabilityURLs {
+		checks = append(checks, v.checkReachable(url))
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == checkFailed {
+			failed++
+		}
+	}
+
+	if v.output.JSON() {
+		err = v.output.PrintJSON(map[string]interface{}{"checks": checks, "passed": failed == 0})
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, check := range checks {
+			v.output.Printf("%s  %-22s %s\n", strings.ToUpper(check.Status), check.Name, check.Detail)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of the checks of bbl validate failed. Fix them before bbl up.", failed)
+	}
+	return nil
+}
+
+func (v Validate) checkStateDir() validateCheck {
+	check := validateCheck{Name: "state directory"}
+
+	stateDir := v.stateStore.GetStateDir()
+	probe := filepath.Join(stateDir, ".bbl-validate")
+	err := v.fs.WriteFile(probe, []byte{}, storage.OS_READ_WRITE_MODE)
+	if err != nil {
+		return failCheck(check, fmt.Sprintf("%s is not writable: %s", stateDir, err))
+	}
+	v.fs.Remove(probe)
+
+	return passCheck(check, fmt.Sprintf("%s is writable", stateDir))
+}
+
+// checkAWS checks the credentials and what they can do. The checks after
+// the one of the credentials are skipped when the credentials are not
+// valid.
+func (v Validate) checkAWS(state storage.State, name string) []validateCheck {
+	credentials := validateCheck{Name: "aws credentials"}
+	region := validateCheck{Name: "aws region"}
+	permissions := validateCheck{Name: "aws permissions"}
+	names := validateCheck{Name: "resource names"}
+
+	account, arn, err := v.preflightClient.CallerIdentity()
+	if err != nil {
+		skipped := "the credentials are not valid"
+		return []validateCheck{
+			failCheck(credentials, err.Error()),
+			skipCheck(region, skipped),
+			skipCheck(permissions, skipped),
+			skipCheck(names, skipped),
+		}
+	}
+
+	return []validateCheck{
+		passCheck(credentials, fmt.Sprintf("account %s as %s", account, arn)),
+		v.checkRegion(region, state.AWS.Region),
+		v.checkPermissions(permissions, arn),
+		v.checkNames(names, state, name),
+	}
+}
+
+func (v Validate) checkRegion(check validateCheck, region string) validateCheck {
+	exists, err := v.preflightClient.RegionExists(region)
+	switch {
+	case err != nil:
+		return failCheck(check, fmt.Sprintf("list regions: %s", err))
+	case !exists:
+		return failCheck(check, fmt.Sprintf("%s is not a region of the account", region))
+	}
+
+	return passCheck(check, fmt.Sprintf("%s exists", region))
+}
+
+// checkPermissions simulates the policies of the credentials. The root user
+// is allowed every action and has no policies to simulate, and credentials
+// that may not simulate policies are not checked.
+func (v Validate) checkPermissions(check validateCheck, arn string) validateCheck {
+	if strings.HasSuffix(arn, ":root") {
+		return passCheck(check, "the root user is allowed every action")
+	}
+
+	denied, err := v.preflightClient.DeniedActions(arn, aws.RequiredActions)
+	switch {
+	case err != nil:
+		return skipCheck(check, fmt.Sprintf("could not simulate the policies of %s, which needs iam:SimulatePrincipalPolicy: %s", arn, err))
+	case len(denied) > 0:
+		return failCheck(check, fmt.Sprintf("the policies of %s do not allow %s", arn, strings.Join(denied, ", ")))
+	}
+
+	return passCheck(check, fmt.Sprintf("the policies of %s allow the actions of bbl up", arn))
+}
+
+// checkNames looks for resources with the names of a new environment. The
+// resources of an existing environment have those names already.
+func (v Validate) checkNames(check validateCheck, state storage.State, name string) validateCheck {
+	switch {
+	case state.EnvID != "":
+		return skipCheck(check, fmt.Sprintf("the environment %s exists already", state.EnvID))
+	case name == "":
+		return skipCheck(check, "bbl plan generates a name. Pass --name to check the name you will plan with")
+	}
+
+	conflicts, err := v.preflightClient.ConflictingResources(name)
+	switch {
+	case err != nil:
+		return failCheck(check, err.Error())
+	case len(conflicts) > 0:
+		return failCheck(check, fmt.Sprintf("these resources have the names of %s already: %s", name, strings.Join(conflicts, ", ")))
+	}
+
+	return passCheck(check, fmt.Sprintf("no resources have the names of %s", name))
+}
+
+// checkReachable requests url. Any response, even an error status, shows
+// that the host can be reached.
+func (v Validate) checkReachable(url string) validateCheck {
+	check := validateCheck{Name: fmt.Sprintf("reach %s", strings.TrimPrefix(url, "https://"))}
+
+	response, err := v.httpClient.Get(url)
+	if err != nil {
+		return failCheck(check, err.Error())
+	}
+	response.Body.Close()
+
+	return passCheck(check, fmt.Sprintf("%s responded", url))
+}
+
+func passCheck(check validateCheck, detail string) validateCheck {
+	check.Status, check.Detail = checkPassed, detail
+	return check
+}
+
+func failCheck(check validateCheck, detail string) validateCheck {
+	check.Status, check.Detail = checkFailed, detail
+	return check
+}
+
+func skipCheck(check validateCheck, detail string) validateCheck {
+	check.Status, check.Detail = checkSkipped, detail
+	return check
+}
+
+func parseValidateArgs(args []string) (validateConfig, error) {
+	var config validateConfig
+
+	validateFlags := flags.New("validate")
+	validateFlags.String(&config.name, "name", "")
+
+	err := validateFlags.Parse(args)
+	if err != nil {
+		return validateConfig{}, err
+	}
+
+	return config, nil
+}
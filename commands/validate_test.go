@@ -0,0 +1,209 @@
+package commands_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	var (
+		preflightClient *fakes.PreflightClient
+		httpClient      *fakes.HTTPGetter
+		stateStore      *fakes.StateStore
+		fileIO          *fakes.FileIO
+		logger          *fakes.Logger
+		command         commands.Validate
+
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		preflightClient = &fakes.PreflightClient{}
+		preflightClient.CallerIdentityCall.Returns.Account = "123456789012"
+		preflightClient.CallerIdentityCall.Returns.ARN = "arn:aws:iam::123456789012:user/bbl"
+		preflightClient.RegionExistsCall.Returns.Exists = true
+
+		httpClient = &fakes.HTTPGetter{}
+		httpClient.GetCall.Stub = func(string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}
+
+		stateStore = &fakes.StateStore{}
+		stateStore.GetStateDirCall.Returns.Directory = "/some/state-dir"
+		fileIO = &fakes.FileIO{}
+		logger = &fakes.Logger{}
+
+		command = commands.NewValidate(preflightClient, httpClient, stateStore, fileIO, commands.NewOutputFormatter(logger, false))
+
+		state = storage.State{
+			IAAS: "aws",
+			AWS:  storage.AWS{Region: "eu-west-1"},
+		}
+	})
+
+	Describe("Execute", func() {
+		It("prints the checks of an aws environment", func() {
+			err := command.Execute(context.Background(), []string{"--name", "some-env"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal("/some/state-dir/.bbl-validate"))
+			Expect(fileIO.RemoveCall.Receives[0].Name).To(Equal("/some/state-dir/.bbl-validate"))
+			Expect(preflightClient.RegionExistsCall.Receives.Region).To(Equal("eu-west-1"))
+			Expect(preflightClient.DeniedActionsCall.Receives.CallerARN).To(Equal("arn:aws:iam::123456789012:user/bbl"))
+			Expect(preflightClient.DeniedActionsCall.Receives.Actions).To(Equal(aws.RequiredActions))
+			Expect(preflightClient.ConflictingResourcesCall.Receives.EnvID).To(Equal("some-env"))
+			Expect(httpClient.GetCall.Receives).To(Equal([]string{"https://bosh.io", "https://s3.amazonaws.com"}))
+
+			Expect(logger.PrintfCall.Messages).To(Equal([]string{
+				"PASS  state directory        /some/state-dir is writable\n",
+				"PASS  aws credentials        account 123456789012 as arn:aws:iam::123456789012:user/bbl\n",
+				"PASS  aws region             eu-west-1 exists\n",
+				"PASS  aws permissions        the policies of arn:aws:iam::123456789012:user/bbl allow the actions of bbl up\n",
+				"PASS  resource names         no resources have the names of some-env\n",
+				"PASS  reach bosh.io          https://bosh.io responded\n",
+				"PASS  reach s3.amazonaws.com https://s3.amazonaws.com responded\n",
+			}))
+		})
+
+		It("only checks the state directory and the downloads of other iaases", func() {
+			err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "gcp"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(preflightClient.CallerIdentityCall.CallCount).To(Equal(0))
+			Expect(logger.PrintfCall.Messages).To(HaveLen(3))
+		})
+
+		Context("when --json is passed", func() {
+			BeforeEach(func() {
+				command = commands.NewValidate(preflightClient, httpClient, stateStore, fileIO, commands.NewOutputFormatter(logger, true))
+			})
+
+			It("prints the checks as json", func() {
+				err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "gcp"})
+				Expect(err).NotTo(HaveOccurred())
+
+				var output struct {
+					Checks []struct {
+						Name   string `json:"name"`
+						Status string `json:"status"`
+					} `json:"checks"`
+					Passed bool `json:"passed"`
+				}
+				Expect(json.Unmarshal([]byte(logger.PrintlnCall.Messages[0]), &output)).To(Succeed())
+				Expect(output.Passed).To(BeTrue())
+				Expect(output.Checks).To(HaveLen(3))
+				Expect(output.Checks[0].Name).To(Equal("state directory"))
+				Expect(output.Checks[0].Status).To(Equal("pass"))
+			})
+		})
+
+		Context("when the name is not passed", func() {
+			It("skips the check of the resource names", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(preflightClient.ConflictingResourcesCall.CallCount).To(Equal(0))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("SKIP  resource names         bbl plan generates a name. Pass --name to check the name you will plan with\n"))
+			})
+		})
+
+		Context("when the environment exists already", func() {
+			It("skips the check of the resource names, which its resources have", func() {
+				state.EnvID = "some-env"
+
+				err := command.Execute(context.Background(), []string{"--name", "some-env"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(preflightClient.ConflictingResourcesCall.CallCount).To(Equal(0))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("SKIP  resource names         the environment some-env exists already\n"))
+			})
+		})
+
+		Context("when the credentials are the root user", func() {
+			It("does not simulate the policies", func() {
+				preflightClient.CallerIdentityCall.Returns.ARN = "arn:aws:iam::123456789012:root"
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(preflightClient.DeniedActionsCall.CallCount).To(Equal(0))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("PASS  aws permissions        the root user is allowed every action\n"))
+			})
+		})
+
+		Context("when the policies cannot be simulated", func() {
+			It("skips the check of the permissions", func() {
+				preflightClient.DeniedActionsCall.Returns.Error = errors.New("AccessDenied")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.Messages).To(ContainElement("SKIP  aws permissions        could not simulate the policies of arn:aws:iam::123456789012:user/bbl, which needs iam:SimulatePrincipalPolicy: AccessDenied\n"))
+			})
+		})
+
+		Context("when checks fail", func() {
+			BeforeEach(func() {
+				fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("permission denied")}}
+				preflightClient.RegionExistsCall.Returns.Exists = false
+				preflightClient.DeniedActionsCall.Returns.Denied = []string{"iam:PassRole", "iam:CreateRole"}
+				preflightClient.ConflictingResourcesCall.Returns.Conflicts = []string{"vpc some-env-vpc"}
+				httpClient.GetCall.Stub = func(url string) (*http.Response, error) {
+					if url == "https://bosh.io" {
+						return nil, errors.New("dial tcp: i/o timeout")
+					}
+					return &http.Response{StatusCode: http.StatusForbidden, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+				}
+			})
+
+			It("prints every failure and returns an error", func() {
+				err := command.Execute(context.Background(), []string{"--name", "some-env"}, state)
+				Expect(err).To(MatchError("5 of the checks of bbl validate failed. Fix them before bbl up."))
+
+				Expect(logger.PrintfCall.Messages).To(Equal([]string{
+					"FAIL  state directory        /some/state-dir is not writable: permission denied\n",
+					"PASS  aws credentials        account 123456789012 as arn:aws:iam::123456789012:user/bbl\n",
+					"FAIL  aws region             eu-west-1 is not a region of the account\n",
+					"FAIL  aws permissions        the policies of arn:aws:iam::123456789012:user/bbl do not allow iam:PassRole, iam:CreateRole\n",
+					"FAIL  resource names         these resources have the names of some-env already: vpc some-env-vpc\n",
+					"FAIL  reach bosh.io          dial tcp: i/o timeout\n",
+					"PASS  reach s3.amazonaws.com https://s3.amazonaws.com responded\n",
+				}))
+			})
+		})
+
+		Context("when the credentials are not valid", func() {
+			It("skips the checks that need them", func() {
+				preflightClient.CallerIdentityCall.Returns.Error = errors.New("InvalidClientTokenId")
+
+				err := command.Execute(context.Background(), []string{"--name", "some-env"}, state)
+				Expect(err).To(MatchError("1 of the checks of bbl validate failed. Fix them before bbl up."))
+
+				Expect(preflightClient.RegionExistsCall.CallCount).To(Equal(0))
+				Expect(preflightClient.DeniedActionsCall.CallCount).To(Equal(0))
+				Expect(preflightClient.ConflictingResourcesCall.CallCount).To(Equal(0))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("FAIL  aws credentials        InvalidClientTokenId\n"))
+				Expect(logger.PrintfCall.Messages).To(ContainElement("SKIP  aws region             the credentials are not valid\n"))
+			})
+		})
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error for unknown flags", func() {
+			err := command.CheckFastFails([]string{"--unknown"}, state)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,26 +1,84 @@
 package commands
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"runtime"
 
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
+// Version prints the bbl version, the artifacts that bbl builds directors
+// from, including those that bbl plan overrode, and, for an environment, a checksum of its terraform template, so
+// that an environment can be traced back to exactly what built it.
 type Version struct {
-	logger  logger
-	version string
+	logger            logger
+	version           string
+	templateGenerator templateGenerator
+	output            OutputFormatter
 }
 
-func NewVersion(version string, logger logger) Version {
+type versionOutput struct {
+	Version  string `json:"version"`
+	Platform string `json:"platform"`
+	bosh.Artifacts
+	TerraformTemplateSHA256 string `json:"terraformTemplateSHA256,omitempty"`
+}
+
+func NewVersion(version string, logger logger, templateGenerator templateGenerator, output OutputFormatter) Version {
 	return Version{
-		logger:  logger,
-		version: fmt.Sprintf("%s (%s/%s)", version, runtime.GOOS, runtime.GOARCH),
+		logger:            logger,
+		version:           version,
+		templateGenerator: templateGenerator,
+		output:            output,
 	}
 }
 
-func (v Version) Execute(subcommandFlags []string, state storage.State) error {
-	v.logger.Printf("bbl %s\n", v.version)
+func (v Version) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	var jsonOutput bool
+	versionFlags := flags.New("version")
+	versionFlags.Bool(&jsonOutput, "json", false)
+	err := versionFlags.Parse(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := bosh.PinnedArtifacts(state.IAAS)
+	if err != nil {
+		return err
+	}
+	if state.ArtifactOverrides != nil {
+		artifacts = artifacts.WithOverrides(*state.ArtifactOverrides)
+	}
+
+	output := versionOutput{
+		Version:   v.version,
+		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		Artifacts: artifacts,
+	}
+	if state.IAAS != "" && v.templateGenerator != nil {
+		output.TerraformTemplateSHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte(v.templateGenerator.Generate(state))))
+	}
+
+	if jsonOutput || v.output.JSON() {
+		return v.output.PrintJSON(output)
+	}
+
+	v.logger.Printf("bbl %s (%s)\n", output.Version, output.Platform)
+	v.logger.Printf("bosh release %s (sha1: %s)\n", artifacts.BOSH.Version, artifacts.BOSH.SHA1)
+	if artifacts.CPI != nil {
+		v.logger.Printf("cpi release %s %s (sha1: %s)\n", artifacts.CPI.Name, artifacts.CPI.Version, artifacts.CPI.SHA1)
+	}
+	if artifacts.Stemcell != nil {
+		v.logger.Printf("stemcell %s %s (sha1: %s)\n", artifacts.Stemcell.Name, artifacts.Stemcell.Version, artifacts.Stemcell.SHA1)
+	}
+	if output.TerraformTemplateSHA256 != "" {
+		v.logger.Printf("terraform template (sha256: %s)\n", output.TerraformTemplateSHA256)
+	}
+
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package commands_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"runtime"
 
@@ -14,17 +16,20 @@ import (
 
 var _ = Describe("Version", func() {
 	var (
-		version commands.Version
-		logger  *fakes.Logger
+		version           commands.Version
+		logger            *fakes.Logger
+		templateGenerator *fakes.TemplateGenerator
 	)
 
 	BeforeEach(func() {
 		logger = &fakes.Logger{}
+		templateGenerator = &fakes.TemplateGenerator{}
+		templateGenerator.GenerateCall.Returns.Template = "some-template"
 	})
 
 	Describe("CheckFastFails", func() {
 		BeforeEach(func() {
-			version = commands.NewVersion("dev", logger)
+			version = commands.NewVersion("dev", logger, templateGenerator, commands.NewOutputFormatter(logger, false))
 		})
 
 		It("returns no error", func() {
@@ -36,34 +41,86 @@ var _ = Describe("Version", func() {
 	Describe("Execute", func() {
 		Context("when no version number was passed in", func() {
 			BeforeEach(func() {
-				version = commands.NewVersion("dev", logger)
+				version = commands.NewVersion("dev", logger, templateGenerator, commands.NewOutputFormatter(logger, false))
 			})
 
 			Describe("Execute", func() {
 				It("prints out dev as the version", func() {
-					err := version.Execute([]string{}, storage.State{})
+					err := version.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).NotTo(HaveOccurred())
 
-					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
-						fmt.Sprintf("bbl dev (%s/%s)\n", runtime.GOOS, runtime.GOARCH),
-					}))
+					Expect(logger.PrintfCall.Messages[0]).To(Equal(fmt.Sprintf("bbl dev (%s/%s)\n", runtime.GOOS, runtime.GOARCH)))
 				})
 			})
 		})
 
 		Context("when a version number was passed in", func() {
 			BeforeEach(func() {
-				version = commands.NewVersion("1.2.3", logger)
+				version = commands.NewVersion("1.2.3", logger, templateGenerator, commands.NewOutputFormatter(logger, false))
 			})
 
 			Describe("Execute", func() {
 				It("prints out the passed in version information", func() {
-					err := version.Execute([]string{}, storage.State{})
+					err := version.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).NotTo(HaveOccurred())
 
-					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
-						fmt.Sprintf("bbl 1.2.3 (%s/%s)\n", runtime.GOOS, runtime.GOARCH),
-					}))
+					Expect(logger.PrintfCall.Messages[0]).To(Equal(fmt.Sprintf("bbl 1.2.3 (%s/%s)\n", runtime.GOOS, runtime.GOARCH)))
+				})
+
+				It("prints the pinned bosh release", func() {
+					err := version.Execute(context.Background(), []string{}, storage.State{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCall.Messages).To(HaveLen(2))
+					Expect(logger.PrintfCall.Messages[1]).To(MatchRegexp(`^bosh release \S+ \(sha1: [0-9a-f]+\)\n$`))
+					Expect(templateGenerator.GenerateCall.CallCount).To(Equal(0))
+				})
+
+				Context("when there is an environment", func() {
+					It("prints the cpi release, stemcell and a checksum of the terraform template", func() {
+						state := storage.State{IAAS: "aws", EnvID: "some-env-id"}
+
+						err := version.Execute(context.Background(), []string{}, state)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(state))
+						Expect(logger.PrintfCall.Messages).To(HaveLen(5))
+						Expect(logger.PrintfCall.Messages[2]).To(MatchRegexp(`^cpi release bosh-aws-cpi \S+ \(sha1: [0-9a-f]+\)\n$`))
+						Expect(logger.PrintfCall.Messages[3]).To(MatchRegexp(`^stemcell bosh-aws-xen-hvm-ubuntu-trusty-go_agent \S+ \(sha1: [0-9a-f]+\)\n$`))
+						Expect(logger.PrintfCall.Messages[4]).To(Equal("terraform template (sha256: b652a2acae0e9dd629d3d8a509e7a67bbe9d775880cf09436723fec7feff30d6)\n"))
+					})
+				})
+
+				Context("when the environment overrides the stemcell", func() {
+					It("prints the stemcell that the director is built from", func() {
+						err := version.Execute(context.Background(), []string{}, storage.State{IAAS: "aws", ArtifactOverrides: &storage.ArtifactOverrides{
+							StemcellURL:  "https://bosh.io/d/stemcells/bosh-aws-xen-hvm-ubuntu-trusty-go_agent?v=3541.10",
+							StemcellSHA1: "some-sha1",
+						}})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintfCall.Messages[3]).To(Equal("stemcell bosh-aws-xen-hvm-ubuntu-trusty-go_agent 3541.10 (sha1: some-sha1)\n"))
+					})
+				})
+
+				Context("when --json is passed", func() {
+					It("prints the version as json", func() {
+						err := version.Execute(context.Background(), []string{"--json"}, storage.State{IAAS: "aws"})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(logger.PrintfCall.Messages).To(BeEmpty())
+						Expect(logger.PrintlnCall.Messages).To(HaveLen(1))
+
+						var output map[string]interface{}
+						err = json.Unmarshal([]byte(logger.PrintlnCall.Messages[0]), &output)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(output["version"]).To(Equal("1.2.3"))
+						Expect(output["platform"]).To(Equal(fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)))
+						Expect(output["bosh"]).To(HaveKeyWithValue("name", "bosh"))
+						Expect(output["cpi"]).To(HaveKeyWithValue("name", "bosh-aws-cpi"))
+						Expect(output["stemcell"]).To(HaveKey("sha1"))
+						Expect(output["terraformTemplateSHA256"]).To(HaveLen(64))
+					})
 				})
 			})
 		})
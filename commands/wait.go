@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// Wait blocks until a command that --no-wait started has finished, and fails
+// when the command failed.
+type Wait struct {
+	logger       logger
+	operations   operations
+	pollInterval time.Duration
+}
+
+func NewWait(logger logger, operations operations, pollInterval time.Duration) Wait {
+	return Wait{
+		logger:       logger,
+		operations:   operations,
+		pollInterval: pollInterval,
+	}
+}
+
+func (w Wait) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	if len(subcommandFlags) != 1 {
+		return errors.New("bbl wait takes the id of one operation. Run bbl status to list them.")
+	}
+
+	return nil
+}
+
+func (w Wait) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	id := subcommandFlags[0]
+
+	operation, err := w.operations.Get(id)
+	for err == nil && operation.Status == storage.OperationRunning {
+		err = sleep(ctx, w.pollInterval)
+		if err != nil {
+			return err
+		}
+		operation, err = w.operations.Get(id)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch operation.Status {
+	case storage.OperationSucceeded:
+		w.logger.Println(fmt.Sprintf("Operation %s (bbl %s) succeeded.", operation.ID, operation.Command))
+		return nil
+	case storage.OperationFailed:
+		return fmt.Errorf("Operation %s (bbl %s) failed with exit code %d. Its output is in %s.", operation.ID, operation.Command, *operation.ExitCode, operation.Output)
+	default:
+		return fmt.Errorf("Operation %s (bbl %s) stopped without recording an exit code. Its output is in %s.", operation.ID, operation.Command, operation.Output)
+	}
+}
+
+// sleep waits for the interval of a polling loop, or returns the error of ctx
+// once it is done first.
+func sleep(ctx context.Context, interval time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(interval):
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Wait", func() {
+	var (
+		logger     *fakes.Logger
+		operations *fakes.Operations
+		command    commands.Wait
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		operations = &fakes.Operations{}
+
+		command = commands.NewWait(logger, operations, 0)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error without an operation id", func() {
+			err := command.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("bbl wait takes the id of one operation. Run bbl status to list them."))
+		})
+	})
+
+	Describe("Execute", func() {
+		var running storage.Operation
+
+		BeforeEach(func() {
+			running = storage.Operation{ID: "some-id", Command: "up", Status: storage.OperationRunning, Output: "/state/bbl-operations/some-id/output.log"}
+		})
+
+		It("polls the operation until it succeeds", func() {
+			succeeded := running
+			succeeded.Status = storage.OperationSucceeded
+			operations.GetCall.Returns = []fakes.GetOperationReturn{{Operation: running}, {Operation: running}, {Operation: succeeded}}
+
+			err := command.Execute(context.Background(), []string{"some-id"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(operations.GetCall.CallCount).To(Equal(3))
+			Expect(operations.GetCall.Receives.ID).To(Equal("some-id"))
+			Expect(logger.PrintlnCall.Receives.Message).To(Equal("Operation some-id (bbl up) succeeded."))
+		})
+
+		It("returns an error with the exit code when the operation failed", func() {
+			exitCode := 1
+			failed := running
+			failed.Status = storage.OperationFailed
+			failed.ExitCode = &exitCode
+			operations.GetCall.Returns = []fakes.GetOperationReturn{{Operation: failed}}
+
+			err := command.Execute(context.Background(), []string{"some-id"}, storage.State{})
+			Expect(err).To(MatchError("Operation some-id (bbl up) failed with exit code 1. Its output is in /state/bbl-operations/some-id/output.log."))
+		})
+
+		It("returns an error when the operation stopped without an exit code", func() {
+			unknown := running
+			unknown.Status = storage.OperationUnknown
+			operations.GetCall.Returns = []fakes.GetOperationReturn{{Operation: unknown}}
+
+			err := command.Execute(context.Background(), []string{"some-id"}, storage.State{})
+			Expect(err).To(MatchError("Operation some-id (bbl up) stopped without recording an exit code. Its output is in /state/bbl-operations/some-id/output.log."))
+		})
+
+		It("stops waiting when it is interrupted", func() {
+			command = commands.NewWait(logger, operations, time.Hour)
+			operations.GetCall.Returns = []fakes.GetOperationReturn{{Operation: running}}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := command.Execute(ctx, []string{"some-id"}, storage.State{})
+			Expect(err).To(Equal(context.Canceled))
+			Expect(operations.GetCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when the operation cannot be read", func() {
+			operations.GetCall.Returns = []fakes.GetOperationReturn{{Error: errors.New("There is no operation some-id in /state/bbl-operations.")}}
+
+			err := command.Execute(context.Background(), []string{"some-id"}, storage.State{})
+			Expect(err).To(MatchError("There is no operation some-id in /state/bbl-operations."))
+		})
+	})
+})
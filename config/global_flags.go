@@ -1,16 +1,42 @@
 package config
 
 type globalFlags struct {
-	Help      bool   `short:"h" long:"help"`
-	Debug     bool   `short:"d" long:"debug"     env:"BBL_DEBUG"`
-	Version   bool   `short:"v" long:"version"`
-	NoConfirm bool   `short:"n" long:"no-confirm"`
-	StateDir  string `short:"s" long:"state-dir" env:"BBL_STATE_DIRECTORY"`
-	IAAS      string `          long:"iaas"      env:"BBL_IAAS"`
-
-	AWSAccessKeyID     string `long:"aws-access-key-id"       env:"BBL_AWS_ACCESS_KEY_ID"`
-	AWSSecretAccessKey string `long:"aws-secret-access-key"   env:"BBL_AWS_SECRET_ACCESS_KEY"`
-	AWSRegion          string `long:"aws-region"              env:"BBL_AWS_REGION"`
+	Help        bool   `short:"h" long:"help"`
+	Examples    bool   `          long:"help-examples"`
+	Debug       bool   `short:"d" long:"debug"     env:"BBL_DEBUG"`
+	Version     bool   `short:"v" long:"version"`
+	NoConfirm   bool   `short:"n" long:"no-confirm"`
+	JSON        bool   `          long:"json"         env:"BBL_JSON"`
+	NoWait      bool   `          long:"no-wait"      env:"BBL_NO_WAIT"`
+	NoCache     bool   `          long:"no-cache"     env:"BBL_NO_CACHE"`
+	Lang        string `          long:"lang"         env:"BBL_LANG"`
+	StateDir    string `short:"s" long:"state-dir"    env:"BBL_STATE_DIRECTORY"`
+	Env         string `          long:"env"          env:"BBL_ENV"`
+	StateFormat string `          long:"state-format" env:"BBL_STATE_FORMAT"`
+	IAAS        string `          long:"iaas"         env:"BBL_IAAS"`
+
+	StatePassphrase string `long:"state-passphrase" env:"BBL_STATE_PASSPHRASE"`
+
+	OverrideAccountCheck bool `long:"override-account-check" env:"BBL_OVERRIDE_ACCOUNT_CHECK"`
+
+	HealthListen string `long:"health-listen" env:"BBL_HEALTH_LISTEN"`
+	ProfileRun   string `long:"profile-run"   env:"BBL_PROFILE_RUN"`
+	ProxyURL     string `long:"proxy-url"     env:"BBL_PROXY_URL"`
+	Config       string `long:"config"        env:"BBL_CONFIG"`
+	Timeout      string `long:"timeout"       env:"BBL_TIMEOUT"`
+
+	AWSAccessKeyID      string `long:"aws-access-key-id"       env:"BBL_AWS_ACCESS_KEY_ID"`
+	AWSSecretAccessKey  string `long:"aws-secret-access-key"   env:"BBL_AWS_SECRET_ACCESS_KEY"`
+	AWSSessionToken     string `long:"aws-session-token"       env:"BBL_AWS_SESSION_TOKEN"`
+	AWSProfile          string `long:"aws-profile"             env:"BBL_AWS_PROFILE"`
+	AWSRoleARN          string `long:"aws-role-arn"            env:"BBL_AWS_ROLE_ARN"`
+	AWSMFASerial        string `long:"aws-mfa-serial"          env:"BBL_AWS_MFA_SERIAL"`
+	AWSMFATokenCode     string `long:"aws-mfa-token-code"      env:"BBL_AWS_MFA_TOKEN_CODE"`
+	AWSRegion           string `long:"aws-region"              env:"BBL_AWS_REGION"`
+	AWSMaxRetries       string `long:"aws-max-retries"         env:"BBL_AWS_MAX_RETRIES"`
+	AWSRetryJitter      string `long:"aws-retry-jitter"        env:"BBL_AWS_RETRY_JITTER"`
+	AWSEndpoint         string `long:"aws-endpoint"            env:"BBL_AWS_ENDPOINT"`
+	AWSServiceEndpoints string `long:"aws-service-endpoints"   env:"BBL_AWS_SERVICE_ENDPOINTS"`
 
 	AzureClientID       string `long:"azure-client-id"        env:"BBL_AZURE_CLIENT_ID"`
 	AzureClientSecret   string `long:"azure-client-secret"    env:"BBL_AZURE_CLIENT_SECRET"`
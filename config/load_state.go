@@ -4,13 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/fileio"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	flags "github.com/jessevdk/go-flags"
+	yaml "gopkg.in/yaml.v2"
 )
 
 type logger interface {
@@ -48,6 +56,12 @@ type Config struct {
 	fs             fs
 }
 
+// defaultConfigFile is the config file of the global flags that bbl reads
+// from the working directory when --config is not given.
+const defaultConfigFile = "bbl.yml"
+
+// ParseArgs reads the global flags from the arguments, the environment and a
+// config file, in that order of precedence.
 func ParseArgs(args []string) (globalFlags, []string, error) {
 	var globals globalFlags
 	parser := flags.NewParser(&globals, flags.IgnoreUnknown)
@@ -57,6 +71,23 @@ func ParseArgs(args []string) (globalFlags, []string, error) {
 		return globalFlags{}, remainingArgs, err
 	}
 
+	fileArgs, err := configFileArgs(parser, globals.Config)
+	if err != nil {
+		return globalFlags{}, remainingArgs, err
+	}
+
+	if len(fileArgs) > 0 {
+		// The arguments come after those of the file, so that they override
+		// them.
+		globals = globalFlags{}
+		parser = flags.NewParser(&globals, flags.IgnoreUnknown)
+
+		remainingArgs, err = parser.ParseArgs(append(fileArgs, args[1:]...))
+		if err != nil {
+			return globalFlags{}, remainingArgs, err
+		}
+	}
+
 	if !filepath.IsAbs(globals.StateDir) {
 		workingDir, err := os.Getwd()
 		if err != nil {
@@ -65,9 +96,69 @@ func ParseArgs(args []string) (globalFlags, []string, error) {
 		globals.StateDir = filepath.Join(workingDir, globals.StateDir)
 	}
 
+	if globals.Env != "" {
+		globals.StateDir, err = storage.EnvStateDir(globals.StateDir, globals.Env)
+		if err != nil {
+			return globalFlags{}, remainingArgs, err
+		}
+	}
+
 	return globals, remainingArgs, nil
 }
 
+// configFileArgs reads a config file whose keys are the names of global flags
+// into the arguments that set them. Flags that are set in the environment
+// are left out, so that the environment overrides the file.
+func configFileArgs(parser *flags.Parser, path string) ([]string, error) {
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err != nil {
+			return nil, nil
+		}
+		path = defaultConfigFile
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Read config file: %s", err)
+	}
+
+	var values map[string]interface{}
+	err = yaml.Unmarshal(contents, &values)
+	if err != nil {
+		return nil, fmt.Errorf("Read config file %s: %s", path, err)
+	}
+
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := []string{}
+	for _, name := range names {
+		option := parser.FindOptionByLongName(name)
+		if option == nil || name == "config" {
+			return nil, fmt.Errorf("The config file %s sets %s, which is not a global flag.", path, name)
+		}
+
+		if _, ok := os.LookupEnv(option.EnvDefaultKey); ok && option.EnvDefaultKey != "" {
+			continue
+		}
+
+		switch value := values[name].(type) {
+		case nil:
+		case bool:
+			if value {
+				args = append(args, "--"+name)
+			}
+		default:
+			args = append(args, fmt.Sprintf("--%s=%v", name, value))
+		}
+	}
+
+	return args, nil
+}
+
 func (c Config) Bootstrap(args []string) (application.Configuration, error) {
 	if len(args) == 1 {
 		return application.Configuration{
@@ -77,7 +168,7 @@ func (c Config) Bootstrap(args []string) (application.Configuration, error) {
 
 	globalFlags, remainingArgs, err := ParseArgs(args)
 	if err != nil {
-		return application.Configuration{}, err
+		return application.Configuration{}, bblerrors.New(bblerrors.Validation, err)
 	}
 
 	var command string
@@ -119,6 +210,35 @@ func (c Config) Bootstrap(args []string) (application.Configuration, error) {
 		}, nil
 	}
 
+	if globalFlags.Examples {
+		return application.Configuration{
+			ShowCommandExamples: true,
+			Command:             command,
+		}, nil
+	}
+
+	if globalFlags.ProxyURL != "" {
+		proxyURL, err := url.Parse(globalFlags.ProxyURL)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return application.Configuration{}, bblerrors.New(bblerrors.Validation, fmt.Errorf("--proxy-url %q is not an http or https url.", globalFlags.ProxyURL))
+		}
+	}
+
+	var timeout time.Duration
+	if globalFlags.Timeout != "" {
+		timeout, err = time.ParseDuration(globalFlags.Timeout)
+		if err != nil || timeout <= 0 {
+			return application.Configuration{}, bblerrors.New(bblerrors.Validation, fmt.Errorf("--timeout %q is not a duration, for example: 90m.", globalFlags.Timeout))
+		}
+	}
+
+	if globalFlags.Env != "" {
+		err = createEnvStateDir(globalFlags, command)
+		if err != nil {
+			return application.Configuration{}, err
+		}
+	}
+
 	state, err := c.stateBootstrap.GetState(globalFlags.StateDir)
 	if err != nil {
 		return application.Configuration{}, err
@@ -131,13 +251,23 @@ func (c Config) Bootstrap(args []string) (application.Configuration, error) {
 
 	state, err = c.updateIAASState(globalFlags, state)
 	if err != nil {
-		return application.Configuration{}, err
+		return application.Configuration{}, bblerrors.New(bblerrors.Validation, err)
 	}
 
 	return application.Configuration{
 		Global: application.GlobalConfiguration{
 			Debug:    globalFlags.Debug,
 			StateDir: globalFlags.StateDir,
+			JSON:     globalFlags.JSON,
+			NoWait:   globalFlags.NoWait,
+			NoCache:  globalFlags.NoCache,
+
+			NoConfirm:            globalFlags.NoConfirm,
+			OverrideAccountCheck: globalFlags.OverrideAccountCheck,
+			HealthListen:         globalFlags.HealthListen,
+			ProfileRun:           globalFlags.ProfileRun,
+			ProxyURL:             globalFlags.ProxyURL,
+			Timeout:              timeout,
 		},
 		State:           state,
 		Command:         command,
@@ -146,6 +276,33 @@ func (c Config) Bootstrap(args []string) (application.Configuration, error) {
 	}, nil
 }
 
+// createEnvStateDir creates the state directory of the environment that
+// --env names, when bbl plan or bbl up creates the environment. Other
+// commands need the environment to exist.
+func createEnvStateDir(globalFlags globalFlags, command string) error {
+	if _, err := os.Stat(globalFlags.StateDir); err == nil {
+		return nil
+	}
+
+	if command != "plan" && command != "up" {
+		stateDir := filepath.Dir(filepath.Dir(globalFlags.StateDir))
+		envs, err := storage.Envs(stateDir)
+		if err != nil {
+			return fmt.Errorf("List environments: %s", err)
+		}
+		if len(envs) == 0 {
+			return bblerrors.New(bblerrors.Validation, fmt.Errorf("The state directory %s has no environments. Run bbl --env %s plan or bbl --env %s up to create one.", stateDir, globalFlags.Env, globalFlags.Env))
+		}
+		return bblerrors.New(bblerrors.Validation, fmt.Errorf("The state directory %s has no environment %s. Its environments are: %s.", stateDir, globalFlags.Env, strings.Join(envs, ", ")))
+	}
+
+	err := os.MkdirAll(globalFlags.StateDir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Create state directory of environment %s: %s", globalFlags.Env, err)
+	}
+	return nil
+}
+
 func (c Config) updateIAASState(globalFlags globalFlags, state storage.State) (storage.State, error) {
 	if globalFlags.IAAS != "" {
 		if state.IAAS != "" && globalFlags.IAAS != state.IAAS {
@@ -170,6 +327,34 @@ func (c Config) updateIAASState(globalFlags globalFlags, state storage.State) (s
 	return state, nil
 }
 
+// parseServiceEndpoints parses the endpoints of single aws services, such as
+// ec2=https://ec2.us-gov-west-1.amazonaws.com,iam=https://iam.us-gov.amazonaws.com.
+func parseServiceEndpoints(value string) (map[string]string, error) {
+	endpoints := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("--aws-service-endpoints %q is not a list of service=url, for example: ec2=https://ec2.us-gov-west-1.amazonaws.com,iam=https://iam.us-gov.amazonaws.com", value)
+		}
+
+		service, rawURL := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		known := false
+		for _, endpointService := range storage.EndpointServices {
+			known = known || service == endpointService
+		}
+		if !known {
+			return nil, fmt.Errorf("--aws-service-endpoints has an endpoint for %q, which is not one of %s.", service, strings.Join(storage.EndpointServices, ", "))
+		}
+
+		endpoint, err := url.Parse(rawURL)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return nil, fmt.Errorf("--aws-service-endpoints has an endpoint for %s, %q, that is not an http or https url.", service, rawURL)
+		}
+		endpoints[service] = rawURL
+	}
+	return endpoints, nil
+}
+
 func copyFlagToState(source string, sink *string) {
 	if source != "" {
 		*sink = source
@@ -230,6 +415,11 @@ func (c Config) updateVSphereState(globalFlags globalFlags, state storage.State)
 func (c Config) updateAWSState(globalFlags globalFlags, state storage.State) (storage.State, error) {
 	copyFlagToState(globalFlags.AWSAccessKeyID, &state.AWS.AccessKeyID)
 	copyFlagToState(globalFlags.AWSSecretAccessKey, &state.AWS.SecretAccessKey)
+	copyFlagToState(globalFlags.AWSSessionToken, &state.AWS.SessionToken)
+	copyFlagToState(globalFlags.AWSProfile, &state.AWS.Profile)
+	copyFlagToState(globalFlags.AWSRoleARN, &state.AWS.RoleARN)
+	copyFlagToState(globalFlags.AWSMFASerial, &state.AWS.MFASerial)
+	copyFlagToState(globalFlags.AWSMFATokenCode, &state.AWS.MFATokenCode)
 
 	if globalFlags.AWSRegion != "" {
 		if state.AWS.Region != "" && globalFlags.AWSRegion != state.AWS.Region {
@@ -238,6 +428,38 @@ func (c Config) updateAWSState(globalFlags globalFlags, state storage.State) (st
 		state.AWS.Region = globalFlags.AWSRegion
 	}
 
+	if globalFlags.AWSEndpoint != "" {
+		endpoint, err := url.Parse(globalFlags.AWSEndpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return storage.State{}, fmt.Errorf("--aws-endpoint %q is not an http or https url.", globalFlags.AWSEndpoint)
+		}
+		state.AWS.Endpoint = globalFlags.AWSEndpoint
+	}
+
+	if globalFlags.AWSServiceEndpoints != "" {
+		endpoints, err := parseServiceEndpoints(globalFlags.AWSServiceEndpoints)
+		if err != nil {
+			return storage.State{}, err
+		}
+		state.AWS.Endpoints = endpoints
+	}
+
+	if globalFlags.AWSMaxRetries != "" {
+		maxRetries, err := strconv.Atoi(globalFlags.AWSMaxRetries)
+		if err != nil || maxRetries < 1 {
+			return storage.State{}, fmt.Errorf("--aws-max-retries %q is not a positive number.", globalFlags.AWSMaxRetries)
+		}
+		state.AWS.MaxRetries = maxRetries
+	}
+
+	if globalFlags.AWSRetryJitter != "" {
+		jitter, err := strconv.ParseFloat(globalFlags.AWSRetryJitter, 64)
+		if err != nil || jitter < 0 || jitter > 1 {
+			return storage.State{}, fmt.Errorf("--aws-retry-jitter %q is not a number between 0 and 1.", globalFlags.AWSRetryJitter)
+		}
+		state.AWS.RetryJitter = &jitter
+	}
+
 	return state, nil
 }
 
@@ -355,7 +577,7 @@ func ValidateIAAS(state storage.State) error {
 	}
 
 	if err != nil {
-		return fmt.Errorf("\n\n%s\n", err)
+		return bblerrors.New(bblerrors.Credentials, fmt.Errorf("\n\n%s\n", err))
 	}
 
 	return nil
@@ -363,13 +585,28 @@ func ValidateIAAS(state storage.State) error {
 
 func NeedsIAASCreds(command string) bool {
 	_, ok := map[string]struct{}{
-		"up":                struct{}{},
-		"down":              struct{}{},
-		"plan":              struct{}{},
-		"destroy":           struct{}{},
-		"leftovers":         struct{}{},
-		"cleanup-leftovers": struct{}{},
-		"rotate":            struct{}{},
+		"up":                          struct{}{},
+		"down":                        struct{}{},
+		"plan":                        struct{}{},
+		"destroy":                     struct{}{},
+		"leftovers":                   struct{}{},
+		"cleanup-leftovers":           struct{}{},
+		"clean-leftovers":             struct{}{},
+		"rotate":                      struct{}{},
+		"rotate-keypair":              struct{}{},
+		"rotate-director-credentials": struct{}{},
+		"upgrade-director":            struct{}{},
+		"migrate-region":              struct{}{},
+		"apply":                       struct{}{},
+		"clone":                       struct{}{},
+		"bootstrap-account":           struct{}{},
+		"copy-stemcell-ami":           struct{}{},
+		"upload-certificate":          struct{}{},
+		"create-certificate":          struct{}{},
+		"rotate-certificate":          struct{}{},
+		"update-security-groups":      struct{}{},
+		"validate":                    struct{}{},
+		"bosh-deployment-vars":        struct{}{},
 	}[command]
 	return ok
 }
@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/config"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -130,6 +132,17 @@ var _ = Describe("LoadState", func() {
 				})
 			})
 
+			Context("when --help-examples is passed in after a command", func() {
+				It("returns command examples without loading the state", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "up", "--help-examples"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.ShowCommandExamples).To(BeTrue())
+					Expect(fakeStateBootstrap.GetStateCall.CallCount).To(Equal(0))
+				})
+			})
+
 			Context("when help is passed in before a command", func() {
 				It("returns command help", func() {
 					args := []string{"bbl", "help", "up"}
@@ -142,6 +155,125 @@ var _ = Describe("LoadState", func() {
 				})
 			})
 
+			Context("when --json is passed in after a command", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "lbs", "--json"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("lbs"))
+					Expect(appConfig.Global.JSON).To(BeTrue())
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+			})
+
+			Context("when --no-wait is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "up", "--no-wait"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.Global.NoWait).To(BeTrue())
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+
+				It("can be turned off through the environment variable", func() {
+					os.Setenv("BBL_NO_WAIT", "false")
+
+					appConfig, err := c.Bootstrap([]string{"bbl", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Global.NoWait).To(BeFalse())
+				})
+			})
+
+			Context("when --no-confirm is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--no-confirm", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.Global.NoConfirm).To(BeTrue())
+				})
+			})
+
+			Context("when --no-cache is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--no-cache", "plan"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("plan"))
+					Expect(appConfig.Global.NoCache).To(BeTrue())
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+			})
+
+			Context("when --health-listen is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--health-listen", ":8800", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.Global.HealthListen).To(Equal(":8800"))
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+			})
+
+			Context("when --proxy-url is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--proxy-url", "http://proxy.example.com:3128", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.Global.ProxyURL).To(Equal("http://proxy.example.com:3128"))
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+
+				Context("when it is not an http url", func() {
+					It("returns an error", func() {
+						_, err := c.Bootstrap([]string{"bbl", "--proxy-url", "socks5://proxy.example.com:1080", "up"})
+						Expect(err).To(MatchError(`--proxy-url "socks5://proxy.example.com:1080" is not an http or https url.`))
+					})
+				})
+			})
+
+			Context("when --timeout is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--timeout", "90m", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Global.Timeout).To(Equal(90 * time.Minute))
+				})
+
+				Context("when it is not a duration", func() {
+					It("returns an error", func() {
+						_, err := c.Bootstrap([]string{"bbl", "--timeout", "90", "up"})
+						Expect(err).To(MatchError(`--timeout "90" is not a duration, for example: 90m.`))
+					})
+				})
+			})
+
+			Context("when --profile-run is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--profile-run", "profile.json", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.Global.ProfileRun).To(Equal("profile.json"))
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+			})
+
+			Context("when --override-account-check is passed in", func() {
+				It("returns it as a global flag", func() {
+					appConfig, err := c.Bootstrap([]string{"bbl", "--override-account-check", "up"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(appConfig.Command).To(Equal("up"))
+					Expect(appConfig.Global.OverrideAccountCheck).To(BeTrue())
+					Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				})
+			})
+
 			Context("when debug flag is passed in through environment variable", func() {
 				BeforeEach(func() {
 					os.Setenv("BBL_DEBUG", "true")
@@ -169,6 +301,88 @@ var _ = Describe("LoadState", func() {
 			})
 		})
 
+		Describe("config file", func() {
+			var configPath string
+
+			BeforeEach(func() {
+				configFile, err := ioutil.TempFile("", "bbl.yml")
+				Expect(err).NotTo(HaveOccurred())
+				configPath = configFile.Name()
+
+				_, err = configFile.WriteString(`---
+iaas: aws
+aws-access-key-id: file-access-key-id
+aws-secret-access-key: file-secret-access-key
+aws-region: file-region
+aws-max-retries: 20
+state-dir: /path/from/file
+debug: true
+`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configFile.Close()).To(Succeed())
+			})
+
+			AfterEach(func() {
+				os.Remove(configPath)
+			})
+
+			It("reads the global flags from the file given with --config", func() {
+				appConfig, err := c.Bootstrap([]string{"bbl", "--config", configPath, "up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(appConfig.Command).To(Equal("up"))
+				Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				Expect(appConfig.Global.StateDir).To(Equal("/path/from/file"))
+				Expect(appConfig.Global.Debug).To(BeTrue())
+				Expect(appConfig.State.IAAS).To(Equal("aws"))
+				Expect(appConfig.State.AWS.AccessKeyID).To(Equal("file-access-key-id"))
+				Expect(appConfig.State.AWS.SecretAccessKey).To(Equal("file-secret-access-key"))
+				Expect(appConfig.State.AWS.Region).To(Equal("file-region"))
+				Expect(appConfig.State.AWS.MaxRetries).To(Equal(20))
+
+				Expect(fakeStateBootstrap.GetStateCall.Receives.Dir).To(Equal("/path/from/file"))
+			})
+
+			It("reads the file given in BBL_CONFIG", func() {
+				os.Setenv("BBL_CONFIG", configPath)
+
+				appConfig, err := c.Bootstrap([]string{"bbl", "up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(appConfig.State.AWS.AccessKeyID).To(Equal("file-access-key-id"))
+			})
+
+			It("prefers the flags and the environment to the file", func() {
+				os.Setenv("BBL_AWS_ACCESS_KEY_ID", "env-access-key-id")
+				os.Setenv("BBL_AWS_REGION", "env-region")
+
+				appConfig, err := c.Bootstrap([]string{"bbl", "--config", configPath, "up", "--aws-region", "flag-region"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(appConfig.SubcommandFlags).To(BeEmpty())
+				Expect(appConfig.State.AWS.AccessKeyID).To(Equal("env-access-key-id"))
+				Expect(appConfig.State.AWS.SecretAccessKey).To(Equal("file-secret-access-key"))
+				Expect(appConfig.State.AWS.Region).To(Equal("flag-region"))
+			})
+
+			Context("when the file sets something other than a global flag", func() {
+				It("returns an error", func() {
+					err := ioutil.WriteFile(configPath, []byte("lb-type: cf\n"), os.ModePerm)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = c.Bootstrap([]string{"bbl", "--config", configPath, "up"})
+					Expect(err).To(MatchError(fmt.Sprintf("The config file %s sets lb-type, which is not a global flag.", configPath)))
+				})
+			})
+
+			Context("when the file cannot be read", func() {
+				It("returns an error", func() {
+					_, err := c.Bootstrap([]string{"bbl", "--config", "/some/missing/bbl.yml", "up"})
+					Expect(err).To(MatchError(ContainSubstring("Read config file: ")))
+				})
+			})
+		})
+
 		Describe("reading a previous state file", func() {
 			var (
 				gotState      storage.State
@@ -271,6 +485,74 @@ var _ = Describe("LoadState", func() {
 				})
 			})
 
+			Context("when --env is passed", func() {
+				var stateDir string
+				BeforeEach(func() {
+					var err error
+					stateDir, err = ioutil.TempDir("", "my-state-dir-")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				AfterEach(func() {
+					os.RemoveAll(stateDir)
+				})
+
+				It("uses the state dir of the environment", func() {
+					envDir := filepath.Join(stateDir, "envs", "staging")
+					Expect(os.MkdirAll(envDir, os.ModePerm)).To(Succeed())
+
+					appConfig, err := c.Bootstrap([]string{
+						"bbl",
+						"--state-dir", stateDir,
+						"--env", "staging",
+						"rotate",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeStateBootstrap.GetStateCall.Receives.Dir).To(Equal(envDir))
+					Expect(appConfig.Global.StateDir).To(Equal(envDir))
+				})
+
+				It("creates the state dir of a new environment for plan and up", func() {
+					appConfig, err := c.Bootstrap([]string{
+						"bbl",
+						"--state-dir", stateDir,
+						"--env", "staging",
+						"plan",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					envDir := filepath.Join(stateDir, "envs", "staging")
+					Expect(envDir).To(BeADirectory())
+					Expect(appConfig.Global.StateDir).To(Equal(envDir))
+				})
+
+				It("returns an error listing the environments for other commands", func() {
+					Expect(os.MkdirAll(filepath.Join(stateDir, "envs", "prod"), os.ModePerm)).To(Succeed())
+					Expect(os.MkdirAll(filepath.Join(stateDir, "envs", "dev"), os.ModePerm)).To(Succeed())
+
+					_, err := c.Bootstrap([]string{
+						"bbl",
+						"--state-dir", stateDir,
+						"--env", "staging",
+						"lbs",
+					})
+					Expect(err).To(MatchError(fmt.Sprintf("The state directory %s has no environment staging. Its environments are: dev, prod.", stateDir)))
+					Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Validation))
+					Expect(filepath.Join(stateDir, "envs", "staging")).NotTo(BeADirectory())
+				})
+
+				It("returns an error for a name that is not an environment name", func() {
+					_, err := c.Bootstrap([]string{
+						"bbl",
+						"--state-dir", stateDir,
+						"--env", "Staging",
+						"lbs",
+					})
+					Expect(err).To(MatchError(`--env "Staging" is not an environment name, which has lowercase letters, digits and hyphens.`))
+				})
+			})
+
 			Context("when invalid state dir is passed in", func() {
 				BeforeEach(func() {
 					fakeStateBootstrap.GetStateCall.Returns.Error = errors.New("some state dir error")
@@ -697,6 +979,30 @@ var _ = Describe("LoadState", func() {
 					})
 				})
 
+				Context("when temporary credentials are requested", func() {
+					It("returns a state object containing the profile, role and MFA flags", func() {
+						appConfig, err := c.Bootstrap([]string{
+							"bbl",
+							"--iaas", "aws",
+							"--aws-region", "some-region",
+							"--aws-session-token", "some-session-token",
+							"--aws-profile", "some-profile",
+							"--aws-role-arn", "some-role-arn",
+							"--aws-mfa-serial", "some-mfa-serial",
+							"--aws-mfa-token-code", "some-mfa-token-code",
+							"up",
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						state := appConfig.State
+						Expect(state.AWS.SessionToken).To(Equal("some-session-token"))
+						Expect(state.AWS.Profile).To(Equal("some-profile"))
+						Expect(state.AWS.RoleARN).To(Equal("some-role-arn"))
+						Expect(state.AWS.MFASerial).To(Equal("some-mfa-serial"))
+						Expect(state.AWS.MFATokenCode).To(Equal("some-mfa-token-code"))
+					})
+				})
+
 				Context("when configuration is passed in by env vars", func() {
 					var args []string
 
@@ -759,6 +1065,41 @@ var _ = Describe("LoadState", func() {
 					})
 				})
 
+				Context("when an endpoint is passed in", func() {
+					It("returns state with the endpoint", func() {
+						appConfig, err := c.Bootstrap([]string{"bbl", "up", "--aws-endpoint", "https://aws.example.com"})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(appConfig.State.AWS.Endpoint).To(Equal("https://aws.example.com"))
+					})
+				})
+
+				Context("when service endpoints are passed in", func() {
+					It("returns state with the endpoint of each service", func() {
+						appConfig, err := c.Bootstrap([]string{"bbl", "up", "--aws-service-endpoints", "ec2=https://ec2.example.com, iam=https://iam.example.com"})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(appConfig.State.AWS.Endpoints).To(Equal(map[string]string{
+							"ec2": "https://ec2.example.com",
+							"iam": "https://iam.example.com",
+						}))
+					})
+				})
+
+				Context("when retry settings are passed in", func() {
+					It("returns state with the retry settings", func() {
+						appConfig, err := c.Bootstrap([]string{
+							"bbl", "up",
+							"--aws-max-retries", "20",
+							"--aws-retry-jitter", "0.25",
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(appConfig.State.AWS.MaxRetries).To(Equal(20))
+						Expect(appConfig.State.AWS.RetryJitter).To(Equal(pointerToFloat(0.25)))
+					})
+				})
+
 				DescribeTable("when non-matching configuration is passed in",
 					func(args []string, expected string) {
 						_, err := c.Bootstrap(args)
@@ -769,6 +1110,18 @@ var _ = Describe("LoadState", func() {
 						"The iaas type cannot be changed for an existing environment. The current iaas type is aws."),
 					Entry("returns an error for non-matching region", []string{"bbl", "up", "--aws-region", "some-other-region"},
 						"The region cannot be changed for an existing environment. The current region is some-region."),
+					Entry("returns an error for an invalid endpoint", []string{"bbl", "up", "--aws-endpoint", "aws.example.com"},
+						`--aws-endpoint "aws.example.com" is not an http or https url.`),
+					Entry("returns an error for service endpoints that are not service=url", []string{"bbl", "up", "--aws-service-endpoints", "https://ec2.example.com"},
+						`--aws-service-endpoints "https://ec2.example.com" is not a list of service=url, for example: ec2=https://ec2.us-gov-west-1.amazonaws.com,iam=https://iam.us-gov.amazonaws.com`),
+					Entry("returns an error for an endpoint of an unknown service", []string{"bbl", "up", "--aws-service-endpoints", "route53=https://route53.example.com"},
+						`--aws-service-endpoints has an endpoint for "route53", which is not one of ec2, elb, iam, kms, s3, sts, cloudwatchlogs.`),
+					Entry("returns an error for an invalid service endpoint", []string{"bbl", "up", "--aws-service-endpoints", "ec2=ec2.example.com"},
+						`--aws-service-endpoints has an endpoint for ec2, "ec2.example.com", that is not an http or https url.`),
+					Entry("returns an error for invalid max retries", []string{"bbl", "up", "--aws-max-retries", "0"},
+						`--aws-max-retries "0" is not a positive number.`),
+					Entry("returns an error for invalid retry jitter", []string{"bbl", "up", "--aws-retry-jitter", "1.5"},
+						`--aws-retry-jitter "1.5" is not a number between 0 and 1.`),
 				)
 			})
 		})
@@ -1216,3 +1569,7 @@ var _ = Describe("LoadState", func() {
 		)
 	})
 })
+
+func pointerToFloat(f float64) *float64 {
+	return &f
+}
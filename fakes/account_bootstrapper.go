@@ -0,0 +1,15 @@
+package fakes
+
+type AccountBootstrapper struct {
+	BootstrapAccountCall struct {
+		CallCount int
+		Returns   struct {
+			Error error
+		}
+	}
+}
+
+func (a *AccountBootstrapper) BootstrapAccount() error {
+	a.BootstrapAccountCall.CallCount++
+	return a.BootstrapAccountCall.Returns.Error
+}
@@ -0,0 +1,25 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/bosh"
+
+type ArtifactDownloader struct {
+	DownloadCall struct {
+		CallCount int
+		Receives  []DownloadCallReceive
+		Returns   struct {
+			Downloaded bool
+			Error      error
+		}
+	}
+}
+
+type DownloadCallReceive struct {
+	Artifact bosh.Artifact
+	Dir      string
+}
+
+func (a *ArtifactDownloader) Download(artifact bosh.Artifact, dir string) (bool, error) {
+	a.DownloadCall.CallCount++
+	a.DownloadCall.Receives = append(a.DownloadCall.Receives, DownloadCallReceive{Artifact: artifact, Dir: dir})
+	return a.DownloadCall.Returns.Downloaded, a.DownloadCall.Returns.Error
+}
@@ -0,0 +1,63 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/bosh"
+
+type ArtifactResolver struct {
+	BOSHReleaseCall struct {
+		CallCount int
+		Receives  struct {
+			Version string
+		}
+		Returns struct {
+			Artifact bosh.Artifact
+			Error    error
+		}
+	}
+
+	CPIReleaseCall struct {
+		CallCount int
+		Receives  struct {
+			IAAS    string
+			Version string
+		}
+		Returns struct {
+			Artifact bosh.Artifact
+			Error    error
+		}
+	}
+
+	StemcellCall struct {
+		CallCount int
+		Receives  struct {
+			IAAS    string
+			Version string
+		}
+		Returns struct {
+			Artifact bosh.Artifact
+			Error    error
+		}
+	}
+}
+
+func (a *ArtifactResolver) BOSHRelease(version string) (bosh.Artifact, error) {
+	a.BOSHReleaseCall.CallCount++
+	a.BOSHReleaseCall.Receives.Version = version
+
+	return a.BOSHReleaseCall.Returns.Artifact, a.BOSHReleaseCall.Returns.Error
+}
+
+func (a *ArtifactResolver) CPIRelease(iaas, version string) (bosh.Artifact, error) {
+	a.CPIReleaseCall.CallCount++
+	a.CPIReleaseCall.Receives.IAAS = iaas
+	a.CPIReleaseCall.Receives.Version = version
+
+	return a.CPIReleaseCall.Returns.Artifact, a.CPIReleaseCall.Returns.Error
+}
+
+func (a *ArtifactResolver) Stemcell(iaas, version string) (bosh.Artifact, error) {
+	a.StemcellCall.CallCount++
+	a.StemcellCall.Receives.IAAS = iaas
+	a.StemcellCall.Receives.Version = version
+
+	return a.StemcellCall.Returns.Artifact, a.StemcellCall.Returns.Error
+}
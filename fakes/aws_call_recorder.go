@@ -0,0 +1,31 @@
+package fakes
+
+import "time"
+
+type AWSCallRecorder struct {
+	AWSCallCall struct {
+		CallCount int
+		Receives  []AWSCallReceive
+	}
+}
+
+type AWSCallReceive struct {
+	Service   string
+	Operation string
+	Started   time.Time
+	Duration  time.Duration
+	Retries   int
+	Error     error
+}
+
+func (r *AWSCallRecorder) AWSCall(service, operation string, started time.Time, duration time.Duration, retries int, err error) {
+	r.AWSCallCall.CallCount++
+	r.AWSCallCall.Receives = append(r.AWSCallCall.Receives, AWSCallReceive{
+		Service:   service,
+		Operation: operation,
+		Started:   started,
+		Duration:  duration,
+		Retries:   retries,
+		Error:     err,
+	})
+}
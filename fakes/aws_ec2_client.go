@@ -34,6 +34,69 @@ type AWSEC2Client struct {
 			Error  error
 		}
 	}
+
+	DescribeKeyPairsCall struct {
+		Receives struct {
+			Input *awsec2.DescribeKeyPairsInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeKeyPairsOutput
+			Error  error
+		}
+	}
+
+	DescribeImagesCall struct {
+		CallCount int
+		Receives  []*awsec2.DescribeImagesInput
+		Returns   struct {
+			Output *awsec2.DescribeImagesOutput
+			Error  error
+		}
+	}
+
+	CopyImageCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsec2.CopyImageInput
+		}
+		Returns struct {
+			Output *awsec2.CopyImageOutput
+			Error  error
+		}
+	}
+
+	DescribeRegionsCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsec2.DescribeRegionsInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeRegionsOutput
+			Error  error
+		}
+	}
+
+	DescribeAccountAttributesCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsec2.DescribeAccountAttributesInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeAccountAttributesOutput
+			Error  error
+		}
+	}
+
+	DescribeAddressesCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsec2.DescribeAddressesInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeAddressesOutput
+			Error  error
+		}
+	}
 }
 
 func (c *AWSEC2Client) DescribeAvailabilityZones(input *awsec2.DescribeAvailabilityZonesInput) (*awsec2.DescribeAvailabilityZonesOutput, error) {
@@ -53,3 +116,43 @@ func (c *AWSEC2Client) DescribeVpcs(input *awsec2.DescribeVpcsInput) (*awsec2.De
 
 	return c.DescribeVpcsCall.Returns.Output, c.DescribeVpcsCall.Returns.Error
 }
+
+func (c *AWSEC2Client) DescribeKeyPairs(input *awsec2.DescribeKeyPairsInput) (*awsec2.DescribeKeyPairsOutput, error) {
+	c.DescribeKeyPairsCall.Receives.Input = input
+
+	return c.DescribeKeyPairsCall.Returns.Output, c.DescribeKeyPairsCall.Returns.Error
+}
+
+func (c *AWSEC2Client) DescribeImages(input *awsec2.DescribeImagesInput) (*awsec2.DescribeImagesOutput, error) {
+	c.DescribeImagesCall.CallCount++
+	c.DescribeImagesCall.Receives = append(c.DescribeImagesCall.Receives, input)
+
+	return c.DescribeImagesCall.Returns.Output, c.DescribeImagesCall.Returns.Error
+}
+
+func (c *AWSEC2Client) CopyImage(input *awsec2.CopyImageInput) (*awsec2.CopyImageOutput, error) {
+	c.CopyImageCall.CallCount++
+	c.CopyImageCall.Receives.Input = input
+
+	return c.CopyImageCall.Returns.Output, c.CopyImageCall.Returns.Error
+}
+
+func (c *AWSEC2Client) DescribeRegions(input *awsec2.DescribeRegionsInput) (*awsec2.DescribeRegionsOutput, error) {
+	c.DescribeRegionsCall.CallCount++
+	c.DescribeRegionsCall.Receives.Input = input
+	return c.DescribeRegionsCall.Returns.Output, c.DescribeRegionsCall.Returns.Error
+}
+
+func (c *AWSEC2Client) DescribeAccountAttributes(input *awsec2.DescribeAccountAttributesInput) (*awsec2.DescribeAccountAttributesOutput, error) {
+	c.DescribeAccountAttributesCall.CallCount++
+	c.DescribeAccountAttributesCall.Receives.Input = input
+
+	return c.DescribeAccountAttributesCall.Returns.Output, c.DescribeAccountAttributesCall.Returns.Error
+}
+
+func (c *AWSEC2Client) DescribeAddresses(input *awsec2.DescribeAddressesInput) (*awsec2.DescribeAddressesOutput, error) {
+	c.DescribeAddressesCall.CallCount++
+	c.DescribeAddressesCall.Receives.Input = input
+
+	return c.DescribeAddressesCall.Returns.Output, c.DescribeAddressesCall.Returns.Error
+}
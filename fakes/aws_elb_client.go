@@ -0,0 +1,61 @@
+package fakes
+
+import (
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+)
+
+type AWSELBClient struct {
+	SetLoadBalancerListenerSSLCertificateCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselb.SetLoadBalancerListenerSSLCertificateInput
+		}
+		Returns struct {
+			Output *awselb.SetLoadBalancerListenerSSLCertificateOutput
+			Error  error
+		}
+	}
+
+	DescribeAccountLimitsCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselb.DescribeAccountLimitsInput
+		}
+		Returns struct {
+			Output *awselb.DescribeAccountLimitsOutput
+			Error  error
+		}
+	}
+
+	DescribeLoadBalancersCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselb.DescribeLoadBalancersInput
+		}
+		Returns struct {
+			Output *awselb.DescribeLoadBalancersOutput
+			Error  error
+		}
+	}
+}
+
+func (c *AWSELBClient) SetLoadBalancerListenerSSLCertificate(input *awselb.SetLoadBalancerListenerSSLCertificateInput) (*awselb.SetLoadBalancerListenerSSLCertificateOutput, error) {
+	c.SetLoadBalancerListenerSSLCertificateCall.CallCount++
+	c.SetLoadBalancerListenerSSLCertificateCall.Receives.Input = input
+
+	return c.SetLoadBalancerListenerSSLCertificateCall.Returns.Output, c.SetLoadBalancerListenerSSLCertificateCall.Returns.Error
+}
+
+func (c *AWSELBClient) DescribeAccountLimits(input *awselb.DescribeAccountLimitsInput) (*awselb.DescribeAccountLimitsOutput, error) {
+	c.DescribeAccountLimitsCall.CallCount++
+	c.DescribeAccountLimitsCall.Receives.Input = input
+
+	return c.DescribeAccountLimitsCall.Returns.Output, c.DescribeAccountLimitsCall.Returns.Error
+}
+
+func (c *AWSELBClient) DescribeLoadBalancers(input *awselb.DescribeLoadBalancersInput) (*awselb.DescribeLoadBalancersOutput, error) {
+	c.DescribeLoadBalancersCall.CallCount++
+	c.DescribeLoadBalancersCall.Receives.Input = input
+
+	return c.DescribeLoadBalancersCall.Returns.Output, c.DescribeLoadBalancersCall.Returns.Error
+}
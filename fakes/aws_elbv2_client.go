@@ -0,0 +1,79 @@
+package fakes
+
+import (
+	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+type AWSELBV2Client struct {
+	DescribeLoadBalancersCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselbv2.DescribeLoadBalancersInput
+		}
+		Returns struct {
+			Output *awselbv2.DescribeLoadBalancersOutput
+			Error  error
+		}
+	}
+
+	DescribeListenersCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselbv2.DescribeListenersInput
+		}
+		Returns struct {
+			Output *awselbv2.DescribeListenersOutput
+			Error  error
+		}
+	}
+
+	ModifyListenerCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselbv2.ModifyListenerInput
+		}
+		Returns struct {
+			Output *awselbv2.ModifyListenerOutput
+			Error  error
+		}
+	}
+
+	DescribeAccountLimitsCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awselbv2.DescribeAccountLimitsInput
+		}
+		Returns struct {
+			Output *awselbv2.DescribeAccountLimitsOutput
+			Error  error
+		}
+	}
+}
+
+func (c *AWSELBV2Client) DescribeLoadBalancers(input *awselbv2.DescribeLoadBalancersInput) (*awselbv2.DescribeLoadBalancersOutput, error) {
+	c.DescribeLoadBalancersCall.CallCount++
+	c.DescribeLoadBalancersCall.Receives.Input = input
+
+	return c.DescribeLoadBalancersCall.Returns.Output, c.DescribeLoadBalancersCall.Returns.Error
+}
+
+func (c *AWSELBV2Client) DescribeListeners(input *awselbv2.DescribeListenersInput) (*awselbv2.DescribeListenersOutput, error) {
+	c.DescribeListenersCall.CallCount++
+	c.DescribeListenersCall.Receives.Input = input
+
+	return c.DescribeListenersCall.Returns.Output, c.DescribeListenersCall.Returns.Error
+}
+
+func (c *AWSELBV2Client) ModifyListener(input *awselbv2.ModifyListenerInput) (*awselbv2.ModifyListenerOutput, error) {
+	c.ModifyListenerCall.CallCount++
+	c.ModifyListenerCall.Receives.Input = input
+
+	return c.ModifyListenerCall.Returns.Output, c.ModifyListenerCall.Returns.Error
+}
+
+func (c *AWSELBV2Client) DescribeAccountLimits(input *awselbv2.DescribeAccountLimitsInput) (*awselbv2.DescribeAccountLimitsOutput, error) {
+	c.DescribeAccountLimitsCall.CallCount++
+	c.DescribeAccountLimitsCall.Receives.Input = input
+
+	return c.DescribeAccountLimitsCall.Returns.Output, c.DescribeAccountLimitsCall.Returns.Error
+}
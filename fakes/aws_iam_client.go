@@ -0,0 +1,121 @@
+package fakes
+
+import (
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+)
+
+type AWSIAMClient struct {
+	CreateServiceLinkedRoleCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsiam.CreateServiceLinkedRoleInput
+		}
+		Returns struct {
+			Output *awsiam.CreateServiceLinkedRoleOutput
+			Error  error
+		}
+	}
+
+	UploadServerCertificateCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsiam.UploadServerCertificateInput
+		}
+		Returns struct {
+			Output *awsiam.UploadServerCertificateOutput
+			Error  error
+		}
+	}
+
+	GetServerCertificateCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsiam.GetServerCertificateInput
+		}
+		Returns struct {
+			Output *awsiam.GetServerCertificateOutput
+			Error  error
+		}
+	}
+
+	DeleteServerCertificateCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awsiam.DeleteServerCertificateInput
+		}
+		Returns struct {
+			Output *awsiam.DeleteServerCertificateOutput
+			Error  error
+		}
+	}
+
+	GetRoleCall struct {
+		CallCount int
+		Receives  []*awsiam.GetRoleInput
+		Stub      func(*awsiam.GetRoleInput) (*awsiam.GetRoleOutput, error)
+	}
+
+	GetInstanceProfileCall struct {
+		CallCount int
+		Receives  []*awsiam.GetInstanceProfileInput
+		Stub      func(*awsiam.GetInstanceProfileInput) (*awsiam.GetInstanceProfileOutput, error)
+	}
+
+	SimulatePrincipalPolicyCall struct {
+		CallCount int
+		Receives  []*awsiam.SimulatePrincipalPolicyInput
+		Stub      func(*awsiam.SimulatePrincipalPolicyInput) (*awsiam.SimulatePolicyResponse, error)
+	}
+}
+
+func (a *AWSIAMClient) CreateServiceLinkedRole(input *awsiam.CreateServiceLinkedRoleInput) (*awsiam.CreateServiceLinkedRoleOutput, error) {
+	a.CreateServiceLinkedRoleCall.CallCount++
+	a.CreateServiceLinkedRoleCall.Receives.Input = input
+	return a.CreateServiceLinkedRoleCall.Returns.Output, a.CreateServiceLinkedRoleCall.Returns.Error
+}
+
+func (a *AWSIAMClient) UploadServerCertificate(input *awsiam.UploadServerCertificateInput) (*awsiam.UploadServerCertificateOutput, error) {
+	a.UploadServerCertificateCall.CallCount++
+	a.UploadServerCertificateCall.Receives.Input = input
+	return a.UploadServerCertificateCall.Returns.Output, a.UploadServerCertificateCall.Returns.Error
+}
+
+func (a *AWSIAMClient) GetServerCertificate(input *awsiam.GetServerCertificateInput) (*awsiam.GetServerCertificateOutput, error) {
+	a.GetServerCertificateCall.CallCount++
+	a.GetServerCertificateCall.Receives.Input = input
+	return a.GetServerCertificateCall.Returns.Output, a.GetServerCertificateCall.Returns.Error
+}
+
+func (a *AWSIAMClient) DeleteServerCertificate(input *awsiam.DeleteServerCertificateInput) (*awsiam.DeleteServerCertificateOutput, error) {
+	a.DeleteServerCertificateCall.CallCount++
+	a.DeleteServerCertificateCall.Receives.Input = input
+	return a.DeleteServerCertificateCall.Returns.Output, a.DeleteServerCertificateCall.Returns.Error
+}
+
+func (a *AWSIAMClient) GetRole(input *awsiam.GetRoleInput) (*awsiam.GetRoleOutput, error) {
+	a.GetRoleCall.CallCount++
+	a.GetRoleCall.Receives = append(a.GetRoleCall.Receives, input)
+	if a.GetRoleCall.Stub != nil {
+		return a.GetRoleCall.Stub(input)
+	}
+	return &awsiam.GetRoleOutput{}, nil
+}
+
+func (a *AWSIAMClient) GetInstanceProfile(input *awsiam.GetInstanceProfileInput) (*awsiam.GetInstanceProfileOutput, error) {
+	a.GetInstanceProfileCall.CallCount++
+	a.GetInstanceProfileCall.Receives = append(a.GetInstanceProfileCall.Receives, input)
+	if a.GetInstanceProfileCall.Stub != nil {
+		return a.GetInstanceProfileCall.Stub(input)
+	}
+	return &awsiam.GetInstanceProfileOutput{}, nil
+}
+
+func (a *AWSIAMClient) SimulatePrincipalPolicy(input *awsiam.SimulatePrincipalPolicyInput) (*awsiam.SimulatePolicyResponse, error) {
+	a.SimulatePrincipalPolicyCall.CallCount++
+	copied := *input
+	a.SimulatePrincipalPolicyCall.Receives = append(a.SimulatePrincipalPolicyCall.Receives, &copied)
+	if a.SimulatePrincipalPolicyCall.Stub != nil {
+		return a.SimulatePrincipalPolicyCall.Stub(input)
+	}
+	return &awsiam.SimulatePolicyResponse{}, nil
+}
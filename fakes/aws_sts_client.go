@@ -0,0 +1,58 @@
+package fakes
+
+import (
+	awssts "github.com/aws/aws-sdk-go/service/sts"
+)
+
+type AWSSTSClient struct {
+	AssumeRoleCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awssts.AssumeRoleInput
+		}
+		Returns struct {
+			Output *awssts.AssumeRoleOutput
+			Error  error
+		}
+	}
+
+	GetSessionTokenCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awssts.GetSessionTokenInput
+		}
+		Returns struct {
+			Output *awssts.GetSessionTokenOutput
+			Error  error
+		}
+	}
+
+	GetCallerIdentityCall struct {
+		CallCount int
+		Receives  struct {
+			Input *awssts.GetCallerIdentityInput
+		}
+		Returns struct {
+			Output *awssts.GetCallerIdentityOutput
+			Error  error
+		}
+	}
+}
+
+func (a *AWSSTSClient) AssumeRole(input *awssts.AssumeRoleInput) (*awssts.AssumeRoleOutput, error) {
+	a.AssumeRoleCall.CallCount++
+	a.AssumeRoleCall.Receives.Input = input
+	return a.AssumeRoleCall.Returns.Output, a.AssumeRoleCall.Returns.Error
+}
+
+func (a *AWSSTSClient) GetSessionToken(input *awssts.GetSessionTokenInput) (*awssts.GetSessionTokenOutput, error) {
+	a.GetSessionTokenCall.CallCount++
+	a.GetSessionTokenCall.Receives.Input = input
+	return a.GetSessionTokenCall.Returns.Output, a.GetSessionTokenCall.Returns.Error
+}
+
+func (a *AWSSTSClient) GetCallerIdentity(input *awssts.GetCallerIdentityInput) (*awssts.GetCallerIdentityOutput, error) {
+	a.GetCallerIdentityCall.CallCount++
+	a.GetCallerIdentityCall.Receives.Input = input
+	return a.GetCallerIdentityCall.Returns.Output, a.GetCallerIdentityCall.Returns.Error
+}
@@ -23,6 +23,21 @@ type BOSHClient struct {
 		}
 	}
 
+	CurlCall struct {
+		CallCount int
+		Receives  struct {
+			Method string
+			Path   string
+			Body   []byte
+		}
+		Returns struct {
+			Status int
+			Body   []byte
+			Error  error
+		}
+		Stub func(method, path string, body []byte) (int, []byte, error)
+	}
+
 	InfoCall struct {
 		CallCount int
 		Returns   struct {
@@ -47,3 +62,14 @@ func (c *BOSHClient) Info() (bosh.Info, error) {
 	c.InfoCall.CallCount++
 	return c.InfoCall.Returns.Info, c.InfoCall.Returns.Error
 }
+
+func (c *BOSHClient) Curl(method, path string, body []byte) (int, []byte, error) {
+	c.CurlCall.CallCount++
+	c.CurlCall.Receives.Method = method
+	c.CurlCall.Receives.Path = path
+	c.CurlCall.Receives.Body = body
+	if c.CurlCall.Stub != nil {
+		return c.CurlCall.Stub(method, path, body)
+	}
+	return c.CurlCall.Returns.Status, c.CurlCall.Returns.Body, c.CurlCall.Returns.Error
+}
@@ -45,6 +45,7 @@ type BOSHManager struct {
 			State storage.State
 			Error error
 		}
+		Stub func(state storage.State) (storage.State, error)
 	}
 	VersionCall struct {
 		CallCount int
@@ -53,6 +54,16 @@ type BOSHManager struct {
 			Error   error
 		}
 	}
+	IsDeployedCall struct {
+		CallCount int
+		Receives  struct {
+			Deployments []string
+		}
+		Returns struct {
+			Deployed map[string]bool
+			Error    error
+		}
+	}
 	DeleteDirectorCall struct {
 		CallCount int
 		Receives  struct {
@@ -83,6 +94,16 @@ type BOSHManager struct {
 			Vars string
 		}
 	}
+	GetBOSHDeploymentVarsCall struct {
+		CallCount int
+		Receives  struct {
+			State            storage.State
+			TerraformOutputs terraform.Outputs
+		}
+		Returns struct {
+			Vars string
+		}
+	}
 	GetJumpboxDeploymentVarsCall struct {
 		CallCount int
 		Receives  struct {
@@ -93,6 +114,16 @@ type BOSHManager struct {
 			Vars string
 		}
 	}
+	WriteDeploymentVarsCall struct {
+		CallCount int
+		Receives  struct {
+			State            storage.State
+			TerraformOutputs terraform.Outputs
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func (b *BOSHManager) InitializeJumpbox(state storage.State) error {
@@ -118,6 +149,9 @@ func (b *BOSHManager) CreateDirector(state storage.State, terraformOutputs terra
 	b.CreateDirectorCall.CallCount++
 	b.CreateDirectorCall.Receives.State = state
 	b.CreateDirectorCall.Receives.TerraformOutputs = terraformOutputs
+	if b.CreateDirectorCall.Stub != nil {
+		return b.CreateDirectorCall.Stub(state)
+	}
 	return b.CreateDirectorCall.Returns.State, b.CreateDirectorCall.Returns.Error
 }
 
@@ -142,6 +176,13 @@ func (b *BOSHManager) GetDirectorDeploymentVars(state storage.State, terraformOu
 	return b.GetDirectorDeploymentVarsCall.Returns.Vars
 }
 
+func (b *BOSHManager) GetBOSHDeploymentVars(state storage.State, terraformOutputs terraform.Outputs) string {
+	b.GetBOSHDeploymentVarsCall.CallCount++
+	b.GetBOSHDeploymentVarsCall.Receives.State = state
+	b.GetBOSHDeploymentVarsCall.Receives.TerraformOutputs = terraformOutputs
+	return b.GetBOSHDeploymentVarsCall.Returns.Vars
+}
+
 func (b *BOSHManager) GetJumpboxDeploymentVars(state storage.State, terraformOutputs terraform.Outputs) string {
 	b.GetJumpboxDeploymentVarsCall.CallCount++
 	b.GetJumpboxDeploymentVarsCall.Receives.State = state
@@ -149,7 +190,20 @@ func (b *BOSHManager) GetJumpboxDeploymentVars(state storage.State, terraformOut
 	return b.GetJumpboxDeploymentVarsCall.Returns.Vars
 }
 
+func (b *BOSHManager) WriteDeploymentVars(state storage.State, terraformOutputs terraform.Outputs) error {
+	b.WriteDeploymentVarsCall.CallCount++
+	b.WriteDeploymentVarsCall.Receives.State = state
+	b.WriteDeploymentVarsCall.Receives.TerraformOutputs = terraformOutputs
+	return b.WriteDeploymentVarsCall.Returns.Error
+}
+
 func (b *BOSHManager) Version() (string, error) {
 	b.VersionCall.CallCount++
 	return b.VersionCall.Returns.Version, b.VersionCall.Returns.Error
 }
+
+func (b *BOSHManager) IsDeployed(deployment string) (bool, error) {
+	b.IsDeployedCall.CallCount++
+	b.IsDeployedCall.Receives.Deployments = append(b.IsDeployedCall.Receives.Deployments, deployment)
+	return b.IsDeployedCall.Returns.Deployed[deployment], b.IsDeployedCall.Returns.Error
+}
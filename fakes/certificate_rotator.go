@@ -0,0 +1,47 @@
+package fakes
+
+type SetListenerCertificateReceive struct {
+	LBName         string
+	Port           int64
+	CertificateARN string
+}
+
+type CertificateRotator struct {
+	CertificateUploader
+
+	SetListenerCertificateCall struct {
+		CallCount int
+		Receives  []SetListenerCertificateReceive
+		Returns   struct {
+			Error error
+		}
+	}
+
+	SetLBListenerCertificateCall struct {
+		CallCount int
+		Receives  []SetListenerCertificateReceive
+		Returns   struct {
+			Error error
+		}
+	}
+}
+
+func (c *CertificateRotator) SetListenerCertificate(lbName string, port int64, certificateARN string) error {
+	c.SetListenerCertificateCall.CallCount++
+	c.SetListenerCertificateCall.Receives = append(c.SetListenerCertificateCall.Receives, SetListenerCertificateReceive{
+		LBName:         lbName,
+		Port:           port,
+		CertificateARN: certificateARN,
+	})
+	return c.SetListenerCertificateCall.Returns.Error
+}
+
+func (c *CertificateRotator) SetLBListenerCertificate(lbName string, port int64, certificateARN string) error {
+	c.SetLBListenerCertificateCall.CallCount++
+	c.SetLBListenerCertificateCall.Receives = append(c.SetLBListenerCertificateCall.Receives, SetListenerCertificateReceive{
+		LBName:         lbName,
+		Port:           port,
+		CertificateARN: certificateARN,
+	})
+	return c.SetLBListenerCertificateCall.Returns.Error
+}
@@ -0,0 +1,43 @@
+package fakes
+
+type CertificateUploader struct {
+	UploadServerCertificateCall struct {
+		CallCount int
+		Receives  struct {
+			Name  string
+			Cert  []byte
+			Key   []byte
+			Chain []byte
+		}
+		Returns struct {
+			ARN     string
+			Created bool
+			Error   error
+		}
+	}
+
+	DeleteServerCertificateCall struct {
+		CallCount int
+		Receives  struct {
+			Name string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (c *CertificateUploader) UploadServerCertificate(name string, cert, key, chain []byte) (string, bool, error) {
+	c.UploadServerCertificateCall.CallCount++
+	c.UploadServerCertificateCall.Receives.Name = name
+	c.UploadServerCertificateCall.Receives.Cert = cert
+	c.UploadServerCertificateCall.Receives.Key = key
+	c.UploadServerCertificateCall.Receives.Chain = chain
+	return c.UploadServerCertificateCall.Returns.ARN, c.UploadServerCertificateCall.Returns.Created, c.UploadServerCertificateCall.Returns.Error
+}
+
+func (c *CertificateUploader) DeleteServerCertificate(name string) error {
+	c.DeleteServerCertificateCall.CallCount++
+	c.DeleteServerCertificateCall.Receives.Name = name
+	return c.DeleteServerCertificateCall.Returns.Error
+}
@@ -1,6 +1,10 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type Command struct {
 	CheckFastFailsCall struct {
@@ -40,7 +44,7 @@ func (c *Command) CheckFastFails(subcommandFlags []string, state storage.State)
 	return c.CheckFastFailsCall.Returns.Error
 }
 
-func (c *Command) Execute(subcommandFlags []string, state storage.State) error {
+func (c *Command) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	c.ExecuteCall.CallCount++
 	c.ExecuteCall.Receives.State = state
 	c.ExecuteCall.Receives.SubcommandFlags = subcommandFlags
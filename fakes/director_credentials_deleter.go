@@ -0,0 +1,16 @@
+package fakes
+
+type DirectorCredentialsDeleter struct {
+	DeleteCall struct {
+		CallCount int
+		Returns   struct {
+			Error error
+		}
+	}
+}
+
+func (d *DirectorCredentialsDeleter) Delete() error {
+	d.DeleteCall.CallCount++
+
+	return d.DeleteCall.Returns.Error
+}
@@ -0,0 +1,43 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/verifier"
+)
+
+type DirectorVerifier struct {
+	SnapshotCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			Snapshot verifier.Snapshot
+			Error    error
+		}
+	}
+
+	VerifyCall struct {
+		CallCount int
+		Receives  struct {
+			State    storage.State
+			Snapshot verifier.Snapshot
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (d *DirectorVerifier) Snapshot(state storage.State) (verifier.Snapshot, error) {
+	d.SnapshotCall.CallCount++
+	d.SnapshotCall.Receives.State = state
+	return d.SnapshotCall.Returns.Snapshot, d.SnapshotCall.Returns.Error
+}
+
+func (d *DirectorVerifier) Verify(state storage.State, before verifier.Snapshot) error {
+	d.VerifyCall.CallCount++
+	d.VerifyCall.Receives.State = state
+	d.VerifyCall.Receives.Snapshot = before
+	return d.VerifyCall.Returns.Error
+}
@@ -61,6 +61,17 @@ type FileIO struct {
 		}
 	}
 
+	OpenCall struct {
+		CallCount int
+		Receives  struct {
+			Name string
+		}
+		Returns struct {
+			File  afero.File
+			Error error
+		}
+	}
+
 	RenameCall struct {
 		CallCount int
 		Receives  struct {
@@ -181,6 +192,12 @@ func (f *FileIO) Stat(name string) (os.FileInfo, error) {
 	return f.StatCall.Fake(name)
 }
 
+func (f *FileIO) Open(name string) (afero.File, error) {
+	f.OpenCall.CallCount++
+	f.OpenCall.Receives.Name = name
+	return f.OpenCall.Returns.File, f.OpenCall.Returns.Error
+}
+
 func (f *FileIO) Rename(oldpath, newpath string) error {
 	f.RenameCall.CallCount++
 	f.RenameCall.Receives.Oldpath = oldpath
@@ -0,0 +1,17 @@
+package fakes
+
+import "net/http"
+
+type HTTPGetter struct {
+	GetCall struct {
+		CallCount int
+		Receives  []string
+		Stub      func(url string) (*http.Response, error)
+	}
+}
+
+func (h *HTTPGetter) Get(url string) (*http.Response, error) {
+	h.GetCall.CallCount++
+	h.GetCall.Receives = append(h.GetCall.Receives, url)
+	return h.GetCall.Stub(url)
+}
@@ -0,0 +1,66 @@
+package fakes
+
+type ImageCopier struct {
+	ImageStateCall struct {
+		CallCount int
+		Stub      func(string) (string, error)
+		Receives  struct {
+			ImageID string
+		}
+		Returns struct {
+			State string
+			Error error
+		}
+	}
+
+	FindOwnImageCall struct {
+		CallCount int
+		Receives  struct {
+			Name string
+		}
+		Returns struct {
+			ImageID string
+			Error   error
+		}
+	}
+
+	CopyImageCall struct {
+		CallCount int
+		Receives  struct {
+			SourceRegion  string
+			SourceImageID string
+			Name          string
+		}
+		Returns struct {
+			ImageID string
+			Error   error
+		}
+	}
+}
+
+func (i *ImageCopier) ImageState(imageID string) (string, error) {
+	i.ImageStateCall.CallCount++
+	i.ImageStateCall.Receives.ImageID = imageID
+
+	if i.ImageStateCall.Stub != nil {
+		return i.ImageStateCall.Stub(imageID)
+	}
+
+	return i.ImageStateCall.Returns.State, i.ImageStateCall.Returns.Error
+}
+
+func (i *ImageCopier) FindOwnImage(name string) (string, error) {
+	i.FindOwnImageCall.CallCount++
+	i.FindOwnImageCall.Receives.Name = name
+
+	return i.FindOwnImageCall.Returns.ImageID, i.FindOwnImageCall.Returns.Error
+}
+
+func (i *ImageCopier) CopyImage(sourceRegion, sourceImageID, name string) (string, error) {
+	i.CopyImageCall.CallCount++
+	i.CopyImageCall.Receives.SourceRegion = sourceRegion
+	i.CopyImageCall.Receives.SourceImageID = sourceImageID
+	i.CopyImageCall.Receives.Name = name
+
+	return i.CopyImageCall.Returns.ImageID, i.CopyImageCall.Returns.Error
+}
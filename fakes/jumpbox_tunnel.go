@@ -0,0 +1,23 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type JumpboxTunnel struct {
+	TunnelAddrCall struct {
+		CallCount int
+		Receives  struct {
+			Jumpbox storage.Jumpbox
+		}
+		Returns struct {
+			Addr  string
+			Error error
+		}
+	}
+}
+
+func (j *JumpboxTunnel) TunnelAddr(jumpbox storage.Jumpbox) (string, error) {
+	j.TunnelAddrCall.CallCount++
+	j.TunnelAddrCall.Receives.Jumpbox = jumpbox
+
+	return j.TunnelAddrCall.Returns.Addr, j.TunnelAddrCall.Returns.Error
+}
@@ -0,0 +1,38 @@
+package fakes
+
+type KMSClient struct {
+	GenerateDataKeyCall struct {
+		CallCount int
+		Receives  struct {
+			KeyID string
+		}
+		Returns struct {
+			Plaintext  []byte
+			Ciphertext []byte
+			Error      error
+		}
+	}
+
+	DecryptCall struct {
+		CallCount int
+		Receives  struct {
+			Ciphertext []byte
+		}
+		Returns struct {
+			Plaintext []byte
+			Error     error
+		}
+	}
+}
+
+func (k *KMSClient) GenerateDataKey(keyID string) ([]byte, []byte, error) {
+	k.GenerateDataKeyCall.CallCount++
+	k.GenerateDataKeyCall.Receives.KeyID = keyID
+	return k.GenerateDataKeyCall.Returns.Plaintext, k.GenerateDataKeyCall.Returns.Ciphertext, k.GenerateDataKeyCall.Returns.Error
+}
+
+func (k *KMSClient) Decrypt(ciphertext []byte) ([]byte, error) {
+	k.DecryptCall.CallCount++
+	k.DecryptCall.Receives.Ciphertext = ciphertext
+	return k.DecryptCall.Returns.Plaintext, k.DecryptCall.Returns.Error
+}
@@ -1,6 +1,10 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type LBs struct {
 	Name        string
@@ -16,7 +20,7 @@ type LBs struct {
 	}
 }
 
-func (u *LBs) Execute(subcommandFlags []string, state storage.State) error {
+func (u *LBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	u.ExecuteCall.CallCount++
 	u.ExecuteCall.Receives.SubcommandFlags = subcommandFlags
 	u.ExecuteCall.Receives.State = state
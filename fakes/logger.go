@@ -30,6 +30,15 @@ type Logger struct {
 		Messages []string
 	}
 
+	DebugfCall struct {
+		CallCount int
+		Receives  struct {
+			Message   string
+			Arguments []interface{}
+		}
+		Messages []string
+	}
+
 	PrintlnCall struct {
 		CallCount int
 		Stub      func(string)
@@ -48,6 +57,25 @@ type Logger struct {
 			Proceed bool
 		}
 	}
+	PromptForNameCall struct {
+		CallCount int
+		Receives  struct {
+			Message string
+			Name    string
+		}
+		Returns struct {
+			Proceed bool
+		}
+	}
+	PromptForInputCall struct {
+		CallCount int
+		Receives  struct {
+			Message string
+		}
+		Returns struct {
+			Input string
+		}
+	}
 }
 
 func (l *Logger) Step(message string, a ...interface{}) {
@@ -70,6 +98,17 @@ func (l *Logger) Printf(message string, a ...interface{}) {
 	l.PrintfCall.Messages = append(l.PrintfCall.Messages, fmt.Sprintf(message, a...))
 }
 
+func (l *Logger) Debugf(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.DebugfCall.CallCount++
+	l.DebugfCall.Receives.Message = message
+	l.DebugfCall.Receives.Arguments = a
+
+	l.DebugfCall.Messages = append(l.DebugfCall.Messages, fmt.Sprintf(message, a...))
+}
+
 func (l *Logger) Println(message string) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
@@ -97,3 +136,18 @@ func (l *Logger) PrintlnMessages() []string {
 
 	return l.PrintlnCall.Messages
 }
+
+func (l *Logger) PromptForName(message, name string) bool {
+	l.PromptForNameCall.CallCount++
+	l.PromptForNameCall.Receives.Message = message
+	l.PromptForNameCall.Receives.Name = name
+
+	return l.PromptForNameCall.Returns.Proceed
+}
+
+func (l *Logger) PromptForInput(message string) string {
+	l.PromptForInputCall.CallCount++
+	l.PromptForInputCall.Receives.Message = message
+
+	return l.PromptForInputCall.Returns.Input
+}
@@ -11,6 +11,17 @@ type NetworkClient struct {
 			Error  error
 		}
 	}
+
+	ConflictingResourcesCall struct {
+		CallCount int
+		Receives  struct {
+			EnvIDs []string
+		}
+		Returns struct {
+			Conflicts map[string][]string
+			Error     error
+		}
+	}
 }
 
 func (n *NetworkClient) CheckExists(name string) (bool, error) {
@@ -18,3 +29,9 @@ func (n *NetworkClient) CheckExists(name string) (bool, error) {
 	n.CheckExistsCall.Receives.Name = name
 	return n.CheckExistsCall.Returns.Exists, n.CheckExistsCall.Returns.Error
 }
+
+func (n *NetworkClient) ConflictingResources(envID string) ([]string, error) {
+	n.ConflictingResourcesCall.CallCount++
+	n.ConflictingResourcesCall.Receives.EnvIDs = append(n.ConflictingResourcesCall.Receives.EnvIDs, envID)
+	return n.ConflictingResourcesCall.Returns.Conflicts[envID], n.ConflictingResourcesCall.Returns.Error
+}
@@ -0,0 +1,64 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type Operations struct {
+	StartCall struct {
+		CallCount int
+		Receives  struct {
+			Command string
+		}
+		Returns struct {
+			Operation storage.Operation
+			Error     error
+		}
+	}
+
+	GetCall struct {
+		CallCount int
+		Receives  struct {
+			ID string
+		}
+		Returns []GetOperationReturn
+	}
+
+	ListCall struct {
+		CallCount int
+		Returns   struct {
+			Operations []storage.Operation
+			Error      error
+		}
+	}
+}
+
+type GetOperationReturn struct {
+	Operation storage.Operation
+	Error     error
+}
+
+func (o *Operations) Start(command string) (storage.Operation, error) {
+	o.StartCall.CallCount++
+	o.StartCall.Receives.Command = command
+	return o.StartCall.Returns.Operation, o.StartCall.Returns.Error
+}
+
+// Get returns the given operations in turn, and the last one from then on.
+func (o *Operations) Get(id string) (storage.Operation, error) {
+	o.GetCall.CallCount++
+	o.GetCall.Receives.ID = id
+
+	if len(o.GetCall.Returns) == 0 {
+		return storage.Operation{}, nil
+	}
+
+	i := o.GetCall.CallCount - 1
+	if i >= len(o.GetCall.Returns) {
+		i = len(o.GetCall.Returns) - 1
+	}
+	return o.GetCall.Returns[i].Operation, o.GetCall.Returns[i].Error
+}
+
+func (o *Operations) List() ([]storage.Operation, error) {
+	o.ListCall.CallCount++
+	return o.ListCall.Returns.Operations, o.ListCall.Returns.Error
+}
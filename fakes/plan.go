@@ -1,6 +1,8 @@
 package fakes
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -57,6 +59,23 @@ type Plan struct {
 			IsInitialized bool
 		}
 	}
+	CheckLBWorkloadsCall struct {
+		CallCount int
+		Receives  struct {
+			Plan  commands.PlanConfig
+			State storage.State
+		}
+	}
+	CheckLBRemovalCall struct {
+		CallCount int
+		Receives  struct {
+			Plan  commands.PlanConfig
+			State storage.State
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func (p *Plan) CheckFastFails(subcommandFlags []string, state storage.State) error {
@@ -75,7 +94,7 @@ func (p *Plan) ParseArgs(args []string, state storage.State) (commands.PlanConfi
 	return p.ParseArgsCall.Returns.Config, p.ParseArgsCall.Returns.Error
 }
 
-func (p *Plan) Execute(args []string, state storage.State) error {
+func (p *Plan) Execute(ctx context.Context, args []string, state storage.State) error {
 	p.ExecuteCall.CallCount++
 	p.ExecuteCall.Receives.Args = args
 	p.ExecuteCall.Receives.State = state
@@ -97,3 +116,17 @@ func (p *Plan) IsInitialized(state storage.State) bool {
 
 	return p.IsInitializedCall.Returns.IsInitialized
 }
+
+func (p *Plan) CheckLBWorkloads(plan commands.PlanConfig, state storage.State) {
+	p.CheckLBWorkloadsCall.CallCount++
+	p.CheckLBWorkloadsCall.Receives.Plan = plan
+	p.CheckLBWorkloadsCall.Receives.State = state
+}
+
+func (p *Plan) CheckLBRemoval(plan commands.PlanConfig, state storage.State) error {
+	p.CheckLBRemovalCall.CallCount++
+	p.CheckLBRemovalCall.Receives.Plan = plan
+	p.CheckLBRemovalCall.Receives.State = state
+
+	return p.CheckLBRemovalCall.Returns.Error
+}
@@ -0,0 +1,70 @@
+package fakes
+
+type PreflightClient struct {
+	CallerIdentityCall struct {
+		CallCount int
+		Returns   struct {
+			Account string
+			ARN     string
+			Error   error
+		}
+	}
+
+	RegionExistsCall struct {
+		CallCount int
+		Receives  struct {
+			Region string
+		}
+		Returns struct {
+			Exists bool
+			Error  error
+		}
+	}
+
+	DeniedActionsCall struct {
+		CallCount int
+		Receives  struct {
+			CallerARN string
+			Actions   []string
+		}
+		Returns struct {
+			Denied []string
+			Error  error
+		}
+	}
+
+	ConflictingResourcesCall struct {
+		CallCount int
+		Receives  struct {
+			EnvID string
+		}
+		Returns struct {
+			Conflicts []string
+			Error     error
+		}
+	}
+}
+
+func (p *PreflightClient) CallerIdentity() (string, string, error) {
+	p.CallerIdentityCall.CallCount++
+	return p.CallerIdentityCall.Returns.Account, p.CallerIdentityCall.Returns.ARN, p.CallerIdentityCall.Returns.Error
+}
+
+func (p *PreflightClient) RegionExists(region string) (bool, error) {
+	p.RegionExistsCall.CallCount++
+	p.RegionExistsCall.Receives.Region = region
+	return p.RegionExistsCall.Returns.Exists, p.RegionExistsCall.Returns.Error
+}
+
+func (p *PreflightClient) DeniedActions(callerARN string, actions []string) ([]string, error) {
+	p.DeniedActionsCall.CallCount++
+	p.DeniedActionsCall.Receives.CallerARN = callerARN
+	p.DeniedActionsCall.Receives.Actions = actions
+	return p.DeniedActionsCall.Returns.Denied, p.DeniedActionsCall.Returns.Error
+}
+
+func (p *PreflightClient) ConflictingResources(envID string) ([]string, error) {
+	p.ConflictingResourcesCall.CallCount++
+	p.ConflictingResourcesCall.Receives.EnvID = envID
+	return p.ConflictingResourcesCall.Returns.Conflicts, p.ConflictingResourcesCall.Returns.Error
+}
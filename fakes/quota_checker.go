@@ -0,0 +1,27 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type QuotaChecker struct {
+	QuotaShortfallsCall struct {
+		CallCount int
+		Receives  struct {
+			State    storage.State
+			Existing aws.ExistingResources
+		}
+		Returns struct {
+			Shortfalls []string
+			Error      error
+		}
+	}
+}
+
+func (q *QuotaChecker) QuotaShortfalls(state storage.State, existing aws.ExistingResources) ([]string, error) {
+	q.QuotaShortfallsCall.CallCount++
+	q.QuotaShortfallsCall.Receives.State = state
+	q.QuotaShortfallsCall.Receives.Existing = existing
+	return q.QuotaShortfallsCall.Returns.Shortfalls, q.QuotaShortfallsCall.Returns.Error
+}
@@ -1,5 +1,7 @@
 package fakes
 
+import proxy "github.com/cloudfoundry/socks5-proxy"
+
 type Socks5Proxy struct {
 	StartCall struct {
 		CallCount int
@@ -11,6 +13,15 @@ type Socks5Proxy struct {
 			Error error
 		}
 	}
+	StartWithDialerCall struct {
+		CallCount int
+		Receives  struct {
+			Dialer proxy.DialFunc
+		}
+		Returns struct {
+			Error error
+		}
+	}
 	AddrCall struct {
 		CallCount int
 		Returns   struct {
@@ -28,6 +39,13 @@ func (s *Socks5Proxy) Start(jumpboxPrivateKey, jumpboxExternalURL string) error
 	return s.StartCall.Returns.Error
 }
 
+func (s *Socks5Proxy) StartWithDialer(dialer proxy.DialFunc) error {
+	s.StartWithDialerCall.CallCount++
+	s.StartWithDialerCall.Receives.Dialer = dialer
+
+	return s.StartWithDialerCall.Returns.Error
+}
+
 func (s *Socks5Proxy) Addr() (string, error) {
 	s.AddrCall.CallCount++
 
@@ -0,0 +1,27 @@
+package fakes
+
+import "time"
+
+type SSHCertIssuer struct {
+	IssueCall struct {
+		CallCount int
+		Receives  struct {
+			Deployment string
+			PublicKey  []byte
+			TTL        time.Duration
+		}
+		Returns struct {
+			Certificate string
+			Error       error
+		}
+	}
+}
+
+func (s *SSHCertIssuer) Issue(deployment string, publicKey []byte, ttl time.Duration) (string, error) {
+	s.IssueCall.CallCount++
+	s.IssueCall.Receives.Deployment = deployment
+	s.IssueCall.Receives.PublicKey = publicKey
+	s.IssueCall.Receives.TTL = ttl
+
+	return s.IssueCall.Returns.Certificate, s.IssueCall.Returns.Error
+}
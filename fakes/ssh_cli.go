@@ -0,0 +1,23 @@
+package fakes
+
+type SSHCLI struct {
+	RunCall struct {
+		CallCount int
+		Receives  struct {
+			Args []string
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(args []string) error
+	}
+}
+
+func (s *SSHCLI) Run(args []string) error {
+	s.RunCall.CallCount++
+	s.RunCall.Receives.Args = args
+	if s.RunCall.Stub != nil {
+		return s.RunCall.Stub(args)
+	}
+	return s.RunCall.Returns.Error
+}
@@ -10,12 +10,16 @@ type SSHKeyGetter struct {
 			PrivateKey string
 			Error      error
 		}
+		Stub func(deployment string) (string, error)
 	}
 }
 
 func (s *SSHKeyGetter) Get(deployment string) (string, error) {
 	s.GetCall.CallCount++
 	s.GetCall.Receives.Deployment = deployment
+	if s.GetCall.Stub != nil {
+		return s.GetCall.Stub(deployment)
+	}
 
 	return s.GetCall.Returns.PrivateKey, s.GetCall.Returns.Error
 }
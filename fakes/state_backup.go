@@ -0,0 +1,15 @@
+package fakes
+
+type StateBackup struct {
+	BackupCall struct {
+		CallCount int
+		Returns   struct {
+			Error error
+		}
+	}
+}
+
+func (s *StateBackup) Backup() error {
+	s.BackupCall.CallCount++
+	return s.BackupCall.Returns.Error
+}
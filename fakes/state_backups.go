@@ -0,0 +1,32 @@
+package fakes
+
+type StateBackups struct {
+	ListCall struct {
+		CallCount int
+		Returns   struct {
+			Timestamps []string
+			Error      error
+		}
+	}
+
+	RestoreCall struct {
+		CallCount int
+		Receives  struct {
+			Timestamp string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (s *StateBackups) List() ([]string, error) {
+	s.ListCall.CallCount++
+	return s.ListCall.Returns.Timestamps, s.ListCall.Returns.Error
+}
+
+func (s *StateBackups) Restore(timestamp string) error {
+	s.RestoreCall.CallCount++
+	s.RestoreCall.Receives.Timestamp = timestamp
+	return s.RestoreCall.Returns.Error
+}
@@ -0,0 +1,42 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type StateCipher struct {
+	EnableCall struct {
+		CallCount int
+		Receives  struct {
+			State     storage.State
+			KMSKeyID  string
+			KMSClient storage.KMSClient
+		}
+		Returns struct {
+			State storage.State
+			Error error
+		}
+	}
+
+	DisableCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			State storage.State
+		}
+	}
+}
+
+func (s *StateCipher) Enable(state storage.State, kmsKeyID string, kmsClient storage.KMSClient) (storage.State, error) {
+	s.EnableCall.CallCount++
+	s.EnableCall.Receives.State = state
+	s.EnableCall.Receives.KMSKeyID = kmsKeyID
+	s.EnableCall.Receives.KMSClient = kmsClient
+	return s.EnableCall.Returns.State, s.EnableCall.Returns.Error
+}
+
+func (s *StateCipher) Disable(state storage.State) storage.State {
+	s.DisableCall.CallCount++
+	s.DisableCall.Receives.State = state
+	return s.DisableCall.Returns.State
+}
@@ -0,0 +1,39 @@
+package fakes
+
+type StateLock struct {
+	LockCall struct {
+		CallCount int
+		Returns   struct {
+			Error error
+		}
+	}
+
+	UnlockCall struct {
+		CallCount int
+		Returns   struct {
+			Error error
+		}
+	}
+
+	IsLockedCall struct {
+		CallCount int
+		Returns   struct {
+			Locked bool
+		}
+	}
+}
+
+func (s *StateLock) Lock() error {
+	s.LockCall.CallCount++
+	return s.LockCall.Returns.Error
+}
+
+func (s *StateLock) Unlock() error {
+	s.UnlockCall.CallCount++
+	return s.UnlockCall.Returns.Error
+}
+
+func (s *StateLock) IsLocked() bool {
+	s.IsLockedCall.CallCount++
+	return s.IsLockedCall.Returns.Locked
+}
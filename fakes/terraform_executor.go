@@ -38,6 +38,15 @@ type TerraformExecutor struct {
 			Error error
 		}
 	}
+	PlanCall struct {
+		CallCount int
+		Receives  struct {
+			Credentials map[string]string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 	DestroyCall struct {
 		CallCount int
 		Receives  struct {
@@ -47,6 +56,36 @@ type TerraformExecutor struct {
 			Error error
 		}
 	}
+	TaintCall struct {
+		CallCount int
+		Receives  struct {
+			Resource string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+	StateListCall struct {
+		CallCount int
+		Returns   struct {
+			Resources []string
+			Error     error
+		}
+	}
+	StateMoveOutCall struct {
+		CallCount int
+		Receives  []StateMoveOutReceive
+		Returns   struct {
+			Error error
+		}
+	}
+	StateMoveInCall struct {
+		CallCount int
+		Receives  []StateMoveInReceive
+		Returns   struct {
+			Error error
+		}
+	}
 	VersionCall struct {
 		CallCount int
 		Returns   struct {
@@ -105,12 +144,51 @@ func (t *TerraformExecutor) Apply(credentials map[string]string) error {
 	return t.ApplyCall.Returns.Error
 }
 
+func (t *TerraformExecutor) Plan(credentials map[string]string) error {
+	t.PlanCall.CallCount++
+	t.PlanCall.Receives.Credentials = credentials
+	return t.PlanCall.Returns.Error
+}
+
 func (t *TerraformExecutor) Destroy(credentials map[string]string) error {
 	t.DestroyCall.CallCount++
 	t.DestroyCall.Receives.Credentials = credentials
 	return t.DestroyCall.Returns.Error
 }
 
+func (t *TerraformExecutor) Taint(resource string) error {
+	t.TaintCall.CallCount++
+	t.TaintCall.Receives.Resource = resource
+	return t.TaintCall.Returns.Error
+}
+
+type StateMoveOutReceive struct {
+	Resource string
+	ToState  string
+}
+
+type StateMoveInReceive struct {
+	FromState string
+	Resource  string
+}
+
+func (t *TerraformExecutor) StateList() ([]string, error) {
+	t.StateListCall.CallCount++
+	return t.StateListCall.Returns.Resources, t.StateListCall.Returns.Error
+}
+
+func (t *TerraformExecutor) StateMoveOut(resource, toState string) error {
+	t.StateMoveOutCall.CallCount++
+	t.StateMoveOutCall.Receives = append(t.StateMoveOutCall.Receives, StateMoveOutReceive{Resource: resource, ToState: toState})
+	return t.StateMoveOutCall.Returns.Error
+}
+
+func (t *TerraformExecutor) StateMoveIn(fromState, resource string) error {
+	t.StateMoveInCall.CallCount++
+	t.StateMoveInCall.Receives = append(t.StateMoveInCall.Receives, StateMoveInReceive{FromState: fromState, Resource: resource})
+	return t.StateMoveInCall.Returns.Error
+}
+
 func (t *TerraformExecutor) Version() (string, error) {
 	t.VersionCall.CallCount++
 	return t.VersionCall.Returns.Version, t.VersionCall.Returns.Error
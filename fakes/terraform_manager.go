@@ -25,6 +25,16 @@ type TerraformManager struct {
 			Error    error
 		}
 	}
+	PlanCall struct {
+		CallCount int
+		Receives  struct {
+			BBLState storage.State
+		}
+		Returns struct {
+			Output string
+			Error  error
+		}
+	}
 	DestroyCall struct {
 		CallCount int
 		Receives  struct {
@@ -46,6 +56,42 @@ type TerraformManager struct {
 			Error    error
 		}
 	}
+	TaintCall struct {
+		CallCount int
+		Receives  struct {
+			Resource string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+	ListResourcesCall struct {
+		CallCount int
+		Returns   struct {
+			Resources []string
+			Error     error
+		}
+	}
+	MoveResourcesOutCall struct {
+		CallCount int
+		Receives  struct {
+			Resources []string
+			ToState   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+	MoveResourcesInCall struct {
+		CallCount int
+		Receives  struct {
+			FromState string
+			Resources []string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 	GetOutputsCall struct {
 		CallCount int
 		Returns   struct {
@@ -89,6 +135,13 @@ func (t *TerraformManager) Apply(bblState storage.State) (storage.State, error)
 	return t.ApplyCall.Returns.BBLState, t.ApplyCall.Returns.Error
 }
 
+func (t *TerraformManager) Plan(bblState storage.State) (string, error) {
+	t.PlanCall.CallCount++
+	t.PlanCall.Receives.BBLState = bblState
+
+	return t.PlanCall.Returns.Output, t.PlanCall.Returns.Error
+}
+
 func (t *TerraformManager) Destroy(bblState storage.State) (storage.State, error) {
 	t.DestroyCall.CallCount++
 	t.DestroyCall.Receives.BBLState = bblState
@@ -104,6 +157,32 @@ func (t *TerraformManager) Import(bblState storage.State, outputs map[string]str
 	return t.ImportCall.Returns.BBLState, t.ImportCall.Returns.Error
 }
 
+func (t *TerraformManager) Taint(resource string) error {
+	t.TaintCall.CallCount++
+	t.TaintCall.Receives.Resource = resource
+
+	return t.TaintCall.Returns.Error
+}
+
+func (t *TerraformManager) ListResources() ([]string, error) {
+	t.ListResourcesCall.CallCount++
+	return t.ListResourcesCall.Returns.Resources, t.ListResourcesCall.Returns.Error
+}
+
+func (t *TerraformManager) MoveResourcesOut(resources []string, toState string) error {
+	t.MoveResourcesOutCall.CallCount++
+	t.MoveResourcesOutCall.Receives.Resources = resources
+	t.MoveResourcesOutCall.Receives.ToState = toState
+	return t.MoveResourcesOutCall.Returns.Error
+}
+
+func (t *TerraformManager) MoveResourcesIn(fromState string, resources []string) error {
+	t.MoveResourcesInCall.CallCount++
+	t.MoveResourcesInCall.Receives.FromState = fromState
+	t.MoveResourcesInCall.Receives.Resources = resources
+	return t.MoveResourcesInCall.Returns.Error
+}
+
 func (t *TerraformManager) GetOutputs() (terraform.Outputs, error) {
 	t.GetOutputsCall.CallCount++
 	return t.GetOutputsCall.Returns.Outputs, t.GetOutputsCall.Returns.Error
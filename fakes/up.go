@@ -1,6 +1,8 @@
 package fakes
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -37,6 +39,18 @@ type Up struct {
 			Error error
 		}
 	}
+	RunCall struct {
+		CallCount int
+		Receives  struct {
+			Options commands.UpOptions
+			Config  commands.PlanConfig
+			State   storage.State
+		}
+		Returns struct {
+			State storage.State
+			Error error
+		}
+	}
 }
 
 func (u *Up) CheckFastFails(subcommandFlags []string, state storage.State) error {
@@ -55,10 +69,19 @@ func (u *Up) ParseArgs(args []string, state storage.State) (commands.PlanConfig,
 	return u.ParseArgsCall.Returns.Config, u.ParseArgsCall.Returns.Error
 }
 
-func (u *Up) Execute(args []string, state storage.State) error {
+func (u *Up) Execute(ctx context.Context, args []string, state storage.State) error {
 	u.ExecuteCall.CallCount++
 	u.ExecuteCall.Receives.Args = args
 	u.ExecuteCall.Receives.State = state
 
 	return u.ExecuteCall.Returns.Error
 }
+
+func (u *Up) Run(ctx context.Context, options commands.UpOptions, config commands.PlanConfig, state storage.State) (storage.State, error) {
+	u.RunCall.CallCount++
+	u.RunCall.Receives.Options = options
+	u.RunCall.Receives.Config = config
+	u.RunCall.Receives.State = state
+
+	return u.RunCall.Returns.State, u.RunCall.Returns.Error
+}
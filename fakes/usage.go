@@ -12,6 +12,13 @@ type Usage struct {
 			Message string
 		}
 	}
+
+	PrintCommandExamplesCall struct {
+		CallCount int
+		Receives  struct {
+			Command string
+		}
+	}
 }
 
 func (u *Usage) Print() {
@@ -23,3 +30,8 @@ func (u *Usage) PrintCommandUsage(command, message string) {
 	u.PrintCommandUsageCall.Receives.Message = message
 	u.PrintCommandUsageCall.Receives.Command = command
 }
+
+func (u *Usage) PrintCommandExamples(command string) {
+	u.PrintCommandExamplesCall.CallCount++
+	u.PrintCommandExamplesCall.Receives.Command = command
+}
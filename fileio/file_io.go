@@ -26,6 +26,10 @@ type Stater interface {
 	Stat(name string) (os.FileInfo, error)
 }
 
+type Opener interface {
+	Open(name string) (afero.File, error)
+}
+
 type Renamer interface {
 	Rename(oldpath, newpath string) error
 }
@@ -3,6 +3,7 @@ package flags
 import (
 	"flag"
 	"io/ioutil"
+	"strings"
 )
 
 type Flags struct {
@@ -23,10 +24,45 @@ func (f Flags) String(v *string, name string, value string) {
 	f.set.StringVar(v, name, value, "")
 }
 
+func (f Flags) Bool(v *bool, name string, value bool) {
+	f.set.BoolVar(v, name, value, "")
+}
+
+// StringSlice collects the values of a flag that is given more than once.
+func (f Flags) StringSlice(v *[]string, name string) {
+	f.set.Var((*stringSlice)(v), name, "")
+}
+
 func (f Flags) Parse(args []string) error {
 	return f.set.Parse(args)
 }
 
+// Changed reports whether the flag was given, so that a flag that is left out
+// can keep a value from the state.
+func (f Flags) Changed(name string) bool {
+	changed := false
+	f.set.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			changed = true
+		}
+	})
+	return changed
+}
+
 func (f Flags) Args() []string {
 	return f.set.Args()
 }
+
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
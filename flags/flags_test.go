@@ -11,11 +11,16 @@ var _ = Describe("Flags", func() {
 	var (
 		f         flags.Flags
 		stringVal string
+		boolVal   bool
+		sliceVal  []string
 	)
 
 	BeforeEach(func() {
 		f = flags.New("test")
 		f.String(&stringVal, "string", "")
+		f.Bool(&boolVal, "bool", false)
+		sliceVal = nil
+		f.StringSlice(&sliceVal, "slice")
 	})
 
 	Describe("Parse", func() {
@@ -24,6 +29,27 @@ var _ = Describe("Flags", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(stringVal).To(Equal("string_value"))
 		})
+
+		It("can parse bool fields from flags", func() {
+			err := f.Parse([]string{"--bool"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(boolVal).To(BeTrue())
+		})
+
+		It("can parse repeated slice fields from flags", func() {
+			err := f.Parse([]string{"--slice", "a=1", "--slice", "b=2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sliceVal).To(Equal([]string{"a=1", "b=2"}))
+		})
+	})
+
+	Describe("Changed", func() {
+		It("reports whether a flag was given", func() {
+			err := f.Parse([]string{"--bool=false"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Changed("bool")).To(BeTrue())
+			Expect(f.Changed("string")).To(BeFalse())
+		})
 	})
 
 	Describe("Args", func() {
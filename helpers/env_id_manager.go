@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -23,6 +24,12 @@ type NetworkClient interface {
 	CheckExists(networkName string) (bool, error)
 }
 
+// resourceChecker finds the resources that would collide with the ones bbl
+// names after an env id, beyond the network.
+type resourceChecker interface {
+	ConflictingResources(envID string) ([]string, error)
+}
+
 func NewEnvIDManager(envIDGenerator envIDGenerator, networkClient NetworkClient) EnvIDManager {
 	return EnvIDManager{
 		envIDGenerator: envIDGenerator,
@@ -51,6 +58,11 @@ func (e EnvIDManager) Sync(state storage.State, envID string) (storage.State, er
 			return storage.State{}, err
 		}
 
+		err = e.checkConflicts(state, envID)
+		if err != nil {
+			return storage.State{}, err
+		}
+
 		state.EnvID = envID
 	}
 
@@ -85,6 +97,57 @@ func (e EnvIDManager) checkFastFail(iaas, envID string) error {
 	return nil
 }
 
+// checkConflicts reports the resources that already have the names bbl
+// would give the resources of envID, so that bbl up does not fail on an
+// AlreadyExists error partway through, and suggests names without conflicts.
+func (e EnvIDManager) checkConflicts(state storage.State, envID string) error {
+	checker, ok := e.networkClient.(resourceChecker)
+	if !ok || state.IAAS != "aws" {
+		return nil
+	}
+
+	conflicts, err := checker.ConflictingResources(envID)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	candidates := []string{fmt.Sprintf("%s-2", envID), fmt.Sprintf("%s-3", envID)}
+	if state.AWS.Region != "" {
+		candidates = append([]string{fmt.Sprintf("%s-%s", envID, state.AWS.Region)}, candidates...)
+	}
+
+	var suggestions []string
+	for _, candidate := range candidates {
+		if len(suggestions) == 2 {
+			break
+		}
+
+		taken, err := checker.ConflictingResources(candidate)
+		if err != nil {
+			return err
+		}
+		if len(taken) == 0 {
+			suggestions = append(suggestions, fmt.Sprintf("--name %s", candidate))
+		}
+	}
+
+	message := fmt.Sprintf("These resources already have the names bbl gives the resources of '%s':\n", envID)
+	for _, conflict := range conflicts {
+		message += fmt.Sprintf("  %s\n", conflict)
+	}
+	if len(suggestions) > 0 {
+		message += fmt.Sprintf("Please provide a different name, such as %s, or delete the resources.", strings.Join(suggestions, " or "))
+	} else {
+		message += "Please provide a different name, or delete the resources."
+	}
+
+	return errors.New(message)
+}
+
 func (e EnvIDManager) validateName(envID string) error {
 	matched, err := matchString("^(?:[a-z](?:[-a-z0-9]*[a-z0-9])?)$", envID)
 	if err != nil {
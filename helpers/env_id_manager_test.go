@@ -129,7 +129,49 @@ var _ = Describe("EnvIDManager", func() {
 			})
 		})
 
+		Context("when resources of aws already have the names of the environment", func() {
+			BeforeEach(func() {
+				networkClient.ConflictingResourcesCall.Returns.Conflicts = map[string][]string{
+					"some-env":   {"key pair some-env_bosh_vms", "IAM role some-env_bosh_role"},
+					"some-env-2": {"IAM role some-env-2_bosh_role"},
+				}
+			})
+
+			It("reports the conflicts and suggests names without conflicts", func() {
+				_, err := envIDManager.Sync(storage.State{IAAS: "aws", AWS: storage.AWS{Region: "eu-west-1"}}, "some-env")
+				Expect(err).To(MatchError(`These resources already have the names bbl gives the resources of 'some-env':
+  key pair some-env_bosh_vms
+  IAM role some-env_bosh_role
+Please provide a different name, such as --name some-env-eu-west-1 or --name some-env-3, or delete the resources.`))
+
+				Expect(networkClient.ConflictingResourcesCall.Receives.EnvIDs).To(Equal([]string{"some-env", "some-env-eu-west-1", "some-env-2", "some-env-3"}))
+			})
+
+			It("does not check the names of other iaases", func() {
+				_, err := envIDManager.Sync(storage.State{IAAS: "gcp"}, "some-env")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(networkClient.ConflictingResourcesCall.CallCount).To(Equal(0))
+			})
+
+			It("does not check generated names", func() {
+				_, err := envIDManager.Sync(storage.State{IAAS: "aws"}, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(networkClient.ConflictingResourcesCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("failure cases", func() {
+			Context("when the resources of aws cannot be checked", func() {
+				BeforeEach(func() {
+					networkClient.ConflictingResourcesCall.Returns.Error = errors.New("Check key pair some-env_bosh_vms: AccessDenied")
+				})
+
+				It("returns an error", func() {
+					_, err := envIDManager.Sync(storage.State{IAAS: "aws"}, "some-env")
+					Expect(err).To(MatchError("Check key pair some-env_bosh_vms: AccessDenied"))
+				})
+			})
+
 			Context("when the NetworkClient cannot check if a network exists", func() {
 				BeforeEach(func() {
 					networkClient.CheckExistsCall.Returns.Error = errors.New("failed to get network list")
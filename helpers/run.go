@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// RunUntilDone runs a command, such as terraform or a create-env script, until
+// it exits. When ctx runs out of time first, the command is sent an
+// interrupt, so that it stops the way it does on ctrl-c and saves what it
+// completed, and bbl waits for it to exit. It is killed where interrupts
+// cannot be sent. A command that ctx was cancelled for is not sent an
+// interrupt, since the interrupt of the terminal reached it already and a
+// second one stops terraform without saving its state.
+func RunUntilDone(ctx context.Context, cmd *exec.Cmd) error {
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err = <-exited:
+		return err
+	case <-ctx.Done():
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		if signalErr := cmd.Process.Signal(os.Interrupt); signalErr != nil {
+			cmd.Process.Kill()
+		}
+	}
+	return <-exited
+}
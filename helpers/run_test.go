@@ -0,0 +1,53 @@
+package helpers_test
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunUntilDone", func() {
+	var (
+		output *bytes.Buffer
+		cmd    *exec.Cmd
+	)
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		cmd = exec.Command("/bin/sh", "-c", "trap 'echo saved; exit 3' INT; sleep 1 & wait; echo done")
+		cmd.Stdout = output
+	})
+
+	It("runs the command until it exits", func() {
+		Expect(helpers.RunUntilDone(context.Background(), cmd)).To(Succeed())
+		Expect(output.String()).To(Equal("done\n"))
+	})
+
+	It("interrupts the command when the context runs out of time", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := helpers.RunUntilDone(ctx, cmd)
+		Expect(err).To(MatchError("exit status 3"))
+		Expect(output.String()).To(Equal("saved\n"))
+	})
+
+	It("waits for the command when the context is cancelled, as the interrupt reached it already", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Expect(helpers.RunUntilDone(ctx, cmd)).To(Succeed())
+		Expect(output.String()).To(Equal("done\n"))
+	})
+
+	It("returns an error when the command cannot be started", func() {
+		err := helpers.RunUntilDone(context.Background(), exec.Command("/does/not/exist"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,32 @@
+package ssh
+
+import (
+	"io"
+	"os/exec"
+)
+
+// Cmd runs the ssh client installed on the workstation, attached to the
+// terminal bbl was started from so that interactive sessions work.
+type Cmd struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewCmd(stdin io.Reader, stdout, stderr io.Writer) Cmd {
+	return Cmd{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+func (c Cmd) Run(args []string) error {
+	command := exec.Command("ssh", args...)
+
+	command.Stdin = c.stdin
+	command.Stdout = c.stdout
+	command.Stderr = c.stderr
+
+	return command.Run()
+}
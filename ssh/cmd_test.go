@@ -0,0 +1,66 @@
+package ssh_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cmd", func() {
+	var (
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+
+		cmd ssh.Cmd
+
+		originalPath string
+		tempDir      string
+	)
+
+	BeforeEach(func() {
+		stdout = bytes.NewBuffer([]byte{})
+		stderr = bytes.NewBuffer([]byte{})
+
+		cmd = ssh.NewCmd(strings.NewReader("some-input"), stdout, stderr)
+
+		var err error
+		tempDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeSSH := "#!/bin/sh\necho \"args: $@\"\necho \"stdin: $(cat)\"\necho \"some-error\" >&2\nexit $FAKE_SSH_EXIT_CODE\n"
+		err = ioutil.WriteFile(filepath.Join(tempDir, "ssh"), []byte(fakeSSH), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		originalPath = os.Getenv("PATH")
+		os.Setenv("PATH", strings.Join([]string{tempDir, originalPath}, ":"))
+		os.Setenv("FAKE_SSH_EXIT_CODE", "0")
+	})
+
+	AfterEach(func() {
+		os.Setenv("PATH", originalPath)
+		os.Unsetenv("FAKE_SSH_EXIT_CODE")
+		os.RemoveAll(tempDir)
+	})
+
+	It("runs ssh with the args, attached to the given streams", func() {
+		err := cmd.Run([]string{"-i", "some-key", "jumpbox@some-host"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stdout.String()).To(Equal("args: -i some-key jumpbox@some-host\nstdin: some-input\n"))
+		Expect(stderr.String()).To(Equal("some-error\n"))
+	})
+
+	It("returns an error when ssh fails", func() {
+		os.Setenv("FAKE_SSH_EXIT_CODE", "255")
+
+		err := cmd.Run([]string{"jumpbox@some-host"})
+		Expect(err).To(MatchError("exit status 255"))
+	})
+})
@@ -0,0 +1,30 @@
+package storage
+
+// ArtifactOverrides replaces the BOSH release, CPI release and stemcell that
+// bosh-deployment pins for the director. Empty fields keep the pinned ones.
+type ArtifactOverrides struct {
+	BOSHReleaseURL  string `json:"boshReleaseURL,omitempty"`
+	BOSHReleaseSHA1 string `json:"boshReleaseSHA1,omitempty"`
+	CPIReleaseURL   string `json:"cpiReleaseURL,omitempty"`
+	CPIReleaseSHA1  string `json:"cpiReleaseSHA1,omitempty"`
+	StemcellURL     string `json:"stemcellURL,omitempty"`
+	StemcellSHA1    string `json:"stemcellSHA1,omitempty"`
+}
+
+func (o ArtifactOverrides) IsEmpty() bool {
+	return o == ArtifactOverrides{}
+}
+
+// Merge returns o with the fields that are set in newer replaced.
+func (o ArtifactOverrides) Merge(newer ArtifactOverrides) ArtifactOverrides {
+	if newer.BOSHReleaseURL != "" {
+		o.BOSHReleaseURL, o.BOSHReleaseSHA1 = newer.BOSHReleaseURL, newer.BOSHReleaseSHA1
+	}
+	if newer.CPIReleaseURL != "" {
+		o.CPIReleaseURL, o.CPIReleaseSHA1 = newer.CPIReleaseURL, newer.CPIReleaseSHA1
+	}
+	if newer.StemcellURL != "" {
+		o.StemcellURL, o.StemcellSHA1 = newer.StemcellURL, newer.StemcellSHA1
+	}
+	return o
+}
@@ -1,7 +1,157 @@
 package storage
 
 type AWS struct {
-	AccessKeyID     string `json:"-"`
-	SecretAccessKey string `json:"-"`
-	Region          string `json:"region,omitempty"`
+	AccessKeyID     string   `json:"-"`
+	SecretAccessKey string   `json:"-"`
+	SessionToken    string   `json:"-"`
+	Profile         string   `json:"-"`
+	RoleARN         string   `json:"-"`
+	MFASerial       string   `json:"-"`
+	MFATokenCode    string   `json:"-"`
+	Region          string   `json:"region,omitempty"`
+	AccountID       string   `json:"accountID,omitempty"`
+	AZs             []string `json:"azs,omitempty"`
+	Minimal         bool     `json:"minimal,omitempty"`
+	VPCCIDR         string   `json:"vpcCIDR,omitempty"`
+	ExistingVPCID   string   `json:"existingVPCID,omitempty"`
+
+	// Lite deploys a bosh-lite director, whose warden cpi creates the VMs of
+	// its deployments as containers on the director VM.
+	Lite bool `json:"lite,omitempty"`
+
+	// Cheap is the cost-saving profile of throwaway environments: a small
+	// NAT instance, a burstable director and a single availability zone.
+	// SpotBidPrice, in USD per hour, lets deployments run their VMs on spot
+	// instances with the spot vm_extension.
+	Cheap        bool    `json:"cheap,omitempty"`
+	SpotBidPrice float64 `json:"spotBidPrice,omitempty"`
+
+	// DualStack gives the VPC and its subnets IPv6 blocks alongside their
+	// IPv4 ones, and has the router load balancer answer on both.
+	DualStack bool `json:"dualStack,omitempty"`
+
+	// NATGateway routes the internal subnets through a managed NAT gateway
+	// in each availability zone instead of the NAT instance. KeepNATInstance
+	// keeps the NAT instance of an environment alongside the gateways while
+	// bbl up moves the subnets onto them.
+	NATGateway      bool `json:"natGateway,omitempty"`
+	KeepNATInstance bool `json:"-"`
+
+	// SubnetSizes are the prefix lengths of the internal subnets by
+	// availability zone, and ReservedCIDRs the blocks of the VPC that bbl
+	// leaves out of its subnets.
+	SubnetSizes   map[string]int `json:"subnetSizes,omitempty"`
+	ReservedCIDRs []string       `json:"reservedCIDRs,omitempty"`
+
+	// DirectorAllowedCIDRs are the blocks that may reach the jumpbox and the
+	// director, and LBAllowedCIDRs the blocks that may reach the load
+	// balancers, in place of anywhere.
+	DirectorAllowedCIDRs []string `json:"directorAllowedCIDRs,omitempty"`
+	LBAllowedCIDRs       []string `json:"lbAllowedCIDRs,omitempty"`
+
+	// S3Blobstore stores the blobs of the director in an S3 bucket, the
+	// S3BlobstoreBucket of the user or else one that bbl creates.
+	S3Blobstore       bool   `json:"s3Blobstore,omitempty"`
+	S3BlobstoreBucket string `json:"s3BlobstoreBucket,omitempty"`
+
+	// Certificates are the IAM server certificates that bbl
+	// create-certificate and bbl upload-certificate uploaded, by name.
+	Certificates []ServerCertificate `json:"certificates,omitempty"`
+
+	// Certificate is the one certificate of bbl upload-certificate of older
+	// states. The migrator moves it to Certificates.
+	Certificate *ServerCertificate `json:"certificate,omitempty"`
+
+	// SNIDomains are the domains of the cf load balancer that are served with
+	// certificates of their own, each by a load balancer of its own, since
+	// classic ELBs present one certificate per listener.
+	SNIDomains []SNIDomain `json:"sniDomains,omitempty"`
+
+	MaxRetries  int      `json:"-"`
+	RetryJitter *float64 `json:"-"`
+	Endpoint    string   `json:"-"`
+
+	// Endpoints are the urls of the endpoints of single services, such as
+	// ec2 or iam, in place of Endpoint and the endpoints of the region.
+	Endpoints map[string]string `json:"-"`
+}
+
+// EndpointServices are the services that Endpoints can have an endpoint for,
+// with the names of the endpoints of the aws terraform provider. The elb
+// endpoint serves both classic and ELBv2 load balancers.
+var EndpointServices = []string{"ec2", "elb", "iam", "kms", "s3", "sts", "cloudwatchlogs"}
+
+// EndpointFor returns the url of the endpoint of the service, or an empty
+// string for the default endpoint of the region.
+func (a AWS) EndpointFor(service string) string {
+	if endpoint, ok := a.Endpoints[service]; ok {
+		return endpoint
+	}
+	return a.Endpoint
+}
+
+type ServerCertificate struct {
+	Name        string `json:"name"`
+	ARN         string `json:"arn"`
+	Fingerprint string `json:"fingerprint"`
+
+	// LBs are the load balancers whose TLS listeners bbl attach-certificate
+	// attached the certificate to.
+	LBs []string `json:"lbs,omitempty"`
+}
+
+// SNIDomain serves Domain with the certificate named Certificate.
+type SNIDomain struct {
+	Domain      string `json:"domain"`
+	Certificate string `json:"certificate"`
+}
+
+// CertificateLBs are the load balancers that a certificate can be attached
+// to, with the terraform variable of the ARN of their TLS listeners.
+var CertificateLBs = map[string]string{
+	"cf-router":     "cf_router_certificate_arn",
+	"cf-iso-router": "cf_iso_router_certificate_arn",
+}
+
+// CertificateNamed returns the certificate with the name, or nil.
+func (a AWS) CertificateNamed(name string) *ServerCertificate {
+	for i := range a.Certificates {
+		if a.Certificates[i].Name == name {
+			return &a.Certificates[i]
+		}
+	}
+	return nil
+}
+
+// AttachedCertificate returns the certificate attached to the load balancer,
+// or nil.
+func (a AWS) AttachedCertificate(lb string) *ServerCertificate {
+	for i := range a.Certificates {
+		for _, attached := range a.Certificates[i].LBs {
+			if attached == lb {
+				return &a.Certificates[i]
+			}
+		}
+	}
+	return nil
+}
+
+// AttachCertificate attaches the certificate with the name to the load
+// balancer, in place of the certificate that was attached to it.
+func (a *AWS) AttachCertificate(name, lb string) {
+	for i := range a.Certificates {
+		lbs := []string{}
+		for _, attached := range a.Certificates[i].LBs {
+			if attached != lb {
+				lbs = append(lbs, attached)
+			}
+		}
+		if a.Certificates[i].Name == name {
+			lbs = append(lbs, lb)
+		}
+		if len(lbs) == 0 {
+			lbs = nil
+		}
+		a.Certificates[i].LBs = lbs
+	}
 }
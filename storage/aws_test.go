@@ -0,0 +1,50 @@
+package storage_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS", func() {
+	var aws storage.AWS
+
+	BeforeEach(func() {
+		aws = storage.AWS{
+			Certificates: []storage.ServerCertificate{
+				{Name: "foo", ARN: "foo-arn", LBs: []string{"cf-router"}},
+				{Name: "bar", ARN: "bar-arn"},
+			},
+		}
+	})
+
+	Describe("CertificateNamed", func() {
+		It("returns the certificate with the name", func() {
+			Expect(aws.CertificateNamed("bar").ARN).To(Equal("bar-arn"))
+			Expect(aws.CertificateNamed("baz")).To(BeNil())
+		})
+	})
+
+	Describe("AttachCertificate", func() {
+		It("attaches the certificate in place of the one attached to the load balancer", func() {
+			aws.AttachCertificate("bar", "cf-router")
+			aws.AttachCertificate("bar", "cf-iso-router")
+
+			Expect(aws.Certificates[0].LBs).To(BeNil())
+			Expect(aws.Certificates[1].LBs).To(Equal([]string{"cf-router", "cf-iso-router"}))
+			Expect(aws.AttachedCertificate("cf-router").Name).To(Equal("bar"))
+			Expect(aws.AttachedCertificate("some-lb")).To(BeNil())
+		})
+	})
+
+	Describe("EndpointFor", func() {
+		It("returns the endpoint of the service, or else the endpoint of every service", func() {
+			aws.Endpoint = "https://aws.example.com"
+			aws.Endpoints = map[string]string{"iam": "https://iam.example.com"}
+
+			Expect(aws.EndpointFor("iam")).To(Equal("https://iam.example.com"))
+			Expect(aws.EndpointFor("ec2")).To(Equal("https://aws.example.com"))
+		})
+	})
+})
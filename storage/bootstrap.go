@@ -1,9 +1,9 @@
 package storage
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -11,6 +11,7 @@ import (
 
 type logger interface {
 	Println(message string)
+	Prompt(message string) bool
 }
 
 type StateBootstrap struct {
@@ -33,7 +34,9 @@ func (b StateBootstrap) GetState(dir string) (State, error) {
 		return state, err
 	}
 
-	file, err := os.Open(filepath.Join(dir, StateFileName))
+	serializer := stateSerializerForDir(dir)
+	stateFile := filepath.Join(dir, serializer.FileName())
+	data, err := ioutil.ReadFile(stateFile)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return state, nil
@@ -41,9 +44,12 @@ func (b StateBootstrap) GetState(dir string) (State, error) {
 		return state, err
 	}
 
-	err = json.NewDecoder(file).Decode(&state)
+	err = serializer.Unmarshal(data, &state)
 	if err != nil {
-		return state, err
+		state, err = b.recoverPreviousState(serializer, stateFile, err)
+		if err != nil {
+			return state, err
+		}
 	}
 
 	emptyState := State{}
@@ -63,30 +69,50 @@ func (b StateBootstrap) GetState(dir string) (State, error) {
 	}
 
 	if state.Version > STATE_SCHEMA {
+		if state.BBLVersion == "" || state.BBLVersion == "dev" {
+			return state, fmt.Errorf("Existing bbl environment was created with a newer version of bbl, with state schema %d. This bbl reads state schema %d and older. Please upgrade bbl.\n", state.Version, STATE_SCHEMA)
+		}
 		return state, fmt.Errorf("Existing bbl environment was created with a newer version of bbl. Please upgrade to bbl v%s.\n", state.BBLVersion)
 	}
 
 	return state, nil
 }
 
+// recoverPreviousState offers to replace a state file that cannot be read,
+// such as one cut short by a full disk, with the copy kept by the last save.
+func (b StateBootstrap) recoverPreviousState(serializer StateSerializer, stateFile string, readErr error) (State, error) {
+	corruptErr := fmt.Errorf("The state file %s is corrupt: %s", stateFile, readErr)
+
+	previousFile := stateFile + PreviousStateSuffix
+	data, err := ioutil.ReadFile(previousFile)
+	if err != nil {
+		return State{}, corruptErr
+	}
+
+	var state State
+	err = serializer.Unmarshal(data, &state)
+	if err != nil {
+		return State{}, corruptErr
+	}
+
+	if !b.logger.Prompt(fmt.Sprintf("%s. Recover the state from before the last change to it, in %s?", corruptErr, previousFile)) {
+		return State{}, fmt.Errorf("%s. The state from before the last change to it is in %s.", corruptErr, previousFile)
+	}
+
+	err = ioutil.WriteFile(stateFile, data, os.FileMode(0644))
+	if err != nil {
+		return State{}, fmt.Errorf("Recover previous state: %s", err)
+	}
+	b.logger.Println(fmt.Sprintf("Recovered the state from %s.", previousFile))
+
+	return state, nil
+}
+
 // Get the earliest bbl version compatible with the given bbl state version.
 func (b StateBootstrap) getBBLVersion(stateSchema int) string {
-	stateToBBLVersion := map[int]string{
-		3:  "3.0.0",
-		5:  "4.0.0",
-		6:  "4.0.0",
-		7:  "4.0.0",
-		8:  "4.0.0",
-		9:  "4.4.0",
-		10: "4.6.0",
-		11: "5.1.0",
-		12: "5.1.0",
-		13: "5.4.0",
-		14: "6.0.0",
-	}
-	bblVersion, ok := stateToBBLVersion[stateSchema]
+	entry, ok := CompatibilityFor(stateSchema)
 	if ok {
-		return bblVersion
+		return entry.BBLVersion
 	}
 	return "dev"
 }
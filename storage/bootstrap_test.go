@@ -1,6 +1,7 @@
 package storage_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -48,6 +49,23 @@ var _ = Describe("StateBootstrap", func() {
 			})
 		})
 
+		Context("when there is a yaml state file", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.yml"), []byte("version: 14\nbblVersion: some-bbl-version\niaas: gcp\n"), storage.StateMode)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reads it", func() {
+				state, err := bootstrap.GetState(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state).To(Equal(storage.State{
+					Version:    14,
+					BBLVersion: "some-bbl-version",
+					IAAS:       "gcp",
+				}))
+			})
+		})
+
 		Context("when there is a pre v3 state file", func() {
 			BeforeEach(func() {
 				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.json"), []byte(`{
@@ -136,6 +154,18 @@ var _ = Describe("StateBootstrap", func() {
 				_, err := bootstrap.GetState(tempDir)
 				Expect(err).To(MatchError("Existing bbl environment was created with a newer version of bbl. Please upgrade to bbl v9.9.9.\n"))
 			})
+
+			Context("when the state does not name the bbl that wrote it", func() {
+				BeforeEach(func() {
+					err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.json"), []byte(`{"version": 999}`), storage.StateMode)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error with the state schemas", func() {
+					_, err := bootstrap.GetState(tempDir)
+					Expect(err).To(MatchError(fmt.Sprintf("Existing bbl environment was created with a newer version of bbl, with state schema 999. This bbl reads state schema %d and older. Please upgrade bbl.\n", storage.STATE_SCHEMA)))
+				})
+			})
 		})
 
 		Context("when the bbl-state.json file doesn't exist", func() {
@@ -173,6 +203,35 @@ var _ = Describe("StateBootstrap", func() {
 			})
 		})
 
+		Context("when the state file is corrupt and the previous state was kept", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.json"), []byte(`{"version": 14, "ia`), storage.StateMode)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.json.previous"), []byte(`{"version": 14, "bblVersion": "some-bbl-version", "iaas": "gcp"}`), storage.StateMode)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("offers to recover the previous state", func() {
+				logger.PromptCall.Returns.Proceed = true
+
+				state, err := bootstrap.GetState(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.IAAS).To(Equal("gcp"))
+
+				Expect(logger.PromptCall.Receives.Message).To(ContainSubstring("Recover the state from before the last change to it"))
+				contents, err := ioutil.ReadFile(filepath.Join(tempDir, "bbl-state.json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contents).To(MatchJSON(`{"version": 14, "bblVersion": "some-bbl-version", "iaas": "gcp"}`))
+			})
+
+			It("returns an error naming the previous state when the offer is declined", func() {
+				_, err := bootstrap.GetState(tempDir)
+				Expect(err).To(MatchError(ContainSubstring("is corrupt: unexpected EOF")))
+				Expect(err).To(MatchError(ContainSubstring(filepath.Join(tempDir, "bbl-state.json.previous"))))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when the directory does not exist", func() {
 				It("returns an error", func() {
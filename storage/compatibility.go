@@ -0,0 +1,61 @@
+package storage
+
+// Compatibility describes the environments that a line of bbl releases
+// writes: the state schema, the layout of the terraform template in the state
+// directory and the BOSH director that its bosh-deployment deploys.
+type Compatibility struct {
+	BBLVersion      string `json:"bblVersion"`
+	StateSchema     int    `json:"stateSchema"`
+	TemplateVersion int    `json:"templateVersion"`
+	DirectorVersion string `json:"directorVersion"`
+
+	// UpgradeThrough marks a release that changes the environment itself, not
+	// only the files in the state directory. Older environments must run bbl
+	// up with it before a later release can upgrade them.
+	UpgradeThrough bool `json:"upgradeThrough"`
+}
+
+// CompatibilityMatrix has an entry for each state schema, with the earliest
+// bbl release that writes it. Template version 1 kept the terraform template
+// and state in bbl-state.json, 2 moved them to the terraform directory as
+// template.tf, and 3 renamed the template to bbl-template.tf.
+var CompatibilityMatrix = []Compatibility{
+	{BBLVersion: "3.0.0", StateSchema: 3, TemplateVersion: 1, DirectorVersion: "262"},
+	{BBLVersion: "4.0.0", StateSchema: 5, TemplateVersion: 1, DirectorVersion: "262", UpgradeThrough: true},
+	{BBLVersion: "4.0.0", StateSchema: 6, TemplateVersion: 1, DirectorVersion: "262"},
+	{BBLVersion: "4.0.0", StateSchema: 7, TemplateVersion: 1, DirectorVersion: "262"},
+	{BBLVersion: "4.0.0", StateSchema: 8, TemplateVersion: 1, DirectorVersion: "262"},
+	{BBLVersion: "4.4.0", StateSchema: 9, TemplateVersion: 1, DirectorVersion: "263"},
+	{BBLVersion: "4.6.0", StateSchema: 10, TemplateVersion: 1, DirectorVersion: "263"},
+	{BBLVersion: "5.1.0", StateSchema: 11, TemplateVersion: 2, DirectorVersion: "264"},
+	{BBLVersion: "5.1.0", StateSchema: 12, TemplateVersion: 2, DirectorVersion: "264"},
+	{BBLVersion: "5.4.0", StateSchema: 13, TemplateVersion: 2, DirectorVersion: "264"},
+	{BBLVersion: "6.0.0", StateSchema: 14, TemplateVersion: 3, DirectorVersion: "264"},
+}
+
+// CompatibilityFor returns the entry of the given state schema.
+func CompatibilityFor(stateSchema int) (Compatibility, bool) {
+	for _, entry := range CompatibilityMatrix {
+		if entry.StateSchema == stateSchema {
+			return entry, true
+		}
+	}
+	return Compatibility{}, false
+}
+
+// RequiredUpgrades returns the releases that an environment with the given
+// state schema must be upgraded with, in order, before this bbl can upgrade
+// it. A schema of 0 is a new environment.
+func RequiredUpgrades(stateSchema int) []Compatibility {
+	upgrades := []Compatibility{}
+	if stateSchema == 0 {
+		return upgrades
+	}
+
+	for _, entry := range CompatibilityMatrix {
+		if entry.UpgradeThrough && entry.StateSchema > stateSchema && entry.StateSchema < STATE_SCHEMA {
+			upgrades = append(upgrades, entry)
+		}
+	}
+	return upgrades
+}
@@ -0,0 +1,47 @@
+package storage_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compatibility", func() {
+	It("has an entry for the current state schema", func() {
+		entry, ok := storage.CompatibilityFor(storage.STATE_SCHEMA)
+		Expect(ok).To(BeTrue())
+		Expect(entry.StateSchema).To(Equal(storage.STATE_SCHEMA))
+	})
+
+	Describe("CompatibilityFor", func() {
+		It("returns the earliest bbl release that writes a state schema", func() {
+			entry, ok := storage.CompatibilityFor(11)
+			Expect(ok).To(BeTrue())
+			Expect(entry.BBLVersion).To(Equal("5.1.0"))
+			Expect(entry.TemplateVersion).To(Equal(2))
+		})
+
+		It("returns false for an unknown state schema", func() {
+			_, ok := storage.CompatibilityFor(4)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("RequiredUpgrades", func() {
+		It("returns the releases that older environments must be upgraded with first", func() {
+			upgrades := storage.RequiredUpgrades(3)
+			Expect(upgrades).To(HaveLen(1))
+			Expect(upgrades[0].BBLVersion).To(Equal("4.0.0"))
+		})
+
+		It("returns nothing for environments past those releases", func() {
+			Expect(storage.RequiredUpgrades(5)).To(BeEmpty())
+			Expect(storage.RequiredUpgrades(storage.STATE_SCHEMA)).To(BeEmpty())
+		})
+
+		It("returns nothing for a new environment", func() {
+			Expect(storage.RequiredUpgrades(0)).To(BeEmpty())
+		})
+	})
+})
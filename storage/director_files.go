@@ -0,0 +1,9 @@
+package storage
+
+// DirectorFile is an ops file or vars file of the operator that bbl applies
+// to the director manifest, kept by its name and contents so that every bbl
+// up replays it without the original file.
+type DirectorFile struct {
+	Name     string `json:"name"`
+	Contents string `json:"contents"`
+}
@@ -0,0 +1,14 @@
+package storage
+
+// DirectorPorts overrides the ports of the director's internal services.
+// Zero keeps the port that bosh-deployment uses.
+type DirectorPorts struct {
+	Blobstore int `json:"blobstore,omitempty"`
+	NATS      int `json:"nats,omitempty"`
+	Registry  int `json:"registry,omitempty"`
+	Mbus      int `json:"mbus,omitempty"`
+}
+
+func (p DirectorPorts) IsEmpty() bool {
+	return p == DirectorPorts{}
+}
@@ -0,0 +1,27 @@
+package storage
+
+// DirectorSyslog forwards the logs of the director, including the audit log
+// of its API, to a syslog server.
+type DirectorSyslog struct {
+	Address   string `json:"address,omitempty"`
+	Port      int    `json:"port,omitempty"`
+	Transport string `json:"transport,omitempty"`
+}
+
+func (s DirectorSyslog) IsEmpty() bool {
+	return s == DirectorSyslog{}
+}
+
+// Merge returns s with the fields that are set in newer replaced.
+func (s DirectorSyslog) Merge(newer DirectorSyslog) DirectorSyslog {
+	if newer.Address != "" {
+		s.Address = newer.Address
+	}
+	if newer.Port != 0 {
+		s.Port = newer.Port
+	}
+	if newer.Transport != "" {
+		s.Transport = newer.Transport
+	}
+	return s
+}
@@ -0,0 +1,9 @@
+package storage
+
+// DirectorVersions are the versions of the BOSH release, CPI release and
+// stemcell that the director was last deployed from.
+type DirectorVersions struct {
+	BOSH     string `json:"bosh"`
+	CPI      string `json:"cpi,omitempty"`
+	Stemcell string `json:"stemcell,omitempty"`
+}
@@ -0,0 +1,25 @@
+package storage
+
+// DirectorVM sizes the director VM. Empty fields keep the instance type and
+// persistent disk that bosh-deployment uses.
+type DirectorVM struct {
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// DiskSize is the size of the persistent disk in GB.
+	DiskSize int `json:"diskSize,omitempty"`
+}
+
+func (v DirectorVM) IsEmpty() bool {
+	return v == DirectorVM{}
+}
+
+// Merge returns v with the fields that are set in newer replaced.
+func (v DirectorVM) Merge(newer DirectorVM) DirectorVM {
+	if newer.InstanceType != "" {
+		v.InstanceType = newer.InstanceType
+	}
+	if newer.DiskSize != 0 {
+		v.DiskSize = newer.DiskSize
+	}
+	return v
+}
@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	PassphraseEncryption = "passphrase"
+	KMSEncryption        = "kms"
+
+	encryptedPrefix      = "bbl-encrypted:"
+	passphraseIterations = 100000
+	keyLength            = 32
+)
+
+// Encryption records how the sensitive fields of the state are encrypted. It
+// is kept in the state in plain text, and holds no secret: the salt of the
+// passphrase, or the data key as encrypted by the KMS key.
+type Encryption struct {
+	Method   string `json:"method"`
+	Salt     string `json:"salt,omitempty"`
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+	DataKey  string `json:"dataKey,omitempty"`
+
+	// KMSRegion is the region of the KMS key when it is not the region of
+	// the environment, as after bbl migrate-region.
+	KMSRegion string `json:"kmsRegion,omitempty"`
+}
+
+// KMSCredentials returns the credentials of the environment in the region of
+// the KMS key.
+func (e Encryption) KMSCredentials(creds AWS) AWS {
+	if e.KMSRegion != "" {
+		creds.Region = e.KMSRegion
+	}
+	return creds
+}
+
+type KMSClient interface {
+	GenerateDataKey(keyID string) (plaintext []byte, ciphertext []byte, err error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// StateCipher encrypts the sensitive fields of the state with AES-GCM when
+// the store writes it, and decrypts them after it is loaded. The key comes
+// from a passphrase, or is a data key that an AWS KMS key encrypts.
+type StateCipher struct {
+	passphrase string
+	key        []byte
+
+	// sealed remembers the encrypted form of each value, so that saving a
+	// state that has not changed writes the same file.
+	sealed map[string]string
+}
+
+func NewStateCipher(passphrase string) *StateCipher {
+	return &StateCipher{
+		passphrase: passphrase,
+		sealed:     map[string]string{},
+	}
+}
+
+// sensitiveFields are the fields of the state that hold credentials. The
+// IAAS credentials are not written to the state at all. The state migrator
+// moves the variables of the jumpbox and director and the terraform state to
+// the vars directory, which bosh create-env and terraform read and write
+// themselves, so they are not in the state to encrypt.
+func sensitiveFields(state *State) []*string {
+	return []*string{
+		&state.BOSH.DirectorPassword,
+		&state.BOSH.DirectorSSLPrivateKey,
+		&state.LB.Key,
+	}
+}
+
+// Unlock works out the key of an encrypted state. kmsClient is only used
+// for states encrypted with a KMS key.
+func (c *StateCipher) Unlock(encryption *Encryption, kmsClient KMSClient) error {
+	if encryption == nil {
+		return nil
+	}
+
+	switch encryption.Method {
+	case PassphraseEncryption:
+		if c.passphrase == "" {
+			return errors.New("The state is encrypted with a passphrase. Pass --state-passphrase or set BBL_STATE_PASSPHRASE.")
+		}
+		salt, err := base64.StdEncoding.DecodeString(encryption.Salt)
+		if err != nil {
+			return fmt.Errorf("Read state encryption salt: %s", err)
+		}
+		c.key = passphraseKey(c.passphrase, salt)
+	case KMSEncryption:
+		if kmsClient == nil {
+			return fmt.Errorf("The state is encrypted with the AWS KMS key %s. Pass AWS credentials that can use it.", encryption.KMSKeyID)
+		}
+		dataKey, err := base64.StdEncoding.DecodeString(encryption.DataKey)
+		if err != nil {
+			return fmt.Errorf("Read state data key: %s", err)
+		}
+		c.key, err = kmsClient.Decrypt(dataKey)
+		if err != nil {
+			return fmt.Errorf("Decrypt state data key with %s: %s", encryption.KMSKeyID, err)
+		}
+	default:
+		return fmt.Errorf("Unknown state encryption method %q.", encryption.Method)
+	}
+
+	return nil
+}
+
+// Enable turns on encryption for a state that is not encrypted yet: with the
+// passphrase of the cipher, or with a new data key of the KMS key.
+func (c *StateCipher) Enable(state State, kmsKeyID string, kmsClient KMSClient) (State, error) {
+	if state.Encryption != nil {
+		return State{}, errors.New("The state is already encrypted.")
+	}
+
+	if kmsKeyID != "" {
+		plaintext, ciphertext, err := kmsClient.GenerateDataKey(kmsKeyID)
+		if err != nil {
+			return State{}, fmt.Errorf("Generate data key with %s: %s", kmsKeyID, err)
+		}
+		c.key = plaintext
+		state.Encryption = &Encryption{
+			Method:   KMSEncryption,
+			KMSKeyID: kmsKeyID,
+			DataKey:  base64.StdEncoding.EncodeToString(ciphertext),
+		}
+		return state, nil
+	}
+
+	if c.passphrase == "" {
+		return State{}, errors.New("Pass --state-passphrase or set BBL_STATE_PASSPHRASE to encrypt the state with a passphrase, or pass --kms-key-id.")
+	}
+
+	salt := make([]byte, 16)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return State{}, fmt.Errorf("Generate salt: %s", err)
+	}
+	c.key = passphraseKey(c.passphrase, salt)
+	state.Encryption = &Encryption{
+		Method: PassphraseEncryption,
+		Salt:   base64.StdEncoding.EncodeToString(salt),
+	}
+	return state, nil
+}
+
+// Disable turns off encryption. The state must have been decrypted.
+func (c *StateCipher) Disable(state State) State {
+	c.key = nil
+	state.Encryption = nil
+	return state
+}
+
+// Encrypt encrypts the sensitive fields of an encrypted state. A state that
+// has not been unlocked can only be saved as it was loaded.
+func (c *StateCipher) Encrypt(state State) (State, error) {
+	if state.Encryption == nil {
+		return state, nil
+	}
+
+	for _, field := range sensitiveFields(&state) {
+		if *field == "" || strings.HasPrefix(*field, encryptedPrefix) {
+			continue
+		}
+		if c.key == nil {
+			return State{}, errors.New("Encrypt state: the state has not been unlocked.")
+		}
+
+		if sealed, ok := c.sealed[*field]; ok {
+			*field = sealed
+			continue
+		}
+
+		sealed, err := c.seal(*field)
+		if err != nil {
+			return State{}, fmt.Errorf("Encrypt state: %s", err)
+		}
+		c.sealed[*field] = sealed
+		*field = sealed
+	}
+
+	return state, nil
+}
+
+// Decrypt decrypts the sensitive fields of a state loaded from the state
+// file. Unlock must be called first for an encrypted state.
+func (c *StateCipher) Decrypt(state State) (State, error) {
+	for _, field := range sensitiveFields(&state) {
+		if !strings.HasPrefix(*field, encryptedPrefix) {
+			continue
+		}
+		if c.key == nil {
+			return State{}, errors.New("Decrypt state: the state has not been unlocked.")
+		}
+
+		value, err := c.open(*field)
+		if err != nil {
+			return State{}, errors.New("Decrypt state: the passphrase or KMS key is not the one the state was encrypted with.")
+		}
+		c.sealed[value] = *field
+		*field = value
+	}
+
+	return state, nil
+}
+
+func (c *StateCipher) seal(value string) (string, error) {
+	gcm, err := c.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *StateCipher) open(value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := c.gcm()
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (c *StateCipher) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// passphraseKey derives the key of a state encrypted with a passphrase, with
+// PBKDF2 and HMAC-SHA256 as in RFC 8018.
+func passphraseKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, passphraseIterations, keyLength, sha256.New)
+}
@@ -0,0 +1,188 @@
+package storage_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateCipher", func() {
+	var (
+		cipher *storage.StateCipher
+		state  storage.State
+	)
+
+	BeforeEach(func() {
+		cipher = storage.NewStateCipher("some-passphrase")
+		state = storage.State{
+			EnvID: "some-env-id",
+			BOSH: storage.BOSH{
+				DirectorUsername:      "admin",
+				DirectorPassword:      "some-director-password",
+				DirectorSSLPrivateKey: "some-private-key",
+			},
+			LB: storage.LB{
+				Type: "cf",
+				Cert: "some-lb-cert",
+				Key:  "some-lb-key",
+			},
+		}
+	})
+
+	Context("when the state is encrypted with a passphrase", func() {
+		var encrypted storage.State
+
+		BeforeEach(func() {
+			var err error
+			state, err = cipher.Enable(state, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			encrypted, err = cipher.Encrypt(state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("encrypts the sensitive fields only", func() {
+			Expect(encrypted.Encryption.Method).To(Equal("passphrase"))
+			Expect(encrypted.Encryption.Salt).NotTo(BeEmpty())
+
+			Expect(encrypted.BOSH.DirectorPassword).To(HavePrefix("bbl-encrypted:"))
+			Expect(encrypted.BOSH.DirectorSSLPrivateKey).To(HavePrefix("bbl-encrypted:"))
+			Expect(encrypted.LB.Key).To(HavePrefix("bbl-encrypted:"))
+
+			Expect(encrypted.EnvID).To(Equal("some-env-id"))
+			Expect(encrypted.BOSH.DirectorUsername).To(Equal("admin"))
+			Expect(encrypted.LB.Cert).To(Equal("some-lb-cert"))
+		})
+
+		It("encrypts a value that has not changed the same way again", func() {
+			again, err := cipher.Encrypt(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again).To(Equal(encrypted))
+		})
+
+		It("decrypts the state with the same passphrase", func() {
+			otherCipher := storage.NewStateCipher("some-passphrase")
+			err := otherCipher.Unlock(encrypted.Encryption, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			decrypted, err := otherCipher.Decrypt(encrypted)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decrypted).To(Equal(state))
+		})
+
+		It("does not decrypt the state with another passphrase", func() {
+			otherCipher := storage.NewStateCipher("some-other-passphrase")
+			err := otherCipher.Unlock(encrypted.Encryption, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = otherCipher.Decrypt(encrypted)
+			Expect(err).To(MatchError("Decrypt state: the passphrase or KMS key is not the one the state was encrypted with."))
+		})
+
+		It("requires a passphrase to unlock it", func() {
+			err := storage.NewStateCipher("").Unlock(encrypted.Encryption, nil)
+			Expect(err).To(MatchError("The state is encrypted with a passphrase. Pass --state-passphrase or set BBL_STATE_PASSPHRASE."))
+		})
+
+		Context("when the state has not been unlocked", func() {
+			var locked *storage.StateCipher
+
+			BeforeEach(func() {
+				locked = storage.NewStateCipher("")
+			})
+
+			It("saves it as it was loaded", func() {
+				saved, err := locked.Encrypt(encrypted)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(saved).To(Equal(encrypted))
+			})
+
+			It("does not save new values in plain text", func() {
+				encrypted.BOSH.DirectorPassword = "some-new-password"
+
+				_, err := locked.Encrypt(encrypted)
+				Expect(err).To(MatchError("Encrypt state: the state has not been unlocked."))
+			})
+		})
+
+		It("writes the fields in plain text again when it is disabled", func() {
+			disabled, err := cipher.Encrypt(cipher.Disable(state))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(disabled.Encryption).To(BeNil())
+			Expect(disabled.BOSH.DirectorPassword).To(Equal("some-director-password"))
+		})
+	})
+
+	Context("when the state is encrypted with a KMS key", func() {
+		var kmsClient *fakes.KMSClient
+
+		BeforeEach(func() {
+			kmsClient = &fakes.KMSClient{}
+			kmsClient.GenerateDataKeyCall.Returns.Plaintext = []byte(strings.Repeat("k", 32))
+			kmsClient.GenerateDataKeyCall.Returns.Ciphertext = []byte("some-encrypted-data-key")
+			kmsClient.DecryptCall.Returns.Plaintext = []byte(strings.Repeat("k", 32))
+		})
+
+		It("keeps the data key encrypted by the KMS key", func() {
+			state, err := cipher.Enable(state, "some-key-id", kmsClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kmsClient.GenerateDataKeyCall.Receives.KeyID).To(Equal("some-key-id"))
+			Expect(state.Encryption).To(Equal(&storage.Encryption{
+				Method:   "kms",
+				KMSKeyID: "some-key-id",
+				DataKey:  base64.StdEncoding.EncodeToString([]byte("some-encrypted-data-key")),
+			}))
+
+			encrypted, err := cipher.Encrypt(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("decrypting the data key with the KMS key", func() {
+				otherCipher := storage.NewStateCipher("")
+				err := otherCipher.Unlock(encrypted.Encryption, kmsClient)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kmsClient.DecryptCall.Receives.Ciphertext).To(Equal([]byte("some-encrypted-data-key")))
+
+				decrypted, err := otherCipher.Decrypt(encrypted)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decrypted).To(Equal(state))
+			})
+		})
+
+		Context("when the data key cannot be decrypted", func() {
+			It("returns an error", func() {
+				kmsClient.DecryptCall.Returns.Error = errors.New("access denied")
+
+				err := cipher.Unlock(&storage.Encryption{Method: "kms", KMSKeyID: "some-key-id"}, kmsClient)
+				Expect(err).To(MatchError("Decrypt state data key with some-key-id: access denied"))
+			})
+		})
+	})
+
+	It("derives keys from passphrases with PBKDF2 and HMAC-SHA256", func() {
+		key := storage.PassphraseKey("passwd", []byte("salt"))
+		Expect(hex.EncodeToString(key)).To(Equal("15361a12e9cdf546262d468fe84b03a9bdc1e711b99d0429db9f8d9167e52366"))
+	})
+})
+
+var _ = Describe("Encryption", func() {
+	Describe("KMSCredentials", func() {
+		It("returns the credentials of the environment in the region of the KMS key", func() {
+			creds := storage.AWS{AccessKeyID: "some-access-key-id", Region: "us-west-2"}
+
+			Expect(storage.Encryption{Method: "kms"}.KMSCredentials(creds)).To(Equal(creds))
+			Expect(storage.Encryption{Method: "kms", KMSRegion: "us-east-1"}.KMSCredentials(creds)).To(Equal(storage.AWS{
+				AccessKeyID: "some-access-key-id",
+				Region:      "us-east-1",
+			}))
+		})
+	})
+})
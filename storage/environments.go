@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// EnvsDir is the directory of a state directory that holds the state
+// directories of its named environments, which bbl --env selects.
+const EnvsDir = "envs"
+
+var envName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// EnvStateDir is the state directory of the named environment of a state
+// directory.
+func EnvStateDir(stateDir, env string) (string, error) {
+	if !envName.MatchString(env) {
+		return "", fmt.Errorf("--env %q is not an environment name, which has lowercase letters, digits and hyphens.", env)
+	}
+
+	return filepath.Join(stateDir, EnvsDir, env), nil
+}
+
+// Envs lists the named environments of a state directory.
+func Envs(stateDir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(stateDir, EnvsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	envs := []string{}
+	for _, info := range infos {
+		if info.IsDir() && envName.MatchString(info.Name()) {
+			envs = append(envs, info.Name())
+		}
+	}
+	return envs, nil
+}
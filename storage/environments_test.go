@@ -0,0 +1,57 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Environments", func() {
+	var stateDir string
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	Describe("EnvStateDir", func() {
+		It("is the directory of the environment in the envs directory", func() {
+			dir, err := storage.EnvStateDir(stateDir, "staging")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dir).To(Equal(filepath.Join(stateDir, "envs", "staging")))
+		})
+
+		It("returns an error for a name that is not a directory name of its own", func() {
+			_, err := storage.EnvStateDir(stateDir, "../prod")
+			Expect(err).To(MatchError(`--env "../prod" is not an environment name, which has lowercase letters, digits and hyphens.`))
+		})
+	})
+
+	Describe("Envs", func() {
+		It("lists the environments in order", func() {
+			Expect(os.MkdirAll(filepath.Join(stateDir, "envs", "staging"), os.ModePerm)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(stateDir, "envs", "prod"), os.ModePerm)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(stateDir, "envs", "notes"), []byte{}, os.ModePerm)).To(Succeed())
+
+			envs, err := storage.Envs(stateDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(envs).To(Equal([]string{"prod", "staging"}))
+		})
+
+		It("lists none for a state directory without environments", func() {
+			envs, err := storage.Envs(stateDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(envs).To(BeEmpty())
+		})
+	})
+})
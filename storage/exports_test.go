@@ -2,6 +2,7 @@ package storage
 
 import (
 	"encoding/json"
+	"time"
 
 	uuid "github.com/nu7hatch/gouuid"
 )
@@ -21,3 +22,15 @@ func SetUUIDNewV4(f func() (uuid *uuid.UUID, err error)) {
 func ResetUUIDNewV4() {
 	uuidNewV4 = uuid.NewV4
 }
+
+func SetBackupNow(f func() time.Time) {
+	backupNow = f
+}
+
+func ResetBackupNow() {
+	backupNow = time.Now
+}
+
+func PassphraseKey(passphrase string, salt []byte) []byte {
+	return passphraseKey(passphrase, salt)
+}
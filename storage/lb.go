@@ -1,9 +1,74 @@
 package storage
 
+import (
+	"strconv"
+	"strings"
+)
+
 type LB struct {
-	Type   string `json:"type"`
-	Cert   string `json:"cert"`
-	Key    string `json:"key"`
-	Chain  string `json:"chain"`
-	Domain string `json:"domain,omitempty"`
+	Type       string `json:"type"`
+	Cert       string `json:"cert"`
+	Key        string `json:"key"`
+	Chain      string `json:"chain"`
+	CertARN    string `json:"certARN,omitempty"`
+	Domain     string `json:"domain,omitempty"`
+	DNSRoleARN string `json:"dnsRoleARN,omitempty"`
+	// DNSZoneID is an existing hosted zone for the records of Domain and
+	// AppsDomain, in place of a zone that bbl creates for Domain.
+	DNSZoneID  string `json:"dnsZoneID,omitempty"`
+	AppsDomain string `json:"appsDomain,omitempty"`
+	// Internal load balancers are only reachable from inside the VPC, on
+	// the internal subnets.
+	Internal bool `json:"internal,omitempty"`
+	// ELBv2 makes the cf router an application load balancer and the ssh
+	// proxy a network load balancer, whose VMs join their target groups.
+	ELBv2 bool `json:"elbv2,omitempty"`
+	// ACMCertificate has bbl request a certificate of Domain from AWS
+	// Certificate Manager, which it validates with a record in the hosted
+	// zone of Domain.
+	ACMCertificate bool `json:"acmCertificate,omitempty"`
+	LBHealthCheck
+}
+
+// LBHealthCheck replaces the settings of the health check of the cf router
+// load balancers, such as to check the HTTP:8080/health endpoint of gorouter
+// rather than port 80. Settings that are not set keep the defaults of the
+// terraform template.
+type LBHealthCheck struct {
+	HealthCheckTarget   string `json:"healthCheckTarget,omitempty"`
+	HealthCheckInterval int    `json:"healthCheckInterval,omitempty"`
+	HealthyThreshold    int    `json:"healthyThreshold,omitempty"`
+	UnhealthyThreshold  int    `json:"unhealthyThreshold,omitempty"`
+	HealthCheckTimeout  int    `json:"healthCheckTimeout,omitempty"`
+}
+
+// HealthCheckPort is the port of the target, such as 8080 of
+// HTTP:8080/health, or 0 when the target is not set.
+func (h LBHealthCheck) HealthCheckPort() int {
+	parts := strings.SplitN(h.HealthCheckTarget, ":", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+
+	port, err := strconv.Atoi(strings.SplitN(parts[1], "/", 2)[0])
+	if err != nil {
+		return 0
+	}
+	return port
+}
+
+// HealthCheckProtocol is the protocol of the target, such as HTTP of
+// HTTP:8080/health.
+func (h LBHealthCheck) HealthCheckProtocol() string {
+	return strings.SplitN(h.HealthCheckTarget, ":", 2)[0]
+}
+
+// HealthCheckPath is the path of the target, such as /health of
+// HTTP:8080/health, or "" when the target has none.
+func (h LBHealthCheck) HealthCheckPath() string {
+	parts := strings.SplitN(h.HealthCheckTarget, "/", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	return "/" + parts[1]
 }
@@ -106,6 +106,8 @@ func (m Migrator) Migrate(state State) (State, error) {
 		return State{}, err
 	}
 
+	state = m.MigrateCertificate(state)
+
 	err = m.store.Set(state)
 	if err != nil {
 		return State{}, fmt.Errorf("saving migrated state: %s", err)
@@ -289,3 +291,22 @@ func (m Migrator) MigrateJumpboxVarsFile(varsDir string) error {
 	}
 	return nil
 }
+
+// MigrateCertificate moves the certificate of bbl upload-certificate, from
+// before certificates were named, to the certificates of the state. It stays
+// attached to the cf router load balancer, which bbl plan --lb-type cf used
+// it for.
+func (m Migrator) MigrateCertificate(state State) State {
+	if state.AWS.Certificate == nil {
+		return state
+	}
+
+	certificate := *state.AWS.Certificate
+	state.AWS.Certificate = nil
+	if state.AWS.CertificateNamed(certificate.Name) == nil {
+		state.AWS.Certificates = append(state.AWS.Certificates, certificate)
+	}
+	state.AWS.AttachCertificate(certificate.Name, "cf-router")
+
+	return state
+}
@@ -508,4 +508,22 @@ var _ = Describe("Migrator", func() {
 			})
 		})
 	})
+
+	Describe("MigrateCertificate", func() {
+		It("moves the certificate of upload-certificate to the certificates, attached to the cf router", func() {
+			state := storage.State{}
+			state.AWS.Certificate = &storage.ServerCertificate{Name: "some-env-475fcfe6f4b01a10", ARN: "some-arn", Fingerprint: "47:5F"}
+
+			state = migrator.MigrateCertificate(state)
+			Expect(state.AWS.Certificate).To(BeNil())
+			Expect(state.AWS.Certificates).To(Equal([]storage.ServerCertificate{
+				{Name: "some-env-475fcfe6f4b01a10", ARN: "some-arn", Fingerprint: "47:5F", LBs: []string{"cf-router"}},
+			}))
+		})
+
+		It("leaves a state without the certificate alone", func() {
+			state := storage.State{EnvID: "some-env"}
+			Expect(migrator.MigrateCertificate(state)).To(Equal(state))
+		})
+	})
 })
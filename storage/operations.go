@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	OperationsDirName = "bbl-operations"
+
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationUnknown   = "unknown"
+
+	operationFileName = "operation.json"
+	outputFileName    = "output.log"
+	exitCodeFileName  = "exit-code"
+)
+
+// The shell records the exit code of the command next to its output, so that
+// the result survives the process that started it. It ignores SIGHUP so that
+// closing the terminal does not stop the command.
+const operationWrapper = `trap "" HUP; "$0" "$@" > "$BBL_OPERATION_DIR/output.log" 2>&1; echo $? > "$BBL_OPERATION_DIR/exit-code"`
+
+// Operation is a mutating command that bbl runs in the background for
+// --no-wait. Only the name of the command is recorded, since its flags can
+// hold credentials.
+type Operation struct {
+	ID       string    `json:"id"`
+	Command  string    `json:"command"`
+	PID      int       `json:"pid"`
+	Started  time.Time `json:"started"`
+	Status   string    `json:"status"`
+	ExitCode *int      `json:"exitCode,omitempty"`
+	Output   string    `json:"output"`
+}
+
+type Operations struct {
+	dir     string
+	bblPath string
+	args    []string
+}
+
+// NewOperations keeps the operations of the state directory in stateDir.
+// bblPath and args are the bbl binary and the arguments it was run with; Start
+// runs them again without --no-wait.
+func NewOperations(stateDir, bblPath string, args []string) Operations {
+	return Operations{
+		dir:     filepath.Join(stateDir, OperationsDirName),
+		bblPath: bblPath,
+		args:    args,
+	}
+}
+
+func (o Operations) Start(command string) (Operation, error) {
+	id, err := newOperationID()
+	if err != nil {
+		return Operation{}, fmt.Errorf("Generate operation id: %s", err)
+	}
+
+	dir := filepath.Join(o.dir, id)
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return Operation{}, fmt.Errorf("Create operation dir: %s", err)
+	}
+
+	cmd := exec.Command("/bin/sh", append([]string{"-c", operationWrapper, o.bblPath}, withoutNoWait(o.args)...)...)
+	cmd.Env = append(os.Environ(), "BBL_NO_WAIT=false", fmt.Sprintf("BBL_OPERATION_DIR=%s", dir))
+
+	err = cmd.Start()
+	if err != nil {
+		return Operation{}, fmt.Errorf("Start operation: %s", err)
+	}
+
+	operation := Operation{
+		ID:      id,
+		Command: command,
+		PID:     cmd.Process.Pid,
+		Started: time.Now().UTC(),
+		Status:  OperationRunning,
+		Output:  filepath.Join(dir, outputFileName),
+	}
+
+	contents, err := json.MarshalIndent(operation, "", "  ")
+	if err != nil {
+		return Operation{}, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, operationFileName), contents, StateMode)
+	if err != nil {
+		return Operation{}, fmt.Errorf("Write operation: %s", err)
+	}
+
+	err = cmd.Process.Release()
+	if err != nil {
+		return Operation{}, fmt.Errorf("Release operation: %s", err)
+	}
+
+	return operation, nil
+}
+
+// Get reads an operation and works out its status: the exit code once the
+// command has finished, running while its process is alive, and unknown when
+// the process went away without recording an exit code.
+func (o Operations) Get(id string) (Operation, error) {
+	dir := filepath.Join(o.dir, filepath.Base(id))
+	contents, err := ioutil.ReadFile(filepath.Join(dir, operationFileName))
+	if os.IsNotExist(err) {
+		return Operation{}, fmt.Errorf("There is no operation %s in %s.", id, o.dir)
+	}
+	if err != nil {
+		return Operation{}, fmt.Errorf("Read operation: %s", err)
+	}
+
+	var operation Operation
+	err = json.Unmarshal(contents, &operation)
+	if err != nil {
+		return Operation{}, fmt.Errorf("Read operation %s: %s", id, err)
+	}
+	operation.Output = filepath.Join(dir, outputFileName)
+
+	exitCode, err := ioutil.ReadFile(filepath.Join(dir, exitCodeFileName))
+	if err == nil {
+		code, err := strconv.Atoi(strings.TrimSpace(string(exitCode)))
+		if err == nil {
+			operation.ExitCode = &code
+			operation.Status = OperationSucceeded
+			if code != 0 {
+				operation.Status = OperationFailed
+			}
+			return operation, nil
+		}
+	}
+
+	if processIsAlive(operation.PID) {
+		operation.Status = OperationRunning
+	} else {
+		operation.Status = OperationUnknown
+	}
+
+	return operation, nil
+}
+
+// List returns every operation, the most recent first.
+func (o Operations) List() ([]Operation, error) {
+	entries, err := ioutil.ReadDir(o.dir)
+	if os.IsNotExist(err) {
+		return []Operation{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("List operations: %s", err)
+	}
+
+	operations := []Operation{}
+	for _, entry := range entries {
+		// An operation that is starting has no record yet.
+		_, err := os.Stat(filepath.Join(o.dir, entry.Name(), operationFileName))
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+
+		operation, err := o.Get(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, operation)
+	}
+
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].Started.After(operations[j].Started)
+	})
+
+	return operations, nil
+}
+
+func newOperationID() (string, error) {
+	suffix := make([]byte, 4)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405Z"), hex.EncodeToString(suffix)), nil
+}
+
+func withoutNoWait(args []string) []string {
+	filtered := []string{}
+	for _, arg := range args {
+		if arg == "--no-wait" || strings.HasPrefix(arg, "--no-wait=") {
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
+
+func processIsAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	return process.Signal(syscall.Signal(0)) == nil
+}
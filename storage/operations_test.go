@@ -0,0 +1,126 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operations", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	waitFor := func(operations storage.Operations, id string) storage.Operation {
+		var operation storage.Operation
+		Eventually(func() string {
+			var err error
+			operation, err = operations.Get(id)
+			Expect(err).NotTo(HaveOccurred())
+			return operation.Status
+		}, "5s", "10ms").ShouldNot(Equal(storage.OperationRunning))
+		return operation
+	}
+
+	Describe("Start", func() {
+		It("runs the command again without --no-wait and records its output and exit code", func() {
+			operations := storage.NewOperations(tempDir, "echo", []string{"--no-wait", "up", "--name", "some-name"})
+
+			operation, err := operations.Start("up")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.ID).To(MatchRegexp(`^\d{8}T\d{6}Z-[0-9a-f]{8}$`))
+			Expect(operation.Command).To(Equal("up"))
+			Expect(operation.PID).NotTo(BeZero())
+			Expect(operation.Output).To(Equal(filepath.Join(tempDir, "bbl-operations", operation.ID, "output.log")))
+
+			operation = waitFor(operations, operation.ID)
+			Expect(operation.Status).To(Equal(storage.OperationSucceeded))
+			Expect(*operation.ExitCode).To(Equal(0))
+
+			output, err := ioutil.ReadFile(operation.Output)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(Equal("up --name some-name\n"))
+		})
+
+		It("does not record the flags of the command", func() {
+			operations := storage.NewOperations(tempDir, "true", []string{"up", "--aws-secret-access-key", "some-secret"})
+
+			operation, err := operations.Start("up")
+			Expect(err).NotTo(HaveOccurred())
+			waitFor(operations, operation.ID)
+
+			record, err := ioutil.ReadFile(filepath.Join(tempDir, "bbl-operations", operation.ID, "operation.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(record)).NotTo(ContainSubstring("some-secret"))
+		})
+
+		It("records a failed command", func() {
+			operations := storage.NewOperations(tempDir, "false", []string{"destroy"})
+
+			operation, err := operations.Start("destroy")
+			Expect(err).NotTo(HaveOccurred())
+
+			operation = waitFor(operations, operation.ID)
+			Expect(operation.Status).To(Equal(storage.OperationFailed))
+			Expect(*operation.ExitCode).To(Equal(1))
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns an error for an unknown operation", func() {
+			operations := storage.NewOperations(tempDir, "true", []string{})
+
+			_, err := operations.Get("some-id")
+			Expect(err).To(MatchError(ContainSubstring("There is no operation some-id in")))
+		})
+
+		It("reports an operation whose process is gone without an exit code as unknown", func() {
+			dir := filepath.Join(tempDir, "bbl-operations", "some-id")
+			Expect(os.MkdirAll(dir, os.ModePerm)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "operation.json"), []byte(`{"id":"some-id","command":"up","pid":0}`), os.ModePerm)).To(Succeed())
+
+			operation, err := storage.NewOperations(tempDir, "true", []string{}).Get("some-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.Status).To(Equal(storage.OperationUnknown))
+			Expect(operation.ExitCode).To(BeNil())
+		})
+	})
+
+	Describe("List", func() {
+		It("returns no operations when none have run", func() {
+			operations, err := storage.NewOperations(tempDir, "true", []string{}).List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations).To(BeEmpty())
+		})
+
+		It("returns the operations, the most recent first", func() {
+			operations := storage.NewOperations(tempDir, "true", []string{})
+
+			first, err := operations.Start("plan")
+			Expect(err).NotTo(HaveOccurred())
+			time.Sleep(10 * time.Millisecond)
+			second, err := operations.Start("up")
+			Expect(err).NotTo(HaveOccurred())
+
+			listed, err := operations.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listed).To(HaveLen(2))
+			Expect(listed[0].ID).To(Equal(second.ID))
+			Expect(listed[1].ID).To(Equal(first.ID))
+		})
+	})
+})
@@ -0,0 +1,16 @@
+package storage
+
+const (
+	RegionMigrationSnapshotted  = "snapshotted"
+	RegionMigrationProvisioning = "provisioning"
+)
+
+// RegionMigration records the progress of `bbl migrate-region` so that an
+// interrupted migration resumes from its last checkpoint.
+type RegionMigration struct {
+	FromRegion  string `json:"fromRegion"`
+	FromEnvID   string `json:"fromEnvID"`
+	To          string `json:"to"`
+	SnapshotDir string `json:"snapshotDir"`
+	Phase       string `json:"phase"`
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	JSONStateFormat = "json"
+	YAMLStateFormat = "yaml"
+
+	YAMLStateFileName = "bbl-state.yml"
+)
+
+var stateFileNames = []string{StateFileName, YAMLStateFileName}
+
+type StateSerializer interface {
+	FileName() string
+	// Marshal encodes the state. existing holds the current contents of the
+	// state file, if any, so that formats with comments can carry them over.
+	Marshal(state State, existing []byte) ([]byte, error)
+	Unmarshal(data []byte, state *State) error
+}
+
+// NewStateSerializer returns the serializer for the given format. When no
+// format is given, the format of the state file already in dir is kept, and
+// new environments default to JSON.
+func NewStateSerializer(format, dir string) (StateSerializer, error) {
+	switch format {
+	case JSONStateFormat:
+		return JSONStateSerializer{}, nil
+	case YAMLStateFormat, "yml":
+		return YAMLStateSerializer{}, nil
+	case "":
+		return stateSerializerForDir(dir), nil
+	default:
+		return nil, fmt.Errorf("Unknown state format %q. Use %q or %q.", format, JSONStateFormat, YAMLStateFormat)
+	}
+}
+
+func stateSerializerForDir(dir string) StateSerializer {
+	if _, err := os.Stat(filepath.Join(dir, StateFileName)); err == nil {
+		return JSONStateSerializer{}
+	}
+	if _, err := os.Stat(filepath.Join(dir, YAMLStateFileName)); err == nil {
+		return YAMLStateSerializer{}
+	}
+	return JSONStateSerializer{}
+}
+
+type JSONStateSerializer struct{}
+
+func (JSONStateSerializer) FileName() string { return StateFileName }
+
+func (JSONStateSerializer) Marshal(state State, existing []byte) ([]byte, error) {
+	return marshalIndent(state, "", "\t")
+}
+
+func (JSONStateSerializer) Unmarshal(data []byte, state *State) error {
+	return json.NewDecoder(bytes.NewReader(data)).Decode(state)
+}
+
+// YAMLStateSerializer writes the same document as the JSON serializer, field
+// for field, so that switching formats is lossless. Comments at the top of
+// an existing file are kept; comments elsewhere are not.
+type YAMLStateSerializer struct{}
+
+func (YAMLStateSerializer) FileName() string { return YAMLStateFileName }
+
+func (YAMLStateSerializer) Marshal(state State, existing []byte) ([]byte, error) {
+	jsonData, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.MapSlice
+	err = yaml.Unmarshal(jsonData, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	yamlData, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(leadingComments(existing)), yamlData...), nil
+}
+
+func (YAMLStateSerializer) Unmarshal(data []byte, state *State) error {
+	var document interface{}
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(stringKeys(document))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonData, state)
+}
+
+func leadingComments(data []byte) string {
+	header := ""
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		header += line
+	}
+
+	if !strings.Contains(header, "#") {
+		return ""
+	}
+	return header
+}
+
+// stringKeys converts the maps yaml.v2 decodes into ones encoding/json accepts.
+func stringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, element := range v {
+			converted[fmt.Sprintf("%v", key)] = stringKeys(element)
+		}
+		return converted
+	case []interface{}:
+		for i, element := range v {
+			v[i] = stringKeys(element)
+		}
+		return v
+	default:
+		return value
+	}
+}
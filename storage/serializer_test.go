@@ -0,0 +1,132 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateSerializer", func() {
+	var state storage.State
+
+	BeforeEach(func() {
+		state = storage.State{
+			Version:    14,
+			BBLVersion: "6.0.0",
+			IAAS:       "aws",
+			ID:         "some-id",
+			EnvID:      "some-env-id",
+			AWS:        storage.AWS{Region: "some-region"},
+			LB: storage.LB{
+				Type: "cf",
+				Cert: "-----BEGIN CERTIFICATE-----\nsome-cert\n-----END CERTIFICATE-----\n",
+			},
+			Jumpbox: storage.Jumpbox{
+				URL:   "some-jumpbox-url",
+				State: map[string]interface{}{"disks": []interface{}{"disk-1"}, "current_vm_cid": "i-123"},
+			},
+			TFState: `{"version": 3, "modules": []}`,
+		}
+	})
+
+	Describe("NewStateSerializer", func() {
+		var tempDir string
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the serializer for the requested format", func() {
+			serializer, err := storage.NewStateSerializer("yaml", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serializer.FileName()).To(Equal("bbl-state.yml"))
+
+			serializer, err = storage.NewStateSerializer("json", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serializer.FileName()).To(Equal("bbl-state.json"))
+		})
+
+		It("defaults to json", func() {
+			serializer, err := storage.NewStateSerializer("", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serializer.FileName()).To(Equal("bbl-state.json"))
+		})
+
+		Context("when the state dir has a yaml state file", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.yml"), []byte("iaas: aws\n"), storage.StateMode)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("keeps the yaml format", func() {
+				serializer, err := storage.NewStateSerializer("", tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(serializer.FileName()).To(Equal("bbl-state.yml"))
+			})
+		})
+
+		Context("when the format is unknown", func() {
+			It("returns an error", func() {
+				_, err := storage.NewStateSerializer("toml", tempDir)
+				Expect(err).To(MatchError(`Unknown state format "toml". Use "json" or "yaml".`))
+			})
+		})
+	})
+
+	Describe("YAMLStateSerializer", func() {
+		var serializer storage.YAMLStateSerializer
+
+		It("round trips the state without losing anything", func() {
+			data, err := serializer.Marshal(state, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var roundTripped storage.State
+			err = serializer.Unmarshal(data, &roundTripped)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roundTripped).To(Equal(state))
+		})
+
+		It("writes the same fields as the json state file", func() {
+			data, err := serializer.Marshal(state, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(data)).To(HavePrefix("version: 14\nbblVersion: 6.0.0\niaas: aws\n"))
+			Expect(string(data)).To(ContainSubstring("  cert: |\n    -----BEGIN CERTIFICATE-----\n    some-cert\n"))
+		})
+
+		It("keeps comments at the top of the existing file", func() {
+			existing := []byte("# Reviewed in PR #12\n# Do not edit by hand\n\nversion: 13\n# not kept\n")
+
+			data, err := serializer.Marshal(state, existing)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("# Reviewed in PR #12\n# Do not edit by hand\n\nversion: 14\n"))
+			Expect(string(data)).NotTo(ContainSubstring("not kept"))
+		})
+
+		It("returns an error when the yaml is invalid", func() {
+			var s storage.State
+			err := serializer.Unmarshal([]byte("iaas: [aws"), &s)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("JSONStateSerializer", func() {
+		var serializer storage.JSONStateSerializer
+
+		It("round trips the state without losing anything", func() {
+			data, err := serializer.Marshal(state, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var roundTripped storage.State
+			err = serializer.Unmarshal(data, &roundTripped)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roundTripped).To(Equal(state))
+		})
+	})
+})
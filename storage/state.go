@@ -7,6 +7,9 @@ type State struct {
 	ID             string    `json:"id"`
 	EnvID          string    `json:"envID"`
 	NoDirector     bool      `json:"noDirector"`
+	SSHCA          bool      `json:"sshCA,omitempty"`
+	TrustedCACerts string    `json:"trustedCACerts,omitempty"`
+	ArtifactsDir   string    `json:"artifactsDir,omitempty"`
 	AWS            AWS       `json:"aws,omitempty"`
 	Azure          Azure     `json:"azure,omitempty"`
 	GCP            GCP       `json:"gcp,omitempty"`
@@ -17,4 +20,27 @@ type State struct {
 	TFState        string    `json:"tfState"`
 	LB             LB        `json:"lb"`
 	LatestTFOutput string    `json:"latestTFOutput"`
+
+	DirectorPorts     *DirectorPorts     `json:"directorPorts,omitempty"`
+	DirectorVM        *DirectorVM        `json:"directorVM,omitempty"`
+	DirectorSyslog    *DirectorSyslog    `json:"directorSyslog,omitempty"`
+	ArtifactOverrides *ArtifactOverrides `json:"artifactOverrides,omitempty"`
+	RegionMigration   *RegionMigration   `json:"regionMigration,omitempty"`
+	Encryption        *Encryption        `json:"encryption,omitempty"`
+	UpProgress        *UpProgress        `json:"upProgress,omitempty"`
+	DirectorVersions  *DirectorVersions  `json:"directorVersions,omitempty"`
+
+	// DeployedArtifacts are the urls and sha1s of the releases and stemcell
+	// that the director was last deployed from, which a failed upgrade rolls
+	// it back to.
+	DeployedArtifacts *ArtifactOverrides `json:"deployedArtifacts,omitempty"`
+
+	// DirectorOpsFiles and DirectorVarsFiles are the files of bbl plan
+	// --ops-file and --vars-file, in the order they are applied.
+	DirectorOpsFiles  []DirectorFile `json:"directorOpsFiles,omitempty"`
+	DirectorVarsFiles []DirectorFile `json:"directorVarsFiles,omitempty"`
+
+	// Annotations are metadata that bbl annotate records about the
+	// environment, such as its owner. On aws they are also resource tags.
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
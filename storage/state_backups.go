@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// StateBackupsDirName is the directory of a state directory that holds
+	// copies of the state file from before each command that changed it.
+	StateBackupsDirName = ".bbl-backups"
+
+	// StateBackupRetention is the number of backups that are kept. The
+	// oldest are removed first.
+	StateBackupRetention = 20
+
+	StateBackupTimeFormat = "20060102T150405Z"
+)
+
+var backupNow = time.Now
+
+type StateBackups struct {
+	dir string
+}
+
+func NewStateBackups(dir string) StateBackups {
+	return StateBackups{dir: dir}
+}
+
+// Backup copies the state file into the backups directory, named by the time,
+// and removes the backups beyond StateBackupRetention. A state directory
+// without a state file has nothing to back up. A backup of the same second is
+// kept rather than overwritten, since it is the state from before both
+// commands.
+func (b StateBackups) Backup() error {
+	name, contents, err := b.stateFile()
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Back up state: %s", err)
+	}
+
+	err = os.MkdirAll(b.path(), os.FileMode(0700))
+	if err != nil {
+		return fmt.Errorf("Back up state: %s", err)
+	}
+
+	backupFile := filepath.Join(b.path(), fmt.Sprintf("%s-%s", backupNow().UTC().Format(StateBackupTimeFormat), name))
+	if _, err := os.Stat(backupFile); err == nil {
+		return nil
+	}
+
+	err = ioutil.WriteFile(backupFile, contents, StateMode)
+	if err != nil {
+		return fmt.Errorf("Back up state: %s", err)
+	}
+
+	files, err := b.files()
+	if err != nil {
+		return fmt.Errorf("Back up state: %s", err)
+	}
+	for len(files) > StateBackupRetention {
+		err = os.Remove(filepath.Join(b.path(), files[0]))
+		if err != nil {
+			return fmt.Errorf("Remove old state backup: %s", err)
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// List returns the times of the backups, oldest first.
+func (b StateBackups) List() ([]string, error) {
+	files, err := b.files()
+	if err != nil {
+		return nil, err
+	}
+
+	timestamps := []string{}
+	for _, file := range files {
+		timestamps = append(timestamps, backupTimestamp(file))
+	}
+	return timestamps, nil
+}
+
+// Restore replaces the state file with the backup of the time.
+func (b StateBackups) Restore(timestamp string) error {
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if backupTimestamp(file) != timestamp {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(b.path(), file))
+		if err != nil {
+			return fmt.Errorf("Read state backup: %s", err)
+		}
+
+		name := strings.TrimPrefix(file, timestamp+"-")
+		for _, stateFileName := range stateFileNames {
+			if stateFileName == name {
+				continue
+			}
+			err = os.Remove(filepath.Join(b.dir, stateFileName))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("Restore state: %s", err)
+			}
+		}
+
+		err = ioutil.WriteFile(filepath.Join(b.dir, name), contents, StateMode)
+		if err != nil {
+			return fmt.Errorf("Restore state: %s", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("There is no state backup from %s. Run bbl state list-backups to list them.", timestamp)
+}
+
+func (b StateBackups) stateFile() (string, []byte, error) {
+	for _, name := range stateFileNames {
+		contents, err := ioutil.ReadFile(filepath.Join(b.dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		return name, contents, err
+	}
+	return "", nil, os.ErrNotExist
+}
+
+// files are the names of the backups, oldest first, since their times sort
+// in order.
+func (b StateBackups) files() ([]string, error) {
+	infos, err := ioutil.ReadDir(b.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("List state backups: %s", err)
+	}
+
+	files := []string{}
+	for _, info := range infos {
+		for _, name := range stateFileNames {
+			if strings.HasSuffix(info.Name(), "-"+name) {
+				files = append(files, info.Name())
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (b StateBackups) path() string {
+	return filepath.Join(b.dir, StateBackupsDirName)
+}
+
+func backupTimestamp(file string) string {
+	return strings.SplitN(file, "-", 2)[0]
+}
@@ -0,0 +1,144 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateBackups", func() {
+	var (
+		tempDir string
+		backups storage.StateBackups
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		now = time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+		storage.SetBackupNow(func() time.Time { return now })
+
+		backups = storage.NewStateBackups(tempDir)
+	})
+
+	AfterEach(func() {
+		storage.ResetBackupNow()
+		os.RemoveAll(tempDir)
+	})
+
+	writeState := func(name, contents string) {
+		Expect(ioutil.WriteFile(filepath.Join(tempDir, name), []byte(contents), storage.StateMode)).To(Succeed())
+	}
+
+	readState := func(name string) string {
+		contents, err := ioutil.ReadFile(filepath.Join(tempDir, name))
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	Describe("Backup", func() {
+		It("copies the state file into the backups directory under the time", func() {
+			writeState("bbl-state.json", `{"version": 14}`)
+
+			Expect(backups.Backup()).To(Succeed())
+
+			Expect(readState(".bbl-backups/20260304T050607Z-bbl-state.json")).To(Equal(`{"version": 14}`))
+		})
+
+		It("keeps a yaml state file by its name", func() {
+			writeState("bbl-state.yml", "version: 14\n")
+
+			Expect(backups.Backup()).To(Succeed())
+
+			Expect(readState(".bbl-backups/20260304T050607Z-bbl-state.yml")).To(Equal("version: 14\n"))
+		})
+
+		It("does nothing without a state file", func() {
+			Expect(backups.Backup()).To(Succeed())
+
+			_, err := os.Stat(filepath.Join(tempDir, ".bbl-backups"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("keeps the backup of the same second", func() {
+			writeState("bbl-state.json", "first")
+			Expect(backups.Backup()).To(Succeed())
+
+			writeState("bbl-state.json", "second")
+			Expect(backups.Backup()).To(Succeed())
+
+			Expect(readState(".bbl-backups/20260304T050607Z-bbl-state.json")).To(Equal("first"))
+		})
+
+		It("removes the oldest backups beyond the retention", func() {
+			writeState("bbl-state.json", "{}")
+			for i := 0; i < storage.StateBackupRetention+2; i++ {
+				now = now.Add(time.Minute)
+				Expect(backups.Backup()).To(Succeed())
+			}
+
+			timestamps, err := backups.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(timestamps).To(HaveLen(storage.StateBackupRetention))
+			Expect(timestamps[0]).To(Equal("20260304T050907Z"))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the times of the backups, oldest first", func() {
+			writeState("bbl-state.json", "{}")
+			Expect(backups.Backup()).To(Succeed())
+			now = now.Add(-time.Hour)
+			Expect(backups.Backup()).To(Succeed())
+
+			timestamps, err := backups.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(timestamps).To(Equal([]string{"20260304T040607Z", "20260304T050607Z"}))
+		})
+
+		It("lists none without backups", func() {
+			timestamps, err := backups.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(timestamps).To(BeEmpty())
+		})
+	})
+
+	Describe("Restore", func() {
+		It("replaces the state file with the backup", func() {
+			writeState("bbl-state.json", "known-good")
+			Expect(backups.Backup()).To(Succeed())
+			writeState("bbl-state.json", "half-updated")
+
+			Expect(backups.Restore("20260304T050607Z")).To(Succeed())
+
+			Expect(readState("bbl-state.json")).To(Equal("known-good"))
+		})
+
+		It("removes a state file of the other format", func() {
+			writeState("bbl-state.json", "known-good")
+			Expect(backups.Backup()).To(Succeed())
+			Expect(os.Remove(filepath.Join(tempDir, "bbl-state.json"))).To(Succeed())
+			writeState("bbl-state.yml", "converted")
+
+			Expect(backups.Restore("20260304T050607Z")).To(Succeed())
+
+			Expect(readState("bbl-state.json")).To(Equal("known-good"))
+			_, err := os.Stat(filepath.Join(tempDir, "bbl-state.yml"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("returns an error for a time without a backup", func() {
+			err := backups.Restore("20260101T000000Z")
+			Expect(err).To(MatchError("There is no state backup from 20260101T000000Z. Run bbl state list-backups to list them."))
+		})
+	})
+})
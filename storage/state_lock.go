@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+)
+
+const StateLockFileName = "bbl-state.lock"
+
+type StateLock struct {
+	dir string
+}
+
+func NewStateLock(dir string) StateLock {
+	return StateLock{dir: dir}
+}
+
+// Lock claims the state directory for a command that mutates state. Only one
+// such command may hold the lock at a time; the lock file records its pid.
+func (l StateLock) Lock() error {
+	file, err := os.OpenFile(l.path(), os.O_CREATE|os.O_EXCL|os.O_WRONLY, OS_READ_WRITE_MODE)
+	if os.IsExist(err) {
+		return bblerrors.New(bblerrors.Conflict, fmt.Errorf("Another bbl command (pid %s) is modifying the state in %q. If no other bbl command is running, remove %s and try again.", l.holder(), l.dir, l.path()))
+	}
+	if err != nil {
+		return fmt.Errorf("Lock state: %s", err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(strconv.Itoa(os.Getpid()))
+	if err != nil {
+		return fmt.Errorf("Write state lock: %s", err)
+	}
+
+	return nil
+}
+
+func (l StateLock) Unlock() error {
+	err := os.Remove(l.path())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Unlock state: %s", err)
+	}
+	return nil
+}
+
+func (l StateLock) IsLocked() bool {
+	_, err := os.Stat(l.path())
+	return err == nil
+}
+
+func (l StateLock) holder() string {
+	contents, err := ioutil.ReadFile(l.path())
+	if err != nil || len(contents) == 0 {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(contents))
+}
+
+func (l StateLock) path() string {
+	return filepath.Join(l.dir, StateLockFileName)
+}
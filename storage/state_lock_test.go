@@ -0,0 +1,81 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateLock", func() {
+	var (
+		tempDir   string
+		stateLock storage.StateLock
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		stateLock = storage.NewStateLock(tempDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("Lock", func() {
+		It("writes a lock file containing the pid", func() {
+			err := stateLock.Lock()
+			Expect(err).NotTo(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(tempDir, "bbl-state.lock"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(strconv.Itoa(os.Getpid())))
+			Expect(stateLock.IsLocked()).To(BeTrue())
+		})
+
+		Context("when the state is already locked", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "bbl-state.lock"), []byte("1234"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error naming the holder", func() {
+				err := stateLock.Lock()
+				Expect(err).To(MatchError(ContainSubstring("Another bbl command (pid 1234) is modifying the state")))
+				Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Conflict))
+			})
+		})
+
+		Context("when the state dir does not exist", func() {
+			It("returns an error", func() {
+				stateLock = storage.NewStateLock(filepath.Join(tempDir, "missing"))
+
+				err := stateLock.Lock()
+				Expect(err).To(MatchError(ContainSubstring("Lock state:")))
+			})
+		})
+	})
+
+	Describe("Unlock", func() {
+		It("removes the lock file", func() {
+			Expect(stateLock.Lock()).To(Succeed())
+
+			err := stateLock.Unlock()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateLock.IsLocked()).To(BeFalse())
+		})
+
+		It("succeeds when the state is not locked", func() {
+			Expect(stateLock.Unlock()).To(Succeed())
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -21,26 +22,38 @@ const (
 
 	OS_READ_WRITE_MODE = os.FileMode(0644)
 	StateFileName      = "bbl-state.json"
+
+	// PreviousStateSuffix names the copy of the state file as it was before
+	// the last save, which bbl offers to recover when the state file is
+	// corrupt.
+	PreviousStateSuffix = ".previous"
 )
 
 type Store struct {
 	dir         string
 	fs          stateStoreFs
+	serializer  StateSerializer
+	cipher      *StateCipher
 	stateSchema int
 }
 
 type stateStoreFs interface {
+	fileio.FileReader
 	fileio.FileWriter
+	fileio.Opener
+	fileio.Renamer
 	fileio.Remover
 	fileio.AllRemover
 	fileio.Stater
 	fileio.AllMkdirer
 }
 
-func NewStore(dir string, fs stateStoreFs) Store {
+func NewStore(dir string, fs stateStoreFs, serializer StateSerializer, cipher *StateCipher) Store {
 	return Store{
 		dir:         dir,
 		fs:          fs,
+		serializer:  serializer,
+		cipher:      cipher,
 		stateSchema: STATE_SCHEMA,
 	}
 }
@@ -51,11 +64,14 @@ func (s Store) Set(state State) error {
 		return fmt.Errorf("Stat state dir: %s", err)
 	}
 
-	stateFile := filepath.Join(s.dir, StateFileName)
+	stateFile := filepath.Join(s.dir, s.serializer.FileName())
 	if reflect.DeepEqual(state, State{}) {
-		err := s.fs.Remove(stateFile)
-		if err != nil && !os.IsNotExist(err) {
-			return err
+		for _, name := range stateFileNames {
+			err := s.fs.Remove(filepath.Join(s.dir, name))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			_ = s.fs.Remove(filepath.Join(s.dir, name+PreviousStateSuffix))
 		}
 
 		rmdir := func(getDirFunc func() (string, error)) error {
@@ -80,6 +96,9 @@ func (s Store) Set(state State) error {
 		if err := rmdir(s.GetBblOpsFilesDir); err != nil {
 			return err
 		}
+		if err := rmdir(s.GetCacheDir); err != nil {
+			return err
+		}
 
 		_ = s.fs.Remove(filepath.Join(s.dir, "create-jumpbox.sh"))
 		_ = s.fs.Remove(filepath.Join(s.dir, "create-director.sh"))
@@ -99,18 +118,82 @@ func (s Store) Set(state State) error {
 		state.ID = uuid.String()
 	}
 
-	jsonData, err := marshalIndent(state, "", "\t")
+	state, err = s.cipher.Encrypt(state)
 	if err != nil {
 		return err
 	}
-	err = s.fs.WriteFile(stateFile, jsonData, os.FileMode(0644))
+
+	existing, _ := s.fs.ReadFile(stateFile)
+	data, err := s.serializer.Marshal(state, existing)
+	if err != nil {
+		return err
+	}
+
+	// Most calls save a state that has not changed since it was loaded, and
+	// large states are slow to rewrite.
+	if !bytes.Equal(data, existing) {
+		err = s.write(stateFile, data, existing)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Switching formats leaves the state file in the old format behind.
+	for _, name := range stateFileNames {
+		if name == s.serializer.FileName() {
+			continue
+		}
+		err = s.fs.Remove(filepath.Join(s.dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write replaces the state file in one step, so that a crash part way
+// through leaves either the old state or the new one, never a mix. The
+// previous state is kept beside it, as long as it could still be read.
+func (s Store) write(stateFile string, data, previous []byte) error {
+	if len(previous) > 0 && s.serializer.Unmarshal(previous, &State{}) == nil {
+		err := s.fs.WriteFile(stateFile+PreviousStateSuffix, previous, os.FileMode(0644))
+		if err != nil {
+			return fmt.Errorf("Keep previous state: %s", err)
+		}
+	}
+
+	tempFile := stateFile + ".tmp"
+	err := s.fs.WriteFile(tempFile, data, os.FileMode(0644))
 	if err != nil {
 		return err
 	}
 
+	err = s.sync(tempFile)
+	if err != nil {
+		_ = s.fs.Remove(tempFile)
+		return fmt.Errorf("Sync state file: %s", err)
+	}
+
+	err = s.fs.Rename(tempFile, stateFile)
+	if err != nil {
+		_ = s.fs.Remove(tempFile)
+		return fmt.Errorf("Replace state file: %s", err)
+	}
+
 	return nil
 }
 
+func (s Store) sync(name string) error {
+	file, err := s.fs.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
+
 func (s Store) GetStateDir() string {
 	return s.dir
 }
@@ -139,6 +222,12 @@ func (s Store) GetJumpboxDeploymentDir() (string, error) {
 	return s.getDir("jumpbox-deployment")
 }
 
+// GetCacheDir returns the directory that keeps the results of slow-changing
+// IaaS lookups between runs.
+func (s Store) GetCacheDir() (string, error) {
+	return s.getDir("cache")
+}
+
 func (s Store) GetOldBblDir() string {
 	return filepath.Join(s.dir, ".bbl")
 }
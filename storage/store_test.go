@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	uuid "github.com/nu7hatch/gouuid"
+	"github.com/spf13/afero"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -27,8 +28,10 @@ var _ = Describe("Store", func() {
 		tempDir, err = ioutil.TempDir("", "")
 
 		fileIO = &fakes.FileIO{}
+		fileIO.OpenCall.Returns.File, err = afero.NewMemMapFs().Create("bbl-state.json.tmp")
+		Expect(err).NotTo(HaveOccurred())
 
-		store = storage.NewStore(tempDir, fileIO)
+		store = storage.NewStore(tempDir, fileIO, storage.JSONStateSerializer{}, storage.NewStateCipher(""))
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -38,6 +41,29 @@ var _ = Describe("Store", func() {
 	})
 
 	Describe("Set", func() {
+		Context("when the state is encrypted", func() {
+			It("writes the sensitive fields encrypted", func() {
+				cipher := storage.NewStateCipher("some-passphrase")
+				store = storage.NewStore(tempDir, fileIO, storage.JSONStateSerializer{}, cipher)
+
+				state, err := cipher.Enable(storage.State{
+					EnvID: "some-env-id",
+					BOSH: storage.BOSH{
+						DirectorPassword: "some-director-password",
+					},
+				}, "", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = store.Set(state)
+				Expect(err).NotTo(HaveOccurred())
+
+				contents := string(fileIO.WriteFileCall.Receives[0].Contents)
+				Expect(contents).To(ContainSubstring("some-env-id"))
+				Expect(contents).To(ContainSubstring(`"directorPassword": "bbl-encrypted:`))
+				Expect(contents).NotTo(ContainSubstring("some-director-password"))
+			})
+		})
+
 		Context("when credhub is enabled", func() {
 			It("stores the state into a file, without IAAS credentials", func() {
 				storage.SetUUIDNewV4(func() (*uuid.UUID, error) {
@@ -128,7 +154,7 @@ var _ = Describe("Store", func() {
 				})
 				Expect(err).NotTo(HaveOccurred())
 
-				Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.json")))
+				Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.json.tmp")))
 				Expect(fileIO.WriteFileCall.Receives[0].Mode).To(Equal(os.FileMode(0644)))
 				Expect(fileIO.WriteFileCall.Receives[0].Contents).To(MatchJSON(`{
 				"version": 14,
@@ -185,7 +211,85 @@ var _ = Describe("Store", func() {
 			})
 		})
 
+		It("replaces the state file with the new state in one step", func() {
+			err := store.Set(storage.State{IAAS: "aws", ID: "some-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.json.tmp")))
+			Expect(fileIO.RenameCall.CallCount).To(Equal(1))
+			Expect(fileIO.RenameCall.Receives.Oldpath).To(Equal(filepath.Join(tempDir, "bbl-state.json.tmp")))
+			Expect(fileIO.RenameCall.Receives.Newpath).To(Equal(filepath.Join(tempDir, "bbl-state.json")))
+		})
+
+		It("syncs the new state to disk before replacing the state file", func() {
+			err := store.Set(storage.State{IAAS: "aws", ID: "some-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileIO.OpenCall.Receives.Name).To(Equal(filepath.Join(tempDir, "bbl-state.json.tmp")))
+		})
+
+		Context("when there is a state file", func() {
+			It("keeps a copy of it", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte(`{"version": 14, "iaas": "gcp"}`)
+
+				err := store.Set(storage.State{IAAS: "aws", ID: "some-id"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.json.previous")))
+				Expect(string(fileIO.WriteFileCall.Receives[0].Contents)).To(Equal(`{"version": 14, "iaas": "gcp"}`))
+				Expect(fileIO.WriteFileCall.Receives[1].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.json.tmp")))
+			})
+
+			It("does not keep a copy that cannot be read", func() {
+				fileIO.ReadFileCall.Returns.Contents = []byte(`{"version": 14, "ia`)
+
+				err := store.Set(storage.State{IAAS: "aws", ID: "some-id"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fileIO.WriteFileCall.Receives).To(HaveLen(1))
+				Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.json.tmp")))
+			})
+		})
+
+		Context("when the state has not changed", func() {
+			It("does not rewrite the state file", func() {
+				state := storage.State{IAAS: "aws", ID: "some-id", Version: 14}
+				contents, err := storage.JSONStateSerializer{}.Marshal(state, nil)
+				Expect(err).NotTo(HaveOccurred())
+				fileIO.ReadFileCall.Returns.Contents = contents
+
+				err = store.Set(state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fileIO.WriteFileCall.CallCount).To(Equal(0))
+				Expect(fileIO.RenameCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the state format is yaml", func() {
+			BeforeEach(func() {
+				store = storage.NewStore(tempDir, fileIO, storage.YAMLStateSerializer{}, storage.NewStateCipher(""))
+			})
+
+			It("writes bbl-state.yml and removes bbl-state.json", func() {
+				err := store.Set(storage.State{IAAS: "aws", ID: "some-id"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fileIO.WriteFileCall.Receives[0].Filename).To(Equal(filepath.Join(tempDir, "bbl-state.yml.tmp")))
+				Expect(fileIO.RenameCall.Receives.Newpath).To(Equal(filepath.Join(tempDir, "bbl-state.yml")))
+				Expect(string(fileIO.WriteFileCall.Receives[0].Contents)).To(ContainSubstring("iaas: aws\n"))
+				Expect(fileIO.RemoveCall.Receives).To(ContainElement(fakes.RemoveReceive{Name: filepath.Join(tempDir, "bbl-state.json")}))
+			})
+		})
+
 		Context("when the state is empty", func() {
+			It("removes the bbl-state.yml file", func() {
+				err := store.Set(storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fileIO.RemoveCall.Receives).To(ContainElement(fakes.RemoveReceive{Name: filepath.Join(tempDir, "bbl-state.yml")}))
+			})
+
 			It("removes the bbl-state.json file", func() {
 				err := store.Set(storage.State{})
 				Expect(err).NotTo(HaveOccurred())
@@ -229,6 +333,7 @@ var _ = Describe("Store", func() {
 				Entry("jumpbox-deployment", "jumpbox-deployment", true),
 				Entry("vars", "vars", true),
 				Entry("bbl-ops-files", "bbl-ops-files", true),
+				Entry("cache", "cache", true),
 				Entry("non-bbl directory", "foo", false),
 			)
 
@@ -289,12 +394,37 @@ var _ = Describe("Store", func() {
 				})
 
 				It("returns an error", func() {
-					store = storage.NewStore("non-valid-dir", fileIO)
+					store = storage.NewStore("non-valid-dir", fileIO, storage.JSONStateSerializer{}, storage.NewStateCipher(""))
 					err := store.Set(storage.State{})
 					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
 				})
 			})
 
+			Context("when the new state cannot be synced to disk", func() {
+				BeforeEach(func() {
+					fileIO.OpenCall.Returns.Error = errors.New("no such file")
+				})
+
+				It("removes the temporary file and returns an error", func() {
+					err := store.Set(storage.State{EnvID: "something"})
+					Expect(err).To(MatchError("Sync state file: no such file"))
+					Expect(fileIO.RenameCall.CallCount).To(Equal(0))
+					Expect(fileIO.RemoveCall.Receives).To(ContainElement(fakes.RemoveReceive{Name: filepath.Join(tempDir, "bbl-state.json.tmp")}))
+				})
+			})
+
+			Context("when the state file cannot be replaced", func() {
+				BeforeEach(func() {
+					fileIO.RenameCall.Returns.Error = errors.New("device busy")
+				})
+
+				It("removes the temporary file and returns an error", func() {
+					err := store.Set(storage.State{EnvID: "something"})
+					Expect(err).To(MatchError("Replace state file: device busy"))
+					Expect(fileIO.RemoveCall.Receives).To(ContainElement(fakes.RemoveReceive{Name: filepath.Join(tempDir, "bbl-state.json.tmp")}))
+				})
+			})
+
 			Context("when it fails to open the bbl-state.json file", func() {
 				BeforeEach(func() {
 					fileIO.WriteFileCall.Returns = []fakes.WriteFileReturn{{Error: errors.New("permission denied")}}
@@ -326,6 +456,7 @@ var _ = Describe("Store", func() {
 		Entry("terraform", "terraform", func() (string, error) { return store.GetTerraformDir() }),
 		Entry("bosh-deployment", "bosh-deployment", func() (string, error) { return store.GetDirectorDeploymentDir() }),
 		Entry("jumpbox-deployment", "jumpbox-deployment", func() (string, error) { return store.GetJumpboxDeploymentDir() }),
+		Entry("cache", "cache", func() (string, error) { return store.GetCacheDir() }),
 	)
 
 	DescribeTable("get dirs returns an error when the subdirectory cannot be created",
@@ -342,6 +473,7 @@ var _ = Describe("Store", func() {
 		Entry("terraform", "terraform", func() (string, error) { return store.GetTerraformDir() }),
 		Entry("bosh-deployment", "bosh-deployment", func() (string, error) { return store.GetDirectorDeploymentDir() }),
 		Entry("jumpbox-deployment", "jumpbox-deployment", func() (string, error) { return store.GetJumpboxDeploymentDir() }),
+		Entry("cache", "cache", func() (string, error) { return store.GetCacheDir() }),
 	)
 
 	Describe("GetCloudConfigDir", func() {
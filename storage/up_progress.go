@@ -0,0 +1,37 @@
+package storage
+
+const (
+	UpStepInfrastructure = "infrastructure"
+	UpStepJumpbox        = "jumpbox"
+)
+
+// UpProgress records the steps of `bbl up` that completed, so that an
+// interrupted up resumes after them. It is cleared once the director is up.
+type UpProgress struct {
+	Completed []string `json:"completed"`
+}
+
+// Done reports whether the step completed. No progress has no steps.
+func (p *UpProgress) Done(step string) bool {
+	if p == nil {
+		return false
+	}
+	for _, completed := range p.Completed {
+		if completed == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete returns the progress with the step completed as well.
+func (p *UpProgress) Complete(step string) *UpProgress {
+	progress := &UpProgress{}
+	if p != nil {
+		progress.Completed = append(progress.Completed, p.Completed...)
+	}
+	if !progress.Done(step) {
+		progress.Completed = append(progress.Completed, step)
+	}
+	return progress
+}
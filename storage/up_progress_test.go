@@ -0,0 +1,24 @@
+package storage_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpProgress", func() {
+	It("has no steps done without progress", func() {
+		var progress *storage.UpProgress
+		Expect(progress.Done(storage.UpStepInfrastructure)).To(BeFalse())
+	})
+
+	It("completes steps once without changing the progress it was given", func() {
+		progress := (*storage.UpProgress)(nil).Complete(storage.UpStepInfrastructure)
+		next := progress.Complete(storage.UpStepJumpbox).Complete(storage.UpStepJumpbox)
+
+		Expect(progress.Completed).To(Equal([]string{"infrastructure"}))
+		Expect(next.Completed).To(Equal([]string{"infrastructure", "jumpbox"}))
+		Expect(next.Done(storage.UpStepJumpbox)).To(BeTrue())
+	})
+})
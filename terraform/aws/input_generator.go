@@ -14,6 +14,13 @@ type InputGenerator struct {
 
 const terraformNameCharLimit = 18
 
+// defaultVPCCIDR is the default of the vpc_cidr variable of the templates.
+const defaultVPCCIDR = "10.0.0.0/16"
+
+// cheapNATInstanceType is the NAT instance of cheap environments, whose
+// deployments send little traffic to the internet.
+const cheapNATInstanceType = "t2.micro"
+
 func NewInputGenerator(availabilityZoneRetriever aws.AvailabilityZoneRetriever) InputGenerator {
 	return InputGenerator{
 		availabilityZoneRetriever: availabilityZoneRetriever,
@@ -21,7 +28,7 @@ func NewInputGenerator(availabilityZoneRetriever aws.AvailabilityZoneRetriever)
 }
 
 func (i InputGenerator) Generate(state storage.State) (map[string]interface{}, error) {
-	azs, err := i.availabilityZoneRetriever.RetrieveAvailabilityZones(state.AWS.Region)
+	azs, err := aws.AvailabilityZones(i.availabilityZoneRetriever, state.AWS)
 	if err != nil {
 		return map[string]interface{}{}, err
 	}
@@ -39,13 +46,156 @@ func (i InputGenerator) Generate(state storage.State) (map[string]interface{}, e
 		"availability_zones": azs,
 	}
 
+	if state.AWS.MaxRetries != 0 {
+		inputs["max_retries"] = state.AWS.MaxRetries
+	}
+
+	if state.AWS.Endpoint != "" {
+		inputs["endpoint"] = state.AWS.Endpoint
+	}
+
+	if len(state.AWS.Endpoints) > 0 {
+		inputs["endpoints"] = state.AWS.Endpoints
+	}
+
+	if partition := aws.PartitionOf(state.AWS.Region); partition.ID != "aws" {
+		inputs["partition"] = partition.ID
+		inputs["dns_suffix"] = partition.DNSSuffix
+	}
+
+	if state.AWS.Minimal {
+		inputs["minimal"] = true
+	}
+
+	if state.AWS.Cheap {
+		inputs["nat_instance_type"] = cheapNATInstanceType
+	}
+
+	if state.AWS.VPCCIDR != "" {
+		inputs["vpc_cidr"] = state.AWS.VPCCIDR
+	}
+
+	if state.AWS.ExistingVPCID != "" {
+		inputs["existing_vpc_id"] = state.AWS.ExistingVPCID
+	}
+
+	if len(state.AWS.SubnetSizes) > 0 || len(state.AWS.ReservedCIDRs) > 0 {
+		vpcCIDR := state.AWS.VPCCIDR
+		if vpcCIDR == "" {
+			vpcCIDR = defaultVPCCIDR
+		}
+		cidrs, err := InternalSubnetCIDRs(vpcCIDR, azs, state.AWS.SubnetSizes, state.AWS.ReservedCIDRs)
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		inputs["internal_subnet_cidrs"] = cidrs
+	}
+
+	if state.AWS.S3Blobstore && state.AWS.S3BlobstoreBucket != "" {
+		inputs["s3_blobstore_bucket"] = state.AWS.S3BlobstoreBucket
+	}
+
+	if state.DirectorPorts != nil && state.DirectorPorts.Mbus != 0 {
+		inputs["director_mbus_port"] = state.DirectorPorts.Mbus
+	}
+
+	if len(state.Annotations) > 0 {
+		inputs["tags"] = state.Annotations
+	}
+
+	if len(state.AWS.DirectorAllowedCIDRs) > 0 {
+		inputs["bosh_inbound_cidr"] = ""
+		inputs["bosh_inbound_cidrs"] = state.AWS.DirectorAllowedCIDRs
+	}
+
+	// Internal load balancers only accept connections from inside the VPC,
+	// unless other blocks are allowed.
+	if len(state.AWS.LBAllowedCIDRs) > 0 {
+		inputs["lb_inbound_cidrs"] = state.AWS.LBAllowedCIDRs
+	} else if state.LB.Internal {
+		vpcCIDR := state.AWS.VPCCIDR
+		if vpcCIDR == "" {
+			vpcCIDR = defaultVPCCIDR
+		}
+		inputs["lb_inbound_cidrs"] = []string{vpcCIDR}
+	}
+
+	// The allowed blocks are all IPv4, so a dual-stack router lets no IPv6
+	// clients in when only those are allowed.
+	if state.AWS.DualStack && (len(state.AWS.LBAllowedCIDRs) > 0 || state.LB.Internal) {
+		inputs["lb_inbound_ipv6_cidrs"] = []string{}
+	}
+
 	if state.LB.Type == "cf" {
-		inputs["ssl_certificate"] = state.LB.Cert
-		inputs["ssl_certificate_private_key"] = state.LB.Key
-		inputs["ssl_certificate_chain"] = state.LB.Chain
+		switch {
+		case state.LB.ACMCertificate:
+		case state.LB.CertARN != "":
+			inputs["ssl_certificate_arn"] = state.LB.CertARN
+		default:
+			inputs["ssl_certificate"] = state.LB.Cert
+			inputs["ssl_certificate_private_key"] = state.LB.Key
+			inputs["ssl_certificate_chain"] = state.LB.Chain
+		}
+
+		for lb, variable := range storage.CertificateLBs {
+			if certificate := state.AWS.AttachedCertificate(lb); certificate != nil {
+				inputs[variable] = certificate.ARN
+			}
+		}
+
+		if len(state.AWS.SNIDomains) > 0 {
+			domains := []string{}
+			certificateARNs := []string{}
+			for _, sni := range state.AWS.SNIDomains {
+				domains = append(domains, sni.Domain)
+				if certificate := state.AWS.CertificateNamed(sni.Certificate); certificate != nil {
+					certificateARNs = append(certificateARNs, certificate.ARN)
+				} else {
+					certificateARNs = append(certificateARNs, "")
+				}
+			}
+			inputs["sni_domains"] = domains
+			inputs["sni_certificate_arns"] = certificateARNs
+		}
+
+		// The target groups of ELBv2 take the parts of the target, and only
+		// check HTTP or HTTPS.
+		healthCheck := state.LB.LBHealthCheck
+		if healthCheck.HealthCheckTarget != "" && state.LB.ELBv2 {
+			inputs["router_health_check_protocol"] = healthCheck.HealthCheckProtocol()
+			inputs["router_health_check_port"] = healthCheck.HealthCheckPort()
+			inputs["router_health_check_path"] = healthCheck.HealthCheckPath()
+		} else if healthCheck.HealthCheckTarget != "" {
+			inputs["router_health_check_target"] = healthCheck.HealthCheckTarget
+			inputs["router_health_check_port"] = healthCheck.HealthCheckPort()
+		}
+		if healthCheck.HealthCheckInterval != 0 {
+			inputs["router_health_check_interval"] = healthCheck.HealthCheckInterval
+		}
+		if healthCheck.HealthCheckTimeout != 0 {
+			inputs["router_health_check_timeout"] = healthCheck.HealthCheckTimeout
+		}
+		if healthCheck.HealthyThreshold != 0 {
+			inputs["router_healthy_threshold"] = healthCheck.HealthyThreshold
+		}
+		if healthCheck.UnhealthyThreshold != 0 {
+			inputs["router_unhealthy_threshold"] = healthCheck.UnhealthyThreshold
+		}
 
 		if state.LB.Domain != "" {
 			inputs["system_domain"] = state.LB.Domain
+
+			if state.LB.DNSRoleARN != "" {
+				inputs["dns_role_arn"] = state.LB.DNSRoleARN
+			}
+
+			if state.LB.DNSZoneID != "" {
+				inputs["dns_zone_id"] = state.LB.DNSZoneID
+			}
+
+			if state.LB.AppsDomain != "" {
+				inputs["apps_domain"] = state.LB.AppsDomain
+			}
 		}
 	}
 
@@ -53,8 +203,12 @@ func (i InputGenerator) Generate(state storage.State) (map[string]interface{}, e
 }
 
 func (i InputGenerator) Credentials(state storage.State) map[string]string {
-	return map[string]string{
+	credentials := map[string]string{
 		"access_key": state.AWS.AccessKeyID,
 		"secret_key": state.AWS.SecretAccessKey,
 	}
+	if state.AWS.SessionToken != "" {
+		credentials["session_token"] = state.AWS.SessionToken
+	}
+	return credentials
 }
@@ -67,6 +67,240 @@ var _ = Describe("InputGenerator", func() {
 			}))
 		})
 
+		Context("when availability zones are pinned", func() {
+			It("uses only those availability zones", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region: "some-region",
+						AZs:    []string{"z3", "z1"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["availability_zones"]).To(Equal([]string{"z1", "z3"}))
+			})
+		})
+
+		Context("when max retries are configured", func() {
+			It("passes them to the aws provider", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:     "some-region",
+						MaxRetries: 20,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["max_retries"]).To(Equal(20))
+			})
+		})
+
+		Context("when an endpoint is given", func() {
+			It("sends the requests of the aws provider to it", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:   "some-region",
+						Endpoint: "https://aws.example.com",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["endpoint"]).To(Equal("https://aws.example.com"))
+			})
+		})
+
+		Context("when endpoints of single services are given", func() {
+			It("sends the requests of those services to them", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:    "some-region",
+						Endpoints: map[string]string{"iam": "https://iam.example.com"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["endpoints"]).To(Equal(map[string]string{"iam": "https://iam.example.com"}))
+			})
+		})
+
+		Context("when the region is in another partition", func() {
+			It("gives the partition of the ARNs and service principals", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS:   storage.AWS{Region: "cn-north-1"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["partition"]).To(Equal("aws-cn"))
+				Expect(inputs["dns_suffix"]).To(Equal("amazonaws.com.cn"))
+			})
+
+			It("leaves the defaults for the commercial partition", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS:   storage.AWS{Region: "some-region"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs).NotTo(HaveKey("partition"))
+				Expect(inputs).NotTo(HaveKey("dns_suffix"))
+			})
+		})
+
+		Context("when the environment is minimal", func() {
+			It("gives the internal subnets public IPs", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:  "some-region",
+						Minimal: true,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["minimal"]).To(BeTrue())
+			})
+		})
+
+		Context("when the environment is cheap", func() {
+			It("gives it a small NAT instance in a single availability zone", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region: "some-region",
+						Cheap:  true,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["nat_instance_type"]).To(Equal("t2.micro"))
+				Expect(inputs["availability_zones"]).To(HaveLen(1))
+			})
+		})
+
+		Context("when the environment has a vpc cidr", func() {
+			It("passes it to the vpc and subnets", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:  "some-region",
+						VPCCIDR: "192.168.0.0/16",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["vpc_cidr"]).To(Equal("192.168.0.0/16"))
+			})
+		})
+
+		Context("when the environment uses an existing vpc", func() {
+			It("passes the vpc id", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:        "some-region",
+						ExistingVPCID: "vpc-0a1b2c3d",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["existing_vpc_id"]).To(Equal("vpc-0a1b2c3d"))
+			})
+		})
+
+		Context("when the environment has subnet sizes or reserved blocks", func() {
+			It("passes the blocks of the internal subnets", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:        "some-region",
+						VPCCIDR:       "192.168.0.0/16",
+						SubnetSizes:   map[string]int{"z2": 22},
+						ReservedCIDRs: []string{"192.168.16.0/20"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["internal_subnet_cidrs"]).To(Equal([]string{"192.168.32.0/20", "192.168.48.0/22", "192.168.64.0/20"}))
+			})
+
+			It("returns an error when the subnets do not fit", func() {
+				_, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region:      "some-region",
+						SubnetSizes: map[string]int{"z4": 22},
+					},
+				})
+				Expect(err).To(MatchError("There is a subnet size for z4, which is not one of the availability zones z1, z2, z3."))
+			})
+		})
+
+		Context("when the director mbus port is overridden", func() {
+			It("opens the port from the jumpbox", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID:         "some-env-id",
+					AWS:           storage.AWS{Region: "some-region"},
+					DirectorPorts: &storage.DirectorPorts{Mbus: 6869},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["director_mbus_port"]).To(Equal(6869))
+			})
+		})
+
+		Context("when blocks are allowed to reach the director", func() {
+			It("allows only them to reach the jumpbox and director", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS:   storage.AWS{Region: "some-region", DirectorAllowedCIDRs: []string{"198.51.100.0/24", "192.0.2.0/24"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs).To(HaveKeyWithValue("bosh_inbound_cidr", ""))
+				Expect(inputs).To(HaveKeyWithValue("bosh_inbound_cidrs", []string{"198.51.100.0/24", "192.0.2.0/24"}))
+			})
+		})
+
+		Context("when the director stores its blobs in s3", func() {
+			It("passes on the bucket of the user", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS:   storage.AWS{Region: "some-region", S3Blobstore: true, S3BlobstoreBucket: "some-bucket"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["s3_blobstore_bucket"]).To(Equal("some-bucket"))
+			})
+
+			It("lets terraform create the bucket otherwise", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID: "some-env-id",
+					AWS:   storage.AWS{Region: "some-region", S3Blobstore: true},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs).NotTo(HaveKey("s3_blobstore_bucket"))
+			})
+		})
+
+		Context("when the environment is annotated", func() {
+			It("tags the resources with the annotations", func() {
+				inputs, err := inputGenerator.Generate(storage.State{
+					EnvID:       "some-env-id",
+					AWS:         storage.AWS{Region: "some-region"},
+					Annotations: map[string]string{"owner": "platform-team"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["tags"]).To(Equal(map[string]string{"owner": "platform-team"}))
+			})
+		})
+
 		Context("when a cf lb exists", func() {
 			var state storage.State
 
@@ -105,6 +339,49 @@ var _ = Describe("InputGenerator", func() {
 				}))
 			})
 
+			Context("when the load balancers are internal", func() {
+				It("only lets the vpc reach them", func() {
+					state.LB.Internal = true
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("lb_inbound_cidrs", []string{"10.0.0.0/16"}))
+
+					state.AWS.VPCCIDR = "10.1.0.0/16"
+					inputs, err = inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("lb_inbound_cidrs", []string{"10.1.0.0/16"}))
+				})
+
+				It("lets the allowed blocks reach them instead", func() {
+					state.LB.Internal = true
+					state.AWS.LBAllowedCIDRs = []string{"192.168.0.0/16"}
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("lb_inbound_cidrs", []string{"192.168.0.0/16"}))
+				})
+
+				It("lets no IPv6 clients reach a dual-stack router", func() {
+					state.LB.Internal = true
+					state.AWS.DualStack = true
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("lb_inbound_ipv6_cidrs", []string{}))
+				})
+			})
+
+			Context("when the environment is dual-stack", func() {
+				It("lets any IPv6 client reach the router", func() {
+					state.AWS.DualStack = true
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).NotTo(HaveKey("lb_inbound_ipv6_cidrs"))
+				})
+			})
+
 			Context("when a domain name is supplied", func() {
 				BeforeEach(func() {
 					state.LB.Domain = "some-domain"
@@ -127,6 +404,130 @@ var _ = Describe("InputGenerator", func() {
 						"system_domain":               "some-domain",
 					}))
 				})
+
+				Context("when a dns role arn is supplied", func() {
+					BeforeEach(func() {
+						state.LB.DNSRoleARN = "some-role-arn"
+					})
+
+					It("passes the role to the dns provider", func() {
+						inputs, err := inputGenerator.Generate(state)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(inputs).To(HaveKeyWithValue("dns_role_arn", "some-role-arn"))
+						Expect(inputs).To(HaveKeyWithValue("system_domain", "some-domain"))
+					})
+				})
+
+				Context("when a hosted zone and an apps domain are supplied", func() {
+					It("passes them", func() {
+						state.LB.DNSZoneID = "Z123"
+						state.LB.AppsDomain = "apps.some-domain"
+
+						inputs, err := inputGenerator.Generate(state)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(inputs).To(HaveKeyWithValue("dns_zone_id", "Z123"))
+						Expect(inputs).To(HaveKeyWithValue("apps_domain", "apps.some-domain"))
+					})
+				})
+			})
+
+			Context("when an ACM certificate arn is supplied", func() {
+				BeforeEach(func() {
+					state.LB = storage.LB{
+						Type:    "cf",
+						CertARN: "some-cert-arn",
+					}
+				})
+
+				It("passes the arns of the certificates attached to the load balancers", func() {
+					state.AWS.Certificates = []storage.ServerCertificate{
+						{Name: "foo", ARN: "foo-arn", LBs: []string{"cf-router"}},
+						{Name: "bar", ARN: "bar-arn"},
+					}
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("cf_router_certificate_arn", "foo-arn"))
+					Expect(inputs).NotTo(HaveKey("cf_iso_router_certificate_arn"))
+				})
+
+				It("passes the SNI domains with the arns of their certificates", func() {
+					state.AWS.Certificates = []storage.ServerCertificate{
+						{Name: "foo", ARN: "foo-arn"},
+						{Name: "bar", ARN: "bar-arn"},
+					}
+					state.AWS.SNIDomains = []storage.SNIDomain{
+						{Domain: "bar.example.com", Certificate: "bar"},
+						{Domain: "foo.example.com", Certificate: "foo"},
+					}
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("sni_domains", []string{"bar.example.com", "foo.example.com"}))
+					Expect(inputs).To(HaveKeyWithValue("sni_certificate_arns", []string{"bar-arn", "foo-arn"}))
+				})
+
+				It("passes the settings of the router health check that are set", func() {
+					state.LB.LBHealthCheck = storage.LBHealthCheck{
+						HealthCheckTarget:  "HTTP:8080/health",
+						HealthyThreshold:   2,
+						HealthCheckTimeout: 5,
+					}
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("router_health_check_target", "HTTP:8080/health"))
+					Expect(inputs).To(HaveKeyWithValue("router_health_check_port", 8080))
+					Expect(inputs).To(HaveKeyWithValue("router_healthy_threshold", 2))
+					Expect(inputs).To(HaveKeyWithValue("router_health_check_timeout", 5))
+					Expect(inputs).NotTo(HaveKey("router_health_check_interval"))
+					Expect(inputs).NotTo(HaveKey("router_unhealthy_threshold"))
+				})
+
+				It("passes the parts of the target for the target group of an ELBv2 router", func() {
+					state.LB.ELBv2 = true
+					state.LB.LBHealthCheck = storage.LBHealthCheck{HealthCheckTarget: "HTTPS:8443/health"}
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("router_health_check_protocol", "HTTPS"))
+					Expect(inputs).To(HaveKeyWithValue("router_health_check_port", 8443))
+					Expect(inputs).To(HaveKeyWithValue("router_health_check_path", "/health"))
+					Expect(inputs).NotTo(HaveKey("router_health_check_target"))
+				})
+
+				It("passes the arn instead of the certificate", func() {
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(inputs).To(Equal(map[string]interface{}{
+						"env_id":              "some-env-id",
+						"short_env_id":        "some-env-id",
+						"region":              "some-region",
+						"availability_zones":  []string{"z1", "z2", "z3"},
+						"ssl_certificate_arn": "some-cert-arn",
+					}))
+				})
+			})
+
+			Context("when an ACM certificate is requested", func() {
+				It("passes no certificate", func() {
+					state.LB = storage.LB{
+						Type:           "cf",
+						Domain:         "some-domain",
+						ACMCertificate: true,
+					}
+
+					inputs, err := inputGenerator.Generate(state)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inputs).To(HaveKeyWithValue("system_domain", "some-domain"))
+					Expect(inputs).NotTo(HaveKey("ssl_certificate"))
+					Expect(inputs).NotTo(HaveKey("ssl_certificate_private_key"))
+					Expect(inputs).NotTo(HaveKey("ssl_certificate_chain"))
+					Expect(inputs).NotTo(HaveKey("ssl_certificate_arn"))
+				})
 			})
 		})
 
@@ -159,5 +560,25 @@ var _ = Describe("InputGenerator", func() {
 				"secret_key": "some-secret-access-key",
 			}))
 		})
+
+		Context("when the credentials are temporary", func() {
+			It("returns the session token", func() {
+				state := storage.State{
+					AWS: storage.AWS{
+						AccessKeyID:     "some-access-key-id",
+						SecretAccessKey: "some-secret-access-key",
+						SessionToken:    "some-session-token",
+					},
+				}
+
+				credentials := inputGenerator.Credentials(state)
+
+				Expect(credentials).To(Equal(map[string]string{
+					"access_key":    "some-access-key-id",
+					"secret_key":    "some-secret-access-key",
+					"session_token": "some-session-token",
+				}))
+			})
+		})
 	})
 })
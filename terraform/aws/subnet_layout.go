@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// maxSubnetPrefix is the smallest subnet that aws allows.
+const maxSubnetPrefix = 28
+
+type block struct {
+	first, last uint32
+}
+
+func newBlock(network *net.IPNet) block {
+	ones, bits := network.Mask.Size()
+	first := binary.BigEndian.Uint32(network.IP.To4())
+	return block{first: first, last: first + uint32(1)<<uint(bits-ones) - 1}
+}
+
+func (b block) overlaps(other block) bool {
+	return b.first <= other.last && other.first <= b.last
+}
+
+func (b block) String() string {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, b.first)
+	ones := 32
+	for size := b.last - b.first + 1; size > 1; size >>= 1 {
+		ones--
+	}
+	return fmt.Sprintf("%s/%d", ip, ones)
+}
+
+// InternalSubnetCIDRs carves the internal subnet of each availability zone
+// out of the VPC, in order, at the first free block of its size. A zone
+// without a size gets the /20 of a /16 VPC that the templates give it by
+// default. The first of those blocks holds the bosh and load balancer
+// subnets, and no subnet is carved out of the reserved blocks.
+func InternalSubnetCIDRs(vpcCIDR string, azs []string, sizes map[string]int, reserved []string) ([]string, error) {
+	_, vpc, err := net.ParseCIDR(vpcCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("Parse VPC CIDR: %s", err)
+	}
+	vpcPrefix, _ := vpc.Mask.Size()
+	vpcBlock := newBlock(vpc)
+	defaultPrefix := vpcPrefix + 4
+
+	sized := []string{}
+	for az := range sizes {
+		sized = append(sized, az)
+	}
+	sort.Strings(sized)
+	for _, az := range sized {
+		if !containsString(azs, az) {
+			return nil, fmt.Errorf("There is a subnet size for %s, which is not one of the availability zones %s.", az, strings.Join(azs, ", "))
+		}
+	}
+
+	boshBlock := block{first: vpcBlock.first, last: vpcBlock.first + uint32(1)<<uint(32-defaultPrefix) - 1}
+	taken := []block{boshBlock}
+	for _, cidr := range reserved {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Parse reserved CIDR: %s", err)
+		}
+		reservedBlock := newBlock(network)
+		if reservedBlock.first < vpcBlock.first || reservedBlock.last > vpcBlock.last {
+			return nil, fmt.Errorf("Reserved CIDR %s is not inside the VPC %s.", cidr, vpc)
+		}
+		if reservedBlock.overlaps(boshBlock) {
+			return nil, fmt.Errorf("Reserved CIDR %s overlaps %s, which holds the bosh and load balancer subnets.", cidr, boshBlock)
+		}
+		taken = append(taken, reservedBlock)
+	}
+
+	cidrs := []string{}
+	for _, az := range azs {
+		prefix, ok := sizes[az]
+		if !ok {
+			prefix = defaultPrefix
+		}
+		if prefix <= vpcPrefix || prefix > maxSubnetPrefix {
+			return nil, fmt.Errorf("The subnet of %s must be between /%d and /%d.", az, vpcPrefix+1, maxSubnetPrefix)
+		}
+
+		subnet, ok := firstFreeBlock(vpcBlock, prefix, taken)
+		if !ok {
+			return nil, fmt.Errorf("There is no room left in the VPC %s for a /%d subnet in %s.", vpc, prefix, az)
+		}
+		taken = append(taken, subnet)
+		cidrs = append(cidrs, subnet.String())
+	}
+
+	return cidrs, nil
+}
+
+func firstFreeBlock(vpc block, prefix int, taken []block) (block, bool) {
+	size := uint32(1) << uint(32-prefix)
+	for first := vpc.first; first+size-1 <= vpc.last && first >= vpc.first; first += size {
+		candidate := block{first: first, last: first + size - 1}
+		free := true
+		for _, t := range taken {
+			if candidate.overlaps(t) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate, true
+		}
+	}
+	return block{}, false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package aws_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/terraform/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InternalSubnetCIDRs", func() {
+	It("carves the same blocks as the templates without sizes or reserved blocks", func() {
+		cidrs, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1", "z2", "z3"}, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(Equal([]string{"10.0.16.0/20", "10.0.32.0/20", "10.0.48.0/20"}))
+	})
+
+	It("gives each availability zone its own size", func() {
+		cidrs, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1", "z2", "z3"}, map[string]int{"z1": 18, "z3": 24}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(Equal([]string{"10.0.64.0/18", "10.0.16.0/20", "10.0.32.0/24"}))
+	})
+
+	It("skips the reserved blocks", func() {
+		cidrs, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1", "z2"}, nil, []string{"10.0.16.0/20", "10.0.40.0/21"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(Equal([]string{"10.0.48.0/20", "10.0.64.0/20"}))
+	})
+
+	Describe("failure cases", func() {
+		It("returns an error for a size of an unknown availability zone", func() {
+			_, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1", "z2"}, map[string]int{"z9": 20}, nil)
+			Expect(err).To(MatchError("There is a subnet size for z9, which is not one of the availability zones z1, z2."))
+		})
+
+		It("returns an error for a size that does not fit the VPC", func() {
+			_, err := aws.InternalSubnetCIDRs("10.0.0.0/20", []string{"z1"}, map[string]int{"z1": 18}, nil)
+			Expect(err).To(MatchError("The subnet of z1 must be between /21 and /28."))
+		})
+
+		It("returns an error for a reserved block outside of the VPC", func() {
+			_, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1"}, nil, []string{"10.1.0.0/20"})
+			Expect(err).To(MatchError("Reserved CIDR 10.1.0.0/20 is not inside the VPC 10.0.0.0/16."))
+		})
+
+		It("returns an error for a reserved block over the bosh and load balancer subnets", func() {
+			_, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1"}, nil, []string{"10.0.8.0/21"})
+			Expect(err).To(MatchError("Reserved CIDR 10.0.8.0/21 overlaps 10.0.0.0/20, which holds the bosh and load balancer subnets."))
+		})
+
+		It("returns an error when the VPC has no room left", func() {
+			_, err := aws.InternalSubnetCIDRs("10.0.0.0/16", []string{"z1", "z2"}, map[string]int{"z2": 17}, []string{"10.0.128.0/17"})
+			Expect(err).To(MatchError("There is no room left in the VPC 10.0.0.0/16 for a /17 subnet in z2."))
+		})
+	})
+})
@@ -1,23 +1,95 @@
 package aws
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
+// iamCertificateARN is how the cf load balancer listeners refer to the
+// certificate bbl uploads to IAM. It is swapped for the ACM certificate ARN
+// variable when the user brings their own certificate.
+const iamCertificateARN = "${aws_iam_server_certificate.lb_cert.arn}"
+
+// route53Resource matches the opening line of each resource and data source
+// in the cf dns templates, so those can be moved onto the provider that
+// assumes the dns role.
+var route53Resource = regexp.MustCompile(`(?m)^((resource|data) "aws_route53_\w+" "\w+" \{\n)`)
+
+// lbSubnets matches the subnets of each load balancer, so that internal load
+// balancers can be moved onto the internal subnets.
+var lbSubnets = regexp.MustCompile(`(?m)^( +)subnets +=\s*\["\$\{aws_subnet\.lb_subnets\.\*\.id\}"\]$`)
+
+// internalRouteTable is the route table that the internal and isolation
+// segment subnets are associated with. With NAT gateways each subnet is
+// associated with the route table of the NAT gateway in its availability
+// zone instead, through its route, so that the subnets only move once the
+// route is there.
+const (
+	internalRouteTable   = `route_table_id = "${aws_route_table.internal_route_table.id}"`
+	natGatewayRouteTable = `route_table_id = "${element(aws_route.nat_gateway_routes.*.route_table_id, count.index)}"`
+)
+
+// natToIsolatedCellsRule matches the rule of the isolation segments on the
+// security group of the NAT instance, which is left out when there is none.
+var natToIsolatedCellsRule = regexp.MustCompile(`(?s)resource "aws_security_group_rule" "nat_to_isolated_cells_rule" \{.*?\n\}\n`)
+
+// elbv2Alias matches the references of the dns records to the router and
+// ssh proxy load balancers, which are aws_lb resources with ELBv2.
+var elbv2Alias = regexp.MustCompile(`\$\{aws_elb\.(cf_router_lb|cf_ssh_lb)\.`)
+
+// subnet matches the opening line of each subnet, which gets an IPv6 block of
+// the VPC in a dual-stack environment.
+var subnet = regexp.MustCompile(`(?m)^resource "aws_subnet" "(\w+)" \{\n`)
+
+// ipv6Netnums are the netnums of the IPv6 blocks of each subnet within the
+// IPv6 block of the VPC, in a range per kind of subnet so that each has room
+// for an availability zone per block.
+var ipv6Netnums = map[string]string{
+	"bosh_subnet":      "0",
+	"internal_subnets": "count.index+16",
+	"lb_subnets":       "count.index+32",
+	"nat_subnets":      "count.index+48",
+	"iso_subnets":      "count.index+64",
+}
+
+// lbInboundCIDRs are the IPv4 blocks allowed in by the security group of
+// the router load balancer, which a dual-stack router allows IPv6 blocks
+// alongside.
+const lbInboundCIDRs = "    cidr_blocks     = [\"${var.lb_inbound_cidrs}\"]\n"
+
 type TemplateGenerator struct{}
 
 type templates struct {
-	base           string
-	iam            string
-	lbSubnet       string
-	cfLB           string
-	cfDNS          string
-	concourseLB    string
-	sslCertificate string
-	isoSeg         string
-	vpc            string
+	base              string
+	iam               string
+	lbSubnet          string
+	cfLB              string
+	cfELBv2LB         string
+	cfTCPLB           string
+	cfSNILB           string
+	cfDNS             string
+	cfDNSZone         string
+	cfDNSExistingZone string
+	dnsRole           string
+	concourseLB       string
+	sslCertificate    string
+	acmCertificate    string
+	acmDNSCertificate string
+	isoSeg            string
+	vpc               string
+	nat               string
+	natGateway        string
+	minimal           string
+	s3Blobstore       string
+	boshLite          string
+	dualStack         string
+	dualStackNAT      string
+	dualStackLB       string
+	dualStackCFLB     string
+	dualStackCFDNS    string
 }
 
 func NewTemplateGenerator() TemplateGenerator {
@@ -28,20 +100,145 @@ func (tg TemplateGenerator) Generate(state storage.State) string {
 	tmpls := readTemplates()
 	template := strings.Join([]string{tmpls.base, tmpls.iam, tmpls.vpc}, "\n")
 
+	natInstance := false
+	switch {
+	case state.AWS.Minimal:
+		template = strings.Join([]string{template, tmpls.minimal}, "\n")
+	case state.AWS.NATGateway:
+		template = strings.Join([]string{template, tmpls.natGateway}, "\n")
+		template = strings.Replace(template, internalRouteTable, natGatewayRouteTable, -1)
+
+		// The NAT instance stays while the subnets move onto the NAT
+		// gateways, so that the director never loses its way out.
+		if state.AWS.KeepNATInstance {
+			template = strings.Join([]string{template, tmpls.nat}, "\n")
+			natInstance = true
+		}
+	default:
+		template = strings.Join([]string{template, tmpls.nat}, "\n")
+		natInstance = true
+	}
+
+	if state.AWS.Lite {
+		template = strings.Join([]string{template, tmpls.boshLite}, "\n")
+	}
+
+	if state.AWS.S3Blobstore {
+		template = strings.Join([]string{template, tmpls.s3Blobstore}, "\n")
+	}
+
 	switch state.LB.Type {
 	case "concourse":
 		template = strings.Join([]string{template, tmpls.lbSubnet, tmpls.concourseLB}, "\n")
 	case "cf":
-		template = strings.Join([]string{template, tmpls.lbSubnet, tmpls.cfLB, tmpls.sslCertificate, tmpls.isoSeg}, "\n")
+		certificateARN := iamCertificateARN
+		certificate := tmpls.sslCertificate
+		if state.LB.CertARN != "" {
+			certificateARN = "${var.ssl_certificate_arn}"
+			certificate = tmpls.acmCertificate
+		}
+		// A certificate that bbl requests from ACM is validated with a
+		// record in the hosted zone of the system domain, so it comes
+		// with the dns templates.
+		if state.LB.ACMCertificate {
+			certificateARN = "${aws_acm_certificate_validation.lb_cert.certificate_arn}"
+			certificate = ""
+		}
+
+		// With ELBv2 the router is an application load balancer and the
+		// ssh proxy a network load balancer. The tcp router keeps a classic
+		// ELB, since a network load balancer has too few listeners for its
+		// ports.
+		cfLB := tmpls.cfLB
+		if state.LB.ELBv2 {
+			cfLB = tmpls.cfELBv2LB
+			if state.AWS.DualStack {
+				cfLB = strings.Replace(cfLB, lbInboundCIDRs, lbInboundCIDRs+"    ipv6_cidr_blocks = [\"${var.lb_inbound_ipv6_cidrs}\"]\n", -1)
+				cfLB = strings.Replace(cfLB, `  load_balancer_type = "application"`+"\n", `  load_balancer_type = "application"`+"\n"+`  ip_address_type    = "dualstack"`+"\n", -1)
+				cfLB = strings.Join([]string{cfLB, tmpls.dualStackCFLB}, "\n")
+			}
+		}
+		cfLB = strings.Replace(strings.Join([]string{cfLB, tmpls.cfTCPLB}, "\n"), iamCertificateARN, certificateARN, -1)
+		isoSeg := strings.Replace(tmpls.isoSeg, iamCertificateARN, certificateARN, -1)
+		if state.AWS.NATGateway {
+			isoSeg = strings.Replace(isoSeg, internalRouteTable, natGatewayRouteTable, -1)
+		}
+		if !natInstance {
+			isoSeg = natToIsolatedCellsRule.ReplaceAllString(isoSeg, "")
+		}
+		cfLB = attachCertificate(cfLB, certificateARN, state.AWS, "cf-router")
+		isoSeg = attachCertificate(isoSeg, certificateARN, state.AWS, "cf-iso-router")
+		template = strings.Join([]string{template, tmpls.lbSubnet, cfLB, certificate, isoSeg}, "\n")
+
+		if len(state.AWS.SNIDomains) > 0 {
+			template = strings.Join([]string{template, tmpls.cfSNILB}, "\n")
+		}
 
 		if state.LB.Domain != "" {
-			template = strings.Join([]string{template, tmpls.cfDNS}, "\n")
+			// The records go into the hosted zone of the user when there is
+			// one, which bbl then neither creates nor deletes.
+			cfDNS := strings.Join([]string{tmpls.cfDNSZone, tmpls.cfDNS}, "\n")
+			if state.LB.DNSZoneID != "" {
+				cfDNS = strings.Join([]string{tmpls.cfDNSExistingZone, tmpls.cfDNS}, "\n")
+			}
+			if state.LB.ACMCertificate {
+				cfDNS = strings.Join([]string{cfDNS, tmpls.acmDNSCertificate}, "\n")
+			}
+			if state.LB.ELBv2 {
+				cfDNS = elbv2Alias.ReplaceAllString(cfDNS, "$${aws_lb.$1.")
+				if state.AWS.DualStack {
+					cfDNS = strings.Join([]string{cfDNS, tmpls.dualStackCFDNS}, "\n")
+				}
+			}
+
+			if state.LB.DNSRoleARN != "" {
+				cfDNS = route53Resource.ReplaceAllString(cfDNS, "${1}  provider = \"aws.dns\"\n\n")
+				template = strings.Join([]string{template, cfDNS, tmpls.dnsRole}, "\n")
+			} else {
+				template = strings.Join([]string{template, cfDNS}, "\n")
+			}
 		}
 	}
 
+	// Dual-stack environments give the VPC and each subnet an IPv6 block.
+	// The director and load balancers reach the internet through the
+	// internet gateway and the other VMs through an egress-only one, which
+	// lets no connections in.
+	if state.AWS.DualStack {
+		template = strings.Join([]string{template, tmpls.dualStack}, "\n")
+		if state.AWS.NATGateway {
+			template = strings.Join([]string{template, tmpls.dualStackNAT}, "\n")
+		}
+		if state.LB.Type != "" {
+			template = strings.Join([]string{template, tmpls.dualStackLB}, "\n")
+		}
+		template = strings.Replace(template, "  enable_dns_hostnames = true\n", "  enable_dns_hostnames = true\n\n  assign_generated_ipv6_cidr_block = true\n", 1)
+		template = subnet.ReplaceAllStringFunc(template, func(resource string) string {
+			netnum := ipv6Netnums[subnet.FindStringSubmatch(resource)[1]]
+			return fmt.Sprintf("%s  ipv6_cidr_block                 = \"${cidrsubnet(local.vpc_ipv6_cidr, 8, %s)}\"\n  assign_ipv6_address_on_creation = true\n\n", resource, netnum)
+		})
+	}
+
+	if state.LB.Internal {
+		template = lbSubnets.ReplaceAllString(template, "${1}internal = true\n${1}subnets  = [\"$${aws_subnet.internal_subnets.*.id}\"]")
+	}
+
 	return template
 }
 
+// attachCertificate has the TLS listeners of the load balancer in template
+// use the certificate that bbl attach-certificate attached to it, in place of
+// the certificate of the load balancers.
+func attachCertificate(template, certificateARN string, state storage.AWS, lb string) string {
+	if state.AttachedCertificate(lb) == nil {
+		return template
+	}
+
+	variable := storage.CertificateLBs[lb]
+	template = strings.Replace(template, certificateARN, fmt.Sprintf("${var.%s}", variable), -1)
+	return fmt.Sprintf("%s\nvariable %q {\n  type = \"string\"\n}\n", template, variable)
+}
+
 func readTemplates() templates {
 	tmpls := templates{}
 	tmpls.base = string(MustAsset("templates/base.tf"))
@@ -49,10 +246,28 @@ func readTemplates() templates {
 	tmpls.lbSubnet = string(MustAsset("templates/lb_subnet.tf"))
 	tmpls.concourseLB = string(MustAsset("templates/concourse_lb.tf"))
 	tmpls.sslCertificate = string(MustAsset("templates/ssl_certificate.tf"))
+	tmpls.acmCertificate = string(MustAsset("templates/acm_certificate.tf"))
+	tmpls.acmDNSCertificate = string(MustAsset("templates/acm_dns_certificate.tf"))
 	tmpls.cfLB = string(MustAsset("templates/cf_lb.tf"))
+	tmpls.cfELBv2LB = string(MustAsset("templates/cf_elbv2_lb.tf"))
+	tmpls.cfTCPLB = string(MustAsset("templates/cf_tcp_lb.tf"))
+	tmpls.cfSNILB = string(MustAsset("templates/cf_sni_lb.tf"))
 	tmpls.cfDNS = string(MustAsset("templates/cf_dns.tf"))
+	tmpls.cfDNSZone = string(MustAsset("templates/cf_dns_zone.tf"))
+	tmpls.cfDNSExistingZone = string(MustAsset("templates/cf_dns_existing_zone.tf"))
+	tmpls.dnsRole = string(MustAsset("templates/dns_role.tf"))
 	tmpls.isoSeg = string(MustAsset("templates/iso_segments.tf"))
 	tmpls.vpc = string(MustAsset("templates/vpc.tf"))
+	tmpls.nat = string(MustAsset("templates/nat.tf"))
+	tmpls.natGateway = string(MustAsset("templates/nat_gateway.tf"))
+	tmpls.minimal = string(MustAsset("templates/minimal.tf"))
+	tmpls.s3Blobstore = string(MustAsset("templates/s3_blobstore.tf"))
+	tmpls.boshLite = string(MustAsset("templates/bosh_lite.tf"))
+	tmpls.dualStack = string(MustAsset("templates/dual_stack.tf"))
+	tmpls.dualStackNAT = string(MustAsset("templates/dual_stack_nat_gateway.tf"))
+	tmpls.dualStackLB = string(MustAsset("templates/dual_stack_lb.tf"))
+	tmpls.dualStackCFLB = string(MustAsset("templates/dual_stack_cf_lb.tf"))
+	tmpls.dualStackCFDNS = string(MustAsset("templates/dual_stack_cf_dns.tf"))
 
 	return tmpls
 }
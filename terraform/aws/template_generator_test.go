@@ -27,7 +27,7 @@ var _ = Describe("TemplateGenerator", func() {
 	Describe("Generate", func() {
 		Context("when no lb type is provided", func() {
 			BeforeEach(func() {
-				expectedTemplate = expectTemplate("base", "iam", "vpc")
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat")
 			})
 			It("uses the base template", func() {
 				template := templateGenerator.Generate(storage.State{})
@@ -35,9 +35,73 @@ var _ = Describe("TemplateGenerator", func() {
 			})
 		})
 
+		Context("when the environment is minimal", func() {
+			BeforeEach(func() {
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "minimal")
+			})
+			It("routes the internal subnets through the internet gateway instead of a NAT instance", func() {
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{Minimal: true}})
+				checkTemplate(template, expectedTemplate)
+				Expect(template).NotTo(ContainSubstring(`resource "aws_instance" "nat"`))
+			})
+		})
+
+		Context("when the director is a bosh-lite", func() {
+			BeforeEach(func() {
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "minimal", "bosh_lite")
+			})
+
+			It("opens the http, https and ssh proxy ports of the director", func() {
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{Minimal: true, Lite: true}})
+				checkTemplate(template, expectedTemplate)
+			})
+		})
+
+		Context("when the environment uses NAT gateways", func() {
+			It("routes the internal subnets through the NAT gateway of their availability zone", func() {
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat_gateway")
+				expectedTemplate = strings.Replace(expectedTemplate,
+					`route_table_id = "${aws_route_table.internal_route_table.id}"`,
+					`route_table_id = "${element(aws_route.nat_gateway_routes.*.route_table_id, count.index)}"`, -1)
+
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{NATGateway: true}})
+				checkTemplate(template, expectedTemplate)
+				Expect(template).NotTo(ContainSubstring(`resource "aws_instance" "nat"`))
+			})
+
+			It("keeps the NAT instance while the subnets move onto the NAT gateways", func() {
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{NATGateway: true, KeepNATInstance: true}})
+				Expect(template).To(ContainSubstring(`resource "aws_nat_gateway" "nat_gateways"`))
+				Expect(template).To(ContainSubstring(`resource "aws_instance" "nat"`))
+				Expect(template).NotTo(ContainSubstring(`route_table_id = "${aws_route_table.internal_route_table.id}"`))
+			})
+
+			It("routes the isolation segment subnets through the NAT gateways and leaves out the rule of the NAT instance", func() {
+				template := templateGenerator.Generate(storage.State{
+					AWS: storage.AWS{NATGateway: true},
+					LB:  storage.LB{Type: "cf"},
+				})
+				Expect(template).To(ContainSubstring(`resource "aws_route_table_association" "route_iso_subnets"`))
+				Expect(template).NotTo(ContainSubstring(`route_table_id = "${aws_route_table.internal_route_table.id}"`))
+				Expect(template).NotTo(ContainSubstring("nat_to_isolated_cells_rule"))
+				Expect(template).NotTo(ContainSubstring("aws_security_group.nat_security_group"))
+			})
+		})
+
+		Context("when the director stores its blobs in s3", func() {
+			BeforeEach(func() {
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat", "s3_blobstore")
+			})
+
+			It("adds the blobstore bucket and the policy of the director and its VMs", func() {
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{S3Blobstore: true}})
+				checkTemplate(template, expectedTemplate)
+			})
+		})
+
 		Context("when a concourse lb type is provided", func() {
 			BeforeEach(func() {
-				expectedTemplate = expectTemplate("base", "iam", "vpc", "lb_subnet", "concourse_lb")
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "concourse_lb")
 				lb = storage.LB{
 					Type: "concourse",
 				}
@@ -50,7 +114,7 @@ var _ = Describe("TemplateGenerator", func() {
 
 		Context("when a CF lb type is provided with no system domain", func() {
 			BeforeEach(func() {
-				expectedTemplate = expectTemplate("base", "iam", "vpc", "lb_subnet", "cf_lb", "ssl_certificate", "iso_segments")
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "cf_lb", "cf_tcp_lb", "ssl_certificate", "iso_segments")
 				lb = storage.LB{
 					Type: "cf",
 				}
@@ -61,9 +125,175 @@ var _ = Describe("TemplateGenerator", func() {
 			})
 		})
 
+		Context("when a CF lb type is provided with an ACM certificate arn", func() {
+			BeforeEach(func() {
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "cf_lb", "cf_tcp_lb", "acm_certificate", "iso_segments")
+				expectedTemplate = strings.Replace(expectedTemplate, "${aws_iam_server_certificate.lb_cert.arn}", "${var.ssl_certificate_arn}", -1)
+				lb = storage.LB{
+					Type:    "cf",
+					CertARN: "some-cert-arn",
+				}
+			})
+			It("uses the ACM certificate on the cf lb listeners instead of an IAM certificate", func() {
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				checkTemplate(template, expectedTemplate)
+				Expect(template).NotTo(ContainSubstring("aws_iam_server_certificate"))
+			})
+		})
+
+		Context("when an ACM certificate is requested for the domain", func() {
+			BeforeEach(func() {
+				lb = storage.LB{
+					Type:           "cf",
+					Domain:         "some-domain",
+					ACMCertificate: true,
+				}
+			})
+
+			It("requests and validates the certificate in the hosted zone and uses it on the cf lb listeners", func() {
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				Expect(template).To(HaveSuffix(expectTemplate("acm_dns_certificate")))
+				Expect(template).To(ContainSubstring(expectTemplate("cf_dns_zone", "cf_dns")))
+				Expect(strings.Count(template, `ssl_certificate_id = "${aws_acm_certificate_validation.lb_cert.certificate_arn}"`)).To(Equal(4))
+				Expect(template).NotTo(ContainSubstring("aws_iam_server_certificate"))
+				Expect(template).NotTo(ContainSubstring("ssl_certificate_arn"))
+			})
+
+			It("creates the validation record with the provider that assumes the dns role", func() {
+				lb.DNSRoleARN = "some-role-arn"
+
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				Expect(template).To(ContainSubstring("resource \"aws_route53_record\" \"lb_cert_validation\" {\n  provider = \"aws.dns\"\n\n"))
+				Expect(template).To(ContainSubstring("resource \"aws_acm_certificate\" \"lb_cert\" {\n  domain_name"))
+			})
+		})
+
+		Context("when a certificate is attached to a cf load balancer", func() {
+			It("uses it on the listeners of that load balancer only", func() {
+				state := storage.State{LB: storage.LB{Type: "cf", CertARN: "some-cert-arn"}}
+				state.AWS.Certificates = []storage.ServerCertificate{{Name: "foo", ARN: "foo-arn", LBs: []string{"cf-iso-router"}}}
+
+				template := templateGenerator.Generate(state)
+				Expect(template).To(ContainSubstring(`ssl_certificate_id = "${var.cf_iso_router_certificate_arn}"`))
+				Expect(strings.Count(template, `ssl_certificate_id = "${var.cf_iso_router_certificate_arn}"`)).To(Equal(2))
+				Expect(strings.Count(template, `ssl_certificate_id = "${var.ssl_certificate_arn}"`)).To(Equal(2))
+				Expect(template).To(ContainSubstring("variable \"cf_iso_router_certificate_arn\" {\n  type = \"string\"\n}\n"))
+				Expect(template).NotTo(ContainSubstring("cf_router_certificate_arn"))
+			})
+		})
+
+		Context("when the cf load balancer has SNI domains", func() {
+			It("adds a load balancer for each domain", func() {
+				state := storage.State{LB: storage.LB{Type: "cf", CertARN: "some-cert-arn"}}
+				state.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "apps"}}
+
+				template := templateGenerator.Generate(state)
+				Expect(template).To(ContainSubstring(`resource "aws_elb" "cf_router_sni_lbs" {`))
+				Expect(template).To(ContainSubstring(`ssl_certificate_id = "${element(var.sni_certificate_arns, count.index)}"`))
+			})
+
+			It("leaves them out of other load balancers", func() {
+				state := storage.State{LB: storage.LB{Type: "concourse"}}
+				state.AWS.SNIDomains = []storage.SNIDomain{{Domain: "apps.example.com", Certificate: "apps"}}
+
+				template := templateGenerator.Generate(state)
+				Expect(template).NotTo(ContainSubstring("sni"))
+			})
+		})
+
+		Context("when the load balancers are internal", func() {
+			It("places each cf load balancer on the internal subnets", func() {
+				template := templateGenerator.Generate(storage.State{LB: storage.LB{Type: "cf", CertARN: "some-cert-arn", Internal: true}})
+				Expect(template).NotTo(ContainSubstring(`subnets         = ["${aws_subnet.lb_subnets.*.id}"]`))
+				Expect(strings.Count(template, "  internal = true\n  subnets  = [\"${aws_subnet.internal_subnets.*.id}\"]")).To(Equal(4))
+				Expect(template).To(ContainSubstring(`output "lb_subnet_ids" {`))
+			})
+
+			It("places the concourse load balancer on the internal subnets", func() {
+				template := templateGenerator.Generate(storage.State{LB: storage.LB{Type: "concourse", Internal: true}})
+				Expect(template).To(ContainSubstring("  internal = true\n  subnets  = [\"${aws_subnet.internal_subnets.*.id}\"]"))
+				Expect(template).NotTo(ContainSubstring(`subnets            = ["${aws_subnet.lb_subnets.*.id}"]`))
+			})
+		})
+
+		Context("when the cf load balancers are ELBv2", func() {
+			BeforeEach(func() {
+				lb = storage.LB{Type: "cf", ELBv2: true}
+			})
+
+			It("uses an application load balancer for the router and a network load balancer for the ssh proxy", func() {
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				checkTemplate(template, expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "cf_elbv2_lb", "cf_tcp_lb", "ssl_certificate", "iso_segments"))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_elb" "cf_router_lb"`))
+				Expect(template).To(ContainSubstring(`resource "aws_elb" "cf_tcp_lb"`))
+			})
+
+			It("uses the ACM certificate on the listeners of the router", func() {
+				lb.CertARN = "some-cert-arn"
+
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				Expect(strings.Count(template, `certificate_arn   = "${var.ssl_certificate_arn}"`)).To(Equal(2))
+				Expect(template).NotTo(ContainSubstring("aws_iam_server_certificate"))
+			})
+
+			It("points the dns records at the ELBv2 load balancers", func() {
+				lb.Domain = "some-domain"
+
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				Expect(template).To(ContainSubstring(`name                   = "${aws_lb.cf_router_lb.dns_name}"`))
+				Expect(template).To(ContainSubstring(`zone_id                = "${aws_lb.cf_ssh_lb.zone_id}"`))
+				Expect(template).To(ContainSubstring(`name                   = "${aws_elb.cf_tcp_lb.dns_name}"`))
+				Expect(template).NotTo(ContainSubstring("aws_elb.cf_router_lb"))
+			})
+		})
+
+		Context("when the environment is dual-stack", func() {
+			It("gives the vpc and each subnet an IPv6 block", func() {
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{DualStack: true}})
+				Expect(template).To(ContainSubstring("  enable_dns_hostnames = true\n\n  assign_generated_ipv6_cidr_block = true\n"))
+				Expect(template).To(ContainSubstring("resource \"aws_subnet\" \"bosh_subnet\" {\n  ipv6_cidr_block                 = \"${cidrsubnet(local.vpc_ipv6_cidr, 8, 0)}\"\n  assign_ipv6_address_on_creation = true\n\n"))
+				Expect(template).To(ContainSubstring("resource \"aws_subnet\" \"internal_subnets\" {\n  ipv6_cidr_block                 = \"${cidrsubnet(local.vpc_ipv6_cidr, 8, count.index+16)}\"\n"))
+				Expect(template).To(HaveSuffix(expectTemplate("dual_stack")))
+				Expect(template).NotTo(ContainSubstring("lb_route_table_ipv6"))
+			})
+
+			It("routes the internal subnets through the egress-only gateway from the NAT gateway route tables", func() {
+				template := templateGenerator.Generate(storage.State{AWS: storage.AWS{DualStack: true, NATGateway: true}})
+				Expect(template).To(HaveSuffix(expectTemplate("dual_stack", "dual_stack_nat_gateway")))
+				Expect(template).To(ContainSubstring("resource \"aws_subnet\" \"nat_subnets\" {\n  ipv6_cidr_block                 = \"${cidrsubnet(local.vpc_ipv6_cidr, 8, count.index+48)}\"\n"))
+			})
+
+			It("has the application load balancer of the router answer on IPv6 too", func() {
+				state := storage.State{
+					AWS: storage.AWS{DualStack: true},
+					LB:  storage.LB{Type: "cf", ELBv2: true, Domain: "some-domain"},
+				}
+
+				template := templateGenerator.Generate(state)
+				Expect(template).To(ContainSubstring("  load_balancer_type = \"application\"\n  ip_address_type    = \"dualstack\"\n"))
+				Expect(strings.Count(template, `ipv6_cidr_blocks = ["${var.lb_inbound_ipv6_cidrs}"]`)).To(Equal(4))
+				Expect(template).To(ContainSubstring(expectTemplate("dual_stack_cf_lb")))
+				Expect(template).To(ContainSubstring(expectTemplate("dual_stack_cf_dns")))
+				Expect(template).To(ContainSubstring("resource \"aws_subnet\" \"lb_subnets\" {\n  ipv6_cidr_block                 = \"${cidrsubnet(local.vpc_ipv6_cidr, 8, count.index+32)}\"\n"))
+				Expect(template).To(HaveSuffix(expectTemplate("dual_stack", "dual_stack_lb")))
+			})
+
+			It("leaves the classic load balancers on IPv4", func() {
+				state := storage.State{
+					AWS: storage.AWS{DualStack: true},
+					LB:  storage.LB{Type: "cf", Domain: "some-domain"},
+				}
+
+				template := templateGenerator.Generate(state)
+				Expect(template).NotTo(ContainSubstring("dualstack"))
+				Expect(template).NotTo(ContainSubstring("lb_inbound_ipv6_cidrs"))
+				Expect(template).NotTo(ContainSubstring(`type    = "AAAA"`))
+			})
+		})
+
 		Context("when a CF lb type is provided with a system domain", func() {
 			BeforeEach(func() {
-				expectedTemplate = expectTemplate("base", "iam", "vpc", "lb_subnet", "cf_lb", "ssl_certificate", "iso_segments", "cf_dns")
+				expectedTemplate = expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "cf_lb", "cf_tcp_lb", "ssl_certificate", "iso_segments", "cf_dns_zone", "cf_dns")
 				lb = storage.LB{
 					Type:   "cf",
 					Domain: "some-domain",
@@ -73,6 +303,38 @@ var _ = Describe("TemplateGenerator", func() {
 				template := templateGenerator.Generate(storage.State{LB: lb})
 				checkTemplate(template, expectedTemplate)
 			})
+
+			It("adds the records to an existing hosted zone instead of creating one", func() {
+				lb.DNSZoneID = "Z123"
+
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				checkTemplate(template, expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "cf_lb", "cf_tcp_lb", "ssl_certificate", "iso_segments", "cf_dns_existing_zone", "cf_dns"))
+			})
+		})
+
+		Context("when a CF lb type is provided with a system domain and a dns role", func() {
+			BeforeEach(func() {
+				lb = storage.LB{
+					Type:       "cf",
+					Domain:     "some-domain",
+					DNSRoleARN: "some-role-arn",
+				}
+			})
+			It("manages the hosted zone and records with the provider that assumes the dns role", func() {
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				Expect(template).To(HavePrefix(expectTemplate("base", "iam", "vpc", "nat", "lb_subnet", "cf_lb", "cf_tcp_lb", "ssl_certificate", "iso_segments")))
+				Expect(template).To(HaveSuffix(expectTemplate("dns_role")))
+				Expect(template).To(ContainSubstring("resource \"aws_route53_zone\" \"env_dns_zone\" {\n  provider = \"aws.dns\"\n\n  name = "))
+				Expect(strings.Count(template, `provider = "aws.dns"`)).To(Equal(7))
+			})
+
+			It("reads an existing hosted zone with the provider that assumes the dns role", func() {
+				lb.DNSZoneID = "Z123"
+
+				template := templateGenerator.Generate(storage.State{LB: lb})
+				Expect(template).To(ContainSubstring("data \"aws_route53_zone\" \"env_dns_zone\" {\n  provider = \"aws.dns\"\n\n  zone_id = "))
+				Expect(strings.Count(template, `provider = "aws.dns"`)).To(Equal(7))
+			})
 		})
 	})
 })
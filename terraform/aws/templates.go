@@ -1,12 +1,30 @@
 // Code generated by go-bindata.
 // sources:
+// templates/acm_certificate.tf
+// templates/acm_dns_certificate.tf
 // templates/base.tf
+// templates/bosh_lite.tf
 // templates/cf_dns.tf
+// templates/cf_dns_existing_zone.tf
+// templates/cf_dns_zone.tf
+// templates/cf_elbv2_lb.tf
 // templates/cf_lb.tf
+// templates/cf_sni_lb.tf
+// templates/cf_tcp_lb.tf
 // templates/concourse_lb.tf
+// templates/dns_role.tf
+// templates/dual_stack.tf
+// templates/dual_stack_cf_dns.tf
+// templates/dual_stack_cf_lb.tf
+// templates/dual_stack_lb.tf
+// templates/dual_stack_nat_gateway.tf
 // templates/iam.tf
 // templates/iso_segments.tf
 // templates/lb_subnet.tf
+// templates/minimal.tf
+// templates/nat.tf
+// templates/nat_gateway.tf
+// templates/s3_blobstore.tf
 // templates/ssl_certificate.tf
 // templates/vpc.tf
 // DO NOT EDIT!
@@ -76,7 +94,47 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _templatesBaseTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdc\x5b\x5f\x6f\xe3\xb8\x11\x7f\x3e\x7f\x0a\xc1\xd8\x87\xdd\x36\xf6\x5a\x8e\xff\xe5\x80\x7d\xb8\xf6\x0a\xf4\xfa\x70\x2d\x7a\xfb\x56\x2c\x04\x8a\xa2\x65\x36\x92\x28\x90\x94\xb3\xd9\xc0\xdf\xbd\xe0\x3f\x49\x94\x44\x4b\xca\xc6\xeb\xa4\x77\x0f\x9b\x88\x33\xc3\x99\xdf\x0c\x67\x86\xd2\xe4\x08\x28\x06\x61\x82\xbc\x69\x06\x78\x00\x52\x1c\xa4\x20\x9f\x7a\x4f\x13\xcf\xe3\x8f\x39\xf2\x3e\x79\x53\xf1\x60\x32\xf1\xbc\x08\xed\x41\x91\x70\xef\x93\x5c\xf5\x3c\x90\xcf\x32\x42\xf9\x01\x01\xc6\x67\xbe\xa0\x04\x29\x9e\xf9\x8b\x68\x0f\x77\xdb\xed\xb4\x4d\xb3\x2c\x69\x80\x1f\xc2\xd5\x76\x55\xd2\x30\x52\xf0\xc3\xcc\x17\xbf\x19\x9a\xed\x0a\xfa\xbb\x8d\x1f\xda\x34\xf6\x5e\xb7\x1b\xb0\x5f\x2e\xd6\xeb\x0e\x9a\x6a\x2f\x74\xe7\xef\xfc\x6d\xa4\x68\x20\x98\x41\x94\x71\x0a\x12\xb9\x9b\xa1\x59\x46\xb7\x1b\xb0\xdd\x28\x1a\x54\x74\xd1\xdc\xa1\x10\xf9\xbb\xbd\x5f\xd2\x3c\x20\xa9\x4a\x5d\xe7\x5b\xb0\x5b\xdd\xed\xd7\xd0\xa6\x59\x5a\x34\x4b\xdf\x5f\x2e\x56\x2b\xad\x73\xc1\x66\xda\xa4\x3a\x4d\xb4\x82\x6b\xb4\x87\x4b\x9b\xc6\x96\xb3\x5f\x6e\xc3\x35\xb8\xdb\x96\x34\x31\x39\x96\x3a\x69\x1a\x78\x7b\xb7\xf1\x17\xa0\x92\xd3\xa1\x73\xb8\xdb\xee\xd7\xb7\xd1\xce\xa6\xb1\xf7\xda\x85\x7b\x88\x76\x7b\x29\xe7\x34\x39\x4d\x26\x55\xd4\x00\x08\x11\x63\xc1\x3d\x7a\xb4\x83\x86\x71\x8a\xb3\x78\x6a\x13\x33\x04\x29\xe2\x03\x89\x29\x8a\x31\xc9\x06\x10\x86\x84\x1d\x02\x9c\x85\xa4\xc8\xa2\x00\xe2\x88\x2a\x9e\x2a\x5c\xa7\x8b\xb9\xfc\xff\xe3\xa2\xc1\x09\x8e\x00\x27\x20\xc4\x09\xe6\x8f\xc1\x37\x92\x21\x66\x6f\x97\x60\xc6\x1b\x2c\x28\x3b\x06\x38\x1a\x62\xeb\x81\x50\x1e\x0c\x26\x3f\xe6\xb0\xa6\xbb\x24\x55\xf8\x1b\x6a\xcb\x20\xdf\x58\xe4\x6f\xa4\x1c\x8a\x18\x29\x28\x14\x26\x3d\xb0\x00\xe1\x7c\xea\x4d\xff\x5b\xa4\x79\x48\xbe\xaa\xdf\x14\x20\x39\xca\x22\x16\x90\xcc\xfb\xe4\xfd\x47\x52\xe2\x8c\x23\x9a\x21\x1e\xc4\x80\xa3\x07\xf0\x38\xc7\xf1\xf4\xcb\xc4\xf3\x8e\x39\xf4\x3c\x13\x02\x9c\x16\xc8\xde\x84\x27\x2c\xc8\x29\x3e\x02\x8e\x94\x33\x95\x0f\x8e\xa9\xc6\x0f\x24\x31\xa1\x98\x1f\x52\xa1\xeb\xbf\xff\xf8\x45\x68\x4f\x19\x08\x42\xcc\x99\x90\xb8\x5a\xdc\x6d\xda\x6a\xdf\xa3\xc7\x20\x07\x98\xb6\xc4\x89\x85\x0c\xa4\x48\x01\xf2\xee\xe9\x08\xe8\x5c\x01\x7b\x0a\x4a\xca\x89\xe7\xe5\x45\x98\x60\x28\xe4\x28\xba\x86\x9a\x73\x43\x3b\xaf\x08\x03\x92\xa3\x8c\xb1\xc3\xa9\x03\x46\x86\x60\x41\x45\x64\xc4\x94\x14\x02\x51\x91\x21\x9b\x0f\x85\x7e\x5a\x37\x73\x62\x2c\x05\x67\x19\xe0\x33\xc3\x34\x53\x4c\xd2\x17\x0c\x52\x9c\x73\x2c\x9d\x31\xfd\xfd\x97\xcf\x53\x05\x7b\x80\xa3\x9a\xa0\x84\x40\x90\xcc\xd5\xe3\x93\x4c\xc2\x1c\xc4\x4c\xe7\xdf\xdf\xc5\xb6\x03\xf7\x3b\x09\xde\x04\xef\x11\x7c\x84\x09\xd2\x02\x70\x9c\x11\x8a\x02\x78\x00\x59\x8c\x98\x0c\x0a\x61\x8a\x8c\x80\x53\x1f\x1e\x01\x2d\x12\xa4\x41\xe1\xa4\x8a\x24\xf5\x58\x6c\xd0\xa0\xc7\x91\x52\xb6\x2d\x6a\xde\x06\x76\x5e\xda\xab\x4f\x82\x86\x04\xc5\x14\x31\xe9\xec\x3d\x25\x69\x90\x13\xca\xe5\xc2\x42\x90\x12\xf3\xbb\x79\x92\x53\xc2\x09\x24\x89\x66\x9e\xc9\xe4\x2d\x4e\x59\x10\x26\x04\xde\x2b\x93\xab\xe4\xf0\x65\x8c\xcd\x18\xa6\xf9\x85\x8d\xc5\x59\x69\x6d\xc3\x12\xb1\x79\x1b\x84\x99\xdf\x42\x41\x3e\x7a\x21\x8b\x39\xbc\xa8\xc1\xd6\x7f\x6e\xeb\x9b\x64\x1c\xb6\x90\x68\x90\x34\x63\xa3\xb1\xbc\x59\xaf\x6f\xd7\xc2\x20\x09\x42\xd3\xfe\x33\x76\xa9\x90\x07\x49\xa7\x71\x23\x70\x2d\xa2\xd7\x88\x6b\x11\xbd\x0d\x5c\x71\xc6\x38\xc8\xa0\x06\x53\x61\x68\x92\x3e\xce\x9b\x56\xbd\x7b\x12\x87\xe1\x40\x18\x7f\x2f\x77\x2e\xc2\x0c\x71\x55\x18\xf4\xcf\xd5\x61\xb9\xf1\xb6\x1f\x4e\x02\x03\xb3\x45\x60\xc3\x2a\x82\x6f\x39\x4f\x51\x84\x8b\x54\x90\x29\x01\x65\x02\xb7\x76\x75\x6c\x26\x4d\x2a\x21\x8a\x10\xe3\x01\x3c\x20\x78\x6f\x38\xf7\x20\x61\x48\x14\xd4\x14\x3b\xbc\x29\x6a\x04\xb9\x2f\xf2\xf7\xa2\x06\xd4\x5a\xf8\x1b\x4f\x3c\x50\x3d\x94\xb2\x42\x54\x91\x96\x13\x54\x42\x18\x13\x5e\x5f\xba\xaa\x50\x67\x19\x52\x1d\xe5\xdf\xb2\xe3\x6f\xbf\xb6\xd6\xa7\xdd\x25\x46\x75\x2e\x62\xe7\xe7\x74\x2d\xc6\x4f\x75\xd0\xcd\x33\x61\x8e\x81\xbb\xb3\xbb\xc9\x29\x39\xe2\x08\x51\xa9\x88\x6e\x63\xca\xde\xb6\xd2\xbf\x7a\xa6\x3c\x57\x76\xb4\x15\x49\xf5\x4c\x92\x28\x1f\x54\xfe\xaa\xfc\xd2\x15\xce\xba\xc9\x6b\x37\x1f\xae\x85\xa7\xaa\x6f\xe8\x6a\x19\xfa\xbb\x1a\xc7\x71\x1b\xd0\xda\x18\xce\xfe\xfe\xe6\x37\x4d\xf9\x52\x4d\xce\x99\x9d\x2f\xd7\xe9\x38\x80\x92\xcb\xa2\x38\x8e\xcd\xdf\x67\xf3\x5c\x57\x0e\xef\x4b\xde\xe7\xaa\xa1\x2b\x5d\xd7\xf2\x34\x4a\xf6\xcd\xfd\xda\xad\xff\x33\xe1\x11\xd5\xe4\x15\xc0\xe3\x2c\x6a\x57\x86\x47\xf6\x73\xaf\x00\x9f\xae\xbe\xd2\x2c\xb6\xba\x4b\x6b\xa1\xde\x63\x9a\x85\x67\x75\x9a\x67\x71\x02\x49\x42\x1e\xca\xfc\xff\x23\x10\x43\xe7\x01\x53\x57\x8a\x31\xf1\xb4\xf8\x61\x60\x31\x76\x70\x21\x54\x19\xf0\x32\x40\x0d\x8c\xb0\x8a\xec\xf3\x5f\xff\xd5\xd3\x5d\x2e\x97\xe7\xdb\x4b\xb9\x3e\xba\xb7\xd4\x2f\x45\x06\xf5\xe8\xe6\x3d\xc4\xe8\xba\x28\xb8\xfa\x6b\xe2\x5f\xfe\xf9\xc7\xdf\xbd\x5f\x31\x45\x90\x13\xfa\x52\x85\xd1\xb1\xf5\xa8\xa2\x78\x23\x9a\x8d\x52\xd5\x71\x35\xb2\x03\xb0\xb2\x3e\x9e\x0b\x48\x97\xbf\x3a\xe4\x5d\xaa\x3e\x3a\x02\x4e\x2f\x74\x1f\x59\x05\x7e\xeb\x05\xe4\x69\xf8\x09\x3e\x0b\x98\x5c\x04\x31\xca\x9c\xa9\xae\xe7\x20\x8f\x82\x6f\xf4\x21\xee\xbf\x7a\x6f\x76\x9b\x5d\xcf\x2d\x51\x51\x5c\xf4\x20\xf7\x62\x5d\x00\xf0\x46\x01\xde\xad\x56\xb7\xe7\x01\xd6\x14\xd7\x05\x18\x52\x14\x1d\x8a\xf0\xad\x82\xbc\x5b\xad\x7a\x40\x56\x14\xd7\x05\x59\x64\x8c\x48\xd7\x93\x00\xe4\xf8\x8d\xa2\xbd\x5c\xaf\xd7\xeb\x9e\xda\xaf\x49\xae\x8e\xf7\x1b\x85\xf8\x75\xbc\xb9\x1b\x9b\xa4\xdd\x57\xc8\xab\xc2\xfd\x56\x5e\x94\x8e\x84\xfb\xfb\xae\x5a\x63\xfb\xb6\xd7\x79\xcd\xaa\xbe\xa2\x0e\xe8\xfa\x35\x65\x7f\xe3\xff\x0f\x2d\xf2\x85\x5a\x7e\xf7\xbe\x3f\xac\xeb\x37\x9f\x9a\x9f\xd1\xe0\x5b\x19\xf9\xff\xa5\xa9\x37\x78\xd0\xf1\x6f\xbd\x2e\x8c\xc7\xed\xed\xee\xce\x81\x88\x5e\xba\x34\x26\x67\xaf\x33\x57\x42\xc5\x79\x4d\x29\x97\x2e\x8d\x8a\xe9\xdb\x5e\x19\x30\xee\x5e\xac\x5a\xbb\x34\x34\xba\x34\x5c\x00\x98\xd7\x59\x74\xac\xca\xdc\x2e\xf1\xdf\xd9\x7a\x5e\xfe\xd5\xde\xf5\xda\x4f\x67\xd3\xf1\x02\x88\x3f\xbf\xfb\xbc\x3c\xe2\xd7\xeb\x40\x47\x20\x2e\x3f\x78\x97\x0d\xa7\xfe\xed\xc9\xee\x81\xba\x5a\xa0\xfa\x89\xaa\xbe\xe0\x2b\x01\xf2\xa3\xb7\x99\x9c\xbb\xf1\x76\x37\xde\xe2\xc3\xa8\x17\xa5\x4a\x8d\xee\x56\x87\x92\x82\xa3\x80\x83\xb0\x8a\x0d\xeb\xd1\xd8\x0f\xaf\x92\xd9\x29\x29\x42\x8c\xe3\x0c\x88\xde\x2b\xb0\x0d\xae\x0d\x2f\x7a\x9e\xfe\xe2\xdd\x1c\x32\xa8\x7d\xee\x6e\x7d\x1a\x37\x81\x56\xdb\xb2\xce\x5e\xb2\xd6\xd6\xe7\x4d\x1d\x1d\x4e\xad\x8b\x04\x8c\x11\x88\xa5\x01\x53\x6f\xaa\x56\x6a\xbe\x36\x09\xdc\x9e\x91\x18\x30\x1b\xd1\x50\xfb\x7b\xd4\x2d\x63\xb0\x3a\x92\x75\xdd\x20\x29\x32\xde\x86\x35\x41\x59\xcc\x0f\x32\xd4\xda\xf3\xa2\xd5\x68\x85\xed\x91\xde\x48\xae\xd3\x39\x03\x7a\x75\xa3\x94\x9a\xe3\x2c\x42\x5f\xff\xec\xab\xdd\x5a\x5a\x28\x29\x28\x41\x29\xca\xb8\x43\x51\x4b\xd2\xd0\x43\x52\x7d\x66\x97\xda\xbd\x7b\xaa\xc9\x38\x8d\xb9\x61\x54\x86\x8b\x7b\x46\x4b\x3b\xd7\x6d\xc3\x3e\x82\xa5\xd7\x5e\xe4\x18\xba\xa5\x0d\x3c\x8a\xe5\x54\x50\x87\xe7\x5d\x93\x27\x63\xce\x60\x97\x82\xcf\x3c\x87\x83\xe2\x7d\x68\xb0\x77\x1d\x61\x13\x7b\xb5\xa3\xdc\xdc\x73\xfe\xa7\x39\x8e\x5a\x51\x38\xec\x7c\x8f\x80\xa2\x99\x00\x85\xa7\xe3\x21\x51\x22\x9f\xa8\xe3\x50\xbe\x44\x6d\xdc\xf7\x65\xcd\x68\x8d\x31\x95\xca\x89\x58\x69\x79\xf4\xec\x84\x99\xcd\x1f\x3f\x34\x9c\x51\x8e\xaa\x29\x6d\xad\x8d\x6e\x3c\x9d\x0d\x4c\x9b\x5c\xae\xe2\x7c\x10\xfb\x5a\xb1\x97\xb6\xd6\xf9\x07\xb0\x6f\x3e\x74\xa1\x7f\x9f\xea\x3f\x05\x98\x96\x3f\x09\x40\x51\x26\xdd\x7b\x8f\x1e\x03\x4a\x38\xd0\x2f\x42\xcc\xf4\x04\x29\x78\x5e\xf0\x6a\xc2\xc9\x8c\x7b\x6b\xaf\x81\xa4\x40\x15\x9e\x66\x48\xbc\x1a\xe6\x36\xe4\x4a\x1f\x23\xcc\x1a\x4f\xaf\xe4\x94\xd8\xba\x67\xc3\xab\x87\x41\x8e\x52\x3d\xe5\x95\x31\xcc\xf1\x11\x75\x68\x8d\xbe\x96\xb8\x75\x2a\x8c\x70\x79\x19\x91\x3f\xeb\xd9\x73\x9c\xdb\xfa\x1a\x92\x82\x26\x23\xc5\xfc\xbc\x5c\x5a\x92\xaa\x4f\x00\x51\x54\xdd\x9c\x4a\x71\x07\xce\x73\xf6\xf3\xc7\x8f\xfd\x62\xc5\xfd\xce\x92\x6c\xcd\xe5\x75\xe8\xa7\xd7\x5d\x26\x9e\x1d\x34\x6b\x88\x1b\xf9\xa6\xd3\x6c\xe1\xfc\x5e\x7f\x5e\xfc\xb9\x36\xb6\xe9\xa0\xf1\xd2\x9b\x1f\x1d\x9a\x12\x1d\x33\x7f\x0d\xc7\x39\xa6\x34\xeb\xc2\xbf\x74\x86\xc1\x77\x89\x77\x21\x63\x6d\x55\x56\x84\x6e\x34\x1c\xad\x5d\x4d\x00\xf8\x36\x94\xb3\x55\x94\x6c\x41\x2a\xa1\xb7\x84\xb5\xb3\xbd\x61\xa8\xff\x91\x51\x8d\xa1\x39\xa3\x69\xc8\x75\x56\x0b\x00\x6d\xf3\xd4\xf2\xdf\xdc\xfc\x0b\x68\xe6\x38\x03\xe0\x5b\x50\xc2\x16\xa4\x20\xcf\x71\x16\xb7\x44\x4e\x7e\xf2\xbc\x6f\x38\x4f\x41\xfe\xde\x86\xa4\xa3\xb8\x76\x20\x73\xe3\xf5\x72\x09\x3c\x3e\x4c\x7e\xea\x55\x52\xd6\xac\xeb\xa9\x59\x2f\x99\x2d\x75\xad\x72\xed\xf0\xbd\x45\xe3\xb0\xb6\xfa\xf3\xab\x16\xbb\x45\xe3\x60\x8f\x1f\xfa\x98\xe3\x07\x47\x02\xa8\xd5\x5e\x87\x8c\x8e\x2a\xef\x00\x61\x80\xb0\xae\x9a\x2f\xa5\xfd\x2f\x00\x00\xff\xff\xef\xa5\x0b\x1c\x0d\x3a\x00\x00")
+var _templatesAcm_certificateTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x35\x00\xca\xff\x76\x61\x72\x69\x61\x62\x6c\x65\x20\x22\x73\x73\x6c\x5f\x63\x65\x72\x74\x69\x66\x69\x63\x61\x74\x65\x5f\x61\x72\x6e\x22\x20\x7b\x0a\x20\x20\x74\x79\x70\x65\x20\x3d\x20\x22\x73\x74\x72\x69\x6e\x67\x22\x0a\x7d\x0a\x03\x00\xc1\x11\xf9\x19\x35\x00\x00\x00")
+
+func templatesAcm_certificateTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesAcm_certificateTf,
+		"templates/acm_certificate.tf",
+	)
+}
+
+func templatesAcm_certificateTf() (*asset, error) {
+	bytes, err := templatesAcm_certificateTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/acm_certificate.tf", size: 53, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesAcm_dns_certificateTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x52\xb1\x4e\xc3\x30\x14\xdc\xf3\x15\x96\xc5\xd0\xa2\xd6\xaa\x84\x60\x63\x63\xee\xc2\x88\x90\xe5\xd8\x2f\x25\xc8\xb1\x83\xed\x04\x85\x2a\xff\xce\x8b\x9d\xa8\x49\x5b\x98\xda\x25\xaa\x75\xef\xde\xdd\xbd\x73\xe0\x6d\xe3\x24\x10\x2a\xbe\x3d\x17\xb2\xe2\x12\x5c\x28\x8b\x52\x8a\x00\x94\x50\x9d\xc7\x07\x4a\x8e\x19\x21\xca\x56\xa2\x34\xdc\x88\x0a\xc8\xf2\xf7\x4c\xe8\xdd\xb1\x15\x8e\xf9\xce\x07\xa8\x78\x42\xf6\x14\x87\x7c\x93\x7f\x82\x0c\x5c\xe8\x00\xce\x88\x50\xb6\x10\x19\x3c\x0e\xbd\xd1\x7b\x76\x75\xee\x1d\x07\x5b\xa1\x4b\x85\x78\x6b\x78\x05\xe1\xc3\xaa\xf9\xb6\x97\xfd\x2b\xcd\x10\x14\xc4\xc1\xa7\xed\x15\xb8\x03\xac\xb4\x95\x42\xb3\xe1\x75\x43\x2a\x51\xaf\xe8\x1e\x57\xd1\xcd\x24\x0f\x4c\xcb\x4b\xd5\x6f\x75\xbe\x8d\xb6\xd6\xeb\x3e\xd2\xe8\xb2\x00\xd9\x49\x0d\xd1\x27\x21\xd2\x01\xfa\xe7\x39\x14\xd6\x01\x57\xe0\x83\xb3\x1d\xee\x09\xae\x01\x04\xf4\x59\x9f\x65\x6e\x11\x9d\xb3\x4d\x80\xc7\x07\xee\x40\x5a\xa7\x4e\xc9\xf1\x93\x8d\x14\xe2\x8f\x35\x80\x1a\x92\xe8\x24\x57\x19\xcf\xc7\xe7\x18\xd9\x14\x70\x84\x5c\xb9\x0b\x1b\xb9\xd9\x78\x90\x59\x52\xb6\x1e\x3e\x9e\xed\xd8\x24\x6f\x54\x14\x33\x8f\xec\xa1\xab\x6f\xcf\x3e\x90\x46\xf6\xf4\x3f\x1d\xf7\x76\xf4\x08\x6a\x20\xd5\x22\x04\x3d\x96\xe0\x69\x77\x79\x86\xb3\x65\x8b\xf4\x97\x65\x9e\xa3\x84\x33\xcb\x2a\xff\xa7\x1b\xc1\xd1\xe9\x4c\xf8\xa8\xb2\xf8\xc2\x4b\xce\x9c\x2f\x3b\xc1\x2e\x1b\xc1\x86\x89\xc1\x16\xfa\x40\x6c\xdd\x84\x53\x6f\x70\x4d\x12\x1a\xbd\xff\x29\x6b\xce\x36\x29\x3c\xb3\x86\x6a\xfb\xec\x17\x41\x35\xad\xee\xe9\x03\x00\x00")
+
+func templatesAcm_dns_certificateTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesAcm_dns_certificateTf,
+		"templates/acm_dns_certificate.tf",
+	)
+}
+
+func templatesAcm_dns_certificateTf() (*asset, error) {
+	bytes, err := templatesAcm_dns_certificateTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/acm_dns_certificate.tf", size: 1001, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesBaseTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x5b\x5b\x6f\x1b\xbb\x11\x7e\x3e\xfe\x15\x84\x70\x1e\xec\x56\x52\x7c\x89\x0d\x9f\x00\x6d\xe1\x9e\x14\x3d\x2e\x70\xd2\x20\x09\xda\x07\x23\x58\x50\xbb\x94\xc4\x7a\x6f\x25\xb9\x92\x65\x43\xff\xbd\x43\x72\xc9\xe5\x5e\x28\xad\x7c\x77\x50\xe7\x21\xd6\x72\x38\xfc\xe6\x9b\xe1\x70\xb8\x1a\x2f\x30\xa3\x78\x12\x13\x34\xc0\x61\x48\x38\x0f\xae\xc9\x6a\x80\xee\xf6\x10\x12\xab\x9c\xa0\x3f\xa1\x01\x17\x8c\xa6\xb3\xc1\xde\x7a\x6f\x6f\x61\x85\x39\x09\x19\x11\xbd\x85\x39\xa7\x59\x1a\x88\xec\x9a\xa4\x8e\x3c\xfc\x38\x53\x10\x8a\xc8\x14\x17\xb1\x90\x0f\x1b\x1a\x18\x99\x81\x82\x1e\x4b\x25\xf8\x26\x00\x60\x8c\x12\xae\xa5\x8d\x4e\xbd\xd8\xf1\xa9\x7a\xc4\x43\x46\x73\x01\x1a\xa5\x9e\xdf\xb2\x25\x4a\x70\xba\x42\x82\x26\x84\x23\x31\x27\x08\x2f\x39\xca\x59\xb6\xa0\x11\x61\xa8\x54\x87\x30\x9a\x62\x1a\x93\x08\x65\x0c\x84\x58\x26\x84\xfc\xc0\xc8\x7f\x0b\xc2\xc5\xb8\x81\x83\xa4\x51\x9e\xd1\x54\x74\x81\x18\x0c\xda\x20\xbe\xc1\xaa\x05\x8b\x41\x31\x16\x6d\x08\x1c\xd4\x71\x44\x05\x37\xcb\x01\xcc\x0c\xd1\x14\xe5\x31\x0e\x09\xca\xa6\x6a\xca\xc5\xbf\xbf\x22\xb3\x2e\x1f\x22\x5e\x84\x73\x84\x01\x77\x2a\x47\x46\x61\x96\xe4\x58\x50\x89\xee\xe2\xf3\xa5\x0f\x2f\xaf\xbb\xa7\x04\x9c\xe0\x7c\xd0\x32\xe3\x6e\xed\x35\x83\x1b\x48\x56\xad\x7c\xc0\xc1\x5d\xb0\x16\x27\x6c\x41\x21\xd4\x2a\x84\x24\x3c\x96\xa4\x52\x9c\x0c\x6b\x46\x99\xd9\x4d\xb0\x39\x66\x82\x0a\x1b\x10\x0d\x76\x81\x39\x0f\xc1\x76\x9e\x81\xa7\xc3\xca\xa1\x6a\xc9\x47\x05\x1f\xcd\xb2\x85\xc4\x23\x3f\x85\x30\x6a\x7d\x72\xf1\xe5\x93\x32\x44\x3b\x82\x67\x05\x03\x33\x10\x17\xa0\x16\x2d\xa9\x98\x37\x71\x46\x29\x0f\x78\x31\x9d\xd2\x9b\x6e\xa0\x09\xbe\xcd\x52\x58\x65\x0c\xbe\xf1\x40\x8e\xb2\x04\x53\x8b\xb7\xa4\x0e\x02\x83\xa6\x21\xcd\x71\x45\xb4\x35\xcd\x31\xc6\x55\x3f\x0e\xd3\x26\xba\x49\xc6\xe7\x01\x4d\x27\x59\x91\x46\x41\x48\x23\x56\x07\x09\x00\x0e\xc7\xea\xdf\xbb\xc3\x6d\x33\xbb\xa3\x26\xa6\x5c\xb4\xc3\xe6\xea\x7b\xdb\xd2\xdf\x33\x46\xd0\xaf\x97\x1f\xbf\x00\xea\x38\xce\x96\xb0\xaf\x20\xbe\x19\xc1\x60\x89\xb4\xee\x3f\x45\x92\x4f\xb2\x9b\x31\xfa\x4a\x04\x6a\xad\x2e\x65\x21\xc8\x49\x92\x8b\x15\xd2\x59\x41\x3d\x92\x9a\x50\x96\xc6\x2b\xa9\x83\x93\x8d\xf6\xe7\x8c\x80\x9b\x02\x09\x39\xa0\xd1\x83\xed\xc1\x29\x9e\x81\x11\x5a\x2b\x92\x13\x9f\xd3\x32\xbc\x80\x44\x85\x27\x34\xa6\x62\x15\x40\x0c\x10\x5e\xcf\x9c\xda\x90\xc6\xfe\x5f\x80\xdd\x7d\x72\xf9\x3c\x63\x22\xe8\x2d\x9e\xd0\x94\x26\x38\xee\x8a\xff\x29\x84\x2f\x69\x73\xf7\x77\xba\x28\xb3\xf0\xbf\x7e\x87\xf0\x4e\xd5\xaf\x90\x04\x08\x4b\x71\x0c\xc1\x3d\x49\x09\x90\x99\x17\x93\x98\x86\xe8\xf2\x33\xa4\x91\x29\xec\x55\x00\x44\x59\x96\x26\x44\xe6\x1a\xb9\x17\xb3\x42\x40\xc6\xfe\x74\xf1\x0d\xe6\xc2\x0e\x4d\xc3\x16\x4b\x11\x65\x24\x14\x19\x0b\x92\x49\xc1\x83\x1c\xcc\xea\x42\x79\x76\x7e\x76\xde\x06\xf9\x19\xa4\x75\x5e\x90\x3e\x43\x70\x16\x62\x41\x46\x00\x42\xbb\xb6\x34\xc0\xac\x80\x20\x18\x52\xa1\x6d\x61\x59\x31\xab\xbb\xbe\x01\x6b\x91\x87\xce\x6e\xdc\x76\x4e\x1e\x99\x3d\x7a\x74\xd6\xd0\x63\x28\x0b\x34\x65\x8f\xb2\x4f\x65\x46\x9a\xc4\x59\x78\x6d\xf3\x4e\xcb\x31\x93\x15\x72\xc3\x0f\xc9\xf0\x1b\xa2\xe5\x9c\xa4\x68\x32\x89\x65\x6e\x4f\x15\x39\x89\xf2\x12\x1a\x8d\xf4\xc4\x11\xa7\xb7\xc0\x1a\x70\x35\x1a\x41\x6a\x85\x3c\x47\xa2\x91\x82\xdc\xe4\x47\xe0\xd9\x03\x0f\xa9\x8b\x34\xcd\x04\x96\x9f\x9c\x73\xca\x86\x0f\x9a\xb2\x2c\x51\x58\xb1\x96\x83\xb3\x38\x8d\x00\x96\x5c\x78\x88\x70\x14\xe9\x3d\x2c\xa7\xc9\x47\xad\x03\x41\x03\x06\x96\x64\x7a\x56\x38\xa5\x14\xac\xfb\xf3\x5d\x42\xd8\x8c\xec\x83\x2d\x63\xad\x0c\x20\xef\x0f\xfe\x96\x2e\x2e\x3f\x0e\x86\x48\x3e\xd6\xfb\xea\xe0\x60\xad\x74\x18\x95\xea\x4c\x0b\x08\xcd\x07\x68\x50\x46\x8d\xfe\xa4\xc3\x35\x97\xd5\x41\xa0\x6c\xbb\x52\x92\xda\x29\xe0\xf3\x19\xa0\x5f\xe2\xd5\x98\xce\x06\xd2\x9b\x10\x5a\x15\x5f\x82\x15\x64\xaf\x03\x9d\x02\xee\xe2\xfb\x84\x13\x02\xf0\x40\xa0\x42\xb8\x1e\x95\x38\x46\x00\xa3\x0d\x57\xc4\xb0\xa1\x18\x5d\xc0\xf2\xba\x44\xd4\xc9\x76\x91\x94\x9e\xc3\xf1\x2c\x63\xe0\xfe\x44\x2e\xfc\xe5\xeb\x85\xf4\x1b\xe3\x38\x98\x48\x22\xe1\xd9\xfb\xc3\x5f\xce\xda\x04\x80\xa6\x20\xc7\x94\xb5\xd4\xc9\x81\x14\x50\xea\x38\xa8\xe1\x0c\xac\x24\xc8\xe9\xa4\x21\xf5\x68\xb9\x06\xcc\xb1\x91\x1d\x57\x82\x41\x06\xec\x72\x3e\xd7\x16\xda\x82\x4c\x15\x19\xda\x14\x5b\x35\x57\x6b\x57\xcf\xd6\x72\xd9\xaa\x56\xae\x44\xaa\x67\x4a\x44\x55\xc6\x36\x92\x8d\x88\x53\x35\x2b\x29\x5d\xae\xd4\xa5\xf4\x33\x18\x86\x71\xa7\xf8\xad\x04\x9c\x87\x5a\xaa\xaa\xc9\x24\x7e\xa4\xaa\x2f\xe7\x47\x4d\x8c\xb3\xec\xba\xc8\xf7\x35\x91\xb6\xa2\x1c\x80\xa8\x0d\x54\xfd\xf4\x40\x01\x03\x25\xf1\xa4\xb7\x92\x78\xe2\x51\x02\x05\x60\x5f\x25\x20\xea\x51\x72\x9d\xf0\xbe\x4a\x40\xd4\xa3\x84\x9f\xa0\xbe\x4a\xf8\x89\x4f\x87\xe8\x0d\x04\x44\x3d\x4a\xc2\x38\x2b\xa2\x25\x16\xe1\x3c\xce\xcc\x3e\xf5\x28\xa9\x8b\x76\xea\x5b\xb7\x37\x55\x99\x2e\x03\x08\xc8\x82\xc9\x7a\x61\x06\x27\x94\x4c\x34\xbe\x01\x19\x34\x21\x54\x28\xc2\x9a\x24\xf3\x85\x3a\xb5\xe4\x53\xb5\x8e\xfc\x44\xa3\xe6\x38\x6c\xc7\x8e\xb4\xd6\x5a\xb8\x3a\xb8\x3a\x56\x2e\xb7\x79\x7d\x0f\x98\x9c\x64\x66\x8e\xcc\xcc\x91\x9e\xd9\x3e\x02\x2e\x4b\x49\x07\xac\xeb\xa4\x1a\xe2\xfb\xe7\x48\x1f\x1e\x95\x30\x41\x6d\x4c\xa7\x24\x5c\x85\x70\xaa\xe9\x9d\x48\x67\x29\x14\xc2\x41\x38\xc7\xe9\x4c\x6d\xe1\xab\x81\xb4\x57\xe5\xee\xf5\x36\xe6\x02\x56\xc4\xc4\x4f\x9f\x1a\x0e\x44\x58\xf2\xd8\x18\x34\xce\x6a\xab\x1d\x7b\xf4\x8d\x15\x37\xf5\x43\xd8\x72\x08\x75\x0a\x40\xd5\x39\x17\x2e\xca\x59\x98\xc5\xb5\x71\x09\x03\xc6\xe4\x49\xab\x0a\x2f\x67\xec\x50\x65\xc3\xfa\x53\x55\x87\x9d\x9e\x9e\x9c\x2a\xe0\xf1\xb4\xb9\x9e\x3a\xca\x1e\x83\x9e\x22\x7a\x15\xf4\x48\x18\xaf\x91\x1e\x1a\x26\xaf\x82\x1f\x85\xc3\x43\xd0\xe8\xc8\xc3\x90\x1a\x90\x15\x65\x50\x56\xb0\x66\xe0\xca\xb9\xe7\x7e\x7f\x14\x9e\xd4\xfd\xcc\x56\x61\xcf\xc1\x18\xd9\x4c\xd8\xe8\x68\xd7\x78\x3a\x7c\x36\xb2\xa0\xb8\xf2\x31\xe4\xe6\xf9\xc7\x20\xaa\x67\x84\x55\x62\xdf\x7e\xfd\xdc\x4d\x9c\x15\x39\x3e\xee\x24\xb0\x3e\xae\x19\x0a\xfa\x87\x80\xb9\x1a\xf6\x3c\x31\x55\xdd\xba\xf3\x69\x29\x67\x6d\x3f\x29\xff\xfa\xcf\xaf\xbf\xa1\x8f\xe5\x45\xf6\x69\x8f\xcb\x2e\x40\xbb\x1e\x95\x43\x59\xb8\x58\x03\x76\x3b\x39\x3b\x68\xb4\xa7\xe6\xa6\x30\xf5\x79\xb1\x43\xdf\x53\x9d\x9a\x9e\x30\x2c\x07\xba\x37\xf2\xcf\x77\xea\x95\x70\x28\xf6\xc3\x2c\x0d\xb1\xd8\x97\xef\x01\x54\x55\xd9\x7a\x0f\x75\xa0\x8b\xc9\xf6\x7b\x3f\xe9\x9e\xef\x0a\x6f\xed\xf5\x99\x5d\xa1\x35\xab\x21\xb8\xee\x9f\x44\x36\x7a\x47\x0d\xaa\xb7\x2c\xf7\xcc\x25\x3b\xf9\x6a\xe7\x3c\xe2\xf5\x5c\x73\x6f\xb6\xdf\x49\x95\xd7\x43\x6f\x82\xd9\x3c\xf1\x49\x33\xcf\x56\xcf\x14\x18\xbf\x51\x77\x9c\xbf\x7f\x7f\xb2\x99\xf7\x52\xe2\x65\x09\x0e\x19\x89\xe6\xc5\xe4\xad\x92\x0c\x1c\x6e\x21\x59\x4b\xbc\x2c\xc9\x32\xbf\xd8\xed\x85\x73\xfa\x46\xd9\x3e\x3e\x85\x9f\x2d\xc5\x4a\x29\xf2\xe2\x7c\xbf\x51\x8a\x0f\x37\xd3\x6b\xef\x68\xbb\xd2\xbb\xb1\xd0\x7d\x70\x92\x8e\x5e\x27\xdd\xde\x4b\xf0\xdb\xa6\xfb\x61\x77\xc3\x5d\x4b\xca\xd7\x79\x2f\xac\xbe\xc6\xe8\x71\x4d\x31\x5f\x34\x6c\xbd\xa9\xfc\xa3\x54\xf9\xa4\x77\x14\x0f\x9a\xe7\xbc\xa6\x98\x6f\x80\xee\x71\x23\xa9\xe5\xe9\xff\xdf\x42\xee\x71\x0b\x31\xe4\xb3\xdd\xdf\x14\x3e\x31\xf9\x27\x27\xe7\xbf\x78\xe8\x2f\x87\x7e\x28\x07\x6c\xbc\xec\xbd\x90\x0b\xca\x5e\x81\xce\xf7\xb3\x7a\xe8\x87\x72\x81\x29\x8a\x5f\x5b\x16\xf2\x16\xba\xd5\xd8\x0f\xe5\x87\xf2\x90\x7f\x02\x2f\xbc\xee\xd7\xca\x25\x8d\xcd\x62\xed\x81\x97\x88\xa7\x7f\xab\xfc\x72\x17\x09\x6f\xf9\xf8\x08\x8c\xdf\xff\x1e\xf1\xf4\x8c\xbf\xdc\x5d\x62\x07\xc6\x55\x1f\x94\xbd\x3a\x94\x9f\xee\xea\xd5\x6c\x57\x31\xeb\xee\x28\x2d\xa0\x12\x91\x52\xa0\x92\x97\xe9\x28\x1b\xa2\xf3\x21\x3a\x3c\x78\x84\x77\xf4\x1a\x5c\x77\x9b\x12\x98\x28\x60\xff\xc9\x66\x2d\x63\x4c\xed\xd1\xdd\xe6\xfe\x80\x07\x42\x53\x4b\x8d\xf4\x52\x1b\xf0\x79\x91\x41\x55\x2e\x68\xaa\x9a\xc3\x82\x3a\xad\x4e\x33\x2c\x42\x65\x63\x95\x13\xdc\x4d\xf7\x34\x7b\xb0\x8c\xaf\x9c\x15\xdd\xd9\x36\x7e\x9c\xf1\x71\x13\xa2\x27\x72\x5c\x95\x98\xf3\x2c\xa4\x58\x37\x47\x0f\xf4\x88\x13\x50\xe6\x94\xd0\x2d\x81\xb5\x2b\x52\x15\x83\x63\x67\xc2\xb8\x1b\xf6\x43\xe0\xda\x40\x6f\x74\x28\xf2\x5a\x6f\x49\xf7\xab\xee\x98\xa4\x33\x31\x57\x51\xdd\x6e\x72\x3d\x58\x37\xef\x7e\x1e\xdf\x74\x6f\x9d\x8d\xab\x75\x76\x53\x1e\xa0\x3f\xa3\x43\xf4\x17\x44\x62\x22\x3b\x07\x4d\xd5\xe0\x95\x1f\xaa\x4e\xe0\xfd\x01\x44\xe6\x50\xdb\x09\x72\x11\xb9\x39\x40\x1f\x90\x77\xcf\xbe\xaf\x89\xfe\xf1\x48\x5b\xd9\xb2\xde\xc5\x6d\xf0\x74\xd3\x54\x5f\x5a\x69\x83\x5d\x15\x94\x3d\x6f\x34\x0f\x20\xf4\x63\x5c\xa4\xe1\xdc\x69\x22\xd3\x6d\xbc\x8f\xd4\x0d\xa3\xcc\x94\xc5\x96\x85\xb1\xde\xe5\x06\x5d\xf9\x4c\x2e\xd2\x32\xd0\x77\x9b\xae\x27\x26\xeb\xa0\xe7\x49\x4e\xd6\xf8\x9e\x09\x6a\xc3\x76\xee\xb5\x6d\xfa\xee\x96\xae\x4c\x60\xc2\xc7\xc9\x08\xcd\x35\xc7\x7f\x80\xcd\xdd\x11\x48\x7d\xd2\x44\x17\xf3\x36\x55\x38\x6d\xb3\xf6\x9d\x7b\xe3\xf5\x90\xca\xf3\x35\x76\xe5\xca\x56\xab\xea\x9b\x47\xdb\x13\x5b\x15\x45\xf5\xf9\xb3\x65\x83\x45\x29\x38\xcf\x60\xdf\xba\x89\xbd\x5c\x68\x88\xca\xfd\x68\x6a\x71\x3b\x4a\xf3\x5e\xd3\x4f\xf5\x74\x6b\xab\x3b\xbf\xc7\xf4\xb3\xce\x08\xba\x4e\xca\x3f\xe3\x1a\xd8\xdf\xee\x54\xe3\xa7\xf2\x8b\xec\x6a\x65\x65\x03\xf4\xc3\xfb\x80\x61\x85\x91\x5c\xc1\xc4\x32\xf8\x34\x2f\x44\xd5\x3e\x68\x7a\x73\xcb\xbd\x85\xe3\x82\x54\x6e\x31\x1d\xbd\x55\xe7\xad\x11\xaf\x2b\xab\xf5\x12\x57\x7a\xac\x8b\xfc\x8d\xbc\xd5\xc3\x20\x27\x49\xd9\x8e\x9b\x72\x2a\xe8\x82\x38\xad\x51\x66\x21\x72\x63\xe9\xef\x04\x4c\xa8\xbd\x38\xa9\xdf\x6d\xd2\xac\xe3\x35\x22\x05\x8b\x77\x54\xf3\xe1\xf8\xb8\x4e\xa3\xfd\xe2\x29\x8a\xaa\x5b\x9e\x55\x37\x17\x22\xe7\x1f\xde\xbd\xdb\xae\x56\x5e\x7c\x6b\x9a\x37\xb6\x59\x36\xf0\xee\xf8\x82\xdc\x2c\xe1\xed\x4b\xd9\xac\x7e\x53\xcd\xdc\x64\x78\x77\xed\xcd\xef\xaa\x9a\x1a\x3d\x1d\xb1\x0d\xe6\xaf\xb6\x2b\xff\xde\xe9\xc7\x07\xa9\xf7\x31\x53\x5b\xca\xa6\xf4\x6e\x36\x3c\x25\x9e\xa3\x00\xdf\xf6\x9d\xd9\x3a\x55\xea\x8a\xf4\xb9\xd9\x52\xd6\xee\x08\x36\x13\xdc\x3f\xf3\x74\x26\xd4\xfa\xdc\x1d\xf1\x32\xbb\x05\x98\xa5\xdd\xe9\x45\x8f\x8f\xcd\xff\x20\xb7\xee\xde\x03\xf8\x36\xb0\xb4\x05\x90\xed\x72\xf9\x97\x36\x4d\x95\x7b\x3f\x21\x74\x4b\x73\x95\x0c\x6b\x94\x74\x9c\x8e\x1d\xcc\x0c\xd1\xd6\x59\x92\x8f\x83\xbd\x9f\xb6\x82\x54\x67\xd7\xcb\xc1\x74\x8f\xce\x16\xdc\xda\xb1\xed\xf1\x7d\x4d\xc6\x63\x6d\xf5\x37\x50\xad\xe9\x35\x19\xcf\xf4\xd9\x72\xdb\xe4\xd9\xd2\x93\x00\x9c\x33\xd8\xa3\xa3\xe3\xb4\xf7\x90\xd0\x43\x59\xd7\xd9\xaf\xb4\xfd\x0f\xc7\x4c\x1f\x1a\x9b\x3d\x00\x00")
 
 func templatesBaseTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -91,12 +149,32 @@ func templatesBaseTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/base.tf", size: 14861, mode: os.FileMode(480), modTime: time.Unix(1519254147, 0)}
+	info := bindataFileInfo{name: "templates/base.tf", size: 15771, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesCf_dnsTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x94\xc1\x6a\xe3\x30\x10\x86\xef\x7e\x8a\x41\xec\x69\x21\x26\x10\xf6\x98\x43\x58\xf6\xb8\x79\x81\x65\x11\xb2\x34\xb5\x55\x64\x49\x68\x24\xa7\x69\xf0\xbb\x17\x59\x2e\x4d\x4a\x5b\x1c\x9a\xdc\x6c\x31\xf3\xff\xff\x37\x83\x34\x88\xa0\x45\x63\x10\x18\x1d\x29\x62\xcf\x95\xeb\x85\xb6\x0c\x4e\x15\x40\x3c\x7a\x84\x2d\x30\x8a\x41\xdb\x96\x55\x63\x55\x05\x24\x97\x82\x44\x60\xe2\x40\x3c\xb8\x14\xf1\xd7\x86\x3f\x3b\x8b\x0c\x18\xda\x81\x2b\x4b\xf3\x6f\x56\xb0\xa2\x9f\x14\x7e\x9c\x06\x11\xea\x0b\x8b\x91\x55\xd9\x42\xb4\x34\x55\x02\xec\x2f\x6a\xb3\x96\x56\xe3\xaa\x73\x14\x51\xad\x26\xc9\x0a\x60\xcc\x21\x5c\x8a\x3e\xc5\x4b\x3f\x9e\xad\x38\x61\x18\x30\x50\x31\x1f\x84\x49\xb3\xe2\xfb\xb0\xf5\x79\x6b\x7d\xde\x3a\x7e\x81\x19\x50\xba\xa0\x18\xb0\x83\x36\x4a\x8a\xa0\xb2\x44\xf1\x9a\x22\x68\xb5\xc4\x4d\xab\x91\xbd\x8e\x06\x20\x77\xfc\xac\x3f\x9e\xcf\xbc\x81\x52\xf4\x7b\xbf\xfb\xfb\x67\x3a\x8b\x06\xca\xd9\x66\xbd\xce\x33\x2c\xb1\x08\xb6\xf0\x6f\x36\x47\xd3\xd4\xf2\xa1\x64\x08\xdc\x34\x75\x36\xcf\x86\x23\xfb\xbf\x00\x8f\xa8\xbb\x01\x15\x51\x77\x27\x2e\xa2\xee\x7a\xa8\xc6\xdd\x84\x2a\xcb\x2c\xc1\xda\x2d\x45\xd2\xbe\x7e\x4c\xbd\x6f\xdc\xd3\xf4\xed\x53\x63\xb4\xe4\xda\x2f\xa3\x8a\xd2\xdf\x00\x2a\x4a\x7f\xa7\x55\x45\xe9\xaf\x5f\x95\x26\x57\xa0\xa4\x4b\x36\xbe\xbd\x09\x9a\x9c\x11\x51\x3b\xcb\x09\xdb\x1e\x6d\xa4\xf2\x88\x7c\xf3\xf2\x69\x72\x2b\xc2\xf6\x1e\x13\xd0\xe4\x3e\xbd\x85\x2f\x01\x00\x00\xff\xff\x9c\x64\x07\x0b\x7b\x05\x00\x00")
+var _templatesBosh_liteTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x90\xc1\x4e\xc3\x30\x0c\x86\xef\x7d\x8a\x28\xe2\x5c\xaa\xb1\x03\x17\x9e\x04\xa1\x28\x4d\xb2\x2d\x22\xd4\x91\xed\x0c\xaa\xa9\xef\x8e\xb3\xa1\xd1\x8d\xed\x82\x90\x98\x73\xfb\x3f\xe9\xb7\xf3\x6d\x2d\x46\xdb\xa7\xa0\x74\x0f\xb4\x31\x29\x72\x30\x71\xe8\xa1\x0c\xde\xb8\xe8\x51\xab\x5d\xa3\x14\x8f\x39\x28\x99\x27\xa5\x89\x31\x0e\x6b\x2d\xa1\x0f\x2b\x5b\x12\xd7\xb0\x6b\xf7\xef\xbe\xd3\xcd\xd4\x34\x18\x08\x0a\x3a\xe9\xb4\xef\x64\x28\xb8\x82\x91\x47\xb3\x46\x28\xd9\x60\x49\x41\xcf\xb7\x5d\xe0\x66\xc3\x9c\x0f\x9b\xcf\x68\xf4\x75\xdd\xdd\xee\x67\x71\xbb\x6f\x3c\xcb\xa2\x9f\xf4\xec\xfc\xef\x91\x12\xf9\x85\x1c\x4a\x95\x67\x04\x06\x07\xe9\x84\xb3\xcb\x95\xad\x10\xde\x4c\x06\xe4\x19\x7b\xec\x6a\x29\x9c\xc6\x47\x50\xb5\x99\x3e\x81\x7b\xa5\x23\x78\x96\x9b\xb7\x16\xdb\xcb\x92\x27\xfd\xf2\x77\xde\xe8\x86\xc5\x2d\x97\x0f\x57\xcc\x1d\xc8\x3f\xaa\x23\x81\xf2\x9d\x8f\xf1\x86\xf5\x2d\x64\xae\xf8\xfb\x42\xbf\x15\xf8\x09\x59\x70\xb1\x4a\x07\x04\x00\x00")
+
+func templatesBosh_liteTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesBosh_liteTf,
+		"templates/bosh_lite.tf",
+	)
+}
+
+func templatesBosh_liteTf() (*asset, error) {
+	bytes, err := templatesBosh_liteTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/bosh_lite.tf", size: 1031, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCf_dnsTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xe5\x55\xc1\x6a\xe3\x30\x10\xbd\xfb\x2b\x06\x93\xd3\x42\x4d\x4a\xe9\x65\xa1\x2c\xfb\x05\xfd\x80\x52\xc4\x58\x9e\x24\x5a\xc6\x96\x90\xe4\xa4\x69\xf0\xbf\x77\x24\x3b\x6d\xba\xdd\x43\xf0\xe2\x53\x7d\xb1\x35\x9a\xf7\xe6\xcd\x1b\x21\xef\xd1\x1b\xac\x99\xa0\x0c\xc7\x10\xa9\x55\x8d\x6d\xd1\x74\x25\x9c\x0a\x80\x78\x74\x04\x0f\xb2\x15\xbd\xe9\xb6\x65\x31\x14\xc5\xfe\x3d\x1f\x9d\x0b\x5f\xb3\xa7\xe7\x02\x04\xd0\xd0\x06\x7b\x8e\xe7\x8d\x31\x14\xb4\x37\x2e\x1a\xdb\xa5\xd0\x63\xfe\x42\xe6\x23\x78\xdb\x47\x02\x84\x83\xe1\x46\xa3\x6f\xc0\x93\xb6\xf2\xb2\x1b\x88\x3b\xd9\x90\xaa\x30\x56\x85\x68\x73\x28\x23\x3c\x60\x80\x03\x31\x57\x59\xa6\xa7\x60\x7b\xaf\x93\xcc\x43\x50\x39\xe3\xfe\x4e\x8d\x54\x25\x94\x67\x72\xd5\x74\x61\x14\xff\x6a\x3b\x52\xa6\x49\x62\x56\x27\xb6\x1a\xb9\x92\x3d\x35\x85\x87\xa4\xb9\xc3\x96\xa6\x16\x7e\x54\xab\x93\x38\x51\x7d\xf2\x2c\x27\x9d\x4d\x90\xa4\xdf\x65\x21\x01\x64\x23\xca\x52\x89\x0f\x86\xbf\x9e\x5c\x33\xe9\x24\xae\x2b\xbd\x19\xe5\x7a\x25\x8b\x24\x21\x81\x32\xf5\x87\xc8\xab\xf1\x97\xf2\x01\x68\x8f\xdc\x63\x24\x15\xd1\x6f\x29\xaa\x1d\x21\xc7\x9d\xc0\x37\xc8\x81\x24\x65\xb8\xc2\xba\x3c\xf6\xaf\xfe\x69\xdb\x77\x71\x54\x92\x8c\xb9\x38\x1c\xf0\x90\x66\x0e\xbf\x60\x0d\x3f\xe1\x76\xc8\xa6\xcc\x73\xfb\x82\xf4\x7b\x78\x1d\xc2\x6e\xce\xe9\x14\xd8\xa2\xe7\x53\xf8\xe7\x1b\x36\x81\x17\x70\xab\xb6\xf3\xec\x4a\xb8\x2b\xfd\x92\x75\xe4\xa9\xad\xbb\xf5\x3a\xf9\x37\x96\x0f\x12\x78\x3a\x37\x6a\x5c\xf5\xa7\x6f\x5d\x6d\x5f\xf2\xb7\xeb\x6b\x36\x5a\x19\x37\x94\xcf\x57\xb4\x11\xb5\x9b\xd3\x85\xc0\x16\x1d\xba\xf0\xcf\x1f\xfa\x04\x5e\x60\xe8\x26\xd8\x7f\x5e\x40\x12\x67\x4c\x3f\x15\x15\x68\xdb\x52\x17\xc3\xbc\x9b\x47\x78\x6e\x84\x61\x39\x6b\xa5\xc0\xff\x5d\x42\x9f\x09\x66\x58\xfc\x06\x7f\xf7\xe4\xd7\x03\x08\x00\x00")
 
 func templatesCf_dnsTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -111,12 +189,72 @@ func templatesCf_dnsTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/cf_dns.tf", size: 1403, mode: os.FileMode(480), modTime: time.Unix(1519251839, 0)}
+	info := bindataFileInfo{name: "templates/cf_dns.tf", size: 2051, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesCf_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\xdc\x5b\x8f\x9b\x46\x14\x07\xf0\x77\x7f\x0a\x64\xf5\xa9\xd2\xba\x1e\xee\x54\xda\xa7\x95\xaa\xf6\xa5\x8a\x9a\xbc\x55\x15\xc2\x78\xd6\x46\x61\xc1\x9a\x19\x6f\x95\x46\xfe\xee\x15\x37\x5f\x62\x0c\xf6\xdf\xff\x24\x9b\xcd\x53\x80\x33\x73\x18\x0e\x3f\x8e\xad\x65\x95\xd4\xe5\x56\xa5\xd2\x9a\x26\xff\xea\x58\xcb\x74\xab\x32\xf3\x29\x5e\xa9\x72\xbb\x99\x5a\xd3\xf4\x39\xd6\x7a\x1d\xe7\x8b\xb3\x5d\x9f\x27\x96\x55\x24\x2f\xd2\x6a\x7f\x1e\xad\xe9\x4f\x9f\x5f\x13\x35\x93\xc5\x6b\x9c\x2d\x77\x0f\xe9\xf3\x83\xd6\xeb\x87\x7c\xf1\xd0\x85\x3e\x34\xa1\x13\xcb\x5a\x4a\x9d\xaa\x6c\x63\xb2\xb2\xa8\x02\x9f\x7e\xb3\xde\xbf\xff\xbd\xda\xf1\xba\x49\xe3\x6c\x79\x34\x62\x5e\xa6\x49\x3e\x6b\x36\xef\xa6\x93\x89\x65\x65\xc5\x4a\x49\xad\xeb\x04\x2c\x2b\xcd\x96\x2a\x5e\xe4\x65\xfa\x51\x5b\x8f\xd6\xdf\xd3\xf9\xac\xfe\xf7\xcb\x7c\xfa\x4f\xbd\x7f\xa3\x4a\x53\xa6\x65\xde\x0e\x68\xd2\x7a\x7e\xcb\x7a\x56\xe5\x4b\xbc\x29\x95\xa9\xb7\xdb\xb6\x6d\xd7\x9b\x4d\xd9\x6d\x3c\xda\xbc\xab\xa6\x95\xc7\xb3\x9e\x46\xcf\x7b\x42\xe7\x7d\xb3\x3f\x88\xe9\x15\x49\xd7\xd3\x99\x64\xd5\x4d\xf6\x67\xb5\xca\x37\x2d\x6f\x3d\x42\x9e\x3d\xcb\xf4\x53\x9a\xcb\x76\x98\x6c\x55\x94\x4a\xc6\xe9\x3a\x29\x56\xb2\x99\xb7\xba\x7e\xed\x94\xbb\xc9\xa4\xdc\x9a\xcd\xd6\x8c\x5d\xf3\xd7\x24\xdf\xb6\xe9\x9c\x57\xcc\xec\x52\xec\xac\xbe\x7a\xbb\xc9\xe4\xea\x7a\xcb\x0a\x23\x55\x91\xe4\xf7\x14\x5e\x37\xc6\xb5\x15\x68\xfd\xd1\x06\x40\xa5\x78\x9a\x68\xb3\xc2\xb7\x2f\xd2\x79\xd9\x0e\x95\xae\x75\xb9\x7c\x7f\xa4\x12\x1e\xb8\x50\xac\x5a\x1e\xac\xa7\x6b\x8b\xfa\xc2\x20\x17\xaa\x5b\xe6\x8b\xe3\x92\x3e\x2f\xdd\xd3\x9f\xfd\xfa\xe8\x75\xa9\x4c\x7c\xb6\x4a\xd5\x6a\xa4\xaa\xd4\x3a\xfe\xaf\x2c\x64\x9c\x97\xc9\x32\x5e\x24\x79\x52\xa4\x59\xb1\xb2\x1e\x2d\xa3\xb6\xb2\x5a\xac\xb5\x4c\x72\xb3\x8e\xd3\xb5\x4c\x3f\xb6\xeb\xd5\x6c\xfa\x14\x9b\xb5\x92\x7a\x5d\xe6\xcb\x7a\x3a\xaf\xde\xb7\x2d\xce\xf7\x3e\x5a\x4d\x3d\xd5\xe7\xfb\x9a\xe4\xa7\x69\xfa\x4d\xb1\x24\x6a\x25\xcd\xd9\x29\x7c\x78\x7a\xf7\x6b\x55\x74\x4d\x99\x98\xec\x45\x96\x5b\xf3\xc5\x41\xf6\xe1\xba\x6a\x23\x0b\xa9\xba\xcb\x5a\x68\x93\x14\xa9\xec\x31\xf8\x78\x67\x57\x91\xc7\x37\x45\xbe\x38\xad\xfc\x93\xd0\x6a\xe7\xe9\x0d\x75\x08\xad\xf3\xe0\xdd\xba\x7a\xbb\x28\xa4\xd1\x47\x59\xec\x47\xaa\xf7\xcc\xaa\xd0\xe6\x98\xd9\xcf\x6d\x54\x6f\xbd\xd6\xf5\xdc\x57\x9c\x32\x5f\x1c\xd2\x98\x55\x87\x35\xb5\x77\x3e\xc4\x56\xe5\x57\x8c\xb0\x2c\x74\x7c\x18\x65\xdc\x67\x55\x6e\x8d\x54\x68\x4b\xd0\x44\x5f\xdb\x15\xfc\x55\x1f\xfd\x1d\x1b\x83\xb0\x0f\xc6\x7a\xe3\xee\x6b\x4d\xe9\xba\x4e\xcf\x9c\xcd\xd6\xaf\x38\xe9\x85\x59\x0f\xd3\xbe\xc1\xa7\xc7\x50\x31\xdd\xfd\xdc\x18\xae\xf3\xd1\x27\xc6\xa5\xf0\x1b\x3a\xa1\xc3\x10\x77\x35\x43\x87\x55\xba\xa9\x1f\x6a\xee\xbd\x6f\xd7\x12\x0d\x2e\x18\xd2\x15\xf5\xdc\xbb\x5f\xde\xbf\x6f\xba\xa6\xbf\x66\x53\x74\x65\x69\xdd\x50\xe5\x60\x6b\xb4\x1f\x00\xef\x8e\xf6\x2b\xf6\x66\x1a\x24\x61\x8f\x75\x48\xe1\x9c\xd5\x1f\xb5\x55\xde\xdb\x1d\xad\x8d\x19\x68\x8f\xda\xc8\xde\xe6\xa8\x8b\xbc\x2e\x8b\xa1\x34\xc6\xf2\x38\x7a\xe2\x9d\x67\xd2\x05\xeb\x26\x5a\xeb\x3c\x4e\xa5\x32\xd9\x73\x96\x26\x46\x56\x16\xed\x6b\x33\x4b\x5e\x62\x2d\xd5\xab\x54\xc7\x87\x54\xed\x56\xf5\xdf\x59\xa2\x8a\x1d\xef\x84\x06\xda\xce\xe3\x87\x69\xff\x09\x69\x9d\x73\x4f\x87\xaa\xec\xfd\x0d\xec\x61\x8a\xb1\x1e\x76\x7f\x64\x7f\x1b\x7b\x18\x68\xa4\x93\x3d\x8c\x73\x6b\x33\x6b\xd2\x0d\xda\xc9\x9a\x74\x73\x6d\x1b\xfb\xe1\xe9\xdd\x77\xec\x61\xc5\xdc\x76\x7b\x1e\x66\x42\xd8\x6f\xb9\xb7\xbb\xb8\xbc\x77\x3f\xfb\x06\xae\xf9\xe8\xf3\xae\x37\xf6\x86\x96\xae\x8d\xbf\xab\x9f\x6b\x57\xe6\xa6\x66\xee\xc3\xd3\xbb\x6f\xd7\xc9\x5d\x5e\x24\xa4\x8d\xeb\x2d\xdf\xf3\x12\x7e\x2b\xe9\xfe\x98\x5d\xe7\x78\x4d\xb1\x6e\xbb\xfb\xfa\xcd\xe1\x41\xc6\x9a\xcd\x26\x1a\xef\x34\x9b\x55\xa2\xb7\x99\xfe\x40\x9b\xe9\x0c\xb4\x99\xde\x7d\x5d\xa6\x73\x43\x97\xb9\xbf\x09\x6f\xff\x16\x6e\x1f\x3a\xfa\x2d\xdc\x75\x79\x78\x78\x1e\x1e\x33\x0f\x1f\xcf\xc3\x67\xe6\x11\xe0\x79\x04\xcc\x3c\x42\x3c\x8f\x90\x99\x47\x84\xe7\x11\x11\xf3\x70\x06\x3e\x97\x8d\xe4\xe1\x0c\x7c\x30\xbb\x3d\x0f\x81\xe7\x21\x98\x79\xa0\xdf\xe2\xef\x43\x49\x79\x38\x78\x1e\x97\x3e\xd5\x41\x79\xe0\x9e\x3a\x4c\x4f\x1d\xdc\x53\x87\xe9\xa9\x83\x7b\xea\x30\x3d\x75\x70\x4f\x1d\xa6\xa7\x0e\xee\xa9\xc3\xf4\xd4\xc1\x3d\x75\x98\x9e\xba\xb8\xa7\x2e\xd3\x53\x17\xf7\xd4\x65\x7a\xea\xe2\x9e\xba\x4c\x4f\x5d\xdc\xd3\x8b\xdf\x92\x41\x79\xe0\x9e\xba\x4c\x4f\x5d\xdc\x53\x97\xe9\xa9\x8b\x7b\xea\x32\x3d\x75\x71\x4f\x5d\xa6\xa7\x2e\xee\xa9\xcb\xf4\xd4\xc5\x3d\x75\x99\x9e\x7a\xb8\xa7\x1e\xd3\x53\x0f\xf7\xd4\x63\x7a\xea\xe1\x9e\x7a\x4c\x4f\x3d\xdc\x53\x8f\xe9\xa9\x87\x7b\xea\x31\x3d\xf5\x70\x4f\x3d\xa6\xa7\x1e\xee\xa9\xc7\xf4\xd4\xc3\x3d\xf5\x98\x9e\x7a\xb8\xa7\x1e\xd3\x53\x0f\xf7\xd4\x63\x7a\xea\xe3\x9e\xfa\x4c\x4f\x7d\xdc\x53\x9f\xe9\xa9\x8f\x7b\xea\x33\x3d\xf5\x71\x4f\x7d\xa6\xa7\x3e\xee\xa9\xcf\xf4\xd4\xc7\x3d\xf5\x99\x9e\xfa\xb8\xa7\x3e\xd3\x53\x1f\xf7\xd4\x67\x7a\xea\xe3\x9e\xfa\x4c\x4f\x7d\xdc\x53\x9f\xe9\x69\x80\x7b\x1a\x30\x3d\x0d\x70\x4f\x03\xa6\xa7\x01\xee\x69\xc0\xf4\x34\xc0\x3d\x0d\x98\x9e\x06\xb8\xa7\x01\xd3\xd3\x00\xf7\x34\x60\x7a\x1a\xe0\x9e\x06\x4c\x4f\x03\xdc\xd3\x80\xe9\x69\x80\x7b\x1a\x30\x3d\x0d\x70\x4f\x03\xa6\xa7\x43\xbf\x27\x38\x92\xc7\xd0\x2f\x0a\xde\x9e\x07\xee\x69\xc8\xf4\x34\xc4\x3d\x0d\x99\x9e\x86\xb8\xa7\x21\xd3\xd3\x10\xf7\x34\x64\x7a\x1a\xe2\x9e\x86\x4c\x4f\x43\xdc\xd3\x90\xe9\x69\x88\x7b\x1a\x32\x3d\x0d\x71\x4f\x43\xa6\xa7\x21\xee\x69\xc8\xf4\x34\xc2\x3d\x8d\x98\x9e\x46\xb8\xa7\x11\xd3\xd3\x08\xf7\x34\x62\x7a\x1a\xe1\x9e\x46\x4c\x4f\x23\xdc\xd3\x88\xe9\x69\x84\x7b\x1a\x31\x3d\x8d\x70\x4f\x23\xa6\xa7\x11\xee\x69\xc4\xf4\x34\xc2\x3d\x8d\x98\x9e\x46\xb8\xa7\x11\xd1\x53\x31\x87\x3d\xed\x42\x49\x79\xc0\x9e\x76\xa1\xa4\x3c\x60\x4f\xbb\x50\x52\x1e\xb0\xa7\x5d\x28\x29\x0f\xd8\xd3\x2e\x94\x94\x07\xec\x69\x17\x4a\xca\x03\xf6\xb4\x0b\x25\xe5\x01\x7b\xda\x85\x92\xf2\x80\x3d\xed\x42\x49\x79\xc0\x9e\x76\xa1\x9c\x3c\x04\xee\xa9\x60\x7a\x2a\x70\x4f\x05\xd3\x53\x81\x7b\x2a\x98\x9e\x0a\xdc\x53\xc1\xf4\x54\xe0\x9e\x0a\xa6\xa7\x02\xf7\x54\x30\x3d\x15\xb8\xa7\x82\xe9\xa9\xc0\x3d\x15\x4c\x4f\x05\xee\xa9\x60\x7a\x2a\x70\x4f\x05\xd3\x53\x1b\xf7\xd4\x66\x7a\x6a\xe3\x9e\xda\x4c\x4f\xe1\xbf\xca\xb3\x0f\x25\xe5\x81\x7b\x6a\x5f\xe9\x29\xef\x65\xc2\xfb\x5f\xae\x6e\xc7\x1f\x7b\xb3\xba\x39\xac\xff\xb5\xea\x76\x88\x91\x77\xaa\xdb\x11\x4e\x5e\xa8\xfe\x3f\x00\x00\xff\xff\x59\xb1\x38\x54\x2d\x50\x00\x00")
+var _templatesCf_dns_existing_zoneTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x4f\xbb\x6e\xc3\x30\x0c\xdc\xfd\x15\x84\xd0\xb1\xd0\x52\x74\xec\xd8\xef\x10\x68\x89\x8d\x05\xc8\x92\x41\xd2\x4a\xd3\x20\xff\x1e\x59\x4e\x01\xa7\x53\x35\x9d\x78\xe4\x3d\x2a\x72\xc4\x31\x11\x98\x90\xc5\xfd\x94\x4c\x2e\x06\x03\xd7\x01\x40\x2f\x0b\xc1\xe3\x7d\x80\x11\xe5\x98\x4f\xa6\x11\x81\xc4\x73\x5c\x34\x96\xbc\x11\x9f\xdf\x51\xb4\x51\x30\x15\x51\x0a\xb0\x89\x80\x4e\xa8\x6d\x90\x82\x34\x48\xc0\xe4\x0b\x37\x5c\xbe\xfa\x57\x2e\x6d\x73\x86\x50\x66\x8c\xf9\x15\xce\x53\xf4\x13\x8c\x63\x82\x4c\xb1\xf1\x0c\x9e\x09\x95\x04\x72\xe1\x66\x97\xa8\x61\x6b\x86\xdb\x30\x04\x54\x04\x83\x67\x71\x5c\x56\xa5\xf7\xb7\x9e\xd9\x80\xa1\x5c\xdd\x6f\x85\x3d\xff\xa3\xcc\x16\xf1\xe5\x5a\x91\xed\xa1\xe1\xad\x8b\xa5\xe2\x31\x49\x5f\x3e\x70\xfb\xc1\x66\x64\xff\xfa\xd8\xa3\x8b\x7d\xd2\x6a\x5b\xcb\xaa\xcf\x39\x5c\xc6\x99\x9c\x10\x57\x62\xd9\x43\x55\x4c\x2b\xfd\xdb\xe1\x78\xdf\x6d\xee\xb6\x72\x6b\xdc\xaf\x01\x00\x00")
+
+func templatesCf_dns_existing_zoneTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesCf_dns_existing_zoneTf,
+		"templates/cf_dns_existing_zone.tf",
+	)
+}
+
+func templatesCf_dns_existing_zoneTf() (*asset, error) {
+	bytes, err := templatesCf_dns_existing_zoneTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cf_dns_existing_zone.tf", size: 431, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCf_dns_zoneTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x8f\x41\x0a\xc3\x20\x10\x45\xf7\x9e\x42\xa4\x8b\x04\x1a\x37\xa5\xcb\x5e\xa1\x57\x90\x21\x0e\x69\x20\x6a\x70\x34\xa5\x0d\xb9\x7b\x8d\x26\x90\x76\xd5\xa5\xf2\xe6\xfd\xff\x3d\x92\x8b\xbe\x45\x2e\xe0\x49\xca\xbb\x18\xf0\x7a\x51\x6f\x67\x51\x70\x81\x76\x52\xda\xd2\xf6\x9c\x19\xe7\x16\x0c\xf2\x1b\x17\xa7\x79\x02\x2f\xe9\x45\x01\x8d\xd2\xce\x40\x6f\x17\xc1\x12\x10\xa0\xa3\x02\x18\xf4\x1d\x56\x83\x6b\x61\x90\xeb\xef\x99\x1b\x18\x2b\x71\x4f\x06\x71\xde\x0d\x6b\x42\xaf\x97\xe6\xe1\x92\x49\x37\x39\xa8\xae\x93\x6a\x61\x2c\x9f\x52\x8e\xdd\x4b\x24\xb6\xc8\x7f\xcb\xca\x63\x55\xb9\xa1\x45\x93\xa8\x31\x86\xef\x31\x6a\xdd\xa1\x08\xfd\x84\x9e\xca\xb2\x09\x86\x88\xff\xc8\x8f\xa7\x39\xe1\x03\xb8\x2b\xec\x0d\x43\x01\x00\x00")
+
+func templatesCf_dns_zoneTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesCf_dns_zoneTf,
+		"templates/cf_dns_zone.tf",
+	)
+}
+
+func templatesCf_dns_zoneTf() (*asset, error) {
+	bytes, err := templatesCf_dns_zoneTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cf_dns_zone.tf", size: 323, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCf_elbv2_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xe5\x58\xdd\x6f\xdb\x36\x10\x7f\xef\x5f\x41\x08\x7b\x68\x87\xd8\x75\xb2\x76\xc8\x4b\x5f\x5a\x6c\xe8\x80\xa1\x08\xe6\xbc\x05\x01\x41\xcb\xb4\x25\x84\x16\x05\x92\x72\x6a\x14\xfe\xdf\x77\xfc\x90\x44\x49\x94\x2c\xbb\x5e\x0a\xaf\xe9\x43\x13\xf1\x7e\xc7\xfb\x3e\xde\x09\x2a\x79\x21\x62\x8a\x22\xf2\x2c\xb1\xa4\x71\x21\x52\xb5\xc3\x6b\xc1\x8b\x3c\x42\x51\xbc\xc2\x52\x26\x98\x2d\x70\x9a\x29\x2a\x32\xc2\x3a\x34\xdf\x5e\x21\x94\x91\x0d\x45\xee\xe7\x03\x8a\x7e\xf9\xb6\x25\x62\x4a\xb3\x2d\x4e\x97\xfb\x49\xbc\x9a\x00\x8f\x09\x5b\x4c\x4a\x1e\x93\x92\xc7\xc4\xf2\x00\x0e\x4b\x2a\x63\x91\xe6\x2a\xe5\x99\xe6\xf0\xe9\x4f\x34\x9f\x7f\x46\x7f\x39\x80\xa6\xd8\xe6\x31\xb0\xf3\xee\x60\x3c\x26\x6c\x6a\x3f\xef\xa3\x57\x40\x92\x66\x6b\x41\xa5\x34\x22\x21\x14\xa7\x4b\x81\x17\x40\xf5\x24\x1d\xe8\xc1\x49\x66\xd4\x59\xf0\x22\x5b\x62\x4d\x24\xf7\xd1\xa3\x41\xe4\x82\xae\xd2\xaf\x98\xa5\x52\x01\x4f\x19\x46\xb4\x88\x6a\x2c\x57\x3c\xe6\xcc\x33\x83\x8a\x8d\x6a\x08\xad\x04\xdf\xe0\x9c\x0b\x55\x9d\xdd\xc0\x8f\x39\x52\xdc\x3f\xf0\x8e\xf6\x5a\x21\xea\xeb\xe3\x73\xf9\x80\x66\x1d\x78\xf9\xcd\x97\x04\xa4\x98\x5c\x47\x1d\x73\x68\xc5\x66\x53\xf3\xef\xed\xcc\x28\x60\xae\x53\x64\x2d\xad\x6d\x37\x54\xac\xe9\x6b\x6b\x61\xfd\xf5\x0a\x6d\x48\xfe\x3a\xfa\x02\x7e\x8e\xae\x8e\x77\xf0\x9b\x37\xd6\x41\x2c\x5d\xd1\x78\x17\x33\xea\x54\x4a\xd7\x19\x17\x14\xc7\x09\xc9\xd6\xd4\x8a\xa5\x43\xc9\x49\x04\x32\xf1\x42\xe5\x85\x1a\x1d\x87\x5b\xc2\x0a\x6a\x35\xe8\x86\xf3\xf4\x20\x93\xa9\x09\x24\xb8\x56\x34\xb2\x82\x2d\xfc\x4c\xe8\x46\x7c\x3b\xf0\x65\x02\x2e\xc1\x2d\xeb\x64\x80\xd4\x16\xe0\x64\x89\x17\x84\x91\x2c\xa6\x02\xab\x5d\x6e\xc4\xcd\xa8\x7a\xe6\xe2\x49\x13\xc8\x62\x01\x7f\xc9\x26\xe3\x87\x52\x21\x73\xa8\xa3\xd1\x91\x4d\x7f\x35\x32\x3f\x9e\xc3\x7b\xd6\x4b\x01\xed\x4d\xac\xd3\x8c\x8a\x46\x41\xd0\x71\x6a\x6d\xd1\xd4\x89\x88\xac\xf6\x00\x5b\xd4\x56\x9f\xc2\xc9\x5e\xab\xd8\x4e\x15\x63\xba\xfb\x4f\x77\xe6\xcc\x4f\x06\x3f\x23\x4c\x8d\x58\x91\x82\x29\x4c\x62\x53\x26\x6c\x08\x19\x13\xb6\xdc\xb0\xe2\xe2\x99\x88\xa5\x8d\x7b\x45\xc0\x18\xca\x3a\xb8\x2d\x1c\xf6\x0f\xa7\x4d\xdd\x2a\x71\xf7\x41\x9b\xf8\xc8\x1e\xbb\x54\x31\x32\x18\x19\x86\xde\xd7\xbc\x2a\x01\x95\x9d\x6a\xf3\x54\x45\xb0\xa7\x02\x26\x94\x30\x95\x40\x42\xd1\xf8\xc9\x19\xc8\x7e\xda\x61\x95\x80\x0e\x09\x67\x16\xfd\xde\x9c\x15\x59\xf7\xb4\x3c\x33\x39\x02\x09\xd5\xb4\xed\xf5\x2c\x58\xed\x9a\x4e\x3c\x4f\x31\x31\x86\x29\x63\xb2\x5b\x08\x4c\xa1\x08\x65\x7d\x23\xe6\x34\x55\x1f\x87\x42\xb0\xc3\x0c\x96\x99\xc4\x43\x4c\xfc\x38\x90\x4d\x76\x0f\xe3\xe2\xcc\x72\x7f\x3c\xcc\x5e\x47\xef\x69\x57\x98\x50\x7e\xec\xc6\x71\xa8\xdf\xc3\x6f\xe0\x78\x0d\x3d\xa5\xd3\x5b\xb4\x76\xdf\x98\x1e\xff\x8f\xa1\xfe\xdf\x75\xf7\xdb\x59\x4f\x6f\x37\x07\xfb\xcb\x52\xe6\xdd\xbb\xdf\x7a\xb4\xb1\x27\x17\xa7\xce\x80\x3e\xb5\x42\x17\xf3\xf2\xea\x4d\xb8\x73\xbc\xb9\x86\x6b\xc1\xc1\xd7\x56\x1f\xbc\x7a\x67\x81\x2e\x29\x59\x80\x68\x91\x23\xf5\xfb\x17\x2e\xcd\x69\x6f\xf3\x1b\x3d\xdc\x29\x95\x80\xa0\x8b\xea\x67\x41\x79\xf0\xf9\xfe\xfe\x2e\x50\x6d\xee\x4a\xdf\xf0\x15\x52\x09\x75\x6d\x11\xd9\x4e\xe9\xbe\x59\x21\xdc\x8b\x01\x19\x51\xaf\x90\xe6\x87\xb8\x30\xff\xcf\xa7\x63\xc4\x86\xb0\xb0\x22\x37\x25\xbb\x9d\x99\xf4\x6f\xcb\xa5\x83\x28\x20\xd3\x15\x7a\x4e\xd2\x38\xf1\xe5\xd2\xef\x2c\x54\xbe\xb3\x90\xa0\x04\x08\xa5\x47\x20\x11\xcf\x46\x09\x48\x54\xd2\xb4\x69\xd0\x9e\xf0\xf1\xad\xc5\x8d\xe0\x59\xbe\x16\x9a\x8a\xc3\x7b\xe1\xe6\x30\x56\xa5\x1b\x0a\x9f\xdb\xd0\x21\xa4\xf7\x60\x69\xc3\xde\x07\x61\x81\x97\x4e\xf0\xbe\x63\xfa\xe3\x77\x8d\xc4\x75\xde\x1e\x35\x15\xdb\x8e\xf9\x7d\x83\x71\x53\x5c\xe9\xcf\x17\xc7\xa5\xf0\x8b\xf4\xc6\x1f\x2a\xad\xf3\x5b\x5f\x9a\xef\xa3\x1e\x65\xc6\xe0\x2e\xb5\xcf\xfc\x97\x43\xfe\xc8\xe4\x3a\xa2\xf3\x9c\x36\xea\x57\xf8\x13\xa6\x7d\x67\x29\x32\x34\xf0\x93\x3c\x67\x69\x4c\x74\x62\x9b\xa1\xbf\x15\xe3\xcd\xa1\xff\xf8\x30\xff\x11\x4b\x84\x2a\x40\xfa\xf7\x08\xdd\x99\xb9\xd6\xe4\x76\x36\x7a\x68\xb6\x20\x00\x34\xa7\x66\x53\x40\xfc\x99\xb9\x7c\x09\x9c\x30\x34\xf7\xcc\xb7\x03\x59\xed\x00\xae\x22\x74\xd7\x18\xe3\xab\x89\x6e\xcf\xc7\x61\x01\xe0\xb0\xe1\x61\x3f\x84\xf5\x68\x1c\x36\xbc\x0c\x68\x62\x03\x34\x0e\x1d\x5e\x17\x0c\x48\x5d\x02\xca\x1a\x6a\x1f\x01\xe3\xb5\x76\x80\x7d\x74\xce\xda\x06\x41\x35\x72\x0b\xd6\x8d\xdc\x83\x6b\xb0\x0a\x32\xbc\x09\x2b\xc3\x36\xb4\x0a\x83\x18\x7f\x99\x45\x98\xaf\xde\x81\x4d\x58\x9f\x5d\x60\x9a\x3a\xbf\x61\xe6\x7d\x96\xb1\xb3\x9b\x94\x0c\x52\x09\x4a\xeb\xce\xc3\xfd\xf1\xf7\xc7\xb9\xab\x93\x77\xe6\x6c\x72\x33\xbb\xfe\x7d\x32\xbb\xd5\xbc\x40\x24\x95\xae\x74\x2d\xa6\x46\x34\x54\x0b\x97\x92\x0d\x14\x58\xb1\x05\xd1\x3c\x2a\x5d\x38\xf5\x9f\x4e\xd6\x0b\x72\xc7\x0b\xfb\xe3\x27\x75\x48\xf0\x35\x73\x60\x59\x59\x1b\x3c\xb8\x6a\xac\xf9\x0c\xaf\x2c\x6b\x36\xbd\x5b\xcb\x9a\xd5\x69\x8b\xcb\x86\xde\xe1\xcd\x65\xf8\x8a\xe3\x96\x97\x5d\xf3\xea\x5b\xfe\x05\x5a\x6c\xba\xab\xad\x1c\x00\x00")
+
+func templatesCf_elbv2_lbTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesCf_elbv2_lbTf,
+		"templates/cf_elbv2_lb.tf",
+	)
+}
+
+func templatesCf_elbv2_lbTf() (*asset, error) {
+	bytes, err := templatesCf_elbv2_lbTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cf_elbv2_lb.tf", size: 7341, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCf_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x58\x4d\x6f\xd4\x30\x10\xbd\xf3\x2b\xac\x88\x03\xa0\x6e\xba\xe5\x4b\x15\x52\x4f\x95\x10\x5c\x50\x05\xbd\x21\x64\x79\xbd\xde\x8d\x85\xd7\x5e\xd9\xce\x42\x41\xfb\xdf\x19\xc7\x4e\xe2\x6c\x3e\x36\x2d\xa1\xa8\x88\xe5\x42\x33\xf3\x9c\x37\xe3\x37\x1e\x4f\x34\x33\x2a\xd7\x94\xa1\x84\x7c\x33\xd8\x30\x9a\x6b\x6e\x6f\xf0\x5a\xab\x7c\x9b\xa0\x84\xae\xb0\x31\x19\x16\x8b\x96\xe9\xe7\x23\x84\x24\xd9\x30\x14\x7e\x17\x28\x79\xfc\x73\x47\x74\xca\xe4\x0e\xf3\xe5\x7e\x46\x57\x33\x80\xce\xc4\x62\x56\x42\x67\x1e\x0a\xc0\x25\x33\x54\xf3\xad\xe5\x4a\x3a\xe0\xe5\x5b\xf4\xe9\xd3\x3b\x67\xd8\x6d\x29\x80\xa3\x15\x85\xa2\x44\xa4\xfe\xf1\x3e\x79\x04\x2e\x5c\xae\x35\x33\xa6\x20\x80\x10\xe5\x4b\x8d\x17\xe0\xf5\xd5\x04\xd0\xe7\xc0\x03\x38\x73\xb9\x50\xb9\x5c\x62\xe7\x64\xf6\xc9\x97\x02\xb1\xd5\x6c\xc5\xbf\x63\xc1\x8d\x85\x35\x4d\x37\xe2\xc0\xa9\xc6\x2a\xab\xa8\x12\x51\xd0\x96\x16\x11\x21\xb4\xd2\x6a\x83\xb7\x4a\xdb\xca\xf6\x1c\x7e\x85\xc9\xaa\xd8\x10\x99\xf6\x2e\x20\x16\xc7\x13\xaf\x72\x81\xe6\x2d\x78\xf9\x2c\x66\x02\x2c\x66\x67\x49\x2b\x1d\x2e\xb0\x79\x5a\xfc\x3b\x9d\x17\x01\x14\xaf\xb3\x64\x6d\x7c\x6e\x37\x4c\xaf\xd9\x13\x9f\x61\xf7\xf4\x04\x6d\xc8\xf6\x49\xf2\x01\x76\x35\x39\x19\xbd\x9d\x4f\x9f\xfa\x7d\x11\x7c\xc5\xe8\x0d\x15\x2c\x44\xc2\xd7\x52\x69\x86\x69\x46\xe4\x9a\x79\x36\x4e\x2f\x81\x08\x50\x51\xb9\xdd\xe6\xf6\x98\xc6\x76\x44\xe4\xcc\xf3\x6d\x2b\x34\xed\xc3\xa6\x85\x5a\xe0\x25\x7a\xac\xbe\xb9\xb4\x4c\x4b\x22\x7e\x47\xe8\xe5\x1a\x63\x15\x8f\xde\x07\xc0\x9d\xa4\xdf\x24\x5a\x0a\xf9\xb6\x49\xfa\x2f\xec\xa3\xdb\x37\xa1\xc2\x07\x55\x36\x56\xea\x3d\x8b\xf4\x68\x9e\x89\x45\x2c\xf4\xb6\xa0\x9b\xbf\x4a\xde\x26\x83\xad\xc1\xad\x2c\xb9\xed\xa0\x5a\x19\x83\x7f\x28\xc9\xb0\x50\x64\x89\x17\x44\x10\x49\x41\x9d\x80\xb6\x3a\x67\x2e\x59\x19\x23\xc2\x66\x90\x1c\x46\xbf\x86\x7c\xf9\x47\x37\xd8\x66\xc0\x30\x53\x62\x59\xbc\xee\x55\x61\xcb\x65\xdb\x0a\x6a\xf2\x79\x76\xf1\x42\x72\x9a\x34\x5f\x7b\x09\x11\xd8\x6a\xdb\x0a\xe1\xfa\xf2\xea\x8d\x93\xa2\x17\x8f\xe5\x1b\x06\x7b\x71\xe0\x54\xe9\xd4\x9d\xf2\x4c\x32\x5d\x6e\xab\x34\x16\xc2\x61\xb1\x36\x2b\xc5\xd7\xc6\x52\xa7\x71\xa9\xc0\xe6\x34\xea\xa1\x01\x75\xc6\x66\x99\xd5\xd0\x82\xc7\x74\x05\x6d\xf2\x85\x64\xd6\x44\x2c\xaa\x95\x0a\x8b\x6b\x75\xc1\x27\x7d\x16\x50\x13\x54\xd0\x94\x95\x52\xd8\xbb\xca\x02\xf4\x5c\x27\x20\x75\x6e\x5e\xf5\xed\x25\x72\x2d\x46\xac\xb0\x94\x06\xd7\xab\x1c\xef\x17\xf0\x3f\x90\xe3\x5d\xaf\x44\x1e\x3d\xf6\x56\xf4\xb1\xf0\xfe\xe7\x2e\x46\xe7\xf3\x9e\xee\x51\x18\xf6\x0f\x2b\x98\x97\x2f\x5f\xf4\x44\xe3\x2d\x0f\x2e\x9c\x81\x78\xea\x80\x1e\x4c\x6f\xef\x2d\xb8\x29\xce\xaa\xe1\xb3\xe0\x68\x3f\xef\x83\x57\x9d\x1c\x62\xe1\x64\x01\xd4\x92\xe0\x1a\x77\x55\xec\x7b\x9f\x7f\x97\xbd\xd9\xc6\xe7\x8e\xb1\x1a\x24\xe7\x0f\x95\x15\xc9\x85\x8d\x1b\xe3\xf9\xbc\xe3\xb4\xb9\xf6\x8d\x54\xad\x90\xcd\x58\x68\xd5\xc8\x77\xef\xf0\xcc\x53\x40\xae\xe3\x23\xdf\xf1\x99\x86\xf4\x9b\x9c\x66\x88\x18\xf4\xee\xfa\xda\x2d\x7d\x3e\x3f\xf5\xe0\x74\x44\x04\x4e\x21\x9e\x7f\x93\x66\x71\x0e\x1c\x10\xbc\x72\x6a\xea\xa2\xe7\xd3\x70\x82\xbe\x65\x1c\x98\xf4\x32\x45\x9a\x91\x86\xdd\x20\x25\xc7\x90\x2c\xaf\x1f\x4d\xa2\x17\xe8\xec\xf9\x88\x2d\xf2\x37\x8f\x43\xe8\x10\x32\xba\x01\x1d\xc2\x5e\x75\xc2\x3a\xae\x4e\x9d\xef\xbb\x4d\x6b\xfb\xad\x69\xa8\x2e\xb9\x5b\x0d\x44\xbe\xd9\xdd\xdf\x4c\x34\x58\x7d\xf7\xd2\xd6\xfe\x2a\xdb\xb0\x6f\x7d\x65\xb9\x4f\x7a\x82\x19\x83\x7b\xa8\x2d\xe2\x4f\x4e\x80\x23\x8b\xeb\x16\x4d\xe3\x8e\x73\x60\xb5\xc0\xdd\x47\xc1\x2a\x63\x93\x4f\x83\x5d\xea\x8a\x7c\x82\x2a\xbb\xa7\xc5\x26\xb6\xc3\x27\xa0\xbb\xe7\xc9\x01\x5d\x97\x80\xb2\x26\xba\x67\xce\x7e\xbc\x07\xec\x87\x86\xd1\x21\xb4\x07\xd4\x85\x35\x62\x5e\x0d\x47\x51\xe7\xb4\x9a\x59\x3b\x30\xae\x06\x64\xe7\xb0\x5a\x22\xc7\xb1\x18\xa2\x71\x8c\x47\x74\xaf\x6e\x33\x29\xc1\xc6\xa3\x8d\x11\x18\x1a\xbc\xe5\x2b\x4e\x89\x65\xae\x61\x54\xe5\xc3\xc9\x06\xaa\x43\xef\x20\xa7\x91\x8b\xbb\x34\xbb\x3f\x53\xa2\xe5\x7e\xba\x80\x06\x3e\x03\xc4\x17\xeb\xee\x80\x20\x8a\x69\xc3\x99\xb4\xb9\xdc\xfb\x07\x85\xfa\x84\x99\xf6\xec\x3d\xf6\x59\xa1\xf2\xec\xfe\xb2\x50\x2f\x74\xe4\xe3\x42\xbd\x4e\xe3\xfb\xc2\x2f\x97\x8d\x34\xf7\x6f\x19\x00\x00")
 
 func templatesCf_lbTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -131,12 +269,52 @@ func templatesCf_lbTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/cf_lb.tf", size: 20525, mode: os.FileMode(480), modTime: time.Unix(1515385070, 0)}
+	info := bindataFileInfo{name: "templates/cf_lb.tf", size: 6511, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCf_sni_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x94\xdd\x8e\x9b\x30\x10\x85\xef\x79\x0a\x0b\xed\x45\x52\x25\xa8\x52\xf7\xa2\x37\x7d\x85\xbe\x40\x55\x59\xc6\x4c\xc0\xaa\xb1\x91\x3d\x64\x37\x1b\xf1\xee\x1d\x83\xc9\xf2\x93\xd0\x56\xda\x72\xc9\xcc\x77\x3c\xc7\x3f\xe7\x2c\x9c\x12\xb9\x06\x96\x7a\xa3\x78\x61\x6b\xa1\x8c\x4f\xd9\x35\x61\x0c\x2f\x0d\xb0\x6f\x2c\xd5\xca\x63\x9a\x74\x49\x72\x9e\xf5\x4a\x70\xa8\x4e\x4a\x0a\x04\x2e\xdc\x43\xc8\x81\xb7\xad\x93\x04\x89\x17\xcf\x41\xe7\x29\x4b\xe5\x89\x3b\xdb\x22\x38\x1e\x84\x74\x1e\x59\x69\x5b\x83\xec\xde\x47\x82\x4f\x57\x0d\xa6\xc4\x6a\x47\x53\x64\x93\x59\xf7\x5d\x4a\xa8\x11\x35\xb0\xc7\x68\xcf\x54\xd6\x21\x07\x73\xe6\xaa\xe8\x8e\xf2\x74\x24\x91\xe3\xd3\xb5\x5f\x34\x53\xa6\x80\xd7\x5e\x49\x3a\xeb\x3d\x7f\xb3\x06\xb8\xb6\xa2\xe0\xb9\xd0\xc2\x48\x65\x4a\x52\x42\xd7\x42\x42\x3d\x15\x08\x8d\x15\x97\x15\xc8\x5f\xfd\xe4\xe3\xaf\x0b\xc7\x8a\x0c\x57\x56\x17\xd3\xa5\xa3\xd9\x55\x4f\xbf\x20\x63\xad\x59\xd3\x4b\xf6\x4e\x4f\xa4\x95\xa1\xfa\x59\xe8\xbb\xa6\x67\x2b\x0f\x03\xf3\x11\x88\x3c\x0a\x57\x02\x3e\xd8\xb4\x7b\xfc\x00\x8c\xb4\xaa\x81\x7a\xfe\x81\x1e\x80\x1e\xef\xc2\x66\x86\x9b\x02\x06\x5c\xdc\x48\x3a\x52\xa4\x0d\x07\xde\xd0\x71\x45\xb9\xaf\x9f\x17\x25\x67\xd1\x4a\xab\xc3\x4a\x15\x62\x33\x8c\xa2\xf3\x77\x86\xcd\xc9\x50\x1a\x99\xdb\x8c\x23\xf9\x77\x53\x6c\x8d\xf1\xa7\x39\xa8\xfe\xfc\xfc\xe5\xc1\x24\x23\xec\x07\xda\x7b\x3d\x7b\x5a\x2a\xde\x05\xd0\x50\x83\xc1\xdb\xf5\x5f\x3e\xbf\x03\x9b\xdc\xe4\x7d\xf7\x71\xc6\x50\x6e\xfb\xda\x34\x46\x6e\xfe\x8f\x2d\x0f\xb2\x75\x0a\x2f\xbc\xa4\x4b\xd6\x78\x12\xfb\x41\x6a\x21\x62\xe6\x95\xec\x3d\x6b\x68\xc2\x45\x8d\x82\x20\xfd\x19\xc4\xda\xdc\x00\xfa\x89\xad\x9b\x58\x5f\xc9\x02\x3a\xf4\x64\x9f\x22\x15\xb2\x4e\x94\x7e\x70\x51\x03\x3d\x89\x9d\xb6\x52\xe8\x2c\xfc\x3d\xb0\x5a\x34\xbb\xf4\x3b\xa5\x52\x7a\x18\x5f\xc3\x2a\x7a\x96\xde\x63\xa2\x2d\x2d\xef\x83\xed\xfe\x24\x4f\x20\x2f\x92\xf2\x37\x1e\x65\x69\xac\x03\x7a\x56\xc2\x94\x30\xec\x40\x88\xc1\xde\x51\x17\xa2\x97\x6c\x37\xf4\x34\x57\x71\xcb\x43\x5b\xcc\x5c\xca\x81\x16\x26\x86\x29\xa0\xb3\x55\x3c\x93\xe9\x80\x04\xdb\x5b\xb2\xad\xd3\x0b\x55\x12\x7d\x53\x4d\xd8\x8b\x95\xc7\xad\xc5\x0a\xe3\xfb\x19\x83\xf1\x2e\xf9\x0d\x10\xbd\x77\xb0\xa3\x06\x00\x00")
+
+func templatesCf_sni_lbTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesCf_sni_lbTf,
+		"templates/cf_sni_lb.tf",
+	)
+}
+
+func templatesCf_sni_lbTf() (*asset, error) {
+	bytes, err := templatesCf_sni_lbTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cf_sni_lb.tf", size: 1699, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesConcourse_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x97\xbd\x6e\xdb\x30\x10\xc7\x77\x3d\x05\x21\x74\x2a\x6a\x55\xb1\x3d\x78\xc9\x94\xa9\x4b\xd1\xa1\x5b\x60\x10\x14\x75\xb6\x84\x30\xa4\x70\x24\x1d\x18\x81\xdf\xbd\x20\x29\xc9\xfa\xb0\x13\x39\x0e\x8c\x26\x59\x0c\x1e\xef\x78\xf7\xfb\x1f\x79\x10\x82\x56\x16\x39\x90\x98\xbd\x68\xaa\x81\x5b\x2c\xcd\x9e\x6e\x51\xd9\x2a\x26\x31\x57\x92\x2b\x8b\x1a\xa8\xc8\x68\x29\x0d\xa0\x64\x62\xb4\xed\x35\x22\x44\xb2\x67\x20\xf5\xdf\x3d\x89\xbf\xbd\xee\x18\x26\x20\x77\xb4\xcc\x0f\xb3\x36\xcc\x4c\x64\xb3\x26\xcc\xac\x09\x33\x0b\x61\x22\x42\x72\xd0\x1c\xcb\xca\x94\x4a\xba\x20\x0f\x8d\x1b\xf9\x55\xfb\xb8\x4d\xbb\x8a\xd3\x32\xef\x9c\x24\x14\x67\x22\x09\xcb\x87\x38\x8a\x08\x31\x6c\xab\x7d\x56\x84\xfc\x76\x79\x7d\x38\xa1\x83\x8b\x26\xca\x0d\xf0\x3d\x17\x50\x87\x2c\xb7\x52\x21\x50\x5e\x30\xb9\x05\x4d\xee\xc9\x63\xec\xaa\x8f\xd7\xde\xe1\x10\x45\x6f\x41\xa5\x68\x05\x9c\x25\xbb\x4a\x03\x4d\xb3\xaf\xba\x34\x4b\xb9\x45\xd0\xda\x65\x54\xa1\x32\x8a\x2b\x51\x5b\x0c\xf7\x79\x6e\x50\x3d\xd3\x4a\xa1\xf1\xab\xab\xd4\x85\x50\xcd\x42\xbb\xc4\xcb\x1c\x69\x26\x14\x7f\x0a\x59\xa7\x89\xff\xff\x99\xc6\x6b\x57\xe7\x20\xd1\x32\x0f\xdc\xc6\x35\x24\x53\xda\x22\xf1\x62\x5c\x45\x63\x3e\x9f\xcf\x3f\x83\x87\x8b\x33\x22\x52\x2f\x7e\x35\x26\xcb\xe5\xe2\x33\x90\x2c\x97\x8b\x11\x91\xb0\xf6\xd5\x80\x40\xa8\xfb\x14\x13\x38\x87\x64\x76\x37\x26\x32\xbe\x33\xff\xcb\x95\x11\xd9\xa0\xf8\xf1\x8b\x3b\x7c\x78\x75\xa1\xd0\xd0\x53\xaf\x9d\x2b\x5c\x28\x96\xd3\x8c\x09\x26\x39\x20\xf5\xd0\xee\x49\x2c\xc1\xbc\x28\x7c\x72\x1b\xb4\xcd\x24\x18\xdd\x0f\xfd\xd8\x14\xe6\x8d\x89\xc8\xea\x5f\x3a\xf9\xee\x13\x5f\x9f\xca\x9c\x8a\x52\x1b\x90\x80\x43\xfd\x9a\x97\xae\x9f\x0b\x43\x79\x24\x28\xb2\x1e\xb5\x84\xa1\x3c\x0c\xc5\x6c\xeb\xfe\xfb\xf0\xc7\xdb\x1a\xf9\x3a\xb6\x55\x1a\xf9\xd9\xb2\x61\x56\x18\xca\xb8\x1f\x2f\xe1\x29\xef\x36\x4c\x13\x69\xa3\xf0\x85\x61\x1e\x87\x0d\x0c\xb7\x60\x6a\x79\x07\xd9\xd1\xae\x31\x19\x54\xd7\x66\x7b\x62\x22\x0c\x5c\xcf\xa1\x69\x05\x7e\x4f\xd6\x55\xda\x2b\xbd\x7e\xed\x5b\x4c\x47\x3a\xed\xe8\x3c\x33\x37\x0b\x60\xc2\x14\x94\x17\xc0\x9f\x6a\x42\x61\x69\x4f\x4d\x81\xa0\x0b\x25\x82\xf7\x5d\xea\x8d\x56\x8e\xcd\xad\xd1\x37\xf9\x8e\x89\x3e\xde\x45\x30\x8e\x35\xec\xaa\x78\x9a\xd9\xb9\x56\x3a\x8e\x89\x1b\x34\x93\x1f\x1b\xb7\x6e\x27\x77\xe8\x15\x0d\x75\x04\x34\xb9\xa5\xbc\x4b\xbf\xa9\xea\x81\x79\x69\x5b\x5d\xa2\x64\x3b\xdc\x6e\x20\xa4\x9b\x76\xb7\xd6\x71\xb9\x5c\x5c\x21\x63\x4b\x67\xb2\x8a\xce\xa3\x2f\x62\x98\xf1\x1f\xd0\x50\x59\x53\x59\x73\xc9\x17\xc1\x8e\x09\x0b\xd7\xcd\x43\x57\xe8\x1b\xc7\x77\x61\xe9\xfe\xa1\x8f\x13\x5f\xe9\x70\xc2\x8f\xc9\xea\x5d\xb2\xdf\xdf\xda\xe0\xb0\x3e\x5b\x83\xff\x72\x38\xc5\x6b\xd8\xe7\xef\xb0\xb0\x28\x26\x85\xc9\xa5\xa6\x6d\xa8\x7f\x01\x00\x00\xff\xff\xaa\x1a\xd6\xc4\xfe\x0d\x00\x00")
+var _templatesCf_tcp_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\xdb\x4b\x8f\xdb\x36\x14\x05\xe0\x7d\x7e\x85\x60\x74\x91\x14\xb1\x6b\x4a\x7c\x16\x98\x55\x80\x02\xdd\x14\x5d\x74\x57\x14\x82\x2c\x73\x6c\x21\x1a\xc9\xd0\x63\xda\x69\x30\xff\xbd\x94\x64\x7b\xec\x38\x4e\x9d\xd3\x53\x20\xf1\xec\x4c\x5d\xfa\x90\xbc\xfc\x66\xa5\xc6\xb7\x75\xdf\xe4\x3e\x9a\x65\x7f\xb6\x69\xeb\xf3\xbe\x29\xba\xa7\x74\xd3\xd4\xfd\x6e\x16\xcd\xf2\xfb\xb4\xcb\x77\x69\xb9\xba\x18\xfa\xf0\x2a\x8a\xaa\xec\xc1\x47\xfb\xcf\x5d\x34\xfb\xee\xc3\x63\xd6\x2c\x7c\xf5\x98\x16\xeb\xe7\x79\x7e\x3f\x0f\xa5\xf3\x72\x35\x3f\x94\xce\xa7\xd2\x50\xb8\xf6\x6d\xde\x14\xbb\xae\xa8\xab\xa1\xf0\xdd\x4f\xd1\x6f\xef\x7e\x1d\x06\x1e\x77\x79\x28\x3e\x99\xb1\xac\xf3\xac\x5c\x4c\x5f\x3f\xcf\x5e\x85\x47\x8a\x6a\xd3\xf8\xb6\x1d\x03\x44\x51\x5e\xac\x9b\x74\x15\x9e\x7a\xdf\xee\x8b\x7e\xdf\xe7\x08\x99\x8b\x6a\x55\xf7\xd5\x3a\x1d\x1e\x6a\x9f\x67\x7f\x8c\x15\xbb\xc6\xdf\x17\x7f\xa5\x65\xd1\x76\x61\xce\xf6\xd3\x15\x1f\x3d\xf4\x52\x5b\x77\x75\x5e\x97\x27\x8b\x0e\x6b\x9c\x8d\x63\xf7\x4d\xfd\x90\xee\xea\xa6\x3b\x8e\x89\x65\x2c\xc7\xa1\xae\x3e\x1d\x18\x87\x44\x9c\x84\xa1\xe7\x61\x41\xfe\x74\x3d\xa7\xb3\xdc\x45\xcb\x8b\xf2\xc3\x77\xa7\x49\x42\x8a\xb9\x98\x5d\x6c\xc7\xb0\xb0\xe5\x62\xfc\xfb\x61\x39\x2e\x60\xfc\xb9\x2e\xdb\xb4\xd3\xde\x3e\xf8\x66\xe3\x5f\x4f\x3b\x3c\x7c\xfb\x36\x7a\xc8\x76\xaf\x67\xbf\x84\x53\x9d\xbd\xbd\xf9\x38\xdf\xbc\x99\xce\xa5\x2c\xee\x7d\xfe\x94\x97\x7e\xbf\x92\x62\x53\xd5\x8d\x4f\xf3\x6d\x56\x6d\xfc\x94\x66\xe8\x97\x7d\x90\x10\xa5\xee\xbb\x5d\xdf\xfd\x5b\x8f\x3d\x66\x65\xef\xa7\xbc\x97\x1d\xba\xb8\x56\xbb\x18\xbb\x25\xfc\x48\x73\x6b\x7f\x17\x55\xe7\x9b\x2a\x2b\xff\x4b\xa3\x1f\xe6\xb8\xb5\xe3\xa3\x9f\xf7\x05\x50\xeb\x9f\x07\x3d\x34\xf2\x97\x6e\xd2\xff\xd8\xd8\x5f\x4b\x5c\xbb\xbc\x12\x76\x1c\xf8\x26\xef\xe0\xb5\x4e\x23\x5e\xc6\xcf\x5e\x88\x5b\x6f\xe5\x95\x49\xae\x5c\x4f\x5f\xae\x4e\xef\xe4\xe5\xdd\x3b\xff\x1c\x6f\x62\xbb\x0d\x47\x93\x5e\xec\xd2\x70\x1c\x79\x53\xb7\x6d\xfa\x77\x5d\xf9\xb4\xac\xb3\x75\xba\xca\xca\xac\xca\x43\x67\x86\xea\xae\xe9\xfd\xb0\x59\x5b\x9f\x95\xdd\x36\x6c\x8e\xcf\xdf\xef\xf7\x6b\xfa\xea\x29\xed\xb6\x21\xe1\xb6\x2e\xd7\xe3\xcf\xe9\x71\xac\xaf\x2e\x47\xef\xa2\x64\xda\xe7\x61\xbd\x61\x73\xce\x63\xaa\xa9\x85\xb2\x70\xd4\xdd\xc5\x12\x82\x03\x3f\xda\xe5\xd4\x3a\x5d\xf1\xe0\xc3\x49\x7c\xf4\xc8\xf1\x42\x0d\xff\x8e\x7c\xe5\x9b\xc3\xa1\x56\x6d\x17\x16\xe3\x4f\x3b\xf3\x78\x35\x5f\x06\x0f\x5d\x7a\x7a\x49\xc2\xd1\x9c\xdd\x85\xb3\xd2\x61\xf0\xfc\x82\xbd\x94\x7e\x41\x0e\x85\xe7\x50\xcc\x1c\x1a\xcf\xa1\x99\x39\x0c\x9e\xc3\x30\x73\x58\x3c\x87\x65\xe6\x70\x78\x0e\x47\xcc\x91\x2c\xe1\x1c\xfb\x52\x52\x0e\x81\xe7\x10\xcc\x1c\x31\x9e\x23\x66\xe6\x48\xf0\x1c\x09\x33\x07\xee\x69\xc2\xf4\x34\xc1\x3d\x4d\x98\x9e\x26\xb8\xa7\x09\xd3\xd3\x04\xf7\x34\x61\x7a\x9a\xe0\x9e\x26\x4c\x4f\x13\xdc\xd3\x84\xe9\xa9\xc4\x3d\x95\x4c\x4f\x25\xee\xa9\x64\x7a\x2a\x71\x4f\x25\xd3\x53\x89\x7b\x2a\x99\x9e\x4a\xdc\x53\xc9\xf4\x54\xe2\x9e\x4a\xa6\xa7\x12\xf7\x54\x32\x3d\x95\xb8\xa7\x92\xe9\xa9\xc4\x3d\x95\x4c\x4f\x25\xee\xa9\x64\x7a\xaa\x70\x4f\x15\xd3\x53\x85\x7b\xaa\x98\x9e\x2a\xdc\x53\xc5\xf4\x54\xe1\x9e\x2a\xa6\xa7\x0a\xf7\x54\x31\x3d\x55\xb8\xa7\x8a\xe9\xa9\xc2\x3d\x55\x4c\x4f\x15\xee\xa9\x62\x7a\xaa\x70\x4f\x15\xd3\x53\x85\x7b\xaa\x98\x9e\x6a\xdc\x53\xcd\xf4\x54\xe3\x9e\x6a\xa6\xa7\x1a\xf7\x54\x33\x3d\xd5\xb8\xa7\x9a\xe9\xa9\xc6\x3d\xd5\x4c\x4f\x35\xee\xa9\x66\x7a\xaa\x71\x4f\x35\xd3\x53\x8d\x7b\xaa\x99\x9e\x6a\xdc\x53\xcd\xf4\x54\xe3\x9e\x6a\xa6\xa7\x06\xf7\xd4\x30\x3d\x35\xb8\xa7\x86\xe9\xa9\xc1\x3d\x35\x4c\x4f\x0d\xee\xa9\x61\x7a\x6a\x70\x4f\x0d\xd3\x53\x83\x7b\x6a\x98\x9e\x1a\xdc\x53\xc3\xf4\xd4\xe0\x9e\x1a\xa6\xa7\x06\xf7\xd4\x30\x3d\x35\xb8\xa7\x86\xe9\xa9\xc5\x3d\xb5\x4c\x4f\x2d\xee\xa9\x65\x7a\x6a\x71\x4f\x2d\xd3\x53\x8b\x7b\x6a\x99\x9e\x5a\xdc\x53\xcb\xf4\xd4\xe2\x9e\x5a\xa6\xa7\x16\xf7\xd4\x32\x3d\xb5\xb8\xa7\x96\xe9\xa9\xc5\x3d\xb5\x4c\x4f\x2d\xee\xa9\x65\x7a\xea\x70\x4f\x1d\xd3\x53\x87\x7b\xea\x98\x9e\x3a\xdc\x53\xc7\xf4\xd4\xe1\x9e\x3a\xa6\xa7\x0e\xf7\xd4\x31\x3d\x75\xb8\xa7\x8e\xe9\xa9\xc3\x3d\x75\x4c\x4f\x1d\xee\xa9\x63\x7a\xea\x70\x4f\x1d\xd3\x53\x87\x7b\xea\x88\x9e\x8a\x25\xec\xe9\xa1\x94\x94\x43\xe0\x39\x04\x33\x47\x8c\xe7\x88\x99\x39\x12\x3c\x47\xc2\xcc\x21\xf1\x1c\x92\x99\x43\xe1\x39\x14\x33\x87\xc6\x73\x68\x66\x0e\x83\xe7\x30\xcc\x1c\x16\xcf\x61\x99\x39\x1c\x9e\x83\xe9\xa9\xc0\x3d\x15\x4c\x4f\x05\xee\xa9\x60\x7a\x2a\x70\x4f\x05\xd3\x53\x81\x7b\x2a\x98\x9e\x0a\xdc\x53\xc1\xf4\x54\xe0\x9e\x0a\xa6\xa7\x02\xf7\x54\x30\x3d\x15\xb8\xa7\x82\xe9\xa9\xc0\x3d\x15\x4c\x4f\x05\xee\xa9\x60\x7a\x1a\xe3\x9e\xc6\x4c\x4f\x63\xdc\xd3\x98\xe9\x69\x8c\x7b\x1a\x33\x3d\x8d\x71\x4f\xe3\x1b\x3d\xe5\xbd\x62\xd8\xf6\xab\xca\x77\xed\x49\x8a\xe3\x4c\xe3\xc8\xf0\xae\xf0\xfe\x99\xc5\xf7\xfb\x2a\xc2\x7b\x7d\xcc\xf7\xf7\xc6\xf1\x4f\xbd\xac\xe7\xcb\xd5\xcb\x06\x2c\x86\xc7\xa6\x77\xf1\x2e\xa7\xe8\x9b\xf2\x86\x19\xd6\x55\x9b\x1e\x67\xf9\x07\x82\xdf\xb4\x0d\x50\x3e\x00\x00")
+
+func templatesCf_tcp_lbTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesCf_tcp_lbTf,
+		"templates/cf_tcp_lb.tf",
+	)
+}
+
+func templatesCf_tcp_lbTf() (*asset, error) {
+	bytes, err := templatesCf_tcp_lbTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cf_tcp_lb.tf", size: 15952, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesConcourse_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x96\xc1\x6e\xf2\x38\x10\xc7\xef\x79\x8a\x91\xd5\x43\x59\x95\x6c\x0a\x1c\xb8\x70\xea\x69\x2f\xab\x3d\xec\xad\xaa\x2c\xc7\x19\x20\xaa\xb1\x23\xdb\xa1\x8b\xaa\xbc\xfb\x6a\x9c\x10\x42\x08\x2d\xfd\x8a\xf8\x0e\x6d\x2f\xc8\x63\xcf\x78\x7e\xf3\x77\x66\x2c\x3a\x53\x5a\x89\xc0\xc4\x9b\xe3\x0e\x65\x69\x73\xbf\xe3\x2b\x6b\xca\x82\x01\x93\x46\x4b\x53\x5a\x87\x5c\xa5\x3c\xd7\x1e\xad\x16\xea\x64\xdb\x7b\x04\xa0\xc5\x06\xa1\xf9\x5b\x00\xbb\x7b\xdf\x0a\x1b\xa3\xde\xf2\x3c\xab\xc6\xad\x9b\xb1\x4a\xc7\x7b\x37\xe3\xbd\x9b\x71\xed\x26\x02\xc8\xd0\x49\x9b\x17\x3e\x37\x1a\x16\xc0\x9e\xf6\xc7\xe0\xaf\xe6\x0c\x8b\x00\xb6\x85\xe4\x79\xd6\x89\xa4\x8c\x14\x2a\xae\x97\x2b\x16\x45\x00\x5e\xac\x1c\x39\xb8\x7b\xdf\xa0\x5d\xe1\x7d\xbd\x83\x56\x1f\x60\x23\x8a\x7b\xf6\xb7\xd8\x20\x7b\xf8\xa5\x6b\x8e\x46\x75\x0c\x95\x2f\x51\xee\xa4\xc2\x90\x3e\x40\xbe\xd2\xc6\x22\x97\x6b\xa1\x57\x48\xd1\x9f\x19\x31\x61\x2f\x11\x40\x15\x55\x51\xf4\x11\x6a\x6e\x4b\x85\x67\x79\xcf\x13\x16\x82\xf8\x5d\xd1\x32\x6e\xb2\xcf\xf5\xca\xa2\x73\xc4\xa5\xb0\xc6\x1b\x69\x54\xc7\xea\x65\xc0\xba\xb4\x66\xc3\x0b\x63\x7d\x6b\x99\x27\xe4\xce\x74\x17\xdb\x65\x99\x67\x96\xa7\xca\xc8\x57\xd7\x2c\x3f\x37\x9c\x82\x06\x52\x53\xea\x8c\xd3\x26\x57\x85\xe4\x0a\x8b\xcb\xfc\x3f\xae\x72\xe7\x79\x9e\xb9\xe1\xfd\xbd\x4d\x74\x32\x02\xe8\x41\xc8\xb3\xba\x68\xa7\x7c\xe2\x61\x30\xbd\x4d\xa1\xfc\xdf\x22\x3d\x99\x4c\x26\xd7\x66\x4d\x3e\x07\x69\x37\x86\x9f\xcc\x7b\x36\x9b\x5e\x1b\xf7\x6c\x36\x1d\xa4\x5d\xaf\xff\x64\xd8\x58\x7f\x2a\x4e\x78\x2f\x80\xe1\x20\xea\x05\xb0\xf1\x63\x9f\xf2\x02\xfa\xdf\x8e\x7a\xa5\x4b\x96\x28\x25\x71\xf8\xff\x33\xb9\x21\x0d\x95\xf6\x92\x3f\xed\x4d\xfd\x16\xe5\xd6\xc6\x7a\x3e\xd4\x01\x28\x71\x65\x44\xc6\x53\xa1\x84\x96\x68\x79\x10\xe9\x02\x98\x46\xff\x66\xec\x2b\x6d\x70\x65\xaa\xd1\xbb\xbd\xdb\x83\xa4\x42\x71\x82\x31\x56\x69\xf3\xcb\xc5\x7f\x84\x8b\xbf\x5c\xa9\x47\xb1\xd1\x68\x98\x42\xd0\x2b\x6a\xb4\x7d\x2d\xec\x3b\xc9\x71\x5e\xc2\xea\x43\x35\x54\x7a\x54\x81\x58\x58\x5d\x0d\xbd\x41\x4a\x94\xfd\xfb\xf4\x4f\xb0\x75\x9f\x5a\x63\x9b\x27\x94\x65\x86\x4b\x51\x2a\xcf\x85\x0c\x4d\x9d\x62\x9f\x3e\x76\xf2\xb4\x34\xf6\x4d\xd8\x8c\xbc\x51\xff\xb6\x2b\xf4\x8d\x54\x7a\xb7\xe3\x5d\xe3\xb1\x58\xe6\x49\x7b\xdb\x81\x8e\xdb\x3b\x7a\x0e\x4d\x2b\x96\xcf\x24\x32\x4f\x8e\x52\x6f\xba\x67\x8b\xe9\x40\xa7\x1d\x58\xce\x4c\x2b\x6b\x14\xca\xaf\xb9\x5c\xa3\x7c\x6d\x86\x89\x7a\x69\xc7\xfd\xda\xa2\x5b\x1b\x45\xe3\xce\x02\x1e\xe9\x9d\x01\x94\xfa\xd4\xdc\x1a\xc3\xe7\x63\x2b\x3a\x65\xa2\x93\xd3\xfa\xe4\x69\x0d\xbb\x55\xac\xae\x35\x3a\xcd\x93\xaf\x2b\xf3\xd0\x79\x6f\xa0\x4d\x0a\x76\x73\x75\x52\xd0\x6f\xe8\xf3\x00\xe8\x62\x85\x86\x23\xc7\x1a\x6d\x66\x8e\x16\xd8\xe5\x2a\xbd\x86\x30\x28\xfa\xd7\xa5\xd1\x0e\x09\x37\x50\x06\x4d\x09\xb7\x16\xc6\x6c\x36\xfd\x86\x2e\x5a\x3a\x17\xcb\x82\x4e\x1c\xab\x82\xb2\xfe\x6d\xa2\xa0\xeb\xec\x35\x61\x4a\x5f\x94\x1e\xd8\x25\x33\x40\xfd\x18\xb6\x42\x95\x78\x00\xdd\x1b\x13\x2e\xf1\x13\x13\xb8\x0f\xc2\x77\xe1\xbb\xe3\xa0\xfb\x46\xff\x61\x79\xe7\x49\x13\xe1\xe1\x62\x35\x7c\x65\x3f\xbd\xa9\xe6\xc0\xcb\xd9\x1c\xc8\x3e\xc8\xab\xff\x6e\x3e\x61\x51\x5a\x75\x91\x9b\x4c\x3b\xae\xc5\x06\x2b\x16\x55\xd1\xff\x03\x00\x71\xc8\x19\xd0\x64\x10\x00\x00")
 
 func templatesConcourse_lbTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -151,12 +329,132 @@ func templatesConcourse_lbTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/concourse_lb.tf", size: 3582, mode: os.FileMode(480), modTime: time.Unix(1519226906, 0)}
+	info := bindataFileInfo{name: "templates/concourse_lb.tf", size: 4196, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesDns_roleTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x93\xb1\x6e\x83\x40\x0c\x86\x77\x9e\xc2\x3a\x75\x68\xa5\x28\x43\x33\x77\xa8\xd4\xb9\x43\x33\x74\x44\x0e\xb8\xe4\xc4\x71\x46\xe7\x83\x34\x8d\x78\xf7\x1a\x92\x10\x22\x95\x0a\xb6\xb3\xbf\xff\x93\x2d\xee\x5a\x0c\x16\x77\x8e\xc0\xe4\x5e\xd2\xc0\x8e\x52\x0c\xde\xc0\x29\x01\x88\xc7\x9a\xe0\xf2\xbd\x80\x91\x18\xac\x2f\x8c\x36\x72\x92\x2c\xd8\x3a\x5a\xf6\x7d\xe3\x43\x53\x10\xf7\x18\xa1\x42\x8f\x05\x89\x1e\x08\xf6\x2c\x91\x72\xf8\x61\x4f\x2b\xf8\xe2\x00\x72\xd4\x42\x05\x39\x57\x68\xbd\xc0\x41\x01\x82\xb7\xf7\x2d\x38\xdb\x6a\xc6\x7a\x40\xcf\x9a\x0c\xf0\xfa\xb9\x05\xcc\x32\x6e\x7c\x5c\x9b\xa4\x4b\x92\x3a\x70\x6b\x73\xed\x18\x3c\xc8\x79\x38\x74\x16\x65\x9c\x4d\x87\xef\x07\xd3\x10\x89\xa4\x25\x1d\xfb\xe2\xc3\xa9\xc5\xb0\xbe\xd5\xba\x1e\x11\xca\x02\xc5\x7b\xe4\x56\x1b\x90\xc8\x25\xf9\x51\x7d\x45\x44\x74\xdd\x74\xe8\x0d\x54\xa0\xa2\xdf\xff\x8e\x3a\xd7\xb4\xad\xfd\x0a\xbf\x53\xb5\x06\xab\xbb\x8d\xc0\xa4\x78\xa6\xc8\xe7\x35\x5b\x1f\x65\x58\x4a\xcf\xd9\x33\x4c\xbe\x21\xe8\x98\xcb\xa6\x7e\xec\xf3\x23\xbe\x02\xa3\xa8\x59\xc1\xb4\xfa\x34\x0c\xa6\x12\xb7\x5b\x2c\x71\xbb\x19\x89\xc5\x6a\xa9\x44\xd1\x19\x49\x59\xc9\x52\x89\xa2\x33\x12\xd9\xc0\x52\x89\x6c\xe6\x1c\x71\xf1\x20\x8a\xce\x48\x32\xc7\x4d\x7e\xc0\x98\xed\x1d\x17\xf2\xaf\xe4\x1e\xfd\xd3\xd7\xf5\xff\x1f\x45\x9a\x8a\x86\x87\x77\xb9\x01\xd7\x37\x78\xbb\x34\xd3\x97\x79\x49\x76\xc9\x2f\x5f\xd9\x9a\xb6\xb9\x03\x00\x00")
+
+func templatesDns_roleTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesDns_roleTf,
+		"templates/dns_role.tf",
+	)
+}
+
+func templatesDns_roleTf() (*asset, error) {
+	bytes, err := templatesDns_roleTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dns_role.tf", size: 953, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesIamTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x57\x51\x6f\xe3\x36\x0c\x7e\xae\x7f\x05\x61\xec\x61\x2b\x9a\xac\xed\xcb\x80\xe0\x8a\x43\xd1\x66\xc5\xb6\x1b\x56\x24\xc5\x3d\xac\x28\x0c\x46\xa6\x1d\x6d\xb2\xe4\x49\x72\xba\xac\xc8\x7f\x1f\x24\xd9\x4e\xd2\xd8\x4e\xbb\xe1\xee\xa5\x40\xfd\x7d\x24\x3f\x92\x0e\x49\xaf\x50\x73\x5c\x08\x82\x78\xa1\xcc\x32\xe1\x58\x24\x5c\x1a\x8b\x92\x51\x52\x6a\x95\x71\x41\x31\xbc\x44\x00\x29\x65\x58\x09\x0b\x57\x10\xc7\xd1\x26\x8a\x84\x62\x28\x8c\x87\x38\x16\xf7\x81\x7a\xaf\xd5\x8a\xa7\x94\x3a\xd6\x37\x2f\x2b\xd4\xe3\x5e\xaf\x70\xe5\x3c\xc1\x47\x38\x87\x09\x5c\xc0\xc6\x3b\x4d\xd1\x22\xc4\xf8\x6c\x7a\x84\x78\x91\x41\x8f\xc4\x82\xde\x10\x66\x13\x47\x11\x00\x53\x95\xb4\x81\xed\x75\x8f\x0f\x25\x07\x01\x9a\x8c\xaa\x34\xa3\xad\x08\xad\x06\x03\x93\x5c\x25\x3c\xdd\x24\x5e\x80\xe7\x46\x00\x25\xda\xa5\xa3\x7c\xff\x3a\xf8\x05\x8c\x60\x40\x40\x04\x20\x78\x46\x6c\xcd\x04\xf9\x58\x00\x4c\x13\x5a\x4a\x16\x94\x29\x4d\x49\x4a\xc6\x6a\xb5\x86\x2b\xb0\xba\xa2\x08\x60\xe3\x6c\xd0\x98\xaa\x20\x1f\x3d\x29\x95\xe0\xcc\x11\x3e\x7c\x98\xfe\xf6\x63\xe4\x9c\xc4\x9f\x49\x1b\xae\x64\x3c\x81\xf8\xf2\xfc\xe2\x72\x74\x71\x3e\xba\xf8\x21\x3e\x73\xd0\xdc\xa2\xa5\x82\xa4\x8d\x27\xf0\xe8\x03\x86\xb0\x00\xf1\x35\xb3\xb5\x91\xb1\x66\x72\xed\x63\xcc\x5c\x82\x67\x0d\xe3\x5e\x73\xc9\x78\x89\x22\x9e\xb4\x66\xce\x27\xe9\x15\x67\xe4\x2c\x89\x5d\x8e\xb1\xc0\x7f\x94\xc4\x67\x33\x66\xaa\x88\x6b\xda\xa6\x75\x32\xcd\x32\x62\x2e\x7c\x7c\x2d\x84\x7a\xde\x7a\x9f\xf3\xd4\x3d\x0d\x16\x9b\x08\xe0\x29\xda\x44\x2e\xa7\xce\x36\x85\xbc\xdf\xda\xa8\x9a\xfd\xff\x5a\xf5\x05\x4a\xfd\xb8\xad\x22\xb1\x4b\x57\x74\xc5\x38\x5a\xba\x4e\x53\x4d\xc6\xb4\xc5\x69\x70\x6b\x91\x2d\x3f\x2b\x51\x15\xf4\x1a\xbb\x51\xe5\xfa\xa7\x02\xf3\x43\xc0\xbf\x51\xdd\x46\xb7\x24\xc8\xd2\x5c\x62\x69\x96\xca\x76\xa3\x7d\x96\x86\x69\xbe\x68\x94\xd2\x81\xd6\x96\xb0\x42\x2e\x70\xc1\x05\xb7\xeb\xdf\x95\xec\x27\x7a\xf1\xfd\x68\xfd\x3b\xef\x25\xcc\x28\xe7\x4a\xf6\xc2\x73\x62\x95\xe6\x76\x7d\xa7\x55\x55\xf6\xb3\xea\x4a\xf4\x13\xaa\x85\xa4\x7e\x38\xd4\xaa\x03\x1e\xe8\x9b\x6f\x4f\x5f\x0b\x02\xfa\x80\xf9\x81\xcf\x5f\x55\xca\xb3\x75\x53\x96\x6b\x6b\x35\x5f\x54\xf6\xc0\xfd\xac\x92\xbd\xa5\x7b\x20\x5d\x70\x89\xb6\xbf\xb8\xae\xa8\xc6\x92\xee\x7c\xb1\x6e\x49\x0f\xc1\x37\xce\xa3\x98\x97\xca\x36\xee\x67\xf4\x57\x45\x66\xa0\xb8\x6f\xe0\xd6\xcf\x77\xa9\x07\x9c\x50\xb4\x99\xea\x28\x47\xfb\xb6\x38\xf0\xc1\x2d\xc2\x8e\x08\xa5\x40\x56\x9b\x47\x27\x00\x4f\x67\xee\x6f\xc7\xe0\x72\x4f\x67\xf5\x64\x72\xcf\x4f\xeb\xd9\x75\x16\x9d\xbc\x78\x70\xe7\x77\x7e\xe2\xfd\x73\x2c\x26\xf7\x68\x8c\x9f\xab\xef\xf5\x7d\x32\xe0\x98\x04\x1a\xcb\x99\x50\x98\x2e\x50\xa0\x64\x5c\xe6\x93\xd3\xff\x14\xa2\x29\xc6\x76\xc2\xc3\xd0\xdc\xae\xe1\x8e\x91\xd6\x62\x7f\x16\x66\x32\xa3\xa9\x64\x7a\x5d\xda\xd3\x57\x96\x2d\xe3\x8e\x24\x69\xb4\x74\x8b\x16\x7f\xa1\x75\x2f\x2f\x74\xf7\x4e\xa3\xb4\x7d\x94\xa6\xcb\xde\xcd\x1e\xe5\xe9\x95\xec\x9d\xfc\x3b\x84\xbf\x36\x6e\xff\x3b\xba\x9e\x76\x76\x73\x82\x7e\x6a\xfb\x4d\xb0\xbb\xae\x1c\xa5\x76\x77\xe4\xba\xa8\xdd\x68\x19\x88\xfb\x2b\xd0\x9f\x42\x63\xd4\xf2\xe0\xf2\x39\xb2\xd1\x3a\x75\xbf\xe3\x04\xab\xb5\x8e\x3c\xde\xe4\xb3\x27\xd0\x3d\x09\xf2\x9c\xe5\x7b\xf5\x75\x1c\x47\x3c\x97\xee\x2a\x62\x4b\x94\x39\x19\xb8\x82\xc7\xd8\x79\x8e\x9f\xfc\x65\x74\x90\x50\x26\xd4\x73\x22\x54\xee\x92\x58\x88\x90\x83\x50\x79\x92\xbb\x1d\x90\x6c\xb3\x71\x5c\x26\x54\x95\x3e\xa3\x65\xcb\xa4\xa5\x8c\x17\x0b\xd1\x48\xf7\x57\x6f\x68\xab\x6b\x04\x74\x64\xda\x84\x33\x75\x37\x00\x56\x25\x4b\x78\xda\xbe\x3f\x3b\xf7\x68\x40\x3c\xc9\x6a\xcc\x32\xce\x12\xbb\x2e\x29\x90\x66\xd3\x9f\xa7\x37\x0f\x1d\x1d\xea\x12\xb9\x9b\x9c\xd3\x9a\x94\x9a\x32\xfe\xf7\xb6\x4f\x66\xa9\xb4\x4d\x9a\x6e\x09\x95\x8f\x82\xdd\xe0\xf9\xdb\xe6\x32\xd4\x79\x47\x72\x0e\xcd\x28\xbc\xaa\x5f\xec\x34\x6d\x4e\xc3\xe3\x47\xe4\xf1\x13\x75\x55\xb2\xad\xf0\x63\xc7\x6a\xef\x4d\xfc\xb6\x23\x75\xa7\x0c\xef\xaf\xe9\xf6\x66\xed\xf9\x65\x6d\xdf\x37\xfe\x55\x2e\x54\x17\xaa\x9e\xbe\x9f\x54\xee\x0f\xa9\xdd\xdd\xb9\x0f\xcf\xad\x26\x2c\x0e\xf0\xfb\xca\x7e\x52\xf9\x74\x45\x72\x7f\xb5\x7b\xb0\x19\xdb\x8d\xf7\x41\x46\x08\x60\x9a\x9e\x3d\x1d\x7f\x37\xba\x56\xf5\x7e\x07\x55\x65\xcb\xca\xfa\x35\xdd\xf3\x55\xbc\x42\x51\xd1\xf0\x87\x25\x7c\x84\x3f\x14\x97\xdf\xc6\xf1\x19\xb8\xef\xdb\x71\xdf\x6c\x0d\xa3\xf1\xd4\x4f\x98\xef\x60\xb2\xb5\x7a\x93\x81\x9f\xe0\xff\x06\x00\x00\xff\xff\x6f\x9b\x07\x6e\xce\x0f\x00\x00")
+var _templatesDual_stackTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x54\xcb\x4e\xc3\x30\x10\xbc\xe7\x2b\x2c\x8b\x03\x20\x30\xe5\xc2\xa1\x12\x5f\x82\x90\xe5\x38\xa6\xb8\xb8\x59\xcb\x8f\x86\xa8\xea\xbf\x63\xd7\x4d\x5a\x27\x69\x85\x8a\x90\x20\x52\x0e\xd9\x9d\xcc\xce\x8c\x1f\x0a\x38\x53\x16\x6d\x0a\x84\xd6\x9a\x53\xa9\xd7\x4f\x94\xcb\xca\xa0\x67\x84\xaf\x36\x4b\x90\xf5\x35\x46\xf8\x0e\xb1\xc6\xd2\x00\x20\xf1\xbd\x25\x3d\x8c\x96\x0a\xf8\xc7\xcd\x16\x17\xdb\xa2\x30\xc2\x82\x37\x5c\x20\x1c\xd1\x62\x11\xbe\x2d\x85\x5a\xb5\x54\xd6\x4e\x98\x5a\x38\xba\x60\x4e\x34\xac\x0d\x94\x59\x7b\x81\x0f\x0a\xaa\x34\x5a\x45\x61\x24\x55\xa6\xe8\x0d\x78\x27\x02\x4f\x09\xf6\x3d\x7d\x50\xc7\x4a\x25\x76\x16\x12\x5d\x25\xac\x93\x35\x73\x12\x6a\x3a\x50\x1c\x67\xcc\xe7\x0f\x33\x1c\x60\x7b\x4d\x71\xf2\xe8\x39\x92\x32\xf4\x90\x74\x21\x94\xcd\xae\x26\x7e\xef\xd5\x26\x10\x19\x2a\x26\xe7\x0d\xa6\xb9\x4c\xfd\xcc\xe4\x71\xdc\x43\xc3\xbd\xca\x73\x4b\x46\xf2\x05\x23\x97\xba\x9f\xb2\x73\x22\x01\x2b\xb8\x37\xd2\x05\xc1\x01\xab\xa9\xf1\x2a\xcb\x63\xa2\x4d\x99\x52\xd0\x1c\xb4\x1f\x62\x1a\x80\xbb\x5d\x36\x1e\x43\x4e\xf0\x77\x86\x5d\xab\xc5\xc8\x66\xca\x26\xb6\xb5\x01\x07\x1c\x54\xd6\xbe\x7f\x8c\xad\x37\x03\x2b\xaa\xc1\xb8\xa3\xd6\x2c\x32\x42\x5e\xed\xea\x83\xd5\xb4\xb1\xfe\x92\x16\xf4\xf5\xbb\x61\xed\xf6\xda\x2f\x05\x35\xc1\xfd\x3f\x43\x5a\xfa\x95\x2e\xe1\xb3\xdb\xfe\x97\x64\xb1\xa7\xf8\x7b\xfe\xc3\x21\xd3\xde\x21\x9c\x5d\xee\xfb\xdb\x96\x29\x2f\x46\x97\x6d\x87\xd9\x1d\xc8\x2f\xc3\x39\xd6\x57\x1e\x06\x00\x00")
+
+func templatesDual_stackTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesDual_stackTf,
+		"templates/dual_stack.tf",
+	)
+}
+
+func templatesDual_stackTf() (*asset, error) {
+	bytes, err := templatesDual_stackTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dual_stack.tf", size: 1566, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesDual_stack_cf_dnsTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xdd\x92\xc1\x6a\xc3\x30\x0c\x86\xef\x79\x0a\x61\x7a\x68\xc7\x08\x1b\x63\x3b\x14\x4a\xd9\x73\x94\x62\x34\x5b\x5d\x0d\x4e\x6c\x6c\x27\xa5\x0b\x79\xf7\xc9\x4e\x47\xcb\x20\x63\xec\x38\x1d\x0c\x16\xff\x2f\xfd\xfe\x70\xa0\xe8\xba\xa0\x08\x04\x9e\xa2\x0c\xae\x4b\xf4\xfc\x24\x03\x29\x17\xb4\x00\x71\x32\x56\x2b\x0c\x5a\xea\x36\x4a\xe3\xfb\x17\x01\x43\x05\xf0\xe1\x5a\x92\x46\xc3\x06\xc4\x62\xb0\x4e\xa1\xad\xb3\xe0\xd2\x1e\x05\x4b\x5a\x6c\x08\xb8\x58\x72\x57\x2f\x86\x1e\x43\x1d\xcf\x31\x51\x23\xb5\x6b\xd0\xb4\x45\x94\xce\xfe\x4b\xf4\xca\x25\x2a\xee\xa1\x35\x18\xcb\x96\xeb\x90\x6f\x55\xd6\xe6\xbc\xf6\xad\x56\x87\x29\x75\xc8\x97\x1c\x22\x7b\xca\xf0\x6b\xcc\xdf\xda\x6f\xf3\x03\x50\x8f\xb6\xc3\x44\x32\x61\x78\xa7\x24\x8f\x84\x36\x1d\xd9\x7d\x40\x1b\x89\x25\x63\x35\x56\x55\xf8\x19\x20\x7a\x1f\xe5\x0c\x45\xe5\xba\x36\x4d\x69\x32\x9e\x22\x9d\xe0\xc0\x86\xbb\x02\xb6\xf0\x00\x6b\x78\x1c\x0b\x97\xbf\x31\xbf\x19\xfa\x5f\x88\xb3\xd9\x77\x09\x44\x06\x79\x79\x5a\x9c\x80\x66\x3b\xb1\x7c\xc7\xdb\x94\x6b\x3c\xaa\xb4\xb4\x26\xa6\xe5\xdc\x17\xbc\x87\x39\xf0\x7c\xac\x67\x28\xae\x56\xa3\xd8\x73\x90\x4f\x78\x46\x09\x09\x3b\x03\x00\x00")
+
+func templatesDual_stack_cf_dnsTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesDual_stack_cf_dnsTf,
+		"templates/dual_stack_cf_dns.tf",
+	)
+}
+
+func templatesDual_stack_cf_dnsTf() (*asset, error) {
+	bytes, err := templatesDual_stack_cf_dnsTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dual_stack_cf_dns.tf", size: 827, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesDual_stack_cf_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x5d\x8e\x3d\x0b\xc2\x30\x10\x86\xf7\xfc\x8a\x23\x38\x57\xa7\x0e\x05\x27\x5d\xdc\xc4\x55\x24\x5c\x93\x2b\x0d\x84\x24\xe4\xab\x48\xe9\x7f\xb7\xb1\x1d\xc4\x1b\xdf\x7b\xde\xbb\xa7\x60\xd0\xd8\x1b\x02\x6e\x7a\xa1\x6d\xef\xb2\x55\x42\xfb\xd2\x0a\xa9\x55\x88\x1c\x66\x06\x90\xde\x9e\x60\x9f\xf3\x4a\xea\x98\xf8\x1a\x2b\x1a\x30\x9b\xb4\xc7\x4f\xde\x75\xc7\x13\x7f\x7d\x17\x51\x06\xed\x93\x76\xb6\xf2\xb7\x7b\x69\xe1\x72\xbb\x3e\x22\xa0\x31\x6e\x22\x05\xc9\x41\x20\x94\x23\xa4\x91\x20\xb8\x9c\x28\x80\x71\xa8\xa0\x47\x83\x56\x52\xe0\x6c\x61\x6c\xcd\x7d\x4e\xc0\xe5\x20\x36\x46\x54\x49\x2f\x50\xa9\x40\x31\x8a\xea\xb5\x19\x16\x34\x99\xea\xaf\xc3\x8c\x53\x5c\xb1\xe6\xb7\xd3\xfc\x75\x96\x7a\xfd\x03\x2a\x17\x03\x17\xfa\x00\x00\x00")
+
+func templatesDual_stack_cf_lbTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesDual_stack_cf_lbTf,
+		"templates/dual_stack_cf_lb.tf",
+	)
+}
+
+func templatesDual_stack_cf_lbTf() (*asset, error) {
+	bytes, err := templatesDual_stack_cf_lbTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dual_stack_cf_lb.tf", size: 250, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesDual_stack_lbTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\x8e\xc1\x0a\xc2\x30\x0c\x86\xef\x7b\x8a\x10\x3c\x57\x4f\x1e\x06\x3e\x4b\x48\xdb\x20\xc1\xd2\x4a\x9b\x39\x64\xec\xdd\x9d\x1b\x28\x43\x73\xfa\x09\xdf\x97\xfc\x55\x5a\x19\x6a\x10\x40\x1e\x1b\xd5\x32\x98\x20\x60\xf2\x5b\x24\x63\x9f\x84\xf4\xfe\x38\x23\x4c\x1d\x40\x94\x66\x9a\xd9\xb4\xe4\x75\x4b\x41\x63\x25\x9f\x4a\xb8\xc1\x05\xb0\xef\x8f\x27\x5c\xb0\x2b\x9b\x8c\xfc\x24\x8d\xf0\x33\x0b\x76\x98\x16\x81\x93\xd3\x6c\x52\xb3\x18\x7d\xf9\xf9\xad\xef\x7e\xc7\x3f\xfa\xa7\xeb\x06\xb9\x7d\x5f\xb7\x9e\x99\xbb\x17\xfb\xf2\x90\xad\xdc\x00\x00\x00")
+
+func templatesDual_stack_lbTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesDual_stack_lbTf,
+		"templates/dual_stack_lb.tf",
+	)
+}
+
+func templatesDual_stack_lbTf() (*asset, error) {
+	bytes, err := templatesDual_stack_lbTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dual_stack_lb.tf", size: 220, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesDual_stack_nat_gatewayTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\x90\x4d\x0a\xc2\x30\x10\x85\xf7\x9e\x22\x04\x17\x2a\x12\x5d\xb9\x10\x3c\x4b\x98\x36\x43\x1d\x8c\x13\x49\xa6\xad\xb5\xf4\xee\x36\x2d\x14\x45\xb3\x9c\x3c\xbe\xf7\x13\x31\x85\x3a\x96\xa8\x34\xb4\xc9\xc6\x50\x0b\x6a\xa5\x19\xc4\x56\x20\xd8\x42\x37\xdf\x92\xa5\x47\x73\xd2\xaa\x5f\x29\x55\x86\x9a\x45\xfd\x7f\x17\xa5\xd7\xbd\x47\xae\xe4\xba\x69\x20\x1a\x68\x80\x3c\x14\xe4\x49\x3a\xfb\x0a\x8c\x69\x3b\xe8\x91\xe1\x30\x09\x8d\x2e\x14\x78\x22\xdb\x92\x5c\xb4\x85\x0f\xe5\x2d\x33\xce\xe7\xc3\x31\xcb\xb0\x8a\x98\x92\x0d\xec\xbb\x25\x0f\xb9\x0f\xab\x1c\xfa\x53\x44\x2c\x18\x19\x97\xf4\xe6\xeb\xb3\x32\xe4\x26\xfb\xa9\x93\x15\x28\x3c\x2e\xbc\xaf\x0a\xe8\xf1\x8e\x2c\x9b\x65\x94\x59\x6c\x7e\x86\x99\xef\xc9\xec\x46\xf4\x7e\x9e\xc6\x10\x3b\x7c\xe6\x9e\xc3\xea\x0d\x66\xa6\x98\xc3\x5f\x01\x00\x00")
+
+func templatesDual_stack_nat_gatewayTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesDual_stack_nat_gatewayTf,
+		"templates/dual_stack_nat_gateway.tf",
+	)
+}
+
+func templatesDual_stack_nat_gatewayTf() (*asset, error) {
+	bytes, err := templatesDual_stack_nat_gatewayTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/dual_stack_nat_gateway.tf", size: 351, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesIamTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbd\x57\x4b\x6f\xe3\x36\x10\x3e\xc7\xbf\x82\x10\x7a\x68\x83\xd8\x8d\x73\x29\x60\xec\xa2\x08\x12\x37\xe8\x0b\x0d\xec\x60\x0f\x0d\x02\x81\xa6\xc6\x36\x5b\x8a\x54\x49\xca\xa9\x1b\xf8\xbf\x77\x48\x4a\xf2\x4b\x94\xe3\x2e\xb6\x17\x3f\xf4\x7d\xf3\xa6\x66\x86\x2b\xaa\x39\x9d\x09\x20\xc9\x4c\x99\x65\xca\x69\x9e\x72\x69\x2c\x95\x0c\xd2\x42\xab\x39\x17\x90\x90\xb7\x1e\x21\x19\xcc\x69\x29\x2c\xf9\x48\x92\xa4\xb7\xe9\xf5\x84\x62\x54\x18\x0f\xa1\xd0\x63\xa0\xe2\xd7\x8a\x67\x90\x39\xd6\x57\x6f\x2b\xaa\x07\x51\xad\xe4\xa3\xd3\x44\xbe\x27\xd7\x64\x44\x86\x64\xe3\x95\x66\xd4\x52\x92\xd0\x57\x13\x71\xc4\x3b\x19\xfc\x91\x34\x87\x77\x98\x41\xbd\x48\x66\xaa\x94\x36\xb0\xbd\xdf\x83\x63\x97\x83\x03\x1a\x8c\x2a\x35\x83\xad\x13\x5a\x75\x1a\x06\xb9\x4a\x79\xb6\x49\xbd\x03\x9e\x8b\x94\x82\xda\xa5\xa3\x7c\x7b\x68\x7c\x48\xfa\xa4\xc3\x01\x24\x0b\x3e\x07\xb6\x66\x98\x1f\x67\x0b\x85\x35\x50\x0b\xe9\x0c\xe6\x4a\x43\x9a\x81\xb1\x5a\xad\x51\x99\xd5\x25\x20\x61\xe3\x64\xa8\x31\x65\x0e\xde\x7a\x5a\x28\xc1\x99\x23\x7c\xf8\x30\xfe\xed\x87\x9e\x53\x92\x7c\x02\x6d\xb8\x92\xc9\x88\x24\x37\xd7\xc3\x9b\xfe\xf0\xba\x3f\xfc\x2e\xb9\x72\xd0\xd4\xa2\xf6\x1c\xa4\x45\xf0\xd9\x1b\x0c\x66\x11\xba\x65\xb6\x12\x32\xd6\x8c\x6e\xbd\x8d\x89\x0b\xf0\xaa\x66\x3c\x6a\x2e\x19\x2f\xa8\x40\x52\x2d\xe6\x74\x82\x5e\x71\x06\x4e\x12\xd8\xcd\x20\xe4\x29\x93\x26\x35\xe5\x7c\xce\xff\xde\x24\x15\x75\xd3\x28\x1a\xcf\x31\x68\xe7\x42\x72\x2b\x84\x7a\xdd\x5a\x98\xf2\xcc\x3d\x0d\x12\x1b\xfc\x7c\xc1\x22\xb9\xb8\x5a\x4b\x15\x62\x7f\x6f\xb1\x2a\xf6\xe7\x95\xeb\x0b\xa4\xfb\x79\x9b\x49\x4c\x9f\x4b\xbc\x62\x1c\xc5\x6e\xb3\x0c\x43\x36\x4d\x72\x6a\xdc\x5a\xca\x96\x9f\x94\xc0\xf2\x1c\x62\x77\xaa\x58\xff\x98\xd3\xc5\x31\xe0\x4f\x55\xbb\xd0\x3d\x08\xb0\x30\x95\xb4\x30\x4b\x65\xdb\xd1\x98\xa4\x61\x9a\xcf\x6a\x4f\xc1\x44\x09\x2b\xca\x05\x9d\x71\xc1\xed\xfa\x77\x25\xe3\x44\xef\x7c\x1c\xad\xde\xf5\x28\x61\x02\x0b\xcc\x69\x14\x9e\x02\x2b\x35\xba\xf0\xa0\x55\x59\xc4\x59\x55\x26\xe2\x84\x72\x26\x21\x0e\x87\x5c\xb5\xc0\x1d\x75\xf3\xe5\x89\x95\x20\xa0\x4f\x74\x71\xa4\xf3\x57\x95\xf1\xf9\xba\x4e\x0b\x9e\x0c\xb4\x5f\xda\x23\xf5\x93\x52\x46\x53\xf7\x04\x3a\xe7\x12\xf5\x47\x19\x2e\xa9\xc6\x82\x6e\x3d\x58\xf7\xa0\xbb\xe0\x3b\xa7\x51\x4c\x0b\x65\x6b\xf5\x13\xf8\xab\xc4\xa6\x16\x4f\xee\x3b\xb8\xd5\xf3\x5d\xaa\x69\x4f\xda\x44\xb5\xa4\xa3\x39\x2d\x0e\x7c\x72\xc3\xb0\xc5\x42\x21\x28\xab\xc4\x7b\x17\xd8\x87\xae\xdc\x67\x4b\xe3\x72\x4f\x27\x55\x67\x72\xcf\x2f\xab\xde\x85\xc8\x9b\x07\x77\xde\xf3\x0b\xaf\x1f\x5b\xcb\xe8\x11\x5b\xb8\xef\xad\xe7\xea\xbe\xe8\x50\x0c\x82\x1a\xcb\x99\x50\x34\x9b\x51\x81\x59\xe1\x72\x31\xba\xfc\x4f\x26\xea\x64\x6c\xbb\x7c\x67\xdf\x8e\xb7\xb4\x06\xfb\x33\x37\x98\xd5\xb1\x64\x7a\x5d\xd8\xcb\x03\xc9\x86\xf1\x00\x12\x34\xd6\xed\x1e\xf7\x82\x9f\x61\x1d\xe5\x85\xea\x3e\x68\x2a\x6d\x8c\x52\x57\xd9\xab\xd9\xa3\xbc\x1c\xb8\xbd\x13\x7f\x8b\xe3\x87\xc2\xcd\xbf\x93\xe3\x69\x67\x3e\xa7\xd4\x77\x6d\x3f\x09\x76\xc7\x95\xa3\x54\xea\x4e\x6c\x18\x95\x1a\x2d\x03\x71\x7f\x04\xfa\x75\x68\x80\xe0\xe6\xcc\x89\xd6\xea\xf7\x19\x6b\x58\xe5\x6b\xdf\xe3\x75\x3c\x7b\x0e\xba\x27\xc1\x3d\x27\xb9\xf9\xfc\x05\x89\x2f\xa4\xdb\x8c\xd8\x92\x4a\x1c\x15\xa8\xe5\x39\x71\x9a\x93\x17\xbf\x1d\x1d\x05\x34\xc7\x73\x9a\x0a\xb5\x70\x41\xcc\x44\x88\x01\xff\xa6\x0b\x37\x03\xd2\x6d\x34\x8e\x8b\xaf\x4e\x99\xbd\x52\xcb\x96\x69\x43\x19\xa0\x54\xed\xba\xdf\x7c\x43\x59\x5d\x21\x48\x4b\xa4\xb5\x39\x53\x55\x83\x90\x55\xc1\x30\x45\xcd\xf9\xd9\xd9\x49\x03\xe2\x49\x56\x53\x5c\x95\x58\x6a\xd7\x05\x04\xd2\x64\xfc\xd3\xf8\xee\xa9\xa5\x42\x6d\x4e\xee\x06\xe7\x7c\xc5\xca\x01\x6e\x5e\xdb\x3a\xe1\x58\xd1\x36\xad\xab\x85\x72\xfd\x20\xd7\xb9\x02\x37\xb1\x74\x55\xde\x91\x9c\x42\xd3\x0f\x47\xf5\x8b\xad\xa7\xf5\x6a\x78\x7a\x89\x3c\xbd\xa6\x62\xe6\xb7\x8e\x0f\x68\x4e\xff\x51\x12\x83\x1f\x30\x95\x1f\x2f\xab\xd1\xbd\xb8\x77\x6e\x17\x38\x3f\xa7\xdb\x9d\x35\xf2\x66\x6d\xcf\x1b\xff\x5f\x36\x54\x67\xaa\xea\xbe\xbf\xa8\x85\x5f\xa4\x76\x67\xe7\x3e\x3c\xb5\xf8\x2b\x3f\xc2\x1f\x4b\x8b\xe0\x78\x85\x46\xcd\x11\x58\xb7\xed\x5a\x7b\x27\x23\x18\x30\x75\xcd\x5e\x4e\x9f\x8d\xb6\x51\xbd\x5f\x41\x1c\xf9\x45\x69\xfd\x98\x8e\xdc\x8c\x57\x54\x94\xd0\x7d\xb9\xc4\x6b\xee\x1f\x8a\xcb\xaf\xf1\xb8\x12\x77\xc7\x1d\xc4\x7a\x6b\x68\x8d\x97\xbe\xc3\x7c\x83\x17\xe3\x46\xea\x5d\x02\xbe\x83\xff\x0b\x91\xcb\x2c\xf9\xd2\x0f\x00\x00")
 
 func templatesIamTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -171,12 +469,12 @@ func templatesIamTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/iam.tf", size: 4046, mode: os.FileMode(480), modTime: time.Unix(1519221551, 0)}
+	info := bindataFileInfo{name: "templates/iam.tf", size: 4050, mode: os.FileMode(480), modTime: time.Unix(1519221551, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesIso_segmentsTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x58\xdd\x6f\xdb\x36\x10\x7f\xcf\x5f\x71\x10\xfa\x50\xb7\x8a\x20\x7f\x75\x4a\x81\x6c\x18\xda\xc7\xa2\x2b\xd0\x6e\x2f\x45\x41\x50\x14\x2d\x13\xa5\x49\x81\xa4\xbc\x25\x41\xfe\xf7\x81\xa4\xa2\xe8\xcb\xf2\x47\xdc\x2d\xc3\xfc\x60\xd8\x24\x8f\xf7\xbb\xbb\x1f\xef\x8e\xdc\x62\xc5\x70\xca\x29\x04\x4c\x4b\x8e\x0d\x93\x02\x69\x9a\x6f\xa8\x30\x3a\x80\xbb\x0b\x00\x73\x53\x50\xa8\x3e\xd7\x10\x68\xa3\x98\xc8\x83\x0b\x80\x8c\xae\x70\xc9\xcd\xc3\x44\xec\xc7\x34\x51\xac\xb0\xdb\xd8\xb1\xdf\xdc\x2f\xcc\xf9\x0d\x10\x45\xb1\xa1\x80\x81\x4b\x9c\x41\x8a\x39\x16\x84\x2a\xc0\x22\x83\xf7\x1f\x3f\x03\x15\x46\x31\xaa\x61\x25\x15\x60\xd0\x4c\xe4\x9c\x42\x0d\x09\x2a\x48\x11\xfc\x81\x39\xcb\x60\x8b\x79\x49\x35\x60\x45\x21\x06\xa9\x60\x1a\x05\x17\xf7\x17\x17\x2d\x63\x90\x91\x28\x95\x7a\x8d\x0a\xa9\xba\xb6\x5c\x43\xc0\x99\x36\x4d\x2b\xae\xe1\xeb\x6c\x16\xc2\x9b\xe4\x4d\x12\xc2\x6c\xb9\x5c\x86\xb0\x98\xd9\x91\xd9\x72\xb6\x8c\xbf\x0d\x6e\xaf\xd7\x58\xd1\x0c\x19\x52\x1c\xae\xe4\x2a\xbe\x8a\x43\xb8\x8a\xaf\xa6\x21\x24\x71\x32\x0b\x21\x99\xc7\xb1\xfb\xb6\x23\x49\x72\x15\x42\xb2\x58\xcc\x43\x98\xc7\x76\x7c\xe1\x7e\x27\x71\x12\x87\x30\x5f\x2c\x7f\xb2\xb2\xb3\xb9\xfb\x9e\x79\x88\xa3\xd8\xca\xec\x08\x6c\x15\x86\x79\x6c\x51\xbd\x89\xbd\xd5\x5c\x12\xcc\xb5\x93\xb6\x5b\xe3\x5b\x44\x64\x29\xec\xfa\xe0\xc5\xdd\x16\xab\xa8\x4f\x1c\xf8\x19\x62\xf8\x05\x38\x15\xb9\x59\xbf\xb4\x6b\xf0\x16\x33\x8e\x53\xc6\x99\xb9\x41\xb7\x52\x50\x3d\x81\xb7\x10\xdf\xbb\xb0\x29\xaa\x65\xa9\x08\x85\x00\xff\xa9\x91\x2e\x53\x41\x4d\xe0\x0d\xf1\x7f\x2a\xf0\x5e\x6f\xf3\xe3\x30\x38\x80\x51\x13\xdb\xbd\xb5\x6b\x5b\x10\xc4\xb2\x1d\xab\xfd\xa4\x5b\x47\x58\xa6\x50\xca\x25\xf9\xde\x5a\x67\x87\xbd\x76\x67\x80\x15\xb0\x43\x21\x2c\x42\x0f\x24\x62\x22\xa3\x7f\xc1\xeb\x7d\x66\xbe\x86\xe9\xc4\x29\xea\x4d\x7a\x45\x94\x53\xeb\xb4\x1d\xf2\x2d\x65\x76\x1f\x1b\x44\x9c\xfb\x78\x00\x7c\xc4\x1b\xfa\x18\x09\x2a\xb6\xd6\xaa\x4b\xa6\xe5\xa5\xc7\xfe\xe2\xae\x21\xee\x50\xdc\xf7\x3d\xae\x64\x69\x28\x32\x96\x3e\x08\x6b\x2d\x09\x73\xe1\x0c\x20\xf0\x33\xfb\x02\x31\x16\x05\x2f\x57\x07\xa2\x65\xf1\x63\xb4\xa3\x86\x8a\xe8\x55\xc4\xb2\x9e\xd9\x00\x4d\x94\x2c\xf3\x3b\x75\xd0\x47\x4c\x18\xaa\x04\xe6\xed\xc1\x6c\x88\x66\x94\xa7\x15\xc7\xdc\x5a\x85\xec\xff\x47\xe3\x46\xd8\xed\x83\x20\xac\xe7\x07\x3f\xb5\xa8\x5e\x4b\x65\x50\x33\x28\x5e\xd5\x25\x4f\x1d\xf1\x94\xd4\xda\x45\x19\xd9\x9c\x88\x7c\x4e\x64\x22\x87\x6b\x30\xaa\xa4\x56\xcb\x9a\x62\x6e\xd6\x88\xac\x29\xf9\x5e\x85\xdc\x0f\xdd\x20\xb3\x56\x54\xaf\x25\xcf\x9c\xca\xa5\x9b\x2b\x45\x7f\xf6\x1a\x66\x6e\xce\xf9\x66\x8b\x79\x1b\xea\xd4\x4f\x1a\xac\x72\x6a\x7a\x76\x7c\x79\xf7\xe9\x6d\xe2\x12\x3b\x80\x61\x1b\x2a\xcb\xee\xf9\x9b\x39\x4a\x5d\x00\xd8\x74\x42\x05\x55\x15\x4a\x26\xb4\xb1\x19\xde\x25\x9f\x6a\x6d\x12\x77\xa6\x94\x34\x92\x48\x6e\x35\xad\x8d\x29\xbc\x1e\x9e\x3e\xca\x40\x5b\xd2\x4e\x3d\xc8\xd4\x18\x1f\x24\x0f\x43\x31\x06\x63\x1f\x0e\xb8\x86\xc5\x62\xbe\x03\xc9\x83\xb0\xf6\xd2\x5a\x73\x44\xa8\x32\x6c\xc5\x08\x36\x6d\xc6\x32\xbc\x41\x9a\xaa\x2d\x55\xcd\x25\x11\x4f\xdd\xdf\x08\x2b\x71\x7f\x3e\x83\x0c\x19\xb7\x67\xd4\x20\xad\xf9\x79\xcd\xd1\x94\x94\xca\x26\xb7\x5c\xc9\xb2\xd0\xb6\xe8\x54\xbb\xb4\x67\x22\xb2\x7a\x3c\x97\xdd\x39\x7b\xa0\xbf\xd5\xb9\x45\x37\xcc\xa9\x37\xf3\x59\xc5\x8a\x36\x92\x8a\x95\xea\x97\x9b\xd6\xde\x0f\x65\xa7\x33\x78\x7c\x5e\x18\xce\xc9\x79\xa3\x30\x0d\x55\xa3\x7e\x07\xf5\x49\xb1\xad\xed\x9b\x7a\xad\xd0\x11\x95\xa0\x32\xe6\xd2\x1b\x33\x5c\x03\x86\xdd\xe0\x7b\x88\x1f\xe5\x0d\xb7\xfb\x29\x4e\xf9\xec\x24\xfb\x3e\xd1\x47\x38\xa5\x52\x7e\xbc\x6f\x90\x2a\x39\x0d\x86\xfa\xe5\xba\xe3\xf4\x2b\x0e\x72\x13\xbc\x6a\xf6\x0f\xbd\xb6\x75\x32\x68\xff\x97\x77\x9f\xc0\x28\xbc\x5a\x31\x02\x2b\x25\x37\xe0\xc9\x05\x46\x82\x15\x0d\xfa\x27\xad\xd1\x09\xd5\xe7\xb6\x73\xaa\x9c\xd2\x81\x93\xd6\xb9\x02\x74\x4b\x04\x13\xb9\xa2\xda\x65\xbd\x6e\x02\x69\x2e\xab\xd2\x90\x91\xbd\x24\xd4\x40\xd5\x6c\x85\x7a\xae\x18\x68\x09\xac\xed\x83\xfb\x9d\xb4\x9b\x0f\x79\x37\xda\xcd\x4c\xd7\xf5\x4e\x2f\x53\xec\xe8\x35\x8e\x21\x50\xe3\x4e\xf1\x54\x1a\x75\xaf\x27\x47\x93\xa9\x73\x4a\x4f\x61\xd5\xce\x34\xf2\x0c\xb8\xd5\xf5\xcf\x39\x18\x76\xc0\x9e\xcf\x8a\x67\xf6\x7e\x78\x26\x9e\xd5\x57\xcd\x61\x9e\xfd\xfe\xfe\xbf\xce\xb3\x32\x7b\x12\xcf\x6a\xff\x9c\x91\x67\x63\x7b\x3e\x0f\x9e\xb9\x94\x8b\x39\x47\x55\xec\x8f\x61\xdb\x20\x8f\x7e\xfd\xf0\x61\x6f\xf1\xcb\x68\x41\x45\xa6\x91\x14\x3d\x77\x7e\x1d\xb0\x60\xa8\xf6\xf9\x0e\xf3\x79\x15\xd1\xcb\xe9\x1e\xae\xc4\xe3\xf4\x8c\xff\x05\x56\x54\x44\xcd\x18\xcd\x25\x4a\x53\xc7\x09\x1f\x69\x9a\x21\x42\x39\xd7\x4f\x66\x44\xaf\x82\x79\x9d\xe0\x74\x42\x9a\xea\x3a\xc7\xe4\x27\xb1\x63\xe0\x46\x70\x12\x39\x76\x79\xf2\x9c\x45\x70\x84\x1c\xd3\x24\x9e\x8e\xf3\xa3\x5a\x71\x1a\x45\x76\x27\xdf\x03\x99\x22\xb0\xf9\x01\xe4\xe8\xa5\x0b\x81\x4d\xb3\xec\x9c\x58\x6f\x2c\xd8\xff\xcd\x39\x97\xa5\x29\x4a\x03\x01\x59\xa1\xd6\x83\x19\xb2\xd7\x3b\x1f\x1c\xf7\x22\xdf\xae\x56\x44\x0a\x82\xfd\x2b\x1f\xe5\x69\xd4\x92\x8c\x5e\x45\x56\x36\x74\xef\x1b\x2f\x83\x60\x32\x09\x21\x9e\xb4\xb5\xf5\x01\x21\x96\x1d\xa2\x6d\xbf\x61\xfe\x89\x71\x8f\x6e\x7c\x8b\xea\xd7\x4b\xb4\xc1\x45\xc1\x44\xde\x53\xef\xae\x99\xb7\xac\xd8\xe0\xe2\x65\xfb\xf1\xa1\xfd\xa4\xd9\x7b\xd9\xbd\x0f\x42\x18\x13\xb0\xbe\x9f\xd8\xfb\xe8\x08\x2e\xf7\x74\xfd\x8f\x23\x7b\x7c\x30\xdf\x85\x70\x30\x17\x3c\x21\x78\x83\xa9\x65\x57\x0c\xff\x0e\x00\x00\xff\xff\xe3\x14\x91\x63\xd2\x1a\x00\x00")
+var _templatesIso_segmentsTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xe5\x59\x4b\x6f\xe3\x36\x10\xbe\xe7\x57\x0c\x84\x1e\xe2\xac\x23\xc8\x8e\x9d\x3a\x05\xd2\xa2\xe8\x1e\x17\xdb\x05\xb6\xed\x25\x08\x08\x5a\xa2\x6d\xa1\xb4\x24\x90\x94\xdb\x24\xf0\x7f\xdf\x21\x29\xcb\x7a\xd0\xf2\x2b\x69\x53\xd4\x07\xc1\x26\x39\x33\xdf\xcc\x7c\x9a\x21\xe9\x15\x15\x31\x9d\x72\x06\x5e\x2c\x53\x4e\x55\x9c\x26\x44\xb2\xf9\x92\x25\x4a\x7a\xf0\x72\x01\xa0\x9e\x32\x06\xc5\xe7\x1e\x3c\xa9\x44\x9c\xcc\x3d\x9c\x88\xd8\x8c\xe6\x5c\x6d\x26\x02\x3b\x26\x43\x11\x67\x5a\x8d\x1e\xfb\xd5\x7c\xa3\x9c\x3f\x41\x28\x18\x55\x0c\x28\xf0\x94\x46\x30\xa5\x9c\x26\x21\x13\x40\x93\x08\x3e\x7e\xfe\x0a\x68\x4f\xc4\x4c\xc2\x2c\xc5\x31\x90\x68\x02\x31\x95\x90\xa0\x80\xe4\xc3\x1f\x94\xc7\x11\xac\x28\xcf\x71\x31\x15\x0c\x02\x40\x89\x81\xef\x5d\xac\x2f\x2e\x56\x55\x67\x88\x4a\xc9\x34\x95\x0b\x92\xa5\xa2\xe9\x0b\x42\xe3\xb1\x54\x55\x2f\xee\xe1\x61\x38\xec\xc3\xed\xe4\x76\xd2\x87\xe1\x78\x3c\xee\xc3\x68\xa8\x47\x86\xe3\xe1\x38\x78\x74\xaa\x97\x0b\x44\x10\x11\x15\x66\x87\x1b\xb9\x0b\xee\x82\x3e\xe0\x73\xd0\x87\x49\x30\x41\x03\x93\x9b\x20\x30\x4f\x3d\x32\x99\xdc\xe1\x73\x34\xba\xe9\x03\x0e\xe3\xf8\xc8\x7c\xc7\x95\xf8\xfd\x66\x34\xfe\x5e\xcb\x0e\x6f\xcc\x73\x68\x21\x76\x62\xcb\xa3\x23\xb0\x15\x18\x6e\x02\x8d\xea\x36\xb0\x5e\xf3\x34\xa4\x5c\x1a\x69\xad\x9a\x3e\x93\x30\xcd\x13\xbd\xde\xfb\xee\x05\xcd\xfa\x6d\xe2\xc0\x8f\x98\x96\x9f\x80\xb3\x64\xae\x16\x97\x7a\x0d\x5d\xd1\x98\xd3\x69\xcc\x63\xf5\x44\x9e\xd3\x84\xc9\x1e\xfc\x00\xc1\xda\xa4\x4d\x30\x99\xe6\x22\x44\xec\xf4\x2f\x49\x64\x3e\x4d\x98\xf2\xac\x23\xf6\x47\x01\xde\xda\xad\x7e\x0c\x06\x03\xd0\xaf\x62\x5b\x6b\xbf\x56\x59\x48\x90\x2a\xee\xd5\x76\xd2\xac\x0b\xe3\x48\x90\x29\x0e\xff\x59\x5b\xa7\x87\xad\x75\xe3\x80\x16\xd0\x43\x18\xf2\xbe\x05\xe2\xc7\x49\xc4\xfe\x86\x0f\xfb\xdc\xfc\x00\x83\x9e\x31\xd4\x9a\xb4\x86\x18\x67\x3a\x68\x3b\xe4\x6b\xc6\xb4\x1e\x9d\x44\x3a\x97\x56\x76\xc9\xc4\x9c\x5d\x5a\x97\xf4\x68\x1f\x96\x34\xbb\xf4\x3e\xd3\x25\xf3\xfa\x9b\xfc\xb0\x64\xa5\x7d\xbd\xc6\x08\x5d\x5b\x8f\xd0\xbb\xad\xd2\xb5\xd7\xeb\xb9\xf2\x20\xd2\x5c\x31\xa2\x34\xa9\x08\x95\x32\x0d\x63\x93\x64\x4c\x8c\x9d\xd9\x97\x9e\xae\xdc\x58\xb9\x32\x3d\xb5\x38\x6c\x39\xe0\x57\x4c\xf8\x57\x7e\x1c\xb5\x82\x01\x50\x45\x89\xea\x8c\xa6\x06\x7a\x5c\xae\x98\xc0\x2a\x54\x1f\x8c\x5c\x4e\x33\x3e\x2d\x98\x67\xd6\x0a\xa2\x7f\x6f\x9d\xeb\xe0\xbc\x4d\x4d\x82\x91\x07\xe7\xa7\x14\x95\x0b\x7c\x1d\x49\x35\x29\xd6\xd4\x35\x9a\xd2\x86\x44\x2a\xa5\xc9\x3d\xd1\x95\x92\xd8\x4a\x89\xf5\x10\x35\x28\x91\x33\x6d\x65\xc1\x28\x57\x0b\x12\x2e\x18\x92\x56\xa3\xdb\x0c\x3d\x11\xb5\x40\x87\x16\x29\x8f\xaa\x26\x0b\x5f\x5a\x6b\x4c\x04\x01\xf2\xa4\x2d\xdd\x94\x75\xac\x29\xa4\x4d\x74\xb1\x20\x3b\x9d\xad\x59\xb6\x80\xc9\x46\xa0\x90\x57\x14\x39\xac\x76\x04\xcb\x25\x6f\x05\x36\xd2\xf1\x92\xe1\x9a\x23\xa4\xad\x80\x11\x5f\xeb\x60\xea\x3a\xc8\x12\x6c\x45\x2f\x85\x3b\x52\xe9\xd6\x64\xaa\x66\xa1\x6e\x12\x34\xa6\x44\xaa\xd2\x30\xe5\xda\xd2\x42\xa9\xcc\x42\xe1\xd3\xad\x0c\xd4\x25\xf5\xd4\x46\xa6\xc4\xb8\x91\x3c\x0c\x45\x17\x8c\x7d\x38\x70\x1e\xbb\xc8\x0e\x24\x1b\x61\x69\xa5\xa5\xe4\x04\x1b\xb3\x8a\x67\x71\x48\x55\xfd\xa5\x8a\xe9\x12\xf9\x2e\x56\x18\xd3\xca\x12\x1f\x75\xea\x9f\x3e\x15\xc9\xfa\xf5\x1c\xc2\xae\xda\xe9\x4f\xa7\x43\xe8\xc5\xeb\xba\x23\x59\x98\x0b\x5d\x95\xe7\x48\xaa\x4c\x97\xdf\x87\x42\x4b\x7d\xc6\x0f\x67\xdb\xd2\xd1\x9c\xd3\x35\xe7\xb1\x2c\x7f\xb2\xe2\x4e\xa9\xcc\x16\x3e\x2d\x5a\xa9\x7b\x5a\xea\xbc\xba\xbf\x2d\x31\xee\x62\x5f\x07\xba\x69\xbe\x8d\xc1\xe3\xeb\xe0\xbd\xb3\x07\xcd\x2b\xed\xd9\xd5\x93\xdb\xfb\xc8\x2f\x22\x5e\xe9\xdd\x63\x6b\x43\x78\x76\x3f\x2c\x5c\xbc\xb6\x2e\x1e\x13\x1c\xbb\xbf\x7a\xab\x18\x19\xed\xa7\x84\xea\xab\x91\x6c\x47\x4a\x9e\x1d\xaa\x02\xd2\xf1\x11\x23\x22\xe7\xcc\x73\x9d\x30\xca\x3d\xba\x5d\x71\x50\xf0\xe0\xaa\xba\xe3\x6a\x6d\xf4\x7b\xce\xa8\xfc\xf6\xcb\x17\xec\x9e\x74\x86\xaf\x38\xcc\x44\xba\x04\x4b\x44\x50\x29\x68\x51\xaf\xfd\x8a\x57\xf6\x8e\x65\xc1\x68\xbc\xce\xc6\xa8\xe3\x15\x6f\x1c\x9a\x9a\xbd\x09\x7b\x39\x46\xcb\x94\xdb\x66\xe5\xaa\x2e\x2b\xea\x1f\x3a\xd7\xac\x7e\x8e\x4d\x93\x33\x14\x8e\xed\x92\xf6\xdd\xa9\xef\x24\x6d\x36\xe5\xa4\x1d\xb8\x5d\x11\x6b\x57\x95\x1d\xfb\xb0\x63\x08\x54\x39\x85\x9d\x4b\xa3\xe6\x81\xee\x68\x32\x35\xde\xdd\x53\x58\xb5\xb3\xb8\xbc\x03\x6e\x35\xe3\xf3\x1a\x0c\x3b\x40\xe7\xbb\xe2\x99\x3e\x51\xbf\x12\xcf\xca\xc3\xb9\x9b\x67\xbf\x7f\xfc\xaf\xf3\x0c\x1d\x3c\x87\x67\x65\x7c\x5e\x91\x67\x5d\x3a\xdf\x07\xcf\x4c\xc9\xa5\x9c\x93\x22\xf7\xc7\xb0\xcd\xc9\xa3\x9f\x3f\x7d\xda\xdb\xfc\x22\x96\xb1\x24\x92\x04\x25\x9a\xe1\x7c\xf0\x0e\xeb\x7d\x76\x6b\xfb\xbe\x9a\xe8\xf5\x60\x0f\x57\x82\x6e\x7a\x06\xff\x02\x2b\x0a\xa2\x46\x31\x9b\x23\x19\xa6\x86\x13\x36\xd3\x38\x1a\x32\xce\xe5\xd9\x8c\x68\x75\x30\x6b\x13\x8c\x4d\x40\x9b\x65\x8d\x99\x9f\xc4\x0e\xc7\xe9\xe1\xf1\xe4\x1a\xf5\xc6\x4d\xb0\x83\x1c\x83\x49\x30\xe8\xe6\x47\xb1\xe2\x34\x8a\xec\x2e\xbe\x07\x32\x25\xa1\xea\x0d\xc8\xd1\x2a\x17\x68\xa6\xda\x76\x4e\xec\x37\x1a\xec\xff\xe6\x3d\xc7\xe3\x76\x96\x2b\xf0\xc2\x19\xa9\x5d\x26\x12\x7d\xe8\xb3\xc9\x31\xff\x61\xd4\xbb\x55\x98\x26\x21\xb5\x37\xa0\x8c\x4f\xfd\x9a\xa4\x7f\xe5\x6b\xd9\xbe\xb9\x58\xb9\xf4\xf0\xe0\xd5\x87\xa0\x57\xb7\xd6\x06\x84\xf0\x0f\xb1\xb6\xdf\x31\x7b\xfd\xba\xc7\x36\x7d\x26\xe5\xcd\x2e\xc1\xe3\x64\xa6\xff\x29\x6a\x9a\x37\xd7\x32\xcf\x71\x66\x8e\x9b\xb5\x5b\x8f\xfa\x75\x6f\xeb\x2e\x7c\x6d\xcf\xa5\x1d\xf7\xc3\x6b\xaf\xa7\xef\x6b\x3a\x70\x99\xcb\xfe\x7f\x1c\xd9\xf6\x2f\x86\x5d\x08\x9d\xb5\xe0\x8c\xe4\x39\x4b\xcb\xae\x1c\x7e\x03\xba\xf1\x26\x7c\x04\x1c\x00\x00")
 
 func templatesIso_segmentsTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -191,12 +489,12 @@ func templatesIso_segmentsTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/iso_segments.tf", size: 6866, mode: os.FileMode(480), modTime: time.Unix(1515385070, 0)}
+	info := bindataFileInfo{name: "templates/iso_segments.tf", size: 7172, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesLb_subnetTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\x4d\x6e\xfb\x20\x10\xc5\xf7\x3e\xc5\x08\x65\xf1\xff\x48\x68\xd4\x55\x37\xb9\x42\x2f\x50\x45\x08\xe3\xa9\x83\x4a\x20\x32\x63\xa7\xa9\xe5\xbb\x57\x80\x15\xdb\xb5\xd3\xa6\xc9\xc6\x02\xe6\xf7\xde\x63\x86\x0a\xbd\xab\x2b\x85\xc0\xe4\xd9\x0b\x5f\xe7\x16\x89\x01\x33\x79\xff\xed\x19\xb4\x19\x80\x72\xb5\x25\x18\xff\x76\xc0\x56\xad\x41\x5b\xd2\xe1\x4f\x23\x2b\x2e\x1b\xa9\x8d\xcc\xb5\xd1\x74\x11\x1f\xce\xa2\xff\xdb\xb1\x0c\xa0\x39\x29\xa1\x8b\x79\xa5\x53\xd2\xf0\xb4\x19\xcf\x29\x5d\x54\x22\x37\x4e\xbd\x4d\xce\x85\xe5\xe4\x24\xaa\x84\x82\xb0\xb4\x86\xa7\x75\x32\xc5\xb5\x2d\xf0\xfd\xff\x63\x52\x9b\xb9\x48\x14\x34\x78\x44\x4b\x37\x8c\x4e\x48\x81\x93\x01\x90\x2c\x7d\x4c\x0e\xf0\x2c\x8f\x3d\x26\x94\xa3\x6d\x82\xe5\x8d\xc9\x37\xc9\xd7\xaa\x1d\x55\x47\x13\x5d\x00\x18\xfd\x8a\xea\xa2\x0c\xf6\x14\x5d\x5a\x57\xa1\x50\x07\x69\x4b\xf4\xb0\x83\x17\x36\x44\x66\x6b\x60\x33\x5f\x6c\x1f\x59\x5d\x96\x4d\x9b\x54\xb9\x9a\x50\x90\xcc\x0d\xa6\x4e\x4d\x16\xda\xe1\xce\x97\x2e\x7a\x99\x76\x83\x53\xa0\x27\x6d\x25\x69\x67\xc5\xa8\x3f\x3b\x60\x5b\x1e\xff\x0f\xdb\x90\xb7\x94\x84\x67\x79\xf9\xd2\xe6\x24\x1f\x24\xb4\x25\xac\x2c\x92\xe8\x0f\x72\x5d\xf2\xbe\xeb\x23\xc9\x71\xf9\xb5\x74\xb4\xcf\xa7\x0e\xf9\x37\x71\x7a\xa0\xf4\xde\x29\x1d\xed\x33\x60\x69\xe7\x87\xc1\xbe\x77\xaa\x13\xe3\x6a\x79\x32\x64\xc3\x43\xe2\x83\x1a\xff\xc7\x75\x31\x1b\xb4\xd9\x05\xfc\x26\xb8\xab\xe9\x54\xd3\xe8\xad\x0a\x5d\xf4\xa9\x1a\x69\x6a\x8c\x33\x96\x68\xcb\x76\x3a\xb6\x5f\xe6\xcc\x53\xdf\x8f\x9d\xd5\xde\x54\x89\x4f\xfb\x7e\xf0\x30\x80\x89\xf8\x19\x00\x00\xff\xff\x0b\x56\xd0\x1c\xba\x04\x00\x00")
+var _templatesLb_subnetTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x55\x31\x6f\xdb\x3c\x10\xdd\xf5\x2b\x0e\x84\x87\xe4\xfb\x1c\x35\xe8\xd4\x25\x53\xbb\x74\x68\x87\x76\x0c\x02\xe2\x44\x9e\x65\xa2\x34\x29\x90\x94\x1c\x55\xd0\x7f\x2f\x48\x0a\x91\x6c\xd9\xa8\x83\xda\x8b\x71\xe4\xbd\x7b\xef\xee\x1d\xdd\xa1\x53\x58\x69\x02\xa6\x2b\xae\x4c\x65\x5b\x23\xb9\x50\xd2\x79\x06\x43\x01\x10\xfa\x86\x60\xfa\x3c\x01\xd3\xca\x07\x56\x00\x48\xda\x61\xab\xc3\x14\x7e\x66\x8f\x65\xfa\x7e\x78\x64\x2f\xe9\xd4\x0b\xa7\x9a\xa0\xac\x81\x27\x60\x9f\xbf\x7e\xf9\xe1\x01\xb5\xb6\x47\x92\x10\x2c\x38\x42\xb1\x87\xb0\x27\xd0\x16\x25\x54\xa8\xd1\x08\x72\x9e\x15\x63\x51\x5c\x64\xd4\x38\xda\xa9\x57\x1e\xcb\x73\x25\xdf\xc5\xed\x02\xa1\x6f\x68\xb0\x26\x09\x19\x15\x62\xe2\x4d\xfc\x4a\xf8\x49\x01\xce\x1b\x15\x15\xa1\x01\x3a\x34\xa1\x4f\x58\x29\x10\xd5\x82\x35\xba\x8f\x38\x9e\xca\xa4\xcd\x91\xb7\xad\x13\x04\x0c\x8f\x9e\xfb\xb6\x32\x14\x58\x12\x9a\x7f\x4f\xc2\x84\x6d\x4d\x98\x84\xbd\xc9\xdb\x0c\x9a\x4c\x1d\xf6\x77\x1d\xba\x12\x3b\x54\x1a\x2b\xa5\x55\xe8\xf9\x6f\x6b\xc8\xdf\x8f\x51\x7b\xd7\x08\xae\xe4\x3a\xd3\x0a\xd4\x65\x3e\x4c\xf7\xe2\x84\x79\xa5\xad\xf8\x75\x72\x2f\x86\x33\x93\x54\x25\x26\xc4\xd0\x16\x3e\x6d\x33\xa9\x52\x19\x49\xaf\xff\x7f\xcc\xd5\x56\x2c\xe2\x18\x36\x03\x69\x3a\x90\x09\x57\x88\x9e\x20\x45\x9c\x38\x49\xac\x7d\xce\x3d\x90\xab\xe9\x2e\xf3\x0d\x58\xfb\x2d\x1c\xb0\xb9\x63\xdf\xf1\x40\x6c\x1b\x2f\x44\x50\x32\x1d\x57\x72\x7c\xd0\xd5\x43\x66\xbb\x19\x16\x98\x23\xbb\x9f\x60\xb5\xda\x91\xe8\x85\xa6\xd4\x55\x00\x55\x1b\xeb\x88\x8b\x3d\x9a\x9a\x62\xc1\x67\x36\x37\x22\xc2\xaf\xd8\x26\x3b\x8f\xeb\xd1\x39\xdb\x06\xe2\x21\xfa\x34\xcf\xef\x24\x30\xcc\x93\xb8\xd4\xfe\x7f\x12\x9c\x0a\x3d\xe4\x42\x49\xe7\x65\x6e\x57\x58\x49\xf2\x41\x19\x8c\x9b\xc9\x17\x1e\x78\x82\xc5\x06\x17\x00\x35\x06\x3a\x62\x7f\x66\xa5\xa5\x97\x94\x09\xe4\x0c\x05\x3e\x5f\x4d\x96\x58\x54\x5c\x66\xa7\xcc\xb3\xc6\x95\xa7\x04\x4b\x25\xaf\xaa\x99\x00\xd1\x7b\x2b\x54\x62\xcf\x80\xe5\x93\xbf\xec\xce\xad\x8b\x93\x7d\xf4\x46\xf9\xc4\xc7\xf3\xae\x96\x73\xb5\xf2\xbf\x52\xc9\x95\x97\x57\x0d\x78\x8f\x70\xdb\x86\xa6\x0d\x8b\xe7\x60\x7e\xea\x3a\xd4\x2d\x25\xc3\x6e\x86\xeb\x74\x46\xf6\x72\x19\x67\xad\xfa\x76\xd8\x55\xee\xd5\x2a\x8b\xbf\x8d\x5b\x80\x67\xff\x8d\xec\xa5\x18\x8b\x3f\x03\x00\x45\x99\xc6\x4a\x8b\x06\x00\x00")
 
 func templatesLb_subnetTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -211,12 +509,92 @@ func templatesLb_subnetTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/lb_subnet.tf", size: 1210, mode: os.FileMode(480), modTime: time.Unix(1515385070, 0)}
+	info := bindataFileInfo{name: "templates/lb_subnet.tf", size: 1675, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesMinimalTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\x51\x0a\xc3\x20\x0c\x40\xff\x3d\x45\x08\xfb\x76\xbd\x40\xcf\x12\x52\x0d\x23\x4c\x14\x34\xa5\x8c\xe2\xdd\xc7\x2a\x6c\x7e\x2c\xf9\x0b\x79\xbc\x57\xa5\x95\xbd\x06\x01\xe4\xa3\x51\x2d\xbb\x09\x02\x6a\x36\xa9\x99\xd3\x38\x90\xf1\x96\x04\xe1\x74\x00\x51\x9a\x69\x66\xd3\x92\x29\x68\xac\xb4\xa5\x12\x9e\xb0\x02\x2e\xfe\xda\xfb\x82\x0e\xe0\xc1\x26\x07\xbf\x48\x23\xcc\xb3\x02\xde\xce\x54\x02\x27\x3f\x14\x62\xf4\x7b\xed\x1f\x72\x32\xce\xf4\x45\x7e\x13\x47\x91\xff\x97\xe9\x35\x76\x74\xdd\xbd\x07\x00\x8e\x2e\x26\xf6\xd9\x00\x00\x00")
+
+func templatesMinimalTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesMinimalTf,
+		"templates/minimal.tf",
+	)
+}
+
+func templatesMinimalTf() (*asset, error) {
+	bytes, err := templatesMinimalTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/minimal.tf", size: 217, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesNatTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x56\x4b\x6f\xa3\x30\x10\xbe\xe7\x57\x20\xd4\x43\x2a\x05\x36\x90\x10\xc8\x61\x0f\xfb\x07\x7a\xda\x5b\x55\x21\x63\x06\x62\x95\x97\x8c\x49\x15\x45\xf9\xef\x3b\xb6\x81\xf0\x48\xaa\x56\xdd\x4a\xbb\xc9\x25\x19\xbe\x79\x7c\xdf\x8c\x3d\x1c\x09\x67\x24\xca\xc0\x30\x0b\x22\x42\x92\xb3\x30\x27\x95\x69\x9c\x17\x86\x21\x4e\x15\x18\x3f\x0d\x53\x1a\x16\xf8\x3f\x86\x84\x34\x99\x40\x93\x7c\x6a\x18\xa4\xb2\x8a\x92\x8b\x03\x90\x5a\x58\x8e\x44\xa2\xbb\xe5\xac\xe3\x84\x06\xbe\x6f\xce\x31\x6e\x8f\x21\x4e\x44\xb7\xfe\xb6\xc7\xd4\x65\x23\x0e\x18\x43\x7e\x5a\x8c\xbf\xa5\x4e\xb0\x73\xa2\x31\x66\x9c\x6b\xb3\x23\x89\xbb\xf6\xbc\x1b\x98\x6b\x2e\xd8\x3b\x81\xe3\xc7\x1a\x43\x89\x45\xa1\x10\x9c\x64\x2a\x5b\x87\x71\x63\x0c\xe5\xef\x34\x06\x9a\x5b\x98\x3d\x44\xe0\x04\x89\xd3\x63\xde\x40\x95\x32\xac\x79\x43\x82\xed\x3e\xf1\xe8\x18\xe3\x8e\x30\xae\xe3\xb8\xeb\xed\xb6\xad\xb9\xa9\xad\x96\xd2\x10\x13\x6f\xa9\x07\x09\x75\xc7\x98\x71\x9c\xc4\xf5\x23\x8f\xec\xfd\x1e\x93\x96\xc7\xbe\xa6\x16\x43\x37\xfb\x9d\xb3\x26\xd7\x38\x37\x6a\x8e\x02\x3f\xf1\x36\x71\x30\xc6\x8c\x73\x05\x51\x42\x21\x48\x54\x9c\xcb\xe2\xb2\x58\x1c\x47\x53\xc3\x8a\x5a\x90\x82\x42\x28\x27\x66\x30\x3b\x3a\x40\x2d\x38\x2b\x52\x73\x34\x40\xa6\x70\xed\x1c\x62\xd6\xe4\xa6\x0c\xc7\x01\x1b\xc7\x29\x86\x23\x6f\x75\x58\x03\x6d\x38\x13\xa7\x30\xe5\x65\x83\xb3\xa8\x72\x4c\x8d\x32\x49\x41\x72\x30\x8c\xbe\xd2\x87\x33\x96\x65\x43\x71\x0c\x59\x7c\xb1\xd0\xc9\xea\x9c\x2c\xed\xa4\x4a\xa8\x29\x67\x95\x60\x65\x21\x5d\x9e\x7e\xfd\x96\xd6\x63\x45\xd1\x67\x10\x28\x2b\x29\xc9\x6c\x6d\xbe\xa8\xe1\x17\x24\xad\xf5\xb3\x1c\x78\x0a\x4b\x8d\x90\xd6\x95\x81\x07\x64\x69\x3e\x61\x31\xe6\xea\x03\x55\x3c\x3e\xea\x88\x19\x4b\x80\x9e\x28\x8a\xa8\x8f\x13\x4b\xf1\xa4\x40\x48\x0f\xa4\x48\x41\xe6\x7a\x36\x25\x41\xf3\xa5\x13\xfd\x3d\x95\x42\xde\x64\xd0\x4a\x25\x4a\xec\x88\x00\x5e\x80\x68\xcd\x32\xc1\x04\x8f\x74\x15\x9b\x79\x28\x7b\x2e\xb7\xdd\xab\xd0\xb6\xb5\x15\x0a\x52\xac\xa9\x96\x0a\x26\xbc\xcc\xc3\x0a\x4f\xba\x7a\xb0\x96\xd0\xb2\xfb\xdf\x59\x2a\x5e\x8a\x92\x96\x59\xeb\x6c\xa9\xa3\x44\x59\xcc\xc3\x08\xd5\x7c\xd5\x94\xd7\xb6\xfa\xfe\x58\x23\xef\x4f\x70\x66\x34\xaf\xbe\x99\x2c\x0e\x71\xc7\x76\xc2\x44\x26\x9f\x8b\x60\x39\x33\x15\x94\xe9\x2f\x31\x16\xf4\x5b\x09\x8f\x3e\xf7\xd9\x4f\x61\x58\xd5\x54\x89\x09\x64\x3a\x1b\x93\xc7\x3b\xcf\xdb\x78\x92\x90\x12\x21\xfc\x38\x2f\x3d\xf2\x24\xbb\x49\xee\x13\xba\x36\xf1\xbf\xa8\x2b\x56\xf5\x5f\xe8\xda\xad\x02\x2d\xa6\xd6\xb0\xe2\xec\x48\x04\x84\xac\x9a\xb2\x7a\x38\xcb\xc3\x70\x28\x6b\xb1\x54\x99\x9b\x08\xef\x2c\x3b\x2a\xeb\x43\xf7\xfb\x7a\x58\x56\x86\x2f\x2f\x4e\xbc\x27\x87\xdb\x66\x12\x4e\x5e\xbd\xb3\x8d\xa4\xbc\x74\xbc\xfe\x96\x1f\x79\xdd\xc9\xad\x18\xf6\x8a\xe1\xe6\x10\x78\x39\x03\x7d\xed\x3c\x13\x92\xd5\x80\x00\xdc\x90\x77\x9a\x2b\x17\x49\xf9\xda\x54\xcb\xae\xae\xf6\xfd\x6a\x65\x48\x03\x87\x14\xd7\x90\x26\x25\x57\xcd\xac\x27\xfa\x7e\xf8\xcc\xb4\xbd\x7c\x6d\x55\xe9\xdd\x34\x6b\x2a\xb0\x6e\x0b\xab\x5f\x67\xb5\x47\x2b\x28\xe2\x3a\x54\x6b\xf4\xb9\x6d\x7d\xbb\x73\x52\x6c\xf6\x1b\x39\xd9\x2c\x55\xbb\xab\xeb\xc5\x50\xed\xce\x26\x79\x74\x3a\xa3\x04\x57\xe9\x04\x6f\xe0\x8b\x6b\x97\x55\x77\xe8\xa0\x5a\x42\x0e\x68\x3f\xd9\xca\x10\x0a\xf9\x42\xd3\xb1\xab\x05\xc3\x18\xd8\x9e\xf0\x3a\x81\xb2\x8e\xeb\x65\x3d\x9c\xc4\xf1\x54\xbd\xcb\x72\x90\x6b\xe8\xd6\xfb\x0c\x9e\xdb\xb7\x0a\xec\xcf\x1d\x9a\xaa\x46\x4c\xda\x72\x24\x59\x03\xd7\x60\x68\xb7\xdb\xe7\x76\xd5\x44\x19\xc3\xf7\x99\x4a\xb9\xff\x01\xd8\x92\x2a\xa5\xf7\x0b\x00\x00")
+
+func templatesNatTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesNatTf,
+		"templates/nat.tf",
+	)
+}
+
+func templatesNatTf() (*asset, error) {
+	bytes, err := templatesNatTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/nat.tf", size: 3063, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesNat_gatewayTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x55\xcb\x6e\xdb\x30\x10\xbc\xeb\x2b\x08\xa2\x07\xa7\xb5\x59\xe7\xd6\x4b\x7f\xa1\x3f\x10\x04\xc2\x8a\xda\xca\x44\x68\x52\x20\x29\xa5\x6e\xa0\x7f\x2f\x1f\x42\x4c\x59\x12\xa2\xa4\xb1\x4f\x5e\xed\xce\xee\xce\x8c\xd6\x06\xad\xee\x0c\x47\x42\xe1\xd9\x96\xb6\xab\x14\x3a\x4a\xa8\x02\x37\xfe\xb0\x94\xbc\x14\x84\x70\xdd\x29\x47\xf2\xcf\x4f\x42\xbf\xbc\x48\x54\x8d\x3b\xed\x7a\x30\x0c\x7a\x10\x12\x2a\x21\x85\xbb\x94\x7f\xb5\x42\x7b\x37\x50\x5f\xd9\xb7\xbc\x14\xf5\xbc\x52\x73\x90\x2c\x3d\x8c\x79\x5c\xd4\xa6\xac\x7c\xf8\x69\x92\x17\xc2\x69\x92\xd8\x25\x14\x84\xd0\x9e\xfc\xd8\xa7\xa1\x98\x50\x35\xfe\xf9\x76\x7f\x4c\xed\x66\x63\x24\x18\x94\x78\x46\xe5\x56\x26\x9d\x40\x05\x1c\x0f\xe4\xa0\xb1\xa9\xf6\x8c\xa6\xc1\x5d\x1a\x38\x44\xf7\xe4\x0c\xed\x8e\xfe\x82\x33\xd2\x7d\x48\x08\xa0\xa8\xfa\xb0\xc9\xc1\x33\x77\x48\xf3\xfa\xd9\xaf\xa0\x03\xbd\x1b\x71\xa5\xf8\x8d\xfc\xc2\x25\x46\x5e\x09\x11\x8d\xd2\x06\x4b\x7e\x02\xd5\x60\xe8\xf8\x40\xaf\x54\x04\xfc\xd9\xb8\xf4\xd1\x17\x0e\xc5\x50\x14\x66\xa2\x9e\xd1\x9d\xc3\xd2\x41\x25\xb1\x04\x6b\x35\x17\xe0\x84\x56\x5e\xce\xf4\xe4\x2d\x51\xb7\x2a\x9a\x30\x5e\x45\x9d\xf0\x7b\x75\x11\xcb\xda\xb1\xaf\x4c\xd4\x33\x92\x09\xc9\x27\xf6\x70\x11\xe9\x66\x13\x56\x69\x7b\x9a\x04\xa2\x5f\x66\xcb\xa3\x68\x47\xdf\x36\xe0\xf0\x19\x2e\x21\x32\xdf\x73\xeb\x8e\x35\xb6\xa8\x6a\x5b\x6a\x15\x15\x09\x1d\x84\x72\x68\xc2\xe2\x63\x03\x26\x9a\xa8\x84\xb7\xe4\x95\x41\x67\x3a\xfc\x4f\xf7\x8c\xf0\x07\xd1\x2e\x39\x68\xb6\x78\xb6\xf2\x94\x80\x45\x91\xb7\xee\x0f\x32\x4c\x1c\xec\xf3\x2a\x4c\x2e\xb1\xe7\x96\xdd\x72\xbd\x26\xf2\xd4\x2e\x1f\x76\xcb\xa7\x90\xba\x89\xd1\xcc\x6c\x37\x96\xca\x9e\x4c\xd9\x7d\xe7\x31\x5c\xba\x80\x9f\x63\x9a\x38\xe1\x21\x4e\xb8\x7d\xd7\xa5\x2d\x57\xef\xfe\x3b\x5f\x23\xeb\x84\x4a\x3e\xca\x0e\xbc\x2f\x3f\xb2\xf8\xfd\x7e\x0c\x69\x79\xf7\xec\xbf\x62\xe6\x95\x2c\x2f\xb7\xdf\xd6\xfb\xb2\x8a\x9b\x9f\x97\x35\xbd\x97\x7b\x78\x42\x7d\x4e\xdb\xb9\xb5\xdb\xd3\x83\xec\x30\xde\x90\x74\xda\x56\xde\x9c\xb6\xab\xa4\xf0\x4e\x68\x07\x7f\x53\x86\xe2\x1f\xb6\x63\x13\x9b\x97\x07\x00\x00")
+
+func templatesNat_gatewayTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesNat_gatewayTf,
+		"templates/nat_gateway.tf",
+	)
+}
+
+func templatesNat_gatewayTf() (*asset, error) {
+	bytes, err := templatesNat_gatewayTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/nat_gateway.tf", size: 1943, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesS3_blobstoreTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbd\x55\x4d\x4f\xdb\x40\x10\xbd\xfb\x57\x8c\x56\x1c\x92\x2a\x31\x84\x1e\x2a\x59\x20\x04\x2a\xed\x05\x15\x04\x52\x2f\x08\x59\x6b\x7b\x4c\x96\xda\x5e\x6b\x77\x1d\x88\x22\xff\xf7\xce\x7a\x6d\xe3\x10\x53\xe8\x87\xca\x01\xc4\x7c\xbc\x79\x33\xf3\x66\xbd\xe2\x4a\xf0\x28\x43\x60\xfa\x63\x18\x65\x32\xd2\x46\x2a\x0c\xa3\x2a\xfe\x81\x86\xc1\xc6\x03\x30\xeb\x12\xa1\xfd\x39\xa6\x38\xa3\x44\x71\xcf\xc8\x91\x60\xca\xab\xcc\x74\x0e\x67\xd2\xb1\x12\xa5\x11\xb2\xb0\xa6\xf3\x27\xa1\x0d\x45\x83\xc3\x83\x54\x2a\x30\x4b\x84\xbe\x10\xc8\xb4\x31\x24\x42\x61\x4c\x06\x1f\xa2\x28\x83\x58\x21\x37\xa8\x41\x16\x08\x8f\x4b\x2c\x40\x18\x10\x1a\x30\x2f\xcd\xda\x67\x5e\xed\x79\x99\x8c\x79\xa6\x1b\x7a\x2e\xf8\xac\x43\x3c\x73\x95\xa8\xf8\xde\x66\xc5\x95\x3f\xd2\x16\x1c\x5b\xb6\x70\x02\x0b\x08\xe0\xa0\xb6\xbc\xa3\x17\xe9\x7d\xb7\x7b\x9b\xa6\x94\x3f\x5e\xe5\x04\x1e\xa4\x28\x26\x8c\xcd\x80\x3f\xea\xd0\xd6\x6a\x1c\x7e\x8f\xe7\x7f\xf0\x45\x32\xa5\x3a\xaf\x70\xa9\x9b\x76\x14\x6a\x59\xa9\x98\xb6\xb0\x05\xc3\x80\xf5\xf1\x6e\x17\xce\x1e\x96\x0a\x53\xf1\xd4\xf1\x7b\x44\x35\x69\xe0\x97\x52\x99\x10\x8b\x55\x48\x25\xeb\x79\x9f\x3a\xb7\x1d\xd2\xe8\x63\x0c\x69\x3f\x46\xc9\x35\x65\x1a\x55\xa1\x67\xc7\x27\xab\xc2\xbc\xd9\x29\xd1\xb4\x4a\xe0\xf7\xda\x85\xe6\xa8\xee\x71\xe2\x12\xac\x75\x06\x39\x2f\x27\xec\x1b\xcf\x91\x66\xd1\x8e\xde\x31\x19\x10\x61\xd3\xe9\x58\xbf\x82\xe7\x61\x29\x33\x11\xaf\x77\x1b\x2e\x08\xf1\x79\x99\x2d\xe2\x60\x8a\x6d\x1e\x45\x96\xdc\x2c\x6d\xe4\x7e\x43\xd5\xd9\xe9\xff\xa3\xa3\xf3\xcb\x2f\x9e\x85\x62\xdf\x51\x69\x12\x26\x0b\x80\x1d\x1e\x2c\x0e\xe7\x8b\x83\xf9\xe2\x13\x9b\x59\xd7\x8d\xa1\x9e\x73\x2c\x0c\x39\x6f\x3d\xbb\xfb\x8d\xe7\x34\xc0\x4e\x63\xe3\x92\x6e\xbd\xee\x0a\xe8\x56\x82\x0b\x52\xb6\x9b\x4d\x83\xf0\xec\xf8\x8a\xad\xfd\x82\xa6\xd3\xa4\xb6\xee\xbb\x2e\x8e\x9d\xa7\x29\xa9\xdd\xf2\x38\xcd\x68\x7d\x3d\x00\xbb\x6e\xe7\x62\x5d\x5c\x15\x81\xeb\xba\xe4\xca\x08\x8b\x54\x07\x84\x1f\x04\xdd\xa6\xa2\x9d\x1d\x59\x90\x7a\xf6\x2e\xfe\x44\xf3\x32\x7a\xb0\x34\xb6\xe9\x5f\x55\xe3\xf6\xcf\x98\xa1\xc1\xd6\xf5\xbf\x3a\xda\xff\xd0\xf6\x44\xbf\xef\x48\x38\x76\x95\xa3\xf2\x51\x32\xeb\xb4\x10\x72\x63\x78\xbc\x6c\x96\x49\x72\x92\x7a\x19\xbe\xd0\x94\x0d\x1e\x5e\xf8\xd6\x0d\x77\x68\xbe\xcd\xa4\xeb\xb5\x02\x9c\x36\xa3\xed\xe0\x55\xe1\xd2\xb6\xa5\x3b\x38\x79\x8a\x70\xf7\x32\xb8\xad\x05\xcc\xc1\xf5\x48\x29\x57\x4a\xa6\x22\x43\xfa\xb3\x12\x09\x26\xaf\xdd\x84\xa5\xf1\xfb\x17\xd1\x64\xed\xdc\x03\xd7\xba\xca\x71\x38\xa7\x7f\x7a\x1b\xf4\x55\xd0\xc1\x69\x53\xe3\xda\xd6\xef\xf7\x7f\x45\x1f\x8b\x58\x94\x3c\xa3\xa0\xcd\xb3\xa0\x6e\x50\xad\x84\x53\x05\xc6\x87\xbe\xeb\x25\x29\xe8\xed\xab\x52\x7a\xd9\xea\x4e\x61\xf5\xdb\x0a\xbb\x11\x89\xb5\xfe\xb5\x50\x7e\xad\x91\x6d\x69\xf4\xab\xb6\xcb\xf8\x13\x71\x8c\x72\x13\x85\x36\xbc\xa0\x57\xba\x74\xfa\x78\xe7\xee\x07\xef\x6b\x47\xfc\x1d\x94\x89\x81\xac\x4c\x59\x99\x41\x91\xad\xcf\xfe\x8a\x67\x15\x0e\x3f\x0c\xbb\xcf\xcd\x28\xc6\x6e\x17\x2f\xd0\x5e\x6b\x77\x8c\xe3\x4f\x96\xb0\xc8\xb2\x9f\x08\x00\x00")
+
+func templatesS3_blobstoreTfBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesS3_blobstoreTf,
+		"templates/s3_blobstore.tf",
+	)
+}
+
+func templatesS3_blobstoreTf() (*asset, error) {
+	bytes, err := templatesS3_blobstoreTfBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/s3_blobstore.tf", size: 2207, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesSsl_certificateTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x8f\x41\x6e\xc3\x20\x14\x44\xf7\x9c\x62\x84\xba\xee\x0d\x72\x16\x84\xf1\xb8\xf9\x2a\x31\xd1\x87\xd0\xa2\x88\xbb\x57\xc6\x1b\xa7\x92\x37\x61\x83\x04\xf3\x46\x6f\xaa\x57\xf1\x53\x24\x6c\xce\xd1\x05\x6a\x91\x45\x82\x2f\xb4\x78\x1a\xa0\xb4\x3b\x71\x81\xcd\x45\x65\xfd\xb2\xa6\x1b\x73\x4a\xb8\x70\xf5\xb2\xbe\xc1\xdd\x55\xea\x76\x7f\xb3\x9d\xd2\xca\x9c\x1e\x1a\x08\xeb\x7f\xb2\x13\x7f\x73\x99\x5a\xa9\xaf\xca\x36\x4e\xe3\x61\xaf\x59\xfd\x6d\x2b\xe7\x22\xbf\x5b\xdb\xc7\xb3\x7a\xfd\xcc\xd7\xa4\xc5\x71\xad\x4e\xe6\x6e\x8d\x01\x8e\x2a\x53\x9a\x1b\x0e\xe1\x57\xd3\x6e\xff\xc5\xc7\xe2\xd3\xf8\xfe\x3d\xa0\xc3\x44\xec\xe7\x14\x3a\x44\x77\xbf\x28\x0b\x43\x0b\x91\x63\x14\x10\x94\x43\x95\x4b\x52\xba\x99\xb9\x68\x6a\xb8\xa0\xe8\x83\x06\xe8\xa6\x9b\xbf\x00\x00\x00\xff\xff\x4f\x95\x65\x5c\xd6\x01\x00\x00")
+var _templatesSsl_certificateTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\x51\x6e\x84\x30\x0c\x44\xff\x73\x0a\xcb\xda\x6f\x6e\xb0\x67\x89\x4c\x30\x5d\xab\xd9\x04\x39\x21\x2d\x42\xb9\x7b\x15\xa8\x2a\x5a\x35\x3f\xcb\x27\x99\x37\x9a\x19\x17\x52\xa1\xd1\x33\x60\x4a\xde\x3a\xd6\x2c\xb3\x38\xca\x8c\xb0\x1b\x80\xbc\x2d\x0c\x77\xc0\x94\x55\xc2\x1b\x9a\x6a\x4c\x97\xb0\xee\x41\x12\x5e\xe0\x16\x95\xd2\xf8\x77\xde\xba\xb4\x72\x8a\xab\x3a\x06\xa4\x8f\x64\x85\x9e\x36\xb1\x16\xd6\xab\x11\x02\xfa\xf1\xf8\x71\xda\x04\x7a\xb2\x5d\x94\x67\xf9\x6c\x6e\xb7\xbd\x90\x0e\xe9\x11\x35\x5b\x0e\xc5\xca\x54\xd1\x18\x80\x6b\x94\x31\x4e\x1b\x5c\xc4\xbf\x93\x56\xfc\x23\x3f\x1a\x77\xe5\xe7\x20\x07\x74\xa9\x08\xe7\xd7\x85\x2e\xd2\x33\x9f\x97\x99\xdd\xe6\x3c\x1f\xa5\x00\x9c\x72\x7b\x1f\x79\x8e\xca\x76\xe2\x94\x35\x6e\x70\x87\xac\x2b\x1b\x80\xda\x8e\x14\xd7\xbc\xac\xf9\x67\x0f\xdb\xa6\x38\x47\x29\xe4\xd7\xe3\xa4\xb7\xbd\xbf\xe4\xf0\xcd\x0d\x8d\xab\xf8\x9f\x23\x69\x78\xc5\x90\x34\x54\x34\xd5\x7c\x0d\x00\x03\xec\x5a\x7a\x78\x02\x00\x00")
 
 func templatesSsl_certificateTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -231,12 +609,12 @@ func templatesSsl_certificateTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/ssl_certificate.tf", size: 470, mode: os.FileMode(480), modTime: time.Unix(1511800392, 0)}
+	info := bindataFileInfo{name: "templates/ssl_certificate.tf", size: 632, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesVpcTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x91\xd1\x6a\xe3\x30\x10\x45\xdf\xf5\x15\x17\xb1\x0f\xc9\xb2\x6b\xb2\xaf\x81\x6c\xff\xa0\xfd\x04\xa1\x48\x53\x67\x5a\x65\x64\x24\xd9\x6d\x08\xfe\xf7\x22\xd9\x69\x21\xf4\xa1\x03\x36\x66\xe6\x0e\xf7\x8c\xef\x64\x13\xdb\x63\x20\x68\x7a\xe7\x5c\x58\x7a\x33\x0d\xce\xb0\xd7\xb8\x2a\xa0\x5c\x06\xc2\x5a\x07\xe8\x5c\x12\x4b\xaf\x15\xe0\xe9\xd9\x8e\xa1\xdc\x06\x4b\x2b\xbb\xc4\x43\xe1\x28\xb5\xf5\xd4\xbe\x6c\x08\x17\x8c\x99\x60\x05\x37\x07\x4c\x83\xd3\x6a\x56\x2a\x44\x67\x43\x6e\x46\xd5\xd4\xc5\x51\x4a\x5d\xfd\x75\x0d\x24\x7d\x39\x6d\x26\x9b\xba\x3b\xae\x2d\xfe\x63\x87\x07\xec\xb0\xc7\xbf\x59\xaf\xab\xec\x57\x90\x9f\xac\x7e\x33\xc2\x1e\x2f\x91\x65\xa3\xa1\xff\xc0\xbe\xe5\xda\xee\xea\xf3\xbb\x63\xbf\x9d\x1b\x6d\xa2\x1c\xc7\xe4\x08\x7a\x15\x68\xe8\xf6\xae\xfc\x0b\xfb\x5d\x2d\x3c\xf5\xc8\xee\xf3\xbe\x86\xec\xd8\x27\x73\x0c\xd1\xbd\xde\xab\x2b\x5b\xd3\xb2\x4f\x4d\xca\x92\x8b\x15\x47\xa6\x90\x58\x71\x97\x9b\x74\x0d\xa0\x4a\x48\x6a\x82\xc6\x4b\x36\xa7\x98\x8b\xd8\x33\x65\x1c\x50\xd2\x48\xaa\x66\x68\xfb\xe5\x1f\x03\x8f\xf6\x4c\x5f\x3e\x24\x93\x61\x3f\xff\x6d\x71\x00\xb3\x9a\xd5\x47\x00\x00\x00\xff\xff\xe1\xdc\x0f\xba\x0f\x02\x00\x00")
+var _templatesVpcTf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x52\xef\x6a\xdb\x30\x10\xff\xae\xa7\xf8\x21\xfa\x21\x1d\x89\x69\xbf\x16\xba\xbd\xc1\xf6\x00\x63\x98\xab\x74\x75\xb4\x29\xb2\x91\xce\xee\x42\xf0\xbb\x0f\x29\xd6\x9a\x39\x2d\x6c\x2c\x10\x63\x4b\x77\xf7\xfb\x73\xbf\x89\xa2\xa3\x27\xcf\xd0\xfc\xd3\x25\x71\xa1\x6b\xa7\xc1\xb4\xce\x6a\x9c\x14\x20\xc7\x81\xb1\xfc\x1e\xa1\x93\x44\x17\x3a\xad\x00\xcb\xcf\x34\x7a\xa9\x17\xe7\xa3\x64\xa2\x1b\xc4\xf5\x21\x1f\x7d\x29\x6f\xe4\xfd\x11\x63\x62\x50\x40\x45\xc0\x34\x18\xad\x66\xa5\x7c\x6f\xc8\xa7\x02\x94\x41\x4d\x3f\x06\xa9\x68\xe7\xb9\x37\x27\xcf\xa1\x93\xfd\x66\xa2\xd8\xac\x18\xde\xe2\x23\xee\xf0\x09\x77\x78\xc0\xfd\xac\x97\x21\xce\xd6\xf6\x7f\x1a\xf2\xc6\x15\x1e\xf0\xbd\x77\x61\xa3\xa1\xb7\xa0\x97\x94\x8f\x9b\xfc\xff\xd0\x38\x7b\x5b\x00\x5d\x10\x8e\x81\xa5\xed\x48\xf8\x85\x8e\xb9\xeb\x2f\x01\x5f\x47\x5b\x12\x6a\xf2\xfc\xf5\xb4\xd7\x56\xd7\x9d\x41\xaf\x28\x5d\xb5\xd4\xca\xb9\x18\x1c\x39\xf5\x63\x34\x0c\xbd\xf0\xd7\xd0\xe5\x99\x2d\x5f\xdb\x7d\x61\x57\xde\x4b\xf3\x7b\x25\x45\xaa\x71\x36\xb6\x4f\xbe\x37\x3f\xd6\xd5\x59\x64\xa9\x75\x36\x2e\xae\x24\xa1\x60\xb8\x15\x0e\x14\xcc\xb1\x96\x2e\x99\xc9\x25\x1c\x72\xe8\x5a\x1b\x52\xbb\xef\x93\x04\x3a\x70\xc2\x23\x24\x8e\xac\x72\xec\xa8\xcb\x9f\xfa\xe6\x74\xe0\xd8\xf1\xa6\x04\xa5\x11\xea\xd2\x16\x07\x1a\x36\xfa\x33\x1d\x58\x6f\x2b\x3a\x87\xa9\x75\x76\xde\x65\x69\xb7\x6f\x4a\x5f\xfb\xa4\xa1\x5d\xf7\x87\x0d\xef\x0a\x5f\xc2\xb0\xba\x77\x76\xd6\xff\xc1\xb4\xf2\xd9\x55\x3e\x95\x76\x0e\xc3\xbb\x94\x2f\xf2\x70\xc9\xbd\x50\xbb\xc7\x0e\xd7\xf4\x15\xf0\xec\xbc\x70\x2c\xe5\x40\x36\xfa\xbc\x0c\x12\x21\xb3\x3f\x70\x90\xac\x67\xe7\x6c\x5e\x0b\x30\x91\x1f\xcb\x2a\xbe\x56\xc6\x15\xb3\xaa\xfe\xa6\x80\x59\xcd\xea\xd7\x00\x90\x40\x0c\x34\x3a\x04\x00\x00")
 
 func templatesVpcTfBytes() ([]byte, error) {
 	return bindataRead(
@@ -251,7 +629,7 @@ func templatesVpcTf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/vpc.tf", size: 527, mode: os.FileMode(480), modTime: time.Unix(1515385070, 0)}
+	info := bindataFileInfo{name: "templates/vpc.tf", size: 1082, mode: os.FileMode(480), modTime: time.Unix(1539648000, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -308,13 +686,31 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"templates/acm_certificate.tf": templatesAcm_certificateTf,
+	"templates/acm_dns_certificate.tf": templatesAcm_dns_certificateTf,
 	"templates/base.tf": templatesBaseTf,
+	"templates/bosh_lite.tf": templatesBosh_liteTf,
 	"templates/cf_dns.tf": templatesCf_dnsTf,
+	"templates/cf_dns_existing_zone.tf": templatesCf_dns_existing_zoneTf,
+	"templates/cf_dns_zone.tf": templatesCf_dns_zoneTf,
+	"templates/cf_elbv2_lb.tf": templatesCf_elbv2_lbTf,
 	"templates/cf_lb.tf": templatesCf_lbTf,
+	"templates/cf_sni_lb.tf": templatesCf_sni_lbTf,
+	"templates/cf_tcp_lb.tf": templatesCf_tcp_lbTf,
 	"templates/concourse_lb.tf": templatesConcourse_lbTf,
+	"templates/dns_role.tf": templatesDns_roleTf,
+	"templates/dual_stack.tf": templatesDual_stackTf,
+	"templates/dual_stack_cf_dns.tf": templatesDual_stack_cf_dnsTf,
+	"templates/dual_stack_cf_lb.tf": templatesDual_stack_cf_lbTf,
+	"templates/dual_stack_lb.tf": templatesDual_stack_lbTf,
+	"templates/dual_stack_nat_gateway.tf": templatesDual_stack_nat_gatewayTf,
 	"templates/iam.tf": templatesIamTf,
 	"templates/iso_segments.tf": templatesIso_segmentsTf,
 	"templates/lb_subnet.tf": templatesLb_subnetTf,
+	"templates/minimal.tf": templatesMinimalTf,
+	"templates/nat.tf": templatesNatTf,
+	"templates/nat_gateway.tf": templatesNat_gatewayTf,
+	"templates/s3_blobstore.tf": templatesS3_blobstoreTf,
 	"templates/ssl_certificate.tf": templatesSsl_certificateTf,
 	"templates/vpc.tf": templatesVpcTf,
 }
@@ -360,13 +756,31 @@ type bintree struct {
 }
 var _bintree = &bintree{nil, map[string]*bintree{
 	"templates": &bintree{nil, map[string]*bintree{
+		"acm_certificate.tf": &bintree{templatesAcm_certificateTf, map[string]*bintree{}},
+		"acm_dns_certificate.tf": &bintree{templatesAcm_dns_certificateTf, map[string]*bintree{}},
 		"base.tf": &bintree{templatesBaseTf, map[string]*bintree{}},
+		"bosh_lite.tf": &bintree{templatesBosh_liteTf, map[string]*bintree{}},
 		"cf_dns.tf": &bintree{templatesCf_dnsTf, map[string]*bintree{}},
+		"cf_dns_existing_zone.tf": &bintree{templatesCf_dns_existing_zoneTf, map[string]*bintree{}},
+		"cf_dns_zone.tf": &bintree{templatesCf_dns_zoneTf, map[string]*bintree{}},
+		"cf_elbv2_lb.tf": &bintree{templatesCf_elbv2_lbTf, map[string]*bintree{}},
 		"cf_lb.tf": &bintree{templatesCf_lbTf, map[string]*bintree{}},
+		"cf_sni_lb.tf": &bintree{templatesCf_sni_lbTf, map[string]*bintree{}},
+		"cf_tcp_lb.tf": &bintree{templatesCf_tcp_lbTf, map[string]*bintree{}},
 		"concourse_lb.tf": &bintree{templatesConcourse_lbTf, map[string]*bintree{}},
+		"dns_role.tf": &bintree{templatesDns_roleTf, map[string]*bintree{}},
+		"dual_stack.tf": &bintree{templatesDual_stackTf, map[string]*bintree{}},
+		"dual_stack_cf_dns.tf": &bintree{templatesDual_stack_cf_dnsTf, map[string]*bintree{}},
+		"dual_stack_cf_lb.tf": &bintree{templatesDual_stack_cf_lbTf, map[string]*bintree{}},
+		"dual_stack_lb.tf": &bintree{templatesDual_stack_lbTf, map[string]*bintree{}},
+		"dual_stack_nat_gateway.tf": &bintree{templatesDual_stack_nat_gatewayTf, map[string]*bintree{}},
 		"iam.tf": &bintree{templatesIamTf, map[string]*bintree{}},
 		"iso_segments.tf": &bintree{templatesIso_segmentsTf, map[string]*bintree{}},
 		"lb_subnet.tf": &bintree{templatesLb_subnetTf, map[string]*bintree{}},
+		"minimal.tf": &bintree{templatesMinimalTf, map[string]*bintree{}},
+		"nat.tf": &bintree{templatesNatTf, map[string]*bintree{}},
+		"nat_gateway.tf": &bintree{templatesNat_gatewayTf, map[string]*bintree{}},
+		"s3_blobstore.tf": &bintree{templatesS3_blobstoreTf, map[string]*bintree{}},
 		"ssl_certificate.tf": &bintree{templatesSsl_certificateTf, map[string]*bintree{}},
 		"vpc.tf": &bintree{templatesVpcTf, map[string]*bintree{}},
 	}},
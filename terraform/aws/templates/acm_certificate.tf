@@ -0,0 +1,3 @@
+variable "ssl_certificate_arn" {
+  type = "string"
+}
@@ -0,0 +1,28 @@
+resource "aws_acm_certificate" "lb_cert" {
+  domain_name               = "${var.system_domain}"
+  subject_alternative_names = ["*.${var.system_domain}"]
+  validation_method         = "DNS"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-lb-cert"))}"
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "aws_route53_record" "lb_cert_validation" {
+  zone_id = "${local.dns_zone_id}"
+  name    = "${aws_acm_certificate.lb_cert.domain_validation_options.0.resource_record_name}"
+  type    = "${aws_acm_certificate.lb_cert.domain_validation_options.0.resource_record_type}"
+  records = ["${aws_acm_certificate.lb_cert.domain_validation_options.0.resource_record_value}"]
+  ttl     = 60
+}
+
+resource "aws_acm_certificate_validation" "lb_cert" {
+  certificate_arn         = "${aws_acm_certificate.lb_cert.arn}"
+  validation_record_fqdns = ["${aws_route53_record.lb_cert_validation.fqdn}"]
+}
+
+output "lb_cert_arn" {
+  value = "${aws_acm_certificate_validation.lb_cert.certificate_arn}"
+}
@@ -1,24 +1,3 @@
-variable "nat_ami_map" {
-  type = "map"
-
-  default = {
-    ap-northeast-1 = "ami-10dfc877"
-    ap-northeast-2 = "ami-1a1bc474"
-    ap-south-1     = "ami-74c1861b"
-    ap-southeast-1 = "ami-36af2055"
-    ap-southeast-2 = "ami-1e91817d"
-    ca-central-1   = "ami-12d36a76"
-    eu-central-1   = "ami-9ebe18f1"
-    eu-west-1      = "ami-3a849f5c"
-    eu-west-2      = "ami-21120445"
-    us-east-1      = "ami-d4c5efc2"
-    us-east-2      = "ami-f27b5a97"
-    us-gov-west-1  = "ami-c39610a2"
-    us-west-1      = "ami-b87f53d8"
-    us-west-2      = "ami-8bfce8f2"
-  }
-}
-
 variable "access_key" {
   type = "string"
 }
@@ -27,127 +6,138 @@ variable "secret_key" {
   type = "string"
 }
 
+variable "session_token" {
+  type    = "string"
+  default = ""
+}
+
 variable "region" {
   type = "string"
 }
 
-variable "bosh_inbound_cidr" {
-  default = "0.0.0.0/0"
+variable "max_retries" {
+  default     = 25
+  description = "How many times the aws provider retries a failed or throttled request."
 }
 
-variable "availability_zones" {
-  type = "list"
+variable "endpoint" {
+  default     = ""
+  description = "The url that the aws provider sends its requests to in place of the AWS endpoints, such as an AWS-compatible API."
 }
 
-variable "env_id" {
-  type = "string"
+variable "endpoints" {
+  type        = "map"
+  default     = {}
+  description = "The urls of the endpoints of single services, such as ec2 or iam, in place of endpoint."
 }
 
-variable "short_env_id" {
-  type = "string"
+variable "partition" {
+  default     = "aws"
+  description = "The partition of the region, such as aws-us-gov or aws-cn, that the ARNs of its resources start with."
 }
 
-variable "vpc_cidr" {
-  type    = "string"
-  default = "10.0.0.0/16"
+variable "dns_suffix" {
+  default     = "amazonaws.com"
+  description = "The domain of the service principals of the partition, such as amazonaws.com.cn."
 }
 
-resource "aws_eip" "jumpbox_eip" {
-  depends_on = ["aws_internet_gateway.ig"]
-  vpc        = true
+variable "bosh_inbound_cidr" {
+  default = "0.0.0.0/0"
 }
 
-resource "tls_private_key" "bosh_vms" {
-  algorithm = "RSA"
-  rsa_bits  = 4096
+variable "bosh_inbound_cidrs" {
+  type        = "list"
+  default     = []
+  description = "More CIDRs allowed to reach the jumpbox. Set bosh_inbound_cidr to an empty string to allow only these."
 }
 
-resource "aws_key_pair" "bosh_vms" {
-  key_name   = "${var.env_id}_bosh_vms"
-  public_key = "${tls_private_key.bosh_vms.public_key_openssh}"
+variable "bosh_inbound_prefix_list_ids" {
+  type        = "list"
+  default     = []
+  description = "Managed prefix lists allowed to reach the jumpbox. Set bosh_inbound_cidr to an empty string to allow only these."
 }
 
-resource "aws_security_group" "nat_security_group" {
-  name        = "${var.env_id}-nat-security-group"
-  description = "NAT"
-  vpc_id      = "${local.vpc_id}"
+variable "availability_zones" {
+  type = "list"
+}
 
-  tags {
-    Name = "${var.env_id}-nat-security-group"
-  }
+variable "env_id" {
+  type = "string"
+}
 
-  lifecycle {
-    ignore_changes = ["name"]
-  }
+variable "short_env_id" {
+  type = "string"
 }
 
-resource "aws_security_group_rule" "nat_to_internet_rule" {
-  security_group_id = "${aws_security_group.nat_security_group.id}"
+variable "minimal" {
+  default     = false
+  description = "Gives the VMs on the internal subnets public IPs, for environments without a NAT instance."
+}
 
-  type        = "egress"
-  from_port   = 0
-  to_port     = 0
-  protocol    = "-1"
-  cidr_blocks = ["0.0.0.0/0"]
+variable "director_mbus_port" {
+  default     = 6868
+  description = "Port that bosh create-env reaches the director agent on through the jumpbox."
 }
 
-resource "aws_security_group_rule" "nat_icmp_rule" {
-  security_group_id = "${aws_security_group.nat_security_group.id}"
+variable "vpc_cidr" {
+  type    = "string"
+  default = "10.0.0.0/16"
+}
 
-  type        = "ingress"
-  protocol    = "icmp"
-  from_port   = -1
-  to_port     = -1
-  cidr_blocks = ["0.0.0.0/0"]
+variable "internal_subnet_cidrs" {
+  type        = "list"
+  default     = []
+  description = "The blocks of the internal subnets by availability zone, when bbl plans them with --subnet-sizes or --reserved-cidrs."
 }
 
-resource "aws_security_group_rule" "nat_tcp_rule" {
-  security_group_id = "${aws_security_group.nat_security_group.id}"
+variable "tags" {
+  type        = "map"
+  default     = {}
+  description = "Annotations of the environment from bbl annotate and --tags, added to the tags of its resources."
+}
 
-  type                     = "ingress"
-  protocol                 = "tcp"
-  from_port                = 0
-  to_port                  = 65535
-  source_security_group_id = "${aws_security_group.internal_security_group.id}"
+locals {
+  tags = "${merge(var.tags, map("EnvID", var.env_id))}"
 }
 
-resource "aws_security_group_rule" "nat_udp_rule" {
-  security_group_id = "${aws_security_group.nat_security_group.id}"
+resource "aws_eip" "jumpbox_eip" {
+  depends_on = ["aws_internet_gateway.ig"]
+  vpc        = true
 
-  type                     = "ingress"
-  protocol                 = "udp"
-  from_port                = 0
-  to_port                  = 65535
-  source_security_group_id = "${aws_security_group.internal_security_group.id}"
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-jumpbox-ip"))}"
 }
 
-resource "aws_instance" "nat" {
-  private_ip             = "${cidrhost(aws_subnet.bosh_subnet.cidr_block, 7)}"
-  instance_type          = "t2.medium"
-  subnet_id              = "${aws_subnet.bosh_subnet.id}"
-  source_dest_check      = false
-  ami                    = "${lookup(var.nat_ami_map, var.region)}"
-  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
-
-  tags {
-    Name  = "${var.env_id}-nat"
-    EnvID = "${var.env_id}"
-  }
+resource "tls_private_key" "bosh_vms" {
+  algorithm = "RSA"
+  rsa_bits  = 4096
 }
 
-resource "aws_eip" "nat_eip" {
-  depends_on = ["aws_internet_gateway.ig"]
-  instance   = "${aws_instance.nat.id}"
-  vpc        = true
+resource "aws_key_pair" "bosh_vms" {
+  key_name   = "${var.env_id}_bosh_vms"
+  public_key = "${tls_private_key.bosh_vms.public_key_openssh}"
 }
 
 provider "aws" {
   access_key = "${var.access_key}"
   secret_key = "${var.secret_key}"
+  token      = "${var.session_token}"
   region     = "${var.region}"
+
+  max_retries = "${var.max_retries}"
+
+  endpoints {
+    ec2            = "${lookup(var.endpoints, "ec2", var.endpoint)}"
+    elb            = "${lookup(var.endpoints, "elb", var.endpoint)}"
+    iam            = "${lookup(var.endpoints, "iam", var.endpoint)}"
+    kms            = "${lookup(var.endpoints, "kms", var.endpoint)}"
+    s3             = "${lookup(var.endpoints, "s3", var.endpoint)}"
+    sts            = "${lookup(var.endpoints, "sts", var.endpoint)}"
+    cloudwatchlogs = "${lookup(var.endpoints, "cloudwatchlogs", var.endpoint)}"
+  }
 }
 
 resource "aws_default_security_group" "default_security_group" {
+  count  = "${local.vpc_count}"
   vpc_id = "${local.vpc_id}"
 }
 
@@ -156,9 +146,7 @@ resource "aws_security_group" "internal_security_group" {
   description = "Internal"
   vpc_id      = "${local.vpc_id}"
 
-  tags {
-    Name = "${var.env_id}-internal-security-group"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-internal-security-group"))}"
 
   lifecycle {
     ignore_changes = ["name"]
@@ -215,9 +203,7 @@ resource "aws_security_group" "bosh_security_group" {
   description = "BOSH Director"
   vpc_id      = "${local.vpc_id}"
 
-  tags {
-    Name = "${var.env_id}-bosh-security-group"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-bosh-security-group"))}"
 
   lifecycle {
     ignore_changes = ["name", "description"]
@@ -230,15 +216,16 @@ resource "aws_security_group_rule" "bosh_security_group_rule_tcp_ssh" {
   protocol          = "tcp"
   from_port         = 22
   to_port           = 22
-  cidr_blocks       = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks       = ["${compact(concat(list(var.bosh_inbound_cidr), var.bosh_inbound_cidrs))}"]
+  prefix_list_ids   = ["${var.bosh_inbound_prefix_list_ids}"]
 }
 
 resource "aws_security_group_rule" "bosh_security_group_rule_tcp_bosh_agent" {
   security_group_id        = "${aws_security_group.bosh_security_group.id}"
   type                     = "ingress"
   protocol                 = "tcp"
-  from_port                = 6868
-  to_port                  = 6868
+  from_port                = "${var.director_mbus_port}"
+  to_port                  = "${var.director_mbus_port}"
   source_security_group_id = "${aws_security_group.jumpbox.id}"
 }
 
@@ -301,9 +288,7 @@ resource "aws_security_group" "jumpbox" {
   description = "Jumpbox"
   vpc_id      = "${local.vpc_id}"
 
-  tags {
-    Name = "${var.env_id}-jumpbox-security-group"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-jumpbox-security-group"))}"
 
   lifecycle {
     ignore_changes = ["name", "description"]
@@ -316,7 +301,8 @@ resource "aws_security_group_rule" "jumpbox_ssh" {
   protocol          = "tcp"
   from_port         = 22
   to_port           = 22
-  cidr_blocks       = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks       = ["${compact(concat(list(var.bosh_inbound_cidr), var.bosh_inbound_cidrs))}"]
+  prefix_list_ids   = ["${var.bosh_inbound_prefix_list_ids}"]
 }
 
 resource "aws_security_group_rule" "jumpbox_rdp" {
@@ -325,7 +311,8 @@ resource "aws_security_group_rule" "jumpbox_rdp" {
   protocol          = "tcp"
   from_port         = 3389
   to_port           = 3389
-  cidr_blocks       = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks       = ["${compact(concat(list(var.bosh_inbound_cidr), var.bosh_inbound_cidrs))}"]
+  prefix_list_ids   = ["${var.bosh_inbound_prefix_list_ids}"]
 }
 
 resource "aws_security_group_rule" "jumpbox_agent" {
@@ -334,7 +321,8 @@ resource "aws_security_group_rule" "jumpbox_agent" {
   protocol          = "tcp"
   from_port         = 6868
   to_port           = 6868
-  cidr_blocks       = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks       = ["${compact(concat(list(var.bosh_inbound_cidr), var.bosh_inbound_cidrs))}"]
+  prefix_list_ids   = ["${var.bosh_inbound_prefix_list_ids}"]
 }
 
 resource "aws_security_group_rule" "jumpbox_director" {
@@ -343,7 +331,8 @@ resource "aws_security_group_rule" "jumpbox_director" {
   protocol          = "tcp"
   from_port         = 25555
   to_port           = 25555
-  cidr_blocks       = ["${var.bosh_inbound_cidr}"]
+  cidr_blocks       = ["${compact(concat(list(var.bosh_inbound_cidr), var.bosh_inbound_cidrs))}"]
+  prefix_list_ids   = ["${var.bosh_inbound_prefix_list_ids}"]
 }
 
 resource "aws_security_group_rule" "jumpbox_egress" {
@@ -377,18 +366,18 @@ resource "aws_subnet" "bosh_subnet" {
   vpc_id     = "${local.vpc_id}"
   cidr_block = "${cidrsubnet(var.vpc_cidr, 8, 0)}"
 
-  tags {
-    Name = "${var.env_id}-bosh-subnet"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-bosh-subnet"))}"
 }
 
 resource "aws_route_table" "bosh_route_table" {
   vpc_id = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-bosh-route-table"))}"
 }
 
 resource "aws_route" "bosh_route_table" {
   destination_cidr_block = "0.0.0.0/0"
-  gateway_id             = "${aws_internet_gateway.ig.id}"
+  gateway_id             = "${local.internet_gateway_id}"
   route_table_id         = "${aws_route_table.bosh_route_table.id}"
 }
 
@@ -398,14 +387,13 @@ resource "aws_route_table_association" "route_bosh_subnets" {
 }
 
 resource "aws_subnet" "internal_subnets" {
-  count             = "${length(var.availability_zones)}"
-  vpc_id            = "${local.vpc_id}"
-  cidr_block        = "${cidrsubnet(var.vpc_cidr, 4, count.index+1)}"
-  availability_zone = "${element(var.availability_zones, count.index)}"
+  count                   = "${length(var.availability_zones)}"
+  vpc_id                  = "${local.vpc_id}"
+  cidr_block              = "${length(var.internal_subnet_cidrs) > 0 ? element(concat(var.internal_subnet_cidrs, list("")), count.index) : cidrsubnet(var.vpc_cidr, 4, count.index+1)}"
+  availability_zone       = "${element(var.availability_zones, count.index)}"
+  map_public_ip_on_launch = "${var.minimal}"
 
-  tags {
-    Name = "${var.env_id}-internal-subnet${count.index}"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-internal-subnet${count.index}"))}"
 
   lifecycle {
     ignore_changes = ["cidr_block", "availability_zone"]
@@ -414,12 +402,8 @@ resource "aws_subnet" "internal_subnets" {
 
 resource "aws_route_table" "internal_route_table" {
   vpc_id = "${local.vpc_id}"
-}
 
-resource "aws_route" "internal_route_table" {
-  destination_cidr_block = "0.0.0.0/0"
-  instance_id            = "${aws_instance.nat.id}"
-  route_table_id         = "${aws_route_table.internal_route_table.id}"
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-internal-route-table"))}"
 }
 
 resource "aws_route_table_association" "route_internal_subnets" {
@@ -428,10 +412,6 @@ resource "aws_route_table_association" "route_internal_subnets" {
   route_table_id = "${aws_route_table.internal_route_table.id}"
 }
 
-resource "aws_internet_gateway" "ig" {
-  vpc_id = "${local.vpc_id}"
-}
-
 locals {
   director_name        = "bosh-${var.env_id}"
   internal_cidr        = "${aws_subnet.bosh_subnet.cidr_block}"
@@ -442,6 +422,8 @@ locals {
 
 resource "aws_kms_key" "kms_key" {
   enable_key_rotation = true
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-kms-key"))}"
 }
 
 output "default_key_name" {
@@ -465,10 +447,6 @@ output "director_address" {
   value = "https://${aws_eip.jumpbox_eip.public_ip}:25555"
 }
 
-output "nat_eip" {
-  value = "${aws_eip.nat_eip.public_ip}"
-}
-
 output "internal_security_group" {
   value = "${aws_security_group.internal_security_group.id}"
 }
@@ -2,38 +2,52 @@ variable "system_domain" {
   type = "string"
 }
 
-resource "aws_route53_zone" "env_dns_zone" {
-  name = "${var.system_domain}"
+variable "apps_domain" {
+  type        = "string"
+  default     = ""
+  description = "Optionally route a wildcard record of the apps domain to the router as well."
+}
+
+resource "aws_route53_record" "wildcard_dns" {
+  zone_id = "${local.dns_zone_id}"
+  name    = "*.${var.system_domain}"
+  type    = "A"
 
-  tags {
-    Name = "${var.env_id}-hosted-zone"
+  alias {
+    name                   = "${aws_elb.cf_router_lb.dns_name}"
+    zone_id                = "${aws_elb.cf_router_lb.zone_id}"
+    evaluate_target_health = false
   }
 }
 
-output "env_dns_zone_name_servers" {
-  value = "${aws_route53_zone.env_dns_zone.name_servers}"
-}
+resource "aws_route53_record" "apps_wildcard_dns" {
+  count = "${var.apps_domain == "" ? 0 : 1}"
 
-resource "aws_route53_record" "wildcard_dns" {
-  zone_id = "${aws_route53_zone.env_dns_zone.id}"
-  name    = "*.${var.system_domain}"
-  type    = "CNAME"
-  ttl     = 300
+  zone_id = "${local.dns_zone_id}"
+  name    = "*.${var.apps_domain}"
+  type    = "A"
 
-  records = ["${aws_elb.cf_router_lb.dns_name}"]
+  alias {
+    name                   = "${aws_elb.cf_router_lb.dns_name}"
+    zone_id                = "${aws_elb.cf_router_lb.zone_id}"
+    evaluate_target_health = false
+  }
 }
 
 resource "aws_route53_record" "ssh" {
-  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  zone_id = "${local.dns_zone_id}"
   name    = "ssh.${var.system_domain}"
-  type    = "CNAME"
-  ttl     = 300
+  type    = "A"
 
-  records = ["${aws_elb.cf_ssh_lb.dns_name}"]
+  alias {
+    name                   = "${aws_elb.cf_ssh_lb.dns_name}"
+    zone_id                = "${aws_elb.cf_ssh_lb.zone_id}"
+    evaluate_target_health = false
+  }
 }
 
 resource "aws_route53_record" "bosh" {
-  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  zone_id = "${local.dns_zone_id}"
   name    = "bosh.${var.system_domain}"
   type    = "A"
   ttl     = 300
@@ -42,21 +56,27 @@ resource "aws_route53_record" "bosh" {
 }
 
 resource "aws_route53_record" "tcp" {
-  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  zone_id = "${local.dns_zone_id}"
   name    = "tcp.${var.system_domain}"
-  type    = "CNAME"
-  ttl     = 300
+  type    = "A"
 
-  records = ["${aws_elb.cf_tcp_lb.dns_name}"]
+  alias {
+    name                   = "${aws_elb.cf_tcp_lb.dns_name}"
+    zone_id                = "${aws_elb.cf_tcp_lb.zone_id}"
+    evaluate_target_health = false
+  }
 }
 
 resource "aws_route53_record" "iso" {
   count = "${var.isolation_segments}"
 
-  zone_id = "${aws_route53_zone.env_dns_zone.id}"
+  zone_id = "${local.dns_zone_id}"
   name    = "*.iso-seg.${var.system_domain}"
-  type    = "CNAME"
-  ttl     = 300
+  type    = "A"
 
-  records = ["${aws_elb.iso_router_lb.dns_name}"]
+  alias {
+    name                   = "${aws_elb.iso_router_lb.dns_name}"
+    zone_id                = "${aws_elb.iso_router_lb.zone_id}"
+    evaluate_target_health = false
+  }
 }
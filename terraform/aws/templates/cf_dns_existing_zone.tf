@@ -0,0 +1,16 @@
+variable "dns_zone_id" {
+  type        = "string"
+  description = "Existing hosted zone that holds the records of the system domain, which bbl neither creates nor deletes."
+}
+
+data "aws_route53_zone" "env_dns_zone" {
+  zone_id = "${var.dns_zone_id}"
+}
+
+locals {
+  dns_zone_id = "${data.aws_route53_zone.env_dns_zone.zone_id}"
+}
+
+output "env_dns_zone_name_servers" {
+  value = "${data.aws_route53_zone.env_dns_zone.name_servers}"
+}
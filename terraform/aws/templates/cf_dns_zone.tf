@@ -0,0 +1,13 @@
+resource "aws_route53_zone" "env_dns_zone" {
+  name = "${var.system_domain}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-hosted-zone"))}"
+}
+
+locals {
+  dns_zone_id = "${aws_route53_zone.env_dns_zone.zone_id}"
+}
+
+output "env_dns_zone_name_servers" {
+  value = "${aws_route53_zone.env_dns_zone.name_servers}"
+}
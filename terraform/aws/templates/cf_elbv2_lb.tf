@@ -0,0 +1,278 @@
+resource "aws_security_group" "cf_ssh_lb_internal_security_group" {
+  name        = "${var.env_id}-cf-ssh-lb-internal-security-group"
+  description = "CF SSH Internal"
+  vpc_id      = "${local.vpc_id}"
+
+  ingress {
+    cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+    prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
+    protocol        = "tcp"
+    from_port       = 2222
+    to_port         = 2222
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-ssh-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "cf_ssh_lb_internal_security_group" {
+  value = "${aws_security_group.cf_ssh_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "cf_ssh_lb" {
+  name               = "${var.short_env_id}-cf-ssh-nlb"
+  load_balancer_type = "network"
+  subnets            = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-ssh-lb"))}"
+}
+
+resource "aws_lb_listener" "cf_ssh_lb_2222" {
+  load_balancer_arn = "${aws_lb.cf_ssh_lb.arn}"
+  protocol          = "TCP"
+  port              = 2222
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_ssh_lb_2222.arn}"
+  }
+}
+
+resource "aws_lb_target_group" "cf_ssh_lb_2222" {
+  name     = "${var.short_env_id}-cf-ssh2222"
+  port     = 2222
+  protocol = "TCP"
+  vpc_id   = "${local.vpc_id}"
+
+  health_check {
+    healthy_threshold   = 5
+    unhealthy_threshold = 5
+    interval            = 10
+    protocol            = "TCP"
+  }
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-ssh-lb-2222"))}"
+}
+
+output "cf_ssh_lb_name" {
+  value = "${aws_lb.cf_ssh_lb.name}"
+}
+
+output "cf_ssh_lb_url" {
+  value = "${aws_lb.cf_ssh_lb.dns_name}"
+}
+
+output "cf_ssh_lb_target_groups" {
+  value = ["${aws_lb_target_group.cf_ssh_lb_2222.name}"]
+}
+
+output "cf_ssh_lb_target_group_arns" {
+  value = ["${aws_lb_target_group.cf_ssh_lb_2222.arn}"]
+}
+
+resource "aws_security_group" "cf_router_lb_security_group" {
+  name        = "${var.env_id}-cf-router-lb-security-group"
+  description = "CF Router"
+  vpc_id      = "${local.vpc_id}"
+
+  ingress {
+    cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+    prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
+    protocol        = "tcp"
+    from_port       = 80
+    to_port         = 80
+  }
+
+  ingress {
+    cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+    prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
+    protocol        = "tcp"
+    from_port       = 443
+    to_port         = 443
+  }
+
+  ingress {
+    cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+    prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
+    protocol        = "tcp"
+    from_port       = 4443
+    to_port         = 4443
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-router-lb-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "cf_router_lb_security_group" {
+  value = "${aws_security_group.cf_router_lb_security_group.id}"
+}
+
+variable "router_health_check_protocol" {
+  type        = "string"
+  default     = "HTTP"
+  description = "Protocol of the health check of the router target group, HTTP or HTTPS."
+}
+
+variable "router_health_check_port" {
+  default     = 8080
+  description = "Port of the health check, which the router load balancer reaches the routers on."
+}
+
+variable "router_health_check_path" {
+  type    = "string"
+  default = "/health"
+}
+
+variable "router_health_check_interval" {
+  default = 12
+}
+
+variable "router_health_check_timeout" {
+  default = 2
+}
+
+variable "router_healthy_threshold" {
+  default = 5
+}
+
+variable "router_unhealthy_threshold" {
+  default = 2
+}
+
+resource "aws_security_group" "cf_router_lb_internal_security_group" {
+  name        = "${var.env_id}-cf-router-lb-internal-security-group"
+  description = "CF Router Internal"
+  vpc_id      = "${local.vpc_id}"
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+    protocol        = "tcp"
+    from_port       = 80
+    to_port         = 80
+  }
+
+  ingress {
+    security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+    protocol        = "tcp"
+    from_port       = "${var.router_health_check_port}"
+    to_port         = "${var.router_health_check_port}"
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-router-lb-internal-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "cf_router_lb_internal_security_group" {
+  value = "${aws_security_group.cf_router_lb_internal_security_group.id}"
+}
+
+resource "aws_lb" "cf_router_lb" {
+  name               = "${var.short_env_id}-cf-router-alb"
+  load_balancer_type = "application"
+  security_groups    = ["${aws_security_group.cf_router_lb_security_group.id}"]
+  subnets            = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-router-lb"))}"
+}
+
+resource "aws_lb_target_group" "cf_router_lb_80" {
+  name     = "${var.short_env_id}-cf-router80"
+  port     = 80
+  protocol = "HTTP"
+  vpc_id   = "${local.vpc_id}"
+
+  health_check {
+    protocol            = "${var.router_health_check_protocol}"
+    port                = "${var.router_health_check_port}"
+    path                = "${var.router_health_check_path}"
+    healthy_threshold   = "${var.router_healthy_threshold}"
+    unhealthy_threshold = "${var.router_unhealthy_threshold}"
+    interval            = "${var.router_health_check_interval}"
+    timeout             = "${var.router_health_check_timeout}"
+  }
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-router-lb-80"))}"
+}
+
+resource "aws_lb_listener" "cf_router_lb_80" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  protocol          = "HTTP"
+  port              = 80
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_80.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  protocol          = "HTTPS"
+  port              = 443
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_80.arn}"
+  }
+}
+
+resource "aws_lb_listener" "cf_router_lb_4443" {
+  load_balancer_arn = "${aws_lb.cf_router_lb.arn}"
+  protocol          = "HTTPS"
+  port              = 4443
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_iam_server_certificate.lb_cert.arn}"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.cf_router_lb_80.arn}"
+  }
+}
+
+output "cf_router_lb_name" {
+  value = "${aws_lb.cf_router_lb.name}"
+}
+
+output "cf_router_lb_url" {
+  value = "${aws_lb.cf_router_lb.dns_name}"
+}
+
+output "cf_router_lb_target_groups" {
+  value = ["${aws_lb_target_group.cf_router_lb_80.name}"]
+}
+
+output "cf_router_lb_target_group_arns" {
+  value = ["${aws_lb_target_group.cf_router_lb_80.arn}"]
+}
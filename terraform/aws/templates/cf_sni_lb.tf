@@ -0,0 +1,61 @@
+variable "sni_domains" {
+  type = "list"
+}
+
+variable "sni_certificate_arns" {
+  type = "list"
+}
+
+resource "aws_elb" "cf_router_sni_lbs" {
+  count                     = "${length(var.sni_domains)}"
+  name                      = "${var.short_env_id}-cf-sni-${count.index}"
+  cross_zone_load_balancing = true
+
+  health_check {
+    healthy_threshold   = "${var.router_healthy_threshold}"
+    unhealthy_threshold = "${var.router_unhealthy_threshold}"
+    interval            = "${var.router_health_check_interval}"
+    target              = "${var.router_health_check_target}"
+    timeout             = "${var.router_health_check_timeout}"
+  }
+
+  listener {
+    instance_port     = 80
+    instance_protocol = "http"
+    lb_port           = 80
+    lb_protocol       = "http"
+  }
+
+  listener {
+    instance_port      = 80
+    instance_protocol  = "http"
+    lb_port            = 443
+    lb_protocol        = "https"
+    ssl_certificate_id = "${element(var.sni_certificate_arns, count.index)}"
+  }
+
+  listener {
+    instance_port      = 80
+    instance_protocol  = "tcp"
+    lb_port            = 4443
+    lb_protocol        = "ssl"
+    ssl_certificate_id = "${element(var.sni_certificate_arns, count.index)}"
+  }
+
+  security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
+  subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-cf-sni-${element(var.sni_domains, count.index)}"))}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+output "cf_router_sni_lb_names" {
+  value = ["${aws_elb.cf_router_sni_lbs.*.name}"]
+}
+
+output "cf_router_sni_lb_urls" {
+  value = "${zipmap(var.sni_domains, aws_elb.cf_router_sni_lbs.*.dns_name)}"
+}
@@ -3,9 +3,7 @@ resource "aws_security_group" "concourse_lb_internal_security_group" {
   description = "Concourse Internal"
   vpc_id      = "${local.vpc_id}"
 
-  tags {
-    Name = "${var.env_id}-concourse-lb-internal-security-group"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-concourse-lb-internal-security-group"))}"
 
   lifecycle {
     ignore_changes = ["name"]
@@ -13,31 +11,34 @@ resource "aws_security_group" "concourse_lb_internal_security_group" {
 }
 
 resource "aws_security_group_rule" "concourse_lb_internal_80" {
-  type        = "ingress"
-  protocol    = "tcp"
-  from_port   = 80
-  to_port     = 80
-  cidr_blocks = ["0.0.0.0/0"]
+  type            = "ingress"
+  protocol        = "tcp"
+  from_port       = 80
+  to_port         = 80
+  cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+  prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
 
   security_group_id = "${aws_security_group.concourse_lb_internal_security_group.id}"
 }
 
 resource "aws_security_group_rule" "concourse_lb_internal_2222" {
-  type        = "ingress"
-  protocol    = "tcp"
-  from_port   = 2222
-  to_port     = 2222
-  cidr_blocks = ["0.0.0.0/0"]
+  type            = "ingress"
+  protocol        = "tcp"
+  from_port       = 2222
+  to_port         = 2222
+  cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+  prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
 
   security_group_id = "${aws_security_group.concourse_lb_internal_security_group.id}"
 }
 
 resource "aws_security_group_rule" "concourse_lb_internal_443" {
-  type        = "ingress"
-  protocol    = "tcp"
-  from_port   = 443
-  to_port     = 443
-  cidr_blocks = ["0.0.0.0/0"]
+  type            = "ingress"
+  protocol        = "tcp"
+  from_port       = 443
+  to_port         = 443
+  cidr_blocks     = ["${var.lb_inbound_cidrs}"]
+  prefix_list_ids = ["${var.lb_inbound_prefix_list_ids}"]
 
   security_group_id = "${aws_security_group.concourse_lb_internal_security_group.id}"
 }
@@ -56,6 +57,8 @@ resource "aws_lb" "concourse_lb" {
   name               = "${var.short_env_id}-concourse-lb"
   load_balancer_type = "network"
   subnets            = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-concourse-lb"))}"
 }
 
 resource "aws_lb_listener" "concourse_lb_80" {
@@ -81,6 +84,8 @@ resource "aws_lb_target_group" "concourse_lb_80" {
     interval            = 30
     protocol            = "TCP"
   }
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-concourse-lb-80"))}"
 }
 
 resource "aws_lb_listener" "concourse_lb_2222" {
@@ -99,6 +104,8 @@ resource "aws_lb_target_group" "concourse_lb_2222" {
   port     = 2222
   protocol = "TCP"
   vpc_id   = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-concourse-lb-2222"))}"
 }
 
 resource "aws_lb_listener" "concourse_lb_443" {
@@ -117,6 +124,8 @@ resource "aws_lb_target_group" "concourse_lb_443" {
   port     = 443
   protocol = "TCP"
   vpc_id   = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-concourse-lb-443"))}"
 }
 
 output "concourse_lb_internal_security_group" {
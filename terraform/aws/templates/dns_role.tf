@@ -0,0 +1,28 @@
+variable "dns_role_arn" {
+  type        = "string"
+  description = "Role that manages the hosted zone, for system domains whose DNS lives in another AWS account."
+}
+
+provider "aws" {
+  alias      = "dns"
+  access_key = "${var.access_key}"
+  secret_key = "${var.secret_key}"
+  token      = "${var.session_token}"
+  region     = "${var.region}"
+
+  max_retries = "${var.max_retries}"
+
+  endpoints {
+    ec2            = "${lookup(var.endpoints, "ec2", var.endpoint)}"
+    elb            = "${lookup(var.endpoints, "elb", var.endpoint)}"
+    iam            = "${lookup(var.endpoints, "iam", var.endpoint)}"
+    kms            = "${lookup(var.endpoints, "kms", var.endpoint)}"
+    s3             = "${lookup(var.endpoints, "s3", var.endpoint)}"
+    sts            = "${lookup(var.endpoints, "sts", var.endpoint)}"
+    cloudwatchlogs = "${lookup(var.endpoints, "cloudwatchlogs", var.endpoint)}"
+  }
+
+  assume_role {
+    role_arn = "${var.dns_role_arn}"
+  }
+}
@@ -0,0 +1,29 @@
+resource "aws_route53_record" "wildcard_dns_ipv6" {
+  zone_id = "${local.dns_zone_id}"
+  name    = "*.${var.system_domain}"
+  type    = "AAAA"
+
+  alias {
+    name                   = "${aws_lb.cf_router_lb.dns_name}"
+    zone_id                = "${aws_lb.cf_router_lb.zone_id}"
+    evaluate_target_health = false
+  }
+}
+
+resource "aws_route53_record" "apps_wildcard_dns_ipv6" {
+  count = "${var.apps_domain == "" ? 0 : 1}"
+
+  zone_id = "${local.dns_zone_id}"
+  name    = "*.${var.apps_domain}"
+  type    = "AAAA"
+
+  alias {
+    name                   = "${aws_lb.cf_router_lb.dns_name}"
+    zone_id                = "${aws_lb.cf_router_lb.zone_id}"
+    evaluate_target_health = false
+  }
+}
+
+output "ipv6_domains" {
+  value = ["${compact(list("*.${var.system_domain}", var.apps_domain == "" ? "" : "*.${var.apps_domain}"))}"]
+}
@@ -0,0 +1,9 @@
+variable "lb_inbound_ipv6_cidrs" {
+  type        = "list"
+  default     = ["::/0"]
+  description = "IPv6 CIDRs allowed to reach the router load balancer"
+}
+
+output "cf_router_lb_ip_address_type" {
+  value = "${aws_lb.cf_router_lb.ip_address_type}"
+}
@@ -0,0 +1,5 @@
+resource "aws_route" "lb_route_table_ipv6" {
+  destination_ipv6_cidr_block = "::/0"
+  gateway_id                  = "${local.internet_gateway_id}"
+  route_table_id              = "${aws_route_table.lb_route_table.id}"
+}
@@ -0,0 +1,6 @@
+resource "aws_route" "nat_gateway_routes_ipv6" {
+  count                       = "${length(var.availability_zones)}"
+  destination_ipv6_cidr_block = "::/0"
+  egress_only_gateway_id      = "${aws_egress_only_internet_gateway.egress_only_ig.id}"
+  route_table_id              = "${element(aws_route_table.nat_gateway_route_tables.*.id, count.index)}"
+}
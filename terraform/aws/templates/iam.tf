@@ -29,7 +29,7 @@ resource "aws_iam_role" "bosh" {
     {
       "Action": "sts:AssumeRole",
       "Principal": {
-        "Service": "ec2.amazonaws.com"
+        "Service": "ec2.${var.dns_suffix}"
       },
       "Effect": "Allow",
       "Sid": ""
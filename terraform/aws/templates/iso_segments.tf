@@ -29,9 +29,7 @@ resource "aws_subnet" "iso_subnets" {
   cidr_block        = "${cidrsubnet(var.vpc_cidr, 4, count.index + length(var.availability_zones) + 1)}"
   availability_zone = "${element(var.availability_zones, count.index)}"
 
-  tags {
-    Name = "${var.env_id}-iso-subnet${count.index}"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-iso-subnet${count.index}"))}"
 }
 
 resource "aws_route_table_association" "route_iso_subnets" {
@@ -47,11 +45,11 @@ resource "aws_elb" "iso_router_lb" {
   cross_zone_load_balancing = true
 
   health_check {
-    healthy_threshold   = 5
-    unhealthy_threshold = 2
-    interval            = 12
-    target              = "TCP:80"
-    timeout             = 2
+    healthy_threshold   = "${var.router_healthy_threshold}"
+    unhealthy_threshold = "${var.router_unhealthy_threshold}"
+    interval            = "${var.router_health_check_interval}"
+    target              = "${var.router_health_check_target}"
+    timeout             = "${var.router_health_check_timeout}"
   }
 
   listener {
@@ -79,6 +77,8 @@ resource "aws_elb" "iso_router_lb" {
 
   security_groups = ["${aws_security_group.cf_router_lb_security_group.id}"]
   subnets         = ["${aws_subnet.lb_subnets.*.id}"]
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-iso-router-lb"))}"
 }
 
 resource "aws_security_group" "iso_security_group" {
@@ -89,9 +89,7 @@ resource "aws_security_group" "iso_security_group" {
 
   description = "Private isolation segment"
 
-  tags {
-    Name = "${var.env_id}-iso-security-group"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-iso-security-group"))}"
 }
 
 resource "aws_security_group" "iso_shared_security_group" {
@@ -102,9 +100,7 @@ resource "aws_security_group" "iso_shared_security_group" {
 
   description = "Shared isolation segments"
 
-  tags {
-    Name = "${var.env_id}-iso-shared-security-group"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-iso-shared-security-group"))}"
 }
 
 resource "aws_security_group_rule" "isolation_segments_to_bosh_rule" {
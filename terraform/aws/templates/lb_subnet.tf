@@ -1,12 +1,22 @@
+variable "lb_inbound_cidrs" {
+  type        = "list"
+  default     = ["0.0.0.0/0"]
+  description = "CIDRs allowed to reach the load balancers"
+}
+
+variable "lb_inbound_prefix_list_ids" {
+  type        = "list"
+  default     = []
+  description = "Managed prefix lists allowed to reach the load balancers. Set lb_inbound_cidrs to an empty list to allow only these."
+}
+
 resource "aws_subnet" "lb_subnets" {
   count             = "${length(var.availability_zones)}"
   vpc_id            = "${local.vpc_id}"
   cidr_block        = "${cidrsubnet(var.vpc_cidr, 8, count.index+2)}"
   availability_zone = "${element(var.availability_zones, count.index)}"
 
-  tags {
-    Name = "${var.env_id}-lb-subnet${count.index}"
-  }
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-lb-subnet${count.index}"))}"
 
   lifecycle {
     ignore_changes = ["cidr_block", "availability_zone"]
@@ -15,11 +25,13 @@ resource "aws_subnet" "lb_subnets" {
 
 resource "aws_route_table" "lb_route_table" {
   vpc_id = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-lb-route-table"))}"
 }
 
 resource "aws_route" "lb_route_table" {
   destination_cidr_block = "0.0.0.0/0"
-  gateway_id             = "${aws_internet_gateway.ig.id}"
+  gateway_id             = "${local.internet_gateway_id}"
   route_table_id         = "${aws_route_table.lb_route_table.id}"
 }
 
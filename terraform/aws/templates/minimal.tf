@@ -0,0 +1,5 @@
+resource "aws_route" "internal_route_table" {
+  destination_cidr_block = "0.0.0.0/0"
+  gateway_id             = "${local.internet_gateway_id}"
+  route_table_id         = "${aws_route_table.internal_route_table.id}"
+}
@@ -0,0 +1,106 @@
+variable "nat_ami_map" {
+  type = "map"
+
+  default = {
+    ap-northeast-1 = "ami-10dfc877"
+    ap-northeast-2 = "ami-1a1bc474"
+    ap-south-1     = "ami-74c1861b"
+    ap-southeast-1 = "ami-36af2055"
+    ap-southeast-2 = "ami-1e91817d"
+    ca-central-1   = "ami-12d36a76"
+    eu-central-1   = "ami-9ebe18f1"
+    eu-west-1      = "ami-3a849f5c"
+    eu-west-2      = "ami-21120445"
+    us-east-1      = "ami-d4c5efc2"
+    us-east-2      = "ami-f27b5a97"
+    us-gov-west-1  = "ami-c39610a2"
+    us-west-1      = "ami-b87f53d8"
+    us-west-2      = "ami-8bfce8f2"
+  }
+}
+
+variable "nat_instance_type" {
+  type    = "string"
+  default = "t2.medium"
+}
+
+resource "aws_security_group" "nat_security_group" {
+  name        = "${var.env_id}-nat-security-group"
+  description = "NAT"
+  vpc_id      = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat-security-group"))}"
+
+  lifecycle {
+    ignore_changes = ["name"]
+  }
+}
+
+resource "aws_security_group_rule" "nat_to_internet_rule" {
+  security_group_id = "${aws_security_group.nat_security_group.id}"
+
+  type        = "egress"
+  from_port   = 0
+  to_port     = 0
+  protocol    = "-1"
+  cidr_blocks = ["0.0.0.0/0"]
+}
+
+resource "aws_security_group_rule" "nat_icmp_rule" {
+  security_group_id = "${aws_security_group.nat_security_group.id}"
+
+  type        = "ingress"
+  protocol    = "icmp"
+  from_port   = -1
+  to_port     = -1
+  cidr_blocks = ["0.0.0.0/0"]
+}
+
+resource "aws_security_group_rule" "nat_tcp_rule" {
+  security_group_id = "${aws_security_group.nat_security_group.id}"
+
+  type                     = "ingress"
+  protocol                 = "tcp"
+  from_port                = 0
+  to_port                  = 65535
+  source_security_group_id = "${aws_security_group.internal_security_group.id}"
+}
+
+resource "aws_security_group_rule" "nat_udp_rule" {
+  security_group_id = "${aws_security_group.nat_security_group.id}"
+
+  type                     = "ingress"
+  protocol                 = "udp"
+  from_port                = 0
+  to_port                  = 65535
+  source_security_group_id = "${aws_security_group.internal_security_group.id}"
+}
+
+resource "aws_instance" "nat" {
+  private_ip             = "${cidrhost(aws_subnet.bosh_subnet.cidr_block, 7)}"
+  instance_type          = "${var.nat_instance_type}"
+  subnet_id              = "${aws_subnet.bosh_subnet.id}"
+  source_dest_check      = false
+  ami                    = "${lookup(var.nat_ami_map, var.region)}"
+  vpc_security_group_ids = ["${aws_security_group.nat_security_group.id}"]
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat"))}"
+}
+
+resource "aws_eip" "nat_eip" {
+  depends_on = ["aws_internet_gateway.ig"]
+  instance   = "${aws_instance.nat.id}"
+  vpc        = true
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat-ip"))}"
+}
+
+resource "aws_route" "internal_route_table" {
+  destination_cidr_block = "0.0.0.0/0"
+  instance_id            = "${aws_instance.nat.id}"
+  route_table_id         = "${aws_route_table.internal_route_table.id}"
+}
+
+output "nat_eip" {
+  value = "${aws_eip.nat_eip.public_ip}"
+}
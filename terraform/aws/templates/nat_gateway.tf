@@ -0,0 +1,52 @@
+resource "aws_subnet" "nat_subnets" {
+  count             = "${length(var.availability_zones)}"
+  vpc_id            = "${local.vpc_id}"
+  cidr_block        = "${cidrsubnet(var.vpc_cidr, 8, count.index+10)}"
+  availability_zone = "${element(var.availability_zones, count.index)}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat-subnet${count.index}"))}"
+
+  lifecycle {
+    ignore_changes = ["cidr_block", "availability_zone"]
+  }
+}
+
+resource "aws_route_table_association" "route_nat_subnets" {
+  count          = "${length(var.availability_zones)}"
+  subnet_id      = "${element(aws_subnet.nat_subnets.*.id, count.index)}"
+  route_table_id = "${aws_route_table.bosh_route_table.id}"
+}
+
+resource "aws_eip" "nat_gateway_eips" {
+  count      = "${length(var.availability_zones)}"
+  depends_on = ["aws_internet_gateway.ig"]
+  vpc        = true
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat-gateway-ip${count.index}"))}"
+}
+
+resource "aws_nat_gateway" "nat_gateways" {
+  count         = "${length(var.availability_zones)}"
+  allocation_id = "${element(aws_eip.nat_gateway_eips.*.id, count.index)}"
+  subnet_id     = "${element(aws_subnet.nat_subnets.*.id, count.index)}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat-gateway${count.index}"))}"
+}
+
+resource "aws_route_table" "nat_gateway_route_tables" {
+  count  = "${length(var.availability_zones)}"
+  vpc_id = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-nat-gateway-route-table${count.index}"))}"
+}
+
+resource "aws_route" "nat_gateway_routes" {
+  count                  = "${length(var.availability_zones)}"
+  destination_cidr_block = "0.0.0.0/0"
+  nat_gateway_id         = "${element(aws_nat_gateway.nat_gateways.*.id, count.index)}"
+  route_table_id         = "${element(aws_route_table.nat_gateway_route_tables.*.id, count.index)}"
+}
+
+output "nat_gateway_eips" {
+  value = ["${aws_eip.nat_gateway_eips.*.public_ip}"]
+}
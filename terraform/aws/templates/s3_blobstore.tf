@@ -0,0 +1,95 @@
+variable "s3_blobstore_bucket" {
+  type        = "string"
+  default     = ""
+  description = "Existing bucket for the blobstore of the director. bbl creates one when it is empty."
+}
+
+locals {
+  createBlobstoreBucket = "${var.s3_blobstore_bucket == "" ? 1 : 0}"
+  blobstoreBucket       = "${local.createBlobstoreBucket ? join("", aws_s3_bucket.blobstore.*.id) : var.s3_blobstore_bucket}"
+}
+
+resource "aws_s3_bucket" "blobstore" {
+  bucket_prefix = "${lower(var.short_env_id)}-blobstore-"
+  force_destroy = true
+
+  count = "${local.createBlobstoreBucket}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-blobstore"))}"
+}
+
+resource "aws_iam_policy" "blobstore" {
+  name = "${var.env_id}_blobstore_policy"
+  path = "/"
+
+  policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": [
+        "s3:ListBucket",
+        "s3:GetBucketLocation"
+      ],
+      "Effect": "Allow",
+      "Resource": "arn:${var.partition}:s3:::${local.blobstoreBucket}"
+    },
+    {
+      "Action": [
+        "s3:GetObject",
+        "s3:PutObject",
+        "s3:DeleteObject"
+      ],
+      "Effect": "Allow",
+      "Resource": "arn:${var.partition}:s3:::${local.blobstoreBucket}/*"
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy_attachment" "bosh_blobstore" {
+  role       = "${join("", aws_iam_role.bosh.*.name)}"
+  policy_arn = "${aws_iam_policy.blobstore.arn}"
+
+  count = "${1 - local.iamProfileProvided}"
+}
+
+resource "aws_iam_role" "blobstore" {
+  name = "${var.env_id}_blobstore_role"
+  path = "/"
+
+  assume_role_policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Principal": {
+        "Service": "ec2.${var.dns_suffix}"
+      },
+      "Effect": "Allow",
+      "Sid": ""
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy_attachment" "blobstore" {
+  role       = "${aws_iam_role.blobstore.name}"
+  policy_arn = "${aws_iam_policy.blobstore.arn}"
+}
+
+resource "aws_iam_instance_profile" "blobstore" {
+  name = "${var.env_id}-blobstore"
+  role = "${aws_iam_role.blobstore.name}"
+}
+
+output "blobstore_bucket" {
+  value = "${local.blobstoreBucket}"
+}
+
+output "blobstore_instance_profile" {
+  value = "${aws_iam_instance_profile.blobstore.name}"
+}
@@ -21,3 +21,11 @@ resource "aws_iam_server_certificate" "lb_cert" {
     create_before_destroy = true
   }
 }
+
+output "lb_cert_name" {
+  value = "${aws_iam_server_certificate.lb_cert.name}"
+}
+
+output "lb_cert_arn" {
+  value = "${aws_iam_server_certificate.lb_cert.arn}"
+}
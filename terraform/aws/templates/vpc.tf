@@ -5,8 +5,9 @@ variable "existing_vpc_id" {
 }
 
 locals {
-  vpc_count = "${length(var.existing_vpc_id) > 0 ? 0 : 1}"
-  vpc_id    = "${length(var.existing_vpc_id) > 0 ? var.existing_vpc_id : join(" ", aws_vpc.vpc.*.id)}"
+  vpc_count           = "${length(var.existing_vpc_id) > 0 ? 0 : 1}"
+  vpc_id              = "${length(var.existing_vpc_id) > 0 ? var.existing_vpc_id : join(" ", aws_vpc.vpc.*.id)}"
+  internet_gateway_id = "${length(var.existing_vpc_id) > 0 ? join(" ", data.aws_internet_gateway.existing_ig.*.id) : join(" ", aws_internet_gateway.ig.*.id)}"
 }
 
 resource "aws_vpc" "vpc" {
@@ -15,7 +16,21 @@ resource "aws_vpc" "vpc" {
   instance_tenancy     = "default"
   enable_dns_hostnames = true
 
-  tags {
-    Name = "${var.env_id}-vpc"
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-vpc"))}"
+}
+
+resource "aws_internet_gateway" "ig" {
+  count  = "${local.vpc_count}"
+  vpc_id = "${local.vpc_id}"
+
+  tags = "${merge(local.tags, map("Name", "${var.env_id}-internet-gateway"))}"
+}
+
+data "aws_internet_gateway" "existing_ig" {
+  count = "${1 - local.vpc_count}"
+
+  filter {
+    name   = "attachment.vpc-id"
+    values = ["${var.existing_vpc_id}"]
   }
 }
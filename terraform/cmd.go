@@ -1,20 +1,27 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 )
 
 type Cmd struct {
+	ctx          context.Context
 	stderr       io.Writer
 	outputBuffer io.Writer
 	tfDataDir    string
 }
 
-func NewCmd(stderr, outputBuffer io.Writer, tfDataDir string) Cmd {
+// NewCmd returns a Cmd whose terraform commands are interrupted once ctx runs
+// out of time.
+func NewCmd(ctx context.Context, stderr, outputBuffer io.Writer, tfDataDir string) Cmd {
 	return Cmd{
+		ctx:          ctx,
 		stderr:       stderr,
 		outputBuffer: outputBuffer,
 		tfDataDir:    tfDataDir,
@@ -33,5 +40,5 @@ func (c Cmd) Run(stdout io.Writer, args []string, debug bool) error {
 		command.Stderr = c.outputBuffer
 	}
 
-	return command.Run()
+	return helpers.RunUntilDone(c.ctx, command)
 }
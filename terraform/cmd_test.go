@@ -2,6 +2,7 @@ package terraform_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -38,7 +39,7 @@ var _ = Describe("Run", func() {
 
 		defaultArgs = []string{"apply", "-state=/tmp/terraform.tfstate", "/tmp"}
 
-		cmd = terraform.NewCmd(fakeStderr, io.MultiWriter(outputBuffer, GinkgoWriter), "some-terraform-dir")
+		cmd = terraform.NewCmd(context.Background(), fakeStderr, io.MultiWriter(outputBuffer, GinkgoWriter), "some-terraform-dir")
 
 		fakeTerraformBackendServer = ghttp.NewServer()
 
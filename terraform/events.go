@@ -0,0 +1,73 @@
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+var (
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	// resourceTransition matches the lines terraform apply and destroy print
+	// when they start and finish changing a resource.
+	resourceTransition = regexp.MustCompile(`^(\S+): (Creating|Creation complete|Destroying|Destruction complete|Modifying|Modifications complete)(.*)$`)
+
+	// stillChanging matches the lines terraform prints every ten seconds
+	// while a resource is being changed, of which a whole minute is reported.
+	stillChanging = regexp.MustCompile(`^(\S+): Still (creating|destroying|modifying)\.\.\. \((\d+h)?\d+m0s elapsed\)$`)
+
+	resourceFailure = regexp.MustCompile(`^\* (\S+: .*)$`)
+	failure         = regexp.MustCompile(`^Error: (.*)$`)
+)
+
+type eventLogger interface {
+	Println(string)
+}
+
+// EventStreamer is written the output of terraform and reports the resources
+// that apply and destroy change, and the errors of the ones that fail, as
+// they happen. Without --debug the output of terraform is otherwise only
+// shown once it has failed, so a resource that takes long cannot be told
+// from one that is stuck.
+type EventStreamer struct {
+	logger eventLogger
+	line   []byte
+}
+
+func NewEventStreamer(logger eventLogger) *EventStreamer {
+	return &EventStreamer{logger: logger}
+}
+
+func (s *EventStreamer) Write(p []byte) (int, error) {
+	s.line = append(s.line, p...)
+	for {
+		i := bytes.IndexByte(s.line, '\n')
+		if i < 0 {
+			break
+		}
+		s.report(string(ansiEscape.ReplaceAll(bytes.TrimSpace(s.line[:i]), nil)))
+		s.line = s.line[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (s *EventStreamer) report(line string) {
+	switch {
+	case resourceTransition.MatchString(line), stillChanging.MatchString(line):
+		s.logger.Println(fmt.Sprintf("terraform: %s", line))
+	case resourceFailure.MatchString(line):
+		// terraform 0.11 lists how many errors a resource had before the
+		// errors themselves.
+		if bytes.HasSuffix([]byte(line), []byte("error(s) occurred:")) {
+			return
+		}
+		s.logger.Println(fmt.Sprintf("terraform: FAILED %s", resourceFailure.FindStringSubmatch(line)[1]))
+	case failure.MatchString(line):
+		if line == "Error: Error applying plan:" {
+			return
+		}
+		s.logger.Println(fmt.Sprintf("terraform: FAILED %s", failure.FindStringSubmatch(line)[1]))
+	}
+}
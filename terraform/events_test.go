@@ -0,0 +1,70 @@
+package terraform_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventStreamer", func() {
+	var (
+		logger   *fakes.Logger
+		streamer *terraform.EventStreamer
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		streamer = terraform.NewEventStreamer(logger)
+	})
+
+	It("reports the resources terraform starts and finishes changing", func() {
+		streamer.Write([]byte("\x1b[0m\x1b[1maws_vpc.vpc: Creating...\x1b[0m\n  cidr_block: \"\" => \"10.0.0.0/16\"\naws_vpc.vpc: Creation com"))
+		streamer.Write([]byte("plete after 2s (ID: vpc-1234)\naws_instance.nat: Destroying... (ID: i-1234)\n"))
+
+		Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+			"terraform: aws_vpc.vpc: Creating...",
+			"terraform: aws_vpc.vpc: Creation complete after 2s (ID: vpc-1234)",
+			"terraform: aws_instance.nat: Destroying... (ID: i-1234)",
+		}))
+	})
+
+	It("reports a resource that is still changing once a minute", func() {
+		streamer.Write([]byte("aws_instance.nat: Still creating... (10s elapsed)\n" +
+			"aws_instance.nat: Still creating... (1m0s elapsed)\n" +
+			"aws_instance.nat: Still creating... (1m10s elapsed)\n" +
+			"aws_instance.nat: Still creating... (2m0s elapsed)\n"))
+
+		Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+			"terraform: aws_instance.nat: Still creating... (1m0s elapsed)",
+			"terraform: aws_instance.nat: Still creating... (2m0s elapsed)",
+		}))
+	})
+
+	It("reports the errors of the resources that failed", func() {
+		streamer.Write([]byte(`Error: Error applying plan:
+
+1 error(s) occurred:
+
+* aws_instance.nat: 1 error(s) occurred:
+
+* aws_instance.nat: Error launching source instance: InstanceLimitExceeded
+	status code: 400
+
+Terraform does not automatically rollback in the face of errors.
+`))
+
+		Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+			"terraform: FAILED aws_instance.nat: Error launching source instance: InstanceLimitExceeded",
+		}))
+	})
+
+	It("reports the errors of terraform 0.12", func() {
+		streamer.Write([]byte("Error: Error creating VPC: VpcLimitExceeded\n"))
+
+		Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+			"terraform: FAILED Error creating VPC: VpcLimitExceeded",
+		}))
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/fileio"
@@ -99,6 +100,17 @@ func formatVars(inputs map[string]interface{}) string {
 			value = vString
 		} else if valList, ok := value.([]string); ok {
 			value = fmt.Sprintf(`["%s"]`, strings.Join(valList, `","`))
+		} else if valMap, ok := value.(map[string]string); ok {
+			keys := []string{}
+			for key := range valMap {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			pairs := []string{}
+			for _, key := range keys {
+				pairs = append(pairs, fmt.Sprintf(`"%s"="%s"`, key, valMap[key]))
+			}
+			value = fmt.Sprintf(`{%s}`, strings.Join(pairs, ","))
 		}
 		formattedVars = fmt.Sprintf("%s\n%s=%s", formattedVars, name, value)
 	}
@@ -166,6 +178,15 @@ func (e Executor) Apply(credentials map[string]string) error {
 	return e.runTFCommand(args)
 }
 
+func (e Executor) Plan(credentials map[string]string) error {
+	args := []string{"plan", "-input=false"}
+	for key, value := range credentials {
+		arg := fmt.Sprintf("%s=%s", key, value)
+		args = append(args, "-var", arg)
+	}
+	return e.runTFCommand(args)
+}
+
 func (e Executor) Destroy(credentials map[string]string) error {
 	args := []string{"destroy", "-force"}
 	for key, value := range credentials {
@@ -175,6 +196,70 @@ func (e Executor) Destroy(credentials map[string]string) error {
 	return e.runTFCommand(args)
 }
 
+func (e Executor) Taint(resource string) error {
+	varsDir, err := e.stateStore.GetVarsDir()
+	if err != nil {
+		return fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	args := []string{"taint", "-state", filepath.Join(varsDir, "terraform.tfstate"), resource}
+	err = e.cmd.Run(os.Stdout, args, e.debug)
+	if err != nil {
+		return fmt.Errorf("Run terraform taint: %s", err)
+	}
+
+	return nil
+}
+
+// StateList lists the addresses of the resources in the terraform state of
+// the vars dir.
+func (e Executor) StateList() ([]string, error) {
+	varsDir, err := e.stateStore.GetVarsDir()
+	if err != nil {
+		return nil, fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err = e.cmd.Run(buffer, []string{"state", "list", "-state", filepath.Join(varsDir, "terraform.tfstate")}, true)
+	if err != nil {
+		return nil, fmt.Errorf("Run terraform state list: %s", err)
+	}
+
+	return strings.Fields(buffer.String()), nil
+}
+
+// StateMoveOut moves a resource from the terraform state of the vars dir to
+// another state file, which terraform creates when it does not exist.
+func (e Executor) StateMoveOut(resource, toState string) error {
+	varsDir, err := e.stateStore.GetVarsDir()
+	if err != nil {
+		return fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	return e.stateMove(resource, filepath.Join(varsDir, "terraform.tfstate"), toState)
+}
+
+// StateMoveIn moves a resource from another state file into the terraform
+// state of the vars dir.
+func (e Executor) StateMoveIn(fromState, resource string) error {
+	varsDir, err := e.stateStore.GetVarsDir()
+	if err != nil {
+		return fmt.Errorf("Get vars dir: %s", err)
+	}
+
+	return e.stateMove(resource, fromState, filepath.Join(varsDir, "terraform.tfstate"))
+}
+
+func (e Executor) stateMove(resource, fromState, toState string) error {
+	args := []string{"state", "mv", "-state", fromState, "-state-out", toState, resource, resource}
+	err := e.cmd.Run(os.Stdout, args, e.debug)
+	if err != nil {
+		return fmt.Errorf("Run terraform state mv: %s", err)
+	}
+
+	return nil
+}
+
 func (e Executor) Version() (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
 	err := e.cmd.Run(buffer, []string{"version"}, true)
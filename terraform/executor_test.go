@@ -81,7 +81,7 @@ var _ = Describe("Executor", func() {
 
 		Context("when terraform init fails", func() {
 			BeforeEach(func() {
-				cmd.RunCall.Returns.Errors = []error{errors.New("guava")}
+				cmd.RunCall.Returns.Errors = []error{nil, errors.New("guava")}
 			})
 
 			It("returns an error", func() {
@@ -110,6 +110,15 @@ var _ = Describe("Executor", func() {
 			Expect(cmd.RunCall.CallCount).To(Equal(0))
 		})
 
+		It("writes maps as terraform maps", func() {
+			input["tags"] = map[string]string{"owner": "platform-team", "cost-center": "1234"}
+
+			err := executor.Setup("some-template", input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(fileIO.WriteFileCall.Receives[2].Contents)).To(ContainSubstring(`tags={"cost-center"="1234","owner"="platform-team"}`))
+		})
+
 		Context("when an error occurs", func() {
 			Context("when getting terraform dir fails", func() {
 				BeforeEach(func() {
@@ -290,6 +299,35 @@ var _ = Describe("Executor", func() {
 		})
 	})
 
+	Describe("Plan", func() {
+		BeforeEach(func() {
+			fileIO.ReadDirCall.Returns.FileInfos = []os.FileInfo{
+				fakes.FileInfo{
+					FileName: "bbl.tfvars",
+				},
+			}
+
+			err := executor.Init()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("runs terraform plan", func() {
+			err := executor.Plan(map[string]string{
+				"some-cert": "some-cert-value",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.Receives.Args).To(ConsistOf([]string{
+				"plan",
+				"-input=false",
+				"-var", "some-cert=some-cert-value",
+				"-state", tfStatePath,
+				"-var-file", tfVarsPath,
+				terraformDir,
+			}))
+		})
+	})
+
 	Describe("Destroy", func() {
 		var credentials map[string]string
 
@@ -378,6 +416,127 @@ var _ = Describe("Executor", func() {
 		})
 	})
 
+	Describe("Taint", func() {
+		BeforeEach(func() {
+			err := executor.Init()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("runs terraform taint against the state in the vars dir", func() {
+			err := executor.Taint("tls_private_key.bosh_vms")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.CallCount).To(Equal(2))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"taint",
+				"-state", tfStatePath,
+				"tls_private_key.bosh_vms",
+			}))
+			Expect(cmd.RunCall.Receives.Debug).To(BeTrue())
+		})
+
+		Context("when getting vars dir fails", func() {
+			BeforeEach(func() {
+				stateStore.GetVarsDirCall.Returns.Error = errors.New("papaya")
+			})
+
+			It("returns an error", func() {
+				err := executor.Taint("tls_private_key.bosh_vms")
+				Expect(err).To(MatchError("Get vars dir: papaya"))
+			})
+		})
+
+		Context("when command run fails", func() {
+			BeforeEach(func() {
+				cmd.RunCall.Returns.Errors = []error{nil, errors.New("guava")}
+			})
+
+			It("returns an error", func() {
+				err := executor.Taint("tls_private_key.bosh_vms")
+				Expect(err).To(MatchError("Run terraform taint: guava"))
+			})
+		})
+	})
+
+	Describe("StateList", func() {
+		BeforeEach(func() {
+			err := executor.Init()
+			Expect(err).NotTo(HaveOccurred())
+
+			cmd.RunCall.Stub = func(stdout io.Writer) {
+				stdout.Write([]byte("aws_elb.cf_router_lb\naws_route53_record.iso[0]\n"))
+			}
+		})
+
+		It("lists the resources of the state in the vars dir", func() {
+			resources, err := executor.StateList()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal([]string{"aws_elb.cf_router_lb", "aws_route53_record.iso[0]"}))
+
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{"state", "list", "-state", tfStatePath}))
+		})
+
+		Context("when command run fails", func() {
+			BeforeEach(func() {
+				cmd.RunCall.Returns.Errors = []error{nil, errors.New("guava")}
+			})
+
+			It("returns an error", func() {
+				_, err := executor.StateList()
+				Expect(err).To(MatchError("Run terraform state list: guava"))
+			})
+		})
+	})
+
+	Describe("StateMoveOut", func() {
+		BeforeEach(func() {
+			err := executor.Init()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("moves the resource from the state in the vars dir", func() {
+			err := executor.StateMoveOut("aws_elb.cf_router_lb", "/some/dir/terraform.tfstate")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"state", "mv",
+				"-state", tfStatePath,
+				"-state-out", "/some/dir/terraform.tfstate",
+				"aws_elb.cf_router_lb", "aws_elb.cf_router_lb",
+			}))
+		})
+
+		Context("when command run fails", func() {
+			BeforeEach(func() {
+				cmd.RunCall.Returns.Errors = []error{nil, errors.New("guava")}
+			})
+
+			It("returns an error", func() {
+				err := executor.StateMoveOut("aws_elb.cf_router_lb", "/some/dir/terraform.tfstate")
+				Expect(err).To(MatchError("Run terraform state mv: guava"))
+			})
+		})
+	})
+
+	Describe("StateMoveIn", func() {
+		BeforeEach(func() {
+			err := executor.Init()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("moves the resource into the state in the vars dir", func() {
+			err := executor.StateMoveIn("/some/dir/terraform.tfstate", "aws_elb.cf_router_lb")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"state", "mv",
+				"-state", "/some/dir/terraform.tfstate",
+				"-state-out", tfStatePath,
+				"aws_elb.cf_router_lb", "aws_elb.cf_router_lb",
+			}))
+		})
+	})
+
 	Describe("Version", func() {
 		BeforeEach(func() {
 			cmd.RunCall.Stub = func(stdout io.Writer) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/coreos/go-semver/semver"
 )
@@ -22,7 +23,12 @@ type executor interface {
 	Setup(terraformTemplate string, inputs map[string]interface{}) error
 	Init() error
 	Apply(credentials map[string]string) error
+	Plan(credentials map[string]string) error
 	Destroy(credentials map[string]string) error
+	Taint(resource string) error
+	StateList() ([]string, error)
+	StateMoveOut(resource, toState string) error
+	StateMoveIn(fromState, resource string) error
 	Outputs() (map[string]interface{}, error)
 	Output(string) (string, error)
 	IsPaved() (bool, error)
@@ -112,12 +118,32 @@ func (m Manager) Apply(bblState storage.State) (storage.State, error) {
 	bblState.LatestTFOutput = readAndReset(m.terraformOutputBuffer)
 
 	if err != nil {
-		return bblState, fmt.Errorf("Executor apply: %s", err)
+		return bblState, bblerrors.New(bblerrors.KindOfOutput(bblState.LatestTFOutput), fmt.Errorf("Executor apply: %s", err))
 	}
 
 	return bblState, nil
 }
 
+// Plan returns the changes terraform apply would make to the infrastructure,
+// without making them.
+func (m Manager) Plan(bblState storage.State) (string, error) {
+	m.logger.Step("terraform init")
+	if err := m.executor.Init(); err != nil {
+		return "", fmt.Errorf("Executor init: %s", err)
+	}
+
+	m.logger.Step("terraform plan")
+	err := m.executor.Plan(m.inputGenerator.Credentials(bblState))
+
+	output := readAndReset(m.terraformOutputBuffer)
+
+	if err != nil {
+		return output, fmt.Errorf("Executor plan: %s", err)
+	}
+
+	return output, nil
+}
+
 func (m Manager) Destroy(bblState storage.State) (storage.State, error) {
 	m.logger.Step("terraform destroy")
 	err := m.executor.Destroy(m.inputGenerator.Credentials(bblState))
@@ -125,13 +151,77 @@ func (m Manager) Destroy(bblState storage.State) (storage.State, error) {
 	bblState.LatestTFOutput = readAndReset(m.terraformOutputBuffer)
 
 	if err != nil {
-		return bblState, fmt.Errorf("Executor destroy: %s", err)
+		return bblState, bblerrors.New(bblerrors.KindOfOutput(bblState.LatestTFOutput), fmt.Errorf("Executor destroy: %s", err))
 	}
 
 	m.logger.Step("finished destroying infrastructure")
 	return bblState, nil
 }
 
+// Taint marks a resource in the terraform state so that the next apply
+// destroys and recreates it.
+func (m Manager) Taint(resource string) error {
+	m.logger.Step("terraform init")
+	if err := m.executor.Init(); err != nil {
+		return fmt.Errorf("Executor init: %s", err)
+	}
+
+	m.logger.Step("terraform taint %s", resource)
+	if err := m.executor.Taint(resource); err != nil {
+		return fmt.Errorf("Executor taint: %s", err)
+	}
+
+	return nil
+}
+
+// ListResources lists the addresses of the resources in the terraform state.
+func (m Manager) ListResources() ([]string, error) {
+	if err := m.executor.Init(); err != nil {
+		return nil, fmt.Errorf("Executor init: %s", err)
+	}
+
+	resources, err := m.executor.StateList()
+	if err != nil {
+		return nil, fmt.Errorf("Executor state list: %s", err)
+	}
+
+	return resources, nil
+}
+
+// MoveResourcesOut moves resources from the terraform state to another state
+// file, so that terraform neither changes nor deletes them here any more.
+func (m Manager) MoveResourcesOut(resources []string, toState string) error {
+	if err := m.executor.Init(); err != nil {
+		return fmt.Errorf("Executor init: %s", err)
+	}
+
+	for _, resource := range resources {
+		m.logger.Step("terraform state mv %s", resource)
+		if err := m.executor.StateMoveOut(resource, toState); err != nil {
+			return fmt.Errorf("Executor state mv: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// MoveResourcesIn moves resources from another state file into the terraform
+// state, so that the next apply manages them.
+func (m Manager) MoveResourcesIn(fromState string, resources []string) error {
+	if err := m.executor.Init(); err != nil {
+		return fmt.Errorf("Executor init: %s", err)
+	}
+
+	for _, resource := range resources {
+		m.logger.Step("terraform state mv %s", resource)
+		if err := m.executor.StateMoveIn(fromState, resource); err != nil {
+			return fmt.Errorf("Executor state mv: %s", err)
+		}
+	}
+
+	return nil
+}
+
 func (m Manager) GetOutputs() (Outputs, error) {
 	tfOutputs, err := m.executor.Outputs()
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 
+	"github.com/cloudfoundry/bosh-bootloader/bblerrors"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -166,6 +167,17 @@ var _ = Describe("Manager", func() {
 				Expect(err).To(MatchError("Executor apply: grape"))
 				Expect(state.LatestTFOutput).To(Equal(incomingState.LatestTFOutput))
 			})
+
+			Context("when the output has an AWS error code", func() {
+				It("returns an error of its kind", func() {
+					terraformOutputBuffer.Reset()
+					terraformOutputBuffer.Write([]byte("* aws_eip.jumpbox_eip: Error creating EIP: AddressLimitExceeded: The maximum number of addresses has been reached."))
+
+					_, err := manager.Apply(incomingState)
+					Expect(err).To(MatchError("Executor apply: grape"))
+					Expect(bblerrors.KindOf(err)).To(Equal(bblerrors.Quota))
+				})
+			})
 		})
 	})
 
@@ -219,6 +231,140 @@ var _ = Describe("Manager", func() {
 		})
 	})
 
+	Describe("Plan", func() {
+		BeforeEach(func() {
+			inputGenerator.CredentialsCall.Returns.Credentials = map[string]string{
+				"some-credential": "some-credential-value",
+			}
+			terraformOutputBuffer.Write([]byte("Plan: 1 to add, 0 to change, 0 to destroy."))
+		})
+
+		It("initializes terraform and returns the output of terraform plan", func() {
+			output, err := manager.Plan(storage.State{EnvID: "some-env-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(output).To(Equal("Plan: 1 to add, 0 to change, 0 to destroy."))
+			Expect(executor.InitCall.CallCount).To(Equal(1))
+			Expect(executor.PlanCall.Receives.Credentials).To(Equal(map[string]string{
+				"some-credential": "some-credential-value",
+			}))
+			Expect(logger.StepCall.Messages).To(gomegamatchers.ContainSequence([]string{
+				"terraform init",
+				"terraform plan",
+			}))
+		})
+
+		Context("when executor plan fails", func() {
+			BeforeEach(func() {
+				executor.PlanCall.Returns.Error = errors.New("pomelo")
+			})
+
+			It("returns the output and the error", func() {
+				output, err := manager.Plan(storage.State{})
+				Expect(err).To(MatchError("Executor plan: pomelo"))
+				Expect(output).To(Equal("Plan: 1 to add, 0 to change, 0 to destroy."))
+			})
+		})
+	})
+
+	Describe("Taint", func() {
+		It("initializes terraform and taints the resource", func() {
+			err := manager.Taint("tls_private_key.bosh_vms")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.InitCall.CallCount).To(Equal(1))
+			Expect(executor.TaintCall.CallCount).To(Equal(1))
+			Expect(executor.TaintCall.Receives.Resource).To(Equal("tls_private_key.bosh_vms"))
+			Expect(logger.StepCall.Messages).To(gomegamatchers.ContainSequence([]string{
+				"terraform init",
+				"terraform taint tls_private_key.bosh_vms",
+			}))
+		})
+
+		Context("when executor init fails", func() {
+			BeforeEach(func() {
+				executor.InitCall.Returns.Error = errors.New("lime")
+			})
+
+			It("returns the error", func() {
+				err := manager.Taint("tls_private_key.bosh_vms")
+				Expect(err).To(MatchError("Executor init: lime"))
+				Expect(executor.TaintCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when executor taint fails", func() {
+			BeforeEach(func() {
+				executor.TaintCall.Returns.Error = errors.New("plum")
+			})
+
+			It("returns the error", func() {
+				err := manager.Taint("tls_private_key.bosh_vms")
+				Expect(err).To(MatchError("Executor taint: plum"))
+			})
+		})
+	})
+
+	Describe("ListResources", func() {
+		It("lists the resources of the terraform state", func() {
+			executor.StateListCall.Returns.Resources = []string{"aws_elb.cf_router_lb", "aws_vpc.vpc"}
+
+			resources, err := manager.ListResources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal([]string{"aws_elb.cf_router_lb", "aws_vpc.vpc"}))
+		})
+
+		It("returns an error when the executor fails", func() {
+			executor.StateListCall.Returns.Error = errors.New("peach")
+
+			_, err := manager.ListResources()
+			Expect(err).To(MatchError("Executor state list: peach"))
+		})
+	})
+
+	Describe("MoveResourcesOut", func() {
+		It("moves each resource to the other state", func() {
+			err := manager.MoveResourcesOut([]string{"aws_elb.cf_router_lb", "aws_elb.cf_ssh_lb"}, "some-dir/terraform.tfstate")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.StateMoveOutCall.Receives).To(Equal([]fakes.StateMoveOutReceive{
+				{Resource: "aws_elb.cf_router_lb", ToState: "some-dir/terraform.tfstate"},
+				{Resource: "aws_elb.cf_ssh_lb", ToState: "some-dir/terraform.tfstate"},
+			}))
+			Expect(logger.StepCall.Messages).To(gomegamatchers.ContainSequence([]string{
+				"terraform state mv aws_elb.cf_router_lb",
+				"terraform state mv aws_elb.cf_ssh_lb",
+			}))
+		})
+
+		It("returns an error when the executor fails", func() {
+			executor.StateMoveOutCall.Returns.Error = errors.New("apricot")
+
+			err := manager.MoveResourcesOut([]string{"aws_elb.cf_router_lb", "aws_elb.cf_ssh_lb"}, "some-dir/terraform.tfstate")
+			Expect(err).To(MatchError("Executor state mv: apricot"))
+			Expect(executor.StateMoveOutCall.CallCount).To(Equal(1))
+		})
+	})
+
+	Describe("MoveResourcesIn", func() {
+		It("moves each resource from the other state", func() {
+			err := manager.MoveResourcesIn("some-dir/terraform.tfstate", []string{"aws_elb.cf_router_lb", "aws_elb.cf_ssh_lb"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.StateMoveInCall.Receives).To(Equal([]fakes.StateMoveInReceive{
+				{FromState: "some-dir/terraform.tfstate", Resource: "aws_elb.cf_router_lb"},
+				{FromState: "some-dir/terraform.tfstate", Resource: "aws_elb.cf_ssh_lb"},
+			}))
+		})
+
+		It("returns an error when the executor fails", func() {
+			executor.StateMoveInCall.Returns.Error = errors.New("apricot")
+
+			err := manager.MoveResourcesIn("some-dir/terraform.tfstate", []string{"aws_elb.cf_router_lb"})
+			Expect(err).To(MatchError("Executor state mv: apricot"))
+		})
+	})
+
 	Describe("GetOutputs", func() {
 		BeforeEach(func() {
 			executor.OutputsCall.Returns.Outputs = map[string]interface{}{"external_ip": "some-external-ip"}
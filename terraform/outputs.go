@@ -1,5 +1,12 @@
 package terraform
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type Outputs struct {
 	Map map[string]interface{}
 }
@@ -49,3 +56,92 @@ func (o Outputs) GetStringMap(key string) map[string]string {
 	}
 	return stringMap
 }
+
+// OutputsFromState reads the outputs recorded in a terraform.tfstate file,
+// without running terraform. Both the module-based format of terraform 0.11
+// and the top-level outputs of later versions are read.
+func OutputsFromState(tfState []byte) (Outputs, error) {
+	type output struct {
+		Value interface{} `json:"value"`
+	}
+	var state struct {
+		Outputs map[string]output `json:"outputs"`
+		Modules []struct {
+			Path    []string          `json:"path"`
+			Outputs map[string]output `json:"outputs"`
+		} `json:"modules"`
+	}
+
+	err := json.Unmarshal(tfState, &state)
+	if err != nil {
+		return Outputs{}, err
+	}
+
+	outputs := state.Outputs
+	for _, module := range state.Modules {
+		if len(module.Path) == 1 && module.Path[0] == "root" {
+			outputs = module.Outputs
+		}
+	}
+
+	values := map[string]interface{}{}
+	for name, output := range outputs {
+		values[name] = output.Value
+	}
+
+	return Outputs{Map: values}, nil
+}
+
+// ResourcesFromState lists the resources recorded in a terraform.tfstate
+// file as address (id), without running terraform. Data sources are left
+// out, because destroying the environment does not delete them.
+func ResourcesFromState(tfState []byte) ([]string, error) {
+	type primary struct {
+		ID string `json:"id"`
+	}
+	var state struct {
+		Modules []struct {
+			Resources map[string]struct {
+				Primary primary `json:"primary"`
+			} `json:"resources"`
+		} `json:"modules"`
+		Resources []struct {
+			Mode      string `json:"mode"`
+			Type      string `json:"type"`
+			Name      string `json:"name"`
+			Instances []struct {
+				Attributes primary `json:"attributes"`
+			} `json:"instances"`
+		} `json:"resources"`
+	}
+
+	err := json.Unmarshal(tfState, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []string{}
+	for _, module := range state.Modules {
+		for address, resource := range module.Resources {
+			if strings.HasPrefix(address, "data.") {
+				continue
+			}
+			resources = append(resources, fmt.Sprintf("%s (%s)", address, resource.Primary.ID))
+		}
+	}
+	for _, resource := range state.Resources {
+		if resource.Mode == "data" {
+			continue
+		}
+		for i, instance := range resource.Instances {
+			address := fmt.Sprintf("%s.%s", resource.Type, resource.Name)
+			if len(resource.Instances) > 1 {
+				address = fmt.Sprintf("%s.%d", address, i)
+			}
+			resources = append(resources, fmt.Sprintf("%s (%s)", address, instance.Attributes.ID))
+		}
+	}
+
+	sort.Strings(resources)
+	return resources, nil
+}
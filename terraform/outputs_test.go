@@ -125,4 +125,58 @@ var _ = Describe("TerraformOutputs", func() {
 			})
 		})
 	})
+
+	Describe("OutputsFromState", func() {
+		It("reads the root module outputs of a terraform 0.11 state", func() {
+			outputs, err := terraform.OutputsFromState([]byte(`{
+				"version": 3,
+				"modules": [
+					{"path": ["root"], "outputs": {"vpc_id": {"type": "string", "value": "vpc-1234"}}},
+					{"path": ["root", "child"], "outputs": {"vpc_id": {"type": "string", "value": "vpc-child"}}}
+				]
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs.GetString("vpc_id")).To(Equal("vpc-1234"))
+		})
+
+		It("reads the top-level outputs of later states", func() {
+			outputs, err := terraform.OutputsFromState([]byte(`{"version": 4, "outputs": {"vpc_id": {"type": "string", "value": "vpc-1234"}}}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs.GetString("vpc_id")).To(Equal("vpc-1234"))
+		})
+
+		It("returns an error when the state is not json", func() {
+			_, err := terraform.OutputsFromState([]byte("%%%"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ResourcesFromState", func() {
+		It("lists the resources of a terraform 0.11 state without data sources", func() {
+			resources, err := terraform.ResourcesFromState([]byte(`{
+				"version": 3,
+				"modules": [{"path": ["root"], "resources": {
+					"aws_vpc.vpc": {"type": "aws_vpc", "primary": {"id": "vpc-1234"}},
+					"aws_elb.cf_router_lb": {"type": "aws_elb", "primary": {"id": "env-cf-router-lb"}},
+					"data.aws_ami.nat": {"type": "aws_ami", "primary": {"id": "ami-1234"}}
+				}}]
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal([]string{"aws_elb.cf_router_lb (env-cf-router-lb)", "aws_vpc.vpc (vpc-1234)"}))
+		})
+
+		It("lists the resources of later states", func() {
+			resources, err := terraform.ResourcesFromState([]byte(`{"version": 4, "resources": [
+				{"mode": "managed", "type": "aws_subnet", "name": "internal_subnets", "instances": [{"attributes": {"id": "subnet-1"}}, {"attributes": {"id": "subnet-2"}}]},
+				{"mode": "data", "type": "aws_ami", "name": "nat", "instances": [{"attributes": {"id": "ami-1234"}}]}
+			]}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal([]string{"aws_subnet.internal_subnets.0 (subnet-1)", "aws_subnet.internal_subnets.1 (subnet-2)"}))
+		})
+
+		It("returns an error when the state is not json", func() {
+			_, err := terraform.ResourcesFromState([]byte("%%%"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
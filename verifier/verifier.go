@@ -0,0 +1,149 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type logger interface {
+	Step(string, ...interface{})
+}
+
+type boshClientProvider interface {
+	Client(jumpbox storage.Jumpbox, directorAddress, directorUsername, directorPassword, directorCACert string) (bosh.Client, error)
+}
+
+// Snapshot is what the director reported before an upgrade, for Verify to
+// compare against.
+type Snapshot struct {
+	Version     string
+	Deployments []string
+}
+
+type Verifier struct {
+	boshClientProvider boshClientProvider
+	logger             logger
+}
+
+func NewVerifier(boshClientProvider boshClientProvider, logger logger) Verifier {
+	return Verifier{
+		boshClientProvider: boshClientProvider,
+		logger:             logger,
+	}
+}
+
+func (v Verifier) Snapshot(state storage.State) (Snapshot, error) {
+	client, err := v.client(state)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	info, err := client.Info()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("Get director info: %s", err)
+	}
+
+	deployments, err := listDeployments(client)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{Version: info.Version, Deployments: deployments}, nil
+}
+
+// Verify checks that the upgraded director serves its API, still has the
+// deployments it had before the upgrade, and can list its tasks.
+func (v Verifier) Verify(state storage.State, before Snapshot) error {
+	v.logger.Step("verifying the director")
+
+	client, err := v.client(state)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+
+	info, err := client.Info()
+	switch {
+	case err != nil:
+		failures = append(failures, fmt.Sprintf("director info: %s", err))
+	case info.Version == "":
+		failures = append(failures, "director info: the director did not report a version")
+	}
+
+	deployments, err := listDeployments(client)
+	if err != nil {
+		failures = append(failures, err.Error())
+	} else if missing := difference(before.Deployments, deployments); len(missing) > 0 {
+		failures = append(failures, fmt.Sprintf("deployments missing after the upgrade: %s", strings.Join(missing, ", ")))
+	}
+
+	status, _, err := client.Curl("GET", "/tasks?limit=1", nil)
+	switch {
+	case err != nil:
+		failures = append(failures, fmt.Sprintf("list tasks: %s", err))
+	case status != 200:
+		failures = append(failures, fmt.Sprintf("list tasks: the director responded with %d", status))
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+
+	v.logger.Step("verified the director (%s -> %s)", before.Version, info.Version)
+	return nil
+}
+
+func (v Verifier) client(state storage.State) (bosh.Client, error) {
+	client, err := v.boshClientProvider.Client(state.Jumpbox, state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword, state.BOSH.DirectorSSLCA)
+	if err != nil {
+		return nil, fmt.Errorf("Connect to the director: %s", err)
+	}
+	return client, nil
+}
+
+func listDeployments(client bosh.Client) ([]string, error) {
+	status, body, err := client.Curl("GET", "/deployments", nil)
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %s", err)
+	}
+	if status != 200 {
+		return nil, fmt.Errorf("list deployments: the director responded with %d", status)
+	}
+
+	var deployments []struct {
+		Name string `json:"name"`
+	}
+	err = json.Unmarshal(body, &deployments)
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %s", err)
+	}
+
+	names := []string{}
+	for _, deployment := range deployments {
+		names = append(names, deployment.Name)
+	}
+	return names, nil
+}
+
+func difference(before, after []string) []string {
+	missing := []string{}
+	for _, name := range before {
+		found := false
+		for _, other := range after {
+			if name == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,120 @@
+package verifier_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/verifier"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verifier", func() {
+	var (
+		boshClientProvider *fakes.BOSHClientProvider
+		boshClient         *fakes.BOSHClient
+		logger             *fakes.Logger
+		v                  verifier.Verifier
+
+		state       storage.State
+		deployments string
+		tasksStatus int
+	)
+
+	BeforeEach(func() {
+		boshClient = &fakes.BOSHClient{}
+		boshClient.InfoCall.Returns.Info = bosh.Info{Version: "264.1.0"}
+		deployments = `[{"name": "cf"}, {"name": "concourse"}]`
+		tasksStatus = 200
+		boshClient.CurlCall.Stub = func(method, path string, body []byte) (int, []byte, error) {
+			switch path {
+			case "/deployments":
+				return 200, []byte(deployments), nil
+			case "/tasks?limit=1":
+				return tasksStatus, []byte("[]"), nil
+			}
+			return 404, nil, nil
+		}
+
+		boshClientProvider = &fakes.BOSHClientProvider{}
+		boshClientProvider.ClientCall.Returns.Client = boshClient
+		logger = &fakes.Logger{}
+
+		v = verifier.NewVerifier(boshClientProvider, logger)
+
+		state = storage.State{
+			Jumpbox: storage.Jumpbox{URL: "some-jumpbox-url"},
+			BOSH: storage.BOSH{
+				DirectorAddress:  "some-director-address",
+				DirectorUsername: "some-director-username",
+				DirectorPassword: "some-director-password",
+				DirectorSSLCA:    "some-director-ca",
+			},
+		}
+	})
+
+	Describe("Snapshot", func() {
+		It("records the director version and deployments", func() {
+			snapshot, err := v.Snapshot(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshot).To(Equal(verifier.Snapshot{Version: "264.1.0", Deployments: []string{"cf", "concourse"}}))
+
+			Expect(boshClientProvider.ClientCall.Receives.Jumpbox).To(Equal(state.Jumpbox))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorCACert).To(Equal("some-director-ca"))
+		})
+
+		It("returns an error when the director cannot be reached", func() {
+			boshClientProvider.ClientCall.Returns.Error = errors.New("fig")
+			_, err := v.Snapshot(state)
+			Expect(err).To(MatchError("Connect to the director: fig"))
+		})
+
+		It("returns an error when the deployments cannot be listed", func() {
+			deployments = "not json"
+			_, err := v.Snapshot(state)
+			Expect(err).To(MatchError(ContainSubstring("list deployments: ")))
+		})
+	})
+
+	Describe("Verify", func() {
+		var before verifier.Snapshot
+
+		BeforeEach(func() {
+			before = verifier.Snapshot{Version: "262.3.0", Deployments: []string{"cf", "concourse"}}
+		})
+
+		It("succeeds when the director is healthy and has the same deployments", func() {
+			err := v.Verify(state, before)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.StepCall.Messages).To(Equal([]string{
+				"verifying the director",
+				"verified the director (262.3.0 -> 264.1.0)",
+			}))
+		})
+
+		It("reports every failed check", func() {
+			deployments = `[{"name": "cf"}]`
+			tasksStatus = 500
+			boshClient.InfoCall.Returns.Info = bosh.Info{}
+
+			err := v.Verify(state, before)
+			Expect(err).To(MatchError("director info: the director did not report a version; deployments missing after the upgrade: concourse; list tasks: the director responded with 500"))
+		})
+
+		It("returns an error when the director info cannot be read", func() {
+			boshClient.InfoCall.Returns.Error = errors.New("guava")
+			err := v.Verify(state, before)
+			Expect(err).To(MatchError("director info: guava"))
+		})
+
+		It("returns an error when the director cannot be reached", func() {
+			boshClientProvider.ClientCall.Returns.Error = errors.New("kiwi")
+			err := v.Verify(state, before)
+			Expect(err).To(MatchError("Connect to the director: kiwi"))
+		})
+	})
+})
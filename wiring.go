@@ -0,0 +1,134 @@
+package bbl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/cloudconfig"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	"github.com/cloudfoundry/bosh-bootloader/verifier"
+	proxy "github.com/cloudfoundry/socks5-proxy"
+	"github.com/spf13/afero"
+)
+
+// WiringConfig is what the wiring of terraform and bosh create-env takes of
+// the global flags of bbl.
+type WiringConfig struct {
+	StateDir string
+	Stderr   io.Writer
+	Debug    bool
+	Version  string
+}
+
+// Wiring runs terraform and bosh create-env for the commands of an
+// environment. bbl/main.go and Client both build plan, up and destroy with
+// it, so that the bbl binary and the client run them the same way.
+type Wiring struct {
+	TerraformExecutor     terraform.Executor
+	TerraformOutputBuffer *bytes.Buffer
+	BOSHCommand           bosh.Cmd
+	SSHKeyGetter          bosh.SSHKeyGetter
+	BOSHClientProvider    bosh.ClientProvider
+	BOSHManager           *bosh.Manager
+	DirectorVerifier      verifier.Verifier
+
+	config     WiringConfig
+	stateStore storage.Store
+	afs        *afero.Afero
+	logger     *application.Logger
+	stderr     *application.Logger
+}
+
+// WiringIAAS is what plan, up and destroy need of the IAAS of the
+// environment. The clients are nil when the command runs without IAAS
+// credentials.
+type WiringIAAS struct {
+	TerraformManager         terraform.Manager
+	CloudConfigOpsGenerator  cloudconfig.OpsGenerator
+	EnvIDManager             helpers.EnvIDManager
+	NetworkDeletionValidator commands.NetworkDeletionValidator
+	Leftovers                commands.FilteredDeleter
+	AccountBootstrapper      commands.AccountBootstrapper
+	QuotaChecker             commands.QuotaChecker
+}
+
+// WiringCommands are the commands that Client runs. bbl/main.go registers
+// them among its other commands.
+type WiringCommands struct {
+	CloudConfigManager cloudconfig.Manager
+	Plan               commands.Plan
+	Up                 commands.Up
+	Destroy            commands.Destroy
+}
+
+// NewWiring stops terraform and bosh create-env once ctx is done. Without
+// debug, the output of terraform is only shown once it fails, so the
+// resources it changes are reported as it changes them.
+func NewWiring(ctx context.Context, config WiringConfig, stateStore storage.Store, afs *afero.Afero, logger, stderrLogger *application.Logger) Wiring {
+	terraformOutputBuffer := bytes.NewBuffer([]byte{})
+	var terraformOutput io.Writer = terraformOutputBuffer
+	if !config.Debug {
+		terraformOutput = io.MultiWriter(terraformOutputBuffer, terraform.NewEventStreamer(logger))
+	}
+	terraformCmd := terraform.NewCmd(ctx, config.Stderr, terraformOutput, filepath.Join(config.StateDir, "terraform", ".terraform"))
+
+	socks5Proxy := proxy.NewSocks5Proxy(proxy.NewHostKey(), nil)
+	boshCommand := bosh.NewCmd(ctx, config.Stderr)
+	boshExecutor := bosh.NewExecutor(ctx, boshCommand, afs, json.Unmarshal, json.Marshal, logger)
+	sshKeyGetter := bosh.NewSSHKeyGetter(stateStore, afs)
+	boshClientProvider := bosh.NewClientProvider(socks5Proxy, sshKeyGetter)
+
+	return Wiring{
+		TerraformExecutor:     terraform.NewExecutor(terraformCmd, stateStore, afs, config.Debug),
+		TerraformOutputBuffer: terraformOutputBuffer,
+		BOSHCommand:           boshCommand,
+		SSHKeyGetter:          sshKeyGetter,
+		BOSHClientProvider:    boshClientProvider,
+		BOSHManager:           bosh.NewManager(boshExecutor, logger, stateStore, sshKeyGetter, afs, boshClientProvider),
+		DirectorVerifier:      verifier.NewVerifier(boshClientProvider, logger),
+
+		config:     config,
+		stateStore: stateStore,
+		afs:        afs,
+		logger:     logger,
+		stderr:     stderrLogger,
+	}
+}
+
+// TerraformManager applies the template of templateGenerator with the
+// inputs of inputGenerator.
+func (w Wiring) TerraformManager(templateGenerator terraform.TemplateGenerator, inputGenerator terraform.InputGenerator) terraform.Manager {
+	return terraform.NewManager(w.TerraformExecutor, templateGenerator, inputGenerator, w.TerraformOutputBuffer, w.logger)
+}
+
+// CachingAvailabilityZoneRetriever keeps the availability zones that
+// retriever lists in the cache directory of the state.
+func (w Wiring) CachingAvailabilityZoneRetriever(retriever aws.AvailabilityZoneRetriever) (aws.AvailabilityZoneRetriever, error) {
+	cacheDir, err := w.stateStore.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewCachingAvailabilityZoneRetriever(retriever, aws.NewCache(cacheDir, w.afs), w.logger), nil
+}
+
+// Commands builds plan, up and destroy on the IAAS of the environment.
+func (w Wiring) Commands(iaas WiringIAAS, lbArgsHandler commands.LBArgsHandler, stateValidator application.StateValidator) WiringCommands {
+	cloudConfigManager := cloudconfig.NewManager(w.logger, w.BOSHCommand, w.stateStore, iaas.CloudConfigOpsGenerator, w.BOSHClientProvider, iaas.TerraformManager, w.afs)
+	plan := commands.NewPlan(w.BOSHManager, cloudConfigManager, w.stateStore, iaas.EnvIDManager, iaas.TerraformManager, lbArgsHandler, w.BOSHClientProvider, w.afs, w.stderr, w.config.Version)
+
+	return WiringCommands{
+		CloudConfigManager: cloudConfigManager,
+		Plan:               plan,
+		Up:                 commands.NewUp(plan, w.BOSHManager, cloudConfigManager, w.stateStore, iaas.TerraformManager, w.DirectorVerifier, iaas.AccountBootstrapper, iaas.QuotaChecker, w.logger),
+		Destroy:            commands.NewDestroy(plan, w.logger, w.BOSHManager, w.stateStore, stateValidator, iaas.TerraformManager, iaas.NetworkDeletionValidator, iaas.Leftovers, w.afs, w.BOSHClientProvider),
+	}
+}